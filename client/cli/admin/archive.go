@@ -0,0 +1,151 @@
+package admin
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/micro/micro/v3/service/router"
+	"github.com/micro/micro/v3/service/store"
+)
+
+const (
+	// archiveVersion is incremented when the archive format changes, archives with a newer version
+	// than the one supported can't be imported
+	archiveVersion = 1
+
+	manifestFile = "manifest.json"
+	authFile     = "auth.json"
+	configFile   = "config.json"
+	routesFile   = "routes.json"
+	servicesFile = "services.json"
+)
+
+// manifest describes the contents of an archive
+type manifest struct {
+	Version   int            `json:"version"`
+	Created   time.Time      `json:"created"`
+	Namespace string         `json:"namespace"`
+	Resources map[string]int `json:"resources"`
+}
+
+// service is the runtime spec of a service
+type service struct {
+	Name     string            `json:"name"`
+	Version  string            `json:"version"`
+	Source   string            `json:"source"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// archive is the state of a namespace. Accounts and rules are stored as the auth service's records
+// so the secrets, which the api never returns, are preserved.
+type archive struct {
+	Manifest manifest
+	Auth     []*store.Record
+	Config   []*store.Record
+	Routes   []router.Route
+	Services []*service
+}
+
+// Write the archive as a gzipped tarball
+func (a *archive) Write(w io.Writer) error {
+	a.Manifest.Version = archiveVersion
+	a.Manifest.Resources = map[string]int{
+		"auth":     len(a.Auth),
+		"config":   len(a.Config),
+		"routes":   len(a.Routes),
+		"services": len(a.Services),
+	}
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	files := []struct {
+		name string
+		v    interface{}
+	}{
+		{manifestFile, a.Manifest},
+		{authFile, a.Auth},
+		{configFile, a.Config},
+		{routesFile, a.Routes},
+		{servicesFile, a.Services},
+	}
+	for _, f := range files {
+		b, err := json.MarshalIndent(f.v, "", "  ")
+		if err != nil {
+			return err
+		}
+		hdr := &tar.Header{
+			Name:    f.name,
+			Mode:    0600,
+			Size:    int64(len(b)),
+			ModTime: a.Manifest.Created,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(b); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// readArchive reads an archive written by Write
+func readArchive(r io.Reader) (*archive, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a valid archive: %v", err)
+	}
+	defer gr.Close()
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("not a valid archive: %v", err)
+		}
+		if files[hdr.Name], err = ioutil.ReadAll(tr); err != nil {
+			return nil, err
+		}
+	}
+
+	a := &archive{}
+	if _, ok := files[manifestFile]; !ok {
+		return nil, fmt.Errorf("not a valid archive: missing %v", manifestFile)
+	}
+	if err := json.Unmarshal(files[manifestFile], &a.Manifest); err != nil {
+		return nil, fmt.Errorf("invalid %v: %v", manifestFile, err)
+	}
+	if a.Manifest.Version > archiveVersion {
+		return nil, fmt.Errorf("archive version %v is newer than the version supported (%v), upgrade micro to import it", a.Manifest.Version, archiveVersion)
+	}
+
+	contents := map[string]interface{}{
+		authFile:     &a.Auth,
+		configFile:   &a.Config,
+		routesFile:   &a.Routes,
+		servicesFile: &a.Services,
+	}
+	for name, v := range contents {
+		b, ok := files[name]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(b, v); err != nil {
+			return nil, fmt.Errorf("invalid %v: %v", name, err)
+		}
+	}
+	return a, nil
+}
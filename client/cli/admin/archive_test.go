@@ -0,0 +1,58 @@
+package admin
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/router"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchive(t *testing.T) {
+	a := &archive{
+		Manifest: manifest{Created: time.Unix(1600000000, 0).UTC(), Namespace: "foo"},
+		Auth:     []*store.Record{{Key: "account/foo/1", Value: []byte(`{"id":"1"}`)}},
+		Config:   []*store.Record{{Key: "foo", Value: []byte(`{"key":"value"}`)}},
+		Routes:   []router.Route{{Service: "helloworld", Address: "10.0.0.1:8080", Metric: 1}},
+		Services: []*service{{Name: "helloworld", Version: "latest", Source: "github.com/micro/services/helloworld"}},
+	}
+
+	var buf bytes.Buffer
+	assert.Nil(t, a.Write(&buf))
+
+	b, err := readArchive(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, archiveVersion, b.Manifest.Version)
+	assert.Equal(t, map[string]int{"auth": 1, "config": 1, "routes": 1, "services": 1}, b.Manifest.Resources)
+	assert.Equal(t, a.Auth[0].Key, b.Auth[0].Key)
+	assert.Equal(t, a.Auth[0].Value, b.Auth[0].Value)
+	assert.Equal(t, a.Config[0].Value, b.Config[0].Value)
+	assert.Equal(t, a.Routes, b.Routes)
+	assert.Equal(t, a.Services, b.Services)
+}
+
+func TestArchiveVersion(t *testing.T) {
+	// archives written by a newer version of micro can't be imported
+	b, err := json.Marshal(manifest{Version: archiveVersion + 1, Namespace: "foo"})
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	assert.Nil(t, tw.WriteHeader(&tar.Header{Name: manifestFile, Mode: 0600, Size: int64(len(b))}))
+	_, err = tw.Write(b)
+	assert.Nil(t, err)
+	assert.Nil(t, tw.Close())
+	assert.Nil(t, gw.Close())
+
+	_, err = readArchive(&buf)
+	assert.NotNil(t, err)
+
+	_, err = readArchive(bytes.NewReader([]byte("not an archive")))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,112 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/micro/v3/util/capture"
+	"github.com/urfave/cli/v2"
+)
+
+// startCapture records the exchanges with the api for a route
+func startCapture(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: route")
+	}
+
+	c := &capture.Capture{
+		Host:    ctx.String("host"),
+		Route:   ctx.Args().First(),
+		Limit:   ctx.Int("limit"),
+		Account: currentAccount(ctx),
+		Created: time.Now(),
+	}
+	c.Expiry = c.Created.Add(ctx.Duration("duration"))
+	if err := capture.Start(c); err != nil {
+		return fmt.Errorf("Error starting capture: %v", err)
+	}
+
+	fmt.Printf("Capturing %v%v until %v, get the exchanges with micro admin capture get %v\n",
+		c.Host, c.Route, c.Expiry.Format(time.RFC3339), c.ID)
+	return nil
+}
+
+// stopCapture stops recording the exchanges of a capture
+func stopCapture(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: id")
+	}
+	if err := capture.Stop(ctx.Args().First()); err != nil {
+		return fmt.Errorf("Error stopping capture: %v", err)
+	}
+	fmt.Printf("Stopped capture %v\n", ctx.Args().First())
+	return nil
+}
+
+// getCapture writes the HAR of a capture to a file, or stdout
+func getCapture(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: id")
+	}
+	har, err := capture.Read(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("Error reading capture: %v", err)
+	}
+	b, err := json.MarshalIndent(har, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	out := ctx.String("output")
+	if len(out) == 0 {
+		fmt.Println(string(b))
+		return nil
+	}
+	if err := os.WriteFile(out, b, 0600); err != nil {
+		return fmt.Errorf("Error writing capture: %v", err)
+	}
+	fmt.Printf("Wrote %v exchanges to %v\n", len(har.Log.Entries), out)
+	return nil
+}
+
+// deleteCapture deletes a capture and the exchanges recorded
+func deleteCapture(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: id")
+	}
+	if err := capture.Delete(ctx.Args().First()); err != nil {
+		return fmt.Errorf("Error deleting capture: %v", err)
+	}
+	fmt.Printf("Deleted capture %v\n", ctx.Args().First())
+	return nil
+}
+
+// listCaptures prints the captures
+func listCaptures(ctx *cli.Context) error {
+	cs, err := capture.List()
+	if err != nil {
+		return fmt.Errorf("Error listing captures: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"ID", "Host", "Route", "Limit", "Started", "Expires", "Account"}, "\t\t"))
+	for _, c := range cs {
+		host := c.Host
+		if len(host) == 0 {
+			host = "*"
+		}
+		expiry := c.Expiry.Format(time.RFC3339)
+		if c.Expired() {
+			expiry = "expired"
+		}
+		fmt.Fprintln(w, strings.Join([]string{c.ID, host, c.Route, strconv.Itoa(c.Limit), c.Created.Format(time.RFC3339), expiry, c.Account}, "\t\t"))
+	}
+	return nil
+}
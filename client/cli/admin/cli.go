@@ -0,0 +1,306 @@
+// Package admin provides commands to administer the platform
+package admin
+
+import (
+	"time"
+
+	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/service/runtime/vuln"
+	"github.com/micro/micro/v3/util/capture"
+	"github.com/micro/micro/v3/util/helper"
+	"github.com/urfave/cli/v2"
+)
+
+var residencyNamespaceFlag = &cli.StringFlag{
+	Name:  "namespace",
+	Usage: "Namespace of the policy, defaults to the current namespace",
+}
+
+func init() {
+	cmd.Register(
+		&cli.Command{
+			Name:   "admin",
+			Usage:  "Administer the platform",
+			Action: helper.UnexpectedSubcommand,
+			Subcommands: []*cli.Command{
+				{
+					Name:  "export",
+					Usage: "Export the accounts, rules, config, routes and services of the namespace to an archive",
+					Description: `The archive can be imported into another cluster using micro admin import. Config secrets
+are exported encrypted, the cluster they're imported into must use the same config secret key.`,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "output",
+							Aliases: []string{"o"},
+							Usage:   "File to write the archive to, use - for stdout. Defaults to micro-<namespace>-<time>.tar.gz",
+						},
+					},
+					Action: exportState,
+				},
+				{
+					Name:      "import",
+					Usage:     "Import an archive created by micro admin export",
+					ArgsUsage: "<archive>",
+					Flags: []cli.Flag{
+						&cli.BoolFlag{
+							Name:  "overwrite",
+							Usage: "Overwrite accounts, rules and config which already exist",
+						},
+					},
+					Action: importState,
+				},
+				{
+					Name:      "upgrade",
+					Usage:     "Upgrade the core services of the platform to a new version",
+					ArgsUsage: "<version>",
+					Description: `The core services are updated one at a time, starting with the registry and ending with
+the edge services, waiting for each to become healthy before moving on. If a service fails to
+become healthy the services already updated are rolled back. The previous version is recorded
+so a completed upgrade can be reverted using --rollback.`,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "image",
+							Usage: "Image of the core services, the version is used as the tag",
+							Value: "micro/micro",
+						},
+						&cli.DurationFlag{
+							Name:  "timeout",
+							Usage: "How long to wait for each service to become healthy",
+							Value: 5 * time.Minute,
+						},
+						&cli.BoolFlag{
+							Name:  "rollback",
+							Usage: "Roll back to the version before the last upgrade",
+						},
+						&cli.BoolFlag{
+							Name:  "force",
+							Usage: "Skip the minor version and downgrade checks",
+						},
+						&cli.BoolFlag{
+							Name:  "dry-run",
+							Usage: "Print the upgrade plan without updating any services",
+						},
+					},
+					Action: upgrade,
+				},
+				{
+					Name:      "disable-endpoint",
+					Usage:     "Reject calls to an endpoint on every instance of a service, e.g. micro admin disable-endpoint users Users.Export --reason incident-123",
+					ArgsUsage: "<service> <endpoint>",
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "reason",
+							Usage: "Reason the endpoint is disabled, returned to callers and recorded in the audit log",
+						},
+						&cli.DurationFlag{
+							Name:  "ttl",
+							Usage: "How long the endpoint is disabled for, 0 disables it until it's enabled",
+							Value: time.Hour,
+						},
+					},
+					Action: disableEndpoint,
+				},
+				{
+					Name:      "enable-endpoint",
+					Usage:     "Allow calls to an endpoint which was disabled",
+					ArgsUsage: "<service> <endpoint>",
+					Action:    enableEndpoint,
+				},
+				{
+					Name:  "disabled-endpoints",
+					Usage: "List the disabled endpoints",
+					Flags: []cli.Flag{
+						&cli.BoolFlag{
+							Name:  "history",
+							Usage: "List the endpoints disabled and enabled over time",
+						},
+						&cli.IntFlag{
+							Name:  "limit",
+							Usage: "Maximum number of changes to list",
+							Value: 100,
+						},
+					},
+					Action: listDisabledEndpoints,
+				},
+				{
+					Name:   "residency",
+					Usage:  "Manage the regions the data of namespaces can be written to",
+					Action: listResidency,
+					Subcommands: []*cli.Command{
+						{
+							Name:      "set",
+							Usage:     "Restrict the data of a namespace to regions, e.g. micro admin residency set eu-west-1,eu-central-1",
+							ArgsUsage: "<regions>",
+							Flags:     []cli.Flag{residencyNamespaceFlag},
+							Action:    setResidency,
+						},
+						{
+							Name:   "delete",
+							Usage:  "Allow the data of a namespace to be written to any region",
+							Flags:  []cli.Flag{residencyNamespaceFlag},
+							Action: deleteResidency,
+						},
+						{
+							Name:   "list",
+							Usage:  "List the residency policies",
+							Action: listResidency,
+						},
+					},
+				},
+				{
+					Name:   "capture",
+					Usage:  "Capture the exchanges with the api for a route to a HAR file to debug clients",
+					Action: listCaptures,
+					Subcommands: []*cli.Command{
+						{
+							Name:      "start",
+							Usage:     "Start capturing the exchanges for a route, e.g. micro admin capture start /users --duration 10m",
+							ArgsUsage: "<route>",
+							Flags: []cli.Flag{
+								&cli.StringFlag{
+									Name:  "host",
+									Usage: "Only capture the exchanges for the host",
+								},
+								&cli.DurationFlag{
+									Name:  "duration",
+									Usage: "Set how long to capture for",
+									Value: capture.DefaultDuration,
+								},
+								&cli.IntFlag{
+									Name:  "limit",
+									Usage: "Set the number of exchanges each replica of the api captures",
+									Value: capture.DefaultLimit,
+								},
+							},
+							Action: startCapture,
+						},
+						{
+							Name:      "stop",
+							Usage:     "Stop a capture before it expires",
+							ArgsUsage: "<id>",
+							Action:    stopCapture,
+						},
+						{
+							Name:      "get",
+							Usage:     "Get the HAR of the exchanges captured",
+							ArgsUsage: "<id>",
+							Flags: []cli.Flag{
+								&cli.StringFlag{
+									Name:    "output",
+									Aliases: []string{"o"},
+									Usage:   "Write the HAR to a file rather than stdout",
+								},
+							},
+							Action: getCapture,
+						},
+						{
+							Name:      "delete",
+							Usage:     "Delete a capture and the exchanges captured",
+							ArgsUsage: "<id>",
+							Action:    deleteCapture,
+						},
+						{
+							Name:   "list",
+							Usage:  "List the captures",
+							Action: listCaptures,
+						},
+					},
+				},
+				{
+					Name:   "policy",
+					Usage:  "Manage the policies of the artifacts namespaces can deploy",
+					Action: listPolicies,
+					Subcommands: []*cli.Command{
+						{
+							Name:  "require-signed-artifacts",
+							Usage: "Only deploy builds with a provenance attestation signed by a trusted key to a namespace",
+							Description: `Builds by the runtime are attested with their SBOM and provenance, signed with the
+--build_signing_key of the runtime. Once required, services which weren't built by the runtime or
+whose attestation isn't signed by the runtime or one of the keys provided fail to deploy.`,
+							Flags: []cli.Flag{
+								residencyNamespaceFlag,
+								&cli.StringSliceFlag{
+									Name:  "key",
+									Usage: "Base64 encoded ed25519 public key trusted in addition to the key of the runtime",
+								},
+								&cli.BoolFlag{
+									Name:  "disable",
+									Usage: "Allow unsigned artifacts to be deployed to the namespace again",
+								},
+							},
+							Action: requireSignedArtifacts,
+						},
+						{
+							Name:  "vulnerabilities",
+							Usage: "Block deploys of builds with vulnerabilities of a severity or above to a namespace",
+							Description: `The dependencies in the SBOM of each build are scanned for known vulnerabilities before it's
+deployed. Deploys with vulnerabilities of the threshold severity or above are blocked, or only
+alerted on with --warn, unless the vulnerability is exempted with micro admin exemptions add.`,
+							Flags: []cli.Flag{
+								residencyNamespaceFlag,
+								&cli.StringFlag{
+									Name:  "threshold",
+									Usage: "Lowest severity which violates the policy, one of low, medium, high or critical",
+									Value: "high",
+								},
+								&cli.BoolFlag{
+									Name:  "warn",
+									Usage: "Alert on deploys which violate the policy rather than blocking them",
+								},
+								&cli.BoolFlag{
+									Name:  "disable",
+									Usage: "Stop scanning the builds deployed to the namespace",
+								},
+							},
+							Action: setVulnerabilityPolicy,
+						},
+						{
+							Name:   "list",
+							Usage:  "List the artifact policies",
+							Action: listPolicies,
+						},
+					},
+				},
+				{
+					Name:   "exemptions",
+					Usage:  "Manage the vulnerabilities exempted from the policy of a namespace",
+					Flags:  []cli.Flag{residencyNamespaceFlag},
+					Action: listExemptions,
+					Subcommands: []*cli.Command{
+						{
+							Name:      "add",
+							Usage:     "Exempt a vulnerability from the policy, e.g. micro admin exemptions add CVE-2021-1234 --reason \"not reachable\"",
+							ArgsUsage: "<id>",
+							Flags: []cli.Flag{
+								residencyNamespaceFlag,
+								&cli.StringFlag{
+									Name:  "reason",
+									Usage: "Reason the vulnerability is exempted, recorded for review",
+								},
+								&cli.DurationFlag{
+									Name:  "ttl",
+									Usage: "How long the exemption lasts",
+									Value: vuln.DefaultExemptionTTL,
+								},
+							},
+							Action: addExemption,
+						},
+						{
+							Name:      "remove",
+							Usage:     "Remove the exemption of a vulnerability",
+							ArgsUsage: "<id>",
+							Flags:     []cli.Flag{residencyNamespaceFlag},
+							Action:    removeExemption,
+						},
+						{
+							Name:   "list",
+							Usage:  "List the exemptions, including those which have expired",
+							Flags:  []cli.Flag{residencyNamespaceFlag},
+							Action: listExemptions,
+						},
+					},
+				},
+			},
+		},
+	)
+}
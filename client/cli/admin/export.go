@@ -0,0 +1,279 @@
+package admin
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/service/router"
+	"github.com/micro/micro/v3/service/router/history"
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/store"
+	authns "github.com/micro/micro/v3/util/auth/namespace"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// the tables of the platform services' state, in the micro database
+	authTable   = "auth"
+	configTable = "config"
+)
+
+// exportAttempts is how many times the state is read before giving up if it keeps changing
+const exportAttempts = 5
+
+var (
+	// authPrefixes are the prefixes of the auth records which are exported. Sessions, refresh tokens
+	// and failed logins are excluded since they're live credentials or short lived, and tied to the
+	// cluster they were created in.
+	authPrefixes = []string{"account", "accountByName", "rules", "scimGroup"}
+)
+
+// exportState writes the state of the namespace to an archive
+func exportState(ctx *cli.Context) error {
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	a, err := snapshotState(ns)
+	if err != nil {
+		return err
+	}
+
+	output := ctx.String("output")
+	if len(output) == 0 {
+		output = fmt.Sprintf("micro-%v-%v.tar.gz", ns, a.Manifest.Created.Format("20060102150405"))
+	}
+	if output == "-" {
+		return a.Write(os.Stdout)
+	}
+
+	// write to a temporary file first so a failed export doesn't leave a partial archive
+	tmp, err := ioutil.TempFile(filepath.Dir(output), ".micro-export-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := a.Write(tmp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Error writing archive: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), output); err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %v auth records, %v config records, %v routes and %v services to %v\n",
+		len(a.Auth), len(a.Config), len(a.Routes), len(a.Services), output)
+	return nil
+}
+
+// snapshotState reads the state of the namespace until two reads in a row match, so the archive is
+// a consistent snapshot rather than a mix of the state before and after a concurrent change. The
+// records are compared by their etags, which change every time they're written.
+func snapshotState(ns string) (*archive, error) {
+	prev, err := readState(ns)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < exportAttempts; i++ {
+		a, err := readState(ns)
+		if err != nil {
+			return nil, err
+		}
+		if fingerprint(a) == fingerprint(prev) {
+			return a, nil
+		}
+		prev = a
+	}
+	return nil, fmt.Errorf("The state of namespace %v kept changing during the export, try again", ns)
+}
+
+// readState reads the state of the namespace once
+func readState(ns string) (*archive, error) {
+	a := &archive{Manifest: manifest{Created: time.Now(), Namespace: ns}}
+
+	// accounts and rules
+	for _, p := range authPrefixes {
+		recs, err := store.Read(p+"/"+ns+"/", store.ReadPrefix(), store.ReadFrom(authns.DefaultNamespace, authTable))
+		if err != nil && err != store.ErrNotFound {
+			return nil, fmt.Errorf("Error reading auth records: %v", err)
+		}
+		a.Auth = append(a.Auth, recs...)
+	}
+
+	// config, which is stored as a single record for the namespace
+	recs, err := store.Read(ns, store.ReadFrom(authns.DefaultNamespace, configTable))
+	if err != nil && err != store.ErrNotFound {
+		return nil, fmt.Errorf("Error reading config: %v", err)
+	}
+	a.Config = recs
+
+	// routes are rebuilt from the registry so they're exported on a best effort basis. The table
+	// has the routes of every namespace, only those of this one are exported.
+	routes, err := router.DefaultRouter.Table().Read()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: unable to read routes: %v\n", err)
+	}
+	for _, r := range routes {
+		if r.Network == ns {
+			a.Routes = append(a.Routes, r)
+		}
+	}
+
+	// the runtime specs of the services
+	srvs, err := runtime.Read(runtime.ReadNamespace(ns))
+	if err != nil {
+		return nil, fmt.Errorf("Error reading services: %v", err)
+	}
+	for _, s := range srvs {
+		a.Services = append(a.Services, &service{
+			Name:     s.Name,
+			Version:  s.Version,
+			Source:   s.Source,
+			Metadata: s.Metadata,
+		})
+	}
+	return a, nil
+}
+
+// fingerprint of the state in the archive, the same if the state hasn't changed
+func fingerprint(a *archive) string {
+	var keys []string
+	for _, recs := range [][]*store.Record{a.Auth, a.Config} {
+		for _, r := range recs {
+			keys = append(keys, r.Key+"@"+r.Etag)
+		}
+	}
+	for _, r := range a.Routes {
+		keys = append(keys, fmt.Sprintf("route/%v/%v", r.Hash(), r.Metric))
+	}
+	for _, s := range a.Services {
+		keys = append(keys, fmt.Sprintf("service/%v@%v/%v/%v", s.Name, s.Version, s.Source, s.Metadata))
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, "\n")
+}
+
+// authRecordNamespace returns the namespace of an exported auth record, which has a key of the form
+// <prefix>/<namespace>/<id>
+func authRecordNamespace(key string) (string, bool) {
+	parts := strings.SplitN(key, "/", 3)
+	if len(parts) != 3 || len(parts[2]) == 0 {
+		return "", false
+	}
+	for _, p := range authPrefixes {
+		if parts[0] == p {
+			return parts[1], true
+		}
+	}
+	return "", false
+}
+
+// importState restores the state in an archive. Existing resources are left unchanged unless
+// overwrite is set.
+func importState(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: archive")
+	}
+
+	var r io.Reader = os.Stdin
+	if path := ctx.Args().First(); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	a, err := readArchive(r)
+	if err != nil {
+		return err
+	}
+	ns := a.Manifest.Namespace
+	overwrite := ctx.Bool("overwrite")
+
+	var restored, skipped int
+	write := func(table string, rec *store.Record) error {
+		if !overwrite {
+			_, err := store.Read(rec.Key, store.ReadFrom(authns.DefaultNamespace, table))
+			if err == nil {
+				skipped++
+				return nil
+			} else if err != store.ErrNotFound {
+				return err
+			}
+		}
+		restored++
+		return store.DefaultStore.Write(rec, store.WriteTo(authns.DefaultNamespace, table))
+	}
+
+	// check everything belongs to the namespace before restoring any of it
+	for _, rec := range a.Auth {
+		if recNs, ok := authRecordNamespace(rec.Key); !ok || recNs != ns {
+			return fmt.Errorf("Invalid auth record %v for namespace %v", rec.Key, ns)
+		}
+	}
+	for _, route := range a.Routes {
+		if route.Network != ns {
+			return fmt.Errorf("Invalid route to %v for namespace %v", route.Service, ns)
+		}
+	}
+
+	for _, rec := range a.Auth {
+		if err := write(authTable, rec); err != nil {
+			return fmt.Errorf("Error restoring auth record: %v", err)
+		}
+	}
+	for _, rec := range a.Config {
+		if err := write(configTable, rec); err != nil {
+			return fmt.Errorf("Error restoring config: %v", err)
+		}
+	}
+
+	routes := history.Table(router.DefaultRouter.Table(), ns, currentAccount(ctx))
+	for _, route := range a.Routes {
+		if err := routes.Create(route); err != nil && err != router.ErrDuplicateRoute {
+			fmt.Fprintf(os.Stderr, "Warning: unable to restore routes, they'll be rebuilt from the registry: %v\n", err)
+			break
+		}
+	}
+
+	existing, err := runtime.Read(runtime.ReadNamespace(ns))
+	if err != nil {
+		return fmt.Errorf("Error reading services: %v", err)
+	}
+	running := map[string]bool{}
+	for _, s := range existing {
+		running[s.Name+"@"+s.Version] = true
+	}
+	var created int
+	for _, s := range a.Services {
+		if running[s.Name+"@"+s.Version] {
+			continue
+		}
+		srv := &runtime.Service{Name: s.Name, Version: s.Version, Source: s.Source, Metadata: s.Metadata}
+		if err := runtime.Create(srv, runtime.CreateNamespace(ns)); err != nil {
+			return fmt.Errorf("Error creating service %v: %v", s.Name, err)
+		}
+		created++
+	}
+
+	fmt.Printf("Restored %v records (%v already existed) and created %v services in namespace %v\n", restored, skipped, created, ns)
+	return nil
+}
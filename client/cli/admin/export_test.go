@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/micro/micro/v3/service/router"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthRecordNamespace(t *testing.T) {
+	tcs := []struct {
+		key string
+		ns  string
+		ok  bool
+	}{
+		{key: "account/foo/1", ns: "foo", ok: true},
+		{key: "rules/foo/bar/1", ns: "foo", ok: true},
+		// the namespace must be the second segment, not any segment
+		{key: "account/bar/foo/1", ns: "bar", ok: true},
+		{key: "refresh/foo/1"},
+		{key: "session/foo/1"},
+		{key: "account/foo/"},
+		{key: "account/foo"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.key, func(t *testing.T) {
+			ns, ok := authRecordNamespace(tc.key)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.ns, ns)
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := &archive{
+		Auth:   []*store.Record{{Key: "account/foo/1", Etag: "1"}},
+		Routes: []router.Route{{Service: "helloworld", Address: "10.0.0.1:8080", Network: "foo"}},
+	}
+	b := &archive{
+		Auth:   []*store.Record{{Key: "account/foo/1", Etag: "1"}},
+		Routes: []router.Route{{Service: "helloworld", Address: "10.0.0.1:8080", Network: "foo"}},
+	}
+	assert.Equal(t, fingerprint(a), fingerprint(b))
+
+	// a record written between the reads changes its etag
+	b.Auth[0].Etag = "2"
+	assert.NotEqual(t, fingerprint(a), fingerprint(b))
+}
@@ -0,0 +1,143 @@
+package admin
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/token"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/util/killswitch"
+	"github.com/urfave/cli/v2"
+)
+
+// disableEndpoint rejects calls to the endpoint of a service on every instance
+func disableEndpoint(ctx *cli.Context) error {
+	if ctx.Args().Len() < 2 {
+		return fmt.Errorf("Missing arguments: service and endpoint")
+	}
+	if err := killswitch.ParseEndpoint(ctx.Args().Get(1)); err != nil {
+		return err
+	}
+	if len(ctx.String("reason")) == 0 {
+		return fmt.Errorf("Missing flag: reason")
+	}
+	ns, err := currentNamespace(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := &killswitch.Switch{
+		Service:  ctx.Args().Get(0),
+		Endpoint: ctx.Args().Get(1),
+		Reason:   ctx.String("reason"),
+		Account:  currentAccount(ctx),
+	}
+	if ttl := ctx.Duration("ttl"); ttl > 0 {
+		s.Expiry = time.Now().Add(ttl)
+	}
+	if err := killswitch.Disable(ns, s); err != nil {
+		return fmt.Errorf("Error disabling endpoint: %v", err)
+	}
+
+	if s.Expiry.IsZero() {
+		fmt.Printf("Disabled %v %v\n", s.Service, s.Endpoint)
+	} else {
+		fmt.Printf("Disabled %v %v until %v\n", s.Service, s.Endpoint, s.Expiry.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// enableEndpoint allows calls to an endpoint which was disabled
+func enableEndpoint(ctx *cli.Context) error {
+	if ctx.Args().Len() < 2 {
+		return fmt.Errorf("Missing arguments: service and endpoint")
+	}
+	ns, err := currentNamespace(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := &killswitch.Switch{
+		Service:  ctx.Args().Get(0),
+		Endpoint: ctx.Args().Get(1),
+		Account:  currentAccount(ctx),
+		Created:  time.Now(),
+	}
+	if err := killswitch.Enable(ns, s); err != nil {
+		return fmt.Errorf("Error enabling endpoint: %v", err)
+	}
+
+	fmt.Printf("Enabled %v %v\n", s.Service, s.Endpoint)
+	return nil
+}
+
+// listDisabledEndpoints prints the disabled endpoints, or the changes made to them if history is set
+func listDisabledEndpoints(ctx *cli.Context) error {
+	ns, err := currentNamespace(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	if ctx.Bool("history") {
+		evs, err := killswitch.History(ns, uint(ctx.Int("limit")))
+		if err != nil {
+			return fmt.Errorf("Error reading history: %v", err)
+		}
+		fmt.Fprintln(w, strings.Join([]string{"Time", "Change", "Service", "Endpoint", "Account", "Reason"}, "\t\t"))
+		for _, e := range evs {
+			s := e.Switch
+			fmt.Fprintln(w, strings.Join([]string{s.Created.Format(time.RFC3339), e.Type, s.Service, s.Endpoint, s.Account, s.Reason}, "\t\t"))
+		}
+		return nil
+	}
+
+	switches, err := killswitch.List(ns)
+	if err != nil {
+		return fmt.Errorf("Error listing disabled endpoints: %v", err)
+	}
+	fmt.Fprintln(w, strings.Join([]string{"Service", "Endpoint", "Disabled", "Expires", "Account", "Reason"}, "\t\t"))
+	for _, s := range switches {
+		expiry := "never"
+		if !s.Expiry.IsZero() {
+			expiry = s.Expiry.Format(time.RFC3339)
+		}
+		fmt.Fprintln(w, strings.Join([]string{s.Service, s.Endpoint, s.Created.Format(time.RFC3339), expiry, s.Account, s.Reason}, "\t\t"))
+	}
+	return nil
+}
+
+func currentNamespace(ctx *cli.Context) (string, error) {
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return "", err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return "", fmt.Errorf("Error getting namespace: %v", err)
+	}
+	return ns, nil
+}
+
+// currentAccount returns the name of the account logged in, it's recorded in the audit log
+func currentAccount(ctx *cli.Context) string {
+	tok, err := token.Get(ctx)
+	if err != nil {
+		return ""
+	}
+	acc, err := auth.Inspect(tok.AccessToken)
+	if err != nil {
+		return ""
+	}
+	if len(acc.Name) > 0 {
+		return acc.Name
+	}
+	return acc.ID
+}
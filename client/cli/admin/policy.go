@@ -0,0 +1,210 @@
+package admin
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/micro/v3/service/runtime/artifact"
+	"github.com/micro/micro/v3/service/runtime/vuln"
+	"github.com/urfave/cli/v2"
+)
+
+// requireSignedArtifacts only allows signed artifacts to be deployed to the namespace
+func requireSignedArtifacts(ctx *cli.Context) error {
+	ns, err := residencyNamespace(ctx)
+	if err != nil {
+		return err
+	}
+
+	p := &artifact.Policy{
+		Namespace:     ns,
+		RequireSigned: !ctx.Bool("disable"),
+		Keys:          ctx.StringSlice("key"),
+		Account:       currentAccount(ctx),
+	}
+	if err := artifact.SetPolicy(p); err != nil {
+		return fmt.Errorf("Error setting artifact policy: %v", err)
+	}
+
+	if p.RequireSigned {
+		fmt.Printf("Only signed artifacts can be deployed to %v\n", ns)
+	} else {
+		fmt.Printf("Unsigned artifacts can be deployed to %v\n", ns)
+	}
+	return nil
+}
+
+// setVulnerabilityPolicy sets the severity of the vulnerabilities which block deploys to the
+// namespace
+func setVulnerabilityPolicy(ctx *cli.Context) error {
+	ns, err := residencyNamespace(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool("disable") {
+		if err := vuln.DeletePolicy(ns); err != nil {
+			return fmt.Errorf("Error deleting vulnerability policy: %v", err)
+		}
+		fmt.Printf("Builds deployed to %v are no longer scanned\n", ns)
+		return nil
+	}
+
+	threshold, err := vuln.ParseSeverity(ctx.String("threshold"))
+	if err != nil {
+		return err
+	}
+	p := &vuln.Policy{
+		Namespace: ns,
+		Threshold: threshold,
+		Warn:      ctx.Bool("warn"),
+		Account:   currentAccount(ctx),
+	}
+	if err := vuln.SetPolicy(p); err != nil {
+		return fmt.Errorf("Error setting vulnerability policy: %v", err)
+	}
+
+	verb := "blocked"
+	if p.Warn {
+		verb = "alerted on"
+	}
+	fmt.Printf("Deploys to %v with vulnerabilities of %v severity or above will be %v\n", ns, threshold, verb)
+	return nil
+}
+
+// listPolicies prints the artifact and vulnerability policies of each namespace
+func listPolicies(ctx *cli.Context) error {
+	artifactPolicies, err := artifact.ListPolicies()
+	if err != nil {
+		return fmt.Errorf("Error listing artifact policies: %v", err)
+	}
+	vulnPolicies, err := vuln.ListPolicies()
+	if err != nil {
+		return fmt.Errorf("Error listing vulnerability policies: %v", err)
+	}
+
+	type policy struct {
+		artifact *artifact.Policy
+		vuln     *vuln.Policy
+	}
+	policies := map[string]*policy{}
+	get := func(ns string) *policy {
+		if _, ok := policies[ns]; !ok {
+			policies[ns] = &policy{}
+		}
+		return policies[ns]
+	}
+	for _, p := range artifactPolicies {
+		get(p.Namespace).artifact = p
+	}
+	for _, p := range vulnPolicies {
+		get(p.Namespace).vuln = p
+	}
+
+	namespaces := make([]string, 0, len(policies))
+	for ns := range policies {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"Namespace", "Require Signed", "Keys", "Vulnerabilities", "Updated", "Account"}, "\t\t"))
+	for _, ns := range namespaces {
+		p := policies[ns]
+		signed, keys, vulns := "false", "0", "-"
+		var updated time.Time
+		var account string
+		if p.artifact != nil {
+			signed = strconv.FormatBool(p.artifact.RequireSigned)
+			keys = strconv.Itoa(len(p.artifact.Keys))
+			updated, account = p.artifact.Updated, p.artifact.Account
+		}
+		if p.vuln != nil {
+			vulns = "block " + p.vuln.Threshold.String()
+			if p.vuln.Warn {
+				vulns = "warn " + p.vuln.Threshold.String()
+			}
+			if p.vuln.Updated.After(updated) {
+				updated, account = p.vuln.Updated, p.vuln.Account
+			}
+		}
+		fmt.Fprintln(w, strings.Join([]string{ns, signed, keys, vulns, updated.Format(time.RFC3339), account}, "\t\t"))
+	}
+	return nil
+}
+
+// addExemption exempts a vulnerability from the policy of the namespace
+func addExemption(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return fmt.Errorf("Missing argument: id")
+	}
+	if len(ctx.String("reason")) == 0 {
+		return fmt.Errorf("Missing flag: reason")
+	}
+	ns, err := residencyNamespace(ctx)
+	if err != nil {
+		return err
+	}
+
+	e := &vuln.Exemption{
+		Namespace: ns,
+		ID:        ctx.Args().First(),
+		Reason:    ctx.String("reason"),
+		Account:   currentAccount(ctx),
+		Created:   time.Now(),
+	}
+	e.Expires = e.Created.Add(ctx.Duration("ttl"))
+	if err := vuln.Exempt(e); err != nil {
+		return fmt.Errorf("Error adding exemption: %v", err)
+	}
+
+	fmt.Printf("%v is exempted from the policy of %v until %v\n", e.ID, ns, e.Expires.Format(time.RFC3339))
+	return nil
+}
+
+// removeExemption removes the exemption of a vulnerability from the policy of the namespace
+func removeExemption(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return fmt.Errorf("Missing argument: id")
+	}
+	ns, err := residencyNamespace(ctx)
+	if err != nil {
+		return err
+	}
+	if err := vuln.Unexempt(ns, ctx.Args().First()); err != nil {
+		return fmt.Errorf("Error removing exemption: %v", err)
+	}
+	return nil
+}
+
+// listExemptions prints the exemptions of the namespace
+func listExemptions(ctx *cli.Context) error {
+	ns, err := residencyNamespace(ctx)
+	if err != nil {
+		return err
+	}
+	exemptions, err := vuln.ListExemptions(ns)
+	if err != nil {
+		return fmt.Errorf("Error listing exemptions: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"ID", "Reason", "Expires", "Account"}, "\t\t"))
+	for _, e := range exemptions {
+		expires := e.Expires.Format(time.RFC3339)
+		if e.Expired() {
+			expires += " (expired)"
+		}
+		fmt.Fprintln(w, strings.Join([]string{e.ID, e.Reason, expires, e.Account}, "\t\t"))
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package admin
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/micro/v3/util/residency"
+	"github.com/urfave/cli/v2"
+)
+
+// setResidency restricts the data of the namespace to the regions provided
+func setResidency(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: regions")
+	}
+	ns, err := residencyNamespace(ctx)
+	if err != nil {
+		return err
+	}
+
+	var regions []string
+	for _, arg := range ctx.Args().Slice() {
+		for _, r := range strings.Split(arg, ",") {
+			if r = strings.TrimSpace(r); len(r) > 0 {
+				regions = append(regions, r)
+			}
+		}
+	}
+
+	p := &residency.Policy{Namespace: ns, Regions: regions, Account: currentAccount(ctx)}
+	if err := residency.Set(p); err != nil {
+		return fmt.Errorf("Error setting residency policy: %v", err)
+	}
+
+	fmt.Printf("The data of %v can only be written to %v\n", ns, strings.Join(regions, ", "))
+	return nil
+}
+
+// deleteResidency allows the data of the namespace to be written to any region
+func deleteResidency(ctx *cli.Context) error {
+	ns, err := residencyNamespace(ctx)
+	if err != nil {
+		return err
+	}
+	if err := residency.Delete(ns); err != nil {
+		return fmt.Errorf("Error deleting residency policy: %v", err)
+	}
+
+	fmt.Printf("The data of %v can be written to any region\n", ns)
+	return nil
+}
+
+// listResidency prints the residency policies
+func listResidency(ctx *cli.Context) error {
+	policies, err := residency.List()
+	if err != nil {
+		return fmt.Errorf("Error listing residency policies: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"Namespace", "Regions", "Updated", "Account"}, "\t\t"))
+	for _, p := range policies {
+		fmt.Fprintln(w, strings.Join([]string{p.Namespace, strings.Join(p.Regions, ","), p.Updated.Format(time.RFC3339), p.Account}, "\t\t"))
+	}
+	return nil
+}
+
+// residencyNamespace returns the namespace flag, defaulting to the current namespace
+func residencyNamespace(ctx *cli.Context) (string, error) {
+	if ns := ctx.String("namespace"); len(ns) > 0 {
+		return ns, nil
+	}
+	return currentNamespace(ctx)
+}
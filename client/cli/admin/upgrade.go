@@ -0,0 +1,251 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	ver "github.com/hashicorp/go-version"
+	proto "github.com/micro/micro/v3/proto/debug"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/store"
+	authns "github.com/micro/micro/v3/util/auth/namespace"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	upgradeTable = "upgrade"
+	upgradeKey   = "state"
+)
+
+var (
+	// upgradeOrder is the order the core services are upgraded in. The registry goes first since
+	// every other service uses it to find its dependencies, followed by the services which hold
+	// state and then the ones which depend on them. The edge services (proxy, api and web) go last
+	// so clients aren't routed to a new version before its dependencies have been upgraded.
+	upgradeOrder = []string{
+		"registry",
+		"store",
+		"broker",
+		"events",
+		"config",
+		"auth",
+		"quota",
+		"connections",
+		"runtime",
+		"network",
+		"proxy",
+		"api",
+		"web",
+	}
+)
+
+// release of the platform
+type release struct {
+	Version string `json:"version"`
+	Image   string `json:"image"`
+}
+
+// upgradeState is written once an upgrade completes so it can be rolled back
+type upgradeState struct {
+	Current  release   `json:"current"`
+	Previous *release  `json:"previous,omitempty"`
+	Updated  time.Time `json:"updated"`
+}
+
+// checkCompatible returns an error if the platform can't be upgraded from one version to the
+// other without an outage. Services of different major versions use incompatible protocols and
+// the platform can only move forward one minor version at a time since the services of both
+// versions run side by side during the upgrade.
+func checkCompatible(from, to string, force bool) error {
+	fv, err := ver.NewVersion(from)
+	if err != nil {
+		return fmt.Errorf("Invalid current version %v: %v", from, err)
+	}
+	tv, err := ver.NewVersion(to)
+	if err != nil {
+		return fmt.Errorf("Invalid version %v: %v", to, err)
+	}
+
+	fs, ts := fv.Segments(), tv.Segments()
+	switch {
+	case fv.Equal(tv):
+		return fmt.Errorf("The platform is already running %v", to)
+	case fs[0] != ts[0]:
+		return fmt.Errorf("Upgrading from %v to %v isn't supported, the protocols of different major versions are incompatible", from, to)
+	case force:
+		return nil
+	case tv.LessThan(fv):
+		return fmt.Errorf("%v is older than the current version %v, use --rollback to revert an upgrade", to, from)
+	case ts[1]-fs[1] > 1:
+		return fmt.Errorf("Upgrading from %v to %v skips more than one minor version, upgrade to v%v.%v first", from, to, fs[0], fs[1]+1)
+	}
+	return nil
+}
+
+// upgrade rolls the core services to a new version one at a time, waiting for each to become
+// healthy before moving on. If a service fails to become healthy the services already upgraded
+// are rolled back to the previous version.
+func upgrade(ctx *cli.Context) error {
+	state, err := readUpgradeState()
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		// the platform hasn't been upgraded before so assume it's running the version of the cli
+		state = &upgradeState{Current: release{
+			Version: ctx.App.Version,
+			Image:   ctx.String("image") + ":" + ctx.App.Version,
+		}}
+	}
+
+	var target release
+	if ctx.Bool("rollback") {
+		if state.Previous == nil {
+			return fmt.Errorf("There is no previous version to roll back to")
+		}
+		target = *state.Previous
+	} else {
+		if ctx.Args().Len() == 0 {
+			return fmt.Errorf("Missing argument: version")
+		}
+		target = release{Version: ctx.Args().First()}
+		target.Image = ctx.String("image") + ":" + target.Version
+		if err := checkCompatible(state.Current.Version, target.Version, ctx.Bool("force")); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Upgrading the platform from %v to %v\n", state.Current.Version, target.Version)
+	if ctx.Bool("dry-run") {
+		for _, srv := range upgradeOrder {
+			fmt.Printf("Would update %v to %v\n", srv, target.Image)
+		}
+		return nil
+	}
+
+	timeout := ctx.Duration("timeout")
+	for i, srv := range upgradeOrder {
+		fmt.Printf("Updating %v to %v\n", srv, target.Image)
+		if err := rollService(srv, target.Image, timeout); err != nil {
+			fmt.Printf("Error updating %v: %v\n", srv, err)
+			rollbackServices(upgradeOrder[:i+1], state.Current.Image, timeout)
+			return fmt.Errorf("Upgrade to %v failed, the platform was rolled back to %v", target.Version, state.Current.Version)
+		}
+	}
+
+	prev := state.Current
+	state = &upgradeState{Current: target, Previous: &prev, Updated: time.Now()}
+	if err := writeUpgradeState(state); err != nil {
+		return fmt.Errorf("The platform was upgraded but the state couldn't be saved: %v", err)
+	}
+
+	fmt.Printf("The platform is now running %v\n", target.Version)
+	return nil
+}
+
+// rollbackServices reverts the services to the image in reverse order
+func rollbackServices(srvs []string, image string, timeout time.Duration) {
+	for i := len(srvs) - 1; i >= 0; i-- {
+		fmt.Printf("Rolling back %v to %v\n", srvs[i], image)
+		if err := rollService(srvs[i], image, timeout); err != nil {
+			fmt.Printf("Error rolling back %v: %v\n", srvs[i], err)
+		}
+	}
+}
+
+// rollService updates the image of a core service and waits for the nodes which were running
+// before the update to be replaced by healthy ones
+func rollService(name, image string, timeout time.Duration) error {
+	old, err := serviceNodes(name)
+	if err != nil {
+		return err
+	}
+
+	srv := &runtime.Service{Name: name, Version: "latest"}
+	if err := runtime.Update(srv, runtime.UpdateNamespace(authns.DefaultNamespace), runtime.UpdateImage(image)); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(time.Second * 2)
+
+		nodes, err := serviceNodes(name)
+		if err != nil || len(nodes) == 0 {
+			continue
+		}
+
+		replaced := true
+		for id, addr := range nodes {
+			if _, ok := old[id]; ok {
+				replaced = false
+				break
+			}
+			if !healthy(name, addr) {
+				replaced = false
+				break
+			}
+		}
+		if replaced {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("timed out waiting for %v to become healthy", name)
+}
+
+// serviceNodes returns the address of each node of the service, keyed by id
+func serviceNodes(name string) (map[string]string, error) {
+	srvs, err := registry.DefaultRegistry.GetService(name, registry.GetDomain(authns.DefaultNamespace))
+	if err == registry.ErrNotFound {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	nodes := map[string]string{}
+	for _, srv := range srvs {
+		for _, n := range srv.Nodes {
+			nodes[n.Id] = n.Address
+		}
+	}
+	return nodes, nil
+}
+
+// healthy returns true if the node responds to a health check
+func healthy(name, address string) bool {
+	req := client.NewRequest(name, "Debug.Health", &proto.HealthRequest{})
+	rsp := &proto.HealthResponse{}
+	if err := client.DefaultClient.Call(context.Background(), req, rsp, client.WithAddress(address)); err != nil {
+		return false
+	}
+	return rsp.Status == "ok"
+}
+
+func readUpgradeState() (*upgradeState, error) {
+	recs, err := store.Read(upgradeKey, store.ReadFrom(authns.DefaultNamespace, upgradeTable))
+	if err == store.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Error reading upgrade state: %v", err)
+	}
+
+	var state upgradeState
+	if err := json.Unmarshal(recs[0].Value, &state); err != nil {
+		return nil, fmt.Errorf("Error reading upgrade state: %v", err)
+	}
+	return &state, nil
+}
+
+func writeUpgradeState(state *upgradeState) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	rec := &store.Record{Key: upgradeKey, Value: b}
+	return store.DefaultStore.Write(rec, store.WriteTo(authns.DefaultNamespace, upgradeTable))
+}
@@ -0,0 +1,34 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCompatible(t *testing.T) {
+	tt := []struct {
+		Name  string
+		From  string
+		To    string
+		Force bool
+		Error bool
+	}{
+		{Name: "Patch", From: "v3.5.0", To: "v3.5.1"},
+		{Name: "Minor", From: "v3.5.0", To: "v3.6.0"},
+		{Name: "SameVersion", From: "v3.5.0", To: "v3.5.0", Error: true},
+		{Name: "MajorVersion", From: "v3.5.0", To: "v4.0.0", Error: true},
+		{Name: "MajorVersionForced", From: "v3.5.0", To: "v4.0.0", Force: true, Error: true},
+		{Name: "SkipMinor", From: "v3.5.0", To: "v3.7.0", Error: true},
+		{Name: "SkipMinorForced", From: "v3.5.0", To: "v3.7.0", Force: true},
+		{Name: "Downgrade", From: "v3.5.0", To: "v3.4.0", Error: true},
+		{Name: "InvalidVersion", From: "v3.5.0", To: "latest", Error: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := checkCompatible(tc.From, tc.To, tc.Force)
+			assert.Equal(t, tc.Error, err != nil, err)
+		})
+	}
+}
@@ -0,0 +1,195 @@
+// Package cli implements the `micro api` subcommands
+// for example:
+//   micro api export --format postman
+//   micro api openapi -o openapi.json
+//   micro api domains add api.customer.com --namespace customer
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/service/api/openapi"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/util/helper"
+	"github.com/urfave/cli/v2"
+)
+
+func init() {
+	cmd.Register(&cli.Command{
+		Name:   "api",
+		Usage:  "Commands for the api gateway",
+		Action: helper.UnexpectedSubcommand,
+		Subcommands: []*cli.Command{
+			{
+				Name:      "export",
+				Usage:     "Export the routes of the api as a collection",
+				UsageText: "micro api export --format postman [service...]",
+				Description: `Exports a collection with a request for each endpoint served by the api gateway, with example
+bodies generated from the endpoint's request type. The collection includes a request which
+generates a token from the id and secret variables and sets it for the other requests. Postman
+collections can also be imported by Insomnia. If no services are specified then every service
+in the namespace is included.`,
+				Action: export,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "format",
+						Usage: "Format of the collection, currently only postman",
+						Value: "postman",
+					},
+					&cli.StringFlag{
+						Name:  "address",
+						Usage: "Address of the api gateway set as the baseUrl variable",
+						Value: "http://localhost:8080",
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "File to write the collection to, defaults to stdout",
+					},
+				},
+			},
+			{
+				Name:      "openapi",
+				Usage:     "Generate an OpenAPI document for the api",
+				UsageText: "micro api openapi [service...]",
+				Description: `Generates an OpenAPI 3 document describing each endpoint served by the api gateway, read from
+the endpoints registered by the services, so it can be used to generate API docs and clients. The
+api gateway also serves the document for the namespace requested at /openapi.json. If no
+services are specified then every service in the namespace is included.`,
+				Action: generateOpenAPI,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "address",
+						Usage: "Address of the api gateway set as the server of the document",
+						Value: "http://localhost:8080",
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "File to write the document to, defaults to stdout",
+					},
+				},
+			},
+			{
+				Name:  "domains",
+				Usage: "Manage the custom domains of namespaces",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "namespace",
+						Usage: "Only list the domains of the namespace",
+					},
+				},
+				Action: listDomains,
+				Subcommands: []*cli.Command{
+					{
+						Name:      "add",
+						Usage:     "Add a custom domain for a namespace",
+						ArgsUsage: "<host>",
+						Description: `Requests to the host are routed to the services of the namespace, the Micro-Namespace
+header is ignored. When ACME is enabled certificates are issued for the host on the first request,
+its DNS should point at the api before it's added.`,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "namespace",
+								Usage: "Namespace requests to the host are routed to, defaults to the current namespace",
+							},
+						},
+						Action: addDomain,
+					},
+					{
+						Name:      "remove",
+						Usage:     "Remove a custom domain",
+						ArgsUsage: "<host>",
+						Action:    removeDomain,
+					},
+				},
+			},
+		},
+	})
+}
+
+func export(ctx *cli.Context) error {
+	if f := ctx.String("format"); f != "postman" {
+		return fmt.Errorf("unsupported format %q", f)
+	}
+
+	ns, services, err := getServices(ctx)
+	if err != nil {
+		return err
+	}
+	return write(ctx, Postman(fmt.Sprintf("micro %s", ns), ctx.String("address"), ns, services))
+}
+
+func generateOpenAPI(ctx *cli.Context) error {
+	ns, services, err := getServices(ctx)
+	if err != nil {
+		return err
+	}
+	return write(ctx, openapi.Generate(fmt.Sprintf("micro %s", ns), ctx.String("address"), services))
+}
+
+// getServices returns the current namespace and the services passed as args, or every service
+// in the namespace if there are none
+func getServices(ctx *cli.Context) (string, []*registry.Service, error) {
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return "", nil, err
+	}
+
+	names := ctx.Args().Slice()
+	if len(names) == 0 {
+		list, err := registry.DefaultRegistry.ListServices(registry.ListDomain(ns))
+		if err != nil {
+			return "", nil, err
+		}
+		for _, s := range list {
+			names = append(names, s.Name)
+		}
+	}
+
+	var services []*registry.Service
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		srvs, err := registry.DefaultRegistry.GetService(name, registry.GetDomain(ns))
+		if err == registry.ErrNotFound || len(srvs) == 0 {
+			return "", nil, fmt.Errorf("service %s not found", name)
+		} else if err != nil {
+			return "", nil, err
+		}
+		services = append(services, srvs[0])
+	}
+
+	return ns, services, nil
+}
+
+// write v as indented JSON to the output file, or stdout if there isn't one
+func write(ctx *cli.Context, v interface{}) error {
+	var w io.Writer = os.Stdout
+	if out := ctx.String("output"); len(out) > 0 {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
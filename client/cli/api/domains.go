@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	pb "github.com/micro/micro/v3/proto/api"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+	"github.com/urfave/cli/v2"
+)
+
+func addDomain(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: host")
+	}
+	ns := ctx.String("namespace")
+	if len(ns) == 0 {
+		env, err := util.GetEnv(ctx)
+		if err != nil {
+			return err
+		}
+		if ns, err = namespace.Get(env.Name); err != nil {
+			return err
+		}
+	}
+	cli := pb.NewApiService("api", client.DefaultClient)
+
+	_, err := cli.AddDomain(context.DefaultContext, &pb.AddDomainRequest{
+		Domain: &pb.Domain{Host: ctx.Args().First(), Namespace: ns},
+	}, client.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error adding domain: %v", err)
+	}
+
+	fmt.Printf("Requests to %v are routed to namespace %v\n", ctx.Args().First(), ns)
+	return nil
+}
+
+func removeDomain(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: host")
+	}
+	cli := pb.NewApiService("api", client.DefaultClient)
+
+	_, err := cli.RemoveDomain(context.DefaultContext, &pb.RemoveDomainRequest{
+		Host: ctx.Args().First(),
+	}, client.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error removing domain: %v", err)
+	}
+
+	fmt.Printf("Removed domain %v\n", ctx.Args().First())
+	return nil
+}
+
+func listDomains(ctx *cli.Context) error {
+	cli := pb.NewApiService("api", client.DefaultClient)
+
+	rsp, err := cli.ReadDomains(context.DefaultContext, &pb.ReadDomainsRequest{
+		Namespace: ctx.String("namespace"),
+	}, client.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error reading domains: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"Host", "Namespace", "Added"}, "\t\t"))
+	for _, d := range rsp.Domains {
+		added := time.Unix(d.Created, 0).Format(time.RFC3339)
+		fmt.Fprintln(w, strings.Join([]string{d.Host, d.Namespace, added}, "\t\t"))
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/micro/micro/v3/service/api/openapi"
+	"github.com/micro/micro/v3/service/registry"
+)
+
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// Collection is a postman v2.1 collection
+type Collection struct {
+	Info     Info        `json:"info"`
+	Auth     *Auth       `json:"auth,omitempty"`
+	Variable []*Variable `json:"variable,omitempty"`
+	Item     []*Item     `json:"item"`
+}
+
+// Info describes the collection
+type Info struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+// Auth used by the requests in the collection
+type Auth struct {
+	Type   string      `json:"type"`
+	Bearer []*Variable `json:"bearer,omitempty"`
+}
+
+// Variable of the collection, referenced as {{key}}
+type Variable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Type  string `json:"type,omitempty"`
+}
+
+// Item is either a folder of items or a request
+type Item struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Item        []*Item  `json:"item,omitempty"`
+	Request     *Request `json:"request,omitempty"`
+	Event       []*Event `json:"event,omitempty"`
+}
+
+// Request made by an item
+type Request struct {
+	Method string      `json:"method"`
+	Header []*Variable `json:"header,omitempty"`
+	Body   *Body       `json:"body,omitempty"`
+	URL    *URL        `json:"url"`
+	Auth   *Auth       `json:"auth,omitempty"`
+}
+
+// Body of a request
+type Body struct {
+	Mode    string                 `json:"mode"`
+	Raw     string                 `json:"raw"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// URL of a request
+type URL struct {
+	Raw  string   `json:"raw"`
+	Host []string `json:"host"`
+	Path []string `json:"path"`
+}
+
+// Event runs a script before or after a request
+type Event struct {
+	Listen string  `json:"listen"`
+	Script *Script `json:"script"`
+}
+
+// Script run by an event
+type Script struct {
+	Type string   `json:"type"`
+	Exec []string `json:"exec"`
+}
+
+// Postman returns a collection with a folder for each service containing a request for each of
+// its endpoints. Requests use the bearer token from the token variable, which is set by the
+// generate token request in the auth folder.
+func Postman(name, address, namespace string, services []*registry.Service) *Collection {
+	c := &Collection{
+		Info: Info{Name: name, Schema: postmanSchema},
+		Auth: &Auth{
+			Type:   "bearer",
+			Bearer: []*Variable{{Key: "token", Value: "{{token}}", Type: "string"}},
+		},
+		Variable: []*Variable{
+			{Key: "baseUrl", Value: address},
+			{Key: "namespace", Value: namespace},
+			{Key: "id", Value: ""},
+			{Key: "secret", Value: ""},
+			{Key: "token", Value: ""},
+		},
+	}
+
+	// the token request can't use the token it generates
+	tokenReq := newRequest("POST", "/auth/Auth/Token", `{
+  "id": "{{id}}",
+  "secret": "{{secret}}"
+}`)
+	tokenReq.Auth = &Auth{Type: "noauth"}
+	c.Item = append(c.Item, &Item{
+		Name: "auth",
+		Item: []*Item{{
+			Name:        "Generate token",
+			Description: "Generates a token from the id and secret variables and sets the token variable",
+			Request:     tokenReq,
+			Event: []*Event{{
+				Listen: "test",
+				Script: &Script{Type: "text/javascript", Exec: []string{
+					`pm.collectionVariables.set("token", pm.response.json().token.access_token);`,
+				}},
+			}},
+		}},
+	})
+
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	for _, s := range services {
+		folder := &Item{Name: s.Name}
+		for _, ep := range s.Endpoints {
+			if it := endpointItem(s.Name, ep); it != nil {
+				folder.Item = append(folder.Item, it)
+			}
+		}
+		if len(folder.Item) == 0 {
+			continue
+		}
+		sort.Slice(folder.Item, func(i, j int) bool { return folder.Item[i].Name < folder.Item[j].Name })
+		c.Item = append(c.Item, folder)
+	}
+
+	return c
+}
+
+// endpointItem returns the request for an endpoint, served at the route of the endpoint. Nil is
+// returned for endpoints which can't be called via the api.
+func endpointItem(service string, ep *registry.Endpoint) *Item {
+	method, path, ok := openapi.Route(service, ep)
+	if !ok {
+		return nil
+	}
+
+	var body string
+	if method != "GET" && method != "DELETE" {
+		b, _ := json.MarshalIndent(example(ep.Request, 0), "", "  ")
+		body = string(b)
+	}
+
+	req := newRequest(method, path, body)
+	if ep.Metadata["stream"] == "true" {
+		// ask the gateway to write each message as a line of JSON
+		req.Header = append(req.Header, &Variable{Key: "Accept", Value: "application/x-ndjson"})
+	}
+
+	return &Item{
+		Name:        ep.Name,
+		Description: ep.Metadata["description"],
+		Request:     req,
+	}
+}
+
+func newRequest(method, path, body string) *Request {
+	r := &Request{
+		Method: method,
+		Header: []*Variable{{Key: "Micro-Namespace", Value: "{{namespace}}"}},
+		URL: &URL{
+			Raw:  "{{baseUrl}}" + path,
+			Host: []string{"{{baseUrl}}"},
+			Path: strings.Split(strings.Trim(path, "/"), "/"),
+		},
+	}
+	if len(body) > 0 {
+		r.Header = append(r.Header, &Variable{Key: "Content-Type", Value: "application/json"})
+		r.Body = &Body{
+			Mode:    "raw",
+			Raw:     body,
+			Options: map[string]interface{}{"raw": map[string]string{"language": "json"}},
+		}
+	}
+	return r
+}
+
+// example returns an example value for a registered type, the zero value of each field as
+// encoded in JSON
+func example(v *registry.Value, depth int) interface{} {
+	if v == nil {
+		return map[string]interface{}{}
+	}
+	if len(v.Values) > 0 {
+		obj := map[string]interface{}{}
+		// stop at recursive types
+		if depth > 5 {
+			return obj
+		}
+		for _, f := range v.Values {
+			obj[f.Name] = example(f, depth+1)
+		}
+		return obj
+	}
+	return exampleType(v.Type)
+}
+
+func exampleType(typ string) interface{} {
+	switch {
+	case typ == "[]uint8":
+		// bytes are base64 encoded
+		return ""
+	case strings.HasPrefix(typ, "[]"):
+		return []interface{}{exampleType(strings.TrimPrefix(typ, "[]"))}
+	case strings.HasPrefix(typ, "map["):
+		if i := strings.Index(typ, "]"); i > 0 {
+			return map[string]interface{}{"key": exampleType(typ[i+1:])}
+		}
+	}
+
+	switch typ {
+	case "string":
+		return ""
+	case "bool":
+		return false
+	case "int32", "uint32", "float32", "float64":
+		return 0
+	case "int64", "uint64":
+		// encoded as strings to avoid losing precision
+		return "0"
+	case "google.protobuf.Timestamp":
+		return "1970-01-01T00:00:00Z"
+	case "google.protobuf.Duration":
+		return "0s"
+	case "google.protobuf.Struct":
+		return map[string]interface{}{}
+	}
+	// the fields of the type aren't described, e.g. enums and nested messages
+	return nil
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostman(t *testing.T) {
+	services := []*registry.Service{
+		{
+			Name: "helloworld",
+			Endpoints: []*registry.Endpoint{
+				{
+					Name: "Helloworld.Call",
+					Request: &registry.Value{Type: "Request", Values: []*registry.Value{
+						{Name: "name", Type: "string"},
+						{Name: "count", Type: "int64"},
+						{Name: "tags", Type: "[]string"},
+						{Name: "created", Type: "google.protobuf.Timestamp"},
+					}},
+					Metadata: map[string]string{"description": "Say hello"},
+				},
+				{
+					Name:     "Helloworld.Get",
+					Metadata: map[string]string{"endpoint": "Helloworld.Get", "method": "GET", "path": "/hello"},
+				},
+				{
+					Name:     "Helloworld.Stream",
+					Metadata: map[string]string{"stream": "true"},
+				},
+				{
+					Name:     "Handle",
+					Metadata: map[string]string{"subscriber": "true"},
+				},
+			},
+		},
+	}
+
+	c := Postman("micro", "http://localhost:8080", "foo", services)
+	assert.Equal(t, postmanSchema, c.Info.Schema)
+	assert.Equal(t, "bearer", c.Auth.Type)
+	if !assert.Len(t, c.Item, 2) {
+		return
+	}
+
+	// the token request doesn't use the bearer token
+	token := c.Item[0].Item[0].Request
+	assert.Equal(t, "noauth", token.Auth.Type)
+	assert.Equal(t, "{{baseUrl}}/auth/Auth/Token", token.URL.Raw)
+
+	items := c.Item[1].Item
+	if !assert.Len(t, items, 3) {
+		return
+	}
+
+	call := items[0]
+	assert.Equal(t, "Say hello", call.Description)
+	assert.Equal(t, "POST", call.Request.Method)
+	assert.Equal(t, []string{"helloworld", "Helloworld", "Call"}, call.Request.URL.Path)
+	assert.JSONEq(t, `{"name": "", "count": "0", "tags": [""], "created": "1970-01-01T00:00:00Z"}`, call.Request.Body.Raw)
+
+	get := items[1]
+	assert.Equal(t, "GET", get.Request.Method)
+	assert.Equal(t, "{{baseUrl}}/hello", get.Request.URL.Raw)
+	assert.Nil(t, get.Request.Body)
+
+	stream := items[2]
+	assert.Contains(t, stream.Request.Header, &Variable{Key: "Accept", Value: "application/x-ndjson"})
+}
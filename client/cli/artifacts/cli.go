@@ -0,0 +1,234 @@
+// Package artifacts implements the `micro artifacts` subcommands
+// for example:
+//   micro artifacts list --namespace foo
+//   micro artifacts gc --dry-run
+//   micro artifacts attestation sha256:2c26b46b68ff --sbom
+//   micro artifacts vulnerabilities sha256:2c26b46b68ff --scan
+package artifacts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dustin/go-humanize"
+	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/service/runtime/artifact"
+	"github.com/micro/micro/v3/service/runtime/vuln"
+	"github.com/micro/micro/v3/util/helper"
+	"github.com/urfave/cli/v2"
+)
+
+func init() {
+	cmd.Register(&cli.Command{
+		Name:   "artifacts",
+		Usage:  "Manage the content addressed store of builds",
+		Action: helper.UnexpectedSubcommand,
+		Subcommands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "List the artifacts and the builds referencing them",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "namespace",
+						Usage: "Only list the artifacts referenced by the namespace",
+					},
+				},
+				Action: list,
+			},
+			{
+				Name:  "gc",
+				Usage: "Delete the artifacts which are no longer referenced",
+				Description: `Artifacts are deleted once no build references them, after a grace period so an artifact
+isn't deleted between being uploaded and referenced. The runtime also collects artifacts hourly.`,
+				Flags: []cli.Flag{
+					&cli.DurationFlag{
+						Name:  "grace",
+						Usage: "How long an unreferenced artifact is kept for",
+						Value: artifact.DefaultGrace,
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "List the artifacts which would be deleted without deleting them",
+					},
+				},
+				Action: gc,
+			},
+			{
+				Name:      "attestation",
+				Usage:     "Print the provenance attestation of an artifact, including its SBOM",
+				ArgsUsage: "<digest>",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "sbom",
+						Usage: "Only print the CycloneDX SBOM",
+					},
+					&cli.StringSliceFlag{
+						Name:  "key",
+						Usage: "Verify the attestation was signed by the base64 encoded ed25519 public key",
+					},
+				},
+				Action: attestation,
+			},
+			{
+				Name:      "vulnerabilities",
+				Usage:     "List the vulnerabilities found in the dependencies of an artifact",
+				ArgsUsage: "<digest>",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "scan",
+						Usage: "Scan the artifact now rather than listing the vulnerabilities found when it was last deployed",
+					},
+				},
+				Action: vulnerabilities,
+			},
+		},
+	})
+}
+
+func list(ctx *cli.Context) error {
+	artifacts, err := artifact.List(ctx.String("namespace"))
+	if err != nil {
+		return fmt.Errorf("Error listing artifacts: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"Digest", "Size", "Created", "Refs"}, "\t\t"))
+	for _, a := range artifacts {
+		refs := strings.Join(a.Refs, ",")
+		if len(refs) == 0 {
+			refs = "-"
+		}
+		fmt.Fprintln(w, strings.Join([]string{
+			artifact.Short(a.Digest),
+			humanize.Bytes(uint64(a.Size)),
+			humanize.Time(a.Created),
+			refs,
+		}, "\t\t"))
+	}
+	return nil
+}
+
+func gc(ctx *cli.Context) error {
+	opts := []artifact.GCOption{artifact.GCGrace(ctx.Duration("grace"))}
+	if ctx.Bool("dry-run") {
+		opts = append(opts, artifact.GCDryRun())
+	}
+
+	deleted, err := artifact.GC(opts...)
+	if err != nil {
+		return fmt.Errorf("Error collecting artifacts: %v", err)
+	}
+
+	var size int64
+	for _, a := range deleted {
+		size += a.Size
+		fmt.Println(artifact.Short(a.Digest))
+	}
+
+	verb := "Deleted"
+	if ctx.Bool("dry-run") {
+		verb = "Would delete"
+	}
+	fmt.Printf("%v %d unreferenced artifacts, %v\n", verb, len(deleted), humanize.Bytes(uint64(size)))
+	return nil
+}
+
+func attestation(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return fmt.Errorf("Missing argument: digest")
+	}
+	digest, err := resolveDigest(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	a, err := artifact.GetAttestation(digest)
+	if err != nil {
+		return fmt.Errorf("Error reading attestation: %v", err)
+	}
+	if keys := ctx.StringSlice("key"); len(keys) > 0 {
+		if err := a.Verify(keys...); err != nil {
+			return fmt.Errorf("Error verifying attestation: %v", err)
+		}
+	}
+
+	var v interface{} = a
+	if ctx.Bool("sbom") {
+		if a.SBOM == nil {
+			return fmt.Errorf("The artifact has no SBOM, its source didn't contain a go.mod")
+		}
+		v = a.SBOM
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// resolveDigest returns the full digest of an artifact from the digest or its short form
+func resolveDigest(d string) (string, error) {
+	artifacts, err := artifact.List("")
+	if err != nil {
+		return "", fmt.Errorf("Error listing artifacts: %v", err)
+	}
+	for _, a := range artifacts {
+		if a.Digest == d || artifact.Short(a.Digest) == d {
+			return a.Digest, nil
+		}
+	}
+	// builds by the local runtime are attested but aren't stored as artifacts
+	if len(d) == len(artifact.Digest(nil)) {
+		return d, nil
+	}
+	return "", fmt.Errorf("Artifact %v not found", d)
+}
+
+func vulnerabilities(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return fmt.Errorf("Missing argument: digest")
+	}
+	digest, err := resolveDigest(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	var r *vuln.Report
+	if ctx.Bool("scan") {
+		a, err := artifact.GetAttestation(digest)
+		if err != nil {
+			return fmt.Errorf("Error reading attestation: %v", err)
+		}
+		if a.SBOM == nil {
+			return fmt.Errorf("The artifact has no SBOM, its source didn't contain a go.mod")
+		}
+		r, err = vuln.Scan(digest, a.SBOM)
+		if err != nil {
+			return fmt.Errorf("Error scanning artifact: %v", err)
+		}
+	} else {
+		r, err = vuln.GetReport(digest)
+		if err == vuln.ErrNotFound {
+			return fmt.Errorf("The artifact hasn't been scanned, use --scan to scan it")
+		} else if err != nil {
+			return fmt.Errorf("Error reading report: %v", err)
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"ID", "Severity", "Component", "Version", "Fixed"}, "\t\t"))
+	for _, v := range r.Vulnerabilities {
+		fixed := v.Fixed
+		if len(fixed) == 0 {
+			fixed = "-"
+		}
+		fmt.Fprintln(w, strings.Join([]string{v.ID, v.Severity.String(), v.Component, v.Version, fixed}, "\t\t"))
+	}
+	return nil
+}
@@ -119,6 +119,33 @@ func deleteAccount(ctx *cli.Context) error {
 	return nil
 }
 
+func unlockAccount(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: ID")
+	}
+	cli := pb.NewAccountsService("auth", client.DefaultClient)
+
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	_, err = cli.Unlock(context.DefaultContext, &pb.UnlockAccountRequest{
+		Id:      ctx.Args().First(),
+		Options: &pb.Options{Namespace: ns},
+	}, client.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error unlocking account: %v", err)
+	}
+
+	fmt.Println("Account unlocked")
+	return nil
+}
+
 func updateAccount(ctx *cli.Context) error {
 	if ctx.Args().Len() == 0 {
 		return fmt.Errorf("Missing argument: ID")
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+	"github.com/urfave/cli/v2"
+)
+
+func listAPIKeys(ctx *cli.Context) error {
+	cli := pb.NewAPIKeysService("auth", client.DefaultClient)
+
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	rsp, err := cli.List(context.DefaultContext, &pb.ListAPIKeysRequest{
+		AccountId: ctx.String("account"),
+		Options:   &pb.Options{Namespace: ns},
+	}, client.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error listing api keys: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"ID", "Name", "Scopes", "Created", "Expiry"}, "\t\t"))
+	for _, k := range rsp.ApiKeys {
+		scopes := strings.Join(k.Scopes, ", ")
+		if len(scopes) == 0 {
+			scopes = "n/a"
+		}
+		expiry := "never"
+		if k.Expiry > 0 {
+			expiry = time.Unix(k.Expiry, 0).Format(time.RFC3339)
+		}
+		created := time.Unix(k.Created, 0).Format(time.RFC3339)
+		fmt.Fprintln(w, strings.Join([]string{k.Id, k.Name, scopes, created, expiry}, "\t\t"))
+	}
+
+	return nil
+}
+
+func createAPIKey(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: name")
+	}
+	cli := pb.NewAPIKeysService("auth", client.DefaultClient)
+
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	var expiry int64
+	if d := ctx.Duration("expiry"); d > 0 {
+		expiry = time.Now().Add(d).Unix()
+	}
+
+	rsp, err := cli.Create(context.DefaultContext, &pb.CreateAPIKeyRequest{
+		AccountId: ctx.String("account"),
+		Name:      ctx.Args().First(),
+		Scopes:    ctx.StringSlice("scopes"),
+		Expiry:    expiry,
+		Options:   &pb.Options{Namespace: ns},
+	}, client.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error creating api key: %v", err)
+	}
+
+	fmt.Printf("API key ID: %v\n", rsp.ApiKey.Id)
+	fmt.Printf("API key: %v\n", rsp.Key)
+	fmt.Println("The key can't be retrieved later, store it somewhere safe")
+	return nil
+}
+
+func deleteAPIKey(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: ID")
+	}
+	cli := pb.NewAPIKeysService("auth", client.DefaultClient)
+
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	_, err = cli.Revoke(context.DefaultContext, &pb.RevokeAPIKeyRequest{
+		Id:      ctx.Args().First(),
+		Options: &pb.Options{Namespace: ns},
+	}, client.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error deleting api key: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+	"github.com/urfave/cli/v2"
+)
+
+func listAudit(ctx *cli.Context) error {
+	cli := pb.NewAuditService("auth", client.DefaultClient)
+
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	req := &pb.ListAuditRecordsRequest{
+		AccountId: ctx.String("account"),
+		Service:   ctx.String("service"),
+		Endpoint:  ctx.String("endpoint"),
+		Decision:  ctx.String("decision"),
+		Limit:     ctx.Uint64("limit"),
+		Offset:    ctx.Uint64("offset"),
+		Options:   &pb.Options{Namespace: ns},
+	}
+	if d := ctx.Duration("since"); d > 0 {
+		req.Since = time.Now().Add(-d).Unix()
+	}
+	rsp, err := cli.List(context.DefaultContext, req, client.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error listing audit records: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"Time", "Account", "Service", "Endpoint", "Decision", "Reason"}, "\t\t"))
+	for _, r := range rsp.Records {
+		account := r.AccountId
+		if len(account) == 0 {
+			account = "n/a"
+		}
+		ts := time.Unix(r.Timestamp, 0).Format(time.RFC3339)
+		fmt.Fprintln(w, strings.Join([]string{ts, account, r.Service, r.Endpoint, r.Decision, r.Reason}, "\t\t"))
+	}
+
+	return nil
+}
@@ -12,11 +12,11 @@ var (
 	ruleFlags = []cli.Flag{
 		&cli.StringFlag{
 			Name:  "scope",
-			Usage: "the scope to amend, e.g. 'user' or '*', leave blank to make public",
+			Usage: "the scope or role to amend, e.g. 'admin' or '*', leave blank to make public",
 		},
 		&cli.StringFlag{
 			Name:  "resource",
-			Usage: "The resource to amend in the format type:name:endpoint, e.g. service:auth:*",
+			Usage: "The resource to amend in the format type:name:endpoint, e.g. service:auth:*, or the endpoint of a service, e.g. users.Users.Delete or users.Users.*",
 		},
 		&cli.StringFlag{
 			Name:  "access",
@@ -63,6 +63,22 @@ func init() {
 							Usage:  "List auth accounts",
 							Action: listAccounts,
 						},
+						{
+							Name:   "clients",
+							Usage:  "List oauth clients",
+							Action: listClients,
+						},
+						{
+							Name:  "apikeys",
+							Usage: "List api keys, e.g. micro auth list apikeys --account=billing",
+							Flags: []cli.Flag{
+								&cli.StringFlag{
+									Name:  "account",
+									Usage: "The account to list the keys of, defaults to your account",
+								},
+							},
+							Action: listAPIKeys,
+						},
 					},
 				},
 				{
@@ -84,6 +100,36 @@ func init() {
 							}),
 							Action: createAccount,
 						},
+						{
+							Name:  "client",
+							Usage: "Create an oauth client, e.g. micro auth create client --scopes=billing billing-export",
+							Flags: []cli.Flag{
+								&cli.StringSliceFlag{
+									Name:  "scopes",
+									Usage: "Comma separated list of scopes the client can request",
+								},
+							},
+							Action: createClient,
+						},
+						{
+							Name:  "apikey",
+							Usage: "Create an api key, e.g. micro auth create apikey --scopes=billing --expiry=720h billing-export",
+							Flags: []cli.Flag{
+								&cli.StringFlag{
+									Name:  "account",
+									Usage: "The account to issue the key to, defaults to your account",
+								},
+								&cli.StringSliceFlag{
+									Name:  "scopes",
+									Usage: "Comma separated list of scopes of the key, defaults to the scopes of the account",
+								},
+								&cli.DurationFlag{
+									Name:  "expiry",
+									Usage: "How long the key is valid for, keys don't expire by default",
+								},
+							},
+							Action: createAPIKey,
+						},
 					},
 				},
 				{
@@ -102,6 +148,126 @@ func init() {
 							Flags:  accountFlags,
 							Action: deleteAccount,
 						},
+						{
+							Name:   "client",
+							Usage:  "Delete an oauth client",
+							Action: deleteClient,
+						},
+						{
+							Name:   "apikey",
+							Usage:  "Revoke an api key",
+							Action: deleteAPIKey,
+						},
+					},
+				},
+				{
+					Name:  "scim",
+					Usage: "Manage the groups provisioned by an identity provider",
+					Subcommands: []*cli.Command{
+						{
+							Name:   "groups",
+							Usage:  "List the groups and the scopes mapped to them",
+							Action: listGroups,
+						},
+						{
+							Name:  "map",
+							Usage: "Set the scopes granted to members of a group, e.g. micro auth scim map Engineering --scopes=admin",
+							Flags: []cli.Flag{
+								&cli.StringSliceFlag{
+									Name:  "scopes",
+									Usage: "Comma separated list of scopes to grant members of the group",
+								},
+							},
+							Action: mapGroup,
+						},
+					},
+				},
+				{
+					Name:  "audit",
+					Usage: "List the authorization decisions recorded for calls, e.g. micro auth audit --decision=denied --since=1h",
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "account",
+							Usage: "Only list the calls made by the account",
+						},
+						&cli.StringFlag{
+							Name:  "service",
+							Usage: "Only list the calls to the service",
+						},
+						&cli.StringFlag{
+							Name:  "endpoint",
+							Usage: "Only list the calls to the endpoint, e.g. Users.Delete",
+						},
+						&cli.StringFlag{
+							Name:  "decision",
+							Usage: "Only list the calls which were granted or denied",
+						},
+						&cli.DurationFlag{
+							Name:  "since",
+							Usage: "Only list the calls made within the duration, e.g. 24h",
+						},
+						&cli.Uint64Flag{
+							Name:  "limit",
+							Usage: "The max number of records to list",
+							Value: 100,
+						},
+						&cli.Uint64Flag{
+							Name:  "offset",
+							Usage: "The number of matching records to skip",
+						},
+					},
+					Action: listAudit,
+				},
+				{
+					Name:  "keys",
+					Usage: "Manage the keys which sign the tokens of the namespace",
+					Subcommands: []*cli.Command{
+						{
+							Name:   "list",
+							Usage:  "List the signing keys of the namespace",
+							Action: listSigningKeys,
+						},
+						{
+							Name:  "rotate",
+							Usage: "Rotate the signing key of the namespace, e.g. micro auth keys rotate --revoke",
+							Description: `A new key signs the tokens of the namespace from then on. The previous key verifies the tokens
+it signed until the grace period ends, unless it's revoked. Revoking the key invalidates the tokens
+it signed, e.g. when the key was compromised, without affecting other namespaces.`,
+							Flags: []cli.Flag{
+								&cli.BoolFlag{
+									Name:  "revoke",
+									Usage: "Revoke the previous keys rather than letting them expire",
+								},
+							},
+							Action: rotateSigningKey,
+						},
+					},
+				},
+				{
+					Name:   "unlock",
+					Usage:  "Unlock an account locked after too many failed logins, e.g. micro auth unlock john",
+					Action: unlockAccount,
+				},
+				{
+					Name:  "sessions",
+					Usage: "Manage the browser sessions of accounts",
+					Subcommands: []*cli.Command{
+						{
+							Name:   "list",
+							Usage:  "List the sessions of an account, e.g. micro auth sessions list john",
+							Action: listSessions,
+						},
+						{
+							Name:  "revoke",
+							Usage: "Revoke the sessions of an account, e.g. micro auth sessions revoke john",
+							Flags: []cli.Flag{
+								&cli.StringFlag{
+									Name:  "session",
+									Usage: "The id of a single session to revoke",
+								},
+							},
+							Action: revokeSessions,
+						},
 					},
 				},
 				{
@@ -146,6 +312,10 @@ func init() {
 					Usage:   "Username to use for login",
 					Aliases: []string{"email"},
 				},
+				&cli.BoolFlag{
+					Name:  "oidc",
+					Usage: "Log in with the OpenID Connect provider configured with --auth_oidc_issuer",
+				},
 			},
 		},
 		&cli.Command{
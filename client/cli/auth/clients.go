@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+	"github.com/urfave/cli/v2"
+)
+
+func listClients(ctx *cli.Context) error {
+	cli := pb.NewOAuthService("auth", client.DefaultClient)
+
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	rsp, err := cli.ListClients(context.DefaultContext, &pb.ListClientsRequest{
+		Options: &pb.Options{Namespace: ns},
+	}, client.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error listing clients: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"ID", "Name", "Scopes"}, "\t\t"))
+	for _, c := range rsp.Clients {
+		scopes := strings.Join(c.Scopes, ", ")
+		if len(scopes) == 0 {
+			scopes = "n/a"
+		}
+		fmt.Fprintln(w, strings.Join([]string{c.Id, c.Name, scopes}, "\t\t"))
+	}
+
+	return nil
+}
+
+func createClient(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: name")
+	}
+	cli := pb.NewOAuthService("auth", client.DefaultClient)
+
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	rsp, err := cli.CreateClient(context.DefaultContext, &pb.CreateClientRequest{
+		Name:    ctx.Args().First(),
+		Scopes:  ctx.StringSlice("scopes"),
+		Options: &pb.Options{Namespace: ns},
+	}, client.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error creating client: %v", err)
+	}
+
+	fmt.Printf("Client ID: %v\n", rsp.Client.Id)
+	fmt.Printf("Client secret: %v\n", rsp.Secret)
+	fmt.Println("The secret can't be retrieved later, store it somewhere safe")
+	return nil
+}
+
+func deleteClient(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: ID")
+	}
+	cli := pb.NewOAuthService("auth", client.DefaultClient)
+
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	_, err = cli.DeleteClient(context.DefaultContext, &pb.DeleteClientRequest{
+		Id:      ctx.Args().First(),
+		Options: &pb.Options{Namespace: ns},
+	}, client.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error deleting client: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+	"github.com/urfave/cli/v2"
+)
+
+func listSigningKeys(ctx *cli.Context) error {
+	cli := pb.NewSigningKeysService("auth", client.DefaultClient)
+
+	ns, err := currentNamespace(ctx)
+	if err != nil {
+		return err
+	}
+
+	rsp, err := cli.List(context.DefaultContext, &pb.ListSigningKeysRequest{
+		Options: &pb.Options{Namespace: ns},
+	}, client.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error listing signing keys: %v", err)
+	}
+	if len(rsp.Keys) == 0 {
+		fmt.Println("The namespace uses the default key")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"ID", "Created", "Status"}, "\t\t"))
+	for _, k := range rsp.Keys {
+		status := "active"
+		if !k.Active {
+			status = "expires " + time.Unix(k.Expiry, 0).Format(time.RFC3339)
+		}
+		created := time.Unix(k.Created, 0).Format(time.RFC3339)
+		fmt.Fprintln(w, strings.Join([]string{k.Id, created, status}, "\t\t"))
+	}
+	return nil
+}
+
+func rotateSigningKey(ctx *cli.Context) error {
+	cli := pb.NewSigningKeysService("auth", client.DefaultClient)
+
+	ns, err := currentNamespace(ctx)
+	if err != nil {
+		return err
+	}
+
+	rsp, err := cli.Rotate(context.DefaultContext, &pb.RotateSigningKeyRequest{
+		Options: &pb.Options{Namespace: ns},
+		Revoke:  ctx.Bool("revoke"),
+	}, client.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error rotating signing key: %v", err)
+	}
+
+	fmt.Printf("Rotated the signing key of %v, the new key is %v\n", ns, rsp.Key.Id)
+	return nil
+}
+
+func currentNamespace(ctx *cli.Context) (string, error) {
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return "", err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return "", fmt.Errorf("Error getting namespace: %v", err)
+	}
+	return ns, nil
+}
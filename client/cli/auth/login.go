@@ -2,15 +2,21 @@ package cli
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/micro/micro/v3/client/cli/namespace"
 	"github.com/micro/micro/v3/client/cli/token"
 	"github.com/micro/micro/v3/client/cli/util"
 	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/auth/oidc"
 	"github.com/micro/micro/v3/util/report"
 	"github.com/urfave/cli/v2"
 	"golang.org/x/crypto/ssh/terminal"
@@ -19,6 +25,10 @@ import (
 // login flow.
 // For documentation of the flow please refer to https://github.com/micro/development/pull/223
 func login(ctx *cli.Context) error {
+	if ctx.Bool("oidc") {
+		return loginOIDC(ctx)
+	}
+
 	// otherwise assume username/password login
 
 	// get the environment
@@ -72,6 +82,84 @@ func login(ctx *cli.Context) error {
 	return nil
 }
 
+// loginOIDC logs in with the OpenID Connect provider using the authorization code flow. The
+// provider redirects the browser back to a server listening on the loopback interface, which
+// redeems the code.
+func loginOIDC(ctx *cli.Context) error {
+	p := oidc.DefaultProvider
+	if p == nil {
+		return errors.New("no OpenID Connect provider configured, set --auth_oidc_issuer or MICRO_AUTH_OIDC_ISSUER")
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	redirect := fmt.Sprintf("http://%s/callback", l.Addr())
+	state := uuid.New().String()
+	verifier, challenge := oidc.NewVerifier()
+	u, err := p.AuthCodeURL(state, challenge, redirect)
+	if err != nil {
+		l.Close()
+		return err
+	}
+
+	type result struct {
+		tok *auth.AccountToken
+		err error
+	}
+	results := make(chan result, 1)
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/callback" || q.Get("state") != state {
+			http.NotFound(w, r)
+			return
+		}
+
+		var res result
+		if e := q.Get("error"); len(e) > 0 {
+			res.err = fmt.Errorf("%v: %v", e, q.Get("error_description"))
+		} else {
+			res.tok, res.err = p.Exchange(q.Get("code"), verifier, redirect)
+		}
+		if res.err != nil {
+			http.Error(w, "Login failed: "+res.err.Error(), http.StatusUnauthorized)
+		} else {
+			fmt.Fprintln(w, "Successfully logged in, you can close this window.")
+		}
+		select {
+		case results <- res:
+		default:
+		}
+	})}
+	go srv.Serve(l)
+	defer srv.Close()
+
+	fmt.Printf("Open the following URL in your browser to log in:\n\n%v\n\n", u)
+
+	var res result
+	select {
+	case res = <-results:
+	case <-time.After(time.Minute * 5):
+		res.err = errors.New("timed out waiting for the login to complete")
+	}
+	if res.err != nil {
+		report.Errorf(ctx, "OIDC login: %v", res.err.Error())
+		return res.err
+	}
+
+	if err := token.Remove(ctx); err != nil {
+		return err
+	}
+	if err := token.Save(ctx, res.tok); err != nil {
+		report.Errorf(ctx, "OIDC login: Save token: %s", err.Error())
+		return err
+	}
+
+	fmt.Println("Successfully logged in.")
+	return nil
+}
+
 // taken from https://stackoverflow.com/questions/2137357/getpasswd-functionality-in-go
 func getPassword() (string, error) {
 	fmt.Print("Enter password: ")
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strings"
 	"text/tabwriter"
+	"unicode"
 
 	"github.com/micro/micro/v3/client/cli/namespace"
 	"github.com/micro/micro/v3/client/cli/util"
@@ -133,9 +134,9 @@ func constructRule(ctx *cli.Context) (*pb.Rule, error) {
 		return nil, fmt.Errorf("Invalid access: %v, must be granted or denied", ctx.String("access"))
 	}
 
-	resComps := strings.Split(ctx.String("resource"), ":")
-	if len(resComps) != 3 {
-		return nil, fmt.Errorf("Invalid resource, must be in the format type:name:endpoint")
+	res, err := parseResource(ctx.String("resource"))
+	if err != nil {
+		return nil, err
 	}
 
 	return &pb.Rule{
@@ -143,10 +144,26 @@ func constructRule(ctx *cli.Context) (*pb.Rule, error) {
 		Access:   access,
 		Scope:    ctx.String("scope"),
 		Priority: int32(ctx.Int("priority")),
-		Resource: &pb.Resource{
-			Type:     resComps[0],
-			Name:     resComps[1],
-			Endpoint: resComps[2],
-		},
+		Resource: res,
 	}, nil
 }
+
+// parseResource parses a resource in the format type:name:endpoint, or the endpoint of a service
+// in the format name.Endpoint, e.g. users.Users.Delete or users.Users.* for every endpoint of the
+// handler. The endpoints of services start with the name of the handler, which is capitalised.
+func parseResource(s string) (*pb.Resource, error) {
+	if comps := strings.Split(s, ":"); len(comps) == 3 {
+		return &pb.Resource{Type: comps[0], Name: comps[1], Endpoint: comps[2]}, nil
+	} else if len(comps) > 1 || len(s) == 0 {
+		return nil, fmt.Errorf("Invalid resource, must be in the format type:name:endpoint or service.Endpoint")
+	}
+
+	res := &pb.Resource{Type: "service", Name: s, Endpoint: "*"}
+	for i := 0; i < len(s)-1; i++ {
+		if s[i] == '.' && (unicode.IsUpper(rune(s[i+1])) || s[i+1] == '*') {
+			res.Name, res.Endpoint = s[:i], s[i+1:]
+			break
+		}
+	}
+	return res, nil
+}
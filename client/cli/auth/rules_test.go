@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"testing"
+
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseResource(t *testing.T) {
+	tcs := map[string]*pb.Resource{
+		"service:auth:*":     {Type: "service", Name: "auth", Endpoint: "*"},
+		"users.Users.Delete": {Type: "service", Name: "users", Endpoint: "Users.Delete"},
+		"users.Users.*":      {Type: "service", Name: "users", Endpoint: "Users.*"},
+		"users.*":            {Type: "service", Name: "users", Endpoint: "*"},
+		"users":              {Type: "service", Name: "users", Endpoint: "*"},
+	}
+	for in, res := range tcs {
+		t.Run(in, func(t *testing.T) {
+			r, err := parseResource(in)
+			assert.NoError(t, err)
+			assert.Equal(t, res.Type, r.Type)
+			assert.Equal(t, res.Name, r.Name)
+			assert.Equal(t, res.Endpoint, r.Endpoint)
+		})
+	}
+
+	for _, in := range []string{"", "service:auth"} {
+		_, err := parseResource(in)
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+	"github.com/urfave/cli/v2"
+)
+
+func listGroups(ctx *cli.Context) error {
+	cli := pb.NewSCIMService("auth", client.DefaultClient)
+
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	rsp, err := cli.ListGroups(context.DefaultContext, &pb.ListSCIMGroupsRequest{
+		Options: &pb.Options{Namespace: ns},
+	}, client.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error listing groups: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"ID", "Name", "Members", "Scopes"}, "\t\t"))
+	for _, g := range rsp.Groups {
+		scopes := strings.Join(g.Scopes, ", ")
+		if len(scopes) == 0 {
+			scopes = "n/a"
+		}
+		fmt.Fprintln(w, strings.Join([]string{g.Id, g.DisplayName, fmt.Sprintf("%d", len(g.Members)), scopes}, "\t\t"))
+	}
+
+	return nil
+}
+
+func mapGroup(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: group")
+	}
+	cli := pb.NewSCIMService("auth", client.DefaultClient)
+
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	_, err = cli.MapGroup(context.DefaultContext, &pb.MapSCIMGroupRequest{
+		Group:   ctx.Args().First(),
+		Scopes:  ctx.StringSlice("scopes"),
+		Options: &pb.Options{Namespace: ns},
+	}, client.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error mapping group: %v", err)
+	}
+
+	return nil
+}
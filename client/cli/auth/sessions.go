@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+	"github.com/urfave/cli/v2"
+)
+
+func listSessions(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: account")
+	}
+	cli := pb.NewSessionsService("auth", client.DefaultClient)
+
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	rsp, err := cli.List(context.DefaultContext, &pb.ListSessionsRequest{
+		AccountId: ctx.Args().First(),
+		Options:   &pb.Options{Namespace: ns},
+	}, client.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error listing sessions: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"ID", "Created", "Last Seen", "IP", "User Agent"}, "\t\t"))
+	for _, s := range rsp.Sessions {
+		created := time.Unix(s.Created, 0).Format(time.RFC3339)
+		lastSeen := time.Unix(s.LastSeen, 0).Format(time.RFC3339)
+		fmt.Fprintln(w, strings.Join([]string{s.Id, created, lastSeen, s.Ip, s.UserAgent}, "\t\t"))
+	}
+
+	return nil
+}
+
+func revokeSessions(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: account")
+	}
+	cli := pb.NewSessionsService("auth", client.DefaultClient)
+
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	rsp, err := cli.Revoke(context.DefaultContext, &pb.RevokeSessionRequest{
+		AccountId: ctx.Args().First(),
+		Id:        ctx.String("session"),
+		Options:   &pb.Options{Namespace: ns},
+	}, client.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error revoking sessions: %v", err)
+	}
+
+	fmt.Printf("Revoked %v session(s)\n", rsp.Revoked)
+	return nil
+}
@@ -0,0 +1,150 @@
+// Package cache is the local cache of the CLI. Registry snapshots and auth tokens are cached
+// encrypted on disk so read only commands such as `micro services` and `micro describe`
+// keep working when the micro server can't be reached.
+package cache
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/util/user"
+)
+
+var (
+	// Dir the cache is written to
+	Dir = filepath.Join(user.Dir, "cache")
+
+	// ErrNotCached is returned when reading an entry which isn't cached
+	ErrNotCached = errors.New("not cached")
+
+	// the key used to encrypt the cache, loaded once
+	keyOnce sync.Once
+	key     []byte
+	keyErr  error
+
+	// loadKey returns the base64 encoded key, replaced in tests
+	loadKey = user.GetCacheKey
+)
+
+// entry of the cache as it's written to disk before being encrypted
+type entry struct {
+	Updated time.Time       `json:"updated"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// Write the value to the cache, the path is joined to name the entry
+func Write(v interface{}, path ...string) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	b, err = json.Marshal(&entry{Updated: time.Now(), Value: b})
+	if err != nil {
+		return err
+	}
+	enc, err := Encrypt(b)
+	if err != nil {
+		return err
+	}
+
+	file := filename(path...)
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	// write to a temporary file first so a concurrent read doesn't see a partial entry
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, enc, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// Read the entry into the value, returning when the entry was written
+func Read(v interface{}, path ...string) (time.Time, error) {
+	enc, err := ioutil.ReadFile(filename(path...))
+	if os.IsNotExist(err) {
+		return time.Time{}, ErrNotCached
+	} else if err != nil {
+		return time.Time{}, err
+	}
+	b, err := Decrypt(enc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var e entry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return time.Time{}, err
+	}
+	if err := json.Unmarshal(e.Value, v); err != nil {
+		return time.Time{}, err
+	}
+	return e.Updated, nil
+}
+
+func filename(path ...string) string {
+	parts := make([]string, len(path))
+	for i, p := range path {
+		parts[i] = url.PathEscape(p)
+	}
+	return filepath.Join(Dir, filepath.Join(parts...))
+}
+
+func getKey() ([]byte, error) {
+	keyOnce.Do(func() {
+		var k string
+		if k, keyErr = loadKey(); keyErr != nil {
+			return
+		}
+		key, keyErr = base64.StdEncoding.DecodeString(k)
+	})
+	return key, keyErr
+}
+
+// Encrypt the data with the local cache key using AES-GCM, the nonce is prepended to the result
+func Encrypt(data []byte) ([]byte, error) {
+	gcm, err := newGCM()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+// Decrypt data encrypted with Encrypt
+func Decrypt(data []byte) ([]byte, error) {
+	gcm, err := newGCM()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("invalid ciphertext")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM() (cipher.AEAD, error) {
+	k, err := getKey()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(k)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/registry/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func setup(t *testing.T) {
+	dir := Dir
+	Dir = t.TempDir()
+	loadKey = func() (string, error) {
+		return base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef")), nil
+	}
+	t.Cleanup(func() {
+		Dir = dir
+		Offline = false
+	})
+}
+
+func TestReadWrite(t *testing.T) {
+	setup(t)
+
+	var v map[string]string
+	_, err := Read(&v, "local", "foo")
+	assert.Equal(t, ErrNotCached, err)
+
+	assert.NoError(t, Write(map[string]string{"secret": "value"}, "local", "foo"))
+	updated, err := Read(&v, "local", "foo")
+	assert.NoError(t, err)
+	assert.False(t, updated.IsZero())
+	assert.Equal(t, "value", v["secret"])
+
+	// the entry is encrypted at rest
+	b, err := ioutil.ReadFile(filepath.Join(Dir, "local", "foo"))
+	assert.NoError(t, err)
+	assert.False(t, strings.Contains(string(b), "secret"))
+}
+
+type failingRegistry struct {
+	registry.Registry
+	err error
+}
+
+func (f *failingRegistry) GetService(name string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.Registry.GetService(name, opts...)
+}
+
+func (f *failingRegistry) ListServices(opts ...registry.ListOption) ([]*registry.Service, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.Registry.ListServices(opts...)
+}
+
+func TestRegistry(t *testing.T) {
+	setup(t)
+
+	mem := memory.NewRegistry()
+	mem.Register(&registry.Service{
+		Name:      "helloworld",
+		Version:   "latest",
+		Nodes:     []*registry.Node{{Id: "helloworld-1", Address: "127.0.0.1:8080"}},
+		Endpoints: []*registry.Endpoint{{Name: "Helloworld.Call"}},
+	})
+	fr := &failingRegistry{Registry: mem}
+	r := Registry(fr, "local")
+
+	// nothing is cached when offline before a lookup
+	Offline = true
+	_, err := r.GetService("helloworld")
+	assert.Error(t, err)
+	Offline = false
+
+	srvs, err := r.GetService("helloworld")
+	assert.NoError(t, err)
+	assert.Len(t, srvs, 1)
+	_, err = r.ListServices()
+	assert.NoError(t, err)
+
+	// services which don't exist aren't served from the cache
+	_, err = r.GetService("missing")
+	assert.Equal(t, registry.ErrNotFound, err)
+
+	// the cached services are used when the registry fails
+	fr.err = errors.New("connection refused")
+	srvs, err = r.GetService("helloworld")
+	assert.NoError(t, err)
+	assert.Equal(t, "Helloworld.Call", srvs[0].Endpoints[0].Name)
+	list, err := r.ListServices()
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	// and when offline
+	fr.err = nil
+	Offline = true
+	srvs, err = r.GetService("helloworld")
+	assert.NoError(t, err)
+	assert.Len(t, srvs, 1)
+
+	// entries are cached per environment
+	_, err = Registry(fr, "platform").GetService("helloworld")
+	assert.Error(t, err)
+}
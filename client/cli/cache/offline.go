@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+var (
+	// Offline is set when the micro server can't be reached, registry lookups are served from
+	// the cache and calls fail immediately rather than waiting to time out
+	Offline bool
+
+	// ProbeTimeout is how long to wait connecting to the micro server before assuming the CLI
+	// is offline
+	ProbeTimeout = 2 * time.Second
+)
+
+// Reachable returns true if a connection can be made to the address
+func Reachable(addr string, timeout time.Duration) bool {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// OfflineClient returns a client which fails every request with an error explaining the micro
+// server at the address can't be reached
+func OfflineClient(c client.Client, addr string) client.Client {
+	return &offlineClient{Client: c, addr: addr}
+}
+
+type offlineClient struct {
+	client.Client
+	addr string
+}
+
+func (o *offlineClient) err() error {
+	return errors.ServiceUnavailable("micro.client", "Offline, can't reach the micro server at %v. "+
+		"Check your connection or change the environment with micro env set", o.addr)
+}
+
+func (o *offlineClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	return o.err()
+}
+
+func (o *offlineClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	return nil, o.err()
+}
+
+func (o *offlineClient) Publish(ctx context.Context, msg client.Message, opts ...client.PublishOption) error {
+	return o.err()
+}
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry"
+)
+
+// Registry returns a registry which caches the services looked up in the environment, and
+// serves them from the cache when offline or the registry returns an error
+func Registry(r registry.Registry, env string) registry.Registry {
+	return &cacheRegistry{Registry: r, env: env}
+}
+
+type cacheRegistry struct {
+	registry.Registry
+	env  string
+	once sync.Once
+}
+
+func (c *cacheRegistry) GetService(name string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	var options registry.GetOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	return c.lookup(func() ([]*registry.Service, error) {
+		return c.Registry.GetService(name, opts...)
+	}, domain(options.Domain), "service", name)
+}
+
+func (c *cacheRegistry) ListServices(opts ...registry.ListOption) ([]*registry.Service, error) {
+	var options registry.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	return c.lookup(func() ([]*registry.Service, error) {
+		return c.Registry.ListServices(opts...)
+	}, domain(options.Domain), "services")
+}
+
+// lookup the services with the registry, caching the result. When offline or the registry
+// returns an error other than not found the cached services are returned.
+func (c *cacheRegistry) lookup(fn func() ([]*registry.Service, error), domain string, path ...string) ([]*registry.Service, error) {
+	path = append([]string{c.env, "registry", domain}, path...)
+
+	var err error
+	if !Offline {
+		var srvs []*registry.Service
+		srvs, err = fn()
+		if err == nil {
+			if err := Write(srvs, path...); err != nil {
+				logger.Debugf("Error caching %v: %v", path, err)
+			}
+			return srvs, nil
+		}
+		if err == registry.ErrNotFound {
+			return nil, err
+		}
+	}
+
+	var srvs []*registry.Service
+	updated, cerr := Read(&srvs, path...)
+	if cerr == ErrNotCached && Offline {
+		return nil, fmt.Errorf("Offline and %v isn't cached", path[len(path)-1])
+	} else if cerr != nil {
+		if err == nil {
+			err = cerr
+		}
+		return nil, err
+	}
+
+	// let the user know the services may be stale, once per command
+	c.once.Do(func() {
+		reason := "offline"
+		if err != nil {
+			reason = fmt.Sprintf("the registry returned an error: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Using services cached at %v as %v\n", updated.Format(time.RFC822), reason)
+	})
+	return srvs, nil
+}
+
+func domain(d string) string {
+	if len(d) == 0 {
+		return registry.DefaultDomain
+	}
+	return d
+}
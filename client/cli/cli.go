@@ -12,14 +12,25 @@ import (
 	"github.com/micro/micro/v3/cmd"
 	"github.com/urfave/cli/v2"
 
+	_ "github.com/micro/micro/v3/client/cli/admin"
+	_ "github.com/micro/micro/v3/client/cli/api"
+	_ "github.com/micro/micro/v3/client/cli/artifacts"
 	_ "github.com/micro/micro/v3/client/cli/auth"
 	_ "github.com/micro/micro/v3/client/cli/config"
+	_ "github.com/micro/micro/v3/client/cli/doctor"
+	_ "github.com/micro/micro/v3/client/cli/events"
 	_ "github.com/micro/micro/v3/client/cli/gen"
 	_ "github.com/micro/micro/v3/client/cli/init"
+	_ "github.com/micro/micro/v3/client/cli/job"
+	_ "github.com/micro/micro/v3/client/cli/namespace/cli"
 	_ "github.com/micro/micro/v3/client/cli/network"
 	_ "github.com/micro/micro/v3/client/cli/new"
+	_ "github.com/micro/micro/v3/client/cli/router"
 	_ "github.com/micro/micro/v3/client/cli/run"
+	_ "github.com/micro/micro/v3/client/cli/scrub"
+	_ "github.com/micro/micro/v3/client/cli/sdk"
 	_ "github.com/micro/micro/v3/client/cli/store"
+	_ "github.com/micro/micro/v3/client/cli/update"
 	_ "github.com/micro/micro/v3/client/cli/user"
 )
 
@@ -121,6 +132,23 @@ func init() {
 				},
 			},
 		},
+		&cli.Command{
+			Name:  "debug",
+			Usage: "Debug a service",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "middleware",
+					Usage:  "List the wrappers active in each instance of a service, in the order requests pass through them e.g. micro debug middleware helloworld",
+					Action: util.Print(QueryMiddleware),
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "address",
+							Usage: "Set the address of the service instance to query",
+						},
+					},
+				},
+			},
+		},
 		&cli.Command{
 			Name:   "health",
 			Usage:  `Get the service health`,
@@ -186,5 +214,10 @@ func init() {
 			Usage:  "List services in the registry",
 			Action: util.Print(ListServices),
 		},
+		&cli.Command{
+			Name:   "describe",
+			Usage:  "Describe a service, including the team which owns it e.g. micro describe helloworld",
+			Action: util.Print(DescribeService),
+		},
 	)
 }
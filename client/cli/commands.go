@@ -19,6 +19,7 @@ import (
 	"github.com/micro/micro/v3/service/context/metadata"
 	"github.com/micro/micro/v3/service/registry"
 	cbytes "github.com/micro/micro/v3/util/codec/bytes"
+	"github.com/micro/micro/v3/util/ownership"
 	"github.com/serenize/snaker"
 	"github.com/urfave/cli/v2"
 )
@@ -305,6 +306,78 @@ func GetService(c *cli.Context, args []string) ([]byte, error) {
 	return []byte(strings.Join(output, "\n")), nil
 }
 
+// DescribeService returns the owner, versions and endpoints of a service
+func DescribeService(c *cli.Context, args []string) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, cli.ShowSubcommandHelp(c)
+	}
+
+	env, err := util.GetEnv(c)
+	if err != nil {
+		return nil, err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	srvs, err := registry.DefaultRegistry.GetService(args[0], registry.GetDomain(ns))
+	if err != nil && err != registry.ErrNotFound {
+		return nil, err
+	}
+
+	// the owner is stored so it's known while the service isn't running
+	owner, err := ownership.Get(ns, args[0])
+	if err != nil && err != ownership.ErrNotFound {
+		return nil, err
+	}
+	if len(srvs) == 0 && owner == nil {
+		return nil, errors.New("Service not found")
+	}
+
+	parse := func(v string) string {
+		if len(v) == 0 {
+			return "n/a"
+		}
+		return v
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%v\n", args[0])
+	if owner != nil {
+		fmt.Fprintf(w, "Team:\t%v\n", owner.Team)
+		fmt.Fprintf(w, "Oncall:\t%v\n", parse(owner.Oncall))
+		fmt.Fprintf(w, "Slack:\t%v\n", parse(owner.Slack))
+		fmt.Fprintf(w, "Runbook:\t%v\n", parse(owner.Runbook))
+	} else {
+		fmt.Fprintf(w, "Team:\t%v\n", "n/a (no owner registered)")
+	}
+
+	if len(srvs) == 0 {
+		fmt.Fprintf(w, "Versions:\t%v\n", "none running")
+		w.Flush()
+		return bytes.TrimRight(buf.Bytes(), "\n"), nil
+	}
+
+	versions := make([]string, 0, len(srvs))
+	for _, srv := range srvs {
+		versions = append(versions, fmt.Sprintf("%v (%d nodes)", parse(srv.Version), len(srv.Nodes)))
+	}
+	sort.Strings(versions)
+	fmt.Fprintf(w, "Versions:\t%v\n", strings.Join(versions, ", "))
+
+	endpoints := make([]string, 0, len(srvs[0].Endpoints))
+	for _, ep := range srvs[0].Endpoints {
+		endpoints = append(endpoints, ep.Name)
+	}
+	sort.Strings(endpoints)
+	fmt.Fprintf(w, "Endpoints:\t%v\n", parse(strings.Join(endpoints, ", ")))
+	w.Flush()
+
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
 func ListServices(c *cli.Context, args []string) ([]byte, error) {
 	var rsp []*registry.Service
 	var err error
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	proto "github.com/micro/micro/v3/proto/debug"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/urfave/cli/v2"
+)
+
+// QueryMiddleware returns the wrappers active in each instance of a service
+func QueryMiddleware(c *cli.Context, args []string) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, errors.New("require service name")
+	}
+
+	req := client.NewRequest(args[0], "Debug.Middleware", &proto.MiddlewareRequest{})
+
+	// if the address is specified then we just call it
+	if addr := c.String("address"); len(addr) > 0 {
+		rsp := &proto.MiddlewareResponse{}
+		if err := client.DefaultClient.Call(context.Background(), req, rsp, client.WithAddress(addr)); err != nil {
+			return nil, err
+		}
+		return []byte(formatMiddleware(rsp)), nil
+	}
+
+	env, err := util.GetEnv(c)
+	if err != nil {
+		return nil, err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// otherwise get the service and call each instance individually
+	service, err := registry.DefaultRegistry.GetService(args[0], registry.GetDomain(ns))
+	if err != nil {
+		return nil, err
+	}
+	if len(service) == 0 {
+		return nil, errors.New("Service not found")
+	}
+
+	var output []string
+	for _, serv := range service {
+		for _, node := range serv.Nodes {
+			output = append(output, fmt.Sprintf("node %s %s version %s", node.Id, node.Address, serv.Version))
+
+			rsp := &proto.MiddlewareResponse{}
+			if err := client.DefaultClient.Call(context.Background(), req, rsp, client.WithAddress(node.Address)); err != nil {
+				output = append(output, err.Error()+"\n")
+				continue
+			}
+			output = append(output, formatMiddleware(rsp))
+		}
+	}
+
+	return []byte(strings.TrimSpace(strings.Join(output, "\n"))), nil
+}
+
+// formatMiddleware lists each chain in the order requests pass through the wrappers
+func formatMiddleware(rsp *proto.MiddlewareResponse) string {
+	chain := func(w []string) string {
+		if len(w) == 0 {
+			return "-"
+		}
+		return strings.Join(w, " > ")
+	}
+
+	return fmt.Sprintf("client\t\t%s\ncall\t\t%s\nhandler\t\t%s\nsubscriber\t%s\n",
+		chain(rsp.Client), chain(rsp.Call), chain(rsp.Handler), chain(rsp.Subscriber))
+}
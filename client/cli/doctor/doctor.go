@@ -0,0 +1,165 @@
+// Package doctor implements the `micro doctor` command, which checks the platform for problems
+// for example:
+//   micro doctor
+//   micro doctor --threshold 500ms
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/cmd"
+	pb "github.com/micro/micro/v3/proto/debug"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/util/skew"
+	"github.com/urfave/cli/v2"
+)
+
+func init() {
+	cmd.Register(&cli.Command{
+		Name:  "doctor",
+		Usage: "Check the services of the namespace for problems",
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:  "threshold",
+				Usage: "Clock offset beyond which a node is skewed",
+				Value: time.Second,
+			},
+		},
+		Action: doctor,
+	})
+}
+
+// result of a check
+type result struct {
+	Check  string
+	OK     bool
+	Detail string
+}
+
+// check returns the results of checking the nodes of the services
+type check func(ctx *cli.Context, srvs []*registry.Service) []*result
+
+var checks = []check{
+	checkClocks,
+}
+
+func doctor(ctx *cli.Context) error {
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	list, err := registry.DefaultRegistry.ListServices(registry.ListDomain(ns))
+	if err != nil {
+		return fmt.Errorf("Error listing services: %v", err)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	// the list doesn't include the nodes
+	var srvs []*registry.Service
+	for _, s := range list {
+		ss, err := registry.DefaultRegistry.GetService(s.Name, registry.GetDomain(ns))
+		if err != nil {
+			return fmt.Errorf("Error getting service %v: %v", s.Name, err)
+		}
+		srvs = append(srvs, ss...)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	fmt.Fprintln(w, strings.Join([]string{"Check", "Status", "Detail"}, "\t\t"))
+
+	var failed int
+	for _, c := range checks {
+		for _, r := range c(ctx, srvs) {
+			status := "ok"
+			if !r.OK {
+				status = "fail"
+				failed++
+			}
+			fmt.Fprintln(w, strings.Join([]string{r.Check, status, r.Detail}, "\t\t"))
+		}
+	}
+	w.Flush()
+
+	if failed > 0 {
+		return fmt.Errorf("%d checks failed", failed)
+	}
+	return nil
+}
+
+// checkClocks compares the clock of each node with the local clock. The offsets each node has
+// observed of the services it calls are checked too, as the local clock may be the one skewed.
+func checkClocks(ctx *cli.Context, srvs []*registry.Service) []*result {
+	threshold := ctx.Duration("threshold")
+
+	var results []*result
+	var nodes int
+	for _, srv := range srvs {
+		for _, node := range srv.Nodes {
+			nodes++
+			name := srv.Name + " " + node.Id
+
+			var md metadata.Metadata
+			rsp := new(pb.StatsResponse)
+			req := client.NewRequest(srv.Name, "Debug.Stats", &pb.StatsRequest{})
+
+			start := time.Now()
+			err := client.DefaultClient.Call(skew.Stamp(context.Background()), req, rsp,
+				client.WithAddress(node.Address), client.ResponseMetadata(&md))
+			end := time.Now()
+			if err != nil {
+				results = append(results, &result{"clock", false, fmt.Sprintf("%v: %v", name, err)})
+				continue
+			}
+
+			// nodes which don't run the skew handler wrapper don't return the time
+			if remote, ok := skew.Parse(md); ok {
+				rtt := end.Sub(start)
+				offset := remote.Sub(start.Add(rtt / 2))
+				if abs(offset)-rtt/2 > threshold {
+					results = append(results, &result{"clock", false, fmt.Sprintf("%v is offset from the local clock by %v", name, offset.Round(time.Millisecond))})
+				}
+			}
+
+			peers := make([]string, 0, len(rsp.ClockSkews))
+			for p := range rsp.ClockSkews {
+				peers = append(peers, p)
+			}
+			sort.Strings(peers)
+			for _, p := range peers {
+				offset := time.Duration(rsp.ClockSkews[p]) * time.Millisecond
+				if abs(offset) > threshold {
+					results = append(results, &result{"clock", false, fmt.Sprintf("%v observed %v offset by %v", name, p, offset)})
+				}
+			}
+		}
+	}
+
+	if len(results) == 0 {
+		results = append(results, &result{"clock", true, fmt.Sprintf("%d nodes within %v", nodes, threshold)})
+	}
+	return results
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,198 @@
+// Package events implements the `micro events` subcommands
+// for example:
+//   micro events deadletters payment.created
+//   micro events replay payment.created --all
+package events
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/util/helper"
+	"github.com/urfave/cli/v2"
+)
+
+// pageSize is the number of dead letters read at a time
+var pageSize uint = 250
+
+func init() {
+	cmd.Register(&cli.Command{
+		Name:   "events",
+		Usage:  "Inspect and replay the events consumers failed to process",
+		Action: helper.UnexpectedSubcommand,
+		Subcommands: []*cli.Command{
+			{
+				Name:  "deadletters",
+				Usage: "List the dead letters of a topic, e.g. micro events deadletters payment.created",
+				Description: `Events are moved to the dead letter stream of their topic once a consumer has nacked them, or
+not acknowledged them, more times than its retry limit allows. Dead letters are kept for a week.`,
+				Flags: []cli.Flag{
+					&cli.UintFlag{
+						Name:  "limit",
+						Usage: "The maximum number of dead letters to list",
+						Value: 25,
+					},
+					&cli.UintFlag{
+						Name:  "offset",
+						Usage: "The number of dead letters to skip",
+					},
+				},
+				Action: list,
+			},
+			{
+				Name:  "replay",
+				Usage: "Publish dead letters to their topic again, e.g. micro events replay payment.created [id...]",
+				Description: `The dead letters with the ids given, or all the dead letters of the topic with --all, are
+published to the topic with their original metadata so they're processed again, and are then
+deleted. The events are delivered to every consumer group of the topic, not only the group which
+failed to process them, so the handlers of the topic need to be idempotent. Use --group to only
+replay the dead letters of one group.`,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "Replay all the dead letters of the topic",
+					},
+					&cli.StringFlag{
+						Name:  "group",
+						Usage: "Only replay the dead letters of the consumer group",
+					},
+				},
+				Action: replay,
+			},
+		},
+	})
+}
+
+func list(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	evs, err := readDeadLetters(ctx.Args().First())
+	if err != nil {
+		return util.CliError(err)
+	}
+	if offset := ctx.Uint("offset"); offset < uint(len(evs)) {
+		evs = evs[offset:]
+	} else {
+		evs = nil
+	}
+	if limit := ctx.Uint("limit"); limit > 0 && limit < uint(len(evs)) {
+		evs = evs[:limit]
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"ID", "Group", "Attempts", "Reason", "Failed"}, "\t\t"))
+	for _, ev := range evs {
+		fmt.Fprintln(w, strings.Join([]string{
+			ev.ID,
+			ev.Metadata[events.DeadLetterGroupKey],
+			ev.Metadata[events.DeadLetterAttemptsKey],
+			ev.Metadata[events.DeadLetterReasonKey],
+			humanize.Time(ev.Timestamp),
+		}, "\t\t"))
+	}
+	return nil
+}
+
+func replay(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+	topic := ctx.Args().First()
+	ids := map[string]bool{}
+	for _, id := range ctx.Args().Tail() {
+		ids[id] = true
+	}
+	if len(ids) == 0 && !ctx.Bool("all") {
+		return cli.Exit("Specify the ids of the dead letters to replay or --all", 1)
+	}
+	if len(ids) > 0 && ctx.Bool("all") {
+		return cli.Exit("Specify either the ids of the dead letters to replay or --all", 1)
+	}
+
+	group := ctx.String("group")
+	missing := map[string]bool{}
+	for id := range ids {
+		missing[id] = true
+	}
+
+	// replayed dead letters are deleted, so the dead letters are read again until none are left to
+	// replay in case any were missed by a page moving as dead letters were written or expired. The
+	// events which fail again once replayed are left for next time.
+	started := time.Now()
+	var replayed int
+	for {
+		evs, err := readDeadLetters(topic)
+		if err != nil {
+			return util.CliError(err)
+		}
+		var n int
+		for _, ev := range evs {
+			if len(ids) > 0 && !ids[ev.ID] {
+				continue
+			}
+			if len(group) > 0 && ev.Metadata[events.DeadLetterGroupKey] != group {
+				continue
+			}
+			if ev.Timestamp.After(started) {
+				continue
+			}
+			if err := events.Replay(events.DefaultStream, ev); err != nil {
+				return util.CliError(fmt.Errorf("error replaying %v after replaying %d: %w", ev.ID, replayed, err))
+			}
+			delete(missing, ev.ID)
+			replayed++
+			n++
+		}
+		if n == 0 {
+			break
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Replayed %d dead letters of %v\n", replayed, topic)
+
+	if len(missing) > 0 {
+		list := make([]string, 0, len(missing))
+		for id := range missing {
+			list = append(list, id)
+		}
+		sort.Strings(list)
+		return cli.Exit(fmt.Sprintf("Dead letters not found: %v", strings.Join(list, ", ")), 1)
+	}
+	return nil
+}
+
+// readDeadLetters reads every page of the dead letters of the topic, newest first. The store
+// orders them by id, so a page can move as dead letters are written or expire; the dead letters
+// are deduplicated by id.
+func readDeadLetters(topic string) ([]*events.Event, error) {
+	seen := map[string]bool{}
+	var evs []*events.Event
+	for offset := uint(0); ; offset += pageSize {
+		page, err := events.Read(events.DeadLetterTopic(topic), events.ReadLimit(pageSize), events.ReadOffset(offset))
+		if err != nil {
+			return nil, err
+		}
+		for _, ev := range page {
+			if !seen[ev.ID] {
+				seen[ev.ID] = true
+				evs = append(evs, ev)
+			}
+		}
+		if uint(len(page)) < pageSize {
+			break
+		}
+	}
+	sort.SliceStable(evs, func(i, j int) bool { return evs[i].Timestamp.After(evs[j].Timestamp) })
+	return evs, nil
+}
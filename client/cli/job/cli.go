@@ -0,0 +1,201 @@
+// Package job implements the `micro job` subcommands
+// for example:
+//   micro job run helloworld --command migrate
+//   micro job list helloworld
+package job
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/cmd"
+	pb "github.com/micro/micro/v3/proto/runtime"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/util/helper"
+	"github.com/urfave/cli/v2"
+)
+
+// pollInterval is how often the status of a job is checked while waiting for it to finish
+var pollInterval = time.Second
+
+func init() {
+	cmd.Register(&cli.Command{
+		Name:   "job",
+		Usage:  "Run one-off tasks with the build of a service",
+		Action: helper.UnexpectedSubcommand,
+		Subcommands: []*cli.Command{
+			{
+				Name:  "run",
+				Usage: "Run a job, e.g. micro job run helloworld --command migrate",
+				Description: `The job is run with the build of the service by a separate instance which runs the command
+registered by the service with service.Job rather than serving requests. The output of the job is
+streamed until it finishes and the command exits with the exit code of the job.`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "command",
+						Usage: "The command registered by the service to run",
+					},
+					&cli.StringFlag{
+						Name:  "version",
+						Usage: "The version of the service to run the job with",
+						Value: "latest",
+					},
+					&cli.StringSliceFlag{
+						Name:  "env_vars",
+						Usage: "Set additional environment variables e.g. foo=bar",
+					},
+					&cli.BoolFlag{
+						Name:  "detach",
+						Usage: "Return once the job is started rather than waiting for it to finish",
+					},
+				},
+				Action: run,
+			},
+			{
+				Name:   "list",
+				Usage:  "List the jobs run recently, e.g. micro job list [service]",
+				Action: list,
+			},
+		},
+	})
+}
+
+func run(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+	command := ctx.String("command")
+	if len(command) == 0 {
+		return cli.Exit("Missing --command", 1)
+	}
+
+	ns, err := getNamespace(ctx)
+	if err != nil {
+		return err
+	}
+
+	var env []string
+	for _, evar := range ctx.StringSlice("env_vars") {
+		for _, e := range strings.Split(evar, ",") {
+			if len(e) > 0 {
+				env = append(env, strings.TrimSpace(e))
+			}
+		}
+	}
+
+	jobs := pb.NewJobsService("runtime", client.DefaultClient)
+	rsp, err := jobs.Run(context.DefaultContext, &pb.RunJobRequest{
+		Service:   ctx.Args().First(),
+		Version:   ctx.String("version"),
+		Command:   command,
+		Env:       env,
+		Namespace: ns,
+	}, client.WithAuthToken())
+	if err != nil {
+		return util.CliError(err)
+	}
+	job := rsp.Job
+	fmt.Fprintf(os.Stderr, "Started job %v\n", job.Id)
+	if ctx.Bool("detach") {
+		return nil
+	}
+
+	// stream the output of the job until it's finished
+	logs, err := runtime.Logs(
+		&runtime.Service{Name: job.Runner, Version: job.Version},
+		runtime.LogsStream(true),
+		runtime.LogsNamespace(ns),
+	)
+	if err == nil {
+		defer logs.Stop()
+		go func() {
+			for record := range logs.Chan() {
+				fmt.Println(record.Message)
+			}
+		}()
+	}
+
+	for job.Status == runtime.JobRunning {
+		time.Sleep(pollInterval)
+
+		rsp, err := jobs.Read(context.DefaultContext, &pb.ReadJobsRequest{
+			Service:   job.Service,
+			Id:        job.Id,
+			Namespace: ns,
+		}, client.WithAuthToken())
+		if err != nil {
+			return util.CliError(err)
+		}
+		if len(rsp.Jobs) == 0 {
+			return cli.Exit("Job not found", 1)
+		}
+		job = rsp.Jobs[0]
+	}
+
+	if job.Status == runtime.JobSucceeded {
+		fmt.Fprintf(os.Stderr, "Job %v succeeded\n", job.Id)
+		return nil
+	}
+	code := int(job.ExitCode)
+	if code == 0 {
+		code = 1
+	}
+	msg := fmt.Sprintf("Job %v failed with exit code %d", job.Id, job.ExitCode)
+	if len(job.Error) > 0 {
+		msg += ": " + job.Error
+	}
+	return cli.Exit(msg, code)
+}
+
+func list(ctx *cli.Context) error {
+	ns, err := getNamespace(ctx)
+	if err != nil {
+		return err
+	}
+
+	jobs := pb.NewJobsService("runtime", client.DefaultClient)
+	rsp, err := jobs.Read(context.DefaultContext, &pb.ReadJobsRequest{
+		Service:   ctx.Args().First(),
+		Namespace: ns,
+	}, client.WithAuthToken())
+	if err != nil {
+		return util.CliError(err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"ID", "Service", "Version", "Command", "Status", "Exit Code", "Started"}, "\t\t"))
+	for _, j := range rsp.Jobs {
+		code := "-"
+		if j.Status != runtime.JobRunning {
+			code = fmt.Sprintf("%d", j.ExitCode)
+		}
+		fmt.Fprintln(w, strings.Join([]string{
+			j.Id,
+			j.Service,
+			j.Version,
+			j.Command,
+			j.Status,
+			code,
+			humanize.Time(time.Unix(j.Started, 0)),
+		}, "\t\t"))
+	}
+	return nil
+}
+
+func getNamespace(ctx *cli.Context) (string, error) {
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return "", err
+	}
+	return namespace.Get(env.Name)
+}
@@ -0,0 +1,64 @@
+// Package cli implements the micro namespace command
+package cli
+
+import (
+	"time"
+
+	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/util/helper"
+	"github.com/urfave/cli/v2"
+)
+
+func init() {
+	cmd.Register(
+		&cli.Command{
+			Name:   "namespace",
+			Usage:  "Manage namespaces",
+			Action: helper.UnexpectedSubcommand,
+			Subcommands: []*cli.Command{
+				{
+					Name:   "maintenance",
+					Usage:  "Manage read only maintenance of namespaces",
+					Action: listMaintenance,
+					Subcommands: []*cli.Command{
+						{
+							Name:      "enable",
+							Usage:     "Put a namespace in maintenance, requests which aren't reads are rejected",
+							ArgsUsage: "<namespace>",
+							Description: `While a namespace is in maintenance the api and web reject requests which aren't reads with
+a 503 and a Retry-After header. A request is a read if its http method is one of the read verbs or
+the endpoint method starts with one of the read prefixes, e.g. /users/list. Admins of the namespace
+can still make changes.`,
+							Flags: []cli.Flag{
+								&cli.StringFlag{
+									Name:  "message",
+									Usage: "Message returned to callers whose requests are rejected",
+								},
+								&cli.DurationFlag{
+									Name:  "retry-after",
+									Usage: "How long callers should wait before retrying",
+									Value: 5 * time.Minute,
+								},
+								&cli.StringSliceFlag{
+									Name:  "read-verbs",
+									Usage: "HTTP methods treated as reads by services without registered endpoints, e.g. web services. Defaults to GET, HEAD and OPTIONS",
+								},
+								&cli.StringSliceFlag{
+									Name:  "read-prefixes",
+									Usage: "Prefixes of the endpoint methods treated as reads, unless the service registered them with api.Read. Defaults to Read, List, Get, Search, Find, Count and Watch",
+								},
+							},
+							Action: enableMaintenance,
+						},
+						{
+							Name:      "disable",
+							Usage:     "Take a namespace out of maintenance",
+							ArgsUsage: "<namespace>",
+							Action:    disableMaintenance,
+						},
+					},
+				},
+			},
+		},
+	)
+}
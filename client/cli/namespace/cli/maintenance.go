@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/micro/micro/v3/proto/api"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+	"github.com/urfave/cli/v2"
+)
+
+func enableMaintenance(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: namespace")
+	}
+	cli := pb.NewApiService("api", client.DefaultClient)
+
+	_, err := cli.EnableMaintenance(context.DefaultContext, &pb.EnableMaintenanceRequest{
+		Maintenance: &pb.Maintenance{
+			Namespace:    ctx.Args().First(),
+			Message:      ctx.String("message"),
+			RetryAfter:   int64(ctx.Duration("retry-after").Seconds()),
+			ReadVerbs:    ctx.StringSlice("read-verbs"),
+			ReadPrefixes: ctx.StringSlice("read-prefixes"),
+		},
+	}, client.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error enabling maintenance: %v", err)
+	}
+
+	fmt.Printf("Namespace %v is in maintenance\n", ctx.Args().First())
+	return nil
+}
+
+func disableMaintenance(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: namespace")
+	}
+	cli := pb.NewApiService("api", client.DefaultClient)
+
+	_, err := cli.DisableMaintenance(context.DefaultContext, &pb.DisableMaintenanceRequest{
+		Namespace: ctx.Args().First(),
+	}, client.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error disabling maintenance: %v", err)
+	}
+
+	fmt.Printf("Namespace %v is no longer in maintenance\n", ctx.Args().First())
+	return nil
+}
+
+func listMaintenance(ctx *cli.Context) error {
+	cli := pb.NewApiService("api", client.DefaultClient)
+
+	rsp, err := cli.ReadMaintenance(context.DefaultContext, &pb.ReadMaintenanceRequest{
+		Namespace: ctx.Args().First(),
+	}, client.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error reading maintenance: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"Namespace", "Since", "Retry After", "Message"}, "\t\t"))
+	for _, m := range rsp.Maintenance {
+		since := time.Unix(m.Created, 0).Format(time.RFC3339)
+		retry := (time.Duration(m.RetryAfter) * time.Second).String()
+		fmt.Fprintln(w, strings.Join([]string{m.Namespace, since, retry, m.Message}, "\t\t"))
+	}
+	return nil
+}
@@ -8,11 +8,14 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/micro/micro/v3/client/cli/util"
 	"github.com/micro/micro/v3/cmd"
+	pb "github.com/micro/micro/v3/proto/network"
 	"github.com/micro/micro/v3/service/client"
 	"github.com/micro/micro/v3/service/context"
+	"github.com/micro/micro/v3/util/netpolicy"
 	"github.com/olekukonko/tablewriter"
 	"github.com/urfave/cli/v2"
 )
@@ -32,6 +35,11 @@ func init() {
 				Usage:  "List the immediate connections to the network",
 				Action: util.Print(networkConnections),
 			},
+			{
+				Name:   "links",
+				Usage:  "List the tunnel links of the network node and their health",
+				Action: util.Print(networkLinks),
+			},
 			{
 				Name:   "graph",
 				Usage:  "Get the network graph",
@@ -74,6 +82,48 @@ func init() {
 				Usage:  "Get the network services",
 				Action: util.Print(networkServices),
 			},
+			{
+				Name:  "policy",
+				Usage: "Manage the policies which allow services to call each other",
+				Description: `Policies are only checked once they're enforced, after which calls between services in
+the namespace are denied unless a policy allows them. Use dry-run first to log the calls which
+would be denied.`,
+				Action: policyList,
+				Subcommands: []*cli.Command{
+					{
+						Name:      "allow",
+						Usage:     "Allow a service to call another, e.g. micro network policy allow 'orders -> payments'",
+						ArgsUsage: "<from> -> <to>",
+						Action:    policyAllow,
+					},
+					{
+						Name:      "revoke",
+						Usage:     "Revoke a policy which allowed a service to call another",
+						ArgsUsage: "<from> -> <to>",
+						Action:    policyRevoke,
+					},
+					{
+						Name:   "list",
+						Usage:  "List the policies",
+						Action: policyList,
+					},
+					{
+						Name:   "enforce",
+						Usage:  "Deny calls between services which aren't allowed by a policy",
+						Action: policyMode(netpolicy.ModeEnforce),
+					},
+					{
+						Name:   "dry-run",
+						Usage:  "Log calls between services which would be denied without denying them",
+						Action: policyMode(netpolicy.ModeDryRun),
+					},
+					{
+						Name:   "disable",
+						Usage:  "Stop checking calls between services",
+						Action: policyMode(netpolicy.ModeDisabled),
+					},
+				},
+			},
 		},
 	})
 }
@@ -148,6 +198,56 @@ func networkConnections(c *cli.Context, args []string) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+func networkLinks(c *cli.Context, args []string) ([]byte, error) {
+	rsp, err := pb.NewNetworkService("network", client.DefaultClient).Links(
+		context.DefaultContext, &pb.LinksRequest{}, client.WithAuthToken())
+	if err != nil {
+		return nil, err
+	}
+	if len(rsp.Links) == 0 {
+		return nil, nil
+	}
+
+	b := bytes.NewBuffer(nil)
+	table := tablewriter.NewWriter(b)
+	table.SetHeader([]string{"LINK", "STATE", "OBSERVED", "RTT", "RATE", "SENT", "RECEIVED", "ERRORS", "KEY ROTATED"})
+
+	sort.Slice(rsp.Links, func(i, j int) bool { return rsp.Links[i].Id < rsp.Links[j].Id })
+	for _, l := range rsp.Links {
+		state := l.State
+		if l.Loopback {
+			state += " (loopback)"
+		} else if l.Relay {
+			state += " (relay)"
+		}
+		observed := l.Observed
+		if len(observed) == 0 {
+			observed = "-"
+		}
+		rotated := "never"
+		if l.KeyRotated > 0 {
+			rotated = time.Since(time.Unix(l.KeyRotated, 0)).Truncate(time.Second).String() + " ago"
+		}
+		table.Append([]string{
+			l.Id,
+			state,
+			observed,
+			time.Duration(l.Length).Round(time.Microsecond).String(),
+			fmt.Sprintf("%.0f bit/s", l.Rate),
+			fmt.Sprintf("%d B / %d msgs", l.BytesSent, l.MessagesSent),
+			fmt.Sprintf("%d B / %d msgs", l.BytesReceived, l.MessagesReceived),
+			fmt.Sprintf("%d", l.Errors),
+			rotated,
+		})
+	}
+
+	// render table into b
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.Render()
+
+	return b.Bytes(), nil
+}
+
 func networkGraph(c *cli.Context, args []string) ([]byte, error) {
 
 	var rsp map[string]interface{}
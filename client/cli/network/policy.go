@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/token"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/util/netpolicy"
+	"github.com/urfave/cli/v2"
+)
+
+// policyAllow allows a service to call another, e.g. micro network policy allow orders payments
+func policyAllow(ctx *cli.Context) error {
+	r, err := netpolicy.ParseRule(ctx.Args().Slice())
+	if err != nil {
+		return err
+	}
+	ns, err := policyNamespace(ctx)
+	if err != nil {
+		return err
+	}
+	r.Account = currentAccount(ctx)
+	if err := netpolicy.Allow(ns, r); err != nil {
+		return fmt.Errorf("Error allowing %v to call %v: %v", r.From, r.To, err)
+	}
+	fmt.Printf("Allowed %v -> %v\n", r.From, r.To)
+	return nil
+}
+
+// policyRevoke removes a rule which allowed a service to call another
+func policyRevoke(ctx *cli.Context) error {
+	r, err := netpolicy.ParseRule(ctx.Args().Slice())
+	if err != nil {
+		return err
+	}
+	ns, err := policyNamespace(ctx)
+	if err != nil {
+		return err
+	}
+	r.Account = currentAccount(ctx)
+	if err := netpolicy.Revoke(ns, r); err != nil {
+		return fmt.Errorf("Error revoking %v -> %v: %v", r.From, r.To, err)
+	}
+	fmt.Printf("Revoked %v -> %v\n", r.From, r.To)
+	return nil
+}
+
+// policyList prints the mode and rules of the namespace
+func policyList(ctx *cli.Context) error {
+	ns, err := policyNamespace(ctx)
+	if err != nil {
+		return err
+	}
+	s, err := netpolicy.GetMode(ns)
+	if err != nil {
+		return fmt.Errorf("Error reading network policy mode: %v", err)
+	}
+	rules, err := netpolicy.Rules(ns)
+	if err != nil {
+		return fmt.Errorf("Error listing network policies: %v", err)
+	}
+
+	fmt.Printf("Mode: %v\n\n", s.Mode)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"From", "To", "Created"}, "\t\t"))
+	for _, r := range rules {
+		fmt.Fprintln(w, strings.Join([]string{r.From, r.To, r.Created.Format(time.RFC3339)}, "\t\t"))
+	}
+	return nil
+}
+
+// policyMode returns an action which sets the mode of the namespace
+func policyMode(mode netpolicy.Mode) func(ctx *cli.Context) error {
+	return func(ctx *cli.Context) error {
+		ns, err := policyNamespace(ctx)
+		if err != nil {
+			return err
+		}
+		if err := netpolicy.SetMode(ns, &netpolicy.Settings{Mode: mode, Account: currentAccount(ctx)}); err != nil {
+			return fmt.Errorf("Error setting network policy mode: %v", err)
+		}
+
+		switch mode {
+		case netpolicy.ModeEnforce:
+			fmt.Printf("Calls between services in %v are denied unless allowed by a policy\n", ns)
+		case netpolicy.ModeDryRun:
+			fmt.Printf("Calls between services in %v which would be denied are logged\n", ns)
+		default:
+			fmt.Printf("Calls between services in %v are no longer checked\n", ns)
+		}
+		return nil
+	}
+}
+
+func policyNamespace(ctx *cli.Context) (string, error) {
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return "", err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return "", fmt.Errorf("Error getting namespace: %v", err)
+	}
+	return ns, nil
+}
+
+// currentAccount returns the name of the account the cli is logged in as, recorded with the
+// changes made to the policies
+func currentAccount(ctx *cli.Context) string {
+	tok, err := token.Get(ctx)
+	if err != nil {
+		return ""
+	}
+	acc, err := auth.Inspect(tok.AccessToken)
+	if err != nil {
+		return ""
+	}
+	if len(acc.Name) > 0 {
+		return acc.Name
+	}
+	return acc.ID
+}
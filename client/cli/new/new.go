@@ -46,6 +46,8 @@ type config struct {
 	Files []file
 	// Comments
 	Comments []string
+	// Topics consumed by a projection
+	Topics []string
 }
 
 type file struct {
@@ -200,6 +202,17 @@ func Run(ctx *cli.Context) error {
 		},
 	}
 
+	// projections build a read model from the events of the topics
+	if ctx.Bool("projection") {
+		c.Topics = ctx.StringSlice("topic")
+		if len(c.Topics) == 0 {
+			fmt.Println("specify the topics of the projection with --topic")
+			return nil
+		}
+		c.Files[1] = file{"main.go", tmpl.MainProjection}
+		c.Files = append(c.Files, file{"projection/" + dir + ".go", tmpl.ProjectionSRV})
+	}
+
 	// set gomodule
 	if os.Getenv("GO111MODULE") != "off" {
 		c.Files = append(c.Files, file{"go.mod", tmpl.Module})
@@ -215,5 +228,15 @@ func init() {
 		Usage:       "Create a service template",
 		Description: `'micro new' scaffolds a new service skeleton. Example: 'micro new helloworld && cd helloworld'`,
 		Action:      Run,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "projection",
+				Usage: "Create a projection service which builds a read model from the events of the topics, e.g. micro new --projection --topic orders orders-view",
+			},
+			&cli.StringSliceFlag{
+				Name:  "topic",
+				Usage: "Topic the projection consumes events from, can be set more than once",
+			},
+		},
 	})
 }
@@ -0,0 +1,77 @@
+package template
+
+var (
+	MainProjection = `package main
+
+import (
+	"{{.Dir}}/handler"
+	"{{.Dir}}/projection"
+	pb "{{.Dir}}/proto"
+
+	"github.com/micro/micro/v3/service"
+	"github.com/micro/micro/v3/service/logger"
+	proj "github.com/micro/micro/v3/util/projection"
+)
+
+func main() {
+	// Create the projection of the source topics, it consumes them from where it left off and
+	// reports its health with Debug.Health
+	p := proj.New("{{lower .Alias}}", projection.Project,
+		proj.Topics({{range $i, $t := .Topics}}{{if $i}}, {{end}}"{{$t}}"{{end}}),
+		proj.Reset(projection.Reset),
+	)
+
+	// Create service
+	srv := service.New(
+		service.Name("{{lower .Alias}}"),
+		service.AfterStart(p.Start),
+		service.BeforeStop(p.Stop),
+	)
+
+	// Register handler
+	pb.Register{{title .Alias}}Handler(srv.Server(), handler.New())
+
+	// Run service
+	if err := srv.Run(); err != nil {
+		logger.Fatal(err)
+	}
+}
+`
+
+	ProjectionSRV = `package projection
+
+import (
+	"context"
+
+	"github.com/micro/micro/v3/service/events"
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
+)
+
+// Project applies an event to the read model. Each event is handled once, return an error to
+// have the event delivered again.
+func Project(ctx context.Context, ev *events.Event) error {
+	log.Infof("Received event %v of %v", ev.ID, ev.Topic)
+
+	var payload map[string]interface{}
+	if err := ev.Unmarshal(&payload); err != nil {
+		return err
+	}
+	return store.Write(store.NewRecord(ev.Topic+"/"+ev.ID, payload))
+}
+
+// Reset clears the read model before the topics are replayed
+func Reset(ctx context.Context) error {
+	keys, err := store.List()
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := store.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+`
+)
@@ -0,0 +1,113 @@
+// Package router implements the `micro router` subcommands
+// for example:
+//   micro router history
+//   micro router history --kind policy --limit 5
+package router
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/service/router/history"
+	"github.com/micro/micro/v3/util/helper"
+	"github.com/urfave/cli/v2"
+)
+
+func init() {
+	cmd.Register(&cli.Command{
+		Name:   "router",
+		Usage:  "Manage the routing of the namespace",
+		Action: helper.UnexpectedSubcommand,
+		Subcommands: []*cli.Command{
+			{
+				Name:  "history",
+				Usage: "List the changes made to the routes and network policies, most recent first",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "namespace",
+						Usage: "Namespace to list the changes of, defaults to the current namespace",
+					},
+					&cli.StringFlag{
+						Name:  "kind",
+						Usage: "Only list changes of the kind, one of route, policy or policy-mode",
+					},
+					&cli.StringFlag{
+						Name:  "key",
+						Usage: "Only list changes to the key, e.g. the service of a route. A trailing * matches a prefix",
+					},
+					&cli.UintFlag{
+						Name:  "limit",
+						Usage: "Maximum number of changes to list",
+						Value: 20,
+					},
+					&cli.BoolFlag{
+						Name:  "verbose",
+						Usage: "Print the state before and after each change",
+					},
+				},
+				Action: listHistory,
+			},
+		},
+	})
+}
+
+func listHistory(ctx *cli.Context) error {
+	ns := ctx.String("namespace")
+	if len(ns) == 0 {
+		env, err := util.GetEnv(ctx)
+		if err != nil {
+			return err
+		}
+		if ns, err = namespace.Get(env.Name); err != nil {
+			return fmt.Errorf("Error getting namespace: %v", err)
+		}
+	}
+
+	opts := []history.ListOption{history.ListLimit(ctx.Uint("limit"))}
+	if k := ctx.String("kind"); len(k) > 0 {
+		opts = append(opts, history.ListKind(k))
+	}
+	if k := ctx.String("key"); len(k) > 0 {
+		opts = append(opts, history.ListKey(k))
+	}
+	changes, err := history.List(ns, opts...)
+	if err != nil {
+		return fmt.Errorf("Error listing routing history: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"Version", "Time", "Account", "Kind", "Action", "Key"}, "\t\t"))
+	for _, c := range changes {
+		account := c.Account
+		if len(account) == 0 {
+			account = "-"
+		}
+		fmt.Fprintln(w, strings.Join([]string{
+			strconv.FormatInt(c.Version, 10),
+			c.Time.Format(time.RFC3339),
+			account,
+			c.Kind,
+			c.Action,
+			c.Key,
+		}, "\t\t"))
+		if !ctx.Bool("verbose") {
+			continue
+		}
+		if len(c.Before) > 0 {
+			fmt.Fprintf(w, "\t\tbefore: %s\n", c.Before)
+		}
+		if len(c.After) > 0 {
+			fmt.Fprintf(w, "\t\tafter: %s\n", c.After)
+		}
+	}
+	return nil
+}
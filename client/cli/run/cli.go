@@ -2,6 +2,8 @@
 package runtime
 
 import (
+	"time"
+
 	"github.com/micro/micro/v3/cmd"
 	"github.com/urfave/cli/v2"
 )
@@ -34,7 +36,7 @@ var flags = []cli.Flag{
 	},
 	&cli.StringSliceFlag{
 		Name:  "env_vars",
-		Usage: "Set the environment variables e.g. foo=bar",
+		Usage: "Set the environment variables e.g. foo=bar, values can reference config e.g. db_url={{ secret \"db.url\" }}",
 	},
 	&cli.IntFlag{
 		Name:    "instances",
@@ -46,6 +48,14 @@ var flags = []cli.Flag{
 		Name:  "metadata",
 		Usage: "Set any metadata on the service e.g. foo=bar",
 	},
+	&cli.StringSliceFlag{
+		Name:  "label",
+		Usage: "Set a label on the service e.g. team=payments, groups of services can be operated on by selecting their labels",
+	},
+	&cli.StringFlag{
+		Name:  "selector",
+		Usage: "Select the services by their labels rather than by name e.g. team=payments,tier!=frontend",
+	},
 	&cli.BoolFlag{
 		Name: "watch",
 		Usage: `Enable live-reloading, watch the file changes of source directories, then rebuild and restart the service. 
@@ -59,7 +69,7 @@ var flags = []cli.Flag{
 	},
 	&cli.BoolFlag{
 		Name:  "force",
-		Usage: "Force rebuild and restart the service even though the service is running.",
+		Usage: "Force rebuild and restart the service even though the service is running. When updating, update the service even though its error budget is exhausted.",
 	},
 }
 
@@ -75,7 +85,8 @@ func init() {
 			micro run ../path/to/folder # deploy local folder to your local micro server
 			micro run helloworld # deploy latest version, translates to micro run github.com/micro/services/helloworld
 			micro run helloworld@9342934e6180 # deploy certain version
-			micro run helloworld@branchname	# deploy certain branch`,
+			micro run helloworld@branchname	# deploy certain branch
+			micro run --label team=payments helloworld # label the service so it can be selected`,
 			Flags:  flags,
 			Action: runService,
 		},
@@ -91,6 +102,43 @@ func init() {
 			Flags:  flags,
 			Action: updateService,
 		},
+		&cli.Command{
+			Name:  "restart",
+			Usage: RestartUsage,
+			Description: `Examples:
+			micro restart helloworld # restart every replica of helloworld at once
+			micro restart helloworld --rolling --max-unavailable 1 # restart the replicas one at a time
+			micro restart helloworld --rolling --image micro/cells:go-v2 # roll the replicas to a new image`,
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "rolling",
+					Usage: "Restart the replicas a few at a time, waiting for them to become ready and rolling back if the error budget of the service is exhausted",
+				},
+				&cli.IntFlag{
+					Name:  "max-unavailable",
+					Usage: "The number of replicas restarted at a time by a rolling restart",
+					Value: 1,
+				},
+				&cli.DurationFlag{
+					Name:  "timeout",
+					Usage: "How long to wait for the replicas to become ready before rolling back",
+					Value: time.Minute * 10,
+				},
+				&cli.StringFlag{
+					Name:  "image",
+					Usage: "Restart the replicas with a new image, it's restored if a rolling restart is rolled back",
+				},
+				&cli.BoolFlag{
+					Name:  "force",
+					Usage: "Restart the service even though its error budget is exhausted",
+				},
+				&cli.StringFlag{
+					Name:  "selector",
+					Usage: "Restart the services selected by their labels one after another e.g. team=payments",
+				},
+			},
+			Action: restartService,
+		},
 		&cli.Command{
 			Name:  "kill",
 			Usage: KillUsage,
@@ -100,7 +148,8 @@ func init() {
 			micro kill .  # kill service deployed from local folder
 			micro kill ../path/to/folder # kill service deployed from local folder
 			micro kill helloworld # kill serviced deployed from master branch, translates to micro kill github.com/micro/services/helloworld
-			micro kill helloworld@branchname	# kill service deployed from certain branch`,
+			micro kill helloworld@branchname	# kill service deployed from certain branch
+			micro kill --selector team=payments # kill the services labelled team=payments`,
 			Action: killService,
 		},
 		&cli.Command{
@@ -111,7 +160,7 @@ func init() {
 		},
 		&cli.Command{
 			Name:   "logs",
-			Usage:  "Get logs for a service e.g. micro logs helloworld",
+			Usage:  "Get logs for a service e.g. micro logs helloworld, or micro logs --selector tier=frontend --grep panic",
 			Action: getLogs,
 			Flags: []cli.Flag{
 				&cli.StringFlag{
@@ -137,6 +186,14 @@ func init() {
 					Aliases: []string{"n"},
 					Usage:   "Set to query the last number of log events",
 				},
+				&cli.StringFlag{
+					Name:  "selector",
+					Usage: "Get the logs of the services selected by their labels e.g. tier=frontend",
+				},
+				&cli.StringFlag{
+					Name:  "grep",
+					Usage: "Only show the log events matching the regular expression e.g. panic",
+				},
 			},
 		},
 	)
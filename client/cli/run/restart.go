@@ -0,0 +1,267 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	proto "github.com/micro/micro/v3/proto/debug"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/util/slo"
+	"github.com/urfave/cli/v2"
+)
+
+// rolloutInterval is how often the replicas of a service are checked during a rolling restart
+var rolloutInterval = time.Second * 2
+
+// rollout restarts the replicas of a service a few at a time. The replicas of the previous and new
+// version serve requests side by side while they're replaced so the error budget of the service is
+// watched throughout, and the restart is rolled back if it's exhausted.
+type rollout struct {
+	service string
+	timeout time.Duration
+	// force the restart even though the error budget of the service is exhausted
+	force bool
+
+	// update the service, replacing its replicas with ones running the image
+	update func(image string, force bool) error
+	// nodes returns the address of each replica of the service, keyed by id
+	nodes func() (map[string]string, error)
+	// healthy returns true if the replica is ready to serve requests
+	healthy func(address string) bool
+	// budget returns the error budget of the service, or nil if it has no objective
+	budget func() (*slo.Status, error)
+}
+
+// run the rollout, replacing the replicas with ones running the image. The previous image is
+// restored if the replicas don't become ready before the timeout or the error budget is exhausted.
+func (r *rollout) run(image, previous string) error {
+	old, err := r.nodes()
+	if err != nil {
+		return err
+	}
+
+	// the guardrail can't tell if the restart burns the budget if it was already exhausted
+	watch := true
+	if st, err := r.budget(); err != nil {
+		return err
+	} else if st == nil {
+		fmt.Printf("%v has no error budget, only the readiness of its replicas is checked\n", r.service)
+		watch = false
+	} else if st.Exhausted() {
+		fmt.Printf("The error budget of %v is already exhausted, only the readiness of its replicas is checked\n", r.service)
+		watch = false
+	}
+
+	if err := r.update(image, r.force); err != nil {
+		return err
+	}
+
+	var restarted int
+	deadline := time.Now().Add(r.timeout)
+	for {
+		time.Sleep(rolloutInterval)
+
+		if time.Now().After(deadline) {
+			return r.rollback(previous, fmt.Errorf("timed out waiting for the replicas of %v to become ready", r.service))
+		}
+
+		if watch {
+			if st, err := r.budget(); err == nil && st != nil && st.Exhausted() {
+				return r.rollback(previous, fmt.Errorf("the error budget of %v was exhausted: %v", r.service, st))
+			}
+		}
+
+		nodes, err := r.nodes()
+		if err != nil || len(nodes) == 0 {
+			continue
+		}
+
+		var ready, remaining int
+		for id, addr := range nodes {
+			if _, ok := old[id]; ok {
+				remaining++
+			} else if r.healthy(addr) {
+				ready++
+			}
+		}
+		if ready != restarted {
+			restarted = ready
+			fmt.Printf("Restarted %d of %d replicas of %v\n", restarted, len(old), r.service)
+		}
+		if remaining == 0 && ready >= len(old) {
+			return nil
+		}
+	}
+}
+
+// rollback the replicas to the previous image, returning the reason the rollout was aborted
+func (r *rollout) rollback(previous string, reason error) error {
+	fmt.Printf("Aborting the restart of %v: %v\n", r.service, reason)
+	if len(previous) == 0 {
+		fmt.Printf("%v was restarted with the image it was running, there's nothing to roll back to\n", r.service)
+		return reason
+	}
+
+	// the rollback is forced since the budget of the service is likely exhausted by now
+	fmt.Printf("Rolling back %v to %v\n", r.service, previous)
+	if err := r.update(previous, true); err != nil {
+		return fmt.Errorf("%v, and rolling back to %v failed: %v", reason, previous, err)
+	}
+	return fmt.Errorf("%v, it was rolled back to %v", reason, previous)
+}
+
+func restartService(ctx *cli.Context) error {
+	// restart each of the selected services, one after another
+	if len(ctx.String("selector")) > 0 {
+		srvs, ns, err := selectServices(ctx)
+		if err != nil {
+			return err
+		}
+		for _, srv := range srvs {
+			if err := restart(ctx, srv, ns); err != nil {
+				return fmt.Errorf("Error restarting %v: %v", srv.Name, err)
+			}
+		}
+		return nil
+	}
+
+	// we need some args to run
+	if ctx.Args().Len() == 0 {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	name := ctx.Args().First()
+	if name == "." {
+		dir, _ := os.Getwd()
+		name = filepath.Base(dir)
+	}
+
+	ref := "latest"
+	if parts := strings.Split(name, "@"); len(parts) > 1 {
+		name = parts[0]
+		ref = parts[1]
+	}
+
+	// determine the namespace
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return err
+	}
+
+	srvs, err := runtime.Read(runtime.ReadService(name), runtime.ReadVersion(ref), runtime.ReadNamespace(ns))
+	if err != nil {
+		return util.CliError(err)
+	}
+	if len(srvs) == 0 {
+		return fmt.Errorf("Service %v:%v not found", name, ref)
+	}
+	return restart(ctx, srvs[0], ns)
+}
+
+// restart the service in the namespace
+func restart(ctx *cli.Context, srv *runtime.Service, ns string) error {
+	name, ref := srv.Name, srv.Version
+	if srv.Status == runtime.Pending || srv.Status == runtime.Building {
+		return fmt.Errorf("Service %v:%v is still being built", name, ref)
+	}
+
+	image := ctx.String("image")
+	if !ctx.Bool("rolling") {
+		opts := []runtime.UpdateOption{
+			runtime.UpdateNamespace(ns),
+			runtime.UpdateForce(ctx.Bool("force")),
+		}
+		if len(image) > 0 {
+			opts = append(opts, runtime.UpdateImage(image))
+		}
+		return util.CliError(runtime.Update(srv, opts...))
+	}
+
+	maxUnavailable := ctx.Int("max-unavailable")
+	if maxUnavailable < 1 {
+		return fmt.Errorf("--max-unavailable must be at least 1")
+	}
+
+	// the previous image is only restored if the restart changes it
+	var previous string
+	if len(image) > 0 && image != srv.Metadata["image"] {
+		previous = srv.Metadata["image"]
+		if len(previous) == 0 {
+			return fmt.Errorf("Service %v:%v is running the default image, it can't be rolled back from %v", name, ref, image)
+		}
+	}
+
+	r := &rollout{
+		service: name,
+		timeout: ctx.Duration("timeout"),
+		force:   ctx.Bool("force"),
+		update: func(img string, force bool) error {
+			// the runtime only replaces this many replicas at a time
+			opts := []runtime.UpdateOption{
+				runtime.UpdateNamespace(ns),
+				runtime.UpdateMaxUnavailable(maxUnavailable),
+				runtime.UpdateForce(force),
+			}
+			if len(img) > 0 {
+				opts = append(opts, runtime.UpdateImage(img))
+			}
+			return util.CliError(runtime.Update(srv, opts...))
+		},
+		nodes: func() (map[string]string, error) {
+			return serviceNodes(name, ns)
+		},
+		healthy: func(addr string) bool {
+			return nodeHealthy(name, addr)
+		},
+		budget: func() (*slo.Status, error) {
+			return slo.Get(context.Background(), ns, srv)
+		},
+	}
+
+	fmt.Printf("Restarting %v %d replica(s) at a time\n", name, maxUnavailable)
+	if err := r.run(image, previous); err != nil {
+		return err
+	}
+	fmt.Printf("Restarted %v\n", name)
+	return nil
+}
+
+// serviceNodes returns the address of each node of the service, keyed by id
+func serviceNodes(name, ns string) (map[string]string, error) {
+	srvs, err := registry.DefaultRegistry.GetService(name, registry.GetDomain(ns))
+	if err == registry.ErrNotFound {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	nodes := map[string]string{}
+	for _, srv := range srvs {
+		for _, n := range srv.Nodes {
+			nodes[n.Id] = n.Address
+		}
+	}
+	return nodes, nil
+}
+
+// nodeHealthy returns true if the node responds to a health check
+func nodeHealthy(name, address string) bool {
+	req := client.NewRequest(name, "Debug.Health", &proto.HealthRequest{})
+	rsp := &proto.HealthResponse{}
+	if err := client.DefaultClient.Call(context.Background(), req, rsp, client.WithAddress(address)); err != nil {
+		return false
+	}
+	return rsp.Status == "ok"
+}
@@ -0,0 +1,97 @@
+package runtime
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/util/slo"
+
+	. "github.com/onsi/gomega"
+)
+
+// fakeRollout replaces one replica each time the nodes are listed after the update
+type fakeRollout struct {
+	nodes   map[string]string
+	images  []string
+	replace int
+	errors  uint64
+}
+
+func (f *fakeRollout) rollout() *rollout {
+	return &rollout{
+		service: "foo",
+		timeout: time.Second,
+		update: func(image string, force bool) error {
+			f.images = append(f.images, image)
+			f.replace = len(f.nodes)
+			return nil
+		},
+		nodes: func() (map[string]string, error) {
+			if f.replace > 0 {
+				delete(f.nodes, fmt.Sprintf("old-%d", f.replace))
+				f.nodes[fmt.Sprintf("new-%d", f.replace)] = "addr"
+				f.replace--
+			}
+			nodes := make(map[string]string, len(f.nodes))
+			for k, v := range f.nodes {
+				nodes[k] = v
+			}
+			return nodes, nil
+		},
+		healthy: func(address string) bool {
+			return true
+		},
+		budget: func() (*slo.Status, error) {
+			return &slo.Status{Service: "foo", Objective: 99, Requests: 1000, Errors: f.errors}, nil
+		},
+	}
+}
+
+func TestRollout(t *testing.T) {
+	rolloutInterval = time.Millisecond
+	defer func() { rolloutInterval = time.Second * 2 }()
+
+	t.Run("Restarted", func(t *testing.T) {
+		g := NewWithT(t)
+		f := &fakeRollout{nodes: map[string]string{"old-1": "addr", "old-2": "addr", "old-3": "addr"}}
+
+		err := f.rollout().run("", "")
+		g.Expect(err).To(BeNil())
+		g.Expect(f.nodes).To(HaveLen(3))
+		g.Expect(f.nodes).To(HaveKey("new-3"))
+		g.Expect(f.images).To(Equal([]string{""}))
+	})
+
+	t.Run("BudgetExhausted", func(t *testing.T) {
+		g := NewWithT(t)
+		f := &fakeRollout{nodes: map[string]string{"old-1": "addr", "old-2": "addr"}}
+
+		r := f.rollout()
+		update := r.update
+		r.update = func(image string, force bool) error {
+			// the new image fails every request
+			if image == "new" {
+				f.errors = 1000
+			}
+			return update(image, force)
+		}
+
+		err := r.run("new", "previous")
+		g.Expect(err).To(MatchError(ContainSubstring("rolled back to previous")))
+		g.Expect(f.images).To(Equal([]string{"new", "previous"}))
+	})
+
+	t.Run("NotReady", func(t *testing.T) {
+		g := NewWithT(t)
+		f := &fakeRollout{nodes: map[string]string{"old-1": "addr"}}
+
+		r := f.rollout()
+		r.timeout = time.Millisecond * 10
+		r.healthy = func(address string) bool { return false }
+
+		err := r.run("", "")
+		g.Expect(err).To(MatchError(ContainSubstring("timed out")))
+		g.Expect(f.images).To(Equal([]string{""}))
+	})
+}
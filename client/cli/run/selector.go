@@ -0,0 +1,42 @@
+package runtime
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/urfave/cli/v2"
+)
+
+// selectServices returns the services in the namespace whose labels match the --selector flag,
+// sorted by name, along with the namespace
+func selectServices(ctx *cli.Context) ([]*runtime.Service, string, error) {
+	sel, err := runtime.ParseSelector(ctx.String("selector"))
+	if err != nil {
+		return nil, "", err
+	}
+
+	// determine the namespace
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	srvs, err := runtime.Read(runtime.ReadNamespace(ns))
+	if err != nil {
+		return nil, "", util.CliError(err)
+	}
+
+	selected := sel.Select(srvs)
+	if len(selected) == 0 {
+		return nil, "", fmt.Errorf("No services match the selector %v", sel)
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Name < selected[j].Name })
+	return selected, ns, nil
+}
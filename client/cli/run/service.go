@@ -9,8 +9,10 @@ import (
 	"os/signal"
 	"path"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"syscall"
 	"text/tabwriter"
 	"time"
@@ -34,6 +36,8 @@ const (
 	KillUsage = "Kill a service: micro kill [source]"
 	// UpdateUsage message for the update command
 	UpdateUsage = "Update a service: micro update [source]"
+	// RestartUsage message for the restart command
+	RestartUsage = "Restart a service: micro restart [service]"
 	// GetUsage message for micro get command
 	GetUsage = "Get the status of services"
 	// ServicesUsage message for micro services command
@@ -360,6 +364,15 @@ func runService(ctx *cli.Context) error {
 		}
 	}
 
+	// labels are kept in the metadata so services can be selected by them
+	for _, val := range ctx.StringSlice("label") {
+		k, v, err := runtime.ParseLabel(val)
+		if err != nil {
+			return err
+		}
+		srv.Metadata[runtime.LabelPrefix+k] = v
+	}
+
 	// specify the options
 	opts := []runtime.CreateOption{
 		runtime.WithOutput(os.Stdout),
@@ -460,6 +473,21 @@ func getGitCredentials(repo string) (string, bool) {
 }
 
 func killService(ctx *cli.Context) error {
+	// kill each of the selected services
+	if len(ctx.String("selector")) > 0 {
+		srvs, ns, err := selectServices(ctx)
+		if err != nil {
+			return err
+		}
+		for _, srv := range srvs {
+			if err := runtime.Delete(srv, runtime.DeleteNamespace(ns)); err != nil {
+				return util.CliError(err)
+			}
+			fmt.Printf("Killed %v:%v\n", srv.Name, srv.Version)
+		}
+		return nil
+	}
+
 	// we need some args to run
 	if ctx.Args().Len() == 0 {
 		return cli.ShowSubcommandHelp(ctx)
@@ -596,7 +624,7 @@ func updateService(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	opts = append(opts, runtime.UpdateNamespace(ns))
+	opts = append(opts, runtime.UpdateNamespace(ns), runtime.UpdateForce(ctx.Bool("force")))
 
 	// get number of instances to run
 	if ctx.IsSet("instances") {
@@ -691,6 +719,15 @@ func getService(ctx *cli.Context) error {
 		return util.CliError(err)
 	}
 
+	// only list the services matching the selector
+	if v := ctx.String("selector"); len(v) > 0 {
+		sel, err := runtime.ParseSelector(v)
+		if err != nil {
+			return err
+		}
+		services = sel.Select(services)
+	}
+
 	// make sure we return UNKNOWN when empty string is supplied
 	parse := func(m string) string {
 		if len(m) == 0 {
@@ -718,6 +755,9 @@ func getService(ctx *cli.Context) error {
 
 		// if there is an error, display this in metadata (there is no error field)
 		metadata := fmt.Sprintf("owner=%s, group=%s", parse(service.Metadata["owner"]), parse(service.Metadata["group"]))
+		if labels := service.Labels(); len(labels) > 0 {
+			metadata = fmt.Sprintf("%v, labels=%v", metadata, runtime.FormatLabels(labels))
+		}
 		if service.Status == runtime.Error {
 			metadata = fmt.Sprintf("%v, error=%v", metadata, parse(service.Metadata["error"]))
 		}
@@ -752,21 +792,20 @@ const (
 
 func getLogs(ctx *cli.Context) error {
 	logger.DefaultLogger.Init(logger.WithFields(map[string]interface{}{"service": "runtime"}))
-	if ctx.Args().Len() == 0 {
-		return cli.ShowSubcommandHelp(ctx)
-	}
 
-	name := ctx.String("name")
-
-	// set name based on input arg if specified
-	if v := ctx.Args().Get(0); len(v) > 0 {
-		name = v
+	selector := ctx.String("selector")
+	if ctx.Args().Len() == 0 && len(selector) == 0 {
+		return cli.ShowSubcommandHelp(ctx)
 	}
 
-	// must specify service name
-	if len(name) == 0 {
-		fmt.Println(logUsage)
-		return nil
+	// only show the records matching the expression
+	var grep *regexp.Regexp
+	if v := ctx.String("grep"); len(v) > 0 {
+		re, err := regexp.Compile(v)
+		if err != nil {
+			return fmt.Errorf("invalid --grep expression: %v", err)
+		}
+		grep = re
 	}
 
 	// get the args
@@ -793,6 +832,55 @@ func getLogs(ctx *cli.Context) error {
 	//	readSince = time.Now().Add(-d)
 	//}
 
+	output := ctx.String("output")
+
+	// get the logs of each of the selected services, the records are prefixed with the name of
+	// the service they're from
+	if len(selector) > 0 {
+		srvs, ns, err := selectServices(ctx)
+		if err != nil {
+			return err
+		}
+		options = append(options, runtime.LogsNamespace(ns))
+
+		var mtx sync.Mutex
+		var wg sync.WaitGroup
+		errs := make([]error, len(srvs))
+		for i, srv := range srvs {
+			wg.Add(1)
+			go func(i int, srv *runtime.Service) {
+				defer wg.Done()
+				errs[i] = printLogs(srv, options, output, grep, srv.Name+": ", &mtx)
+			}(i, srv)
+
+			// without following the logs are printed one service after another
+			if !follow {
+				wg.Wait()
+			}
+		}
+		wg.Wait()
+
+		for i, err := range errs {
+			if err != nil {
+				return util.CliError(fmt.Errorf("Error reading logs of %v: %v", srvs[i].Name, err))
+			}
+		}
+		return nil
+	}
+
+	name := ctx.String("name")
+
+	// set name based on input arg if specified
+	if v := ctx.Args().Get(0); len(v) > 0 {
+		name = v
+	}
+
+	// must specify service name
+	if len(name) == 0 {
+		fmt.Println(logUsage)
+		return nil
+	}
+
 	var ref string
 
 	if parts := strings.Split(name, "@"); len(parts) > 1 {
@@ -821,34 +909,51 @@ func getLogs(ctx *cli.Context) error {
 	}
 	options = append(options, runtime.LogsNamespace(ns))
 
-	logs, err := runtime.Logs(srv, options...)
+	if err := printLogs(srv, options, output, grep, "", &sync.Mutex{}); err != nil {
+		if status.Convert(err).Code() == codes.NotFound {
+			return cli.Exit("Service not found", 1)
+		}
+		return util.CliError(fmt.Errorf("Error reading logs: %s\n", status.Convert(err).Message()))
+	}
+
+	return nil
+}
 
+// printLogs prints the log records of the service matching the expression, if any. Text records
+// are prefixed with the prefix. The mutex is held while printing so records of services read concurrently
+// aren't interleaved.
+func printLogs(srv *runtime.Service, options []runtime.LogsOption, output string, grep *regexp.Regexp, prefix string, mtx *sync.Mutex) error {
+	logs, err := runtime.Logs(srv, options...)
 	if err != nil {
-		return util.CliError(err)
+		return err
 	}
 
-	output := ctx.String("output")
-
 	// range over all records until its closed
 	for record := range logs.Chan() {
+		if grep != nil && !grep.MatchString(record.Message) {
+			continue
+		}
+
+		mtx.Lock()
 		switch output {
 		case "json":
+			// json records stay valid so the service is set in their metadata instead
+			if len(prefix) > 0 {
+				if record.Metadata == nil {
+					record.Metadata = map[string]string{}
+				}
+				record.Metadata["service"] = srv.Name
+			}
 			b, _ := json.Marshal(record)
 			fmt.Printf("%v\n", string(b))
 		default:
-			fmt.Printf("%v\n", record.Message)
+			fmt.Printf("%v%v\n", prefix, record.Message)
 		}
+		mtx.Unlock()
 	}
 
 	// check for an error
-	if err := logs.Error(); err != nil {
-		if status.Convert(err).Code() == codes.NotFound {
-			return cli.Exit("Service not found", 1)
-		}
-		return util.CliError(fmt.Errorf("Error reading logs: %s\n", status.Convert(err).Message()))
-	}
-
-	return nil
+	return logs.Error()
 }
 
 func humanizeStatus(status runtime.ServiceStatus) string {
@@ -0,0 +1,107 @@
+// Package scrub implements the `micro scrub` subcommands
+// for example:
+//   micro scrub test '{"email": "john@example.com"}'
+//   micro scrub test --rules rules.json --service users < payload.json
+//   micro scrub test --config --service users 'call +447700900123'
+package scrub
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/util/helper"
+	"github.com/micro/micro/v3/util/scrub"
+	"github.com/urfave/cli/v2"
+)
+
+func init() {
+	cmd.Register(&cli.Command{
+		Name:   "scrub",
+		Usage:  "Test the rules which scrub personal data from traces, logs, captures and analytics",
+		Action: helper.UnexpectedSubcommand,
+		Subcommands: []*cli.Command{
+			{
+				Name:  "test",
+				Usage: "Print a sample payload scrubbed and the rules which matched it",
+				Description: `The payload is read from the argument or stdin. The built in detectors of emails, card numbers,
+tokens and credentials are always applied, followed by the custom rules of the service.`,
+				ArgsUsage: "[payload]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "rules",
+						Usage: "File of the custom rules to test, a JSON array in the format of the scrub config",
+					},
+					&cli.BoolFlag{
+						Name:  "config",
+						Usage: "Test the custom rules set in the config service",
+					},
+					&cli.StringFlag{
+						Name:  "service",
+						Usage: "Service the payload is from, only the custom rules of the service are applied",
+					},
+					&cli.StringFlag{
+						Name:  "content_type",
+						Usage: "Content type of the payload, JSON payloads are detected if blank",
+					},
+				},
+				Action: test,
+			},
+		},
+	})
+}
+
+func test(ctx *cli.Context) error {
+	var rules []*scrub.Rule
+	if path := ctx.String("rules"); len(path) > 0 {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("Error reading rules: %v", err)
+		}
+		rs, err := scrub.Parse(b)
+		if err != nil {
+			return fmt.Errorf("Error parsing rules: %v", err)
+		}
+		rules = append(rules, rs...)
+	}
+	if ctx.Bool("config") {
+		rs, err := scrub.Load()
+		if err != nil {
+			return fmt.Errorf("Error loading rules: %v", err)
+		}
+		rules = append(rules, rs...)
+	}
+
+	var payload []byte
+	if ctx.Args().Len() > 0 {
+		payload = []byte(strings.Join(ctx.Args().Slice(), " "))
+	} else {
+		b, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("Error reading payload: %v", err)
+		}
+		payload = b
+	}
+
+	applied := append([]*scrub.Rule{}, scrub.Detectors...)
+	for _, r := range rules {
+		if r.Applies(ctx.String("service")) {
+			applied = append(applied, r)
+		}
+	}
+
+	res, err := scrub.Apply(applied, ctx.String("content_type"), payload)
+	if err != nil {
+		return fmt.Errorf("Error scrubbing payload, it would be omitted: %v", err)
+	}
+	fmt.Println(res.Payload)
+
+	if len(res.Matched) == 0 {
+		fmt.Fprintln(os.Stderr, "No rules matched")
+	} else {
+		fmt.Fprintf(os.Stderr, "Matched: %v\n", strings.Join(res.Matched, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package sdk
+
+import (
+	"io"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// python generates a single module client which only depends on the standard library. Types are
+// TypedDicts so requests and responses are plain dicts.
+type python struct{}
+
+var pyScalars = map[string]string{
+	"string":  "str",
+	"bool":    "bool",
+	"int32":   "int",
+	"uint32":  "int",
+	"float32": "float",
+	"float64": "float",
+	// 64 bit integers are encoded as strings in JSON to avoid losing precision
+	"int64":  "str",
+	"uint64": "str",
+
+	"google.protobuf.Timestamp": "str",
+	"google.protobuf.Duration":  "str",
+	"google.protobuf.Struct":    "Dict[str, Any]",
+}
+
+var pyLanguage = &language{
+	scalars: pyScalars,
+	any:     "Any",
+	list:    func(s string) string { return "List[" + s + "]" },
+	dict:    func(s string) string { return "Dict[str, " + s + "]" },
+	// types are quoted since they may be referenced before they're defined
+	named: func(srv, typ string) string { return `"` + typeName(srv, typ) + `"` },
+}
+
+// snake converts a name to snake case, e.g. greeterCall becomes greeter_call
+func snake(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (p *python) Filename() string {
+	return "client.py"
+}
+
+func (p *python) Generate(w io.Writer, services []*Service) error {
+	return pyTemplate.Execute(w, services)
+}
+
+var pyTemplate = template.Must(template.New("python").Funcs(template.FuncMap{
+	"class": identifier,
+	"field": func(name string) string { return snake(identifier(name)) },
+	"method": func(srv string, ep *Endpoint) string {
+		h, m := method(srv, ep)
+		if len(h) == 0 {
+			return snake(m)
+		}
+		return snake(h) + "_" + snake(m)
+	},
+	"name": typeName,
+	"type": pyLanguage.typeOf,
+}).Parse(`# Code generated by micro sdk generate. DO NOT EDIT.
+
+import json
+import threading
+import time
+import urllib.error
+import urllib.request
+from typing import Any, Dict, Iterator, List, Optional, TypedDict
+
+# tokens are refreshed when they have less than this many seconds left
+REFRESH_WINDOW = 30
+
+
+class MicroError(Exception):
+    """The error returned by services"""
+
+    def __init__(self, id: str = "", code: int = 0, detail: str = "", status: str = "", **kwargs):
+        super().__init__(detail or status or "unknown error")
+        self.id = id
+        self.code = code
+        self.detail = detail
+        self.status = status
+
+
+class Client:
+    """Calls services via the api gateway.
+
+    A token is used as is, otherwise when the id and secret of an account are set a token is
+    acquired using them and refreshed before it expires. Failed calls are retried.
+    """
+
+    def __init__(
+        self,
+        address: str = "http://localhost:8080",
+        namespace: str = "",
+        token: str = "",
+        id: str = "",
+        secret: str = "",
+        retries: int = 1,
+        timeout: float = 30,
+    ):
+        self.address = address.rstrip("/")
+        self.namespace = namespace
+        self.retries = retries
+        self.timeout = timeout
+        self._static_token = token
+        self._id = id
+        self._secret = secret
+        self._token: Optional[Dict[str, Any]] = None
+        self._lock = threading.Lock()
+
+    def call(self, path: str, req: Any) -> Any:
+        """Call an endpoint, retrying timeouts, internal server errors and failed connections"""
+        attempt = 0
+        while True:
+            try:
+                with self._post(path, req, "application/json") as rsp:
+                    body = rsp.read()
+                return json.loads(body) if body else {}
+            except (MicroError, urllib.error.URLError) as e:
+                if attempt >= self.retries or not _retryable(e):
+                    raise
+                attempt += 1
+                time.sleep(_backoff(attempt))
+
+    def stream(self, path: str, req: Any) -> Iterator[Any]:
+        """Stream the responses of a streaming endpoint, which are sent by the gateway as lines of JSON"""
+        with self._post(path, req, "application/x-ndjson") as rsp:
+            for line in rsp:
+                line = line.strip()
+                if not line:
+                    continue
+                msg = json.loads(line)
+                # errors which occur after the stream has started are sent as the last line
+                if isinstance(msg, dict) and {"id", "code", "status"} <= msg.keys():
+                    raise MicroError(**msg)
+                yield msg
+
+    def _post(self, path: str, req: Any, accept: str):
+        headers = {"Content-Type": "application/json", "Accept": accept}
+        if self.namespace:
+            headers["Micro-Namespace"] = self.namespace
+        token = self._access_token()
+        if token:
+            headers["Authorization"] = "Bearer " + token
+        return self._request(path, req or {}, headers)
+
+    def _request(self, path: str, req: Any, headers: Dict[str, str]):
+        r = urllib.request.Request(
+            self.address + path, data=json.dumps(req).encode(), headers=headers, method="POST"
+        )
+        try:
+            return urllib.request.urlopen(r, timeout=self.timeout)
+        except urllib.error.HTTPError as e:
+            body = e.read().decode()
+            try:
+                err = json.loads(body)
+            except ValueError:
+                err = {"detail": body}
+            err["code"] = e.code
+            raise MicroError(**err) from None
+
+    def _access_token(self) -> str:
+        if self._static_token:
+            return self._static_token
+        if not self._id or not self._secret:
+            return ""
+
+        with self._lock:
+            if self._token and float(self._token.get("expiry", 0)) - time.time() > REFRESH_WINDOW:
+                return self._token["access_token"]
+            if self._token:
+                try:
+                    self._token = self._generate_token({"refresh_token": self._token["refresh_token"]})
+                    return self._token["access_token"]
+                except MicroError:
+                    # the refresh token has expired or been revoked so fall back to the credentials
+                    pass
+            self._token = self._generate_token({"id": self._id, "secret": self._secret})
+            return self._token["access_token"]
+
+    def _generate_token(self, req: Dict[str, Any]) -> Dict[str, Any]:
+        headers = {"Content-Type": "application/json"}
+        if self.namespace:
+            headers["Micro-Namespace"] = self.namespace
+        with self._request("/auth/Auth/Token", req, headers) as rsp:
+            return json.loads(rsp.read()).get("token", {})
+
+
+def _retryable(e: Exception) -> bool:
+    """Matches the go client, retrying timeouts, internal server errors and failed connections"""
+    if isinstance(e, MicroError):
+        return e.code in (408, 500)
+    return True
+
+
+def _backoff(attempts: int) -> float:
+    """Matches the go client, attempts^e * 100ms capped at 2 minutes"""
+    return min(pow(attempts, 2.718281828459045) * 0.1, 120)
+{{- range $srv := .}}
+
+
+# {{$srv.Name}}
+{{- range $srv.Types}}
+
+{{name $srv.Name .Name}} = TypedDict(
+    "{{name $srv.Name .Name}}",
+    {
+{{- range .Fields}}
+        "{{.Name}}": {{type $srv .Type}},
+{{- end}}
+    },
+    total=False,
+)
+{{- end}}
+
+
+class {{class $srv.Name}}Service:
+    def __init__(self, client: Client):
+        self.client = client
+{{- range $srv.Endpoints}}
+{{if .Stream}}
+    def {{method $srv.Name .}}(self, req: {{type $srv .Request}}) -> Iterator[{{type $srv .Response}}]:
+        return self.client.stream("{{.Path}}", req)
+{{- else}}
+    def {{method $srv.Name .}}(self, req: {{type $srv .Request}}) -> {{type $srv .Response}}:
+        return self.client.call("{{.Path}}", req)
+{{- end}}
+{{- end}}
+{{- end}}
+
+
+class Services:
+    """Groups a client for each service"""
+
+    def __init__(self, **kwargs):
+        self.client = Client(**kwargs)
+{{- range .}}
+        self.{{field .Name}} = {{class .Name}}Service(self.client)
+{{- end}}
+`))
@@ -0,0 +1,322 @@
+// Package sdk implements the `micro sdk` command which generates client libraries in other
+// languages from the endpoints services register, for example:
+//   micro sdk generate --lang ts helloworld
+//   micro sdk generate --lang python --output ./clients
+package sdk
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/util/helper"
+	"github.com/urfave/cli/v2"
+)
+
+// Generator renders a client library for a set of services
+type Generator interface {
+	// Filename of the generated client, e.g. client.ts
+	Filename() string
+	// Generate writes the client to w
+	Generate(w io.Writer, services []*Service) error
+}
+
+// Generators are keyed by the value passed to --lang
+var Generators = map[string]Generator{
+	"ts":     new(typescript),
+	"python": new(python),
+}
+
+func init() {
+	cmd.Register(&cli.Command{
+		Name:   "sdk",
+		Usage:  "Generate client libraries for services",
+		Action: helper.UnexpectedSubcommand,
+		Subcommands: []*cli.Command{
+			{
+				Name:      "generate",
+				Usage:     "Generate a client for the running services",
+				UsageText: "micro sdk generate --lang [ts|python] [service...]",
+				Description: `Generates a client library from the endpoints registered by the services. If no services
+are specified then every service in the namespace is included. The client calls services via
+the api gateway, acquires and refreshes tokens, retries failed calls and streams the responses of streaming endpoints.`,
+				Action: generate,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "lang",
+						Aliases:  []string{"l"},
+						Usage:    "Language of the client e.g ts or python",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Directory to write the client to, defaults to ./sdk/{lang}",
+					},
+				},
+			},
+		},
+	})
+}
+
+func generate(ctx *cli.Context) error {
+	gen, ok := Generators[ctx.String("lang")]
+	if !ok {
+		var langs []string
+		for k := range Generators {
+			langs = append(langs, k)
+		}
+		sort.Strings(langs)
+		return fmt.Errorf("unsupported language %q, must be one of %s", ctx.String("lang"), strings.Join(langs, ", "))
+	}
+
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return err
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return err
+	}
+	services, err := lookup(ns, ctx.Args().Slice())
+	if err != nil {
+		return err
+	}
+
+	dir := ctx.String("output")
+	if len(dir) == 0 {
+		dir = filepath.Join("sdk", ctx.String("lang"))
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, gen.Filename())
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := gen.Generate(f, Build(services)); err != nil {
+		return err
+	}
+	fmt.Printf("Generated %s with %d services\n", path, len(services))
+	return nil
+}
+
+// lookup the named services in the registry, or all services if none are named
+func lookup(ns string, names []string) ([]*registry.Service, error) {
+	if len(names) == 0 {
+		list, err := registry.DefaultRegistry.ListServices(registry.ListDomain(ns))
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range list {
+			names = append(names, s.Name)
+		}
+	}
+
+	var services []*registry.Service
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		srvs, err := registry.DefaultRegistry.GetService(name, registry.GetDomain(ns))
+		if err == registry.ErrNotFound || len(srvs) == 0 {
+			return nil, fmt.Errorf("service %s not found", name)
+		} else if err != nil {
+			return nil, err
+		}
+		// the first version returned is the one the gateway routes to by default
+		services = append(services, srvs[0])
+	}
+	return services, nil
+}
+
+// Service is the language independent description of a service used by the generators
+type Service struct {
+	// Name of the service as registered, e.g. helloworld
+	Name string
+	// Endpoints sorted by name
+	Endpoints []*Endpoint
+	// Types used by the requests and responses, sorted by name
+	Types []*Type
+}
+
+// Endpoint of a service
+type Endpoint struct {
+	// Name as registered, e.g. Helloworld.Call
+	Name string
+	// Path the api gateway serves the endpoint on, e.g. /helloworld/Helloworld/Call
+	Path string
+	// Request and Response are the type names, empty if the type isn't described
+	Request  string
+	Response string
+	// Stream is true for streaming endpoints
+	Stream bool
+}
+
+// Type is a message with described fields
+type Type struct {
+	Name   string
+	Fields []*Field
+}
+
+// Field of a type. The type is as registered, e.g. string, []Foo or map[string]int64
+type Field struct {
+	Name string
+	Type string
+}
+
+// Build the description of the services, any types which are defined by more than one service
+// are included in each
+func Build(services []*registry.Service) []*Service {
+	var result []*Service
+	for _, s := range services {
+		srv := &Service{Name: s.Name}
+		types := map[string]*Type{}
+
+		for _, ep := range s.Endpoints {
+			// subscribers and endpoints without a handler can't be called via the gateway
+			parts := strings.Split(ep.Name, ".")
+			if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 || ep.Metadata["subscriber"] == "true" {
+				continue
+			}
+			e := &Endpoint{
+				Name:   ep.Name,
+				Path:   fmt.Sprintf("/%s/%s/%s", s.Name, parts[0], parts[1]),
+				Stream: ep.Metadata["stream"] == "true",
+			}
+			if ep.Request != nil {
+				e.Request = ep.Request.Type
+				collect(ep.Request, types)
+			}
+			if ep.Response != nil {
+				e.Response = ep.Response.Type
+				collect(ep.Response, types)
+			}
+			srv.Endpoints = append(srv.Endpoints, e)
+		}
+
+		sort.Slice(srv.Endpoints, func(i, j int) bool { return srv.Endpoints[i].Name < srv.Endpoints[j].Name })
+		for _, t := range types {
+			srv.Types = append(srv.Types, t)
+		}
+		sort.Slice(srv.Types, func(i, j int) bool { return srv.Types[i].Name < srv.Types[j].Name })
+		result = append(result, srv)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// collect the types with described fields
+func collect(v *registry.Value, types map[string]*Type) {
+	if len(v.Values) == 0 || len(v.Type) == 0 {
+		return
+	}
+	if _, ok := types[v.Type]; ok {
+		return
+	}
+	t := &Type{Name: v.Type}
+	types[v.Type] = t
+	for _, f := range v.Values {
+		t.Fields = append(t.Fields, &Field{Name: f.Name, Type: f.Type})
+		collect(f, types)
+	}
+}
+
+// language describes how registered types are converted to those of a language
+type language struct {
+	// scalars maps the built in and well known types
+	scalars map[string]string
+	// any is used for types which aren't described
+	any string
+	// list and dict wrap the type of repeated and map values
+	list func(string) string
+	dict func(string) string
+	// named returns the name of a type the service describes
+	named func(srv, typ string) string
+}
+
+// typeOf converts a registered type to the language's
+func (l *language) typeOf(srv *Service, typ string) string {
+	switch {
+	case typ == "[]uint8":
+		// bytes are base64 encoded strings in JSON
+		return l.scalars["string"]
+	case strings.HasPrefix(typ, "[]"):
+		return l.list(l.typeOf(srv, strings.TrimPrefix(typ, "[]")))
+	case strings.HasPrefix(typ, "map["):
+		if i := strings.Index(typ, "]"); i > 0 {
+			return l.dict(l.typeOf(srv, typ[i+1:]))
+		}
+	}
+	if t, ok := l.scalars[typ]; ok {
+		return t
+	}
+	for _, t := range srv.Types {
+		if t.Name == typ {
+			return l.named(srv.Name, typ)
+		}
+	}
+	return l.any
+}
+
+// typeName prefixes the name of a type with the service, since the clients are a single file
+// and services often define types with the same names, e.g. helloworld and Request becomes
+// HelloworldRequest
+func typeName(srv, typ string) string {
+	prefix := identifier(srv)
+	if strings.HasPrefix(typ, prefix) {
+		return typ
+	}
+	return prefix + typ
+}
+
+// identifier converts a service name to a class name, e.g. foo-bar becomes FooBar
+func identifier(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	s := b.String()
+	if len(s) > 0 && unicode.IsDigit(rune(s[0])) {
+		s = "S" + s
+	}
+	return s
+}
+
+// method returns the name of the method for an endpoint. The handler is omitted when it matches
+// the service, e.g. Helloworld.Call becomes call but Greeter.Call becomes greeterCall.
+func method(srv string, ep *Endpoint) (string, string) {
+	parts := strings.Split(ep.Name, ".")
+	m := []rune(parts[1])
+	m[0] = unicode.ToLower(m[0])
+	if strings.EqualFold(parts[0], identifier(srv)) {
+		return "", string(m)
+	}
+	h := []rune(parts[0])
+	h[0] = unicode.ToLower(h[0])
+	return string(h), string(m)
+}
@@ -0,0 +1,122 @@
+package sdk
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+var testServices = []*registry.Service{
+	{
+		Name: "helloworld",
+		Endpoints: []*registry.Endpoint{
+			{
+				Name: "Helloworld.Call",
+				Request: &registry.Value{Type: "Request", Values: []*registry.Value{
+					{Name: "name", Type: "string"},
+				}},
+				Response: &registry.Value{Type: "Response", Values: []*registry.Value{
+					{Name: "msg", Type: "string"},
+					{Name: "count", Type: "int64"},
+					{Name: "tags", Type: "[]string"},
+					{Name: "meta", Type: "map[string]Meta"},
+					{Name: "created", Type: "google.protobuf.Timestamp"},
+					{Name: "user", Type: "User", Values: []*registry.Value{
+						{Name: "id", Type: "string"},
+					}},
+				}},
+			},
+			{
+				Name:     "Helloworld.Stream",
+				Request:  &registry.Value{Type: "StreamRequest"},
+				Response: &registry.Value{Type: "StreamResponse"},
+				Metadata: map[string]string{"stream": "true"},
+			},
+			{
+				Name:     "Greeter.SayHello",
+				Request:  &registry.Value{Type: "Request"},
+				Response: &registry.Value{Type: "Response"},
+			},
+			{
+				Name:     "Handle",
+				Metadata: map[string]string{"subscriber": "true"},
+			},
+		},
+	},
+}
+
+func TestBuild(t *testing.T) {
+	services := Build(testServices)
+	assert.Len(t, services, 1)
+
+	srv := services[0]
+	assert.Equal(t, "helloworld", srv.Name)
+	if assert.Len(t, srv.Endpoints, 3) {
+		assert.Equal(t, "Greeter.SayHello", srv.Endpoints[0].Name)
+		assert.Equal(t, "/helloworld/Helloworld/Call", srv.Endpoints[1].Path)
+		assert.True(t, srv.Endpoints[2].Stream)
+	}
+
+	var names []string
+	for _, t := range srv.Types {
+		names = append(names, t.Name)
+	}
+	assert.Equal(t, []string{"Request", "Response", "User"}, names)
+
+	ts := map[string]string{
+		"int64":                     "string",
+		"[]string":                  "string[]",
+		"[]uint8":                   "string",
+		"map[string]int32":          "{ [key: string]: number }",
+		"[]map[string]bool":         "Array<{ [key: string]: boolean }>",
+		"google.protobuf.Timestamp": "string",
+		"User":                      "HelloworldUser",
+		"[]User":                    "HelloworldUser[]",
+		"Meta":                      "any",
+	}
+	for typ, want := range ts {
+		assert.Equal(t, want, tsLanguage.typeOf(srv, typ), typ)
+	}
+
+	py := map[string]string{
+		"int64":            "str",
+		"[]float64":        "List[float]",
+		"map[string]User":  `Dict[str, "HelloworldUser"]`,
+		"HelloworldRecord": "Any",
+	}
+	for typ, want := range py {
+		assert.Equal(t, want, pyLanguage.typeOf(srv, typ), typ)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	services := Build(testServices)
+
+	var buf bytes.Buffer
+	assert.NoError(t, Generators["ts"].Generate(&buf, services))
+	ts := buf.String()
+	assert.Contains(t, ts, "export interface HelloworldResponse {")
+	assert.Contains(t, ts, "  meta?: { [key: string]: any };")
+	assert.Contains(t, ts, `  call(req: HelloworldRequest): Promise<HelloworldResponse> {`)
+	assert.Contains(t, ts, `  greeterSayHello(req: HelloworldRequest): Promise<HelloworldResponse> {`)
+	assert.Contains(t, ts, `return this.client.stream("/helloworld/Helloworld/Stream", req);`)
+	assert.Contains(t, ts, "this.helloworld = new HelloworldService(this.client);")
+
+	buf.Reset()
+	assert.NoError(t, Generators["python"].Generate(&buf, services))
+	py := buf.String()
+	assert.Contains(t, py, `HelloworldUser = TypedDict(`)
+	assert.Contains(t, py, `        "user": "HelloworldUser",`)
+	assert.Contains(t, py, `    def greeter_say_hello(self, req: "HelloworldRequest") -> "HelloworldResponse":`)
+	assert.Contains(t, py, `    def stream(self, req: Any) -> Iterator[Any]:`)
+	assert.Contains(t, py, "self.helloworld = HelloworldService(self.client)")
+}
+
+func TestIdentifier(t *testing.T) {
+	assert.Equal(t, "FooBar", identifier("foo-bar"))
+	assert.Equal(t, "GoMicroFoo", identifier("go.micro.foo"))
+	assert.Equal(t, "S1password", identifier("1password"))
+	assert.Equal(t, "greeter_say_hello", snake("greeterSayHello"))
+}
@@ -0,0 +1,303 @@
+package sdk
+
+import (
+	"io"
+	"strings"
+	"text/template"
+)
+
+// typescript generates a single file client which uses fetch, so runs in browsers and node 18+
+type typescript struct{}
+
+var tsScalars = map[string]string{
+	"string":  "string",
+	"bool":    "boolean",
+	"int32":   "number",
+	"uint32":  "number",
+	"float32": "number",
+	"float64": "number",
+	// 64 bit integers are encoded as strings in JSON to avoid losing precision
+	"int64":  "string",
+	"uint64": "string",
+
+	"google.protobuf.Timestamp": "string",
+	"google.protobuf.Duration":  "string",
+	"google.protobuf.Struct":    "{ [key: string]: any }",
+}
+
+var tsLanguage = &language{
+	scalars: tsScalars,
+	any:     "any",
+	list: func(s string) string {
+		if strings.ContainsAny(s, " {") {
+			return "Array<" + s + ">"
+		}
+		return s + "[]"
+	},
+	dict:  func(s string) string { return "{ [key: string]: " + s + " }" },
+	named: typeName,
+}
+
+func (t *typescript) Filename() string {
+	return "client.ts"
+}
+
+func (t *typescript) Generate(w io.Writer, services []*Service) error {
+	return tsTemplate.Execute(w, services)
+}
+
+var tsTemplate = template.Must(template.New("ts").Funcs(template.FuncMap{
+	"class": identifier,
+	"field": func(name string) string {
+		c := identifier(name)
+		return strings.ToLower(c[:1]) + c[1:]
+	},
+	"method": func(srv string, ep *Endpoint) string {
+		h, m := method(srv, ep)
+		if len(h) == 0 {
+			return m
+		}
+		return h + strings.ToUpper(m[:1]) + m[1:]
+	},
+	"name": typeName,
+	"type": tsLanguage.typeOf,
+}).Parse(`// Code generated by micro sdk generate. DO NOT EDIT.
+
+export interface Options {
+  // address of the api gateway, defaults to http://localhost:8080
+  address?: string;
+  // namespace the services are running in
+  namespace?: string;
+  // token to authenticate with, used as is
+  token?: string;
+  // id and secret of an account, used to acquire and refresh tokens
+  id?: string;
+  secret?: string;
+  // number of times a failed call is retried, defaults to 1
+  retries?: number;
+  // fetch implementation, defaults to the global
+  fetch?: typeof fetch;
+}
+
+// MicroError is the error returned by services
+export class MicroError extends Error {
+  id: string;
+  code: number;
+  detail: string;
+  status: string;
+
+  constructor(e: { id?: string; code?: number; detail?: string; status?: string }) {
+    super(e.detail || e.status || "unknown error");
+    this.id = e.id || "";
+    this.code = e.code || 0;
+    this.detail = e.detail || "";
+    this.status = e.status || "";
+  }
+}
+
+interface Token {
+  access_token: string;
+  refresh_token: string;
+  expiry: number;
+}
+
+// tokens are refreshed when they have less than this many seconds left
+const refreshWindow = 30;
+
+export class Client {
+  private opts: Options;
+  private tok?: Token;
+
+  constructor(opts: Options = {}) {
+    this.opts = { address: "http://localhost:8080", retries: 1, ...opts };
+  }
+
+  // call an endpoint, retrying timeouts, internal server errors and failed connections
+  async call<Req, Rsp>(path: string, req: Req): Promise<Rsp> {
+    for (let attempt = 0; ; attempt++) {
+      try {
+        const rsp = await this.post(path, req, "application/json");
+        return JSON.parse((await rsp.text()) || "{}") as Rsp;
+      } catch (e) {
+        if (attempt >= (this.opts.retries || 0) || !retryable(e)) {
+          throw e;
+        }
+        await sleep(backoff(attempt + 1));
+      }
+    }
+  }
+
+  // stream the responses of a streaming endpoint, which are sent by the gateway as lines of JSON
+  async *stream<Req, Rsp>(path: string, req: Req): AsyncGenerator<Rsp> {
+    const rsp = await this.post(path, req, "application/x-ndjson");
+    if (!rsp.body) {
+      return;
+    }
+    const reader = rsp.body.getReader();
+    const decoder = new TextDecoder();
+    let buf = "";
+    for (;;) {
+      const { done, value } = await reader.read();
+      if (value) {
+        buf += decoder.decode(value, { stream: true });
+      }
+      let idx: number;
+      while ((idx = buf.indexOf("\n")) >= 0) {
+        const line = buf.slice(0, idx).trim();
+        buf = buf.slice(idx + 1);
+        if (line.length) {
+          yield decodeLine<Rsp>(line);
+        }
+      }
+      if (done) {
+        if (buf.trim().length) {
+          yield decodeLine<Rsp>(buf.trim());
+        }
+        return;
+      }
+    }
+  }
+
+  private async post(path: string, req: any, accept: string): Promise<Response> {
+    const headers: Record<string, string> = {
+      "Content-Type": "application/json",
+      Accept: accept,
+    };
+    if (this.opts.namespace) {
+      headers["Micro-Namespace"] = this.opts.namespace;
+    }
+    const token = await this.token();
+    if (token) {
+      headers["Authorization"] = "Bearer " + token;
+    }
+    const f = this.opts.fetch || fetch;
+    const rsp = await f(this.opts.address + path, {
+      method: "POST",
+      headers,
+      body: JSON.stringify(req || {}),
+    });
+    if (!rsp.ok) {
+      const text = await rsp.text();
+      let err: any = { code: rsp.status, detail: text };
+      try {
+        err = { code: rsp.status, ...JSON.parse(text) };
+      } catch (e) {}
+      throw new MicroError(err);
+    }
+    return rsp;
+  }
+
+  // token returns the token to authenticate with. When an account is set a token is acquired
+  // using its credentials and refreshed before it expires.
+  private async token(): Promise<string | undefined> {
+    if (this.opts.token) {
+      return this.opts.token;
+    }
+    if (!this.opts.id || !this.opts.secret) {
+      return undefined;
+    }
+    if (this.tok && this.tok.expiry - Date.now() / 1000 > refreshWindow) {
+      return this.tok.access_token;
+    }
+
+    const creds = { id: this.opts.id, secret: this.opts.secret };
+    if (this.tok) {
+      try {
+        this.tok = await this.generateToken({ refresh_token: this.tok.refresh_token });
+        return this.tok.access_token;
+      } catch (e) {
+        // the refresh token has expired or been revoked so fall back to the credentials
+      }
+    }
+    this.tok = await this.generateToken(creds);
+    return this.tok.access_token;
+  }
+
+  private async generateToken(req: any): Promise<Token> {
+    const headers: Record<string, string> = { "Content-Type": "application/json" };
+    if (this.opts.namespace) {
+      headers["Micro-Namespace"] = this.opts.namespace;
+    }
+    const f = this.opts.fetch || fetch;
+    const rsp = await f(this.opts.address + "/auth/Auth/Token", {
+      method: "POST",
+      headers,
+      body: JSON.stringify(req),
+    });
+    const body = await rsp.json();
+    if (!rsp.ok) {
+      throw new MicroError({ code: rsp.status, ...body });
+    }
+    const t = body.token || {};
+    return { access_token: t.access_token, refresh_token: t.refresh_token, expiry: Number(t.expiry || 0) };
+  }
+}
+
+function decodeLine<Rsp>(line: string): Rsp {
+  const msg = JSON.parse(line);
+  // errors which occur after the stream has started are sent as the last line
+  if (msg && msg.id !== undefined && msg.code !== undefined && msg.status !== undefined) {
+    throw new MicroError(msg);
+  }
+  return msg as Rsp;
+}
+
+// retryable matches the go client, retrying timeouts, internal server errors and failed connections
+function retryable(e: any): boolean {
+  if (e instanceof MicroError) {
+    return e.code === 408 || e.code === 500;
+  }
+  return e instanceof TypeError;
+}
+
+// backoff matches the go client, attempts^e * 100ms capped at 2 minutes
+function backoff(attempts: number): number {
+  return Math.min(Math.pow(attempts, Math.E) * 100, 120000);
+}
+
+function sleep(ms: number): Promise<void> {
+  return new Promise((resolve) => setTimeout(resolve, ms));
+}
+{{- range $srv := .}}
+
+// {{$srv.Name}}
+{{- range $srv.Types}}
+
+export interface {{name $srv.Name .Name}} {
+{{- range .Fields}}
+  {{.Name}}?: {{type $srv .Type}};
+{{- end}}
+}
+{{- end}}
+
+export class {{class $srv.Name}}Service {
+  constructor(private client: Client) {}
+{{- range $srv.Endpoints}}
+{{if .Stream}}
+  {{method $srv.Name .}}(req: {{type $srv .Request}}): AsyncGenerator<{{type $srv .Response}}> {
+    return this.client.stream("{{.Path}}", req);
+  }
+{{- else}}
+  {{method $srv.Name .}}(req: {{type $srv .Request}}): Promise<{{type $srv .Response}}> {
+    return this.client.call("{{.Path}}", req);
+  }
+{{- end}}
+{{- end}}
+}
+{{- end}}
+
+// Services groups a client for each service
+export class Services {
+  client: Client;
+{{- range .}}
+  {{field .Name}}: {{class .Name}}Service;
+{{- end}}
+
+  constructor(opts: Options = {}) {
+    this.client = new Client(opts);
+{{- range .}}
+    this.{{field .Name}} = new {{class .Name}}Service(this.client);
+{{- end}}
+  }
+}
+`))
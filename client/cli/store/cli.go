@@ -130,6 +130,10 @@ func init() {
 						Usage:   "expiry in time.ParseDuration format",
 						Value:   "",
 					},
+					&cli.StringFlag{
+						Name:  "if_match",
+						Usage: "only write the record if its etag matches, as shown by micro store read --verbose. An empty etag only writes the record if it doesn't exist",
+					},
 					&cli.StringFlag{
 						Name:    "database",
 						Aliases: []string{"d"},
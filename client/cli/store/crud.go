@@ -76,7 +76,7 @@ func read(ctx *cli.Context) error {
 	default:
 		if ctx.Bool("verbose") {
 			w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
-			fmt.Fprintf(w, "%v \t %v \t %v\n", "KEY", "VALUE", "EXPIRY")
+			fmt.Fprintf(w, "%v \t %v \t %v \t %v\n", "KEY", "VALUE", "EXPIRY", "ETAG")
 			for _, r := range records {
 				var key, value, expiry string
 				key = r.Key
@@ -90,7 +90,7 @@ func read(ctx *cli.Context) error {
 				} else {
 					expiry = humanize.Time(time.Now().Add(r.Expiry))
 				}
-				fmt.Fprintf(w, "%v \t %v \t %v\n", key, value, expiry)
+				fmt.Fprintf(w, "%v \t %v \t %v \t %v\n", key, value, expiry, r.Etag)
 			}
 			w.Flush()
 			return nil
@@ -132,7 +132,14 @@ func write(ctx *cli.Context) error {
 		return err
 	}
 
-	if err := store.DefaultStore.Write(record, store.WriteTo(ns, ctx.String("table"))); err != nil {
+	opts := []store.WriteOption{store.WriteTo(ns, ctx.String("table"))}
+	if ctx.IsSet("if_match") {
+		opts = append(opts, store.IfMatch(ctx.String("if_match")))
+	}
+
+	if err := store.DefaultStore.Write(record, opts...); err == store.ErrConflict {
+		return fmt.Errorf("couldn't write, %s has been modified since it was read", record.Key)
+	} else if err != nil {
 		return errors.Wrap(err, "couldn't write")
 	}
 	return nil
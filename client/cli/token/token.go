@@ -4,6 +4,8 @@
 // micro://m3o.com/foo-bar-baz/asim@aslam.me:afsafasfasfaceevqcCEWVEWV
 // or
 // micro://m3o.com/foo-bar-baz:afsafasfasfaceevqcCEWVEWV
+// Tokens are encrypted with the key of the local cache, in which case the token is prefixed
+// with `enc.`. Tokens saved before they were encrypted are read as plain base64 encoded json.
 package token
 
 import (
@@ -18,6 +20,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/micro/micro/v3/client/cli/cache"
 	"github.com/micro/micro/v3/client/cli/namespace"
 	"github.com/micro/micro/v3/client/cli/util"
 	"github.com/micro/micro/v3/service/auth"
@@ -26,7 +29,11 @@ import (
 	"github.com/urfave/cli/v2"
 )
 
-const tokensFileName = "tokens"
+const (
+	tokensFileName = "tokens"
+	// prefix of the tokens which are encrypted
+	encryptedPrefix = "enc."
+)
 
 // Get tries a best effort read of auth token from user config.
 // Might have missing `RefreshToken` or `Expiry` fields in case of
@@ -111,10 +118,17 @@ func getTokens() (map[string]token, error) {
 		}
 		key := strings.Join(parts[0:len(parts)-1], ":")
 		base64Encoded := parts[len(parts)-1]
-		jsonMarshalled, err := base64.StdEncoding.DecodeString(base64Encoded)
+		encrypted := strings.HasPrefix(base64Encoded, encryptedPrefix)
+		jsonMarshalled, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(base64Encoded, encryptedPrefix))
 		if err != nil {
 			return nil, fmt.Errorf("Error base64 decoding token: %v", err)
 		}
+		if encrypted {
+			jsonMarshalled, err = cache.Decrypt(jsonMarshalled)
+			if err != nil {
+				return nil, fmt.Errorf("Error decrypting token: %v", err)
+			}
+		}
 		tok := token{}
 		err = json.Unmarshal(jsonMarshalled, &tok)
 		if err != nil {
@@ -183,7 +197,11 @@ func saveTokens(tokens map[string]token) error {
 		if err != nil {
 			return err
 		}
-		base64Token := base64.StdEncoding.EncodeToString(marshalledToken)
+		encrypted, err := cache.Encrypt(marshalledToken)
+		if err != nil {
+			return err
+		}
+		base64Token := encryptedPrefix + base64.StdEncoding.EncodeToString(encrypted)
 		_, err = buf.WriteString(key + ":" + base64Token + "\n")
 		if err != nil {
 			return err
@@ -0,0 +1,140 @@
+// Package update implements the `micro self-update` command
+// for example:
+//   micro self-update
+//   micro self-update --channel beta
+//   micro self-update pin v3.6.0
+package update
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/util/config"
+	"github.com/micro/micro/v3/util/update"
+	"github.com/urfave/cli/v2"
+)
+
+func init() {
+	cmd.Register(&cli.Command{
+		Name:  "self-update",
+		Usage: "Update the micro binary to the latest release, or the version pinned for the environment",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "channel",
+				Usage: "Channel to update from, e.g. stable or beta",
+				Value: update.DefaultChannel,
+			},
+			&cli.StringFlag{
+				Name:  "version",
+				Usage: "Version to update to, overriding the version pinned for the environment",
+			},
+			&cli.StringFlag{
+				Name:    "url",
+				Usage:   "URL the release manifests are served at",
+				Value:   update.DefaultURL,
+				EnvVars: []string{"MICRO_UPDATE_URL"},
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Update even if the binary is already at the version",
+			},
+			&cli.BoolFlag{
+				Name:  "allow-downgrade",
+				Usage: "Update even if the release is older than the binary",
+			},
+		},
+		Action: selfUpdate,
+		Subcommands: []*cli.Command{
+			{
+				Name:   "pin",
+				Usage:  "Pin the version the current environment updates to, e.g. micro self-update pin v3.6.0",
+				Action: pin,
+			},
+			{
+				Name:   "unpin",
+				Usage:  "Remove the version pinned for the current environment",
+				Action: unpin,
+			},
+		},
+	})
+}
+
+func pinPath(env string) string {
+	return config.Path("update", env, "version")
+}
+
+func selfUpdate(ctx *cli.Context) error {
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return err
+	}
+
+	version := ctx.String("version")
+	if len(version) == 0 {
+		if version, err = config.Get(pinPath(env.Name)); err != nil {
+			return err
+		}
+	}
+
+	opts := []update.Option{
+		update.URL(ctx.String("url")),
+		update.Channel(ctx.String("channel")),
+		update.Version(version),
+	}
+	rel, err := update.Fetch(context.Background(), opts...)
+	if err != nil {
+		return fmt.Errorf("Error fetching release: %v", err)
+	}
+
+	current := ctx.App.Version
+	if rel.Version == current && !ctx.Bool("force") {
+		fmt.Printf("Already at %v\n", current)
+		return nil
+	}
+	if err := update.CheckVersion(current, rel.Version); err != nil {
+		if err != update.ErrDowngrade || !ctx.Bool("allow-downgrade") {
+			return fmt.Errorf("Error updating to %v: %v", rel.Version, err)
+		}
+	}
+
+	bin, err := rel.Binary(update.Platform())
+	if err != nil {
+		return err
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if err := update.Apply(context.Background(), bin, exe, opts...); err != nil {
+		return fmt.Errorf("Error updating to %v: %v", rel.Version, err)
+	}
+	if err := config.WriteVersion(rel.Version); err != nil {
+		return err
+	}
+
+	fmt.Printf("Updated from %v to %v\n", current, rel.Version)
+	return nil
+}
+
+func pin(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return errors.New("expected a version e.g. micro self-update pin v3.6.0")
+	}
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return err
+	}
+	return config.Set(pinPath(env.Name), ctx.Args().First())
+}
+
+func unpin(ctx *cli.Context) error {
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return err
+	}
+	return config.Set(pinPath(env.Name), "")
+}
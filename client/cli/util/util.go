@@ -73,7 +73,7 @@ func IsBuiltInService(command string) bool {
 // CLIProxyAddress returns the proxy address which should be set for the client
 func CLIProxyAddress(ctx *cli.Context) (string, error) {
 	switch ctx.Args().First() {
-	case "new", "server", "help", "env":
+	case "new", "server", "help", "env", "completion", "man":
 		return "", nil
 	}
 
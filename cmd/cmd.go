@@ -8,30 +8,50 @@ import (
 	"math/rand"
 	"os"
 	"os/exec"
+	"regexp"
 	"runtime/debug"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/micro/micro/v3/client/cli/cache"
 	"github.com/micro/micro/v3/client/cli/util"
 	_ "github.com/micro/micro/v3/cmd/usage"
 	"github.com/micro/micro/v3/plugin"
 	"github.com/micro/micro/v3/profile"
 	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/auth/oidc"
 	"github.com/micro/micro/v3/service/broker"
 	"github.com/micro/micro/v3/service/client"
 	"github.com/micro/micro/v3/service/config"
 	configCli "github.com/micro/micro/v3/service/config/client"
 	storeConf "github.com/micro/micro/v3/service/config/store"
+	mudebug "github.com/micro/micro/v3/service/debug"
+	"github.com/micro/micro/v3/service/debug/trace"
+	"github.com/micro/micro/v3/service/debug/trace/otlp"
 	"github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/network"
 	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/router/failover"
 	"github.com/micro/micro/v3/service/server"
+	grpcServer "github.com/micro/micro/v3/service/server/grpc"
 	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/util/admission"
+	"github.com/micro/micro/v3/util/analytics"
+	"github.com/micro/micro/v3/util/anomaly"
+	inauth "github.com/micro/micro/v3/util/auth"
+	"github.com/micro/micro/v3/util/auth/audit"
+	"github.com/micro/micro/v3/util/auth/token/kms"
 	uconf "github.com/micro/micro/v3/util/config"
 	"github.com/micro/micro/v3/util/helper"
+	"github.com/micro/micro/v3/util/namespace"
+	"github.com/micro/micro/v3/util/netpolicy"
 	"github.com/micro/micro/v3/util/report"
+	"github.com/micro/micro/v3/util/residency"
+	"github.com/micro/micro/v3/util/scrub"
+	usync "github.com/micro/micro/v3/util/sync"
+	storesync "github.com/micro/micro/v3/util/sync/store"
 	"github.com/micro/micro/v3/util/user"
 	"github.com/micro/micro/v3/util/wrapper"
 	"github.com/urfave/cli/v2"
@@ -98,6 +118,16 @@ var (
 			Usage:   "Namespace the service is operating in",
 			Value:   "micro",
 		},
+		&cli.StringFlag{
+			Name:    "region",
+			EnvVars: []string{"MICRO_REGION"},
+			Usage:   "Region the service and its store and events backends are running in, used to enforce the data residency of namespaces and route requests within the region",
+		},
+		&cli.StringSliceFlag{
+			Name:    "failover",
+			EnvVars: []string{"MICRO_FAILOVER"},
+			Usage:   "Comma separated list of services to fail over to another region when every instance in the region is unhealthy, in the form service=max added latency, e.g. users=150ms. * matches every service",
+		},
 		&cli.StringFlag{
 			Name:    "auth_address",
 			EnvVars: []string{"MICRO_AUTH_ADDRESS"},
@@ -123,6 +153,42 @@ var (
 			EnvVars: []string{"MICRO_AUTH_PRIVATE_KEY"},
 			Usage:   "Private key for JWT auth (base64 encoded PEM)",
 		},
+		&cli.StringFlag{
+			Name:    "auth_kms_key",
+			EnvVars: []string{"MICRO_AUTH_KMS_KEY"},
+			Usage:   "Key used to sign JWTs in place of the private key, e.g. awskms:///<key arn>, gcpkms://<key version name> or vault://<mount>/<key>",
+		},
+		&cli.StringFlag{
+			Name:    "auth_oidc_issuer",
+			EnvVars: []string{"MICRO_AUTH_OIDC_ISSUER"},
+			Usage:   "URL of an OpenID Connect provider whose id tokens are accepted, e.g. https://accounts.google.com",
+		},
+		&cli.StringFlag{
+			Name:    "auth_oidc_client_id",
+			EnvVars: []string{"MICRO_AUTH_OIDC_CLIENT_ID"},
+			Usage:   "ID of the client registered with the OpenID Connect provider",
+		},
+		&cli.StringFlag{
+			Name:    "auth_oidc_client_secret",
+			EnvVars: []string{"MICRO_AUTH_OIDC_CLIENT_SECRET"},
+			Usage:   "Secret of the client registered with the OpenID Connect provider",
+		},
+		&cli.StringFlag{
+			Name:    "auth_oidc_scopes_claim",
+			EnvVars: []string{"MICRO_AUTH_OIDC_SCOPES_CLAIM"},
+			Usage:   "Claim of the OpenID Connect id tokens the scopes of accounts are mapped from, see auth_oidc_scope_mapping",
+			Value:   "groups",
+		},
+		&cli.StringSliceFlag{
+			Name:    "auth_oidc_scope_mapping",
+			EnvVars: []string{"MICRO_AUTH_OIDC_SCOPE_MAPPING"},
+			Usage:   "Comma separated list of the scopes accounts of the OpenID Connect provider are given, in the form value of the scopes claim=scope, e.g. engineers=developer. Values which aren't mapped give no scopes",
+		},
+		&cli.BoolFlag{
+			Name:    "auth_audit",
+			EnvVars: []string{"MICRO_AUTH_AUDIT"},
+			Usage:   "Record the authorization decision of every call the service receives in the audit log of the auth service",
+		},
 		&cli.StringFlag{
 			Name:    "registry_address",
 			EnvVars: []string{"MICRO_REGISTRY_ADDRESS"},
@@ -188,6 +254,11 @@ var (
 			Usage:   "Proxy requests via the HTTP address specified",
 			EnvVars: []string{"MICRO_PROXY"},
 		},
+		&cli.BoolFlag{
+			Name:    "offline",
+			Usage:   "Use the local cache of the registry rather than the micro server",
+			EnvVars: []string{"MICRO_OFFLINE"},
+		},
 		&cli.BoolFlag{
 			Name:    "report_usage",
 			Usage:   "Report usage statistics",
@@ -209,6 +280,108 @@ var (
 			Usage:   "Address to run the service on",
 			EnvVars: []string{"MICRO_SERVICE_ADDRESS"},
 		},
+		&cli.StringSliceFlag{
+			Name:    "client_wrappers",
+			Usage:   "Comma separated list of the built in client wrappers to apply, in the order calls pass through them. Wrappers which aren't listed are disabled",
+			EnvVars: []string{"MICRO_CLIENT_WRAPPERS"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "handler_wrappers",
+			Usage:   "Comma separated list of the built in handler wrappers to apply, in the order requests pass through them. Wrappers which aren't listed are disabled",
+			EnvVars: []string{"MICRO_HANDLER_WRAPPERS"},
+		},
+		&cli.DurationFlag{
+			Name:    "handler_timeout",
+			Usage:   "Default timeout of requests handled which don't set the Micro-Timeout header, e.g. 30s",
+			EnvVars: []string{"MICRO_HANDLER_TIMEOUT"},
+		},
+		&cli.IntFlag{
+			Name:    "server_concurrency",
+			Usage:   "Number of requests a service handles at once before queueing them, zero is unlimited",
+			EnvVars: []string{"MICRO_SERVER_CONCURRENCY"},
+			Value:   1000,
+		},
+		&cli.IntFlag{
+			Name:    "server_queue_size",
+			Usage:   "Number of requests which can wait to be handled before they're rejected",
+			EnvVars: []string{"MICRO_SERVER_QUEUE_SIZE"},
+			Value:   1000,
+		},
+		&cli.StringSliceFlag{
+			Name:    "service_warmup",
+			Usage:   "Warmup the service before it starts, connecting to the comma separated list of critical dependencies",
+			EnvVars: []string{"MICRO_SERVICE_WARMUP"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "service_dependency",
+			Usage:   "Set the criticality of a dependency e.g. store=critical, the service isn't ready while its critical dependencies are unhealthy",
+			EnvVars: []string{"MICRO_SERVICE_DEPENDENCY"},
+		},
+		&cli.StringFlag{
+			Name:    "service_team",
+			Usage:   "Set the team which owns the service, alerts about the service are routed to the team",
+			EnvVars: []string{"MICRO_SERVICE_TEAM"},
+		},
+		&cli.StringFlag{
+			Name:    "service_oncall",
+			Usage:   "Set the oncall rotation of the team which owns the service e.g. payments-primary",
+			EnvVars: []string{"MICRO_SERVICE_ONCALL"},
+		},
+		&cli.StringFlag{
+			Name:    "service_slack_channel",
+			Usage:   "Set the slack channel of the team which owns the service e.g. #payments",
+			EnvVars: []string{"MICRO_SERVICE_SLACK_CHANNEL"},
+		},
+		&cli.StringFlag{
+			Name:    "service_runbook",
+			Usage:   "Set the url of the runbook of the service",
+			EnvVars: []string{"MICRO_SERVICE_RUNBOOK"},
+		},
+		&cli.StringFlag{
+			Name:    "service_job",
+			Usage:   "Run the job of the service with the command and exit rather than serving requests",
+			EnvVars: []string{"MICRO_SERVICE_JOB"},
+		},
+		&cli.BoolFlag{
+			Name:    "service_mtls",
+			Usage:   "Authenticate the connections between services with certificates issued by the auth service",
+			EnvVars: []string{"MICRO_SERVICE_MTLS"},
+		},
+		&cli.BoolFlag{
+			Name:    "service_mtls_plaintext",
+			Usage:   "Dial the services which haven't been issued a certificate without TLS rather than failing",
+			EnvVars: []string{"MICRO_SERVICE_MTLS_PLAINTEXT"},
+		},
+		&cli.BoolFlag{
+			Name:    "service_wire_compatible",
+			Usage:   "Expose the service as a standard gRPC service so plain gRPC clients can call it directly",
+			EnvVars: []string{"MICRO_SERVICE_WIRE_COMPATIBLE"},
+		},
+		&cli.BoolFlag{
+			Name:    "service_reflection",
+			Usage:   "Serve the gRPC reflection service so tools like grpcurl can discover the endpoints of the service",
+			EnvVars: []string{"MICRO_SERVICE_REFLECTION"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "analytics_sample",
+			Usage:   "Sample rates of the analytics tap, either a rate e.g. 0.1 or a route prefix and rate e.g. /users=0.5. The tap is enabled by the analytics handler wrapper or api flag",
+			EnvVars: []string{"MICRO_ANALYTICS_SAMPLE"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "analytics_scrub",
+			Usage:   "Patterns scrubbed from the routes and user agents published by the analytics tap, in addition to emails, uuids and numeric ids",
+			EnvVars: []string{"MICRO_ANALYTICS_SCRUB"},
+		},
+		&cli.StringFlag{
+			Name:    "analytics_salt",
+			Usage:   "Salt of the account hashes published by the analytics tap, set it so the hashes of an account match across instances and restarts. Defaults to a random salt",
+			EnvVars: []string{"MICRO_ANALYTICS_SALT"},
+		},
+		&cli.Float64Flag{
+			Name:    "anomaly_sensitivity",
+			Usage:   "Number of standard deviations from its baseline a metric must be for the anomaly detector to raise an alert, lower values raise more alerts. The detector is enabled by the anomaly handler wrapper",
+			EnvVars: []string{"MICRO_ANOMALY_SENSITIVITY"},
+		},
 		&cli.StringFlag{
 			Name:    "config_secret_key",
 			Usage:   "Key to use when encoding/decoding secret config values. Will be generated and saved to file if not provided.",
@@ -220,6 +393,21 @@ var (
 			Usage:   "The host:port of the opentracing agent e.g. localhost:6831",
 			EnvVars: []string{"MICRO_TRACING_REPORTER_ADDRESS"},
 		},
+		&cli.StringFlag{
+			Name:    "tracing_otlp_endpoint",
+			Usage:   "Endpoint of an OpenTelemetry collector the spans of the trace wrappers are exported to over http e.g. localhost:4318",
+			EnvVars: []string{"MICRO_TRACING_OTLP_ENDPOINT"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "tracing_otlp_headers",
+			Usage:   "Headers sent to the OpenTelemetry collector in the form key=value",
+			EnvVars: []string{"MICRO_TRACING_OTLP_HEADERS"},
+		},
+		&cli.BoolFlag{
+			Name:    "tracing_b3",
+			Usage:   "Propagate the trace in B3 headers as well as the W3C traceparent, for systems such as zipkin which only read B3",
+			EnvVars: []string{"MICRO_TRACING_B3"},
+		},
 	}
 )
 
@@ -325,7 +513,8 @@ func (c *command) Before(ctx *cli.Context) error {
 	}
 
 	// certain commands don't require loading
-	if ctx.Args().First() == "env" {
+	switch ctx.Args().First() {
+	case "env", "completion", "man":
 		return nil
 	}
 
@@ -335,7 +524,7 @@ func (c *command) Before(ctx *cli.Context) error {
 	// if no profile is set then set one
 	if len(prof) == 0 {
 		switch ctx.Args().First() {
-		case "service", "server":
+		case "service", "server", "dev":
 			prof = "local"
 		default:
 			prof = "client"
@@ -369,6 +558,12 @@ func (c *command) Before(ctx *cli.Context) error {
 		client.DefaultClient.Init(client.Proxy(proxy))
 	}
 
+	// check the CLI can reach the micro server so commands can fail fast or use the cache
+	cliProxy := !c.service && len(proxy) > 0
+	if cliProxy {
+		cache.Offline = ctx.Bool("offline") || !cache.Reachable(proxy, cache.ProbeTimeout)
+	}
+
 	// use the internal network lookup
 	client.DefaultClient.Init(
 		client.Lookup(network.Lookup),
@@ -376,20 +571,54 @@ func (c *command) Before(ctx *cli.Context) error {
 
 	onceBefore.Do(func() {
 		// wrap the client
-		client.DefaultClient = wrapper.AuthClient(client.DefaultClient)
-		client.DefaultClient = wrapper.TraceCall(client.DefaultClient)
-		client.DefaultClient = wrapper.LogClient(client.DefaultClient)
-		client.DefaultClient = wrapper.OpentraceClient(client.DefaultClient)
+		client.DefaultClient.Init(client.WrapCall(failover.CallWrapper))
+
+		clientWrappers := wrapper.DefaultClientWrappers
+		if ctx.IsSet("client_wrappers") {
+			clientWrappers = ctx.StringSlice("client_wrappers")
+		}
+		var names []string
+		for _, n := range clientWrappers {
+			// only services identify themselves to the services they call
+			if n == "from_service" && !c.service {
+				continue
+			}
+			names = append(names, n)
+		}
+		wrapped, err := wrapper.WrapClient(client.DefaultClient, names...)
+		if err != nil {
+			logger.Fatalf("Error wrapping the client: %v", err)
+		}
+		client.DefaultClient = wrapped
+		if cliProxy && cache.Offline {
+			client.DefaultClient = cache.OfflineClient(client.DefaultClient, proxy)
+		}
 
 		// wrap the server
-		server.DefaultServer.Init(
-			server.WrapHandler(wrapper.AuthHandler()),
-			server.WrapHandler(wrapper.TraceHandler()),
-			server.WrapHandler(wrapper.HandlerStats()),
-			server.WrapHandler(wrapper.LogHandler()),
-			server.WrapHandler(wrapper.MetricsHandler()),
-			server.WrapHandler(wrapper.OpenTraceHandler()),
+		wrapper.DefaultTimeout = ctx.Duration("handler_timeout")
+		admission.DefaultController.Init(
+			admission.Concurrency(ctx.Int("server_concurrency")),
+			admission.QueueSize(ctx.Int("server_queue_size")),
 		)
+		handlerWrappers := wrapper.DefaultHandlerWrappers
+		if ctx.IsSet("handler_wrappers") {
+			handlerWrappers = ctx.StringSlice("handler_wrappers")
+		}
+		hws, err := wrapper.HandlerWrappers(handlerWrappers...)
+		if err != nil {
+			logger.Fatalf("Error wrapping the server: %v", err)
+		}
+		var authed bool
+		for _, n := range handlerWrappers {
+			authed = authed || n == "auth"
+		}
+		if !authed {
+			logger.Warnf("The auth handler wrapper is disabled, requests to the service won't be authorized")
+		}
+		for _, w := range hws {
+			server.DefaultServer.Init(server.WrapHandler(w))
+		}
+		wrapper.AppliedHandlerWrappers = handlerWrappers
 	})
 
 	// setup auth
@@ -409,7 +638,15 @@ func (c *command) Before(ctx *cli.Context) error {
 	// load the jwt private and public keys, in the case of the server we want to generate them if not
 	// present. The server will inject these creds into the core services, if the services generated
 	// the credentials themselves then they wouldn't match
-	if len(ctx.String("auth_public_key")) > 0 || len(ctx.String("auth_private_key")) > 0 {
+	if len(ctx.String("auth_kms_key")) > 0 {
+		// the private key is held by the kms, tokens are verified using its public key. The public
+		// key isn't set so it's read from the signer, which refreshes it when the key is rotated.
+		signer, err := kms.NewSigner(ctx.String("auth_kms_key"))
+		if err != nil {
+			logger.Fatalf("Error loading kms key: %v", err)
+		}
+		authOpts = append(authOpts, auth.Signer(signer))
+	} else if len(ctx.String("auth_public_key")) > 0 || len(ctx.String("auth_private_key")) > 0 {
 		authOpts = append(authOpts, auth.PublicKey(ctx.String("auth_public_key")))
 		authOpts = append(authOpts, auth.PrivateKey(ctx.String("auth_private_key")))
 	} else if ctx.Args().First() == "server" || ctx.Args().First() == "service" {
@@ -422,22 +659,123 @@ func (c *command) Before(ctx *cli.Context) error {
 
 	auth.DefaultAuth.Init(authOpts...)
 
+	// accept the id tokens of an external identity provider
+	if len(ctx.String("auth_oidc_issuer")) > 0 && oidc.DefaultProvider == nil {
+		ns := ctx.String("namespace")
+		if len(ns) == 0 {
+			ns = namespace.DefaultNamespace
+		}
+		mapping := map[string][]string{}
+		for _, m := range ctx.StringSlice("auth_oidc_scope_mapping") {
+			parts := strings.SplitN(m, "=", 2)
+			if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+				logger.Fatalf("Error configuring OpenID Connect: invalid scope mapping %v", m)
+			}
+			mapping[parts[0]] = append(mapping[parts[0]], parts[1])
+		}
+		oidc.DefaultProvider = oidc.NewProvider(
+			oidc.Issuer(ctx.String("auth_oidc_issuer")),
+			oidc.ClientCredentials(ctx.String("auth_oidc_client_id"), ctx.String("auth_oidc_client_secret")),
+			oidc.ScopesClaim(ctx.String("auth_oidc_scopes_claim")),
+			oidc.ScopeMapping(mapping),
+			oidc.Namespace(ns),
+		)
+		auth.DefaultAuth = oidc.NewAuth(auth.DefaultAuth, oidc.DefaultProvider)
+	}
+
+	// record the decisions of the auth handler wrapper
+	audit.DefaultRecorder.Init(audit.Enabled(ctx.Bool("auth_audit")))
+
 	// setup auth credentials, use local credentials for the CLI and injected creds
 	// for the service.
 	var err error
 	if c.service {
-		err = setupAuthForService()
+		// core services are run using micro service <name>
+		name := ctx.String("service_name")
+		if len(name) == 0 && ctx.Args().First() == "service" {
+			name = ctx.Args().Get(1)
+		}
+		err = setupAuthForService(name)
 	} else {
 		err = setupAuthForCLI(ctx)
 	}
 	if err != nil {
 		logger.Fatalf("Error setting up auth: %v", err)
 	}
-	go refreshAuthToken()
+	if !cache.Offline {
+		go inauth.DefaultRefresher.Run()
+	}
 
 	// initialize the server with the namespace so it knows which domain to register in
 	server.DefaultServer.Init(server.Namespace(ctx.String("namespace")))
 
+	// expose the handlers to plain grpc clients
+	if ctx.Bool("service_wire_compatible") {
+		server.DefaultServer.Init(grpcServer.WireCompatible())
+	}
+
+	// let tools discover the endpoints of the service
+	if ctx.Bool("service_reflection") {
+		server.DefaultServer.Init(grpcServer.Reflection())
+	}
+
+	// configure the analytics tap
+	for _, s := range ctx.StringSlice("analytics_sample") {
+		opt, err := analytics.ParseSample(s)
+		if err != nil {
+			logger.Fatalf("Error configuring analytics: %v", err)
+		}
+		analytics.DefaultTap.Init(opt)
+	}
+	for _, s := range ctx.StringSlice("analytics_scrub") {
+		re, err := regexp.Compile(s)
+		if err != nil {
+			logger.Fatalf("Error configuring analytics scrub rule %v: %v", s, err)
+		}
+		analytics.DefaultTap.Init(analytics.Scrub(&analytics.ScrubRule{Pattern: re, Replace: "{redacted}"}))
+	}
+	if s := ctx.String("analytics_salt"); len(s) > 0 {
+		analytics.DefaultTap.Init(analytics.Salt([]byte(s)))
+	}
+
+	// scrub personal data and credentials from the logs of services
+	if h, ok := logger.DefaultLogger.(*logger.Helper); ok && c.service {
+		logger.DefaultLogger = logger.NewHelper(scrub.NewLogger(h.Logger))
+	}
+
+	trace.PropagateB3 = ctx.Bool("tracing_b3")
+
+	// export the spans of the trace wrappers to an opentelemetry collector
+	if ep := ctx.String("tracing_otlp_endpoint"); len(ep) > 0 {
+		name := ctx.String("service_name")
+		if len(name) == 0 && ctx.Args().First() == "service" {
+			name = ctx.Args().Get(1)
+		}
+		opts := []otlp.Option{otlp.Endpoint(ep)}
+		if len(name) > 0 {
+			opts = append(opts, otlp.ServiceName(name))
+		}
+		headers := map[string]string{}
+		for _, h := range ctx.StringSlice("tracing_otlp_headers") {
+			parts := strings.SplitN(h, "=", 2)
+			if len(parts) != 2 {
+				logger.Fatalf("Error configuring tracing: invalid header %v", h)
+			}
+			headers[parts[0]] = parts[1]
+		}
+		opts = append(opts, otlp.Headers(headers))
+		mudebug.DefaultTracer = otlp.NewTracer(mudebug.DefaultTracer, opts...)
+	}
+
+	// configure the anomaly detector
+	if ctx.IsSet("anomaly_sensitivity") {
+		s := ctx.Float64("anomaly_sensitivity")
+		if s <= 0 {
+			logger.Fatalf("Error configuring anomaly detection: sensitivity must be positive")
+		}
+		anomaly.DefaultDetector.Init(anomaly.Sensitivity(s))
+	}
+
 	// setup registry
 	registryOpts := []registry.Option{}
 
@@ -468,6 +806,13 @@ func (c *command) Before(ctx *cli.Context) error {
 	if err := registry.DefaultRegistry.Init(registryOpts...); err != nil {
 		logger.Fatalf("Error configuring registry: %v", err)
 	}
+	if cliProxy {
+		env, err := util.GetEnv(ctx)
+		if err != nil {
+			return err
+		}
+		registry.DefaultRegistry = cache.Registry(registry.DefaultRegistry, env.Name)
+	}
 
 	// Setup broker options.
 	brokerOpts := []broker.Option{}
@@ -508,6 +853,19 @@ func (c *command) Before(ctx *cli.Context) error {
 		logger.Fatalf("Error configuring runtime: %v", err)
 	}
 
+	// Setup the region data is written to
+	residency.Region = ctx.String("region")
+
+	// Setup the region routes are failed over from
+	failover.Region = ctx.String("region")
+	for _, f := range ctx.StringSlice("failover") {
+		srv, p, err := failover.ParsePolicy(f)
+		if err != nil {
+			logger.Fatalf("Error configuring failover: %v", err)
+		}
+		failover.SetPolicy(srv, p)
+	}
+
 	// Setup store options
 	storeOpts := []store.StoreOption{}
 	if len(ctx.String("store_address")) > 0 {
@@ -522,6 +880,10 @@ func (c *command) Before(ctx *cli.Context) error {
 	if err := store.DefaultStore.Init(storeOpts...); err != nil {
 		logger.Fatalf("Error configuring store: %v", err)
 	}
+	netpolicy.DefaultPolicies = netpolicy.New(store.DefaultStore)
+
+	// the instances of a service elect the one which publishes its anomaly alerts
+	anomaly.DefaultDetector.Init(anomaly.Sync(storesync.NewSync(store.DefaultStore, usync.Prefix("anomaly/"))))
 
 	// set the registry and broker in the client and server
 	client.DefaultClient.Init(
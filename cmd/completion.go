@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/urfave/cli/v2"
+)
+
+// the completion scripts call "micro __complete" with the words of the command line after micro,
+// the last being the word completed, which prints the candidates one per line
+var completionScripts = map[string]string{
+	"bash": `# micro bash completion, add to ~/.bashrc:
+#   source <(micro completion bash)
+_micro_complete() {
+    local IFS=$'\n'
+    COMPREPLY=($(micro __complete "${COMP_WORDS[@]:1:$COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _micro_complete micro
+`,
+	"zsh": `#compdef micro
+# micro zsh completion, add to ~/.zshrc:
+#   source <(micro completion zsh)
+_micro() {
+    local -a completions
+    completions=("${(@f)$(micro __complete "${(@)words[2,$CURRENT]}" 2>/dev/null)}")
+    compadd -- $completions
+}
+compdef _micro micro
+`,
+	"fish": `# micro fish completion, add to ~/.config/fish/config.fish:
+#   micro completion fish | source
+function __micro_complete
+    set -l args (commandline -opc) (commandline -ct)
+    micro __complete $args[2..-1] 2>/dev/null
+end
+complete -c micro -f -a '(__micro_complete)'
+`,
+	"powershell": `# micro powershell completion, add to $PROFILE:
+#   micro completion powershell | Out-String | Invoke-Expression
+Register-ArgumentCompleter -Native -CommandName micro -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    $words = @($commandAst.CommandElements | Select-Object -Skip 1 | ForEach-Object { $_.ToString() })
+    if ($wordToComplete -eq '') { $words += '""' }
+    micro __complete @words 2>$null | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`,
+}
+
+func init() {
+	Register(
+		&cli.Command{
+			Name:      "completion",
+			Usage:     "Print the shell completion script, e.g. source <(micro completion bash)",
+			ArgsUsage: "bash|zsh|fish|powershell",
+			Action: func(ctx *cli.Context) error {
+				script, ok := completionScripts[ctx.Args().First()]
+				if !ok {
+					return fmt.Errorf("expected one of bash, zsh, fish or powershell")
+				}
+				fmt.Print(script)
+				return nil
+			},
+		},
+		&cli.Command{
+			Name:  "man",
+			Usage: "Print the man page of micro, e.g. micro man > /usr/local/share/man/man1/micro.1",
+			Action: func(ctx *cli.Context) error {
+				man, err := ctx.App.ToMan()
+				if err != nil {
+					return err
+				}
+				fmt.Print(man)
+				return nil
+			},
+		},
+		&cli.Command{
+			Name:            "__complete",
+			Hidden:          true,
+			SkipFlagParsing: true,
+			Action: func(ctx *cli.Context) error {
+				for _, c := range complete(ctx, ctx.Args().Slice()) {
+					fmt.Println(c)
+				}
+				return nil
+			},
+		},
+	)
+}
+
+// complete returns the candidates for the last of the words. The words are matched against the
+// commands registered, and services in the registry for words which aren't a command so the
+// endpoints of services and their request fields are completed too.
+func complete(ctx *cli.Context, words []string) []string {
+	cur := ""
+	if len(words) > 0 {
+		cur = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	// the positional words, flags and their values aren't matched against commands
+	var args []string
+	for _, w := range words {
+		if !strings.HasPrefix(w, "-") {
+			args = append(args, w)
+		}
+	}
+
+	cmds := ctx.App.Commands
+	flags := ctx.App.Flags
+	var matched int
+	for _, a := range args {
+		c := findCommand(cmds, a)
+		if c == nil {
+			break
+		}
+		cmds = c.Subcommands
+		flags = c.Flags
+		matched++
+	}
+
+	var candidates []string
+	switch {
+	case matched == 0 && len(args) > 0:
+		// the first word isn't a command so it's a service, the words after the first flag
+		// are the request fields and their values
+		var cmd []string
+		for _, w := range words[indexOf(words, args[0])+1:] {
+			if strings.HasPrefix(w, "-") {
+				break
+			}
+			cmd = append(cmd, w)
+		}
+		candidates = completeService(ctx, args[0], cmd, cur)
+	case matched < len(args):
+		// the command has no subcommand with the name
+		return nil
+	case strings.HasPrefix(cur, "-"):
+		for _, f := range flags {
+			for _, n := range f.Names() {
+				if len(n) == 1 {
+					candidates = append(candidates, "-"+n)
+				} else {
+					candidates = append(candidates, "--"+n)
+				}
+			}
+		}
+	default:
+		for _, c := range cmds {
+			if !c.Hidden {
+				candidates = append(candidates, c.Name)
+			}
+		}
+		if matched == 0 {
+			candidates = append(candidates, listServices(ctx)...)
+		}
+	}
+
+	return filterPrefix(candidates, cur)
+}
+
+// completeService returns the commands of the service's endpoints, or the request fields of the
+// endpoint if a flag is being completed
+func completeService(ctx *cli.Context, name string, args []string, cur string) []string {
+	domain := registry.DefaultDomain
+	if !util.IsBuiltInService(name) {
+		domain = currentNamespace(ctx)
+	}
+	srv, err := serviceWithName(name, domain)
+	if err != nil || srv == nil {
+		return nil
+	}
+	commands, endpoints := endpointCommands(srv, name)
+
+	if strings.HasPrefix(cur, "-") {
+		// the endpoint is called when no command is given
+		cmd := strings.Join(args, " ")
+		if len(cmd) == 0 {
+			cmd = "call"
+		}
+		var candidates []string
+		for i, c := range commands {
+			if c != cmd || endpoints[i].Request == nil {
+				continue
+			}
+			for _, v := range endpoints[i].Request.Values {
+				candidates = append(candidates, requestFlags(nil, v)...)
+			}
+		}
+		return candidates
+	}
+
+	// commands are one or two words, e.g. "call" or "foo bar"
+	if len(args) > 1 {
+		return nil
+	}
+	seen := map[string]bool{}
+	var candidates []string
+	for _, c := range commands {
+		parts := strings.SplitN(c, " ", 2)
+		var word string
+		switch {
+		case len(args) == 0:
+			word = parts[0]
+		case len(parts) == 2 && parts[0] == args[0]:
+			word = parts[1]
+		default:
+			continue
+		}
+		if !seen[word] {
+			seen[word] = true
+			candidates = append(candidates, word)
+		}
+	}
+	return candidates
+}
+
+// requestFlags returns the flags the fields of the value are set with, nested fields are
+// joined with an underscore the same as they're rendered in the usage
+func requestFlags(path []string, value *registry.Value) []string {
+	if len(value.Values) == 0 {
+		return []string{"--" + strings.Join(append(path, value.Name), "_")}
+	}
+	var flags []string
+	for _, v := range value.Values {
+		flags = append(flags, requestFlags(append(path, value.Name), v)...)
+	}
+	return flags
+}
+
+func listServices(ctx *cli.Context) []string {
+	srvs, err := registry.DefaultRegistry.ListServices(registry.ListDomain(currentNamespace(ctx)))
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(srvs))
+	for _, s := range srvs {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+func currentNamespace(ctx *cli.Context) string {
+	env, err := util.GetEnv(ctx)
+	if err != nil {
+		return registry.DefaultDomain
+	}
+	ns, err := namespace.Get(env.Name)
+	if err != nil {
+		return registry.DefaultDomain
+	}
+	return ns
+}
+
+func findCommand(cmds []*cli.Command, name string) *cli.Command {
+	for _, c := range cmds {
+		if c.Name == name {
+			return c
+		}
+		for _, a := range c.Aliases {
+			if a == name {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+func indexOf(words []string, word string) int {
+	for i, w := range words {
+		if w == word {
+			return i
+		}
+	}
+	return -1
+}
+
+// filterPrefix returns the sorted, unique candidates with the prefix
+func filterPrefix(candidates []string, prefix string) []string {
+	seen := map[string]bool{}
+	var res []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) && !seen[c] {
+			seen[c] = true
+			res = append(res, c)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
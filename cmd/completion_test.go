@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+
+	goregistry "github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/registry/memory"
+	"github.com/urfave/cli/v2"
+)
+
+func TestComplete(t *testing.T) {
+	reg := goregistry.DefaultRegistry
+	defer func() { goregistry.DefaultRegistry = reg }()
+	goregistry.DefaultRegistry = memory.NewRegistry()
+
+	goregistry.DefaultRegistry.Register(&goregistry.Service{
+		Name:    "helloworld",
+		Version: "latest",
+		Nodes:   []*goregistry.Node{{Id: "helloworld-1", Address: "127.0.0.1:8080"}},
+		Endpoints: []*goregistry.Endpoint{
+			{
+				Name: "Helloworld.Call",
+				Request: &goregistry.Value{
+					Values: []*goregistry.Value{
+						{Name: "name", Type: "string"},
+						{Name: "meta", Type: "Meta", Values: []*goregistry.Value{{Name: "id", Type: "string"}}},
+					},
+				},
+			},
+			{Name: "Helloworld.Stream"},
+			{Name: "Greeter.Hello"},
+		},
+	}, goregistry.RegisterDomain(goregistry.DefaultDomain))
+
+	app := &cli.App{
+		Flags: []cli.Flag{&cli.StringFlag{Name: "env", Aliases: []string{"e"}}},
+		Commands: []*cli.Command{
+			{Name: "help"},
+			{Name: "hidden", Hidden: true},
+			{
+				Name:        "store",
+				Subcommands: []*cli.Command{{Name: "read", Flags: []cli.Flag{&cli.StringFlag{Name: "table"}}}, {Name: "write"}},
+			},
+		},
+	}
+	ctx := cli.NewContext(app, flag.NewFlagSet("micro", flag.ContinueOnError), nil)
+
+	cases := []struct {
+		words    []string
+		expected []string
+	}{
+		{[]string{""}, []string{"helloworld", "help", "store"}},
+		{[]string{"he"}, []string{"helloworld", "help"}},
+		{[]string{"-"}, []string{"--env", "-e"}},
+		{[]string{"store", ""}, []string{"read", "write"}},
+		{[]string{"store", "read", "--t"}, []string{"--table"}},
+		{[]string{"store", "missing", ""}, nil},
+		{[]string{"helloworld", ""}, []string{"call", "greeter", "stream"}},
+		{[]string{"helloworld", "greeter", ""}, []string{"hello"}},
+		{[]string{"helloworld", "--"}, []string{"--meta_id", "--name"}},
+		{[]string{"helloworld", "call", "--name", "foo", "--m"}, []string{"--meta_id"}},
+		{[]string{"unknown", ""}, nil},
+	}
+	for _, c := range cases {
+		if got := complete(ctx, c.words); !reflect.DeepEqual(got, c.expected) {
+			t.Errorf("complete %q: expected %v, got %v", c.words, c.expected, got)
+		}
+	}
+}
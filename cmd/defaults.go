@@ -13,6 +13,8 @@ import (
 	noopMet "github.com/micro/micro/v3/service/metrics/noop"
 	"github.com/micro/micro/v3/service/network"
 	mucpNet "github.com/micro/micro/v3/service/network/mucp"
+	"github.com/micro/micro/v3/service/quota"
+	quotaSrv "github.com/micro/micro/v3/service/quota/client"
 	"github.com/micro/micro/v3/service/registry"
 	registrySrv "github.com/micro/micro/v3/service/registry/client"
 	"github.com/micro/micro/v3/service/router"
@@ -38,6 +40,7 @@ func setupDefaults() {
 	broker.DefaultBroker = brokerSrv.NewBroker()
 	events.DefaultStream = eventsSrv.NewStream()
 	events.DefaultStore = eventsSrv.NewStore()
+	quota.DefaultQuota = quotaSrv.NewQuota()
 	registry.DefaultRegistry = registrySrv.NewRegistry()
 	router.DefaultRouter = routerSrv.NewRouter()
 	store.DefaultStore = storeSrv.NewStore()
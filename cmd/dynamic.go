@@ -67,26 +67,7 @@ func formatServiceUsage(srv *registry.Service, c *cli.Context) string {
 	alias := c.Args().First()
 	subcommand := c.Args().Get(1)
 
-	commands := make([]string, len(srv.Endpoints))
-	endpoints := make([]*registry.Endpoint, len(srv.Endpoints))
-	for i, e := range srv.Endpoints {
-		// map "Helloworld.Call" to "helloworld.call"
-		parts := strings.Split(e.Name, ".")
-		for i, part := range parts {
-			parts[i] = lowercaseInitial(part)
-		}
-		name := strings.Join(parts, ".")
-
-		// remove the prefix if it is the service name, e.g. rather than
-		// "micro run helloworld helloworld call", it would be
-		// "micro run helloworld call".
-		name = strings.TrimPrefix(name, alias+".")
-
-		// instead of "micro run helloworld foo.bar", the command should
-		// be "micro run helloworld foo bar".
-		commands[i] = strings.Replace(name, ".", " ", 1)
-		endpoints[i] = e
-	}
+	commands, endpoints := endpointCommands(srv, alias)
 
 	result := ""
 	if len(subcommand) > 0 && subcommand != "--help" {
@@ -113,6 +94,32 @@ func formatServiceUsage(srv *registry.Service, c *cli.Context) string {
 	return result
 }
 
+// endpointCommands returns the commands the endpoints of the service are called with, e.g. "call"
+// for "Helloworld.Call" and "foo bar" for "Foo.Bar", and the endpoint of each
+func endpointCommands(srv *registry.Service, alias string) ([]string, []*registry.Endpoint) {
+	commands := make([]string, len(srv.Endpoints))
+	endpoints := make([]*registry.Endpoint, len(srv.Endpoints))
+	for i, e := range srv.Endpoints {
+		// map "Helloworld.Call" to "helloworld.call"
+		parts := strings.Split(e.Name, ".")
+		for i, part := range parts {
+			parts[i] = lowercaseInitial(part)
+		}
+		name := strings.Join(parts, ".")
+
+		// remove the prefix if it is the service name, e.g. rather than
+		// "micro run helloworld helloworld call", it would be
+		// "micro run helloworld call".
+		name = strings.TrimPrefix(name, alias+".")
+
+		// instead of "micro run helloworld foo.bar", the command should
+		// be "micro run helloworld foo bar".
+		commands[i] = strings.Replace(name, ".", " ", 1)
+		endpoints[i] = e
+	}
+	return commands, endpoints
+}
+
 func lowercaseInitial(str string) string {
 	for i, v := range str {
 		return string(unicode.ToLower(v)) + str[i+1:]
@@ -9,6 +9,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	pb "github.com/golang/protobuf/protoc-gen-go/descriptor"
 	"github.com/micro/micro/v3/cmd/protoc-gen-micro/generator"
+	microopts "github.com/micro/micro/v3/proto/options"
 	options "google.golang.org/genproto/googleapis/api/annotations"
 )
 
@@ -19,6 +20,7 @@ const (
 	contextPkgPath = "context"
 	clientPkgPath  = "github.com/micro/micro/v3/service/client"
 	serverPkgPath  = "github.com/micro/micro/v3/service/server"
+	eventsPkgPath  = "github.com/micro/micro/v3/service/events"
 )
 
 func init() {
@@ -44,6 +46,7 @@ var (
 	contextPkg string
 	clientPkg  string
 	serverPkg  string
+	eventsPkg  string
 	pkgImports map[generator.GoPackageName]bool
 )
 
@@ -54,6 +57,7 @@ func (g *micro) Init(gen *generator.Generator) {
 	contextPkg = generator.RegisterUniquePackageName("context", nil)
 	clientPkg = generator.RegisterUniquePackageName("client", nil)
 	serverPkg = generator.RegisterUniquePackageName("server", nil)
+	eventsPkg = generator.RegisterUniquePackageName("events", nil)
 }
 
 // Given a type name defined in a .proto, return its object.
@@ -73,31 +77,50 @@ func (g *micro) P(args ...interface{}) { g.gen.P(args...) }
 
 // Generate generates code for the services in the given file.
 func (g *micro) Generate(file *generator.FileDescriptor) {
-	if len(file.FileDescriptorProto.Service) == 0 {
+	services := len(file.FileDescriptorProto.Service) > 0
+	topics := hasTopics(file)
+	if !services && !topics {
 		return
 	}
 	g.P("// Reference imports to suppress errors if they are not otherwise used.")
-	g.P("var _ ", apiPkg, ".Endpoint")
-	g.P("var _ ", contextPkg, ".Context")
-	g.P("var _ ", clientPkg, ".Option")
-	g.P("var _ ", serverPkg, ".Option")
+	if services {
+		g.P("var _ ", apiPkg, ".Endpoint")
+		g.P("var _ ", contextPkg, ".Context")
+		g.P("var _ ", clientPkg, ".Option")
+		g.P("var _ ", serverPkg, ".Option")
+	}
+	if topics {
+		g.P("var _ ", eventsPkg, ".Event")
+	}
 	g.P()
 
 	for i, service := range file.FileDescriptorProto.Service {
 		g.generateService(file, service, i)
 	}
+	for i, message := range file.FileDescriptorProto.MessageType {
+		if topic := messageTopic(message); len(topic) > 0 {
+			g.generateTopic(file, message, topic, i)
+		}
+	}
 }
 
 // GenerateImports generates the import declaration for this file.
 func (g *micro) GenerateImports(file *generator.FileDescriptor, imports map[generator.GoImportPath]generator.GoPackageName) {
-	if len(file.FileDescriptorProto.Service) == 0 {
+	services := len(file.FileDescriptorProto.Service) > 0
+	topics := hasTopics(file)
+	if !services && !topics {
 		return
 	}
 	g.P("import (")
-	g.P(apiPkg, " ", strconv.Quote(path.Join(g.gen.ImportPrefix, apiPkgPath)))
-	g.P(contextPkg, " ", strconv.Quote(path.Join(g.gen.ImportPrefix, contextPkgPath)))
-	g.P(clientPkg, " ", strconv.Quote(path.Join(g.gen.ImportPrefix, clientPkgPath)))
-	g.P(serverPkg, " ", strconv.Quote(path.Join(g.gen.ImportPrefix, serverPkgPath)))
+	if services {
+		g.P(apiPkg, " ", strconv.Quote(path.Join(g.gen.ImportPrefix, apiPkgPath)))
+		g.P(contextPkg, " ", strconv.Quote(path.Join(g.gen.ImportPrefix, contextPkgPath)))
+		g.P(clientPkg, " ", strconv.Quote(path.Join(g.gen.ImportPrefix, clientPkgPath)))
+		g.P(serverPkg, " ", strconv.Quote(path.Join(g.gen.ImportPrefix, serverPkgPath)))
+	}
+	if topics {
+		g.P(eventsPkg, " ", strconv.Quote(path.Join(g.gen.ImportPrefix, eventsPkgPath)))
+	}
 	g.P(")")
 	g.P()
 
@@ -588,3 +611,67 @@ func (g *micro) generateServerMethod(servName string, method *pb.MethodDescripto
 
 	return hname
 }
+
+// hasTopics returns true if any of the messages in the file are published to a topic
+func hasTopics(file *generator.FileDescriptor) bool {
+	for _, message := range file.FileDescriptorProto.MessageType {
+		if len(messageTopic(message)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// messageTopic returns the topic set using the micro.topic option of the message
+func messageTopic(message *pb.DescriptorProto) string {
+	if message.Options == nil || !proto.HasExtension(message.Options, microopts.E_Topic) {
+		return ""
+	}
+	t, err := proto.GetExtension(message.Options, microopts.E_Topic)
+	if err != nil {
+		return ""
+	}
+	if topic, ok := t.(*string); ok && topic != nil {
+		return *topic
+	}
+	return ""
+}
+
+// generateTopic generates the typed publish and subscribe helpers for a message published to a
+// topic, and registers the schema of the topic
+func (g *micro) generateTopic(file *generator.FileDescriptor, message *pb.DescriptorProto, topic string, index int) {
+	msgName := generator.CamelCase(message.GetName())
+	typeName := message.GetName()
+	if pkg := file.GetPackage(); pkg != "" {
+		typeName = pkg + "." + typeName
+	}
+	topicConst := msgName + "Topic"
+
+	g.P()
+	g.P("// ", topicConst, " is the topic ", msgName, " events are published to")
+	g.P("const ", topicConst, " = ", strconv.Quote(topic))
+	g.P()
+	// registering another type for the topic is a programming error, so it fails on start
+	g.P("func init() {")
+	g.P("if err := ", eventsPkg, ".RegisterSchema(&", eventsPkg, ".Schema{")
+	g.P("Topic: ", topicConst, ",")
+	g.P("Type: ", strconv.Quote(typeName), ",")
+	g.P("New: func() interface{} { return new(", msgName, ") },")
+	g.P("}); err != nil {")
+	g.P("panic(err)")
+	g.P("}")
+	g.P("}")
+	g.P()
+	g.P("// Publish", msgName, " publishes the event to the ", topic, " topic")
+	g.P("func Publish", msgName, "(msg *", msgName, ", opts ...", eventsPkg, ".PublishOption) error {")
+	g.P("return ", eventsPkg, ".Publish(", topicConst, ", msg, opts...)")
+	g.P("}")
+	g.P()
+	g.P("// Subscribe", msgName, " calls the handler with each event published to the ", topic, " topic")
+	g.P("func Subscribe", msgName, "(fn func(*", eventsPkg, ".Event, *", msgName, ") error, opts ...", eventsPkg, ".ConsumeOption) error {")
+	g.P("return ", eventsPkg, ".Subscribe(", topicConst, ", func(ev *", eventsPkg, ".Event, msg interface{}) error {")
+	g.P("return fn(ev, msg.(*", msgName, "))")
+	g.P("}, opts...)")
+	g.P("}")
+	g.P()
+}
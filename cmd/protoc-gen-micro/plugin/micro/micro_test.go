@@ -0,0 +1,57 @@
+package micro
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/golang/protobuf/protoc-gen-go/descriptor"
+	plugin "github.com/golang/protobuf/protoc-gen-go/plugin"
+	"github.com/micro/micro/v3/cmd/protoc-gen-micro/generator"
+	microopts "github.com/micro/micro/v3/proto/options"
+	"github.com/stretchr/testify/assert"
+)
+
+// generate runs the generator for the file, returning the generated code
+func generate(t *testing.T, file *pb.FileDescriptorProto) string {
+	g := generator.New()
+	g.Request = &plugin.CodeGeneratorRequest{
+		FileToGenerate: []string{file.GetName()},
+		Parameter:      proto.String("paths=source_relative"),
+		ProtoFile:      []*pb.FileDescriptorProto{file},
+	}
+	g.CommandLineParameters(g.Request.GetParameter())
+	g.WrapTypes()
+	g.SetPackageNames()
+	g.BuildTypeNameMap()
+	g.GenerateAllFiles()
+
+	if !assert.Len(t, g.Response.File, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, "payment.pb.micro.go", g.Response.File[0].GetName())
+	return g.Response.File[0].GetContent()
+}
+
+func TestGenerateTopic(t *testing.T) {
+	opts := &pb.MessageOptions{}
+	assert.NoError(t, proto.SetExtension(opts, microopts.E_Topic, proto.String("payment.created")))
+
+	code := generate(t, &pb.FileDescriptorProto{
+		Name:    proto.String("payment.proto"),
+		Package: proto.String("payment"),
+		Syntax:  proto.String("proto3"),
+		Options: &pb.FileOptions{GoPackage: proto.String("./proto;payment")},
+		MessageType: []*pb.DescriptorProto{
+			{Name: proto.String("Created"), Options: opts},
+			{Name: proto.String("Refunded")},
+		},
+	})
+
+	assert.Contains(t, code, `const CreatedTopic = "payment.created"`)
+	assert.Contains(t, code, "if err := events.RegisterSchema(&events.Schema{")
+	assert.Contains(t, code, `Type:  "payment.Created"`)
+	assert.Contains(t, code, "panic(err)")
+	assert.Contains(t, code, "func PublishCreated(msg *Created, opts ...events.PublishOption) error {")
+	assert.Contains(t, code, "func SubscribeCreated(fn func(*events.Event, *Created) error, opts ...events.ConsumeOption) error {")
+	assert.NotContains(t, code, "RefundedTopic")
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/micro/micro/v3/cmd"
+	stub "github.com/micro/micro/v3/service/stub/server"
+	"github.com/urfave/cli/v2"
+)
+
+func init() {
+	cmd.Register(&cli.Command{
+		Name:  "dev",
+		Usage: "Run the micro server locally for development",
+		Description: `Launching the micro server in dev mode ('micro dev') runs the server locally along with
+		stubs of the services which aren't being run, e.g.
+
+		micro dev --stub payments=./stubs/payments.yaml --stub users=./stubs/users.yaml
+
+		Stubs return the canned or templated responses declared in the file for each endpoint.`,
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:    "stub",
+				Usage:   "Stub a service using the file declaring its responses, e.g. payments=./stubs/payments.yaml",
+				EnvVars: []string{"MICRO_DEV_STUB"},
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			var stubs []string
+			for _, s := range ctx.StringSlice("stub") {
+				// check the stubs are valid before starting anything
+				st, err := stub.Parse(s)
+				if err != nil {
+					return err
+				}
+
+				path := s
+				if parts := strings.SplitN(s, "=", 2); len(parts) == 2 {
+					path = parts[1]
+				}
+				abs, err := filepath.Abs(path)
+				if err != nil {
+					return err
+				}
+				// the stubs are run from the directory of the server
+				stubs = append(stubs, fmt.Sprintf("%s=%s", st.Name, abs))
+			}
+
+			return run(ctx, stubs)
+		},
+	})
+}
@@ -17,17 +17,19 @@ import (
 var (
 	// list of services managed
 	services = []string{
-		"registry", // :8000
-		"broker",   // :8003
-		"network",  // :8443
-		"runtime",  // :8088
-		"config",   // :8001
-		"store",    // :8002
-		"events",   // :unset
-		"auth",     // :8010
-		"proxy",    // :8081
-		"api",      // :8080
-		"web",      // :8082
+		"registry",    // :8000
+		"broker",      // :8003
+		"network",     // :8443
+		"runtime",     // :8088
+		"config",      // :8001
+		"store",       // :8002
+		"events",      // :unset
+		"auth",        // :8010
+		"quota",       // :8005
+		"connections", // :8006
+		"proxy",       // :8081
+		"api",         // :8080
+		"web",         // :8082
 	}
 )
 
@@ -78,6 +80,11 @@ func init() {
 
 // Run runs the entire platform
 func Run(context *cli.Context) error {
+	return run(context, nil)
+}
+
+// run the platform along with any stubs of services, in the form name=path
+func run(context *cli.Context, stubs []string) error {
 	if context.Args().Len() > 0 {
 		cli.ShowSubcommandHelp(context)
 		os.Exit(1)
@@ -114,86 +121,16 @@ func Run(context *cli.Context) error {
 	// start the services
 	for _, service := range services {
 		log.Infof("Registering %s", service)
-
-		// all things run by the server are `micro service [name]`
-		cmdArgs := []string{"service"}
-
-		// TODO: remove hacks
-		profile := context.String("profile")
-
-		// web has to behave like a client
-		if service == "web" {
-			profile = "client"
-		}
-
-		env := envvars
-		env = append(env, "MICRO_PROFILE="+profile)
-
-		// set the proxy address, default to the network running locally
-		if service != "network" {
-			proxy := context.String("proxy_address")
-			if len(proxy) == 0 {
-				proxy = "127.0.0.1:8443"
-			}
-			env = append(env, "MICRO_PROXY="+proxy)
-		}
-
-		// for kubernetes we want to provide a port and instruct the service to bind to it. we don't do
-		// this locally because the services are not isolated and the ports will conflict
-		var port string
-		if runtime.DefaultRuntime.String() == "kubernetes" {
-			switch service {
-			case "api":
-				// run the api on :443, the standard port for HTTPs
-				port = "443"
-				env = append(env, "MICRO_API_ADDRESS=:443")
-				// pass :8080 for the internal service address, since this is the default port used for the
-				// static (k8s) router. Because the http api will register on :443 it won't conflict
-				env = append(env, "MICRO_SERVICE_ADDRESS=:8080")
-			case "proxy":
-				// run the proxy on :443, the standard port for HTTPs
-				port = "443"
-				env = append(env, "MICRO_PROXY_ADDRESS=:443")
-				// pass :8080 for the internal service address, since this is the default port used for the
-				// static (k8s) router. Because the grpc proxy will register on :443 it won't conflict
-				env = append(env, "MICRO_SERVICE_ADDRESS=:8080")
-			case "network":
-				port = "8443"
-				env = append(env, "MICRO_SERVICE_ADDRESS=:8443")
-			default:
-				port = "8080"
-				env = append(env, "MICRO_SERVICE_ADDRESS=:8080")
-			}
-		}
-
-		// we want to pass through the global args so go up one level in the context lineage
-		if len(context.Lineage()) > 1 {
-			globCtx := context.Lineage()[1]
-			for _, f := range globCtx.FlagNames() {
-				cmdArgs = append(cmdArgs, "--"+f, context.String(f))
-			}
-		}
-		cmdArgs = append(cmdArgs, service)
-
-		// runtime based on environment we run the service in
-		args := []runtime.CreateOption{
-			runtime.WithCommand(os.Args[0]),
-			runtime.WithArgs(cmdArgs...),
-			runtime.WithEnv(env),
-			runtime.WithPort(port),
-			runtime.WithRetries(10),
-			runtime.WithServiceAccount("micro"),
-			runtime.WithVolume("store-pvc", "/store"),
-			runtime.CreateImage(context.String("image")),
-			runtime.CreateNamespace("micro"),
-			runtime.WithSecret("MICRO_AUTH_PUBLIC_KEY", auth.DefaultAuth.Options().PublicKey),
-			runtime.WithSecret("MICRO_AUTH_PRIVATE_KEY", auth.DefaultAuth.Options().PrivateKey),
+		if err := create(context, runtimeServer, envvars, service, service); err != nil {
+			return err
 		}
+	}
 
-		// NOTE: we use Version right now to check for the latest release
-		muService := &runtime.Service{Name: service, Version: "latest"}
-		if err := runtimeServer.Create(muService, args...); err != nil {
-			log.Errorf("Failed to create runtime environment: %v", err)
+	// start the stubs of the services which aren't being run
+	for _, s := range stubs {
+		name := strings.SplitN(s, "=", 2)[0]
+		log.Infof("Registering stub of %s", name)
+		if err := create(context, runtimeServer, envvars, name, "stub", "--stub", s); err != nil {
 			return err
 		}
 	}
@@ -219,3 +156,90 @@ func Run(context *cli.Context) error {
 	log.Info("Stopped server")
 	return nil
 }
+
+// create the runtime service with the name which runs `micro service [command]`
+func create(context *cli.Context, runtimeServer runtime.Runtime, envvars []string, service string, command ...string) error {
+	// all things run by the server are `micro service [name]`
+	cmdArgs := []string{"service"}
+
+	// TODO: remove hacks
+	profile := context.String("profile")
+
+	// web has to behave like a client
+	if service == "web" {
+		profile = "client"
+	}
+
+	env := envvars
+	env = append(env, "MICRO_PROFILE="+profile)
+
+	// set the proxy address, default to the network running locally
+	if service != "network" {
+		proxy := context.String("proxy_address")
+		if len(proxy) == 0 {
+			proxy = "127.0.0.1:8443"
+		}
+		env = append(env, "MICRO_PROXY="+proxy)
+	}
+
+	// for kubernetes we want to provide a port and instruct the service to bind to it. we don't do
+	// this locally because the services are not isolated and the ports will conflict
+	var port string
+	if runtime.DefaultRuntime.String() == "kubernetes" {
+		switch service {
+		case "api":
+			// run the api on :443, the standard port for HTTPs
+			port = "443"
+			env = append(env, "MICRO_API_ADDRESS=:443")
+			// pass :8080 for the internal service address, since this is the default port used for the
+			// static (k8s) router. Because the http api will register on :443 it won't conflict
+			env = append(env, "MICRO_SERVICE_ADDRESS=:8080")
+		case "proxy":
+			// run the proxy on :443, the standard port for HTTPs
+			port = "443"
+			env = append(env, "MICRO_PROXY_ADDRESS=:443")
+			// pass :8080 for the internal service address, since this is the default port used for the
+			// static (k8s) router. Because the grpc proxy will register on :443 it won't conflict
+			env = append(env, "MICRO_SERVICE_ADDRESS=:8080")
+		case "network":
+			port = "8443"
+			env = append(env, "MICRO_SERVICE_ADDRESS=:8443")
+		default:
+			port = "8080"
+			env = append(env, "MICRO_SERVICE_ADDRESS=:8080")
+		}
+	}
+
+	// we want to pass through the global args so go up one level in the context lineage
+	if len(context.Lineage()) > 1 {
+		globCtx := context.Lineage()[1]
+		for _, f := range globCtx.FlagNames() {
+			cmdArgs = append(cmdArgs, "--"+f, context.String(f))
+		}
+	}
+	cmdArgs = append(cmdArgs, command...)
+
+	// runtime based on environment we run the service in
+	args := []runtime.CreateOption{
+		runtime.WithCommand(os.Args[0]),
+		runtime.WithArgs(cmdArgs...),
+		runtime.WithEnv(env),
+		runtime.WithPort(port),
+		runtime.WithRetries(10),
+		runtime.WithServiceAccount("micro"),
+		runtime.WithVolume("store-pvc", "/store"),
+		runtime.CreateImage(context.String("image")),
+		runtime.CreateNamespace("micro"),
+		runtime.WithSecret("MICRO_AUTH_PUBLIC_KEY", auth.DefaultAuth.Options().PublicKey),
+		runtime.WithSecret("MICRO_AUTH_PRIVATE_KEY", auth.DefaultAuth.Options().PrivateKey),
+	}
+
+	// NOTE: we use Version right now to check for the latest release
+	muService := &runtime.Service{Name: service, Version: "latest"}
+	if err := runtimeServer.Create(muService, args...); err != nil {
+		log.Errorf("Failed to create runtime environment: %v", err)
+		return err
+	}
+
+	return nil
+}
@@ -3,6 +3,8 @@ package service
 
 import (
 	"fmt"
+	"net"
+	nethttp "net/http"
 	"os"
 	"strings"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/micro/micro/v3/service"
 	"github.com/micro/micro/v3/service/logger"
 	prox "github.com/micro/micro/v3/service/proxy"
+	"github.com/micro/micro/v3/service/proxy/bridge"
 	"github.com/micro/micro/v3/service/proxy/grpc"
 	"github.com/micro/micro/v3/service/proxy/http"
 	"github.com/micro/micro/v3/service/proxy/mucp"
@@ -23,12 +26,15 @@ import (
 	auth "github.com/micro/micro/v3/service/auth/server"
 	broker "github.com/micro/micro/v3/service/broker/server"
 	config "github.com/micro/micro/v3/service/config/server"
+	connections "github.com/micro/micro/v3/service/connections/server"
 	events "github.com/micro/micro/v3/service/events/server"
 	network "github.com/micro/micro/v3/service/network/server"
 	proxy "github.com/micro/micro/v3/service/proxy/server"
+	quota "github.com/micro/micro/v3/service/quota/server"
 	registry "github.com/micro/micro/v3/service/registry/server"
 	runtime "github.com/micro/micro/v3/service/runtime/server"
 	store "github.com/micro/micro/v3/service/store/server"
+	stub "github.com/micro/micro/v3/service/stub/server"
 	"github.com/micro/micro/v3/service/web"
 
 	// misc commands
@@ -72,8 +78,20 @@ func Run(ctx *ccli.Context) {
 	}
 
 	var p prox.Proxy
+	var b *bridge.Bridge
+	bridgeAddress := ctx.String("bridge")
 
 	switch {
+	case len(bridgeAddress) > 0:
+		// the app registers its handlers with the bridge
+		addr, err := bridge.ListenAddress(bridgeAddress)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		bridgeAddress = addr
+		b = bridge.New()
+		p = b
+		endpoint = bridgeAddress
 	case strings.HasPrefix(endpoint, "grpc"):
 		endpoint = strings.TrimPrefix(endpoint, "grpc://")
 		p = grpc.NewProxy(prox.WithEndpoint(endpoint))
@@ -95,6 +113,9 @@ func Run(ctx *ccli.Context) {
 			rt.WithCommand(ctx.Args().Slice()...),
 			rt.WithOutput(os.Stdout),
 		}
+		if b != nil {
+			args = append(args, rt.WithEnv([]string{"MICRO_BRIDGE_ADDRESS=http://" + bridgeAddress}))
+		}
 
 		// create new local runtime
 		r := rt.DefaultRuntime
@@ -121,6 +142,17 @@ func Run(ctx *ccli.Context) {
 	// new service
 	srv := service.New(opts...)
 
+	// serve the bridge the app registers its handlers with and makes calls through
+	if b != nil {
+		l, err := net.Listen("tcp", bridgeAddress)
+		if err != nil {
+			logger.Fatalf("Error listening on %v: %v", bridgeAddress, err)
+		}
+		hs := &nethttp.Server{Handler: b}
+		go hs.Serve(l)
+		defer hs.Close()
+	}
+
 	// create new muxer
 	//	muxer := mux.New(name, p)
 
@@ -159,9 +191,14 @@ var srvCommands = []srvCommand{
 		Command: config.Run,
 		Flags:   config.Flags,
 	},
+	{
+		Name:    "connections",
+		Command: connections.Run,
+	},
 	{
 		Name:    "events",
 		Command: events.Run,
+		Flags:   events.Flags,
 	},
 	{
 		Name:    "network",
@@ -173,9 +210,14 @@ var srvCommands = []srvCommand{
 		Command: proxy.Run,
 		Flags:   proxy.Flags,
 	},
+	{
+		Name:    "quota",
+		Command: quota.Run,
+	},
 	{
 		Name:    "registry",
 		Command: registry.Run,
+		Flags:   registry.Flags,
 	},
 	{
 		Name:    "runtime",
@@ -185,6 +227,12 @@ var srvCommands = []srvCommand{
 	{
 		Name:    "store",
 		Command: store.Run,
+		Flags:   store.Flags,
+	},
+	{
+		Name:    "stub",
+		Command: stub.Run,
+		Flags:   stub.Flags,
 	},
 	{
 		Name:    "web",
@@ -255,6 +303,11 @@ func init() {
 				Usage:   "The local service endpoint (Defaults to localhost:9090); {http, grpc, file, exec}://path-or-address e.g http://localhost:9090",
 				EnvVars: []string{"MICRO_SERVICE_ENDPOINT"},
 			},
+			&ccli.StringFlag{
+				Name:    "bridge",
+				Usage:   "Loopback address of the bridge the app registers its handlers with and calls services through e.g localhost:9091, for apps in other languages. The address is passed to the app in MICRO_BRIDGE_ADDRESS",
+				EnvVars: []string{"MICRO_SERVICE_BRIDGE"},
+			},
 			&ccli.StringSliceFlag{
 				Name:    "metadata",
 				Usage:   "Add metadata as key-value pairs describing the service e.g owner=john@example.com",
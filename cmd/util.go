@@ -5,12 +5,14 @@ import (
 	"unicode"
 
 	"github.com/google/uuid"
+	"github.com/micro/micro/v3/client/cli/cache"
 	"github.com/micro/micro/v3/client/cli/namespace"
 	clitoken "github.com/micro/micro/v3/client/cli/token"
 	"github.com/micro/micro/v3/client/cli/util"
 	"github.com/micro/micro/v3/service/auth"
 	"github.com/micro/micro/v3/service/errors"
 	"github.com/micro/micro/v3/service/logger"
+	inauth "github.com/micro/micro/v3/util/auth"
 	"github.com/urfave/cli/v2"
 )
 
@@ -50,10 +52,18 @@ func setupAuthForCLI(ctx *cli.Context) error {
 		return err
 	}
 
-	// If there is no refresh token, do not try to refresh it
+	// If there is no refresh token, do not try to refresh it. When offline the
+	// token can't be refreshed either, the cached token is used as is.
 	if len(tok.RefreshToken) == 0 {
 		return nil
 	}
+	if cache.Offline {
+		auth.DefaultAuth.Init(
+			auth.ClientToken(tok),
+			auth.Issuer(ns),
+		)
+		return nil
+	}
 
 	// Check if token is valid
 	if time.Now().Before(tok.Expiry.Add(time.Minute * -1)) {
@@ -82,8 +92,9 @@ func setupAuthForCLI(ctx *cli.Context) error {
 	return clitoken.Save(ctx, tok)
 }
 
-// setupAuthForService generates auth credentials for the service
-func setupAuthForService() error {
+// setupAuthForService generates auth credentials for the service, the name of the service is bound
+// to the account if it's known
+func setupAuthForService(name string) error {
 	opts := auth.DefaultAuth.Options()
 
 	// extract the account creds from options, these can be set by flags
@@ -96,6 +107,9 @@ func setupAuthForService() error {
 			auth.WithType("service"),
 			auth.WithScopes("service"),
 		}
+		if len(name) > 0 {
+			opts = append(opts, auth.WithMetadata(map[string]string{inauth.ServiceMetadataKey: name}))
+		}
 
 		acc, err := auth.Generate(uuid.New().String(), opts...)
 		if err != nil {
@@ -125,49 +139,3 @@ func setupAuthForService() error {
 	)
 	return nil
 }
-
-// refreshAuthToken if it is close to expiring
-func refreshAuthToken() {
-	// can't refresh a token we don't have
-	if auth.DefaultAuth.Options().Token == nil {
-		return
-	}
-
-	t := time.NewTicker(time.Second * 15)
-	defer t.Stop()
-
-	for {
-		select {
-		case <-t.C:
-			// don't refresh the token if it's not close to expiring
-			tok := auth.DefaultAuth.Options().Token
-			if tok.Expiry.Unix() > time.Now().Add(time.Minute).Unix() {
-				continue
-			}
-
-			// generate the first token
-			tok, err := auth.Token(
-				auth.WithToken(tok.RefreshToken),
-				auth.WithExpiry(time.Minute*10),
-			)
-			if err == auth.ErrInvalidToken {
-				logger.Warnf("[Auth] Refresh token expired, regenerating using account credentials")
-
-				tok, err = auth.Token(
-					auth.WithCredentials(
-						auth.DefaultAuth.Options().ID,
-						auth.DefaultAuth.Options().Secret,
-					),
-					auth.WithExpiry(time.Minute*10),
-				)
-			} else if err != nil {
-				logger.Warnf("[Auth] Error refreshing token: %v", err)
-				continue
-			}
-
-			// set the token
-			logger.Debugf("Auth token refreshed, expires at %v", tok.Expiry.Format(time.UnixDate))
-			auth.DefaultAuth.Init(auth.ClientToken(tok))
-		}
-	}
-}
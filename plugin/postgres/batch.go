@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/pkg/errors"
+)
+
+// BatchWrite writes the records with a single multi-row insert, so either all of the records
+// are written or none are. If a key is repeated the last record with it is written.
+func (s *sqlStore) BatchWrite(recs []*store.Record, opts ...store.WriteOption) error {
+	var options store.WriteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	// create the db if not exists
+	if err := s.createDB(options.Database, options.Table); err != nil {
+		return err
+	}
+
+	// a row can only be affected once by an insert
+	idx := map[string]int{}
+	var unique []*store.Record
+	for _, r := range recs {
+		if i, ok := idx[r.Key]; ok {
+			unique[i] = r
+			continue
+		}
+		idx[r.Key] = len(unique)
+		unique = append(unique, r)
+	}
+
+	values := make([]string, 0, len(unique))
+	args := make([]interface{}, 0, len(unique)*4)
+	for i, r := range unique {
+		n := i * 4
+		values = append(values, fmt.Sprintf("($%d, $%d::bytea, $%d, $%d)", n+1, n+2, n+3, n+4))
+
+		metadata := make(Metadata)
+		for k, v := range r.Metadata {
+			metadata[k] = v
+		}
+
+		var expiry interface{}
+		if r.Expiry != 0 {
+			expiry = time.Now().Add(r.Expiry)
+		}
+		args = append(args, r.Key, r.Value, metadata, expiry)
+	}
+
+	database, table := s.getDB(options.Database, options.Table)
+	q := fmt.Sprintf("INSERT INTO %s.%s(key, value, metadata, expiry) VALUES %s ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, metadata = EXCLUDED.metadata, expiry = EXCLUDED.expiry, revision = DEFAULT;",
+		database, table, strings.Join(values, ", "))
+
+	db, err := s.db()
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec(q, args...); err != nil {
+		return errors.Wrap(err, "Couldn't insert records")
+	}
+	return nil
+}
+
+// ReadMany reads the records with a single query
+func (s *sqlStore) ReadMany(keys []string, opts ...store.ReadOption) ([]*store.Record, error) {
+	options := store.ReadOptions{
+		Order: store.OrderAsc,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	// create the db if not exists
+	if err := s.createDB(options.Database, options.Table); err != nil {
+		return nil, err
+	}
+
+	db, replica, err := s.reader(options)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.readKeys(db, keys, options)
+	if replica && isBadConnError(errors.Cause(err)) {
+		// the replica is unavailable so fall back to the primary
+		s.replicaFailed(db)
+		if db, err = s.db(); err != nil {
+			return nil, err
+		}
+		records, err = s.readKeys(db, keys, options)
+	}
+	return records, err
+}
+
+func (s *sqlStore) readKeys(db *sql.DB, keys []string, options store.ReadOptions) ([]*store.Record, error) {
+	st, err := s.prepareOn(db, options.Database, options.Table, "readKeys", options.Order)
+	if err != nil {
+		return nil, err
+	}
+	defer st.Close()
+
+	rows, err := st.Query(pq.Array(keys))
+	if err != nil {
+		return nil, errors.Wrap(err, "sqlStore.readKeys failed")
+	}
+	defer rows.Close()
+
+	records, err := s.rowsToRecords(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return records, err
+	}
+	return records, nil
+}
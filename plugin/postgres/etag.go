@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/micro/micro/v3/service/store"
+	"github.com/pkg/errors"
+)
+
+// writeIfMatch writes the record if the stored record has the etag. The stored record is locked
+// while it's checked and written. Records which shouldn't exist are created with an insert which
+// only replaces expired rows, so concurrent creates can't both succeed.
+func (s *sqlStore) writeIfMatch(r *store.Record, options store.WriteOptions) error {
+	db, err := s.db()
+	if err != nil {
+		return err
+	}
+	database, table := s.getDB(options.Database, options.Table)
+
+	metadata := make(Metadata)
+	for k, v := range r.Metadata {
+		metadata[k] = v
+	}
+	var expiry interface{}
+	if r.Expiry != 0 {
+		expiry = time.Now().Add(r.Expiry)
+	}
+
+	if len(options.Etag) == 0 {
+		res, err := db.Exec(fmt.Sprintf(statements["create"], database, table), r.Key, r.Value, metadata, expiry)
+		if err != nil {
+			return errors.Wrap(err, "Couldn't insert record "+r.Key)
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return store.ErrConflict
+		}
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := s.rowToRecord(tx.QueryRow(fmt.Sprintf(statements["readForUpdate"], database, table), r.Key))
+	if err == store.ErrNotFound {
+		current = nil
+	} else if err != nil {
+		return err
+	}
+	if err := store.CheckEtag(current, options.Etag); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf(statements["write"], database, table), r.Key, r.Value, metadata, expiry); err != nil {
+		return errors.Wrap(err, "Couldn't insert record "+r.Key)
+	}
+	return tx.Commit()
+}
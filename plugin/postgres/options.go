@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/micro/micro/v3/service/store"
+)
+
+type replicaReadsKey struct{}
+
+// ReplicaReads routes reads to the replicas amongst the nodes of the store, skipping replicas
+// which lag the primary by more than maxStaleness. Reads fall back to the primary when no
+// replica is fresh enough. Use store.ReadPrimary for reads which must see preceding writes.
+func ReplicaReads(maxStaleness time.Duration) store.Option {
+	return func(o *store.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, replicaReadsKey{}, maxStaleness)
+	}
+}
+
+// replicaReads returns the max staleness of replica reads, or false if they're disabled
+func replicaReads(o store.Options) (time.Duration, bool) {
+	if o.Context == nil {
+		return 0, false
+	}
+	d, ok := o.Context.Value(replicaReadsKey{}).(time.Duration)
+	return d, ok
+}
@@ -52,14 +52,19 @@ var (
 
 	// the sql statements we prepare and use
 	statements = map[string]string{
-		"list":          "SELECT key, value, metadata, expiry FROM %s.%s WHERE key LIKE $1 ORDER BY key ASC LIMIT $2 OFFSET $3;",
-		"read":          "SELECT key, value, metadata, expiry FROM %s.%s WHERE key = $1;",
-		"readMany":      "SELECT key, value, metadata, expiry FROM %s.%s WHERE key LIKE $1 ORDER BY key ASC;",
-		"readOffset":    "SELECT key, value, metadata, expiry FROM %s.%s WHERE key LIKE $1 ORDER BY key ASC LIMIT $2 OFFSET $3;",
-		"write":         "INSERT INTO %s.%s(key, value, metadata, expiry) VALUES ($1, $2::bytea, $3, $4) ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, metadata = EXCLUDED.metadata, expiry = EXCLUDED.expiry;",
+		"list":          "SELECT key, value, metadata, expiry, revision FROM %s.%s WHERE key LIKE $1 ORDER BY key ASC LIMIT $2 OFFSET $3;",
+		"read":          "SELECT key, value, metadata, expiry, revision FROM %s.%s WHERE key = $1;",
+		"readMany":      "SELECT key, value, metadata, expiry, revision FROM %s.%s WHERE key LIKE $1 ORDER BY key ASC;",
+		"readKeys":      "SELECT key, value, metadata, expiry, revision FROM %s.%s WHERE key = ANY($1) ORDER BY key ASC;",
+		"readOffset":    "SELECT key, value, metadata, expiry, revision FROM %s.%s WHERE key LIKE $1 ORDER BY key ASC LIMIT $2 OFFSET $3;",
+		"write":         "INSERT INTO %s.%s(key, value, metadata, expiry) VALUES ($1, $2::bytea, $3, $4) ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, metadata = EXCLUDED.metadata, expiry = EXCLUDED.expiry, revision = DEFAULT;",
+		"readForUpdate": "SELECT key, value, metadata, expiry, revision FROM %s.%s WHERE key = $1 FOR UPDATE;",
+		"create":        "INSERT INTO %s.%s(key, value, metadata, expiry) VALUES ($1, $2::bytea, $3, $4) ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, metadata = EXCLUDED.metadata, expiry = EXCLUDED.expiry, revision = DEFAULT WHERE %[1]s.%[2]s.expiry < now();",
 		"delete":        "DELETE FROM %s.%s WHERE key = $1;",
 		"deleteExpired": "DELETE FROM %s.%s WHERE expiry < now();",
 		"showTables":    "SELECT schemaname, tablename FROM pg_catalog.pg_tables WHERE schemaname != 'pg_catalog' AND schemaname != 'information_schema';",
+		"isReplica":     "SELECT pg_is_in_recovery();",
+		"replicaLag":    "SELECT pg_is_in_recovery(), COALESCE(CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0 ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()) END, 0);",
 	}
 )
 
@@ -70,6 +75,11 @@ type sqlStore struct {
 	sync.RWMutex
 	// known databases
 	databases map[string]bool
+
+	// replicas reads are routed to
+	replicaMtx sync.Mutex
+	replicas   []*replica
+	next       int
 }
 
 func (s *sqlStore) getDB(database, table string) (string, string) {
@@ -191,12 +201,19 @@ func (s *sqlStore) initDB(database, table string) error {
 		value bytea,
 		metadata JSONB,
 		expiry timestamp with time zone,
+		revision bigserial,
 		CONSTRAINT %s_pkey PRIMARY KEY (key)
 	);`, database, table, table))
 	if err != nil {
 		return errors.Wrap(err, "Couldn't create table")
 	}
 
+	// Add the revision to tables created before it, it's bumped on every write and used as the etag
+	_, err = db.Exec(fmt.Sprintf(`ALTER TABLE %s.%s ADD COLUMN IF NOT EXISTS revision bigserial;`, database, table))
+	if err != nil {
+		return errors.Wrap(err, "Couldn't add revision column")
+	}
+
 	// Create Index
 	_, err = db.Exec(fmt.Sprintf(`CREATE INDEX IF NOT EXISTS "%s" ON %s.%s USING btree ("key");`, "key_index_"+table, database, table))
 	if err != nil {
@@ -212,12 +229,8 @@ func (s *sqlStore) initDB(database, table string) error {
 	return nil
 }
 
-func (s *sqlStore) configure() error {
-	if len(s.options.Nodes) == 0 {
-		s.options.Nodes = []string{"postgresql://root@localhost:26257?sslmode=disable"}
-	}
-
-	source := s.options.Nodes[0]
+// connect to a node of the database
+func connect(source string) (*sql.DB, error) {
 	// check if it is a standard connection string eg: host=%s port=%d user=%s password=%s dbname=%s sslmode=disable
 	// if err is nil which means it would be a URL like postgre://xxxx?yy=zz
 	_, err := url.Parse(source)
@@ -227,14 +240,59 @@ func (s *sqlStore) configure() error {
 		}
 	}
 
-	// create source from first node
 	db, err := sql.Open("postgres", source)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := db.Ping(); err != nil {
-		return err
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// configure connects to the nodes of the database. The first node which isn't a replica is used
+// as the primary, so when the primary is lost and a replica is promoted the store fails over
+// to it the next time it's configured.
+func (s *sqlStore) configure() error {
+	if len(s.options.Nodes) == 0 {
+		s.options.Nodes = []string{"postgresql://root@localhost:26257?sslmode=disable"}
+	}
+
+	var db *sql.DB
+	var replicas []*replica
+	var connErr error
+
+	for _, node := range s.options.Nodes {
+		conn, err := connect(node)
+		if err != nil {
+			if connErr == nil {
+				connErr = err
+			}
+			continue
+		}
+		if db == nil && !isReplica(conn) {
+			db = conn
+			continue
+		}
+		replicas = append(replicas, &replica{db: conn})
+	}
+
+	if db == nil {
+		if len(replicas) == 0 {
+			return connErr
+		}
+		// none of the nodes are primaries, e.g. the database doesn't support replication
+		db, replicas = replicas[0].db, replicas[1:]
+	}
+
+	// connections to the replicas are only kept when reads are routed to them
+	if _, ok := replicaReads(s.options); !ok {
+		for _, r := range replicas {
+			r.db.Close()
+		}
+		replicas = nil
 	}
 
 	if s.dbConn != nil {
@@ -243,6 +301,7 @@ func (s *sqlStore) configure() error {
 
 	// save the values
 	s.dbConn = db
+	s.setReplicas(replicas)
 
 	// get DB
 	database, table := s.getDB(s.options.Database, s.options.Table)
@@ -252,6 +311,15 @@ func (s *sqlStore) configure() error {
 }
 
 func (s *sqlStore) prepare(database, table, query string, order store.Order) (*sql.Stmt, error) {
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+	return s.prepareOn(db, database, table, query, order)
+}
+
+// prepareOn prepares the statement on the connection, which may be the primary or a replica
+func (s *sqlStore) prepareOn(db *sql.DB, database, table, query string, order store.Order) (*sql.Stmt, error) {
 	st, ok := statements[query]
 	if !ok {
 		return nil, errors.New("unsupported statement")
@@ -268,10 +336,6 @@ func (s *sqlStore) prepare(database, table, query string, order store.Order) (*s
 
 	q := fmt.Sprintf(st, database, table)
 
-	db, err := s.db()
-	if err != nil {
-		return nil, err
-	}
 	stmt, err := db.Prepare(q)
 	if err != nil {
 		return nil, err
@@ -280,6 +344,7 @@ func (s *sqlStore) prepare(database, table, query string, order store.Order) (*s
 }
 
 func (s *sqlStore) Close() error {
+	s.setReplicas(nil)
 	if s.dbConn != nil {
 		return s.dbConn.Close()
 	}
@@ -363,10 +428,11 @@ func (s *sqlStore) List(opts ...store.ListOption) ([]string, error) {
 // rowToRecord converts from sql.Row to a store.Record. If the record has expired it will issue a delete in a separate goroutine
 func (s *sqlStore) rowToRecord(row *sql.Row) (*store.Record, error) {
 	var timehelper pq.NullTime
+	var revision uint64
 	record := &store.Record{}
 	metadata := make(Metadata)
 
-	if err := row.Scan(&record.Key, &record.Value, &metadata, &timehelper); err != nil {
+	if err := row.Scan(&record.Key, &record.Value, &metadata, &timehelper, &revision); err != nil {
 		if err == sql.ErrNoRows {
 			return record, store.ErrNotFound
 		}
@@ -375,6 +441,7 @@ func (s *sqlStore) rowToRecord(row *sql.Row) (*store.Record, error) {
 
 	// set the metadata
 	record.Metadata = toMetadata(&metadata)
+	record.Etag = store.Etag(revision)
 	if timehelper.Valid {
 		if timehelper.Time.Before(time.Now()) {
 			// record has expired
@@ -391,17 +458,19 @@ func (s *sqlStore) rowToRecord(row *sql.Row) (*store.Record, error) {
 func (s *sqlStore) rowsToRecords(rows *sql.Rows) ([]*store.Record, error) {
 	var records []*store.Record
 	var timehelper pq.NullTime
+	var revision uint64
 
 	for rows.Next() {
 		record := &store.Record{}
 		metadata := make(Metadata)
 
-		if err := rows.Scan(&record.Key, &record.Value, &metadata, &timehelper); err != nil {
+		if err := rows.Scan(&record.Key, &record.Value, &metadata, &timehelper, &revision); err != nil {
 			return records, err
 		}
 
 		// set the metadata
 		record.Metadata = toMetadata(&metadata)
+		record.Etag = store.Etag(revision)
 
 		if timehelper.Valid {
 			if timehelper.Time.Before(time.Now()) {
@@ -432,11 +501,30 @@ func (s *sqlStore) Read(key string, opts ...store.ReadOption) ([]*store.Record,
 		return nil, err
 	}
 
+	db, replica, err := s.reader(options)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.readFrom(db, key, options)
+	if replica && isBadConnError(errors.Cause(err)) {
+		// the replica is unavailable so fall back to the primary
+		s.replicaFailed(db)
+		if db, err = s.db(); err != nil {
+			return nil, err
+		}
+		records, err = s.readFrom(db, key, options)
+	}
+	return records, err
+}
+
+// readFrom reads the records from the connection
+func (s *sqlStore) readFrom(db *sql.DB, key string, options store.ReadOptions) ([]*store.Record, error) {
 	if options.Prefix || options.Suffix {
-		return s.read(key, options)
+		return s.read(db, key, options)
 	}
 
-	st, err := s.prepare(options.Database, options.Table, "read", options.Order)
+	st, err := s.prepareOn(db, options.Database, options.Table, "read", options.Order)
 	if err != nil {
 		return nil, err
 	}
@@ -452,7 +540,7 @@ func (s *sqlStore) Read(key string, opts ...store.ReadOption) ([]*store.Record,
 }
 
 // Read Many records
-func (s *sqlStore) read(key string, options store.ReadOptions) ([]*store.Record, error) {
+func (s *sqlStore) read(db *sql.DB, key string, options store.ReadOptions) ([]*store.Record, error) {
 	pattern := "%"
 	if options.Prefix {
 		pattern = key + pattern
@@ -466,7 +554,7 @@ func (s *sqlStore) read(key string, options store.ReadOptions) ([]*store.Record,
 	var err error
 
 	if options.Limit != 0 {
-		st, err = s.prepare(options.Database, options.Table, "readOffset", options.Order)
+		st, err = s.prepareOn(db, options.Database, options.Table, "readOffset", options.Order)
 		if err != nil {
 			return nil, err
 		}
@@ -474,7 +562,7 @@ func (s *sqlStore) read(key string, options store.ReadOptions) ([]*store.Record,
 
 		rows, err = st.Query(pattern, options.Limit, options.Offset)
 	} else {
-		st, err = s.prepare(options.Database, options.Table, "readMany", options.Order)
+		st, err = s.prepareOn(db, options.Database, options.Table, "readMany", options.Order)
 		if err != nil {
 			return nil, err
 		}
@@ -519,6 +607,10 @@ func (s *sqlStore) Write(r *store.Record, opts ...store.WriteOption) error {
 		return err
 	}
 
+	if options.IfMatch {
+		return s.writeIfMatch(r, options)
+	}
+
 	st, err := s.prepare(options.Database, options.Table, "write", store.OrderAsc)
 	if err != nil {
 		return err
@@ -611,6 +703,7 @@ func NewStore(opts ...store.Option) store.Store {
 		}
 	}
 	go s.expiryLoop()
+	go s.replicaLoop()
 	// return store
 	return s
 }
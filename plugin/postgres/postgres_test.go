@@ -1,8 +1,10 @@
 package postgres
 
 import (
+	"database/sql"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/micro/micro/v3/service/store"
 	"github.com/stretchr/testify/assert"
@@ -143,3 +145,27 @@ func TestPostgres(t *testing.T) {
 		assert.Equal(t, "foo/baz", recs2[0])
 	})
 }
+
+func TestReplicaReads(t *testing.T) {
+	fresh := &replica{db: &sql.DB{}, healthy: true}
+	stale := &replica{db: &sql.DB{}, healthy: true, lag: time.Minute}
+	down := &replica{db: &sql.DB{}}
+	s := &sqlStore{replicas: []*replica{stale, fresh, down}}
+	ReplicaReads(time.Second)(&s.options)
+
+	// reads go to the replica within the staleness bound
+	for i := 0; i < 3; i++ {
+		db, replica, err := s.reader(store.ReadOptions{})
+		assert.NoError(t, err)
+		assert.True(t, replica)
+		assert.True(t, db == fresh.db)
+	}
+
+	// unless the replica fails or the read is from the primary, which isn't connected
+	_, _, err := s.reader(store.ReadOptions{Primary: true})
+	assert.Equal(t, ErrNoConnection, err)
+
+	s.replicaFailed(fresh.db)
+	_, _, err = s.reader(store.ReadOptions{})
+	assert.Equal(t, ErrNoConnection, err)
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
+)
+
+// replicaCheckInterval is how often the lag of the replicas is checked
+var replicaCheckInterval = time.Second * 5
+
+// replica is a read only node of the database
+type replica struct {
+	db      *sql.DB
+	healthy bool
+	// lag behind the primary
+	lag time.Duration
+}
+
+// isReplica returns true if the node is a replica. Databases which don't support the query,
+// e.g. cockroach, are never replicas.
+func isReplica(db *sql.DB) bool {
+	var recovery bool
+	if err := db.QueryRow(statements["isReplica"]).Scan(&recovery); err != nil {
+		return false
+	}
+	return recovery
+}
+
+// setReplicas replaces the replicas, closing the connections to the previous ones
+func (s *sqlStore) setReplicas(replicas []*replica) {
+	s.replicaMtx.Lock()
+	old := s.replicas
+	s.replicas = replicas
+	s.next = 0
+	s.replicaMtx.Unlock()
+
+	for _, r := range old {
+		r.db.Close()
+	}
+	s.checkReplicas()
+}
+
+// checkReplicas updates the health and lag of the replicas. Replicas which have been promoted
+// are no longer healthy since they may diverge from the primary.
+func (s *sqlStore) checkReplicas() {
+	s.replicaMtx.Lock()
+	replicas := s.replicas
+	s.replicaMtx.Unlock()
+
+	for _, r := range replicas {
+		var recovery bool
+		var lag float64
+		err := r.db.QueryRow(statements["replicaLag"]).Scan(&recovery, &lag)
+		if err != nil {
+			logger.Debugf("Error checking replica: %v", err)
+		}
+
+		s.replicaMtx.Lock()
+		r.healthy = err == nil && recovery
+		r.lag = time.Duration(lag * float64(time.Second))
+		s.replicaMtx.Unlock()
+	}
+}
+
+func (s *sqlStore) replicaLoop() {
+	for {
+		time.Sleep(replicaCheckInterval)
+		s.checkReplicas()
+	}
+}
+
+// reader returns the connection to read from. Reads go to the next healthy replica within the
+// staleness bound when replica reads are enabled, otherwise to the primary.
+func (s *sqlStore) reader(options store.ReadOptions) (*sql.DB, bool, error) {
+	if maxStaleness, ok := replicaReads(s.options); ok && !options.Primary {
+		s.replicaMtx.Lock()
+		for i := range s.replicas {
+			r := s.replicas[(s.next+i)%len(s.replicas)]
+			if r.healthy && r.lag <= maxStaleness {
+				s.next = (s.next + i + 1) % len(s.replicas)
+				s.replicaMtx.Unlock()
+				return r.db, true, nil
+			}
+		}
+		s.replicaMtx.Unlock()
+	}
+
+	db, err := s.db()
+	return db, false, err
+}
+
+// replicaFailed marks the replica as unhealthy until it's next checked
+func (s *sqlStore) replicaFailed(db *sql.DB) {
+	s.replicaMtx.Lock()
+	defer s.replicaMtx.Unlock()
+	for _, r := range s.replicas {
+		if r.db == db {
+			r.healthy = false
+		}
+	}
+}
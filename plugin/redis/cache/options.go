@@ -0,0 +1,47 @@
+package cache
+
+import "crypto/tls"
+
+// Options which are used to configure the redis cache
+type Options struct {
+	Address   string
+	User      string
+	Password  string
+	TLSConfig *tls.Config
+	// Prefix of the keys the responses are cached in
+	Prefix string
+}
+
+// Option is a function which configures options
+type Option func(o *Options)
+
+func Address(addr string) Option {
+	return func(o *Options) {
+		o.Address = addr
+	}
+}
+
+func User(user string) Option {
+	return func(o *Options) {
+		o.User = user
+	}
+}
+
+func Password(password string) Option {
+	return func(o *Options) {
+		o.Password = password
+	}
+}
+
+func TLSConfig(tlsConfig *tls.Config) Option {
+	return func(o *Options) {
+		o.TLSConfig = tlsConfig
+	}
+}
+
+// Prefix sets the prefix of the keys, so several caches can share a redis
+func Prefix(p string) Option {
+	return func(o *Options) {
+		o.Prefix = p
+	}
+}
@@ -0,0 +1,59 @@
+// Package cache is a redis backend of the client response cache, so responses cached with the
+// CallExpiry call option are shared between the replicas of a service. Set it as the default
+// backend in a profile:
+//
+//	cache.DefaultBackend = rediscache.NewBackend(rediscache.Address("localhost:6379"))
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/micro/micro/v3/util/cache"
+)
+
+type backend struct {
+	opts        Options
+	redisClient *redis.Client
+}
+
+// NewBackend returns a cache backend which stores the responses in redis
+func NewBackend(opts ...Option) cache.Backend {
+	options := Options{
+		Address: "localhost:6379",
+		Prefix:  "micro-cache:",
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	return &backend{
+		opts: options,
+		redisClient: redis.NewClient(&redis.Options{
+			Addr:      options.Address,
+			Username:  options.User,
+			Password:  options.Password,
+			TLSConfig: options.TLSConfig,
+		}),
+	}
+}
+
+func (b *backend) Get(ctx context.Context, key string) ([]byte, error) {
+	val, err := b.redisClient.Get(ctx, b.opts.Prefix+key).Bytes()
+	if err == redis.Nil {
+		return nil, cache.ErrNotFound
+	}
+	return val, err
+}
+
+func (b *backend) Set(ctx context.Context, key string, val []byte, expiry time.Duration) error {
+	return b.redisClient.Set(ctx, b.opts.Prefix+key, val, expiry).Err()
+}
+
+func (b *backend) Delete(ctx context.Context, key string) error {
+	return b.redisClient.Del(ctx, b.opts.Prefix+key).Err()
+}
+
+func (b *backend) String() string {
+	return "redis"
+}
@@ -0,0 +1,28 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/util/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackend(t *testing.T) {
+	b := NewBackend(Address("localhost:6379"), User("default"), Prefix("test-cache:"))
+	ctx := context.TODO()
+
+	_, err := b.Get(ctx, "missing")
+	assert.Equal(t, cache.ErrNotFound, err)
+
+	assert.NoError(t, b.Set(ctx, "foo", []byte("bar"), time.Second))
+	val, err := b.Get(ctx, "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", string(val))
+
+	// the response expires
+	time.Sleep(1100 * time.Millisecond)
+	_, err = b.Get(ctx, "foo")
+	assert.Equal(t, cache.ErrNotFound, err)
+}
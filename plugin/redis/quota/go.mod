@@ -0,0 +1,11 @@
+module github.com/micro/micro/plugin/redis/quota/v3
+
+go 1.15
+
+require (
+	github.com/go-redis/redis/v8 v8.11.3
+	github.com/micro/micro/v3 v3.3.1-0.20210803122146-2a2fa437600d
+	github.com/stretchr/testify v1.7.0
+)
+
+replace github.com/micro/micro/v3 => ../../..
@@ -0,0 +1,111 @@
+// Package quota is a redis backend of the quota service, for limits checked more often than the
+// store can keep up with. Set it as the backend of the quota service in a profile:
+//
+//	server.Backend = redisquota.NewQuota(redisquota.Address("localhost:6379"))
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/micro/micro/v3/service/quota"
+)
+
+// allowRetries is the number of times taking tokens is retried when the bucket was changed by
+// another request since it was read
+var allowRetries = 10
+
+type redisQuota struct {
+	opts        Options
+	redisClient *redis.Client
+}
+
+// NewQuota returns a quota which keeps the buckets in redis. Buckets are watched while tokens
+// are taken so requests taking tokens concurrently, from any replica of the quota service, are
+// all counted.
+func NewQuota(opts ...Option) quota.Quota {
+	options := Options{
+		Address: "localhost:6379",
+		Prefix:  "micro-quota:",
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	return &redisQuota{
+		opts: options,
+		redisClient: redis.NewClient(&redis.Options{
+			Addr:      options.Address,
+			Username:  options.User,
+			Password:  options.Password,
+			TLSConfig: options.TLSConfig,
+		}),
+	}
+}
+
+func (r *redisQuota) Allow(key string, opts ...quota.AllowOption) (*quota.Result, error) {
+	options := quota.NewAllowOptions(opts...)
+	if len(key) == 0 {
+		return nil, quota.ErrMissingKey
+	}
+	if options.Limit <= 0 || options.Window <= 0 {
+		return nil, quota.ErrInvalidLimit
+	}
+
+	ctx := context.Background()
+	k := r.key(options.Namespace, key)
+
+	var res *quota.Result
+	take := func(tx *redis.Tx) error {
+		var b quota.Bucket
+		val, err := tx.Get(ctx, k).Bytes()
+		if err == nil {
+			if err := json.Unmarshal(val, &b); err != nil {
+				return err
+			}
+		} else if err != redis.Nil {
+			return err
+		}
+
+		res = b.Take(time.Now(), options)
+
+		val, err = json.Marshal(&b)
+		if err != nil {
+			return err
+		}
+		// the bucket is full again once it expires, so there's no need to keep it
+		_, err = tx.TxPipelined(ctx, func(p redis.Pipeliner) error {
+			return p.Set(ctx, k, val, quota.FillTime(options)).Err()
+		})
+		return err
+	}
+
+	for i := 0; i < allowRetries; i++ {
+		err := r.redisClient.Watch(ctx, take, k)
+		if err == redis.TxFailedErr {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+	return nil, redis.TxFailedErr
+}
+
+func (r *redisQuota) Reset(key string, opts ...quota.ResetOption) error {
+	var options quota.ResetOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	return r.redisClient.Del(context.Background(), r.key(options.Namespace, key)).Err()
+}
+
+func (r *redisQuota) String() string {
+	return "redis"
+}
+
+func (r *redisQuota) key(ns, key string) string {
+	return r.opts.Prefix + ns + ":" + key
+}
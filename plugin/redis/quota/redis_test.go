@@ -0,0 +1,31 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/quota"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuota(t *testing.T) {
+	q := NewQuota(Address("localhost:6379"), User("default"), Prefix("test-quota:"))
+	opts := []quota.AllowOption{quota.Limit(2, time.Minute), quota.AllowNamespace("foo")}
+	assert.NoError(t, q.Reset("user", quota.ResetNamespace("foo")))
+
+	for i := 0; i < 2; i++ {
+		res, err := q.Allow("user", opts...)
+		assert.NoError(t, err)
+		assert.True(t, res.Allowed)
+	}
+	res, err := q.Allow("user", opts...)
+	assert.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.True(t, res.RetryAfter > 0)
+
+	// resetting refills the bucket
+	assert.NoError(t, q.Reset("user", quota.ResetNamespace("foo")))
+	res, err = q.Allow("user", opts...)
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+}
@@ -0,0 +1,117 @@
+package s3
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sthree "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/micro/micro/v3/service/store"
+)
+
+// location returns the bucket and object name of a key in the namespace
+func (s *s3) location(namespace, key string) (string, string) {
+	if len(namespace) == 0 {
+		namespace = "micro"
+	}
+	if len(s.options.Bucket) > 0 {
+		return s.options.Bucket, filepath.Join(namespace, cleanKey(key))
+	}
+	return namespace, cleanKey(key)
+}
+
+// SetLifecycle sets the expiry and transitions of the rules as the lifecycle configuration of
+// the buckets. S3 rounds ages up to whole days. Versions are pruned by the lifecycle worker as
+// s3 can only expire noncurrent versions by age.
+func (s *s3) SetLifecycle(rules []*store.LifecycleRule) error {
+	buckets := map[string][]*sthree.LifecycleRule{}
+
+	for _, r := range rules {
+		if r.ExpireAfter == 0 && r.TransitionAfter == 0 {
+			continue
+		}
+
+		// the prefix is used as is, joining it would strip a trailing slash
+		bucket, prefix := s.location(r.Namespace, "")
+		if len(s.options.Bucket) > 0 {
+			prefix += "/"
+		}
+		prefix += cleanKey(r.Prefix)
+
+		rule := &sthree.LifecycleRule{
+			ID:     aws.String(fmt.Sprintf("micro-%s-%d", r.Namespace, len(buckets[bucket]))),
+			Status: aws.String(sthree.ExpirationStatusEnabled),
+			Filter: &sthree.LifecycleRuleFilter{Prefix: aws.String(prefix)},
+		}
+		if r.ExpireAfter > 0 {
+			rule.Expiration = &sthree.LifecycleExpiration{Days: aws.Int64(days(r.ExpireAfter))}
+		}
+		if r.TransitionAfter > 0 {
+			rule.Transitions = []*sthree.Transition{{
+				Days:         aws.Int64(days(r.TransitionAfter)),
+				StorageClass: aws.String(r.StorageClass),
+			}}
+		}
+		buckets[bucket] = append(buckets[bucket], rule)
+	}
+
+	for bucket, rules := range buckets {
+		_, err := s.client.PutBucketLifecycleConfiguration(&sthree.PutBucketLifecycleConfigurationInput{
+			Bucket:                 aws.String(bucket),
+			LifecycleConfiguration: &sthree.BucketLifecycleConfiguration{Rules: rules},
+		})
+		if err != nil {
+			return fmt.Errorf("error setting the lifecycle of bucket %s: %v", bucket, err)
+		}
+	}
+	return nil
+}
+
+// days rounds the duration up to whole days
+func days(d time.Duration) int64 {
+	day := time.Hour * 24
+	return int64((d + day - 1) / day)
+}
+
+// Versions returns the ids of the previous versions of the blob, newest first. Buckets without
+// versioning enabled have none.
+func (s *s3) Versions(key string, opts ...store.BlobOption) ([]string, error) {
+	var options store.BlobOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	bucket, name := s.location(options.Namespace, key)
+
+	var versions []string
+	err := s.client.ListObjectVersionsPages(&sthree.ListObjectVersionsInput{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(name),
+	}, func(page *sthree.ListObjectVersionsOutput, last bool) bool {
+		for _, v := range page.Versions {
+			// the prefix also matches longer keys
+			if aws.StringValue(v.Key) != name || aws.BoolValue(v.IsLatest) {
+				continue
+			}
+			versions = append(versions, aws.StringValue(v.VersionId))
+		}
+		return true
+	})
+	return versions, err
+}
+
+// DeleteVersion permanently deletes a previous version of the blob
+func (s *s3) DeleteVersion(key, version string, opts ...store.BlobOption) error {
+	var options store.BlobOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	bucket, name := s.location(options.Namespace, key)
+
+	_, err := s.client.DeleteObject(&sthree.DeleteObjectInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(name),
+		VersionId: aws.String(version),
+	})
+	return err
+}
@@ -45,6 +45,12 @@ type ApiService interface {
 	AddToBlockList(ctx context.Context, in *AddToBlockListRequest, opts ...client.CallOption) (*AddToBlockListResponse, error)
 	RemoveFromBlockList(ctx context.Context, in *RemoveFromBlockListRequest, opts ...client.CallOption) (*RemoveFromBlockListResponse, error)
 	ReadBlockList(ctx context.Context, in *ReadBlockListRequest, opts ...client.CallOption) (*ReadBlockListResponse, error)
+	EnableMaintenance(ctx context.Context, in *EnableMaintenanceRequest, opts ...client.CallOption) (*EnableMaintenanceResponse, error)
+	DisableMaintenance(ctx context.Context, in *DisableMaintenanceRequest, opts ...client.CallOption) (*DisableMaintenanceResponse, error)
+	ReadMaintenance(ctx context.Context, in *ReadMaintenanceRequest, opts ...client.CallOption) (*ReadMaintenanceResponse, error)
+	AddDomain(ctx context.Context, in *AddDomainRequest, opts ...client.CallOption) (*AddDomainResponse, error)
+	RemoveDomain(ctx context.Context, in *RemoveDomainRequest, opts ...client.CallOption) (*RemoveDomainResponse, error)
+	ReadDomains(ctx context.Context, in *ReadDomainsRequest, opts ...client.CallOption) (*ReadDomainsResponse, error)
 }
 
 type apiService struct {
@@ -89,12 +95,78 @@ func (c *apiService) ReadBlockList(ctx context.Context, in *ReadBlockListRequest
 	return out, nil
 }
 
+func (c *apiService) EnableMaintenance(ctx context.Context, in *EnableMaintenanceRequest, opts ...client.CallOption) (*EnableMaintenanceResponse, error) {
+	req := c.c.NewRequest(c.name, "Api.EnableMaintenance", in)
+	out := new(EnableMaintenanceResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiService) DisableMaintenance(ctx context.Context, in *DisableMaintenanceRequest, opts ...client.CallOption) (*DisableMaintenanceResponse, error) {
+	req := c.c.NewRequest(c.name, "Api.DisableMaintenance", in)
+	out := new(DisableMaintenanceResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiService) ReadMaintenance(ctx context.Context, in *ReadMaintenanceRequest, opts ...client.CallOption) (*ReadMaintenanceResponse, error) {
+	req := c.c.NewRequest(c.name, "Api.ReadMaintenance", in)
+	out := new(ReadMaintenanceResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiService) AddDomain(ctx context.Context, in *AddDomainRequest, opts ...client.CallOption) (*AddDomainResponse, error) {
+	req := c.c.NewRequest(c.name, "Api.AddDomain", in)
+	out := new(AddDomainResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiService) RemoveDomain(ctx context.Context, in *RemoveDomainRequest, opts ...client.CallOption) (*RemoveDomainResponse, error) {
+	req := c.c.NewRequest(c.name, "Api.RemoveDomain", in)
+	out := new(RemoveDomainResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiService) ReadDomains(ctx context.Context, in *ReadDomainsRequest, opts ...client.CallOption) (*ReadDomainsResponse, error) {
+	req := c.c.NewRequest(c.name, "Api.ReadDomains", in)
+	out := new(ReadDomainsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Api service
 
 type ApiHandler interface {
 	AddToBlockList(context.Context, *AddToBlockListRequest, *AddToBlockListResponse) error
 	RemoveFromBlockList(context.Context, *RemoveFromBlockListRequest, *RemoveFromBlockListResponse) error
 	ReadBlockList(context.Context, *ReadBlockListRequest, *ReadBlockListResponse) error
+	EnableMaintenance(context.Context, *EnableMaintenanceRequest, *EnableMaintenanceResponse) error
+	DisableMaintenance(context.Context, *DisableMaintenanceRequest, *DisableMaintenanceResponse) error
+	ReadMaintenance(context.Context, *ReadMaintenanceRequest, *ReadMaintenanceResponse) error
+	AddDomain(context.Context, *AddDomainRequest, *AddDomainResponse) error
+	RemoveDomain(context.Context, *RemoveDomainRequest, *RemoveDomainResponse) error
+	ReadDomains(context.Context, *ReadDomainsRequest, *ReadDomainsResponse) error
 }
 
 func RegisterApiHandler(s server.Server, hdlr ApiHandler, opts ...server.HandlerOption) error {
@@ -102,6 +174,12 @@ func RegisterApiHandler(s server.Server, hdlr ApiHandler, opts ...server.Handler
 		AddToBlockList(ctx context.Context, in *AddToBlockListRequest, out *AddToBlockListResponse) error
 		RemoveFromBlockList(ctx context.Context, in *RemoveFromBlockListRequest, out *RemoveFromBlockListResponse) error
 		ReadBlockList(ctx context.Context, in *ReadBlockListRequest, out *ReadBlockListResponse) error
+		EnableMaintenance(ctx context.Context, in *EnableMaintenanceRequest, out *EnableMaintenanceResponse) error
+		DisableMaintenance(ctx context.Context, in *DisableMaintenanceRequest, out *DisableMaintenanceResponse) error
+		ReadMaintenance(ctx context.Context, in *ReadMaintenanceRequest, out *ReadMaintenanceResponse) error
+		AddDomain(ctx context.Context, in *AddDomainRequest, out *AddDomainResponse) error
+		RemoveDomain(ctx context.Context, in *RemoveDomainRequest, out *RemoveDomainResponse) error
+		ReadDomains(ctx context.Context, in *ReadDomainsRequest, out *ReadDomainsResponse) error
 	}
 	type Api struct {
 		api
@@ -125,3 +203,27 @@ func (h *apiHandler) RemoveFromBlockList(ctx context.Context, in *RemoveFromBloc
 func (h *apiHandler) ReadBlockList(ctx context.Context, in *ReadBlockListRequest, out *ReadBlockListResponse) error {
 	return h.ApiHandler.ReadBlockList(ctx, in, out)
 }
+
+func (h *apiHandler) EnableMaintenance(ctx context.Context, in *EnableMaintenanceRequest, out *EnableMaintenanceResponse) error {
+	return h.ApiHandler.EnableMaintenance(ctx, in, out)
+}
+
+func (h *apiHandler) DisableMaintenance(ctx context.Context, in *DisableMaintenanceRequest, out *DisableMaintenanceResponse) error {
+	return h.ApiHandler.DisableMaintenance(ctx, in, out)
+}
+
+func (h *apiHandler) ReadMaintenance(ctx context.Context, in *ReadMaintenanceRequest, out *ReadMaintenanceResponse) error {
+	return h.ApiHandler.ReadMaintenance(ctx, in, out)
+}
+
+func (h *apiHandler) AddDomain(ctx context.Context, in *AddDomainRequest, out *AddDomainResponse) error {
+	return h.ApiHandler.AddDomain(ctx, in, out)
+}
+
+func (h *apiHandler) RemoveDomain(ctx context.Context, in *RemoveDomainRequest, out *RemoveDomainResponse) error {
+	return h.ApiHandler.RemoveDomain(ctx, in, out)
+}
+
+func (h *apiHandler) ReadDomains(ctx context.Context, in *ReadDomainsRequest, out *ReadDomainsResponse) error {
+	return h.ApiHandler.ReadDomains(ctx, in, out)
+}
@@ -718,8 +718,11 @@ func (m *RevokeResponse) XXX_DiscardUnknown() {
 var xxx_messageInfo_RevokeResponse proto.InternalMessageInfo
 
 type InspectRequest struct {
-	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
-	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	Token   string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Options *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	// the namespace the call was made to, tokens signed with the key of a namespace are verified
+	// with its keys. by default they're verified with the keys of the namespace which issued them
+	CallNamespace        string   `protobuf:"bytes,3,opt,name=call_namespace,json=callNamespace,proto3" json:"call_namespace,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -764,6 +767,13 @@ func (m *InspectRequest) GetOptions() *Options {
 	return nil
 }
 
+func (m *InspectRequest) GetCallNamespace() string {
+	if m != nil {
+		return m.CallNamespace
+	}
+	return ""
+}
+
 type InspectResponse struct {
 	Account              *Account `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -1351,503 +1361,4902 @@ func (m *ChangeSecretResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ChangeSecretResponse proto.InternalMessageInfo
 
-func init() {
-	proto.RegisterEnum("auth.Access", Access_name, Access_value)
-	proto.RegisterType((*ListAccountsRequest)(nil), "auth.ListAccountsRequest")
-	proto.RegisterType((*ListAccountsResponse)(nil), "auth.ListAccountsResponse")
-	proto.RegisterType((*DeleteAccountRequest)(nil), "auth.DeleteAccountRequest")
-	proto.RegisterType((*DeleteAccountResponse)(nil), "auth.DeleteAccountResponse")
-	proto.RegisterType((*Token)(nil), "auth.Token")
-	proto.RegisterType((*Account)(nil), "auth.Account")
-	proto.RegisterMapType((map[string]string)(nil), "auth.Account.MetadataEntry")
-	proto.RegisterType((*Resource)(nil), "auth.Resource")
-	proto.RegisterType((*GenerateRequest)(nil), "auth.GenerateRequest")
-	proto.RegisterMapType((map[string]string)(nil), "auth.GenerateRequest.MetadataEntry")
-	proto.RegisterType((*GenerateResponse)(nil), "auth.GenerateResponse")
-	proto.RegisterType((*GrantRequest)(nil), "auth.GrantRequest")
-	proto.RegisterType((*GrantResponse)(nil), "auth.GrantResponse")
-	proto.RegisterType((*RevokeRequest)(nil), "auth.RevokeRequest")
-	proto.RegisterType((*RevokeResponse)(nil), "auth.RevokeResponse")
-	proto.RegisterType((*InspectRequest)(nil), "auth.InspectRequest")
-	proto.RegisterType((*InspectResponse)(nil), "auth.InspectResponse")
-	proto.RegisterType((*TokenRequest)(nil), "auth.TokenRequest")
-	proto.RegisterType((*TokenResponse)(nil), "auth.TokenResponse")
-	proto.RegisterType((*Rule)(nil), "auth.Rule")
-	proto.RegisterType((*Options)(nil), "auth.Options")
-	proto.RegisterType((*CreateRequest)(nil), "auth.CreateRequest")
-	proto.RegisterType((*CreateResponse)(nil), "auth.CreateResponse")
-	proto.RegisterType((*DeleteRequest)(nil), "auth.DeleteRequest")
-	proto.RegisterType((*DeleteResponse)(nil), "auth.DeleteResponse")
-	proto.RegisterType((*ListRequest)(nil), "auth.ListRequest")
-	proto.RegisterType((*ListResponse)(nil), "auth.ListResponse")
-	proto.RegisterType((*ChangeSecretRequest)(nil), "auth.ChangeSecretRequest")
-	proto.RegisterType((*ChangeSecretResponse)(nil), "auth.ChangeSecretResponse")
+type UnlockAccountRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func init() { proto.RegisterFile("auth/auth.proto", fileDescriptor_712ec48c1eaf43a2) }
-
-var fileDescriptor_712ec48c1eaf43a2 = []byte{
-	// 1052 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x56, 0xdd, 0x6e, 0xe3, 0xc4,
-	0x17, 0xaf, 0x63, 0xe7, 0xeb, 0xe4, 0xa3, 0xf9, 0x4f, 0xd3, 0xfe, 0x4d, 0x16, 0x50, 0xeb, 0x45,
-	0x6a, 0x77, 0x91, 0x52, 0x94, 0xd5, 0xc2, 0x6a, 0x2b, 0xb1, 0x2a, 0x6d, 0x54, 0x56, 0x40, 0x2a,
-	0x99, 0x45, 0x20, 0x6e, 0x2a, 0xaf, 0x73, 0xd8, 0x58, 0x4d, 0x6d, 0x63, 0x8f, 0xbb, 0x84, 0x3b,
-	0xee, 0x79, 0x02, 0x1e, 0x00, 0xae, 0xb9, 0xe7, 0x45, 0x78, 0x1a, 0x90, 0x67, 0xce, 0x38, 0x76,
-	0xea, 0x86, 0x22, 0x90, 0xb8, 0xb1, 0x7c, 0x3e, 0xe7, 0xfc, 0x7e, 0x73, 0xce, 0xcc, 0xc0, 0xa6,
-	0x93, 0xf0, 0xd9, 0x61, 0xfa, 0x19, 0x86, 0x51, 0xc0, 0x03, 0x66, 0xa4, 0xff, 0xd6, 0x87, 0xb0,
-	0xf5, 0xa9, 0x17, 0xf3, 0x63, 0xd7, 0x0d, 0x12, 0x9f, 0xc7, 0x36, 0x7e, 0x9b, 0x60, 0xcc, 0xd9,
-	0x3e, 0xd4, 0x83, 0x90, 0x7b, 0x81, 0x1f, 0x9b, 0xda, 0xae, 0x76, 0xd0, 0x1a, 0x75, 0x86, 0x22,
-	0xf4, 0x5c, 0x2a, 0x6d, 0x65, 0xb5, 0x8e, 0xa1, 0x5f, 0x8c, 0x8f, 0xc3, 0xc0, 0x8f, 0x91, 0x3d,
-	0x80, 0x86, 0x43, 0x3a, 0x53, 0xdb, 0xd5, 0x97, 0x19, 0xc8, 0xd3, 0xce, 0xcc, 0xd6, 0x39, 0xf4,
-	0x4f, 0x71, 0x8e, 0x1c, 0x95, 0x89, 0x6a, 0xe8, 0x42, 0xc5, 0x9b, 0x8a, 0xe5, 0x9b, 0x76, 0xc5,
-	0x9b, 0xe6, 0x6b, 0xaa, 0xac, 0xad, 0xe9, 0xff, 0xb0, 0xbd, 0x92, 0x50, 0x16, 0x65, 0xfd, 0xa0,
-	0x41, 0xf5, 0x45, 0x70, 0x89, 0x3e, 0xdb, 0x83, 0xb6, 0xe3, 0xba, 0x18, 0xc7, 0x17, 0x3c, 0x95,
-	0x69, 0x95, 0x96, 0xd4, 0x49, 0x97, 0xfb, 0xd0, 0x89, 0xf0, 0x9b, 0x08, 0xe3, 0x19, 0xf9, 0x54,
-	0x84, 0x4f, 0x9b, 0x94, 0xd2, 0xc9, 0x84, 0xba, 0x1b, 0xa1, 0xc3, 0x71, 0x6a, 0xea, 0xbb, 0xda,
-	0x81, 0x6e, 0x2b, 0x91, 0xed, 0x40, 0x0d, 0xbf, 0x0b, 0xbd, 0x68, 0x61, 0x1a, 0xc2, 0x40, 0x92,
-	0xf5, 0x87, 0x06, 0x75, 0xaa, 0xeb, 0x06, 0x42, 0x06, 0x06, 0x5f, 0x84, 0x48, 0x2b, 0x89, 0x7f,
-	0xf6, 0x01, 0x34, 0xae, 0x90, 0x3b, 0x53, 0x87, 0x3b, 0xa6, 0x21, 0x88, 0xbc, 0x57, 0x20, 0x72,
-	0xf8, 0x19, 0x59, 0xc7, 0x3e, 0x8f, 0x16, 0x76, 0xe6, 0x9c, 0x16, 0x10, 0xbb, 0x41, 0x88, 0xb1,
-	0x59, 0xdd, 0xd5, 0x0f, 0x9a, 0x36, 0x49, 0xa9, 0xde, 0x8b, 0xe3, 0x04, 0x23, 0xb3, 0x26, 0x96,
-	0x21, 0x49, 0xf8, 0xa3, 0x1b, 0x21, 0x37, 0xeb, 0x52, 0x2f, 0xa5, 0xb4, 0x28, 0xdf, 0xb9, 0x42,
-	0xb3, 0x21, 0x8b, 0x4a, 0xff, 0x07, 0x47, 0xd0, 0x29, 0x2c, 0xcb, 0x7a, 0xa0, 0x5f, 0xe2, 0x82,
-	0xa0, 0xa4, 0xbf, 0xac, 0x0f, 0xd5, 0x6b, 0x67, 0x9e, 0x28, 0x30, 0x52, 0x78, 0x5a, 0x79, 0xa2,
-	0x59, 0x13, 0x68, 0xd8, 0x18, 0x07, 0x49, 0xe4, 0x62, 0x96, 0x5c, 0x5b, 0x26, 0x2f, 0x65, 0x61,
-	0x00, 0x0d, 0xf4, 0xa7, 0x61, 0xe0, 0xf9, 0x5c, 0x10, 0xdd, 0xb4, 0x33, 0xd9, 0xfa, 0xad, 0x02,
-	0x9b, 0x67, 0xe8, 0x63, 0xe4, 0x70, 0xbc, 0xad, 0x77, 0x9e, 0xe5, 0x58, 0xd4, 0x05, 0x8b, 0xf7,
-	0x25, 0x8b, 0x2b, 0x81, 0x77, 0x60, 0xd3, 0x58, 0x65, 0x93, 0x58, 0xab, 0xae, 0xb2, 0x26, 0x40,
-	0xd4, 0x8a, 0x20, 0xc2, 0x28, 0xb8, 0xf6, 0xa6, 0x18, 0x11, 0xc7, 0x99, 0x9c, 0x6f, 0xee, 0xc6,
-	0xba, 0xe6, 0xce, 0x18, 0x6b, 0xfe, 0x5b, 0xdb, 0x71, 0x04, 0xbd, 0x25, 0x09, 0x34, 0xbd, 0xfb,
-	0x50, 0xa7, 0xf1, 0x2c, 0x8e, 0xbf, 0x1a, 0x28, 0x65, 0xb5, 0x16, 0xd0, 0x3e, 0x8b, 0x9c, 0xe5,
-	0xcc, 0xf6, 0xa1, 0x2a, 0x88, 0xa1, 0xa5, 0xa5, 0xc0, 0x1e, 0x42, 0x23, 0xa2, 0x1d, 0xa7, 0xd1,
-	0xed, 0xca, 0x7c, 0xaa, 0x0f, 0xec, 0xcc, 0x9e, 0x27, 0x42, 0x5f, 0x3b, 0xe5, 0x9b, 0xd0, 0xa1,
-	0xa5, 0x69, 0xba, 0xbf, 0x87, 0x8e, 0x8d, 0xd7, 0xc1, 0x25, 0xfe, 0x07, 0xc5, 0xf4, 0xa0, 0xab,
-	0xd6, 0xa6, 0x6a, 0xce, 0xa1, 0xfb, 0xdc, 0x8f, 0x43, 0x74, 0xf3, 0xdc, 0xe4, 0x0f, 0x1b, 0x29,
-	0xdc, 0xfd, 0x54, 0x7b, 0x0a, 0x9b, 0x59, 0xc2, 0xbf, 0xbb, 0x4d, 0xbf, 0x68, 0xd0, 0x16, 0x07,
-	0xd6, 0x6d, 0xf3, 0xb1, 0x6c, 0xe3, 0x4a, 0xa1, 0x8d, 0x6f, 0x1c, 0x82, 0x7a, 0xc9, 0x21, 0xb8,
-	0x07, 0x6d, 0x61, 0xbc, 0x28, 0x1c, 0x78, 0x2d, 0xa1, 0x1b, 0x0b, 0x55, 0x1e, 0x65, 0x75, 0x2d,
-	0xca, 0x11, 0x74, 0xa8, 0x50, 0xc2, 0xb8, 0x97, 0x67, 0xad, 0x35, 0x6a, 0xc9, 0x38, 0xe9, 0x23,
-	0x2d, 0xd6, 0x4f, 0x1a, 0x18, 0x76, 0x32, 0xc7, 0x1b, 0xa8, 0xb2, 0x06, 0xa8, 0xdc, 0xd6, 0x00,
-	0xfa, 0x5f, 0x34, 0xc0, 0x3b, 0x50, 0x93, 0x77, 0x82, 0x00, 0xd5, 0x1d, 0xb5, 0x33, 0x82, 0x31,
-	0x8e, 0x6d, 0xb2, 0xc9, 0xc1, 0xf6, 0x82, 0xc8, 0xe3, 0x0b, 0x01, 0xaf, 0x6a, 0x67, 0xb2, 0xb5,
-	0x0f, 0x75, 0x02, 0xc9, 0xde, 0x84, 0x66, 0x3a, 0xae, 0x71, 0xe8, 0xb8, 0xaa, 0x27, 0x97, 0x0a,
-	0xeb, 0x2b, 0xe8, 0x9c, 0x88, 0xbb, 0x43, 0xed, 0xd1, 0xdb, 0x60, 0x44, 0xc9, 0x1c, 0x09, 0x38,
-	0x50, 0x8d, 0xc9, 0x1c, 0x6d, 0xa1, 0xbf, 0x7b, 0xe7, 0xf4, 0xa0, 0xab, 0x32, 0x53, 0x73, 0x7e,
-	0x0c, 0x1d, 0x79, 0x43, 0xfe, 0xe3, 0xbb, 0xb6, 0x07, 0x5d, 0x95, 0x89, 0x72, 0xbf, 0x0f, 0xad,
-	0xf4, 0x45, 0x50, 0xf2, 0x92, 0x58, 0x9f, 0xe9, 0x3d, 0x68, 0xcb, 0x38, 0xda, 0xf8, 0x5d, 0xa8,
-	0xa6, 0x30, 0xd5, 0xf3, 0x21, 0x8f, 0x5f, 0x1a, 0xac, 0x1f, 0x35, 0xd8, 0x3a, 0x99, 0x39, 0xfe,
-	0x2b, 0xfc, 0x5c, 0x74, 0xeb, 0x6d, 0x60, 0xde, 0x02, 0x08, 0xe6, 0xd3, 0x8b, 0x42, 0x83, 0x37,
-	0x83, 0xf9, 0x54, 0x46, 0xa5, 0x66, 0x1f, 0x5f, 0x2b, 0xb3, 0x4e, 0xfb, 0x82, 0xaf, 0xc9, 0x9c,
-	0x03, 0x60, 0xac, 0x05, 0xb0, 0x03, 0xfd, 0x62, 0x35, 0x12, 0xc8, 0xc3, 0x21, 0xd4, 0x64, 0xbf,
-	0xb0, 0x16, 0xd4, 0xbf, 0x98, 0x7c, 0x32, 0x39, 0xff, 0x72, 0xd2, 0xdb, 0x48, 0x85, 0x33, 0xfb,
-	0x78, 0xf2, 0x62, 0x7c, 0xda, 0xd3, 0x18, 0x40, 0xed, 0x74, 0x3c, 0x79, 0x3e, 0x3e, 0xed, 0x55,
-	0x46, 0xbf, 0x6a, 0x60, 0x1c, 0x27, 0x7c, 0xc6, 0x8e, 0xa0, 0xa1, 0x4e, 0x66, 0xb6, 0x5d, 0x7a,
-	0x5d, 0x0d, 0x76, 0x56, 0xd5, 0xb4, 0x09, 0x1b, 0xec, 0x09, 0xd4, 0xe9, 0xb8, 0x60, 0x7d, 0xe9,
-	0x54, 0x3c, 0x8e, 0x06, 0xdb, 0x2b, 0xda, 0x2c, 0x72, 0xa4, 0x1e, 0x49, 0x2c, 0x3f, 0x6b, 0x14,
-	0xb5, 0x55, 0xd0, 0xa9, 0x98, 0xd1, 0xef, 0x1a, 0x34, 0xd4, 0x1b, 0x90, 0x3d, 0x03, 0x23, 0xdd,
-	0x49, 0xf6, 0x86, 0xf4, 0x2d, 0x79, 0x5f, 0x0e, 0x06, 0x65, 0xa6, 0xac, 0x82, 0x13, 0xa8, 0xc9,
-	0xa6, 0x62, 0xe4, 0x57, 0xf6, 0x3e, 0x1c, 0xdc, 0x2b, 0xb5, 0x65, 0x49, 0xce, 0xa0, 0x9d, 0xdf,
-	0x0e, 0x55, 0x4d, 0x49, 0xc3, 0xa8, 0x6a, 0xca, 0x76, 0xcf, 0xda, 0x18, 0xfd, 0xac, 0x41, 0x35,
-	0x6d, 0xbb, 0x98, 0x3d, 0x86, 0x9a, 0x1c, 0x24, 0x46, 0x34, 0x14, 0x06, 0x76, 0xd0, 0x2f, 0x2a,
-	0xb3, 0x4a, 0x1e, 0x67, 0x70, 0xb6, 0xf2, 0x25, 0xaf, 0x84, 0xad, 0x8c, 0xd1, 0x06, 0x3b, 0x24,
-	0x1a, 0xff, 0xb7, 0xe4, 0x4a, 0x85, 0xb0, 0xbc, 0x4a, 0x05, 0x7c, 0xf4, 0xee, 0xd7, 0x0f, 0x5e,
-	0x79, 0x7c, 0x96, 0xbc, 0x1c, 0xba, 0xc1, 0xd5, 0xe1, 0x95, 0xe7, 0x46, 0x01, 0x7d, 0xaf, 0x1f,
-	0x1d, 0x8a, 0x47, 0xbf, 0x78, 0xff, 0x1f, 0xa5, 0x9f, 0x97, 0x35, 0xa1, 0x78, 0xf4, 0x67, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0x59, 0x5b, 0x5f, 0xcd, 0x18, 0x0c, 0x00, 0x00,
+func (m *UnlockAccountRequest) Reset()         { *m = UnlockAccountRequest{} }
+func (m *UnlockAccountRequest) String() string { return proto.CompactTextString(m) }
+func (*UnlockAccountRequest) ProtoMessage()    {}
+func (*UnlockAccountRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{27}
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *UnlockAccountRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnlockAccountRequest.Unmarshal(m, b)
+}
+func (m *UnlockAccountRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnlockAccountRequest.Marshal(b, m, deterministic)
+}
+func (m *UnlockAccountRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnlockAccountRequest.Merge(m, src)
+}
+func (m *UnlockAccountRequest) XXX_Size() int {
+	return xxx_messageInfo_UnlockAccountRequest.Size(m)
+}
+func (m *UnlockAccountRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnlockAccountRequest.DiscardUnknown(m)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+var xxx_messageInfo_UnlockAccountRequest proto.InternalMessageInfo
 
-// AuthClient is the client API for Auth service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type AuthClient interface {
-	Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error)
-	Inspect(ctx context.Context, in *InspectRequest, opts ...grpc.CallOption) (*InspectResponse, error)
-	Token(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*TokenResponse, error)
+func (m *UnlockAccountRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
 }
 
-type authClient struct {
-	cc *grpc.ClientConn
+func (m *UnlockAccountRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
 }
 
-func NewAuthClient(cc *grpc.ClientConn) AuthClient {
-	return &authClient{cc}
+type UnlockAccountResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (c *authClient) Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error) {
-	out := new(GenerateResponse)
-	err := c.cc.Invoke(ctx, "/auth.Auth/Generate", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *UnlockAccountResponse) Reset()         { *m = UnlockAccountResponse{} }
+func (m *UnlockAccountResponse) String() string { return proto.CompactTextString(m) }
+func (*UnlockAccountResponse) ProtoMessage()    {}
+func (*UnlockAccountResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{28}
 }
 
-func (c *authClient) Inspect(ctx context.Context, in *InspectRequest, opts ...grpc.CallOption) (*InspectResponse, error) {
-	out := new(InspectResponse)
-	err := c.cc.Invoke(ctx, "/auth.Auth/Inspect", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *UnlockAccountResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnlockAccountResponse.Unmarshal(m, b)
+}
+func (m *UnlockAccountResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnlockAccountResponse.Marshal(b, m, deterministic)
+}
+func (m *UnlockAccountResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnlockAccountResponse.Merge(m, src)
+}
+func (m *UnlockAccountResponse) XXX_Size() int {
+	return xxx_messageInfo_UnlockAccountResponse.Size(m)
+}
+func (m *UnlockAccountResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnlockAccountResponse.DiscardUnknown(m)
 }
 
-func (c *authClient) Token(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*TokenResponse, error) {
-	out := new(TokenResponse)
-	err := c.cc.Invoke(ctx, "/auth.Auth/Token", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+var xxx_messageInfo_UnlockAccountResponse proto.InternalMessageInfo
+
+type OAuthTokenRequest struct {
+	// client_credentials or refresh_token
+	GrantType    string `protobuf:"bytes,1,opt,name=grant_type,json=grantType,proto3" json:"grant_type,omitempty"`
+	ClientId     string `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	ClientSecret string `protobuf:"bytes,3,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
+	RefreshToken string `protobuf:"bytes,4,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	// space delimited list of scopes
+	Scope                string   `protobuf:"bytes,5,opt,name=scope,proto3" json:"scope,omitempty"`
+	Options              *Options `protobuf:"bytes,6,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-// AuthServer is the server API for Auth service.
-type AuthServer interface {
-	Generate(context.Context, *GenerateRequest) (*GenerateResponse, error)
-	Inspect(context.Context, *InspectRequest) (*InspectResponse, error)
-	Token(context.Context, *TokenRequest) (*TokenResponse, error)
+func (m *OAuthTokenRequest) Reset()         { *m = OAuthTokenRequest{} }
+func (m *OAuthTokenRequest) String() string { return proto.CompactTextString(m) }
+func (*OAuthTokenRequest) ProtoMessage()    {}
+func (*OAuthTokenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{29}
 }
 
-func RegisterAuthServer(s *grpc.Server, srv AuthServer) {
-	s.RegisterService(&_Auth_serviceDesc, srv)
+func (m *OAuthTokenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OAuthTokenRequest.Unmarshal(m, b)
+}
+func (m *OAuthTokenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OAuthTokenRequest.Marshal(b, m, deterministic)
+}
+func (m *OAuthTokenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OAuthTokenRequest.Merge(m, src)
+}
+func (m *OAuthTokenRequest) XXX_Size() int {
+	return xxx_messageInfo_OAuthTokenRequest.Size(m)
+}
+func (m *OAuthTokenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_OAuthTokenRequest.DiscardUnknown(m)
 }
 
-func _Auth_Generate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GenerateRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(AuthServer).Generate(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/auth.Auth/Generate",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthServer).Generate(ctx, req.(*GenerateRequest))
+var xxx_messageInfo_OAuthTokenRequest proto.InternalMessageInfo
+
+func (m *OAuthTokenRequest) GetGrantType() string {
+	if m != nil {
+		return m.GrantType
 	}
-	return interceptor(ctx, in, info, handler)
+	return ""
 }
 
-func _Auth_Inspect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(InspectRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(AuthServer).Inspect(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/auth.Auth/Inspect",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthServer).Inspect(ctx, req.(*InspectRequest))
+func (m *OAuthTokenRequest) GetClientId() string {
+	if m != nil {
+		return m.ClientId
 	}
-	return interceptor(ctx, in, info, handler)
+	return ""
 }
 
-func _Auth_Token_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TokenRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(AuthServer).Token(ctx, in)
+func (m *OAuthTokenRequest) GetClientSecret() string {
+	if m != nil {
+		return m.ClientSecret
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/auth.Auth/Token",
+	return ""
+}
+
+func (m *OAuthTokenRequest) GetRefreshToken() string {
+	if m != nil {
+		return m.RefreshToken
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthServer).Token(ctx, req.(*TokenRequest))
+	return ""
+}
+
+func (m *OAuthTokenRequest) GetScope() string {
+	if m != nil {
+		return m.Scope
 	}
-	return interceptor(ctx, in, info, handler)
+	return ""
 }
 
-var _Auth_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "auth.Auth",
-	HandlerType: (*AuthServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Generate",
-			Handler:    _Auth_Generate_Handler,
-		},
-		{
-			MethodName: "Inspect",
-			Handler:    _Auth_Inspect_Handler,
-		},
-		{
-			MethodName: "Token",
-			Handler:    _Auth_Token_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "auth/auth.proto",
+func (m *OAuthTokenRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
 }
 
-// AccountsClient is the client API for Accounts service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type AccountsClient interface {
-	List(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error)
-	Delete(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*DeleteAccountResponse, error)
-	ChangeSecret(ctx context.Context, in *ChangeSecretRequest, opts ...grpc.CallOption) (*ChangeSecretResponse, error)
+// OAuthTokenResponse follows RFC 6749, errors in the grant are returned in the
+// error fields rather than as an rpc error
+type OAuthTokenResponse struct {
+	AccessToken          string   `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	TokenType            string   `protobuf:"bytes,2,opt,name=token_type,json=tokenType,proto3" json:"token_type,omitempty"`
+	ExpiresIn            int64    `protobuf:"varint,3,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	RefreshToken         string   `protobuf:"bytes,4,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	Scope                string   `protobuf:"bytes,5,opt,name=scope,proto3" json:"scope,omitempty"`
+	Error                string   `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	ErrorDescription     string   `protobuf:"bytes,7,opt,name=error_description,json=errorDescription,proto3" json:"error_description,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-type accountsClient struct {
-	cc *grpc.ClientConn
+func (m *OAuthTokenResponse) Reset()         { *m = OAuthTokenResponse{} }
+func (m *OAuthTokenResponse) String() string { return proto.CompactTextString(m) }
+func (*OAuthTokenResponse) ProtoMessage()    {}
+func (*OAuthTokenResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{30}
 }
 
-func NewAccountsClient(cc *grpc.ClientConn) AccountsClient {
-	return &accountsClient{cc}
+func (m *OAuthTokenResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OAuthTokenResponse.Unmarshal(m, b)
+}
+func (m *OAuthTokenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OAuthTokenResponse.Marshal(b, m, deterministic)
+}
+func (m *OAuthTokenResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OAuthTokenResponse.Merge(m, src)
+}
+func (m *OAuthTokenResponse) XXX_Size() int {
+	return xxx_messageInfo_OAuthTokenResponse.Size(m)
+}
+func (m *OAuthTokenResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_OAuthTokenResponse.DiscardUnknown(m)
 }
 
-func (c *accountsClient) List(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error) {
-	out := new(ListAccountsResponse)
-	err := c.cc.Invoke(ctx, "/auth.Accounts/List", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_OAuthTokenResponse proto.InternalMessageInfo
+
+func (m *OAuthTokenResponse) GetAccessToken() string {
+	if m != nil {
+		return m.AccessToken
 	}
-	return out, nil
+	return ""
 }
 
-func (c *accountsClient) Delete(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*DeleteAccountResponse, error) {
-	out := new(DeleteAccountResponse)
-	err := c.cc.Invoke(ctx, "/auth.Accounts/Delete", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *OAuthTokenResponse) GetTokenType() string {
+	if m != nil {
+		return m.TokenType
 	}
-	return out, nil
+	return ""
 }
 
-func (c *accountsClient) ChangeSecret(ctx context.Context, in *ChangeSecretRequest, opts ...grpc.CallOption) (*ChangeSecretResponse, error) {
-	out := new(ChangeSecretResponse)
-	err := c.cc.Invoke(ctx, "/auth.Accounts/ChangeSecret", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *OAuthTokenResponse) GetExpiresIn() int64 {
+	if m != nil {
+		return m.ExpiresIn
 	}
-	return out, nil
+	return 0
 }
 
-// AccountsServer is the server API for Accounts service.
-type AccountsServer interface {
-	List(context.Context, *ListAccountsRequest) (*ListAccountsResponse, error)
-	Delete(context.Context, *DeleteAccountRequest) (*DeleteAccountResponse, error)
-	ChangeSecret(context.Context, *ChangeSecretRequest) (*ChangeSecretResponse, error)
+func (m *OAuthTokenResponse) GetRefreshToken() string {
+	if m != nil {
+		return m.RefreshToken
+	}
+	return ""
 }
 
-func RegisterAccountsServer(s *grpc.Server, srv AccountsServer) {
+func (m *OAuthTokenResponse) GetScope() string {
+	if m != nil {
+		return m.Scope
+	}
+	return ""
+}
+
+func (m *OAuthTokenResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *OAuthTokenResponse) GetErrorDescription() string {
+	if m != nil {
+		return m.ErrorDescription
+	}
+	return ""
+}
+
+type Client struct {
+	Id                   string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string            `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes               []string          `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	Metadata             map[string]string `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *Client) Reset()         { *m = Client{} }
+func (m *Client) String() string { return proto.CompactTextString(m) }
+func (*Client) ProtoMessage()    {}
+func (*Client) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{31}
+}
+
+func (m *Client) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Client.Unmarshal(m, b)
+}
+func (m *Client) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Client.Marshal(b, m, deterministic)
+}
+func (m *Client) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Client.Merge(m, src)
+}
+func (m *Client) XXX_Size() int {
+	return xxx_messageInfo_Client.Size(m)
+}
+func (m *Client) XXX_DiscardUnknown() {
+	xxx_messageInfo_Client.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Client proto.InternalMessageInfo
+
+func (m *Client) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Client) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Client) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *Client) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+type CreateClientRequest struct {
+	Name                 string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes               []string          `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	Metadata             map[string]string `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Options              *Options          `protobuf:"bytes,4,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *CreateClientRequest) Reset()         { *m = CreateClientRequest{} }
+func (m *CreateClientRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateClientRequest) ProtoMessage()    {}
+func (*CreateClientRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{32}
+}
+
+func (m *CreateClientRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateClientRequest.Unmarshal(m, b)
+}
+func (m *CreateClientRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateClientRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateClientRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateClientRequest.Merge(m, src)
+}
+func (m *CreateClientRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateClientRequest.Size(m)
+}
+func (m *CreateClientRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateClientRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateClientRequest proto.InternalMessageInfo
+
+func (m *CreateClientRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CreateClientRequest) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *CreateClientRequest) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *CreateClientRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type CreateClientResponse struct {
+	Client *Client `protobuf:"bytes,1,opt,name=client,proto3" json:"client,omitempty"`
+	// the secret is only returned when the client is created
+	Secret               string   `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateClientResponse) Reset()         { *m = CreateClientResponse{} }
+func (m *CreateClientResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateClientResponse) ProtoMessage()    {}
+func (*CreateClientResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{33}
+}
+
+func (m *CreateClientResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateClientResponse.Unmarshal(m, b)
+}
+func (m *CreateClientResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateClientResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateClientResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateClientResponse.Merge(m, src)
+}
+func (m *CreateClientResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateClientResponse.Size(m)
+}
+func (m *CreateClientResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateClientResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateClientResponse proto.InternalMessageInfo
+
+func (m *CreateClientResponse) GetClient() *Client {
+	if m != nil {
+		return m.Client
+	}
+	return nil
+}
+
+func (m *CreateClientResponse) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+type ListClientsRequest struct {
+	Options              *Options `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListClientsRequest) Reset()         { *m = ListClientsRequest{} }
+func (m *ListClientsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListClientsRequest) ProtoMessage()    {}
+func (*ListClientsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{34}
+}
+
+func (m *ListClientsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListClientsRequest.Unmarshal(m, b)
+}
+func (m *ListClientsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListClientsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListClientsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListClientsRequest.Merge(m, src)
+}
+func (m *ListClientsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListClientsRequest.Size(m)
+}
+func (m *ListClientsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListClientsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListClientsRequest proto.InternalMessageInfo
+
+func (m *ListClientsRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type ListClientsResponse struct {
+	Clients              []*Client `protobuf:"bytes,1,rep,name=clients,proto3" json:"clients,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ListClientsResponse) Reset()         { *m = ListClientsResponse{} }
+func (m *ListClientsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListClientsResponse) ProtoMessage()    {}
+func (*ListClientsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{35}
+}
+
+func (m *ListClientsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListClientsResponse.Unmarshal(m, b)
+}
+func (m *ListClientsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListClientsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListClientsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListClientsResponse.Merge(m, src)
+}
+func (m *ListClientsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListClientsResponse.Size(m)
+}
+func (m *ListClientsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListClientsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListClientsResponse proto.InternalMessageInfo
+
+func (m *ListClientsResponse) GetClients() []*Client {
+	if m != nil {
+		return m.Clients
+	}
+	return nil
+}
+
+type DeleteClientRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteClientRequest) Reset()         { *m = DeleteClientRequest{} }
+func (m *DeleteClientRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteClientRequest) ProtoMessage()    {}
+func (*DeleteClientRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{36}
+}
+
+func (m *DeleteClientRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteClientRequest.Unmarshal(m, b)
+}
+func (m *DeleteClientRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteClientRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteClientRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteClientRequest.Merge(m, src)
+}
+func (m *DeleteClientRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteClientRequest.Size(m)
+}
+func (m *DeleteClientRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteClientRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteClientRequest proto.InternalMessageInfo
+
+func (m *DeleteClientRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *DeleteClientRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type DeleteClientResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteClientResponse) Reset()         { *m = DeleteClientResponse{} }
+func (m *DeleteClientResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteClientResponse) ProtoMessage()    {}
+func (*DeleteClientResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{37}
+}
+
+func (m *DeleteClientResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteClientResponse.Unmarshal(m, b)
+}
+func (m *DeleteClientResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteClientResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteClientResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteClientResponse.Merge(m, src)
+}
+func (m *DeleteClientResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteClientResponse.Size(m)
+}
+func (m *DeleteClientResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteClientResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteClientResponse proto.InternalMessageInfo
+
+// SCIMUser is a user provisioned by an identity provider, backed by an account
+type SCIMUser struct {
+	Id          string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExternalId  string   `protobuf:"bytes,2,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	UserName    string   `protobuf:"bytes,3,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	DisplayName string   `protobuf:"bytes,4,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Active      bool     `protobuf:"varint,5,opt,name=active,proto3" json:"active,omitempty"`
+	Emails      []string `protobuf:"bytes,6,rep,name=emails,proto3" json:"emails,omitempty"`
+	// ids of the groups the user is a member of, read only
+	Groups               []string `protobuf:"bytes,7,rep,name=groups,proto3" json:"groups,omitempty"`
+	Created              int64    `protobuf:"varint,8,opt,name=created,proto3" json:"created,omitempty"`
+	Updated              int64    `protobuf:"varint,9,opt,name=updated,proto3" json:"updated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SCIMUser) Reset()         { *m = SCIMUser{} }
+func (m *SCIMUser) String() string { return proto.CompactTextString(m) }
+func (*SCIMUser) ProtoMessage()    {}
+func (*SCIMUser) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{38}
+}
+
+func (m *SCIMUser) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SCIMUser.Unmarshal(m, b)
+}
+func (m *SCIMUser) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SCIMUser.Marshal(b, m, deterministic)
+}
+func (m *SCIMUser) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SCIMUser.Merge(m, src)
+}
+func (m *SCIMUser) XXX_Size() int {
+	return xxx_messageInfo_SCIMUser.Size(m)
+}
+func (m *SCIMUser) XXX_DiscardUnknown() {
+	xxx_messageInfo_SCIMUser.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SCIMUser proto.InternalMessageInfo
+
+func (m *SCIMUser) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *SCIMUser) GetExternalId() string {
+	if m != nil {
+		return m.ExternalId
+	}
+	return ""
+}
+
+func (m *SCIMUser) GetUserName() string {
+	if m != nil {
+		return m.UserName
+	}
+	return ""
+}
+
+func (m *SCIMUser) GetDisplayName() string {
+	if m != nil {
+		return m.DisplayName
+	}
+	return ""
+}
+
+func (m *SCIMUser) GetActive() bool {
+	if m != nil {
+		return m.Active
+	}
+	return false
+}
+
+func (m *SCIMUser) GetEmails() []string {
+	if m != nil {
+		return m.Emails
+	}
+	return nil
+}
+
+func (m *SCIMUser) GetGroups() []string {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+func (m *SCIMUser) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+func (m *SCIMUser) GetUpdated() int64 {
+	if m != nil {
+		return m.Updated
+	}
+	return 0
+}
+
+// SCIMGroup is a group of users, members are granted the scopes mapped to the group
+type SCIMGroup struct {
+	Id          string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExternalId  string `protobuf:"bytes,2,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	DisplayName string `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// ids of the member users
+	Members []string `protobuf:"bytes,4,rep,name=members,proto3" json:"members,omitempty"`
+	// scopes granted to members, set using MapGroup
+	Scopes               []string `protobuf:"bytes,5,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	Created              int64    `protobuf:"varint,6,opt,name=created,proto3" json:"created,omitempty"`
+	Updated              int64    `protobuf:"varint,7,opt,name=updated,proto3" json:"updated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SCIMGroup) Reset()         { *m = SCIMGroup{} }
+func (m *SCIMGroup) String() string { return proto.CompactTextString(m) }
+func (*SCIMGroup) ProtoMessage()    {}
+func (*SCIMGroup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{39}
+}
+
+func (m *SCIMGroup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SCIMGroup.Unmarshal(m, b)
+}
+func (m *SCIMGroup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SCIMGroup.Marshal(b, m, deterministic)
+}
+func (m *SCIMGroup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SCIMGroup.Merge(m, src)
+}
+func (m *SCIMGroup) XXX_Size() int {
+	return xxx_messageInfo_SCIMGroup.Size(m)
+}
+func (m *SCIMGroup) XXX_DiscardUnknown() {
+	xxx_messageInfo_SCIMGroup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SCIMGroup proto.InternalMessageInfo
+
+func (m *SCIMGroup) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *SCIMGroup) GetExternalId() string {
+	if m != nil {
+		return m.ExternalId
+	}
+	return ""
+}
+
+func (m *SCIMGroup) GetDisplayName() string {
+	if m != nil {
+		return m.DisplayName
+	}
+	return ""
+}
+
+func (m *SCIMGroup) GetMembers() []string {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+func (m *SCIMGroup) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *SCIMGroup) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+func (m *SCIMGroup) GetUpdated() int64 {
+	if m != nil {
+		return m.Updated
+	}
+	return 0
+}
+
+type SCIMUserRequest struct {
+	User                 *SCIMUser `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Options              *Options  `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *SCIMUserRequest) Reset()         { *m = SCIMUserRequest{} }
+func (m *SCIMUserRequest) String() string { return proto.CompactTextString(m) }
+func (*SCIMUserRequest) ProtoMessage()    {}
+func (*SCIMUserRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{40}
+}
+
+func (m *SCIMUserRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SCIMUserRequest.Unmarshal(m, b)
+}
+func (m *SCIMUserRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SCIMUserRequest.Marshal(b, m, deterministic)
+}
+func (m *SCIMUserRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SCIMUserRequest.Merge(m, src)
+}
+func (m *SCIMUserRequest) XXX_Size() int {
+	return xxx_messageInfo_SCIMUserRequest.Size(m)
+}
+func (m *SCIMUserRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SCIMUserRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SCIMUserRequest proto.InternalMessageInfo
+
+func (m *SCIMUserRequest) GetUser() *SCIMUser {
+	if m != nil {
+		return m.User
+	}
+	return nil
+}
+
+func (m *SCIMUserRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type SCIMUserResponse struct {
+	User                 *SCIMUser `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *SCIMUserResponse) Reset()         { *m = SCIMUserResponse{} }
+func (m *SCIMUserResponse) String() string { return proto.CompactTextString(m) }
+func (*SCIMUserResponse) ProtoMessage()    {}
+func (*SCIMUserResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{41}
+}
+
+func (m *SCIMUserResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SCIMUserResponse.Unmarshal(m, b)
+}
+func (m *SCIMUserResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SCIMUserResponse.Marshal(b, m, deterministic)
+}
+func (m *SCIMUserResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SCIMUserResponse.Merge(m, src)
+}
+func (m *SCIMUserResponse) XXX_Size() int {
+	return xxx_messageInfo_SCIMUserResponse.Size(m)
+}
+func (m *SCIMUserResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SCIMUserResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SCIMUserResponse proto.InternalMessageInfo
+
+func (m *SCIMUserResponse) GetUser() *SCIMUser {
+	if m != nil {
+		return m.User
+	}
+	return nil
+}
+
+type ReadSCIMUserRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadSCIMUserRequest) Reset()         { *m = ReadSCIMUserRequest{} }
+func (m *ReadSCIMUserRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadSCIMUserRequest) ProtoMessage()    {}
+func (*ReadSCIMUserRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{42}
+}
+
+func (m *ReadSCIMUserRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadSCIMUserRequest.Unmarshal(m, b)
+}
+func (m *ReadSCIMUserRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadSCIMUserRequest.Marshal(b, m, deterministic)
+}
+func (m *ReadSCIMUserRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadSCIMUserRequest.Merge(m, src)
+}
+func (m *ReadSCIMUserRequest) XXX_Size() int {
+	return xxx_messageInfo_ReadSCIMUserRequest.Size(m)
+}
+func (m *ReadSCIMUserRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadSCIMUserRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadSCIMUserRequest proto.InternalMessageInfo
+
+func (m *ReadSCIMUserRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ReadSCIMUserRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type DeleteSCIMUserRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteSCIMUserRequest) Reset()         { *m = DeleteSCIMUserRequest{} }
+func (m *DeleteSCIMUserRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteSCIMUserRequest) ProtoMessage()    {}
+func (*DeleteSCIMUserRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{43}
+}
+
+func (m *DeleteSCIMUserRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteSCIMUserRequest.Unmarshal(m, b)
+}
+func (m *DeleteSCIMUserRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteSCIMUserRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteSCIMUserRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteSCIMUserRequest.Merge(m, src)
+}
+func (m *DeleteSCIMUserRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteSCIMUserRequest.Size(m)
+}
+func (m *DeleteSCIMUserRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteSCIMUserRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteSCIMUserRequest proto.InternalMessageInfo
+
+func (m *DeleteSCIMUserRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *DeleteSCIMUserRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type DeleteSCIMUserResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteSCIMUserResponse) Reset()         { *m = DeleteSCIMUserResponse{} }
+func (m *DeleteSCIMUserResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteSCIMUserResponse) ProtoMessage()    {}
+func (*DeleteSCIMUserResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{44}
+}
+
+func (m *DeleteSCIMUserResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteSCIMUserResponse.Unmarshal(m, b)
+}
+func (m *DeleteSCIMUserResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteSCIMUserResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteSCIMUserResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteSCIMUserResponse.Merge(m, src)
+}
+func (m *DeleteSCIMUserResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteSCIMUserResponse.Size(m)
+}
+func (m *DeleteSCIMUserResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteSCIMUserResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteSCIMUserResponse proto.InternalMessageInfo
+
+type ListSCIMUsersRequest struct {
+	// filter by user name, the only filter supported
+	UserName string `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	// 1 based index of the first result
+	StartIndex           int64    `protobuf:"varint,2,opt,name=start_index,json=startIndex,proto3" json:"start_index,omitempty"`
+	Count                int64    `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	Options              *Options `protobuf:"bytes,4,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSCIMUsersRequest) Reset()         { *m = ListSCIMUsersRequest{} }
+func (m *ListSCIMUsersRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSCIMUsersRequest) ProtoMessage()    {}
+func (*ListSCIMUsersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{45}
+}
+
+func (m *ListSCIMUsersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSCIMUsersRequest.Unmarshal(m, b)
+}
+func (m *ListSCIMUsersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSCIMUsersRequest.Marshal(b, m, deterministic)
+}
+func (m *ListSCIMUsersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSCIMUsersRequest.Merge(m, src)
+}
+func (m *ListSCIMUsersRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSCIMUsersRequest.Size(m)
+}
+func (m *ListSCIMUsersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSCIMUsersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSCIMUsersRequest proto.InternalMessageInfo
+
+func (m *ListSCIMUsersRequest) GetUserName() string {
+	if m != nil {
+		return m.UserName
+	}
+	return ""
+}
+
+func (m *ListSCIMUsersRequest) GetStartIndex() int64 {
+	if m != nil {
+		return m.StartIndex
+	}
+	return 0
+}
+
+func (m *ListSCIMUsersRequest) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *ListSCIMUsersRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type ListSCIMUsersResponse struct {
+	Users                []*SCIMUser `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	Total                int64       `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ListSCIMUsersResponse) Reset()         { *m = ListSCIMUsersResponse{} }
+func (m *ListSCIMUsersResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSCIMUsersResponse) ProtoMessage()    {}
+func (*ListSCIMUsersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{46}
+}
+
+func (m *ListSCIMUsersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSCIMUsersResponse.Unmarshal(m, b)
+}
+func (m *ListSCIMUsersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSCIMUsersResponse.Marshal(b, m, deterministic)
+}
+func (m *ListSCIMUsersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSCIMUsersResponse.Merge(m, src)
+}
+func (m *ListSCIMUsersResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSCIMUsersResponse.Size(m)
+}
+func (m *ListSCIMUsersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSCIMUsersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSCIMUsersResponse proto.InternalMessageInfo
+
+func (m *ListSCIMUsersResponse) GetUsers() []*SCIMUser {
+	if m != nil {
+		return m.Users
+	}
+	return nil
+}
+
+func (m *ListSCIMUsersResponse) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+type SCIMGroupRequest struct {
+	Group                *SCIMGroup `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Options              *Options   `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *SCIMGroupRequest) Reset()         { *m = SCIMGroupRequest{} }
+func (m *SCIMGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*SCIMGroupRequest) ProtoMessage()    {}
+func (*SCIMGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{47}
+}
+
+func (m *SCIMGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SCIMGroupRequest.Unmarshal(m, b)
+}
+func (m *SCIMGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SCIMGroupRequest.Marshal(b, m, deterministic)
+}
+func (m *SCIMGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SCIMGroupRequest.Merge(m, src)
+}
+func (m *SCIMGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_SCIMGroupRequest.Size(m)
+}
+func (m *SCIMGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SCIMGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SCIMGroupRequest proto.InternalMessageInfo
+
+func (m *SCIMGroupRequest) GetGroup() *SCIMGroup {
+	if m != nil {
+		return m.Group
+	}
+	return nil
+}
+
+func (m *SCIMGroupRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type SCIMGroupResponse struct {
+	Group                *SCIMGroup `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *SCIMGroupResponse) Reset()         { *m = SCIMGroupResponse{} }
+func (m *SCIMGroupResponse) String() string { return proto.CompactTextString(m) }
+func (*SCIMGroupResponse) ProtoMessage()    {}
+func (*SCIMGroupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{48}
+}
+
+func (m *SCIMGroupResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SCIMGroupResponse.Unmarshal(m, b)
+}
+func (m *SCIMGroupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SCIMGroupResponse.Marshal(b, m, deterministic)
+}
+func (m *SCIMGroupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SCIMGroupResponse.Merge(m, src)
+}
+func (m *SCIMGroupResponse) XXX_Size() int {
+	return xxx_messageInfo_SCIMGroupResponse.Size(m)
+}
+func (m *SCIMGroupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SCIMGroupResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SCIMGroupResponse proto.InternalMessageInfo
+
+func (m *SCIMGroupResponse) GetGroup() *SCIMGroup {
+	if m != nil {
+		return m.Group
+	}
+	return nil
+}
+
+type ReadSCIMGroupRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadSCIMGroupRequest) Reset()         { *m = ReadSCIMGroupRequest{} }
+func (m *ReadSCIMGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadSCIMGroupRequest) ProtoMessage()    {}
+func (*ReadSCIMGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{49}
+}
+
+func (m *ReadSCIMGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadSCIMGroupRequest.Unmarshal(m, b)
+}
+func (m *ReadSCIMGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadSCIMGroupRequest.Marshal(b, m, deterministic)
+}
+func (m *ReadSCIMGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadSCIMGroupRequest.Merge(m, src)
+}
+func (m *ReadSCIMGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_ReadSCIMGroupRequest.Size(m)
+}
+func (m *ReadSCIMGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadSCIMGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadSCIMGroupRequest proto.InternalMessageInfo
+
+func (m *ReadSCIMGroupRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ReadSCIMGroupRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type DeleteSCIMGroupRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteSCIMGroupRequest) Reset()         { *m = DeleteSCIMGroupRequest{} }
+func (m *DeleteSCIMGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteSCIMGroupRequest) ProtoMessage()    {}
+func (*DeleteSCIMGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{50}
+}
+
+func (m *DeleteSCIMGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteSCIMGroupRequest.Unmarshal(m, b)
+}
+func (m *DeleteSCIMGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteSCIMGroupRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteSCIMGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteSCIMGroupRequest.Merge(m, src)
+}
+func (m *DeleteSCIMGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteSCIMGroupRequest.Size(m)
+}
+func (m *DeleteSCIMGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteSCIMGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteSCIMGroupRequest proto.InternalMessageInfo
+
+func (m *DeleteSCIMGroupRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *DeleteSCIMGroupRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type DeleteSCIMGroupResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteSCIMGroupResponse) Reset()         { *m = DeleteSCIMGroupResponse{} }
+func (m *DeleteSCIMGroupResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteSCIMGroupResponse) ProtoMessage()    {}
+func (*DeleteSCIMGroupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{51}
+}
+
+func (m *DeleteSCIMGroupResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteSCIMGroupResponse.Unmarshal(m, b)
+}
+func (m *DeleteSCIMGroupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteSCIMGroupResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteSCIMGroupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteSCIMGroupResponse.Merge(m, src)
+}
+func (m *DeleteSCIMGroupResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteSCIMGroupResponse.Size(m)
+}
+func (m *DeleteSCIMGroupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteSCIMGroupResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteSCIMGroupResponse proto.InternalMessageInfo
+
+type ListSCIMGroupsRequest struct {
+	// filter by display name, the only filter supported
+	DisplayName string `protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// 1 based index of the first result
+	StartIndex           int64    `protobuf:"varint,2,opt,name=start_index,json=startIndex,proto3" json:"start_index,omitempty"`
+	Count                int64    `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	Options              *Options `protobuf:"bytes,4,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSCIMGroupsRequest) Reset()         { *m = ListSCIMGroupsRequest{} }
+func (m *ListSCIMGroupsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSCIMGroupsRequest) ProtoMessage()    {}
+func (*ListSCIMGroupsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{52}
+}
+
+func (m *ListSCIMGroupsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSCIMGroupsRequest.Unmarshal(m, b)
+}
+func (m *ListSCIMGroupsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSCIMGroupsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListSCIMGroupsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSCIMGroupsRequest.Merge(m, src)
+}
+func (m *ListSCIMGroupsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSCIMGroupsRequest.Size(m)
+}
+func (m *ListSCIMGroupsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSCIMGroupsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSCIMGroupsRequest proto.InternalMessageInfo
+
+func (m *ListSCIMGroupsRequest) GetDisplayName() string {
+	if m != nil {
+		return m.DisplayName
+	}
+	return ""
+}
+
+func (m *ListSCIMGroupsRequest) GetStartIndex() int64 {
+	if m != nil {
+		return m.StartIndex
+	}
+	return 0
+}
+
+func (m *ListSCIMGroupsRequest) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *ListSCIMGroupsRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type ListSCIMGroupsResponse struct {
+	Groups               []*SCIMGroup `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	Total                int64        `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ListSCIMGroupsResponse) Reset()         { *m = ListSCIMGroupsResponse{} }
+func (m *ListSCIMGroupsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSCIMGroupsResponse) ProtoMessage()    {}
+func (*ListSCIMGroupsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{53}
+}
+
+func (m *ListSCIMGroupsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSCIMGroupsResponse.Unmarshal(m, b)
+}
+func (m *ListSCIMGroupsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSCIMGroupsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListSCIMGroupsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSCIMGroupsResponse.Merge(m, src)
+}
+func (m *ListSCIMGroupsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSCIMGroupsResponse.Size(m)
+}
+func (m *ListSCIMGroupsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSCIMGroupsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSCIMGroupsResponse proto.InternalMessageInfo
+
+func (m *ListSCIMGroupsResponse) GetGroups() []*SCIMGroup {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+func (m *ListSCIMGroupsResponse) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+type MapSCIMGroupRequest struct {
+	// id or display name of the group
+	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Scopes               []string `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	Options              *Options `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MapSCIMGroupRequest) Reset()         { *m = MapSCIMGroupRequest{} }
+func (m *MapSCIMGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*MapSCIMGroupRequest) ProtoMessage()    {}
+func (*MapSCIMGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{54}
+}
+
+func (m *MapSCIMGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MapSCIMGroupRequest.Unmarshal(m, b)
+}
+func (m *MapSCIMGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MapSCIMGroupRequest.Marshal(b, m, deterministic)
+}
+func (m *MapSCIMGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MapSCIMGroupRequest.Merge(m, src)
+}
+func (m *MapSCIMGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_MapSCIMGroupRequest.Size(m)
+}
+func (m *MapSCIMGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MapSCIMGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MapSCIMGroupRequest proto.InternalMessageInfo
+
+func (m *MapSCIMGroupRequest) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *MapSCIMGroupRequest) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *MapSCIMGroupRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type MapSCIMGroupResponse struct {
+	Group                *SCIMGroup `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *MapSCIMGroupResponse) Reset()         { *m = MapSCIMGroupResponse{} }
+func (m *MapSCIMGroupResponse) String() string { return proto.CompactTextString(m) }
+func (*MapSCIMGroupResponse) ProtoMessage()    {}
+func (*MapSCIMGroupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{55}
+}
+
+func (m *MapSCIMGroupResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MapSCIMGroupResponse.Unmarshal(m, b)
+}
+func (m *MapSCIMGroupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MapSCIMGroupResponse.Marshal(b, m, deterministic)
+}
+func (m *MapSCIMGroupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MapSCIMGroupResponse.Merge(m, src)
+}
+func (m *MapSCIMGroupResponse) XXX_Size() int {
+	return xxx_messageInfo_MapSCIMGroupResponse.Size(m)
+}
+func (m *MapSCIMGroupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MapSCIMGroupResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MapSCIMGroupResponse proto.InternalMessageInfo
+
+func (m *MapSCIMGroupResponse) GetGroup() *SCIMGroup {
+	if m != nil {
+		return m.Group
+	}
+	return nil
+}
+
+// Session is a browser session, the tokens backing it are kept by the auth service and never
+// returned in a listing
+type Session struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AccountId            string   `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Created              int64    `protobuf:"varint,3,opt,name=created,proto3" json:"created,omitempty"`
+	LastSeen             int64    `protobuf:"varint,4,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	Expiry               int64    `protobuf:"varint,5,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	UserAgent            string   `protobuf:"bytes,6,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	Ip                   string   `protobuf:"bytes,7,opt,name=ip,proto3" json:"ip,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Session) Reset()         { *m = Session{} }
+func (m *Session) String() string { return proto.CompactTextString(m) }
+func (*Session) ProtoMessage()    {}
+func (*Session) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{56}
+}
+
+func (m *Session) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Session.Unmarshal(m, b)
+}
+func (m *Session) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Session.Marshal(b, m, deterministic)
+}
+func (m *Session) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Session.Merge(m, src)
+}
+func (m *Session) XXX_Size() int {
+	return xxx_messageInfo_Session.Size(m)
+}
+func (m *Session) XXX_DiscardUnknown() {
+	xxx_messageInfo_Session.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Session proto.InternalMessageInfo
+
+func (m *Session) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Session) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+func (m *Session) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+func (m *Session) GetLastSeen() int64 {
+	if m != nil {
+		return m.LastSeen
+	}
+	return 0
+}
+
+func (m *Session) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
+func (m *Session) GetUserAgent() string {
+	if m != nil {
+		return m.UserAgent
+	}
+	return ""
+}
+
+func (m *Session) GetIp() string {
+	if m != nil {
+		return m.Ip
+	}
+	return ""
+}
+
+type CreateSessionRequest struct {
+	// id or name of the account
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Secret               string   `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	UserAgent            string   `protobuf:"bytes,3,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	Ip                   string   `protobuf:"bytes,4,opt,name=ip,proto3" json:"ip,omitempty"`
+	Options              *Options `protobuf:"bytes,5,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateSessionRequest) Reset()         { *m = CreateSessionRequest{} }
+func (m *CreateSessionRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateSessionRequest) ProtoMessage()    {}
+func (*CreateSessionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{57}
+}
+
+func (m *CreateSessionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateSessionRequest.Unmarshal(m, b)
+}
+func (m *CreateSessionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateSessionRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateSessionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateSessionRequest.Merge(m, src)
+}
+func (m *CreateSessionRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateSessionRequest.Size(m)
+}
+func (m *CreateSessionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateSessionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateSessionRequest proto.InternalMessageInfo
+
+func (m *CreateSessionRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *CreateSessionRequest) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *CreateSessionRequest) GetUserAgent() string {
+	if m != nil {
+		return m.UserAgent
+	}
+	return ""
+}
+
+func (m *CreateSessionRequest) GetIp() string {
+	if m != nil {
+		return m.Ip
+	}
+	return ""
+}
+
+func (m *CreateSessionRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type CreateSessionResponse struct {
+	Session *Session `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	// key is the opaque value stored in the session cookie
+	Key                  string   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	CsrfToken            string   `protobuf:"bytes,3,opt,name=csrf_token,json=csrfToken,proto3" json:"csrf_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateSessionResponse) Reset()         { *m = CreateSessionResponse{} }
+func (m *CreateSessionResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateSessionResponse) ProtoMessage()    {}
+func (*CreateSessionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{58}
+}
+
+func (m *CreateSessionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateSessionResponse.Unmarshal(m, b)
+}
+func (m *CreateSessionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateSessionResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateSessionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateSessionResponse.Merge(m, src)
+}
+func (m *CreateSessionResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateSessionResponse.Size(m)
+}
+func (m *CreateSessionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateSessionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateSessionResponse proto.InternalMessageInfo
+
+func (m *CreateSessionResponse) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *CreateSessionResponse) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *CreateSessionResponse) GetCsrfToken() string {
+	if m != nil {
+		return m.CsrfToken
+	}
+	return ""
+}
+
+type RefreshSessionRequest struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RefreshSessionRequest) Reset()         { *m = RefreshSessionRequest{} }
+func (m *RefreshSessionRequest) String() string { return proto.CompactTextString(m) }
+func (*RefreshSessionRequest) ProtoMessage()    {}
+func (*RefreshSessionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{59}
+}
+
+func (m *RefreshSessionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RefreshSessionRequest.Unmarshal(m, b)
+}
+func (m *RefreshSessionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RefreshSessionRequest.Marshal(b, m, deterministic)
+}
+func (m *RefreshSessionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RefreshSessionRequest.Merge(m, src)
+}
+func (m *RefreshSessionRequest) XXX_Size() int {
+	return xxx_messageInfo_RefreshSessionRequest.Size(m)
+}
+func (m *RefreshSessionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RefreshSessionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RefreshSessionRequest proto.InternalMessageInfo
+
+func (m *RefreshSessionRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *RefreshSessionRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type RefreshSessionResponse struct {
+	Session   *Session `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	CsrfToken string   `protobuf:"bytes,2,opt,name=csrf_token,json=csrfToken,proto3" json:"csrf_token,omitempty"`
+	// access_token is a valid access token for the session's account
+	AccessToken          string   `protobuf:"bytes,3,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RefreshSessionResponse) Reset()         { *m = RefreshSessionResponse{} }
+func (m *RefreshSessionResponse) String() string { return proto.CompactTextString(m) }
+func (*RefreshSessionResponse) ProtoMessage()    {}
+func (*RefreshSessionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{60}
+}
+
+func (m *RefreshSessionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RefreshSessionResponse.Unmarshal(m, b)
+}
+func (m *RefreshSessionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RefreshSessionResponse.Marshal(b, m, deterministic)
+}
+func (m *RefreshSessionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RefreshSessionResponse.Merge(m, src)
+}
+func (m *RefreshSessionResponse) XXX_Size() int {
+	return xxx_messageInfo_RefreshSessionResponse.Size(m)
+}
+func (m *RefreshSessionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RefreshSessionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RefreshSessionResponse proto.InternalMessageInfo
+
+func (m *RefreshSessionResponse) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *RefreshSessionResponse) GetCsrfToken() string {
+	if m != nil {
+		return m.CsrfToken
+	}
+	return ""
+}
+
+func (m *RefreshSessionResponse) GetAccessToken() string {
+	if m != nil {
+		return m.AccessToken
+	}
+	return ""
+}
+
+type ListSessionsRequest struct {
+	AccountId            string   `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSessionsRequest) Reset()         { *m = ListSessionsRequest{} }
+func (m *ListSessionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSessionsRequest) ProtoMessage()    {}
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{61}
+}
+
+func (m *ListSessionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSessionsRequest.Unmarshal(m, b)
+}
+func (m *ListSessionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSessionsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListSessionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSessionsRequest.Merge(m, src)
+}
+func (m *ListSessionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSessionsRequest.Size(m)
+}
+func (m *ListSessionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSessionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSessionsRequest proto.InternalMessageInfo
+
+func (m *ListSessionsRequest) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+func (m *ListSessionsRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type ListSessionsResponse struct {
+	Sessions             []*Session `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ListSessionsResponse) Reset()         { *m = ListSessionsResponse{} }
+func (m *ListSessionsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSessionsResponse) ProtoMessage()    {}
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{62}
+}
+
+func (m *ListSessionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSessionsResponse.Unmarshal(m, b)
+}
+func (m *ListSessionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSessionsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListSessionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSessionsResponse.Merge(m, src)
+}
+func (m *ListSessionsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSessionsResponse.Size(m)
+}
+func (m *ListSessionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSessionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSessionsResponse proto.InternalMessageInfo
+
+func (m *ListSessionsResponse) GetSessions() []*Session {
+	if m != nil {
+		return m.Sessions
+	}
+	return nil
+}
+
+// RevokeSessionRequest revokes a single session by id or key, or all the sessions for an account
+type RevokeSessionRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Key                  string   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	AccountId            string   `protobuf:"bytes,3,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Options              *Options `protobuf:"bytes,4,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeSessionRequest) Reset()         { *m = RevokeSessionRequest{} }
+func (m *RevokeSessionRequest) String() string { return proto.CompactTextString(m) }
+func (*RevokeSessionRequest) ProtoMessage()    {}
+func (*RevokeSessionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{63}
+}
+
+func (m *RevokeSessionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeSessionRequest.Unmarshal(m, b)
+}
+func (m *RevokeSessionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeSessionRequest.Marshal(b, m, deterministic)
+}
+func (m *RevokeSessionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeSessionRequest.Merge(m, src)
+}
+func (m *RevokeSessionRequest) XXX_Size() int {
+	return xxx_messageInfo_RevokeSessionRequest.Size(m)
+}
+func (m *RevokeSessionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeSessionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeSessionRequest proto.InternalMessageInfo
+
+func (m *RevokeSessionRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RevokeSessionRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *RevokeSessionRequest) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+func (m *RevokeSessionRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type RevokeSessionResponse struct {
+	Revoked              int64    `protobuf:"varint,1,opt,name=revoked,proto3" json:"revoked,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeSessionResponse) Reset()         { *m = RevokeSessionResponse{} }
+func (m *RevokeSessionResponse) String() string { return proto.CompactTextString(m) }
+func (*RevokeSessionResponse) ProtoMessage()    {}
+func (*RevokeSessionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{64}
+}
+
+func (m *RevokeSessionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeSessionResponse.Unmarshal(m, b)
+}
+func (m *RevokeSessionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeSessionResponse.Marshal(b, m, deterministic)
+}
+func (m *RevokeSessionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeSessionResponse.Merge(m, src)
+}
+func (m *RevokeSessionResponse) XXX_Size() int {
+	return xxx_messageInfo_RevokeSessionResponse.Size(m)
+}
+func (m *RevokeSessionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeSessionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeSessionResponse proto.InternalMessageInfo
+
+func (m *RevokeSessionResponse) GetRevoked() int64 {
+	if m != nil {
+		return m.Revoked
+	}
+	return 0
+}
+
+type IssueCertificateRequest struct {
+	// PEM encoded certificate signing request, the certificate is issued to the account making the
+	// request regardless of the subject requested
+	Csr                  string   `protobuf:"bytes,1,opt,name=csr,proto3" json:"csr,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IssueCertificateRequest) Reset()         { *m = IssueCertificateRequest{} }
+func (m *IssueCertificateRequest) String() string { return proto.CompactTextString(m) }
+func (*IssueCertificateRequest) ProtoMessage()    {}
+func (*IssueCertificateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{65}
+}
+
+func (m *IssueCertificateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IssueCertificateRequest.Unmarshal(m, b)
+}
+func (m *IssueCertificateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IssueCertificateRequest.Marshal(b, m, deterministic)
+}
+func (m *IssueCertificateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IssueCertificateRequest.Merge(m, src)
+}
+func (m *IssueCertificateRequest) XXX_Size() int {
+	return xxx_messageInfo_IssueCertificateRequest.Size(m)
+}
+func (m *IssueCertificateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_IssueCertificateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IssueCertificateRequest proto.InternalMessageInfo
+
+func (m *IssueCertificateRequest) GetCsr() string {
+	if m != nil {
+		return m.Csr
+	}
+	return ""
+}
+
+func (m *IssueCertificateRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type IssueCertificateResponse struct {
+	// PEM encoded certificate
+	Certificate string `protobuf:"bytes,1,opt,name=certificate,proto3" json:"certificate,omitempty"`
+	// PEM encoded certificate of the authority which issued it
+	Authority string `protobuf:"bytes,2,opt,name=authority,proto3" json:"authority,omitempty"`
+	// unix timestamp of when the certificate expires
+	Expiry               int64    `protobuf:"varint,3,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IssueCertificateResponse) Reset()         { *m = IssueCertificateResponse{} }
+func (m *IssueCertificateResponse) String() string { return proto.CompactTextString(m) }
+func (*IssueCertificateResponse) ProtoMessage()    {}
+func (*IssueCertificateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{66}
+}
+
+func (m *IssueCertificateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IssueCertificateResponse.Unmarshal(m, b)
+}
+func (m *IssueCertificateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IssueCertificateResponse.Marshal(b, m, deterministic)
+}
+func (m *IssueCertificateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IssueCertificateResponse.Merge(m, src)
+}
+func (m *IssueCertificateResponse) XXX_Size() int {
+	return xxx_messageInfo_IssueCertificateResponse.Size(m)
+}
+func (m *IssueCertificateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_IssueCertificateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IssueCertificateResponse proto.InternalMessageInfo
+
+func (m *IssueCertificateResponse) GetCertificate() string {
+	if m != nil {
+		return m.Certificate
+	}
+	return ""
+}
+
+func (m *IssueCertificateResponse) GetAuthority() string {
+	if m != nil {
+		return m.Authority
+	}
+	return ""
+}
+
+func (m *IssueCertificateResponse) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
+// APIKey is a long lived credential of an account for machine clients, it grants a subset of the
+// scopes of the account
+type APIKey struct {
+	Id        string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AccountId string   `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Name      string   `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes    []string `protobuf:"bytes,4,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// unix timestamp of when the key was created
+	Created int64 `protobuf:"varint,5,opt,name=created,proto3" json:"created,omitempty"`
+	// unix timestamp of when the key expires, zero if it doesn't
+	Expiry               int64    `protobuf:"varint,6,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *APIKey) Reset()         { *m = APIKey{} }
+func (m *APIKey) String() string { return proto.CompactTextString(m) }
+func (*APIKey) ProtoMessage()    {}
+func (*APIKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{67}
+}
+
+func (m *APIKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_APIKey.Unmarshal(m, b)
+}
+func (m *APIKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_APIKey.Marshal(b, m, deterministic)
+}
+func (m *APIKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_APIKey.Merge(m, src)
+}
+func (m *APIKey) XXX_Size() int {
+	return xxx_messageInfo_APIKey.Size(m)
+}
+func (m *APIKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_APIKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_APIKey proto.InternalMessageInfo
+
+func (m *APIKey) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *APIKey) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+func (m *APIKey) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *APIKey) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *APIKey) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+func (m *APIKey) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
+type CreateAPIKeyRequest struct {
+	// the account the key is issued to, defaults to the account making the request
+	AccountId string `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Name      string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// the scopes of the key, which must be granted to the account. defaults to the account scopes
+	Scopes []string `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// unix timestamp of when the key expires, zero if it doesn't
+	Expiry               int64    `protobuf:"varint,4,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	Options              *Options `protobuf:"bytes,5,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateAPIKeyRequest) Reset()         { *m = CreateAPIKeyRequest{} }
+func (m *CreateAPIKeyRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateAPIKeyRequest) ProtoMessage()    {}
+func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{68}
+}
+
+func (m *CreateAPIKeyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateAPIKeyRequest.Unmarshal(m, b)
+}
+func (m *CreateAPIKeyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateAPIKeyRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateAPIKeyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateAPIKeyRequest.Merge(m, src)
+}
+func (m *CreateAPIKeyRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateAPIKeyRequest.Size(m)
+}
+func (m *CreateAPIKeyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateAPIKeyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateAPIKeyRequest proto.InternalMessageInfo
+
+func (m *CreateAPIKeyRequest) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+func (m *CreateAPIKeyRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CreateAPIKeyRequest) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *CreateAPIKeyRequest) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
+func (m *CreateAPIKeyRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type CreateAPIKeyResponse struct {
+	ApiKey *APIKey `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// the key is only returned when it's created
+	Key                  string   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateAPIKeyResponse) Reset()         { *m = CreateAPIKeyResponse{} }
+func (m *CreateAPIKeyResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateAPIKeyResponse) ProtoMessage()    {}
+func (*CreateAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{69}
+}
+
+func (m *CreateAPIKeyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateAPIKeyResponse.Unmarshal(m, b)
+}
+func (m *CreateAPIKeyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateAPIKeyResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateAPIKeyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateAPIKeyResponse.Merge(m, src)
+}
+func (m *CreateAPIKeyResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateAPIKeyResponse.Size(m)
+}
+func (m *CreateAPIKeyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateAPIKeyResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateAPIKeyResponse proto.InternalMessageInfo
+
+func (m *CreateAPIKeyResponse) GetApiKey() *APIKey {
+	if m != nil {
+		return m.ApiKey
+	}
+	return nil
+}
+
+func (m *CreateAPIKeyResponse) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+type ListAPIKeysRequest struct {
+	// the account to list the keys of, defaults to the account making the request
+	AccountId            string   `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListAPIKeysRequest) Reset()         { *m = ListAPIKeysRequest{} }
+func (m *ListAPIKeysRequest) String() string { return proto.CompactTextString(m) }
+func (*ListAPIKeysRequest) ProtoMessage()    {}
+func (*ListAPIKeysRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{70}
+}
+
+func (m *ListAPIKeysRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAPIKeysRequest.Unmarshal(m, b)
+}
+func (m *ListAPIKeysRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAPIKeysRequest.Marshal(b, m, deterministic)
+}
+func (m *ListAPIKeysRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAPIKeysRequest.Merge(m, src)
+}
+func (m *ListAPIKeysRequest) XXX_Size() int {
+	return xxx_messageInfo_ListAPIKeysRequest.Size(m)
+}
+func (m *ListAPIKeysRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAPIKeysRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAPIKeysRequest proto.InternalMessageInfo
+
+func (m *ListAPIKeysRequest) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+func (m *ListAPIKeysRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type ListAPIKeysResponse struct {
+	ApiKeys              []*APIKey `protobuf:"bytes,1,rep,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ListAPIKeysResponse) Reset()         { *m = ListAPIKeysResponse{} }
+func (m *ListAPIKeysResponse) String() string { return proto.CompactTextString(m) }
+func (*ListAPIKeysResponse) ProtoMessage()    {}
+func (*ListAPIKeysResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{71}
+}
+
+func (m *ListAPIKeysResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAPIKeysResponse.Unmarshal(m, b)
+}
+func (m *ListAPIKeysResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAPIKeysResponse.Marshal(b, m, deterministic)
+}
+func (m *ListAPIKeysResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAPIKeysResponse.Merge(m, src)
+}
+func (m *ListAPIKeysResponse) XXX_Size() int {
+	return xxx_messageInfo_ListAPIKeysResponse.Size(m)
+}
+func (m *ListAPIKeysResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAPIKeysResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAPIKeysResponse proto.InternalMessageInfo
+
+func (m *ListAPIKeysResponse) GetApiKeys() []*APIKey {
+	if m != nil {
+		return m.ApiKeys
+	}
+	return nil
+}
+
+type RevokeAPIKeyRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeAPIKeyRequest) Reset()         { *m = RevokeAPIKeyRequest{} }
+func (m *RevokeAPIKeyRequest) String() string { return proto.CompactTextString(m) }
+func (*RevokeAPIKeyRequest) ProtoMessage()    {}
+func (*RevokeAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{72}
+}
+
+func (m *RevokeAPIKeyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeAPIKeyRequest.Unmarshal(m, b)
+}
+func (m *RevokeAPIKeyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeAPIKeyRequest.Marshal(b, m, deterministic)
+}
+func (m *RevokeAPIKeyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeAPIKeyRequest.Merge(m, src)
+}
+func (m *RevokeAPIKeyRequest) XXX_Size() int {
+	return xxx_messageInfo_RevokeAPIKeyRequest.Size(m)
+}
+func (m *RevokeAPIKeyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeAPIKeyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeAPIKeyRequest proto.InternalMessageInfo
+
+func (m *RevokeAPIKeyRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RevokeAPIKeyRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type RevokeAPIKeyResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeAPIKeyResponse) Reset()         { *m = RevokeAPIKeyResponse{} }
+func (m *RevokeAPIKeyResponse) String() string { return proto.CompactTextString(m) }
+func (*RevokeAPIKeyResponse) ProtoMessage()    {}
+func (*RevokeAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{73}
+}
+
+func (m *RevokeAPIKeyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeAPIKeyResponse.Unmarshal(m, b)
+}
+func (m *RevokeAPIKeyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeAPIKeyResponse.Marshal(b, m, deterministic)
+}
+func (m *RevokeAPIKeyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeAPIKeyResponse.Merge(m, src)
+}
+func (m *RevokeAPIKeyResponse) XXX_Size() int {
+	return xxx_messageInfo_RevokeAPIKeyResponse.Size(m)
+}
+func (m *RevokeAPIKeyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeAPIKeyResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeAPIKeyResponse proto.InternalMessageInfo
+
+type VerifyAPIKeyRequest struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VerifyAPIKeyRequest) Reset()         { *m = VerifyAPIKeyRequest{} }
+func (m *VerifyAPIKeyRequest) String() string { return proto.CompactTextString(m) }
+func (*VerifyAPIKeyRequest) ProtoMessage()    {}
+func (*VerifyAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{74}
+}
+
+func (m *VerifyAPIKeyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyAPIKeyRequest.Unmarshal(m, b)
+}
+func (m *VerifyAPIKeyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyAPIKeyRequest.Marshal(b, m, deterministic)
+}
+func (m *VerifyAPIKeyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyAPIKeyRequest.Merge(m, src)
+}
+func (m *VerifyAPIKeyRequest) XXX_Size() int {
+	return xxx_messageInfo_VerifyAPIKeyRequest.Size(m)
+}
+func (m *VerifyAPIKeyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyAPIKeyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyAPIKeyRequest proto.InternalMessageInfo
+
+func (m *VerifyAPIKeyRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *VerifyAPIKeyRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type VerifyAPIKeyResponse struct {
+	// the account the key was issued to, with the scopes of the key
+	Account              *Account `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VerifyAPIKeyResponse) Reset()         { *m = VerifyAPIKeyResponse{} }
+func (m *VerifyAPIKeyResponse) String() string { return proto.CompactTextString(m) }
+func (*VerifyAPIKeyResponse) ProtoMessage()    {}
+func (*VerifyAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{75}
+}
+
+func (m *VerifyAPIKeyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyAPIKeyResponse.Unmarshal(m, b)
+}
+func (m *VerifyAPIKeyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyAPIKeyResponse.Marshal(b, m, deterministic)
+}
+func (m *VerifyAPIKeyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyAPIKeyResponse.Merge(m, src)
+}
+func (m *VerifyAPIKeyResponse) XXX_Size() int {
+	return xxx_messageInfo_VerifyAPIKeyResponse.Size(m)
+}
+func (m *VerifyAPIKeyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyAPIKeyResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyAPIKeyResponse proto.InternalMessageInfo
+
+func (m *VerifyAPIKeyResponse) GetAccount() *Account {
+	if m != nil {
+		return m.Account
+	}
+	return nil
+}
+
+// AuditRecord is the authorization decision made for a call
+type AuditRecord struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// the account which made the call, blank for unauthenticated calls
+	AccountId string `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	// the namespace of the service called
+	Namespace string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Service   string `protobuf:"bytes,4,opt,name=service,proto3" json:"service,omitempty"`
+	Endpoint  string `protobuf:"bytes,5,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	// either granted or denied
+	Decision string `protobuf:"bytes,6,opt,name=decision,proto3" json:"decision,omitempty"`
+	// why the call was denied
+	Reason string `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	// unix timestamp of the call
+	Timestamp            int64    `protobuf:"varint,8,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditRecord) Reset()         { *m = AuditRecord{} }
+func (m *AuditRecord) String() string { return proto.CompactTextString(m) }
+func (*AuditRecord) ProtoMessage()    {}
+func (*AuditRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{76}
+}
+
+func (m *AuditRecord) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditRecord.Unmarshal(m, b)
+}
+func (m *AuditRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditRecord.Marshal(b, m, deterministic)
+}
+func (m *AuditRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditRecord.Merge(m, src)
+}
+func (m *AuditRecord) XXX_Size() int {
+	return xxx_messageInfo_AuditRecord.Size(m)
+}
+func (m *AuditRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditRecord proto.InternalMessageInfo
+
+func (m *AuditRecord) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetEndpoint() string {
+	if m != nil {
+		return m.Endpoint
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetDecision() string {
+	if m != nil {
+		return m.Decision
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+type ListAuditRecordsRequest struct {
+	// filters, blank values match every record
+	AccountId string `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Service   string `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
+	Endpoint  string `protobuf:"bytes,3,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	Decision  string `protobuf:"bytes,4,opt,name=decision,proto3" json:"decision,omitempty"`
+	// unix timestamps of the period to list the records of
+	Since int64 `protobuf:"varint,5,opt,name=since,proto3" json:"since,omitempty"`
+	Until int64 `protobuf:"varint,6,opt,name=until,proto3" json:"until,omitempty"`
+	// the max number of records to return, newest first
+	Limit   uint64   `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+	Options *Options `protobuf:"bytes,8,opt,name=options,proto3" json:"options,omitempty"`
+	// the number of matching records to skip
+	Offset               uint64   `protobuf:"varint,9,opt,name=offset,proto3" json:"offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListAuditRecordsRequest) Reset()         { *m = ListAuditRecordsRequest{} }
+func (m *ListAuditRecordsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListAuditRecordsRequest) ProtoMessage()    {}
+func (*ListAuditRecordsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{77}
+}
+
+func (m *ListAuditRecordsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAuditRecordsRequest.Unmarshal(m, b)
+}
+func (m *ListAuditRecordsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAuditRecordsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListAuditRecordsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAuditRecordsRequest.Merge(m, src)
+}
+func (m *ListAuditRecordsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListAuditRecordsRequest.Size(m)
+}
+func (m *ListAuditRecordsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAuditRecordsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAuditRecordsRequest proto.InternalMessageInfo
+
+func (m *ListAuditRecordsRequest) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+func (m *ListAuditRecordsRequest) GetService() string {
+	if m != nil {
+		return m.Service
+	}
+	return ""
+}
+
+func (m *ListAuditRecordsRequest) GetEndpoint() string {
+	if m != nil {
+		return m.Endpoint
+	}
+	return ""
+}
+
+func (m *ListAuditRecordsRequest) GetDecision() string {
+	if m != nil {
+		return m.Decision
+	}
+	return ""
+}
+
+func (m *ListAuditRecordsRequest) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+func (m *ListAuditRecordsRequest) GetUntil() int64 {
+	if m != nil {
+		return m.Until
+	}
+	return 0
+}
+
+func (m *ListAuditRecordsRequest) GetLimit() uint64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *ListAuditRecordsRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *ListAuditRecordsRequest) GetOffset() uint64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type ListAuditRecordsResponse struct {
+	Records              []*AuditRecord `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ListAuditRecordsResponse) Reset()         { *m = ListAuditRecordsResponse{} }
+func (m *ListAuditRecordsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListAuditRecordsResponse) ProtoMessage()    {}
+func (*ListAuditRecordsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{78}
+}
+
+func (m *ListAuditRecordsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAuditRecordsResponse.Unmarshal(m, b)
+}
+func (m *ListAuditRecordsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAuditRecordsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListAuditRecordsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAuditRecordsResponse.Merge(m, src)
+}
+func (m *ListAuditRecordsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListAuditRecordsResponse.Size(m)
+}
+func (m *ListAuditRecordsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAuditRecordsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAuditRecordsResponse proto.InternalMessageInfo
+
+func (m *ListAuditRecordsResponse) GetRecords() []*AuditRecord {
+	if m != nil {
+		return m.Records
+	}
+	return nil
+}
+
+// SigningKey signs the tokens issued by a namespace, only the public key is returned
+type SigningKey struct {
+	Id        string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// PEM encoded public key used to verify the tokens signed by the key
+	PublicKey string `protobuf:"bytes,3,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	// unix timestamp of when the key was created
+	Created int64 `protobuf:"varint,4,opt,name=created,proto3" json:"created,omitempty"`
+	// true if the key signs new tokens, rotated keys only verify tokens until they expire
+	Active bool `protobuf:"varint,5,opt,name=active,proto3" json:"active,omitempty"`
+	// unix timestamp of when a rotated key expires
+	Expiry               int64    `protobuf:"varint,6,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SigningKey) Reset()         { *m = SigningKey{} }
+func (m *SigningKey) String() string { return proto.CompactTextString(m) }
+func (*SigningKey) ProtoMessage()    {}
+func (*SigningKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{79}
+}
+
+func (m *SigningKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SigningKey.Unmarshal(m, b)
+}
+func (m *SigningKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SigningKey.Marshal(b, m, deterministic)
+}
+func (m *SigningKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SigningKey.Merge(m, src)
+}
+func (m *SigningKey) XXX_Size() int {
+	return xxx_messageInfo_SigningKey.Size(m)
+}
+func (m *SigningKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_SigningKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SigningKey proto.InternalMessageInfo
+
+func (m *SigningKey) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *SigningKey) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *SigningKey) GetPublicKey() string {
+	if m != nil {
+		return m.PublicKey
+	}
+	return ""
+}
+
+func (m *SigningKey) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+func (m *SigningKey) GetActive() bool {
+	if m != nil {
+		return m.Active
+	}
+	return false
+}
+
+func (m *SigningKey) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
+type RotateSigningKeyRequest struct {
+	Options *Options `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	// revoke the previous keys rather than letting them expire, invalidating the tokens they signed
+	Revoke               bool     `protobuf:"varint,2,opt,name=revoke,proto3" json:"revoke,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RotateSigningKeyRequest) Reset()         { *m = RotateSigningKeyRequest{} }
+func (m *RotateSigningKeyRequest) String() string { return proto.CompactTextString(m) }
+func (*RotateSigningKeyRequest) ProtoMessage()    {}
+func (*RotateSigningKeyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{80}
+}
+
+func (m *RotateSigningKeyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RotateSigningKeyRequest.Unmarshal(m, b)
+}
+func (m *RotateSigningKeyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RotateSigningKeyRequest.Marshal(b, m, deterministic)
+}
+func (m *RotateSigningKeyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RotateSigningKeyRequest.Merge(m, src)
+}
+func (m *RotateSigningKeyRequest) XXX_Size() int {
+	return xxx_messageInfo_RotateSigningKeyRequest.Size(m)
+}
+func (m *RotateSigningKeyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RotateSigningKeyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RotateSigningKeyRequest proto.InternalMessageInfo
+
+func (m *RotateSigningKeyRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *RotateSigningKeyRequest) GetRevoke() bool {
+	if m != nil {
+		return m.Revoke
+	}
+	return false
+}
+
+type RotateSigningKeyResponse struct {
+	Key                  *SigningKey `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *RotateSigningKeyResponse) Reset()         { *m = RotateSigningKeyResponse{} }
+func (m *RotateSigningKeyResponse) String() string { return proto.CompactTextString(m) }
+func (*RotateSigningKeyResponse) ProtoMessage()    {}
+func (*RotateSigningKeyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{81}
+}
+
+func (m *RotateSigningKeyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RotateSigningKeyResponse.Unmarshal(m, b)
+}
+func (m *RotateSigningKeyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RotateSigningKeyResponse.Marshal(b, m, deterministic)
+}
+func (m *RotateSigningKeyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RotateSigningKeyResponse.Merge(m, src)
+}
+func (m *RotateSigningKeyResponse) XXX_Size() int {
+	return xxx_messageInfo_RotateSigningKeyResponse.Size(m)
+}
+func (m *RotateSigningKeyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RotateSigningKeyResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RotateSigningKeyResponse proto.InternalMessageInfo
+
+func (m *RotateSigningKeyResponse) GetKey() *SigningKey {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+type ListSigningKeysRequest struct {
+	Options              *Options `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSigningKeysRequest) Reset()         { *m = ListSigningKeysRequest{} }
+func (m *ListSigningKeysRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSigningKeysRequest) ProtoMessage()    {}
+func (*ListSigningKeysRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{82}
+}
+
+func (m *ListSigningKeysRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSigningKeysRequest.Unmarshal(m, b)
+}
+func (m *ListSigningKeysRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSigningKeysRequest.Marshal(b, m, deterministic)
+}
+func (m *ListSigningKeysRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSigningKeysRequest.Merge(m, src)
+}
+func (m *ListSigningKeysRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSigningKeysRequest.Size(m)
+}
+func (m *ListSigningKeysRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSigningKeysRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSigningKeysRequest proto.InternalMessageInfo
+
+func (m *ListSigningKeysRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type ListSigningKeysResponse struct {
+	Keys                 []*SigningKey `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ListSigningKeysResponse) Reset()         { *m = ListSigningKeysResponse{} }
+func (m *ListSigningKeysResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSigningKeysResponse) ProtoMessage()    {}
+func (*ListSigningKeysResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ec48c1eaf43a2, []int{83}
+}
+
+func (m *ListSigningKeysResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSigningKeysResponse.Unmarshal(m, b)
+}
+func (m *ListSigningKeysResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSigningKeysResponse.Marshal(b, m, deterministic)
+}
+func (m *ListSigningKeysResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSigningKeysResponse.Merge(m, src)
+}
+func (m *ListSigningKeysResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSigningKeysResponse.Size(m)
+}
+func (m *ListSigningKeysResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSigningKeysResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSigningKeysResponse proto.InternalMessageInfo
+
+func (m *ListSigningKeysResponse) GetKeys() []*SigningKey {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("auth.Access", Access_name, Access_value)
+	proto.RegisterType((*ListAccountsRequest)(nil), "auth.ListAccountsRequest")
+	proto.RegisterType((*ListAccountsResponse)(nil), "auth.ListAccountsResponse")
+	proto.RegisterType((*DeleteAccountRequest)(nil), "auth.DeleteAccountRequest")
+	proto.RegisterType((*DeleteAccountResponse)(nil), "auth.DeleteAccountResponse")
+	proto.RegisterType((*Token)(nil), "auth.Token")
+	proto.RegisterType((*Account)(nil), "auth.Account")
+	proto.RegisterMapType((map[string]string)(nil), "auth.Account.MetadataEntry")
+	proto.RegisterType((*Resource)(nil), "auth.Resource")
+	proto.RegisterType((*GenerateRequest)(nil), "auth.GenerateRequest")
+	proto.RegisterMapType((map[string]string)(nil), "auth.GenerateRequest.MetadataEntry")
+	proto.RegisterType((*GenerateResponse)(nil), "auth.GenerateResponse")
+	proto.RegisterType((*GrantRequest)(nil), "auth.GrantRequest")
+	proto.RegisterType((*GrantResponse)(nil), "auth.GrantResponse")
+	proto.RegisterType((*RevokeRequest)(nil), "auth.RevokeRequest")
+	proto.RegisterType((*RevokeResponse)(nil), "auth.RevokeResponse")
+	proto.RegisterType((*InspectRequest)(nil), "auth.InspectRequest")
+	proto.RegisterType((*InspectResponse)(nil), "auth.InspectResponse")
+	proto.RegisterType((*TokenRequest)(nil), "auth.TokenRequest")
+	proto.RegisterType((*TokenResponse)(nil), "auth.TokenResponse")
+	proto.RegisterType((*Rule)(nil), "auth.Rule")
+	proto.RegisterType((*Options)(nil), "auth.Options")
+	proto.RegisterType((*CreateRequest)(nil), "auth.CreateRequest")
+	proto.RegisterType((*CreateResponse)(nil), "auth.CreateResponse")
+	proto.RegisterType((*DeleteRequest)(nil), "auth.DeleteRequest")
+	proto.RegisterType((*DeleteResponse)(nil), "auth.DeleteResponse")
+	proto.RegisterType((*ListRequest)(nil), "auth.ListRequest")
+	proto.RegisterType((*ListResponse)(nil), "auth.ListResponse")
+	proto.RegisterType((*ChangeSecretRequest)(nil), "auth.ChangeSecretRequest")
+	proto.RegisterType((*ChangeSecretResponse)(nil), "auth.ChangeSecretResponse")
+	proto.RegisterType((*UnlockAccountRequest)(nil), "auth.UnlockAccountRequest")
+	proto.RegisterType((*UnlockAccountResponse)(nil), "auth.UnlockAccountResponse")
+	proto.RegisterType((*OAuthTokenRequest)(nil), "auth.OAuthTokenRequest")
+	proto.RegisterType((*OAuthTokenResponse)(nil), "auth.OAuthTokenResponse")
+	proto.RegisterType((*Client)(nil), "auth.Client")
+	proto.RegisterMapType((map[string]string)(nil), "auth.Client.MetadataEntry")
+	proto.RegisterType((*CreateClientRequest)(nil), "auth.CreateClientRequest")
+	proto.RegisterMapType((map[string]string)(nil), "auth.CreateClientRequest.MetadataEntry")
+	proto.RegisterType((*CreateClientResponse)(nil), "auth.CreateClientResponse")
+	proto.RegisterType((*ListClientsRequest)(nil), "auth.ListClientsRequest")
+	proto.RegisterType((*ListClientsResponse)(nil), "auth.ListClientsResponse")
+	proto.RegisterType((*DeleteClientRequest)(nil), "auth.DeleteClientRequest")
+	proto.RegisterType((*DeleteClientResponse)(nil), "auth.DeleteClientResponse")
+	proto.RegisterType((*SCIMUser)(nil), "auth.SCIMUser")
+	proto.RegisterType((*SCIMGroup)(nil), "auth.SCIMGroup")
+	proto.RegisterType((*SCIMUserRequest)(nil), "auth.SCIMUserRequest")
+	proto.RegisterType((*SCIMUserResponse)(nil), "auth.SCIMUserResponse")
+	proto.RegisterType((*ReadSCIMUserRequest)(nil), "auth.ReadSCIMUserRequest")
+	proto.RegisterType((*DeleteSCIMUserRequest)(nil), "auth.DeleteSCIMUserRequest")
+	proto.RegisterType((*DeleteSCIMUserResponse)(nil), "auth.DeleteSCIMUserResponse")
+	proto.RegisterType((*ListSCIMUsersRequest)(nil), "auth.ListSCIMUsersRequest")
+	proto.RegisterType((*ListSCIMUsersResponse)(nil), "auth.ListSCIMUsersResponse")
+	proto.RegisterType((*SCIMGroupRequest)(nil), "auth.SCIMGroupRequest")
+	proto.RegisterType((*SCIMGroupResponse)(nil), "auth.SCIMGroupResponse")
+	proto.RegisterType((*ReadSCIMGroupRequest)(nil), "auth.ReadSCIMGroupRequest")
+	proto.RegisterType((*DeleteSCIMGroupRequest)(nil), "auth.DeleteSCIMGroupRequest")
+	proto.RegisterType((*DeleteSCIMGroupResponse)(nil), "auth.DeleteSCIMGroupResponse")
+	proto.RegisterType((*ListSCIMGroupsRequest)(nil), "auth.ListSCIMGroupsRequest")
+	proto.RegisterType((*ListSCIMGroupsResponse)(nil), "auth.ListSCIMGroupsResponse")
+	proto.RegisterType((*MapSCIMGroupRequest)(nil), "auth.MapSCIMGroupRequest")
+	proto.RegisterType((*MapSCIMGroupResponse)(nil), "auth.MapSCIMGroupResponse")
+	proto.RegisterType((*Session)(nil), "auth.Session")
+	proto.RegisterType((*CreateSessionRequest)(nil), "auth.CreateSessionRequest")
+	proto.RegisterType((*CreateSessionResponse)(nil), "auth.CreateSessionResponse")
+	proto.RegisterType((*RefreshSessionRequest)(nil), "auth.RefreshSessionRequest")
+	proto.RegisterType((*RefreshSessionResponse)(nil), "auth.RefreshSessionResponse")
+	proto.RegisterType((*ListSessionsRequest)(nil), "auth.ListSessionsRequest")
+	proto.RegisterType((*ListSessionsResponse)(nil), "auth.ListSessionsResponse")
+	proto.RegisterType((*RevokeSessionRequest)(nil), "auth.RevokeSessionRequest")
+	proto.RegisterType((*RevokeSessionResponse)(nil), "auth.RevokeSessionResponse")
+	proto.RegisterType((*IssueCertificateRequest)(nil), "auth.IssueCertificateRequest")
+	proto.RegisterType((*IssueCertificateResponse)(nil), "auth.IssueCertificateResponse")
+	proto.RegisterType((*APIKey)(nil), "auth.APIKey")
+	proto.RegisterType((*CreateAPIKeyRequest)(nil), "auth.CreateAPIKeyRequest")
+	proto.RegisterType((*CreateAPIKeyResponse)(nil), "auth.CreateAPIKeyResponse")
+	proto.RegisterType((*ListAPIKeysRequest)(nil), "auth.ListAPIKeysRequest")
+	proto.RegisterType((*ListAPIKeysResponse)(nil), "auth.ListAPIKeysResponse")
+	proto.RegisterType((*RevokeAPIKeyRequest)(nil), "auth.RevokeAPIKeyRequest")
+	proto.RegisterType((*RevokeAPIKeyResponse)(nil), "auth.RevokeAPIKeyResponse")
+	proto.RegisterType((*VerifyAPIKeyRequest)(nil), "auth.VerifyAPIKeyRequest")
+	proto.RegisterType((*VerifyAPIKeyResponse)(nil), "auth.VerifyAPIKeyResponse")
+	proto.RegisterType((*AuditRecord)(nil), "auth.AuditRecord")
+	proto.RegisterType((*ListAuditRecordsRequest)(nil), "auth.ListAuditRecordsRequest")
+	proto.RegisterType((*ListAuditRecordsResponse)(nil), "auth.ListAuditRecordsResponse")
+	proto.RegisterType((*SigningKey)(nil), "auth.SigningKey")
+	proto.RegisterType((*RotateSigningKeyRequest)(nil), "auth.RotateSigningKeyRequest")
+	proto.RegisterType((*RotateSigningKeyResponse)(nil), "auth.RotateSigningKeyResponse")
+	proto.RegisterType((*ListSigningKeysRequest)(nil), "auth.ListSigningKeysRequest")
+	proto.RegisterType((*ListSigningKeysResponse)(nil), "auth.ListSigningKeysResponse")
+}
+
+func init() { proto.RegisterFile("auth/auth.proto", fileDescriptor_712ec48c1eaf43a2) }
+
+var fileDescriptor_712ec48c1eaf43a2 = []byte{
+	// 2905 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x3a, 0x4b, 0x6f, 0x1c, 0xc7,
+	0xd1, 0x9a, 0x9d, 0x7d, 0xd6, 0x92, 0xd4, 0x6a, 0xb8, 0x24, 0x57, 0x43, 0x51, 0x96, 0xc7, 0xf6,
+	0x27, 0x3f, 0x00, 0xe9, 0x0b, 0x0d, 0x3b, 0x86, 0x15, 0x59, 0xa6, 0x29, 0x85, 0x5e, 0xd8, 0xa2,
+	0x94, 0xa1, 0x14, 0x1b, 0x46, 0x12, 0x62, 0x34, 0xdb, 0xa4, 0x06, 0x5a, 0xce, 0xac, 0x67, 0x66,
+	0x69, 0x33, 0xa7, 0x04, 0xb9, 0x26, 0x87, 0x20, 0x48, 0x0e, 0x06, 0x92, 0x20, 0x08, 0x90, 0x9c,
+	0x73, 0x09, 0x10, 0x20, 0xd7, 0xfc, 0x87, 0x9c, 0x73, 0xce, 0x2d, 0x87, 0xdc, 0x92, 0xa0, 0xbb,
+	0xab, 0x7b, 0xba, 0xe7, 0x41, 0xed, 0x8a, 0x0a, 0x72, 0x59, 0x6c, 0x57, 0x75, 0x57, 0x57, 0x55,
+	0x57, 0x55, 0x57, 0x55, 0x0f, 0x9c, 0xf7, 0xa6, 0xe9, 0xe3, 0xeb, 0xf4, 0xe7, 0xda, 0x24, 0x8e,
+	0xd2, 0xc8, 0xaa, 0xd3, 0xff, 0xce, 0x7b, 0xb0, 0xfc, 0x71, 0x90, 0xa4, 0x5b, 0xbe, 0x1f, 0x4d,
+	0xc3, 0x34, 0x71, 0xc9, 0xe7, 0x53, 0x92, 0xa4, 0xd6, 0x55, 0x68, 0x45, 0x93, 0x34, 0x88, 0xc2,
+	0x64, 0x60, 0x5c, 0x31, 0x5e, 0xed, 0x6e, 0x2e, 0x5e, 0x63, 0x4b, 0xef, 0x71, 0xa0, 0x2b, 0xb0,
+	0xce, 0x16, 0xf4, 0xf5, 0xf5, 0xc9, 0x24, 0x0a, 0x13, 0x62, 0xbd, 0x06, 0x6d, 0x0f, 0x61, 0x03,
+	0xe3, 0x8a, 0x99, 0x51, 0xc0, 0x99, 0xae, 0x44, 0x3b, 0xf7, 0xa0, 0x7f, 0x9b, 0x8c, 0x49, 0x4a,
+	0x04, 0x0a, 0x79, 0x58, 0x82, 0x5a, 0x30, 0x62, 0xdb, 0x77, 0xdc, 0x5a, 0x30, 0x52, 0x79, 0xaa,
+	0x9d, 0xca, 0xd3, 0x1a, 0xac, 0xe4, 0x08, 0x72, 0xa6, 0x9c, 0x1f, 0x1a, 0xd0, 0x78, 0x10, 0x3d,
+	0x21, 0xa1, 0xf5, 0x22, 0x2c, 0x78, 0xbe, 0x4f, 0x92, 0x64, 0x3f, 0xa5, 0x63, 0xdc, 0xa5, 0xcb,
+	0x61, 0x7c, 0xca, 0x4b, 0xb0, 0x18, 0x93, 0x83, 0x98, 0x24, 0x8f, 0x71, 0x4e, 0x8d, 0xcd, 0x59,
+	0x40, 0x20, 0x9f, 0x34, 0x80, 0x96, 0x1f, 0x13, 0x2f, 0x25, 0xa3, 0x81, 0x79, 0xc5, 0x78, 0xd5,
+	0x74, 0xc5, 0xd0, 0x5a, 0x85, 0x26, 0xf9, 0x72, 0x12, 0xc4, 0x27, 0x83, 0x3a, 0x43, 0xe0, 0xc8,
+	0xf9, 0xb7, 0x01, 0x2d, 0xe4, 0xab, 0x20, 0xa1, 0x05, 0xf5, 0xf4, 0x64, 0x42, 0x70, 0x27, 0xf6,
+	0xdf, 0xfa, 0x3a, 0xb4, 0x8f, 0x48, 0xea, 0x8d, 0xbc, 0xd4, 0x1b, 0xd4, 0x99, 0x22, 0xd7, 0x35,
+	0x45, 0x5e, 0xbb, 0x8b, 0xd8, 0x3b, 0x61, 0x1a, 0x9f, 0xb8, 0x72, 0x32, 0x65, 0x20, 0xf1, 0xa3,
+	0x09, 0x49, 0x06, 0x8d, 0x2b, 0xe6, 0xab, 0x1d, 0x17, 0x47, 0x14, 0x1e, 0x24, 0xc9, 0x94, 0xc4,
+	0x83, 0x26, 0xdb, 0x06, 0x47, 0x6c, 0x3e, 0xf1, 0x63, 0x92, 0x0e, 0x5a, 0x1c, 0xce, 0x47, 0x94,
+	0xa9, 0xd0, 0x3b, 0x22, 0x83, 0x36, 0x67, 0x8a, 0xfe, 0xb7, 0x6f, 0xc0, 0xa2, 0xb6, 0xad, 0xd5,
+	0x03, 0xf3, 0x09, 0x39, 0x41, 0x51, 0xe8, 0x5f, 0xab, 0x0f, 0x8d, 0x63, 0x6f, 0x3c, 0x15, 0xc2,
+	0xf0, 0xc1, 0xbb, 0xb5, 0x77, 0x0c, 0x67, 0x17, 0xda, 0x2e, 0x49, 0xa2, 0x69, 0xec, 0x13, 0x49,
+	0xdc, 0xc8, 0x88, 0x97, 0x6a, 0xc1, 0x86, 0x36, 0x09, 0x47, 0x93, 0x28, 0x08, 0x53, 0xa6, 0xe8,
+	0x8e, 0x2b, 0xc7, 0xce, 0x9f, 0x6b, 0x70, 0x7e, 0x87, 0x84, 0x24, 0xf6, 0x52, 0x52, 0x65, 0x3b,
+	0xb7, 0x14, 0x2d, 0x9a, 0x4c, 0x8b, 0x2f, 0x71, 0x2d, 0xe6, 0x16, 0xce, 0xa0, 0xcd, 0x7a, 0x5e,
+	0x9b, 0xa8, 0xb5, 0x46, 0x5e, 0x6b, 0x4c, 0x88, 0xa6, 0x2e, 0xc4, 0x24, 0x8e, 0x8e, 0x83, 0x11,
+	0x89, 0x51, 0xc7, 0x72, 0xac, 0x1a, 0x77, 0xfb, 0x34, 0xe3, 0x96, 0x1a, 0xeb, 0x3c, 0xaf, 0xe3,
+	0xb8, 0x01, 0xbd, 0x4c, 0x09, 0xe8, 0xbd, 0x57, 0xa1, 0x85, 0xee, 0xa9, 0xbb, 0xbf, 0x70, 0x28,
+	0x81, 0x75, 0x4e, 0x60, 0x61, 0x27, 0xf6, 0x32, 0x9f, 0xed, 0x43, 0x83, 0x29, 0x06, 0xb7, 0xe6,
+	0x03, 0xeb, 0x75, 0x68, 0xc7, 0x78, 0xe2, 0xe8, 0xba, 0x4b, 0x9c, 0x9e, 0xb0, 0x03, 0x57, 0xe2,
+	0x55, 0x45, 0x98, 0xa7, 0x7a, 0xf9, 0x79, 0x58, 0xc4, 0xad, 0xd1, 0xbb, 0xbf, 0x0f, 0x8b, 0x2e,
+	0x39, 0x8e, 0x9e, 0x90, 0xff, 0x01, 0x33, 0x3d, 0x58, 0x12, 0x7b, 0x23, 0x37, 0xc7, 0xb0, 0x34,
+	0x0c, 0x93, 0x09, 0xf1, 0x55, 0xdd, 0xa8, 0xc1, 0x86, 0x0f, 0x66, 0x8e, 0x6a, 0xd6, 0x2b, 0xb0,
+	0xe4, 0x7b, 0xe3, 0xf1, 0x3e, 0x3d, 0xf1, 0x64, 0xe2, 0xf9, 0x04, 0x1d, 0x61, 0x91, 0x42, 0x77,
+	0x05, 0xd0, 0x79, 0x17, 0xce, 0xcb, 0x7d, 0xe7, 0x3d, 0xcd, 0xdf, 0x1b, 0xb0, 0xc0, 0xe2, 0x5a,
+	0x95, 0x1b, 0x65, 0xd6, 0x5e, 0xd3, 0xac, 0xbd, 0x10, 0x2b, 0xcd, 0x92, 0x58, 0xf9, 0x22, 0x2c,
+	0x30, 0xe4, 0xbe, 0x16, 0x17, 0xbb, 0x0c, 0x76, 0x87, 0x81, 0x54, 0x65, 0x34, 0x4e, 0xd5, 0xf7,
+	0x26, 0x2c, 0x22, 0xa3, 0x28, 0xe3, 0x8b, 0xaa, 0x72, 0xbb, 0x9b, 0x5d, 0xbe, 0x8e, 0xcf, 0xe1,
+	0x18, 0xe7, 0x2b, 0x03, 0xea, 0xee, 0x74, 0x4c, 0x0a, 0x52, 0x49, 0x3b, 0xa9, 0x55, 0xd9, 0x89,
+	0xf9, 0x14, 0x3b, 0x79, 0x19, 0x9a, 0xfc, 0xea, 0x60, 0x42, 0x2d, 0x6d, 0x2e, 0x48, 0x05, 0x93,
+	0x24, 0x71, 0x11, 0xc7, 0xfd, 0x3f, 0x88, 0xe2, 0x20, 0x3d, 0x61, 0xe2, 0x35, 0x5c, 0x39, 0x76,
+	0xae, 0x42, 0x0b, 0x85, 0xb4, 0x2e, 0x41, 0x27, 0x3b, 0x63, 0xce, 0x65, 0x06, 0x70, 0x3e, 0x85,
+	0xc5, 0x6d, 0x76, 0xc5, 0x88, 0x33, 0xba, 0x0c, 0xf5, 0x78, 0x3a, 0x26, 0x28, 0x38, 0x20, 0x8f,
+	0xd3, 0x31, 0x71, 0x19, 0x7c, 0xf6, 0x6b, 0xb3, 0x07, 0x4b, 0x82, 0x32, 0xda, 0xf0, 0x87, 0xb0,
+	0xc8, 0x2f, 0xd2, 0x33, 0x5f, 0xc9, 0x3d, 0x58, 0x12, 0x94, 0x90, 0xf6, 0xdb, 0xd0, 0xa5, 0x89,
+	0x43, 0x49, 0xc2, 0x71, 0x3a, 0xa5, 0xff, 0x87, 0x05, 0xbe, 0x0e, 0x0f, 0xfe, 0x0a, 0x34, 0xa8,
+	0x98, 0x22, 0xcb, 0x50, 0xe5, 0xe7, 0x08, 0xe7, 0xc7, 0x06, 0x2c, 0x6f, 0x3f, 0xf6, 0xc2, 0x43,
+	0xb2, 0xc7, 0xac, 0xb5, 0x4a, 0x98, 0x0d, 0x80, 0x68, 0x3c, 0xda, 0xd7, 0x0c, 0xbc, 0x13, 0x8d,
+	0x47, 0x7c, 0x15, 0x45, 0x87, 0xe4, 0x0b, 0x81, 0x36, 0xf1, 0x5c, 0xc8, 0x17, 0x88, 0x56, 0x04,
+	0xa8, 0x9f, 0x2a, 0xc0, 0x2a, 0xf4, 0x75, 0x6e, 0x50, 0x21, 0xf7, 0xa0, 0xff, 0x30, 0x1c, 0x47,
+	0xfe, 0x93, 0xe7, 0x98, 0x06, 0xe5, 0x08, 0xe2, 0x4e, 0x7f, 0x35, 0xe0, 0xc2, 0xbd, 0xad, 0x69,
+	0xfa, 0x58, 0xf3, 0xf5, 0x0d, 0x80, 0x43, 0x1a, 0x4f, 0xf7, 0xd9, 0xbd, 0x85, 0x76, 0xc7, 0x20,
+	0x0f, 0xe8, 0xe5, 0xb5, 0x0e, 0x1d, 0x7f, 0x1c, 0x90, 0x30, 0xdd, 0x0f, 0x46, 0xa8, 0x9c, 0x36,
+	0x07, 0x0c, 0x47, 0xd4, 0xff, 0x11, 0xa9, 0xa9, 0x67, 0x81, 0x03, 0xf7, 0x2a, 0x82, 0x44, 0xbd,
+	0x24, 0x48, 0x48, 0x5f, 0x6c, 0xa8, 0xbe, 0xa8, 0xc8, 0xdc, 0x3c, 0x55, 0xe6, 0x7f, 0x18, 0x60,
+	0xa9, 0xa2, 0xc9, 0xe8, 0xf0, 0xd4, 0x74, 0x6f, 0x03, 0x80, 0x47, 0x27, 0x25, 0xf7, 0xe8, 0x30,
+	0x08, 0x13, 0x7f, 0x03, 0x80, 0x85, 0x2d, 0x92, 0xec, 0x07, 0x21, 0xe6, 0x7a, 0x1d, 0x84, 0x0c,
+	0xc3, 0xb3, 0xc8, 0xd6, 0x87, 0x06, 0x89, 0xe3, 0x48, 0xa4, 0x63, 0x7c, 0x60, 0xbd, 0x01, 0x17,
+	0xd8, 0x9f, 0xfd, 0x11, 0x49, 0xfc, 0x38, 0x60, 0xe2, 0x61, 0xd2, 0xd0, 0x63, 0x88, 0xdb, 0x19,
+	0xdc, 0xf9, 0x93, 0x01, 0xcd, 0x6d, 0xa6, 0xea, 0xb2, 0x94, 0x92, 0xa5, 0x0b, 0x35, 0x25, 0xc1,
+	0xca, 0x72, 0x19, 0x53, 0xcb, 0x65, 0xde, 0x2e, 0xa4, 0x9a, 0x36, 0x57, 0x33, 0xa7, 0x5d, 0x95,
+	0x1b, 0x9d, 0x2d, 0xfd, 0xf8, 0x3b, 0xf5, 0x4e, 0x16, 0x76, 0xf8, 0x2e, 0xc2, 0x1c, 0xcb, 0x32,
+	0xc3, 0x8c, 0xf1, 0x9a, 0xc6, 0xf8, 0x76, 0x21, 0xbb, 0xbb, 0x8a, 0x8c, 0x17, 0x09, 0x57, 0x66,
+	0x78, 0xb3, 0x3a, 0xf0, 0xd9, 0xc4, 0x7d, 0x00, 0x7d, 0x9d, 0x29, 0xb4, 0xd0, 0x97, 0xa1, 0xc9,
+	0x9d, 0x05, 0xe3, 0xf8, 0x82, 0xaa, 0x79, 0x17, 0x71, 0x55, 0xf7, 0xaf, 0x73, 0x13, 0x2c, 0x1a,
+	0x14, 0xf9, 0xec, 0xf9, 0x8b, 0xb8, 0x9b, 0xb0, 0xac, 0x2d, 0x47, 0x9e, 0xfe, 0x0f, 0x5a, 0x7c,
+	0x5f, 0x11, 0x5c, 0x75, 0xa6, 0x04, 0xd2, 0xd9, 0x85, 0x65, 0x1e, 0xdc, 0xf5, 0x13, 0x7c, 0xe6,
+	0xc0, 0xb5, 0x0a, 0x7d, 0x9d, 0x1e, 0xc6, 0xad, 0x7f, 0x1a, 0xd0, 0xde, 0xdb, 0x1e, 0xde, 0x7d,
+	0x98, 0x90, 0xb8, 0x40, 0xfd, 0x05, 0xe8, 0x92, 0x2f, 0x53, 0x12, 0x87, 0xde, 0x38, 0x8b, 0x50,
+	0x20, 0x40, 0xc3, 0x11, 0x0d, 0x60, 0xd3, 0x84, 0xc4, 0x2c, 0x7f, 0x12, 0x35, 0x04, 0x05, 0xd0,
+	0xd4, 0x89, 0x06, 0x88, 0x51, 0x90, 0x4c, 0xc6, 0xde, 0x09, 0xc7, 0x73, 0xf7, 0xed, 0x22, 0x6c,
+	0x17, 0x8d, 0xcf, 0xf3, 0xd3, 0xe0, 0x98, 0xbb, 0x6f, 0xdb, 0xc5, 0x11, 0x85, 0x93, 0x23, 0x2f,
+	0x18, 0xd3, 0xd0, 0xc4, 0x8c, 0x92, 0x8f, 0x28, 0xfc, 0x30, 0x8e, 0xa6, 0x93, 0x64, 0xd0, 0xe2,
+	0x70, 0x3e, 0x52, 0x4b, 0xc6, 0xb6, 0x5e, 0x32, 0x0e, 0xa0, 0x35, 0x9d, 0x8c, 0x18, 0xa6, 0xc3,
+	0x31, 0x38, 0x74, 0xfe, 0x62, 0x40, 0x87, 0x4a, 0xbe, 0x43, 0x49, 0xcc, 0x2f, 0x7a, 0x5e, 0x3a,
+	0xb3, 0x28, 0xdd, 0x00, 0x5a, 0x47, 0xe4, 0xe8, 0x11, 0x89, 0x45, 0x81, 0x23, 0x86, 0x95, 0x75,
+	0xa4, 0x22, 0x47, 0xb3, 0x52, 0x8e, 0x96, 0x2e, 0xc7, 0xf7, 0xe0, 0xbc, 0x38, 0x40, 0x61, 0x25,
+	0x0e, 0xd4, 0xe9, 0x29, 0x0c, 0x0c, 0x35, 0xc5, 0x92, 0x93, 0x18, 0x6e, 0x76, 0xcb, 0x79, 0x1b,
+	0x7a, 0x19, 0x7d, 0xb4, 0xe2, 0x19, 0x36, 0xa0, 0x16, 0xec, 0x12, 0x6f, 0x94, 0xe7, 0xed, 0x99,
+	0x2d, 0xf8, 0xbe, 0xe8, 0x40, 0x3c, 0x37, 0x8a, 0x03, 0x58, 0xcd, 0x53, 0x44, 0xaf, 0xf8, 0x99,
+	0xc1, 0x5b, 0x30, 0x02, 0x21, 0xdd, 0x5f, 0x33, 0x78, 0x23, 0x67, 0xf0, 0x2f, 0x40, 0x37, 0x49,
+	0xbd, 0x38, 0xdd, 0x0f, 0xc2, 0x11, 0xf9, 0x92, 0x6d, 0x6e, 0xba, 0xc0, 0x40, 0x43, 0x0a, 0xa1,
+	0x21, 0x8c, 0x97, 0x0c, 0xfc, 0xae, 0xe3, 0x83, 0xd9, 0xb3, 0x9c, 0x3d, 0x58, 0xc9, 0x31, 0x25,
+	0x03, 0x5d, 0x83, 0x32, 0x21, 0x42, 0x4a, 0xfe, 0x3c, 0x38, 0x92, 0xd7, 0x4a, 0xa9, 0x37, 0x46,
+	0xc6, 0xf8, 0xc0, 0x79, 0x04, 0x3d, 0xe9, 0x05, 0x42, 0xca, 0x57, 0xa0, 0xc1, 0x1c, 0x0b, 0xcf,
+	0xf7, 0x7c, 0x46, 0x8f, 0x4f, 0xe3, 0xd8, 0xd9, 0x15, 0xfd, 0x2e, 0x5c, 0x50, 0xf6, 0x40, 0xa6,
+	0x67, 0xdb, 0x84, 0xa6, 0x70, 0xc2, 0x8c, 0x34, 0x1e, 0x9f, 0xf9, 0xd4, 0xbf, 0xa5, 0x9e, 0xfa,
+	0xf3, 0x21, 0x79, 0x11, 0xd6, 0x0a, 0x24, 0xd1, 0x92, 0xbe, 0x32, 0xb2, 0x43, 0x63, 0x18, 0x69,
+	0x4a, 0xf9, 0x00, 0x62, 0x14, 0x03, 0xc8, 0x7f, 0xdb, 0xa0, 0x3e, 0x81, 0xd5, 0x3c, 0x6f, 0xb2,
+	0xbc, 0x15, 0x81, 0x96, 0x9b, 0x54, 0xe1, 0x74, 0x10, 0x5d, 0x61, 0x54, 0x63, 0x58, 0xbe, 0xeb,
+	0x4d, 0x0a, 0x0a, 0xee, 0xab, 0x47, 0xde, 0x11, 0x66, 0x54, 0x95, 0x81, 0xcc, 0xdc, 0x28, 0xb8,
+	0x09, 0x7d, 0x7d, 0xb7, 0xf9, 0x2c, 0xec, 0x8f, 0x06, 0xb4, 0xf6, 0x48, 0x92, 0x04, 0x51, 0x58,
+	0x56, 0xbf, 0x60, 0x21, 0x9f, 0xdd, 0x02, 0x1d, 0x84, 0x0c, 0x47, 0xa7, 0xb4, 0x2a, 0xd7, 0xa1,
+	0x33, 0xf6, 0x12, 0x9a, 0xbb, 0x63, 0xe2, 0x6a, 0xba, 0x6d, 0x0a, 0xd8, 0x23, 0x24, 0x54, 0xfa,
+	0x98, 0x0d, 0xb5, 0x8f, 0x49, 0x77, 0x63, 0xd1, 0xc5, 0x3b, 0xa4, 0x49, 0x0b, 0xcf, 0x5d, 0x59,
+	0xbc, 0xd9, 0x3a, 0x14, 0x79, 0xe8, 0x04, 0x13, 0xd6, 0x5a, 0x30, 0x71, 0x7e, 0x61, 0x88, 0xc4,
+	0x07, 0xd9, 0x9f, 0xb7, 0xc5, 0xa0, 0xef, 0x67, 0x96, 0xef, 0x57, 0x17, 0xfb, 0xcd, 0xde, 0x49,
+	0xf8, 0x1c, 0x56, 0x72, 0x7c, 0x65, 0x5d, 0x93, 0x84, 0x83, 0xf4, 0xec, 0x49, 0xcc, 0x13, 0x58,
+	0x91, 0xfe, 0xd5, 0xb2, 0xf4, 0x6f, 0x03, 0xc0, 0x4f, 0xe2, 0x03, 0xad, 0x17, 0xd2, 0xa1, 0x10,
+	0x56, 0x07, 0x38, 0x2e, 0xac, 0xb8, 0xbc, 0x2e, 0xc8, 0xe9, 0xa2, 0x98, 0x48, 0xce, 0xec, 0xd6,
+	0x3f, 0x32, 0x60, 0x35, 0x4f, 0x74, 0x5e, 0x41, 0x74, 0xb6, 0x6b, 0x39, 0xb6, 0x0b, 0x45, 0x94,
+	0x59, 0x28, 0xa2, 0x9c, 0xef, 0xf2, 0x44, 0x12, 0x29, 0x27, 0x4a, 0x69, 0xa9, 0x58, 0xa6, 0x91,
+	0xb7, 0xcc, 0x99, 0x85, 0xc4, 0xc7, 0x86, 0x8c, 0x7c, 0xf6, 0xd8, 0x80, 0x32, 0xe4, 0x1e, 0x1b,
+	0x84, 0x88, 0x12, 0xed, 0xfc, 0xc0, 0x80, 0x3e, 0xef, 0xd4, 0x3d, 0xc5, 0x0e, 0x4b, 0x4f, 0x55,
+	0x91, 0xc2, 0x3c, 0x45, 0x8a, 0xd3, 0x23, 0xd9, 0xd7, 0x60, 0x25, 0xc7, 0x01, 0x8a, 0x31, 0x80,
+	0x56, 0xcc, 0x10, 0x9c, 0x0f, 0xd3, 0x15, 0x43, 0xe7, 0x01, 0xac, 0x0d, 0x93, 0x64, 0x4a, 0xb6,
+	0x49, 0x9c, 0x06, 0x07, 0x81, 0xaf, 0xb4, 0x7f, 0x7a, 0x60, 0xfa, 0x49, 0x2c, 0x6c, 0xc6, 0x4f,
+	0xe6, 0xc8, 0x96, 0x62, 0x18, 0x14, 0xa9, 0xca, 0xb6, 0x4a, 0xd7, 0xcf, 0xc0, 0x22, 0xe0, 0x2b,
+	0x20, 0xda, 0xa6, 0xa2, 0x64, 0x79, 0x3b, 0x4b, 0x44, 0x1b, 0x01, 0x50, 0xc2, 0x86, 0xa9, 0x3d,
+	0x7f, 0xfc, 0xdc, 0x80, 0xe6, 0xd6, 0xfd, 0xe1, 0x47, 0xe4, 0x64, 0xde, 0xf8, 0x25, 0x0a, 0x42,
+	0xb3, 0xb4, 0x20, 0xac, 0x57, 0xe5, 0xa6, 0x8d, 0xaa, 0x67, 0x99, 0xa6, 0xc6, 0xd7, 0x6f, 0x64,
+	0x19, 0xca, 0xb9, 0x9b, 0xd1, 0x74, 0xe7, 0x29, 0xaf, 0x2b, 0x5e, 0x84, 0x66, 0x0f, 0x55, 0xf7,
+	0xa0, 0xaf, 0xb3, 0x28, 0xef, 0x8e, 0x96, 0x37, 0x09, 0xf6, 0x45, 0xe8, 0x90, 0x75, 0x1a, 0x4e,
+	0x6b, 0x7a, 0x93, 0x80, 0xea, 0xbb, 0x60, 0xd1, 0xce, 0x77, 0x78, 0xd9, 0xc8, 0xe7, 0x3d, 0x77,
+	0x6f, 0x15, 0x4f, 0x8b, 0x82, 0xba, 0x0c, 0x47, 0x6d, 0xe4, 0x36, 0x57, 0x56, 0x22, 0xbb, 0x2d,
+	0xce, 0x6e, 0xc2, 0x93, 0x72, 0x6a, 0xff, 0xfa, 0x89, 0x9c, 0xa5, 0xac, 0xd4, 0xe9, 0x61, 0xda,
+	0x73, 0x1f, 0x96, 0xbf, 0x4d, 0xe2, 0xe0, 0xe0, 0x44, 0xdf, 0xe7, 0x0c, 0xc1, 0xf8, 0x16, 0xf4,
+	0x75, 0x8a, 0xf3, 0x36, 0xe2, 0xff, 0x66, 0x40, 0x77, 0x6b, 0x3a, 0x0a, 0x52, 0x97, 0xf8, 0x51,
+	0x3c, 0x9a, 0xd7, 0x55, 0xb4, 0x0e, 0xb2, 0x99, 0xeb, 0x20, 0x53, 0xe7, 0x48, 0x48, 0x7c, 0x1c,
+	0xf8, 0xa2, 0xcc, 0x15, 0x43, 0xed, 0x95, 0xad, 0xa1, 0xbf, 0xb2, 0x51, 0xdc, 0x88, 0xf8, 0x01,
+	0xbb, 0x46, 0xf8, 0x6d, 0x2f, 0xc7, 0xd4, 0xb2, 0x63, 0xe2, 0x25, 0xb2, 0x43, 0x85, 0x23, 0xca,
+	0x47, 0x1a, 0x1c, 0x91, 0x24, 0xf5, 0x8e, 0x26, 0x58, 0xec, 0x66, 0x00, 0xe7, 0x27, 0x35, 0x58,
+	0x63, 0x06, 0x92, 0x09, 0x3a, 0xab, 0x0d, 0x2a, 0x22, 0xd4, 0xaa, 0x45, 0x30, 0x4f, 0x11, 0xa1,
+	0x9e, 0x13, 0x81, 0xf6, 0xe6, 0x82, 0xd0, 0x27, 0x18, 0x2f, 0xf8, 0x80, 0x42, 0xa7, 0x61, 0x1a,
+	0x8c, 0x31, 0x58, 0xf0, 0x01, 0x85, 0x8e, 0x83, 0xa3, 0x80, 0x3f, 0x94, 0xd6, 0x5d, 0x3e, 0x98,
+	0xfd, 0x05, 0x6f, 0x15, 0x9a, 0xd1, 0xc1, 0x41, 0x42, 0x52, 0x56, 0xe5, 0xd7, 0x5d, 0x1c, 0x39,
+	0x3b, 0x30, 0x28, 0xaa, 0x03, 0x2d, 0xe7, 0x0d, 0x7a, 0x35, 0x30, 0x10, 0xfa, 0xcc, 0x05, 0xb4,
+	0x9c, 0x6c, 0xb2, 0x2b, 0x66, 0x38, 0xbf, 0x35, 0x00, 0xf6, 0x82, 0xc3, 0x30, 0x08, 0x0f, 0xcb,
+	0xe2, 0xac, 0x66, 0x1d, 0xb5, 0xbc, 0x75, 0x6c, 0x00, 0x4c, 0xa6, 0x8f, 0xc6, 0x81, 0xcf, 0xe2,
+	0x09, 0x1a, 0x0f, 0x87, 0x50, 0x62, 0x4a, 0x64, 0xad, 0x17, 0x22, 0x6b, 0x65, 0x7f, 0xa4, 0x2c,
+	0xe2, 0x7e, 0x06, 0x6b, 0x6e, 0x94, 0xd2, 0xc4, 0x4b, 0xb2, 0x3a, 0x6f, 0xe3, 0x8a, 0x1b, 0x1e,
+	0x75, 0x69, 0x26, 0x47, 0xdb, 0xc5, 0x91, 0xf3, 0x1e, 0x0c, 0x8a, 0xb4, 0x65, 0x3f, 0xc0, 0xcc,
+	0x22, 0x65, 0x0f, 0xf3, 0x84, 0x6c, 0x1a, 0x0b, 0x8c, 0x5b, 0x58, 0x6c, 0x48, 0xf0, 0xfc, 0x3d,
+	0xb5, 0x5b, 0xb0, 0x56, 0x20, 0x21, 0x4b, 0xe0, 0xba, 0x12, 0xfd, 0x8a, 0x2c, 0x30, 0xec, 0xeb,
+	0xd7, 0xa0, 0xc9, 0xdf, 0x8f, 0xac, 0x2e, 0xb4, 0x1e, 0xee, 0x7e, 0xb4, 0x7b, 0xef, 0x93, 0xdd,
+	0xde, 0x39, 0x3a, 0xd8, 0x71, 0xb7, 0x76, 0x1f, 0xdc, 0xb9, 0xdd, 0x33, 0x2c, 0x80, 0xe6, 0xed,
+	0x3b, 0xbb, 0xc3, 0x3b, 0xb7, 0x7b, 0xb5, 0xcd, 0x3f, 0x18, 0x50, 0xa7, 0x9d, 0x6f, 0xeb, 0x06,
+	0xb4, 0xc5, 0x83, 0xae, 0xb5, 0x52, 0xfa, 0xca, 0x6d, 0xaf, 0xe6, 0xc1, 0x18, 0x0a, 0xcf, 0x59,
+	0xef, 0x40, 0x0b, 0x9f, 0x0f, 0xad, 0x3e, 0x9f, 0xa4, 0xbf, 0x62, 0xda, 0x2b, 0x39, 0xa8, 0x5c,
+	0xb9, 0x29, 0xbe, 0xad, 0xb0, 0xd4, 0xb7, 0x37, 0x5c, 0xb5, 0xac, 0xc1, 0xc4, 0x9a, 0xcd, 0x5f,
+	0xd5, 0xa0, 0x2d, 0x3e, 0x1d, 0xb1, 0x6e, 0x41, 0x9d, 0x6a, 0xcc, 0xba, 0xc8, 0xe7, 0x96, 0x7c,
+	0x96, 0x62, 0xdb, 0x65, 0x28, 0xc9, 0xc1, 0x36, 0x34, 0x79, 0x69, 0x6b, 0xe1, 0xbc, 0xb2, 0xcf,
+	0x4a, 0xec, 0xf5, 0x52, 0x9c, 0x24, 0xb2, 0x03, 0x0b, 0xea, 0xf3, 0x8c, 0xe0, 0xa6, 0xe4, 0x01,
+	0xc9, 0xb6, 0xcb, 0x50, 0x2a, 0x37, 0xfc, 0xf9, 0x45, 0x70, 0x53, 0xf6, 0xba, 0x63, 0xaf, 0x97,
+	0xe2, 0xa4, 0x82, 0x7e, 0x67, 0x40, 0x83, 0xbe, 0x65, 0x25, 0xd6, 0x5b, 0xd0, 0xe4, 0x97, 0xbf,
+	0xb5, 0xac, 0xf6, 0xb6, 0x05, 0x9d, 0xbe, 0x0e, 0x94, 0x5c, 0xbc, 0x25, 0x75, 0xb2, 0xac, 0xca,
+	0x9d, 0x5b, 0x96, 0x7b, 0x9b, 0x3b, 0x67, 0x5d, 0xc7, 0xb3, 0xb8, 0x90, 0x29, 0x5c, 0x2c, 0xb1,
+	0x54, 0x90, 0x64, 0xf4, 0x5f, 0x0d, 0xa8, 0xd3, 0x6a, 0xd5, 0xba, 0x09, 0xc0, 0x99, 0x60, 0x5d,
+	0xda, 0x95, 0x5c, 0x7b, 0x47, 0xb7, 0xbf, 0x42, 0x2f, 0xeb, 0x1c, 0xfd, 0x50, 0x83, 0xb6, 0x50,
+	0xd8, 0xe2, 0x8b, 0xe2, 0xbd, 0xd5, 0x1b, 0xcd, 0x4e, 0xe0, 0x26, 0xc0, 0x43, 0xd6, 0x6d, 0x7c,
+	0xb6, 0xfd, 0x87, 0x00, 0x5c, 0x19, 0x6c, 0xb9, 0x66, 0x2b, 0x79, 0x22, 0x97, 0xca, 0x91, 0x92,
+	0xd4, 0x37, 0xa1, 0x43, 0x95, 0xf4, 0x90, 0x35, 0xb4, 0x14, 0xcb, 0xcd, 0x37, 0xea, 0xec, 0xf5,
+	0x52, 0x9c, 0xa4, 0xf3, 0x3e, 0x74, 0xb9, 0x46, 0x79, 0xf7, 0x77, 0x35, 0xdf, 0x1a, 0x40, 0x2a,
+	0x6b, 0x05, 0xb8, 0xa4, 0xf0, 0x01, 0x74, 0xa8, 0x12, 0xf9, 0x7a, 0x5b, 0xd7, 0xea, 0xac, 0x34,
+	0xde, 0x87, 0x2e, 0xd7, 0xeb, 0x33, 0x73, 0xf1, 0x31, 0x74, 0xb9, 0xae, 0x38, 0x85, 0x82, 0xfa,
+	0x34, 0x3a, 0x1b, 0x15, 0x58, 0xf5, 0xa0, 0xa8, 0xc2, 0x76, 0x78, 0x6b, 0x27, 0xa7, 0x42, 0xad,
+	0x7b, 0x65, 0x5f, 0x2a, 0x47, 0x2a, 0x9e, 0xda, 0xbe, 0xeb, 0x4d, 0x38, 0x57, 0x68, 0x73, 0x25,
+	0x1d, 0x21, 0xdb, 0x2e, 0x43, 0x49, 0x07, 0xf8, 0x65, 0x0d, 0xda, 0xa2, 0x30, 0xa5, 0xbe, 0x8f,
+	0xce, 0x6a, 0xab, 0x7e, 0xa9, 0x97, 0x9c, 0xf6, 0x7a, 0x29, 0x4e, 0xb1, 0x9f, 0x16, 0x56, 0xf4,
+	0x42, 0xbc, 0xd2, 0xae, 0x81, 0x7d, 0xa9, 0x1c, 0xa9, 0xb8, 0x54, 0x21, 0xae, 0xe6, 0x0a, 0x74,
+	0xdb, 0x2e, 0x43, 0xa9, 0x91, 0x8c, 0x27, 0xce, 0x99, 0xed, 0x14, 0x0b, 0x68, 0x7b, 0xbd, 0x14,
+	0x27, 0xf5, 0xf3, 0x29, 0x2c, 0x28, 0x75, 0x66, 0x62, 0x7d, 0x08, 0x0d, 0x56, 0x7c, 0x5a, 0x78,
+	0xd2, 0x15, 0xf5, 0xad, 0x7d, 0xb9, 0x0a, 0xad, 0x6a, 0xbe, 0xc1, 0xde, 0x7c, 0xad, 0x6f, 0x88,
+	0x2b, 0x08, 0xad, 0xb0, 0xf0, 0xc8, 0x6d, 0x0f, 0x8a, 0x08, 0x2d, 0xf2, 0x2b, 0x4f, 0x73, 0x32,
+	0xf2, 0x17, 0xdf, 0x10, 0x6d, 0xbb, 0x0c, 0x25, 0x09, 0xdd, 0xe6, 0x9f, 0x36, 0x70, 0x78, 0x62,
+	0x0d, 0x32, 0xe5, 0xea, 0x0f, 0x74, 0xf6, 0xc5, 0x12, 0x8c, 0xca, 0x8e, 0xfa, 0x0a, 0x26, 0xd8,
+	0x29, 0x79, 0x69, 0xb3, 0xed, 0x32, 0x94, 0xd4, 0xcf, 0x4f, 0x6b, 0xd0, 0xc2, 0x22, 0xcc, 0xda,
+	0x92, 0x86, 0xa9, 0x49, 0xa7, 0x55, 0x3e, 0xb6, 0x5d, 0x86, 0x52, 0x02, 0x2c, 0x37, 0x27, 0x45,
+	0x2c, 0xbd, 0x80, 0xb4, 0x2f, 0x96, 0x60, 0xe4, 0xf2, 0x2d, 0x69, 0x4c, 0x17, 0x55, 0x83, 0x29,
+	0xe5, 0xa0, 0xb4, 0x5c, 0x63, 0x24, 0x78, 0x79, 0x25, 0x48, 0x94, 0x94, 0x6f, 0xb6, 0x5d, 0x86,
+	0x92, 0x3a, 0xb9, 0x0f, 0x0d, 0x96, 0x3a, 0x5b, 0x3b, 0x28, 0xcd, 0x86, 0xc2, 0x73, 0xb1, 0x1e,
+	0xb1, 0x2f, 0x57, 0xa1, 0x25, 0xc5, 0x5f, 0x1b, 0xd0, 0x55, 0x92, 0x3d, 0x6b, 0x08, 0x4d, 0x9e,
+	0x82, 0x0a, 0xd2, 0x15, 0xc9, 0xae, 0x7d, 0xb9, 0x0a, 0x2d, 0xe5, 0xbd, 0x83, 0x3c, 0xaa, 0x71,
+	0xac, 0x90, 0x99, 0xda, 0x1b, 0x15, 0x58, 0x41, 0xe6, 0x83, 0x37, 0x3e, 0x7b, 0xed, 0x30, 0x48,
+	0x1f, 0x4f, 0x1f, 0x5d, 0xf3, 0xa3, 0xa3, 0xeb, 0x47, 0x81, 0x1f, 0x47, 0xf8, 0x7b, 0xfc, 0xe6,
+	0x75, 0xf6, 0x4d, 0x30, 0xfb, 0x3c, 0xf8, 0x06, 0xfd, 0x79, 0xd4, 0x64, 0x80, 0x37, 0xff, 0x33,
+	0x00, 0xc3, 0x2b, 0xe1, 0xcc, 0x37, 0x2c, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// AuthClient is the client API for Auth service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AuthClient interface {
+	Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error)
+	Inspect(ctx context.Context, in *InspectRequest, opts ...grpc.CallOption) (*InspectResponse, error)
+	Token(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*TokenResponse, error)
+}
+
+type authClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAuthClient(cc *grpc.ClientConn) AuthClient {
+	return &authClient{cc}
+}
+
+func (c *authClient) Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error) {
+	out := new(GenerateResponse)
+	err := c.cc.Invoke(ctx, "/auth.Auth/Generate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) Inspect(ctx context.Context, in *InspectRequest, opts ...grpc.CallOption) (*InspectResponse, error) {
+	out := new(InspectResponse)
+	err := c.cc.Invoke(ctx, "/auth.Auth/Inspect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) Token(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*TokenResponse, error) {
+	out := new(TokenResponse)
+	err := c.cc.Invoke(ctx, "/auth.Auth/Token", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AuthServer is the server API for Auth service.
+type AuthServer interface {
+	Generate(context.Context, *GenerateRequest) (*GenerateResponse, error)
+	Inspect(context.Context, *InspectRequest) (*InspectResponse, error)
+	Token(context.Context, *TokenRequest) (*TokenResponse, error)
+}
+
+func RegisterAuthServer(s *grpc.Server, srv AuthServer) {
+	s.RegisterService(&_Auth_serviceDesc, srv)
+}
+
+func _Auth_Generate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).Generate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Auth/Generate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).Generate(ctx, req.(*GenerateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_Inspect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InspectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).Inspect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Auth/Inspect",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).Inspect(ctx, req.(*InspectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_Token_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).Token(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Auth/Token",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).Token(ctx, req.(*TokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Auth_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.Auth",
+	HandlerType: (*AuthServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Generate",
+			Handler:    _Auth_Generate_Handler,
+		},
+		{
+			MethodName: "Inspect",
+			Handler:    _Auth_Inspect_Handler,
+		},
+		{
+			MethodName: "Token",
+			Handler:    _Auth_Token_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "auth/auth.proto",
+}
+
+// AccountsClient is the client API for Accounts service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AccountsClient interface {
+	List(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error)
+	Delete(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*DeleteAccountResponse, error)
+	ChangeSecret(ctx context.Context, in *ChangeSecretRequest, opts ...grpc.CallOption) (*ChangeSecretResponse, error)
+	Unlock(ctx context.Context, in *UnlockAccountRequest, opts ...grpc.CallOption) (*UnlockAccountResponse, error)
+}
+
+type accountsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAccountsClient(cc *grpc.ClientConn) AccountsClient {
+	return &accountsClient{cc}
+}
+
+func (c *accountsClient) List(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error) {
+	out := new(ListAccountsResponse)
+	err := c.cc.Invoke(ctx, "/auth.Accounts/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) Delete(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*DeleteAccountResponse, error) {
+	out := new(DeleteAccountResponse)
+	err := c.cc.Invoke(ctx, "/auth.Accounts/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) ChangeSecret(ctx context.Context, in *ChangeSecretRequest, opts ...grpc.CallOption) (*ChangeSecretResponse, error) {
+	out := new(ChangeSecretResponse)
+	err := c.cc.Invoke(ctx, "/auth.Accounts/ChangeSecret", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) Unlock(ctx context.Context, in *UnlockAccountRequest, opts ...grpc.CallOption) (*UnlockAccountResponse, error) {
+	out := new(UnlockAccountResponse)
+	err := c.cc.Invoke(ctx, "/auth.Accounts/Unlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AccountsServer is the server API for Accounts service.
+type AccountsServer interface {
+	List(context.Context, *ListAccountsRequest) (*ListAccountsResponse, error)
+	Delete(context.Context, *DeleteAccountRequest) (*DeleteAccountResponse, error)
+	ChangeSecret(context.Context, *ChangeSecretRequest) (*ChangeSecretResponse, error)
+	Unlock(context.Context, *UnlockAccountRequest) (*UnlockAccountResponse, error)
+}
+
+func RegisterAccountsServer(s *grpc.Server, srv AccountsServer) {
 	s.RegisterService(&_Accounts_serviceDesc, srv)
 }
 
-func _Accounts_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListAccountsRequest)
+func _Accounts_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Accounts/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).List(ctx, req.(*ListAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Accounts/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).Delete(ctx, req.(*DeleteAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_ChangeSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).ChangeSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Accounts/ChangeSecret",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).ChangeSecret(ctx, req.(*ChangeSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_Unlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).Unlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Accounts/Unlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).Unlock(ctx, req.(*UnlockAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Accounts_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.Accounts",
+	HandlerType: (*AccountsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "List",
+			Handler:    _Accounts_List_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Accounts_Delete_Handler,
+		},
+		{
+			MethodName: "ChangeSecret",
+			Handler:    _Accounts_ChangeSecret_Handler,
+		},
+		{
+			MethodName: "Unlock",
+			Handler:    _Accounts_Unlock_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "auth/auth.proto",
+}
+
+// RulesClient is the client API for Rules service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type RulesClient interface {
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+}
+
+type rulesClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewRulesClient(cc *grpc.ClientConn) RulesClient {
+	return &rulesClient{cc}
+}
+
+func (c *rulesClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error) {
+	out := new(CreateResponse)
+	err := c.cc.Invoke(ctx, "/auth.Rules/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rulesClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/auth.Rules/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rulesClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := c.cc.Invoke(ctx, "/auth.Rules/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RulesServer is the server API for Rules service.
+type RulesServer interface {
+	Create(context.Context, *CreateRequest) (*CreateResponse, error)
+	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	List(context.Context, *ListRequest) (*ListResponse, error)
+}
+
+func RegisterRulesServer(s *grpc.Server, srv RulesServer) {
+	s.RegisterService(&_Rules_serviceDesc, srv)
+}
+
+func _Rules_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RulesServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Rules/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RulesServer).Create(ctx, req.(*CreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Rules_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RulesServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Rules/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RulesServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Rules_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RulesServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Rules/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RulesServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Rules_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.Rules",
+	HandlerType: (*RulesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _Rules_Create_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Rules_Delete_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _Rules_List_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "auth/auth.proto",
+}
+
+// SCIMClient is the client API for SCIM service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SCIMClient interface {
+	CreateUser(ctx context.Context, in *SCIMUserRequest, opts ...grpc.CallOption) (*SCIMUserResponse, error)
+	ReadUser(ctx context.Context, in *ReadSCIMUserRequest, opts ...grpc.CallOption) (*SCIMUserResponse, error)
+	UpdateUser(ctx context.Context, in *SCIMUserRequest, opts ...grpc.CallOption) (*SCIMUserResponse, error)
+	DeleteUser(ctx context.Context, in *DeleteSCIMUserRequest, opts ...grpc.CallOption) (*DeleteSCIMUserResponse, error)
+	ListUsers(ctx context.Context, in *ListSCIMUsersRequest, opts ...grpc.CallOption) (*ListSCIMUsersResponse, error)
+	CreateGroup(ctx context.Context, in *SCIMGroupRequest, opts ...grpc.CallOption) (*SCIMGroupResponse, error)
+	ReadGroup(ctx context.Context, in *ReadSCIMGroupRequest, opts ...grpc.CallOption) (*SCIMGroupResponse, error)
+	UpdateGroup(ctx context.Context, in *SCIMGroupRequest, opts ...grpc.CallOption) (*SCIMGroupResponse, error)
+	DeleteGroup(ctx context.Context, in *DeleteSCIMGroupRequest, opts ...grpc.CallOption) (*DeleteSCIMGroupResponse, error)
+	ListGroups(ctx context.Context, in *ListSCIMGroupsRequest, opts ...grpc.CallOption) (*ListSCIMGroupsResponse, error)
+	MapGroup(ctx context.Context, in *MapSCIMGroupRequest, opts ...grpc.CallOption) (*MapSCIMGroupResponse, error)
+}
+
+type sCIMClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewSCIMClient(cc *grpc.ClientConn) SCIMClient {
+	return &sCIMClient{cc}
+}
+
+func (c *sCIMClient) CreateUser(ctx context.Context, in *SCIMUserRequest, opts ...grpc.CallOption) (*SCIMUserResponse, error) {
+	out := new(SCIMUserResponse)
+	err := c.cc.Invoke(ctx, "/auth.SCIM/CreateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sCIMClient) ReadUser(ctx context.Context, in *ReadSCIMUserRequest, opts ...grpc.CallOption) (*SCIMUserResponse, error) {
+	out := new(SCIMUserResponse)
+	err := c.cc.Invoke(ctx, "/auth.SCIM/ReadUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sCIMClient) UpdateUser(ctx context.Context, in *SCIMUserRequest, opts ...grpc.CallOption) (*SCIMUserResponse, error) {
+	out := new(SCIMUserResponse)
+	err := c.cc.Invoke(ctx, "/auth.SCIM/UpdateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sCIMClient) DeleteUser(ctx context.Context, in *DeleteSCIMUserRequest, opts ...grpc.CallOption) (*DeleteSCIMUserResponse, error) {
+	out := new(DeleteSCIMUserResponse)
+	err := c.cc.Invoke(ctx, "/auth.SCIM/DeleteUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sCIMClient) ListUsers(ctx context.Context, in *ListSCIMUsersRequest, opts ...grpc.CallOption) (*ListSCIMUsersResponse, error) {
+	out := new(ListSCIMUsersResponse)
+	err := c.cc.Invoke(ctx, "/auth.SCIM/ListUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sCIMClient) CreateGroup(ctx context.Context, in *SCIMGroupRequest, opts ...grpc.CallOption) (*SCIMGroupResponse, error) {
+	out := new(SCIMGroupResponse)
+	err := c.cc.Invoke(ctx, "/auth.SCIM/CreateGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sCIMClient) ReadGroup(ctx context.Context, in *ReadSCIMGroupRequest, opts ...grpc.CallOption) (*SCIMGroupResponse, error) {
+	out := new(SCIMGroupResponse)
+	err := c.cc.Invoke(ctx, "/auth.SCIM/ReadGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sCIMClient) UpdateGroup(ctx context.Context, in *SCIMGroupRequest, opts ...grpc.CallOption) (*SCIMGroupResponse, error) {
+	out := new(SCIMGroupResponse)
+	err := c.cc.Invoke(ctx, "/auth.SCIM/UpdateGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sCIMClient) DeleteGroup(ctx context.Context, in *DeleteSCIMGroupRequest, opts ...grpc.CallOption) (*DeleteSCIMGroupResponse, error) {
+	out := new(DeleteSCIMGroupResponse)
+	err := c.cc.Invoke(ctx, "/auth.SCIM/DeleteGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sCIMClient) ListGroups(ctx context.Context, in *ListSCIMGroupsRequest, opts ...grpc.CallOption) (*ListSCIMGroupsResponse, error) {
+	out := new(ListSCIMGroupsResponse)
+	err := c.cc.Invoke(ctx, "/auth.SCIM/ListGroups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sCIMClient) MapGroup(ctx context.Context, in *MapSCIMGroupRequest, opts ...grpc.CallOption) (*MapSCIMGroupResponse, error) {
+	out := new(MapSCIMGroupResponse)
+	err := c.cc.Invoke(ctx, "/auth.SCIM/MapGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SCIMServer is the server API for SCIM service.
+type SCIMServer interface {
+	CreateUser(context.Context, *SCIMUserRequest) (*SCIMUserResponse, error)
+	ReadUser(context.Context, *ReadSCIMUserRequest) (*SCIMUserResponse, error)
+	UpdateUser(context.Context, *SCIMUserRequest) (*SCIMUserResponse, error)
+	DeleteUser(context.Context, *DeleteSCIMUserRequest) (*DeleteSCIMUserResponse, error)
+	ListUsers(context.Context, *ListSCIMUsersRequest) (*ListSCIMUsersResponse, error)
+	CreateGroup(context.Context, *SCIMGroupRequest) (*SCIMGroupResponse, error)
+	ReadGroup(context.Context, *ReadSCIMGroupRequest) (*SCIMGroupResponse, error)
+	UpdateGroup(context.Context, *SCIMGroupRequest) (*SCIMGroupResponse, error)
+	DeleteGroup(context.Context, *DeleteSCIMGroupRequest) (*DeleteSCIMGroupResponse, error)
+	ListGroups(context.Context, *ListSCIMGroupsRequest) (*ListSCIMGroupsResponse, error)
+	MapGroup(context.Context, *MapSCIMGroupRequest) (*MapSCIMGroupResponse, error)
+}
+
+func RegisterSCIMServer(s *grpc.Server, srv SCIMServer) {
+	s.RegisterService(&_SCIM_serviceDesc, srv)
+}
+
+func _SCIM_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SCIMUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SCIMServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.SCIM/CreateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SCIMServer).CreateUser(ctx, req.(*SCIMUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SCIM_ReadUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadSCIMUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SCIMServer).ReadUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.SCIM/ReadUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SCIMServer).ReadUser(ctx, req.(*ReadSCIMUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SCIM_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SCIMUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SCIMServer).UpdateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.SCIM/UpdateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SCIMServer).UpdateUser(ctx, req.(*SCIMUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SCIM_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSCIMUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SCIMServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.SCIM/DeleteUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SCIMServer).DeleteUser(ctx, req.(*DeleteSCIMUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SCIM_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSCIMUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SCIMServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.SCIM/ListUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SCIMServer).ListUsers(ctx, req.(*ListSCIMUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SCIM_CreateGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SCIMGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SCIMServer).CreateGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.SCIM/CreateGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SCIMServer).CreateGroup(ctx, req.(*SCIMGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SCIM_ReadGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadSCIMGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SCIMServer).ReadGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.SCIM/ReadGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SCIMServer).ReadGroup(ctx, req.(*ReadSCIMGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SCIM_UpdateGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SCIMGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SCIMServer).UpdateGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.SCIM/UpdateGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SCIMServer).UpdateGroup(ctx, req.(*SCIMGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SCIM_DeleteGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSCIMGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SCIMServer).DeleteGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.SCIM/DeleteGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SCIMServer).DeleteGroup(ctx, req.(*DeleteSCIMGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SCIM_ListGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSCIMGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SCIMServer).ListGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.SCIM/ListGroups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SCIMServer).ListGroups(ctx, req.(*ListSCIMGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SCIM_MapGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MapSCIMGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SCIMServer).MapGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.SCIM/MapGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SCIMServer).MapGroup(ctx, req.(*MapSCIMGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _SCIM_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.SCIM",
+	HandlerType: (*SCIMServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateUser",
+			Handler:    _SCIM_CreateUser_Handler,
+		},
+		{
+			MethodName: "ReadUser",
+			Handler:    _SCIM_ReadUser_Handler,
+		},
+		{
+			MethodName: "UpdateUser",
+			Handler:    _SCIM_UpdateUser_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _SCIM_DeleteUser_Handler,
+		},
+		{
+			MethodName: "ListUsers",
+			Handler:    _SCIM_ListUsers_Handler,
+		},
+		{
+			MethodName: "CreateGroup",
+			Handler:    _SCIM_CreateGroup_Handler,
+		},
+		{
+			MethodName: "ReadGroup",
+			Handler:    _SCIM_ReadGroup_Handler,
+		},
+		{
+			MethodName: "UpdateGroup",
+			Handler:    _SCIM_UpdateGroup_Handler,
+		},
+		{
+			MethodName: "DeleteGroup",
+			Handler:    _SCIM_DeleteGroup_Handler,
+		},
+		{
+			MethodName: "ListGroups",
+			Handler:    _SCIM_ListGroups_Handler,
+		},
+		{
+			MethodName: "MapGroup",
+			Handler:    _SCIM_MapGroup_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "auth/auth.proto",
+}
+
+// SessionsClient is the client API for Sessions service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SessionsClient interface {
+	Create(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*CreateSessionResponse, error)
+	Refresh(ctx context.Context, in *RefreshSessionRequest, opts ...grpc.CallOption) (*RefreshSessionResponse, error)
+	List(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	Revoke(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error)
+}
+
+type sessionsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewSessionsClient(cc *grpc.ClientConn) SessionsClient {
+	return &sessionsClient{cc}
+}
+
+func (c *sessionsClient) Create(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*CreateSessionResponse, error) {
+	out := new(CreateSessionResponse)
+	err := c.cc.Invoke(ctx, "/auth.Sessions/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionsClient) Refresh(ctx context.Context, in *RefreshSessionRequest, opts ...grpc.CallOption) (*RefreshSessionResponse, error) {
+	out := new(RefreshSessionResponse)
+	err := c.cc.Invoke(ctx, "/auth.Sessions/Refresh", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionsClient) List(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, "/auth.Sessions/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionsClient) Revoke(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error) {
+	out := new(RevokeSessionResponse)
+	err := c.cc.Invoke(ctx, "/auth.Sessions/Revoke", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SessionsServer is the server API for Sessions service.
+type SessionsServer interface {
+	Create(context.Context, *CreateSessionRequest) (*CreateSessionResponse, error)
+	Refresh(context.Context, *RefreshSessionRequest) (*RefreshSessionResponse, error)
+	List(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	Revoke(context.Context, *RevokeSessionRequest) (*RevokeSessionResponse, error)
+}
+
+func RegisterSessionsServer(s *grpc.Server, srv SessionsServer) {
+	s.RegisterService(&_Sessions_serviceDesc, srv)
+}
+
+func _Sessions_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionsServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Sessions/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionsServer).Create(ctx, req.(*CreateSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sessions_Refresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionsServer).Refresh(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Sessions/Refresh",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionsServer).Refresh(ctx, req.(*RefreshSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sessions_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionsServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Sessions/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionsServer).List(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sessions_Revoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionsServer).Revoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Sessions/Revoke",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionsServer).Revoke(ctx, req.(*RevokeSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Sessions_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.Sessions",
+	HandlerType: (*SessionsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _Sessions_Create_Handler,
+		},
+		{
+			MethodName: "Refresh",
+			Handler:    _Sessions_Refresh_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _Sessions_List_Handler,
+		},
+		{
+			MethodName: "Revoke",
+			Handler:    _Sessions_Revoke_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "auth/auth.proto",
+}
+
+// CertificatesClient is the client API for Certificates service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type CertificatesClient interface {
+	Issue(ctx context.Context, in *IssueCertificateRequest, opts ...grpc.CallOption) (*IssueCertificateResponse, error)
+}
+
+type certificatesClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewCertificatesClient(cc *grpc.ClientConn) CertificatesClient {
+	return &certificatesClient{cc}
+}
+
+func (c *certificatesClient) Issue(ctx context.Context, in *IssueCertificateRequest, opts ...grpc.CallOption) (*IssueCertificateResponse, error) {
+	out := new(IssueCertificateResponse)
+	err := c.cc.Invoke(ctx, "/auth.Certificates/Issue", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CertificatesServer is the server API for Certificates service.
+type CertificatesServer interface {
+	Issue(context.Context, *IssueCertificateRequest) (*IssueCertificateResponse, error)
+}
+
+func RegisterCertificatesServer(s *grpc.Server, srv CertificatesServer) {
+	s.RegisterService(&_Certificates_serviceDesc, srv)
+}
+
+func _Certificates_Issue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IssueCertificateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CertificatesServer).Issue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Certificates/Issue",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CertificatesServer).Issue(ctx, req.(*IssueCertificateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Certificates_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.Certificates",
+	HandlerType: (*CertificatesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Issue",
+			Handler:    _Certificates_Issue_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "auth/auth.proto",
+}
+
+// OAuthClient is the client API for OAuth service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type OAuthClient interface {
+	Token(ctx context.Context, in *OAuthTokenRequest, opts ...grpc.CallOption) (*OAuthTokenResponse, error)
+	CreateClient(ctx context.Context, in *CreateClientRequest, opts ...grpc.CallOption) (*CreateClientResponse, error)
+	ListClients(ctx context.Context, in *ListClientsRequest, opts ...grpc.CallOption) (*ListClientsResponse, error)
+	DeleteClient(ctx context.Context, in *DeleteClientRequest, opts ...grpc.CallOption) (*DeleteClientResponse, error)
+}
+
+type oAuthClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewOAuthClient(cc *grpc.ClientConn) OAuthClient {
+	return &oAuthClient{cc}
+}
+
+func (c *oAuthClient) Token(ctx context.Context, in *OAuthTokenRequest, opts ...grpc.CallOption) (*OAuthTokenResponse, error) {
+	out := new(OAuthTokenResponse)
+	err := c.cc.Invoke(ctx, "/auth.OAuth/Token", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oAuthClient) CreateClient(ctx context.Context, in *CreateClientRequest, opts ...grpc.CallOption) (*CreateClientResponse, error) {
+	out := new(CreateClientResponse)
+	err := c.cc.Invoke(ctx, "/auth.OAuth/CreateClient", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oAuthClient) ListClients(ctx context.Context, in *ListClientsRequest, opts ...grpc.CallOption) (*ListClientsResponse, error) {
+	out := new(ListClientsResponse)
+	err := c.cc.Invoke(ctx, "/auth.OAuth/ListClients", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oAuthClient) DeleteClient(ctx context.Context, in *DeleteClientRequest, opts ...grpc.CallOption) (*DeleteClientResponse, error) {
+	out := new(DeleteClientResponse)
+	err := c.cc.Invoke(ctx, "/auth.OAuth/DeleteClient", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OAuthServer is the server API for OAuth service.
+type OAuthServer interface {
+	Token(context.Context, *OAuthTokenRequest) (*OAuthTokenResponse, error)
+	CreateClient(context.Context, *CreateClientRequest) (*CreateClientResponse, error)
+	ListClients(context.Context, *ListClientsRequest) (*ListClientsResponse, error)
+	DeleteClient(context.Context, *DeleteClientRequest) (*DeleteClientResponse, error)
+}
+
+func RegisterOAuthServer(s *grpc.Server, srv OAuthServer) {
+	s.RegisterService(&_OAuth_serviceDesc, srv)
+}
+
+func _OAuth_Token_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OAuthTokenRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AccountsServer).List(ctx, in)
+		return srv.(OAuthServer).Token(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/auth.Accounts/List",
+		FullMethod: "/auth.OAuth/Token",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AccountsServer).List(ctx, req.(*ListAccountsRequest))
+		return srv.(OAuthServer).Token(ctx, req.(*OAuthTokenRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Accounts_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteAccountRequest)
+func _OAuth_CreateClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateClientRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AccountsServer).Delete(ctx, in)
+		return srv.(OAuthServer).CreateClient(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/auth.Accounts/Delete",
+		FullMethod: "/auth.OAuth/CreateClient",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AccountsServer).Delete(ctx, req.(*DeleteAccountRequest))
+		return srv.(OAuthServer).CreateClient(ctx, req.(*CreateClientRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Accounts_ChangeSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChangeSecretRequest)
+func _OAuth_ListClients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListClientsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AccountsServer).ChangeSecret(ctx, in)
+		return srv.(OAuthServer).ListClients(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/auth.Accounts/ChangeSecret",
+		FullMethod: "/auth.OAuth/ListClients",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AccountsServer).ChangeSecret(ctx, req.(*ChangeSecretRequest))
+		return srv.(OAuthServer).ListClients(ctx, req.(*ListClientsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _Accounts_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "auth.Accounts",
-	HandlerType: (*AccountsServer)(nil),
+func _OAuth_DeleteClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteClientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OAuthServer).DeleteClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.OAuth/DeleteClient",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OAuthServer).DeleteClient(ctx, req.(*DeleteClientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _OAuth_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.OAuth",
+	HandlerType: (*OAuthServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "List",
-			Handler:    _Accounts_List_Handler,
+			MethodName: "Token",
+			Handler:    _OAuth_Token_Handler,
 		},
 		{
-			MethodName: "Delete",
-			Handler:    _Accounts_Delete_Handler,
+			MethodName: "CreateClient",
+			Handler:    _OAuth_CreateClient_Handler,
 		},
 		{
-			MethodName: "ChangeSecret",
-			Handler:    _Accounts_ChangeSecret_Handler,
+			MethodName: "ListClients",
+			Handler:    _OAuth_ListClients_Handler,
+		},
+		{
+			MethodName: "DeleteClient",
+			Handler:    _OAuth_DeleteClient_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "auth/auth.proto",
 }
 
-// RulesClient is the client API for Rules service.
+// APIKeysClient is the client API for APIKeys service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type RulesClient interface {
-	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
-	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
-	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+type APIKeysClient interface {
+	Create(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error)
+	List(ctx context.Context, in *ListAPIKeysRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error)
+	Revoke(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*RevokeAPIKeyResponse, error)
+	Verify(ctx context.Context, in *VerifyAPIKeyRequest, opts ...grpc.CallOption) (*VerifyAPIKeyResponse, error)
 }
 
-type rulesClient struct {
+type aPIKeysClient struct {
 	cc *grpc.ClientConn
 }
 
-func NewRulesClient(cc *grpc.ClientConn) RulesClient {
-	return &rulesClient{cc}
+func NewAPIKeysClient(cc *grpc.ClientConn) APIKeysClient {
+	return &aPIKeysClient{cc}
 }
 
-func (c *rulesClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error) {
-	out := new(CreateResponse)
-	err := c.cc.Invoke(ctx, "/auth.Rules/Create", in, out, opts...)
+func (c *aPIKeysClient) Create(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error) {
+	out := new(CreateAPIKeyResponse)
+	err := c.cc.Invoke(ctx, "/auth.APIKeys/Create", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *rulesClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
-	out := new(DeleteResponse)
-	err := c.cc.Invoke(ctx, "/auth.Rules/Delete", in, out, opts...)
+func (c *aPIKeysClient) List(ctx context.Context, in *ListAPIKeysRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error) {
+	out := new(ListAPIKeysResponse)
+	err := c.cc.Invoke(ctx, "/auth.APIKeys/List", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *rulesClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
-	out := new(ListResponse)
-	err := c.cc.Invoke(ctx, "/auth.Rules/List", in, out, opts...)
+func (c *aPIKeysClient) Revoke(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*RevokeAPIKeyResponse, error) {
+	out := new(RevokeAPIKeyResponse)
+	err := c.cc.Invoke(ctx, "/auth.APIKeys/Revoke", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// RulesServer is the server API for Rules service.
-type RulesServer interface {
-	Create(context.Context, *CreateRequest) (*CreateResponse, error)
-	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
-	List(context.Context, *ListRequest) (*ListResponse, error)
+func (c *aPIKeysClient) Verify(ctx context.Context, in *VerifyAPIKeyRequest, opts ...grpc.CallOption) (*VerifyAPIKeyResponse, error) {
+	out := new(VerifyAPIKeyResponse)
+	err := c.cc.Invoke(ctx, "/auth.APIKeys/Verify", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func RegisterRulesServer(s *grpc.Server, srv RulesServer) {
-	s.RegisterService(&_Rules_serviceDesc, srv)
+// APIKeysServer is the server API for APIKeys service.
+type APIKeysServer interface {
+	Create(context.Context, *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error)
+	List(context.Context, *ListAPIKeysRequest) (*ListAPIKeysResponse, error)
+	Revoke(context.Context, *RevokeAPIKeyRequest) (*RevokeAPIKeyResponse, error)
+	Verify(context.Context, *VerifyAPIKeyRequest) (*VerifyAPIKeyResponse, error)
 }
 
-func _Rules_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateRequest)
+func RegisterAPIKeysServer(s *grpc.Server, srv APIKeysServer) {
+	s.RegisterService(&_APIKeys_serviceDesc, srv)
+}
+
+func _APIKeys_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAPIKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RulesServer).Create(ctx, in)
+		return srv.(APIKeysServer).Create(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/auth.Rules/Create",
+		FullMethod: "/auth.APIKeys/Create",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RulesServer).Create(ctx, req.(*CreateRequest))
+		return srv.(APIKeysServer).Create(ctx, req.(*CreateAPIKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Rules_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteRequest)
+func _APIKeys_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAPIKeysRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RulesServer).Delete(ctx, in)
+		return srv.(APIKeysServer).List(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/auth.Rules/Delete",
+		FullMethod: "/auth.APIKeys/List",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RulesServer).Delete(ctx, req.(*DeleteRequest))
+		return srv.(APIKeysServer).List(ctx, req.(*ListAPIKeysRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Rules_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListRequest)
+func _APIKeys_Revoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAPIKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RulesServer).List(ctx, in)
+		return srv.(APIKeysServer).Revoke(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/auth.Rules/List",
+		FullMethod: "/auth.APIKeys/Revoke",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RulesServer).List(ctx, req.(*ListRequest))
+		return srv.(APIKeysServer).Revoke(ctx, req.(*RevokeAPIKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _Rules_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "auth.Rules",
-	HandlerType: (*RulesServer)(nil),
+func _APIKeys_Verify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIKeysServer).Verify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.APIKeys/Verify",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIKeysServer).Verify(ctx, req.(*VerifyAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _APIKeys_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.APIKeys",
+	HandlerType: (*APIKeysServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
 			MethodName: "Create",
-			Handler:    _Rules_Create_Handler,
+			Handler:    _APIKeys_Create_Handler,
 		},
 		{
-			MethodName: "Delete",
-			Handler:    _Rules_Delete_Handler,
+			MethodName: "List",
+			Handler:    _APIKeys_List_Handler,
+		},
+		{
+			MethodName: "Revoke",
+			Handler:    _APIKeys_Revoke_Handler,
+		},
+		{
+			MethodName: "Verify",
+			Handler:    _APIKeys_Verify_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "auth/auth.proto",
+}
+
+// AuditClient is the client API for Audit service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AuditClient interface {
+	List(ctx context.Context, in *ListAuditRecordsRequest, opts ...grpc.CallOption) (*ListAuditRecordsResponse, error)
+}
+
+type auditClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAuditClient(cc *grpc.ClientConn) AuditClient {
+	return &auditClient{cc}
+}
+
+func (c *auditClient) List(ctx context.Context, in *ListAuditRecordsRequest, opts ...grpc.CallOption) (*ListAuditRecordsResponse, error) {
+	out := new(ListAuditRecordsResponse)
+	err := c.cc.Invoke(ctx, "/auth.Audit/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AuditServer is the server API for Audit service.
+type AuditServer interface {
+	List(context.Context, *ListAuditRecordsRequest) (*ListAuditRecordsResponse, error)
+}
+
+func RegisterAuditServer(s *grpc.Server, srv AuditServer) {
+	s.RegisterService(&_Audit_serviceDesc, srv)
+}
+
+func _Audit_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuditServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Audit/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuditServer).List(ctx, req.(*ListAuditRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Audit_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.Audit",
+	HandlerType: (*AuditServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "List",
+			Handler:    _Audit_List_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "auth/auth.proto",
+}
+
+// SigningKeysClient is the client API for SigningKeys service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SigningKeysClient interface {
+	Rotate(ctx context.Context, in *RotateSigningKeyRequest, opts ...grpc.CallOption) (*RotateSigningKeyResponse, error)
+	List(ctx context.Context, in *ListSigningKeysRequest, opts ...grpc.CallOption) (*ListSigningKeysResponse, error)
+}
+
+type signingKeysClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewSigningKeysClient(cc *grpc.ClientConn) SigningKeysClient {
+	return &signingKeysClient{cc}
+}
+
+func (c *signingKeysClient) Rotate(ctx context.Context, in *RotateSigningKeyRequest, opts ...grpc.CallOption) (*RotateSigningKeyResponse, error) {
+	out := new(RotateSigningKeyResponse)
+	err := c.cc.Invoke(ctx, "/auth.SigningKeys/Rotate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signingKeysClient) List(ctx context.Context, in *ListSigningKeysRequest, opts ...grpc.CallOption) (*ListSigningKeysResponse, error) {
+	out := new(ListSigningKeysResponse)
+	err := c.cc.Invoke(ctx, "/auth.SigningKeys/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SigningKeysServer is the server API for SigningKeys service.
+type SigningKeysServer interface {
+	Rotate(context.Context, *RotateSigningKeyRequest) (*RotateSigningKeyResponse, error)
+	List(context.Context, *ListSigningKeysRequest) (*ListSigningKeysResponse, error)
+}
+
+func RegisterSigningKeysServer(s *grpc.Server, srv SigningKeysServer) {
+	s.RegisterService(&_SigningKeys_serviceDesc, srv)
+}
+
+func _SigningKeys_Rotate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateSigningKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SigningKeysServer).Rotate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.SigningKeys/Rotate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SigningKeysServer).Rotate(ctx, req.(*RotateSigningKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SigningKeys_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSigningKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SigningKeysServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.SigningKeys/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SigningKeysServer).List(ctx, req.(*ListSigningKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _SigningKeys_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.SigningKeys",
+	HandlerType: (*SigningKeysServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Rotate",
+			Handler:    _SigningKeys_Rotate_Handler,
 		},
 		{
 			MethodName: "List",
-			Handler:    _Rules_List_Handler,
+			Handler:    _SigningKeys_List_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
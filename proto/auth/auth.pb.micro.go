@@ -138,6 +138,7 @@ type AccountsService interface {
 	List(ctx context.Context, in *ListAccountsRequest, opts ...client.CallOption) (*ListAccountsResponse, error)
 	Delete(ctx context.Context, in *DeleteAccountRequest, opts ...client.CallOption) (*DeleteAccountResponse, error)
 	ChangeSecret(ctx context.Context, in *ChangeSecretRequest, opts ...client.CallOption) (*ChangeSecretResponse, error)
+	Unlock(ctx context.Context, in *UnlockAccountRequest, opts ...client.CallOption) (*UnlockAccountResponse, error)
 }
 
 type accountsService struct {
@@ -182,12 +183,23 @@ func (c *accountsService) ChangeSecret(ctx context.Context, in *ChangeSecretRequ
 	return out, nil
 }
 
+func (c *accountsService) Unlock(ctx context.Context, in *UnlockAccountRequest, opts ...client.CallOption) (*UnlockAccountResponse, error) {
+	req := c.c.NewRequest(c.name, "Accounts.Unlock", in)
+	out := new(UnlockAccountResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Accounts service
 
 type AccountsHandler interface {
 	List(context.Context, *ListAccountsRequest, *ListAccountsResponse) error
 	Delete(context.Context, *DeleteAccountRequest, *DeleteAccountResponse) error
 	ChangeSecret(context.Context, *ChangeSecretRequest, *ChangeSecretResponse) error
+	Unlock(context.Context, *UnlockAccountRequest, *UnlockAccountResponse) error
 }
 
 func RegisterAccountsHandler(s server.Server, hdlr AccountsHandler, opts ...server.HandlerOption) error {
@@ -195,6 +207,7 @@ func RegisterAccountsHandler(s server.Server, hdlr AccountsHandler, opts ...serv
 		List(ctx context.Context, in *ListAccountsRequest, out *ListAccountsResponse) error
 		Delete(ctx context.Context, in *DeleteAccountRequest, out *DeleteAccountResponse) error
 		ChangeSecret(ctx context.Context, in *ChangeSecretRequest, out *ChangeSecretResponse) error
+		Unlock(ctx context.Context, in *UnlockAccountRequest, out *UnlockAccountResponse) error
 	}
 	type Accounts struct {
 		accounts
@@ -219,6 +232,10 @@ func (h *accountsHandler) ChangeSecret(ctx context.Context, in *ChangeSecretRequ
 	return h.AccountsHandler.ChangeSecret(ctx, in, out)
 }
 
+func (h *accountsHandler) Unlock(ctx context.Context, in *UnlockAccountRequest, out *UnlockAccountResponse) error {
+	return h.AccountsHandler.Unlock(ctx, in, out)
+}
+
 // Api Endpoints for Rules service
 
 func NewRulesEndpoints() []*api.Endpoint {
@@ -311,3 +328,756 @@ func (h *rulesHandler) Delete(ctx context.Context, in *DeleteRequest, out *Delet
 func (h *rulesHandler) List(ctx context.Context, in *ListRequest, out *ListResponse) error {
 	return h.RulesHandler.List(ctx, in, out)
 }
+
+// Api Endpoints for SCIM service
+
+func NewSCIMEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for SCIM service
+
+type SCIMService interface {
+	CreateUser(ctx context.Context, in *SCIMUserRequest, opts ...client.CallOption) (*SCIMUserResponse, error)
+	ReadUser(ctx context.Context, in *ReadSCIMUserRequest, opts ...client.CallOption) (*SCIMUserResponse, error)
+	UpdateUser(ctx context.Context, in *SCIMUserRequest, opts ...client.CallOption) (*SCIMUserResponse, error)
+	DeleteUser(ctx context.Context, in *DeleteSCIMUserRequest, opts ...client.CallOption) (*DeleteSCIMUserResponse, error)
+	ListUsers(ctx context.Context, in *ListSCIMUsersRequest, opts ...client.CallOption) (*ListSCIMUsersResponse, error)
+	CreateGroup(ctx context.Context, in *SCIMGroupRequest, opts ...client.CallOption) (*SCIMGroupResponse, error)
+	ReadGroup(ctx context.Context, in *ReadSCIMGroupRequest, opts ...client.CallOption) (*SCIMGroupResponse, error)
+	UpdateGroup(ctx context.Context, in *SCIMGroupRequest, opts ...client.CallOption) (*SCIMGroupResponse, error)
+	DeleteGroup(ctx context.Context, in *DeleteSCIMGroupRequest, opts ...client.CallOption) (*DeleteSCIMGroupResponse, error)
+	ListGroups(ctx context.Context, in *ListSCIMGroupsRequest, opts ...client.CallOption) (*ListSCIMGroupsResponse, error)
+	MapGroup(ctx context.Context, in *MapSCIMGroupRequest, opts ...client.CallOption) (*MapSCIMGroupResponse, error)
+}
+
+type sCIMService struct {
+	c    client.Client
+	name string
+}
+
+func NewSCIMService(name string, c client.Client) SCIMService {
+	return &sCIMService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *sCIMService) CreateUser(ctx context.Context, in *SCIMUserRequest, opts ...client.CallOption) (*SCIMUserResponse, error) {
+	req := c.c.NewRequest(c.name, "SCIM.CreateUser", in)
+	out := new(SCIMUserResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sCIMService) ReadUser(ctx context.Context, in *ReadSCIMUserRequest, opts ...client.CallOption) (*SCIMUserResponse, error) {
+	req := c.c.NewRequest(c.name, "SCIM.ReadUser", in)
+	out := new(SCIMUserResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sCIMService) UpdateUser(ctx context.Context, in *SCIMUserRequest, opts ...client.CallOption) (*SCIMUserResponse, error) {
+	req := c.c.NewRequest(c.name, "SCIM.UpdateUser", in)
+	out := new(SCIMUserResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sCIMService) DeleteUser(ctx context.Context, in *DeleteSCIMUserRequest, opts ...client.CallOption) (*DeleteSCIMUserResponse, error) {
+	req := c.c.NewRequest(c.name, "SCIM.DeleteUser", in)
+	out := new(DeleteSCIMUserResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sCIMService) ListUsers(ctx context.Context, in *ListSCIMUsersRequest, opts ...client.CallOption) (*ListSCIMUsersResponse, error) {
+	req := c.c.NewRequest(c.name, "SCIM.ListUsers", in)
+	out := new(ListSCIMUsersResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sCIMService) CreateGroup(ctx context.Context, in *SCIMGroupRequest, opts ...client.CallOption) (*SCIMGroupResponse, error) {
+	req := c.c.NewRequest(c.name, "SCIM.CreateGroup", in)
+	out := new(SCIMGroupResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sCIMService) ReadGroup(ctx context.Context, in *ReadSCIMGroupRequest, opts ...client.CallOption) (*SCIMGroupResponse, error) {
+	req := c.c.NewRequest(c.name, "SCIM.ReadGroup", in)
+	out := new(SCIMGroupResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sCIMService) UpdateGroup(ctx context.Context, in *SCIMGroupRequest, opts ...client.CallOption) (*SCIMGroupResponse, error) {
+	req := c.c.NewRequest(c.name, "SCIM.UpdateGroup", in)
+	out := new(SCIMGroupResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sCIMService) DeleteGroup(ctx context.Context, in *DeleteSCIMGroupRequest, opts ...client.CallOption) (*DeleteSCIMGroupResponse, error) {
+	req := c.c.NewRequest(c.name, "SCIM.DeleteGroup", in)
+	out := new(DeleteSCIMGroupResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sCIMService) ListGroups(ctx context.Context, in *ListSCIMGroupsRequest, opts ...client.CallOption) (*ListSCIMGroupsResponse, error) {
+	req := c.c.NewRequest(c.name, "SCIM.ListGroups", in)
+	out := new(ListSCIMGroupsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sCIMService) MapGroup(ctx context.Context, in *MapSCIMGroupRequest, opts ...client.CallOption) (*MapSCIMGroupResponse, error) {
+	req := c.c.NewRequest(c.name, "SCIM.MapGroup", in)
+	out := new(MapSCIMGroupResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for SCIM service
+
+type SCIMHandler interface {
+	CreateUser(context.Context, *SCIMUserRequest, *SCIMUserResponse) error
+	ReadUser(context.Context, *ReadSCIMUserRequest, *SCIMUserResponse) error
+	UpdateUser(context.Context, *SCIMUserRequest, *SCIMUserResponse) error
+	DeleteUser(context.Context, *DeleteSCIMUserRequest, *DeleteSCIMUserResponse) error
+	ListUsers(context.Context, *ListSCIMUsersRequest, *ListSCIMUsersResponse) error
+	CreateGroup(context.Context, *SCIMGroupRequest, *SCIMGroupResponse) error
+	ReadGroup(context.Context, *ReadSCIMGroupRequest, *SCIMGroupResponse) error
+	UpdateGroup(context.Context, *SCIMGroupRequest, *SCIMGroupResponse) error
+	DeleteGroup(context.Context, *DeleteSCIMGroupRequest, *DeleteSCIMGroupResponse) error
+	ListGroups(context.Context, *ListSCIMGroupsRequest, *ListSCIMGroupsResponse) error
+	MapGroup(context.Context, *MapSCIMGroupRequest, *MapSCIMGroupResponse) error
+}
+
+func RegisterSCIMHandler(s server.Server, hdlr SCIMHandler, opts ...server.HandlerOption) error {
+	type sCIM interface {
+		CreateUser(ctx context.Context, in *SCIMUserRequest, out *SCIMUserResponse) error
+		ReadUser(ctx context.Context, in *ReadSCIMUserRequest, out *SCIMUserResponse) error
+		UpdateUser(ctx context.Context, in *SCIMUserRequest, out *SCIMUserResponse) error
+		DeleteUser(ctx context.Context, in *DeleteSCIMUserRequest, out *DeleteSCIMUserResponse) error
+		ListUsers(ctx context.Context, in *ListSCIMUsersRequest, out *ListSCIMUsersResponse) error
+		CreateGroup(ctx context.Context, in *SCIMGroupRequest, out *SCIMGroupResponse) error
+		ReadGroup(ctx context.Context, in *ReadSCIMGroupRequest, out *SCIMGroupResponse) error
+		UpdateGroup(ctx context.Context, in *SCIMGroupRequest, out *SCIMGroupResponse) error
+		DeleteGroup(ctx context.Context, in *DeleteSCIMGroupRequest, out *DeleteSCIMGroupResponse) error
+		ListGroups(ctx context.Context, in *ListSCIMGroupsRequest, out *ListSCIMGroupsResponse) error
+		MapGroup(ctx context.Context, in *MapSCIMGroupRequest, out *MapSCIMGroupResponse) error
+	}
+	type SCIM struct {
+		sCIM
+	}
+	h := &sCIMHandler{hdlr}
+	return s.Handle(s.NewHandler(&SCIM{h}, opts...))
+}
+
+type sCIMHandler struct {
+	SCIMHandler
+}
+
+func (h *sCIMHandler) CreateUser(ctx context.Context, in *SCIMUserRequest, out *SCIMUserResponse) error {
+	return h.SCIMHandler.CreateUser(ctx, in, out)
+}
+
+func (h *sCIMHandler) ReadUser(ctx context.Context, in *ReadSCIMUserRequest, out *SCIMUserResponse) error {
+	return h.SCIMHandler.ReadUser(ctx, in, out)
+}
+
+func (h *sCIMHandler) UpdateUser(ctx context.Context, in *SCIMUserRequest, out *SCIMUserResponse) error {
+	return h.SCIMHandler.UpdateUser(ctx, in, out)
+}
+
+func (h *sCIMHandler) DeleteUser(ctx context.Context, in *DeleteSCIMUserRequest, out *DeleteSCIMUserResponse) error {
+	return h.SCIMHandler.DeleteUser(ctx, in, out)
+}
+
+func (h *sCIMHandler) ListUsers(ctx context.Context, in *ListSCIMUsersRequest, out *ListSCIMUsersResponse) error {
+	return h.SCIMHandler.ListUsers(ctx, in, out)
+}
+
+func (h *sCIMHandler) CreateGroup(ctx context.Context, in *SCIMGroupRequest, out *SCIMGroupResponse) error {
+	return h.SCIMHandler.CreateGroup(ctx, in, out)
+}
+
+func (h *sCIMHandler) ReadGroup(ctx context.Context, in *ReadSCIMGroupRequest, out *SCIMGroupResponse) error {
+	return h.SCIMHandler.ReadGroup(ctx, in, out)
+}
+
+func (h *sCIMHandler) UpdateGroup(ctx context.Context, in *SCIMGroupRequest, out *SCIMGroupResponse) error {
+	return h.SCIMHandler.UpdateGroup(ctx, in, out)
+}
+
+func (h *sCIMHandler) DeleteGroup(ctx context.Context, in *DeleteSCIMGroupRequest, out *DeleteSCIMGroupResponse) error {
+	return h.SCIMHandler.DeleteGroup(ctx, in, out)
+}
+
+func (h *sCIMHandler) ListGroups(ctx context.Context, in *ListSCIMGroupsRequest, out *ListSCIMGroupsResponse) error {
+	return h.SCIMHandler.ListGroups(ctx, in, out)
+}
+
+func (h *sCIMHandler) MapGroup(ctx context.Context, in *MapSCIMGroupRequest, out *MapSCIMGroupResponse) error {
+	return h.SCIMHandler.MapGroup(ctx, in, out)
+}
+
+// Api Endpoints for Sessions service
+
+func NewSessionsEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for Sessions service
+
+type SessionsService interface {
+	Create(ctx context.Context, in *CreateSessionRequest, opts ...client.CallOption) (*CreateSessionResponse, error)
+	Refresh(ctx context.Context, in *RefreshSessionRequest, opts ...client.CallOption) (*RefreshSessionResponse, error)
+	List(ctx context.Context, in *ListSessionsRequest, opts ...client.CallOption) (*ListSessionsResponse, error)
+	Revoke(ctx context.Context, in *RevokeSessionRequest, opts ...client.CallOption) (*RevokeSessionResponse, error)
+}
+
+type sessionsService struct {
+	c    client.Client
+	name string
+}
+
+func NewSessionsService(name string, c client.Client) SessionsService {
+	return &sessionsService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *sessionsService) Create(ctx context.Context, in *CreateSessionRequest, opts ...client.CallOption) (*CreateSessionResponse, error) {
+	req := c.c.NewRequest(c.name, "Sessions.Create", in)
+	out := new(CreateSessionResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionsService) Refresh(ctx context.Context, in *RefreshSessionRequest, opts ...client.CallOption) (*RefreshSessionResponse, error) {
+	req := c.c.NewRequest(c.name, "Sessions.Refresh", in)
+	out := new(RefreshSessionResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionsService) List(ctx context.Context, in *ListSessionsRequest, opts ...client.CallOption) (*ListSessionsResponse, error) {
+	req := c.c.NewRequest(c.name, "Sessions.List", in)
+	out := new(ListSessionsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sessionsService) Revoke(ctx context.Context, in *RevokeSessionRequest, opts ...client.CallOption) (*RevokeSessionResponse, error) {
+	req := c.c.NewRequest(c.name, "Sessions.Revoke", in)
+	out := new(RevokeSessionResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Sessions service
+
+type SessionsHandler interface {
+	Create(context.Context, *CreateSessionRequest, *CreateSessionResponse) error
+	Refresh(context.Context, *RefreshSessionRequest, *RefreshSessionResponse) error
+	List(context.Context, *ListSessionsRequest, *ListSessionsResponse) error
+	Revoke(context.Context, *RevokeSessionRequest, *RevokeSessionResponse) error
+}
+
+func RegisterSessionsHandler(s server.Server, hdlr SessionsHandler, opts ...server.HandlerOption) error {
+	type sessions interface {
+		Create(ctx context.Context, in *CreateSessionRequest, out *CreateSessionResponse) error
+		Refresh(ctx context.Context, in *RefreshSessionRequest, out *RefreshSessionResponse) error
+		List(ctx context.Context, in *ListSessionsRequest, out *ListSessionsResponse) error
+		Revoke(ctx context.Context, in *RevokeSessionRequest, out *RevokeSessionResponse) error
+	}
+	type Sessions struct {
+		sessions
+	}
+	h := &sessionsHandler{hdlr}
+	return s.Handle(s.NewHandler(&Sessions{h}, opts...))
+}
+
+type sessionsHandler struct {
+	SessionsHandler
+}
+
+func (h *sessionsHandler) Create(ctx context.Context, in *CreateSessionRequest, out *CreateSessionResponse) error {
+	return h.SessionsHandler.Create(ctx, in, out)
+}
+
+func (h *sessionsHandler) Refresh(ctx context.Context, in *RefreshSessionRequest, out *RefreshSessionResponse) error {
+	return h.SessionsHandler.Refresh(ctx, in, out)
+}
+
+func (h *sessionsHandler) List(ctx context.Context, in *ListSessionsRequest, out *ListSessionsResponse) error {
+	return h.SessionsHandler.List(ctx, in, out)
+}
+
+func (h *sessionsHandler) Revoke(ctx context.Context, in *RevokeSessionRequest, out *RevokeSessionResponse) error {
+	return h.SessionsHandler.Revoke(ctx, in, out)
+}
+
+// Api Endpoints for Certificates service
+
+func NewCertificatesEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for Certificates service
+
+type CertificatesService interface {
+	Issue(ctx context.Context, in *IssueCertificateRequest, opts ...client.CallOption) (*IssueCertificateResponse, error)
+}
+
+type certificatesService struct {
+	c    client.Client
+	name string
+}
+
+func NewCertificatesService(name string, c client.Client) CertificatesService {
+	return &certificatesService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *certificatesService) Issue(ctx context.Context, in *IssueCertificateRequest, opts ...client.CallOption) (*IssueCertificateResponse, error) {
+	req := c.c.NewRequest(c.name, "Certificates.Issue", in)
+	out := new(IssueCertificateResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Certificates service
+
+type CertificatesHandler interface {
+	Issue(context.Context, *IssueCertificateRequest, *IssueCertificateResponse) error
+}
+
+func RegisterCertificatesHandler(s server.Server, hdlr CertificatesHandler, opts ...server.HandlerOption) error {
+	type certificates interface {
+		Issue(ctx context.Context, in *IssueCertificateRequest, out *IssueCertificateResponse) error
+	}
+	type Certificates struct {
+		certificates
+	}
+	h := &certificatesHandler{hdlr}
+	return s.Handle(s.NewHandler(&Certificates{h}, opts...))
+}
+
+type certificatesHandler struct {
+	CertificatesHandler
+}
+
+func (h *certificatesHandler) Issue(ctx context.Context, in *IssueCertificateRequest, out *IssueCertificateResponse) error {
+	return h.CertificatesHandler.Issue(ctx, in, out)
+}
+
+// Api Endpoints for OAuth service
+
+func NewOAuthEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for OAuth service
+
+type OAuthService interface {
+	Token(ctx context.Context, in *OAuthTokenRequest, opts ...client.CallOption) (*OAuthTokenResponse, error)
+	CreateClient(ctx context.Context, in *CreateClientRequest, opts ...client.CallOption) (*CreateClientResponse, error)
+	ListClients(ctx context.Context, in *ListClientsRequest, opts ...client.CallOption) (*ListClientsResponse, error)
+	DeleteClient(ctx context.Context, in *DeleteClientRequest, opts ...client.CallOption) (*DeleteClientResponse, error)
+}
+
+type oAuthService struct {
+	c    client.Client
+	name string
+}
+
+func NewOAuthService(name string, c client.Client) OAuthService {
+	return &oAuthService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *oAuthService) Token(ctx context.Context, in *OAuthTokenRequest, opts ...client.CallOption) (*OAuthTokenResponse, error) {
+	req := c.c.NewRequest(c.name, "OAuth.Token", in)
+	out := new(OAuthTokenResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oAuthService) CreateClient(ctx context.Context, in *CreateClientRequest, opts ...client.CallOption) (*CreateClientResponse, error) {
+	req := c.c.NewRequest(c.name, "OAuth.CreateClient", in)
+	out := new(CreateClientResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oAuthService) ListClients(ctx context.Context, in *ListClientsRequest, opts ...client.CallOption) (*ListClientsResponse, error) {
+	req := c.c.NewRequest(c.name, "OAuth.ListClients", in)
+	out := new(ListClientsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oAuthService) DeleteClient(ctx context.Context, in *DeleteClientRequest, opts ...client.CallOption) (*DeleteClientResponse, error) {
+	req := c.c.NewRequest(c.name, "OAuth.DeleteClient", in)
+	out := new(DeleteClientResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for OAuth service
+
+type OAuthHandler interface {
+	Token(context.Context, *OAuthTokenRequest, *OAuthTokenResponse) error
+	CreateClient(context.Context, *CreateClientRequest, *CreateClientResponse) error
+	ListClients(context.Context, *ListClientsRequest, *ListClientsResponse) error
+	DeleteClient(context.Context, *DeleteClientRequest, *DeleteClientResponse) error
+}
+
+func RegisterOAuthHandler(s server.Server, hdlr OAuthHandler, opts ...server.HandlerOption) error {
+	type oAuth interface {
+		Token(ctx context.Context, in *OAuthTokenRequest, out *OAuthTokenResponse) error
+		CreateClient(ctx context.Context, in *CreateClientRequest, out *CreateClientResponse) error
+		ListClients(ctx context.Context, in *ListClientsRequest, out *ListClientsResponse) error
+		DeleteClient(ctx context.Context, in *DeleteClientRequest, out *DeleteClientResponse) error
+	}
+	type OAuth struct {
+		oAuth
+	}
+	h := &oAuthHandler{hdlr}
+	return s.Handle(s.NewHandler(&OAuth{h}, opts...))
+}
+
+type oAuthHandler struct {
+	OAuthHandler
+}
+
+func (h *oAuthHandler) Token(ctx context.Context, in *OAuthTokenRequest, out *OAuthTokenResponse) error {
+	return h.OAuthHandler.Token(ctx, in, out)
+}
+
+func (h *oAuthHandler) CreateClient(ctx context.Context, in *CreateClientRequest, out *CreateClientResponse) error {
+	return h.OAuthHandler.CreateClient(ctx, in, out)
+}
+
+func (h *oAuthHandler) ListClients(ctx context.Context, in *ListClientsRequest, out *ListClientsResponse) error {
+	return h.OAuthHandler.ListClients(ctx, in, out)
+}
+
+func (h *oAuthHandler) DeleteClient(ctx context.Context, in *DeleteClientRequest, out *DeleteClientResponse) error {
+	return h.OAuthHandler.DeleteClient(ctx, in, out)
+}
+
+// Api Endpoints for APIKeys service
+
+func NewAPIKeysEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for APIKeys service
+
+type APIKeysService interface {
+	Create(ctx context.Context, in *CreateAPIKeyRequest, opts ...client.CallOption) (*CreateAPIKeyResponse, error)
+	List(ctx context.Context, in *ListAPIKeysRequest, opts ...client.CallOption) (*ListAPIKeysResponse, error)
+	Revoke(ctx context.Context, in *RevokeAPIKeyRequest, opts ...client.CallOption) (*RevokeAPIKeyResponse, error)
+	Verify(ctx context.Context, in *VerifyAPIKeyRequest, opts ...client.CallOption) (*VerifyAPIKeyResponse, error)
+}
+
+type aPIKeysService struct {
+	c    client.Client
+	name string
+}
+
+func NewAPIKeysService(name string, c client.Client) APIKeysService {
+	return &aPIKeysService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *aPIKeysService) Create(ctx context.Context, in *CreateAPIKeyRequest, opts ...client.CallOption) (*CreateAPIKeyResponse, error) {
+	req := c.c.NewRequest(c.name, "APIKeys.Create", in)
+	out := new(CreateAPIKeyResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIKeysService) List(ctx context.Context, in *ListAPIKeysRequest, opts ...client.CallOption) (*ListAPIKeysResponse, error) {
+	req := c.c.NewRequest(c.name, "APIKeys.List", in)
+	out := new(ListAPIKeysResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIKeysService) Revoke(ctx context.Context, in *RevokeAPIKeyRequest, opts ...client.CallOption) (*RevokeAPIKeyResponse, error) {
+	req := c.c.NewRequest(c.name, "APIKeys.Revoke", in)
+	out := new(RevokeAPIKeyResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIKeysService) Verify(ctx context.Context, in *VerifyAPIKeyRequest, opts ...client.CallOption) (*VerifyAPIKeyResponse, error) {
+	req := c.c.NewRequest(c.name, "APIKeys.Verify", in)
+	out := new(VerifyAPIKeyResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for APIKeys service
+
+type APIKeysHandler interface {
+	Create(context.Context, *CreateAPIKeyRequest, *CreateAPIKeyResponse) error
+	List(context.Context, *ListAPIKeysRequest, *ListAPIKeysResponse) error
+	Revoke(context.Context, *RevokeAPIKeyRequest, *RevokeAPIKeyResponse) error
+	Verify(context.Context, *VerifyAPIKeyRequest, *VerifyAPIKeyResponse) error
+}
+
+func RegisterAPIKeysHandler(s server.Server, hdlr APIKeysHandler, opts ...server.HandlerOption) error {
+	type aPIKeys interface {
+		Create(ctx context.Context, in *CreateAPIKeyRequest, out *CreateAPIKeyResponse) error
+		List(ctx context.Context, in *ListAPIKeysRequest, out *ListAPIKeysResponse) error
+		Revoke(ctx context.Context, in *RevokeAPIKeyRequest, out *RevokeAPIKeyResponse) error
+		Verify(ctx context.Context, in *VerifyAPIKeyRequest, out *VerifyAPIKeyResponse) error
+	}
+	type APIKeys struct {
+		aPIKeys
+	}
+	h := &aPIKeysHandler{hdlr}
+	return s.Handle(s.NewHandler(&APIKeys{h}, opts...))
+}
+
+type aPIKeysHandler struct {
+	APIKeysHandler
+}
+
+func (h *aPIKeysHandler) Create(ctx context.Context, in *CreateAPIKeyRequest, out *CreateAPIKeyResponse) error {
+	return h.APIKeysHandler.Create(ctx, in, out)
+}
+
+func (h *aPIKeysHandler) List(ctx context.Context, in *ListAPIKeysRequest, out *ListAPIKeysResponse) error {
+	return h.APIKeysHandler.List(ctx, in, out)
+}
+
+func (h *aPIKeysHandler) Revoke(ctx context.Context, in *RevokeAPIKeyRequest, out *RevokeAPIKeyResponse) error {
+	return h.APIKeysHandler.Revoke(ctx, in, out)
+}
+
+func (h *aPIKeysHandler) Verify(ctx context.Context, in *VerifyAPIKeyRequest, out *VerifyAPIKeyResponse) error {
+	return h.APIKeysHandler.Verify(ctx, in, out)
+}
+
+// Api Endpoints for Audit service
+
+func NewAuditEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for Audit service
+
+type AuditService interface {
+	List(ctx context.Context, in *ListAuditRecordsRequest, opts ...client.CallOption) (*ListAuditRecordsResponse, error)
+}
+
+type auditService struct {
+	c    client.Client
+	name string
+}
+
+func NewAuditService(name string, c client.Client) AuditService {
+	return &auditService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *auditService) List(ctx context.Context, in *ListAuditRecordsRequest, opts ...client.CallOption) (*ListAuditRecordsResponse, error) {
+	req := c.c.NewRequest(c.name, "Audit.List", in)
+	out := new(ListAuditRecordsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Audit service
+
+type AuditHandler interface {
+	List(context.Context, *ListAuditRecordsRequest, *ListAuditRecordsResponse) error
+}
+
+func RegisterAuditHandler(s server.Server, hdlr AuditHandler, opts ...server.HandlerOption) error {
+	type audit interface {
+		List(ctx context.Context, in *ListAuditRecordsRequest, out *ListAuditRecordsResponse) error
+	}
+	type Audit struct {
+		audit
+	}
+	h := &auditHandler{hdlr}
+	return s.Handle(s.NewHandler(&Audit{h}, opts...))
+}
+
+type auditHandler struct {
+	AuditHandler
+}
+
+func (h *auditHandler) List(ctx context.Context, in *ListAuditRecordsRequest, out *ListAuditRecordsResponse) error {
+	return h.AuditHandler.List(ctx, in, out)
+}
+
+// Api Endpoints for SigningKeys service
+
+func NewSigningKeysEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for SigningKeys service
+
+type SigningKeysService interface {
+	Rotate(ctx context.Context, in *RotateSigningKeyRequest, opts ...client.CallOption) (*RotateSigningKeyResponse, error)
+	List(ctx context.Context, in *ListSigningKeysRequest, opts ...client.CallOption) (*ListSigningKeysResponse, error)
+}
+
+type signingKeysService struct {
+	c    client.Client
+	name string
+}
+
+func NewSigningKeysService(name string, c client.Client) SigningKeysService {
+	return &signingKeysService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *signingKeysService) Rotate(ctx context.Context, in *RotateSigningKeyRequest, opts ...client.CallOption) (*RotateSigningKeyResponse, error) {
+	req := c.c.NewRequest(c.name, "SigningKeys.Rotate", in)
+	out := new(RotateSigningKeyResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signingKeysService) List(ctx context.Context, in *ListSigningKeysRequest, opts ...client.CallOption) (*ListSigningKeysResponse, error) {
+	req := c.c.NewRequest(c.name, "SigningKeys.List", in)
+	out := new(ListSigningKeysResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for SigningKeys service
+
+type SigningKeysHandler interface {
+	Rotate(context.Context, *RotateSigningKeyRequest, *RotateSigningKeyResponse) error
+	List(context.Context, *ListSigningKeysRequest, *ListSigningKeysResponse) error
+}
+
+func RegisterSigningKeysHandler(s server.Server, hdlr SigningKeysHandler, opts ...server.HandlerOption) error {
+	type signingKeys interface {
+		Rotate(ctx context.Context, in *RotateSigningKeyRequest, out *RotateSigningKeyResponse) error
+		List(ctx context.Context, in *ListSigningKeysRequest, out *ListSigningKeysResponse) error
+	}
+	type SigningKeys struct {
+		signingKeys
+	}
+	h := &signingKeysHandler{hdlr}
+	return s.Handle(s.NewHandler(&SigningKeys{h}, opts...))
+}
+
+type signingKeysHandler struct {
+	SigningKeysHandler
+}
+
+func (h *signingKeysHandler) Rotate(ctx context.Context, in *RotateSigningKeyRequest, out *RotateSigningKeyResponse) error {
+	return h.SigningKeysHandler.Rotate(ctx, in, out)
+}
+
+func (h *signingKeysHandler) List(ctx context.Context, in *ListSigningKeysRequest, out *ListSigningKeysResponse) error {
+	return h.SigningKeysHandler.List(ctx, in, out)
+}
@@ -0,0 +1,865 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: connections/connections.proto
+
+package connections
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Connection struct {
+	// unique id of the connection
+	Id        string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// account the client authenticated as
+	Account string `protobuf:"bytes,3,opt,name=account,proto3" json:"account,omitempty"`
+	// session of the client, e.g. a browser tab, so messages can be pushed to a single session
+	Session string `protobuf:"bytes,4,opt,name=session,proto3" json:"session,omitempty"`
+	// id of the gateway instance holding the connection
+	Gateway string `protobuf:"bytes,5,opt,name=gateway,proto3" json:"gateway,omitempty"`
+	// address of the gateway instance
+	Address string `protobuf:"bytes,6,opt,name=address,proto3" json:"address,omitempty"`
+	// protocol of the connection, websocket or sse
+	Protocol string `protobuf:"bytes,7,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	// unix timestamp the connection was made
+	Created              int64    `protobuf:"varint,8,opt,name=created,proto3" json:"created,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Connection) Reset()         { *m = Connection{} }
+func (m *Connection) String() string { return proto.CompactTextString(m) }
+func (*Connection) ProtoMessage()    {}
+func (*Connection) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0453e8e693d8b82a, []int{0}
+}
+
+func (m *Connection) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Connection.Unmarshal(m, b)
+}
+func (m *Connection) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Connection.Marshal(b, m, deterministic)
+}
+func (m *Connection) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Connection.Merge(m, src)
+}
+func (m *Connection) XXX_Size() int {
+	return xxx_messageInfo_Connection.Size(m)
+}
+func (m *Connection) XXX_DiscardUnknown() {
+	xxx_messageInfo_Connection.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Connection proto.InternalMessageInfo
+
+func (m *Connection) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Connection) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *Connection) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *Connection) GetSession() string {
+	if m != nil {
+		return m.Session
+	}
+	return ""
+}
+
+func (m *Connection) GetGateway() string {
+	if m != nil {
+		return m.Gateway
+	}
+	return ""
+}
+
+func (m *Connection) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Connection) GetProtocol() string {
+	if m != nil {
+		return m.Protocol
+	}
+	return ""
+}
+
+func (m *Connection) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+type RegisterRequest struct {
+	Connections []*Connection `protobuf:"bytes,1,rep,name=connections,proto3" json:"connections,omitempty"`
+	// seconds until the connections expire unless registered again
+	Ttl                  int64    `protobuf:"varint,2,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RegisterRequest) Reset()         { *m = RegisterRequest{} }
+func (m *RegisterRequest) String() string { return proto.CompactTextString(m) }
+func (*RegisterRequest) ProtoMessage()    {}
+func (*RegisterRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0453e8e693d8b82a, []int{1}
+}
+
+func (m *RegisterRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterRequest.Unmarshal(m, b)
+}
+func (m *RegisterRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterRequest.Marshal(b, m, deterministic)
+}
+func (m *RegisterRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterRequest.Merge(m, src)
+}
+func (m *RegisterRequest) XXX_Size() int {
+	return xxx_messageInfo_RegisterRequest.Size(m)
+}
+func (m *RegisterRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterRequest proto.InternalMessageInfo
+
+func (m *RegisterRequest) GetConnections() []*Connection {
+	if m != nil {
+		return m.Connections
+	}
+	return nil
+}
+
+func (m *RegisterRequest) GetTtl() int64 {
+	if m != nil {
+		return m.Ttl
+	}
+	return 0
+}
+
+type RegisterResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RegisterResponse) Reset()         { *m = RegisterResponse{} }
+func (m *RegisterResponse) String() string { return proto.CompactTextString(m) }
+func (*RegisterResponse) ProtoMessage()    {}
+func (*RegisterResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0453e8e693d8b82a, []int{2}
+}
+
+func (m *RegisterResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterResponse.Unmarshal(m, b)
+}
+func (m *RegisterResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterResponse.Marshal(b, m, deterministic)
+}
+func (m *RegisterResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterResponse.Merge(m, src)
+}
+func (m *RegisterResponse) XXX_Size() int {
+	return xxx_messageInfo_RegisterResponse.Size(m)
+}
+func (m *RegisterResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterResponse proto.InternalMessageInfo
+
+type DeregisterRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Namespace            string   `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Account              string   `protobuf:"bytes,3,opt,name=account,proto3" json:"account,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeregisterRequest) Reset()         { *m = DeregisterRequest{} }
+func (m *DeregisterRequest) String() string { return proto.CompactTextString(m) }
+func (*DeregisterRequest) ProtoMessage()    {}
+func (*DeregisterRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0453e8e693d8b82a, []int{3}
+}
+
+func (m *DeregisterRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeregisterRequest.Unmarshal(m, b)
+}
+func (m *DeregisterRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeregisterRequest.Marshal(b, m, deterministic)
+}
+func (m *DeregisterRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeregisterRequest.Merge(m, src)
+}
+func (m *DeregisterRequest) XXX_Size() int {
+	return xxx_messageInfo_DeregisterRequest.Size(m)
+}
+func (m *DeregisterRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeregisterRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeregisterRequest proto.InternalMessageInfo
+
+func (m *DeregisterRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *DeregisterRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *DeregisterRequest) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+type DeregisterResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeregisterResponse) Reset()         { *m = DeregisterResponse{} }
+func (m *DeregisterResponse) String() string { return proto.CompactTextString(m) }
+func (*DeregisterResponse) ProtoMessage()    {}
+func (*DeregisterResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0453e8e693d8b82a, []int{4}
+}
+
+func (m *DeregisterResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeregisterResponse.Unmarshal(m, b)
+}
+func (m *DeregisterResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeregisterResponse.Marshal(b, m, deterministic)
+}
+func (m *DeregisterResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeregisterResponse.Merge(m, src)
+}
+func (m *DeregisterResponse) XXX_Size() int {
+	return xxx_messageInfo_DeregisterResponse.Size(m)
+}
+func (m *DeregisterResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeregisterResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeregisterResponse proto.InternalMessageInfo
+
+type ListRequest struct {
+	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Account              string   `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListRequest) Reset()         { *m = ListRequest{} }
+func (m *ListRequest) String() string { return proto.CompactTextString(m) }
+func (*ListRequest) ProtoMessage()    {}
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0453e8e693d8b82a, []int{5}
+}
+
+func (m *ListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRequest.Unmarshal(m, b)
+}
+func (m *ListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRequest.Marshal(b, m, deterministic)
+}
+func (m *ListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRequest.Merge(m, src)
+}
+func (m *ListRequest) XXX_Size() int {
+	return xxx_messageInfo_ListRequest.Size(m)
+}
+func (m *ListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRequest proto.InternalMessageInfo
+
+func (m *ListRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ListRequest) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+type ListResponse struct {
+	Connections          []*Connection `protobuf:"bytes,1,rep,name=connections,proto3" json:"connections,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ListResponse) Reset()         { *m = ListResponse{} }
+func (m *ListResponse) String() string { return proto.CompactTextString(m) }
+func (*ListResponse) ProtoMessage()    {}
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0453e8e693d8b82a, []int{6}
+}
+
+func (m *ListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListResponse.Unmarshal(m, b)
+}
+func (m *ListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListResponse.Marshal(b, m, deterministic)
+}
+func (m *ListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListResponse.Merge(m, src)
+}
+func (m *ListResponse) XXX_Size() int {
+	return xxx_messageInfo_ListResponse.Size(m)
+}
+func (m *ListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListResponse proto.InternalMessageInfo
+
+func (m *ListResponse) GetConnections() []*Connection {
+	if m != nil {
+		return m.Connections
+	}
+	return nil
+}
+
+type PushRequest struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Account   string `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+	// only push to the session of the account if set
+	Session              string   `protobuf:"bytes,3,opt,name=session,proto3" json:"session,omitempty"`
+	Payload              []byte   `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PushRequest) Reset()         { *m = PushRequest{} }
+func (m *PushRequest) String() string { return proto.CompactTextString(m) }
+func (*PushRequest) ProtoMessage()    {}
+func (*PushRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0453e8e693d8b82a, []int{7}
+}
+
+func (m *PushRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PushRequest.Unmarshal(m, b)
+}
+func (m *PushRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PushRequest.Marshal(b, m, deterministic)
+}
+func (m *PushRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushRequest.Merge(m, src)
+}
+func (m *PushRequest) XXX_Size() int {
+	return xxx_messageInfo_PushRequest.Size(m)
+}
+func (m *PushRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushRequest proto.InternalMessageInfo
+
+func (m *PushRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *PushRequest) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *PushRequest) GetSession() string {
+	if m != nil {
+		return m.Session
+	}
+	return ""
+}
+
+func (m *PushRequest) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+type PushResponse struct {
+	// number of connections the message was delivered to
+	Delivered            int64    `protobuf:"varint,1,opt,name=delivered,proto3" json:"delivered,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PushResponse) Reset()         { *m = PushResponse{} }
+func (m *PushResponse) String() string { return proto.CompactTextString(m) }
+func (*PushResponse) ProtoMessage()    {}
+func (*PushResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0453e8e693d8b82a, []int{8}
+}
+
+func (m *PushResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PushResponse.Unmarshal(m, b)
+}
+func (m *PushResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PushResponse.Marshal(b, m, deterministic)
+}
+func (m *PushResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushResponse.Merge(m, src)
+}
+func (m *PushResponse) XXX_Size() int {
+	return xxx_messageInfo_PushResponse.Size(m)
+}
+func (m *PushResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushResponse proto.InternalMessageInfo
+
+func (m *PushResponse) GetDelivered() int64 {
+	if m != nil {
+		return m.Delivered
+	}
+	return 0
+}
+
+type DeliverRequest struct {
+	// ids of the connections held by the gateway to deliver the payload to
+	Ids                  []string `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	Payload              []byte   `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeliverRequest) Reset()         { *m = DeliverRequest{} }
+func (m *DeliverRequest) String() string { return proto.CompactTextString(m) }
+func (*DeliverRequest) ProtoMessage()    {}
+func (*DeliverRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0453e8e693d8b82a, []int{9}
+}
+
+func (m *DeliverRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeliverRequest.Unmarshal(m, b)
+}
+func (m *DeliverRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeliverRequest.Marshal(b, m, deterministic)
+}
+func (m *DeliverRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeliverRequest.Merge(m, src)
+}
+func (m *DeliverRequest) XXX_Size() int {
+	return xxx_messageInfo_DeliverRequest.Size(m)
+}
+func (m *DeliverRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeliverRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeliverRequest proto.InternalMessageInfo
+
+func (m *DeliverRequest) GetIds() []string {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+func (m *DeliverRequest) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+type DeliverResponse struct {
+	// ids of the connections the gateway no longer holds
+	Missing              []string `protobuf:"bytes,1,rep,name=missing,proto3" json:"missing,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeliverResponse) Reset()         { *m = DeliverResponse{} }
+func (m *DeliverResponse) String() string { return proto.CompactTextString(m) }
+func (*DeliverResponse) ProtoMessage()    {}
+func (*DeliverResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0453e8e693d8b82a, []int{10}
+}
+
+func (m *DeliverResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeliverResponse.Unmarshal(m, b)
+}
+func (m *DeliverResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeliverResponse.Marshal(b, m, deterministic)
+}
+func (m *DeliverResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeliverResponse.Merge(m, src)
+}
+func (m *DeliverResponse) XXX_Size() int {
+	return xxx_messageInfo_DeliverResponse.Size(m)
+}
+func (m *DeliverResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeliverResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeliverResponse proto.InternalMessageInfo
+
+func (m *DeliverResponse) GetMissing() []string {
+	if m != nil {
+		return m.Missing
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Connection)(nil), "connections.Connection")
+	proto.RegisterType((*RegisterRequest)(nil), "connections.RegisterRequest")
+	proto.RegisterType((*RegisterResponse)(nil), "connections.RegisterResponse")
+	proto.RegisterType((*DeregisterRequest)(nil), "connections.DeregisterRequest")
+	proto.RegisterType((*DeregisterResponse)(nil), "connections.DeregisterResponse")
+	proto.RegisterType((*ListRequest)(nil), "connections.ListRequest")
+	proto.RegisterType((*ListResponse)(nil), "connections.ListResponse")
+	proto.RegisterType((*PushRequest)(nil), "connections.PushRequest")
+	proto.RegisterType((*PushResponse)(nil), "connections.PushResponse")
+	proto.RegisterType((*DeliverRequest)(nil), "connections.DeliverRequest")
+	proto.RegisterType((*DeliverResponse)(nil), "connections.DeliverResponse")
+}
+
+func init() { proto.RegisterFile("connections/connections.proto", fileDescriptor_0453e8e693d8b82a) }
+
+var fileDescriptor_0453e8e693d8b82a = []byte{
+	// 505 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x54, 0x5d, 0x6f, 0xd3, 0x30,
+	0x14, 0x25, 0xc9, 0x58, 0xda, 0x9b, 0x6a, 0x2b, 0x16, 0x12, 0x5e, 0xe8, 0xa0, 0xca, 0x53, 0x25,
+	0x50, 0x2b, 0x75, 0x0f, 0x68, 0x02, 0x5e, 0xd8, 0x00, 0x4d, 0x42, 0x02, 0xfc, 0x08, 0x12, 0x52,
+	0xe6, 0x58, 0x9d, 0xa5, 0x36, 0x2e, 0xb1, 0x3b, 0xd4, 0x5f, 0xc4, 0x5f, 0xe2, 0xe7, 0xa0, 0xf8,
+	0xa3, 0x71, 0xba, 0x8e, 0x97, 0xf2, 0x12, 0xf9, 0xdc, 0x93, 0x7b, 0xee, 0xf5, 0xbd, 0x47, 0x86,
+	0x53, 0x2a, 0xca, 0x92, 0x51, 0xc5, 0x45, 0x29, 0x27, 0xde, 0x79, 0xbc, 0xac, 0x84, 0x12, 0x28,
+	0xf1, 0x42, 0xd9, 0x9f, 0x00, 0xe0, 0x62, 0x83, 0xd1, 0x11, 0x84, 0xbc, 0xc0, 0xc1, 0x30, 0x18,
+	0x75, 0x49, 0xc8, 0x0b, 0x34, 0x80, 0x6e, 0x99, 0x2f, 0x98, 0x5c, 0xe6, 0x94, 0xe1, 0x50, 0x87,
+	0x9b, 0x00, 0xc2, 0x10, 0xe7, 0x94, 0x8a, 0x55, 0xa9, 0x70, 0xa4, 0x39, 0x07, 0x6b, 0x46, 0x32,
+	0x29, 0xb9, 0x28, 0xf1, 0x81, 0x61, 0x2c, 0xac, 0x99, 0x59, 0xae, 0xd8, 0xaf, 0x7c, 0x8d, 0x1f,
+	0x1a, 0xc6, 0x42, 0xad, 0x56, 0x14, 0x15, 0x93, 0x12, 0x1f, 0x5a, 0x35, 0x03, 0x51, 0x0a, 0x1d,
+	0xdd, 0x3a, 0x15, 0x73, 0x1c, 0x6b, 0x6a, 0x83, 0xeb, 0x2c, 0x5a, 0xb1, 0x5c, 0xb1, 0x02, 0x77,
+	0x86, 0xc1, 0x28, 0x22, 0x0e, 0x66, 0x3f, 0xe0, 0x98, 0xb0, 0x19, 0x97, 0x8a, 0x55, 0x84, 0xfd,
+	0x5c, 0x31, 0xa9, 0xd0, 0x39, 0xf8, 0x97, 0xc7, 0xc1, 0x30, 0x1a, 0x25, 0xd3, 0x27, 0x63, 0x7f,
+	0x46, 0xcd, 0x30, 0x88, 0xff, 0x2f, 0xea, 0x43, 0xa4, 0xd4, 0x5c, 0xcf, 0x20, 0x22, 0xf5, 0x31,
+	0x43, 0xd0, 0x6f, 0xf4, 0xe5, 0x52, 0x94, 0x92, 0x65, 0xdf, 0xe1, 0xd1, 0x25, 0xab, 0xb6, 0xaa,
+	0xfe, 0xa7, 0xa1, 0x66, 0x8f, 0x01, 0xf9, 0xe2, 0xb6, 0xe4, 0x7b, 0x48, 0x3e, 0x71, 0xa9, 0x5c,
+	0xb1, 0x96, 0x78, 0xf0, 0x0f, 0xf1, 0xb0, 0x2d, 0x7e, 0x05, 0x3d, 0x23, 0x63, 0x64, 0xf7, 0x18,
+	0x55, 0xb6, 0x86, 0xe4, 0xcb, 0x4a, 0xde, 0xec, 0xd9, 0x91, 0xef, 0xa1, 0xe8, 0x8e, 0x87, 0x96,
+	0xf9, 0x7a, 0x2e, 0xf2, 0x42, 0xbb, 0xab, 0x47, 0x1c, 0xcc, 0x5e, 0x42, 0xcf, 0x94, 0xb6, 0xb7,
+	0x18, 0x40, 0xb7, 0x60, 0x73, 0x7e, 0xcb, 0x2a, 0x66, 0x36, 0x10, 0x91, 0x26, 0x90, 0xbd, 0x81,
+	0xa3, 0x4b, 0x03, 0x5c, 0xaf, 0x7d, 0x88, 0x78, 0x61, 0x6e, 0xdb, 0x25, 0xf5, 0xd1, 0xaf, 0x15,
+	0xb6, 0x6b, 0xbd, 0x80, 0xe3, 0x4d, 0xb6, 0x2d, 0x87, 0x21, 0x5e, 0x70, 0x29, 0x79, 0x39, 0xb3,
+	0x12, 0x0e, 0x4e, 0x7f, 0x87, 0x90, 0x5c, 0x78, 0x76, 0xba, 0x82, 0x8e, 0x33, 0x0f, 0x1a, 0xb4,
+	0xa6, 0xba, 0xe5, 0xd9, 0xf4, 0xf4, 0x1e, 0xd6, 0xae, 0xff, 0x01, 0xfa, 0x0c, 0xd0, 0xd8, 0x02,
+	0x3d, 0x6b, 0xfd, 0x7e, 0xc7, 0x8c, 0xe9, 0xf3, 0x7b, 0xf9, 0x8d, 0xe0, 0x5b, 0x38, 0xa8, 0xad,
+	0x80, 0x70, 0xeb, 0x57, 0xcf, 0x64, 0xe9, 0xc9, 0x0e, 0xc6, 0x4f, 0xaf, 0x77, 0xb0, 0x95, 0xee,
+	0x39, 0x22, 0x3d, 0xd9, 0xc1, 0xb8, 0xf4, 0xe9, 0x57, 0x88, 0x3f, 0xda, 0x17, 0xe1, 0x03, 0xc4,
+	0x76, 0xc2, 0xe8, 0xe9, 0x56, 0xdb, 0xfe, 0xd6, 0xd2, 0xc1, 0x6e, 0xd2, 0x49, 0xbe, 0x3b, 0xff,
+	0xf6, 0x6a, 0xc6, 0xd5, 0xcd, 0xea, 0x7a, 0x4c, 0xc5, 0x62, 0xb2, 0xe0, 0xb4, 0x12, 0xf6, 0x7b,
+	0x7b, 0x36, 0xd1, 0x2f, 0x89, 0xff, 0x4c, 0xbe, 0xf6, 0xce, 0xd7, 0x87, 0x9a, 0x3e, 0xfb, 0x3b,
+	0x00, 0x5c, 0xe9, 0xa1, 0x68, 0x54, 0x05, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ConnectionsClient is the client API for Connections service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ConnectionsClient interface {
+	// Register connections held by a gateway, gateways register their connections periodically
+	// so those of a gateway which has stopped expire
+	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
+	// Deregister a connection which has been closed
+	Deregister(ctx context.Context, in *DeregisterRequest, opts ...grpc.CallOption) (*DeregisterResponse, error)
+	// List the connections of an account
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	// Push a message to the connections of an account, via the gateways holding them
+	Push(ctx context.Context, in *PushRequest, opts ...grpc.CallOption) (*PushResponse, error)
+}
+
+type connectionsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewConnectionsClient(cc *grpc.ClientConn) ConnectionsClient {
+	return &connectionsClient{cc}
+}
+
+func (c *connectionsClient) Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error) {
+	out := new(RegisterResponse)
+	err := c.cc.Invoke(ctx, "/connections.Connections/Register", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connectionsClient) Deregister(ctx context.Context, in *DeregisterRequest, opts ...grpc.CallOption) (*DeregisterResponse, error) {
+	out := new(DeregisterResponse)
+	err := c.cc.Invoke(ctx, "/connections.Connections/Deregister", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connectionsClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := c.cc.Invoke(ctx, "/connections.Connections/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connectionsClient) Push(ctx context.Context, in *PushRequest, opts ...grpc.CallOption) (*PushResponse, error) {
+	out := new(PushResponse)
+	err := c.cc.Invoke(ctx, "/connections.Connections/Push", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConnectionsServer is the server API for Connections service.
+type ConnectionsServer interface {
+	// Register connections held by a gateway, gateways register their connections periodically
+	// so those of a gateway which has stopped expire
+	Register(context.Context, *RegisterRequest) (*RegisterResponse, error)
+	// Deregister a connection which has been closed
+	Deregister(context.Context, *DeregisterRequest) (*DeregisterResponse, error)
+	// List the connections of an account
+	List(context.Context, *ListRequest) (*ListResponse, error)
+	// Push a message to the connections of an account, via the gateways holding them
+	Push(context.Context, *PushRequest) (*PushResponse, error)
+}
+
+func RegisterConnectionsServer(s *grpc.Server, srv ConnectionsServer) {
+	s.RegisterService(&_Connections_serviceDesc, srv)
+}
+
+func _Connections_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConnectionsServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/connections.Connections/Register",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConnectionsServer).Register(ctx, req.(*RegisterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Connections_Deregister_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeregisterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConnectionsServer).Deregister(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/connections.Connections/Deregister",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConnectionsServer).Deregister(ctx, req.(*DeregisterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Connections_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConnectionsServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/connections.Connections/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConnectionsServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Connections_Push_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PushRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConnectionsServer).Push(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/connections.Connections/Push",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConnectionsServer).Push(ctx, req.(*PushRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Connections_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "connections.Connections",
+	HandlerType: (*ConnectionsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Register",
+			Handler:    _Connections_Register_Handler,
+		},
+		{
+			MethodName: "Deregister",
+			Handler:    _Connections_Deregister_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _Connections_List_Handler,
+		},
+		{
+			MethodName: "Push",
+			Handler:    _Connections_Push_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "connections/connections.proto",
+}
+
+// GatewayClient is the client API for Gateway service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GatewayClient interface {
+	Deliver(ctx context.Context, in *DeliverRequest, opts ...grpc.CallOption) (*DeliverResponse, error)
+}
+
+type gatewayClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewGatewayClient(cc *grpc.ClientConn) GatewayClient {
+	return &gatewayClient{cc}
+}
+
+func (c *gatewayClient) Deliver(ctx context.Context, in *DeliverRequest, opts ...grpc.CallOption) (*DeliverResponse, error) {
+	out := new(DeliverResponse)
+	err := c.cc.Invoke(ctx, "/connections.Gateway/Deliver", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GatewayServer is the server API for Gateway service.
+type GatewayServer interface {
+	Deliver(context.Context, *DeliverRequest) (*DeliverResponse, error)
+}
+
+func RegisterGatewayServer(s *grpc.Server, srv GatewayServer) {
+	s.RegisterService(&_Gateway_serviceDesc, srv)
+}
+
+func _Gateway_Deliver_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeliverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).Deliver(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/connections.Gateway/Deliver",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).Deliver(ctx, req.(*DeliverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Gateway_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "connections.Gateway",
+	HandlerType: (*GatewayServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Deliver",
+			Handler:    _Gateway_Deliver_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "connections/connections.proto",
+}
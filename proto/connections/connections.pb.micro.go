@@ -0,0 +1,213 @@
+// Code generated by protoc-gen-micro. DO NOT EDIT.
+// source: connections/connections.proto
+
+package connections
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+import (
+	context "context"
+	api "github.com/micro/micro/v3/service/api"
+	client "github.com/micro/micro/v3/service/client"
+	server "github.com/micro/micro/v3/service/server"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ api.Endpoint
+var _ context.Context
+var _ client.Option
+var _ server.Option
+
+// Api Endpoints for Connections service
+
+func NewConnectionsEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for Connections service
+
+type ConnectionsService interface {
+	// Register connections held by a gateway, gateways register their connections periodically
+	// so those of a gateway which has stopped expire
+	Register(ctx context.Context, in *RegisterRequest, opts ...client.CallOption) (*RegisterResponse, error)
+	// Deregister a connection which has been closed
+	Deregister(ctx context.Context, in *DeregisterRequest, opts ...client.CallOption) (*DeregisterResponse, error)
+	// List the connections of an account
+	List(ctx context.Context, in *ListRequest, opts ...client.CallOption) (*ListResponse, error)
+	// Push a message to the connections of an account, via the gateways holding them
+	Push(ctx context.Context, in *PushRequest, opts ...client.CallOption) (*PushResponse, error)
+}
+
+type connectionsService struct {
+	c    client.Client
+	name string
+}
+
+func NewConnectionsService(name string, c client.Client) ConnectionsService {
+	return &connectionsService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *connectionsService) Register(ctx context.Context, in *RegisterRequest, opts ...client.CallOption) (*RegisterResponse, error) {
+	req := c.c.NewRequest(c.name, "Connections.Register", in)
+	out := new(RegisterResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connectionsService) Deregister(ctx context.Context, in *DeregisterRequest, opts ...client.CallOption) (*DeregisterResponse, error) {
+	req := c.c.NewRequest(c.name, "Connections.Deregister", in)
+	out := new(DeregisterResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connectionsService) List(ctx context.Context, in *ListRequest, opts ...client.CallOption) (*ListResponse, error) {
+	req := c.c.NewRequest(c.name, "Connections.List", in)
+	out := new(ListResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *connectionsService) Push(ctx context.Context, in *PushRequest, opts ...client.CallOption) (*PushResponse, error) {
+	req := c.c.NewRequest(c.name, "Connections.Push", in)
+	out := new(PushResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Connections service
+
+type ConnectionsHandler interface {
+	// Register connections held by a gateway, gateways register their connections periodically
+	// so those of a gateway which has stopped expire
+	Register(context.Context, *RegisterRequest, *RegisterResponse) error
+	// Deregister a connection which has been closed
+	Deregister(context.Context, *DeregisterRequest, *DeregisterResponse) error
+	// List the connections of an account
+	List(context.Context, *ListRequest, *ListResponse) error
+	// Push a message to the connections of an account, via the gateways holding them
+	Push(context.Context, *PushRequest, *PushResponse) error
+}
+
+func RegisterConnectionsHandler(s server.Server, hdlr ConnectionsHandler, opts ...server.HandlerOption) error {
+	type connections interface {
+		Register(ctx context.Context, in *RegisterRequest, out *RegisterResponse) error
+		Deregister(ctx context.Context, in *DeregisterRequest, out *DeregisterResponse) error
+		List(ctx context.Context, in *ListRequest, out *ListResponse) error
+		Push(ctx context.Context, in *PushRequest, out *PushResponse) error
+	}
+	type Connections struct {
+		connections
+	}
+	h := &connectionsHandler{hdlr}
+	return s.Handle(s.NewHandler(&Connections{h}, opts...))
+}
+
+type connectionsHandler struct {
+	ConnectionsHandler
+}
+
+func (h *connectionsHandler) Register(ctx context.Context, in *RegisterRequest, out *RegisterResponse) error {
+	return h.ConnectionsHandler.Register(ctx, in, out)
+}
+
+func (h *connectionsHandler) Deregister(ctx context.Context, in *DeregisterRequest, out *DeregisterResponse) error {
+	return h.ConnectionsHandler.Deregister(ctx, in, out)
+}
+
+func (h *connectionsHandler) List(ctx context.Context, in *ListRequest, out *ListResponse) error {
+	return h.ConnectionsHandler.List(ctx, in, out)
+}
+
+func (h *connectionsHandler) Push(ctx context.Context, in *PushRequest, out *PushResponse) error {
+	return h.ConnectionsHandler.Push(ctx, in, out)
+}
+
+// Api Endpoints for Gateway service
+
+func NewGatewayEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for Gateway service
+
+type GatewayService interface {
+	Deliver(ctx context.Context, in *DeliverRequest, opts ...client.CallOption) (*DeliverResponse, error)
+}
+
+type gatewayService struct {
+	c    client.Client
+	name string
+}
+
+func NewGatewayService(name string, c client.Client) GatewayService {
+	return &gatewayService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *gatewayService) Deliver(ctx context.Context, in *DeliverRequest, opts ...client.CallOption) (*DeliverResponse, error) {
+	req := c.c.NewRequest(c.name, "Gateway.Deliver", in)
+	out := new(DeliverResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Gateway service
+
+type GatewayHandler interface {
+	Deliver(context.Context, *DeliverRequest, *DeliverResponse) error
+}
+
+func RegisterGatewayHandler(s server.Server, hdlr GatewayHandler, opts ...server.HandlerOption) error {
+	type gateway interface {
+		Deliver(ctx context.Context, in *DeliverRequest, out *DeliverResponse) error
+	}
+	type Gateway struct {
+		gateway
+	}
+	h := &gatewayHandler{hdlr}
+	return s.Handle(s.NewHandler(&Gateway{h}, opts...))
+}
+
+type gatewayHandler struct {
+	GatewayHandler
+}
+
+func (h *gatewayHandler) Deliver(ctx context.Context, in *DeliverRequest, out *DeliverResponse) error {
+	return h.GatewayHandler.Deliver(ctx, in, out)
+}
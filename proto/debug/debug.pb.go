@@ -80,10 +80,12 @@ var xxx_messageInfo_HealthRequest proto.InternalMessageInfo
 
 type HealthResponse struct {
 	// default: ok
-	Status               string   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Status string `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	// health of the dependencies keyed by name, ok or the error of their check
+	Dependencies         map[string]string `protobuf:"bytes,2,rep,name=dependencies,proto3" json:"dependencies,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *HealthResponse) Reset()         { *m = HealthResponse{} }
@@ -118,6 +120,13 @@ func (m *HealthResponse) GetStatus() string {
 	return ""
 }
 
+func (m *HealthResponse) GetDependencies() map[string]string {
+	if m != nil {
+		return m.Dependencies
+	}
+	return nil
+}
+
 type StatsRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -165,7 +174,17 @@ type StatsResponse struct {
 	// total number of requests
 	Requests uint64 `protobuf:"varint,7,opt,name=requests,proto3" json:"requests,omitempty"`
 	// total number of errors
-	Errors               uint64   `protobuf:"varint,8,opt,name=errors,proto3" json:"errors,omitempty"`
+	Errors uint64 `protobuf:"varint,8,opt,name=errors,proto3" json:"errors,omitempty"`
+	// largest clock offset of the services called, in milliseconds
+	ClockSkew int64 `protobuf:"varint,9,opt,name=clock_skew,json=clockSkew,proto3" json:"clock_skew,omitempty"`
+	// clock offset of each service called, in milliseconds
+	ClockSkews map[string]int64 `protobuf:"bytes,10,rep,name=clock_skews,json=clockSkews,proto3" json:"clock_skews,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// number of requests being handled
+	InFlight uint64 `protobuf:"varint,11,opt,name=in_flight,json=inFlight,proto3" json:"in_flight,omitempty"`
+	// number of requests waiting to be handled
+	Queued uint64 `protobuf:"varint,12,opt,name=queued,proto3" json:"queued,omitempty"`
+	// total number of requests rejected because the queue was full
+	Rejected             uint64   `protobuf:"varint,13,opt,name=rejected,proto3" json:"rejected,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -252,6 +271,41 @@ func (m *StatsResponse) GetErrors() uint64 {
 	return 0
 }
 
+func (m *StatsResponse) GetClockSkew() int64 {
+	if m != nil {
+		return m.ClockSkew
+	}
+	return 0
+}
+
+func (m *StatsResponse) GetClockSkews() map[string]int64 {
+	if m != nil {
+		return m.ClockSkews
+	}
+	return nil
+}
+
+func (m *StatsResponse) GetInFlight() uint64 {
+	if m != nil {
+		return m.InFlight
+	}
+	return 0
+}
+
+func (m *StatsResponse) GetQueued() uint64 {
+	if m != nil {
+		return m.Queued
+	}
+	return 0
+}
+
+func (m *StatsResponse) GetRejected() uint64 {
+	if m != nil {
+		return m.Rejected
+	}
+	return 0
+}
+
 // LogRequest requests service logs
 type LogRequest struct {
 	// count of records to request
@@ -584,12 +638,112 @@ func (m *Span) GetType() SpanType {
 	return SpanType_INBOUND
 }
 
+type MiddlewareRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MiddlewareRequest) Reset()         { *m = MiddlewareRequest{} }
+func (m *MiddlewareRequest) String() string { return proto.CompactTextString(m) }
+func (*MiddlewareRequest) ProtoMessage()    {}
+func (*MiddlewareRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ae24eab94cb53d5, []int{10}
+}
+
+func (m *MiddlewareRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MiddlewareRequest.Unmarshal(m, b)
+}
+func (m *MiddlewareRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MiddlewareRequest.Marshal(b, m, deterministic)
+}
+func (m *MiddlewareRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MiddlewareRequest.Merge(m, src)
+}
+func (m *MiddlewareRequest) XXX_Size() int {
+	return xxx_messageInfo_MiddlewareRequest.Size(m)
+}
+func (m *MiddlewareRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MiddlewareRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MiddlewareRequest proto.InternalMessageInfo
+
+type MiddlewareResponse struct {
+	// client wrappers in the order calls pass through them
+	Client []string `protobuf:"bytes,1,rep,name=client,proto3" json:"client,omitempty"`
+	// call wrappers, run for each attempt to call a node
+	Call []string `protobuf:"bytes,2,rep,name=call,proto3" json:"call,omitempty"`
+	// handler wrappers in the order requests pass through them
+	Handler []string `protobuf:"bytes,3,rep,name=handler,proto3" json:"handler,omitempty"`
+	// subscriber wrappers in the order messages pass through them
+	Subscriber           []string `protobuf:"bytes,4,rep,name=subscriber,proto3" json:"subscriber,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MiddlewareResponse) Reset()         { *m = MiddlewareResponse{} }
+func (m *MiddlewareResponse) String() string { return proto.CompactTextString(m) }
+func (*MiddlewareResponse) ProtoMessage()    {}
+func (*MiddlewareResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ae24eab94cb53d5, []int{11}
+}
+
+func (m *MiddlewareResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MiddlewareResponse.Unmarshal(m, b)
+}
+func (m *MiddlewareResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MiddlewareResponse.Marshal(b, m, deterministic)
+}
+func (m *MiddlewareResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MiddlewareResponse.Merge(m, src)
+}
+func (m *MiddlewareResponse) XXX_Size() int {
+	return xxx_messageInfo_MiddlewareResponse.Size(m)
+}
+func (m *MiddlewareResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MiddlewareResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MiddlewareResponse proto.InternalMessageInfo
+
+func (m *MiddlewareResponse) GetClient() []string {
+	if m != nil {
+		return m.Client
+	}
+	return nil
+}
+
+func (m *MiddlewareResponse) GetCall() []string {
+	if m != nil {
+		return m.Call
+	}
+	return nil
+}
+
+func (m *MiddlewareResponse) GetHandler() []string {
+	if m != nil {
+		return m.Handler
+	}
+	return nil
+}
+
+func (m *MiddlewareResponse) GetSubscriber() []string {
+	if m != nil {
+		return m.Subscriber
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("debug.SpanType", SpanType_name, SpanType_value)
 	proto.RegisterType((*HealthRequest)(nil), "debug.HealthRequest")
 	proto.RegisterType((*HealthResponse)(nil), "debug.HealthResponse")
+	proto.RegisterMapType((map[string]string)(nil), "debug.HealthResponse.DependenciesEntry")
 	proto.RegisterType((*StatsRequest)(nil), "debug.StatsRequest")
 	proto.RegisterType((*StatsResponse)(nil), "debug.StatsResponse")
+	proto.RegisterMapType((map[string]int64)(nil), "debug.StatsResponse.ClockSkewsEntry")
 	proto.RegisterType((*LogRequest)(nil), "debug.LogRequest")
 	proto.RegisterType((*LogResponse)(nil), "debug.LogResponse")
 	proto.RegisterType((*Record)(nil), "debug.Record")
@@ -598,51 +752,67 @@ func init() {
 	proto.RegisterType((*TraceResponse)(nil), "debug.TraceResponse")
 	proto.RegisterType((*Span)(nil), "debug.Span")
 	proto.RegisterMapType((map[string]string)(nil), "debug.Span.MetadataEntry")
+	proto.RegisterType((*MiddlewareRequest)(nil), "debug.MiddlewareRequest")
+	proto.RegisterType((*MiddlewareResponse)(nil), "debug.MiddlewareResponse")
 }
 
 func init() { proto.RegisterFile("debug/debug.proto", fileDescriptor_5ae24eab94cb53d5) }
 
 var fileDescriptor_5ae24eab94cb53d5 = []byte{
-	// 623 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0xdd, 0x6e, 0xd3, 0x4c,
-	0x10, 0xad, 0xed, 0x38, 0x71, 0x26, 0x4d, 0xda, 0xee, 0xd7, 0x0f, 0x19, 0x83, 0x10, 0x18, 0x21,
-	0x2a, 0x10, 0xa9, 0x94, 0x02, 0xad, 0xe8, 0x5d, 0x55, 0x24, 0x90, 0x4a, 0x2b, 0x6d, 0xdb, 0x1b,
-	0xee, 0x36, 0xf6, 0xca, 0xb1, 0xa8, 0xbd, 0x66, 0x77, 0x5d, 0x29, 0x4f, 0xc4, 0x1b, 0xf0, 0x26,
-	0xdc, 0xf1, 0x30, 0x68, 0x7f, 0xec, 0xda, 0x20, 0xae, 0xb8, 0xb1, 0xf6, 0x9c, 0xf1, 0x99, 0x9d,
-	0x39, 0x3b, 0x1a, 0xd8, 0x49, 0xe9, 0xb2, 0xce, 0xf6, 0xf5, 0x77, 0x5e, 0x71, 0x26, 0x19, 0xf2,
-	0x35, 0x88, 0xb7, 0x60, 0xfa, 0x81, 0x92, 0x1b, 0xb9, 0xc2, 0xf4, 0x6b, 0x4d, 0x85, 0x8c, 0xf7,
-	0x60, 0xd6, 0x10, 0xa2, 0x62, 0xa5, 0xa0, 0xe8, 0x1e, 0x0c, 0x85, 0x24, 0xb2, 0x16, 0xa1, 0xf3,
-	0xd8, 0xd9, 0x1b, 0x63, 0x8b, 0xe2, 0x19, 0x6c, 0x5e, 0x4a, 0x22, 0x45, 0xa3, 0xfc, 0xe1, 0xc0,
-	0xd4, 0x12, 0x56, 0xf9, 0x10, 0xc6, 0x32, 0x2f, 0xa8, 0x90, 0xa4, 0xa8, 0xb4, 0x78, 0x80, 0xef,
-	0x08, 0x14, 0xc2, 0x48, 0x48, 0xc2, 0x25, 0x4d, 0x43, 0x57, 0xc7, 0x1a, 0xa8, 0x6e, 0xac, 0x2b,
-	0xf5, 0x63, 0xe8, 0xe9, 0x80, 0x45, 0x8a, 0x2f, 0x68, 0xc1, 0xf8, 0x3a, 0x1c, 0x18, 0xde, 0x20,
-	0x95, 0x49, 0xae, 0x38, 0x25, 0xa9, 0x08, 0x7d, 0x93, 0xc9, 0x42, 0x34, 0x03, 0x37, 0x4b, 0xc2,
-	0xa1, 0x26, 0xdd, 0x2c, 0x41, 0x11, 0x04, 0xdc, 0x94, 0x2b, 0xc2, 0x91, 0x66, 0x5b, 0xac, 0xb2,
-	0x53, 0xce, 0x19, 0x17, 0x61, 0x60, 0xb2, 0x1b, 0x14, 0x1f, 0x01, 0x9c, 0xb1, 0xcc, 0x76, 0x89,
-	0x76, 0xc1, 0x4f, 0x58, 0x5d, 0x4a, 0xdd, 0x8f, 0x87, 0x0d, 0x50, 0xac, 0xc8, 0xcb, 0x84, 0xea,
-	0x4e, 0x3c, 0x6c, 0x40, 0xfc, 0x16, 0x26, 0x5a, 0x69, 0xed, 0x78, 0x0e, 0x23, 0x4e, 0x13, 0xc6,
-	0x53, 0xe5, 0xa4, 0xb7, 0x37, 0x59, 0x4c, 0xe7, 0xe6, 0x45, 0xb0, 0x66, 0x71, 0x13, 0x8d, 0xbf,
-	0x3b, 0x30, 0x34, 0xdc, 0x9f, 0x16, 0x7a, 0x5d, 0x0b, 0x0f, 0x21, 0x28, 0xa8, 0x24, 0x29, 0x91,
-	0x24, 0x74, 0x75, 0xca, 0x07, 0xbd, 0x94, 0xf3, 0x4f, 0x36, 0xfa, 0xbe, 0x94, 0x7c, 0x8d, 0xdb,
-	0x9f, 0x95, 0x63, 0x05, 0x15, 0x82, 0x64, 0xc6, 0xe2, 0x31, 0x6e, 0x60, 0x74, 0x0c, 0xd3, 0x9e,
-	0x08, 0x6d, 0x83, 0xf7, 0x85, 0xae, 0xed, 0xdb, 0xab, 0xa3, 0x6a, 0xf6, 0x96, 0xdc, 0xd4, 0xa6,
-	0xd9, 0x31, 0x36, 0xe0, 0x9d, 0x7b, 0xe4, 0xc4, 0x8f, 0x60, 0xf3, 0x8a, 0x93, 0x84, 0x36, 0x66,
-	0xcd, 0xc0, 0xcd, 0x53, 0x2b, 0x75, 0xf3, 0x34, 0x5e, 0xc0, 0xd4, 0xc6, 0xad, 0x25, 0x4f, 0xc0,
-	0x17, 0x15, 0x29, 0x1b, 0x43, 0x26, 0xb6, 0xfa, 0xcb, 0x8a, 0x94, 0xd8, 0x44, 0xe2, 0x6f, 0x2e,
-	0x0c, 0x14, 0x56, 0xd7, 0x4a, 0x25, 0xb6, 0xf9, 0x0c, 0xb0, 0x57, 0xb8, 0xcd, 0x15, 0xea, 0x15,
-	0x2b, 0xc2, 0x69, 0x29, 0x6d, 0x63, 0x16, 0x21, 0x04, 0x83, 0x92, 0x14, 0x54, 0x4f, 0xce, 0x18,
-	0xeb, 0x73, 0x77, 0x02, 0xfd, 0xfe, 0x04, 0x46, 0x10, 0xa4, 0x35, 0x27, 0x32, 0x67, 0xa5, 0x9d,
-	0x9e, 0x16, 0xa3, 0x37, 0x1d, 0xd3, 0x47, 0xba, 0xec, 0xfb, 0x9d, 0xb2, 0xff, 0x6a, 0xf9, 0x53,
-	0x18, 0xc8, 0x75, 0x45, 0xf5, 0x70, 0xcd, 0x16, 0x5b, 0x1d, 0xc9, 0xd5, 0xba, 0xa2, 0x58, 0x07,
-	0xff, 0xc9, 0xfd, 0x17, 0xcf, 0x20, 0x68, 0xd2, 0xa1, 0x09, 0x8c, 0x3e, 0x9e, 0x9f, 0x5c, 0x5c,
-	0x9f, 0x9f, 0x6e, 0x6f, 0xa0, 0x4d, 0x08, 0x2e, 0xae, 0xaf, 0x0c, 0x72, 0x16, 0x3f, 0x1d, 0xf0,
-	0x4f, 0xd5, 0xe5, 0x68, 0x0e, 0xde, 0x19, 0xcb, 0xd0, 0x8e, 0xad, 0xe5, 0x6e, 0xca, 0x23, 0xd4,
-	0xa5, 0xcc, 0x5b, 0xc5, 0x1b, 0xe8, 0x10, 0x86, 0x66, 0x37, 0xa0, 0x5d, 0x1b, 0xef, 0xed, 0x8e,
-	0xe8, 0xff, 0xdf, 0xd8, 0x56, 0xf8, 0x1a, 0x7c, 0xbd, 0x19, 0xd0, 0x7f, 0x4d, 0xdb, 0x9d, 0xc5,
-	0x11, 0xed, 0xf6, 0xc9, 0xae, 0x4a, 0x4f, 0x4b, 0xab, 0xea, 0xce, 0x56, 0xab, 0xea, 0x0d, 0x54,
-	0xbc, 0x71, 0xf2, 0xea, 0xf3, 0xcb, 0x2c, 0x97, 0xab, 0x7a, 0x39, 0x4f, 0x58, 0xb1, 0x5f, 0xe4,
-	0x09, 0x67, 0xf6, 0x7b, 0x7b, 0x60, 0x36, 0xe0, 0xbe, 0xde, 0x80, 0xc7, 0xfa, 0xbc, 0x1c, 0x6a,
-	0x70, 0xf0, 0x2b, 0x00, 0x00, 0xff, 0xff, 0x59, 0x82, 0x51, 0x15, 0x23, 0x05, 0x00, 0x00,
+	// 841 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x55, 0xdb, 0x8e, 0xdc, 0x44,
+	0x10, 0xdd, 0xb1, 0xe7, 0xe6, 0x9a, 0xcb, 0x66, 0x3b, 0x0b, 0xea, 0x38, 0x10, 0x2d, 0x06, 0x94,
+	0x15, 0x88, 0x59, 0x69, 0x02, 0x24, 0x22, 0x42, 0x48, 0xc9, 0x06, 0x81, 0xc8, 0x45, 0x72, 0x36,
+	0x2f, 0xbc, 0x44, 0x3d, 0x76, 0xe1, 0x31, 0xe3, 0x5b, 0xba, 0xdb, 0x59, 0x0d, 0x7f, 0xc3, 0x13,
+	0x7f, 0xc0, 0x4f, 0xf0, 0x53, 0xa8, 0x2f, 0x9e, 0xb1, 0xb3, 0x8b, 0x10, 0xe2, 0xc5, 0xea, 0x73,
+	0xaa, 0xab, 0xba, 0xeb, 0x54, 0x55, 0x1b, 0x8e, 0x62, 0x5c, 0xd5, 0xc9, 0x99, 0xfe, 0x2e, 0x2a,
+	0x5e, 0xca, 0x92, 0x0c, 0x34, 0x08, 0x0e, 0x61, 0xf6, 0x03, 0xb2, 0x4c, 0xae, 0x43, 0x7c, 0x53,
+	0xa3, 0x90, 0xc1, 0x9f, 0x3d, 0x98, 0x37, 0x8c, 0xa8, 0xca, 0x42, 0x20, 0x79, 0x1f, 0x86, 0x42,
+	0x32, 0x59, 0x0b, 0xda, 0x3b, 0xe9, 0x9d, 0x7a, 0xa1, 0x45, 0xe4, 0x27, 0x98, 0xc6, 0x58, 0x61,
+	0x11, 0x63, 0x11, 0xa5, 0x28, 0xa8, 0x73, 0xe2, 0x9e, 0x4e, 0x96, 0x77, 0x17, 0xe6, 0x98, 0x6e,
+	0x90, 0xc5, 0x79, 0x6b, 0xe7, 0x93, 0x42, 0xf2, 0x6d, 0xd8, 0x71, 0xf6, 0xbf, 0x83, 0xa3, 0x2b,
+	0x5b, 0xc8, 0x0d, 0x70, 0x37, 0xb8, 0xb5, 0xc7, 0xaa, 0x25, 0x39, 0x86, 0xc1, 0x5b, 0x96, 0xd5,
+	0x48, 0x1d, 0xcd, 0x19, 0xf0, 0x8d, 0xf3, 0xa0, 0x17, 0xcc, 0x61, 0xfa, 0x52, 0x32, 0x29, 0x9a,
+	0x44, 0xfe, 0x72, 0x61, 0x66, 0x09, 0x9b, 0xc7, 0x07, 0xe0, 0xc9, 0x34, 0x47, 0x21, 0x59, 0x5e,
+	0xe9, 0x98, 0xfd, 0x70, 0x4f, 0x10, 0x0a, 0x23, 0x21, 0x19, 0x97, 0x18, 0xeb, 0xd8, 0xfd, 0xb0,
+	0x81, 0x2a, 0xff, 0xba, 0x52, 0x1b, 0xa9, 0xab, 0x0d, 0x16, 0x29, 0x3e, 0xc7, 0xbc, 0xe4, 0x5b,
+	0xda, 0x37, 0xbc, 0x41, 0x2a, 0x92, 0x5c, 0x73, 0x64, 0xb1, 0xa0, 0x03, 0x13, 0xc9, 0x42, 0x32,
+	0x07, 0x27, 0x89, 0xe8, 0x50, 0x93, 0x4e, 0x12, 0x11, 0x1f, 0xc6, 0xdc, 0x5c, 0x57, 0xd0, 0x91,
+	0x66, 0x77, 0x58, 0x45, 0x47, 0xce, 0x4b, 0x2e, 0xe8, 0xd8, 0x44, 0x37, 0x88, 0x7c, 0x08, 0x10,
+	0x65, 0x65, 0xb4, 0x79, 0x2d, 0x36, 0x78, 0x49, 0xbd, 0x93, 0xde, 0xa9, 0x1b, 0x7a, 0x9a, 0x79,
+	0xb9, 0xc1, 0x4b, 0xf2, 0x04, 0x26, 0x7b, 0xb3, 0xa0, 0xa0, 0x6b, 0xf2, 0x89, 0xad, 0x49, 0x47,
+	0x8f, 0xc5, 0xe3, 0xc6, 0xc9, 0x16, 0x04, 0x76, 0x51, 0x04, 0xb9, 0x0d, 0x5e, 0x5a, 0xbc, 0xfe,
+	0x25, 0x4b, 0x93, 0xb5, 0xa4, 0x13, 0x73, 0xb5, 0xb4, 0xf8, 0x5e, 0x63, 0x75, 0xb5, 0x37, 0x35,
+	0xd6, 0x18, 0xd3, 0xa9, 0xb9, 0x9a, 0x41, 0x26, 0x9d, 0x5f, 0x31, 0x52, 0x1a, 0xce, 0x9a, 0x74,
+	0x0c, 0xf6, 0xbf, 0x85, 0xc3, 0x77, 0xce, 0xfb, 0xb7, 0xea, 0xba, 0xed, 0xea, 0x3e, 0x00, 0x78,
+	0x5a, 0x26, 0xb6, 0xb6, 0x6a, 0x5f, 0x54, 0xd6, 0x85, 0xd4, 0xbe, 0x6e, 0x68, 0x80, 0x62, 0x45,
+	0x5a, 0x44, 0x3b, 0x6f, 0x0d, 0x82, 0xaf, 0x61, 0xa2, 0x3d, 0x6d, 0x13, 0xdc, 0x85, 0x11, 0xc7,
+	0xa8, 0xe4, 0xb1, 0xea, 0x66, 0xa5, 0xcd, 0xcc, 0x6a, 0x13, 0x6a, 0x36, 0x6c, 0xac, 0x6a, 0x10,
+	0x86, 0x86, 0xbb, 0xda, 0x38, 0x6e, 0xbb, 0x71, 0xee, 0xc3, 0x38, 0x47, 0xc9, 0x62, 0x26, 0x99,
+	0x1d, 0x81, 0xdb, 0x9d, 0x90, 0x8b, 0x67, 0xd6, 0x6a, 0x54, 0xde, 0x6d, 0x56, 0x7d, 0x92, 0xa3,
+	0x10, 0x2c, 0x31, 0x8d, 0xe5, 0x85, 0x0d, 0xf4, 0x1f, 0xc2, 0xac, 0xe3, 0xf4, 0x9f, 0x06, 0xe1,
+	0x0e, 0x4c, 0x2f, 0x38, 0x8b, 0xb0, 0x11, 0x6b, 0x0e, 0x4e, 0x1a, 0x5b, 0x57, 0x27, 0x8d, 0x83,
+	0x25, 0xcc, 0xac, 0xdd, 0x4a, 0xf2, 0x11, 0x0c, 0x44, 0xc5, 0x8a, 0x46, 0x90, 0x49, 0xd3, 0x2c,
+	0x15, 0x2b, 0x42, 0x63, 0x09, 0xfe, 0x70, 0xa0, 0xaf, 0xb0, 0x3a, 0x56, 0x2a, 0x67, 0x1b, 0xcf,
+	0x00, 0x7b, 0x84, 0xd3, 0x1c, 0xa1, 0x1a, 0xa4, 0x62, 0x1c, 0x0b, 0x69, 0x13, 0xb3, 0x88, 0x10,
+	0xe8, 0x17, 0x2c, 0x47, 0x3d, 0x2f, 0x5e, 0xa8, 0xd7, 0xed, 0xb9, 0x1b, 0x74, 0xe7, 0xce, 0x87,
+	0x71, 0x5c, 0x73, 0x26, 0xd3, 0xb2, 0xb0, 0x33, 0xb3, 0xc3, 0xe4, 0xab, 0x96, 0xe8, 0x23, 0x7d,
+	0xed, 0x5b, 0xad, 0x6b, 0xff, 0xa3, 0xe4, 0x1f, 0x43, 0x5f, 0x6e, 0x2b, 0xd4, 0x23, 0x35, 0x5f,
+	0x1e, 0xb6, 0x5c, 0x2e, 0xb6, 0x15, 0x86, 0xda, 0xf8, 0xff, 0xd4, 0xbf, 0x09, 0x47, 0xcf, 0xd2,
+	0x38, 0xce, 0xf0, 0x92, 0xf1, 0xa6, 0x04, 0xc1, 0x6f, 0x40, 0xda, 0xe4, 0xfe, 0x5d, 0x8d, 0xb2,
+	0x14, 0x75, 0x1b, 0xbb, 0x4a, 0x25, 0x83, 0x94, 0x4a, 0x11, 0xcb, 0x32, 0xdd, 0x4c, 0x5e, 0xa8,
+	0xd7, 0x4a, 0xa5, 0x35, 0x2b, 0xe2, 0x0c, 0x39, 0x75, 0x35, 0xdd, 0x40, 0x72, 0x07, 0x40, 0xd4,
+	0x2b, 0x11, 0xf1, 0x74, 0x85, 0x9c, 0xf6, 0xb5, 0xb1, 0xc5, 0x7c, 0xf6, 0x29, 0x8c, 0x9b, 0xfc,
+	0xc8, 0x04, 0x46, 0x3f, 0x3e, 0x7f, 0xf4, 0xe2, 0xd5, 0xf3, 0xf3, 0x1b, 0x07, 0x64, 0x0a, 0xe3,
+	0x17, 0xaf, 0x2e, 0x0c, 0xea, 0x2d, 0x7f, 0x77, 0x60, 0x70, 0xae, 0xd4, 0x20, 0x0b, 0x70, 0x9f,
+	0x96, 0x09, 0x39, 0xb2, 0xe2, 0xec, 0xc7, 0xce, 0x27, 0x6d, 0xca, 0x24, 0x11, 0x1c, 0x90, 0xfb,
+	0x30, 0x34, 0x6f, 0x3d, 0x39, 0x7e, 0xe7, 0xe9, 0x37, 0x5e, 0xef, 0x5d, 0xfb, 0x43, 0x08, 0x0e,
+	0xc8, 0x97, 0x30, 0xd0, 0x0f, 0x12, 0xb9, 0xd9, 0x7d, 0x9e, 0x8c, 0xdb, 0xf1, 0x75, 0x6f, 0x96,
+	0xf1, 0xd2, 0xed, 0xbb, 0xf3, 0x6a, 0x37, 0xbb, 0x7f, 0xdc, 0x25, 0x77, 0x5e, 0x8f, 0x01, 0xf6,
+	0x15, 0x20, 0xd4, 0xee, 0xba, 0x52, 0x29, 0xff, 0xd6, 0x35, 0x96, 0x26, 0xc8, 0xa3, 0x2f, 0x7e,
+	0xfe, 0x3c, 0x49, 0xe5, 0xba, 0x5e, 0x2d, 0xa2, 0x32, 0x3f, 0xcb, 0xd3, 0x88, 0x97, 0xf6, 0xfb,
+	0xf6, 0x9e, 0xf9, 0xb9, 0x9e, 0xe9, 0x9f, 0xeb, 0x43, 0xbd, 0x5e, 0x0d, 0x35, 0xb8, 0xf7, 0xf7,
+	0x00, 0xec, 0x9b, 0x09, 0xc8, 0x7e, 0x07, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -661,6 +831,7 @@ type DebugClient interface {
 	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
 	Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
 	Trace(ctx context.Context, in *TraceRequest, opts ...grpc.CallOption) (*TraceResponse, error)
+	Middleware(ctx context.Context, in *MiddlewareRequest, opts ...grpc.CallOption) (*MiddlewareResponse, error)
 }
 
 type debugClient struct {
@@ -707,12 +878,22 @@ func (c *debugClient) Trace(ctx context.Context, in *TraceRequest, opts ...grpc.
 	return out, nil
 }
 
+func (c *debugClient) Middleware(ctx context.Context, in *MiddlewareRequest, opts ...grpc.CallOption) (*MiddlewareResponse, error) {
+	out := new(MiddlewareResponse)
+	err := c.cc.Invoke(ctx, "/debug.Debug/Middleware", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DebugServer is the server API for Debug service.
 type DebugServer interface {
 	Log(context.Context, *LogRequest) (*LogResponse, error)
 	Health(context.Context, *HealthRequest) (*HealthResponse, error)
 	Stats(context.Context, *StatsRequest) (*StatsResponse, error)
 	Trace(context.Context, *TraceRequest) (*TraceResponse, error)
+	Middleware(context.Context, *MiddlewareRequest) (*MiddlewareResponse, error)
 }
 
 func RegisterDebugServer(s *grpc.Server, srv DebugServer) {
@@ -791,6 +972,24 @@ func _Debug_Trace_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Debug_Middleware_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MiddlewareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebugServer).Middleware(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/debug.Debug/Middleware",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DebugServer).Middleware(ctx, req.(*MiddlewareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Debug_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "debug.Debug",
 	HandlerType: (*DebugServer)(nil),
@@ -811,6 +1010,10 @@ var _Debug_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Trace",
 			Handler:    _Debug_Trace_Handler,
 		},
+		{
+			MethodName: "Middleware",
+			Handler:    _Debug_Middleware_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "debug/debug.proto",
@@ -46,6 +46,7 @@ type DebugService interface {
 	Health(ctx context.Context, in *HealthRequest, opts ...client.CallOption) (*HealthResponse, error)
 	Stats(ctx context.Context, in *StatsRequest, opts ...client.CallOption) (*StatsResponse, error)
 	Trace(ctx context.Context, in *TraceRequest, opts ...client.CallOption) (*TraceResponse, error)
+	Middleware(ctx context.Context, in *MiddlewareRequest, opts ...client.CallOption) (*MiddlewareResponse, error)
 }
 
 type debugService struct {
@@ -100,6 +101,16 @@ func (c *debugService) Trace(ctx context.Context, in *TraceRequest, opts ...clie
 	return out, nil
 }
 
+func (c *debugService) Middleware(ctx context.Context, in *MiddlewareRequest, opts ...client.CallOption) (*MiddlewareResponse, error) {
+	req := c.c.NewRequest(c.name, "Debug.Middleware", in)
+	out := new(MiddlewareResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Debug service
 
 type DebugHandler interface {
@@ -107,6 +118,7 @@ type DebugHandler interface {
 	Health(context.Context, *HealthRequest, *HealthResponse) error
 	Stats(context.Context, *StatsRequest, *StatsResponse) error
 	Trace(context.Context, *TraceRequest, *TraceResponse) error
+	Middleware(context.Context, *MiddlewareRequest, *MiddlewareResponse) error
 }
 
 func RegisterDebugHandler(s server.Server, hdlr DebugHandler, opts ...server.HandlerOption) error {
@@ -115,6 +127,7 @@ func RegisterDebugHandler(s server.Server, hdlr DebugHandler, opts ...server.Han
 		Health(ctx context.Context, in *HealthRequest, out *HealthResponse) error
 		Stats(ctx context.Context, in *StatsRequest, out *StatsResponse) error
 		Trace(ctx context.Context, in *TraceRequest, out *TraceResponse) error
+		Middleware(ctx context.Context, in *MiddlewareRequest, out *MiddlewareResponse) error
 	}
 	type Debug struct {
 		debug
@@ -142,3 +155,7 @@ func (h *debugHandler) Stats(ctx context.Context, in *StatsRequest, out *StatsRe
 func (h *debugHandler) Trace(ctx context.Context, in *TraceRequest, out *TraceResponse) error {
 	return h.DebugHandler.Trace(ctx, in, out)
 }
+
+func (h *debugHandler) Middleware(ctx context.Context, in *MiddlewareRequest, out *MiddlewareResponse) error {
+	return h.DebugHandler.Middleware(ctx, in, out)
+}
@@ -439,6 +439,84 @@ func (m *WriteResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_WriteResponse proto.InternalMessageInfo
 
+type DeleteRequest struct {
+	Topic                string   `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteRequest) Reset()         { *m = DeleteRequest{} }
+func (m *DeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteRequest) ProtoMessage()    {}
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8ec31f2d2a3db598, []int{8}
+}
+
+func (m *DeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteRequest.Unmarshal(m, b)
+}
+func (m *DeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteRequest.Merge(m, src)
+}
+func (m *DeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteRequest.Size(m)
+}
+func (m *DeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteRequest proto.InternalMessageInfo
+
+func (m *DeleteRequest) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+func (m *DeleteRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type DeleteResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteResponse) Reset()         { *m = DeleteResponse{} }
+func (m *DeleteResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteResponse) ProtoMessage()    {}
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8ec31f2d2a3db598, []int{9}
+}
+
+func (m *DeleteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteResponse.Unmarshal(m, b)
+}
+func (m *DeleteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteResponse.Merge(m, src)
+}
+func (m *DeleteResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteResponse.Size(m)
+}
+func (m *DeleteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteResponse proto.InternalMessageInfo
+
 type AckRequest struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Success              bool     `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
@@ -451,7 +529,7 @@ func (m *AckRequest) Reset()         { *m = AckRequest{} }
 func (m *AckRequest) String() string { return proto.CompactTextString(m) }
 func (*AckRequest) ProtoMessage()    {}
 func (*AckRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8ec31f2d2a3db598, []int{8}
+	return fileDescriptor_8ec31f2d2a3db598, []int{10}
 }
 
 func (m *AckRequest) XXX_Unmarshal(b []byte) error {
@@ -497,49 +575,53 @@ func init() {
 	proto.RegisterType((*ReadResponse)(nil), "events.ReadResponse")
 	proto.RegisterType((*WriteRequest)(nil), "events.WriteRequest")
 	proto.RegisterType((*WriteResponse)(nil), "events.WriteResponse")
+	proto.RegisterType((*DeleteRequest)(nil), "events.DeleteRequest")
+	proto.RegisterType((*DeleteResponse)(nil), "events.DeleteResponse")
 	proto.RegisterType((*AckRequest)(nil), "events.AckRequest")
 }
 
 func init() { proto.RegisterFile("events/events.proto", fileDescriptor_8ec31f2d2a3db598) }
 
 var fileDescriptor_8ec31f2d2a3db598 = []byte{
-	// 562 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x54, 0x4d, 0x6f, 0xd3, 0x40,
-	0x10, 0x95, 0xed, 0x38, 0x4e, 0x27, 0x1f, 0x85, 0x6d, 0x28, 0x26, 0x20, 0x11, 0x19, 0x90, 0x72,
-	0x40, 0x09, 0xa4, 0x7c, 0xa9, 0xbd, 0x50, 0x50, 0x6e, 0x20, 0xc1, 0xf6, 0x50, 0x89, 0x4b, 0xb4,
-	0x71, 0xb6, 0xed, 0xca, 0x71, 0xd6, 0x78, 0xd7, 0x81, 0xfc, 0x26, 0x7e, 0x12, 0xe2, 0xbf, 0x20,
-	0xef, 0xae, 0x13, 0x3b, 0x85, 0x8a, 0x03, 0x97, 0xc4, 0x6f, 0xc6, 0x33, 0x9e, 0xf7, 0xde, 0xec,
-	0xc2, 0x01, 0x5d, 0xd1, 0xa5, 0x14, 0x23, 0xfd, 0x37, 0x4c, 0x52, 0x2e, 0x39, 0xaa, 0x6b, 0x14,
-	0xfc, 0xb2, 0xa0, 0xf3, 0x29, 0x9b, 0x2d, 0x98, 0xb8, 0xc2, 0xf4, 0x6b, 0x46, 0x85, 0x44, 0x5d,
-	0x70, 0x25, 0x4f, 0x58, 0xe8, 0x5b, 0x7d, 0x6b, 0xb0, 0x87, 0x35, 0x40, 0x6f, 0xa1, 0x11, 0x53,
-	0x49, 0xe6, 0x44, 0x12, 0xdf, 0xee, 0x3b, 0x83, 0xe6, 0xf8, 0xf1, 0xd0, 0x74, 0xac, 0xd6, 0x0f,
-	0x3f, 0x9a, 0xd7, 0x26, 0x4b, 0x99, 0xae, 0xf1, 0xa6, 0x0a, 0xf9, 0xe0, 0x25, 0x64, 0xbd, 0xe0,
-	0x64, 0xee, 0x3b, 0x7d, 0x6b, 0xd0, 0xc2, 0x05, 0x44, 0x0f, 0x60, 0x4f, 0xb2, 0x98, 0x0a, 0x49,
-	0xe2, 0xc4, 0xaf, 0xf5, 0xad, 0x81, 0x83, 0xb7, 0x81, 0xde, 0x09, 0xb4, 0x2b, 0x2d, 0xd1, 0x2d,
-	0x70, 0x22, 0xba, 0x36, 0xe3, 0xe5, 0x8f, 0xf9, 0xc8, 0x2b, 0xb2, 0xc8, 0xa8, 0x6f, 0xeb, 0x91,
-	0x15, 0x38, 0xb6, 0xdf, 0x58, 0xc1, 0x6d, 0xd8, 0xdf, 0x8c, 0x27, 0x12, 0xbe, 0x14, 0x34, 0xf8,
-	0x61, 0x41, 0xe7, 0x3d, 0x5f, 0x8a, 0x2c, 0xa6, 0x25, 0xca, 0x97, 0x29, 0xcf, 0x92, 0x82, 0xb2,
-	0x02, 0x5b, 0x21, 0xec, 0xb2, 0x10, 0x87, 0x50, 0xe7, 0x17, 0x17, 0x82, 0x4a, 0xc5, 0xc2, 0xc1,
-	0x06, 0xa1, 0x7b, 0xd0, 0x20, 0x99, 0xe4, 0x53, 0x12, 0x46, 0x8a, 0x43, 0x03, 0x7b, 0x39, 0x3e,
-	0x0d, 0x23, 0x95, 0x0a, 0xa3, 0xe9, 0x37, 0xc2, 0xa4, 0xef, 0xaa, 0x22, 0x8f, 0x84, 0xd1, 0x39,
-	0x61, 0x12, 0x3d, 0x84, 0x66, 0x4a, 0x65, 0xba, 0x9e, 0x2e, 0x58, 0xcc, 0xa4, 0x5f, 0x57, 0x59,
-	0x50, 0xa1, 0x0f, 0x79, 0x24, 0xf8, 0x69, 0x81, 0x3b, 0xc9, 0x75, 0x46, 0x1d, 0xb0, 0xd9, 0xdc,
-	0x4c, 0x68, 0xb3, 0xf9, 0x5f, 0xc6, 0x7b, 0x5d, 0xf2, 0xc9, 0x51, 0x3e, 0xdd, 0x2f, 0x7c, 0x52,
-	0x6d, 0xfe, 0xc5, 0x9e, 0xda, 0x0d, 0xf6, 0xb8, 0xff, 0xd5, 0x9e, 0xcf, 0xd0, 0xc4, 0x94, 0xcc,
-	0x6f, 0x5e, 0xbd, 0x2e, 0xb8, 0x5a, 0x9d, 0xbc, 0xbc, 0x86, 0x35, 0xd8, 0xf1, 0xa1, 0x56, 0xf8,
-	0x10, 0xbc, 0x84, 0x96, 0x6e, 0xa9, 0xed, 0x46, 0x4f, 0xc0, 0xec, 0xba, 0x6f, 0x29, 0x39, 0xda,
-	0x15, 0x39, 0x70, 0x71, 0x10, 0x26, 0xd0, 0x3a, 0x4f, 0x99, 0xdc, 0xac, 0xc4, 0x23, 0x70, 0x55,
-	0x46, 0x8d, 0x72, 0xad, 0x4a, 0xe7, 0x72, 0xaa, 0x52, 0x2e, 0xd4, 0x5c, 0x0e, 0xce, 0x1f, 0x83,
-	0x7d, 0x68, 0x9b, 0x36, 0x66, 0xdb, 0x5e, 0x01, 0x9c, 0x86, 0x51, 0xd1, 0x75, 0xd7, 0x43, 0x1f,
-	0x3c, 0x91, 0x85, 0x21, 0x15, 0x42, 0x35, 0x69, 0xe0, 0x02, 0x8e, 0xbf, 0x43, 0xfd, 0x4c, 0xa6,
-	0x94, 0xc4, 0xe8, 0x18, 0x3c, 0xb3, 0xc2, 0xe8, 0xf0, 0xcf, 0x47, 0xae, 0x77, 0xf7, 0x5a, 0xdc,
-	0x90, 0x1f, 0x83, 0x67, 0x56, 0x7d, 0x5b, 0x5b, 0xdd, 0xfd, 0x5e, 0x95, 0xd9, 0x33, 0x6b, 0x9c,
-	0x80, 0x7b, 0x26, 0x79, 0x4a, 0xd1, 0x73, 0xa8, 0xe5, 0x4a, 0xa2, 0x83, 0xe2, 0x8d, 0x92, 0x55,
-	0xbd, 0x6e, 0x35, 0x68, 0xbe, 0xf7, 0x02, 0x5c, 0x45, 0x1f, 0x6d, 0xd2, 0x65, 0x51, 0x7b, 0x77,
-	0x76, 0xa2, 0xba, 0xea, 0xdd, 0xf0, 0xcb, 0xd3, 0x4b, 0x26, 0xaf, 0xb2, 0xd9, 0x30, 0xe4, 0xf1,
-	0x28, 0x66, 0x61, 0xca, 0xcd, 0xef, 0xea, 0x68, 0xa4, 0xee, 0x2b, 0x7d, 0x79, 0x9d, 0xe8, 0xea,
-	0x59, 0x5d, 0xc5, 0x8e, 0x7e, 0x07, 0x00, 0x00, 0xff, 0xff, 0xd2, 0x87, 0x7d, 0x33, 0xda, 0x04,
-	0x00, 0x00,
+	// 598 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x54, 0x41, 0x6f, 0xd3, 0x4c,
+	0x10, 0x95, 0xed, 0xd8, 0x4e, 0xa7, 0x4d, 0xda, 0x6f, 0xdb, 0xaf, 0x18, 0x83, 0x44, 0x64, 0x40,
+	0xca, 0x01, 0x25, 0x90, 0x52, 0x8a, 0xda, 0x0b, 0x05, 0x72, 0x03, 0x09, 0xb6, 0x87, 0x4a, 0x5c,
+	0xa2, 0x8d, 0xb3, 0x6d, 0x57, 0xb6, 0xb3, 0xc6, 0x5e, 0x07, 0xf2, 0x93, 0x10, 0x3f, 0x09, 0xf1,
+	0x5f, 0x90, 0x77, 0xd7, 0x49, 0x9c, 0x42, 0xc4, 0x81, 0x4b, 0xe2, 0x37, 0xbb, 0x6f, 0x3c, 0xf3,
+	0xde, 0x8c, 0x61, 0x9f, 0xce, 0xe8, 0x54, 0xe4, 0x7d, 0xf5, 0xd7, 0x4b, 0x33, 0x2e, 0x38, 0x72,
+	0x14, 0x0a, 0x7e, 0x1a, 0xd0, 0xfe, 0x50, 0x8c, 0x63, 0x96, 0xdf, 0x60, 0xfa, 0xb9, 0xa0, 0xb9,
+	0x40, 0x07, 0x60, 0x0b, 0x9e, 0xb2, 0xd0, 0x33, 0x3a, 0x46, 0x77, 0x0b, 0x2b, 0x80, 0x5e, 0x41,
+	0x33, 0xa1, 0x82, 0x4c, 0x88, 0x20, 0x9e, 0xd9, 0xb1, 0xba, 0xdb, 0x83, 0x47, 0x3d, 0x9d, 0xb1,
+	0xce, 0xef, 0xbd, 0xd7, 0xd7, 0x86, 0x53, 0x91, 0xcd, 0xf1, 0x82, 0x85, 0x3c, 0x70, 0x53, 0x32,
+	0x8f, 0x39, 0x99, 0x78, 0x56, 0xc7, 0xe8, 0xee, 0xe0, 0x0a, 0xa2, 0xfb, 0xb0, 0x25, 0x58, 0x42,
+	0x73, 0x41, 0x92, 0xd4, 0x6b, 0x74, 0x8c, 0xae, 0x85, 0x97, 0x01, 0xff, 0x0c, 0x5a, 0xb5, 0x94,
+	0x68, 0x0f, 0xac, 0x88, 0xce, 0x75, 0x79, 0xe5, 0x63, 0x59, 0xf2, 0x8c, 0xc4, 0x05, 0xf5, 0x4c,
+	0x55, 0xb2, 0x04, 0xa7, 0xe6, 0x4b, 0x23, 0xf8, 0x0f, 0x76, 0x17, 0xe5, 0xe5, 0x29, 0x9f, 0xe6,
+	0x34, 0xf8, 0x6e, 0x40, 0xfb, 0x0d, 0x9f, 0xe6, 0x45, 0x42, 0x57, 0x5a, 0xbe, 0xce, 0x78, 0x91,
+	0x56, 0x2d, 0x4b, 0xb0, 0x14, 0xc2, 0x5c, 0x15, 0xe2, 0x10, 0x1c, 0x7e, 0x75, 0x95, 0x53, 0x21,
+	0xbb, 0xb0, 0xb0, 0x46, 0xe8, 0x2e, 0x34, 0x49, 0x21, 0xf8, 0x88, 0x84, 0x91, 0xec, 0xa1, 0x89,
+	0xdd, 0x12, 0x9f, 0x87, 0x91, 0x3c, 0x0a, 0xa3, 0xd1, 0x17, 0xc2, 0x84, 0x67, 0x4b, 0x92, 0x4b,
+	0xc2, 0xe8, 0x92, 0x30, 0x81, 0x1e, 0xc0, 0x76, 0x46, 0x45, 0x36, 0x1f, 0xc5, 0x2c, 0x61, 0xc2,
+	0x73, 0xe4, 0x29, 0xc8, 0xd0, 0xbb, 0x32, 0x12, 0xfc, 0x30, 0xc0, 0x1e, 0x96, 0x3a, 0xa3, 0x36,
+	0x98, 0x6c, 0xa2, 0x2b, 0x34, 0xd9, 0xe4, 0x0f, 0xe5, 0x9d, 0xac, 0xf8, 0x64, 0x49, 0x9f, 0xee,
+	0x55, 0x3e, 0xc9, 0x34, 0x7f, 0x63, 0x4f, 0x63, 0x83, 0x3d, 0xf6, 0x3f, 0xb5, 0xe7, 0x23, 0x6c,
+	0x63, 0x4a, 0x26, 0x9b, 0x47, 0xef, 0x00, 0x6c, 0xa5, 0x4e, 0x49, 0x6f, 0x60, 0x05, 0xd6, 0x7c,
+	0x68, 0x54, 0x3e, 0x04, 0xc7, 0xb0, 0xa3, 0x52, 0x2a, 0xbb, 0xd1, 0x63, 0xd0, 0xb3, 0xee, 0x19,
+	0x52, 0x8e, 0x56, 0x4d, 0x0e, 0x5c, 0x2d, 0xc2, 0x10, 0x76, 0x2e, 0x33, 0x26, 0x16, 0x23, 0xf1,
+	0x10, 0x6c, 0x79, 0x22, 0x4b, 0xb9, 0xc5, 0x52, 0x67, 0x65, 0xab, 0x42, 0xc4, 0xb2, 0x2e, 0x0b,
+	0x97, 0x8f, 0xc1, 0x2e, 0xb4, 0x74, 0x1a, 0x3d, 0x6d, 0xc7, 0xd0, 0x7a, 0x4b, 0x63, 0x2a, 0xe8,
+	0xe6, 0x1e, 0x95, 0xb9, 0x66, 0x65, 0x6e, 0xb0, 0x07, 0xed, 0x8a, 0xa6, 0x13, 0xbd, 0x00, 0x38,
+	0x0f, 0xa3, 0x2a, 0xcb, 0xfa, 0x30, 0x78, 0xe0, 0xe6, 0x45, 0x18, 0xd2, 0x3c, 0x97, 0x49, 0x9a,
+	0xb8, 0x82, 0x83, 0xaf, 0xe0, 0x5c, 0x88, 0x8c, 0x92, 0x04, 0x9d, 0x82, 0xab, 0x77, 0x01, 0x1d,
+	0xfe, 0x7e, 0x77, 0xfd, 0x3b, 0xb7, 0xe2, 0x5a, 0xc5, 0x01, 0xb8, 0x7a, 0x67, 0x96, 0xdc, 0xfa,
+	0x12, 0xf9, 0x75, 0x89, 0x9e, 0x1a, 0x83, 0x6f, 0x06, 0xd8, 0x17, 0x82, 0x67, 0x14, 0x3d, 0x83,
+	0x46, 0xe9, 0x09, 0xda, 0xaf, 0xae, 0xac, 0x98, 0xee, 0x1f, 0xd4, 0x83, 0xfa, 0x85, 0xcf, 0xc1,
+	0x96, 0x42, 0xa2, 0xc5, 0xf1, 0xaa, 0x3d, 0xfe, 0xff, 0x6b, 0x51, 0xcd, 0x3a, 0x01, 0x47, 0xc9,
+	0x86, 0x16, 0x17, 0x6a, 0xea, 0xfb, 0x87, 0xeb, 0x61, 0x45, 0x7c, 0xdd, 0xfb, 0xf4, 0xe4, 0x9a,
+	0x89, 0x9b, 0x62, 0xdc, 0x0b, 0x79, 0xd2, 0x4f, 0x58, 0x98, 0x71, 0xfd, 0x3b, 0x3b, 0xea, 0xcb,
+	0x4f, 0xa6, 0xfa, 0x7e, 0x9e, 0x29, 0xfa, 0xd8, 0x91, 0xb1, 0xa3, 0x5f, 0x03, 0x00, 0x46, 0xdb,
+	0xfc, 0xf7, 0x5d, 0x05, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -681,6 +763,7 @@ var _Stream_serviceDesc = grpc.ServiceDesc{
 type StoreClient interface {
 	Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error)
 	Write(ctx context.Context, in *WriteRequest, opts ...grpc.CallOption) (*WriteResponse, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
 }
 
 type storeClient struct {
@@ -709,10 +792,20 @@ func (c *storeClient) Write(ctx context.Context, in *WriteRequest, opts ...grpc.
 	return out, nil
 }
 
+func (c *storeClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/events.Store/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StoreServer is the server API for Store service.
 type StoreServer interface {
 	Read(context.Context, *ReadRequest) (*ReadResponse, error)
 	Write(context.Context, *WriteRequest) (*WriteResponse, error)
+	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
 }
 
 func RegisterStoreServer(s *grpc.Server, srv StoreServer) {
@@ -755,6 +848,24 @@ func _Store_Write_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Store_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoreServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/events.Store/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoreServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Store_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "events.Store",
 	HandlerType: (*StoreServer)(nil),
@@ -767,6 +878,10 @@ var _Store_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Write",
 			Handler:    _Store_Write_Handler,
 		},
+		{
+			MethodName: "Delete",
+			Handler:    _Store_Delete_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "events/events.proto",
@@ -195,6 +195,7 @@ func NewStoreEndpoints() []*api.Endpoint {
 type StoreService interface {
 	Read(ctx context.Context, in *ReadRequest, opts ...client.CallOption) (*ReadResponse, error)
 	Write(ctx context.Context, in *WriteRequest, opts ...client.CallOption) (*WriteResponse, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...client.CallOption) (*DeleteResponse, error)
 }
 
 type storeService struct {
@@ -229,17 +230,29 @@ func (c *storeService) Write(ctx context.Context, in *WriteRequest, opts ...clie
 	return out, nil
 }
 
+func (c *storeService) Delete(ctx context.Context, in *DeleteRequest, opts ...client.CallOption) (*DeleteResponse, error) {
+	req := c.c.NewRequest(c.name, "Store.Delete", in)
+	out := new(DeleteResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Store service
 
 type StoreHandler interface {
 	Read(context.Context, *ReadRequest, *ReadResponse) error
 	Write(context.Context, *WriteRequest, *WriteResponse) error
+	Delete(context.Context, *DeleteRequest, *DeleteResponse) error
 }
 
 func RegisterStoreHandler(s server.Server, hdlr StoreHandler, opts ...server.HandlerOption) error {
 	type store interface {
 		Read(ctx context.Context, in *ReadRequest, out *ReadResponse) error
 		Write(ctx context.Context, in *WriteRequest, out *WriteResponse) error
+		Delete(ctx context.Context, in *DeleteRequest, out *DeleteResponse) error
 	}
 	type Store struct {
 		store
@@ -259,3 +272,7 @@ func (h *storeHandler) Read(ctx context.Context, in *ReadRequest, out *ReadRespo
 func (h *storeHandler) Write(ctx context.Context, in *WriteRequest, out *WriteResponse) error {
 	return h.StoreHandler.Write(ctx, in, out)
 }
+
+func (h *storeHandler) Delete(ctx context.Context, in *DeleteRequest, out *DeleteResponse) error {
+	return h.StoreHandler.Delete(ctx, in, out)
+}
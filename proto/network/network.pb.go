@@ -475,6 +475,238 @@ func (m *ServicesResponse) GetServices() []string {
 	return nil
 }
 
+type LinksRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LinksRequest) Reset()         { *m = LinksRequest{} }
+func (m *LinksRequest) String() string { return proto.CompactTextString(m) }
+func (*LinksRequest) ProtoMessage()    {}
+func (*LinksRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ad937ae012c472, []int{11}
+}
+
+func (m *LinksRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LinksRequest.Unmarshal(m, b)
+}
+func (m *LinksRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LinksRequest.Marshal(b, m, deterministic)
+}
+func (m *LinksRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LinksRequest.Merge(m, src)
+}
+func (m *LinksRequest) XXX_Size() int {
+	return xxx_messageInfo_LinksRequest.Size(m)
+}
+func (m *LinksRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LinksRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LinksRequest proto.InternalMessageInfo
+
+type LinksResponse struct {
+	Links                []*Link  `protobuf:"bytes,1,rep,name=links,proto3" json:"links,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LinksResponse) Reset()         { *m = LinksResponse{} }
+func (m *LinksResponse) String() string { return proto.CompactTextString(m) }
+func (*LinksResponse) ProtoMessage()    {}
+func (*LinksResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ad937ae012c472, []int{12}
+}
+
+func (m *LinksResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LinksResponse.Unmarshal(m, b)
+}
+func (m *LinksResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LinksResponse.Marshal(b, m, deterministic)
+}
+func (m *LinksResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LinksResponse.Merge(m, src)
+}
+func (m *LinksResponse) XXX_Size() int {
+	return xxx_messageInfo_LinksResponse.Size(m)
+}
+func (m *LinksResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LinksResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LinksResponse proto.InternalMessageInfo
+
+func (m *LinksResponse) GetLinks() []*Link {
+	if m != nil {
+		return m.Links
+	}
+	return nil
+}
+
+// Link is a tunnel link of the node
+type Link struct {
+	// link id, the address of the remote node
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// connected, closed or error
+	State string `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	// whether its a loopback link
+	Loopback bool `protobuf:"varint,3,opt,name=loopback,proto3" json:"loopback,omitempty"`
+	// roundtrip time in nanoseconds
+	Length int64 `protobuf:"varint,4,opt,name=length,proto3" json:"length,omitempty"`
+	// transfer rate in bits per second
+	Rate float64 `protobuf:"fixed64,5,opt,name=rate,proto3" json:"rate,omitempty"`
+	// number of messages waiting to be sent or received
+	Delay            int64  `protobuf:"varint,6,opt,name=delay,proto3" json:"delay,omitempty"`
+	BytesSent        uint64 `protobuf:"varint,7,opt,name=bytes_sent,json=bytesSent,proto3" json:"bytes_sent,omitempty"`
+	BytesReceived    uint64 `protobuf:"varint,8,opt,name=bytes_received,json=bytesReceived,proto3" json:"bytes_received,omitempty"`
+	MessagesSent     uint64 `protobuf:"varint,9,opt,name=messages_sent,json=messagesSent,proto3" json:"messages_sent,omitempty"`
+	MessagesReceived uint64 `protobuf:"varint,10,opt,name=messages_received,json=messagesReceived,proto3" json:"messages_received,omitempty"`
+	Errors           uint64 `protobuf:"varint,11,opt,name=errors,proto3" json:"errors,omitempty"`
+	// number of session keys established
+	KeyRotations uint64 `protobuf:"varint,12,opt,name=key_rotations,json=keyRotations,proto3" json:"key_rotations,omitempty"`
+	// unix timestamp the current session key was established
+	KeyRotated int64 `protobuf:"varint,13,opt,name=key_rotated,json=keyRotated,proto3" json:"key_rotated,omitempty"`
+	// whether the link is to a relay
+	Relay bool `protobuf:"varint,14,opt,name=relay,proto3" json:"relay,omitempty"`
+	// the address the remote node sees the link connecting from
+	Observed             string   `protobuf:"bytes,15,opt,name=observed,proto3" json:"observed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Link) Reset()         { *m = Link{} }
+func (m *Link) String() string { return proto.CompactTextString(m) }
+func (*Link) ProtoMessage()    {}
+func (*Link) Descriptor() ([]byte, []int) {
+	return fileDescriptor_96ad937ae012c472, []int{13}
+}
+
+func (m *Link) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Link.Unmarshal(m, b)
+}
+func (m *Link) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Link.Marshal(b, m, deterministic)
+}
+func (m *Link) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Link.Merge(m, src)
+}
+func (m *Link) XXX_Size() int {
+	return xxx_messageInfo_Link.Size(m)
+}
+func (m *Link) XXX_DiscardUnknown() {
+	xxx_messageInfo_Link.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Link proto.InternalMessageInfo
+
+func (m *Link) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Link) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *Link) GetLoopback() bool {
+	if m != nil {
+		return m.Loopback
+	}
+	return false
+}
+
+func (m *Link) GetLength() int64 {
+	if m != nil {
+		return m.Length
+	}
+	return 0
+}
+
+func (m *Link) GetRate() float64 {
+	if m != nil {
+		return m.Rate
+	}
+	return 0
+}
+
+func (m *Link) GetDelay() int64 {
+	if m != nil {
+		return m.Delay
+	}
+	return 0
+}
+
+func (m *Link) GetBytesSent() uint64 {
+	if m != nil {
+		return m.BytesSent
+	}
+	return 0
+}
+
+func (m *Link) GetBytesReceived() uint64 {
+	if m != nil {
+		return m.BytesReceived
+	}
+	return 0
+}
+
+func (m *Link) GetMessagesSent() uint64 {
+	if m != nil {
+		return m.MessagesSent
+	}
+	return 0
+}
+
+func (m *Link) GetMessagesReceived() uint64 {
+	if m != nil {
+		return m.MessagesReceived
+	}
+	return 0
+}
+
+func (m *Link) GetErrors() uint64 {
+	if m != nil {
+		return m.Errors
+	}
+	return 0
+}
+
+func (m *Link) GetKeyRotations() uint64 {
+	if m != nil {
+		return m.KeyRotations
+	}
+	return 0
+}
+
+func (m *Link) GetKeyRotated() int64 {
+	if m != nil {
+		return m.KeyRotated
+	}
+	return 0
+}
+
+func (m *Link) GetRelay() bool {
+	if m != nil {
+		return m.Relay
+	}
+	return false
+}
+
+func (m *Link) GetObserved() string {
+	if m != nil {
+		return m.Observed
+	}
+	return ""
+}
+
 type StatusRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -485,7 +717,7 @@ func (m *StatusRequest) Reset()         { *m = StatusRequest{} }
 func (m *StatusRequest) String() string { return proto.CompactTextString(m) }
 func (*StatusRequest) ProtoMessage()    {}
 func (*StatusRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_96ad937ae012c472, []int{11}
+	return fileDescriptor_96ad937ae012c472, []int{14}
 }
 
 func (m *StatusRequest) XXX_Unmarshal(b []byte) error {
@@ -517,7 +749,7 @@ func (m *StatusResponse) Reset()         { *m = StatusResponse{} }
 func (m *StatusResponse) String() string { return proto.CompactTextString(m) }
 func (*StatusResponse) ProtoMessage()    {}
 func (*StatusResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_96ad937ae012c472, []int{12}
+	return fileDescriptor_96ad937ae012c472, []int{15}
 }
 
 func (m *StatusResponse) XXX_Unmarshal(b []byte) error {
@@ -558,7 +790,7 @@ func (m *Error) Reset()         { *m = Error{} }
 func (m *Error) String() string { return proto.CompactTextString(m) }
 func (*Error) ProtoMessage()    {}
 func (*Error) Descriptor() ([]byte, []int) {
-	return fileDescriptor_96ad937ae012c472, []int{13}
+	return fileDescriptor_96ad937ae012c472, []int{16}
 }
 
 func (m *Error) XXX_Unmarshal(b []byte) error {
@@ -605,7 +837,7 @@ func (m *Status) Reset()         { *m = Status{} }
 func (m *Status) String() string { return proto.CompactTextString(m) }
 func (*Status) ProtoMessage()    {}
 func (*Status) Descriptor() ([]byte, []int) {
-	return fileDescriptor_96ad937ae012c472, []int{14}
+	return fileDescriptor_96ad937ae012c472, []int{17}
 }
 
 func (m *Status) XXX_Unmarshal(b []byte) error {
@@ -654,7 +886,7 @@ func (m *Node) Reset()         { *m = Node{} }
 func (m *Node) String() string { return proto.CompactTextString(m) }
 func (*Node) ProtoMessage()    {}
 func (*Node) Descriptor() ([]byte, []int) {
-	return fileDescriptor_96ad937ae012c472, []int{15}
+	return fileDescriptor_96ad937ae012c472, []int{18}
 }
 
 func (m *Node) XXX_Unmarshal(b []byte) error {
@@ -723,7 +955,7 @@ func (m *Connect) Reset()         { *m = Connect{} }
 func (m *Connect) String() string { return proto.CompactTextString(m) }
 func (*Connect) ProtoMessage()    {}
 func (*Connect) Descriptor() ([]byte, []int) {
-	return fileDescriptor_96ad937ae012c472, []int{16}
+	return fileDescriptor_96ad937ae012c472, []int{19}
 }
 
 func (m *Connect) XXX_Unmarshal(b []byte) error {
@@ -764,7 +996,7 @@ func (m *Close) Reset()         { *m = Close{} }
 func (m *Close) String() string { return proto.CompactTextString(m) }
 func (*Close) ProtoMessage()    {}
 func (*Close) Descriptor() ([]byte, []int) {
-	return fileDescriptor_96ad937ae012c472, []int{17}
+	return fileDescriptor_96ad937ae012c472, []int{20}
 }
 
 func (m *Close) XXX_Unmarshal(b []byte) error {
@@ -807,7 +1039,7 @@ func (m *Peer) Reset()         { *m = Peer{} }
 func (m *Peer) String() string { return proto.CompactTextString(m) }
 func (*Peer) ProtoMessage()    {}
 func (*Peer) Descriptor() ([]byte, []int) {
-	return fileDescriptor_96ad937ae012c472, []int{18}
+	return fileDescriptor_96ad937ae012c472, []int{21}
 }
 
 func (m *Peer) XXX_Unmarshal(b []byte) error {
@@ -857,7 +1089,7 @@ func (m *Sync) Reset()         { *m = Sync{} }
 func (m *Sync) String() string { return proto.CompactTextString(m) }
 func (*Sync) ProtoMessage()    {}
 func (*Sync) Descriptor() ([]byte, []int) {
-	return fileDescriptor_96ad937ae012c472, []int{19}
+	return fileDescriptor_96ad937ae012c472, []int{22}
 }
 
 func (m *Sync) XXX_Unmarshal(b []byte) error {
@@ -904,6 +1136,9 @@ func init() {
 	proto.RegisterType((*RoutesResponse)(nil), "network.RoutesResponse")
 	proto.RegisterType((*ServicesRequest)(nil), "network.ServicesRequest")
 	proto.RegisterType((*ServicesResponse)(nil), "network.ServicesResponse")
+	proto.RegisterType((*LinksRequest)(nil), "network.LinksRequest")
+	proto.RegisterType((*LinksResponse)(nil), "network.LinksResponse")
+	proto.RegisterType((*Link)(nil), "network.Link")
 	proto.RegisterType((*StatusRequest)(nil), "network.StatusRequest")
 	proto.RegisterType((*StatusResponse)(nil), "network.StatusResponse")
 	proto.RegisterType((*Error)(nil), "network.Error")
@@ -919,49 +1154,64 @@ func init() {
 func init() { proto.RegisterFile("network/network.proto", fileDescriptor_96ad937ae012c472) }
 
 var fileDescriptor_96ad937ae012c472 = []byte{
-	// 673 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x55, 0x51, 0x4f, 0xd4, 0x40,
-	0x10, 0xa6, 0xbd, 0xf6, 0x0e, 0x46, 0x7a, 0xe0, 0x2a, 0x50, 0xeb, 0x0b, 0xae, 0x18, 0x89, 0x31,
-	0x6d, 0x04, 0x09, 0x28, 0x89, 0x89, 0x12, 0xe2, 0x93, 0x04, 0xcb, 0x9b, 0x6f, 0xa5, 0xdd, 0xc0,
-	0x05, 0xae, 0x7b, 0x6c, 0xb7, 0x90, 0xfb, 0x05, 0xfe, 0x56, 0xff, 0x82, 0x4f, 0x66, 0x77, 0xa7,
-	0xbd, 0x96, 0xea, 0xc9, 0xcb, 0xed, 0xcd, 0x7c, 0x33, 0xb3, 0xbb, 0xdf, 0x7c, 0xb3, 0x85, 0xb5,
-	0x9c, 0xc9, 0x3b, 0x2e, 0xae, 0x22, 0x5c, 0xc3, 0x89, 0xe0, 0x92, 0x93, 0x01, 0x9a, 0xc1, 0x13,
-	0xc1, 0x4b, 0xc9, 0x44, 0x64, 0x16, 0x83, 0xd2, 0x9f, 0x16, 0xb8, 0xdf, 0x4b, 0x26, 0xa6, 0xc4,
-	0x87, 0x41, 0xc1, 0xc4, 0xed, 0x28, 0x65, 0xbe, 0xb5, 0x69, 0x6d, 0x2f, 0xc5, 0x95, 0xa9, 0x90,
-	0x24, 0xcb, 0x04, 0x2b, 0x0a, 0xdf, 0x36, 0x08, 0x9a, 0x0a, 0xb9, 0x48, 0x24, 0xbb, 0x4b, 0xa6,
-	0x7e, 0xcf, 0x20, 0x68, 0x92, 0x75, 0xe8, 0x9b, 0x7d, 0x7c, 0x47, 0x03, 0x68, 0xa9, 0x0c, 0x3c,
-	0x8f, 0xef, 0x9a, 0x0c, 0x34, 0xe9, 0x1e, 0x0c, 0x8f, 0x78, 0x9e, 0xb3, 0x54, 0xc6, 0xec, 0xa6,
-	0x64, 0x85, 0x24, 0x2f, 0xc1, 0xcd, 0x79, 0xc6, 0x0a, 0xdf, 0xda, 0xec, 0x6d, 0x3f, 0xda, 0xf1,
-	0xc2, 0xea, 0x62, 0x27, 0x3c, 0x63, 0xb1, 0xc1, 0xe8, 0x63, 0x58, 0xa9, 0xd3, 0x8a, 0x09, 0xcf,
-	0x0b, 0x46, 0xb7, 0x60, 0x59, 0x45, 0x14, 0x55, 0x9d, 0xa7, 0xe0, 0x66, 0x6c, 0x22, 0x2f, 0xf5,
-	0xbd, 0xbc, 0xd8, 0x18, 0xf4, 0x3d, 0x78, 0x18, 0x65, 0xd2, 0x1e, 0xb6, 0xdd, 0x16, 0x2c, 0x7f,
-	0x15, 0xc9, 0xe4, 0x72, 0x7e, 0xed, 0x1d, 0xf0, 0x30, 0x0a, 0x6b, 0xbf, 0x00, 0x47, 0x70, 0x2e,
-	0x75, 0x54, 0xb3, 0xf4, 0x29, 0x63, 0x22, 0xd6, 0x10, 0xdd, 0x03, 0x2f, 0x56, 0x1c, 0xd5, 0xc7,
-	0xde, 0x02, 0xf7, 0x46, 0x75, 0x06, 0x93, 0x86, 0x75, 0x92, 0xee, 0x57, 0x6c, 0x40, 0xba, 0x0f,
-	0xc3, 0x2a, 0x0d, 0xf7, 0x7a, 0x85, 0xd4, 0xcf, 0x2e, 0x82, 0x1d, 0xd7, 0x71, 0xd8, 0x09, 0x4d,
-	0xdc, 0x99, 0x69, 0x70, 0xb5, 0x23, 0x0d, 0x61, 0x75, 0xe6, 0xc2, 0x6a, 0x01, 0x2c, 0xa2, 0x0e,
-	0x4c, 0xbd, 0xa5, 0xb8, 0xb6, 0xe9, 0x0a, 0x78, 0x67, 0x32, 0x91, 0x65, 0x5d, 0xe0, 0x03, 0x0c,
-	0x2b, 0x07, 0xa6, 0xbf, 0x86, 0x7e, 0xa1, 0x3d, 0x78, 0x8b, 0x95, 0xfa, 0x16, 0x18, 0x88, 0x30,
-	0x8d, 0xc0, 0x3d, 0x16, 0x82, 0x0b, 0xc5, 0x68, 0xca, 0xcb, 0x5c, 0x56, 0x8c, 0x6a, 0x83, 0xac,
-	0x42, 0x6f, 0x5c, 0x5c, 0xa0, 0xfe, 0xd4, 0x5f, 0x1a, 0x42, 0xdf, 0x94, 0x50, 0x44, 0x31, 0x95,
-	0xda, 0x21, 0x4a, 0x17, 0x8c, 0x0d, 0x48, 0x7f, 0x59, 0xe0, 0xa8, 0x4e, 0x92, 0x21, 0xd8, 0xa3,
-	0x0c, 0x35, 0x6e, 0x8f, 0xb2, 0xf9, 0xf2, 0xae, 0xc4, 0xda, 0x6b, 0x89, 0x95, 0xec, 0xc3, 0xe2,
-	0x98, 0xc9, 0x24, 0x4b, 0x64, 0xe2, 0x3b, 0x9a, 0xe5, 0xe7, 0x2d, 0xb9, 0x84, 0xdf, 0x10, 0x3d,
-	0xce, 0xa5, 0x98, 0xc6, 0x75, 0x70, 0x83, 0x0f, 0x77, 0x2e, 0x1f, 0xc1, 0x21, 0x78, 0xad, 0x1a,
-	0x8a, 0x81, 0x2b, 0x36, 0xc5, 0x73, 0xab, 0xbf, 0x8a, 0xa9, 0xdb, 0xe4, 0xba, 0x64, 0x78, 0x6c,
-	0x63, 0x7c, 0xb4, 0x0f, 0x2c, 0xfa, 0x16, 0x06, 0x38, 0x14, 0x4a, 0x79, 0x4a, 0xb9, 0x1d, 0xe5,
-	0x69, 0x51, 0x6b, 0x88, 0xbe, 0x01, 0xf7, 0xe8, 0x9a, 0x1b, 0x95, 0xfe, 0x2f, 0xf6, 0x04, 0x1c,
-	0xa5, 0xd9, 0x07, 0x84, 0xaa, 0x79, 0x9a, 0x30, 0x26, 0x14, 0xab, 0xbd, 0xae, 0xe8, 0x0d, 0x46,
-	0x4f, 0xc1, 0x39, 0x9b, 0xe6, 0xa9, 0xaa, 0xa7, 0x1c, 0xff, 0x18, 0x10, 0x05, 0x35, 0x74, 0x6d,
-	0xcf, 0xd1, 0xf5, 0xce, 0x6f, 0x1b, 0x06, 0x27, 0xd8, 0xa6, 0x4f, 0x33, 0x1e, 0x36, 0xea, 0x92,
-	0xed, 0x57, 0x26, 0xf0, 0xbb, 0x00, 0xbe, 0x23, 0x0b, 0xe4, 0x00, 0x5c, 0x3d, 0xc7, 0x64, 0xad,
-	0x0e, 0x6a, 0x4e, 0x7f, 0xb0, 0x7e, 0xdf, 0xdd, 0xcc, 0xd4, 0xaf, 0x4b, 0x23, 0xb3, 0xf9, 0x26,
-	0x35, 0x32, 0x5b, 0x8f, 0x10, 0x5d, 0x20, 0x87, 0xd0, 0x37, 0x03, 0x4d, 0x66, 0x31, 0xad, 0x87,
-	0x21, 0xd8, 0xe8, 0xf8, 0xeb, 0xe4, 0xcf, 0xb0, 0x58, 0x4d, 0x30, 0x99, 0x5d, 0xec, 0xde, 0x9c,
-	0x07, 0xcf, 0xfe, 0x82, 0x34, 0xf7, 0xc7, 0xb9, 0x5a, 0xbf, 0xaf, 0xcd, 0xce, 0xfe, 0xed, 0x61,
-	0xa7, 0x0b, 0x5f, 0xde, 0xfd, 0x88, 0x2e, 0x46, 0xf2, 0xb2, 0x3c, 0x0f, 0x53, 0x3e, 0x8e, 0xc6,
-	0xa3, 0x54, 0x70, 0xfc, 0xbd, 0xdd, 0x8d, 0xf4, 0x17, 0xa7, 0xfa, 0x3a, 0x1d, 0xe2, 0x7a, 0xde,
-	0xd7, 0xee, 0xdd, 0x3f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x1d, 0x05, 0xe7, 0x07, 0xbf, 0x06, 0x00,
+	// 913 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x56, 0xe1, 0x8e, 0xdb, 0x44,
+	0x10, 0x26, 0x89, 0x9d, 0xe4, 0xe6, 0xe2, 0xdc, 0xd5, 0xa2, 0xa9, 0x31, 0x42, 0x1c, 0xee, 0x55,
+	0x9c, 0x00, 0x25, 0x70, 0x6d, 0xd5, 0xc2, 0x49, 0x48, 0x50, 0x55, 0xfc, 0x81, 0x53, 0xf1, 0xfd,
+	0xe3, 0x4f, 0xe5, 0xc4, 0xa3, 0x5c, 0x94, 0xc4, 0x9b, 0xee, 0x6e, 0xae, 0xf2, 0x13, 0xf0, 0x3e,
+	0x88, 0x97, 0xe2, 0x2d, 0xd0, 0xce, 0x8e, 0x37, 0x4e, 0x42, 0xc3, 0xfd, 0x89, 0x3d, 0xf3, 0x7d,
+	0x33, 0xeb, 0x9d, 0x99, 0xfd, 0x36, 0xf0, 0xb0, 0x40, 0xfd, 0x5e, 0xc8, 0xf9, 0x88, 0x9f, 0xc3,
+	0x95, 0x14, 0x5a, 0x84, 0x1d, 0x36, 0xe3, 0x6f, 0xa7, 0x33, 0x7d, 0xbb, 0x1e, 0x0f, 0x27, 0x62,
+	0x39, 0x5a, 0xce, 0x26, 0x52, 0xf0, 0x2f, 0xd1, 0x46, 0x52, 0xac, 0x35, 0x4a, 0x7e, 0xd8, 0xd0,
+	0xe4, 0xcf, 0x06, 0xf8, 0xbf, 0xaf, 0x51, 0x96, 0x61, 0x04, 0x1d, 0x85, 0xf2, 0x6e, 0x36, 0xc1,
+	0xa8, 0x71, 0xd6, 0xb8, 0x38, 0x4a, 0x2b, 0xd3, 0x20, 0x59, 0x9e, 0x4b, 0x54, 0x2a, 0x6a, 0x5a,
+	0x84, 0x4d, 0x83, 0x4c, 0x33, 0x8d, 0xef, 0xb3, 0x32, 0x6a, 0x59, 0x84, 0xcd, 0x70, 0x00, 0x6d,
+	0xbb, 0x4e, 0xe4, 0x11, 0xc0, 0x96, 0x89, 0xe0, 0x8f, 0x8d, 0x7c, 0x1b, 0xc1, 0x66, 0xf2, 0x1c,
+	0xfa, 0xaf, 0x44, 0x51, 0xe0, 0x44, 0xa7, 0xf8, 0x6e, 0x8d, 0x4a, 0x87, 0x8f, 0xc1, 0x2f, 0x44,
+	0x8e, 0x2a, 0x6a, 0x9c, 0xb5, 0x2e, 0x8e, 0x2f, 0x83, 0x61, 0xb5, 0xeb, 0x6b, 0x91, 0x63, 0x6a,
+	0xb1, 0xe4, 0x01, 0x9c, 0xb8, 0x30, 0xb5, 0x12, 0x85, 0xc2, 0xe4, 0x1c, 0x7a, 0x86, 0xa1, 0xaa,
+	0x3c, 0x1f, 0x83, 0x9f, 0xe3, 0x4a, 0xdf, 0xd2, 0xbe, 0x82, 0xd4, 0x1a, 0xc9, 0x33, 0x08, 0x98,
+	0x65, 0xc3, 0xee, 0xb7, 0xdc, 0x39, 0xf4, 0x7e, 0x91, 0xd9, 0xea, 0xf6, 0x70, 0xee, 0x4b, 0x08,
+	0x98, 0xc5, 0xb9, 0xbf, 0x00, 0x4f, 0x0a, 0xa1, 0x89, 0x55, 0x4f, 0xfd, 0x06, 0x51, 0xa6, 0x04,
+	0x25, 0xcf, 0x21, 0x48, 0x4d, 0x8d, 0xdc, 0x67, 0x9f, 0x83, 0xff, 0xce, 0x74, 0x86, 0x83, 0xfa,
+	0x2e, 0x88, 0xfa, 0x95, 0x5a, 0x30, 0x79, 0x01, 0xfd, 0x2a, 0x8c, 0xd7, 0x7a, 0xc2, 0xa5, 0xdf,
+	0x6c, 0x84, 0x3b, 0x4e, 0x3c, 0xee, 0x04, 0x15, 0xee, 0xc6, 0x36, 0xb8, 0x5a, 0x31, 0x19, 0xc2,
+	0xe9, 0xc6, 0xc5, 0xd9, 0x62, 0xe8, 0xf2, 0x1c, 0xd8, 0x7c, 0x47, 0xa9, 0xb3, 0x93, 0x3e, 0xf4,
+	0x7e, 0x9d, 0x15, 0x73, 0x17, 0xff, 0x0c, 0x02, 0xb6, 0x37, 0x25, 0x5d, 0x18, 0xc7, 0x5e, 0x49,
+	0x0d, 0x2d, 0xb5, 0x58, 0xf2, 0x57, 0x0b, 0x3c, 0x63, 0x87, 0x7d, 0x68, 0xce, 0x72, 0x1e, 0xbe,
+	0xe6, 0x2c, 0x37, 0xb5, 0x55, 0x3a, 0xd3, 0xc8, 0x53, 0x67, 0x0d, 0xf3, 0x41, 0x0b, 0x21, 0x56,
+	0xe3, 0x6c, 0x32, 0xa7, 0xa1, 0xeb, 0xa6, 0xce, 0x36, 0x53, 0xb7, 0xc0, 0x62, 0xaa, 0x6f, 0x69,
+	0xea, 0x5a, 0x29, 0x5b, 0x61, 0x08, 0x9e, 0x34, 0x89, 0xcc, 0xc8, 0x35, 0x52, 0x7a, 0xb7, 0x9d,
+	0x5b, 0x64, 0x65, 0xd4, 0x26, 0xaa, 0x35, 0xc2, 0xcf, 0x00, 0xc6, 0xa5, 0x46, 0xf5, 0x56, 0x61,
+	0xa1, 0xa3, 0xce, 0x59, 0xe3, 0xc2, 0x4b, 0x8f, 0xc8, 0x73, 0x83, 0x85, 0x0e, 0x9f, 0x40, 0xdf,
+	0xc2, 0x12, 0x27, 0x38, 0xbb, 0xc3, 0x3c, 0xea, 0x12, 0x25, 0x20, 0x6f, 0xca, 0xce, 0xf0, 0x31,
+	0x04, 0x4b, 0x54, 0x2a, 0x9b, 0x56, 0x89, 0x8e, 0x88, 0xd5, 0xab, 0x9c, 0x94, 0xeb, 0x6b, 0x78,
+	0xe0, 0x48, 0x2e, 0x1d, 0x10, 0xf1, 0xb4, 0x02, 0x5c, 0xc6, 0x01, 0xb4, 0x51, 0x4a, 0x21, 0x55,
+	0x74, 0x4c, 0x0c, 0xb6, 0xcc, 0x4a, 0x73, 0x2c, 0xdf, 0x4a, 0xa1, 0x33, 0x3d, 0x13, 0x85, 0x8a,
+	0x7a, 0x76, 0xa5, 0x39, 0x96, 0x69, 0xe5, 0x0b, 0x3f, 0x87, 0x63, 0x47, 0xc2, 0x3c, 0x0a, 0x68,
+	0xc3, 0x50, 0x51, 0x90, 0x2a, 0x2d, 0xa9, 0x16, 0x7d, 0x2a, 0xa8, 0x35, 0x4c, 0xa5, 0xc5, 0xd8,
+	0x34, 0x1b, 0xf3, 0xe8, 0x84, 0x5a, 0xe0, 0xec, 0xe4, 0x04, 0x82, 0x1b, 0x9d, 0xe9, 0xb5, 0xeb,
+	0xfd, 0xf7, 0xd0, 0xaf, 0x1c, 0xdc, 0xfc, 0x2f, 0xa1, 0xad, 0xc8, 0xc3, 0x03, 0x7c, 0xe2, 0xba,
+	0xcf, 0x44, 0x86, 0x93, 0x11, 0xf8, 0xaf, 0xcd, 0x6e, 0xcc, 0x67, 0x4c, 0xc4, 0xba, 0xd0, 0xd5,
+	0x61, 0x22, 0x23, 0x3c, 0x85, 0xd6, 0x52, 0x4d, 0x79, 0x08, 0xcc, 0x6b, 0x32, 0x84, 0xb6, 0x4d,
+	0x61, 0xce, 0x08, 0x15, 0x62, 0xef, 0x8c, 0x50, 0xc2, 0xd4, 0x82, 0xc9, 0x3f, 0x0d, 0xf0, 0xcc,
+	0x21, 0xde, 0x9b, 0xb0, 0x83, 0xca, 0x56, 0xe9, 0x54, 0x6b, 0x4b, 0xa7, 0xc2, 0x17, 0xd0, 0x5d,
+	0xa2, 0xce, 0xf2, 0x4c, 0x67, 0x91, 0x47, 0x63, 0xfd, 0xe9, 0x96, 0x52, 0x0c, 0x7f, 0x63, 0xf4,
+	0x75, 0xa1, 0x65, 0x99, 0x3a, 0x72, 0xad, 0x1e, 0xfe, 0xc1, 0x7a, 0xc4, 0x57, 0x10, 0x6c, 0xe5,
+	0x30, 0x15, 0x98, 0x63, 0xc9, 0xdf, 0x6d, 0x5e, 0x4d, 0xa5, 0xee, 0xb2, 0xc5, 0xda, 0x1d, 0x0d,
+	0x32, 0x7e, 0x68, 0xbe, 0x6c, 0x24, 0xdf, 0x40, 0x87, 0xf5, 0xd0, 0x88, 0x8e, 0x11, 0xad, 0x3d,
+	0xd1, 0x21, 0x3d, 0x23, 0x28, 0xf9, 0x0a, 0xfc, 0x57, 0x0b, 0x61, 0x05, 0xea, 0xff, 0xb8, 0xd7,
+	0xe0, 0x19, 0xb9, 0xba, 0x07, 0xd5, 0x9c, 0xfb, 0x15, 0xa2, 0x34, 0x55, 0x6d, 0xed, 0xeb, 0x9d,
+	0xc5, 0x92, 0x37, 0xe0, 0xdd, 0x94, 0xc5, 0xc4, 0xe4, 0x33, 0x8e, 0x0f, 0x68, 0xa3, 0x81, 0x6a,
+	0x92, 0xd6, 0x3c, 0x20, 0x69, 0x97, 0x7f, 0xb7, 0xa0, 0x73, 0xcd, 0x6d, 0xfa, 0x71, 0x53, 0x87,
+	0x47, 0x2e, 0xe5, 0xf6, 0x05, 0x13, 0x47, 0xfb, 0x00, 0x5f, 0x21, 0x1f, 0x85, 0x2f, 0xc1, 0x27,
+	0x09, 0x0f, 0x1f, 0x3a, 0x52, 0x5d, 0xf8, 0xe3, 0xc1, 0xae, 0xbb, 0x1e, 0x49, 0x17, 0x4b, 0x2d,
+	0xb2, 0x7e, 0x1d, 0xc5, 0x83, 0x5d, 0xb7, 0x8b, 0xbc, 0x82, 0xb6, 0xd5, 0xf2, 0x70, 0xc3, 0xd9,
+	0xba, 0x13, 0xe2, 0x47, 0x7b, 0x7e, 0x17, 0xfc, 0x13, 0x74, 0x2b, 0xf1, 0x0e, 0x37, 0x1b, 0xdb,
+	0x91, 0xf8, 0xf8, 0x93, 0xff, 0x40, 0xea, 0xeb, 0xf3, 0xb9, 0x1a, 0xec, 0xce, 0xe6, 0xde, 0xfa,
+	0xdb, 0x87, 0xdd, 0x6e, 0x9b, 0xc4, 0xbf, 0xb6, 0xed, 0xfa, 0xe5, 0x10, 0x0f, 0x76, 0xdd, 0x55,
+	0xe4, 0xcf, 0xdf, 0xfd, 0x31, 0xfa, 0xc0, 0xff, 0x96, 0xbb, 0xa7, 0xfc, 0xd7, 0x85, 0x43, 0xaf,
+	0xf8, 0x39, 0x6e, 0x93, 0xfb, 0xe9, 0xbf, 0x03, 0x00, 0x93, 0x61, 0x0d, 0xef, 0x11, 0x09, 0x00,
 	0x00,
 }
 
@@ -989,6 +1239,8 @@ type NetworkClient interface {
 	Services(ctx context.Context, in *ServicesRequest, opts ...grpc.CallOption) (*ServicesResponse, error)
 	// Status returns network status
 	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	// Links returns the tunnel links of the node and their health
+	Links(ctx context.Context, in *LinksRequest, opts ...grpc.CallOption) (*LinksResponse, error)
 }
 
 type networkClient struct {
@@ -1053,6 +1305,15 @@ func (c *networkClient) Status(ctx context.Context, in *StatusRequest, opts ...g
 	return out, nil
 }
 
+func (c *networkClient) Links(ctx context.Context, in *LinksRequest, opts ...grpc.CallOption) (*LinksResponse, error) {
+	out := new(LinksResponse)
+	err := c.cc.Invoke(ctx, "/network.Network/Links", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NetworkServer is the server API for Network service.
 type NetworkServer interface {
 	// Connect to the network
@@ -1067,6 +1328,8 @@ type NetworkServer interface {
 	Services(context.Context, *ServicesRequest) (*ServicesResponse, error)
 	// Status returns network status
 	Status(context.Context, *StatusRequest) (*StatusResponse, error)
+	// Links returns the tunnel links of the node and their health
+	Links(context.Context, *LinksRequest) (*LinksResponse, error)
 }
 
 func RegisterNetworkServer(s *grpc.Server, srv NetworkServer) {
@@ -1181,6 +1444,24 @@ func _Network_Status_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Network_Links_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetworkServer).Links(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/network.Network/Links",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetworkServer).Links(ctx, req.(*LinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Network_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "network.Network",
 	HandlerType: (*NetworkServer)(nil),
@@ -1209,6 +1490,10 @@ var _Network_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Status",
 			Handler:    _Network_Status_Handler,
 		},
+		{
+			MethodName: "Links",
+			Handler:    _Network_Links_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "network/network.proto",
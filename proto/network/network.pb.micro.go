@@ -55,6 +55,8 @@ type NetworkService interface {
 	Services(ctx context.Context, in *ServicesRequest, opts ...client.CallOption) (*ServicesResponse, error)
 	// Status returns network status
 	Status(ctx context.Context, in *StatusRequest, opts ...client.CallOption) (*StatusResponse, error)
+	// Links returns the tunnel links of the node and their health
+	Links(ctx context.Context, in *LinksRequest, opts ...client.CallOption) (*LinksResponse, error)
 }
 
 type networkService struct {
@@ -129,6 +131,16 @@ func (c *networkService) Status(ctx context.Context, in *StatusRequest, opts ...
 	return out, nil
 }
 
+func (c *networkService) Links(ctx context.Context, in *LinksRequest, opts ...client.CallOption) (*LinksResponse, error) {
+	req := c.c.NewRequest(c.name, "Network.Links", in)
+	out := new(LinksResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Network service
 
 type NetworkHandler interface {
@@ -144,6 +156,8 @@ type NetworkHandler interface {
 	Services(context.Context, *ServicesRequest, *ServicesResponse) error
 	// Status returns network status
 	Status(context.Context, *StatusRequest, *StatusResponse) error
+	// Links returns the tunnel links of the node and their health
+	Links(context.Context, *LinksRequest, *LinksResponse) error
 }
 
 func RegisterNetworkHandler(s server.Server, hdlr NetworkHandler, opts ...server.HandlerOption) error {
@@ -154,6 +168,7 @@ func RegisterNetworkHandler(s server.Server, hdlr NetworkHandler, opts ...server
 		Routes(ctx context.Context, in *RoutesRequest, out *RoutesResponse) error
 		Services(ctx context.Context, in *ServicesRequest, out *ServicesResponse) error
 		Status(ctx context.Context, in *StatusRequest, out *StatusResponse) error
+		Links(ctx context.Context, in *LinksRequest, out *LinksResponse) error
 	}
 	type Network struct {
 		network
@@ -189,3 +204,7 @@ func (h *networkHandler) Services(ctx context.Context, in *ServicesRequest, out
 func (h *networkHandler) Status(ctx context.Context, in *StatusRequest, out *StatusResponse) error {
 	return h.NetworkHandler.Status(ctx, in, out)
 }
+
+func (h *networkHandler) Links(ctx context.Context, in *LinksRequest, out *LinksResponse) error {
+	return h.NetworkHandler.Links(ctx, in, out)
+}
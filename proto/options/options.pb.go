@@ -0,0 +1,50 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: options/options.proto
+
+package options
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	descriptor "github.com/golang/protobuf/protoc-gen-go/descriptor"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+var E_Topic = &proto.ExtensionDesc{
+	ExtendedType:  (*descriptor.MessageOptions)(nil),
+	ExtensionType: (*string)(nil),
+	Field:         51200,
+	Name:          "micro.topic",
+	Tag:           "bytes,51200,opt,name=topic",
+	Filename:      "options/options.proto",
+}
+
+func init() {
+	proto.RegisterExtension(E_Topic)
+}
+
+func init() { proto.RegisterFile("options/options.proto", fileDescriptor_fa3ac5190829870e) }
+
+var fileDescriptor_fa3ac5190829870e = []byte{
+	// 144 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0xcd, 0x2f, 0x28, 0xc9,
+	0xcc, 0xcf, 0x2b, 0xd6, 0x87, 0xd2, 0x7a, 0x05, 0x45, 0xf9, 0x25, 0xf9, 0x42, 0xac, 0xb9, 0x99,
+	0xc9, 0x45, 0xf9, 0x52, 0x0a, 0xe9, 0xf9, 0xf9, 0xe9, 0x39, 0xa9, 0xfa, 0x60, 0xc1, 0xa4, 0xd2,
+	0x34, 0xfd, 0x94, 0xd4, 0xe2, 0xe4, 0xa2, 0xcc, 0x82, 0x92, 0xfc, 0x22, 0x88, 0x42, 0x2b, 0x73,
+	0x2e, 0xd6, 0x92, 0xfc, 0x82, 0xcc, 0x64, 0x21, 0x79, 0x3d, 0x88, 0x5a, 0x3d, 0x98, 0x5a, 0x3d,
+	0xdf, 0xd4, 0xe2, 0xe2, 0xc4, 0xf4, 0x54, 0x7f, 0x88, 0xc1, 0x12, 0x0d, 0x13, 0x98, 0x15, 0x18,
+	0x35, 0x38, 0x83, 0x20, 0xea, 0x9d, 0x0c, 0xa3, 0xf4, 0xd3, 0x33, 0x4b, 0x32, 0x4a, 0x93, 0xf4,
+	0x92, 0xf3, 0x73, 0xf5, 0xc1, 0xd6, 0x41, 0xc9, 0x32, 0x63, 0x88, 0x7d, 0x30, 0x27, 0x59, 0x43,
+	0xe9, 0x24, 0x36, 0xb0, 0xb0, 0x31, 0x60, 0x00, 0x27, 0xde, 0x2f, 0x0c, 0xb4, 0x00, 0x00, 0x00,
+}
@@ -0,0 +1,385 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: quota/quota.proto
+
+package quota
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type AllowRequest struct {
+	// key of the bucket, e.g. the account or ip being limited
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// number of tokens added to the bucket per window
+	Limit int64 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// window in milliseconds
+	Window int64 `protobuf:"varint,3,opt,name=window,proto3" json:"window,omitempty"`
+	// maximum number of tokens the bucket holds, defaults to the limit
+	Burst int64 `protobuf:"varint,4,opt,name=burst,proto3" json:"burst,omitempty"`
+	// number of tokens to take, defaults to one
+	Tokens int64 `protobuf:"varint,5,opt,name=tokens,proto3" json:"tokens,omitempty"`
+	// namespace of the bucket
+	Namespace            string   `protobuf:"bytes,6,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AllowRequest) Reset()         { *m = AllowRequest{} }
+func (m *AllowRequest) String() string { return proto.CompactTextString(m) }
+func (*AllowRequest) ProtoMessage()    {}
+func (*AllowRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_416bd91ebc5aade8, []int{0}
+}
+
+func (m *AllowRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AllowRequest.Unmarshal(m, b)
+}
+func (m *AllowRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AllowRequest.Marshal(b, m, deterministic)
+}
+func (m *AllowRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AllowRequest.Merge(m, src)
+}
+func (m *AllowRequest) XXX_Size() int {
+	return xxx_messageInfo_AllowRequest.Size(m)
+}
+func (m *AllowRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AllowRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AllowRequest proto.InternalMessageInfo
+
+func (m *AllowRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *AllowRequest) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *AllowRequest) GetWindow() int64 {
+	if m != nil {
+		return m.Window
+	}
+	return 0
+}
+
+func (m *AllowRequest) GetBurst() int64 {
+	if m != nil {
+		return m.Burst
+	}
+	return 0
+}
+
+func (m *AllowRequest) GetTokens() int64 {
+	if m != nil {
+		return m.Tokens
+	}
+	return 0
+}
+
+func (m *AllowRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type AllowResponse struct {
+	Allowed bool `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
+	// tokens remaining in the bucket
+	Remaining int64 `protobuf:"varint,2,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	// milliseconds until the tokens requested are available if not allowed
+	RetryAfter           int64    `protobuf:"varint,3,opt,name=retry_after,json=retryAfter,proto3" json:"retry_after,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AllowResponse) Reset()         { *m = AllowResponse{} }
+func (m *AllowResponse) String() string { return proto.CompactTextString(m) }
+func (*AllowResponse) ProtoMessage()    {}
+func (*AllowResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_416bd91ebc5aade8, []int{1}
+}
+
+func (m *AllowResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AllowResponse.Unmarshal(m, b)
+}
+func (m *AllowResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AllowResponse.Marshal(b, m, deterministic)
+}
+func (m *AllowResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AllowResponse.Merge(m, src)
+}
+func (m *AllowResponse) XXX_Size() int {
+	return xxx_messageInfo_AllowResponse.Size(m)
+}
+func (m *AllowResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AllowResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AllowResponse proto.InternalMessageInfo
+
+func (m *AllowResponse) GetAllowed() bool {
+	if m != nil {
+		return m.Allowed
+	}
+	return false
+}
+
+func (m *AllowResponse) GetRemaining() int64 {
+	if m != nil {
+		return m.Remaining
+	}
+	return 0
+}
+
+func (m *AllowResponse) GetRetryAfter() int64 {
+	if m != nil {
+		return m.RetryAfter
+	}
+	return 0
+}
+
+type ResetRequest struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Namespace            string   `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResetRequest) Reset()         { *m = ResetRequest{} }
+func (m *ResetRequest) String() string { return proto.CompactTextString(m) }
+func (*ResetRequest) ProtoMessage()    {}
+func (*ResetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_416bd91ebc5aade8, []int{2}
+}
+
+func (m *ResetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResetRequest.Unmarshal(m, b)
+}
+func (m *ResetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResetRequest.Marshal(b, m, deterministic)
+}
+func (m *ResetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResetRequest.Merge(m, src)
+}
+func (m *ResetRequest) XXX_Size() int {
+	return xxx_messageInfo_ResetRequest.Size(m)
+}
+func (m *ResetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResetRequest proto.InternalMessageInfo
+
+func (m *ResetRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *ResetRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type ResetResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResetResponse) Reset()         { *m = ResetResponse{} }
+func (m *ResetResponse) String() string { return proto.CompactTextString(m) }
+func (*ResetResponse) ProtoMessage()    {}
+func (*ResetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_416bd91ebc5aade8, []int{3}
+}
+
+func (m *ResetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResetResponse.Unmarshal(m, b)
+}
+func (m *ResetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResetResponse.Marshal(b, m, deterministic)
+}
+func (m *ResetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResetResponse.Merge(m, src)
+}
+func (m *ResetResponse) XXX_Size() int {
+	return xxx_messageInfo_ResetResponse.Size(m)
+}
+func (m *ResetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResetResponse proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*AllowRequest)(nil), "quota.AllowRequest")
+	proto.RegisterType((*AllowResponse)(nil), "quota.AllowResponse")
+	proto.RegisterType((*ResetRequest)(nil), "quota.ResetRequest")
+	proto.RegisterType((*ResetResponse)(nil), "quota.ResetResponse")
+}
+
+func init() { proto.RegisterFile("quota/quota.proto", fileDescriptor_416bd91ebc5aade8) }
+
+var fileDescriptor_416bd91ebc5aade8 = []byte{
+	// 309 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x92, 0x51, 0x4b, 0x3a, 0x41,
+	0x14, 0xc5, 0xff, 0xab, 0xff, 0xb5, 0xbc, 0x29, 0xd5, 0x24, 0x31, 0x48, 0x90, 0xec, 0x93, 0x10,
+	0x29, 0x64, 0x6f, 0x41, 0x60, 0xdf, 0xa0, 0x7d, 0xec, 0x25, 0x46, 0xbd, 0xe9, 0xa0, 0x3b, 0xa3,
+	0x33, 0xb3, 0x89, 0x5f, 0xa5, 0x4f, 0x1b, 0x73, 0x67, 0x4c, 0x37, 0xe8, 0xe5, 0xb2, 0xe7, 0xc7,
+	0x3d, 0xb3, 0x67, 0xcf, 0x0e, 0x5c, 0x6e, 0x4a, 0xed, 0xc4, 0x90, 0xe6, 0x60, 0x6d, 0xb4, 0xd3,
+	0x2c, 0x25, 0x91, 0x7d, 0x25, 0xd0, 0x1a, 0xaf, 0x56, 0x7a, 0x9b, 0xe3, 0xa6, 0x44, 0xeb, 0xd8,
+	0x05, 0xd4, 0x97, 0xb8, 0xe3, 0x49, 0x2f, 0xe9, 0x37, 0x73, 0xff, 0xc8, 0x3a, 0x90, 0xae, 0x64,
+	0x21, 0x1d, 0xaf, 0xf5, 0x92, 0x7e, 0x3d, 0x0f, 0x82, 0x5d, 0x43, 0x63, 0x2b, 0xd5, 0x4c, 0x6f,
+	0x79, 0x9d, 0x70, 0x54, 0x7e, 0x7b, 0x52, 0x1a, 0xeb, 0xf8, 0xff, 0xb0, 0x4d, 0xc2, 0x6f, 0x3b,
+	0xbd, 0x44, 0x65, 0x79, 0x1a, 0xb6, 0x83, 0x62, 0x37, 0xd0, 0x54, 0xa2, 0x40, 0xbb, 0x16, 0x53,
+	0xe4, 0x0d, 0x7a, 0xe7, 0x01, 0x64, 0x0b, 0x68, 0xc7, 0x6c, 0x76, 0xad, 0x95, 0x45, 0xc6, 0xe1,
+	0x44, 0x78, 0x80, 0x33, 0x0a, 0x78, 0x9a, 0xef, 0xa5, 0x3f, 0xc8, 0x60, 0x21, 0xa4, 0x92, 0x6a,
+	0x1e, 0x83, 0x1e, 0x00, 0xbb, 0x85, 0x33, 0x83, 0xce, 0xec, 0xde, 0xc5, 0x87, 0x43, 0x13, 0x13,
+	0x03, 0xa1, 0xb1, 0x27, 0xd9, 0x33, 0xb4, 0x72, 0xb4, 0xe8, 0xfe, 0x6e, 0xa1, 0x92, 0xb4, 0xf6,
+	0x3b, 0xe9, 0x39, 0xb4, 0xa3, 0x3f, 0x24, 0x7d, 0xb0, 0x90, 0xbe, 0xfa, 0x82, 0xd9, 0x23, 0xa4,
+	0xf4, 0x0d, 0xec, 0x6a, 0x10, 0xea, 0x3f, 0x6e, 0xbb, 0xdb, 0xa9, 0xc2, 0x60, 0xce, 0xfe, 0x79,
+	0x17, 0x9d, 0xf7, 0xe3, 0x3a, 0x4e, 0xd7, 0xed, 0x54, 0xe1, 0xde, 0xf5, 0x72, 0xff, 0x76, 0x37,
+	0x97, 0x6e, 0x51, 0x4e, 0x06, 0x53, 0x5d, 0x0c, 0x0b, 0x39, 0x35, 0x3a, 0xce, 0xcf, 0xd1, 0x90,
+	0x7e, 0x7b, 0xb8, 0x02, 0x4f, 0x34, 0x27, 0x0d, 0x42, 0xa3, 0xef, 0x01, 0x00, 0xe9, 0xa9, 0x18,
+	0xe2, 0x1e, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// QuotaClient is the client API for Quota service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type QuotaClient interface {
+	// Allow takes tokens from a bucket, returning whether there were enough
+	Allow(ctx context.Context, in *AllowRequest, opts ...grpc.CallOption) (*AllowResponse, error)
+	// Reset refills a bucket
+	Reset(ctx context.Context, in *ResetRequest, opts ...grpc.CallOption) (*ResetResponse, error)
+}
+
+type quotaClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewQuotaClient(cc *grpc.ClientConn) QuotaClient {
+	return &quotaClient{cc}
+}
+
+func (c *quotaClient) Allow(ctx context.Context, in *AllowRequest, opts ...grpc.CallOption) (*AllowResponse, error) {
+	out := new(AllowResponse)
+	err := c.cc.Invoke(ctx, "/quota.Quota/Allow", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quotaClient) Reset(ctx context.Context, in *ResetRequest, opts ...grpc.CallOption) (*ResetResponse, error) {
+	out := new(ResetResponse)
+	err := c.cc.Invoke(ctx, "/quota.Quota/Reset", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// QuotaServer is the server API for Quota service.
+type QuotaServer interface {
+	// Allow takes tokens from a bucket, returning whether there were enough
+	Allow(context.Context, *AllowRequest) (*AllowResponse, error)
+	// Reset refills a bucket
+	Reset(context.Context, *ResetRequest) (*ResetResponse, error)
+}
+
+func RegisterQuotaServer(s *grpc.Server, srv QuotaServer) {
+	s.RegisterService(&_Quota_serviceDesc, srv)
+}
+
+func _Quota_Allow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AllowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuotaServer).Allow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/quota.Quota/Allow",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuotaServer).Allow(ctx, req.(*AllowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Quota_Reset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuotaServer).Reset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/quota.Quota/Reset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuotaServer).Reset(ctx, req.(*ResetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Quota_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "quota.Quota",
+	HandlerType: (*QuotaServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Allow",
+			Handler:    _Quota_Allow_Handler,
+		},
+		{
+			MethodName: "Reset",
+			Handler:    _Quota_Reset_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "quota/quota.proto",
+}
@@ -0,0 +1,114 @@
+// Code generated by protoc-gen-micro. DO NOT EDIT.
+// source: quota/quota.proto
+
+package quota
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+import (
+	context "context"
+	api "github.com/micro/micro/v3/service/api"
+	client "github.com/micro/micro/v3/service/client"
+	server "github.com/micro/micro/v3/service/server"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ api.Endpoint
+var _ context.Context
+var _ client.Option
+var _ server.Option
+
+// Api Endpoints for Quota service
+
+func NewQuotaEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for Quota service
+
+type QuotaService interface {
+	// Allow takes tokens from a bucket, returning whether there were enough
+	Allow(ctx context.Context, in *AllowRequest, opts ...client.CallOption) (*AllowResponse, error)
+	// Reset refills a bucket
+	Reset(ctx context.Context, in *ResetRequest, opts ...client.CallOption) (*ResetResponse, error)
+}
+
+type quotaService struct {
+	c    client.Client
+	name string
+}
+
+func NewQuotaService(name string, c client.Client) QuotaService {
+	return &quotaService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *quotaService) Allow(ctx context.Context, in *AllowRequest, opts ...client.CallOption) (*AllowResponse, error) {
+	req := c.c.NewRequest(c.name, "Quota.Allow", in)
+	out := new(AllowResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quotaService) Reset(ctx context.Context, in *ResetRequest, opts ...client.CallOption) (*ResetResponse, error) {
+	req := c.c.NewRequest(c.name, "Quota.Reset", in)
+	out := new(ResetResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Quota service
+
+type QuotaHandler interface {
+	// Allow takes tokens from a bucket, returning whether there were enough
+	Allow(context.Context, *AllowRequest, *AllowResponse) error
+	// Reset refills a bucket
+	Reset(context.Context, *ResetRequest, *ResetResponse) error
+}
+
+func RegisterQuotaHandler(s server.Server, hdlr QuotaHandler, opts ...server.HandlerOption) error {
+	type quota interface {
+		Allow(ctx context.Context, in *AllowRequest, out *AllowResponse) error
+		Reset(ctx context.Context, in *ResetRequest, out *ResetResponse) error
+	}
+	type Quota struct {
+		quota
+	}
+	h := &quotaHandler{hdlr}
+	return s.Handle(s.NewHandler(&Quota{h}, opts...))
+}
+
+type quotaHandler struct {
+	QuotaHandler
+}
+
+func (h *quotaHandler) Allow(ctx context.Context, in *AllowRequest, out *AllowResponse) error {
+	return h.QuotaHandler.Allow(ctx, in, out)
+}
+
+func (h *quotaHandler) Reset(ctx context.Context, in *ResetRequest, out *ResetResponse) error {
+	return h.QuotaHandler.Reset(ctx, in, out)
+}
@@ -317,8 +317,10 @@ func (m *Value) GetValues() []*Value {
 
 // Options are registry options
 type Options struct {
-	Ttl                  int64    `protobuf:"varint,1,opt,name=ttl,proto3" json:"ttl,omitempty"`
-	Domain               string   `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	Ttl    int64  `protobuf:"varint,1,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	Domain string `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	// region to scope the nodes of get and list requests to
+	Region               string   `protobuf:"bytes,3,opt,name=region,proto3" json:"region,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -363,6 +365,13 @@ func (m *Options) GetDomain() string {
 	return ""
 }
 
+func (m *Options) GetRegion() string {
+	if m != nil {
+		return m.Region
+	}
+	return ""
+}
+
 // Result is returns by the watcher
 type Result struct {
 	Action               string   `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
@@ -753,52 +762,52 @@ func init() {
 func init() { proto.RegisterFile("registry/registry.proto", fileDescriptor_f3f64dc2c9630278) }
 
 var fileDescriptor_f3f64dc2c9630278 = []byte{
-	// 709 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0xdb, 0x6e, 0xd3, 0x4c,
-	0x10, 0x8e, 0x9d, 0xf3, 0xa4, 0x87, 0x74, 0xff, 0xfe, 0xad, 0x15, 0x55, 0x22, 0xb2, 0x90, 0x1a,
-	0xa8, 0x48, 0x4a, 0x22, 0xa4, 0xaa, 0x29, 0x42, 0x82, 0x46, 0xdc, 0x70, 0x90, 0x5c, 0x0a, 0x88,
-	0x3b, 0x37, 0x1e, 0xb5, 0x56, 0x63, 0xaf, 0xd9, 0xdd, 0x44, 0xca, 0x33, 0xf0, 0x3c, 0x70, 0xc3,
-	0x53, 0xf1, 0x06, 0x68, 0xd7, 0xeb, 0x43, 0x9b, 0x40, 0xa4, 0x02, 0x37, 0xd1, 0xec, 0xcc, 0x37,
-	0xe7, 0x6f, 0x62, 0xd8, 0x65, 0x78, 0xe9, 0x73, 0xc1, 0xe6, 0xbd, 0x44, 0xe8, 0x46, 0x8c, 0x0a,
-	0x4a, 0x6a, 0xc9, 0xdb, 0xfe, 0x66, 0x42, 0xf5, 0x0c, 0xd9, 0xcc, 0x1f, 0x23, 0x21, 0x50, 0x0a,
-	0xdd, 0x00, 0x2d, 0xa3, 0x6d, 0x74, 0xea, 0x8e, 0x92, 0x89, 0x05, 0xd5, 0x19, 0x32, 0xee, 0xd3,
-	0xd0, 0x32, 0x95, 0x3a, 0x79, 0x92, 0x21, 0xd4, 0x02, 0x14, 0xae, 0xe7, 0x0a, 0xd7, 0x2a, 0xb6,
-	0x8b, 0x9d, 0x46, 0xff, 0x5e, 0x37, 0x4d, 0xa3, 0x43, 0x76, 0x5f, 0x6b, 0xc4, 0x28, 0x14, 0x6c,
-	0xee, 0xa4, 0x0e, 0xe4, 0x10, 0xea, 0x18, 0x7a, 0x11, 0xf5, 0x43, 0xc1, 0xad, 0x92, 0xf2, 0x26,
-	0x99, 0xf7, 0x48, 0x9b, 0x9c, 0x0c, 0x44, 0xee, 0x43, 0x39, 0xa4, 0x1e, 0x72, 0xab, 0xac, 0xd0,
-	0x1b, 0x19, 0xfa, 0x0d, 0xf5, 0xd0, 0x89, 0x8d, 0xe4, 0x00, 0xaa, 0x34, 0x12, 0x3e, 0x0d, 0xb9,
-	0x55, 0x69, 0x1b, 0x9d, 0x46, 0x7f, 0x2b, 0xc3, 0xbd, 0x8d, 0x0d, 0x4e, 0x82, 0x68, 0x0d, 0x61,
-	0xfd, 0x46, 0x7d, 0xa4, 0x09, 0xc5, 0x6b, 0x9c, 0xeb, 0xfe, 0xa5, 0x48, 0xb6, 0xa1, 0x3c, 0x73,
-	0x27, 0x53, 0xd4, 0xcd, 0xc7, 0x8f, 0x63, 0xf3, 0xc8, 0xb0, 0xbf, 0x1b, 0x50, 0x92, 0x99, 0xc9,
-	0x06, 0x98, 0xbe, 0xa7, 0x7d, 0x4c, 0xdf, 0x93, 0x13, 0x73, 0x3d, 0x8f, 0x21, 0xe7, 0xc9, 0xc4,
-	0xf4, 0x53, 0xce, 0x37, 0xa2, 0x4c, 0x58, 0xc5, 0xb6, 0xd1, 0x29, 0x3a, 0x4a, 0x26, 0x47, 0xb9,
-	0x29, 0xc6, 0x73, 0xd8, 0xbb, 0xd9, 0xd9, 0xaf, 0x46, 0xf8, 0x67, 0xd5, 0xff, 0x30, 0xa0, 0x96,
-	0x4c, 0x79, 0xe9, 0xde, 0x1f, 0x40, 0x95, 0xe1, 0xe7, 0x29, 0x72, 0xa1, 0x9c, 0x1b, 0xfd, 0xcd,
-	0xac, 0xac, 0xf7, 0x32, 0x8c, 0x93, 0xd8, 0xc9, 0x01, 0xd4, 0x18, 0xf2, 0x88, 0x86, 0x1c, 0x55,
-	0x6b, 0x4b, 0xb0, 0x29, 0x80, 0x9c, 0x2c, 0xf4, 0xdb, 0x5e, 0xdc, 0xfb, 0xbf, 0xe9, 0xf9, 0x23,
-	0x94, 0x55, 0x35, 0x4b, 0xfb, 0x25, 0x50, 0x12, 0xf3, 0x28, 0xf1, 0x52, 0x32, 0xd9, 0x87, 0x8a,
-	0xf2, 0xe6, 0x9a, 0xdf, 0x0b, 0x6d, 0x69, 0xb3, 0x3d, 0x80, 0xaa, 0x26, 0x97, 0x2c, 0x48, 0x88,
-	0x89, 0x0a, 0x5d, 0x74, 0xa4, 0x48, 0x76, 0xa0, 0xe2, 0xd1, 0xc0, 0xf5, 0x93, 0x03, 0xd2, 0x2f,
-	0xfb, 0x1a, 0x2a, 0x0e, 0xf2, 0xe9, 0x44, 0x48, 0x84, 0x3b, 0x96, 0xee, 0xba, 0x22, 0xfd, 0x92,
-	0x64, 0xe6, 0xf1, 0x1d, 0xe9, 0x1d, 0x6c, 0x2d, 0x1c, 0x98, 0x93, 0x20, 0xc8, 0x1e, 0xd4, 0x85,
-	0x1f, 0x20, 0x17, 0x6e, 0x10, 0x69, 0x86, 0x65, 0x0a, 0x7b, 0x13, 0xd6, 0x47, 0x41, 0x24, 0xe6,
-	0x8e, 0xde, 0x83, 0x7d, 0x06, 0xf0, 0x12, 0x85, 0xa3, 0x57, 0x68, 0x65, 0x99, 0xe2, 0x12, 0xd2,
-	0xb0, 0xb9, 0x83, 0x32, 0x57, 0x1d, 0x94, 0x7d, 0x02, 0x0d, 0x15, 0x54, 0xef, 0xfa, 0x11, 0xd4,
-	0x74, 0x18, 0x6e, 0x19, 0x6a, 0x82, 0x4b, 0x1a, 0x48, 0x21, 0xf6, 0x31, 0x34, 0x5e, 0xf9, 0x3c,
-	0xad, 0x29, 0x97, 0xd9, 0x58, 0x99, 0xf9, 0x29, 0xac, 0xc5, 0xbe, 0x77, 0x4b, 0x7d, 0x0e, 0x6b,
-	0x1f, 0x5c, 0x31, 0xbe, 0xfa, 0xcb, 0xf3, 0xf8, 0x62, 0x40, 0x79, 0x34, 0xc3, 0x50, 0x2c, 0xfc,
-	0x49, 0xec, 0xe7, 0xe8, 0xb6, 0xd1, 0xff, 0x2f, 0x77, 0x02, 0x12, 0xfe, 0x6e, 0x1e, 0xa1, 0xe6,
-	0xe0, 0x6f, 0xd7, 0x9a, 0x67, 0x48, 0x69, 0x15, 0x43, 0x1e, 0xf6, 0xa0, 0x9e, 0x46, 0x27, 0x00,
-	0x95, 0x17, 0x0c, 0x5d, 0x81, 0xcd, 0x82, 0x94, 0x4f, 0x71, 0x82, 0x02, 0x9b, 0x86, 0x94, 0xcf,
-	0x23, 0x4f, 0xea, 0xcd, 0xfe, 0x57, 0x13, 0x6a, 0x8e, 0x0e, 0x47, 0x86, 0x8a, 0x30, 0xc9, 0xa7,
-	0x62, 0x3b, 0xcb, 0x93, 0xd1, 0xa8, 0xf5, 0xff, 0x2d, 0xad, 0xe6, 0x5a, 0x41, 0xfe, 0xcb, 0xc5,
-	0x81, 0x90, 0x91, 0xc5, 0x12, 0x5b, 0xbb, 0xb9, 0xfe, 0x6f, 0xb0, 0xb4, 0x40, 0x8e, 0x01, 0x4e,
-	0x91, 0xdd, 0xcd, 0xf7, 0x59, 0x4c, 0x0a, 0x8d, 0xe4, 0x24, 0x57, 0x5e, 0x8e, 0x68, 0xad, 0x9d,
-	0xdb, 0xea, 0x34, 0xc0, 0x13, 0x28, 0x2b, 0x5a, 0x90, 0x1c, 0x24, 0xcf, 0x93, 0x56, 0x33, 0xd3,
-	0xc7, 0xb7, 0x6c, 0x17, 0x0e, 0x8d, 0xe7, 0x83, 0x4f, 0x8f, 0x2f, 0x7d, 0x71, 0x35, 0xbd, 0xe8,
-	0x8e, 0x69, 0xd0, 0x0b, 0xfc, 0x31, 0xa3, 0xfa, 0x77, 0x36, 0xe8, 0xa9, 0x0f, 0x70, 0xfa, 0x3d,
-	0x1e, 0x26, 0xc2, 0x45, 0x45, 0x19, 0x06, 0x3f, 0x03, 0x00, 0x00, 0xff, 0xff, 0xde, 0x1c, 0x89,
-	0xfa, 0xb4, 0x07, 0x00, 0x00,
+	// 716 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0xd9, 0x6e, 0xd3, 0x4c,
+	0x14, 0x8e, 0xed, 0xac, 0x27, 0x5d, 0xf2, 0xcf, 0x5f, 0x5a, 0x2b, 0xaa, 0x44, 0x64, 0x21, 0x35,
+	0x50, 0x91, 0x94, 0x54, 0x48, 0x55, 0x53, 0x84, 0x04, 0x8d, 0xb8, 0x60, 0x93, 0x5c, 0x0a, 0x88,
+	0x3b, 0x37, 0x3e, 0x6a, 0xad, 0xc6, 0x0b, 0x33, 0x93, 0x48, 0x79, 0x06, 0x9e, 0x07, 0x6e, 0x78,
+	0x2a, 0xde, 0x00, 0xcd, 0x78, 0xc6, 0x76, 0x9a, 0x42, 0xa5, 0x02, 0x37, 0xd1, 0x59, 0xbe, 0xb3,
+	0xce, 0x77, 0x62, 0xd8, 0xa2, 0x78, 0x1e, 0x30, 0x4e, 0xe7, 0x7d, 0x2d, 0xf4, 0x12, 0x1a, 0xf3,
+	0x98, 0xd4, 0xb5, 0xee, 0x7c, 0x33, 0xa1, 0x76, 0x82, 0x74, 0x16, 0x8c, 0x91, 0x10, 0x28, 0x47,
+	0x5e, 0x88, 0xb6, 0xd1, 0x31, 0xba, 0x0d, 0x57, 0xca, 0xc4, 0x86, 0xda, 0x0c, 0x29, 0x0b, 0xe2,
+	0xc8, 0x36, 0xa5, 0x59, 0xab, 0x64, 0x08, 0xf5, 0x10, 0xb9, 0xe7, 0x7b, 0xdc, 0xb3, 0xad, 0x8e,
+	0xd5, 0x6d, 0x0e, 0xee, 0xf6, 0xb2, 0x32, 0x2a, 0x65, 0xef, 0xb5, 0x42, 0x8c, 0x22, 0x4e, 0xe7,
+	0x6e, 0x16, 0x40, 0xf6, 0xa0, 0x81, 0x91, 0x9f, 0xc4, 0x41, 0xc4, 0x99, 0x5d, 0x96, 0xd1, 0x24,
+	0x8f, 0x1e, 0x29, 0x97, 0x9b, 0x83, 0xc8, 0x3d, 0xa8, 0x44, 0xb1, 0x8f, 0xcc, 0xae, 0x48, 0xf4,
+	0x5a, 0x8e, 0x7e, 0x13, 0xfb, 0xe8, 0xa6, 0x4e, 0xb2, 0x0b, 0xb5, 0x38, 0xe1, 0x41, 0x1c, 0x31,
+	0xbb, 0xda, 0x31, 0xba, 0xcd, 0xc1, 0x7f, 0x39, 0xee, 0x6d, 0xea, 0x70, 0x35, 0xa2, 0x3d, 0x84,
+	0xd5, 0x85, 0xfe, 0x48, 0x0b, 0xac, 0x4b, 0x9c, 0xab, 0xf9, 0x85, 0x48, 0x36, 0xa0, 0x32, 0xf3,
+	0x26, 0x53, 0x54, 0xc3, 0xa7, 0xca, 0xa1, 0x79, 0x60, 0x38, 0xdf, 0x0d, 0x28, 0x8b, 0xca, 0x64,
+	0x0d, 0xcc, 0xc0, 0x57, 0x31, 0x66, 0xe0, 0x8b, 0x8d, 0x79, 0xbe, 0x4f, 0x91, 0x31, 0xbd, 0x31,
+	0xa5, 0x8a, 0xfd, 0x26, 0x31, 0xe5, 0xb6, 0xd5, 0x31, 0xba, 0x96, 0x2b, 0x65, 0x72, 0x50, 0xd8,
+	0x62, 0xba, 0x87, 0xed, 0xc5, 0xc9, 0x7e, 0xb5, 0xc2, 0x3f, 0xeb, 0xfe, 0x87, 0x01, 0x75, 0xbd,
+	0xe5, 0x6b, 0xdf, 0xfd, 0x3e, 0xd4, 0x28, 0x7e, 0x9e, 0x22, 0xe3, 0x32, 0xb8, 0x39, 0x58, 0xcf,
+	0xdb, 0x7a, 0x2f, 0xd2, 0xb8, 0xda, 0x4f, 0x76, 0xa1, 0x4e, 0x91, 0x25, 0x71, 0xc4, 0xd0, 0xb6,
+	0xae, 0xc7, 0x66, 0x00, 0x72, 0xb4, 0x34, 0x6f, 0x67, 0xf9, 0xdd, 0xff, 0xcd, 0xcc, 0x1f, 0xa1,
+	0x22, 0xbb, 0xb9, 0x76, 0x5e, 0x02, 0x65, 0x3e, 0x4f, 0x74, 0x94, 0x94, 0xc9, 0x0e, 0x54, 0x65,
+	0x34, 0x53, 0xfc, 0x5e, 0x1a, 0x4b, 0xb9, 0x9d, 0x97, 0x50, 0x53, 0xe4, 0x12, 0x0d, 0x71, 0x3e,
+	0x91, 0xa9, 0x2d, 0x57, 0x88, 0x64, 0x13, 0xaa, 0x7e, 0x1c, 0x7a, 0x81, 0x3e, 0x20, 0xa5, 0x09,
+	0xbb, 0x48, 0x17, 0x47, 0x72, 0x69, 0x0d, 0x57, 0x69, 0xce, 0x25, 0x54, 0x5d, 0x64, 0xd3, 0x09,
+	0x17, 0x08, 0x6f, 0x2c, 0xd2, 0xaa, 0x4e, 0x95, 0x26, 0x48, 0xce, 0xd2, 0xfb, 0xb2, 0xcd, 0xab,
+	0x24, 0x57, 0x87, 0xe7, 0x6a, 0x04, 0xd9, 0x86, 0x06, 0x0f, 0x42, 0x64, 0xdc, 0x0b, 0x13, 0xc5,
+	0xbc, 0xdc, 0xe0, 0xac, 0xc3, 0xea, 0x28, 0x4c, 0xf8, 0xdc, 0x55, 0xef, 0xe3, 0x9c, 0x00, 0xbc,
+	0x40, 0xee, 0xaa, 0xa7, 0xb5, 0xf3, 0x4a, 0x69, 0x0b, 0x59, 0xda, 0xc2, 0xa1, 0x99, 0x37, 0x1d,
+	0x9a, 0x73, 0x04, 0x4d, 0x99, 0x54, 0x71, 0xe0, 0x21, 0xd4, 0x55, 0x1a, 0x66, 0x1b, 0x1d, 0x6b,
+	0x31, 0x58, 0x0f, 0x90, 0x41, 0x9c, 0x43, 0x68, 0xbe, 0x0a, 0x58, 0xd6, 0x53, 0xa1, 0xb2, 0x71,
+	0x63, 0xe5, 0x27, 0xb0, 0x92, 0xc6, 0xde, 0xae, 0xf4, 0x29, 0xac, 0x7c, 0xf0, 0xf8, 0xf8, 0xe2,
+	0x2f, 0xef, 0xe3, 0x8b, 0x01, 0x95, 0xd1, 0x0c, 0x23, 0xbe, 0xf4, 0xe7, 0xb1, 0x53, 0xa0, 0xe1,
+	0xda, 0xe0, 0xff, 0xc2, 0x69, 0x08, 0xf8, 0xbb, 0x79, 0x82, 0x8a, 0x9b, 0xbf, 0x7d, 0xd6, 0x22,
+	0x43, 0xca, 0x37, 0x31, 0xe4, 0x41, 0x1f, 0x1a, 0x59, 0x76, 0x02, 0x50, 0x7d, 0x4e, 0xd1, 0xe3,
+	0xd8, 0x2a, 0x09, 0xf9, 0x18, 0x27, 0xc8, 0xb1, 0x65, 0x08, 0xf9, 0x34, 0xf1, 0x85, 0xdd, 0x1c,
+	0x7c, 0x35, 0xa1, 0xee, 0xaa, 0x74, 0x64, 0x28, 0x09, 0xa3, 0x3f, 0x21, 0x1b, 0x79, 0x9d, 0x9c,
+	0x46, 0xed, 0x3b, 0x57, 0xac, 0x8a, 0x6b, 0x25, 0x72, 0xa0, 0x13, 0x21, 0x25, 0xcb, 0x2d, 0xb6,
+	0xb7, 0x0a, 0xf3, 0x2f, 0xb0, 0xb4, 0x44, 0x0e, 0x01, 0x8e, 0x91, 0xde, 0x2e, 0xf6, 0x69, 0x4a,
+	0x0a, 0x85, 0x64, 0xa4, 0xd0, 0x5e, 0x81, 0x68, 0xed, 0xcd, 0xab, 0xe6, 0x2c, 0xc1, 0x63, 0xa8,
+	0x48, 0x5a, 0x90, 0x02, 0xa4, 0xc8, 0x93, 0x76, 0x2b, 0xb7, 0xa7, 0xb7, 0xec, 0x94, 0xf6, 0x8c,
+	0x67, 0xfb, 0x9f, 0x1e, 0x9d, 0x07, 0xfc, 0x62, 0x7a, 0xd6, 0x1b, 0xc7, 0x61, 0x3f, 0x0c, 0xc6,
+	0x34, 0x56, 0xbf, 0xb3, 0xfd, 0xbe, 0xfc, 0x30, 0x67, 0xdf, 0xe9, 0xa1, 0x16, 0xce, 0xaa, 0xd2,
+	0xb1, 0xff, 0x73, 0x00, 0x1e, 0xaa, 0x51, 0xe9, 0xcc, 0x07, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
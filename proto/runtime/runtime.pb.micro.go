@@ -502,3 +502,79 @@ func (x *buildReadStream) RecvMsg(m interface{}) error {
 func (x *buildReadStream) Send(m *BuildReadResponse) error {
 	return x.stream.Send(m)
 }
+
+// Api Endpoints for Jobs service
+
+func NewJobsEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for Jobs service
+
+type JobsService interface {
+	Run(ctx context.Context, in *RunJobRequest, opts ...client.CallOption) (*RunJobResponse, error)
+	Read(ctx context.Context, in *ReadJobsRequest, opts ...client.CallOption) (*ReadJobsResponse, error)
+}
+
+type jobsService struct {
+	c    client.Client
+	name string
+}
+
+func NewJobsService(name string, c client.Client) JobsService {
+	return &jobsService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *jobsService) Run(ctx context.Context, in *RunJobRequest, opts ...client.CallOption) (*RunJobResponse, error) {
+	req := c.c.NewRequest(c.name, "Jobs.Run", in)
+	out := new(RunJobResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobsService) Read(ctx context.Context, in *ReadJobsRequest, opts ...client.CallOption) (*ReadJobsResponse, error) {
+	req := c.c.NewRequest(c.name, "Jobs.Read", in)
+	out := new(ReadJobsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Jobs service
+
+type JobsHandler interface {
+	Run(context.Context, *RunJobRequest, *RunJobResponse) error
+	Read(context.Context, *ReadJobsRequest, *ReadJobsResponse) error
+}
+
+func RegisterJobsHandler(s server.Server, hdlr JobsHandler, opts ...server.HandlerOption) error {
+	type jobs interface {
+		Run(ctx context.Context, in *RunJobRequest, out *RunJobResponse) error
+		Read(ctx context.Context, in *ReadJobsRequest, out *ReadJobsResponse) error
+	}
+	type Jobs struct {
+		jobs
+	}
+	h := &jobsHandler{hdlr}
+	return s.Handle(s.NewHandler(&Jobs{h}, opts...))
+}
+
+type jobsHandler struct {
+	JobsHandler
+}
+
+func (h *jobsHandler) Run(ctx context.Context, in *RunJobRequest, out *RunJobResponse) error {
+	return h.JobsHandler.Run(ctx, in, out)
+}
+
+func (h *jobsHandler) Read(ctx context.Context, in *ReadJobsRequest, out *ReadJobsResponse) error {
+	return h.JobsHandler.Read(ctx, in, out)
+}
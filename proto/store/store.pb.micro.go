@@ -48,6 +48,8 @@ type StoreService interface {
 	List(ctx context.Context, in *ListRequest, opts ...client.CallOption) (Store_ListService, error)
 	Databases(ctx context.Context, in *DatabasesRequest, opts ...client.CallOption) (*DatabasesResponse, error)
 	Tables(ctx context.Context, in *TablesRequest, opts ...client.CallOption) (*TablesResponse, error)
+	BatchWrite(ctx context.Context, in *BatchWriteRequest, opts ...client.CallOption) (*BatchWriteResponse, error)
+	ReadMany(ctx context.Context, in *ReadManyRequest, opts ...client.CallOption) (*ReadManyResponse, error)
 }
 
 type storeService struct {
@@ -161,6 +163,26 @@ func (c *storeService) Tables(ctx context.Context, in *TablesRequest, opts ...cl
 	return out, nil
 }
 
+func (c *storeService) BatchWrite(ctx context.Context, in *BatchWriteRequest, opts ...client.CallOption) (*BatchWriteResponse, error) {
+	req := c.c.NewRequest(c.name, "Store.BatchWrite", in)
+	out := new(BatchWriteResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeService) ReadMany(ctx context.Context, in *ReadManyRequest, opts ...client.CallOption) (*ReadManyResponse, error) {
+	req := c.c.NewRequest(c.name, "Store.ReadMany", in)
+	out := new(ReadManyResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Store service
 
 type StoreHandler interface {
@@ -170,6 +192,8 @@ type StoreHandler interface {
 	List(context.Context, *ListRequest, Store_ListStream) error
 	Databases(context.Context, *DatabasesRequest, *DatabasesResponse) error
 	Tables(context.Context, *TablesRequest, *TablesResponse) error
+	BatchWrite(context.Context, *BatchWriteRequest, *BatchWriteResponse) error
+	ReadMany(context.Context, *ReadManyRequest, *ReadManyResponse) error
 }
 
 func RegisterStoreHandler(s server.Server, hdlr StoreHandler, opts ...server.HandlerOption) error {
@@ -180,6 +204,8 @@ func RegisterStoreHandler(s server.Server, hdlr StoreHandler, opts ...server.Han
 		List(ctx context.Context, stream server.Stream) error
 		Databases(ctx context.Context, in *DatabasesRequest, out *DatabasesResponse) error
 		Tables(ctx context.Context, in *TablesRequest, out *TablesResponse) error
+		BatchWrite(ctx context.Context, in *BatchWriteRequest, out *BatchWriteResponse) error
+		ReadMany(ctx context.Context, in *ReadManyRequest, out *ReadManyResponse) error
 	}
 	type Store struct {
 		store
@@ -252,6 +278,14 @@ func (h *storeHandler) Tables(ctx context.Context, in *TablesRequest, out *Table
 	return h.StoreHandler.Tables(ctx, in, out)
 }
 
+func (h *storeHandler) BatchWrite(ctx context.Context, in *BatchWriteRequest, out *BatchWriteResponse) error {
+	return h.StoreHandler.BatchWrite(ctx, in, out)
+}
+
+func (h *storeHandler) ReadMany(ctx context.Context, in *ReadManyRequest, out *ReadManyResponse) error {
+	return h.StoreHandler.ReadMany(ctx, in, out)
+}
+
 // Api Endpoints for BlobStore service
 
 func NewBlobStoreEndpoints() []*api.Endpoint {
@@ -265,6 +299,7 @@ type BlobStoreService interface {
 	Write(ctx context.Context, opts ...client.CallOption) (BlobStore_WriteService, error)
 	Delete(ctx context.Context, in *BlobDeleteRequest, opts ...client.CallOption) (*BlobDeleteResponse, error)
 	List(ctx context.Context, in *BlobListRequest, opts ...client.CallOption) (*BlobListResponse, error)
+	Sign(ctx context.Context, in *BlobSignRequest, opts ...client.CallOption) (*BlobSignResponse, error)
 }
 
 type blobStoreService struct {
@@ -394,6 +429,16 @@ func (c *blobStoreService) List(ctx context.Context, in *BlobListRequest, opts .
 	return out, nil
 }
 
+func (c *blobStoreService) Sign(ctx context.Context, in *BlobSignRequest, opts ...client.CallOption) (*BlobSignResponse, error) {
+	req := c.c.NewRequest(c.name, "BlobStore.Sign", in)
+	out := new(BlobSignResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for BlobStore service
 
 type BlobStoreHandler interface {
@@ -401,6 +446,7 @@ type BlobStoreHandler interface {
 	Write(context.Context, BlobStore_WriteStream) error
 	Delete(context.Context, *BlobDeleteRequest, *BlobDeleteResponse) error
 	List(context.Context, *BlobListRequest, *BlobListResponse) error
+	Sign(context.Context, *BlobSignRequest, *BlobSignResponse) error
 }
 
 func RegisterBlobStoreHandler(s server.Server, hdlr BlobStoreHandler, opts ...server.HandlerOption) error {
@@ -409,6 +455,7 @@ func RegisterBlobStoreHandler(s server.Server, hdlr BlobStoreHandler, opts ...se
 		Write(ctx context.Context, stream server.Stream) error
 		Delete(ctx context.Context, in *BlobDeleteRequest, out *BlobDeleteResponse) error
 		List(ctx context.Context, in *BlobListRequest, out *BlobListResponse) error
+		Sign(ctx context.Context, in *BlobSignRequest, out *BlobSignResponse) error
 	}
 	type BlobStore struct {
 		blobStore
@@ -511,3 +558,7 @@ func (h *blobStoreHandler) Delete(ctx context.Context, in *BlobDeleteRequest, ou
 func (h *blobStoreHandler) List(ctx context.Context, in *BlobListRequest, out *BlobListResponse) error {
 	return h.BlobStoreHandler.List(ctx, in, out)
 }
+
+func (h *blobStoreHandler) Sign(ctx context.Context, in *BlobSignRequest, out *BlobSignResponse) error {
+	return h.BlobStoreHandler.Sign(ctx, in, out)
+}
@@ -0,0 +1,507 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: transfer/transfer.proto
+
+package transfer
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Chunk struct {
+	// name of the blob
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// offset of the data within the blob
+	Offset int64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// data of the chunk
+	Data []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	// crc32 (castagnoli) checksum of the data
+	Checksum uint32 `protobuf:"varint,4,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	// size of the blob, if known
+	Size                 int64    `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Chunk) Reset()         { *m = Chunk{} }
+func (m *Chunk) String() string { return proto.CompactTextString(m) }
+func (*Chunk) ProtoMessage()    {}
+func (*Chunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44038b0c710d7f2f, []int{0}
+}
+
+func (m *Chunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Chunk.Unmarshal(m, b)
+}
+func (m *Chunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Chunk.Marshal(b, m, deterministic)
+}
+func (m *Chunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Chunk.Merge(m, src)
+}
+func (m *Chunk) XXX_Size() int {
+	return xxx_messageInfo_Chunk.Size(m)
+}
+func (m *Chunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_Chunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Chunk proto.InternalMessageInfo
+
+func (m *Chunk) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Chunk) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *Chunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *Chunk) GetChecksum() uint32 {
+	if m != nil {
+		return m.Checksum
+	}
+	return 0
+}
+
+func (m *Chunk) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+type DownloadRequest struct {
+	// name of the blob
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// offset to start from, e.g. to resume a download
+	Offset               int64    `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DownloadRequest) Reset()         { *m = DownloadRequest{} }
+func (m *DownloadRequest) String() string { return proto.CompactTextString(m) }
+func (*DownloadRequest) ProtoMessage()    {}
+func (*DownloadRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44038b0c710d7f2f, []int{1}
+}
+
+func (m *DownloadRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DownloadRequest.Unmarshal(m, b)
+}
+func (m *DownloadRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DownloadRequest.Marshal(b, m, deterministic)
+}
+func (m *DownloadRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DownloadRequest.Merge(m, src)
+}
+func (m *DownloadRequest) XXX_Size() int {
+	return xxx_messageInfo_DownloadRequest.Size(m)
+}
+func (m *DownloadRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DownloadRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DownloadRequest proto.InternalMessageInfo
+
+func (m *DownloadRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *DownloadRequest) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type UploadResponse struct {
+	// number of bytes stored of the blob
+	Size                 int64    `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UploadResponse) Reset()         { *m = UploadResponse{} }
+func (m *UploadResponse) String() string { return proto.CompactTextString(m) }
+func (*UploadResponse) ProtoMessage()    {}
+func (*UploadResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44038b0c710d7f2f, []int{2}
+}
+
+func (m *UploadResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UploadResponse.Unmarshal(m, b)
+}
+func (m *UploadResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UploadResponse.Marshal(b, m, deterministic)
+}
+func (m *UploadResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UploadResponse.Merge(m, src)
+}
+func (m *UploadResponse) XXX_Size() int {
+	return xxx_messageInfo_UploadResponse.Size(m)
+}
+func (m *UploadResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UploadResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UploadResponse proto.InternalMessageInfo
+
+func (m *UploadResponse) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+type StatRequest struct {
+	// name of the blob
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatRequest) Reset()         { *m = StatRequest{} }
+func (m *StatRequest) String() string { return proto.CompactTextString(m) }
+func (*StatRequest) ProtoMessage()    {}
+func (*StatRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44038b0c710d7f2f, []int{3}
+}
+
+func (m *StatRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatRequest.Unmarshal(m, b)
+}
+func (m *StatRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatRequest.Marshal(b, m, deterministic)
+}
+func (m *StatRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatRequest.Merge(m, src)
+}
+func (m *StatRequest) XXX_Size() int {
+	return xxx_messageInfo_StatRequest.Size(m)
+}
+func (m *StatRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatRequest proto.InternalMessageInfo
+
+func (m *StatRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type StatResponse struct {
+	// number of bytes stored of the blob
+	Size                 int64    `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatResponse) Reset()         { *m = StatResponse{} }
+func (m *StatResponse) String() string { return proto.CompactTextString(m) }
+func (*StatResponse) ProtoMessage()    {}
+func (*StatResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44038b0c710d7f2f, []int{4}
+}
+
+func (m *StatResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatResponse.Unmarshal(m, b)
+}
+func (m *StatResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatResponse.Marshal(b, m, deterministic)
+}
+func (m *StatResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatResponse.Merge(m, src)
+}
+func (m *StatResponse) XXX_Size() int {
+	return xxx_messageInfo_StatResponse.Size(m)
+}
+func (m *StatResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatResponse proto.InternalMessageInfo
+
+func (m *StatResponse) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*Chunk)(nil), "transfer.Chunk")
+	proto.RegisterType((*DownloadRequest)(nil), "transfer.DownloadRequest")
+	proto.RegisterType((*UploadResponse)(nil), "transfer.UploadResponse")
+	proto.RegisterType((*StatRequest)(nil), "transfer.StatRequest")
+	proto.RegisterType((*StatResponse)(nil), "transfer.StatResponse")
+}
+
+func init() { proto.RegisterFile("transfer/transfer.proto", fileDescriptor_44038b0c710d7f2f) }
+
+var fileDescriptor_44038b0c710d7f2f = []byte{
+	// 297 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0x4f, 0x4f, 0xbc, 0x30,
+	0x10, 0xdd, 0xfe, 0x96, 0x25, 0xfc, 0xc6, 0xd5, 0x4d, 0x9a, 0xb8, 0x56, 0x4e, 0xd8, 0x78, 0xe0,
+	0x04, 0x2a, 0x07, 0x13, 0x8d, 0x17, 0xf5, 0x13, 0xa0, 0x5e, 0xbc, 0x75, 0xd9, 0x22, 0x64, 0x85,
+	0x22, 0x2d, 0x6e, 0xe2, 0x27, 0xf3, 0xe3, 0x19, 0xca, 0xbf, 0x95, 0x44, 0x13, 0x2f, 0xcd, 0x9b,
+	0xce, 0x9b, 0x99, 0x37, 0x2f, 0x03, 0x47, 0xaa, 0x64, 0xb9, 0x8c, 0x79, 0xe9, 0x77, 0xc0, 0x2b,
+	0x4a, 0xa1, 0x04, 0xb6, 0xba, 0x98, 0x6e, 0x61, 0x76, 0x97, 0x54, 0xf9, 0x06, 0x63, 0x30, 0x72,
+	0x96, 0x71, 0x82, 0x1c, 0xe4, 0xfe, 0x0f, 0x35, 0xc6, 0x4b, 0x30, 0x45, 0x1c, 0x4b, 0xae, 0xc8,
+	0x3f, 0x07, 0xb9, 0xd3, 0xb0, 0x8d, 0x6a, 0xee, 0x9a, 0x29, 0x46, 0xa6, 0x0e, 0x72, 0xe7, 0xa1,
+	0xc6, 0xd8, 0x06, 0x2b, 0x4a, 0x78, 0xb4, 0x91, 0x55, 0x46, 0x0c, 0x07, 0xb9, 0xfb, 0x61, 0x1f,
+	0xd7, 0x7c, 0x99, 0x7e, 0x70, 0x32, 0xd3, 0x5d, 0x34, 0xa6, 0x37, 0xb0, 0xb8, 0x17, 0xdb, 0xfc,
+	0x55, 0xb0, 0x75, 0xc8, 0xdf, 0x2a, 0x2e, 0xd5, 0x5f, 0x24, 0xd0, 0x53, 0x38, 0x78, 0x2a, 0x9a,
+	0x62, 0x59, 0x88, 0x5c, 0xf2, 0x7e, 0x08, 0xda, 0x19, 0x72, 0x02, 0x7b, 0x0f, 0x8a, 0xa9, 0x5f,
+	0x06, 0x50, 0x0a, 0xf3, 0x86, 0xf2, 0x73, 0x9b, 0x8b, 0x4f, 0x04, 0xd6, 0x63, 0xeb, 0x18, 0xbe,
+	0x02, 0xab, 0x13, 0x8e, 0x8f, 0xbd, 0xde, 0xd8, 0xd1, 0x32, 0xf6, 0x62, 0x48, 0x69, 0x83, 0xe9,
+	0xe4, 0x0c, 0xe1, 0x4b, 0x30, 0x1b, 0xd5, 0x78, 0x9c, 0xb6, 0xc9, 0xf0, 0xf1, 0x7d, 0x31, 0x3a,
+	0x71, 0xeb, 0x42, 0xa3, 0x56, 0x89, 0x0f, 0x07, 0xd6, 0xce, 0x62, 0xf6, 0x72, 0xfc, 0xdd, 0x95,
+	0xde, 0x06, 0xcf, 0xe7, 0x2f, 0xa9, 0x4a, 0xaa, 0x95, 0x17, 0x89, 0xcc, 0xcf, 0xd2, 0xa8, 0x14,
+	0xed, 0xfb, 0x1e, 0xf8, 0xfa, 0x18, 0xfa, 0xdb, 0xb8, 0xee, 0xc0, 0xca, 0xd4, 0x89, 0xe0, 0x6b,
+	0x00, 0xe7, 0x1b, 0x0e, 0xf8, 0x40, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// TransferClient is the client API for Transfer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type TransferClient interface {
+	// Download streams the chunks of a blob from an offset
+	Download(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (Transfer_DownloadClient, error)
+	// Upload streams the chunks of a blob, starting at the offset returned by Stat to resume
+	Upload(ctx context.Context, opts ...grpc.CallOption) (Transfer_UploadClient, error)
+	// Stat returns the number of bytes stored of a blob
+	Stat(ctx context.Context, in *StatRequest, opts ...grpc.CallOption) (*StatResponse, error)
+}
+
+type transferClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewTransferClient(cc *grpc.ClientConn) TransferClient {
+	return &transferClient{cc}
+}
+
+func (c *transferClient) Download(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (Transfer_DownloadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Transfer_serviceDesc.Streams[0], "/transfer.Transfer/Download", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &transferDownloadClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Transfer_DownloadClient interface {
+	Recv() (*Chunk, error)
+	grpc.ClientStream
+}
+
+type transferDownloadClient struct {
+	grpc.ClientStream
+}
+
+func (x *transferDownloadClient) Recv() (*Chunk, error) {
+	m := new(Chunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *transferClient) Upload(ctx context.Context, opts ...grpc.CallOption) (Transfer_UploadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Transfer_serviceDesc.Streams[1], "/transfer.Transfer/Upload", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &transferUploadClient{stream}
+	return x, nil
+}
+
+type Transfer_UploadClient interface {
+	Send(*Chunk) error
+	CloseAndRecv() (*UploadResponse, error)
+	grpc.ClientStream
+}
+
+type transferUploadClient struct {
+	grpc.ClientStream
+}
+
+func (x *transferUploadClient) Send(m *Chunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *transferUploadClient) CloseAndRecv() (*UploadResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *transferClient) Stat(ctx context.Context, in *StatRequest, opts ...grpc.CallOption) (*StatResponse, error) {
+	out := new(StatResponse)
+	err := c.cc.Invoke(ctx, "/transfer.Transfer/Stat", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TransferServer is the server API for Transfer service.
+type TransferServer interface {
+	// Download streams the chunks of a blob from an offset
+	Download(*DownloadRequest, Transfer_DownloadServer) error
+	// Upload streams the chunks of a blob, starting at the offset returned by Stat to resume
+	Upload(Transfer_UploadServer) error
+	// Stat returns the number of bytes stored of a blob
+	Stat(context.Context, *StatRequest) (*StatResponse, error)
+}
+
+func RegisterTransferServer(s *grpc.Server, srv TransferServer) {
+	s.RegisterService(&_Transfer_serviceDesc, srv)
+}
+
+func _Transfer_Download_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TransferServer).Download(m, &transferDownloadServer{stream})
+}
+
+type Transfer_DownloadServer interface {
+	Send(*Chunk) error
+	grpc.ServerStream
+}
+
+type transferDownloadServer struct {
+	grpc.ServerStream
+}
+
+func (x *transferDownloadServer) Send(m *Chunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Transfer_Upload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TransferServer).Upload(&transferUploadServer{stream})
+}
+
+type Transfer_UploadServer interface {
+	SendAndClose(*UploadResponse) error
+	Recv() (*Chunk, error)
+	grpc.ServerStream
+}
+
+type transferUploadServer struct {
+	grpc.ServerStream
+}
+
+func (x *transferUploadServer) SendAndClose(m *UploadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *transferUploadServer) Recv() (*Chunk, error) {
+	m := new(Chunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Transfer_Stat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransferServer).Stat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/transfer.Transfer/Stat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransferServer).Stat(ctx, req.(*StatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Transfer_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "transfer.Transfer",
+	HandlerType: (*TransferServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Stat",
+			Handler:    _Transfer_Stat_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Download",
+			Handler:       _Transfer_Download_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Upload",
+			Handler:       _Transfer_Upload_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "transfer/transfer.proto",
+}
@@ -0,0 +1,283 @@
+// Code generated by protoc-gen-micro. DO NOT EDIT.
+// source: transfer/transfer.proto
+
+package transfer
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+import (
+	context "context"
+	api "github.com/micro/micro/v3/service/api"
+	client "github.com/micro/micro/v3/service/client"
+	server "github.com/micro/micro/v3/service/server"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ api.Endpoint
+var _ context.Context
+var _ client.Option
+var _ server.Option
+
+// Api Endpoints for Transfer service
+
+func NewTransferEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for Transfer service
+
+type TransferService interface {
+	// Download streams the chunks of a blob from an offset
+	Download(ctx context.Context, in *DownloadRequest, opts ...client.CallOption) (Transfer_DownloadService, error)
+	// Upload streams the chunks of a blob, starting at the offset returned by Stat to resume
+	Upload(ctx context.Context, opts ...client.CallOption) (Transfer_UploadService, error)
+	// Stat returns the number of bytes stored of a blob
+	Stat(ctx context.Context, in *StatRequest, opts ...client.CallOption) (*StatResponse, error)
+}
+
+type transferService struct {
+	c    client.Client
+	name string
+}
+
+func NewTransferService(name string, c client.Client) TransferService {
+	return &transferService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *transferService) Download(ctx context.Context, in *DownloadRequest, opts ...client.CallOption) (Transfer_DownloadService, error) {
+	req := c.c.NewRequest(c.name, "Transfer.Download", &DownloadRequest{})
+	stream, err := c.c.Stream(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(in); err != nil {
+		return nil, err
+	}
+	return &transferServiceDownload{stream}, nil
+}
+
+type Transfer_DownloadService interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Recv() (*Chunk, error)
+}
+
+type transferServiceDownload struct {
+	stream client.Stream
+}
+
+func (x *transferServiceDownload) Close() error {
+	return x.stream.Close()
+}
+
+func (x *transferServiceDownload) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *transferServiceDownload) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *transferServiceDownload) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *transferServiceDownload) Recv() (*Chunk, error) {
+	m := new(Chunk)
+	err := x.stream.Recv(m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *transferService) Upload(ctx context.Context, opts ...client.CallOption) (Transfer_UploadService, error) {
+	req := c.c.NewRequest(c.name, "Transfer.Upload", &Chunk{})
+	stream, err := c.c.Stream(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &transferServiceUpload{stream}, nil
+}
+
+type Transfer_UploadService interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	CloseAndRecv() (*UploadResponse, error)
+	Send(*Chunk) error
+}
+
+type transferServiceUpload struct {
+	stream client.Stream
+}
+
+func (x *transferServiceUpload) CloseAndRecv() (*UploadResponse, error) {
+	if err := x.stream.Close(); err != nil {
+		return nil, err
+	}
+	r := new(UploadResponse)
+	err := x.RecvMsg(r)
+	return r, err
+}
+
+func (x *transferServiceUpload) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *transferServiceUpload) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *transferServiceUpload) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *transferServiceUpload) Send(m *Chunk) error {
+	return x.stream.Send(m)
+}
+
+func (c *transferService) Stat(ctx context.Context, in *StatRequest, opts ...client.CallOption) (*StatResponse, error) {
+	req := c.c.NewRequest(c.name, "Transfer.Stat", in)
+	out := new(StatResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Transfer service
+
+type TransferHandler interface {
+	// Download streams the chunks of a blob from an offset
+	Download(context.Context, *DownloadRequest, Transfer_DownloadStream) error
+	// Upload streams the chunks of a blob, starting at the offset returned by Stat to resume
+	Upload(context.Context, Transfer_UploadStream) error
+	// Stat returns the number of bytes stored of a blob
+	Stat(context.Context, *StatRequest, *StatResponse) error
+}
+
+func RegisterTransferHandler(s server.Server, hdlr TransferHandler, opts ...server.HandlerOption) error {
+	type transfer interface {
+		Download(ctx context.Context, stream server.Stream) error
+		Upload(ctx context.Context, stream server.Stream) error
+		Stat(ctx context.Context, in *StatRequest, out *StatResponse) error
+	}
+	type Transfer struct {
+		transfer
+	}
+	h := &transferHandler{hdlr}
+	return s.Handle(s.NewHandler(&Transfer{h}, opts...))
+}
+
+type transferHandler struct {
+	TransferHandler
+}
+
+func (h *transferHandler) Download(ctx context.Context, stream server.Stream) error {
+	m := new(DownloadRequest)
+	if err := stream.Recv(m); err != nil {
+		return err
+	}
+	return h.TransferHandler.Download(ctx, m, &transferDownloadStream{stream})
+}
+
+type Transfer_DownloadStream interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Send(*Chunk) error
+}
+
+type transferDownloadStream struct {
+	stream server.Stream
+}
+
+func (x *transferDownloadStream) Close() error {
+	return x.stream.Close()
+}
+
+func (x *transferDownloadStream) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *transferDownloadStream) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *transferDownloadStream) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *transferDownloadStream) Send(m *Chunk) error {
+	return x.stream.Send(m)
+}
+
+func (h *transferHandler) Upload(ctx context.Context, stream server.Stream) error {
+	return h.TransferHandler.Upload(ctx, &transferUploadStream{stream})
+}
+
+type Transfer_UploadStream interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	SendAndClose(*UploadResponse) error
+	Recv() (*Chunk, error)
+}
+
+type transferUploadStream struct {
+	stream server.Stream
+}
+
+func (x *transferUploadStream) SendAndClose(in *UploadResponse) error {
+	if err := x.SendMsg(in); err != nil {
+		return err
+	}
+	return x.stream.Close()
+}
+
+func (x *transferUploadStream) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *transferUploadStream) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *transferUploadStream) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *transferUploadStream) Recv() (*Chunk, error) {
+	m := new(Chunk)
+	if err := x.stream.Recv(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (h *transferHandler) Stat(ctx context.Context, in *StatRequest, out *StatResponse) error {
+	return h.TransferHandler.Stat(ctx, in, out)
+}
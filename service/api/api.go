@@ -22,6 +22,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 
 	jsonpatch "github.com/evanphx/json-patch/v5"
@@ -43,6 +44,20 @@ var (
 	bufferPool = bpool.NewSizedBufferPool(1024, 8)
 )
 
+const (
+	// StreamModeNDJSON writes each message of a stream as a line of JSON
+	StreamModeNDJSON = "ndjson"
+	// StreamModeLongPoll buffers the messages of a stream at the gateway so clients which
+	// can't hold a connection open can fetch them by polling
+	StreamModeLongPoll = "longpoll"
+	// StreamModeWebSocket only serves the stream to clients which upgrade to a websocket, the
+	// frames sent by the client are sent to the stream and the messages it returns are sent back
+	StreamModeWebSocket = "websocket"
+	// StreamModeSSE writes each message of a stream as a server-sent event so browsers can
+	// consume it with an EventSource
+	StreamModeSSE = "sse"
+)
+
 type buffer struct {
 	io.ReadCloser
 }
@@ -156,6 +171,14 @@ type Endpoint struct {
 	Body string
 	// Stream flag
 	Stream bool
+	// StreamMode used to serve stream responses to http clients
+	// "" - the raw messages are written as they're received
+	// "ndjson" - newline delimited JSON
+	// "longpoll" - messages are buffered and fetched by polling
+	StreamMode string
+	// Strict rejects JSON requests with fields which aren't fields of the request, or fields of the
+	// wrong type, rather than the service dropping them
+	Strict bool
 }
 
 // Service represents an API service
@@ -191,6 +214,10 @@ func Encode(e *Endpoint) map[string]string {
 	set("method", strings.Join(e.Method, ","))
 	set("path", strings.Join(e.Path, ","))
 	set("host", strings.Join(e.Host, ","))
+	set("stream_mode", e.StreamMode)
+	if e.Strict {
+		set("strict", "true")
+	}
 
 	return ep
 }
@@ -208,6 +235,8 @@ func Decode(e map[string]string) *Endpoint {
 		Path:        slice(e["path"]),
 		Host:        slice(e["host"]),
 		Handler:     e["handler"],
+		StreamMode:  e["stream_mode"],
+		Strict:      e["strict"] == "true",
 	}
 }
 
@@ -245,7 +274,36 @@ func Validate(e *Endpoint) error {
 }
 
 func WithEndpoint(e *Endpoint) server.HandlerOption {
-	return server.EndpointMetadata(e.Name, Encode(e))
+	return endpointMetadata(e.Name, Encode(e))
+}
+
+// Strict rejects JSON requests to the endpoint, e.g. Greeter.Hello, with fields which aren't fields
+// of the request or are of the wrong type, at the api. Use it for endpoints which aren't mapped to
+// http with WithEndpoint, otherwise set Endpoint.Strict.
+func Strict(name string) server.HandlerOption {
+	return endpointMetadata(name, map[string]string{"strict": "true"})
+}
+
+// Read registers the endpoint, e.g. Users.Lookup, as a read if read is true or a write otherwise.
+// Reads are still served while the namespace is in maintenance, endpoints which aren't registered
+// are reads if their method starts with a read prefix such as List.
+func Read(name string, read bool) server.HandlerOption {
+	return endpointMetadata(name, map[string]string{"read": strconv.FormatBool(read)})
+}
+
+// endpointMetadata merges the metadata into the metadata of the endpoint, so the options of an
+// endpoint can be combined
+func endpointMetadata(name string, md map[string]string) server.HandlerOption {
+	return func(o *server.HandlerOptions) {
+		merged := make(map[string]string, len(o.Metadata[name])+len(md))
+		for k, v := range o.Metadata[name] {
+			merged[k] = v
+		}
+		for k, v := range md {
+			merged[k] = v
+		}
+		server.EndpointMetadata(name, merged)(o)
+	}
 }
 
 func slice(s string) []string {
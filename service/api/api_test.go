@@ -24,6 +24,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	go_api "github.com/micro/micro/v3/proto/api"
 	"github.com/micro/micro/v3/service/api"
+	"github.com/micro/micro/v3/service/server"
 )
 
 func TestEncoding(t *testing.T) {
@@ -37,6 +38,22 @@ func TestEncoding(t *testing.T) {
 			Method:      []string{"GET"},
 			Path:        []string{"/test"},
 		},
+		{
+			Name:       "Foo.Stream",
+			Handler:    "rpc",
+			Host:       []string{"foo.com"},
+			Method:     []string{"POST"},
+			Path:       []string{"/stream"},
+			StreamMode: api.StreamModeNDJSON,
+		},
+		{
+			Name:    "Foo.Create",
+			Handler: "rpc",
+			Host:    []string{"foo.com"},
+			Method:  []string{"POST"},
+			Path:    []string{"/create"},
+			Strict:  true,
+		},
 	}
 
 	compare := func(expect, got []string) bool {
@@ -130,6 +147,27 @@ func TestEncoding(t *testing.T) {
 		if ok := compare(d.Host, de.Host); !ok {
 			t.Fatalf("expected %v got %v", d.Host, de.Host)
 		}
+		if de.StreamMode != d.StreamMode {
+			t.Fatalf("expected %v got %v", d.StreamMode, de.StreamMode)
+		}
+		if de.Strict != d.Strict {
+			t.Fatalf("expected %v got %v", d.Strict, de.Strict)
+		}
+	}
+}
+
+func TestStrict(t *testing.T) {
+	opts := server.HandlerOptions{Metadata: map[string]map[string]string{}}
+	api.WithEndpoint(&api.Endpoint{Name: "Foo.Bar", Handler: "rpc", Path: []string{"/bar"}})(&opts)
+	api.Strict("Foo.Bar")(&opts)
+
+	// the options of the endpoint are merged
+	md := opts.Metadata["Foo.Bar"]
+	if md["path"] != "/bar" || md["strict"] != "true" {
+		t.Fatalf("expected the path and strict to be set, got %v", md)
+	}
+	if e := api.Decode(md); !e.Strict {
+		t.Fatalf("expected the endpoint to be strict")
 	}
 }
 
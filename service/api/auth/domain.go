@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/store"
+)
+
+var (
+	// DefaultDomains is used by the wrapper to route requests for custom domains
+	DefaultDomains DomainList = &StoreDomainList{cache: map[string]*cachedDomain{}}
+
+	// ErrUnknownDomain is returned by HostPolicy for hosts which aren't custom domains
+	ErrUnknownDomain = errors.New("unknown domain")
+
+	// domainCacheTTL is how long the lookup of a host is cached for, the api takes this long
+	// to start or stop routing a domain after it's added or removed
+	domainCacheTTL = time.Second * 30
+)
+
+const (
+	domainTable  = "api"
+	domainPrefix = "domain/"
+
+	// maxCachedDomains bounds the cache since the host is set by the caller
+	maxCachedDomains = 10000
+)
+
+// Domain is a custom hostname for a namespace. Requests to the host are routed to the services
+// in the namespace regardless of the namespace header.
+type Domain struct {
+	Host      string    `json:"host"`
+	Namespace string    `json:"namespace"`
+	Created   time.Time `json:"created"`
+}
+
+// DomainList manages the custom domains
+type DomainList interface {
+	// Get returns the domain for a host, or nil if it isn't a custom domain
+	Get(ctx context.Context, host string) (*Domain, error)
+	// Add a domain, replacing the namespace if the host already exists
+	Add(ctx context.Context, d *Domain) error
+	// Remove the domain for a host
+	Remove(ctx context.Context, host string) error
+	// List the domains
+	List(ctx context.Context) ([]*Domain, error)
+}
+
+// NormalizeHost lower cases the host and removes the port, e.g. API.example.com:443 becomes
+// api.example.com
+func NormalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// HostPolicy is an acme host policy which allows certificates to be issued for custom domains
+func HostPolicy(ctx context.Context, host string) error {
+	d, err := DefaultDomains.Get(ctx, host)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return ErrUnknownDomain
+	}
+	return nil
+}
+
+type cachedDomain struct {
+	domain *Domain
+	expiry time.Time
+}
+
+// StoreDomainList is an implementation of DomainList which keeps the domains in the store so
+// they're shared by every instance of the api. Lookups are cached since they're made on every
+// request.
+type StoreDomainList struct {
+	sync.RWMutex
+	cache map[string]*cachedDomain
+}
+
+func (s *StoreDomainList) Get(ctx context.Context, host string) (*Domain, error) {
+	host = NormalizeHost(host)
+	if len(host) == 0 {
+		return nil, nil
+	}
+
+	s.RLock()
+	c, ok := s.cache[host]
+	s.RUnlock()
+	if ok && time.Now().Before(c.expiry) {
+		return c.domain, nil
+	}
+
+	var d *Domain
+	recs, err := store.Read(domainPrefix+host, store.ReadFrom("micro", domainTable))
+	if err == nil {
+		d = &Domain{}
+		if err := json.Unmarshal(recs[0].Value, d); err != nil {
+			return nil, err
+		}
+	} else if err != store.ErrNotFound {
+		return nil, err
+	}
+
+	s.Lock()
+	if len(s.cache) >= maxCachedDomains {
+		s.cache = map[string]*cachedDomain{}
+	}
+	s.cache[host] = &cachedDomain{domain: d, expiry: time.Now().Add(domainCacheTTL)}
+	s.Unlock()
+	return d, nil
+}
+
+func (s *StoreDomainList) Add(ctx context.Context, d *Domain) error {
+	d.Host = NormalizeHost(d.Host)
+	b, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	rec := &store.Record{Key: domainPrefix + d.Host, Value: b}
+	if err := store.DefaultStore.Write(rec, store.WriteTo("micro", domainTable)); err != nil {
+		return err
+	}
+
+	s.Lock()
+	delete(s.cache, d.Host)
+	s.Unlock()
+	return nil
+}
+
+func (s *StoreDomainList) Remove(ctx context.Context, host string) error {
+	host = NormalizeHost(host)
+	err := store.DefaultStore.Delete(domainPrefix+host, store.DeleteFrom("micro", domainTable))
+	if err != nil && err != store.ErrNotFound {
+		return err
+	}
+
+	s.Lock()
+	delete(s.cache, host)
+	s.Unlock()
+	return nil
+}
+
+func (s *StoreDomainList) List(ctx context.Context) ([]*Domain, error) {
+	recs, err := store.Read(domainPrefix, store.ReadPrefix(), store.ReadFrom("micro", domainTable))
+	if err != nil && err != store.ErrNotFound {
+		return nil, err
+	}
+
+	res := make([]*Domain, 0, len(recs))
+	for _, r := range recs {
+		d := &Domain{}
+		if err := json.Unmarshal(r.Value, d); err != nil {
+			return nil, err
+		}
+		res = append(res, d)
+	}
+	return res, nil
+}
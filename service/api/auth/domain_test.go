@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeHost(t *testing.T) {
+	assert.Equal(t, "api.example.com", NormalizeHost("API.example.com:443"))
+	assert.Equal(t, "api.example.com", NormalizeHost("api.example.com."))
+	assert.Equal(t, "::1", NormalizeHost("[::1]:8080"))
+}
+
+func TestStoreDomainList(t *testing.T) {
+	store.DefaultStore = memory.NewStore()
+	l := &StoreDomainList{cache: map[string]*cachedDomain{}}
+	ctx := context.Background()
+
+	d, err := l.Get(ctx, "api.customer.com")
+	assert.Nil(t, err)
+	assert.Nil(t, d)
+
+	assert.Nil(t, l.Add(ctx, &Domain{Host: "API.customer.com", Namespace: "customer"}))
+	d, err = l.Get(ctx, "api.customer.com:443")
+	assert.Nil(t, err)
+	if assert.NotNil(t, d) {
+		assert.Equal(t, "customer", d.Namespace)
+	}
+
+	list, err := l.List(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, list, 1)
+
+	assert.Nil(t, l.Remove(ctx, "api.customer.com"))
+	d, err = l.Get(ctx, "api.customer.com")
+	assert.Nil(t, err)
+	assert.Nil(t, d)
+}
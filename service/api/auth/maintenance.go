@@ -0,0 +1,218 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/api"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/store"
+)
+
+var (
+	// DefaultMaintenance is used by the wrapper to determine if a namespace is in maintenance
+	DefaultMaintenance MaintenanceList = &StoreMaintenanceList{cache: map[string]*cachedMaintenance{}}
+
+	// DefaultReadVerbs are the http methods treated as reads when maintenance doesn't set them
+	DefaultReadVerbs = []string{"GET", "HEAD", "OPTIONS"}
+	// DefaultReadPrefixes are the prefixes of the endpoint methods treated as reads when
+	// maintenance doesn't set them, e.g. /users/list or Users.List
+	DefaultReadPrefixes = []string{"Read", "List", "Get", "Search", "Find", "Count", "Watch"}
+	// DefaultRetryAfter is returned when maintenance doesn't set a retry after
+	DefaultRetryAfter = time.Minute * 5
+
+	// maintenanceCacheTTL is how long the maintenance of a namespace is cached for, the api and
+	// web take this long to start or stop rejecting requests after maintenance changes
+	maintenanceCacheTTL = time.Second * 10
+)
+
+const (
+	maintenanceTable  = "api"
+	maintenancePrefix = "maintenance/"
+)
+
+// Maintenance of a namespace. While a namespace is in maintenance requests which aren't reads
+// are rejected.
+type Maintenance struct {
+	Namespace    string        `json:"namespace"`
+	Message      string        `json:"message"`
+	RetryAfter   time.Duration `json:"retry_after"`
+	ReadVerbs    []string      `json:"read_verbs"`
+	ReadPrefixes []string      `json:"read_prefixes"`
+	Created      time.Time     `json:"created"`
+}
+
+// IsRead returns true if the request to the endpoint path is a read. Endpoints the service
+// registered as reads or writes with api.Read are read according to that. The rpc handlers serve
+// other endpoints the same whatever the http method, so they're reads if their method starts with
+// one of the read prefixes. Requests to services without registered endpoints, e.g. web services,
+// are reads if their method is one of the read verbs.
+func (m *Maintenance) IsRead(req *http.Request, endpointPath string, eps []*registry.Endpoint) bool {
+	// the endpoint method is the last part of the path, e.g. /users/list or /users.Users/List
+	method := path.Base(endpointPath)
+	if idx := strings.LastIndex(method, "."); idx >= 0 {
+		method = method[idx+1:]
+	}
+	method = strings.ToLower(method)
+
+	if len(eps) == 0 {
+		verbs := m.ReadVerbs
+		if len(verbs) == 0 {
+			verbs = DefaultReadVerbs
+		}
+		for _, v := range verbs {
+			if strings.EqualFold(v, req.Method) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, ep := range eps {
+		if read, ok := ep.Metadata["read"]; ok && matchEndpoint(ep, endpointPath, method) {
+			return read == "true"
+		}
+	}
+
+	prefixes := m.ReadPrefixes
+	if len(prefixes) == 0 {
+		prefixes = DefaultReadPrefixes
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(method, strings.ToLower(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchEndpoint returns true if the registered endpoint serves the path, either because it's
+// mapped to the path or its method is the endpoint method
+func matchEndpoint(ep *registry.Endpoint, endpointPath, method string) bool {
+	if e := api.Decode(ep.Metadata); e != nil {
+		for _, p := range e.Path {
+			if p == endpointPath {
+				return true
+			}
+		}
+	}
+	name := ep.Name
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		name = name[idx+1:]
+	}
+	return strings.ToLower(name) == method
+}
+
+// isAdmin returns true if the account is an admin
+func isAdmin(acc *auth.Account) bool {
+	if acc == nil {
+		return false
+	}
+	for _, s := range acc.Scopes {
+		if s == "admin" {
+			return true
+		}
+	}
+	return false
+}
+
+// MaintenanceList manages the namespaces in maintenance
+type MaintenanceList interface {
+	// Get returns the maintenance of a namespace, or nil if it's not in maintenance
+	Get(ctx context.Context, namespace string) (*Maintenance, error)
+	// Enable maintenance for a namespace
+	Enable(ctx context.Context, m *Maintenance) error
+	// Disable maintenance for a namespace
+	Disable(ctx context.Context, namespace string) error
+	// List the namespaces in maintenance
+	List(ctx context.Context) ([]*Maintenance, error)
+}
+
+type cachedMaintenance struct {
+	maintenance *Maintenance
+	expiry      time.Time
+}
+
+// StoreMaintenanceList is an implementation of MaintenanceList which keeps the list in the store
+// so it's shared by every instance of the api and web. Lookups are cached briefly since they're
+// made on every request.
+type StoreMaintenanceList struct {
+	sync.RWMutex
+	cache map[string]*cachedMaintenance
+}
+
+func (s *StoreMaintenanceList) Get(ctx context.Context, namespace string) (*Maintenance, error) {
+	s.RLock()
+	c, ok := s.cache[namespace]
+	s.RUnlock()
+	if ok && time.Now().Before(c.expiry) {
+		return c.maintenance, nil
+	}
+
+	var m *Maintenance
+	recs, err := store.Read(maintenancePrefix+namespace, store.ReadFrom("micro", maintenanceTable))
+	if err == nil {
+		m = &Maintenance{}
+		if err := json.Unmarshal(recs[0].Value, m); err != nil {
+			return nil, err
+		}
+	} else if err != store.ErrNotFound {
+		return nil, err
+	}
+
+	s.Lock()
+	s.cache[namespace] = &cachedMaintenance{maintenance: m, expiry: time.Now().Add(maintenanceCacheTTL)}
+	s.Unlock()
+	return m, nil
+}
+
+func (s *StoreMaintenanceList) Enable(ctx context.Context, m *Maintenance) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	rec := &store.Record{Key: maintenancePrefix + m.Namespace, Value: b}
+	if err := store.DefaultStore.Write(rec, store.WriteTo("micro", maintenanceTable)); err != nil {
+		return err
+	}
+
+	s.Lock()
+	delete(s.cache, m.Namespace)
+	s.Unlock()
+	return nil
+}
+
+func (s *StoreMaintenanceList) Disable(ctx context.Context, namespace string) error {
+	err := store.DefaultStore.Delete(maintenancePrefix+namespace, store.DeleteFrom("micro", maintenanceTable))
+	if err != nil && err != store.ErrNotFound {
+		return err
+	}
+
+	s.Lock()
+	delete(s.cache, namespace)
+	s.Unlock()
+	return nil
+}
+
+func (s *StoreMaintenanceList) List(ctx context.Context) ([]*Maintenance, error) {
+	recs, err := store.Read(maintenancePrefix, store.ReadPrefix(), store.ReadFrom("micro", maintenanceTable))
+	if err != nil && err != store.ErrNotFound {
+		return nil, err
+	}
+
+	res := make([]*Maintenance, 0, len(recs))
+	for _, r := range recs {
+		m := &Maintenance{}
+		if err := json.Unmarshal(r.Value, m); err != nil {
+			return nil, err
+		}
+		res = append(res, m)
+	}
+	return res, nil
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceIsRead(t *testing.T) {
+	eps := []*registry.Endpoint{
+		{Name: "Users.Create"},
+		{Name: "Users.Delete"},
+		{Name: "Users.Lookup", Metadata: map[string]string{"read": "true"}},
+		{Name: "Users.ListAndPrune", Metadata: map[string]string{"read": "false"}},
+		{Name: "Users.Profile", Metadata: map[string]string{"endpoint": "Users.Profile", "path": "/profile", "read": "true"}},
+	}
+
+	tcs := []struct {
+		name        string
+		method      string
+		path        string
+		endpoints   []*registry.Endpoint
+		maintenance Maintenance
+		read        bool
+	}{
+		{name: "rpc get", method: "GET", path: "/users/delete", endpoints: eps},
+		{name: "read method", method: "POST", path: "/users/read", endpoints: eps, read: true},
+		{name: "grpc read method", method: "POST", path: "/users.Users/ListUsers", endpoints: eps, read: true},
+		{name: "write method", method: "POST", path: "/users/create", endpoints: eps},
+		{name: "registered read", method: "POST", path: "/users/lookup", endpoints: eps, read: true},
+		{name: "registered write", method: "GET", path: "/users/listAndPrune", endpoints: eps},
+		{name: "registered path", method: "POST", path: "/profile", endpoints: eps, read: true},
+		{name: "http get", method: "GET", path: "/users/delete", read: true},
+		{name: "http delete", method: "DELETE", path: "/users"},
+		{
+			name:        "custom verbs",
+			method:      "GET",
+			path:        "/users/delete",
+			maintenance: Maintenance{ReadVerbs: []string{"HEAD"}},
+		},
+		{
+			name:        "custom prefixes",
+			method:      "POST",
+			path:        "/users/fetch",
+			endpoints:   eps,
+			maintenance: Maintenance{ReadPrefixes: []string{"Fetch"}},
+			read:        true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			assert.Equal(t, tc.read, tc.maintenance.IsRead(req, tc.path, tc.endpoints))
+		})
+	}
+}
+
+func TestStoreMaintenanceList(t *testing.T) {
+	store.DefaultStore = memory.NewStore()
+	l := &StoreMaintenanceList{cache: map[string]*cachedMaintenance{}}
+	ctx := context.Background()
+
+	m, err := l.Get(ctx, "foo")
+	assert.Nil(t, err)
+	assert.Nil(t, m)
+
+	assert.Nil(t, l.Enable(ctx, &Maintenance{Namespace: "foo", Message: "migrating"}))
+	m, err = l.Get(ctx, "foo")
+	assert.Nil(t, err)
+	if assert.NotNil(t, m) {
+		assert.Equal(t, "migrating", m.Message)
+	}
+
+	list, err := l.List(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, list, 1)
+
+	assert.Nil(t, l.Disable(ctx, "foo"))
+	m, err = l.Get(ctx, "foo")
+	assert.Nil(t, err)
+	assert.Nil(t, m)
+}
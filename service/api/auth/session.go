@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/client"
+	inauth "github.com/micro/micro/v3/util/auth"
+)
+
+// resolveSession exchanges the session key for an access token. The auth service refreshes the
+// token as it nears expiry and rejects sessions which have expired or been revoked.
+func resolveSession(ctx context.Context, key, ns string) (*pb.RefreshSessionResponse, error) {
+	req := &pb.RefreshSessionRequest{Key: key, Options: &pb.Options{Namespace: ns}}
+	return pb.NewSessionsService("auth", client.DefaultClient).Refresh(ctx, req, client.WithAuthToken())
+}
+
+// safeMethod returns true if the request method shouldn't change any state
+func safeMethod(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// validCSRF returns true if the request was made with the csrf token of the session. Browsers
+// attach cookies to cross site requests so the session alone doesn't prove the user made the
+// request.
+func validCSRF(req *http.Request, token string) bool {
+	actual := req.Header.Get(inauth.CSRFHeader)
+	return len(token) > 0 && subtle.ConstantTimeCompare([]byte(token), []byte(actual)) == 1
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	inauth "github.com/micro/micro/v3/util/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidCSRF(t *testing.T) {
+	tcs := []struct {
+		name   string
+		method string
+		header string
+		token  string
+		valid  bool
+	}{
+		{name: "safe method", method: "GET", token: "abc"},
+		{name: "safe method with token", method: "GET", header: "abc", token: "abc", valid: true},
+		{name: "matching token", method: "POST", header: "abc", token: "abc", valid: true},
+		{name: "missing token", method: "POST", token: "abc"},
+		{name: "wrong token", method: "DELETE", header: "abd", token: "abc"},
+		{name: "session without token", method: "PUT"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/foo/bar", nil)
+			if len(tc.header) > 0 {
+				req.Header.Set(inauth.CSRFHeader, tc.header)
+			}
+			assert.Equal(t, tc.valid, validCSRF(req, tc.token))
+		})
+	}
+}
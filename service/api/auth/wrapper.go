@@ -5,18 +5,35 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/micro/micro/v3/service/api"
 	"github.com/micro/micro/v3/service/api/resolver"
 	"github.com/micro/micro/v3/service/api/resolver/subdomain"
 	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/errors"
 	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry"
 	inauth "github.com/micro/micro/v3/util/auth"
 	"github.com/micro/micro/v3/util/ctx"
 	"github.com/micro/micro/v3/util/namespace"
 )
 
+// endpoints returns the endpoints registered by the service, the metadata of which determines
+// if they're reads during maintenance
+func endpoints(name, ns string) []*registry.Endpoint {
+	srvs, err := registry.DefaultRegistry.GetService(name, registry.GetDomain(ns))
+	if err != nil {
+		return nil
+	}
+	var eps []*registry.Endpoint
+	for _, s := range srvs {
+		eps = append(eps, s.Endpoints...)
+	}
+	return eps
+}
+
 // Wrapper wraps a handler and authenticates requests
 func Wrapper(r resolver.Resolver, prefix string) api.Wrapper {
 	return func(h http.Handler) http.Handler {
@@ -28,13 +45,36 @@ func Wrapper(r resolver.Resolver, prefix string) api.Wrapper {
 	}
 }
 
+// RPCWrapper wraps a handler which calls services and authenticates requests. The handlers turn
+// the query of a GET into the body of the rpc, so requests made with a session need the csrf token
+// whatever their method.
+func RPCWrapper(r resolver.Resolver, prefix string) api.Wrapper {
+	return func(h http.Handler) http.Handler {
+		return authWrapper{
+			handler:       h,
+			resolver:      r,
+			servicePrefix: prefix,
+			rpc:           true,
+		}
+	}
+}
+
 type authWrapper struct {
 	handler       http.Handler
 	resolver      resolver.Resolver
 	servicePrefix string
+	rpc           bool
 }
 
 func (a authWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// Requests to a custom domain are routed to its namespace, the header can't be used to
+	// reach any other
+	if d, err := DefaultDomains.Get(req.Context(), req.Host); err != nil {
+		logger.Errorf("Error looking up domain %v: %v", req.Host, err)
+	} else if d != nil {
+		req.Header.Set(namespace.NamespaceKey, d.Namespace)
+	}
+
 	// Determine the name of the service being requested
 	endpoint, err := a.resolver.Resolve(req)
 	if err == resolver.ErrInvalidPath || err == resolver.ErrNotFound {
@@ -57,19 +97,54 @@ func (a authWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		endpoint.Domain = r.Domain(req)
 	}
 
-	// Set the metadata so we can access it in micro api / web
-	req = req.WithContext(ctx.FromRequest(req))
+	// Set the metadata so we can access it in micro api / web. The request is updated in place
+	// so the wrappers which ran before this one can read the account once it's been served.
+	*req = *req.WithContext(ctx.FromRequest(req))
 
 	// Extract the token from the request
 	var token string
-	if header := req.Header.Get("Authorization"); len(header) > 0 {
-		// Extract the auth token from the request
+	if key := req.Header.Get(inauth.APIKeyHeader); len(key) > 0 {
+		// Machine clients authenticate with an api key, which is passed on as the token
+		token = key
+		req.Header.Set("Authorization", inauth.BearerScheme+token)
+	} else if header := req.Header.Get("Authorization"); len(header) > 0 {
+		// Extract the auth token from the request, api keys can also be passed as the password of
+		// the basic scheme
 		if strings.HasPrefix(header, inauth.BearerScheme) {
 			token = header[len(inauth.BearerScheme):]
+		} else if _, pass, ok := req.BasicAuth(); ok && inauth.IsAPIKey(pass) {
+			token = pass
+			req.Header.Set("Authorization", inauth.BearerScheme+token)
+		}
+	} else if wsToken := inauth.WebSocketToken(req); len(wsToken) > 0 {
+		// Browsers pass the token of a websocket as a subprotocol since they can't set headers
+		token = wsToken
+		req.Header.Set("Authorization", inauth.BearerScheme+token)
+	} else if c, err := req.Cookie(inauth.SessionCookieName); err == nil && len(c.Value) > 0 {
+		// Exchange the session key for a token, the session belongs to the namespace being requested
+		ns := req.Header.Get(namespace.NamespaceKey)
+		if len(ns) == 0 {
+			ns = endpoint.Domain
+		}
+		sess, err := resolveSession(req.Context(), c.Value, ns)
+		if err != nil {
+			logger.Debugf("Error resolving session: %v", err)
+			if errors.FromError(err).Code == http.StatusUnauthorized {
+				inauth.ClearSessionCookies(w, req.Host)
+			}
+		} else if validCSRF(req, sess.CsrfToken) || (!a.rpc && safeMethod(req)) {
+			token = sess.AccessToken
+			req.Header.Set("Authorization", inauth.BearerScheme+token)
+		} else if !safeMethod(req) {
+			http.Error(w, "invalid csrf token", http.StatusForbidden)
+			return
+		} else {
+			// e.g. a link from another site, the request is made without the session
+			logger.Debugf("Ignoring session of %v request without a csrf token", req.Method)
 		}
 	} else {
 		// Get the token out the cookies if not provided in headers
-		if c, err := req.Cookie("micro-token"); err == nil && c != nil {
+		if c, err := req.Cookie(inauth.TokenCookieName); err == nil && c != nil {
 			token = strings.TrimPrefix(c.Value, inauth.TokenCookieName+"=")
 			req.Header.Set("Authorization", inauth.BearerScheme+token)
 		}
@@ -109,6 +184,24 @@ func (a authWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		acc = nil
 	}
 
+	// Reject requests which aren't reads while the namespace is in maintenance. Admins of the
+	// namespace can still make changes, e.g. to run a migration.
+	if m, err := DefaultMaintenance.Get(req.Context(), ns); err != nil {
+		logger.Errorf("Error reading maintenance of %v: %v", ns, err)
+	} else if m != nil && !isAdmin(acc) && !m.IsRead(req, endpoint.Path, endpoints(endpoint.Name, ns)) {
+		retry := m.RetryAfter
+		if retry == 0 {
+			retry = DefaultRetryAfter
+		}
+		msg := m.Message
+		if len(msg) == 0 {
+			msg = "The namespace is in maintenance, only reads are allowed"
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(int(retry.Seconds())))
+		http.Error(w, msg, http.StatusServiceUnavailable)
+		return
+	}
+
 	// construct the resource name, e.g. home => foo.api.home
 	resName := endpoint.Name
 	if len(a.servicePrefix) > 0 {
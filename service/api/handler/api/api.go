@@ -90,7 +90,7 @@ func (a *apiHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	if err := c.Call(cx, req, rsp, client.WithRouter(router.New(service.Services))); err != nil {
 		w.Header().Set("Content-Type", "application/json")
-		ce := errors.Parse(err.Error())
+		ce := handler.Error(cx, err)
 		switch ce.Code {
 		case 0:
 			w.WriteHeader(500)
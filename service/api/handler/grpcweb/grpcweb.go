@@ -0,0 +1,341 @@
+// Package grpcweb is a handler which serves gRPC-Web requests so browser gRPC clients can call
+// services through the api without a separate proxy
+package grpcweb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/micro/micro/v3/service/api"
+	"github.com/micro/micro/v3/service/api/handler"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/server/grpc"
+	"github.com/micro/micro/v3/util/codec/bytes"
+	"github.com/micro/micro/v3/util/ctx"
+	"github.com/micro/micro/v3/util/router"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	Handler = "grpcweb"
+
+	// ContentType of binary gRPC-Web requests
+	ContentType = "application/grpc-web"
+	// TextContentType of gRPC-Web requests which are base64 encoded, used by clients which can't
+	// stream binary responses
+	TextContentType = "application/grpc-web-text"
+
+	// the flag set on the frame of the trailers
+	trailerFlag = 0x80
+	// the flag set on frames which are compressed
+	compressedFlag = 0x01
+)
+
+// ExposedHeaders are the response headers browsers must expose to gRPC-Web clients
+var ExposedHeaders = []string{"Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin"}
+
+// AllowedHeaders are the request headers gRPC-Web clients send, which must be allowed by CORS
+var AllowedHeaders = []string{"X-Grpc-Web", "X-User-Agent", "Grpc-Timeout"}
+
+type grpcWebHandler struct {
+	opts handler.Options
+}
+
+// IsRequest returns true if the request is a gRPC-Web request
+func IsRequest(r *http.Request) bool {
+	ct := r.Header.Get("Content-Type")
+	return strings.HasPrefix(ct, ContentType)
+}
+
+func (h *grpcWebHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Expose-Headers", strings.Join(ExposedHeaders, ", "))
+
+	if r.Method != "POST" {
+		writeError(w, errors.MethodNotAllowed("go.micro.api", "gRPC-Web requests must be POST requests"))
+		return
+	}
+
+	// the response is encoded in the same way as the request
+	text := strings.HasPrefix(r.Header.Get("Content-Type"), TextContentType)
+	ct := ContentType + "+proto"
+	if text {
+		ct = TextContentType + "+proto"
+	}
+	if t := strings.TrimPrefix(strings.TrimPrefix(r.Header.Get("Content-Type"), TextContentType), ContentType); len(t) > 0 && t != "+proto" {
+		writeError(w, errors.New("go.micro.api", "only proto gRPC-Web requests are supported", http.StatusUnsupportedMediaType))
+		return
+	}
+
+	service, err := h.opts.Router.Route(r)
+	if err != nil {
+		writeError(w, errors.NotFound("go.micro.api", "no service found for %v", r.URL.Path))
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, h.opts.MaxRecvSize))
+	if err != nil {
+		writeError(w, errors.BadRequest("go.micro.api", "error reading the request: %v", err))
+		return
+	}
+	if text {
+		if body, err = decodeText(body); err != nil {
+			writeError(w, errors.BadRequest("go.micro.api", "invalid base64 request: %v", err))
+			return
+		}
+	}
+	msg, err := readMessage(body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	cx := ctx.FromRequest(r)
+	if v := r.Header.Get("Grpc-Timeout"); len(v) > 0 {
+		d, err := parseTimeout(v)
+		if err != nil {
+			writeError(w, errors.BadRequest("go.micro.api", "invalid grpc-timeout %q", v))
+			return
+		}
+		var cancel context.CancelFunc
+		cx, cancel = context.WithTimeout(cx, d)
+		defer cancel()
+	}
+
+	c := h.opts.Client
+	callOpt := client.WithRouter(router.New(service.Services))
+	reqOpts := []client.RequestOption{client.WithContentType("application/grpc+proto")}
+	stream := isStream(service)
+	if stream {
+		reqOpts = append(reqOpts, client.StreamingRequest())
+	}
+	req := c.NewRequest(service.Name, service.Endpoint.Name, &bytes.Frame{Data: msg}, reqOpts...)
+
+	rw := &responseWriter{w: w, ct: ct, text: text}
+	if !stream {
+		rsp := &bytes.Frame{}
+		if err := c.Call(cx, req, rsp, callOpt); err != nil {
+			rw.close(err)
+			return
+		}
+		rw.write(rsp.Data)
+		rw.close(nil)
+		return
+	}
+
+	// server streams, gRPC-Web doesn't support streaming requests
+	st, err := c.Stream(cx, req, callOpt)
+	if err != nil {
+		rw.close(err)
+		return
+	}
+	defer st.Close()
+
+	if err := st.Send(&bytes.Frame{Data: msg}); err != nil {
+		rw.close(err)
+		return
+	}
+
+	for {
+		rsp := &bytes.Frame{}
+		if err := st.Recv(rsp); err == io.EOF {
+			rw.close(nil)
+			return
+		} else if err != nil {
+			rw.close(err)
+			return
+		}
+		rw.write(rsp.Data)
+	}
+}
+
+func (h *grpcWebHandler) String() string {
+	return "grpcweb"
+}
+
+// responseWriter writes the messages and trailers of a response as gRPC-Web frames
+type responseWriter struct {
+	w    http.ResponseWriter
+	ct   string
+	text bool
+	// set once the headers have been written
+	started bool
+}
+
+func (r *responseWriter) start() {
+	if r.started {
+		return
+	}
+	r.started = true
+	r.w.Header().Set("Content-Type", r.ct)
+	r.w.WriteHeader(http.StatusOK)
+}
+
+// write the message as a data frame
+func (r *responseWriter) write(msg []byte) {
+	r.start()
+	r.frame(0, msg)
+}
+
+// close the response with the status of the error. If no messages have been written the status is
+// returned in the headers, as a trailers only response.
+func (r *responseWriter) close(err error) {
+	code, msg := status(err)
+	if err != nil && logger.V(logger.DebugLevel, logger.DefaultLogger) {
+		logger.Debugf("gRPC-Web request failed: %v", err)
+	}
+
+	if !r.started {
+		r.w.Header().Set("Grpc-Status", strconv.Itoa(int(code)))
+		if len(msg) > 0 {
+			r.w.Header().Set("Grpc-Message", encodeMessage(msg))
+		}
+		r.start()
+		return
+	}
+
+	trailers := fmt.Sprintf("grpc-status: %d\r\n", code)
+	if len(msg) > 0 {
+		trailers += fmt.Sprintf("grpc-message: %s\r\n", encodeMessage(msg))
+	}
+	r.frame(trailerFlag, []byte(trailers))
+}
+
+func (r *responseWriter) frame(flag byte, data []byte) {
+	b := make([]byte, 5+len(data))
+	b[0] = flag
+	binary.BigEndian.PutUint32(b[1:5], uint32(len(data)))
+	copy(b[5:], data)
+
+	// each frame is encoded separately so it can be decoded as soon as it's received
+	if r.text {
+		b = []byte(base64.StdEncoding.EncodeToString(b))
+	}
+	r.w.Write(b)
+	if f, ok := r.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// readMessage returns the message in the frame of the request
+func readMessage(b []byte) ([]byte, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	if len(b) < 5 {
+		return nil, errors.BadRequest("go.micro.api", "invalid gRPC-Web frame")
+	}
+	if b[0]&compressedFlag != 0 {
+		return nil, errors.NotImplemented("go.micro.api", "compressed gRPC-Web requests aren't supported")
+	}
+	size := binary.BigEndian.Uint32(b[1:5])
+	if uint32(len(b)-5) < size {
+		return nil, errors.BadRequest("go.micro.api", "invalid gRPC-Web frame, expected %d bytes but got %d", size, len(b)-5)
+	}
+	return b[5 : 5+size], nil
+}
+
+// decodeText decodes a base64 request. Clients may send several frames each of which are encoded
+// separately, so it's decoded four characters at a time as the padding can end any of them.
+func decodeText(b []byte) ([]byte, error) {
+	s := strings.Join(strings.Fields(string(b)), "")
+	if len(s)%4 != 0 {
+		return nil, base64.CorruptInputError(len(s))
+	}
+
+	out := make([]byte, 0, base64.StdEncoding.DecodedLen(len(s)))
+	for i := 0; i < len(s); i += 4 {
+		d, err := base64.StdEncoding.DecodeString(s[i : i+4])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d...)
+	}
+	return out, nil
+}
+
+// parseTimeout parses the grpc-timeout header, e.g. 10S or 200m
+func parseTimeout(v string) (time.Duration, error) {
+	if len(v) < 2 {
+		return 0, fmt.Errorf("invalid timeout %q", v)
+	}
+	n, err := strconv.ParseInt(v[:len(v)-1], 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid timeout %q", v)
+	}
+
+	units := map[byte]time.Duration{
+		'H': time.Hour,
+		'M': time.Minute,
+		'S': time.Second,
+		'm': time.Millisecond,
+		'u': time.Microsecond,
+		'n': time.Nanosecond,
+	}
+	unit, ok := units[v[len(v)-1]]
+	if !ok {
+		return 0, fmt.Errorf("invalid timeout unit in %q", v)
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// status returns the grpc status code and message of the error
+func status(err error) (codes.Code, string) {
+	if err == nil {
+		return codes.OK, ""
+	}
+	merr := errors.FromError(err)
+	if len(merr.Detail) > 0 {
+		return grpc.Code(merr), merr.Detail
+	}
+	return grpc.Code(merr), merr.Status
+}
+
+// encodeMessage percent encodes the grpc-message as required by the gRPC spec
+func encodeMessage(msg string) string {
+	var sb strings.Builder
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		if c < ' ' || c > '~' || c == '%' {
+			fmt.Fprintf(&sb, "%%%02X", c)
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// writeError writes an error which occurred before the call was made
+func writeError(w http.ResponseWriter, err error) {
+	rw := &responseWriter{w: w, ct: ContentType + "+proto"}
+	rw.close(err)
+}
+
+// isStream returns true if the endpoint streams its responses
+func isStream(srv *api.Service) bool {
+	for _, service := range srv.Services {
+		for _, ep := range service.Endpoints {
+			if ep.Name == srv.Endpoint.Name && ep.Metadata["stream"] == "true" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NewHandler returns a handler which serves gRPC-Web requests. A router which resolves the
+// requests with the Resolver is required.
+func NewHandler(opts ...handler.Option) http.Handler {
+	return &grpcWebHandler{
+		opts: handler.NewOptions(opts...),
+	}
+}
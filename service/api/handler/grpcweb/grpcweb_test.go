@@ -0,0 +1,161 @@
+package grpcweb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/api"
+	"github.com/micro/micro/v3/service/api/handler"
+	"github.com/micro/micro/v3/service/api/resolver"
+	"github.com/micro/micro/v3/service/api/router"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/util/codec/bytes"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRouter struct {
+	router.Router
+	srv *api.Service
+}
+
+func (t *testRouter) Route(r *http.Request) (*api.Service, error) {
+	return t.srv, nil
+}
+
+type testRequest struct {
+	client.Request
+	service, endpoint string
+	body              interface{}
+}
+
+// testClient returns the body of the request, or the error
+type testClient struct {
+	client.Client
+	req *testRequest
+	err error
+}
+
+func (t *testClient) NewRequest(service, endpoint string, req interface{}, opts ...client.RequestOption) client.Request {
+	t.req = &testRequest{service: service, endpoint: endpoint, body: req}
+	return t.req
+}
+
+func (t *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	if t.err != nil {
+		return t.err
+	}
+	rsp.(*bytes.Frame).Data = append([]byte("echo:"), req.(*testRequest).body.(*bytes.Frame).Data...)
+	return nil
+}
+
+func frame(flag byte, data []byte) []byte {
+	b := make([]byte, 5+len(data))
+	b[0] = flag
+	binary.BigEndian.PutUint32(b[1:5], uint32(len(data)))
+	copy(b[5:], data)
+	return b
+}
+
+func TestHandler(t *testing.T) {
+	srv := &api.Service{Name: "helloworld", Endpoint: &api.Endpoint{Name: "Helloworld.Call"}}
+	c := &testClient{}
+	h := NewHandler(handler.WithRouter(&testRouter{srv: srv}), handler.WithClient(c))
+
+	t.Run("Binary", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/helloworld.Helloworld/Call", strings.NewReader(string(frame(0, []byte("hi")))))
+		r.Header.Set("Content-Type", "application/grpc-web+proto")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/grpc-web+proto", w.Header().Get("Content-Type"))
+		assert.Equal(t, "helloworld", c.req.service)
+		assert.Equal(t, "Helloworld.Call", c.req.endpoint)
+
+		// the message is followed by the trailers
+		expect := append(frame(0, []byte("echo:hi")), frame(trailerFlag, []byte("grpc-status: 0\r\n"))...)
+		assert.Equal(t, expect, w.Body.Bytes())
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		body := base64.StdEncoding.EncodeToString(frame(0, []byte("hi")))
+		r := httptest.NewRequest("POST", "/helloworld.Helloworld/Call", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/grpc-web-text")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/grpc-web-text+proto", w.Header().Get("Content-Type"))
+
+		rsp, err := decodeText(w.Body.Bytes())
+		assert.NoError(t, err)
+		expect := append(frame(0, []byte("echo:hi")), frame(trailerFlag, []byte("grpc-status: 0\r\n"))...)
+		assert.Equal(t, expect, rsp)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		c.err = errors.Forbidden("helloworld", "not allowed %v%%", 100)
+		defer func() { c.err = nil }()
+
+		r := httptest.NewRequest("POST", "/helloworld.Helloworld/Call", strings.NewReader(string(frame(0, []byte("hi")))))
+		r.Header.Set("Content-Type", "application/grpc-web+proto")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		// errors before any message are returned in the headers
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "7", w.Header().Get("Grpc-Status"))
+		assert.Equal(t, "not allowed 100%25", w.Header().Get("Grpc-Message"))
+		assert.Empty(t, w.Body.Bytes())
+	})
+
+	t.Run("Compressed", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/helloworld.Helloworld/Call", strings.NewReader(string(frame(compressedFlag, []byte("hi")))))
+		r.Header.Set("Content-Type", "application/grpc-web+proto")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, "12", w.Header().Get("Grpc-Status"))
+	})
+}
+
+func TestResolver(t *testing.T) {
+	rr := NewResolver(nil, resolver.WithServicePrefix("foo"))
+
+	r := httptest.NewRequest("POST", "/helloworld.Helloworld/Call", nil)
+	r.Header.Set("Content-Type", "application/grpc-web-text")
+	r.Header.Set("Micro-Namespace", "bar")
+	ep, err := rr.Resolve(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "foo.helloworld", ep.Name)
+	assert.Equal(t, "Helloworld.Call", ep.Method)
+	assert.Equal(t, "bar", ep.Domain)
+
+	for _, p := range []string{"/", "/helloworld", "/Helloworld/Call", "/helloworld.Helloworld/"} {
+		r := httptest.NewRequest("POST", p, nil)
+		r.Header.Set("Content-Type", "application/grpc-web")
+		_, err := rr.Resolve(r)
+		assert.Equal(t, resolver.ErrInvalidPath, err, p)
+	}
+}
+
+func TestParseTimeout(t *testing.T) {
+	d, err := parseTimeout("10S")
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Second, d)
+
+	d, err = parseTimeout("250m")
+	assert.NoError(t, err)
+	assert.Equal(t, 250*time.Millisecond, d)
+
+	for _, v := range []string{"", "S", "10", "10x", "-1S"} {
+		_, err := parseTimeout(v)
+		assert.Error(t, err, v)
+	}
+}
@@ -0,0 +1,64 @@
+package grpcweb
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/micro/micro/v3/service/api/resolver"
+	"github.com/micro/micro/v3/service/registry"
+)
+
+// Resolver resolves gRPC-Web requests, e.g. /helloworld.Helloworld/Call, to the service and the
+// endpoint they call, e.g. helloworld and Helloworld.Call, so they're authorized like any other
+// request to the endpoint. Other requests are resolved by the parent.
+type Resolver struct {
+	opts resolver.Options
+	resolver.Resolver
+}
+
+func (r *Resolver) Resolve(req *http.Request, opts ...resolver.ResolveOption) (*resolver.Endpoint, error) {
+	if !IsRequest(req) {
+		return r.Resolver.Resolve(req, opts...)
+	}
+	options := resolver.NewResolveOptions(opts...)
+
+	// /helloworld.Helloworld/Call => [helloworld.Helloworld, Call]
+	parts := strings.Split(strings.TrimPrefix(req.URL.Path, "/"), "/")
+	if len(parts) != 2 || len(parts[1]) == 0 {
+		return nil, resolver.ErrInvalidPath
+	}
+	idx := strings.LastIndex(parts[0], ".")
+	if idx <= 0 || idx == len(parts[0])-1 {
+		return nil, resolver.ErrInvalidPath
+	}
+
+	// append the service prefix, e.g. foo.api
+	name := parts[0][:idx]
+	if len(r.opts.ServicePrefix) > 0 {
+		name = r.opts.ServicePrefix + "." + name
+	}
+
+	// the namespace header takes priority over the domain passed as a default
+	domain := options.Domain
+	if dom := req.Header.Get("Micro-Namespace"); len(dom) > 0 && dom != domain {
+		domain = dom
+	} else if len(domain) == 0 {
+		domain = registry.DefaultDomain
+	}
+
+	return &resolver.Endpoint{
+		Name:   name,
+		Host:   req.Host,
+		Method: parts[0][idx+1:] + "." + parts[1],
+		Domain: domain,
+	}, nil
+}
+
+func (r *Resolver) String() string {
+	return "grpcweb"
+}
+
+// NewResolver returns a resolver of gRPC-Web requests which resolves any others with the parent
+func NewResolver(parent resolver.Resolver, opts ...resolver.Option) resolver.Resolver {
+	return &Resolver{resolver.NewOptions(opts...), parent}
+}
@@ -20,6 +20,7 @@ package handler
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/micro/micro/v3/proto/api"
 	"github.com/micro/micro/v3/service/api/auth"
@@ -38,6 +39,17 @@ type Handler interface {
 type APIHandler struct {
 }
 
+// Error returns the error to write to the caller of the api. The chain of the services the error
+// was returned through is only returned to admins of the platform, it reveals the services
+// behind the api to everyone else.
+func Error(ctx context.Context, err error) *errors.Error {
+	e := *errors.FromError(err)
+	if namespace.AuthorizeAdmin(ctx, namespace.DefaultNamespace, "") != nil {
+		e.Chain = nil
+	}
+	return &e
+}
+
 func (a *APIHandler) ReadBlockList(ctx context.Context, request *api.ReadBlockListRequest, response *api.ReadBlockListResponse) error {
 	if err := namespace.AuthorizeAdmin(ctx, namespace.DefaultNamespace, "api.API.AddToBlockList"); err != nil {
 		return err
@@ -79,3 +91,129 @@ func (a *APIHandler) RemoveFromBlockList(ctx context.Context, request *api.Remov
 
 	return auth.DefaultBlockList.Remove(ctx, request.Id, request.Namespace)
 }
+
+func (a *APIHandler) EnableMaintenance(ctx context.Context, request *api.EnableMaintenanceRequest, response *api.EnableMaintenanceResponse) error {
+	if request.Maintenance == nil || len(request.Maintenance.Namespace) == 0 {
+		return errors.BadRequest("api.EnableMaintenance", "Missing Namespace field")
+	}
+	if request.Maintenance.RetryAfter < 0 {
+		return errors.BadRequest("api.EnableMaintenance", "Invalid RetryAfter field")
+	}
+	if err := namespace.AuthorizeAdmin(ctx, request.Maintenance.Namespace, "api.API.EnableMaintenance"); err != nil {
+		return err
+	}
+
+	m := request.Maintenance
+	return auth.DefaultMaintenance.Enable(ctx, &auth.Maintenance{
+		Namespace:    m.Namespace,
+		Message:      m.Message,
+		RetryAfter:   time.Duration(m.RetryAfter) * time.Second,
+		ReadVerbs:    m.ReadVerbs,
+		ReadPrefixes: m.ReadPrefixes,
+		Created:      time.Now(),
+	})
+}
+
+func (a *APIHandler) DisableMaintenance(ctx context.Context, request *api.DisableMaintenanceRequest, response *api.DisableMaintenanceResponse) error {
+	if len(request.Namespace) == 0 {
+		return errors.BadRequest("api.DisableMaintenance", "Missing Namespace field")
+	}
+	if err := namespace.AuthorizeAdmin(ctx, request.Namespace, "api.API.DisableMaintenance"); err != nil {
+		return err
+	}
+
+	return auth.DefaultMaintenance.Disable(ctx, request.Namespace)
+}
+
+func (a *APIHandler) ReadMaintenance(ctx context.Context, request *api.ReadMaintenanceRequest, response *api.ReadMaintenanceResponse) error {
+	ns := request.Namespace
+	if len(ns) == 0 {
+		ns = namespace.DefaultNamespace
+	}
+	if err := namespace.AuthorizeAdmin(ctx, ns, "api.API.ReadMaintenance"); err != nil {
+		return err
+	}
+
+	var list []*auth.Maintenance
+	if len(request.Namespace) > 0 {
+		m, err := auth.DefaultMaintenance.Get(ctx, request.Namespace)
+		if err != nil {
+			return errors.InternalServerError("api.ReadMaintenance", err.Error())
+		} else if m != nil {
+			list = append(list, m)
+		}
+	} else {
+		var err error
+		if list, err = auth.DefaultMaintenance.List(ctx); err != nil {
+			return errors.InternalServerError("api.ReadMaintenance", err.Error())
+		}
+	}
+
+	for _, m := range list {
+		response.Maintenance = append(response.Maintenance, &api.Maintenance{
+			Namespace:    m.Namespace,
+			Message:      m.Message,
+			RetryAfter:   int64(m.RetryAfter.Seconds()),
+			ReadVerbs:    m.ReadVerbs,
+			ReadPrefixes: m.ReadPrefixes,
+			Created:      m.Created.Unix(),
+		})
+	}
+	return nil
+}
+
+func (a *APIHandler) AddDomain(ctx context.Context, request *api.AddDomainRequest, response *api.AddDomainResponse) error {
+	if request.Domain == nil || len(auth.NormalizeHost(request.Domain.Host)) == 0 {
+		return errors.BadRequest("api.AddDomain", "Missing Host field")
+	}
+	if len(request.Domain.Namespace) == 0 {
+		return errors.BadRequest("api.AddDomain", "Missing Namespace field")
+	}
+	// certificates are issued for the domains so only platform admins can add them
+	if err := namespace.AuthorizeAdmin(ctx, namespace.DefaultNamespace, "api.API.AddDomain"); err != nil {
+		return err
+	}
+
+	return auth.DefaultDomains.Add(ctx, &auth.Domain{
+		Host:      request.Domain.Host,
+		Namespace: request.Domain.Namespace,
+		Created:   time.Now(),
+	})
+}
+
+func (a *APIHandler) RemoveDomain(ctx context.Context, request *api.RemoveDomainRequest, response *api.RemoveDomainResponse) error {
+	if len(auth.NormalizeHost(request.Host)) == 0 {
+		return errors.BadRequest("api.RemoveDomain", "Missing Host field")
+	}
+	if err := namespace.AuthorizeAdmin(ctx, namespace.DefaultNamespace, "api.API.RemoveDomain"); err != nil {
+		return err
+	}
+
+	return auth.DefaultDomains.Remove(ctx, request.Host)
+}
+
+func (a *APIHandler) ReadDomains(ctx context.Context, request *api.ReadDomainsRequest, response *api.ReadDomainsResponse) error {
+	ns := request.Namespace
+	if len(ns) == 0 {
+		ns = namespace.DefaultNamespace
+	}
+	if err := namespace.AuthorizeAdmin(ctx, ns, "api.API.ReadDomains"); err != nil {
+		return err
+	}
+
+	list, err := auth.DefaultDomains.List(ctx)
+	if err != nil {
+		return errors.InternalServerError("api.ReadDomains", err.Error())
+	}
+	for _, d := range list {
+		if len(request.Namespace) > 0 && d.Namespace != request.Namespace {
+			continue
+		}
+		response.Domains = append(response.Domains, &api.Domain{
+			Host:      d.Host,
+			Namespace: d.Namespace,
+			Created:   d.Created.Unix(),
+		})
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// Package oauth provides a handler which serves the OAuth2 token endpoint (RFC 6749) for machine
+// clients, backed by the auth service
+package oauth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/api/handler"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/util/ctx"
+	"github.com/micro/micro/v3/util/namespace"
+)
+
+const (
+	Handler = "oauth"
+)
+
+type oauthHandler struct {
+	opts handler.Options
+}
+
+// tokenError is the error response defined in section 5.2 of RFC 6749
+type tokenError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// tokenResponse is the successful response defined in section 5.1 of RFC 6749
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+func (h *oauthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		w.Header().Set("Allow", "POST")
+		writeError(w, http.StatusMethodNotAllowed, "invalid_request", "The token endpoint only accepts POST requests")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.opts.MaxRecvSize)
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Unable to parse the request body")
+		return
+	}
+
+	// clients may authenticate using basic auth or by passing the credentials in the body,
+	// but not both (section 2.3.1)
+	id, secret, basic := r.BasicAuth()
+	if basic {
+		if len(r.PostForm.Get("client_id")) > 0 || len(r.PostForm.Get("client_secret")) > 0 {
+			writeError(w, http.StatusBadRequest, "invalid_request", "Multiple client authentication methods used")
+			return
+		}
+		// the credentials are form encoded before being placed in the header
+		id, _ = url.QueryUnescape(id)
+		secret, _ = url.QueryUnescape(secret)
+	} else {
+		id = r.PostForm.Get("client_id")
+		secret = r.PostForm.Get("client_secret")
+	}
+
+	// the namespace is set by the auth wrapper based on the domain requested
+	cx := ctx.FromRequest(r)
+	ns := namespace.FromContext(cx)
+
+	srv := pb.NewOAuthService("auth", h.opts.Client)
+	rsp, err := srv.Token(cx, &pb.OAuthTokenRequest{
+		GrantType:    r.PostForm.Get("grant_type"),
+		ClientId:     id,
+		ClientSecret: secret,
+		RefreshToken: r.PostForm.Get("refresh_token"),
+		Scope:        r.PostForm.Get("scope"),
+		Options:      &pb.Options{Namespace: ns},
+	})
+	if err != nil {
+		logger.Errorf("Error generating oauth token: %v", err)
+		writeError(w, http.StatusInternalServerError, "server_error", errors.Parse(err.Error()).Detail)
+		return
+	}
+
+	switch rsp.Error {
+	case "":
+	case "invalid_client":
+		// clients using basic auth are challenged to authenticate (section 5.2)
+		if basic {
+			w.Header().Set("WWW-Authenticate", `Basic realm="micro"`)
+		}
+		writeError(w, http.StatusUnauthorized, rsp.Error, rsp.ErrorDescription)
+		return
+	default:
+		writeError(w, http.StatusBadRequest, rsp.Error, rsp.ErrorDescription)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &tokenResponse{
+		AccessToken:  rsp.AccessToken,
+		TokenType:    rsp.TokenType,
+		ExpiresIn:    rsp.ExpiresIn,
+		RefreshToken: rsp.RefreshToken,
+		Scope:        rsp.Scope,
+	})
+}
+
+func (h *oauthHandler) String() string {
+	return Handler
+}
+
+func writeError(w http.ResponseWriter, status int, code, description string) {
+	writeJSON(w, status, &tokenError{Error: code, ErrorDescription: description})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	b, _ := json.Marshal(v)
+
+	// token responses must not be cached (section 5.1)
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(status)
+
+	if _, err := w.Write(b); err != nil {
+		logger.Error(err)
+	}
+}
+
+// NewHandler returns a handler which serves the OAuth2 token endpoint
+func NewHandler(opts ...handler.Option) handler.Handler {
+	return &oauthHandler{
+		opts: handler.NewOptions(opts...),
+	}
+}
@@ -0,0 +1,121 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/api/handler"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/client/grpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// testClient returns the response set rather than calling the auth service
+type testClient struct {
+	client.Client
+	req *pb.OAuthTokenRequest
+	rsp *pb.OAuthTokenResponse
+}
+
+func (t *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	t.req = req.Body().(*pb.OAuthTokenRequest)
+	out := rsp.(*pb.OAuthTokenResponse)
+	out.AccessToken = t.rsp.AccessToken
+	out.TokenType = t.rsp.TokenType
+	out.ExpiresIn = t.rsp.ExpiresIn
+	out.Error = t.rsp.Error
+	out.ErrorDescription = t.rsp.ErrorDescription
+	return nil
+}
+
+func TestToken(t *testing.T) {
+	tcs := []struct {
+		name   string
+		basic  bool
+		form   url.Values
+		rsp    *pb.OAuthTokenResponse
+		status int
+		body   map[string]interface{}
+	}{
+		{
+			name:   "basic auth",
+			basic:  true,
+			form:   url.Values{"grant_type": {"client_credentials"}, "scope": {"billing"}},
+			rsp:    &pb.OAuthTokenResponse{AccessToken: "token", TokenType: "Bearer", ExpiresIn: 3600},
+			status: http.StatusOK,
+			body:   map[string]interface{}{"access_token": "token", "token_type": "Bearer", "expires_in": float64(3600)},
+		},
+		{
+			name:   "form credentials",
+			form:   url.Values{"grant_type": {"client_credentials"}, "client_id": {"foo"}, "client_secret": {"bar"}},
+			rsp:    &pb.OAuthTokenResponse{AccessToken: "token", TokenType: "Bearer"},
+			status: http.StatusOK,
+			body:   map[string]interface{}{"access_token": "token", "token_type": "Bearer"},
+		},
+		{
+			name:   "invalid client",
+			basic:  true,
+			form:   url.Values{"grant_type": {"client_credentials"}},
+			rsp:    &pb.OAuthTokenResponse{Error: "invalid_client", ErrorDescription: "Client authentication failed"},
+			status: http.StatusUnauthorized,
+			body:   map[string]interface{}{"error": "invalid_client", "error_description": "Client authentication failed"},
+		},
+		{
+			name:   "invalid scope",
+			form:   url.Values{"grant_type": {"client_credentials"}, "client_id": {"foo"}, "client_secret": {"bar"}},
+			rsp:    &pb.OAuthTokenResponse{Error: "invalid_scope"},
+			status: http.StatusBadRequest,
+			body:   map[string]interface{}{"error": "invalid_scope"},
+		},
+		{
+			name:   "multiple authentication methods",
+			basic:  true,
+			form:   url.Values{"grant_type": {"client_credentials"}, "client_id": {"foo"}},
+			status: http.StatusBadRequest,
+			body:   map[string]interface{}{"error": "invalid_request", "error_description": "Multiple client authentication methods used"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &testClient{Client: grpc.NewClient(), rsp: tc.rsp}
+			h := NewHandler(handler.WithClient(c))
+
+			req := httptest.NewRequest("POST", "/oauth/token", strings.NewReader(tc.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tc.basic {
+				req.SetBasicAuth("foo", "bar")
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.status, w.Code)
+			assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+			var body map[string]interface{}
+			assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tc.body, body)
+
+			if tc.rsp != nil {
+				assert.Equal(t, "foo", c.req.ClientId)
+				assert.Equal(t, "bar", c.req.ClientSecret)
+				assert.Equal(t, tc.form.Get("grant_type"), c.req.GrantType)
+			}
+		})
+	}
+}
+
+func TestTokenMethod(t *testing.T) {
+	h := NewHandler(handler.WithClient(&testClient{Client: grpc.NewClient()}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/oauth/token", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "POST", w.Header().Get("Allow"))
+}
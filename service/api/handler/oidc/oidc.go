@@ -0,0 +1,147 @@
+// Package oidc provides a handler which logs users in to the api with an OpenID Connect provider
+// using the authorization code flow
+package oidc
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/api/handler"
+	idp "github.com/micro/micro/v3/service/auth/oidc"
+	"github.com/micro/micro/v3/service/logger"
+	inauth "github.com/micro/micro/v3/util/auth"
+)
+
+const (
+	Handler = "oidc"
+	// Path the handler is served at, users log in at /auth/oidc/login?redirect_to=/foo
+	Path = "/auth/oidc"
+
+	// stateCookieName is the name of the cookie which stores the state and code verifier of a
+	// login in progress
+	stateCookieName = "micro-oidc"
+	// stateExpiry is how long users have to log in with the provider
+	stateExpiry = time.Minute * 10
+)
+
+type oidcHandler struct {
+	opts     handler.Options
+	provider *idp.Provider
+}
+
+func (h *oidcHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch strings.TrimPrefix(r.URL.Path, Path) {
+	case "/login":
+		h.login(w, r)
+	case "/callback":
+		h.callback(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// login redirects the user to the provider, the state of the login is stored in a cookie which
+// the callback checks so the code can only be redeemed by the browser which started the login
+func (h *oidcHandler) login(w http.ResponseWriter, r *http.Request) {
+	state := uuid.New().String()
+	verifier, challenge := idp.NewVerifier()
+
+	u, err := h.provider.AuthCodeURL(state, challenge, callbackURL(r))
+	if err != nil {
+		logger.Errorf("Error building the oidc login url: %v", err)
+		http.Error(w, "Identity provider unavailable", http.StatusBadGateway)
+		return
+	}
+
+	redirect := base64.RawURLEncoding.EncodeToString([]byte(r.URL.Query().Get("redirect_to")))
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookieName,
+		Value:    strings.Join([]string{state, verifier, redirect}, "."),
+		Path:     Path,
+		Expires:  time.Now().Add(stateExpiry),
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, u, http.StatusFound)
+}
+
+// callback redeems the code returned by the provider and sets the token cookie
+func (h *oidcHandler) callback(w http.ResponseWriter, r *http.Request) {
+	c, err := r.Cookie(stateCookieName)
+	if err != nil {
+		http.Error(w, "Login expired", http.StatusBadRequest)
+		return
+	}
+	parts := strings.Split(c.Value, ".")
+	q := r.URL.Query()
+	if len(parts) != 3 || parts[0] != q.Get("state") {
+		http.Error(w, "Invalid login state", http.StatusBadRequest)
+		return
+	}
+
+	// the login is finished whatever the outcome
+	http.SetCookie(w, &http.Cookie{
+		Name:    stateCookieName,
+		Value:   "",
+		Path:    Path,
+		Expires: time.Unix(0, 0),
+		Secure:  true,
+	})
+
+	if e := q.Get("error"); len(e) > 0 {
+		http.Error(w, "Login failed: "+e, http.StatusUnauthorized)
+		return
+	}
+	tok, err := h.provider.Exchange(q.Get("code"), parts[1], callbackURL(r))
+	if err != nil {
+		logger.Debugf("Error redeeming oidc code: %v", err)
+		http.Error(w, "Login failed", http.StatusUnauthorized)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     inauth.TokenCookieName,
+		Value:    tok.AccessToken,
+		Path:     "/",
+		Expires:  tok.Expiry,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	// only redirect within the api
+	redirect := "/"
+	if b, err := base64.RawURLEncoding.DecodeString(parts[2]); err == nil {
+		if s := string(b); strings.HasPrefix(s, "/") && !strings.HasPrefix(s, "//") && !strings.HasPrefix(s, "/\\") {
+			redirect = s
+		}
+	}
+	http.Redirect(w, r, redirect, http.StatusFound)
+}
+
+func (h *oidcHandler) String() string {
+	return Handler
+}
+
+// callbackURL returns the url the provider redirects back to, which must be registered with it
+func callbackURL(r *http.Request) string {
+	scheme := "https"
+	if proto := r.Header.Get("X-Forwarded-Proto"); len(proto) > 0 {
+		scheme = proto
+	} else if r.TLS == nil {
+		scheme = "http"
+	}
+	return scheme + "://" + r.Host + Path + "/callback"
+}
+
+// NewHandler returns a handler which logs users in with the provider
+func NewHandler(p *idp.Provider, opts ...handler.Option) handler.Handler {
+	return &oidcHandler{
+		opts:     handler.NewOptions(opts...),
+		provider: p,
+	}
+}
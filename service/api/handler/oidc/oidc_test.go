@@ -0,0 +1,101 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	idp "github.com/micro/micro/v3/service/auth/oidc"
+	inauth "github.com/micro/micro/v3/util/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+// testProvider serves the endpoints of an identity provider which issues a token for any code
+func testProvider(t *testing.T) *httptest.Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 srv.URL,
+			"authorization_endpoint": srv.URL + "/authorize",
+			"token_endpoint":         srv.URL + "/token",
+			"jwks_uri":               srv.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "1",
+				"kty": "RSA",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		tok := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss": srv.URL,
+			"sub": "123",
+			"aud": "micro",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		})
+		tok.Header["kid"] = "1"
+		s, _ := tok.SignedString(key)
+		json.NewEncoder(w).Encode(map[string]string{"id_token": s})
+	})
+
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestLogin(t *testing.T) {
+	srv := testProvider(t)
+	h := NewHandler(idp.NewProvider(idp.Issuer(srv.URL), idp.ClientCredentials("micro", "")))
+
+	// the user is sent to the provider
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://localhost"+Path+"/login?redirect_to=/foo", nil))
+	assert.Equal(t, http.StatusFound, w.Code)
+	loc, err := url.Parse(w.Header().Get("Location"))
+	assert.NoError(t, err)
+	assert.Equal(t, "/authorize", loc.Path)
+	assert.Equal(t, "http://localhost"+Path+"/callback", loc.Query().Get("redirect_uri"))
+	state := w.Result().Cookies()[0]
+
+	// and logged in when the provider redirects back
+	callback := func(s string, cookie *http.Cookie) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", "http://localhost"+Path+"/callback?code=code&state="+s, nil)
+		r.AddCookie(cookie)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+	w = callback(loc.Query().Get("state"), state)
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "/foo", w.Header().Get("Location"))
+	var tok string
+	for _, c := range w.Result().Cookies() {
+		if c.Name == inauth.TokenCookieName {
+			tok = c.Value
+		}
+	}
+	assert.NotEmpty(t, tok)
+
+	// the state must match the login started
+	w = callback("other", state)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
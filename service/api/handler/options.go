@@ -31,6 +31,8 @@ type Options struct {
 	Namespace   string
 	Router      router.Router
 	Client      client.Client
+	// Validate the request body against the endpoint's request before calling the service
+	Validate bool
 }
 
 type Option func(o *Options)
@@ -84,3 +86,11 @@ func WithMaxRecvSize(size int64) Option {
 		o.MaxRecvSize = size
 	}
 }
+
+// WithValidation validates the request body against the endpoint's request before calling the
+// service, requests with invalid fields are rejected with a 400
+func WithValidation(b bool) Option {
+	return func(o *Options) {
+		o.Validate = b
+	}
+}
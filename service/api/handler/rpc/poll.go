@@ -0,0 +1,275 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/api"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+const (
+	// Header used to return the poll id to the client and by the client to continue polling
+	pollIDHeader = "Micro-Poll-Id"
+	// Header set once the stream has finished and the poll has been closed
+	pollDoneHeader = "Micro-Poll-Done"
+	// Header used by the client to set how long to wait for messages, in seconds
+	pollWaitHeader = "Micro-Poll-Wait"
+)
+
+var (
+	// DefaultPollWait is how long a poll waits for messages before returning an empty response
+	DefaultPollWait = 30 * time.Second
+	// DefaultPollExpiry is how long a stream is held open without the client polling
+	DefaultPollExpiry = 2 * time.Minute
+	// DefaultPollBatch is the maximum number of messages returned by a poll
+	DefaultPollBatch = 100
+	// maxPollWait caps the wait requested by the client
+	maxPollWait = 60 * time.Second
+
+	polls = newPoller()
+)
+
+// poller holds open the streams of long poll clients between requests. Polls are held in memory so
+// requests for the same poll need to be routed to the same gateway instance.
+type poller struct {
+	sync.Mutex
+	polls map[string]*poll
+	once  sync.Once
+}
+
+type poll struct {
+	id string
+	// account the poll was created by, only it can continue the poll
+	account string
+	stream  client.Stream
+	ctx     context.Context
+	cancel  context.CancelFunc
+	// messages read from the stream
+	msgs chan []byte
+	// closed once the stream has finished
+	done chan bool
+	// error the stream finished with, if any
+	err error
+
+	sync.Mutex
+	lastSeen time.Time
+}
+
+func newPoller() *poller {
+	return &poller{polls: make(map[string]*poll)}
+}
+
+// servePoll serves the stream to long poll clients. The first request opens the stream and returns a
+// poll id in the Micro-Poll-Id header, subsequent requests with the header return the messages received
+// since as a JSON array.
+func servePoll(ctx context.Context, w http.ResponseWriter, r *http.Request, service *api.Service, c client.Client) {
+	polls.once.Do(func() { go polls.reap() })
+
+	var account string
+	acc, ok := auth.AccountFromContext(ctx)
+	if ok {
+		account = acc.ID
+	}
+
+	var p *poll
+	if id := r.Header.Get(pollIDHeader); len(id) > 0 {
+		p = polls.get(id)
+		if p == nil || p.account != account {
+			writeError(w, r, errors.NotFound("go.micro.api", "poll %v not found", id))
+			return
+		}
+	} else {
+		// the stream outlives the request so it can't use the request context, the metadata
+		// is copied to pass on the auth and other headers
+		md, _ := metadata.FromContext(ctx)
+		sctx, cancel := context.WithCancel(metadata.NewContext(context.Background(), md))
+		if acc != nil {
+			sctx = auth.ContextWithAccount(sctx, acc)
+		}
+
+		stream, _, err := openStream(sctx, r, service, c)
+		if err != nil {
+			cancel()
+			writeError(w, r, err)
+			return
+		}
+		p = polls.open(uuid.New().String(), account, stream, sctx, cancel)
+	}
+
+	p.Lock()
+	p.lastSeen = time.Now()
+	p.Unlock()
+
+	wait := DefaultPollWait
+	if s, err := strconv.Atoi(r.Header.Get(pollWaitHeader)); err == nil && s >= 0 {
+		wait = time.Duration(s) * time.Second
+		if wait > maxPollWait {
+			wait = maxPollWait
+		}
+	}
+
+	msgs, done := p.wait(ctx, wait)
+	if done {
+		polls.close(p.id)
+		if p.err != nil && len(msgs) == 0 {
+			writeError(w, r, p.err)
+			return
+		}
+		w.Header().Set(pollDoneHeader, "true")
+	}
+
+	// write the messages as a json array
+	buf := bytes.NewBuffer([]byte{'['})
+	for i, msg := range msgs {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(bytes.TrimSpace(msg))
+	}
+	buf.WriteByte(']')
+
+	w.Header().Set(pollIDHeader, p.id)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+			logger.Error(err)
+		}
+	}
+}
+
+func (p *poller) get(id string) *poll {
+	p.Lock()
+	defer p.Unlock()
+	return p.polls[id]
+}
+
+func (p *poller) open(id, account string, stream client.Stream, ctx context.Context, cancel context.CancelFunc) *poll {
+	pl := &poll{
+		id:       id,
+		account:  account,
+		stream:   stream,
+		ctx:      ctx,
+		cancel:   cancel,
+		msgs:     make(chan []byte, DefaultPollBatch),
+		done:     make(chan bool),
+		lastSeen: time.Now(),
+	}
+
+	p.Lock()
+	p.polls[id] = pl
+	p.Unlock()
+
+	go pl.read()
+
+	return pl
+}
+
+func (p *poller) close(id string) {
+	p.Lock()
+	pl, ok := p.polls[id]
+	delete(p.polls, id)
+	p.Unlock()
+
+	if ok {
+		pl.stream.Close()
+		pl.cancel()
+	}
+}
+
+// reap closes the polls which clients have stopped polling
+func (p *poller) reap() {
+	t := time.NewTicker(DefaultPollExpiry / 2)
+	defer t.Stop()
+
+	for range t.C {
+		var expired []string
+
+		p.Lock()
+		for id, pl := range p.polls {
+			pl.Lock()
+			if time.Since(pl.lastSeen) > DefaultPollExpiry {
+				expired = append(expired, id)
+			}
+			pl.Unlock()
+		}
+		p.Unlock()
+
+		for _, id := range expired {
+			p.close(id)
+		}
+	}
+}
+
+// read the messages from the stream until it finishes
+func (p *poll) read() {
+	defer close(p.done)
+
+	rsp := p.stream.Response()
+	for {
+		buf, err := rsp.Read()
+		if err == io.EOF {
+			return
+		} else if err != nil {
+			p.err = err
+			return
+		}
+		select {
+		case p.msgs <- buf:
+		case <-p.ctx.Done():
+			// the poll was closed
+			return
+		}
+	}
+}
+
+// wait for messages to be received, returning as soon as there's at least one. The bool returned is
+// true if the stream has finished and all its messages have been returned.
+func (p *poll) wait(ctx context.Context, wait time.Duration) ([][]byte, bool) {
+	var msgs [][]byte
+
+	// drain returns any messages already received without blocking
+	drain := func() {
+		for len(msgs) < DefaultPollBatch {
+			select {
+			case msg := <-p.msgs:
+				msgs = append(msgs, msg)
+			default:
+				return
+			}
+		}
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case msg := <-p.msgs:
+		msgs = append(msgs, msg)
+	case <-p.done:
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	drain()
+
+	select {
+	case <-p.done:
+		// the stream may have finished since the last drain
+		drain()
+		return msgs, len(p.msgs) == 0
+	default:
+		return msgs, false
+	}
+}
@@ -0,0 +1,106 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/api"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+type testStream struct {
+	client.Stream
+	rsp *testResponse
+}
+
+func (t *testStream) Response() client.Response {
+	return t.rsp
+}
+
+func (t *testStream) Close() error {
+	return nil
+}
+
+type testResponse struct {
+	client.Response
+	msgs chan []byte
+}
+
+func (t *testResponse) Read() ([]byte, error) {
+	msg, ok := <-t.msgs
+	if !ok {
+		return nil, io.EOF
+	}
+	return msg, nil
+}
+
+func TestPoll(t *testing.T) {
+	rsp := &testResponse{msgs: make(chan []byte)}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	p := newPoller()
+	pl := p.open("1", "", &testStream{rsp: rsp}, ctx, cancel)
+	assert.Equal(t, pl, p.get("1"))
+
+	// nothing received before the wait
+	msgs, done := pl.wait(context.Background(), 10*time.Millisecond)
+	assert.Empty(t, msgs)
+	assert.False(t, done)
+
+	rsp.msgs <- []byte(`{"count":1}`)
+	rsp.msgs <- []byte(`{"count":2}`)
+	close(rsp.msgs)
+
+	// wait for the stream to finish so all the messages are returned in one poll
+	<-pl.done
+
+	msgs, done = pl.wait(context.Background(), time.Second)
+	assert.Equal(t, [][]byte{[]byte(`{"count":1}`), []byte(`{"count":2}`)}, msgs)
+	assert.True(t, done)
+
+	p.close("1")
+	assert.Nil(t, p.get("1"))
+	assert.NotNil(t, ctx.Err())
+}
+
+func TestStreamMode(t *testing.T) {
+	srv := &api.Service{
+		Name:     "foo",
+		Endpoint: &api.Endpoint{Name: "Foo.Stream"},
+		Services: []*registry.Service{{
+			Name: "foo",
+			Endpoints: []*registry.Endpoint{{
+				Name:     "Foo.Stream",
+				Metadata: map[string]string{"stream": "true", "stream_mode": api.StreamModeLongPoll},
+			}},
+		}},
+	}
+
+	tcs := []struct {
+		name   string
+		header http.Header
+		mode   string
+	}{
+		{"route", http.Header{}, api.StreamModeLongPoll},
+		{"accept", http.Header{"Accept": []string{"application/x-ndjson"}}, api.StreamModeNDJSON},
+		{"header", http.Header{"Micro-Stream-Mode": []string{api.StreamModeNDJSON}}, api.StreamModeNDJSON},
+		{"poll", http.Header{"Micro-Poll-Id": []string{"1"}, "Accept": []string{"application/x-ndjson"}}, api.StreamModeLongPoll},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "/foo/stream", nil)
+			r.Header = tc.header
+			assert.Equal(t, tc.mode, streamMode(r, srv))
+		})
+	}
+}
+
+func TestNDJSONLine(t *testing.T) {
+	assert.Equal(t, "{\"foo\":\"bar\"}\n", string(ndjsonLine([]byte("{\n  \"foo\": \"bar\"\n}"))))
+}
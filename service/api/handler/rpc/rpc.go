@@ -28,9 +28,14 @@ import (
 	"github.com/micro/micro/v3/service/api"
 	"github.com/micro/micro/v3/service/api/handler"
 	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context/metadata"
 	"github.com/micro/micro/v3/service/errors"
 	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry"
+	inauth "github.com/micro/micro/v3/util/auth"
+	"github.com/micro/micro/v3/util/cache"
 	"github.com/micro/micro/v3/util/codec/bytes"
+	"github.com/micro/micro/v3/util/cost"
 	"github.com/micro/micro/v3/util/ctx"
 	"github.com/micro/micro/v3/util/router"
 )
@@ -120,6 +125,10 @@ func (h *rpcHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// create context
 	cx := ctx.FromRequest(r)
+	// when debugging return the cost of the request so expensive calls can be found
+	if logger.V(logger.DebugLevel, logger.DefaultLogger) {
+		cx = cost.NewContext(cx)
+	}
 
 	// set merged context to request
 	*r = *r.Clone(cx)
@@ -132,6 +141,10 @@ func (h *rpcHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// create custom router
 	callOpt := client.WithRouter(router.New(service.Services))
 
+	// the response metadata set by the service is returned as response headers
+	var rspMd metadata.Metadata
+	mdOpt := client.ResponseMetadata(&rspMd)
+
 	// walk the standard call path
 	// get payload
 	br, err := api.RequestPayload(r)
@@ -161,7 +174,9 @@ func (h *rpcHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 		// make the call
 		var response *bytes.Frame
-		if err := c.Call(cx, req, response, callOpt); err != nil {
+		err := c.Call(cx, req, response, callOpt, mdOpt)
+		writeResponseMetadata(w, r, rspMd)
+		if err != nil {
 			writeError(w, r, err)
 			return
 		}
@@ -172,6 +187,26 @@ func (h *rpcHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			ct = "application/json"
 		}
 
+		// convert well known types to the form expected by the service
+		br, err = api.Transcode(br, requestValue(service))
+		if err != nil {
+			writeError(w, r, errors.BadRequest("go.micro.api", "invalid request: %v", err))
+			return
+		}
+
+		// reject malformed requests before they reach the service, strict endpoints also reject
+		// fields which aren't fields of the request
+		if strict := isStrict(service); h.opts.Validate || strict {
+			var vopts []api.ValidateOption
+			if strict {
+				vopts = append(vopts, api.RejectUnknownFields())
+			}
+			if err := api.ValidateRequest(br, requestValue(service), vopts...); err != nil {
+				writeValidationError(w, r, err)
+				return
+			}
+		}
+
 		// default to trying json
 		var request json.RawMessage
 		// if the extracted payload isn't empty lets use it
@@ -189,7 +224,9 @@ func (h *rpcHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			client.WithContentType(ct),
 		)
 		// make the call
-		if err := c.Call(cx, req, &response, callOpt); err != nil {
+		err := c.Call(cx, req, &response, callOpt, mdOpt)
+		writeResponseMetadata(w, r, rspMd)
+		if err != nil {
 			writeError(w, r, err)
 			return
 		}
@@ -201,6 +238,13 @@ func (h *rpcHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			writeError(w, r, err)
 			return
 		}
+
+		// convert the well known types returned by the service to their canonical form
+		rsp, err = api.Transcode(rsp, responseValue(service))
+		if err != nil {
+			writeError(w, r, errors.InternalServerError("go.micro.api", "invalid response: %v", err))
+			return
+		}
 	}
 
 	// write the response
@@ -220,12 +264,113 @@ func hasCodec(ct string, codecs []string) bool {
 	return false
 }
 
+// requestValue returns the registered request value for the endpoint
+func requestValue(srv *api.Service) *registry.Value {
+	for _, service := range srv.Services {
+		for _, ep := range service.Endpoints {
+			if ep.Name == srv.Endpoint.Name && ep.Request != nil {
+				return ep.Request
+			}
+		}
+	}
+	return nil
+}
+
+// responseValue returns the registered response value for the endpoint
+func responseValue(srv *api.Service) *registry.Value {
+	for _, service := range srv.Services {
+		for _, ep := range service.Endpoints {
+			if ep.Name == srv.Endpoint.Name && ep.Response != nil {
+				return ep.Response
+			}
+		}
+	}
+	return nil
+}
+
+// isStrict returns true if the endpoint rejects unknown fields, either because its route is strict
+// or the service registered it as strict
+func isStrict(srv *api.Service) bool {
+	if srv.Endpoint.Strict {
+		return true
+	}
+	for _, service := range srv.Services {
+		for _, ep := range service.Endpoints {
+			if ep.Name == srv.Endpoint.Name && ep.Metadata["strict"] == "true" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeCost sets the cost header if the cost of the request was recorded
+func writeCost(w http.ResponseWriter, r *http.Request) {
+	if c, ok := cost.FromContext(r.Context()); ok {
+		if str := c.String(); len(str) > 0 {
+			w.Header().Set(cost.Header, str)
+		}
+	}
+}
+
+// responseHeaders are the headers other than the Micro- headers a handler can return with the
+// response metadata. Others, e.g. Set-Cookie or Access-Control-Allow-Origin, are dropped.
+var responseHeaders = map[string]bool{
+	cache.ControlKey:   true,
+	cache.VaryKey:      true,
+	"Retry-After":      true,
+	"Etag":             true,
+	"Last-Modified":    true,
+	"Content-Language": true,
+}
+
+// writeResponseMetadata sets the metadata returned with the response as headers. The cache
+// directive set by the handler is returned as the Cache-Control header, responses to authenticated
+// requests are marked private so shared caches don't return them to other callers. The cost is
+// only returned by writeCost, when the api is debugging.
+func writeResponseMetadata(w http.ResponseWriter, r *http.Request, md metadata.Metadata) {
+	for k, v := range md {
+		k = http.CanonicalHeaderKey(k)
+		if k == cost.Header {
+			continue
+		}
+		if strings.HasPrefix(k, "Micro-") || responseHeaders[k] {
+			w.Header().Set(k, v)
+		}
+	}
+
+	d, ok := cache.ParseDirective(md)
+	if !ok {
+		return
+	}
+	ctrl := d.String()
+	if !d.NoStore && authenticated(r) {
+		ctrl = "private, " + ctrl
+	}
+	w.Header().Set(cache.ControlKey, ctrl)
+}
+
+// authenticated returns true if the request was made with credentials
+func authenticated(r *http.Request) bool {
+	if len(r.Header.Get("Authorization")) > 0 || len(r.Header.Get(inauth.APIKeyHeader)) > 0 {
+		return true
+	}
+	for _, c := range r.Cookies() {
+		if c.Name == inauth.TokenCookieName || c.Name == inauth.SessionCookieName {
+			return true
+		}
+	}
+	return false
+}
+
 func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	writeCost(w, r)
+
 	// response content type
 	w.Header().Set("Content-Type", "application/json")
 
 	// parse out the error code
-	ce := errors.Parse(err.Error())
+	ce := handler.Error(r.Context(), err)
 
 	switch ce.Code {
 	case 0:
@@ -255,7 +400,31 @@ func writeError(w http.ResponseWriter, r *http.Request, err error) {
 	}
 }
 
+// writeValidationError writes a bad request error with the fields which are invalid
+func writeValidationError(w http.ResponseWriter, r *http.Request, err error) {
+	writeCost(w, r)
+
+	verr, ok := err.(*api.ValidationError)
+	if !ok {
+		writeError(w, r, errors.BadRequest("go.micro.api", "invalid request: %v", err))
+		return
+	}
+
+	b, _ := json.Marshal(map[string]interface{}{
+		"id":     "go.micro.api",
+		"code":   http.StatusBadRequest,
+		"detail": "invalid request: " + verr.Error(),
+		"status": http.StatusText(http.StatusBadRequest),
+		"errors": verr.Errors,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	w.Write(b)
+}
+
 func writeResponse(w http.ResponseWriter, r *http.Request, rsp []byte) {
+	writeCost(w, r)
 	w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
 	w.Header().Set("Content-Length", strconv.Itoa(len(rsp)))
 
@@ -0,0 +1,48 @@
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteResponseMetadata(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/foo/bar", nil)
+	writeResponseMetadata(w, r, metadata.Metadata{
+		"Micro-Timestamp":             "1",
+		"retry-after":                 "5",
+		"Set-Cookie":                  "session=foo",
+		"Access-Control-Allow-Origin": "*",
+		"Location":                    "https://example.com",
+	})
+
+	assert.Equal(t, "1", w.Header().Get("Micro-Timestamp"))
+	assert.Equal(t, "5", w.Header().Get("Retry-After"))
+	assert.Empty(t, w.Header().Get("Set-Cookie"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Location"))
+}
+
+func TestWriteErrorChain(t *testing.T) {
+	err := errors.Wrap(errors.InternalServerError("go.micro.store", "database unavailable"), "store", "Store.Read", "abc")
+
+	// the services behind the api aren't revealed to users
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/foo/bar", nil)
+	ctx := auth.ContextWithAccount(r.Context(), &auth.Account{ID: "john", Type: "user", Issuer: "foo", Scopes: []string{"admin"}})
+	writeError(w, r.WithContext(ctx), err)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, errors.Chain(errors.Parse(w.Body.String())))
+
+	// but are to admins of the platform
+	w = httptest.NewRecorder()
+	ctx = auth.ContextWithAccount(r.Context(), &auth.Account{ID: "admin", Type: "user", Issuer: "micro", Scopes: []string{"admin"}})
+	writeError(w, r.WithContext(ctx), err)
+	assert.Len(t, errors.Chain(errors.Parse(w.Body.String())), 1)
+}
@@ -0,0 +1,152 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/micro/micro/v3/service/api"
+	"github.com/micro/micro/v3/service/api/handler"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+const (
+	// Content type of server-sent event streams
+	sseContentType = "text/event-stream"
+	// Header sent by browsers reconnecting to an event stream with the id of the last event
+	lastEventIDHeader = "Last-Event-ID"
+	// How long browsers wait before reconnecting to an event stream which was closed, in ms
+	sseRetry = 3000
+)
+
+// sseHeartbeat is how often a comment is written to keep idle event streams open through proxies
+var sseHeartbeat = 15 * time.Second
+
+// serveSSE serves the stream as server-sent events. Each message is an event with an id which
+// counts the messages of the stream. Browsers reconnecting send the id of the last event they
+// received in the Last-Event-ID header, which is passed to the service in the metadata of the
+// request like any other header so services which support resumption can continue the stream
+// after it, and the ids continue from it. An end event is sent once the stream has finished so
+// clients know not to reconnect.
+func serveSSE(ctx context.Context, w http.ResponseWriter, r *http.Request, service *api.Service, c client.Client) {
+	var lastID uint64
+	if v := r.Header.Get(lastEventIDHeader); len(v) > 0 {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			writeStreamError(ctx, w, errors.BadRequest("go.micro.api", "Invalid Last-Event-ID %q", v))
+			return
+		}
+		lastID = id
+	}
+
+	stream, _, err := openStream(ctx, r, service, c)
+	if err != nil {
+		if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+			logger.Error(err)
+		}
+		writeStreamError(ctx, w, err)
+		return
+	}
+	defer stream.Close()
+
+	w.Header().Set("Content-Type", sseContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	flush := func() {
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetry)
+	flush()
+
+	// read the messages of the stream in the background so heartbeats can be written while idle
+	type result struct {
+		msg []byte
+		err error
+	}
+	msgs := make(chan result)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		rsp := stream.Response()
+		for {
+			msg, err := rsp.Read()
+			select {
+			case msgs <- result{msg, err}:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	id := lastID
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			io.WriteString(w, ": heartbeat\n\n")
+			flush()
+		case res := <-msgs:
+			if res.err == io.EOF {
+				io.WriteString(w, "event: end\ndata: {}\n\n")
+				flush()
+				return
+			} else if res.err != nil {
+				// the client gave up on the stream
+				if ctx.Err() != nil {
+					return
+				}
+				if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+					logger.Error(res.err)
+				}
+				b, _ := json.Marshal(handler.Error(ctx, res.err))
+				io.WriteString(w, "event: error\n")
+				w.Write(sseData(b))
+				flush()
+				return
+			}
+
+			id++
+			fmt.Fprintf(w, "id: %d\n", id)
+			w.Write(sseData(res.msg))
+			flush()
+		}
+	}
+}
+
+// sseData formats the message as the data of an event. JSON is compacted onto a single line, any
+// other message is split into a data field per line.
+func sseData(msg []byte) []byte {
+	var b bytes.Buffer
+	if err := json.Compact(&b, msg); err == nil {
+		msg = b.Bytes()
+	}
+
+	var out bytes.Buffer
+	for _, line := range bytes.Split(bytes.TrimRight(msg, "\n"), []byte("\n")) {
+		out.WriteString("data: ")
+		out.Write(bytes.TrimRight(line, "\r"))
+		out.WriteByte('\n')
+	}
+	out.WriteByte('\n')
+	return out.Bytes()
+}
@@ -0,0 +1,103 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/micro/micro/v3/service/api"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+// sseStream returns the messages of its response
+type sseStream struct {
+	client.Stream
+	rsp *echoResponse
+}
+
+func (s *sseStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *sseStream) Response() client.Response {
+	return s.rsp
+}
+
+func (s *sseStream) Send(msg interface{}) error {
+	return nil
+}
+
+func (s *sseStream) Close() error {
+	return nil
+}
+
+type sseClient struct {
+	client.Client
+	stream *sseStream
+}
+
+func (s *sseClient) NewRequest(service, endpoint string, req interface{}, opts ...client.RequestOption) client.Request {
+	return nil
+}
+
+func (s *sseClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	return s.stream, nil
+}
+
+func TestServeSSE(t *testing.T) {
+	srv := &api.Service{
+		Name:     "foo",
+		Endpoint: &api.Endpoint{Name: "Foo.Stream"},
+		Services: []*registry.Service{{Name: "foo"}},
+	}
+
+	t.Run("Events", func(t *testing.T) {
+		rsp := &echoResponse{msgs: make(chan []byte, 2)}
+		rsp.msgs <- []byte("{\n  \"count\": 1\n}")
+		rsp.msgs <- []byte("hello\nworld")
+		close(rsp.msgs)
+		rsp.err = errors.InternalServerError("foo", "oops")
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/foo/stream", nil)
+		r.Header.Set("Accept", sseContentType)
+		serveStream(r.Context(), w, r, srv, &sseClient{stream: &sseStream{rsp: rsp}})
+
+		assert.Equal(t, sseContentType, w.Header().Get("Content-Type"))
+		assert.Equal(t, "retry: 3000\n\n"+
+			"id: 1\ndata: {\"count\":1}\n\n"+
+			"id: 2\ndata: hello\ndata: world\n\n"+
+			"event: error\ndata: {\"id\":\"foo\",\"code\":500,\"detail\":\"oops\",\"status\":\"Internal Server Error\"}\n\n", w.Body.String())
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		rsp := &echoResponse{msgs: make(chan []byte, 1), err: io.EOF}
+		rsp.msgs <- []byte(`{"count":6}`)
+		close(rsp.msgs)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/foo/stream", nil)
+		r.Header.Set("Accept", sseContentType)
+		r.Header.Set(lastEventIDHeader, "5")
+		serveStream(r.Context(), w, r, srv, &sseClient{stream: &sseStream{rsp: rsp}})
+
+		// the ids continue from the last event and the end of the stream is sent
+		assert.Equal(t, "retry: 3000\n\n"+
+			"id: 6\ndata: {\"count\":6}\n\n"+
+			"event: end\ndata: {}\n\n", w.Body.String())
+	})
+
+	t.Run("InvalidLastEventID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/foo/stream", nil)
+		r.Header.Set("Accept", sseContentType)
+		r.Header.Set(lastEventIDHeader, "abc")
+		serveStream(r.Context(), w, r, srv, &sseClient{})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -30,9 +30,12 @@ import (
 	"github.com/gorilla/websocket"
 	pbapi "github.com/micro/micro/v3/proto/api"
 	"github.com/micro/micro/v3/service/api"
+	"github.com/micro/micro/v3/service/api/handler"
 	"github.com/micro/micro/v3/service/client"
 	"github.com/micro/micro/v3/service/errors"
 	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry"
+	inauth "github.com/micro/micro/v3/util/auth"
 	raw "github.com/micro/micro/v3/util/codec/bytes"
 	"github.com/micro/micro/v3/util/router"
 )
@@ -49,6 +52,15 @@ const (
 
 	// Maximum message size allowed from client.
 	maxMessageSize = 512
+
+	// Maximum length of the reason of a close frame, control frames are limited to 125 bytes.
+	maxCloseReason = 123
+
+	// Content type of newline delimited JSON streams
+	ndjsonContentType = "application/x-ndjson"
+
+	// Header used by clients to request a stream mode, e.g. ndjson or longpoll
+	streamModeHeader = "Micro-Stream-Mode"
 )
 
 var upgrader = websocket.Upgrader{
@@ -66,83 +78,41 @@ func serveStream(ctx context.Context, w http.ResponseWriter, r *http.Request, se
 		return
 	}
 
-	ct := r.Header.Get("Content-Type")
-	// Strip charset from Content-Type (like `application/json; charset=UTF-8`)
-	if idx := strings.IndexRune(ct, ';'); idx >= 0 {
-		ct = ct[:idx]
-	}
+	mode := streamMode(r, service)
 
-	payload, err := api.RequestPayload(r)
-	if err != nil {
-		if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
-			logger.Error(err)
-		}
+	// the route only bridges websockets to the stream
+	if mode == api.StreamModeWebSocket {
+		w.Header().Set("Upgrade", "websocket")
+		writeStreamError(ctx, w, errors.New("go.micro.api", "The endpoint is only served over a websocket", http.StatusUpgradeRequired))
 		return
 	}
-	if len(payload) == 0 {
-		// make it valid json
-		payload = []byte("{}")
-	}
 
-	var request interface{}
-	if !bytes.Equal(payload, []byte(`{}`)) {
-		switch ct {
-		case "application/json", "":
-			m := json.RawMessage(payload)
-			request = &m
-		default:
-			request = &raw.Frame{Data: payload}
-		}
+	// clients which can't hold the stream open poll for the messages instead
+	if mode == api.StreamModeLongPoll {
+		servePoll(ctx, w, r, service, c)
+		return
 	}
 
-	// we always need to set content type for message
-	if ct == "" {
-		ct = "application/json"
+	// browsers consume the stream as server-sent events
+	if mode == api.StreamModeSSE {
+		serveSSE(ctx, w, r, service, c)
+		return
 	}
-	req := c.NewRequest(
-		service.Name,
-		service.Endpoint.Name,
-		request,
-		client.WithContentType(ct),
-		client.StreamingRequest(),
-	)
 
-	w.Header().Set("Content-Type", ct)
-
-	// create custom router
-	callOpt := client.WithRouter(router.New(service.Services))
-
-	// create a new stream
-	stream, err := c.Stream(ctx, req, callOpt)
+	stream, ct, err := openStream(ctx, r, service, c)
 	if err != nil {
 		if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
 			logger.Error(err)
 		}
-		merr, ok := err.(*errors.Error)
-		if ok {
-			w.WriteHeader(int(merr.Code))
-			w.Write([]byte(merr.Error()))
-		}
+		writeStreamError(ctx, w, err)
 		return
 	}
 	defer stream.Close()
 
-	// send request even if nil because it triggers the call in case server expects no input
-	// without this, we establish a connection but don't kick off the stream of communication
-	if err = stream.Send(request); err != nil {
-		if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
-			logger.Error(err)
-		}
-		merr, ok := err.(*errors.Error)
-		if ok {
-			w.WriteHeader(int(merr.Code))
-			w.Write([]byte(merr.Error()))
-		} else {
-			w.WriteHeader(500)
-			w.Write([]byte(err.Error()))
-		}
-		return
+	if mode == api.StreamModeNDJSON {
+		ct = ndjsonContentType
 	}
+	w.Header().Set("Content-Type", ct)
 
 	rsp := stream.Response()
 
@@ -169,7 +139,10 @@ func serveStream(ctx context.Context, w http.ResponseWriter, r *http.Request, se
 					logger.Error(err)
 				}
 				merr, ok := err.(*errors.Error)
-				if ok {
+				if ok && mode == api.StreamModeNDJSON {
+					// the status has already been written so the error is sent as the last line
+					w.Write(append([]byte(merr.Error()), '\n'))
+				} else if ok {
 					w.WriteHeader(int(merr.Code))
 					w.Write([]byte(merr.Error()))
 				}
@@ -186,6 +159,8 @@ func serveStream(ctx context.Context, w http.ResponseWriter, r *http.Request, se
 				}
 				w.WriteHeader(int(apiRsp.StatusCode))
 				bufOut = apiRsp.Body
+			} else if mode == api.StreamModeNDJSON {
+				bufOut = string(ndjsonLine(buf))
 			} else {
 				bufOut = string(buf)
 			}
@@ -207,6 +182,134 @@ func serveStream(ctx context.Context, w http.ResponseWriter, r *http.Request, se
 	}
 }
 
+// openStream creates a stream to the service and sends the request, returning the stream
+// and the content type used for the messages
+func openStream(ctx context.Context, r *http.Request, service *api.Service, c client.Client) (client.Stream, string, error) {
+	ct := r.Header.Get("Content-Type")
+
+	// Strip charset from Content-Type (like `application/json; charset=UTF-8`)
+	if idx := strings.IndexRune(ct, ';'); idx >= 0 {
+		ct = ct[:idx]
+	}
+
+	payload, err := api.RequestPayload(r)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(payload) == 0 {
+		// make it valid json
+		payload = []byte("{}")
+	}
+
+	var request interface{}
+	if !bytes.Equal(payload, []byte(`{}`)) {
+		switch ct {
+		case "application/json", "":
+			// convert well known types to the form expected by the service
+			payload, err = api.Transcode(payload, requestValue(service))
+			if err != nil {
+				return nil, "", errors.BadRequest("go.micro.api", "invalid request: %v", err)
+			}
+			m := json.RawMessage(payload)
+			request = &m
+		default:
+			request = &raw.Frame{Data: payload}
+		}
+	}
+
+	// we always need to set content type for message
+	if ct == "" {
+		ct = "application/json"
+	}
+	req := c.NewRequest(
+		service.Name,
+		service.Endpoint.Name,
+		request,
+		client.WithContentType(ct),
+		client.StreamingRequest(),
+	)
+
+	// create custom router
+	callOpt := client.WithRouter(router.New(service.Services))
+
+	// create a new stream
+	stream, err := c.Stream(ctx, req, callOpt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// send request even if nil because it triggers the call in case server expects no input
+	// without this, we establish a connection but don't kick off the stream of communication
+	if err = stream.Send(request); err != nil {
+		stream.Close()
+		return nil, "", err
+	}
+
+	if ct == "application/json" {
+		stream = transcodeStream(stream, service)
+	}
+	return stream, ct, nil
+}
+
+// wktStream converts the well known types in the messages of the stream to their canonical form
+type wktStream struct {
+	client.Stream
+	value *registry.Value
+}
+
+func (s *wktStream) Response() client.Response {
+	return &wktResponse{Response: s.Stream.Response(), value: s.value}
+}
+
+type wktResponse struct {
+	client.Response
+	value *registry.Value
+}
+
+func (r *wktResponse) Read() ([]byte, error) {
+	b, err := r.Response.Read()
+	if err != nil {
+		return nil, err
+	}
+	b, err = api.Transcode(b, r.value)
+	if err != nil {
+		return nil, errors.InternalServerError("go.micro.api", "invalid response: %v", err)
+	}
+	return b, nil
+}
+
+// transcodeStream returns the stream with the messages it receives transcoded if the response
+// of the endpoint has well known types
+func transcodeStream(stream client.Stream, service *api.Service) client.Stream {
+	value := responseValue(service)
+	if value == nil {
+		return stream
+	}
+	return &wktStream{Stream: stream, value: value}
+}
+
+func writeStreamError(ctx context.Context, w http.ResponseWriter, err error) {
+	if _, ok := err.(*errors.Error); ok {
+		merr := handler.Error(ctx, err)
+		w.WriteHeader(int(merr.Code))
+		w.Write([]byte(merr.Error()))
+	} else {
+		w.WriteHeader(500)
+		w.Write([]byte(err.Error()))
+	}
+}
+
+// ndjsonLine compacts the message onto a single line and terminates it with a new line
+func ndjsonLine(buf []byte) []byte {
+	var b bytes.Buffer
+	if err := json.Compact(&b, buf); err != nil {
+		b.Reset()
+		b.Write(bytes.ReplaceAll(buf, []byte("\n"), nil))
+	}
+	b.WriteByte('\n')
+	return b.Bytes()
+}
+
 type stream struct {
 	// message type requested (binary or text)
 	messageType int
@@ -218,12 +321,57 @@ type stream struct {
 	stream client.Stream
 }
 
+// wsMessage is written to the client by the write loop, which is the only writer of the
+// connection. A close message ends the connection with its code and reason once written.
+type wsMessage struct {
+	data   []byte
+	close  bool
+	code   int
+	reason string
+}
+
+// closeMessage returns the message which ends the connection once the stream has returned the
+// error. The stream ending is a normal closure, otherwise the error is written before closing.
+func closeMessage(ctx context.Context, err error) wsMessage {
+	if err == io.EOF {
+		return wsMessage{close: true, code: websocket.CloseNormalClosure}
+	}
+	merr := handler.Error(ctx, err)
+	b, _ := json.Marshal(merr)
+	return wsMessage{data: b, close: true, code: closeCode(merr), reason: merr.Detail}
+}
+
+// closeCode maps the status code of an error to a websocket close code
+func closeCode(err *errors.Error) int {
+	switch err.Code {
+	case http.StatusBadRequest:
+		return websocket.CloseInvalidFramePayloadData
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return websocket.ClosePolicyViolation
+	case http.StatusRequestEntityTooLarge:
+		return websocket.CloseMessageTooBig
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusGatewayTimeout, http.StatusRequestTimeout:
+		return websocket.CloseTryAgainLater
+	default:
+		return websocket.CloseInternalServerErr
+	}
+}
+
+// writeClose writes the close frame, the reason is truncated to fit in a control frame
+func writeClose(conn *websocket.Conn, code int, reason string) error {
+	if len(reason) > maxCloseReason {
+		reason = reason[:maxCloseReason]
+	}
+	conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason))
+}
+
 func (s *stream) processWSReadsAndWrites() {
 	defer func() {
 		s.conn.Close()
 	}()
 
-	msgs := make(chan []byte)
+	msgs := make(chan wsMessage)
 
 	stopCtx, cancel := context.WithCancel(context.Background())
 	wg := sync.WaitGroup{}
@@ -251,9 +399,11 @@ func (s *stream) clientToServerLoop(cancel context.CancelFunc, wg *sync.WaitGrou
 		default:
 		}
 
+		// the client closing the connection closes the stream, the close frame is echoed back by
+		// the default close handler of the connection
 		_, msg, err := s.conn.ReadMessage()
 		if err != nil {
-			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
 					logger.Error(err)
 				}
@@ -280,7 +430,7 @@ func (s *stream) clientToServerLoop(cancel context.CancelFunc, wg *sync.WaitGrou
 
 }
 
-func (s *stream) rspToBufLoop(cancel context.CancelFunc, wg *sync.WaitGroup, stopCtx context.Context, msgs chan []byte) {
+func (s *stream) rspToBufLoop(cancel context.CancelFunc, wg *sync.WaitGroup, stopCtx context.Context, msgs chan wsMessage) {
 	defer func() {
 		cancel()
 		wg.Done()
@@ -294,27 +444,24 @@ func (s *stream) rspToBufLoop(cancel context.CancelFunc, wg *sync.WaitGroup, sto
 		}
 		bytes, err := rsp.Read()
 		if err != nil {
-			if err == io.EOF {
-				// clean exit
-				return
+			// the write loop writes the error and closes the connection
+			select {
+			case <-stopCtx.Done():
+			case msgs <- closeMessage(s.ctx, err):
 			}
-			// write error then close the connection
-			b, _ := json.Marshal(err)
-			s.conn.WriteMessage(s.messageType, b)
-			s.conn.WriteMessage(websocket.CloseAbnormalClosure, []byte{})
 			return
 		}
 		select {
 		case <-stopCtx.Done():
 			return
-		case msgs <- bytes:
+		case msgs <- wsMessage{data: bytes}:
 		}
 
 	}
 
 }
 
-func (s *stream) bufToClientLoop(cancel context.CancelFunc, wg *sync.WaitGroup, stopCtx context.Context, msgs chan []byte) {
+func (s *stream) bufToClientLoop(cancel context.CancelFunc, wg *sync.WaitGroup, stopCtx context.Context, msgs chan wsMessage) {
 	defer func() {
 		s.conn.Close()
 		cancel()
@@ -328,9 +475,10 @@ func (s *stream) bufToClientLoop(cancel context.CancelFunc, wg *sync.WaitGroup,
 		case <-stopCtx.Done():
 			return
 		case <-s.ctx.Done():
+			writeClose(s.conn, websocket.CloseGoingAway, "")
 			return
 		case <-s.stream.Context().Done():
-			s.conn.WriteMessage(websocket.CloseMessage, []byte{})
+			writeClose(s.conn, websocket.CloseNormalClosure, "")
 			return
 		case <-ticker.C:
 			s.conn.SetWriteDeadline(time.Now().Add(writeWait))
@@ -338,16 +486,22 @@ func (s *stream) bufToClientLoop(cancel context.CancelFunc, wg *sync.WaitGroup,
 				return
 			}
 		case msg := <-msgs:
-			// read response body
-			s.conn.SetWriteDeadline(time.Now().Add(writeWait))
-			w, err := s.conn.NextWriter(s.messageType)
-			if err != nil {
-				return
-			}
-			if _, err := w.Write(msg); err != nil {
-				return
+			// write the message body
+			if len(msg.data) > 0 {
+				s.conn.SetWriteDeadline(time.Now().Add(writeWait))
+				w, err := s.conn.NextWriter(s.messageType)
+				if err != nil {
+					return
+				}
+				if _, err := w.Write(msg.data); err != nil {
+					return
+				}
+				if err := w.Close(); err != nil {
+					return
+				}
 			}
-			if err := w.Close(); err != nil {
+			if msg.close {
+				writeClose(s.conn, msg.code, msg.reason)
 				return
 			}
 		}
@@ -357,13 +511,18 @@ func (s *stream) bufToClientLoop(cancel context.CancelFunc, wg *sync.WaitGroup,
 
 // serveWebsocket will stream rpc back over websockets assuming json
 func serveWebsocket(ctx context.Context, w http.ResponseWriter, r *http.Request, service *api.Service, c client.Client) {
+	// browsers fail the connection unless one of the subprotocols they asked for is accepted, the
+	// token passed as a subprotocol is only accepted if the client didn't ask for another
 	var rspHdr http.Header
-	// we use Sec-Websocket-Protocol to pass auth headers so just accept anything here
-	if prots := r.Header.Values("Sec-WebSocket-Protocol"); len(prots) > 0 {
-		rspHdr = http.Header{}
+	if prots := inauth.Subprotocols(r); len(prots) > 0 {
+		prot := prots[0]
 		for _, p := range prots {
-			rspHdr.Add("Sec-WebSocket-Protocol", p)
+			if !strings.HasPrefix(p, inauth.TokenProtocolPrefix) {
+				prot = p
+				break
+			}
 		}
+		rspHdr = http.Header{"Sec-Websocket-Protocol": []string{prot}}
 	}
 
 	conn, err := upgrader.Upgrade(w, r, rspHdr)
@@ -384,13 +543,17 @@ func serveWebsocket(ctx context.Context, w http.ResponseWriter, r *http.Request,
 		ct = "application/json"
 	}
 
-	// create stream
+	// create stream, the metadata of the upgrade request such as the auth token is in the context
 	req := c.NewRequest(service.Name, service.Endpoint.Name, nil, client.WithContentType(ct), client.StreamingRequest())
 	str, err := c.Stream(ctx, req, client.WithRouter(router.New(service.Services)))
 	if err != nil {
 		if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
 			logger.Error(err)
 		}
+		// the connection has been upgraded so the error is returned in the close frame
+		merr := errors.FromError(err)
+		writeClose(conn, closeCode(merr), merr.Detail)
+		conn.Close()
 		return
 	}
 
@@ -398,6 +561,7 @@ func serveWebsocket(ctx context.Context, w http.ResponseWriter, r *http.Request,
 	msgType := websocket.BinaryMessage
 	if ct == "application/json" {
 		msgType = websocket.TextMessage
+		str = transcodeStream(str, service)
 	}
 
 	s := stream{ctx: ctx, conn: conn, stream: str, messageType: msgType}
@@ -422,6 +586,42 @@ func isStream(r *http.Request, srv *api.Service) bool {
 	return false
 }
 
+// streamMode returns the mode used to serve the stream to http clients. The client may ask for a
+// mode with the Accept or Micro-Stream-Mode headers, otherwise the mode configured for the route is used.
+func streamMode(r *http.Request, srv *api.Service) string {
+	// clients continuing a poll
+	if len(r.Header.Get(pollIDHeader)) > 0 {
+		return api.StreamModeLongPoll
+	}
+	if mode := r.Header.Get(streamModeHeader); len(mode) > 0 {
+		return mode
+	}
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		if idx := strings.IndexRune(accept, ';'); idx >= 0 {
+			accept = accept[:idx]
+		}
+		switch strings.TrimSpace(accept) {
+		case ndjsonContentType, "application/ndjson":
+			return api.StreamModeNDJSON
+		case sseContentType:
+			return api.StreamModeSSE
+		}
+	}
+
+	if srv.Endpoint != nil && len(srv.Endpoint.StreamMode) > 0 {
+		return srv.Endpoint.StreamMode
+	}
+	for _, service := range srv.Services {
+		for _, ep := range service.Endpoints {
+			if ep.Name == srv.Endpoint.Name && len(ep.Metadata["stream_mode"]) > 0 {
+				return ep.Metadata["stream_mode"]
+			}
+		}
+	}
+
+	return ""
+}
+
 func isWebSocket(r *http.Request) bool {
 	contains := func(key, val string) bool {
 		vv := strings.Split(r.Header.Get(key), ",")
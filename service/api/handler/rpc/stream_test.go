@@ -0,0 +1,174 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/micro/micro/v3/service/api"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/registry"
+	inauth "github.com/micro/micro/v3/util/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+// echoStream returns each message sent to it
+type echoStream struct {
+	client.Stream
+	ctx context.Context
+	rsp *echoResponse
+}
+
+func (e *echoStream) Context() context.Context {
+	return e.ctx
+}
+
+func (e *echoStream) Response() client.Response {
+	return e.rsp
+}
+
+func (e *echoStream) Send(msg interface{}) error {
+	e.rsp.msgs <- []byte(*msg.(*json.RawMessage))
+	return nil
+}
+
+func (e *echoStream) Close() error {
+	return nil
+}
+
+// echoResponse returns the error once its messages are closed
+type echoResponse struct {
+	client.Response
+	msgs chan []byte
+	err  error
+}
+
+func (e *echoResponse) Read() ([]byte, error) {
+	msg, ok := <-e.msgs
+	if !ok {
+		return nil, e.err
+	}
+	return msg, nil
+}
+
+type echoClient struct {
+	client.Client
+	stream *echoStream
+}
+
+func (e *echoClient) NewRequest(service, endpoint string, req interface{}, opts ...client.RequestOption) client.Request {
+	return nil
+}
+
+func (e *echoClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	e.stream.ctx = ctx
+	return e.stream, nil
+}
+
+func TestServeWebsocket(t *testing.T) {
+	rsp := &echoResponse{msgs: make(chan []byte, 1)}
+	c := &echoClient{stream: &echoStream{rsp: rsp}}
+	srv := &api.Service{Name: "foo", Endpoint: &api.Endpoint{Name: "Foo.Stream"}}
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveWebsocket(r.Context(), w, r, srv, c)
+	}))
+	defer s.Close()
+
+	dialer := websocket.Dialer{Subprotocols: []string{inauth.TokenProtocolPrefix + "abc"}}
+	conn, hrsp, err := dialer.Dial("ws"+strings.TrimPrefix(s.URL, "http"), nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	// the token is accepted as the subprotocol since the client didn't ask for another
+	assert.Equal(t, inauth.TokenProtocolPrefix+"abc", hrsp.Header.Get("Sec-WebSocket-Protocol"))
+
+	// frames are sent to the stream and the messages it returns are sent back
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"count":1}`)))
+	mt, msg, err := conn.ReadMessage()
+	assert.NoError(t, err)
+	assert.Equal(t, websocket.TextMessage, mt)
+	assert.Equal(t, `{"count":1}`, string(msg))
+
+	// errors returned by the stream are written before the connection is closed
+	rsp.err = errors.Forbidden("foo", "not allowed")
+	close(rsp.msgs)
+
+	_, msg, err = conn.ReadMessage()
+	assert.NoError(t, err)
+	assert.Equal(t, "not allowed", errors.Parse(string(msg)).Detail)
+
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation))
+	assert.Equal(t, "not allowed", err.(*websocket.CloseError).Text)
+}
+
+func TestServeWebsocketMode(t *testing.T) {
+	srv := &api.Service{Name: "foo", Endpoint: &api.Endpoint{Name: "Foo.Stream", StreamMode: api.StreamModeWebSocket}}
+
+	// plain http requests to a route which only serves websockets are told to upgrade
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/foo/stream", strings.NewReader(`{}`))
+	serveStream(r.Context(), w, r, srv, &echoClient{})
+	assert.Equal(t, http.StatusUpgradeRequired, w.Code)
+	assert.Equal(t, "websocket", w.Header().Get("Upgrade"))
+}
+
+func TestCloseCode(t *testing.T) {
+	tt := []struct {
+		Name string
+		Err  error
+		Code int
+	}{
+		{Name: "BadRequest", Err: errors.BadRequest("foo", "bad"), Code: websocket.CloseInvalidFramePayloadData},
+		{Name: "Unauthorized", Err: errors.Unauthorized("foo", "who"), Code: websocket.ClosePolicyViolation},
+		{Name: "Forbidden", Err: errors.Forbidden("foo", "no"), Code: websocket.ClosePolicyViolation},
+		{Name: "Timeout", Err: errors.Timeout("foo", "slow"), Code: websocket.CloseTryAgainLater},
+		{Name: "Internal", Err: errors.InternalServerError("foo", "oops"), Code: websocket.CloseInternalServerErr},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Code, closeCode(errors.FromError(tc.Err)))
+		})
+	}
+}
+
+func TestServeStreamTranscode(t *testing.T) {
+	msg := &registry.Value{Name: "Event", Type: "Event", Values: []*registry.Value{
+		{Name: "created", Type: "google.protobuf.Timestamp"},
+	}}
+	srv := &api.Service{
+		Name:     "foo",
+		Endpoint: &api.Endpoint{Name: "Foo.Stream"},
+		Services: []*registry.Service{{Name: "foo", Endpoints: []*registry.Endpoint{
+			{Name: "Foo.Stream", Request: msg, Response: msg},
+		}}},
+	}
+
+	t.Run("Response", func(t *testing.T) {
+		rsp := &echoResponse{msgs: make(chan []byte, 1), err: io.EOF}
+		rsp.msgs <- []byte(`{"created":{"seconds":1600000000}}`)
+		close(rsp.msgs)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/foo/stream", strings.NewReader(`{"created":1600000000}`))
+		r.Header.Set("Content-Type", "application/json")
+		serveStream(r.Context(), w, r, srv, &sseClient{stream: &sseStream{rsp: rsp}})
+		assert.Equal(t, `{"created":"2020-09-13T12:26:40Z"}`, w.Body.String())
+	})
+
+	t.Run("InvalidRequest", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/foo/stream", strings.NewReader(`{"created":`))
+		r.Header.Set("Content-Type", "application/json")
+		serveStream(r.Context(), w, r, srv, &sseClient{stream: &sseStream{}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,668 @@
+// Package scim provides a handler which serves the SCIM 2.0 protocol (RFC 7643, RFC 7644) so users
+// and groups can be provisioned by an identity provider, backed by the auth service
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/api/handler"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/util/ctx"
+	"github.com/micro/micro/v3/util/namespace"
+)
+
+const (
+	Handler = "scim"
+
+	// Path the handler is served at
+	Path = "/scim/v2"
+
+	contentType = "application/scim+json"
+
+	schemaUser      = "urn:ietf:params:scim:schemas:core:2.0:User"
+	schemaGroup     = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	schemaList      = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	schemaError     = "urn:ietf:params:scim:api:messages:2.0:Error"
+	schemaSPConfig  = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	maxResults      = 1000
+	defaultPageSize = 100
+)
+
+type scimHandler struct {
+	opts handler.Options
+}
+
+func (h *scimHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.opts.MaxRecvSize)
+
+	// e.g. /scim/v2/Users/1234 => [Users, 1234]
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, Path), "/"), "/")
+	resource := parts[0]
+	var id string
+	if len(parts) > 1 {
+		id = parts[1]
+	}
+	if len(parts) > 2 {
+		writeError(w, errors.NotFound("scim", "Resource not found"))
+		return
+	}
+
+	// the namespace is set by the auth wrapper based on the domain requested
+	cx := ctx.FromRequest(r)
+	req := &request{
+		r:    r,
+		w:    w,
+		id:   id,
+		opts: &pb.Options{Namespace: namespace.FromContext(cx)},
+		srv:  pb.NewSCIMService("auth", h.opts.Client),
+	}
+
+	switch resource {
+	case "ServiceProviderConfig":
+		writeJSON(w, http.StatusOK, serviceProviderConfig)
+	case "Users":
+		req.users()
+	case "Groups":
+		req.groups()
+	default:
+		writeError(w, errors.NotFound("scim", "Resource not found"))
+	}
+}
+
+func (h *scimHandler) String() string {
+	return Handler
+}
+
+type meta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Location     string `json:"location,omitempty"`
+}
+
+type value struct {
+	Value   string `json:"value"`
+	Primary bool   `json:"primary,omitempty"`
+	Display string `json:"display,omitempty"`
+}
+
+type user struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"externalId,omitempty"`
+	UserName    string   `json:"userName"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Name        *struct {
+		Formatted string `json:"formatted,omitempty"`
+	} `json:"name,omitempty"`
+	Active *bool   `json:"active,omitempty"`
+	Emails []value `json:"emails,omitempty"`
+	Groups []value `json:"groups,omitempty"`
+	Meta   *meta   `json:"meta,omitempty"`
+}
+
+type group struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"externalId,omitempty"`
+	DisplayName string   `json:"displayName"`
+	Members     []value  `json:"members,omitempty"`
+	Meta        *meta    `json:"meta,omitempty"`
+}
+
+type listResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int64         `json:"totalResults"`
+	StartIndex   int64         `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+type patchRequest struct {
+	Schemas    []string    `json:"schemas"`
+	Operations []operation `json:"Operations"`
+}
+
+type operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+type errorResponse struct {
+	Schemas []string `json:"schemas"`
+	Status  string   `json:"status"`
+	Detail  string   `json:"detail,omitempty"`
+}
+
+var serviceProviderConfig = map[string]interface{}{
+	"schemas":        []string{schemaSPConfig},
+	"patch":          map[string]bool{"supported": true},
+	"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+	"filter":         map[string]interface{}{"supported": true, "maxResults": maxResults},
+	"changePassword": map[string]bool{"supported": false},
+	"sort":           map[string]bool{"supported": false},
+	"etag":           map[string]bool{"supported": false},
+	"authenticationSchemes": []map[string]interface{}{{
+		"type":        "oauthbearertoken",
+		"name":        "OAuth Bearer Token",
+		"description": "Authentication using a micro token with admin scope in the namespace",
+		"primary":     true,
+	}},
+}
+
+type request struct {
+	r    *http.Request
+	w    http.ResponseWriter
+	id   string
+	opts *pb.Options
+	srv  pb.SCIMService
+}
+
+func (q *request) users() {
+	cx := ctx.FromRequest(q.r)
+
+	switch {
+	case q.r.Method == "GET" && len(q.id) == 0:
+		filter, err := parseFilter(q.r.URL.Query().Get("filter"), "userName")
+		if err != nil {
+			writeError(q.w, err)
+			return
+		}
+		start, count := pagination(q.r)
+		rsp, err := q.srv.ListUsers(cx, &pb.ListSCIMUsersRequest{
+			UserName:   filter,
+			StartIndex: start,
+			Count:      count,
+			Options:    q.opts,
+		})
+		if err != nil {
+			writeError(q.w, err)
+			return
+		}
+		list := &listResponse{
+			Schemas:      []string{schemaList},
+			TotalResults: rsp.Total,
+			StartIndex:   start,
+			ItemsPerPage: len(rsp.Users),
+			Resources:    make([]interface{}, 0, len(rsp.Users)),
+		}
+		for _, u := range rsp.Users {
+			list.Resources = append(list.Resources, q.user(u))
+		}
+		writeJSON(q.w, http.StatusOK, list)
+	case q.r.Method == "GET":
+		rsp, err := q.srv.ReadUser(cx, &pb.ReadSCIMUserRequest{Id: q.id, Options: q.opts})
+		if err != nil {
+			writeError(q.w, err)
+			return
+		}
+		writeJSON(q.w, http.StatusOK, q.user(rsp.User))
+	case q.r.Method == "POST" && len(q.id) == 0:
+		var u user
+		if err := json.NewDecoder(q.r.Body).Decode(&u); err != nil {
+			writeError(q.w, errors.BadRequest("scim", "Invalid user: %v", err))
+			return
+		}
+		rsp, err := q.srv.CreateUser(cx, &pb.SCIMUserRequest{User: deserializeUser(&u), Options: q.opts})
+		if err != nil {
+			writeError(q.w, err)
+			return
+		}
+		writeJSON(q.w, http.StatusCreated, q.user(rsp.User))
+	case q.r.Method == "PUT" && len(q.id) > 0:
+		var u user
+		if err := json.NewDecoder(q.r.Body).Decode(&u); err != nil {
+			writeError(q.w, errors.BadRequest("scim", "Invalid user: %v", err))
+			return
+		}
+		pu := deserializeUser(&u)
+		pu.Id = q.id
+		rsp, err := q.srv.UpdateUser(cx, &pb.SCIMUserRequest{User: pu, Options: q.opts})
+		if err != nil {
+			writeError(q.w, err)
+			return
+		}
+		writeJSON(q.w, http.StatusOK, q.user(rsp.User))
+	case q.r.Method == "PATCH" && len(q.id) > 0:
+		var p patchRequest
+		if err := json.NewDecoder(q.r.Body).Decode(&p); err != nil {
+			writeError(q.w, errors.BadRequest("scim", "Invalid patch: %v", err))
+			return
+		}
+		rsp, err := q.srv.ReadUser(cx, &pb.ReadSCIMUserRequest{Id: q.id, Options: q.opts})
+		if err != nil {
+			writeError(q.w, err)
+			return
+		}
+		if err := patchUser(rsp.User, p.Operations); err != nil {
+			writeError(q.w, err)
+			return
+		}
+		rsp, err = q.srv.UpdateUser(cx, &pb.SCIMUserRequest{User: rsp.User, Options: q.opts})
+		if err != nil {
+			writeError(q.w, err)
+			return
+		}
+		writeJSON(q.w, http.StatusOK, q.user(rsp.User))
+	case q.r.Method == "DELETE" && len(q.id) > 0:
+		if _, err := q.srv.DeleteUser(cx, &pb.DeleteSCIMUserRequest{Id: q.id, Options: q.opts}); err != nil {
+			writeError(q.w, err)
+			return
+		}
+		q.w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(q.w, errors.MethodNotAllowed("scim", "Method not allowed"))
+	}
+}
+
+func (q *request) groups() {
+	cx := ctx.FromRequest(q.r)
+
+	switch {
+	case q.r.Method == "GET" && len(q.id) == 0:
+		filter, err := parseFilter(q.r.URL.Query().Get("filter"), "displayName")
+		if err != nil {
+			writeError(q.w, err)
+			return
+		}
+		start, count := pagination(q.r)
+		rsp, err := q.srv.ListGroups(cx, &pb.ListSCIMGroupsRequest{
+			DisplayName: filter,
+			StartIndex:  start,
+			Count:       count,
+			Options:     q.opts,
+		})
+		if err != nil {
+			writeError(q.w, err)
+			return
+		}
+		list := &listResponse{
+			Schemas:      []string{schemaList},
+			TotalResults: rsp.Total,
+			StartIndex:   start,
+			ItemsPerPage: len(rsp.Groups),
+			Resources:    make([]interface{}, 0, len(rsp.Groups)),
+		}
+		for _, g := range rsp.Groups {
+			list.Resources = append(list.Resources, q.group(g))
+		}
+		writeJSON(q.w, http.StatusOK, list)
+	case q.r.Method == "GET":
+		rsp, err := q.srv.ReadGroup(cx, &pb.ReadSCIMGroupRequest{Id: q.id, Options: q.opts})
+		if err != nil {
+			writeError(q.w, err)
+			return
+		}
+		writeJSON(q.w, http.StatusOK, q.group(rsp.Group))
+	case q.r.Method == "POST" && len(q.id) == 0:
+		var g group
+		if err := json.NewDecoder(q.r.Body).Decode(&g); err != nil {
+			writeError(q.w, errors.BadRequest("scim", "Invalid group: %v", err))
+			return
+		}
+		rsp, err := q.srv.CreateGroup(cx, &pb.SCIMGroupRequest{Group: deserializeGroup(&g), Options: q.opts})
+		if err != nil {
+			writeError(q.w, err)
+			return
+		}
+		writeJSON(q.w, http.StatusCreated, q.group(rsp.Group))
+	case q.r.Method == "PUT" && len(q.id) > 0:
+		var g group
+		if err := json.NewDecoder(q.r.Body).Decode(&g); err != nil {
+			writeError(q.w, errors.BadRequest("scim", "Invalid group: %v", err))
+			return
+		}
+		pg := deserializeGroup(&g)
+		pg.Id = q.id
+		rsp, err := q.srv.UpdateGroup(cx, &pb.SCIMGroupRequest{Group: pg, Options: q.opts})
+		if err != nil {
+			writeError(q.w, err)
+			return
+		}
+		writeJSON(q.w, http.StatusOK, q.group(rsp.Group))
+	case q.r.Method == "PATCH" && len(q.id) > 0:
+		var p patchRequest
+		if err := json.NewDecoder(q.r.Body).Decode(&p); err != nil {
+			writeError(q.w, errors.BadRequest("scim", "Invalid patch: %v", err))
+			return
+		}
+		rsp, err := q.srv.ReadGroup(cx, &pb.ReadSCIMGroupRequest{Id: q.id, Options: q.opts})
+		if err != nil {
+			writeError(q.w, err)
+			return
+		}
+		if err := patchGroup(rsp.Group, p.Operations); err != nil {
+			writeError(q.w, err)
+			return
+		}
+		rsp, err = q.srv.UpdateGroup(cx, &pb.SCIMGroupRequest{Group: rsp.Group, Options: q.opts})
+		if err != nil {
+			writeError(q.w, err)
+			return
+		}
+		writeJSON(q.w, http.StatusOK, q.group(rsp.Group))
+	case q.r.Method == "DELETE" && len(q.id) > 0:
+		if _, err := q.srv.DeleteGroup(cx, &pb.DeleteSCIMGroupRequest{Id: q.id, Options: q.opts}); err != nil {
+			writeError(q.w, err)
+			return
+		}
+		q.w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(q.w, errors.MethodNotAllowed("scim", "Method not allowed"))
+	}
+}
+
+func (q *request) user(u *pb.SCIMUser) *user {
+	active := u.Active
+	rsp := &user{
+		Schemas:     []string{schemaUser},
+		ID:          u.Id,
+		ExternalID:  u.ExternalId,
+		UserName:    u.UserName,
+		DisplayName: u.DisplayName,
+		Active:      &active,
+		Meta: &meta{
+			ResourceType: "User",
+			Created:      timestamp(u.Created),
+			LastModified: timestamp(u.Updated),
+			Location:     Path + "/Users/" + u.Id,
+		},
+	}
+	for i, e := range u.Emails {
+		rsp.Emails = append(rsp.Emails, value{Value: e, Primary: i == 0})
+	}
+	for _, g := range u.Groups {
+		rsp.Groups = append(rsp.Groups, value{Value: g})
+	}
+	return rsp
+}
+
+func (q *request) group(g *pb.SCIMGroup) *group {
+	rsp := &group{
+		Schemas:     []string{schemaGroup},
+		ID:          g.Id,
+		ExternalID:  g.ExternalId,
+		DisplayName: g.DisplayName,
+		Meta: &meta{
+			ResourceType: "Group",
+			Created:      timestamp(g.Created),
+			LastModified: timestamp(g.Updated),
+			Location:     Path + "/Groups/" + g.Id,
+		},
+	}
+	for _, m := range g.Members {
+		rsp.Members = append(rsp.Members, value{Value: m})
+	}
+	return rsp
+}
+
+func deserializeUser(u *user) *pb.SCIMUser {
+	rsp := &pb.SCIMUser{
+		ExternalId:  u.ExternalID,
+		UserName:    u.UserName,
+		DisplayName: u.DisplayName,
+		// users are active unless provisioned otherwise
+		Active: u.Active == nil || *u.Active,
+	}
+	if len(rsp.DisplayName) == 0 && u.Name != nil {
+		rsp.DisplayName = u.Name.Formatted
+	}
+	rsp.Emails = emails(u.Emails)
+	return rsp
+}
+
+func deserializeGroup(g *group) *pb.SCIMGroup {
+	rsp := &pb.SCIMGroup{
+		ExternalId:  g.ExternalID,
+		DisplayName: g.DisplayName,
+	}
+	for _, m := range g.Members {
+		rsp.Members = append(rsp.Members, m.Value)
+	}
+	return rsp
+}
+
+// emails returns the email addresses with the primary address first
+func emails(values []value) []string {
+	var result []string
+	for _, e := range values {
+		if e.Primary {
+			result = append([]string{e.Value}, result...)
+		} else {
+			result = append(result, e.Value)
+		}
+	}
+	return result
+}
+
+// patchUser applies the patch operations to the user, the attributes identity providers
+// commonly patch are supported
+func patchUser(u *pb.SCIMUser, ops []operation) error {
+	for _, op := range ops {
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+		default:
+			return errors.BadRequest("scim", "Unsupported patch operation %v", op.Op)
+		}
+
+		// without a path the value is an object of the attributes to replace
+		attrs := map[string]json.RawMessage{}
+		if len(op.Path) == 0 {
+			if err := json.Unmarshal(op.Value, &attrs); err != nil {
+				return errors.BadRequest("scim", "Invalid patch value: %v", err)
+			}
+		} else {
+			attrs[op.Path] = op.Value
+		}
+
+		for path, val := range attrs {
+			var err error
+			switch path {
+			case "active":
+				err = unmarshalBool(val, &u.Active)
+			case "userName":
+				err = json.Unmarshal(val, &u.UserName)
+			case "displayName", "name.formatted":
+				err = json.Unmarshal(val, &u.DisplayName)
+			case "externalId":
+				err = json.Unmarshal(val, &u.ExternalId)
+			case "emails":
+				var v []value
+				err = json.Unmarshal(val, &v)
+				u.Emails = emails(v)
+			default:
+				// attributes which micro doesn't store are ignored
+				continue
+			}
+			if err != nil {
+				return errors.BadRequest("scim", "Invalid value for %v: %v", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// patchGroup applies the patch operations to the group, supporting changes to the display name
+// and members
+func patchGroup(g *pb.SCIMGroup, ops []operation) error {
+	for _, op := range ops {
+		path := op.Path
+
+		// members[value eq "id"] removes a single member
+		var member string
+		if strings.HasPrefix(path, "members[") {
+			filter, err := parseFilter(strings.TrimSuffix(strings.TrimPrefix(path, "members["), "]"), "value")
+			if err != nil {
+				return err
+			}
+			path, member = "members", filter
+		}
+
+		switch strings.ToLower(op.Op) + " " + path {
+		case "replace displayName", "add displayName":
+			if err := json.Unmarshal(op.Value, &g.DisplayName); err != nil {
+				return errors.BadRequest("scim", "Invalid value for displayName: %v", err)
+			}
+		case "replace externalId", "add externalId":
+			if err := json.Unmarshal(op.Value, &g.ExternalId); err != nil {
+				return errors.BadRequest("scim", "Invalid value for externalId: %v", err)
+			}
+		case "replace ":
+			var v group
+			if err := json.Unmarshal(op.Value, &v); err != nil {
+				return errors.BadRequest("scim", "Invalid patch value: %v", err)
+			}
+			if len(v.DisplayName) > 0 {
+				g.DisplayName = v.DisplayName
+			}
+			if len(v.ExternalID) > 0 {
+				g.ExternalId = v.ExternalID
+			}
+		case "add members", "replace members", "remove members":
+			var values []value
+			if len(op.Value) > 0 {
+				if err := json.Unmarshal(op.Value, &values); err != nil {
+					return errors.BadRequest("scim", "Invalid value for members: %v", err)
+				}
+			}
+			if len(member) > 0 {
+				values = append(values, value{Value: member})
+			}
+
+			switch strings.ToLower(op.Op) {
+			case "add":
+				for _, v := range values {
+					if !contains(g.Members, v.Value) {
+						g.Members = append(g.Members, v.Value)
+					}
+				}
+			case "replace":
+				g.Members = nil
+				for _, v := range values {
+					g.Members = append(g.Members, v.Value)
+				}
+			case "remove":
+				// removing without a value removes all the members
+				if len(values) == 0 {
+					g.Members = nil
+				}
+				for _, v := range values {
+					g.Members = without(g.Members, v.Value)
+				}
+			}
+		default:
+			return errors.BadRequest("scim", "Unsupported patch operation %v %v", op.Op, op.Path)
+		}
+	}
+	return nil
+}
+
+// parseFilter supports the equality filter on a single attribute, e.g. userName eq "john",
+// which is used by identity providers to find existing resources
+func parseFilter(filter, attr string) (string, error) {
+	if len(filter) == 0 {
+		return "", nil
+	}
+	parts := strings.SplitN(strings.TrimSpace(filter), " ", 3)
+	if len(parts) != 3 || !strings.EqualFold(parts[0], attr) || !strings.EqualFold(parts[1], "eq") {
+		return "", errors.BadRequest("scim", "Unsupported filter %v", filter)
+	}
+	v, err := strconv.Unquote(parts[2])
+	if err != nil {
+		return "", errors.BadRequest("scim", "Invalid filter %v", filter)
+	}
+	return v, nil
+}
+
+func pagination(r *http.Request) (int64, int64) {
+	start, _ := strconv.ParseInt(r.URL.Query().Get("startIndex"), 10, 64)
+	if start < 1 {
+		start = 1
+	}
+	count, err := strconv.ParseInt(r.URL.Query().Get("count"), 10, 64)
+	if err != nil || count < 0 {
+		count = defaultPageSize
+	}
+	if count > maxResults {
+		count = maxResults
+	}
+	return start, count
+}
+
+// unmarshalBool accepts both booleans and the strings some identity providers send
+func unmarshalBool(v json.RawMessage, b *bool) error {
+	var s string
+	if err := json.Unmarshal(v, &s); err == nil {
+		parsed, err := strconv.ParseBool(strings.ToLower(s))
+		*b = parsed
+		return err
+	}
+	return json.Unmarshal(v, b)
+}
+
+func timestamp(t int64) string {
+	if t == 0 {
+		return ""
+	}
+	return time.Unix(t, 0).UTC().Format(time.RFC3339)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func without(list []string, s string) []string {
+	var result []string
+	for _, v := range list {
+		if v != s {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	merr, ok := err.(*errors.Error)
+	if !ok {
+		merr = errors.Parse(err.Error())
+	}
+	if merr.Code == 0 {
+		logger.Errorf("Error handling scim request: %v", err)
+		merr.Code = http.StatusInternalServerError
+	}
+	writeJSON(w, int(merr.Code), &errorResponse{
+		Schemas: []string{schemaError},
+		Status:  strconv.Itoa(int(merr.Code)),
+		Detail:  merr.Detail,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	b, _ := json.Marshal(v)
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+
+	if _, err := w.Write(b); err != nil {
+		logger.Error(err)
+	}
+}
+
+// NewHandler returns a handler which serves the SCIM protocol
+func NewHandler(opts ...handler.Option) handler.Handler {
+	return &scimHandler{
+		opts: handler.NewOptions(opts...),
+	}
+}
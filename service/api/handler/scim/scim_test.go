@@ -0,0 +1,121 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/api/handler"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/client/grpc"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilter(t *testing.T) {
+	v, err := parseFilter(`userName eq "john@example.com"`, "userName")
+	assert.Nil(t, err)
+	assert.Equal(t, "john@example.com", v)
+
+	v, err = parseFilter("", "userName")
+	assert.Nil(t, err)
+	assert.Equal(t, "", v)
+
+	_, err = parseFilter(`userName co "john"`, "userName")
+	assert.NotNil(t, err)
+	_, err = parseFilter(`displayName eq "john"`, "userName")
+	assert.NotNil(t, err)
+}
+
+func TestPatchUser(t *testing.T) {
+	u := &pb.SCIMUser{UserName: "john", Active: true}
+
+	ops := []operation{
+		{Op: "Replace", Path: "active", Value: json.RawMessage(`"False"`)},
+		{Op: "replace", Value: json.RawMessage(`{"displayName":"John Smith","emails":[{"value":"b@example.com"},{"value":"a@example.com","primary":true}]}`)},
+	}
+	assert.Nil(t, patchUser(u, ops))
+	assert.False(t, u.Active)
+	assert.Equal(t, "John Smith", u.DisplayName)
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, u.Emails)
+
+	assert.NotNil(t, patchUser(u, []operation{{Op: "remove", Path: "emails"}}))
+}
+
+func TestPatchGroup(t *testing.T) {
+	g := &pb.SCIMGroup{DisplayName: "Engineering", Members: []string{"1"}}
+
+	ops := []operation{
+		{Op: "add", Path: "members", Value: json.RawMessage(`[{"value":"2"},{"value":"3"}]`)},
+		{Op: "remove", Path: `members[value eq "1"]`},
+		{Op: "replace", Path: "displayName", Value: json.RawMessage(`"Platform"`)},
+	}
+	assert.Nil(t, patchGroup(g, ops))
+	assert.Equal(t, []string{"2", "3"}, g.Members)
+	assert.Equal(t, "Platform", g.DisplayName)
+
+	assert.Nil(t, patchGroup(g, []operation{{Op: "remove", Path: "members"}}))
+	assert.Empty(t, g.Members)
+}
+
+// testClient returns the users set rather than calling the auth service
+type testClient struct {
+	client.Client
+	users []*pb.SCIMUser
+}
+
+func (t *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	switch r := req.Body().(type) {
+	case *pb.ListSCIMUsersRequest:
+		out := rsp.(*pb.ListSCIMUsersResponse)
+		for _, u := range t.users {
+			if len(r.UserName) == 0 || u.UserName == r.UserName {
+				out.Users = append(out.Users, u)
+			}
+		}
+		out.Total = int64(len(out.Users))
+	case *pb.ReadSCIMUserRequest:
+		return errors.NotFound("auth.SCIM.ReadUser", "User not found")
+	}
+	return nil
+}
+
+func TestUsers(t *testing.T) {
+	c := &testClient{
+		Client: grpc.NewClient(),
+		users: []*pb.SCIMUser{
+			{Id: "1", UserName: "john", Active: true, Emails: []string{"john@example.com"}},
+			{Id: "2", UserName: "jane", Active: false},
+		},
+	}
+	h := NewHandler(handler.WithClient(c))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", `/scim/v2/Users?filter=userName+eq+"john"`, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, contentType, w.Header().Get("Content-Type"))
+
+	var list struct {
+		TotalResults int64  `json:"totalResults"`
+		Resources    []user `json:"Resources"`
+	}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, int64(1), list.TotalResults)
+	assert.Equal(t, "john", list.Resources[0].UserName)
+	assert.True(t, *list.Resources[0].Active)
+	assert.Equal(t, []value{{Value: "john@example.com", Primary: true}}, list.Resources[0].Emails)
+	assert.Equal(t, "/scim/v2/Users/1", list.Resources[0].Meta.Location)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/scim/v2/Users/3", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var rsp errorResponse
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &rsp))
+	assert.Equal(t, []string{schemaError}, rsp.Schemas)
+	assert.Equal(t, "404", rsp.Status)
+	assert.Equal(t, "User not found", rsp.Detail)
+}
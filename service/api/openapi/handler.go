@@ -0,0 +1,97 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/util/namespace"
+)
+
+// Path the document is served at by the api gateway
+const Path = "/openapi.json"
+
+type openapiHandler struct {
+	prefix string
+}
+
+// NewHandler returns a handler which serves the document of the namespace of the request, as set
+// by the auth wrapper. If the api has a service prefix then only the services with the prefix are
+// included, with the prefix removed as it is by the resolver.
+func NewHandler(prefix string) http.Handler {
+	return &openapiHandler{prefix: prefix}
+}
+
+func (h *openapiHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == "OPTIONS" {
+		return
+	}
+	if r.Method != "GET" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ns := r.Header.Get(namespace.NamespaceKey)
+	if len(ns) == 0 {
+		ns = namespace.DefaultNamespace
+	}
+
+	services, err := Services(ns, h.prefix)
+	if err != nil {
+		logger.Errorf("Error listing the services of %v: %v", ns, err)
+		http.Error(w, "error listing services", http.StatusInternalServerError)
+		return
+	}
+
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	doc := Generate(fmt.Sprintf("micro %s", ns), scheme+"://"+r.Host, services)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(doc)
+}
+
+// Services returns the services of the namespace with the prefix, which is removed from their
+// names. Only the first version of each service is returned.
+func Services(ns, prefix string) ([]*registry.Service, error) {
+	list, err := registry.DefaultRegistry.ListServices(registry.ListDomain(ns))
+	if err != nil {
+		return nil, err
+	}
+
+	var services []*registry.Service
+	seen := map[string]bool{}
+	for _, s := range list {
+		// a service is listed for each of its versions
+		if seen[s.Name] {
+			continue
+		}
+		seen[s.Name] = true
+
+		name := s.Name
+		if len(prefix) > 0 {
+			if !strings.HasPrefix(name, prefix+".") {
+				continue
+			}
+			name = strings.TrimPrefix(name, prefix+".")
+		}
+
+		srvs, err := registry.DefaultRegistry.GetService(s.Name, registry.GetDomain(ns))
+		if err == registry.ErrNotFound || len(srvs) == 0 {
+			// the service was deregistered since it was listed
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		srv := *srvs[0]
+		srv.Name = name
+		services = append(services, &srv)
+	}
+	return services, nil
+}
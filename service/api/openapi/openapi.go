@@ -0,0 +1,314 @@
+// Package openapi generates OpenAPI 3 documents describing the endpoints served by the api
+// gateway from the endpoints of the services in the registry, so API docs and clients can be
+// generated without maintaining specs by hand.
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/micro/micro/v3/service/api"
+	"github.com/micro/micro/v3/service/registry"
+)
+
+// Version of the OpenAPI specification the documents conform to
+const Version = "3.0.3"
+
+// maxDepth of the nested types described, beyond it recursive types are left undescribed
+const maxDepth = 5
+
+// params matches the variables of a path template, e.g. {id} or {name=users/*}
+var params = regexp.MustCompile(`\{([a-zA-Z0-9_.]+)(=[^}]*)?\}`)
+
+// Document is an OpenAPI 3 document
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Servers    []*Server             `json:"servers,omitempty"`
+	Paths      map[string]*PathItem  `json:"paths"`
+	Components *Components           `json:"components,omitempty"`
+	Security   []map[string][]string `json:"security,omitempty"`
+	Tags       []*Tag                `json:"tags,omitempty"`
+}
+
+// Info describes the api
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Server the api is served by
+type Server struct {
+	URL string `json:"url"`
+}
+
+// Tag groups the operations of a service
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem holds the operations of a path by HTTP method
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty"`
+	Put    *Operation `json:"put,omitempty"`
+	Post   *Operation `json:"post,omitempty"`
+	Delete *Operation `json:"delete,omitempty"`
+	Patch  *Operation `json:"patch,omitempty"`
+}
+
+// Operation is a call to an endpoint
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter of an operation passed in the path or query
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody of an operation
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response of an operation
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType describes the encoding of a body
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema describes a value
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// Components holds the security schemes of the document
+type Components struct {
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme used to authenticate requests
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+// Route returns the HTTP method and path an endpoint is served at by the api gateway, using the
+// path and method of the api endpoint if one is set. False is returned for endpoints which can't
+// be called via the api, e.g. subscribers, or which are only served at a regex path.
+func Route(service string, ep *registry.Endpoint) (string, string, bool) {
+	parts := strings.Split(ep.Name, ".")
+	if len(parts) != 2 || ep.Metadata["subscriber"] == "true" {
+		return "", "", false
+	}
+
+	method := "POST"
+	path := fmt.Sprintf("/%s/%s/%s", service, parts[0], parts[1])
+	if e := api.Decode(ep.Metadata); e != nil && len(e.Path) > 0 {
+		// regex paths can't be called as is
+		if p := e.Path[0]; !strings.HasPrefix(p, "^") {
+			path = p
+		}
+		if len(e.Method) > 0 {
+			method = strings.ToUpper(e.Method[0])
+		}
+	}
+	return method, path, true
+}
+
+// Generate returns a document describing the endpoints of the services, served by the api
+// gateway at the address. Requests are authenticated with a bearer token.
+func Generate(title, address string, services []*registry.Service) *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: title, Version: "1.0.0"},
+		Paths:   map[string]*PathItem{},
+		Components: &Components{SecuritySchemes: map[string]*SecurityScheme{
+			"bearer": {Type: "http", Scheme: "bearer"},
+		}},
+		Security: []map[string][]string{{"bearer": {}}},
+	}
+	if len(address) > 0 {
+		doc.Servers = []*Server{{URL: address}}
+	}
+
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	for _, s := range services {
+		var tagged bool
+		for _, ep := range s.Endpoints {
+			method, path, ok := Route(s.Name, ep)
+			if !ok {
+				continue
+			}
+			path = params.ReplaceAllString(path, "{$1}")
+
+			item, ok := doc.Paths[path]
+			if !ok {
+				item = &PathItem{}
+			}
+			if !item.set(method, operation(s.Name, method, path, ep)) {
+				continue
+			}
+			doc.Paths[path] = item
+
+			if !tagged {
+				doc.Tags = append(doc.Tags, &Tag{Name: s.Name})
+				tagged = true
+			}
+		}
+	}
+
+	return doc
+}
+
+// set the operation of the method, false is returned if the method isn't supported
+func (p *PathItem) set(method string, op *Operation) bool {
+	switch method {
+	case "GET":
+		p.Get = op
+	case "PUT":
+		p.Put = op
+	case "POST":
+		p.Post = op
+	case "DELETE":
+		p.Delete = op
+	case "PATCH":
+		p.Patch = op
+	default:
+		return false
+	}
+	return true
+}
+
+func operation(service, method, path string, ep *registry.Endpoint) *Operation {
+	op := &Operation{
+		OperationID: service + "." + ep.Name,
+		Summary:     ep.Metadata["description"],
+		Tags:        []string{service},
+	}
+
+	// variables of the path are set in the request
+	inPath := map[string]bool{}
+	for _, m := range params.FindAllStringSubmatch(path, -1) {
+		inPath[m[1]] = true
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name:     m[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+
+	req := schema(ep.Request, 0)
+	if method == "GET" || method == "DELETE" {
+		// the remaining fields are read from the query
+		names := make([]string, 0, len(req.Properties))
+		for name := range req.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			p := req.Properties[name]
+			if inPath[name] || p.Type == "object" {
+				continue
+			}
+			op.Parameters = append(op.Parameters, &Parameter{Name: name, In: "query", Schema: p})
+		}
+	} else {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{"application/json": {Schema: req}},
+		}
+	}
+
+	rsp := &Response{
+		Description: "Successful response",
+		Content:     map[string]*MediaType{"application/json": {Schema: schema(ep.Response, 0)}},
+	}
+	if ep.Metadata["stream"] == "true" {
+		// streams are written as a line of JSON per message when requested
+		rsp.Description = "Stream of responses"
+		rsp.Content["application/x-ndjson"] = rsp.Content["application/json"]
+	}
+	op.Responses = map[string]*Response{"200": rsp, "default": {Description: "Error response"}}
+
+	return op
+}
+
+// schema returns the schema of a registered type
+func schema(v *registry.Value, depth int) *Schema {
+	if v == nil {
+		return &Schema{Type: "object"}
+	}
+	if len(v.Values) > 0 {
+		s := &Schema{Type: "object"}
+		// stop at recursive types
+		if depth > maxDepth {
+			return s
+		}
+		s.Properties = map[string]*Schema{}
+		for _, f := range v.Values {
+			s.Properties[f.Name] = schema(f, depth+1)
+		}
+		return s
+	}
+	return schemaType(v.Type)
+}
+
+func schemaType(typ string) *Schema {
+	switch {
+	case typ == "[]uint8":
+		return &Schema{Type: "string", Format: "byte"}
+	case strings.HasPrefix(typ, "[]"):
+		return &Schema{Type: "array", Items: schemaType(strings.TrimPrefix(typ, "[]"))}
+	case strings.HasPrefix(typ, "map["):
+		if i := strings.Index(typ, "]"); i > 0 {
+			return &Schema{Type: "object", AdditionalProperties: schemaType(typ[i+1:])}
+		}
+	}
+
+	switch typ {
+	case "string":
+		return &Schema{Type: "string"}
+	case "bool":
+		return &Schema{Type: "boolean"}
+	case "int32", "uint32":
+		return &Schema{Type: "integer", Format: "int32"}
+	case "int64", "uint64":
+		// encoded as strings to avoid losing precision
+		return &Schema{Type: "string", Format: "int64"}
+	case "float32":
+		return &Schema{Type: "number", Format: "float"}
+	case "float64":
+		return &Schema{Type: "number", Format: "double"}
+	case "google.protobuf.Timestamp":
+		return &Schema{Type: "string", Format: "date-time"}
+	case "google.protobuf.Duration":
+		return &Schema{Type: "string"}
+	case "google.protobuf.Struct":
+		return &Schema{Type: "object"}
+	}
+	// the fields of the type aren't described, e.g. enums and nested messages, so any value is
+	// accepted
+	return &Schema{}
+}
@@ -0,0 +1,128 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/micro/micro/v3/service/registry"
+	memregistry "github.com/micro/micro/v3/service/registry/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func testService() *registry.Service {
+	return &registry.Service{
+		Name: "users",
+		Endpoints: []*registry.Endpoint{
+			{
+				Name: "Users.Create",
+				Request: &registry.Value{Name: "CreateRequest", Type: "CreateRequest", Values: []*registry.Value{
+					{Name: "name", Type: "string"},
+					{Name: "tags", Type: "[]string"},
+					{Name: "created", Type: "google.protobuf.Timestamp"},
+				}},
+				Response: &registry.Value{Name: "CreateResponse", Type: "CreateResponse", Values: []*registry.Value{
+					{Name: "id", Type: "int64"},
+				}},
+				Metadata: map[string]string{"description": "Create a user"},
+			},
+			{
+				Name: "Users.Read",
+				Request: &registry.Value{Name: "ReadRequest", Type: "ReadRequest", Values: []*registry.Value{
+					{Name: "id", Type: "string"},
+					{Name: "fields", Type: "[]string"},
+					{Name: "labels", Type: "map[string]string"},
+				}},
+				Metadata: map[string]string{"endpoint": "Users.Read", "method": "GET", "path": "/users/{id}"},
+			},
+			{
+				Name:     "Users.Search",
+				Metadata: map[string]string{"endpoint": "Users.Search", "path": "^/users/search/.*$"},
+			},
+			{
+				Name:     "Users.Watch",
+				Metadata: map[string]string{"stream": "true"},
+			},
+			{Name: "Handler", Metadata: map[string]string{"subscriber": "true"}},
+		},
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	doc := Generate("micro foo", "https://api.example.com", []*registry.Service{testService()})
+	assert.Equal(t, Version, doc.OpenAPI)
+	assert.Equal(t, "https://api.example.com", doc.Servers[0].URL)
+	assert.Equal(t, "users", doc.Tags[0].Name)
+
+	// endpoints are served at the default path unless an api path is set
+	create := doc.Paths["/users/Users/Create"].Post
+	if assert.NotNil(t, create) {
+		assert.Equal(t, "users.Users.Create", create.OperationID)
+		assert.Equal(t, "Create a user", create.Summary)
+		req := create.RequestBody.Content["application/json"].Schema
+		assert.Equal(t, "string", req.Properties["name"].Type)
+		assert.Equal(t, "string", req.Properties["tags"].Items.Type)
+		assert.Equal(t, "date-time", req.Properties["created"].Format)
+		rsp := create.Responses["200"].Content["application/json"].Schema
+		assert.Equal(t, &Schema{Type: "string", Format: "int64"}, rsp.Properties["id"])
+	}
+
+	// path variables and the remaining fields of reads are passed as parameters
+	read := doc.Paths["/users/{id}"].Get
+	if assert.NotNil(t, read) {
+		assert.Nil(t, read.RequestBody)
+		if assert.Len(t, read.Parameters, 2) {
+			assert.Equal(t, &Parameter{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}}, read.Parameters[0])
+			assert.Equal(t, "fields", read.Parameters[1].Name)
+			assert.Equal(t, "query", read.Parameters[1].In)
+		}
+	}
+
+	// regex paths are served at the default path
+	assert.NotNil(t, doc.Paths["/users/Users/Search"].Post)
+
+	// streams can be read as newline delimited JSON
+	assert.NotNil(t, doc.Paths["/users/Users/Watch"].Post.Responses["200"].Content["application/x-ndjson"])
+
+	// subscribers can't be called via the api
+	assert.Len(t, doc.Paths, 4)
+}
+
+func TestSchemaType(t *testing.T) {
+	assert.Equal(t, &Schema{Type: "string", Format: "byte"}, schemaType("[]uint8"))
+	assert.Equal(t, &Schema{Type: "object", AdditionalProperties: &Schema{Type: "integer", Format: "int32"}}, schemaType("map[string]int32"))
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{}}, schemaType("[]User"))
+	assert.Equal(t, &Schema{}, schemaType("Status"))
+}
+
+func TestHandler(t *testing.T) {
+	registry.DefaultRegistry = memregistry.NewRegistry()
+	srv := testService()
+	srv.Name = "foo.users"
+	srv.Nodes = []*registry.Node{{Id: "users-1", Address: "localhost:9090"}}
+	assert.NoError(t, registry.DefaultRegistry.Register(srv, registry.RegisterDomain("bar")))
+
+	h := NewHandler("foo")
+
+	r := httptest.NewRequest("GET", Path, nil)
+	r.Header.Set("Micro-Namespace", "bar")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+
+	var doc Document
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "micro bar", doc.Info.Title)
+	assert.Equal(t, "http://example.com", doc.Servers[0].URL)
+	// the service prefix is removed as it is by the resolver
+	assert.NotNil(t, doc.Paths["/users/Users/Create"])
+
+	// the services of other namespaces aren't included
+	r = httptest.NewRequest("GET", Path, nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	var def Document
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &def))
+	assert.Equal(t, "micro micro", def.Info.Title)
+	assert.Empty(t, def.Paths)
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/util/analytics"
+	"github.com/micro/micro/v3/util/namespace"
+)
+
+// analyticsWrapper records a summary of each request with the analytics tap. It runs before the
+// auth wrapper so rejected requests are recorded too, the auth wrapper sets the account and
+// namespace on the request so they're read once it's been served.
+func analyticsWrapper(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cw := &captureWriter{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		h.ServeHTTP(cw, r)
+
+		ev := &analytics.Event{
+			Source:    "api",
+			Route:     r.URL.Path,
+			Method:    r.Method,
+			Status:    cw.status,
+			Latency:   float64(time.Since(start).Microseconds()) / 1000,
+			Namespace: r.Header.Get(namespace.NamespaceKey),
+			UserAgent: r.UserAgent(),
+		}
+		if acc, ok := auth.AccountFromContext(r.Context()); ok {
+			ev.Account = acc.ID
+		}
+		analytics.DefaultTap.Record(ev)
+	})
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/util/signedurl"
+)
+
+// blobWrapper serves the signed urls to blobs. The signature authorizes the request so it
+// doesn't need an account, every other request is passed to the handler.
+func blobWrapper(key []byte) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.URL.Path, signedurl.Path) {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			u, err := signedurl.Verify(key, r)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+			if !u.Allows(r.Method) {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+
+			switch r.Method {
+			case http.MethodPut:
+				putBlob(w, r, u)
+			default:
+				getBlob(w, r, u)
+			}
+		})
+	}
+}
+
+// getBlob serves the blob, http.ServeContent handles range and conditional requests
+func getBlob(w http.ResponseWriter, r *http.Request, u *signedurl.URL) {
+	blob, err := store.DefaultBlobStore.Read(u.Key, store.BlobNamespace(u.Namespace))
+	if err == store.ErrNotFound {
+		http.Error(w, "blob not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Errorf("Error reading blob %v/%v: %v", u.Namespace, u.Key, err)
+		http.Error(w, "error reading blob", http.StatusInternalServerError)
+		return
+	}
+
+	// blobs are read into memory by the stores so this doesn't copy large blobs
+	var content io.ReadSeeker
+	switch b := blob.(type) {
+	case *bytes.Buffer:
+		content = bytes.NewReader(b.Bytes())
+	case io.ReadSeeker:
+		content = b
+	default:
+		buf, err := ioutil.ReadAll(blob)
+		if err != nil {
+			log.Errorf("Error reading blob %v/%v: %v", u.Namespace, u.Key, err)
+			http.Error(w, "error reading blob", http.StatusInternalServerError)
+			return
+		}
+		content = bytes.NewReader(buf)
+	}
+
+	var modified time.Time
+	if s, ok := store.DefaultBlobStore.(store.BlobStater); ok {
+		if info, err := s.Stat(u.Key, store.BlobNamespace(u.Namespace)); err == nil {
+			modified = info.Modified
+		}
+	}
+
+	// the url can be shared until it expires, but no longer
+	w.Header().Set("Cache-Control", "private, max-age="+maxAge(u.Expires))
+	http.ServeContent(w, r, path.Base(u.Key), modified, content)
+}
+
+// putBlob writes the body of the request to the blob
+func putBlob(w http.ResponseWriter, r *http.Request, u *signedurl.URL) {
+	err := store.DefaultBlobStore.Write(u.Key, r.Body,
+		store.BlobNamespace(u.Namespace),
+		store.BlobContentType(r.Header.Get("Content-Type")),
+	)
+	if err != nil {
+		log.Errorf("Error writing blob %v/%v: %v", u.Namespace, u.Key, err)
+		http.Error(w, "error writing blob", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func maxAge(expires time.Time) string {
+	secs := int64(time.Until(expires).Seconds())
+	if secs < 0 {
+		secs = 0
+	}
+	return strconv.FormatInt(secs, 10)
+}
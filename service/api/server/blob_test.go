@@ -0,0 +1,74 @@
+package api
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/file"
+	"github.com/micro/micro/v3/util/signedurl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlobWrapper(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blob")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	bs, err := file.NewBlobStore(file.WithDir(dir))
+	assert.NoError(t, err)
+	defer func(s store.BlobStore) { store.DefaultBlobStore = s }(store.DefaultBlobStore)
+	store.DefaultBlobStore = bs
+
+	key := []byte("secret")
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	h := blobWrapper(key)(next)
+	sign := func(method string) string {
+		return signedurl.Sign(key, "", &signedurl.URL{
+			Namespace: "micro",
+			Key:       "files/hello.txt",
+			Method:    method,
+			Expires:   time.Now().Add(time.Minute),
+		})
+	}
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	// other requests are passed through
+	assert.Equal(t, http.StatusTeapot, serve(httptest.NewRequest("GET", "/users/list", nil)).Code)
+
+	// the blob is uploaded
+	w := serve(httptest.NewRequest("PUT", sign("PUT"), bytes.NewBufferString("hello world")))
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// and downloaded
+	w = serve(httptest.NewRequest("GET", sign("GET"), nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "hello world", w.Body.String())
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+
+	// in parts
+	r := httptest.NewRequest("GET", sign("GET"), nil)
+	r.Header.Set("Range", "bytes=6-")
+	w = serve(r)
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "world", w.Body.String())
+
+	// the url can't be used with another method
+	w = serve(httptest.NewRequest("PUT", sign("GET"), bytes.NewBufferString("bye")))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	// or without the signature
+	w = serve(httptest.NewRequest("GET", "/_blob/micro/files/hello.txt", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
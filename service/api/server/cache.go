@@ -0,0 +1,228 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/micro/micro/v3/service/auth"
+	log "github.com/micro/micro/v3/service/logger"
+	inauth "github.com/micro/micro/v3/util/auth"
+	authns "github.com/micro/micro/v3/util/auth/namespace"
+	"github.com/micro/micro/v3/util/cache"
+	"github.com/micro/micro/v3/util/namespace"
+)
+
+var (
+	// CachePath is where admins purge the responses cached, e.g. DELETE /_cache?path=/users/list
+	// purges the responses of the path in their namespace, without a path every response of the
+	// namespace is purged
+	CachePath = "/_cache"
+)
+
+// cacheWrapper caches the responses to public GET requests which services mark cacheable with the
+// Cache-Control header, so read heavy endpoints don't reach the services on every request.
+// Responses are given an ETag if they don't have one, so clients can revalidate them with
+// If-None-Match. It runs after the auth and rate limit wrappers so cached responses are only
+// returned to requests which are allowed.
+func cacheWrapper(c *cache.HTTPCache) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ns := r.Header.Get(namespace.NamespaceKey)
+			if r.URL.Path == CachePath {
+				purgeCache(c, ns, w, r)
+				return
+			}
+
+			ctrl := strings.ToLower(r.Header.Get(cache.ControlKey))
+			if (r.Method != "GET" && r.Method != "HEAD") || hasCredentials(r) || strings.Contains(ctrl, "no-store") {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			// clients can ask for the response to be fetched from the service again
+			if !strings.Contains(ctrl, "no-cache") {
+				e, err := c.Get(r.Context(), ns, r)
+				if err == nil {
+					writeCached(w, r, e)
+					return
+				} else if err != cache.ErrNotFound {
+					log.Errorf("Error reading cached response of %v: %v", r.URL.Path, err)
+				}
+			}
+
+			// responses to HEAD requests have no body so they aren't cached
+			if r.Method == "HEAD" {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &cacheWriter{responseRecorder: newResponseRecorder(w), r: r}
+			h.ServeHTTP(cw, r)
+			e, expiry := cw.finish()
+			if e == nil {
+				return
+			}
+			if err := c.Set(r.Context(), ns, r, e, expiry); err != nil {
+				log.Errorf("Error caching response of %v: %v", r.URL.Path, err)
+			}
+		})
+	}
+}
+
+// purgeCache purges the responses cached for the path, or the whole namespace, if the account
+// is an admin of the namespace
+func purgeCache(c *cache.HTTPCache, ns string, w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if _, ok := auth.AccountFromContext(r.Context()); !ok {
+		http.Error(w, "unauthorized request", http.StatusUnauthorized)
+		return
+	}
+	if err := authns.AuthorizeAdmin(r.Context(), ns, "api.Cache.Purge"); err != nil {
+		http.Error(w, "forbidden request", http.StatusForbidden)
+		return
+	}
+
+	path := r.URL.Query().Get("path")
+	if err := c.Purge(r.Context(), ns, path); err != nil {
+		log.Errorf("Error purging cache of %v: %v", path, err)
+		http.Error(w, "error purging cache", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeCached writes the cached response, or that it's not modified if the client has it
+func writeCached(w http.ResponseWriter, r *http.Request, e *cache.HTTPEntry) {
+	h := w.Header()
+	for k, vs := range e.Header {
+		for _, v := range vs {
+			h.Add(k, v)
+		}
+	}
+	h.Set("Age", strconv.Itoa(int(time.Since(e.Stored).Seconds())))
+	h.Set("X-Cache", "HIT")
+
+	if cache.MatchETag(r, h.Get("ETag")) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	h.Set("Content-Length", strconv.Itoa(len(e.Body)))
+	w.WriteHeader(e.Status)
+	if r.Method != "HEAD" {
+		w.Write(e.Body)
+	}
+}
+
+// hasCredentials returns true if the request was made with credentials, the responses to them
+// may be personal so they're never cached
+func hasCredentials(r *http.Request) bool {
+	if len(r.Header.Get("Authorization")) > 0 || len(r.Header.Get(inauth.APIKeyHeader)) > 0 {
+		return true
+	}
+	for _, c := range r.Cookies() {
+		if c.Name == inauth.TokenCookieName || c.Name == inauth.SessionCookieName {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheWriter buffers the responses which can be cached, so they can be given an ETag and stored
+// once the handler returns. Other responses, or those too large to cache, are written through.
+type cacheWriter struct {
+	responseRecorder
+	r      *http.Request
+	expiry time.Duration
+}
+
+func (c *cacheWriter) WriteHeader(status int) {
+	if c.wrote {
+		return
+	}
+	c.wrote = true
+
+	if expiry, ok := cache.HTTPExpiry(status, c.Header()); ok {
+		c.status = status
+		c.expiry = expiry
+		c.buf = &bytes.Buffer{}
+		return
+	}
+	c.Header().Set("X-Cache", "MISS")
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *cacheWriter) Write(b []byte) (int, error) {
+	if !c.wrote {
+		c.WriteHeader(http.StatusOK)
+	}
+	if c.buf == nil {
+		return c.ResponseWriter.Write(b)
+	}
+	if c.buf.Len()+len(b) > cache.MaxHTTPBodySize {
+		// too large to cache, write what's buffered and the rest through
+		c.Header().Set("X-Cache", "MISS")
+		c.ResponseWriter.WriteHeader(c.status)
+		c.ResponseWriter.Write(c.buf.Bytes())
+		c.buf = nil
+		return c.ResponseWriter.Write(b)
+	}
+	return c.buf.Write(b)
+}
+
+// finish writes the buffered response, returning the entry to cache and its expiry. A nil entry
+// is returned if the response can't be cached.
+func (c *cacheWriter) finish() (*cache.HTTPEntry, time.Duration) {
+	if c.buf == nil {
+		return nil, 0
+	}
+	body := c.buf.Bytes()
+	h := c.Header()
+	if len(h.Get("ETag")) == 0 {
+		h.Set("ETag", cache.ETag(body))
+	}
+
+	header := c.handlerHeader()
+	e := &cache.HTTPEntry{
+		Status: c.status,
+		Header: header,
+		Body:   body,
+		Vary:   cache.VaryHeaders(c.r, header),
+		Stored: time.Now(),
+	}
+
+	h.Set("X-Cache", "MISS")
+	if cache.MatchETag(c.r, h.Get("ETag")) {
+		c.ResponseWriter.WriteHeader(http.StatusNotModified)
+		return e, c.expiry
+	}
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+	c.ResponseWriter.WriteHeader(c.status)
+	c.ResponseWriter.Write(body)
+	return e, c.expiry
+}
+
+func (c *cacheWriter) Flush() {
+	// buffered responses are written once the handler returns
+	if c.buf != nil {
+		return
+	}
+	if f, ok := c.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (c *cacheWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := c.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, errors.New("hijacking isn't supported")
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/util/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheWrapper(t *testing.T) {
+	var calls int
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.URL.Path {
+		case "/public":
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"count":1}`))
+		default:
+			w.Header().Set("Cache-Control", "private, max-age=60")
+			w.Write([]byte(`{"name":"john"}`))
+		}
+	})
+
+	c := &cache.HTTPCache{Backend: cache.NewMemoryBackend()}
+	h := cacheWrapper(c)(next)
+
+	serve := func(method, path string, header http.Header) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, nil)
+		r.Header.Set("Micro-Namespace", "foo")
+		for k, v := range header {
+			r.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		// headers set by the wrappers which ran first aren't cached
+		w.Header().Set("Vary", "Origin")
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	// the first request reaches the handler, the second is served from the cache
+	w := serve("GET", "/public", nil)
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.Equal(t, `{"count":1}`, w.Body.String())
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	w = serve("GET", "/public", nil)
+	assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+	assert.Equal(t, `{"count":1}`, w.Body.String())
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+	assert.Equal(t, []string{"Origin"}, w.Header().Values("Vary"))
+	assert.Equal(t, 1, calls)
+
+	// clients revalidate with the etag
+	w = serve("GET", "/public", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	// HEAD requests are served from the cached GET response
+	w = serve("HEAD", "/public", nil)
+	assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+	assert.Empty(t, w.Body.String())
+
+	// requests with credentials and private responses aren't cached
+	serve("GET", "/public", http.Header{"Authorization": {"Bearer abc"}})
+	assert.Equal(t, 2, calls)
+	serve("GET", "/private", nil)
+	serve("GET", "/private", nil)
+	assert.Equal(t, 4, calls)
+
+	// clients can bypass the cache
+	serve("GET", "/public", http.Header{"Cache-Control": {"no-cache"}})
+	assert.Equal(t, 5, calls)
+
+	// purging requires an admin
+	w = serve("DELETE", CachePath+"?path=/public", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w = serve("POST", CachePath, nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	r := httptest.NewRequest("DELETE", CachePath+"?path=/public", nil)
+	r.Header.Set("Micro-Namespace", "foo")
+	r = r.WithContext(auth.ContextWithAccount(r.Context(), &auth.Account{ID: "1", Type: "user", Issuer: "foo", Scopes: []string{"admin"}}))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = serve("GET", "/public", nil)
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.Equal(t, 6, calls)
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/micro/micro/v3/util/capture"
+)
+
+// captureWrapper records the exchanges for the routes being captured. It runs before the other
+// wrappers so requests rejected by them, e.g. because they weren't authorized, are captured too.
+func captureWrapper(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := capture.Match(r.Host, r.URL.Path)
+		if c == nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		// read the start of the body, leaving it intact for the handler
+		var reqBody []byte
+		if r.Body != nil {
+			reqBody, _ = io.ReadAll(io.LimitReader(r.Body, int64(capture.MaxBodySize)))
+			r.Body = readCloser{io.MultiReader(bytes.NewReader(reqBody), r.Body), r.Body}
+		}
+		reqSize := len(reqBody)
+		if r.ContentLength > 0 {
+			reqSize = int(r.ContentLength)
+		}
+
+		rec := &captureWriter{ResponseWriter: w, status: http.StatusOK, limit: capture.MaxBodySize}
+		start := time.Now()
+		h.ServeHTTP(rec, r)
+
+		capture.Record(c, capture.NewEntry(r, reqBody, reqSize, rec.status, w.Header(), rec.body.Bytes(), rec.size, start, time.Since(start)))
+	})
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// captureWriter records the status and size of the response and up to limit bytes of its body
+type captureWriter struct {
+	http.ResponseWriter
+	status int
+	size   int
+	limit  int
+	body   bytes.Buffer
+}
+
+func (c *captureWriter) WriteHeader(status int) {
+	c.status = status
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *captureWriter) Write(b []byte) (int, error) {
+	if rem := c.limit - c.body.Len(); rem > 0 {
+		if len(b) < rem {
+			rem = len(b)
+		}
+		c.body.Write(b[:rem])
+	}
+	c.size += len(b)
+	return c.ResponseWriter.Write(b)
+}
+
+func (c *captureWriter) Flush() {
+	if f, ok := c.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (c *captureWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := c.ResponseWriter.(http.Hijacker); ok {
+		// the exchange is recorded as a switch of protocols
+		c.status = http.StatusSwitchingProtocols
+		return h.Hijack()
+	}
+	return nil, nil, errors.New("hijacking isn't supported")
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/util/challenge"
+	"github.com/micro/micro/v3/util/clientip"
+)
+
+const (
+	// challengeHeader is the header the response to a challenge is sent in
+	challengeHeader = "Micro-Challenge-Response"
+	// challengePassHeader is the header the pass is sent in by clients which don't support cookies
+	challengePassHeader = "Micro-Challenge-Pass"
+	// challengeCookie is the cookie the pass is stored in once a client has passed a challenge
+	challengeCookie = "micro-challenge-pass"
+)
+
+// challengeWrapper challenges the clients flagged as abusive, e.g. by overflowing the rate limit,
+// when they call one of the routes provided. Clients which pass the challenge are given a pass
+// which is valid for the duration provided.
+func challengeWrapper(v challenge.Verifier, secret string, routes []string, passFor time.Duration) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := clientip.FromRequest(r, clientip.DefaultTrusted)
+			if !challengeRoute(routes, r.URL.Path) || !challenge.Flagged(ip) {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			// the client already passed a challenge
+			if c, err := r.Cookie(challengeCookie); err == nil && challenge.VerifyPass(secret, c.Value, ip) == nil {
+				h.ServeHTTP(w, r)
+				return
+			}
+			if pass := r.Header.Get(challengePassHeader); len(pass) > 0 && challenge.VerifyPass(secret, pass, ip) == nil {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			// the client is responding to a challenge
+			if rsp := r.Header.Get(challengeHeader); len(rsp) > 0 {
+				if err := v.Verify(r.Context(), rsp, ip); err != nil {
+					if err != challenge.ErrFailed {
+						log.Errorf("Error verifying %v challenge: %v", v, err)
+					}
+					writeChallenge(w, v, ip, http.StatusForbidden)
+					return
+				}
+
+				pass := challenge.Pass(secret, ip, time.Now().Add(passFor))
+				http.SetCookie(w, &http.Cookie{
+					Name:     challengeCookie,
+					Value:    pass,
+					Path:     "/",
+					Expires:  time.Now().Add(passFor),
+					HttpOnly: true,
+					Secure:   r.TLS != nil,
+					SameSite: http.SameSiteLaxMode,
+				})
+				// clients which don't support cookies can send the pass as a header
+				w.Header().Set(challengePassHeader, pass)
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			writeChallenge(w, v, ip, http.StatusUnauthorized)
+		})
+	}
+}
+
+// challengeRoute returns true if the path matches one of the route prefixes
+func challengeRoute(routes []string, path string) bool {
+	for _, r := range routes {
+		if r == "*" || strings.HasPrefix(path, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeChallenge(w http.ResponseWriter, v challenge.Verifier, ip string, status int) {
+	c, err := v.Issue(ip)
+	if err != nil {
+		log.Errorf("Error issuing %v challenge: %v", v, err)
+		http.Error(w, "error issuing challenge", http.StatusInternalServerError)
+		return
+	}
+
+	b, _ := json.Marshal(map[string]interface{}{
+		"id":        "go.micro.api",
+		"code":      status,
+		"detail":    "challenge required",
+		"status":    http.StatusText(status),
+		"challenge": c,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Micro-Challenge", c.Type)
+	w.WriteHeader(status)
+	w.Write(b)
+}
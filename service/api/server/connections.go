@@ -0,0 +1,332 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	pb "github.com/micro/micro/v3/proto/connections"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/client"
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/util/cors"
+	"github.com/micro/micro/v3/util/namespace"
+)
+
+var (
+	// ConnectPath is where clients connect to receive the messages pushed to their account
+	ConnectPath = "/_connect"
+
+	// connectionTTL is how long the connections service tracks a connection unless it's
+	// registered again, the gateway registers its connections at a third of it
+	connectionTTL = time.Minute
+	// connectionBuffer is the number of messages buffered for a connection before they're dropped
+	connectionBuffer = 64
+
+	connectUpgrader = websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		// the origin is checked by the gateway before upgrading, by the cors policy
+		CheckOrigin: func(r *http.Request) bool {
+			return true
+		},
+	}
+)
+
+// gateway holds the websocket and server sent event clients connected to this instance of the
+// api, registering them with the connections service so messages pushed to an account are
+// delivered by the instance holding its connections
+type gateway struct {
+	// name and id of the api service, used to find the address it's registered at
+	name string
+	id   string
+
+	client pb.ConnectionsService
+	// cors policies of the origins allowed to connect, only the origin of the api if nil
+	cors *cors.Policies
+
+	sync.RWMutex
+	address string
+	conns   map[string]*connection
+}
+
+type connection struct {
+	*pb.Connection
+	send chan []byte
+}
+
+func newGateway(name, id string, c client.Client, p *cors.Policies) *gateway {
+	return &gateway{
+		name:   name,
+		id:     id,
+		client: pb.NewConnectionsService("connections", c),
+		cors:   p,
+		conns:  map[string]*connection{},
+	}
+}
+
+// Deliver the payload to the connections held by the gateway, called by the connections service
+func (g *gateway) Deliver(ctx context.Context, req *pb.DeliverRequest, rsp *pb.DeliverResponse) error {
+	g.RLock()
+	defer g.RUnlock()
+
+	for _, id := range req.Ids {
+		c, ok := g.conns[id]
+		if !ok {
+			rsp.Missing = append(rsp.Missing, id)
+			continue
+		}
+		select {
+		case c.send <- req.Payload:
+		default:
+			log.Warnf("Dropping message to connection %v of %v, the client isn't reading", c.Id, c.Account)
+		}
+	}
+	return nil
+}
+
+// wrapper serves the connect path, every other request is passed to the handler. It runs after
+// the auth wrapper as clients connect as an account.
+func (g *gateway) wrapper(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != ConnectPath {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		acc, ok := auth.AccountFromContext(r.Context())
+		if !ok {
+			http.Error(w, "unauthorized request", http.StatusUnauthorized)
+			return
+		}
+		if !g.checkOrigin(r) {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+
+		addr, err := g.lookupAddress()
+		if err != nil {
+			log.Errorf("Error looking up the address of the gateway: %v", err)
+			http.Error(w, "gateway not ready", http.StatusServiceUnavailable)
+			return
+		}
+
+		c := &connection{
+			Connection: &pb.Connection{
+				Id:        uuid.New().String(),
+				Namespace: r.Header.Get(namespace.NamespaceKey),
+				Account:   acc.ID,
+				Session:   r.URL.Query().Get("session"),
+				Gateway:   g.id,
+				Address:   addr,
+				Created:   time.Now().Unix(),
+			},
+			send: make(chan []byte, connectionBuffer),
+		}
+
+		switch {
+		case websocket.IsWebSocketUpgrade(r):
+			c.Protocol = "websocket"
+			g.serveWebsocket(w, r, c)
+		case strings.Contains(r.Header.Get("Accept"), "text/event-stream"):
+			c.Protocol = "sse"
+			g.serveEvents(w, r, c)
+		default:
+			http.Error(w, "expected a websocket or text/event-stream request", http.StatusBadRequest)
+		}
+	})
+}
+
+// checkOrigin returns true if the request is from the origin of the api, or from an origin the
+// cors policy allows credentials from. Browsers send the cookies of the api with websocket
+// requests from any page and don't apply cors to them, so any site could otherwise connect as
+// the user visiting it. Requests without an origin aren't from browsers.
+func (g *gateway) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if len(origin) == 0 {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	if g.cors == nil {
+		return false
+	}
+	p := g.cors.Policy(r.Header.Get(namespace.NamespaceKey), r.Host, r.URL.Path)
+	return p.AllowCredentials && p.AllowOrigin(origin)
+}
+
+func (g *gateway) serveWebsocket(w http.ResponseWriter, r *http.Request, c *connection) {
+	ws, err := connectUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Debugf("Error upgrading connection: %v", err)
+		return
+	}
+	defer ws.Close()
+
+	if err := g.add(c); err != nil {
+		log.Errorf("Error registering connection of %v: %v", c.Account, err)
+		ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "error registering connection"))
+		return
+	}
+	defer g.remove(c)
+
+	// clients don't send messages, the reads detect when the client has gone away
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		case msg := <-c.send:
+			typ := websocket.BinaryMessage
+			if utf8.Valid(msg) {
+				typ = websocket.TextMessage
+			}
+			if err := ws.WriteMessage(typ, msg); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (g *gateway) serveEvents(w http.ResponseWriter, r *http.Request, c *connection) {
+	f, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	if err := g.add(c); err != nil {
+		log.Errorf("Error registering connection of %v: %v", c.Account, err)
+		http.Error(w, "error registering connection", http.StatusInternalServerError)
+		return
+	}
+	defer g.remove(c)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	f.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg := <-c.send:
+			for _, line := range strings.Split(string(msg), "\n") {
+				fmt.Fprintf(w, "data: %s\n", line)
+			}
+			fmt.Fprint(w, "\n")
+			f.Flush()
+		}
+	}
+}
+
+func (g *gateway) add(c *connection) error {
+	_, err := g.client.Register(context.Background(), &pb.RegisterRequest{
+		Connections: []*pb.Connection{c.Connection},
+		Ttl:         int64(connectionTTL.Seconds()),
+	}, client.WithAuthToken())
+	if err != nil {
+		return err
+	}
+
+	g.Lock()
+	g.conns[c.Id] = c
+	g.Unlock()
+	return nil
+}
+
+func (g *gateway) remove(c *connection) {
+	g.Lock()
+	delete(g.conns, c.Id)
+	g.Unlock()
+
+	_, err := g.client.Deregister(context.Background(), &pb.DeregisterRequest{
+		Id:        c.Id,
+		Namespace: c.Namespace,
+		Account:   c.Account,
+	}, client.WithAuthToken())
+	if err != nil {
+		log.Warnf("Error deregistering connection %v of %v, it'll expire: %v", c.Id, c.Account, err)
+	}
+}
+
+// refresh registers the connections held by the gateway until the context is done, so they
+// expire if the gateway stops
+func (g *gateway) refresh(ctx context.Context) {
+	t := time.NewTicker(connectionTTL / 3)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		g.RLock()
+		conns := make([]*pb.Connection, 0, len(g.conns))
+		for _, c := range g.conns {
+			conns = append(conns, c.Connection)
+		}
+		g.RUnlock()
+		if len(conns) == 0 {
+			continue
+		}
+
+		_, err := g.client.Register(context.Background(), &pb.RegisterRequest{
+			Connections: conns,
+			Ttl:         int64(connectionTTL.Seconds()),
+		}, client.WithAuthToken())
+		if err != nil {
+			log.Errorf("Error registering %d connections: %v", len(conns), err)
+		}
+	}
+}
+
+// lookupAddress returns the address the api is registered at, which the connections service
+// calls to deliver messages to this instance
+func (g *gateway) lookupAddress() (string, error) {
+	g.RLock()
+	addr := g.address
+	g.RUnlock()
+	if len(addr) > 0 {
+		return addr, nil
+	}
+
+	srvs, err := registry.DefaultRegistry.GetService(g.name)
+	if err != nil {
+		return "", err
+	}
+	for _, srv := range srvs {
+		for _, node := range srv.Nodes {
+			if node.Id != g.name+"-"+g.id {
+				continue
+			}
+			g.Lock()
+			g.address = node.Address
+			g.Unlock()
+			return node.Address, nil
+		}
+	}
+	return "", fmt.Errorf("%v isn't registered", g.name+"-"+g.id)
+}
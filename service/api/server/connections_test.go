@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectOrigin(t *testing.T) {
+	g := newGateway("api", "1", nil, nil)
+	h := g.wrapper(http.NotFoundHandler())
+
+	connect := func(origin string) int {
+		r := httptest.NewRequest("GET", "http://api.example.com"+ConnectPath, nil)
+		r.Header.Set("Origin", origin)
+		r = r.WithContext(auth.ContextWithAccount(r.Context(), &auth.Account{ID: "john"}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// other sites can't connect as the user visiting them
+	assert.Equal(t, http.StatusForbidden, connect("https://evil.com"))
+
+	// the origin of the api and clients which aren't browsers can
+	assert.True(t, g.checkOrigin(httptest.NewRequest("GET", "http://api.example.com"+ConnectPath, nil)))
+	r := httptest.NewRequest("GET", "http://api.example.com"+ConnectPath, nil)
+	r.Header.Set("Origin", "https://api.example.com")
+	assert.True(t, g.checkOrigin(r))
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/micro/micro/v3/service/api/auth"
+	"github.com/micro/micro/v3/service/api/resolver"
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/util/cors"
+	"github.com/micro/micro/v3/util/namespace"
+)
+
+// corsWrapper sets the CORS headers by the policy of the namespace and route requested. It runs
+// first so preflight requests, which don't have credentials, are answered before they reach the
+// auth wrapper.
+func corsWrapper(p *cors.Policies, rr resolver.Resolver) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			policy := p.Policy(corsNamespace(r, rr), r.Host, r.URL.Path)
+			policy.SetHeaders(w, r)
+
+			if r.Method == "OPTIONS" {
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// corsNamespace returns the namespace of the request the way the auth wrapper determines it, the
+// namespace of a custom domain then the header then the domain of the endpoint requested
+func corsNamespace(r *http.Request, rr resolver.Resolver) string {
+	if d, err := auth.DefaultDomains.Get(r.Context(), r.Host); err != nil {
+		log.Errorf("Error looking up domain %v: %v", r.Host, err)
+	} else if d != nil {
+		return d.Namespace
+	}
+	if ns := r.Header.Get(namespace.NamespaceKey); len(ns) > 0 {
+		return ns
+	}
+	if ep, err := rr.Resolve(r); err == nil && len(ep.Domain) > 0 {
+		return ep.Domain
+	}
+	return Namespace
+}
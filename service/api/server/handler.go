@@ -18,9 +18,10 @@ import (
 )
 
 type metaHandler struct {
-	c  client.Client
-	r  router.Router
-	ns string
+	c    client.Client
+	r    router.Router
+	ns   string
+	opts []handler.Option
 }
 
 func (m *metaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -37,13 +38,13 @@ func (m *metaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	switch service.Endpoint.Handler {
 	// web socket handler
 	case aweb.Handler:
-		aweb.WithService(service, handler.WithClient(m.c)).ServeHTTP(w, r)
+		aweb.WithService(service, m.options()...).ServeHTTP(w, r)
 	// proxy handler
 	case ahttp.Handler:
-		ahttp.WithService(service, handler.WithClient(m.c)).ServeHTTP(w, r)
+		ahttp.WithService(service, m.options()...).ServeHTTP(w, r)
 	// rpcx handler
 	case arpc.Handler:
-		arpc.WithService(service, handler.WithClient(m.c)).ServeHTTP(w, r)
+		arpc.WithService(service, m.options()...).ServeHTTP(w, r)
 	// event handler
 	case event.Handler:
 		ev := event.NewHandler(
@@ -53,18 +54,24 @@ func (m *metaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		ev.ServeHTTP(w, r)
 	// api handler
 	case aapi.Handler:
-		aapi.WithService(service, handler.WithClient(m.c)).ServeHTTP(w, r)
+		aapi.WithService(service, m.options()...).ServeHTTP(w, r)
 	// default handler: rpc
 	default:
-		arpc.WithService(service, handler.WithClient(m.c)).ServeHTTP(w, r)
+		arpc.WithService(service, m.options()...).ServeHTTP(w, r)
 	}
 }
 
+// options of the handlers, using the client of the service
+func (m *metaHandler) options() []handler.Option {
+	return append([]handler.Option{handler.WithClient(m.c)}, m.opts...)
+}
+
 // Meta is a http.Handler that routes based on endpoint metadata
-func Meta(s *service.Service, r router.Router, ns string) http.Handler {
+func Meta(s *service.Service, r router.Router, ns string, opts ...handler.Option) http.Handler {
 	return &metaHandler{
-		c:  s.Client(),
-		r:  r,
-		ns: ns,
+		c:    s.Client(),
+		r:    r,
+		ns:   ns,
+		opts: opts,
 	}
 }
@@ -18,6 +18,9 @@ package http
 
 import (
 	"net/http"
+	"strings"
+
+	"github.com/micro/micro/v3/service/api/handler/grpcweb"
 )
 
 // CombinedCORSHandler wraps a server and provides CORS headers
@@ -56,5 +59,7 @@ func SetHeaders(w http.ResponseWriter, r *http.Request) {
 
 	set(w, "Access-Control-Allow-Credentials", "true")
 	set(w, "Access-Control-Allow-Methods", "POST, PATCH, GET, OPTIONS, PUT, DELETE")
-	set(w, "Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Micro-Namespace")
+	set(w, "Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Micro-Namespace, "+strings.Join(grpcweb.AllowedHeaders, ", "))
+	// gRPC-Web clients read the status of the call from the response headers
+	set(w, "Access-Control-Expose-Headers", strings.Join(grpcweb.ExposedHeaders, ", "))
 }
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/micro/micro/v3/service/auth"
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/util/clientip"
+	"github.com/micro/micro/v3/util/idempotency"
+	"github.com/micro/micro/v3/util/namespace"
+)
+
+// idempotentMethods are the methods of the requests made idempotent by the Idempotency-Key header
+var idempotentMethods = map[string]bool{
+	"POST":   true,
+	"PUT":    true,
+	"PATCH":  true,
+	"DELETE": true,
+}
+
+// idempotencyWrapper records the responses to requests made with the Idempotency-Key header, so
+// clients can retry them after a network error without repeating them, e.g. taking a payment
+// twice. Retries are replayed the response recorded, concurrent retries are rejected until it's
+// recorded and the key can't be reused for a different request. Keys are scoped by account, or by
+// ip for requests which aren't authenticated. Server errors aren't recorded so they can be retried.
+func idempotencyWrapper(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotency.Header)
+		if len(key) == 0 || !idempotentMethods[r.Method] {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if err := idempotency.Validate(key); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, int64(idempotency.MaxBodySize)+1))
+		if err != nil {
+			http.Error(w, "error reading request", http.StatusBadRequest)
+			return
+		}
+		if len(body) > idempotency.MaxBodySize {
+			http.Error(w, "request too large to be made with an idempotency key", http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		scope := "ip/" + clientip.FromRequest(r, clientip.DefaultTrusted)
+		if acc, ok := auth.AccountFromContext(r.Context()); ok {
+			scope = "account/" + acc.ID
+		}
+		fingerprint := idempotency.Fingerprint([]byte(r.Method), []byte(r.URL.Path), []byte(r.URL.RawQuery), body)
+		opts := []idempotency.Option{idempotency.Namespace(r.Header.Get(namespace.NamespaceKey))}
+
+		rsp, claim, err := idempotency.Begin(scope, key, fingerprint, opts...)
+		switch err {
+		case nil:
+		case idempotency.ErrInProgress:
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		case idempotency.ErrMismatch:
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		default:
+			// don't take the api down with the store, the request is made without the key
+			log.Errorf("Error claiming idempotency key of %v: %v", r.URL.Path, err)
+			h.ServeHTTP(w, r)
+			return
+		}
+		if rsp != nil {
+			writeReplayed(w, rsp)
+			return
+		}
+
+		// the key is released if the handler panics, so the request can be retried
+		defer claim.Release()
+		iw := &idempotencyWriter{responseRecorder: newResponseRecorder(w)}
+		iw.buf = &bytes.Buffer{}
+		h.ServeHTTP(iw, r)
+
+		if iw.status >= 500 || iw.buf == nil {
+			if err := claim.Release(); err != nil {
+				log.Errorf("Error releasing idempotency key of %v: %v", r.URL.Path, err)
+			}
+			return
+		}
+		rec := &idempotency.Response{Status: iw.status, Header: iw.handlerHeader(), Body: iw.buf.Bytes()}
+		if err := claim.Complete(rec); err != nil {
+			log.Errorf("Error recording response of %v: %v", r.URL.Path, err)
+		}
+	})
+}
+
+// writeReplayed writes the response recorded for the idempotency key
+func writeReplayed(w http.ResponseWriter, rsp *idempotency.Response) {
+	h := w.Header()
+	for k, vs := range rsp.Header {
+		for _, v := range vs {
+			h.Add(k, v)
+		}
+	}
+	h.Set("Idempotent-Replayed", "true")
+	h.Set("Content-Length", strconv.Itoa(len(rsp.Body)))
+	w.WriteHeader(rsp.Status)
+	w.Write(rsp.Body)
+}
+
+// idempotencyWriter writes the response through, recording it so it can be replayed. The buffer
+// is dropped if the response is too large to record.
+type idempotencyWriter struct {
+	responseRecorder
+}
+
+func (i *idempotencyWriter) WriteHeader(status int) {
+	if i.wrote {
+		return
+	}
+	i.wrote = true
+	i.status = status
+	i.ResponseWriter.WriteHeader(status)
+}
+
+func (i *idempotencyWriter) Write(b []byte) (int, error) {
+	if !i.wrote {
+		i.WriteHeader(http.StatusOK)
+	}
+	if i.buf != nil {
+		if i.buf.Len()+len(b) > idempotency.MaxBodySize {
+			i.buf = nil
+		} else {
+			i.buf.Write(b)
+		}
+	}
+	return i.ResponseWriter.Write(b)
+}
+
+func (i *idempotencyWriter) Flush() {
+	if f, ok := i.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (i *idempotencyWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	// hijacked connections, e.g. websockets, can't be replayed
+	i.buf = nil
+	if h, ok := i.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, errors.New("hijacking isn't supported")
+}
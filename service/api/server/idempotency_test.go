@@ -0,0 +1,75 @@
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyWrapper(t *testing.T) {
+	store.DefaultStore = memory.NewStore()
+
+	var calls int
+	status := http.StatusCreated
+	h := idempotencyWrapper(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write(body)
+	}))
+
+	serve := func(method, key, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/payments/create", strings.NewReader(body))
+		r.Header.Set("Micro-Namespace", "foo")
+		if len(key) > 0 {
+			r.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		w.Header().Set("Vary", "Origin")
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	// retries are replayed the response, with the headers set by the handler
+	w := serve("POST", "abc", `{"amount":100}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"amount":100}`, w.Body.String())
+
+	w = serve("POST", "abc", `{"amount":100}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"amount":100}`, w.Body.String())
+	assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, []string{"Origin"}, w.Header().Values("Vary"))
+	assert.Equal(t, 1, calls)
+
+	// the key can't be reused for another request
+	w = serve("POST", "abc", `{"amount":200}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, 1, calls)
+
+	// requests without a key, or which are idempotent already, aren't recorded
+	serve("POST", "", `{"amount":100}`)
+	serve("GET", "abc", "")
+	assert.Equal(t, 3, calls)
+
+	// server errors aren't recorded so the request can be retried
+	status = http.StatusInternalServerError
+	serve("POST", "def", `{"amount":100}`)
+	status = http.StatusCreated
+	w = serve("POST", "def", `{"amount":100}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, 5, calls)
+
+	// keys are validated
+	w = serve("POST", strings.Repeat("a", 256), `{"amount":100}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
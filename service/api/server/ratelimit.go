@@ -0,0 +1,97 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/micro/micro/v3/service/auth"
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/quota"
+	"github.com/micro/micro/v3/util/challenge"
+	"github.com/micro/micro/v3/util/clientip"
+	"github.com/micro/micro/v3/util/namespace"
+	"github.com/micro/micro/v3/util/routelimit"
+)
+
+// rateLimitWrapper limits the requests made by each account, or each ip for requests which aren't
+// authenticated. The limit is enforced by the quota service so it applies across every replica
+// of the api rather than to each one. Clients which overflow the limit are flagged so they're
+// challenged by the challenge wrapper for the flag duration, if it's enabled.
+func rateLimitWrapper(limit int64, window time.Duration, burst int64, flagFor time.Duration) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := clientip.FromRequest(r, clientip.DefaultTrusted)
+			key := "ip/" + ip
+			if acc, ok := auth.AccountFromContext(r.Context()); ok {
+				key = "account/" + acc.ID
+			}
+
+			res, err := quota.Allow(key,
+				quota.Limit(limit, window),
+				quota.Burst(burst),
+				quota.AllowNamespace(r.Header.Get(namespace.NamespaceKey)),
+			)
+			if err != nil {
+				// don't take the api down with the quota service
+				log.Errorf("Error checking rate limit of %v: %v", key, err)
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			if !res.Allowed {
+				if flagFor > 0 {
+					challenge.Flag(ip, flagFor)
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(int(res.RetryAfter.Seconds())+1))
+				http.Error(w, "too many requests", http.StatusTooManyRequests)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Remaining", strconv.FormatInt(res.Remaining, 10))
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// routeLimitWrapper enforces the limits of the routes loaded from the config service, keyed by the
+// api key, account or ip which made the request. Like the rate limit wrapper the limits apply
+// across every replica of the api and clients which overflow the rate of a route are flagged.
+func routeLimitWrapper(l *routelimit.Limiter, flagFor time.Duration) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lim := l.Match(r.Host, r.Method, r.URL.Path)
+			if lim == nil {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			ip := clientip.FromRequest(r, clientip.DefaultTrusted)
+			var apiKey, account string
+			if acc, ok := auth.AccountFromContext(r.Context()); ok {
+				apiKey, account = acc.Metadata["apiKey"], acc.ID
+			}
+			client := lim.Client(apiKey, account, ip)
+
+			res, err := lim.Allow(client, r.Header.Get(namespace.NamespaceKey))
+			if err != nil {
+				// don't take the api down with the quota service
+				log.Errorf("Error checking route limit of %v for %v: %v", lim.Route, client, err)
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			if !res.Allowed {
+				if flagFor > 0 {
+					challenge.Flag(ip, flagFor)
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(int(res.RetryAfter.Seconds())+1))
+				http.Error(w, "too many requests", http.StatusTooManyRequests)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Remaining", strconv.FormatInt(res.Remaining, 10))
+			h.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+)
+
+// responseRecorder is embedded by the writers which record the response set by the handler so it
+// can be replayed, e.g. from the cache or for an idempotency key
+type responseRecorder struct {
+	http.ResponseWriter
+	// before are the headers set by the wrappers which ran first, they aren't recorded
+	before http.Header
+	status int
+	wrote  bool
+	buf    *bytes.Buffer
+}
+
+func newResponseRecorder(w http.ResponseWriter) responseRecorder {
+	return responseRecorder{ResponseWriter: w, before: w.Header().Clone(), status: http.StatusOK}
+}
+
+// handlerHeader returns the headers of the response set by the handler, excluding those set by
+// the wrappers which ran first, e.g. the CORS headers, since they depend on the request
+func (r *responseRecorder) handlerHeader() http.Header {
+	res := http.Header{}
+	for k, vs := range r.Header() {
+		if k == "Content-Length" || k == "X-Cache" {
+			continue
+		}
+		prev := r.before[k]
+		for i, v := range vs {
+			if i < len(prev) && prev[i] == v {
+				continue
+			}
+			res.Add(k, v)
+		}
+	}
+	return res
+}
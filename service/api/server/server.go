@@ -2,23 +2,32 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/go-acme/lego/v3/providers/dns/cloudflare"
 	"github.com/gorilla/mux"
 	"github.com/micro/micro/v3/plugin"
 	pb "github.com/micro/micro/v3/proto/api"
+	cpb "github.com/micro/micro/v3/proto/connections"
 	"github.com/micro/micro/v3/service"
 	apiserver "github.com/micro/micro/v3/service/api"
 	"github.com/micro/micro/v3/service/api/auth"
 	ahandler "github.com/micro/micro/v3/service/api/handler"
 	aapi "github.com/micro/micro/v3/service/api/handler/api"
 	"github.com/micro/micro/v3/service/api/handler/event"
+	"github.com/micro/micro/v3/service/api/handler/grpcweb"
 	ahttp "github.com/micro/micro/v3/service/api/handler/http"
+	"github.com/micro/micro/v3/service/api/handler/oauth"
+	apioidc "github.com/micro/micro/v3/service/api/handler/oidc"
 	arpc "github.com/micro/micro/v3/service/api/handler/rpc"
+	"github.com/micro/micro/v3/service/api/handler/scim"
 	"github.com/micro/micro/v3/service/api/handler/web"
+	"github.com/micro/micro/v3/service/api/openapi"
 	"github.com/micro/micro/v3/service/api/resolver"
 	"github.com/micro/micro/v3/service/api/resolver/grpc"
 	"github.com/micro/micro/v3/service/api/resolver/host"
@@ -27,16 +36,25 @@ import (
 	"github.com/micro/micro/v3/service/api/router"
 	regRouter "github.com/micro/micro/v3/service/api/router/registry"
 	httpapi "github.com/micro/micro/v3/service/api/server/http"
+	"github.com/micro/micro/v3/service/auth/oidc"
 	log "github.com/micro/micro/v3/service/logger"
 	muregistry "github.com/micro/micro/v3/service/registry"
 	"github.com/micro/micro/v3/service/store"
 	"github.com/micro/micro/v3/util/acme"
 	"github.com/micro/micro/v3/util/acme/autocert"
 	"github.com/micro/micro/v3/util/acme/certmagic"
+	"github.com/micro/micro/v3/util/analytics"
+	"github.com/micro/micro/v3/util/cache"
+	"github.com/micro/micro/v3/util/challenge"
+	"github.com/micro/micro/v3/util/clientip"
+	"github.com/micro/micro/v3/util/cors"
 	"github.com/micro/micro/v3/util/helper"
 	"github.com/micro/micro/v3/util/opentelemetry"
 	"github.com/micro/micro/v3/util/opentelemetry/jaeger"
+	"github.com/micro/micro/v3/util/routelimit"
+	"github.com/micro/micro/v3/util/signedurl"
 	"github.com/micro/micro/v3/util/sync/memory"
+	"github.com/micro/micro/v3/util/transform"
 	"github.com/micro/micro/v3/util/wrapper"
 	"github.com/opentracing/opentracing-go"
 	"github.com/urfave/cli/v2"
@@ -49,6 +67,7 @@ var (
 	Resolver              = "micro"
 	APIPath               = "/"
 	ProxyPath             = "/{service:[a-zA-Z0-9]+}"
+	OAuthPath             = "/oauth/token"
 	Namespace             = ""
 	ACMEProvider          = "autocert"
 	ACMEChallengeProvider = "cloudflare"
@@ -79,10 +98,27 @@ var (
 		},
 		&cli.BoolFlag{
 			Name:    "enable_cors",
-			Usage:   "Enable CORS, allowing the API to be called by frontend applications",
+			Usage:   "Enable CORS, allowing the API to be called by frontend applications from the origins allowed by the policies set in the config service at cors",
 			EnvVars: []string{"MICRO_API_ENABLE_CORS"},
 			Value:   true,
 		},
+		&cli.BoolFlag{
+			Name:    "enable_transforms",
+			Usage:   "Apply the request and response transformation rules set in the config service at transforms",
+			EnvVars: []string{"MICRO_API_ENABLE_TRANSFORMS"},
+			Value:   true,
+		},
+		&cli.BoolFlag{
+			Name:    "enable_cache",
+			Usage:   "Cache the responses to public requests which services mark cacheable with Cache-Control, in the cache backend shared by the replicas of the api. Admins purge them at /_cache",
+			EnvVars: []string{"MICRO_API_ENABLE_CACHE"},
+		},
+		&cli.BoolFlag{
+			Name:    "enable_idempotency",
+			Usage:   "Record the responses to requests made with the Idempotency-Key header in the store, replaying them when the requests are retried",
+			EnvVars: []string{"MICRO_API_ENABLE_IDEMPOTENCY"},
+			Value:   true,
+		},
 		&cli.BoolFlag{
 			Name:    "enable_acme",
 			Usage:   "Enables ACME support via Let's Encrypt. ACME hosts should also be specified.",
@@ -118,6 +154,114 @@ var (
 			Usage:   "Path to the TLS CA file to verify clients against",
 			EnvVars: []string{"MICRO_API_TLS_CLIENT_CA_FILE"},
 		},
+		&cli.BoolFlag{
+			Name:    "enable_oauth",
+			Usage:   "Enable the OAuth2 token endpoint at /oauth/token for machine clients",
+			EnvVars: []string{"MICRO_API_ENABLE_OAUTH"},
+		},
+		&cli.BoolFlag{
+			Name:    "enable_analytics",
+			Usage:   "Publish a sanitized summary of each request to the analytics topic, see the analytics_sample and analytics_scrub flags",
+			EnvVars: []string{"MICRO_API_ENABLE_ANALYTICS"},
+		},
+		&cli.BoolFlag{
+			Name:    "enable_scim",
+			Usage:   "Enable the SCIM 2.0 endpoint at /scim/v2 for provisioning accounts from an identity provider",
+			EnvVars: []string{"MICRO_API_ENABLE_SCIM"},
+		},
+		&cli.BoolFlag{
+			Name:    "enable_connections",
+			Usage:   "Enable websocket and server sent event clients to connect at /_connect, so services can push messages to them with the connections service",
+			EnvVars: []string{"MICRO_API_ENABLE_CONNECTIONS"},
+		},
+		&cli.BoolFlag{
+			Name:    "enable_openapi",
+			Usage:   "Serve an OpenAPI document describing the endpoints of the namespace at /openapi.json",
+			EnvVars: []string{"MICRO_API_ENABLE_OPENAPI"},
+			Value:   true,
+		},
+		&cli.BoolFlag{
+			Name:    "enable_grpc_web",
+			Usage:   "Serve gRPC-Web requests, allowing browser gRPC clients to call services without a separate proxy",
+			EnvVars: []string{"MICRO_API_ENABLE_GRPC_WEB"},
+			Value:   true,
+		},
+		&cli.BoolFlag{
+			Name:    "validate_requests",
+			Usage:   "Validate the fields of JSON request bodies against the registered request of the endpoint, rejecting invalid requests with a 400",
+			EnvVars: []string{"MICRO_API_VALIDATE_REQUESTS"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "trusted_proxies",
+			Usage:   "Comma separated list of CIDRs of the proxies trusted to set the forwarded header, e.g. the load balancer",
+			EnvVars: []string{"MICRO_API_TRUSTED_PROXIES"},
+		},
+		&cli.StringFlag{
+			Name:    "forwarded_header",
+			Usage:   "Set the header the trusted proxies forward the client ip in, X-Forwarded-For or Forwarded",
+			EnvVars: []string{"MICRO_API_FORWARDED_HEADER"},
+			Value:   "X-Forwarded-For",
+		},
+		&cli.Int64Flag{
+			Name:    "rate_limit",
+			Usage:   "Limit the requests made by each account or ip per rate limit window, shared by every replica of the api",
+			EnvVars: []string{"MICRO_API_RATE_LIMIT"},
+		},
+		&cli.DurationFlag{
+			Name:    "rate_limit_window",
+			Usage:   "Set the window the rate limit applies to",
+			EnvVars: []string{"MICRO_API_RATE_LIMIT_WINDOW"},
+			Value:   time.Second,
+		},
+		&cli.Int64Flag{
+			Name:    "rate_limit_burst",
+			Usage:   "Set the number of requests which can be made at once, defaults to the rate limit",
+			EnvVars: []string{"MICRO_API_RATE_LIMIT_BURST"},
+		},
+		&cli.BoolFlag{
+			Name:    "enable_route_limits",
+			Usage:   "Enforce the limits of the routes set in the config service at routelimits, shared by every replica of the api",
+			EnvVars: []string{"MICRO_API_ENABLE_ROUTE_LIMITS"},
+			Value:   true,
+		},
+		&cli.StringFlag{
+			Name:    "challenge",
+			Usage:   "Challenge clients which overflow the rate limit before they can continue; {turnstile, hcaptcha, pow}",
+			EnvVars: []string{"MICRO_API_CHALLENGE"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "challenge_routes",
+			Usage:   "Comma separated list of path prefixes flagged clients are challenged on, * matches every route",
+			EnvVars: []string{"MICRO_API_CHALLENGE_ROUTES"},
+			Value:   cli.NewStringSlice("*"),
+		},
+		&cli.StringFlag{
+			Name:    "challenge_site_key",
+			Usage:   "Set the site key of the turnstile or hcaptcha site",
+			EnvVars: []string{"MICRO_API_CHALLENGE_SITE_KEY"},
+		},
+		&cli.StringFlag{
+			Name:    "blob_signing_key",
+			Usage:   "Set the key signed urls to blobs are verified with, it must be the same as the store's. Signed urls aren't served without it",
+			EnvVars: []string{"MICRO_BLOB_SIGNING_KEY"},
+		},
+		&cli.StringFlag{
+			Name:    "challenge_secret",
+			Usage:   "Set the turnstile or hcaptcha secret, also used to sign challenge passes so it must be the same for every replica of the api",
+			EnvVars: []string{"MICRO_API_CHALLENGE_SECRET"},
+		},
+		&cli.IntFlag{
+			Name:    "challenge_difficulty",
+			Usage:   "Set the number of leading zero bits required to solve proof of work challenges",
+			EnvVars: []string{"MICRO_API_CHALLENGE_DIFFICULTY"},
+			Value:   challenge.DefaultDifficulty,
+		},
+		&cli.DurationFlag{
+			Name:    "challenge_duration",
+			Usage:   "Set how long clients are challenged for after overflowing the rate limit, and how long a pass is valid for",
+			EnvVars: []string{"MICRO_API_CHALLENGE_DURATION"},
+			Value:   time.Minute * 30,
+		},
 	}
 )
 
@@ -146,6 +290,19 @@ func Run(ctx *cli.Context) error {
 	if len(ctx.String("api_address")) > 0 {
 		Address = ctx.String("api_address")
 	}
+	if proxies := ctx.StringSlice("trusted_proxies"); len(proxies) > 0 {
+		trusted, err := clientip.ParseTrusted(proxies)
+		if err != nil {
+			log.Fatalf("Invalid trusted proxies: %v", err)
+		}
+		clientip.DefaultTrusted = trusted
+	}
+	if h := ctx.String("forwarded_header"); len(h) > 0 {
+		if !strings.EqualFold(h, "X-Forwarded-For") && !strings.EqualFold(h, "Forwarded") {
+			log.Fatalf("Invalid forwarded header %v, expected X-Forwarded-For or Forwarded", h)
+		}
+		clientip.DefaultHeader = h
+	}
 	// initialise service
 	srv := service.New(service.Name(Name))
 
@@ -156,9 +313,10 @@ func Run(ctx *cli.Context) error {
 		hosts := helper.ACMEHosts(ctx)
 		opts = append(opts, apiserver.EnableACME(true))
 		opts = append(opts, apiserver.ACMEHosts(hosts...))
+		// certificates are also issued for the custom domains of namespaces
 		switch ACMEProvider {
 		case "autocert":
-			opts = append(opts, apiserver.ACMEProvider(autocert.NewProvider()))
+			opts = append(opts, apiserver.ACMEProvider(autocert.NewProvider(acme.HostPolicy(auth.HostPolicy))))
 		case "certmagic":
 			if ACMEChallengeProvider != "cloudflare" {
 				log.Fatal("The only implemented DNS challenge provider is cloudflare")
@@ -190,6 +348,7 @@ func Run(ctx *cli.Context) error {
 						acme.Cache(storage),
 						acme.ChallengeProvider(challengeProvider),
 						acme.OnDemand(false),
+						acme.HostPolicy(auth.HostPolicy),
 					),
 				),
 			)
@@ -207,10 +366,6 @@ func Run(ctx *cli.Context) error {
 		opts = append(opts, apiserver.TLSConfig(config))
 	}
 
-	if ctx.Bool("enable_cors") {
-		opts = append(opts, apiserver.EnableCORS(true))
-	}
-
 	// create the router
 	var h http.Handler
 	r := mux.NewRouter()
@@ -229,6 +384,30 @@ func Run(ctx *cli.Context) error {
 	// strip favicon.ico
 	r.HandleFunc("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {})
 
+	// serve the oauth token endpoint
+	if ctx.Bool("enable_oauth") {
+		log.Infof("Registering OAuth Token Handler at %s", OAuthPath)
+		r.Handle(OAuthPath, oauth.NewHandler(ahandler.WithClient(srv.Client())))
+	}
+
+	// log users in with the openid connect provider
+	if oidc.DefaultProvider != nil {
+		log.Infof("Registering OIDC Login Handler at %s", apioidc.Path)
+		r.PathPrefix(apioidc.Path + "/").Handler(apioidc.NewHandler(oidc.DefaultProvider))
+	}
+
+	// serve the scim provisioning endpoint
+	if ctx.Bool("enable_scim") {
+		log.Infof("Registering SCIM Handler at %s", scim.Path)
+		r.PathPrefix(scim.Path + "/").Handler(scim.NewHandler(ahandler.WithClient(srv.Client())))
+	}
+
+	// serve the openapi document generated from the registry
+	if ctx.Bool("enable_openapi") {
+		log.Infof("Registering OpenAPI Handler at %s", openapi.Path)
+		r.Handle(openapi.Path, openapi.NewHandler(Namespace))
+	}
+
 	// resolver options
 	ropts := []resolver.Option{
 		resolver.WithServicePrefix(Namespace),
@@ -249,6 +428,31 @@ func Run(ctx *cli.Context) error {
 		rr = grpc.NewResolver(ropts...)
 	}
 
+	// serve the grpc-web requests, they're resolved to the endpoint they call so they're
+	// authorized like any other request to it
+	if ctx.Bool("enable_grpc_web") {
+		if sr, ok := rr.(*subdomain.Resolver); ok {
+			sr.Resolver = grpcweb.NewResolver(sr.Resolver, ropts...)
+		} else {
+			rr = grpcweb.NewResolver(rr, ropts...)
+		}
+
+		log.Infof("Registering gRPC-Web Handler")
+		rt := regRouter.NewRouter(
+			router.WithHandler(arpc.Handler),
+			router.WithResolver(rr),
+			router.WithRegistry(muregistry.DefaultRegistry),
+		)
+		gw := grpcweb.NewHandler(
+			ahandler.WithNamespace(Namespace),
+			ahandler.WithRouter(rt),
+			ahandler.WithClient(srv.Client()),
+		)
+		r.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+			return grpcweb.IsRequest(req)
+		}).Handler(gw)
+	}
+
 	switch Handler {
 	case "rpc":
 		log.Infof("Registering API RPC Handler at %s", APIPath)
@@ -261,6 +465,7 @@ func Run(ctx *cli.Context) error {
 			ahandler.WithNamespace(Namespace),
 			ahandler.WithRouter(rt),
 			ahandler.WithClient(srv.Client()),
+			ahandler.WithValidation(ctx.Bool("validate_requests")),
 		)
 		r.PathPrefix(APIPath).Handler(rp)
 	case "api":
@@ -321,7 +526,7 @@ func Run(ctx *cli.Context) error {
 			router.WithResolver(rr),
 			router.WithRegistry(muregistry.DefaultRegistry),
 		)
-		r.PathPrefix(APIPath).Handler(Meta(srv, rt, Namespace))
+		r.PathPrefix(APIPath).Handler(Meta(srv, rt, Namespace, ahandler.WithValidation(ctx.Bool("validate_requests"))))
 	}
 
 	// register all the http handler plugins
@@ -353,8 +558,100 @@ func Run(ctx *cli.Context) error {
 	// append the opentelemetry wrapper
 	h = wrapper.HTTPWrapper(h)
 
+	// append the connections wrapper, it runs after the auth and rate limit wrappers so clients
+	// connect as an account and connections are limited
+	// the cors policies decide the origins clients can connect from too
+	var corsPolicies *cors.Policies
+	if ctx.Bool("enable_cors") {
+		corsPolicies = cors.New()
+	}
+	var gw *gateway
+	if ctx.Bool("enable_connections") {
+		log.Infof("Serving connections at %s", ConnectPath)
+		gw = newGateway(Name, srv.Server().Options().Id, srv.Client(), corsPolicies)
+		h = gw.wrapper(h)
+	}
+
+	// append the cache wrapper, it runs after the auth and rate limit wrappers so cached responses
+	// are only returned to requests which are allowed
+	if ctx.Bool("enable_cache") {
+		log.Infof("Caching responses in the %v cache backend", cache.DefaultBackend)
+		h = cacheWrapper(cache.NewHTTP())(h)
+	}
+
+	// append the idempotency wrapper, it runs after the auth wrapper so keys are scoped by account
+	if ctx.Bool("enable_idempotency") {
+		h = idempotencyWrapper(h)
+	}
+
+	// append the rate limit wrappers, they run after the auth wrapper so requests are limited by
+	// account. Route limits match the path once it's rewritten by the transform wrapper.
+	var flagFor time.Duration
+	if len(ctx.String("challenge")) > 0 {
+		flagFor = ctx.Duration("challenge_duration")
+	}
+	if ctx.Bool("enable_route_limits") {
+		h = routeLimitWrapper(routelimit.New(), flagFor)(h)
+	}
+	if limit := ctx.Int64("rate_limit"); limit > 0 {
+		h = rateLimitWrapper(limit, ctx.Duration("rate_limit_window"), ctx.Int64("rate_limit_burst"), flagFor)(h)
+	}
+
+	// append the challenge wrapper, it runs before the rate limit wrapper so flagged clients
+	// don't use up their limit until they've passed the challenge
+	if name := ctx.String("challenge"); len(name) > 0 {
+		secret := ctx.String("challenge_secret")
+		if len(secret) == 0 {
+			log.Fatal("The challenge secret is required to sign challenge passes")
+		}
+
+		var v challenge.Verifier
+		switch name {
+		case "turnstile":
+			v = challenge.NewTurnstile(ctx.String("challenge_site_key"), secret)
+		case "hcaptcha":
+			v = challenge.NewHCaptcha(ctx.String("challenge_site_key"), secret)
+		case "pow":
+			v = challenge.NewProofOfWork(secret, ctx.Int("challenge_difficulty"))
+		default:
+			log.Fatalf("Unknown challenge %v", name)
+		}
+
+		h = challengeWrapper(v, secret, ctx.StringSlice("challenge_routes"), flagFor)(h)
+	}
+
 	// append the auth wrapper
-	h = auth.Wrapper(rr, Namespace)(h)
+	h = auth.RPCWrapper(rr, Namespace)(h)
+
+	// append the transform wrapper, it runs before the auth wrapper so requests are authorized and
+	// routed by their rewritten path
+	if ctx.Bool("enable_transforms") {
+		h = transformWrapper(transform.New())(h)
+	}
+
+	// append the signed blob url wrapper, it runs before the auth wrapper as the signature
+	// authorizes the request
+	if key := ctx.String("blob_signing_key"); len(key) > 0 {
+		log.Infof("Serving signed blob urls at %s", signedurl.Path)
+		h = blobWrapper([]byte(key))(h)
+	}
+
+	// append the analytics wrapper, it runs before the auth wrapper so rejected requests are
+	// recorded too
+	if ctx.Bool("enable_analytics") {
+		log.Infof("Publishing request analytics to %v, sampled at %v", analytics.Topic, analytics.DefaultTap)
+		h = analyticsWrapper(h)
+	}
+
+	// append the capture wrapper, it runs before the other wrappers so rejected requests are
+	// captured too
+	h = captureWrapper(h)
+
+	// append the cors wrapper, it runs before the capture wrapper so preflight requests aren't
+	// captured
+	if ctx.Bool("enable_cors") {
+		h = corsWrapper(corsPolicies, rr)(h)
+	}
 
 	// create a new api server with wrappers
 	api := httpapi.NewServer(Address)
@@ -370,6 +667,14 @@ func Run(ctx *cli.Context) error {
 
 	pb.RegisterApiHandler(srv.Server(), &ahandler.APIHandler{})
 
+	// the connections service delivers the messages pushed to the clients of this instance
+	if gw != nil {
+		cpb.RegisterGatewayHandler(srv.Server(), gw)
+		refreshCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go gw.refresh(refreshCtx)
+	}
+
 	// Run server
 	if err := srv.Run(); err != nil {
 		log.Fatal(err)
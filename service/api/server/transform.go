@@ -0,0 +1,152 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/util/transform"
+)
+
+// transformWrapper applies the transformation rules to the requests and their responses. It runs
+// before the auth wrapper so requests are routed by their rewritten path.
+func transformWrapper(t *transform.Transformer) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rule := t.Match(r.Host, r.Method, r.URL.Path)
+			if rule == nil {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			if tr := rule.Request; tr != nil {
+				tr.Headers(r.Header)
+				if path := tr.Path(r.URL.Path); path != r.URL.Path {
+					r.URL.Path = path
+					r.URL.RawPath = ""
+				}
+				if tr.HasBody() && r.Body != nil && isJSON(r.Header) {
+					transformRequestBody(rule, tr, r)
+				}
+			}
+
+			if rule.Response == nil {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			tw := &transformWriter{ResponseWriter: w, rule: rule}
+			h.ServeHTTP(tw, r)
+			tw.finish()
+		})
+	}
+}
+
+// transformRequestBody replaces the body of the request with the transformed body. Bodies which
+// are too large or can't be transformed are passed through.
+func transformRequestBody(rule *transform.Rule, tr *transform.Transform, r *http.Request) {
+	b, err := ioutil.ReadAll(io.LimitReader(r.Body, int64(transform.MaxBodySize)+1))
+	if err != nil || len(b) > transform.MaxBodySize {
+		r.Body = readCloser{io.MultiReader(bytes.NewReader(b), r.Body), r.Body}
+		return
+	}
+	r.Body.Close()
+
+	out, err := tr.Body(b)
+	if err != nil {
+		logger.Debugf("Error transforming request body for rule %v: %v", rule.Route, err)
+		out = b
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(out))
+	r.ContentLength = int64(len(out))
+	r.Header.Set("Content-Length", strconv.Itoa(len(out)))
+}
+
+// isJSON returns true if the content type of the headers is JSON
+func isJSON(h http.Header) bool {
+	ct, _, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	return ct == "application/json" || strings.HasSuffix(ct, "+json")
+}
+
+// transformWriter applies the response transform of the rule. JSON bodies are buffered so their
+// fields can be transformed, other bodies, e.g. streams, are written through.
+type transformWriter struct {
+	http.ResponseWriter
+	rule   *transform.Rule
+	status int
+	wrote  bool
+	buf    *bytes.Buffer
+}
+
+func (t *transformWriter) WriteHeader(status int) {
+	if t.wrote {
+		return
+	}
+	t.wrote = true
+	t.rule.Response.Headers(t.Header())
+
+	h := t.Header()
+	if t.rule.Response.HasBody() && isJSON(h) && len(h.Get("Content-Encoding")) == 0 {
+		t.status = status
+		t.buf = &bytes.Buffer{}
+		return
+	}
+	t.ResponseWriter.WriteHeader(status)
+}
+
+func (t *transformWriter) Write(b []byte) (int, error) {
+	if !t.wrote {
+		t.WriteHeader(http.StatusOK)
+	}
+	if t.buf == nil {
+		return t.ResponseWriter.Write(b)
+	}
+	return t.buf.Write(b)
+}
+
+// finish writes the buffered body, transformed
+func (t *transformWriter) finish() {
+	if t.buf == nil {
+		return
+	}
+	b := t.buf.Bytes()
+	if len(b) <= transform.MaxBodySize {
+		out, err := t.rule.Response.Body(b)
+		if err != nil {
+			logger.Debugf("Error transforming response body for rule %v: %v", t.rule.Route, err)
+		} else {
+			b = out
+		}
+	}
+	t.Header().Set("Content-Length", strconv.Itoa(len(b)))
+	t.ResponseWriter.WriteHeader(t.status)
+	t.ResponseWriter.Write(b)
+}
+
+func (t *transformWriter) Flush() {
+	// buffered bodies are written once the handler returns
+	if t.buf != nil {
+		return
+	}
+	if f, ok := t.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (t *transformWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := t.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, errors.New("hijacking isn't supported")
+}
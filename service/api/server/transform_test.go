@@ -0,0 +1,74 @@
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/micro/micro/v3/util/transform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformWrapper(t *testing.T) {
+	tr, err := transform.NewStatic(&transform.Rule{
+		Route: "/v1/users",
+		Request: &transform.Transform{
+			SetHeaders:    map[string]string{"Api-Version": "1"},
+			RemoveHeaders: []string{"X-Debug"},
+			Rewrite:       &transform.Rewrite{From: "^/v1/users/(.*)$", To: "/users/$1"},
+			Rename:        map[string]string{"userName": "name"},
+		},
+		Response: &transform.Transform{
+			RemoveHeaders: []string{"X-Powered-By"},
+			Rename:        map[string]string{"name": "userName"},
+			Remove:        []string{"password"},
+		},
+	})
+	assert.NoError(t, err)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1", r.Header.Get("Api-Version"))
+		assert.Empty(t, r.Header.Get("X-Debug"))
+		b, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		switch r.URL.Path {
+		case "/users/read":
+			assert.JSONEq(t, `{"name": "john"}`, string(b))
+			assert.Equal(t, int64(len(b)), r.ContentLength)
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Powered-By", "micro")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"name": "john", `))
+			w.Write([]byte(`"password": "secret"}`))
+		case "/users/stream":
+			// bodies which aren't JSON are passed through
+			assert.Equal(t, `{"userName": "john"}`, string(b))
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Write([]byte(`data: {"name": "john"}`))
+		default:
+			t.Fatalf("unexpected path %v", r.URL.Path)
+		}
+	})
+	h := transformWrapper(tr)(next)
+
+	req := httptest.NewRequest("POST", "/v1/users/read", strings.NewReader(`{"userName": "john"}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("X-Debug", "true")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, `{"userName": "john"}`, rec.Body.String())
+	assert.Equal(t, "19", rec.Header().Get("Content-Length"))
+	assert.Empty(t, rec.Header().Get("X-Powered-By"))
+
+	req = httptest.NewRequest("POST", "/v1/users/stream", strings.NewReader(`{"userName": "john"}`))
+	req.Header.Set("Content-Type", "text/plain")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `data: {"name": "john"}`, rec.Body.String())
+	assert.Empty(t, rec.Header().Get("Content-Length"))
+}
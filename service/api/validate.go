@@ -0,0 +1,250 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/micro/micro/v3/service/registry"
+)
+
+// FieldError describes why a field of a request is invalid
+type FieldError struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// ValidationError is returned by ValidateRequest when fields of the request are invalid
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+func (v *ValidationError) Error() string {
+	msgs := make([]string, 0, len(v.Errors))
+	for _, e := range v.Errors {
+		if len(e.Field) == 0 {
+			msgs = append(msgs, e.Description)
+			continue
+		}
+		msgs = append(msgs, e.Field+": "+e.Description)
+	}
+	return strings.Join(msgs, ", ")
+}
+
+// ValidateOptions are the options of ValidateRequest
+type ValidateOptions struct {
+	// RejectUnknown rejects the fields which aren't fields of the request
+	RejectUnknown bool
+}
+
+// ValidateOption sets an option of ValidateRequest
+type ValidateOption func(o *ValidateOptions)
+
+// RejectUnknownFields rejects the fields of the payload which aren't fields of the request, rather
+// than them being dropped by the service. Messages the registry doesn't describe the fields of,
+// e.g. those nested deeper than the registry records, aren't checked.
+func RejectUnknownFields() ValidateOption {
+	return func(o *ValidateOptions) {
+		o.RejectUnknown = true
+	}
+}
+
+// ValidateRequest checks the types of the fields of a JSON request payload against the endpoint's request
+// as registered by the service, so malformed requests are rejected before they reach it. Fields
+// which aren't described by the request value, e.g. enums, aren't checked. It should be
+// called after Transcode since the well known types are checked by the service.
+func ValidateRequest(payload []byte, req *registry.Value, opts ...ValidateOption) error {
+	if req == nil || len(payload) == 0 {
+		return nil
+	}
+	var options ValidateOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return &ValidationError{Errors: []*FieldError{{Description: "invalid JSON: " + err.Error()}}}
+	}
+
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return &ValidationError{Errors: []*FieldError{{Description: "expected a JSON object"}}}
+	}
+
+	var errs []*FieldError
+	validateMessage(obj, req, "", &options, &errs)
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+func validateMessage(obj map[string]interface{}, msg *registry.Value, prefix string, opts *ValidateOptions, errs *[]*FieldError) {
+	known := make(map[string]bool, len(msg.Values)*2)
+	for _, field := range msg.Values {
+		known[field.Name] = true
+		known[lowerCamel(field.Name)] = true
+
+		for _, name := range []string{field.Name, lowerCamel(field.Name)} {
+			val, ok := obj[name]
+			if !ok {
+				continue
+			}
+			validateField(val, field, prefix+name, opts, errs)
+			break
+		}
+	}
+
+	if !opts.RejectUnknown || len(msg.Values) == 0 {
+		return
+	}
+	var unknown []string
+	for name := range obj {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		*errs = append(*errs, &FieldError{Field: prefix + name, Description: "unknown field"})
+	}
+}
+
+func validateField(val interface{}, field *registry.Value, path string, opts *ValidateOptions, errs *[]*FieldError) {
+	// null is the default value of every field
+	if val == nil {
+		return
+	}
+
+	switch {
+	case isWellKnownType(field.Type):
+		// checked by the service once transcoded
+	case field.Type == "[]uint8":
+		validateValue(val, "bytes", path, errs)
+	case strings.HasPrefix(field.Type, "[]"):
+		list, ok := val.([]interface{})
+		if !ok {
+			*errs = append(*errs, &FieldError{Field: path, Description: "expected an array"})
+			return
+		}
+		typ := strings.TrimPrefix(field.Type, "[]")
+		for i, v := range list {
+			if v != nil {
+				validateValue(v, typ, fmt.Sprintf("%v[%d]", path, i), errs)
+			}
+		}
+	case strings.HasPrefix(field.Type, "map["):
+		m, ok := val.(map[string]interface{})
+		if !ok {
+			*errs = append(*errs, &FieldError{Field: path, Description: "expected an object"})
+			return
+		}
+		typ := field.Type[strings.Index(field.Type, "]")+1:]
+		for k, v := range m {
+			if v != nil {
+				validateValue(v, typ, path+"."+k, errs)
+			}
+		}
+	case len(field.Values) > 0:
+		obj, ok := val.(map[string]interface{})
+		if !ok {
+			*errs = append(*errs, &FieldError{Field: path, Description: "expected an object"})
+			return
+		}
+		validateMessage(obj, field, path+".", opts, errs)
+	default:
+		validateValue(val, field.Type, path, errs)
+	}
+}
+
+// validateValue checks a value of a scalar type, other types aren't checked
+func validateValue(val interface{}, typ, path string, errs *[]*FieldError) {
+	var desc string
+
+	switch typ {
+	case "string":
+		if _, ok := val.(string); !ok {
+			desc = "expected a string"
+		}
+	case "bool":
+		if _, ok := val.(bool); !ok {
+			desc = "expected a boolean"
+		}
+	case "bytes":
+		s, ok := val.(string)
+		if !ok || !isBase64(s) {
+			desc = "expected a base64 encoded string"
+		}
+	case "int32", "int64", "uint32", "uint64":
+		desc = validateInt(val, typ)
+	case "float32", "float64":
+		// numbers can be sent as strings, as can the special values such as NaN
+		if s := toString(val); len(s) == 0 {
+			desc = "expected a number"
+		} else if _, err := strconv.ParseFloat(s, 64); err != nil && s != "NaN" && s != "Infinity" && s != "-Infinity" {
+			desc = "expected a number"
+		}
+	}
+
+	if len(desc) > 0 {
+		*errs = append(*errs, &FieldError{Field: path, Description: desc})
+	}
+}
+
+// validateInt returns a description of the error if the value isn't an integer in the range of
+// the type. Integers can be sent as numbers or strings.
+func validateInt(val interface{}, typ string) string {
+	s := toString(val)
+	if len(s) == 0 {
+		return "expected an integer"
+	}
+
+	bits := 64
+	if strings.HasSuffix(typ, "32") {
+		bits = 32
+	}
+
+	if strings.HasPrefix(typ, "uint") {
+		if _, err := strconv.ParseUint(s, 10, bits); err == nil {
+			return ""
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil && f == math.Trunc(f) && f >= 0 && f <= math.MaxUint64 {
+			if bits == 64 || f <= math.MaxUint32 {
+				return ""
+			}
+		}
+		return fmt.Sprintf("expected an unsigned %d bit integer", bits)
+	}
+
+	if _, err := strconv.ParseInt(s, 10, bits); err == nil {
+		return ""
+	}
+	// integers in exponent form, e.g. 1e3
+	if f, err := strconv.ParseFloat(s, 64); err == nil && f == math.Trunc(f) {
+		if bits == 64 && f >= math.MinInt64 && f <= math.MaxInt64 {
+			return ""
+		}
+		if bits == 32 && f >= math.MinInt32 && f <= math.MaxInt32 {
+			return ""
+		}
+	}
+	return fmt.Sprintf("expected a %d bit integer", bits)
+}
+
+func isBase64(s string) bool {
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if _, err := enc.DecodeString(s); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRequest(t *testing.T) {
+	req := &registry.Value{
+		Name: "CreateRequest",
+		Values: []*registry.Value{
+			{Name: "name", Type: "string"},
+			{Name: "age", Type: "int32"},
+			{Name: "balance", Type: "uint64"},
+			{Name: "score", Type: "float64"},
+			{Name: "active", Type: "bool"},
+			{Name: "avatar", Type: "[]uint8"},
+			{Name: "tags", Type: "[]string"},
+			{Name: "labels", Type: "map[string]string"},
+			{Name: "created_at", Type: "google.protobuf.Timestamp"},
+			{Name: "address", Type: "Address", Values: []*registry.Value{
+				{Name: "post_code", Type: "string"},
+			}},
+		},
+	}
+
+	tt := []struct {
+		Name    string
+		Payload string
+		Errors  []*FieldError
+	}{
+		{
+			Name:    "Valid",
+			Payload: `{"name":"john","age":"30","balance":1e3,"score":"NaN","active":true,"avatar":"aGVsbG8=","tags":["a"],"labels":{"a":"b"},"createdAt":"2020-01-01T00:00:00Z","address":{"postCode":"N1"}}`,
+		},
+		{
+			Name:    "Nulls",
+			Payload: `{"name":null,"tags":[null],"address":null}`,
+		},
+		{
+			Name:    "UnknownFields",
+			Payload: `{"foo":1}`,
+		},
+		{
+			Name:    "InvalidJSON",
+			Payload: `{"name":`,
+			Errors:  []*FieldError{{Description: "invalid JSON: unexpected EOF"}},
+		},
+		{
+			Name:    "NotAnObject",
+			Payload: `[]`,
+			Errors:  []*FieldError{{Description: "expected a JSON object"}},
+		},
+		{
+			Name:    "InvalidFields",
+			Payload: `{"name":1,"age":1.5,"balance":-1,"active":"yes","avatar":"!","tags":"a","labels":{"a":1},"address":{"post_code":false}}`,
+			Errors: []*FieldError{
+				{Field: "name", Description: "expected a string"},
+				{Field: "age", Description: "expected a 32 bit integer"},
+				{Field: "balance", Description: "expected an unsigned 64 bit integer"},
+				{Field: "active", Description: "expected a boolean"},
+				{Field: "avatar", Description: "expected a base64 encoded string"},
+				{Field: "tags", Description: "expected an array"},
+				{Field: "labels.a", Description: "expected a string"},
+				{Field: "address.post_code", Description: "expected a string"},
+			},
+		},
+		{
+			Name:    "OutOfRange",
+			Payload: `{"age":3000000000}`,
+			Errors:  []*FieldError{{Field: "age", Description: "expected a 32 bit integer"}},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := ValidateRequest([]byte(tc.Payload), req)
+			if len(tc.Errors) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			verr, ok := err.(*ValidationError)
+			if !assert.True(t, ok) {
+				return
+			}
+			assert.Equal(t, tc.Errors, verr.Errors)
+		})
+	}
+}
+
+func TestValidateRequestStrict(t *testing.T) {
+	req := &registry.Value{
+		Name: "CreateRequest",
+		Values: []*registry.Value{
+			{Name: "name", Type: "string"},
+			{Name: "created_at", Type: "google.protobuf.Timestamp"},
+			{Name: "labels", Type: "map[string]string"},
+			{Name: "address", Type: "Address", Values: []*registry.Value{
+				{Name: "post_code", Type: "string"},
+			}},
+			{Name: "status", Type: "Status"},
+		},
+	}
+
+	// known fields, by either name, and the keys of maps are allowed
+	err := ValidateRequest([]byte(`{"name":"john","createdAt":"2020-01-01T00:00:00Z","labels":{"a":"b"},"address":{"postCode":"N1"},"status":"ACTIVE"}`), req, RejectUnknownFields())
+	assert.NoError(t, err)
+
+	// unknown fields are ignored unless they're rejected
+	payload := []byte(`{"nmae":"john","address":{"post_code":"N1","town":"London"},"age":30}`)
+	assert.NoError(t, ValidateRequest(payload, req))
+
+	err = ValidateRequest(payload, req, RejectUnknownFields())
+	verr, ok := err.(*ValidationError)
+	if assert.True(t, ok) {
+		assert.Equal(t, []*FieldError{
+			{Field: "address.town", Description: "unknown field"},
+			{Field: "age", Description: "unknown field"},
+			{Field: "nmae", Description: "unknown field"},
+		}, verr.Errors)
+	}
+
+	// fields of the wrong type are still rejected
+	err = ValidateRequest([]byte(`{"name":1}`), req, RejectUnknownFields())
+	verr, ok = err.(*ValidationError)
+	if assert.True(t, ok) {
+		assert.Equal(t, []*FieldError{{Field: "name", Description: "expected a string"}}, verr.Errors)
+	}
+}
@@ -0,0 +1,343 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/micro/micro/v3/service/registry"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	// anyPrefix is the default type url prefix for google.protobuf.Any
+	anyPrefix = "type.googleapis.com/"
+)
+
+// Transcode rewrites the well known protobuf types in a JSON payload, e.g. google.protobuf.Timestamp, into
+// their canonical JSON form. Loose forms such as unix timestamps, {"seconds": 1} objects or {"value": "foo"}
+// wrappers are accepted from callers, and are what services encoding messages without jsonpb return. The
+// value describes the message as registered by the service, i.e. the request or the response of the
+// endpoint; payloads with no well known types are returned as is.
+func Transcode(payload []byte, req *registry.Value) ([]byte, error) {
+	if req == nil || len(payload) == 0 || !hasWellKnownTypes(req) {
+		return payload, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return payload, nil
+	}
+	if !transcodeMessage(obj, req) {
+		return payload, nil
+	}
+
+	// the payload is written as is, so html characters in it aren't escaped
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(obj); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(b.Bytes(), "\n"), nil
+}
+
+// hasWellKnownTypes checks if any of the fields in the value are well known types
+func hasWellKnownTypes(v *registry.Value) bool {
+	if isWellKnownType(v.Type) {
+		return true
+	}
+	for _, f := range v.Values {
+		if hasWellKnownTypes(f) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWellKnownType(t string) bool {
+	return strings.Contains(t, "google.protobuf.")
+}
+
+// transcodeMessage walks the fields of the message, returning true if any were changed
+func transcodeMessage(obj map[string]interface{}, msg *registry.Value) bool {
+	var changed bool
+
+	for _, field := range msg.Values {
+		// jsonpb accepts both the original field name and its lower camel case form
+		for _, name := range []string{field.Name, lowerCamel(field.Name)} {
+			val, ok := obj[name]
+			if !ok {
+				continue
+			}
+			if nval, ok := transcodeField(val, field); ok {
+				obj[name] = nval
+				changed = true
+			}
+			break
+		}
+	}
+
+	return changed
+}
+
+// transcodeField returns the transcoded value and true if it was changed
+func transcodeField(val interface{}, field *registry.Value) (interface{}, bool) {
+	switch {
+	case strings.HasPrefix(field.Type, "[]") && isWellKnownType(field.Type):
+		list, ok := val.([]interface{})
+		if !ok {
+			return nil, false
+		}
+		return transcodeEach(list, strings.TrimPrefix(field.Type, "[]"))
+	case strings.HasPrefix(field.Type, "map[") && isWellKnownType(field.Type):
+		m, ok := val.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		typ := field.Type[strings.Index(field.Type, "]")+1:]
+		var changed bool
+		for k, v := range m {
+			if nv, ok := transcodeValue(v, typ); ok {
+				m[k] = nv
+				changed = true
+			}
+		}
+		return m, changed
+	case isWellKnownType(field.Type):
+		return transcodeValue(val, field.Type)
+	case len(field.Values) > 0:
+		obj, ok := val.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		return obj, transcodeMessage(obj, field)
+	}
+
+	return nil, false
+}
+
+func transcodeEach(list []interface{}, typ string) (interface{}, bool) {
+	var changed bool
+	for i, v := range list {
+		if nv, ok := transcodeValue(v, typ); ok {
+			list[i] = nv
+			changed = true
+		}
+	}
+	return list, changed
+}
+
+// transcodeValue converts a single well known type to its canonical JSON form
+func transcodeValue(val interface{}, typ string) (interface{}, bool) {
+	switch typ {
+	case "google.protobuf.Timestamp":
+		return transcodeTimestamp(val)
+	case "google.protobuf.Duration":
+		return transcodeDuration(val)
+	case "google.protobuf.Any":
+		return transcodeAny(val)
+	case "google.protobuf.Struct", "google.protobuf.ListValue":
+		// structs are sometimes sent as an encoded JSON string, e.g. from a query string
+		s, ok := val.(string)
+		if !ok {
+			return nil, false
+		}
+		var v interface{}
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			return nil, false
+		}
+		return v, true
+	case "google.protobuf.FieldMask":
+		// the JSON form of a field mask is a comma separated string
+		list, ok := val.([]interface{})
+		if !ok {
+			return nil, false
+		}
+		paths := make([]string, 0, len(list))
+		for _, p := range list {
+			s, ok := p.(string)
+			if !ok {
+				return nil, false
+			}
+			paths = append(paths, s)
+		}
+		return strings.Join(paths, ","), true
+	case "google.protobuf.StringValue":
+		val = unwrap(val)
+		switch v := val.(type) {
+		case json.Number:
+			return v.String(), true
+		case bool:
+			return strconv.FormatBool(v), true
+		}
+		return val, true
+	case "google.protobuf.BoolValue", "google.protobuf.BytesValue",
+		"google.protobuf.DoubleValue", "google.protobuf.FloatValue",
+		"google.protobuf.Int32Value", "google.protobuf.Int64Value",
+		"google.protobuf.UInt32Value", "google.protobuf.UInt64Value":
+		if m, ok := val.(map[string]interface{}); ok {
+			if v, ok := m["value"]; ok && len(m) == 1 {
+				return v, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// unwrap returns the value of a wrapper type given in its object form, e.g. {"value": "foo"}
+func unwrap(val interface{}) interface{} {
+	if m, ok := val.(map[string]interface{}); ok && len(m) == 1 {
+		if v, ok := m["value"]; ok {
+			return v
+		}
+	}
+	return val
+}
+
+// seconds and nanos from either a number of seconds or a {"seconds": 1, "nanos": 0} object
+func secondsAndNanos(val interface{}) (int64, int64, bool) {
+	switch v := val.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, 0, false
+		}
+		secs := int64(f)
+		return secs, int64((f - float64(secs)) * 1e9), true
+	case map[string]interface{}:
+		var secs, nanos int64
+		if n, ok := v["seconds"]; ok {
+			s, err := strconv.ParseInt(toString(n), 10, 64)
+			if err != nil {
+				return 0, 0, false
+			}
+			secs = s
+		}
+		if n, ok := v["nanos"]; ok {
+			s, err := strconv.ParseInt(toString(n), 10, 64)
+			if err != nil {
+				return 0, 0, false
+			}
+			nanos = s
+		}
+		return secs, nanos, true
+	}
+	return 0, 0, false
+}
+
+func transcodeTimestamp(val interface{}) (interface{}, bool) {
+	secs, nanos, ok := secondsAndNanos(val)
+	if !ok {
+		return nil, false
+	}
+	return time.Unix(secs, nanos).UTC().Format(time.RFC3339Nano), true
+}
+
+func transcodeDuration(val interface{}) (interface{}, bool) {
+	var d time.Duration
+
+	if s, ok := val.(string); ok {
+		// the canonical form is already seconds with an "s" suffix, e.g. 1.5s
+		if _, err := strconv.ParseFloat(strings.TrimSuffix(s, "s"), 64); err == nil {
+			return nil, false
+		}
+		// otherwise accept go durations such as 1m30s
+		pd, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, false
+		}
+		d = pd
+	} else {
+		secs, nanos, ok := secondsAndNanos(val)
+		if !ok {
+			return nil, false
+		}
+		d = time.Duration(secs)*time.Second + time.Duration(nanos)
+	}
+
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s", true
+}
+
+func transcodeAny(val interface{}) (interface{}, bool) {
+	m, ok := val.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	// the JSON form with the type url and the message fields inline
+	if t, ok := m["@type"].(string); ok {
+		if strings.Contains(t, "/") {
+			return nil, false
+		}
+		m["@type"] = anyPrefix + t
+		return m, true
+	}
+
+	// the binary form with the type url and the base64 encoded message, this can only be
+	// converted if the message type is registered
+	url, ok := m["type_url"].(string)
+	if !ok {
+		url, ok = m["typeUrl"].(string)
+	}
+	if !ok {
+		return nil, false
+	}
+	if !strings.Contains(url, "/") {
+		url = anyPrefix + url
+	}
+	if _, err := protoregistry.GlobalTypes.FindMessageByURL(url); err != nil {
+		return nil, false
+	}
+
+	value, _ := m["value"].(string)
+	b, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, false
+	}
+
+	js, err := protojson.Marshal(&anypb.Any{TypeUrl: url, Value: b})
+	if err != nil {
+		return nil, false
+	}
+	var v interface{}
+	if err := json.Unmarshal(js, &v); err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+func toString(v interface{}) string {
+	switch t := v.(type) {
+	case json.Number:
+		return t.String()
+	case string:
+		return t
+	}
+	return ""
+}
+
+// lowerCamel converts a snake case field name to lower camel case, e.g. created_at to createdAt
+func lowerCamel(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) > 0 {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestTranscode(t *testing.T) {
+	req := &registry.Value{
+		Name: "CreateRequest",
+		Type: "CreateRequest",
+		Values: []*registry.Value{
+			{Name: "name", Type: "string"},
+			{Name: "created_at", Type: "google.protobuf.Timestamp"},
+			{Name: "timeout", Type: "google.protobuf.Duration"},
+			{Name: "nickname", Type: "google.protobuf.StringValue"},
+			{Name: "count", Type: "google.protobuf.Int32Value"},
+			{Name: "metadata", Type: "google.protobuf.Struct"},
+			{Name: "mask", Type: "google.protobuf.FieldMask"},
+			{Name: "details", Type: "[]google.protobuf.Any"},
+			{Name: "user", Type: "User", Values: []*registry.Value{
+				{Name: "updated", Type: "google.protobuf.Timestamp"},
+			}},
+		},
+	}
+
+	value, err := proto.Marshal(wrapperspb.String("foo"))
+	assert.Nil(t, err)
+
+	payload := map[string]interface{}{
+		"name":      "john",
+		"createdAt": 1600000000,
+		"timeout":   "1m30s",
+		"nickname":  map[string]interface{}{"value": 10},
+		"count":     map[string]interface{}{"value": 5},
+		"metadata":  `{"foo":"bar"}`,
+		"mask":      []string{"name", "created_at"},
+		"details": []interface{}{
+			map[string]interface{}{"@type": "google.protobuf.BoolValue", "value": true},
+			map[string]interface{}{
+				"type_url": "type.googleapis.com/google.protobuf.StringValue",
+				"value":    base64.StdEncoding.EncodeToString(value),
+			},
+		},
+		"user": map[string]interface{}{
+			"updated": map[string]interface{}{"seconds": 1600000000, "nanos": 500},
+		},
+	}
+	b, err := json.Marshal(payload)
+	assert.Nil(t, err)
+
+	b, err = Transcode(b, req)
+	assert.Nil(t, err)
+
+	var rsp map[string]interface{}
+	assert.Nil(t, json.Unmarshal(b, &rsp))
+	assert.Equal(t, "john", rsp["name"])
+	assert.Equal(t, "2020-09-13T12:26:40Z", rsp["createdAt"])
+	assert.Equal(t, "90s", rsp["timeout"])
+	assert.Equal(t, "10", rsp["nickname"])
+	assert.Equal(t, float64(5), rsp["count"])
+	assert.Equal(t, map[string]interface{}{"foo": "bar"}, rsp["metadata"])
+	assert.Equal(t, "name,created_at", rsp["mask"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"@type": "type.googleapis.com/google.protobuf.BoolValue", "value": true},
+		map[string]interface{}{"@type": "type.googleapis.com/google.protobuf.StringValue", "value": "foo"},
+	}, rsp["details"])
+	assert.Equal(t, map[string]interface{}{"updated": "2020-09-13T12:26:40.0000005Z"}, rsp["user"])
+}
+
+func TestTranscodeUnchanged(t *testing.T) {
+	req := &registry.Value{
+		Name:   "Request",
+		Type:   "Request",
+		Values: []*registry.Value{{Name: "created", Type: "google.protobuf.Timestamp"}},
+	}
+
+	for _, payload := range []string{
+		`{"created":"2020-09-13T12:26:40Z"}`,
+		`{"name":"john"}`,
+		`[1,2,3]`,
+	} {
+		b, err := Transcode([]byte(payload), req)
+		assert.Nil(t, err)
+		assert.Equal(t, payload, string(b))
+	}
+
+	_, err := Transcode([]byte(`{"created":`), req)
+	assert.NotNil(t, err)
+}
+
+func TestTranscodeResponse(t *testing.T) {
+	rsp := &registry.Value{
+		Name: "ReadResponse",
+		Type: "ReadResponse",
+		Values: []*registry.Value{
+			{Name: "name", Type: "string"},
+			{Name: "created_at", Type: "google.protobuf.Timestamp"},
+			{Name: "nickname", Type: "google.protobuf.StringValue"},
+		},
+	}
+
+	// services encoding without jsonpb return the fields of the well known types
+	b, err := Transcode([]byte(`{"name":"<b>john</b>","created_at":{"seconds":1600000000},"nickname":{"value":"jj"}}`), rsp)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"created_at":"2020-09-13T12:26:40Z","name":"<b>john</b>","nickname":"jj"}`, string(b))
+}
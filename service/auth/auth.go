@@ -135,6 +135,12 @@ type Auth interface {
 	String() string
 }
 
+// NamespaceInspector is implemented by auths which verify tokens with the keys of the namespace the
+// call was made to
+type NamespaceInspector interface {
+	InspectNamespace(token, ns string) (*Account, error)
+}
+
 // Generate a new account
 func Generate(id string, opts ...GenerateOption) (*Account, error) {
 	return DefaultAuth.Generate(id, opts...)
@@ -150,6 +156,15 @@ func Inspect(token string) (*Account, error) {
 	return DefaultAuth.Inspect(token)
 }
 
+// InspectNamespace inspects a token the call to a namespace was made with, auths which don't verify
+// tokens with the keys of namespaces inspect it as any other token
+func InspectNamespace(token, ns string) (*Account, error) {
+	if i, ok := DefaultAuth.(NamespaceInspector); ok {
+		return i.InspectNamespace(token, ns)
+	}
+	return DefaultAuth.Inspect(token)
+}
+
 // Token generated using refresh token or credentials
 func Token(opts ...TokenOption) (*AccountToken, error) {
 	return DefaultAuth.Token(opts...)
@@ -1,6 +1,8 @@
 package client
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"strings"
 	"sync"
 	"time"
@@ -11,6 +13,7 @@ import (
 	"github.com/micro/micro/v3/service/context"
 	"github.com/micro/micro/v3/service/errors"
 	"github.com/micro/micro/v3/service/logger"
+	inauth "github.com/micro/micro/v3/util/auth"
 	"github.com/micro/micro/v3/util/auth/rules"
 	"github.com/micro/micro/v3/util/auth/token"
 	"github.com/micro/micro/v3/util/auth/token/jwt"
@@ -18,6 +21,9 @@ import (
 
 const (
 	ruleCacheTTL = 2 * time.Minute
+	// apiKeyCacheTTL is how long verified api keys are cached for, so a revoked key can be used
+	// for up to this long after it's revoked
+	apiKeyCacheTTL = 30 * time.Second
 )
 
 type rulesCache struct {
@@ -47,13 +53,55 @@ type cacheEntry struct {
 	v []*auth.Rule
 }
 
+// apiKeyCache caches the accounts of verified api keys, keyed by the hash of the key
+type apiKeyCache struct {
+	sync.RWMutex
+	accounts map[string]*apiKeyEntry
+	ttl      time.Duration
+}
+
+type apiKeyEntry struct {
+	t   time.Time
+	acc *auth.Account
+}
+
+func (c *apiKeyCache) get(key string) *auth.Account {
+	sum := sha256.Sum256([]byte(key))
+	c.RLock()
+	entry := c.accounts[hex.EncodeToString(sum[:])]
+	c.RUnlock()
+	if entry != nil && time.Since(entry.t) < c.ttl {
+		return entry.acc
+	}
+	return nil
+}
+
+func (c *apiKeyCache) put(key string, acc *auth.Account) {
+	sum := sha256.Sum256([]byte(key))
+	c.Lock()
+	defer c.Unlock()
+	if c.accounts == nil {
+		c.accounts = map[string]*apiKeyEntry{}
+	}
+	for k, e := range c.accounts {
+		if time.Since(e.t) >= c.ttl {
+			delete(c.accounts, k)
+		}
+	}
+	c.accounts[hex.EncodeToString(sum[:])] = &apiKeyEntry{t: time.Now(), acc: acc}
+}
+
 // srv is the service implementation of the Auth interface
 type srv struct {
 	options   auth.Options
 	auth      pb.AuthService
 	rules     pb.RulesService
+	apiKeys   pb.APIKeysService
 	token     token.Provider
 	ruleCache rulesCache
+	keyCache  apiKeyCache
+	// signingKeys are the public keys of namespaces
+	signingKeys *signingKeys
 }
 
 func (s *srv) String() string {
@@ -66,11 +114,16 @@ func (s *srv) Init(opts ...auth.Option) {
 	}
 	s.auth = pb.NewAuthService("auth", client.DefaultClient)
 	s.rules = pb.NewRulesService("auth", client.DefaultClient)
+	s.apiKeys = pb.NewAPIKeysService("auth", client.DefaultClient)
 	s.setupJWT()
 	s.ruleCache = rulesCache{
 		ruleCache: map[string]*cacheEntry{},
 		ttl:       ruleCacheTTL,
 	}
+	s.keyCache = apiKeyCache{
+		accounts: map[string]*apiKeyEntry{},
+		ttl:      apiKeyCacheTTL,
+	}
 }
 
 func (s *srv) Options() auth.Options {
@@ -222,11 +275,22 @@ func (s *srv) Verify(acc *auth.Account, res *auth.Resource, opts ...auth.VerifyO
 
 // Inspect a token
 func (s *srv) Inspect(token string) (*auth.Account, error) {
+	return s.InspectNamespace(token, "")
+}
+
+// InspectNamespace inspects a token the call to a namespace was made with. Tokens signed with the
+// key of a namespace are verified with the keys of the namespace the call was made to.
+func (s *srv) InspectNamespace(token, ns string) (*auth.Account, error) {
 	// validate the request
 	if len(token) == 0 {
 		return nil, auth.ErrInvalidToken
 	}
 
+	// api keys are opaque and always verified by the auth service
+	if inauth.IsAPIKey(token) {
+		return s.inspectAPIKey(token)
+	}
+
 	// optimisation - is the key the right format for jwt auth?
 	if s.token.String() == "jwt" && strings.Count(token, ".") != 2 {
 		return nil, auth.ErrInvalidToken
@@ -234,13 +298,16 @@ func (s *srv) Inspect(token string) (*auth.Account, error) {
 
 	// try to decode JWT locally and fall back to srv if an error occurs
 	if len(strings.Split(token, ".")) == 3 && len(s.options.PublicKey) > 0 {
+		if i, ok := s.token.(auth.NamespaceInspector); ok {
+			return i.InspectNamespace(token, ns)
+		}
 		return s.token.Inspect(token)
 	}
 
 	// the token is not a JWT or we do not have the keys to decode it,
 	// fall back to the auth service
 	rsp, err := s.auth.Inspect(context.DefaultContext, &pb.InspectRequest{
-		Token: token, Options: &pb.Options{Namespace: s.Options().Issuer},
+		Token: token, Options: &pb.Options{Namespace: s.Options().Issuer}, CallNamespace: ns,
 	}, s.callOpts()...)
 	if err != nil {
 		return nil, err
@@ -248,6 +315,28 @@ func (s *srv) Inspect(token string) (*auth.Account, error) {
 	return serializeAccount(rsp.Account), nil
 }
 
+// inspectAPIKey verifies an api key with the auth service, caching the account briefly since keys
+// are sent with every request
+func (s *srv) inspectAPIKey(key string) (*auth.Account, error) {
+	if acc := s.keyCache.get(key); acc != nil {
+		return acc, nil
+	}
+
+	rsp, err := s.apiKeys.Verify(context.DefaultContext, &pb.VerifyAPIKeyRequest{
+		Key: key, Options: &pb.Options{Namespace: s.Options().Issuer},
+	}, s.callOpts()...)
+	if err != nil {
+		if errors.FromError(err).Code == 401 {
+			return nil, auth.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	acc := serializeAccount(rsp.Account)
+	s.keyCache.put(key, acc)
+	return acc, nil
+}
+
 // Token generation using an account ID and secret
 func (s *srv) Token(opts ...auth.TokenOption) (*auth.AccountToken, error) {
 	options := auth.NewTokenOptions(opts...)
@@ -349,9 +438,11 @@ func (s *srv) callOpts() []client.CallOption {
 // NewAuth returns a new instance of the Auth service
 func NewAuth(opts ...auth.Option) auth.Auth {
 	service := &srv{
-		auth:    pb.NewAuthService("auth", client.DefaultClient),
-		rules:   pb.NewRulesService("auth", client.DefaultClient),
-		options: auth.NewOptions(opts...),
+		auth:     pb.NewAuthService("auth", client.DefaultClient),
+		rules:    pb.NewRulesService("auth", client.DefaultClient),
+		apiKeys:  pb.NewAPIKeysService("auth", client.DefaultClient),
+		options:  auth.NewOptions(opts...),
+		keyCache: apiKeyCache{ttl: apiKeyCacheTTL},
 	}
 
 	service.setupJWT()
@@ -377,5 +468,19 @@ func (s *srv) setupJWT() {
 		tokenOpts = append(tokenOpts, token.WithPrivateKey(key))
 	}
 
+	// the signer is used in place of the private key when the key is held in a KMS
+	if s.options.Signer != nil {
+		tokenOpts = append(tokenOpts, token.WithSigner(s.options.Signer))
+	}
+
+	// tokens signed with the keys of namespaces are verified with the public keys of the namespace
+	s.signingKeys = &signingKeys{
+		keys:     map[string]*signingKeysEntry{},
+		svc:      pb.NewSigningKeysService("auth", client.DefaultClient),
+		opts:     s.callOpts,
+		fetching: map[string]chan struct{}{},
+	}
+	tokenOpts = append(tokenOpts, token.WithKeys(s.signingKeys))
+
 	s.token = jwt.NewTokenProvider(tokenOpts...)
 }
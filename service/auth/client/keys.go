@@ -0,0 +1,148 @@
+package client
+
+import (
+	"sync"
+	"time"
+
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+	"github.com/micro/micro/v3/util/auth/token"
+)
+
+const (
+	// signingKeyCacheTTL is how long the keys of a namespace are cached for, so a revoked key can
+	// verify tokens for up to this long after it's revoked
+	signingKeyCacheTTL = time.Minute
+	// signingKeyRefresh is the min interval between fetching the keys of a namespace, so tokens which
+	// reference unknown keys or namespaces can't be used to flood the auth service. Errors fetching
+	// the keys are cached for this long too.
+	signingKeyRefresh = time.Second * 10
+	// signingKeyCacheSize is the max number of namespaces the keys of are cached. The namespace comes
+	// from the token being verified, so the oldest are evicted rather than the cache growing forever.
+	signingKeyCacheSize = 1024
+)
+
+// signingKeys is the source of the public keys of namespaces, which are fetched from the auth
+// service. Only the auth service has the private keys so services sign with the default key.
+type signingKeys struct {
+	sync.Mutex
+	keys map[string]*signingKeysEntry
+	svc  pb.SigningKeysService
+	opts func() []client.CallOption
+
+	// fetching has a channel for each namespace being fetched, which is closed once it's fetched,
+	// so concurrent lookups of a namespace make one call to the auth service
+	fetching map[string]chan struct{}
+}
+
+type signingKeysEntry struct {
+	t    time.Time
+	keys map[string]*pb.SigningKey
+	err  error
+}
+
+// stale returns true if the keys should be fetched again to find the key with the id
+func (e *signingKeysEntry) stale(id string) bool {
+	age := time.Since(e.t)
+	if age < signingKeyRefresh {
+		return false
+	}
+	return age >= signingKeyCacheTTL || e.err != nil || (len(id) > 0 && e.keys[id] == nil)
+}
+
+func (s *signingKeys) SigningKey(ns string) (*token.Key, error) {
+	return nil, nil
+}
+
+func (s *signingKeys) VerificationKey(ns, id string) (*token.Key, error) {
+	entry, err := s.entry(ns, id)
+	if err != nil {
+		return nil, err
+	}
+
+	k, ok := entry.keys[id]
+	if !ok || expired(k) {
+		return nil, token.ErrNotFound
+	}
+	return &token.Key{ID: k.Id, Namespace: ns, PublicKey: k.PublicKey}, nil
+}
+
+func (s *signingKeys) HasKeys(ns string) (bool, error) {
+	entry, err := s.entry(ns, "")
+	if err != nil {
+		return false, err
+	}
+	for _, k := range entry.keys {
+		if !expired(k) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// entry returns the cached keys of the namespace, fetching them if they're stale
+func (s *signingKeys) entry(ns, id string) (*signingKeysEntry, error) {
+	for {
+		s.Lock()
+		if entry := s.keys[ns]; entry != nil && !entry.stale(id) {
+			s.Unlock()
+			return entry, entry.err
+		}
+		if wait, ok := s.fetching[ns]; ok {
+			s.Unlock()
+			<-wait
+			continue
+		}
+		done := make(chan struct{})
+		s.fetching[ns] = done
+		s.Unlock()
+
+		entry := s.fetch(ns)
+
+		s.Lock()
+		delete(s.fetching, ns)
+		close(done)
+		s.Unlock()
+		return entry, entry.err
+	}
+}
+
+func (s *signingKeys) fetch(ns string) *signingKeysEntry {
+	entry := &signingKeysEntry{t: time.Now()}
+	rsp, err := s.svc.List(context.DefaultContext, &pb.ListSigningKeysRequest{
+		Options: &pb.Options{Namespace: ns},
+	}, s.opts()...)
+	if err != nil {
+		entry.err = err
+	} else {
+		entry.keys = make(map[string]*pb.SigningKey, len(rsp.Keys))
+		for _, k := range rsp.Keys {
+			entry.keys[k.Id] = k
+		}
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.keys[ns]; !ok && len(s.keys) >= signingKeyCacheSize {
+		s.evict()
+	}
+	s.keys[ns] = entry
+	return entry
+}
+
+// evict the namespace fetched longest ago, the lock must be held
+func (s *signingKeys) evict() {
+	var oldest string
+	var t time.Time
+	for ns, e := range s.keys {
+		if t.IsZero() || e.t.Before(t) {
+			oldest, t = ns, e.t
+		}
+	}
+	delete(s.keys, oldest)
+}
+
+func expired(k *pb.SigningKey) bool {
+	return k.Expiry > 0 && time.Now().Unix() > k.Expiry
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/util/auth/token"
+	"github.com/stretchr/testify/assert"
+)
+
+// testKeysService returns a key for the foo namespace and an error for the others
+type testKeysService struct {
+	pb.SigningKeysService
+	calls int32
+	delay time.Duration
+}
+
+func (t *testKeysService) List(ctx context.Context, req *pb.ListSigningKeysRequest, opts ...client.CallOption) (*pb.ListSigningKeysResponse, error) {
+	atomic.AddInt32(&t.calls, 1)
+	time.Sleep(t.delay)
+	if req.Options.Namespace != "foo" {
+		return nil, errors.New("namespace not found")
+	}
+	return &pb.ListSigningKeysResponse{Keys: []*pb.SigningKey{{Id: "1", PublicKey: "key"}}}, nil
+}
+
+func newTestSigningKeys(svc pb.SigningKeysService) *signingKeys {
+	return &signingKeys{
+		keys:     map[string]*signingKeysEntry{},
+		svc:      svc,
+		opts:     func() []client.CallOption { return nil },
+		fetching: map[string]chan struct{}{},
+	}
+}
+
+func TestVerificationKey(t *testing.T) {
+	svc := &testKeysService{}
+	s := newTestSigningKeys(svc)
+
+	k, err := s.VerificationKey("foo", "1")
+	assert.Nil(t, err)
+	assert.Equal(t, "key", k.PublicKey)
+
+	// unknown keys don't refetch the keys until the refresh interval has passed
+	_, err = s.VerificationKey("foo", "2")
+	assert.Equal(t, token.ErrNotFound, err)
+	has, err := s.HasKeys("foo")
+	assert.Nil(t, err)
+	assert.True(t, has)
+	assert.Equal(t, int32(1), svc.calls)
+
+	// errors are cached too
+	_, err = s.VerificationKey("bar", "1")
+	assert.NotNil(t, err)
+	_, err = s.HasKeys("bar")
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(2), svc.calls)
+}
+
+func TestVerificationKeyConcurrent(t *testing.T) {
+	svc := &testKeysService{delay: time.Millisecond * 10}
+	s := newTestSigningKeys(svc)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.VerificationKey("foo", "1")
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), svc.calls)
+}
+
+func TestSigningKeysCacheSize(t *testing.T) {
+	s := newTestSigningKeys(&testKeysService{})
+
+	for i := 0; i < signingKeyCacheSize+10; i++ {
+		s.HasKeys(fmt.Sprintf("ns-%d", i))
+	}
+	assert.Equal(t, signingKeyCacheSize, len(s.keys))
+}
@@ -0,0 +1,26 @@
+package auth
+
+import "time"
+
+const (
+	// EventTopic the auth events are published to
+	EventTopic = "auth"
+
+	// EventLoginThrottled is published when login attempts for an account start being throttled
+	EventLoginThrottled = "login.throttled"
+	// EventAccountLocked is published when an account is locked after too many failed logins
+	EventAccountLocked = "account.locked"
+	// EventAccountUnlocked is published when an admin unlocks an account
+	EventAccountUnlocked = "account.unlocked"
+)
+
+// EventLoginPayload which is published with login events
+type EventLoginPayload struct {
+	Type      string
+	Namespace string
+	Account   string
+	// Source is the ip the failed logins came from
+	Source      string
+	Failures    int
+	LockedUntil time.Time
+}
@@ -170,7 +170,12 @@ func (a *Auth) ChangeSecret(ctx context.Context, req *pb.ChangeSecretRequest, rs
 	// or the caller is a service, do not require the knowledge of the previous secret.
 	// This will enable both micro admins and services to change secrets on behalf of their users.
 	if !((callerAcc.Issuer == namespace.DefaultNamespace && isAdmin(callerAcc.Scopes)) || (callerAcc.Type == "service" && callerAcc.Issuer == req.Options.Namespace)) {
+		source := loginSource(ctx)
+		if _, err := a.checkLogin(req.Options.Namespace, acc.ID, source, "auth.Accounts.ChangeSecret"); err != nil {
+			return err
+		}
 		if !secretsMatch(acc.Secret, req.OldSecret) {
+			a.loginFailed(req.Options.Namespace, acc.ID, source)
 			return errors.BadRequest("auth.Accounts.ChangeSecret", "Secret not correct")
 		}
 	}
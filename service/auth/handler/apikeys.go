@@ -0,0 +1,322 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store"
+	inauth "github.com/micro/micro/v3/util/auth"
+	"github.com/micro/micro/v3/util/auth/namespace"
+)
+
+const (
+	storePrefixAPIKeys = "apikey"
+	// storePrefixAPIKeyNamespaces indexes the namespace of each key by its id, so keys can be
+	// verified by services in other namespaces, e.g. the api gateway
+	storePrefixAPIKeyNamespaces = "apikeyNamespace"
+
+	// metadataAPIKey is set on the accounts returned by Verify to the id of the key used
+	metadataAPIKey = "apiKey"
+)
+
+// APIKeys issues long lived keys to accounts for machine clients which can't refresh tokens. A key
+// is formatted as mk_<id>_<secret>, only the hash of the secret is stored so the key is returned
+// once when it's created. Keys grant a subset of the scopes of the account and are checked against
+// the account each time they're verified, so disabling the account or removing its scopes also
+// applies to its keys.
+type APIKeys struct {
+	Auth *Auth
+}
+
+type apiKey struct {
+	ID        string   `json:"id"`
+	AccountID string   `json:"accountId"`
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	Hash      string   `json:"hash"`
+	Created   int64    `json:"created"`
+	Expiry    int64    `json:"expiry"`
+}
+
+// Create a key for an account. Accounts can create keys for themselves and admins for any account.
+// Keys can't be created by callers authenticated with a key, so a leaked key can't be used to
+// create others which outlive its revocation.
+func (k *APIKeys) Create(ctx context.Context, req *pb.CreateAPIKeyRequest, rsp *pb.CreateAPIKeyResponse) error {
+	if caller, ok := auth.AccountFromContext(ctx); ok && len(caller.Metadata[metadataAPIKey]) > 0 {
+		return errors.Forbidden("auth.APIKeys.Create", "Keys can't be created with a key")
+	}
+	ns, acc, err := k.authorize(ctx, &req.Options, req.AccountId, "auth.APIKeys.Create")
+	if err != nil {
+		return err
+	}
+	if req.Expiry > 0 && req.Expiry <= time.Now().Unix() {
+		return errors.BadRequest("auth.APIKeys.Create", "Expiry must be in the future")
+	}
+	scopes, ok := grantScopes(acc.Scopes, req.Scopes)
+	if !ok {
+		return errors.Forbidden("auth.APIKeys.Create", "The key can't have scopes the account doesn't have")
+	}
+
+	secret, err := randomToken()
+	if err != nil {
+		return errors.InternalServerError("auth.APIKeys.Create", "Unable to generate key: %v", err)
+	}
+	key := &apiKey{
+		ID:        uuid.New().String(),
+		AccountID: acc.ID,
+		Name:      req.Name,
+		Scopes:    scopes,
+		Hash:      hashKey(secret),
+		Created:   time.Now().Unix(),
+		Expiry:    req.Expiry,
+	}
+	if err := k.writeKey(ns, key); err != nil {
+		return errors.InternalServerError("auth.APIKeys.Create", "Unable to write key: %v", err)
+	}
+
+	rsp.ApiKey = serializeAPIKey(key)
+	rsp.Key = inauth.APIKeyPrefix + key.ID + "_" + secret
+	return nil
+}
+
+// List the keys of an account
+func (k *APIKeys) List(ctx context.Context, req *pb.ListAPIKeysRequest, rsp *pb.ListAPIKeysResponse) error {
+	ns, acc, err := k.authorize(ctx, &req.Options, req.AccountId, "auth.APIKeys.List")
+	if err != nil {
+		return err
+	}
+
+	recs, err := store.Read(strings.Join([]string{storePrefixAPIKeys, ns, ""}, joinKey), store.ReadPrefix())
+	if err != nil {
+		return errors.InternalServerError("auth.APIKeys.List", "Unable to read keys: %v", err)
+	}
+	rsp.ApiKeys = make([]*pb.APIKey, 0, len(recs))
+	for _, rec := range recs {
+		var key *apiKey
+		if err := json.Unmarshal(rec.Value, &key); err != nil {
+			return errors.InternalServerError("auth.APIKeys.List", "Unable to unmarshal key: %v", err)
+		}
+		if key.AccountID == acc.ID {
+			rsp.ApiKeys = append(rsp.ApiKeys, serializeAPIKey(key))
+		}
+	}
+	sort.Slice(rsp.ApiKeys, func(i, j int) bool {
+		return rsp.ApiKeys[i].Created > rsp.ApiKeys[j].Created
+	})
+	return nil
+}
+
+// Revoke a key, it can no longer be used once it's revoked
+func (k *APIKeys) Revoke(ctx context.Context, req *pb.RevokeAPIKeyRequest, rsp *pb.RevokeAPIKeyResponse) error {
+	if len(req.Id) == 0 {
+		return errors.BadRequest("auth.APIKeys.Revoke", "Missing id")
+	}
+	if req.Options == nil {
+		req.Options = &pb.Options{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	key, err := k.readKey(req.Options.Namespace, req.Id)
+	if err == store.ErrNotFound {
+		return errors.NotFound("auth.APIKeys.Revoke", "Key not found")
+	} else if err != nil {
+		return errors.InternalServerError("auth.APIKeys.Revoke", "Unable to read key: %v", err)
+	}
+	ns, _, err := k.authorize(ctx, &req.Options, key.AccountID, "auth.APIKeys.Revoke")
+	if err != nil {
+		return err
+	}
+
+	if err := store.Delete(strings.Join([]string{storePrefixAPIKeys, ns, key.ID}, joinKey)); err != nil && err != store.ErrNotFound {
+		return errors.InternalServerError("auth.APIKeys.Revoke", "Unable to delete key: %v", err)
+	}
+	if err := store.Delete(strings.Join([]string{storePrefixAPIKeyNamespaces, key.ID}, joinKey)); err != nil && err != store.ErrNotFound {
+		return errors.InternalServerError("auth.APIKeys.Revoke", "Unable to delete key: %v", err)
+	}
+	return nil
+}
+
+// Verify a key, returning the account it was issued to with the scopes of the key. It's called by
+// services authenticating requests so like token inspection it requires a service account. The key
+// is verified in the namespace it was created in, which the caller must be able to administer.
+func (k *APIKeys) Verify(ctx context.Context, req *pb.VerifyAPIKeyRequest, rsp *pb.VerifyAPIKeyResponse) error {
+	if req.Options == nil {
+		req.Options = &pb.Options{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+	if err := namespace.AuthorizeAdmin(ctx, req.Options.Namespace, "auth.APIKeys.Verify"); err != nil {
+		return err
+	}
+
+	id, secret, ok := parseAPIKey(req.Key)
+	if !ok {
+		return errors.Unauthorized("auth.APIKeys.Verify", auth.ErrInvalidToken.Error())
+	}
+	ns, err := k.keyNamespace(id, req.Options.Namespace)
+	if err != nil {
+		return errors.InternalServerError("auth.APIKeys.Verify", "Unable to read key: %v", err)
+	}
+	if ns != req.Options.Namespace && namespace.AuthorizeAdmin(ctx, ns, "auth.APIKeys.Verify") != nil {
+		return errors.Unauthorized("auth.APIKeys.Verify", auth.ErrInvalidToken.Error())
+	}
+	key, err := k.readKey(ns, id)
+	if err == store.ErrNotFound {
+		return errors.Unauthorized("auth.APIKeys.Verify", auth.ErrInvalidToken.Error())
+	} else if err != nil {
+		return errors.InternalServerError("auth.APIKeys.Verify", "Unable to read key: %v", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(hashKey(secret))) != 1 {
+		return errors.Unauthorized("auth.APIKeys.Verify", auth.ErrInvalidToken.Error())
+	}
+	if key.Expiry > 0 && time.Now().Unix() > key.Expiry {
+		return errors.Unauthorized("auth.APIKeys.Verify", "Key expired")
+	}
+
+	acc, err := k.Auth.getAccountForID(key.AccountID, ns, "auth.APIKeys.Verify")
+	if err != nil {
+		if merr, ok := err.(*errors.Error); ok && merr.Code < 500 {
+			return errors.Unauthorized("auth.APIKeys.Verify", "Account not found")
+		}
+		return err
+	}
+	if acc.Metadata[metadataActive] == "false" {
+		return errors.Unauthorized("auth.APIKeys.Verify", "Account disabled")
+	}
+
+	// the account may have lost scopes since the key was created
+	var scopes []string
+	for _, s := range key.Scopes {
+		if hasScope(s, acc.Scopes) {
+			scopes = append(scopes, s)
+		}
+	}
+	md := make(map[string]string, len(acc.Metadata)+1)
+	for mk, v := range acc.Metadata {
+		md[mk] = v
+	}
+	md[metadataAPIKey] = key.ID
+	acc.Scopes = scopes
+	acc.Metadata = md
+
+	rsp.Account = serializeAccount(acc)
+	return nil
+}
+
+// authorize checks the caller can manage the keys of the account, returning the namespace and the
+// account. Accounts can manage their own keys, only admins can manage the keys of other accounts.
+func (k *APIKeys) authorize(ctx context.Context, opts **pb.Options, accountID, method string) (string, *auth.Account, error) {
+	if *opts == nil {
+		*opts = &pb.Options{}
+	}
+	if len((*opts).Namespace) == 0 {
+		(*opts).Namespace = namespace.DefaultNamespace
+	}
+	ns := (*opts).Namespace
+	if err := namespace.Authorize(ctx, ns, method); err != nil {
+		return "", nil, err
+	}
+	caller, ok := auth.AccountFromContext(ctx)
+	if !ok {
+		return "", nil, errors.Unauthorized(method, "Unauthorized")
+	}
+
+	// the scopes of the caller limit the keys it can create, so a key can't be used to create a
+	// key with more scopes than it has
+	if len(accountID) == 0 || accountID == caller.ID || accountID == caller.Name {
+		acc, err := k.Auth.getAccountForID(caller.ID, ns, method)
+		if err != nil {
+			return "", nil, err
+		}
+		acc.Scopes = caller.Scopes
+		return ns, acc, nil
+	}
+
+	if err := namespace.AuthorizeAdmin(ctx, ns, method); err != nil {
+		return "", nil, err
+	}
+	acc, err := k.Auth.getAccountForID(accountID, ns, method)
+	if err != nil {
+		return "", nil, err
+	}
+	return ns, acc, nil
+}
+
+// keyNamespace returns the namespace the key was created in, or the default if it isn't indexed
+func (k *APIKeys) keyNamespace(id, def string) (string, error) {
+	recs, err := store.Read(strings.Join([]string{storePrefixAPIKeyNamespaces, id}, joinKey))
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return def, nil
+	} else if err != nil {
+		return "", err
+	}
+	return string(recs[0].Value), nil
+}
+
+func (k *APIKeys) readKey(ns, id string) (*apiKey, error) {
+	recs, err := store.Read(strings.Join([]string{storePrefixAPIKeys, ns, id}, joinKey))
+	if err != nil {
+		return nil, err
+	}
+	var key *apiKey
+	if err := json.Unmarshal(recs[0].Value, &key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (k *APIKeys) writeKey(ns string, key *apiKey) error {
+	bytes, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	rec := &store.Record{
+		Key:   strings.Join([]string{storePrefixAPIKeys, ns, key.ID}, joinKey),
+		Value: bytes,
+	}
+	if key.Expiry > 0 {
+		rec.Expiry = time.Until(time.Unix(key.Expiry, 0))
+	}
+	if err := store.Write(rec); err != nil {
+		return err
+	}
+	return store.Write(&store.Record{
+		Key:    strings.Join([]string{storePrefixAPIKeyNamespaces, key.ID}, joinKey),
+		Value:  []byte(ns),
+		Expiry: rec.Expiry,
+	})
+}
+
+// parseAPIKey splits a key into its id and secret
+func parseAPIKey(key string) (string, string, bool) {
+	if !inauth.IsAPIKey(key) {
+		return "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(key, inauth.APIKeyPrefix), "_", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+func serializeAPIKey(k *apiKey) *pb.APIKey {
+	return &pb.APIKey{
+		Id:        k.ID,
+		AccountId: k.AccountID,
+		Name:      k.Name,
+		Scopes:    k.Scopes,
+		Created:   k.Created,
+		Expiry:    k.Expiry,
+	}
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store"
+	memstore "github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeys(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	a := &Accounts{Auth: &Auth{}}
+	assert.Nil(t, a.createAccount(&auth.Account{ID: "1", Name: "john", Type: "user", Issuer: "micro", Secret: "secret", Scopes: []string{"billing", "reports"}}))
+	assert.Nil(t, a.createAccount(&auth.Account{ID: "2", Name: "jane", Type: "user", Issuer: "micro", Secret: "secret", Scopes: []string{"billing"}}))
+	k := &APIKeys{Auth: a.Auth}
+
+	john := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "1", Name: "john", Type: "user", Issuer: "micro", Scopes: []string{"billing", "reports"}})
+	jane := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "2", Name: "jane", Type: "user", Issuer: "micro", Scopes: []string{"billing"}})
+	svc := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "api", Type: "service", Issuer: "micro", Scopes: []string{"service"}})
+
+	// keys can only have the scopes of the account
+	err := k.Create(john, &pb.CreateAPIKeyRequest{Name: "export", Scopes: []string{"admin"}}, &pb.CreateAPIKeyResponse{})
+	assert.Equal(t, int32(http.StatusForbidden), errors.FromError(err).Code)
+
+	var rsp pb.CreateAPIKeyResponse
+	assert.Nil(t, k.Create(john, &pb.CreateAPIKeyRequest{Name: "export", Scopes: []string{"billing"}}, &rsp))
+	assert.Equal(t, "1", rsp.ApiKey.AccountId)
+
+	// the key authenticates as the account with the scopes of the key
+	var vrsp pb.VerifyAPIKeyResponse
+	assert.Nil(t, k.Verify(svc, &pb.VerifyAPIKeyRequest{Key: rsp.Key}, &vrsp))
+	assert.Equal(t, "1", vrsp.Account.Id)
+	assert.Equal(t, []string{"billing"}, vrsp.Account.Scopes)
+	assert.Equal(t, rsp.ApiKey.Id, vrsp.Account.Metadata[metadataAPIKey])
+
+	// keys can't be used to create other keys
+	key := auth.ContextWithAccount(context.TODO(), &auth.Account{
+		ID: "1", Name: "john", Type: "user", Issuer: "micro", Scopes: vrsp.Account.Scopes, Metadata: vrsp.Account.Metadata,
+	})
+	err = k.Create(key, &pb.CreateAPIKeyRequest{Name: "copy", Scopes: []string{"billing"}}, &pb.CreateAPIKeyResponse{})
+	assert.Equal(t, int32(http.StatusForbidden), errors.FromError(err).Code)
+
+	err = k.Verify(svc, &pb.VerifyAPIKeyRequest{Key: rsp.Key + "x"}, &pb.VerifyAPIKeyResponse{})
+	assert.Equal(t, int32(http.StatusUnauthorized), errors.FromError(err).Code)
+	err = k.Verify(john, &pb.VerifyAPIKeyRequest{Key: rsp.Key}, &pb.VerifyAPIKeyResponse{})
+	assert.Equal(t, int32(http.StatusUnauthorized), errors.FromError(err).Code)
+
+	// other accounts can't see or revoke the key
+	var lrsp pb.ListAPIKeysResponse
+	assert.Nil(t, k.List(john, &pb.ListAPIKeysRequest{}, &lrsp))
+	assert.Len(t, lrsp.ApiKeys, 1)
+	assert.NotNil(t, k.List(jane, &pb.ListAPIKeysRequest{AccountId: "john"}, &pb.ListAPIKeysResponse{}))
+	assert.NotNil(t, k.Revoke(jane, &pb.RevokeAPIKeyRequest{Id: rsp.ApiKey.Id}, &pb.RevokeAPIKeyResponse{}))
+
+	// revoked keys can't be used
+	assert.Nil(t, k.Revoke(john, &pb.RevokeAPIKeyRequest{Id: rsp.ApiKey.Id}, &pb.RevokeAPIKeyResponse{}))
+	err = k.Verify(svc, &pb.VerifyAPIKeyRequest{Key: rsp.Key}, &pb.VerifyAPIKeyResponse{})
+	assert.Equal(t, int32(http.StatusUnauthorized), errors.FromError(err).Code)
+}
+
+func TestAPIKeyExpiry(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	a := &Accounts{Auth: &Auth{}}
+	assert.Nil(t, a.createAccount(&auth.Account{ID: "1", Name: "john", Type: "user", Issuer: "micro", Secret: "secret"}))
+	k := &APIKeys{Auth: a.Auth}
+	john := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "1", Name: "john", Type: "user", Issuer: "micro"})
+	svc := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "api", Type: "service", Issuer: "micro", Scopes: []string{"service"}})
+
+	err := k.Create(john, &pb.CreateAPIKeyRequest{Expiry: time.Now().Add(-time.Minute).Unix()}, &pb.CreateAPIKeyResponse{})
+	assert.Equal(t, int32(http.StatusBadRequest), errors.FromError(err).Code)
+
+	var rsp pb.CreateAPIKeyResponse
+	assert.Nil(t, k.Create(john, &pb.CreateAPIKeyRequest{Expiry: time.Now().Add(time.Hour).Unix()}, &rsp))
+	key, err := k.readKey("micro", rsp.ApiKey.Id)
+	assert.Nil(t, err)
+
+	// the store may not have expired the key yet
+	key.Expiry = time.Now().Add(-time.Second).Unix()
+	bytes, err := json.Marshal(key)
+	assert.Nil(t, err)
+	assert.Nil(t, store.Write(&store.Record{Key: "apikey/micro/" + key.ID, Value: bytes}))
+
+	err = k.Verify(svc, &pb.VerifyAPIKeyRequest{Key: rsp.Key}, &pb.VerifyAPIKeyResponse{})
+	assert.Equal(t, int32(http.StatusUnauthorized), errors.FromError(err).Code)
+}
+
+func TestAPIKeyNamespace(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	a := &Accounts{Auth: &Auth{}}
+	assert.Nil(t, a.createAccount(&auth.Account{ID: "1", Name: "john", Type: "user", Issuer: "foo", Secret: "secret"}))
+	k := &APIKeys{Auth: a.Auth}
+	john := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "1", Name: "john", Type: "user", Issuer: "foo"})
+	svc := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "api", Type: "service", Issuer: "micro", Scopes: []string{"service"}})
+	tenant := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "api", Type: "service", Issuer: "bar", Scopes: []string{"service"}})
+
+	var rsp pb.CreateAPIKeyResponse
+	assert.Nil(t, k.Create(john, &pb.CreateAPIKeyRequest{Options: &pb.Options{Namespace: "foo"}}, &rsp))
+
+	// the key is verified in the namespace it was created in, e.g. by the api gateway
+	var vrsp pb.VerifyAPIKeyResponse
+	assert.Nil(t, k.Verify(svc, &pb.VerifyAPIKeyRequest{Key: rsp.Key}, &vrsp))
+	assert.Equal(t, "1", vrsp.Account.Id)
+	assert.Equal(t, "foo", vrsp.Account.Issuer)
+
+	// services of other namespaces can't verify it
+	err := k.Verify(tenant, &pb.VerifyAPIKeyRequest{Key: rsp.Key, Options: &pb.Options{Namespace: "bar"}}, &pb.VerifyAPIKeyResponse{})
+	assert.Equal(t, int32(http.StatusUnauthorized), errors.FromError(err).Code)
+}
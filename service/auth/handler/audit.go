@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/util/auth/audit"
+	"github.com/micro/micro/v3/util/auth/namespace"
+	"github.com/micro/micro/v3/util/backoff"
+)
+
+const (
+	storePrefixAudit = "audit"
+
+	// auditListLimit is the max number of records returned when no limit is requested
+	auditListLimit = 100
+	// auditPageSize is the number of records read from the store at a time when listing them
+	auditPageSize = 500
+)
+
+// AuditRetention is how long audit records are kept for
+var AuditRetention = time.Hour * 24 * 30
+
+// Audit stores the audit records published by services and serves them to the admins of the
+// namespace the services are in
+type Audit struct {
+	Auth *Auth
+}
+
+// Consume stores the records published to the audit topic until the context is cancelled. The
+// auth service instances consume as a group so each record is stored once. Records are stored in
+// the namespace of the service which published them, unless it's in the default namespace.
+func (a *Audit) Consume(ctx context.Context) {
+	for attempts := 0; ; attempts++ {
+		evs, err := events.Consume(audit.Topic, events.WithGroup("auth"), events.WithContext(ctx))
+		if err != nil {
+			logger.Errorf("Error consuming %v: %v", audit.Topic, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff.Do(attempts)):
+			}
+			continue
+		}
+		attempts = 0
+
+		for ev := range evs {
+			var rec audit.Record
+			if err := ev.Unmarshal(&rec); err != nil {
+				logger.Debugf("Error unmarshaling audit record: %v", err)
+				continue
+			}
+			// services outside the default namespace can only record decisions of their own
+			if ns, ok := ev.Metadata[events.PublisherNamespaceKey]; ok && ns != namespace.DefaultNamespace {
+				rec.Namespace = ns
+			}
+			if err := writeAuditRecord(&rec); err != nil {
+				logger.Errorf("Error writing audit record: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
+
+// List the records of the namespace, newest first
+func (a *Audit) List(ctx context.Context, req *pb.ListAuditRecordsRequest, rsp *pb.ListAuditRecordsResponse) error {
+	if req.Options == nil {
+		req.Options = &pb.Options{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+	ns := req.Options.Namespace
+	if err := namespace.AuthorizeAdmin(ctx, ns, "auth.Audit.List"); err != nil {
+		return err
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = auditListLimit
+	}
+	skip := int(req.Offset)
+
+	// the keys are ordered by time so the records are read a page at a time newest first, until
+	// there are enough matching records or they're older than the period listed
+	prefix := strings.Join([]string{storePrefixAudit, ns, ""}, joinKey)
+	rsp.Records = make([]*pb.AuditRecord, 0, limit)
+	for offset := uint(0); ; offset += auditPageSize {
+		recs, err := store.Read(prefix, store.ReadPrefix(), store.ReadOrder(store.OrderDesc),
+			store.ReadLimit(auditPageSize), store.ReadOffset(offset))
+		if err != nil && err != store.ErrNotFound {
+			return errors.InternalServerError("auth.Audit.List", "Unable to read audit records: %v", err)
+		}
+
+		for _, r := range recs {
+			var rec audit.Record
+			if err := r.Decode(&rec); err != nil {
+				return errors.InternalServerError("auth.Audit.List", "Unable to decode audit record: %v", err)
+			}
+			if req.Since > 0 && rec.Timestamp.Unix() < req.Since {
+				return nil
+			}
+			if !matchAuditRecord(req, &rec) {
+				continue
+			}
+			if skip > 0 {
+				skip--
+				continue
+			}
+			rsp.Records = append(rsp.Records, serializeAuditRecord(&rec))
+			if len(rsp.Records) == limit {
+				return nil
+			}
+		}
+		if len(recs) < auditPageSize {
+			return nil
+		}
+	}
+}
+
+func writeAuditRecord(rec *audit.Record) error {
+	if len(rec.Namespace) == 0 {
+		rec.Namespace = namespace.DefaultNamespace
+	}
+	// the timestamp is zero padded so the keys sort by time
+	ts := fmt.Sprintf("%020d", rec.Timestamp.UnixNano())
+	r := store.NewRecord(strings.Join([]string{storePrefixAudit, rec.Namespace, ts, rec.ID}, joinKey), rec)
+	r.Expiry = AuditRetention
+	return store.Write(r)
+}
+
+func matchAuditRecord(req *pb.ListAuditRecordsRequest, rec *audit.Record) bool {
+	switch {
+	case len(req.AccountId) > 0 && req.AccountId != rec.Account:
+		return false
+	case len(req.Service) > 0 && req.Service != rec.Service:
+		return false
+	case len(req.Endpoint) > 0 && req.Endpoint != rec.Endpoint:
+		return false
+	case len(req.Decision) > 0 && req.Decision != rec.Decision:
+		return false
+	case req.Since > 0 && rec.Timestamp.Unix() < req.Since:
+		return false
+	case req.Until > 0 && rec.Timestamp.Unix() > req.Until:
+		return false
+	}
+	return true
+}
+
+func serializeAuditRecord(rec *audit.Record) *pb.AuditRecord {
+	return &pb.AuditRecord{
+		Id:        rec.ID,
+		AccountId: rec.Account,
+		Namespace: rec.Namespace,
+		Service:   rec.Service,
+		Endpoint:  rec.Endpoint,
+		Decision:  rec.Decision,
+		Reason:    rec.Reason,
+		Timestamp: rec.Timestamp.Unix(),
+	}
+}
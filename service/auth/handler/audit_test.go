@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/events/stream/memory"
+	"github.com/micro/micro/v3/service/store"
+	memstore "github.com/micro/micro/v3/service/store/memory"
+	"github.com/micro/micro/v3/util/auth/audit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditList(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	a := &Audit{Auth: &Auth{}}
+
+	now := time.Now()
+	records := []*audit.Record{
+		{ID: "1", Account: "john", Namespace: "micro", Service: "users", Endpoint: "Users.Read", Decision: audit.Granted, Timestamp: now.Add(-time.Hour * 2)},
+		{ID: "2", Account: "john", Namespace: "micro", Service: "users", Endpoint: "Users.Delete", Decision: audit.Denied, Reason: "forbidden", Timestamp: now.Add(-time.Hour)},
+		{ID: "3", Account: "jane", Namespace: "micro", Service: "users", Endpoint: "Users.Delete", Decision: audit.Granted, Timestamp: now},
+		{ID: "4", Account: "jane", Namespace: "foo", Service: "users", Endpoint: "Users.Delete", Decision: audit.Granted, Timestamp: now},
+	}
+	for _, r := range records {
+		assert.Nil(t, writeAuditRecord(r))
+	}
+
+	admin := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "admin", Type: "user", Scopes: []string{"admin"}, Issuer: "micro"})
+	list := func(req *pb.ListAuditRecordsRequest) []string {
+		var rsp pb.ListAuditRecordsResponse
+		assert.Nil(t, a.List(admin, req, &rsp))
+		var ids []string
+		for _, r := range rsp.Records {
+			ids = append(ids, r.Id)
+		}
+		return ids
+	}
+
+	// the records of the namespace are listed newest first
+	assert.Equal(t, []string{"3", "2", "1"}, list(&pb.ListAuditRecordsRequest{}))
+	assert.Equal(t, []string{"3"}, list(&pb.ListAuditRecordsRequest{Limit: 1}))
+	assert.Equal(t, []string{"2"}, list(&pb.ListAuditRecordsRequest{Limit: 1, Offset: 1}))
+	assert.Equal(t, []string{"1"}, list(&pb.ListAuditRecordsRequest{AccountId: "john", Offset: 1}))
+	assert.Equal(t, []string{"2"}, list(&pb.ListAuditRecordsRequest{Decision: audit.Denied}))
+	assert.Equal(t, []string{"2", "1"}, list(&pb.ListAuditRecordsRequest{AccountId: "john"}))
+	assert.Equal(t, []string{"3", "2"}, list(&pb.ListAuditRecordsRequest{Endpoint: "Users.Delete", Since: now.Add(-90 * time.Minute).Unix()}))
+
+	// only admins can read the audit log
+	user := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "john", Type: "user", Issuer: "micro"})
+	assert.NotNil(t, a.List(user, &pb.ListAuditRecordsRequest{}, &pb.ListAuditRecordsResponse{}))
+}
+
+func TestAuditConsume(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	stream, err := memory.NewStream()
+	assert.Nil(t, err)
+	events.DefaultStream = stream
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	a := &Audit{Auth: &Auth{}}
+	go a.Consume(ctx)
+	time.Sleep(time.Millisecond * 50)
+
+	// services outside the default namespace can't record decisions of other namespaces
+	rec := &audit.Record{ID: "1", Namespace: "micro", Service: "users", Decision: audit.Granted, Timestamp: time.Now()}
+	assert.Nil(t, stream.Publish(audit.Topic, rec, events.WithMetadata(map[string]string{
+		events.PublisherNamespaceKey: "foo",
+	})))
+	rec = &audit.Record{ID: "2", Namespace: "bar", Service: "users", Decision: audit.Granted, Timestamp: time.Now()}
+	assert.Nil(t, stream.Publish(audit.Topic, rec, events.WithMetadata(map[string]string{
+		events.PublisherNamespaceKey: "micro",
+	})))
+
+	admin := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "admin", Type: "user", Scopes: []string{"admin"}, Issuer: "micro"})
+	count := func(ns string) int {
+		var rsp pb.ListAuditRecordsResponse
+		assert.Nil(t, a.List(admin, &pb.ListAuditRecordsRequest{Options: &pb.Options{Namespace: ns}}, &rsp))
+		return len(rsp.Records)
+	}
+	assert.Eventually(t, func() bool {
+		return count("foo") == 1 && count("bar") == 1
+	}, time.Second, time.Millisecond*10)
+	assert.Equal(t, 0, count("micro"))
+}
@@ -139,6 +139,13 @@ func (a *Auth) Generate(ctx context.Context, req *pb.GenerateRequest, rsp *pb.Ge
 
 	// authorize the request
 	if err := authns.AuthorizeAdmin(ctx, req.Options.Namespace, "auth.Auth.Generate"); err != nil {
+		// repeated attempts by an account to generate accounts it's not allowed to are throttled
+		if acc, ok := auth.AccountFromContext(ctx); ok && len(acc.ID) > 0 {
+			if err := a.checkGenerate(acc.Issuer, acc.ID, "auth.Auth.Generate"); err != nil {
+				return err
+			}
+			a.generateFailed(acc.Issuer, acc.ID, loginSource(ctx))
+		}
 		return err
 	}
 
@@ -227,7 +234,13 @@ func (a *Auth) createAccount(acc *auth.Account) error {
 
 // Inspect a token and retrieve the account
 func (a *Auth) Inspect(ctx context.Context, req *pb.InspectRequest, rsp *pb.InspectResponse) error {
-	acc, err := a.TokenProvider.Inspect(req.Token)
+	var acc *auth.Account
+	var err error
+	if i, ok := a.TokenProvider.(auth.NamespaceInspector); ok && len(req.CallNamespace) > 0 {
+		acc, err = i.InspectNamespace(req.Token, req.CallNamespace)
+	} else {
+		acc, err = a.TokenProvider.Inspect(req.Token)
+	}
 	if err == token.ErrInvalidToken || err == token.ErrNotFound {
 		return errors.BadRequest("auth.Auth.Inspect", err.Error())
 	} else if err != nil {
@@ -296,12 +309,26 @@ func (a *Auth) Token(ctx context.Context, req *pb.TokenRequest, rsp *pb.TokenRes
 		return err
 	}
 
+	// accounts can be disabled, e.g. when deprovisioned by an identity provider
+	if acc.Metadata[metadataActive] == "false" {
+		return errors.Forbidden("auth.Auth.Token", "Account is disabled")
+	}
+
 	// If the refresh token was not used, validate the secrets match and then set the refresh token
 	// so it can be returned to the user
 	if len(req.RefreshToken) == 0 {
+		source := loginSource(ctx)
+		failures, err := a.checkLogin(req.Options.Namespace, acc.ID, source, "auth.Auth.Token")
+		if err != nil {
+			return err
+		}
 		if !secretsMatch(acc.Secret, req.Secret) {
+			a.loginFailed(req.Options.Namespace, acc.ID, source)
 			return errors.BadRequest("auth.Auth.Token", "Secret not correct")
 		}
+		if failures > 0 {
+			a.loginSucceeded(req.Options.Namespace, acc.ID, source)
+		}
 
 		refreshToken, err = a.refreshTokenForAccount(req.Options.Namespace, acc.ID)
 		if err != nil {
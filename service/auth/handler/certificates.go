@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/util/auth/cert"
+)
+
+const storeKeyAuthority = "certificateAuthority"
+
+var (
+	// CertificateExpiry is the lifetime of the certificates issued to services, they're kept short
+	// since a certificate can't be revoked
+	CertificateExpiry = time.Hour * 12
+	// AuthorityExpiry is the lifetime of the authority generated to issue the certificates
+	AuthorityExpiry = time.Hour * 24 * 365 * 10
+)
+
+// Certificates issues certificates to services which identify their account, so the connections
+// between services can be authenticated with mTLS
+type Certificates struct {
+	// Auth is used to read the accounts certificates are issued to
+	Auth *Auth
+
+	sync.Mutex
+	ca    *x509.Certificate
+	caPEM []byte
+	key   crypto.Signer
+}
+
+type authority struct {
+	Certificate []byte `json:"certificate"`
+	Key         []byte `json:"key"`
+}
+
+// Issue a certificate to the service making the request
+func (c *Certificates) Issue(ctx context.Context, req *pb.IssueCertificateRequest, rsp *pb.IssueCertificateResponse) error {
+	acc, ok := auth.AccountFromContext(ctx)
+	if !ok {
+		return errors.Unauthorized("auth.Certificates.Issue", "An account is required")
+	}
+	if acc.Type != "service" {
+		return errors.Forbidden("auth.Certificates.Issue", "Certificates are only issued to services")
+	}
+	if req.Options != nil && len(req.Options.Namespace) > 0 && req.Options.Namespace != acc.Issuer {
+		return errors.Forbidden("auth.Certificates.Issue", "Forbidden namespace")
+	}
+
+	// the account may come from the certificate being renewed, which isn't checked against the
+	// store, so the account is read again in case it was deleted or disabled
+	acc, err := c.Auth.getAccountForID(acc.ID, acc.Issuer, "auth.Certificates.Issue")
+	if err != nil {
+		if errors.FromError(err).Code == http.StatusBadRequest {
+			return errors.Forbidden("auth.Certificates.Issue", "Account not found")
+		}
+		return err
+	}
+	if acc.Type != "service" || acc.Metadata[metadataActive] == "false" {
+		return errors.Forbidden("auth.Certificates.Issue", "Certificates are only issued to active services")
+	}
+
+	certPEM, caPEM, err := c.Sign(acc, []byte(req.Csr))
+	if err == cert.ErrInvalidRequest {
+		return errors.BadRequest("auth.Certificates.Issue", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("auth.Certificates.Issue", "Error issuing certificate: %v", err)
+	}
+
+	rsp.Certificate = string(certPEM)
+	rsp.Authority = string(caPEM)
+	rsp.Expiry = time.Now().Add(CertificateExpiry).Unix()
+	return nil
+}
+
+// Sign the certificate signing request for the account, returning the certificate and the
+// certificate of the authority
+func (c *Certificates) Sign(acc *auth.Account, csr []byte) ([]byte, []byte, error) {
+	if err := c.loadAuthority(); err != nil {
+		return nil, nil, err
+	}
+	certPEM, err := cert.Sign(c.ca, c.key, csr, acc, CertificateExpiry)
+	if err != nil {
+		return nil, nil, err
+	}
+	return certPEM, c.caPEM, nil
+}
+
+// loadAuthority loads the authority from the store, generating it the first time certificates
+// are issued
+func (c *Certificates) loadAuthority() error {
+	c.Lock()
+	defer c.Unlock()
+	if c.ca != nil {
+		return nil
+	}
+
+	recs, err := store.Read(storeKeyAuthority)
+	if err == store.ErrNotFound {
+		certPEM, keyPEM, err := cert.NewAuthority("micro", AuthorityExpiry)
+		if err != nil {
+			return err
+		}
+		b, _ := json.Marshal(&authority{Certificate: certPEM, Key: keyPEM})
+		// only create the authority if another instance hasn't generated one at the same time,
+		// then read back whichever was written first
+		err = store.WriteIfMatch(&store.Record{Key: storeKeyAuthority, Value: b}, "")
+		if err != nil && err != store.ErrConflict {
+			return err
+		}
+		recs, err = store.Read(storeKeyAuthority)
+		if err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	var a authority
+	if err := json.Unmarshal(recs[0].Value, &a); err != nil {
+		return err
+	}
+	ca, key, err := cert.ParseAuthority(a.Certificate, a.Key)
+	if err != nil {
+		return err
+	}
+	c.ca, c.caPEM, c.key = ca, a.Certificate, key
+	return nil
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"testing"
+
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/util/auth/cert"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueCertificate(t *testing.T) {
+	setupLockoutTest(t)
+	c := &Certificates{Auth: &Auth{}}
+	assert.Nil(t, c.Auth.createAccount(&auth.Account{ID: "foo", Type: "service", Issuer: "micro", Secret: "secret"}))
+	assert.Nil(t, c.Auth.createAccount(&auth.Account{ID: "bar", Type: "service", Issuer: "micro", Secret: "secret",
+		Metadata: map[string]string{metadataActive: "false"}}))
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	csr, err := cert.NewRequest(key)
+	assert.Nil(t, err)
+
+	tcs := []struct {
+		name string
+		acc  *auth.Account
+		code int32
+	}{
+		{name: "service", acc: &auth.Account{ID: "foo", Type: "service", Issuer: "micro"}},
+		{name: "user", acc: &auth.Account{ID: "foo", Type: "user", Issuer: "micro"}, code: http.StatusForbidden},
+		// accounts from certificates are always services, so the stored account must be checked
+		{name: "deleted service", acc: &auth.Account{ID: "baz", Type: "service", Issuer: "micro"}, code: http.StatusForbidden},
+		{name: "disabled service", acc: &auth.Account{ID: "bar", Type: "service", Issuer: "micro"}, code: http.StatusForbidden},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := auth.ContextWithAccount(context.TODO(), tc.acc)
+			rsp := &pb.IssueCertificateResponse{}
+			err := c.Issue(ctx, &pb.IssueCertificateRequest{Csr: string(csr)}, rsp)
+			if tc.code == 0 {
+				assert.Nil(t, err)
+				assert.NotEmpty(t, rsp.Certificate)
+				return
+			}
+			assert.Equal(t, tc.code, errors.FromError(err).Code)
+		})
+	}
+}
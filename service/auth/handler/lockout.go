@@ -0,0 +1,320 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
+	inauth "github.com/micro/micro/v3/util/auth"
+	"github.com/micro/micro/v3/util/auth/namespace"
+	"github.com/micro/micro/v3/util/clientip"
+)
+
+const (
+	storePrefixLoginAttempts = "loginAttempts"
+	storePrefixLoginSources  = "loginSources"
+	// denied attempts to generate accounts are throttled separately so they don't lock the account
+	storePrefixGenerateAttempts = "generateAttempts"
+
+	// unknownSource is the source of logins which didn't come from a known ip
+	unknownSource = "unknown"
+
+	// loginRetries is how many times a failed login is recorded again when the attempts were
+	// changed by a concurrent login
+	loginRetries = 5
+)
+
+var (
+	// LoginThrottleAfter is the number of consecutive failed logins to an account from an ip after
+	// which logins to the account from the ip must wait before trying again. The wait doubles with
+	// each further failure.
+	LoginThrottleAfter = 3
+	// LoginMaxFailures is the number of failed logins to an account from any ip after which the
+	// account is locked. It's well above LoginThrottleAfter since anyone can fail to login to an
+	// account, and locking it locks out its owner too.
+	LoginMaxFailures = 50
+	// LoginLockout is how long an account is locked for
+	LoginLockout = time.Minute * 15
+	// LoginFailureWindow is how long a failed login counts towards throttling and lockout
+	LoginFailureWindow = time.Hour
+
+	// loginProxies are the services trusted to set the ip of the client which made the request
+	loginProxies = map[string]bool{"api": true, "web": true}
+)
+
+type loginAttempts struct {
+	Failures    int   `json:"failures"`
+	LastFailure int64 `json:"lastFailure"`
+	LockedUntil int64 `json:"lockedUntil"`
+}
+
+// loginSource returns the ip the login was made from. Failed logins are throttled by account and
+// source, so failures from one ip don't throttle the owner of the account logging in from another.
+// The client ip set in the metadata is only used if the caller is the api or web, anyone calling
+// the service directly could set it to a different ip each time.
+func loginSource(ctx context.Context) string {
+	if acc, ok := auth.AccountFromContext(ctx); ok && acc.Type == "service" &&
+		acc.Issuer == namespace.DefaultNamespace && loginProxies[acc.Metadata[inauth.ServiceMetadataKey]] {
+		if ip, ok := metadata.Get(ctx, clientip.MetadataKey); ok && len(ip) > 0 {
+			return ip
+		}
+	}
+	if ip, ok := clientip.RemoteFromContext(ctx); ok {
+		return ip
+	}
+	return unknownSource
+}
+
+// checkLogin returns an error if the account is locked, the login attempts can't be read, or logins
+// from the source must wait before trying again. Otherwise it returns the number of recent failed
+// logins from the source.
+func (a *Auth) checkLogin(ns, id, source, method string) (int, error) {
+	acc, _, err := a.readLoginAttempts(accountAttemptsKey(ns, id))
+	if err != nil {
+		logger.Errorf("Error reading login attempts: %v", err)
+		return 0, errors.InternalServerError(method, "Unable to check login attempts")
+	}
+
+	now := time.Now()
+	if now.Unix() < acc.LockedUntil {
+		return acc.Failures, errors.Forbidden(method, "Account is locked until %v", time.Unix(acc.LockedUntil, 0).Format(time.RFC3339))
+	}
+
+	att, _, err := a.readLoginAttempts(sourceAttemptsKey(ns, id, source))
+	if err != nil {
+		logger.Errorf("Error reading login attempts: %v", err)
+		return 0, errors.InternalServerError(method, "Unable to check login attempts")
+	}
+	return att.Failures, throttled(att, method)
+}
+
+// checkGenerate returns an error if the account must wait before trying to generate accounts again
+// after being denied too many times
+func (a *Auth) checkGenerate(ns, id, method string) error {
+	att, _, err := a.readLoginAttempts(generateAttemptsKey(ns, id))
+	if err != nil {
+		logger.Errorf("Error reading generate attempts: %v", err)
+		return errors.InternalServerError(method, "Unable to check generate attempts")
+	}
+	return throttled(att, method)
+}
+
+// throttled returns an error if the attempts must wait before trying again
+func throttled(att *loginAttempts, method string) error {
+	wait := throttleDelay(att.Failures)
+	if wait == 0 {
+		return nil
+	}
+	now := time.Now()
+	if retry := time.Unix(att.LastFailure, 0).Add(wait); now.Before(retry) {
+		msg := fmt.Sprintf("Too many failed attempts, try again in %v", retry.Sub(now).Round(time.Second))
+		return errors.New(method, msg, http.StatusTooManyRequests)
+	}
+	return nil
+}
+
+// loginFailed records a failed login from the source, throttling the source if there have been too
+// many from it and locking the account if there have been too many from any source.
+func (a *Auth) loginFailed(ns, id, source string) {
+	a.recordLoginFailure(ns, id, source, sourceAttemptsKey(ns, id, source), func(att *loginAttempts, now time.Time) string {
+		if att.Failures == LoginThrottleAfter {
+			return auth.EventLoginThrottled
+		}
+		return ""
+	})
+	a.recordLoginFailure(ns, id, source, accountAttemptsKey(ns, id), func(att *loginAttempts, now time.Time) string {
+		if att.Failures >= LoginMaxFailures {
+			att.Failures = 0
+			att.LockedUntil = now.Add(LoginLockout).Unix()
+			return auth.EventAccountLocked
+		}
+		return ""
+	})
+}
+
+// generateFailed records a denied attempt to generate accounts, throttling the account's further
+// attempts if there have been too many. Unlike failed logins they don't lock the account.
+func (a *Auth) generateFailed(ns, id, source string) {
+	a.recordLoginFailure(ns, id, source, generateAttemptsKey(ns, id), func(att *loginAttempts, now time.Time) string {
+		if att.Failures == LoginThrottleAfter {
+			return auth.EventLoginThrottled
+		}
+		return ""
+	})
+}
+
+// recordLoginFailure counts a failed login in the attempts with the key, check returns the event to
+// publish if any. The attempts are written conditionally so concurrent failures are all counted.
+func (a *Auth) recordLoginFailure(ns, id, source, key string, check func(*loginAttempts, time.Time) string) {
+	for i := 0; i < loginRetries; i++ {
+		att, etag, err := a.readLoginAttempts(key)
+		if err != nil {
+			logger.Errorf("Error reading login attempts: %v", err)
+			return
+		}
+
+		now := time.Now()
+		att.Failures++
+		att.LastFailure = now.Unix()
+		evType := check(att, now)
+
+		err = a.writeLoginAttempts(key, att, etag)
+		if err == store.ErrConflict {
+			continue
+		} else if err != nil {
+			logger.Errorf("Error writing login attempts: %v", err)
+		}
+		if len(evType) > 0 {
+			logger.Warnf("Suspicious login activity for account %v in namespace %v from %v: %v", id, ns, source, evType)
+			publishLoginEvent(evType, ns, id, source, att)
+		}
+		return
+	}
+	logger.Errorf("Error writing login attempts of account %v in namespace %v: %v", id, ns, store.ErrConflict)
+}
+
+// loginSucceeded resets the failed logins from the source. Failures from other sources still count
+// towards locking the account.
+func (a *Auth) loginSucceeded(ns, id, source string) {
+	if err := store.Delete(sourceAttemptsKey(ns, id, source)); err != nil && err != store.ErrNotFound {
+		logger.Errorf("Error resetting login attempts: %v", err)
+	}
+}
+
+// Accounts serves the accounts service. Auth embeds a mutex so the Unlock rpc is defined here rather
+// than on Auth itself.
+type Accounts struct {
+	*Auth
+}
+
+// Unlock an account which was locked after too many failed logins
+func (a *Accounts) Unlock(ctx context.Context, req *pb.UnlockAccountRequest, rsp *pb.UnlockAccountResponse) error {
+	if len(req.Id) == 0 {
+		return errors.BadRequest("auth.Accounts.Unlock", "Missing ID")
+	}
+
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.Options{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	// authorize the request
+	if err := namespace.AuthorizeAdmin(ctx, req.Options.Namespace, "auth.Accounts.Unlock"); err != nil {
+		return err
+	}
+
+	acc, err := a.getAccountForID(req.Id, req.Options.Namespace, "auth.Accounts.Unlock")
+	if err != nil {
+		return err
+	}
+
+	// the throttled sources are reset too
+	keys, err := store.List(store.ListPrefix(sourceAttemptsKey(req.Options.Namespace, acc.ID, "")))
+	if err != nil {
+		return errors.InternalServerError("auth.Accounts.Unlock", "Unable to unlock account: %v", err)
+	}
+	keys = append(keys, accountAttemptsKey(req.Options.Namespace, acc.ID), generateAttemptsKey(req.Options.Namespace, acc.ID))
+	for _, key := range keys {
+		if err := store.Delete(key); err != nil && err != store.ErrNotFound {
+			return errors.InternalServerError("auth.Accounts.Unlock", "Unable to unlock account: %v", err)
+		}
+	}
+
+	publishLoginEvent(auth.EventAccountUnlocked, req.Options.Namespace, acc.ID, "", &loginAttempts{})
+	return nil
+}
+
+// accountAttemptsKey is the key of the failed logins to the account from any source
+func accountAttemptsKey(ns, id string) string {
+	return strings.Join([]string{storePrefixLoginAttempts, ns, id}, joinKey)
+}
+
+// sourceAttemptsKey is the key of the failed logins to the account from the source
+func sourceAttemptsKey(ns, id, source string) string {
+	return strings.Join([]string{storePrefixLoginSources, ns, id, source}, joinKey)
+}
+
+// generateAttemptsKey is the key of the denied attempts of the account to generate accounts
+func generateAttemptsKey(ns, id string) string {
+	return strings.Join([]string{storePrefixGenerateAttempts, ns, id}, joinKey)
+}
+
+// readLoginAttempts returns the login attempts with the key and the etag of their record, which
+// is empty if there have been none
+func (a *Auth) readLoginAttempts(key string) (*loginAttempts, string, error) {
+	recs, err := store.Read(key)
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return &loginAttempts{}, "", nil
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	var att *loginAttempts
+	if err := json.Unmarshal(recs[0].Value, &att); err != nil {
+		return nil, "", err
+	}
+	return att, recs[0].Etag, nil
+}
+
+// writeLoginAttempts writes the login attempts if their record still has the etag
+func (a *Auth) writeLoginAttempts(key string, att *loginAttempts, etag string) error {
+	bytes, err := json.Marshal(att)
+	if err != nil {
+		return err
+	}
+
+	// the record expires once neither the failures or the lock apply
+	expiry := LoginFailureWindow
+	if until := time.Until(time.Unix(att.LockedUntil, 0)); until > expiry {
+		expiry = until
+	}
+
+	return store.WriteIfMatch(&store.Record{Key: key, Value: bytes, Expiry: expiry}, etag)
+}
+
+// throttleDelay returns how long to wait after the last failed login before trying again
+func throttleDelay(failures int) time.Duration {
+	if LoginThrottleAfter <= 0 || failures < LoginThrottleAfter {
+		return 0
+	}
+	// the shift is capped so the wait doesn't overflow
+	shift := failures - LoginThrottleAfter
+	if wait := time.Second << uint(shift); shift < 32 && wait < LoginLockout {
+		return wait
+	}
+	return LoginLockout
+}
+
+func publishLoginEvent(evType, ns, id, source string, att *loginAttempts) {
+	ev := &auth.EventLoginPayload{
+		Type:      evType,
+		Namespace: ns,
+		Account:   id,
+		Source:    source,
+		Failures:  att.Failures,
+	}
+	if att.LockedUntil > 0 {
+		ev.LockedUntil = time.Unix(att.LockedUntil, 0)
+	}
+
+	err := events.Publish(auth.EventTopic, ev, events.WithMetadata(map[string]string{
+		"type":      evType,
+		"namespace": ns,
+	}))
+	if err != nil {
+		logger.Errorf("Error publishing %v event: %v", evType, err)
+	}
+}
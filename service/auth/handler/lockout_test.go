@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/events/stream/memory"
+	"github.com/micro/micro/v3/service/store"
+	memstore "github.com/micro/micro/v3/service/store/memory"
+	inauth "github.com/micro/micro/v3/util/auth"
+	"github.com/micro/micro/v3/util/clientip"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupLockoutTest(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	stream, err := memory.NewStream()
+	assert.Nil(t, err)
+	events.DefaultStream = stream
+}
+
+func TestThrottleDelay(t *testing.T) {
+	assert.Equal(t, time.Duration(0), throttleDelay(LoginThrottleAfter-1))
+	assert.Equal(t, time.Second, throttleDelay(LoginThrottleAfter))
+	assert.Equal(t, time.Second*4, throttleDelay(LoginThrottleAfter+2))
+	assert.Equal(t, LoginLockout, throttleDelay(LoginThrottleAfter+100))
+}
+
+func TestLockout(t *testing.T) {
+	setupLockoutTest(t)
+	a := &Auth{}
+
+	for i := 0; i < LoginThrottleAfter; i++ {
+		_, err := a.checkLogin("micro", "john", "10.0.0.1", "auth.Auth.Token")
+		assert.Nil(t, err)
+		a.loginFailed("micro", "john", "10.0.0.1")
+	}
+
+	// the next attempt from the ip must wait
+	_, err := a.checkLogin("micro", "john", "10.0.0.1", "auth.Auth.Token")
+	assert.Equal(t, int32(http.StatusTooManyRequests), errors.FromError(err).Code)
+
+	// other ips and accounts are unaffected
+	_, err = a.checkLogin("micro", "john", "10.0.0.2", "auth.Auth.Token")
+	assert.Nil(t, err)
+	_, err = a.checkLogin("micro", "jane", "10.0.0.1", "auth.Auth.Token")
+	assert.Nil(t, err)
+
+	// a successful login resets the failures
+	a.loginSucceeded("micro", "john", "10.0.0.1")
+	failures, err := a.checkLogin("micro", "john", "10.0.0.1", "auth.Auth.Token")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, failures)
+}
+
+func TestLockoutAccount(t *testing.T) {
+	setupLockoutTest(t)
+	a := &Auth{}
+
+	// the account is locked after too many failures from any ip, so spreading them out doesn't help
+	for i := 0; i < LoginMaxFailures; i++ {
+		a.loginFailed("micro", "john", fmt.Sprintf("10.0.0.%d", i%LoginThrottleAfter))
+	}
+	_, err := a.checkLogin("micro", "john", "10.0.1.1", "auth.Auth.Token")
+	assert.Equal(t, int32(http.StatusForbidden), errors.FromError(err).Code)
+
+	// other accounts are unaffected
+	_, err = a.checkLogin("micro", "jane", "10.0.1.1", "auth.Auth.Token")
+	assert.Nil(t, err)
+}
+
+func TestCheckLoginStoreError(t *testing.T) {
+	setupLockoutTest(t)
+	store.DefaultStore = &failingStore{Store: store.DefaultStore}
+	a := &Auth{}
+
+	// logins aren't allowed if the attempts can't be checked
+	_, err := a.checkLogin("micro", "john", "10.0.0.1", "auth.Auth.Token")
+	assert.Equal(t, int32(http.StatusInternalServerError), errors.FromError(err).Code)
+}
+
+func TestLoginSource(t *testing.T) {
+	md := metadata.Metadata{clientip.MetadataKey: "10.0.0.1", "Remote": "10.0.0.2:1234"}
+	api := &auth.Account{ID: "1", Type: "service", Issuer: "micro", Metadata: map[string]string{inauth.ServiceMetadataKey: "api"}}
+	foo := &auth.Account{ID: "2", Type: "service", Issuer: "micro", Metadata: map[string]string{inauth.ServiceMetadataKey: "foo"}}
+	user := &auth.Account{ID: "3", Type: "user", Issuer: "micro", Metadata: map[string]string{inauth.ServiceMetadataKey: "api"}}
+
+	// the client ip is only trusted from the api and web
+	ctx := metadata.NewContext(context.TODO(), md)
+	assert.Equal(t, "10.0.0.1", loginSource(auth.ContextWithAccount(ctx, api)))
+	assert.Equal(t, "10.0.0.2", loginSource(auth.ContextWithAccount(ctx, foo)))
+	assert.Equal(t, "10.0.0.2", loginSource(auth.ContextWithAccount(ctx, user)))
+	assert.Equal(t, "10.0.0.2", loginSource(ctx))
+	assert.Equal(t, unknownSource, loginSource(context.TODO()))
+}
+
+func TestGenerateThrottle(t *testing.T) {
+	setupLockoutTest(t)
+	a := &Auth{}
+	acc := &auth.Account{ID: "john", Type: "user", Issuer: "micro"}
+	ctx := auth.ContextWithAccount(context.TODO(), acc)
+
+	for i := 0; i < LoginThrottleAfter; i++ {
+		err := a.Generate(ctx, &pb.GenerateRequest{Id: "jane"}, &pb.GenerateResponse{})
+		assert.NotNil(t, err)
+		assert.NotEqual(t, int32(http.StatusTooManyRequests), errors.FromError(err).Code)
+	}
+
+	// further attempts must wait, but the account can still login
+	err := a.Generate(ctx, &pb.GenerateRequest{Id: "jane"}, &pb.GenerateResponse{})
+	assert.Equal(t, int32(http.StatusTooManyRequests), errors.FromError(err).Code)
+	_, err = a.checkLogin("micro", "john", "10.0.0.1", "auth.Auth.Token")
+	assert.Nil(t, err)
+}
+
+// failingStore returns an error from reads
+type failingStore struct {
+	store.Store
+}
+
+func (f *failingStore) Read(key string, opts ...store.ReadOption) ([]*store.Record, error) {
+	return nil, goerrors.New("store unavailable")
+}
+
+func TestUnlock(t *testing.T) {
+	setupLockoutTest(t)
+	a := &Accounts{Auth: &Auth{}}
+	assert.Nil(t, a.createAccount(&auth.Account{ID: "1", Name: "john", Issuer: "micro", Secret: "secret"}))
+
+	for i := 0; i < LoginMaxFailures; i++ {
+		a.loginFailed("micro", "1", "10.0.0.1")
+	}
+	_, err := a.checkLogin("micro", "1", "10.0.0.2", "auth.Auth.Token")
+	assert.NotNil(t, err)
+
+	ctx := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "admin", Type: "user", Scopes: []string{"admin"}, Issuer: "micro"})
+	err = a.Unlock(ctx, &pb.UnlockAccountRequest{Id: "john"}, &pb.UnlockAccountResponse{})
+	assert.Nil(t, err)
+
+	// the account and the throttled ips are reset
+	_, err = a.checkLogin("micro", "1", "10.0.0.2", "auth.Auth.Token")
+	assert.Nil(t, err)
+	_, err = a.checkLogin("micro", "1", "10.0.0.1", "auth.Auth.Token")
+	assert.Nil(t, err)
+}
+
+func TestLoginFailedConcurrently(t *testing.T) {
+	setupLockoutTest(t)
+	a := &Auth{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < loginRetries; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.loginFailed("micro", "john", "10.0.0.1")
+		}()
+	}
+	wg.Wait()
+
+	att, _, err := a.readLoginAttempts(sourceAttemptsKey("micro", "john", "10.0.0.1"))
+	assert.Nil(t, err)
+	assert.Equal(t, loginRetries, att.Failures)
+}
+
+func TestOAuthLockout(t *testing.T) {
+	setupLockoutTest(t)
+	o := &OAuth{Auth: &Auth{}}
+	assert.Nil(t, o.Auth.createAccount(&auth.Account{ID: "client", Type: clientAccountType, Issuer: "micro", Secret: "secret"}))
+
+	req := &pb.OAuthTokenRequest{ClientId: "client", ClientSecret: "wrong", GrantType: grantClientCredentials}
+	for i := 0; i < LoginThrottleAfter; i++ {
+		rsp := &pb.OAuthTokenResponse{}
+		assert.Nil(t, o.Token(context.TODO(), req, rsp))
+		assert.Equal(t, "invalid_client", rsp.Error)
+	}
+
+	// the client must wait before authenticating again, even with the right secret
+	req.ClientSecret = "secret"
+	err := o.Token(context.TODO(), req, &pb.OAuthTokenResponse{})
+	assert.Equal(t, int32(http.StatusTooManyRequests), errors.FromError(err).Code)
+}
@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/util/auth/namespace"
+	"github.com/micro/micro/v3/util/auth/token"
+)
+
+const (
+	// clientAccountType is the type of the accounts created for oauth clients
+	clientAccountType = "client"
+
+	// grant types supported by the token endpoint
+	grantClientCredentials = "client_credentials"
+	grantRefreshToken      = "refresh_token"
+)
+
+var (
+	// OAuthTokenExpiry is the lifetime of access tokens issued to oauth clients
+	OAuthTokenExpiry = time.Hour
+)
+
+// OAuth acts as an OAuth2 authorization server for machine clients. Clients are stored as accounts
+// of type client, the scopes they're granted are the account scopes so access is controlled by the
+// auth rules as it is for any other account.
+type OAuth struct {
+	Auth *Auth
+}
+
+// Token implements the token endpoint for the client_credentials and refresh_token grants
+func (o *OAuth) Token(ctx context.Context, req *pb.OAuthTokenRequest, rsp *pb.OAuthTokenResponse) error {
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.Options{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+	ns := req.Options.Namespace
+
+	if len(req.ClientId) == 0 || len(req.ClientSecret) == 0 {
+		return oauthError(rsp, "invalid_client", "Client authentication failed")
+	}
+
+	// authenticate the client
+	acc, err := o.Auth.getAccountForID(req.ClientId, ns, "auth.OAuth.Token")
+	if err != nil {
+		if merr, ok := err.(*errors.Error); ok && merr.Code == 400 {
+			return oauthError(rsp, "invalid_client", "Client authentication failed")
+		}
+		return err
+	}
+	if acc.Type != clientAccountType || acc.Metadata[metadataActive] == "false" {
+		return oauthError(rsp, "invalid_client", "Client authentication failed")
+	}
+
+	// failed client authentication is throttled and locked out as failed logins are
+	source := loginSource(ctx)
+	failures, err := o.Auth.checkLogin(ns, acc.ID, source, "auth.OAuth.Token")
+	if err != nil {
+		return err
+	}
+	if !secretsMatch(acc.Secret, req.ClientSecret) {
+		o.Auth.loginFailed(ns, acc.ID, source)
+		return oauthError(rsp, "invalid_client", "Client authentication failed")
+	}
+	if failures > 0 {
+		o.Auth.loginSucceeded(ns, acc.ID, source)
+	}
+
+	var refreshToken string
+	switch req.GrantType {
+	case grantClientCredentials:
+		refreshToken, err = o.Auth.refreshTokenForAccount(ns, acc.ID)
+		if err != nil {
+			return errors.InternalServerError("auth.OAuth.Token", "Unable to get refresh token: %v", err)
+		}
+	case grantRefreshToken:
+		if len(req.RefreshToken) == 0 {
+			return oauthError(rsp, "invalid_request", "Missing refresh_token")
+		}
+		id, err := o.Auth.accountIDForRefreshToken(ns, req.RefreshToken)
+		if err == store.ErrNotFound || (err == nil && id != acc.ID) {
+			return oauthError(rsp, "invalid_grant", "Invalid refresh token")
+		} else if err != nil {
+			return errors.InternalServerError("auth.OAuth.Token", "Unable to lookup token: %v", err)
+		}
+		refreshToken = req.RefreshToken
+	case "":
+		return oauthError(rsp, "invalid_request", "Missing grant_type")
+	default:
+		return oauthError(rsp, "unsupported_grant_type", "Grant type "+req.GrantType+" is not supported")
+	}
+
+	// the scopes requested must be a subset of the ones granted to the client
+	scopes, ok := grantScopes(acc.Scopes, strings.Fields(req.Scope))
+	if !ok {
+		return oauthError(rsp, "invalid_scope", "The requested scope exceeds the scope granted to the client")
+	}
+	acc.Scopes = scopes
+	acc.Secret = ""
+
+	tok, err := o.Auth.TokenProvider.Generate(acc, token.WithExpiry(OAuthTokenExpiry))
+	if err != nil {
+		return errors.InternalServerError("auth.OAuth.Token", "Unable to generate token: %v", err)
+	}
+
+	rsp.AccessToken = tok.Token
+	rsp.TokenType = "Bearer"
+	rsp.ExpiresIn = int64(OAuthTokenExpiry.Seconds())
+	rsp.RefreshToken = refreshToken
+	rsp.Scope = strings.Join(scopes, " ")
+	return nil
+}
+
+// CreateClient registers a new oauth client, returning its credentials
+func (o *OAuth) CreateClient(ctx context.Context, req *pb.CreateClientRequest, rsp *pb.CreateClientResponse) error {
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.Options{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	// authorize the request
+	if err := namespace.AuthorizeAdmin(ctx, req.Options.Namespace, "auth.OAuth.CreateClient"); err != nil {
+		return err
+	}
+
+	secret := uuid.New().String()
+	acc := &auth.Account{
+		ID:       uuid.New().String(),
+		Type:     clientAccountType,
+		Name:     req.Name,
+		Scopes:   req.Scopes,
+		Metadata: req.Metadata,
+		Issuer:   req.Options.Namespace,
+		Secret:   secret,
+	}
+	if err := o.Auth.createAccount(acc); err != nil {
+		return err
+	}
+
+	rsp.Client = serializeClient(acc)
+	rsp.Secret = secret
+	return nil
+}
+
+// ListClients returns the oauth clients registered in the namespace
+func (o *OAuth) ListClients(ctx context.Context, req *pb.ListClientsRequest, rsp *pb.ListClientsResponse) error {
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.Options{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	// authorize the request
+	if err := namespace.AuthorizeAdmin(ctx, req.Options.Namespace, "auth.OAuth.ListClients"); err != nil {
+		return err
+	}
+
+	prefix := strings.Join([]string{storePrefixAccounts, req.Options.Namespace, ""}, joinKey)
+	recs, err := store.Read(prefix, store.ReadPrefix())
+	if err != nil {
+		return errors.InternalServerError("auth.OAuth.ListClients", "Unable to read from store: %v", err)
+	}
+
+	rsp.Clients = make([]*pb.Client, 0, len(recs))
+	for _, rec := range recs {
+		var acc *auth.Account
+		if err := json.Unmarshal(rec.Value, &acc); err != nil {
+			return errors.InternalServerError("auth.OAuth.ListClients", "Unable to unmarshal account: %v", err)
+		}
+		if acc.Type == clientAccountType {
+			rsp.Clients = append(rsp.Clients, serializeClient(acc))
+		}
+	}
+
+	return nil
+}
+
+// DeleteClient removes an oauth client, its refresh token is deleted so it can no longer be used
+// to issue new access tokens
+func (o *OAuth) DeleteClient(ctx context.Context, req *pb.DeleteClientRequest, rsp *pb.DeleteClientResponse) error {
+	if len(req.Id) == 0 {
+		return errors.BadRequest("auth.OAuth.DeleteClient", "Missing ID")
+	}
+
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.Options{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+	ns := req.Options.Namespace
+
+	// authorize the request
+	if err := namespace.AuthorizeAdmin(ctx, ns, "auth.OAuth.DeleteClient"); err != nil {
+		return err
+	}
+
+	acc, err := o.Auth.getAccountForID(req.Id, ns, "auth.OAuth.DeleteClient")
+	if err != nil {
+		return err
+	}
+	if acc.Type != clientAccountType {
+		return errors.BadRequest("auth.OAuth.DeleteClient", "Account %v is not a client", req.Id)
+	}
+
+	prefix := strings.Join([]string{storePrefixRefreshTokens, ns, acc.ID, ""}, joinKey)
+	keys, err := store.List(store.ListPrefix(prefix))
+	if err != nil {
+		return errors.InternalServerError("auth.OAuth.DeleteClient", "Error finding refresh token: %v", err)
+	}
+
+	keys = append(keys,
+		strings.Join([]string{storePrefixAccounts, ns, acc.ID}, joinKey),
+		strings.Join([]string{storePrefixAccountsByName, ns, acc.Name}, joinKey),
+	)
+	for _, k := range keys {
+		if err := store.Delete(k); err != nil && err != store.ErrNotFound {
+			return errors.InternalServerError("auth.OAuth.DeleteClient", "Error deleting client: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// grantScopes returns the scopes to grant given those requested. If none are requested
+// all the scopes the client has are granted.
+func grantScopes(allowed, requested []string) ([]string, bool) {
+	if len(requested) == 0 {
+		return allowed, true
+	}
+	for _, s := range requested {
+		if !hasScope(s, allowed) {
+			return nil, false
+		}
+	}
+	return requested, true
+}
+
+func oauthError(rsp *pb.OAuthTokenResponse, code, description string) error {
+	rsp.Error = code
+	rsp.ErrorDescription = description
+	return nil
+}
+
+func serializeClient(a *auth.Account) *pb.Client {
+	return &pb.Client{
+		Id:       a.ID,
+		Name:     a.Name,
+		Scopes:   a.Scopes,
+		Metadata: a.Metadata,
+	}
+}
@@ -0,0 +1,660 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/util/auth/namespace"
+)
+
+const (
+	storePrefixSCIMGroups = "scimGroup"
+
+	// metadata keys used to store the scim attributes of an account
+	metadataSCIM        = "scim"
+	metadataExternalID  = "externalId"
+	metadataDisplayName = "displayName"
+	metadataEmails      = "emails"
+	metadataUpdated     = "updated"
+	// metadataActive is set to false for accounts which are disabled and can't generate tokens
+	metadataActive = "active"
+)
+
+// SCIM provisions accounts from an identity provider. Users are stored as accounts and granted the
+// scopes mapped to the groups they're members of, so the identity provider controls access through
+// group membership and the namespace admins control what each group can access.
+type SCIM struct {
+	Auth *Auth
+}
+
+type scimGroup struct {
+	ID          string   `json:"id"`
+	ExternalID  string   `json:"externalId"`
+	DisplayName string   `json:"displayName"`
+	Members     []string `json:"members"`
+	Scopes      []string `json:"scopes"`
+	Created     int64    `json:"created"`
+	Updated     int64    `json:"updated"`
+}
+
+// CreateUser provisions an account for the user
+func (s *SCIM) CreateUser(ctx context.Context, req *pb.SCIMUserRequest, rsp *pb.SCIMUserResponse) error {
+	ns, err := s.authorize(ctx, &req.Options, "auth.SCIM.CreateUser")
+	if err != nil {
+		return err
+	}
+	if req.User == nil || len(req.User.UserName) == 0 {
+		return errors.BadRequest("auth.SCIM.CreateUser", "Missing userName")
+	}
+	if s.nameTaken(ns, req.User.UserName) {
+		return errors.Conflict("auth.SCIM.CreateUser", "User with userName %v already exists", req.User.UserName)
+	}
+
+	acc := &auth.Account{
+		ID:       uuid.New().String(),
+		Type:     "user",
+		Name:     req.User.UserName,
+		Issuer:   ns,
+		Metadata: map[string]string{metadataSCIM: "true"},
+		// users provisioned by an identity provider login using it rather than a secret
+		Secret: uuid.New().String(),
+	}
+	setUserMetadata(acc, req.User)
+
+	if err := s.Auth.createAccount(acc); err != nil {
+		return err
+	}
+
+	rsp.User = serializeSCIMUser(acc, nil)
+	return nil
+}
+
+// ReadUser returns the user with the given id
+func (s *SCIM) ReadUser(ctx context.Context, req *pb.ReadSCIMUserRequest, rsp *pb.SCIMUserResponse) error {
+	ns, err := s.authorize(ctx, &req.Options, "auth.SCIM.ReadUser")
+	if err != nil {
+		return err
+	}
+
+	acc, err := s.readUser(ns, req.Id, "auth.SCIM.ReadUser")
+	if err != nil {
+		return err
+	}
+	groups, err := s.listGroups(ns)
+	if err != nil {
+		return errors.InternalServerError("auth.SCIM.ReadUser", "Unable to read groups: %v", err)
+	}
+
+	rsp.User = serializeSCIMUser(acc, groups)
+	return nil
+}
+
+// UpdateUser replaces the attributes of the user. Users which are set to inactive can no longer
+// generate tokens.
+func (s *SCIM) UpdateUser(ctx context.Context, req *pb.SCIMUserRequest, rsp *pb.SCIMUserResponse) error {
+	ns, err := s.authorize(ctx, &req.Options, "auth.SCIM.UpdateUser")
+	if err != nil {
+		return err
+	}
+	if req.User == nil || len(req.User.Id) == 0 {
+		return errors.BadRequest("auth.SCIM.UpdateUser", "Missing id")
+	}
+	if len(req.User.UserName) == 0 {
+		return errors.BadRequest("auth.SCIM.UpdateUser", "Missing userName")
+	}
+
+	acc, err := s.readUser(ns, req.User.Id, "auth.SCIM.UpdateUser")
+	if err != nil {
+		return err
+	}
+
+	oldName := acc.Name
+	if req.User.UserName != oldName && s.nameTaken(ns, req.User.UserName) {
+		return errors.Conflict("auth.SCIM.UpdateUser", "User with userName %v already exists", req.User.UserName)
+	}
+	acc.Name = req.User.UserName
+	setUserMetadata(acc, req.User)
+
+	if err := s.writeAccount(acc, oldName); err != nil {
+		return errors.InternalServerError("auth.SCIM.UpdateUser", "Unable to write account: %v", err)
+	}
+
+	groups, err := s.listGroups(ns)
+	if err != nil {
+		return errors.InternalServerError("auth.SCIM.UpdateUser", "Unable to read groups: %v", err)
+	}
+
+	rsp.User = serializeSCIMUser(acc, groups)
+	return nil
+}
+
+// DeleteUser deprovisions the user, deleting the account and removing it from any groups
+func (s *SCIM) DeleteUser(ctx context.Context, req *pb.DeleteSCIMUserRequest, rsp *pb.DeleteSCIMUserResponse) error {
+	ns, err := s.authorize(ctx, &req.Options, "auth.SCIM.DeleteUser")
+	if err != nil {
+		return err
+	}
+
+	acc, err := s.readUser(ns, req.Id, "auth.SCIM.DeleteUser")
+	if err != nil {
+		return err
+	}
+
+	// remove the user from the groups they're a member of
+	groups, err := s.listGroups(ns)
+	if err != nil {
+		return errors.InternalServerError("auth.SCIM.DeleteUser", "Unable to read groups: %v", err)
+	}
+	for _, g := range groups {
+		if !hasScope(acc.ID, g.Members) {
+			continue
+		}
+		g.Members = remove(g.Members, acc.ID)
+		if err := s.writeGroup(ns, g); err != nil {
+			return errors.InternalServerError("auth.SCIM.DeleteUser", "Unable to write group: %v", err)
+		}
+	}
+
+	prefix := strings.Join([]string{storePrefixRefreshTokens, ns, acc.ID, ""}, joinKey)
+	keys, err := store.List(store.ListPrefix(prefix))
+	if err != nil {
+		return errors.InternalServerError("auth.SCIM.DeleteUser", "Error finding refresh token: %v", err)
+	}
+	keys = append(keys,
+		strings.Join([]string{storePrefixAccounts, ns, acc.ID}, joinKey),
+		strings.Join([]string{storePrefixAccountsByName, ns, acc.Name}, joinKey),
+	)
+	for _, k := range keys {
+		if err := store.Delete(k); err != nil && err != store.ErrNotFound {
+			return errors.InternalServerError("auth.SCIM.DeleteUser", "Error deleting account: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// ListUsers returns the users provisioned in the namespace, ordered by creation
+func (s *SCIM) ListUsers(ctx context.Context, req *pb.ListSCIMUsersRequest, rsp *pb.ListSCIMUsersResponse) error {
+	ns, err := s.authorize(ctx, &req.Options, "auth.SCIM.ListUsers")
+	if err != nil {
+		return err
+	}
+
+	prefix := strings.Join([]string{storePrefixAccounts, ns, ""}, joinKey)
+	recs, err := store.Read(prefix, store.ReadPrefix())
+	if err != nil {
+		return errors.InternalServerError("auth.SCIM.ListUsers", "Unable to read from store: %v", err)
+	}
+	groups, err := s.listGroups(ns)
+	if err != nil {
+		return errors.InternalServerError("auth.SCIM.ListUsers", "Unable to read groups: %v", err)
+	}
+
+	var users []*pb.SCIMUser
+	for _, rec := range recs {
+		var acc *auth.Account
+		if err := json.Unmarshal(rec.Value, &acc); err != nil {
+			return errors.InternalServerError("auth.SCIM.ListUsers", "Unable to unmarshal account: %v", err)
+		}
+		if acc.Metadata[metadataSCIM] != "true" {
+			continue
+		}
+		// user names are case insensitive
+		if len(req.UserName) > 0 && !strings.EqualFold(req.UserName, acc.Name) {
+			continue
+		}
+		users = append(users, serializeSCIMUser(acc, groups))
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		if users[i].Created == users[j].Created {
+			return users[i].Id < users[j].Id
+		}
+		return users[i].Created < users[j].Created
+	})
+
+	start, end := page(len(users), req.StartIndex, req.Count)
+	rsp.Users = users[start:end]
+	rsp.Total = int64(len(users))
+	return nil
+}
+
+// CreateGroup creates a group, its members are granted the scopes mapped to the group
+func (s *SCIM) CreateGroup(ctx context.Context, req *pb.SCIMGroupRequest, rsp *pb.SCIMGroupResponse) error {
+	ns, err := s.authorize(ctx, &req.Options, "auth.SCIM.CreateGroup")
+	if err != nil {
+		return err
+	}
+	if req.Group == nil || len(req.Group.DisplayName) == 0 {
+		return errors.BadRequest("auth.SCIM.CreateGroup", "Missing displayName")
+	}
+
+	groups, err := s.listGroups(ns)
+	if err != nil {
+		return errors.InternalServerError("auth.SCIM.CreateGroup", "Unable to read groups: %v", err)
+	}
+	if findGroup(groups, req.Group.DisplayName) != nil {
+		return errors.Conflict("auth.SCIM.CreateGroup", "Group with displayName %v already exists", req.Group.DisplayName)
+	}
+
+	now := time.Now().Unix()
+	g := &scimGroup{
+		ID:          uuid.New().String(),
+		ExternalID:  req.Group.ExternalId,
+		DisplayName: req.Group.DisplayName,
+		Members:     req.Group.Members,
+		Created:     now,
+		Updated:     now,
+	}
+	if err := s.writeGroup(ns, g); err != nil {
+		return errors.InternalServerError("auth.SCIM.CreateGroup", "Unable to write group: %v", err)
+	}
+
+	rsp.Group = serializeSCIMGroup(g)
+	return nil
+}
+
+// ReadGroup returns the group with the given id
+func (s *SCIM) ReadGroup(ctx context.Context, req *pb.ReadSCIMGroupRequest, rsp *pb.SCIMGroupResponse) error {
+	ns, err := s.authorize(ctx, &req.Options, "auth.SCIM.ReadGroup")
+	if err != nil {
+		return err
+	}
+
+	g, err := s.readGroup(ns, req.Id, "auth.SCIM.ReadGroup")
+	if err != nil {
+		return err
+	}
+
+	rsp.Group = serializeSCIMGroup(g)
+	return nil
+}
+
+// UpdateGroup replaces the attributes and members of the group. The scopes mapped to the group
+// are left unchanged, members added or removed have their scopes updated.
+func (s *SCIM) UpdateGroup(ctx context.Context, req *pb.SCIMGroupRequest, rsp *pb.SCIMGroupResponse) error {
+	ns, err := s.authorize(ctx, &req.Options, "auth.SCIM.UpdateGroup")
+	if err != nil {
+		return err
+	}
+	if req.Group == nil || len(req.Group.Id) == 0 {
+		return errors.BadRequest("auth.SCIM.UpdateGroup", "Missing id")
+	}
+	if len(req.Group.DisplayName) == 0 {
+		return errors.BadRequest("auth.SCIM.UpdateGroup", "Missing displayName")
+	}
+
+	g, err := s.readGroup(ns, req.Group.Id, "auth.SCIM.UpdateGroup")
+	if err != nil {
+		return err
+	}
+
+	if req.Group.DisplayName != g.DisplayName {
+		groups, err := s.listGroups(ns)
+		if err != nil {
+			return errors.InternalServerError("auth.SCIM.UpdateGroup", "Unable to read groups: %v", err)
+		}
+		if findGroup(groups, req.Group.DisplayName) != nil {
+			return errors.Conflict("auth.SCIM.UpdateGroup", "Group with displayName %v already exists", req.Group.DisplayName)
+		}
+	}
+
+	changed := union(g.Members, req.Group.Members)
+
+	g.ExternalID = req.Group.ExternalId
+	g.DisplayName = req.Group.DisplayName
+	g.Members = req.Group.Members
+	g.Updated = time.Now().Unix()
+	if err := s.writeGroup(ns, g); err != nil {
+		return errors.InternalServerError("auth.SCIM.UpdateGroup", "Unable to write group: %v", err)
+	}
+	if err := s.syncScopes(ns, changed); err != nil {
+		return errors.InternalServerError("auth.SCIM.UpdateGroup", "Unable to update scopes: %v", err)
+	}
+
+	rsp.Group = serializeSCIMGroup(g)
+	return nil
+}
+
+// DeleteGroup deletes the group, its members lose the scopes mapped to it
+func (s *SCIM) DeleteGroup(ctx context.Context, req *pb.DeleteSCIMGroupRequest, rsp *pb.DeleteSCIMGroupResponse) error {
+	ns, err := s.authorize(ctx, &req.Options, "auth.SCIM.DeleteGroup")
+	if err != nil {
+		return err
+	}
+
+	g, err := s.readGroup(ns, req.Id, "auth.SCIM.DeleteGroup")
+	if err != nil {
+		return err
+	}
+
+	key := strings.Join([]string{storePrefixSCIMGroups, ns, g.ID}, joinKey)
+	if err := store.Delete(key); err != nil {
+		return errors.InternalServerError("auth.SCIM.DeleteGroup", "Unable to delete group: %v", err)
+	}
+	if err := s.syncScopes(ns, g.Members); err != nil {
+		return errors.InternalServerError("auth.SCIM.DeleteGroup", "Unable to update scopes: %v", err)
+	}
+
+	return nil
+}
+
+// ListGroups returns the groups in the namespace, ordered by creation
+func (s *SCIM) ListGroups(ctx context.Context, req *pb.ListSCIMGroupsRequest, rsp *pb.ListSCIMGroupsResponse) error {
+	ns, err := s.authorize(ctx, &req.Options, "auth.SCIM.ListGroups")
+	if err != nil {
+		return err
+	}
+
+	groups, err := s.listGroups(ns)
+	if err != nil {
+		return errors.InternalServerError("auth.SCIM.ListGroups", "Unable to read groups: %v", err)
+	}
+
+	var result []*pb.SCIMGroup
+	for _, g := range groups {
+		if len(req.DisplayName) > 0 && !strings.EqualFold(req.DisplayName, g.DisplayName) {
+			continue
+		}
+		result = append(result, serializeSCIMGroup(g))
+	}
+
+	start, end := page(len(result), req.StartIndex, req.Count)
+	rsp.Groups = result[start:end]
+	rsp.Total = int64(len(result))
+	return nil
+}
+
+// MapGroup sets the scopes granted to members of the group
+func (s *SCIM) MapGroup(ctx context.Context, req *pb.MapSCIMGroupRequest, rsp *pb.MapSCIMGroupResponse) error {
+	ns, err := s.authorize(ctx, &req.Options, "auth.SCIM.MapGroup")
+	if err != nil {
+		return err
+	}
+	if len(req.Group) == 0 {
+		return errors.BadRequest("auth.SCIM.MapGroup", "Missing group")
+	}
+
+	groups, err := s.listGroups(ns)
+	if err != nil {
+		return errors.InternalServerError("auth.SCIM.MapGroup", "Unable to read groups: %v", err)
+	}
+	g := findGroup(groups, req.Group)
+	if g == nil {
+		return errors.NotFound("auth.SCIM.MapGroup", "Group not found")
+	}
+
+	g.Scopes = req.Scopes
+	g.Updated = time.Now().Unix()
+	if err := s.writeGroup(ns, g); err != nil {
+		return errors.InternalServerError("auth.SCIM.MapGroup", "Unable to write group: %v", err)
+	}
+	if err := s.syncScopes(ns, g.Members); err != nil {
+		return errors.InternalServerError("auth.SCIM.MapGroup", "Unable to update scopes: %v", err)
+	}
+
+	rsp.Group = serializeSCIMGroup(g)
+	return nil
+}
+
+// authorize sets the default namespace and checks the caller is an admin of it
+func (s *SCIM) authorize(ctx context.Context, opts **pb.Options, method string) (string, error) {
+	if *opts == nil {
+		*opts = &pb.Options{}
+	}
+	if len((*opts).Namespace) == 0 {
+		(*opts).Namespace = namespace.DefaultNamespace
+	}
+	ns := (*opts).Namespace
+	return ns, namespace.AuthorizeAdmin(ctx, ns, method)
+}
+
+func (s *SCIM) nameTaken(ns, name string) bool {
+	key := strings.Join([]string{storePrefixAccountsByName, ns, name}, joinKey)
+	_, err := store.Read(key)
+	return err != store.ErrNotFound
+}
+
+// readUser returns the account for a user provisioned using scim
+func (s *SCIM) readUser(ns, id, method string) (*auth.Account, error) {
+	if len(id) == 0 {
+		return nil, errors.BadRequest(method, "Missing id")
+	}
+	key := strings.Join([]string{storePrefixAccounts, ns, id}, joinKey)
+	recs, err := store.Read(key)
+	if err == store.ErrNotFound {
+		return nil, errors.NotFound(method, "User not found")
+	} else if err != nil {
+		return nil, errors.InternalServerError(method, "Unable to read from store: %v", err)
+	}
+
+	var acc *auth.Account
+	if err := json.Unmarshal(recs[0].Value, &acc); err != nil {
+		return nil, errors.InternalServerError(method, "Unable to unmarshal account: %v", err)
+	}
+	if acc.Metadata[metadataSCIM] != "true" {
+		return nil, errors.NotFound(method, "User not found")
+	}
+	return acc, nil
+}
+
+// writeAccount writes the account and the record used to look it up by name
+func (s *SCIM) writeAccount(acc *auth.Account, oldName string) error {
+	bytes, err := json.Marshal(acc)
+	if err != nil {
+		return err
+	}
+
+	key := strings.Join([]string{storePrefixAccounts, acc.Issuer, acc.ID}, joinKey)
+	if err := store.Write(&store.Record{Key: key, Value: bytes}); err != nil {
+		return err
+	}
+	nameKey := strings.Join([]string{storePrefixAccountsByName, acc.Issuer, acc.Name}, joinKey)
+	if err := store.Write(&store.Record{Key: nameKey, Value: bytes}); err != nil {
+		return err
+	}
+
+	if len(oldName) > 0 && oldName != acc.Name {
+		oldKey := strings.Join([]string{storePrefixAccountsByName, acc.Issuer, oldName}, joinKey)
+		if err := store.Delete(oldKey); err != nil && err != store.ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SCIM) readGroup(ns, id, method string) (*scimGroup, error) {
+	if len(id) == 0 {
+		return nil, errors.BadRequest(method, "Missing id")
+	}
+	key := strings.Join([]string{storePrefixSCIMGroups, ns, id}, joinKey)
+	recs, err := store.Read(key)
+	if err == store.ErrNotFound {
+		return nil, errors.NotFound(method, "Group not found")
+	} else if err != nil {
+		return nil, errors.InternalServerError(method, "Unable to read from store: %v", err)
+	}
+
+	var g *scimGroup
+	if err := json.Unmarshal(recs[0].Value, &g); err != nil {
+		return nil, errors.InternalServerError(method, "Unable to unmarshal group: %v", err)
+	}
+	return g, nil
+}
+
+func (s *SCIM) listGroups(ns string) ([]*scimGroup, error) {
+	prefix := strings.Join([]string{storePrefixSCIMGroups, ns, ""}, joinKey)
+	recs, err := store.Read(prefix, store.ReadPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]*scimGroup, 0, len(recs))
+	for _, rec := range recs {
+		var g *scimGroup
+		if err := json.Unmarshal(rec.Value, &g); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Created == groups[j].Created {
+			return groups[i].ID < groups[j].ID
+		}
+		return groups[i].Created < groups[j].Created
+	})
+	return groups, nil
+}
+
+func (s *SCIM) writeGroup(ns string, g *scimGroup) error {
+	bytes, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+	key := strings.Join([]string{storePrefixSCIMGroups, ns, g.ID}, joinKey)
+	return store.Write(&store.Record{Key: key, Value: bytes})
+}
+
+// syncScopes sets the scopes of the users to the union of the scopes mapped to their groups
+func (s *SCIM) syncScopes(ns string, users []string) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	groups, err := s.listGroups(ns)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range users {
+		acc, err := s.readUser(ns, id, "auth.SCIM")
+		if merr, ok := err.(*errors.Error); ok && merr.Code == 404 {
+			// members may reference users which have since been deleted
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		var scopes []string
+		for _, g := range groups {
+			if hasScope(id, g.Members) {
+				scopes = union(scopes, g.Scopes)
+			}
+		}
+		sort.Strings(scopes)
+		acc.Scopes = scopes
+
+		if err := s.writeAccount(acc, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func setUserMetadata(acc *auth.Account, user *pb.SCIMUser) {
+	if acc.Metadata == nil {
+		acc.Metadata = make(map[string]string)
+	}
+	acc.Metadata[metadataExternalID] = user.ExternalId
+	acc.Metadata[metadataDisplayName] = user.DisplayName
+	acc.Metadata[metadataEmails] = strings.Join(user.Emails, ",")
+	acc.Metadata[metadataActive] = strconv.FormatBool(user.Active)
+	acc.Metadata[metadataUpdated] = fmt.Sprintf("%d", time.Now().Unix())
+}
+
+func serializeSCIMUser(acc *auth.Account, groups []*scimGroup) *pb.SCIMUser {
+	u := &pb.SCIMUser{
+		Id:          acc.ID,
+		ExternalId:  acc.Metadata[metadataExternalID],
+		UserName:    acc.Name,
+		DisplayName: acc.Metadata[metadataDisplayName],
+		Active:      acc.Metadata[metadataActive] != "false",
+		Emails:      slice(acc.Metadata[metadataEmails]),
+	}
+	u.Created, _ = strconv.ParseInt(acc.Metadata["created"], 10, 64)
+	u.Updated, _ = strconv.ParseInt(acc.Metadata[metadataUpdated], 10, 64)
+
+	for _, g := range groups {
+		if hasScope(acc.ID, g.Members) {
+			u.Groups = append(u.Groups, g.ID)
+		}
+	}
+	return u
+}
+
+func serializeSCIMGroup(g *scimGroup) *pb.SCIMGroup {
+	return &pb.SCIMGroup{
+		Id:          g.ID,
+		ExternalId:  g.ExternalID,
+		DisplayName: g.DisplayName,
+		Members:     g.Members,
+		Scopes:      g.Scopes,
+		Created:     g.Created,
+		Updated:     g.Updated,
+	}
+}
+
+// findGroup by id or display name
+func findGroup(groups []*scimGroup, group string) *scimGroup {
+	for _, g := range groups {
+		if g.ID == group || strings.EqualFold(g.DisplayName, group) {
+			return g
+		}
+	}
+	return nil
+}
+
+// page returns the bounds of the page given the 1 based start index and count
+func page(total int, startIndex, count int64) (int, int) {
+	start := int(startIndex) - 1
+	if start < 0 {
+		start = 0
+	}
+	if start > total {
+		start = total
+	}
+	end := total
+	if count > 0 && start+int(count) < total {
+		end = start + int(count)
+	}
+	return start, end
+}
+
+func union(a, b []string) []string {
+	result := append([]string{}, a...)
+	for _, s := range b {
+		if !hasScope(s, result) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func remove(list []string, s string) []string {
+	result := make([]string, 0, len(list))
+	for _, v := range list {
+		if v != s {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func slice(s string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
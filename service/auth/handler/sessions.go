@@ -0,0 +1,365 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/util/auth/namespace"
+)
+
+const (
+	storePrefixSessions      = "session"
+	storePrefixSessionsByKey = "sessionByKey"
+)
+
+var (
+	// SessionExpiry is the maximum lifetime of a session, after which the user must login again
+	SessionExpiry = time.Hour * 24 * 7
+	// SessionIdleTimeout is how long a session can go unused before it expires
+	SessionIdleTimeout = time.Hour * 2
+	// SessionTokenExpiry is the lifetime of the access tokens backing a session. They're kept short
+	// so revoking a session or disabling an account takes effect quickly.
+	SessionTokenExpiry = time.Minute * 10
+
+	// sessionTouchInterval limits how often the last seen time of a session is written to the store
+	sessionTouchInterval = time.Minute
+)
+
+// Sessions manages cookie based sessions for browser clients of the web and api services. The
+// browser only ever holds an opaque key, the access and refresh tokens for the session are kept
+// by the auth service and refreshed as they near expiry.
+type Sessions struct {
+	Auth *Auth
+}
+
+type session struct {
+	ID           string `json:"id"`
+	AccountID    string `json:"accountId"`
+	CSRFToken    string `json:"csrfToken"`
+	AccessToken  string `json:"accessToken"`
+	AccessExpiry int64  `json:"accessExpiry"`
+	RefreshToken string `json:"refreshToken"`
+	Created      int64  `json:"created"`
+	LastSeen     int64  `json:"lastSeen"`
+	Expiry       int64  `json:"expiry"`
+	UserAgent    string `json:"userAgent"`
+	IP           string `json:"ip"`
+	// KeyHash is the hash of the key, so the key lookup can be deleted with the session
+	KeyHash string `json:"keyHash"`
+}
+
+// Create authenticates the account and starts a new session for it
+func (s *Sessions) Create(ctx context.Context, req *pb.CreateSessionRequest, rsp *pb.CreateSessionResponse) error {
+	ns, err := s.authorize(ctx, &req.Options, "auth.Sessions.Create")
+	if err != nil {
+		return err
+	}
+
+	var tokRsp pb.TokenResponse
+	tokReq := &pb.TokenRequest{
+		Id:          req.Id,
+		Secret:      req.Secret,
+		TokenExpiry: int64(SessionTokenExpiry.Seconds()),
+		Options:     req.Options,
+	}
+	if err := s.Auth.Token(ctx, tokReq, &tokRsp); err != nil {
+		return err
+	}
+	acc, err := s.Auth.getAccountForID(req.Id, ns, "auth.Sessions.Create")
+	if err != nil {
+		return err
+	}
+
+	key, err := randomToken()
+	if err != nil {
+		return errors.InternalServerError("auth.Sessions.Create", "Unable to generate session key: %v", err)
+	}
+	csrf, err := randomToken()
+	if err != nil {
+		return errors.InternalServerError("auth.Sessions.Create", "Unable to generate csrf token: %v", err)
+	}
+
+	now := time.Now()
+	sess := &session{
+		ID:           uuid.New().String(),
+		AccountID:    acc.ID,
+		CSRFToken:    csrf,
+		AccessToken:  tokRsp.Token.AccessToken,
+		AccessExpiry: tokRsp.Token.Expiry,
+		RefreshToken: tokRsp.Token.RefreshToken,
+		Created:      now.Unix(),
+		LastSeen:     now.Unix(),
+		Expiry:       now.Add(SessionExpiry).Unix(),
+		UserAgent:    req.UserAgent,
+		IP:           req.Ip,
+		KeyHash:      hashKey(key),
+	}
+	if err := s.writeSession(ns, sess); err != nil {
+		return errors.InternalServerError("auth.Sessions.Create", "Unable to write session: %v", err)
+	}
+	// the lookup expires with the session
+	byKey := strings.Join([]string{storePrefixSessionsByKey, ns, sess.KeyHash}, joinKey)
+	ref := strings.Join([]string{sess.AccountID, sess.ID}, joinKey)
+	rec := &store.Record{Key: byKey, Value: []byte(ref), Expiry: time.Until(time.Unix(sess.Expiry, 0))}
+	if err := store.Write(rec); err != nil {
+		return errors.InternalServerError("auth.Sessions.Create", "Unable to write session: %v", err)
+	}
+
+	rsp.Session = serializeSession(sess)
+	rsp.Key = key
+	rsp.CsrfToken = csrf
+	return nil
+}
+
+// Refresh validates the session for the key, returning an access token for the account. The access
+// token is refreshed if it's close to expiry and the session is revoked if it has expired or been
+// idle for longer than the idle timeout.
+func (s *Sessions) Refresh(ctx context.Context, req *pb.RefreshSessionRequest, rsp *pb.RefreshSessionResponse) error {
+	ns, err := s.authorize(ctx, &req.Options, "auth.Sessions.Refresh")
+	if err != nil {
+		return err
+	}
+	if len(req.Key) == 0 {
+		return errors.BadRequest("auth.Sessions.Refresh", "Missing key")
+	}
+
+	sess, err := s.readSessionForKey(ns, req.Key)
+	if err == store.ErrNotFound {
+		return errors.Unauthorized("auth.Sessions.Refresh", "Session not found")
+	} else if err != nil {
+		return errors.InternalServerError("auth.Sessions.Refresh", "Unable to read session: %v", err)
+	}
+
+	now := time.Now()
+	if now.Unix() > sess.Expiry || now.Sub(time.Unix(sess.LastSeen, 0)) > SessionIdleTimeout {
+		s.deleteSession(ns, sess)
+		return errors.Unauthorized("auth.Sessions.Refresh", "Session expired")
+	}
+
+	dirty := now.Sub(time.Unix(sess.LastSeen, 0)) > sessionTouchInterval
+	if time.Unix(sess.AccessExpiry, 0).Sub(now) < SessionTokenExpiry/2 {
+		var tokRsp pb.TokenResponse
+		tokReq := &pb.TokenRequest{
+			RefreshToken: sess.RefreshToken,
+			TokenExpiry:  int64(SessionTokenExpiry.Seconds()),
+			Options:      req.Options,
+		}
+		if err := s.Auth.Token(ctx, tokReq, &tokRsp); err != nil {
+			// the account was deleted or disabled so the session is no longer valid
+			if merr, ok := err.(*errors.Error); ok && merr.Code < 500 {
+				s.deleteSession(ns, sess)
+				return errors.Unauthorized("auth.Sessions.Refresh", "Session revoked")
+			}
+			return err
+		}
+		sess.AccessToken = tokRsp.Token.AccessToken
+		sess.AccessExpiry = tokRsp.Token.Expiry
+		dirty = true
+	}
+
+	if dirty {
+		sess.LastSeen = now.Unix()
+		if err := s.writeSession(ns, sess); err != nil {
+			return errors.InternalServerError("auth.Sessions.Refresh", "Unable to write session: %v", err)
+		}
+	}
+
+	rsp.Session = serializeSession(sess)
+	rsp.CsrfToken = sess.CSRFToken
+	rsp.AccessToken = sess.AccessToken
+	return nil
+}
+
+// List the sessions in the namespace, optionally filtered by account
+func (s *Sessions) List(ctx context.Context, req *pb.ListSessionsRequest, rsp *pb.ListSessionsResponse) error {
+	ns, err := s.authorize(ctx, &req.Options, "auth.Sessions.List")
+	if err != nil {
+		return err
+	}
+
+	sessions, err := s.listSessions(ns, req.AccountId)
+	if err != nil {
+		return errors.InternalServerError("auth.Sessions.List", "Unable to read sessions: %v", err)
+	}
+
+	rsp.Sessions = make([]*pb.Session, 0, len(sessions))
+	for _, sess := range sessions {
+		rsp.Sessions = append(rsp.Sessions, serializeSession(sess))
+	}
+	sort.Slice(rsp.Sessions, func(i, j int) bool {
+		return rsp.Sessions[i].LastSeen > rsp.Sessions[j].LastSeen
+	})
+	return nil
+}
+
+// Revoke a session by its id or key, or all the sessions for an account
+func (s *Sessions) Revoke(ctx context.Context, req *pb.RevokeSessionRequest, rsp *pb.RevokeSessionResponse) error {
+	ns, err := s.authorize(ctx, &req.Options, "auth.Sessions.Revoke")
+	if err != nil {
+		return err
+	}
+
+	var sessions []*session
+	switch {
+	case len(req.Key) > 0:
+		sess, err := s.readSessionForKey(ns, req.Key)
+		if err == store.ErrNotFound {
+			return nil
+		} else if err != nil {
+			return errors.InternalServerError("auth.Sessions.Revoke", "Unable to read session: %v", err)
+		}
+		sessions = []*session{sess}
+	case len(req.Id) > 0 || len(req.AccountId) > 0:
+		all, err := s.listSessions(ns, req.AccountId)
+		if err != nil {
+			return errors.InternalServerError("auth.Sessions.Revoke", "Unable to read sessions: %v", err)
+		}
+		for _, sess := range all {
+			if len(req.Id) == 0 || sess.ID == req.Id {
+				sessions = append(sessions, sess)
+			}
+		}
+	default:
+		return errors.BadRequest("auth.Sessions.Revoke", "An id, key or account id is required")
+	}
+
+	for _, sess := range sessions {
+		if err := s.deleteSession(ns, sess); err != nil {
+			return errors.InternalServerError("auth.Sessions.Revoke", "Unable to delete session: %v", err)
+		}
+		rsp.Revoked++
+	}
+	return nil
+}
+
+func (s *Sessions) authorize(ctx context.Context, opts **pb.Options, method string) (string, error) {
+	if *opts == nil {
+		*opts = &pb.Options{}
+	}
+	if len((*opts).Namespace) == 0 {
+		(*opts).Namespace = namespace.DefaultNamespace
+	}
+	ns := (*opts).Namespace
+	return ns, namespace.AuthorizeAdmin(ctx, ns, method)
+}
+
+func (s *Sessions) readSessionForKey(ns, key string) (*session, error) {
+	recs, err := store.Read(strings.Join([]string{storePrefixSessionsByKey, ns, hashKey(key)}, joinKey))
+	if err != nil {
+		return nil, err
+	}
+	recs, err = store.Read(strings.Join([]string{storePrefixSessions, ns, string(recs[0].Value)}, joinKey))
+	if err != nil {
+		return nil, err
+	}
+	var sess *session
+	if err := json.Unmarshal(recs[0].Value, &sess); err != nil {
+		return nil, err
+	}
+	sess.KeyHash = hashKey(key)
+	return sess, nil
+}
+
+func (s *Sessions) listSessions(ns, accountID string) ([]*session, error) {
+	comps := []string{storePrefixSessions, ns}
+	if len(accountID) > 0 {
+		// the account can be referenced by name
+		if acc, err := s.Auth.getAccountForID(accountID, ns, "auth.Sessions.List"); err == nil {
+			accountID = acc.ID
+		}
+		comps = append(comps, accountID)
+	}
+	recs, err := store.Read(strings.Join(append(comps, ""), joinKey), store.ReadPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*session, 0, len(recs))
+	for _, rec := range recs {
+		var sess *session
+		if err := json.Unmarshal(rec.Value, &sess); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, sess)
+	}
+	return sessions, nil
+}
+
+func (s *Sessions) writeSession(ns string, sess *session) error {
+	bytes, err := json.Marshal(sess)
+	if err != nil {
+		return err
+	}
+	key := strings.Join([]string{storePrefixSessions, ns, sess.AccountID, sess.ID}, joinKey)
+	return store.Write(&store.Record{
+		Key:    key,
+		Value:  bytes,
+		Expiry: time.Until(time.Unix(sess.Expiry, 0)),
+	})
+}
+
+// deleteSession removes the session and its key lookup. Sessions created before the hash of the
+// key was kept on the session are found by scanning for the lookup which references them.
+func (s *Sessions) deleteSession(ns string, sess *session) error {
+	ref := strings.Join([]string{sess.AccountID, sess.ID}, joinKey)
+	keys := []string{strings.Join([]string{storePrefixSessions, ns, ref}, joinKey)}
+
+	if len(sess.KeyHash) > 0 {
+		keys = append(keys, strings.Join([]string{storePrefixSessionsByKey, ns, sess.KeyHash}, joinKey))
+	} else {
+		prefix := strings.Join([]string{storePrefixSessionsByKey, ns, ""}, joinKey)
+		recs, err := store.Read(prefix, store.ReadPrefix())
+		if err != nil {
+			return err
+		}
+		for _, rec := range recs {
+			if string(rec.Value) == ref {
+				keys = append(keys, rec.Key)
+			}
+		}
+	}
+
+	for _, k := range keys {
+		if err := store.Delete(k); err != nil && err != store.ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// hashKey returns the hash of a session key, the key itself is never stored
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func serializeSession(s *session) *pb.Session {
+	return &pb.Session{
+		Id:        s.ID,
+		AccountId: s.AccountID,
+		Created:   s.Created,
+		LastSeen:  s.LastSeen,
+		Expiry:    s.Expiry,
+		UserAgent: s.UserAgent,
+		Ip:        s.IP,
+	}
+}
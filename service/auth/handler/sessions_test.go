@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupSessionsTest(t *testing.T) (*Sessions, context.Context) {
+	setupLockoutTest(t)
+	a := &Auth{DisableAdmin: true}
+	a.Init()
+	acc := &Accounts{Auth: a}
+	assert.Nil(t, acc.createAccount(&auth.Account{ID: "1", Name: "john", Type: "user", Issuer: "micro", Secret: "secret"}))
+
+	ctx := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "web", Type: "service", Issuer: "micro", Scopes: []string{"service"}})
+	return &Sessions{Auth: a}, ctx
+}
+
+// keyLookups returns the number of key lookups in the store
+func keyLookups(t *testing.T) int {
+	recs, err := store.Read(strings.Join([]string{storePrefixSessionsByKey, "micro", ""}, joinKey), store.ReadPrefix())
+	assert.Nil(t, err)
+	return len(recs)
+}
+
+func TestCreateSession(t *testing.T) {
+	s, ctx := setupSessionsTest(t)
+
+	err := s.Create(ctx, &pb.CreateSessionRequest{Id: "john", Secret: "wrong"}, &pb.CreateSessionResponse{})
+	assert.NotNil(t, err)
+
+	var rsp pb.CreateSessionResponse
+	assert.Nil(t, s.Create(ctx, &pb.CreateSessionRequest{Id: "john", Secret: "secret", UserAgent: "firefox"}, &rsp))
+	assert.NotEmpty(t, rsp.Key)
+	assert.NotEmpty(t, rsp.CsrfToken)
+	assert.Equal(t, "1", rsp.Session.AccountId)
+	assert.Equal(t, "firefox", rsp.Session.UserAgent)
+
+	// the key isn't stored and its lookup expires with the session
+	recs, err := store.Read(strings.Join([]string{storePrefixSessionsByKey, "micro", hashKey(rsp.Key)}, joinKey))
+	assert.Nil(t, err)
+	assert.True(t, recs[0].Expiry > 0 && recs[0].Expiry <= SessionExpiry)
+
+	// only admins and services can create sessions
+	user := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "1", Type: "user", Issuer: "micro"})
+	err = s.Create(user, &pb.CreateSessionRequest{Id: "john", Secret: "secret"}, &pb.CreateSessionResponse{})
+	assert.Equal(t, int32(http.StatusUnauthorized), errors.FromError(err).Code)
+}
+
+func TestRefreshSession(t *testing.T) {
+	s, ctx := setupSessionsTest(t)
+
+	var crsp pb.CreateSessionResponse
+	assert.Nil(t, s.Create(ctx, &pb.CreateSessionRequest{Id: "john", Secret: "secret"}, &crsp))
+
+	var rsp pb.RefreshSessionResponse
+	assert.Nil(t, s.Refresh(ctx, &pb.RefreshSessionRequest{Key: crsp.Key}, &rsp))
+	assert.NotEmpty(t, rsp.AccessToken)
+	assert.Equal(t, crsp.CsrfToken, rsp.CsrfToken)
+	assert.Equal(t, crsp.Session.Id, rsp.Session.Id)
+
+	err := s.Refresh(ctx, &pb.RefreshSessionRequest{}, &pb.RefreshSessionResponse{})
+	assert.Equal(t, int32(http.StatusBadRequest), errors.FromError(err).Code)
+	err = s.Refresh(ctx, &pb.RefreshSessionRequest{Key: "foo"}, &pb.RefreshSessionResponse{})
+	assert.Equal(t, int32(http.StatusUnauthorized), errors.FromError(err).Code)
+}
+
+func TestSessionIdleTimeout(t *testing.T) {
+	s, ctx := setupSessionsTest(t)
+
+	var crsp pb.CreateSessionResponse
+	assert.Nil(t, s.Create(ctx, &pb.CreateSessionRequest{Id: "john", Secret: "secret"}, &crsp))
+
+	// sessions which have been idle for too long are revoked
+	defer func(d time.Duration) { SessionIdleTimeout = d }(SessionIdleTimeout)
+	SessionIdleTimeout = -time.Second
+	err := s.Refresh(ctx, &pb.RefreshSessionRequest{Key: crsp.Key}, &pb.RefreshSessionResponse{})
+	assert.Equal(t, int32(http.StatusUnauthorized), errors.FromError(err).Code)
+	assert.Equal(t, 0, keyLookups(t))
+
+	var lrsp pb.ListSessionsResponse
+	assert.Nil(t, s.List(ctx, &pb.ListSessionsRequest{}, &lrsp))
+	assert.Len(t, lrsp.Sessions, 0)
+}
+
+func TestRevokeSession(t *testing.T) {
+	s, ctx := setupSessionsTest(t)
+
+	var first, second, third pb.CreateSessionResponse
+	assert.Nil(t, s.Create(ctx, &pb.CreateSessionRequest{Id: "john", Secret: "secret"}, &first))
+	assert.Nil(t, s.Create(ctx, &pb.CreateSessionRequest{Id: "john", Secret: "secret"}, &second))
+	assert.Nil(t, s.Create(ctx, &pb.CreateSessionRequest{Id: "john", Secret: "secret"}, &third))
+
+	err := s.Revoke(ctx, &pb.RevokeSessionRequest{}, &pb.RevokeSessionResponse{})
+	assert.Equal(t, int32(http.StatusBadRequest), errors.FromError(err).Code)
+
+	// revoking by id removes the key lookup of the session
+	var rsp pb.RevokeSessionResponse
+	assert.Nil(t, s.Revoke(ctx, &pb.RevokeSessionRequest{Id: first.Session.Id}, &rsp))
+	assert.Equal(t, int64(1), int64(rsp.Revoked))
+	assert.Equal(t, 2, keyLookups(t))
+	err = s.Refresh(ctx, &pb.RefreshSessionRequest{Key: first.Key}, &pb.RefreshSessionResponse{})
+	assert.Equal(t, int32(http.StatusUnauthorized), errors.FromError(err).Code)
+	assert.Nil(t, s.Refresh(ctx, &pb.RefreshSessionRequest{Key: second.Key}, &pb.RefreshSessionResponse{}))
+
+	// and by key
+	assert.Nil(t, s.Revoke(ctx, &pb.RevokeSessionRequest{Key: second.Key}, &pb.RevokeSessionResponse{}))
+	assert.Equal(t, 1, keyLookups(t))
+
+	// revoking by account removes every session of the account
+	rsp = pb.RevokeSessionResponse{}
+	assert.Nil(t, s.Revoke(ctx, &pb.RevokeSessionRequest{AccountId: "john"}, &rsp))
+	assert.Equal(t, int64(1), int64(rsp.Revoked))
+	assert.Equal(t, 0, keyLookups(t))
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/util/auth/namespace"
+	"github.com/micro/micro/v3/util/auth/token"
+)
+
+const (
+	storePrefixSigningKeys = "signingkey"
+
+	// signingKeyBits is the size of the RSA keys generated for namespaces
+	signingKeyBits = 2048
+)
+
+// SigningKeyGrace is how long the previous key of a namespace verifies tokens after it's rotated,
+// so the tokens it signed can be refreshed
+var SigningKeyGrace = time.Hour * 24
+
+// SigningKeys manages the keys which sign the tokens issued by namespaces. A namespace uses the
+// default key until its key is first rotated, from then on its tokens are signed with its own key
+// and only verified with the keys of the namespace, so a compromised key can be rotated without
+// invalidating the tokens of other namespaces. It's also the source of keys for the token provider.
+type SigningKeys struct{}
+
+type signingKey struct {
+	ID         string `json:"id"`
+	Namespace  string `json:"namespace"`
+	PublicKey  string `json:"publicKey"`
+	PrivateKey string `json:"privateKey"`
+	Created    int64  `json:"created"`
+	Active     bool   `json:"active"`
+	Expiry     int64  `json:"expiry"`
+}
+
+// Rotate generates a new key for the namespace. The previous keys stop signing tokens and are either
+// revoked or expire after the grace period.
+func (s *SigningKeys) Rotate(ctx context.Context, req *pb.RotateSigningKeyRequest, rsp *pb.RotateSigningKeyResponse) error {
+	if req.Options == nil {
+		req.Options = &pb.Options{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+	ns := req.Options.Namespace
+	if err := namespace.AuthorizeAdmin(ctx, ns, "auth.SigningKeys.Rotate"); err != nil {
+		return err
+	}
+
+	key, err := generateSigningKey(ns)
+	if err != nil {
+		return errors.InternalServerError("auth.SigningKeys.Rotate", "Unable to generate key: %v", err)
+	}
+	prev, err := s.readKeys(ns)
+	if err != nil {
+		return errors.InternalServerError("auth.SigningKeys.Rotate", "Unable to read keys: %v", err)
+	}
+	if err := s.writeKey(key); err != nil {
+		return errors.InternalServerError("auth.SigningKeys.Rotate", "Unable to write key: %v", err)
+	}
+
+	for _, k := range prev {
+		if req.Revoke {
+			err = store.Delete(strings.Join([]string{storePrefixSigningKeys, ns, k.ID}, joinKey))
+		} else if k.Active {
+			k.Active = false
+			k.Expiry = time.Now().Add(SigningKeyGrace).Unix()
+			err = s.writeKey(k)
+		}
+		if err != nil && err != store.ErrNotFound {
+			return errors.InternalServerError("auth.SigningKeys.Rotate", "Unable to retire key %v: %v", k.ID, err)
+		}
+	}
+
+	rsp.Key = serializeSigningKey(key)
+	return nil
+}
+
+// List the keys of the namespace, newest first. Only the public keys are returned, services use
+// them to verify the tokens of the namespace.
+func (s *SigningKeys) List(ctx context.Context, req *pb.ListSigningKeysRequest, rsp *pb.ListSigningKeysResponse) error {
+	if req.Options == nil {
+		req.Options = &pb.Options{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+	ns := req.Options.Namespace
+	if err := namespace.Authorize(ctx, ns, "auth.SigningKeys.List"); err != nil {
+		return err
+	}
+
+	keys, err := s.readKeys(ns)
+	if err != nil {
+		return errors.InternalServerError("auth.SigningKeys.List", "Unable to read keys: %v", err)
+	}
+	rsp.Keys = make([]*pb.SigningKey, 0, len(keys))
+	for _, k := range keys {
+		rsp.Keys = append(rsp.Keys, serializeSigningKey(k))
+	}
+	return nil
+}
+
+// SigningKey returns the active key of the namespace, nil is returned if the namespace uses the
+// default key
+func (s *SigningKeys) SigningKey(ns string) (*token.Key, error) {
+	keys, err := s.readKeys(ns)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range keys {
+		if k.Active {
+			return &token.Key{ID: k.ID, Namespace: ns, PublicKey: k.PublicKey, PrivateKey: k.PrivateKey}, nil
+		}
+	}
+	return nil, nil
+}
+
+// VerificationKey returns the key of the namespace with the id if it hasn't been revoked or expired
+func (s *SigningKeys) VerificationKey(ns, id string) (*token.Key, error) {
+	recs, err := store.Read(strings.Join([]string{storePrefixSigningKeys, ns, id}, joinKey))
+	if err == store.ErrNotFound {
+		return nil, token.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	var k *signingKey
+	if err := json.Unmarshal(recs[0].Value, &k); err != nil {
+		return nil, err
+	}
+	if k.Expiry > 0 && time.Now().Unix() > k.Expiry {
+		return nil, token.ErrNotFound
+	}
+	return &token.Key{ID: k.ID, Namespace: ns, PublicKey: k.PublicKey}, nil
+}
+
+// HasKeys returns true if the namespace has keys which haven't expired
+func (s *SigningKeys) HasKeys(ns string) (bool, error) {
+	keys, err := s.readKeys(ns)
+	if err != nil {
+		return false, err
+	}
+	return len(keys) > 0, nil
+}
+
+// readKeys returns the keys of the namespace which haven't expired, newest first
+func (s *SigningKeys) readKeys(ns string) ([]*signingKey, error) {
+	recs, err := store.Read(strings.Join([]string{storePrefixSigningKeys, ns, ""}, joinKey), store.ReadPrefix())
+	if err != nil && err != store.ErrNotFound {
+		return nil, err
+	}
+	keys := make([]*signingKey, 0, len(recs))
+	for _, rec := range recs {
+		var k *signingKey
+		if err := json.Unmarshal(rec.Value, &k); err != nil {
+			return nil, err
+		}
+		if k.Expiry > 0 && time.Now().Unix() > k.Expiry {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Created > keys[j].Created
+	})
+	return keys, nil
+}
+
+func (s *SigningKeys) writeKey(k *signingKey) error {
+	bytes, err := json.Marshal(k)
+	if err != nil {
+		return err
+	}
+	rec := &store.Record{
+		Key:   strings.Join([]string{storePrefixSigningKeys, k.Namespace, k.ID}, joinKey),
+		Value: bytes,
+	}
+	if k.Expiry > 0 {
+		rec.Expiry = time.Until(time.Unix(k.Expiry, 0))
+	}
+	return store.Write(rec)
+}
+
+func generateSigningKey(ns string) (*signingKey, error) {
+	priv, err := rsa.GenerateKey(rand.Reader, signingKeyBits)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &signingKey{
+		ID:         uuid.New().String(),
+		Namespace:  ns,
+		PublicKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub})),
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)})),
+		Created:    time.Now().Unix(),
+		Active:     true,
+	}, nil
+}
+
+func serializeSigningKey(k *signingKey) *pb.SigningKey {
+	return &pb.SigningKey{
+		Id:        k.ID,
+		Namespace: k.Namespace,
+		PublicKey: k.PublicKey,
+		Created:   k.Created,
+		Active:    k.Active,
+		Expiry:    k.Expiry,
+	}
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/store"
+	memstore "github.com/micro/micro/v3/service/store/memory"
+	"github.com/micro/micro/v3/util/auth/token"
+	"github.com/micro/micro/v3/util/auth/token/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigningKeys(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	pubKey, err := ioutil.ReadFile("../../../util/auth/token/jwt/test/sample_key.pub")
+	assert.Nil(t, err)
+	privKey, err := ioutil.ReadFile("../../../util/auth/token/jwt/test/sample_key")
+	assert.Nil(t, err)
+
+	k := &SigningKeys{}
+	p := jwt.NewTokenProvider(
+		token.WithPublicKey(string(pubKey)),
+		token.WithPrivateKey(string(privKey)),
+		token.WithKeys(k),
+	)
+	inspector := p.(auth.NamespaceInspector)
+
+	generate := func(ns string) string {
+		tok, err := p.Generate(&auth.Account{ID: "john", Issuer: ns})
+		assert.Nil(t, err)
+		return tok.Token
+	}
+
+	// namespaces use the default key until their key is rotated
+	fooDefault := generate("foo")
+	bar := generate("bar")
+	_, err = inspector.InspectNamespace(fooDefault, "foo")
+	assert.Nil(t, err)
+
+	admin := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "admin", Type: "user", Issuer: "foo", Scopes: []string{"admin"}})
+	user := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "john", Type: "user", Issuer: "foo"})
+	opts := &pb.Options{Namespace: "foo"}
+
+	assert.NotNil(t, k.Rotate(user, &pb.RotateSigningKeyRequest{Options: opts}, &pb.RotateSigningKeyResponse{}))
+	var rsp pb.RotateSigningKeyResponse
+	assert.Nil(t, k.Rotate(admin, &pb.RotateSigningKeyRequest{Options: opts}, &rsp))
+	assert.True(t, rsp.Key.Active)
+
+	// tokens are verified with the keys of the namespace the call was made to
+	foo := generate("foo")
+	acc, err := inspector.InspectNamespace(foo, "foo")
+	assert.Nil(t, err)
+	assert.Equal(t, "foo", acc.Issuer)
+	_, err = inspector.InspectNamespace(foo, "bar")
+	assert.Equal(t, token.ErrInvalidToken, err)
+	_, err = p.Inspect(foo)
+	assert.Nil(t, err)
+
+	// the previous key verifies tokens until it expires
+	assert.Nil(t, k.Rotate(admin, &pb.RotateSigningKeyRequest{Options: opts}, &pb.RotateSigningKeyResponse{}))
+	var lrsp pb.ListSigningKeysResponse
+	assert.Nil(t, k.List(user, &pb.ListSigningKeysRequest{Options: opts}, &lrsp))
+	assert.Len(t, lrsp.Keys, 2)
+	assert.True(t, lrsp.Keys[0].Active != lrsp.Keys[1].Active)
+	_, err = inspector.InspectNamespace(foo, "foo")
+	assert.Nil(t, err)
+
+	// revoking the keys invalidates the tokens they signed without affecting other namespaces
+	assert.Nil(t, k.Rotate(admin, &pb.RotateSigningKeyRequest{Options: opts, Revoke: true}, &pb.RotateSigningKeyResponse{}))
+	_, err = inspector.InspectNamespace(foo, "foo")
+	assert.Equal(t, token.ErrInvalidToken, err)
+	_, err = inspector.InspectNamespace(generate("foo"), "foo")
+	assert.Nil(t, err)
+	_, err = inspector.InspectNamespace(bar, "bar")
+	assert.Nil(t, err)
+}
@@ -57,6 +57,7 @@ func (j *jwtAuth) Init(opts ...auth.Option) {
 	j.token = jwt.NewTokenProvider(
 		token.WithPrivateKey(j.options.PrivateKey),
 		token.WithPublicKey(j.options.PublicKey),
+		token.WithSigner(j.options.Signer),
 	)
 }
 
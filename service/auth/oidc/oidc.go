@@ -0,0 +1,51 @@
+// Package oidc verifies the id tokens issued by OpenID Connect identity providers, so users can
+// log in with an external provider such as Keycloak, Auth0 or Google
+package oidc
+
+import (
+	"github.com/micro/micro/v3/service/auth"
+)
+
+// NewAuth returns an auth which verifies the tokens issued by the provider and passes every other
+// request through to the auth provided
+func NewAuth(a auth.Auth, p *Provider) auth.Auth {
+	return &oidcAuth{Auth: a, provider: p}
+}
+
+type oidcAuth struct {
+	auth.Auth
+	provider *Provider
+}
+
+func (o *oidcAuth) String() string {
+	return "oidc"
+}
+
+// Inspect verifies the tokens of the provider locally and the rest with the auth wrapped
+func (o *oidcAuth) Inspect(token string) (*auth.Account, error) {
+	if o.provider.Issues(token) {
+		return o.provider.Inspect(token)
+	}
+	return o.Auth.Inspect(token)
+}
+
+// Token uses the provider to refresh tokens the auth wrapped doesn't recognise, since the refresh
+// tokens of the provider are opaque
+func (o *oidcAuth) Token(opts ...auth.TokenOption) (*auth.AccountToken, error) {
+	tok, err := o.Auth.Token(opts...)
+	if err == nil {
+		return tok, nil
+	}
+
+	var options auth.TokenOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if len(options.RefreshToken) == 0 {
+		return nil, err
+	}
+	if t, perr := o.provider.Refresh(options.RefreshToken); perr == nil {
+		return t, nil
+	}
+	return nil, err
+}
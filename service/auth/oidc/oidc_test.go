@@ -0,0 +1,210 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/auth/noop"
+	"github.com/stretchr/testify/assert"
+)
+
+// testProvider serves the discovery, keys and token endpoints of an identity provider
+type testProvider struct {
+	*httptest.Server
+	key       *rsa.PrivateKey
+	kid       string
+	keyFetch  int
+	verifiers map[string]string
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &testProvider{key: key, kid: "1", verifiers: map[string]string{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.URL,
+			"authorization_endpoint": p.URL + "/authorize",
+			"token_endpoint":         p.URL + "/token",
+			"jwks_uri":               p.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		p.keyFetch++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": p.kid,
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.Form.Get("grant_type") {
+		case "authorization_code":
+			if p.verifiers[r.Form.Get("code")] != r.Form.Get("code_verifier") {
+				json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+				return
+			}
+		case "refresh_token":
+			if r.Form.Get("refresh_token") != "refresh" {
+				json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+				return
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"id_token":      p.sign(t, jwt.MapClaims{"sub": "123", "aud": "micro", "email": "john@example.com"}),
+			"refresh_token": "refresh",
+		})
+	})
+
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+	return p
+}
+
+func (p *testProvider) sign(t *testing.T, claims jwt.MapClaims) string {
+	if _, ok := claims["iss"]; !ok {
+		claims["iss"] = p.URL
+	}
+	if _, ok := claims["exp"]; !ok {
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+	}
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	tok.Header["kid"] = p.kid
+	s, err := tok.SignedString(p.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestInspect(t *testing.T) {
+	tp := newTestProvider(t)
+	p := NewProvider(Issuer(tp.URL), ClientCredentials("micro", ""), Namespace("foo"), ScopeMapping(map[string][]string{
+		"engineers": {"developer"},
+		"leads":     {"developer", "admin"},
+	}))
+
+	acc, err := p.Inspect(tp.sign(t, jwt.MapClaims{
+		"sub":    "123",
+		"aud":    []string{"micro", "other"},
+		"email":  "john@example.com",
+		"groups": []string{"engineers", "leads", "service"},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "123", acc.ID)
+	assert.Equal(t, "john@example.com", acc.Name)
+	assert.Equal(t, "foo", acc.Issuer)
+	assert.Equal(t, []string{"developer", "admin"}, acc.Scopes)
+
+	// the keys are cached
+	_, err = p.Inspect(tp.sign(t, jwt.MapClaims{"sub": "123", "aud": "micro"}))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, tp.keyFetch)
+
+	// the groups of the provider aren't scopes unless they're mapped
+	acc, err = NewProvider(Issuer(tp.URL), ClientCredentials("micro", "")).Inspect(tp.sign(t, jwt.MapClaims{
+		"sub":    "123",
+		"aud":    "micro",
+		"groups": []string{"admin", "service"},
+	}))
+	assert.NoError(t, err)
+	assert.Empty(t, acc.Scopes)
+
+	tests := map[string]jwt.MapClaims{
+		"wrong audience": {"sub": "123", "aud": "other"},
+		"expired":        {"sub": "123", "aud": "micro", "exp": time.Now().Add(-time.Minute).Unix()},
+		"no subject":     {"aud": "micro"},
+	}
+	for name, claims := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := p.Inspect(tp.sign(t, claims))
+			assert.Equal(t, auth.ErrInvalidToken, err)
+		})
+	}
+
+	_, err = p.Inspect(tp.sign(t, jwt.MapClaims{"sub": "123", "aud": "micro", "iss": "https://example.com"}))
+	assert.Equal(t, ErrUnknownIssuer, err)
+}
+
+func TestKeyRotation(t *testing.T) {
+	tp := newTestProvider(t)
+	p := NewProvider(Issuer(tp.URL), ClientCredentials("micro", ""))
+	_, err := p.Inspect(tp.sign(t, jwt.MapClaims{"sub": "123", "aud": "micro"}))
+	assert.NoError(t, err)
+
+	// tokens signed with a new key are verified once the keys are refetched
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	tp.key, tp.kid = key, "2"
+	p.fetched = time.Now().Add(-minKeyRefresh)
+
+	_, err = p.Inspect(tp.sign(t, jwt.MapClaims{"sub": "123", "aud": "micro"}))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, tp.keyFetch)
+}
+
+func TestAuthorizationCode(t *testing.T) {
+	tp := newTestProvider(t)
+	p := NewProvider(Issuer(tp.URL), ClientCredentials("micro", "secret"))
+
+	verifier, challenge := NewVerifier()
+	u, err := p.AuthCodeURL("state", challenge, "http://127.0.0.1/callback")
+	assert.NoError(t, err)
+	parsed, err := url.Parse(u)
+	assert.NoError(t, err)
+	assert.Equal(t, "/authorize", parsed.Path)
+	assert.Equal(t, challenge, parsed.Query().Get("code_challenge"))
+	assert.Equal(t, "state", parsed.Query().Get("state"))
+
+	tp.verifiers["code"] = verifier
+	tok, err := p.Exchange("code", verifier, "http://127.0.0.1/callback")
+	assert.NoError(t, err)
+	assert.Equal(t, "refresh", tok.RefreshToken)
+	assert.False(t, tok.Expired())
+
+	_, err = p.Exchange("code", "wrong", "http://127.0.0.1/callback")
+	assert.Equal(t, auth.ErrInvalidToken, err)
+}
+
+// rejectAuth doesn't recognise the refresh tokens of the provider
+type rejectAuth struct {
+	auth.Auth
+}
+
+func (r *rejectAuth) Token(opts ...auth.TokenOption) (*auth.AccountToken, error) {
+	return nil, auth.ErrInvalidToken
+}
+
+func TestAuth(t *testing.T) {
+	tp := newTestProvider(t)
+	a := NewAuth(&rejectAuth{noop.NewAuth()}, NewProvider(Issuer(tp.URL), ClientCredentials("micro", "")))
+
+	// tokens of the provider are verified with its keys
+	acc, err := a.Inspect(tp.sign(t, jwt.MapClaims{"sub": "123", "aud": "micro"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "123", acc.ID)
+
+	// refresh tokens of the provider are redeemed with it
+	tok, err := a.Token(auth.WithToken("refresh"))
+	assert.NoError(t, err)
+	assert.Equal(t, "refresh", tok.RefreshToken)
+}
@@ -0,0 +1,106 @@
+package oidc
+
+import (
+	"net/http"
+	"time"
+)
+
+// Options of the provider
+type Options struct {
+	// Issuer is the url of the identity provider, e.g. https://accounts.google.com. The provider
+	// configuration is discovered from it.
+	Issuer string
+	// ClientID of the micro client registered with the provider, the id tokens must be issued to it
+	ClientID string
+	// ClientSecret of the client, confidential clients use it to redeem codes
+	ClientSecret string
+	// Scopes requested when logging in
+	Scopes []string
+	// ScopesClaim is the claim of the id tokens the scopes of the accounts are read from, e.g.
+	// groups. The claim can be a list or a space separated string.
+	ScopesClaim string
+	// ScopeMapping maps the values of the scopes claim, e.g. the groups of the identity provider, to
+	// the scopes of the accounts. Values which aren't mapped are ignored, so accounts have no scopes
+	// unless a mapping is set.
+	ScopeMapping map[string][]string
+	// Namespace the accounts of the provider are issued in
+	Namespace string
+	// KeyTTL is how long the signing keys of the provider are cached for
+	KeyTTL time.Duration
+	// Client used to make requests to the provider
+	Client *http.Client
+}
+
+// Option sets an option of the provider
+type Option func(o *Options)
+
+func newOptions(opts ...Option) Options {
+	options := Options{
+		Scopes:      []string{"openid", "profile", "email"},
+		ScopesClaim: "groups",
+		Namespace:   "micro",
+		KeyTTL:      time.Hour,
+		Client:      &http.Client{Timeout: time.Second * 10},
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	return options
+}
+
+// Issuer sets the url of the identity provider
+func Issuer(url string) Option {
+	return func(o *Options) {
+		o.Issuer = url
+	}
+}
+
+// ClientCredentials sets the id and secret of the client registered with the provider
+func ClientCredentials(id, secret string) Option {
+	return func(o *Options) {
+		o.ClientID = id
+		o.ClientSecret = secret
+	}
+}
+
+// Scopes sets the scopes requested when logging in
+func Scopes(scopes ...string) Option {
+	return func(o *Options) {
+		o.Scopes = scopes
+	}
+}
+
+// ScopesClaim sets the claim the scopes of the accounts are read from
+func ScopesClaim(claim string) Option {
+	return func(o *Options) {
+		o.ScopesClaim = claim
+	}
+}
+
+// ScopeMapping sets the scopes the values of the scopes claim map to
+func ScopeMapping(m map[string][]string) Option {
+	return func(o *Options) {
+		o.ScopeMapping = m
+	}
+}
+
+// Namespace sets the namespace the accounts are issued in
+func Namespace(ns string) Option {
+	return func(o *Options) {
+		o.Namespace = ns
+	}
+}
+
+// KeyTTL sets how long the signing keys of the provider are cached for
+func KeyTTL(d time.Duration) Option {
+	return func(o *Options) {
+		o.KeyTTL = d
+	}
+}
+
+// HTTPClient sets the client used to make requests to the provider
+func HTTPClient(c *http.Client) Option {
+	return func(o *Options) {
+		o.Client = c
+	}
+}
@@ -0,0 +1,412 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/micro/micro/v3/service/auth"
+)
+
+var (
+	// DefaultProvider is the identity provider configured with the auth_oidc flags, nil if there
+	// isn't one
+	DefaultProvider *Provider
+
+	// ErrUnknownIssuer is returned when a token wasn't issued by the provider
+	ErrUnknownIssuer = errors.New("token not issued by the provider")
+
+	// minKeyRefresh is how often the keys are fetched when a token is signed with an unknown key
+	minKeyRefresh = time.Second * 10
+	// signingMethods are the algorithms the id tokens can be signed with
+	signingMethods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+)
+
+// configuration is the provider metadata returned by the discovery endpoint
+type configuration struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// jsonWebKey is a public key in the JWK format (RFC 7517)
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// tokenResponse is the response of the token endpoint
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	RefreshToken     string `json:"refresh_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Provider is an OpenID Connect identity provider, e.g. Keycloak, Auth0 or Google. The id tokens
+// it issues are verified with the keys it publishes, which are cached.
+type Provider struct {
+	opts Options
+
+	sync.Mutex
+	config  *configuration
+	keys    map[string]crypto.PublicKey
+	fetched time.Time
+}
+
+// NewProvider returns a provider with the options
+func NewProvider(opts ...Option) *Provider {
+	return &Provider{opts: newOptions(opts...)}
+}
+
+// Options of the provider
+func (p *Provider) Options() Options {
+	return p.opts
+}
+
+// Issues returns true if the token claims to be issued by the provider, the token isn't verified
+func (p *Provider) Issues(token string) bool {
+	claims := jwt.MapClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(token, claims); err != nil {
+		return false
+	}
+	iss, _ := claims["iss"].(string)
+	return len(iss) > 0 && strings.TrimSuffix(iss, "/") == strings.TrimSuffix(p.opts.Issuer, "/")
+}
+
+// Inspect verifies an id token issued by the provider and returns the account it identifies
+func (p *Provider) Inspect(token string) (*auth.Account, error) {
+	if !p.Issues(token) {
+		return nil, ErrUnknownIssuer
+	}
+
+	parser := &jwt.Parser{ValidMethods: signingMethods}
+	claims := jwt.MapClaims{}
+	if _, err := parser.ParseWithClaims(token, claims, p.key); err != nil {
+		return nil, auth.ErrInvalidToken
+	}
+	if !claims.VerifyAudience(p.opts.ClientID, true) {
+		return nil, auth.ErrInvalidToken
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, auth.ErrInvalidToken
+	}
+
+	sub, _ := claims["sub"].(string)
+	if len(sub) == 0 {
+		return nil, auth.ErrInvalidToken
+	}
+	email, _ := claims["email"].(string)
+	name, _ := claims["name"].(string)
+
+	acc := &auth.Account{
+		ID:       sub,
+		Type:     "user",
+		Issuer:   p.opts.Namespace,
+		Scopes:   p.scopes(claims[p.opts.ScopesClaim]),
+		Metadata: map[string]string{"provider": p.opts.Issuer},
+		Name:     sub,
+	}
+	if len(email) > 0 {
+		acc.Name = email
+		acc.Metadata["email"] = email
+	}
+	if len(name) > 0 {
+		acc.Metadata["name"] = name
+	}
+	return acc, nil
+}
+
+// scopes reads the scopes from a claim, which is either a list or a space separated string
+func scopes(claim interface{}) []string {
+	var s []string
+	switch v := claim.(type) {
+	case string:
+		s = strings.Fields(v)
+	case []interface{}:
+		for _, i := range v {
+			if str, ok := i.(string); ok && len(str) > 0 {
+				s = append(s, str)
+			}
+		}
+	}
+	return s
+}
+
+// scopes returns the scopes the values of a claim are mapped to. The values of the claim are chosen
+// by the identity provider, e.g. group names, so they're never used as scopes directly.
+func (p *Provider) scopes(claim interface{}) []string {
+	var s []string
+	seen := map[string]bool{}
+	for _, v := range scopes(claim) {
+		for _, scope := range p.opts.ScopeMapping[v] {
+			if !seen[scope] {
+				seen[scope] = true
+				s = append(s, scope)
+			}
+		}
+	}
+	return s
+}
+
+// key returns the public key a token was signed with
+func (p *Provider) key(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	p.Lock()
+	defer p.Unlock()
+
+	// refetch the keys once they've expired or if the provider has rotated its key
+	key, ok := p.keys[kid]
+	stale := time.Since(p.fetched) > p.opts.KeyTTL
+	if stale || (!ok && time.Since(p.fetched) > minKeyRefresh) {
+		if err := p.fetchKeys(); err != nil {
+			// keep using the cached keys if the provider is unavailable
+			if !ok {
+				return nil, err
+			}
+			return key, nil
+		}
+		key, ok = p.keys[kid]
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// fetchKeys fetches the signing keys of the provider, the lock must be held
+func (p *Provider) fetchKeys() error {
+	config, err := p.configuration()
+	if err != nil {
+		return err
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.get(config.JWKSURI, &set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if len(k.Use) > 0 && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+
+	p.keys = keys
+	p.fetched = time.Now()
+	return nil
+}
+
+// configuration returns the provider metadata, discovering it on first use. The lock must be held.
+func (p *Provider) configuration() (*configuration, error) {
+	if p.config != nil {
+		return p.config, nil
+	}
+
+	var config configuration
+	if err := p.get(strings.TrimSuffix(p.opts.Issuer, "/")+"/.well-known/openid-configuration", &config); err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(config.Issuer, "/") != strings.TrimSuffix(p.opts.Issuer, "/") {
+		return nil, fmt.Errorf("provider issuer %q doesn't match %q", config.Issuer, p.opts.Issuer)
+	}
+	p.config = &config
+	return p.config, nil
+}
+
+func (p *Provider) get(url string, v interface{}) error {
+	rsp, err := p.opts.Client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error fetching %v: %v", url, rsp.Status)
+	}
+	return json.NewDecoder(rsp.Body).Decode(v)
+}
+
+// publicKey decodes the RSA or EC public key
+func (k *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	decode := func(s string) (*big.Int, error) {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetBytes(b), nil
+	}
+
+	switch k.Kty {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// NewVerifier returns a PKCE code verifier and its S256 challenge (RFC 7636)
+func NewVerifier() (string, string) {
+	b := make([]byte, 32)
+	rand.Read(b)
+	verifier := base64.RawURLEncoding.EncodeToString(b)
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL returns the url users are sent to to log in with the provider using the
+// authorization code flow. The provider redirects back to the redirect uri with the code and state.
+func (p *Provider) AuthCodeURL(state, challenge, redirectURI string) (string, error) {
+	p.Lock()
+	config, err := p.configuration()
+	p.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.opts.ClientID},
+		"redirect_uri":          {redirectURI},
+		"scope":                 {strings.Join(p.opts.Scopes, " ")},
+		"state":                 {state},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"S256"},
+	}
+	sep := "?"
+	if strings.Contains(config.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return config.AuthorizationEndpoint + sep + params.Encode(), nil
+}
+
+// Exchange redeems an authorization code for a token. The access token of the token returned is
+// the id token issued by the provider, which is what the services verify.
+func (p *Provider) Exchange(code, verifier, redirectURI string) (*auth.AccountToken, error) {
+	return p.token(url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"code_verifier": {verifier},
+		"redirect_uri":  {redirectURI},
+	})
+}
+
+// Refresh a token using a refresh token issued by the provider
+func (p *Provider) Refresh(refreshToken string) (*auth.AccountToken, error) {
+	tok, err := p.token(url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+	// providers which don't rotate refresh tokens don't return a new one
+	if err == nil && len(tok.RefreshToken) == 0 {
+		tok.RefreshToken = refreshToken
+	}
+	return tok, err
+}
+
+func (p *Provider) token(params url.Values) (*auth.AccountToken, error) {
+	p.Lock()
+	config, err := p.configuration()
+	p.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	params.Set("client_id", p.opts.ClientID)
+	if len(p.opts.ClientSecret) > 0 {
+		params.Set("client_secret", p.opts.ClientSecret)
+	}
+	rsp, err := p.opts.Client.PostForm(config.TokenEndpoint, params)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	var tok tokenResponse
+	if err := json.NewDecoder(rsp.Body).Decode(&tok); err != nil {
+		return nil, fmt.Errorf("error decoding token response: %v", err)
+	}
+	if len(tok.Error) > 0 {
+		if tok.Error == "invalid_grant" {
+			return nil, auth.ErrInvalidToken
+		}
+		return nil, fmt.Errorf("%v: %v", tok.Error, tok.ErrorDescription)
+	}
+	if len(tok.IDToken) == 0 {
+		return nil, errors.New("the provider didn't return an id token")
+	}
+
+	// the expiry of the token is the expiry of the id token
+	if _, err := p.Inspect(tok.IDToken); err != nil {
+		return nil, err
+	}
+	claims := jwt.MapClaims{}
+	new(jwt.Parser).ParseUnverified(tok.IDToken, claims)
+	exp, _ := claims["exp"].(float64)
+
+	return &auth.AccountToken{
+		AccessToken:  tok.IDToken,
+		RefreshToken: tok.RefreshToken,
+		Created:      time.Now(),
+		Expiry:       time.Unix(int64(exp), 0),
+	}, nil
+}
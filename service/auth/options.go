@@ -18,6 +18,7 @@ package auth
 
 import (
 	"context"
+	"crypto"
 	"time"
 
 	"github.com/micro/micro/v3/service/store"
@@ -44,6 +45,8 @@ type Options struct {
 	PublicKey string
 	// PrivateKey for encoding JWTs
 	PrivateKey string
+	// Signer for encoding JWTs in place of the private key, e.g. using a key held in a KMS
+	Signer crypto.Signer
 	// LoginURL is the relative url path where a user can login
 	LoginURL string
 	// Store to back auth
@@ -91,6 +94,13 @@ func PrivateKey(key string) Option {
 	}
 }
 
+// Signer signs JWTs in place of the private key
+func Signer(s crypto.Signer) Option {
+	return func(o *Options) {
+		o.Signer = s
+	}
+}
+
 // Credentials sets the auth credentials
 func Credentials(id, secret string) Option {
 	return func(o *Options) {
@@ -1,6 +1,9 @@
 package server
 
 import (
+	"context"
+	"errors"
+
 	pb "github.com/micro/micro/v3/proto/auth"
 	"github.com/micro/micro/v3/service"
 	"github.com/micro/micro/v3/service/auth"
@@ -8,6 +11,7 @@ import (
 	log "github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/store"
 	mustore "github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/util/auth/cert"
 	"github.com/micro/micro/v3/util/auth/token"
 	"github.com/micro/micro/v3/util/auth/token/jwt"
 	"github.com/urfave/cli/v2"
@@ -20,6 +24,36 @@ var Flags = []cli.Flag{
 		EnvVars: []string{"MICRO_AUTH_DISABLE_ADMIN"},
 		Usage:   "Prevent generation of default accounts in namespaces",
 	},
+	&cli.IntFlag{
+		Name:    "login_throttle_after",
+		EnvVars: []string{"MICRO_AUTH_LOGIN_THROTTLE_AFTER"},
+		Usage:   "Number of failed logins to an account from an ip after which the ip must wait before trying again",
+		Value:   handler.LoginThrottleAfter,
+	},
+	&cli.IntFlag{
+		Name:    "login_max_failures",
+		EnvVars: []string{"MICRO_AUTH_LOGIN_MAX_FAILURES"},
+		Usage:   "Number of failed logins to an account from any ip after which the account is locked",
+		Value:   handler.LoginMaxFailures,
+	},
+	&cli.DurationFlag{
+		Name:    "login_lockout",
+		EnvVars: []string{"MICRO_AUTH_LOGIN_LOCKOUT"},
+		Usage:   "How long an account is locked for after too many failed logins",
+		Value:   handler.LoginLockout,
+	},
+	&cli.DurationFlag{
+		Name:    "audit_retention",
+		EnvVars: []string{"MICRO_AUTH_AUDIT_RETENTION"},
+		Usage:   "How long the audit records of authorized calls are kept for",
+		Value:   handler.AuditRetention,
+	},
+	&cli.DurationFlag{
+		Name:    "signing_key_grace",
+		EnvVars: []string{"MICRO_AUTH_SIGNING_KEY_GRACE"},
+		Usage:   "How long the previous signing key of a namespace verifies tokens after it's rotated",
+		Value:   handler.SigningKeyGrace,
+	},
 }
 
 const (
@@ -34,16 +68,28 @@ func Run(ctx *cli.Context) error {
 		service.Address(address),
 	)
 
+	// configure the protection against brute force logins
+	handler.LoginThrottleAfter = ctx.Int("login_throttle_after")
+	handler.LoginMaxFailures = ctx.Int("login_max_failures")
+	handler.LoginLockout = ctx.Duration("login_lockout")
+	handler.AuditRetention = ctx.Duration("audit_retention")
+	handler.SigningKeyGrace = ctx.Duration("signing_key_grace")
+
 	// setup the handlers
 	ruleH := &handler.Rules{}
+	certH := &handler.Certificates{}
+	keysH := &handler.SigningKeys{}
 	authH := &handler.Auth{
 		DisableAdmin: ctx.Bool("disable_admin"),
 	}
+	certH.Auth = authH
 
-	// setup the auth handler to use JWTs
+	// setup the auth handler to use JWTs, signed with the keys of namespaces once they're rotated
 	authH.TokenProvider = jwt.NewTokenProvider(
 		token.WithPublicKey(auth.DefaultAuth.Options().PublicKey),
 		token.WithPrivateKey(auth.DefaultAuth.Options().PrivateKey),
+		token.WithSigner(auth.DefaultAuth.Options().Signer),
+		token.WithKeys(keysH),
 	)
 
 	// set the handlers store
@@ -54,7 +100,31 @@ func Run(ctx *cli.Context) error {
 	// register handlers
 	pb.RegisterAuthHandler(srv.Server(), authH)
 	pb.RegisterRulesHandler(srv.Server(), ruleH)
-	pb.RegisterAccountsHandler(srv.Server(), authH)
+	pb.RegisterAccountsHandler(srv.Server(), &handler.Accounts{Auth: authH})
+	pb.RegisterOAuthHandler(srv.Server(), &handler.OAuth{Auth: authH})
+	pb.RegisterSCIMHandler(srv.Server(), &handler.SCIM{Auth: authH})
+	pb.RegisterSessionsHandler(srv.Server(), &handler.Sessions{Auth: authH})
+	pb.RegisterAPIKeysHandler(srv.Server(), &handler.APIKeys{Auth: authH})
+	pb.RegisterCertificatesHandler(srv.Server(), certH)
+	pb.RegisterSigningKeysHandler(srv.Server(), keysH)
+
+	// store the audit records published by services
+	auditH := &handler.Audit{Auth: authH}
+	pb.RegisterAuditHandler(srv.Server(), auditH)
+	go auditH.Consume(context.Background())
+
+	// the auth service issues its own certificate rather than calling itself
+	cert.DefaultIssue = func(ctx context.Context, csr []byte) ([]byte, []byte, error) {
+		tok := auth.DefaultAuth.Options().Token
+		if tok == nil {
+			return nil, nil, errors.New("missing token")
+		}
+		acc, err := authH.TokenProvider.Inspect(tok.AccessToken)
+		if err != nil {
+			return nil, nil, err
+		}
+		return certH.Sign(acc, csr)
+	}
 
 	// run service
 	if err := srv.Run(); err != nil {
@@ -8,11 +8,18 @@ import (
 	"github.com/micro/micro/v3/service/client"
 	"github.com/micro/micro/v3/service/context"
 	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/metrics"
+	"github.com/micro/micro/v3/util/backoff"
+	"github.com/micro/micro/v3/util/cost"
 )
 
 var (
 	name    = "broker"
 	address = ":8003"
+
+	// MaxResubscribeBackoff is the longest a subscriber waits between attempts to resubscribe
+	// to the broker
+	MaxResubscribeBackoff = time.Second * 30
 )
 
 type serviceBroker struct {
@@ -46,6 +53,12 @@ func (b *serviceBroker) Options() broker.Options {
 }
 
 func (b *serviceBroker) Publish(topic string, msg *broker.Message, opts ...broker.PublishOption) error {
+	var options broker.PublishOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	defer cost.Record(options.Context, cost.Broker, time.Now())
+
 	if logger.V(logger.DebugLevel, logger.DefaultLogger) {
 		logger.Debugf("Publishing to topic %s broker %v", topic, b.Addrs)
 	}
@@ -97,23 +110,8 @@ func (b *serviceBroker) Subscribe(topic string, handler broker.Handler, opts ...
 					// run the subscriber
 					logger.Debugf("Streaming from broker %v to topic [%s] queue [%s]", b.Addrs, topic, options.Queue)
 				}
-				if err := sub.run(); err != nil {
-					if logger.V(logger.DebugLevel, logger.DefaultLogger) {
-						logger.Debugf("Resubscribing to topic %s broker %v", topic, b.Addrs)
-					}
-					stream, err := b.Client.Subscribe(context.DefaultContext, &pb.SubscribeRequest{
-						Topic: topic,
-						Queue: options.Queue,
-					}, client.WithAuthToken(), client.WithAddress(b.Addrs...), client.WithRequestTimeout(time.Hour))
-					if err != nil {
-						if logger.V(logger.DebugLevel, logger.DefaultLogger) {
-							logger.Debugf("Failed to resubscribe to topic %s: %v", topic, err)
-						}
-						time.Sleep(time.Second)
-						continue
-					}
-					// new stream
-					sub.stream = stream
+				if err := sub.run(); err != nil && !b.resubscribe(sub) {
+					return
 				}
 			}
 		}
@@ -122,6 +120,56 @@ func (b *serviceBroker) Subscribe(topic string, handler broker.Handler, opts ...
 	return sub, nil
 }
 
+// resubscribe to the topic of the subscriber once its stream breaks, e.g. because the broker
+// restarted, retrying with a backoff. It returns false if the subscriber unsubscribes first.
+func (b *serviceBroker) resubscribe(sub *serviceSub) bool {
+	start := time.Now()
+
+	for attempts := 1; ; attempts++ {
+		wait := backoff.Do(attempts)
+		if wait > MaxResubscribeBackoff {
+			wait = MaxResubscribeBackoff
+		}
+		select {
+		case <-sub.closed:
+			return false
+		case <-time.After(wait):
+		}
+
+		if logger.V(logger.DebugLevel, logger.DefaultLogger) {
+			logger.Debugf("Resubscribing to topic %s broker %v", sub.topic, b.Addrs)
+		}
+		stream, err := b.Client.Subscribe(context.DefaultContext, &pb.SubscribeRequest{
+			Topic: sub.topic,
+			Queue: sub.queue,
+		}, client.WithAuthToken(), client.WithAddress(b.Addrs...), client.WithRequestTimeout(time.Hour))
+		if err != nil {
+			if logger.V(logger.DebugLevel, logger.DefaultLogger) {
+				logger.Debugf("Failed to resubscribe to topic %s, attempt %d: %v", sub.topic, attempts, err)
+			}
+			recordResubscribe(sub.topic, "failure")
+			continue
+		}
+
+		recordResubscribe(sub.topic, "success")
+		if metrics.IsSet() {
+			metrics.Timing("broker.subscriber.downtime", time.Since(start), metrics.Tags{"topic": sub.topic})
+		}
+		logger.Infof("Resubscribed to topic %s after %d attempts", sub.topic, attempts)
+
+		// new stream
+		sub.stream = stream
+		return true
+	}
+}
+
+func recordResubscribe(topic, result string) {
+	if !metrics.IsSet() {
+		return
+	}
+	metrics.Count("broker.subscriber.reconnects", 1, metrics.Tags{"topic": topic, "result": result})
+}
+
 func (b *serviceBroker) String() string {
 	return "service"
 }
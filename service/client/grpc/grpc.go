@@ -32,6 +32,8 @@ import (
 	"github.com/micro/micro/v3/service/context/metadata"
 	"github.com/micro/micro/v3/service/errors"
 	raw "github.com/micro/micro/v3/util/codec/bytes"
+	"github.com/micro/micro/v3/util/cost"
+	"github.com/micro/micro/v3/util/idempotency"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -125,6 +127,9 @@ func (g *grpcClient) call(ctx context.Context, addr string, req client.Request,
 		),
 	}
 
+	if d := g.getDialer(req.Service()); d != nil {
+		grpcDialOptions = append(grpcDialOptions, grpc.WithContextDialer(d))
+	}
 	if opts := g.getGrpcDialOptions(); opts != nil {
 		grpcDialOptions = append(grpcDialOptions, opts...)
 	}
@@ -140,10 +145,19 @@ func (g *grpcClient) call(ctx context.Context, addr string, req client.Request,
 
 	ch := make(chan error, 1)
 
+	// the response header contains the cost of the work the service did for the request and
+	// the response metadata, which is in the trailer if the service returned an error
+	var rspMd, rspTrailer gmetadata.MD
+	if _, ok := cost.FromContext(ctx); ok {
+		defer cost.Record(ctx, cost.Client, time.Now())
+	}
+
 	go func() {
 		grpcCallOptions := []grpc.CallOption{
 			grpc.ForceCodec(cf),
-			grpc.CallContentSubtype(cf.Name())}
+			grpc.CallContentSubtype(cf.Name()),
+			grpc.Header(&rspMd),
+			grpc.Trailer(&rspTrailer)}
 		if opts := g.getGrpcCallOptions(); opts != nil {
 			grpcCallOptions = append(grpcCallOptions, opts...)
 		}
@@ -154,6 +168,13 @@ func (g *grpcClient) call(ctx context.Context, addr string, req client.Request,
 	select {
 	case err := <-ch:
 		grr = err
+		md := responseMetadata(rspMd, rspTrailer)
+		if v, ok := md[cost.Header]; ok {
+			cost.Merge(ctx, v)
+		}
+		if opts.ResponseMetadata != nil {
+			*opts.ResponseMetadata = md
+		}
 	case <-ctx.Done():
 		grr = errors.Timeout("go.micro.client", "%v", ctx.Err())
 	}
@@ -202,6 +223,9 @@ func (g *grpcClient) stream(ctx context.Context, addr string, req client.Request
 		),
 	}
 
+	if d := g.getDialer(req.Service()); d != nil {
+		grpcDialOptions = append(grpcDialOptions, grpc.WithContextDialer(d))
+	}
 	if opts := g.getGrpcDialOptions(); opts != nil {
 		grpcDialOptions = append(grpcDialOptions, opts...)
 	}
@@ -381,6 +405,11 @@ func (g *grpcClient) Call(ctx context.Context, req client.Request, rsp interface
 		opt(&callOpts)
 	}
 
+	// set the idempotency key once so every retry of the call is made with it
+	if len(callOpts.IdempotencyKey) > 0 {
+		ctx = metadata.Set(ctx, idempotency.Header, callOpts.IdempotencyKey)
+	}
+
 	// check if we already have a deadline
 	d, ok := ctx.Deadline()
 	if !ok {
@@ -680,6 +709,19 @@ func (g *grpcClient) String() string {
 	return "grpc"
 }
 
+func (g *grpcClient) getDialer(service string) func(context.Context, string) (net.Conn, error) {
+	if g.opts.Context == nil {
+		return nil
+	}
+	d, ok := g.opts.Context.Value(dialerKey{}).(func(context.Context, string, string) (net.Conn, error))
+	if !ok {
+		return nil
+	}
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		return d(ctx, service, addr)
+	}
+}
+
 func (g *grpcClient) getGrpcDialOptions() []grpc.DialOption {
 	if g.opts.CallOptions.Context == nil {
 		return nil
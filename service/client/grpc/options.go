@@ -20,6 +20,7 @@ package grpc
 import (
 	"context"
 	"crypto/tls"
+	"net"
 
 	"github.com/micro/micro/v3/service/client"
 	"google.golang.org/grpc"
@@ -48,6 +49,7 @@ type poolMaxStreams struct{}
 type poolMaxIdle struct{}
 type codecsKey struct{}
 type tlsAuth struct{}
+type dialerKey struct{}
 type maxRecvMsgSizeKey struct{}
 type maxSendMsgSizeKey struct{}
 type grpcDialOptions struct{}
@@ -98,6 +100,17 @@ func AuthTLS(t *tls.Config) client.Option {
 	}
 }
 
+// Dialer sets the func the connections to the servers of a service are dialed with, e.g. to
+// authenticate the client to the servers with mTLS
+func Dialer(fn func(ctx context.Context, service, addr string) (net.Conn, error)) client.Option {
+	return func(o *client.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, dialerKey{}, fn)
+	}
+}
+
 //
 // MaxRecvMsgSize set the maximum size of message that client can receive.
 //
@@ -20,11 +20,13 @@ import (
 	"encoding/json"
 	"strings"
 
+	"github.com/micro/micro/v3/service/context/metadata"
 	"github.com/micro/micro/v3/service/errors"
 	"github.com/micro/micro/v3/util/codec"
 	"github.com/micro/micro/v3/util/codec/bytes"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/encoding"
+	gmetadata "google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -80,3 +82,14 @@ func grpcErrToMicroErr(stat *status.Status) error {
 		errBytes = []byte(merr.Detail)
 	}
 }
+
+// responseMetadata returns the metadata the service returned in the headers and trailers of the response
+func responseMetadata(mds ...gmetadata.MD) metadata.Metadata {
+	hdr := map[string]string{}
+	for _, md := range mds {
+		for k, v := range md {
+			hdr[k] = strings.Join(v, ", ")
+		}
+	}
+	return metadata.FromResponseHeader(hdr)
+}
@@ -34,6 +34,22 @@ func LookupRoute(ctx context.Context, req Request, opts CallOptions) ([]string,
 		return opts.Address, nil
 	}
 
+	routes, err := LookupRoutes(ctx, req, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var addrs []string
+
+	for _, route := range routes {
+		addrs = append(addrs, route.Address)
+	}
+
+	return addrs, nil
+}
+
+// LookupRoutes which can be used to execute the request, sorted by lowest metric first
+func LookupRoutes(ctx context.Context, req Request, opts CallOptions) ([]router.Route, error) {
 	// construct the router query
 	query := []router.LookupOption{}
 
@@ -56,11 +72,5 @@ func LookupRoute(ctx context.Context, req Request, opts CallOptions) ([]string,
 		return routes[i].Metric < routes[j].Metric
 	})
 
-	var addrs []string
-
-	for _, route := range routes {
-		addrs = append(addrs, route.Address)
-	}
-
-	return addrs, nil
+	return routes, nil
 }
@@ -32,6 +32,8 @@ import (
 	"github.com/micro/micro/v3/util/buf"
 	"github.com/micro/micro/v3/util/codec"
 	raw "github.com/micro/micro/v3/util/codec/bytes"
+	"github.com/micro/micro/v3/util/cost"
+	"github.com/micro/micro/v3/util/idempotency"
 	"github.com/micro/micro/v3/util/pool"
 )
 
@@ -172,6 +174,15 @@ func (r *rpcClient) call(ctx context.Context, addr string, req client.Request, r
 
 	select {
 	case err := <-ch:
+		stream.Lock()
+		md := metadata.FromResponseHeader(rsp.header)
+		stream.Unlock()
+		if v, ok := md[cost.Header]; ok {
+			cost.Merge(ctx, v)
+		}
+		if opts.ResponseMetadata != nil {
+			*opts.ResponseMetadata = md
+		}
 		return err
 	case <-ctx.Done():
 		grr = errors.Timeout("go.micro.client", fmt.Sprintf("%v", ctx.Err()))
@@ -325,6 +336,11 @@ func (r *rpcClient) Call(ctx context.Context, request client.Request, response i
 		opt(&callOpts)
 	}
 
+	// set the idempotency key once so every retry of the call is made with it
+	if len(callOpts.IdempotencyKey) > 0 {
+		ctx = metadata.Set(ctx, idempotency.Header, callOpts.IdempotencyKey)
+	}
+
 	// check if we already have a deadline
 	if d, ok := ctx.Deadline(); !ok {
 		// no deadline so we create a new one
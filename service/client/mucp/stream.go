@@ -112,6 +112,11 @@ func (r *rpcStream) Recv(msg interface{}) error {
 		return err
 	}
 
+	// keep the header of the last response, it has the response metadata
+	if rsp, ok := r.response.(*rpcResponse); ok {
+		rsp.header = resp.Header
+	}
+
 	switch {
 	case len(resp.Error) > 0:
 		// We've got an error response. Give this to the request;
@@ -22,6 +22,7 @@ import (
 
 	"github.com/micro/micro/v3/service/broker"
 	"github.com/micro/micro/v3/service/broker/memory"
+	"github.com/micro/micro/v3/service/context/metadata"
 	"github.com/micro/micro/v3/service/network/transport"
 	thttp "github.com/micro/micro/v3/service/network/transport/http"
 	"github.com/micro/micro/v3/service/registry"
@@ -88,6 +89,12 @@ type CallOptions struct {
 	AuthToken bool
 	// Network to lookup the route within
 	Network string
+	// ResponseMetadata is set to the metadata returned with the response
+	ResponseMetadata *metadata.Metadata
+	// CacheKey the response is cached under so it can be invalidated
+	CacheKey string
+	// IdempotencyKey the call is made with, so retries replay its response
+	IdempotencyKey string
 
 	// Middleware for low level call func
 	CallWrappers []CallWrapper
@@ -395,6 +402,31 @@ func WithSelectOptions(sops ...selector.SelectOption) CallOption {
 	}
 }
 
+// ResponseMetadata sets the metadata to the metadata returned with the response, e.g. a
+// pagination cursor set by the handler with server.SetResponseMetadata
+func ResponseMetadata(md *metadata.Metadata) CallOption {
+	return func(o *CallOptions) {
+		o.ResponseMetadata = md
+	}
+}
+
+// WithCacheKey sets the key the response is cached under when the call is cached, so the
+// responses can be invalidated with cache.Delete when the data they were built from changes
+func WithCacheKey(key string) CallOption {
+	return func(o *CallOptions) {
+		o.CacheKey = key
+	}
+}
+
+// WithIdempotencyKey makes the call with the idempotency key, the response is recorded by the
+// service so calls retried with the same key, e.g. after a network error, return the response
+// rather than being made again
+func WithIdempotencyKey(key string) CallOption {
+	return func(o *CallOptions) {
+		o.IdempotencyKey = key
+	}
+}
+
 func WithMessageContentType(ct string) MessageOption {
 	return func(o *MessageOptions) {
 		o.ContentType = ct
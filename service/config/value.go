@@ -189,7 +189,7 @@ func (j *JSONValue) StringSlice(def []string) []string {
 }
 
 func (j *JSONValue) Exists() bool {
-	return false
+	return j.Json != nil && j.Json.Interface() != nil
 }
 
 func (j *JSONValue) StringMap(def map[string]string) map[string]string {
@@ -0,0 +1,16 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONValueExists(t *testing.T) {
+	vals := NewJSONValues([]byte(`{"ratelimit":{"rate":10},"empty":null}`))
+	assert.True(t, vals.Get("ratelimit").Exists())
+	assert.True(t, vals.Get("ratelimit.rate").Exists())
+	assert.False(t, vals.Get("ratelimit.burst").Exists())
+	assert.False(t, vals.Get("missing").Exists())
+	assert.False(t, vals.Get("empty").Exists())
+}
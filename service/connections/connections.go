@@ -0,0 +1,57 @@
+// Package connections pushes messages to the websocket and server sent event clients connected to
+// the api gateway. The connections service tracks which instance of the api holds the clients of
+// each account and delivers the messages via those instances.
+package connections
+
+import (
+	pb "github.com/micro/micro/v3/proto/connections"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+)
+
+const name = "connections"
+
+// PushOptions are the options of a push
+type PushOptions struct {
+	// Namespace of the account
+	Namespace string
+	// Session of the account to push to, every session if blank
+	Session string
+}
+
+// PushOption sets an option of a push
+type PushOption func(o *PushOptions)
+
+// PushNamespace sets the namespace of the account
+func PushNamespace(ns string) PushOption {
+	return func(o *PushOptions) {
+		o.Namespace = ns
+	}
+}
+
+// PushSession only pushes the message to the session of the account
+func PushSession(s string) PushOption {
+	return func(o *PushOptions) {
+		o.Session = s
+	}
+}
+
+// Push the payload to the clients connected as the account, returning the number of connections
+// it was delivered to. Clients which aren't connected don't receive the message.
+func Push(account string, payload []byte, opts ...PushOption) (int64, error) {
+	var options PushOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	rsp, err := pb.NewConnectionsService(name, client.DefaultClient).Push(context.DefaultContext, &pb.PushRequest{
+		Namespace: options.Namespace,
+		Account:   account,
+		Session:   options.Session,
+		Payload:   payload,
+	}, client.WithAuthToken())
+	if err != nil {
+		return 0, err
+	}
+	return rsp.Delivered, nil
+}
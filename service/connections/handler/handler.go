@@ -0,0 +1,164 @@
+// Package handler implements the connections service, which tracks the websocket and server sent
+// event clients held by each api gateway so messages can be pushed to them
+package handler
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/micro/micro/v3/proto/connections"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/util/auth/namespace"
+)
+
+var (
+	table = "connections"
+	// DefaultTTL is how long a connection is tracked for unless it's registered again
+	DefaultTTL = time.Minute
+	// gateway is the name of the service the gateways are registered as
+	gateway = "api"
+)
+
+// Connections implements the connections service
+type Connections struct {
+	// Client used to call the gateways, defaults to client.DefaultClient
+	Client client.Client
+}
+
+func (c *Connections) Register(ctx context.Context, req *pb.RegisterRequest, rsp *pb.RegisterResponse) error {
+	ttl := DefaultTTL
+	if req.Ttl > 0 {
+		ttl = time.Duration(req.Ttl) * time.Second
+	}
+
+	for _, conn := range req.Connections {
+		if len(conn.Namespace) == 0 {
+			conn.Namespace = namespace.DefaultNamespace
+		}
+		if err := namespace.AuthorizeAdmin(ctx, conn.Namespace, "connections.Connections.Register"); err != nil {
+			return err
+		}
+		if len(conn.Id) == 0 || len(conn.Account) == 0 || len(conn.Address) == 0 {
+			return errors.BadRequest("connections.Connections.Register", "Missing id, account or address")
+		}
+
+		rec := store.NewRecord(key(conn.Account, conn.Id), conn)
+		rec.Expiry = ttl
+		if err := store.DefaultStore.Write(rec, store.WriteTo(conn.Namespace, table)); err != nil {
+			return errors.InternalServerError("connections.Connections.Register", "Error registering connection: %v", err)
+		}
+	}
+	return nil
+}
+
+func (c *Connections) Deregister(ctx context.Context, req *pb.DeregisterRequest, rsp *pb.DeregisterResponse) error {
+	if len(req.Namespace) == 0 {
+		req.Namespace = namespace.DefaultNamespace
+	}
+	if err := namespace.AuthorizeAdmin(ctx, req.Namespace, "connections.Connections.Deregister"); err != nil {
+		return err
+	}
+	if len(req.Id) == 0 || len(req.Account) == 0 {
+		return errors.BadRequest("connections.Connections.Deregister", "Missing id or account")
+	}
+
+	err := store.DefaultStore.Delete(key(req.Account, req.Id), store.DeleteFrom(req.Namespace, table))
+	if err != nil && err != store.ErrNotFound {
+		return errors.InternalServerError("connections.Connections.Deregister", "Error deregistering connection: %v", err)
+	}
+	return nil
+}
+
+func (c *Connections) List(ctx context.Context, req *pb.ListRequest, rsp *pb.ListResponse) error {
+	if len(req.Namespace) == 0 {
+		req.Namespace = namespace.DefaultNamespace
+	}
+	if err := namespace.AuthorizeAdmin(ctx, req.Namespace, "connections.Connections.List"); err != nil {
+		return err
+	}
+
+	conns, err := list(req.Namespace, req.Account)
+	if err != nil {
+		return errors.InternalServerError("connections.Connections.List", "Error listing connections: %v", err)
+	}
+	rsp.Connections = conns
+	return nil
+}
+
+func (c *Connections) Push(ctx context.Context, req *pb.PushRequest, rsp *pb.PushResponse) error {
+	if len(req.Namespace) == 0 {
+		req.Namespace = namespace.DefaultNamespace
+	}
+	if err := namespace.AuthorizeAdmin(ctx, req.Namespace, "connections.Connections.Push"); err != nil {
+		return err
+	}
+	if len(req.Account) == 0 {
+		return errors.BadRequest("connections.Connections.Push", "Missing account")
+	}
+
+	conns, err := list(req.Namespace, req.Account)
+	if err != nil {
+		return errors.InternalServerError("connections.Connections.Push", "Error listing connections: %v", err)
+	}
+
+	// the message is delivered by each gateway holding a connection of the account
+	byAddress := map[string][]string{}
+	for _, conn := range conns {
+		if len(req.Session) > 0 && conn.Session != req.Session {
+			continue
+		}
+		byAddress[conn.Address] = append(byAddress[conn.Address], conn.Id)
+	}
+
+	cli := c.Client
+	if cli == nil {
+		cli = client.DefaultClient
+	}
+	gw := pb.NewGatewayService(gateway, cli)
+
+	for addr, ids := range byAddress {
+		drsp, err := gw.Deliver(ctx, &pb.DeliverRequest{Ids: ids, Payload: req.Payload}, client.WithAddress(addr), client.WithAuthToken())
+		if err != nil {
+			// the connections expire if the gateway has stopped
+			logger.Warnf("Error delivering message to gateway %v: %v", addr, err)
+			continue
+		}
+
+		// the gateway no longer holds these connections, e.g. they closed before being deregistered
+		for _, id := range drsp.Missing {
+			store.DefaultStore.Delete(key(req.Account, id), store.DeleteFrom(req.Namespace, table))
+		}
+		rsp.Delivered += int64(len(ids) - len(drsp.Missing))
+	}
+	return nil
+}
+
+func list(ns, account string) ([]*pb.Connection, error) {
+	prefix := ""
+	if len(account) > 0 {
+		prefix = key(account, "")
+	}
+	recs, err := store.Read(prefix, store.ReadPrefix(), store.ReadFrom(ns, table))
+	if err == store.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	conns := make([]*pb.Connection, 0, len(recs))
+	for _, rec := range recs {
+		var conn *pb.Connection
+		if err := rec.Decode(&conn); err != nil {
+			return nil, err
+		}
+		conns = append(conns, conn)
+	}
+	return conns, nil
+}
+
+func key(account, id string) string {
+	return account + "/" + id
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/micro/micro/v3/proto/connections"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/client/mucp"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClient records the messages delivered to each gateway, each gateway only holds the
+// connections in held
+type fakeClient struct {
+	client.Client
+	held      map[string]bool
+	delivered map[string][]string
+}
+
+func (f *fakeClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	var options client.CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	addr := options.Address[0]
+
+	dreq := req.Body().(*pb.DeliverRequest)
+	for _, id := range dreq.Ids {
+		if !f.held[id] {
+			rsp.(*pb.DeliverResponse).Missing = append(rsp.(*pb.DeliverResponse).Missing, id)
+			continue
+		}
+		f.delivered[addr] = append(f.delivered[addr], id)
+	}
+	return nil
+}
+
+func TestConnections(t *testing.T) {
+	store.DefaultStore = memory.NewStore()
+	ctx := auth.ContextWithAccount(context.Background(), &auth.Account{
+		ID: "gateway", Type: "service", Issuer: "micro", Scopes: []string{"service"},
+	})
+
+	f := &fakeClient{
+		Client:    mucp.NewClient(),
+		held:      map[string]bool{"1": true, "2": true},
+		delivered: map[string][]string{},
+	}
+	h := &Connections{Client: f}
+
+	err := h.Register(ctx, &pb.RegisterRequest{Connections: []*pb.Connection{
+		{Id: "1", Account: "alice", Session: "a", Address: "10.0.0.1:8080"},
+		{Id: "2", Account: "alice", Session: "b", Address: "10.0.0.2:8080"},
+		{Id: "3", Account: "alice", Session: "b", Address: "10.0.0.2:8080"},
+		{Id: "4", Account: "bob", Address: "10.0.0.1:8080"},
+	}}, &pb.RegisterResponse{})
+	assert.NoError(t, err)
+
+	err = h.Register(ctx, &pb.RegisterRequest{Connections: []*pb.Connection{{Id: "5"}}}, &pb.RegisterResponse{})
+	assert.Error(t, err)
+
+	lrsp := &pb.ListResponse{}
+	assert.NoError(t, h.List(ctx, &pb.ListRequest{Account: "alice"}, lrsp))
+	assert.Len(t, lrsp.Connections, 3)
+
+	// the gateway no longer holds connection 3 so it's removed
+	prsp := &pb.PushResponse{}
+	assert.NoError(t, h.Push(ctx, &pb.PushRequest{Account: "alice", Payload: []byte("hi")}, prsp))
+	assert.Equal(t, int64(2), prsp.Delivered)
+	assert.Equal(t, []string{"1"}, f.delivered["10.0.0.1:8080"])
+	assert.Equal(t, []string{"2"}, f.delivered["10.0.0.2:8080"])
+
+	lrsp = &pb.ListResponse{}
+	assert.NoError(t, h.List(ctx, &pb.ListRequest{Account: "alice"}, lrsp))
+	assert.Len(t, lrsp.Connections, 2)
+
+	// pushes can be limited to a session
+	prsp = &pb.PushResponse{}
+	assert.NoError(t, h.Push(ctx, &pb.PushRequest{Account: "alice", Session: "a", Payload: []byte("hi")}, prsp))
+	assert.Equal(t, int64(1), prsp.Delivered)
+
+	assert.NoError(t, h.Deregister(ctx, &pb.DeregisterRequest{Id: "1", Account: "alice"}, &pb.DeregisterResponse{}))
+	lrsp = &pb.ListResponse{}
+	assert.NoError(t, h.List(ctx, &pb.ListRequest{Account: "alice"}, lrsp))
+	assert.Len(t, lrsp.Connections, 1)
+
+	// accounts which aren't services or admins can't push
+	userCtx := auth.ContextWithAccount(context.Background(), &auth.Account{ID: "alice", Type: "user", Issuer: "micro"})
+	assert.Error(t, h.Push(userCtx, &pb.PushRequest{Account: "bob", Payload: []byte("hi")}, &pb.PushResponse{}))
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	pb "github.com/micro/micro/v3/proto/connections"
+	"github.com/micro/micro/v3/service"
+	"github.com/micro/micro/v3/service/connections/handler"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	name    = "connections"
+	address = ":8006"
+)
+
+// Run micro connections
+func Run(c *cli.Context) error {
+	srv := service.New(
+		service.Name(name),
+		service.Address(address),
+	)
+
+	pb.RegisterConnectionsHandler(srv.Server(), &handler.Connections{Client: srv.Client()})
+
+	if err := srv.Run(); err != nil {
+		logger.Fatal(err)
+	}
+	return nil
+}
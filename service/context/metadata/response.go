@@ -0,0 +1,31 @@
+package metadata
+
+import (
+	"strings"
+)
+
+// ResponsePrefix is prepended to the keys of the metadata returned with a response, so it's
+// distinguished from the headers of the transport
+const ResponsePrefix = "Micro-Response-"
+
+// ResponseHeader returns the headers the metadata is returned to the caller in
+func ResponseHeader(md Metadata) map[string]string {
+	hdr := make(map[string]string, len(md))
+	for k, v := range md {
+		hdr[ResponsePrefix+k] = v
+	}
+	return hdr
+}
+
+// FromResponseHeader returns the metadata returned in the headers of a response. The keys are
+// canonicalised since some transports lower case them.
+func FromResponseHeader(hdr map[string]string) Metadata {
+	md := Metadata{}
+	for k, v := range hdr {
+		if len(k) <= len(ResponsePrefix) || !strings.EqualFold(k[:len(ResponsePrefix)], ResponsePrefix) {
+			continue
+		}
+		md[strings.Title(k[len(ResponsePrefix):])] = v
+	}
+	return md
+}
@@ -3,13 +3,18 @@ package handler
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	pb "github.com/micro/micro/v3/proto/debug"
 	"github.com/micro/micro/v3/service/debug"
+	"github.com/micro/micro/v3/service/debug/health"
 	"github.com/micro/micro/v3/service/debug/log"
 	"github.com/micro/micro/v3/service/debug/stats"
 	"github.com/micro/micro/v3/service/debug/trace"
+	"github.com/micro/micro/v3/util/admission"
+	"github.com/micro/micro/v3/util/skew"
 )
 
 // NewHandler returns an instance of the Debug Handler
@@ -33,11 +38,51 @@ type Debug struct {
 }
 
 func (d *Debug) Health(ctx context.Context, req *pb.HealthRequest, rsp *pb.HealthResponse) error {
-	rsp.Status = "ok"
+	var status []string
+	for _, f := range health.Run() {
+		status = append(status, fmt.Sprintf("%v: %v", f.Name, f.Error))
+	}
+
+	// the service is unhealthy while its critical dependencies are, the optional ones are only
+	// reported
+	for _, d := range health.CheckDependencies() {
+		if rsp.Dependencies == nil {
+			rsp.Dependencies = map[string]string{}
+		}
+		if d.Error == nil {
+			rsp.Dependencies[d.Name] = "ok"
+			continue
+		}
+		rsp.Dependencies[d.Name] = d.Error.Error()
+		if d.Criticality == health.Critical {
+			status = append(status, fmt.Sprintf("%v: %v", d.Name, d.Error))
+		}
+	}
+
+	if len(status) == 0 {
+		rsp.Status = "ok"
+		return nil
+	}
+	rsp.Status = "unhealthy: " + strings.Join(status, ", ")
 	return nil
 }
 
 func (d *Debug) Stats(ctx context.Context, req *pb.StatsRequest, rsp *pb.StatsResponse) error {
+	// the clock offsets are observed by the skew client wrapper
+	rsp.ClockSkew = skew.DefaultDetector.Max().Milliseconds()
+	for _, p := range skew.DefaultDetector.Peers() {
+		if rsp.ClockSkews == nil {
+			rsp.ClockSkews = map[string]int64{}
+		}
+		rsp.ClockSkews[p.Service] = p.Offset.Milliseconds()
+	}
+
+	// the queue of the requests waiting to be handled
+	adm := admission.DefaultController.Stats()
+	rsp.InFlight = uint64(adm.InFlight)
+	rsp.Queued = uint64(adm.Queued)
+	rsp.Rejected = adm.Rejected
+
 	stats, err := d.stats.Read()
 	if err != nil {
 		return err
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strings"
+
+	pb "github.com/micro/micro/v3/proto/debug"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/server"
+)
+
+// Middleware returns the wrappers active in the process, in the order requests pass through them
+func (d *Debug) Middleware(ctx context.Context, req *pb.MiddlewareRequest, rsp *pb.MiddlewareResponse) error {
+	rsp.Client = clientChain(client.DefaultClient)
+
+	for _, w := range client.DefaultClient.Options().CallOptions.CallWrappers {
+		rsp.Call = append(rsp.Call, funcName(w))
+	}
+
+	opts := server.DefaultServer.Options()
+	for _, w := range opts.HdlrWrappers {
+		rsp.Handler = append(rsp.Handler, funcName(w))
+	}
+	for _, w := range opts.SubWrappers {
+		rsp.Subscriber = append(rsp.Subscriber, funcName(w))
+	}
+
+	return nil
+}
+
+var clientType = reflect.TypeOf((*client.Client)(nil)).Elem()
+
+// clientChain walks the wrappers of the client, which embed the client they wrap, ending with the
+// name of the client implementation
+func clientChain(c client.Client) []string {
+	var chain []string
+
+	for c != nil {
+		v := reflect.ValueOf(c)
+		if v.Kind() == reflect.Ptr {
+			v = v.Elem()
+		}
+
+		var next client.Client
+		if v.Kind() == reflect.Struct {
+			for i := 0; i < v.NumField(); i++ {
+				f := v.Field(i)
+				if f.Type() != clientType || !f.CanInterface() || f.IsNil() {
+					continue
+				}
+				next = f.Interface().(client.Client)
+				break
+			}
+		}
+
+		if next == nil {
+			chain = append(chain, c.String())
+			break
+		}
+		chain = append(chain, strings.TrimPrefix(reflect.TypeOf(c).String(), "*"))
+		c = next
+	}
+
+	return chain
+}
+
+// funcName returns the name of the function which created the wrapper, e.g. wrapper.AuthHandler
+func funcName(fn interface{}) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return "unknown"
+	}
+	name := f.Name()
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		name = name[idx+1:]
+	}
+	// remove the suffix of the closure returned
+	if idx := strings.Index(name, ".func"); idx >= 0 {
+		name = name[:idx]
+	}
+	return name
+}
@@ -0,0 +1,145 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Criticality of a dependency decides if the service is ready while the dependency is unhealthy
+type Criticality string
+
+const (
+	// Critical dependencies are needed to handle requests, the service isn't ready while any of
+	// them are unhealthy so traffic isn't routed to it
+	Critical Criticality = "critical"
+	// Optional dependencies are reported by the health check but the service stays ready while
+	// they're unhealthy
+	Optional Criticality = "optional"
+)
+
+// DependencyTimeout is the longest the check of a dependency can take before it fails
+var DependencyTimeout = time.Second * 5
+
+type dependency struct {
+	criticality Criticality
+	fn          CheckFunc
+}
+
+var (
+	depMtx sync.RWMutex
+	deps   = map[string]*dependency{}
+	// criticality configured for dependencies, which overrides the criticality they're
+	// registered with
+	overrides = map[string]Criticality{}
+)
+
+// RegisterDependency registers the check of a dependency of the service, replacing any dependency
+// registered with the name
+func RegisterDependency(name string, c Criticality, fn CheckFunc) {
+	depMtx.Lock()
+	defer depMtx.Unlock()
+	deps[name] = &dependency{criticality: c, fn: fn}
+}
+
+// DeregisterDependency deregisters the dependency with the name
+func DeregisterDependency(name string) {
+	depMtx.Lock()
+	defer depMtx.Unlock()
+	delete(deps, name)
+}
+
+// SetCriticality sets the criticality of a dependency, overriding the criticality it's registered
+// with, e.g. to make the store optional for a service which can handle requests without it
+func SetCriticality(name string, c Criticality) {
+	depMtx.Lock()
+	defer depMtx.Unlock()
+	overrides[name] = c
+}
+
+// ParseCriticality parses the criticality of a dependency in the format name=criticality, e.g.
+// store=optional
+func ParseCriticality(s string) (string, Criticality, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 {
+		return "", "", fmt.Errorf("invalid dependency %q, must be in the format name=criticality", s)
+	}
+	switch c := Criticality(parts[1]); c {
+	case Critical, Optional:
+		return parts[0], c, nil
+	default:
+		return "", "", fmt.Errorf("invalid criticality %q, must be %v or %v", parts[1], Critical, Optional)
+	}
+}
+
+// Dependency is the result of the check of a dependency
+type Dependency struct {
+	Name        string
+	Criticality Criticality
+	// Error is nil if the dependency is healthy
+	Error error
+}
+
+// CheckDependencies runs the checks of the dependencies concurrently, returning their results
+// sorted by name. Checks which don't return before the DependencyTimeout fail.
+func CheckDependencies() []*Dependency {
+	depMtx.RLock()
+	results := make([]*Dependency, 0, len(deps))
+	fns := make([]CheckFunc, 0, len(deps))
+	for name, d := range deps {
+		c := d.criticality
+		if o, ok := overrides[name]; ok {
+			c = o
+		}
+		results = append(results, &Dependency{Name: name, Criticality: c})
+		fns = append(fns, d.fn)
+	}
+	depMtx.RUnlock()
+
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(d *Dependency, fn CheckFunc) {
+			defer wg.Done()
+			d.Error = check(fn)
+		}(results[i], fns[i])
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+// check runs the check, failing it once the timeout has passed
+func check(fn CheckFunc) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fn()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-time.After(DependencyTimeout):
+		return fmt.Errorf("check timed out after %v", DependencyTimeout)
+	}
+}
+
+// Ready returns an error if any of the critical dependencies are unhealthy
+func Ready(ctx context.Context) error {
+	var failed []string
+	for _, d := range CheckDependencies() {
+		if d.Error != nil && d.Criticality == Critical {
+			failed = append(failed, fmt.Sprintf("%v: %v", d.Name, d.Error))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("critical dependencies are unhealthy: %v", strings.Join(failed, ", "))
+	}
+	return nil
+}
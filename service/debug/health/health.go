@@ -0,0 +1,59 @@
+// Package health is the registry of the health checks of a service. The checks are run by the
+// Debug.Health endpoint, which reports the service is unhealthy if any of them fail. The checks of
+// the dependencies of the service are registered with their criticality, the service isn't ready
+// to handle requests while any of its critical dependencies are unhealthy.
+package health
+
+import (
+	"sort"
+	"sync"
+)
+
+// CheckFunc returns an error if the service is unhealthy
+type CheckFunc func() error
+
+var (
+	mtx    sync.RWMutex
+	checks = map[string]CheckFunc{}
+)
+
+// Register the check with the name, replacing any check registered with the name
+func Register(name string, fn CheckFunc) {
+	mtx.Lock()
+	defer mtx.Unlock()
+	checks[name] = fn
+}
+
+// Deregister the check with the name
+func Deregister(name string) {
+	mtx.Lock()
+	defer mtx.Unlock()
+	delete(checks, name)
+}
+
+// Failure of a check
+type Failure struct {
+	Name  string
+	Error error
+}
+
+// Run the checks, returning the failures sorted by name
+func Run() []*Failure {
+	mtx.RLock()
+	fns := make(map[string]CheckFunc, len(checks))
+	for k, v := range checks {
+		fns[k] = v
+	}
+	mtx.RUnlock()
+
+	var failures []*Failure
+	for name, fn := range fns {
+		if err := fn(); err != nil {
+			failures = append(failures, &Failure{Name: name, Error: err})
+		}
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].Name < failures[j].Name
+	})
+	return failures
+}
@@ -0,0 +1,97 @@
+package trace
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/micro/micro/v3/service/context/metadata"
+)
+
+// B3 headers set by zipkin instrumented systems, e.g. envoy, as the metadata keys are normalised
+const (
+	// B3Key is the single header format {trace id}-{span id}-{sampled}-{parent span id}
+	B3Key             = "B3"
+	B3TraceIDKey      = "X-B3-Traceid"
+	B3SpanIDKey       = "X-B3-Spanid"
+	B3ParentSpanIDKey = "X-B3-Parentspanid"
+	B3SampledKey      = "X-B3-Sampled"
+	B3FlagsKey        = "X-B3-Flags"
+)
+
+// PropagateB3 sets the B3 headers on outbound requests as well as the traceparent. They're always
+// set if the inbound request had B3 headers, so systems which only understand B3 join the trace.
+var PropagateB3 = false
+
+// fromB3 returns the trace and span ids of the B3 headers in the context
+func fromB3(ctx context.Context) (string, string, bool) {
+	if b3, ok := metadata.Get(ctx, B3Key); ok {
+		parts := strings.Split(strings.TrimSpace(b3), "-")
+		// the header may only contain the sampling decision
+		if len(parts) >= 2 {
+			return b3IDs(parts[0], parts[1])
+		}
+	}
+
+	traceID, ok := metadata.Get(ctx, B3TraceIDKey)
+	if !ok {
+		return "", "", false
+	}
+	spanID, _ := metadata.Get(ctx, B3SpanIDKey)
+	return b3IDs(traceID, spanID)
+}
+
+// b3IDs validates the ids, 64 bit trace ids are left padded to 128 bits
+func b3IDs(traceID, spanID string) (string, string, bool) {
+	traceID = strings.ToLower(traceID)
+	spanID = strings.ToLower(spanID)
+	if isHex(traceID, 8) {
+		traceID = strings.Repeat("0", 16) + traceID
+	}
+	if !isHex(traceID, 16) || !isHex(spanID, 8) {
+		return "", "", false
+	}
+	if strings.Trim(traceID, "0") == "" || strings.Trim(spanID, "0") == "" {
+		return "", "", false
+	}
+	return traceID, spanID, true
+}
+
+// hasB3 returns true if the context has B3 headers
+func hasB3(ctx context.Context) bool {
+	for _, k := range []string{B3Key, B3TraceIDKey, B3SampledKey, B3FlagsKey} {
+		if _, ok := metadata.Get(ctx, k); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sampled returns the sampling decision of the trace in the context, traces are sampled unless
+// the caller decided otherwise
+func sampled(ctx context.Context) bool {
+	if tp, ok := metadata.Get(ctx, TraceparentKey); ok {
+		if parts := strings.Split(strings.TrimSpace(tp), "-"); len(parts) >= 4 {
+			if flags, err := strconv.ParseUint(parts[3], 16, 8); err == nil {
+				return flags&1 == 1
+			}
+		}
+	}
+	if b3, ok := metadata.Get(ctx, B3Key); ok {
+		parts := strings.Split(strings.TrimSpace(b3), "-")
+		state := parts[0]
+		if len(parts) >= 3 {
+			state = parts[2]
+		}
+		if len(parts) != 2 {
+			return state != "0"
+		}
+	}
+	if f, ok := metadata.Get(ctx, B3FlagsKey); ok && f == "1" {
+		return true
+	}
+	if s, ok := metadata.Get(ctx, B3SampledKey); ok {
+		return s != "0" && s != "false"
+	}
+	return true
+}
@@ -18,7 +18,6 @@ import (
 	"context"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/micro/micro/v3/service/debug/trace"
 	"github.com/micro/micro/v3/util/ring"
 )
@@ -55,8 +54,8 @@ func (t *Tracer) Read(opts ...trace.ReadOption) ([]*trace.Span, error) {
 func (t *Tracer) Start(ctx context.Context, name string) (context.Context, *trace.Span) {
 	span := &trace.Span{
 		Name:     name,
-		Trace:    uuid.New().String(),
-		Id:       uuid.New().String(),
+		Trace:    trace.NewTraceID(),
+		Id:       trace.NewSpanID(),
 		Started:  time.Now(),
 		Metadata: make(map[string]string),
 	}
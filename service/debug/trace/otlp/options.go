@@ -0,0 +1,79 @@
+package otlp
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Options of the exporter
+type Options struct {
+	// Endpoint of the collector, e.g. http://localhost:4318
+	Endpoint string
+	// ServiceName the spans are exported as
+	ServiceName string
+	// Headers sent with each export, e.g. to authenticate with the collector
+	Headers map[string]string
+	// BatchSize is the most spans sent in an export
+	BatchSize int
+	// Interval spans are exported at if the batch isn't full
+	Interval time.Duration
+	// Client used to export the spans
+	Client *http.Client
+}
+
+// Option sets an option of the exporter
+type Option func(o *Options)
+
+func newOptions(opts ...Option) Options {
+	o := Options{
+		Endpoint:    "http://localhost:4318",
+		ServiceName: "unknown_service",
+		BatchSize:   512,
+		Interval:    time.Second * 5,
+		Client:      &http.Client{Timeout: time.Second * 10},
+	}
+	for _, fn := range opts {
+		fn(&o)
+	}
+	o.Endpoint = strings.TrimSuffix(o.Endpoint, "/")
+	return o
+}
+
+// Endpoint sets the endpoint of the collector. The scheme defaults to http.
+func Endpoint(e string) Option {
+	return func(o *Options) {
+		if !strings.Contains(e, "://") {
+			e = "http://" + e
+		}
+		o.Endpoint = e
+	}
+}
+
+// ServiceName sets the name of the service the spans are exported as
+func ServiceName(n string) Option {
+	return func(o *Options) {
+		o.ServiceName = n
+	}
+}
+
+// Headers sets the headers sent with each export
+func Headers(h map[string]string) Option {
+	return func(o *Options) {
+		o.Headers = h
+	}
+}
+
+// BatchSize sets the most spans sent in an export
+func BatchSize(n int) Option {
+	return func(o *Options) {
+		o.BatchSize = n
+	}
+}
+
+// Interval sets the interval spans are exported at
+func Interval(d time.Duration) Option {
+	return func(o *Options) {
+		o.Interval = d
+	}
+}
@@ -0,0 +1,229 @@
+// Package otlp exports the spans of a tracer with the OpenTelemetry protocol, so the spans recorded
+// by the trace wrappers can be viewed in Jaeger, Tempo or any other OTLP collector. Spans are sent
+// in batches as json over http to the /v1/traces path of the endpoint.
+package otlp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/debug/trace"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+// span kinds and status codes of the protocol
+const (
+	kindServer  = 2
+	kindClient  = 3
+	statusError = 2
+)
+
+// Tracer records spans with the tracer it wraps and exports them when they finish
+type Tracer struct {
+	trace.Tracer
+	opts Options
+
+	spans     chan *trace.Span
+	once      sync.Once
+	closeOnce sync.Once
+	exit      chan bool
+	done      chan bool
+}
+
+// NewTracer returns a tracer which exports the spans finished with the tracer
+func NewTracer(t trace.Tracer, opts ...Option) *Tracer {
+	return &Tracer{
+		Tracer: t,
+		opts:   newOptions(opts...),
+		spans:  make(chan *trace.Span, 2048),
+		exit:   make(chan bool),
+		done:   make(chan bool),
+	}
+}
+
+// Finish the span and queue it to be exported. Spans are dropped if the queue is full so a slow
+// collector doesn't slow down requests.
+func (t *Tracer) Finish(s *trace.Span) error {
+	err := t.Tracer.Finish(s)
+	if s == nil {
+		return err
+	}
+	if s.Duration == 0 {
+		s.Duration = time.Since(s.Started)
+	}
+
+	t.once.Do(func() { go t.run() })
+
+	select {
+	case t.spans <- s:
+	default:
+		logger.Debugf("Dropping span %v, the export queue is full", s.Name)
+	}
+	return err
+}
+
+// Close exports the spans queued and stops the exporter
+func (t *Tracer) Close() error {
+	t.closeOnce.Do(func() {
+		// start the exporter if no spans have finished so there's something to stop
+		t.once.Do(func() { go t.run() })
+		close(t.exit)
+	})
+	<-t.done
+	return nil
+}
+
+func (t *Tracer) run() {
+	defer close(t.done)
+
+	ticker := time.NewTicker(t.opts.Interval)
+	defer ticker.Stop()
+
+	batch := make([]*trace.Span, 0, t.opts.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := t.export(batch); err != nil {
+			logger.Warnf("Error exporting %d spans: %v", len(batch), err)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case s := <-t.spans:
+			batch = append(batch, s)
+			if len(batch) >= t.opts.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-t.exit:
+			for {
+				select {
+				case s := <-t.spans:
+					batch = append(batch, s)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (t *Tracer) export(spans []*trace.Span) error {
+	b, err := json.Marshal(encode(t.opts.ServiceName, spans))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, t.opts.Endpoint+"/v1/traces", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.opts.Headers {
+		req.Header.Set(k, v)
+	}
+
+	rsp, err := t.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return fmt.Errorf("collector returned %v", rsp.Status)
+	}
+	return nil
+}
+
+// the json encoding of an export request, ids are hex encoded and 64 bit integers are strings
+type exportRequest struct {
+	ResourceSpans []*resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource      `json:"resource"`
+	ScopeSpans []*scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []*attribute `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope   `json:"scope"`
+	Spans []*span `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type span struct {
+	TraceID           string       `json:"traceId"`
+	SpanID            string       `json:"spanId"`
+	ParentSpanID      string       `json:"parentSpanId,omitempty"`
+	Name              string       `json:"name"`
+	Kind              int          `json:"kind"`
+	StartTimeUnixNano string       `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string       `json:"endTimeUnixNano"`
+	Attributes        []*attribute `json:"attributes,omitempty"`
+	Status            *status      `json:"status,omitempty"`
+}
+
+type attribute struct {
+	Key   string `json:"key"`
+	Value value  `json:"value"`
+}
+
+type value struct {
+	StringValue string `json:"stringValue"`
+}
+
+type status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+func encode(service string, spans []*trace.Span) *exportRequest {
+	ss := &scopeSpans{Scope: scope{Name: "micro"}}
+	for _, s := range spans {
+		sp := &span{
+			TraceID:           s.Trace,
+			SpanID:            s.Id,
+			ParentSpanID:      s.Parent,
+			Name:              s.Name,
+			Kind:              kindServer,
+			StartTimeUnixNano: strconv.FormatInt(s.Started.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.Started.Add(s.Duration).UnixNano(), 10),
+		}
+		if s.Type == trace.SpanTypeRequestOutbound {
+			sp.Kind = kindClient
+		}
+		for k, v := range s.Metadata {
+			if k == "error" {
+				sp.Status = &status{Code: statusError, Message: v}
+				continue
+			}
+			sp.Attributes = append(sp.Attributes, &attribute{Key: k, Value: value{v}})
+		}
+		ss.Spans = append(ss.Spans, sp)
+	}
+
+	return &exportRequest{
+		ResourceSpans: []*resourceSpans{{
+			Resource: resource{
+				Attributes: []*attribute{{Key: "service.name", Value: value{service}}},
+			},
+			ScopeSpans: []*scopeSpans{ss},
+		}},
+	}
+}
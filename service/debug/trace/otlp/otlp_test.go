@@ -0,0 +1,74 @@
+package otlp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/debug/trace"
+	"github.com/micro/micro/v3/service/debug/trace/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExport(t *testing.T) {
+	reqs := make(chan *exportRequest, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "secret", r.Header.Get("Api-Key"))
+
+		var req *exportRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		reqs <- req
+	}))
+	defer srv.Close()
+
+	tr := NewTracer(memory.NewTracer(),
+		Endpoint(srv.URL),
+		ServiceName("users"),
+		Headers(map[string]string{"Api-Key": "secret"}),
+		Interval(time.Hour),
+	)
+
+	ctx, parent := tr.Start(nil, "users.Users.Read")
+	_, child := tr.Start(ctx, "store.Store.Read")
+	child.Type = trace.SpanTypeRequestOutbound
+	child.Metadata["error"] = "not found"
+	assert.NoError(t, tr.Finish(child))
+	assert.NoError(t, tr.Finish(parent))
+
+	// the spans are still recorded by the tracer wrapped
+	spans, err := tr.Read(trace.ReadTrace(parent.Trace))
+	assert.NoError(t, err)
+	assert.Len(t, spans, 2)
+
+	// closing exports the spans queued
+	assert.NoError(t, tr.Close())
+
+	var req *exportRequest
+	select {
+	case req = <-reqs:
+	default:
+		t.Fatal("expected the spans to be exported")
+	}
+
+	rs := req.ResourceSpans[0]
+	assert.Equal(t, "service.name", rs.Resource.Attributes[0].Key)
+	assert.Equal(t, "users", rs.Resource.Attributes[0].Value.StringValue)
+
+	exported := rs.ScopeSpans[0].Spans
+	if assert.Len(t, exported, 2) {
+		assert.Equal(t, child.Id, exported[0].SpanID)
+		assert.Equal(t, parent.Id, exported[0].ParentSpanID)
+		assert.Equal(t, parent.Trace, exported[0].TraceID)
+		assert.Equal(t, kindClient, exported[0].Kind)
+		assert.Equal(t, statusError, exported[0].Status.Code)
+		assert.Equal(t, "not found", exported[0].Status.Message)
+
+		assert.Equal(t, kindServer, exported[1].Kind)
+		assert.Empty(t, exported[1].ParentSpanID)
+		assert.Nil(t, exported[1].Status)
+	}
+}
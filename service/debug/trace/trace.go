@@ -17,6 +17,11 @@ package trace
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/micro/micro/v3/service/context/metadata"
@@ -62,13 +67,45 @@ type Span struct {
 	Type SpanType
 }
 
+// TraceparentKey is the metadata key the trace context is propagated in, in the W3C trace context
+// format 00-<trace id>-<parent span id>-<flags>
+const TraceparentKey = "Traceparent"
+
+// legacy keys the trace was propagated in, read from services which don't set the traceparent
 const (
 	traceIDKey = "Micro-Trace-Id"
 	spanIDKey  = "Micro-Span-Id"
 )
 
-// FromContext returns a span from context
+// NewTraceID returns a random trace id, 16 bytes hex encoded
+func NewTraceID() string {
+	return randomID(16)
+}
+
+// NewSpanID returns a random span id, 8 bytes hex encoded
+func NewSpanID() string {
+	return randomID(8)
+}
+
+func randomID(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// FromContext returns a span from context. The W3C traceparent is read first, then the B3 headers
+// set by systems outside micro, then the headers set by older versions of micro.
 func FromContext(ctx context.Context) (traceID string, parentSpanID string, isFound bool) {
+	if tp, ok := metadata.Get(ctx, TraceparentKey); ok {
+		if traceID, parentSpanID, ok := parseTraceparent(tp); ok {
+			return traceID, parentSpanID, true
+		}
+	}
+
+	if traceID, parentSpanID, ok := fromB3(ctx); ok {
+		return traceID, parentSpanID, true
+	}
+
 	traceID, traceOk := metadata.Get(ctx, traceIDKey)
 	microID, microOk := metadata.Get(ctx, "Micro-Id")
 	if !traceOk && !microOk {
@@ -79,15 +116,70 @@ func FromContext(ctx context.Context) (traceID string, parentSpanID string, isFo
 		traceID = microID
 	}
 	parentSpanID, ok := metadata.Get(ctx, spanIDKey)
-	return traceID, parentSpanID, ok
+	return normalizeID(traceID, 16), normalizeID(parentSpanID, 8), ok
 }
 
-// ToContext saves the trace and span ids in the context
+// ToContext saves the trace and span ids in the context, keeping the sampling decision of the
+// caller. The B3 headers are set too if the trace came from a system using them.
 func ToContext(ctx context.Context, traceID, parentSpanID string) context.Context {
-	return metadata.MergeContext(ctx, map[string]string{
-		traceIDKey: traceID,
-		spanIDKey:  parentSpanID,
-	}, true)
+	traceID, parentSpanID = normalizeID(traceID, 16), normalizeID(parentSpanID, 8)
+	flags, b3Sampled := "01", "1"
+	if !sampled(ctx) {
+		flags, b3Sampled = "00", "0"
+	}
+
+	if PropagateB3 || hasB3(ctx) {
+		ctx = metadata.Set(ctx, B3Key, traceID+"-"+parentSpanID+"-"+b3Sampled)
+		ctx = metadata.Set(ctx, B3TraceIDKey, traceID)
+		ctx = metadata.Set(ctx, B3SpanIDKey, parentSpanID)
+		ctx = metadata.Set(ctx, B3SampledKey, b3Sampled)
+		// the parent of the caller's span isn't the parent of the span
+		ctx = metadata.Delete(ctx, B3ParentSpanIDKey)
+		ctx = metadata.Delete(ctx, B3FlagsKey)
+	}
+	return metadata.Set(ctx, TraceparentKey, fmt.Sprintf("00-%s-%s-%s", traceID, parentSpanID, flags))
+}
+
+// parseTraceparent returns the trace and parent span ids of a version 00 traceparent
+func parseTraceparent(tp string) (string, string, bool) {
+	parts := strings.Split(strings.TrimSpace(tp), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return "", "", false
+	}
+	if !isHex(parts[1], 16) || !isHex(parts[2], 8) {
+		return "", "", false
+	}
+	// all zero ids are invalid
+	if strings.Trim(parts[1], "0") == "" || strings.Trim(parts[2], "0") == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+// normalizeID converts an id to n bytes hex encoded. The uuids used as ids before the trace
+// context was adopted are hashed if they aren't already the right length once the dashes are
+// removed.
+func normalizeID(id string, n int) string {
+	if isHex(id, n) {
+		return id
+	}
+	if s := strings.ToLower(strings.ReplaceAll(id, "-", "")); isHex(s, n) {
+		return s
+	}
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:n])
+}
+
+func isHex(s string, n int) bool {
+	if len(s) != n*2 {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
 }
 
 var (
@@ -0,0 +1,108 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceparent(t *testing.T) {
+	traceID, spanID := NewTraceID(), NewSpanID()
+	assert.Len(t, traceID, 32)
+	assert.Len(t, spanID, 16)
+
+	ctx := ToContext(context.TODO(), traceID, spanID)
+	tp, ok := metadata.Get(ctx, TraceparentKey)
+	assert.True(t, ok)
+	assert.Equal(t, "00-"+traceID+"-"+spanID+"-01", tp)
+
+	tid, sid, ok := FromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, traceID, tid)
+	assert.Equal(t, spanID, sid)
+
+	// invalid trace contexts are ignored
+	for _, tp := range []string{
+		"00-0af7651916cd43dd8448eb211c80319c-00f067aa0ba902b7",
+		"ff-0af7651916cd43dd8448eb211c80319c-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-0AF7651916CD43DD8448EB211C80319C-00f067aa0ba902b7-01",
+		"00-0af7651916cd43dd-00f067aa0ba902b7-01",
+	} {
+		_, _, ok := FromContext(metadata.Set(context.TODO(), TraceparentKey, tp))
+		assert.False(t, ok, tp)
+	}
+}
+
+func TestLegacyHeaders(t *testing.T) {
+	ctx := metadata.NewContext(context.TODO(), metadata.Metadata{
+		traceIDKey: "9b1deb4d-3b7d-4bad-9bdd-2b0d7b3dcb6d",
+		spanIDKey:  "1b4e28ba-2fa1-11d2-883f-0016d3cca427",
+	})
+
+	tid, sid, ok := FromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "9b1deb4d3b7d4bad9bdd2b0d7b3dcb6d", tid)
+	assert.Len(t, sid, 16)
+
+	// the ids are stable so spans of the same trace are linked
+	_, sid2, _ := FromContext(ctx)
+	assert.Equal(t, sid, sid2)
+}
+
+func TestB3(t *testing.T) {
+	// multiple headers with a 64 bit trace id, as normalised from http or grpc headers
+	ctx := metadata.NewContext(context.TODO(), metadata.Metadata{
+		B3TraceIDKey:      "A3CE929D0E0E4736",
+		B3SpanIDKey:       "00f067aa0ba902b7",
+		B3ParentSpanIDKey: "05e3ac9a4f6e3b90",
+		B3SampledKey:      "0",
+	})
+	tid, sid, ok := FromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "0000000000000000a3ce929d0e0e4736", tid)
+	assert.Equal(t, "00f067aa0ba902b7", sid)
+
+	// the outbound request has both formats for the new span and keeps the sampling decision
+	spanID := NewSpanID()
+	out := ToContext(ctx, tid, spanID)
+	tp, _ := metadata.Get(out, TraceparentKey)
+	assert.Equal(t, "00-"+tid+"-"+spanID+"-00", tp)
+	b3, _ := metadata.Get(out, B3Key)
+	assert.Equal(t, tid+"-"+spanID+"-0", b3)
+	s, _ := metadata.Get(out, B3SpanIDKey)
+	assert.Equal(t, spanID, s)
+	_, ok = metadata.Get(out, B3ParentSpanIDKey)
+	assert.False(t, ok)
+
+	// the single header
+	ctx = metadata.Set(context.TODO(), B3Key, "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90")
+	tid, sid, ok = FromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "80f198ee56343ba864fe8b2a57d3eff7", tid)
+	assert.Equal(t, "e457b5a2e4d86bd1", sid)
+	assert.True(t, sampled(ctx))
+
+	// a sampling decision without a trace
+	_, _, ok = FromContext(metadata.Set(context.TODO(), B3Key, "0"))
+	assert.False(t, ok)
+	assert.False(t, sampled(metadata.Set(context.TODO(), B3Key, "0")))
+
+	// the traceparent takes precedence
+	ctx = metadata.Set(ctx, TraceparentKey, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00")
+	tid, _, _ = FromContext(ctx)
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", tid)
+	assert.False(t, sampled(ctx))
+
+	// B3 headers are only set for traces which didn't come from B3 systems if enabled
+	out = ToContext(context.TODO(), NewTraceID(), NewSpanID())
+	_, ok = metadata.Get(out, B3Key)
+	assert.False(t, ok)
+	PropagateB3 = true
+	defer func() { PropagateB3 = false }()
+	out = ToContext(context.TODO(), NewTraceID(), NewSpanID())
+	_, ok = metadata.Get(out, B3Key)
+	assert.True(t, ok)
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+
+	pb "github.com/micro/micro/v3/proto/debug"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/debug/health"
+	"github.com/micro/micro/v3/service/store"
+)
+
+// storeDependency is the name of the dependency on the store
+const storeDependency = "store"
+
+// dependencies registers the checks of the dependencies of the service. The store is optional
+// since not every service uses it, and the services connected to by the warmup are critical as
+// they're the ones the service can't handle requests without. The criticality set in the options
+// overrides these.
+func (s *Service) dependencies() {
+	health.RegisterDependency(storeDependency, health.Optional, checkStore)
+	for _, dep := range s.opts.Warmup {
+		health.RegisterDependency(dep, health.Critical, checkService(s.Client(), dep))
+	}
+	for name, c := range s.opts.Dependencies {
+		health.SetCriticality(name, c)
+	}
+}
+
+// checkStore fails if the store can't be reached
+func checkStore() error {
+	ctx, cancel := context.WithTimeout(context.Background(), health.DependencyTimeout)
+	defer cancel()
+	_, err := store.DefaultStore.List(store.ListLimit(1), store.ListContext(ctx))
+	if err == store.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+// checkService fails if the service has no nodes to route requests to
+func checkService(c client.Client, name string) health.CheckFunc {
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), health.DependencyTimeout)
+		defer cancel()
+		_, err := lookup(ctx, c, c.NewRequest(name, "Debug.Health", &pb.HealthRequest{}))
+		return err
+	}
+}
@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Hop is a service an error was returned through on its way back to the caller
+type Hop struct {
+	// Service which returned the error
+	Service string `json:"service"`
+	// Endpoint of the service which was called
+	Endpoint string `json:"endpoint,omitempty"`
+	// Code of the error the service returned
+	Code int32 `json:"code"`
+	// TraceId of the request, so the spans of the service can be found
+	TraceId string `json:"trace_id,omitempty"`
+}
+
+func (h *Hop) String() string {
+	s := fmt.Sprintf("%v.%v (%d)", h.Service, h.Endpoint, h.Code)
+	if len(h.TraceId) > 0 {
+		s += " trace " + h.TraceId
+	}
+	return s
+}
+
+// Wrap adds the service to the chain of the error as it's returned by the service, so the caller
+// can tell which service the error came from and the path it took. Errors which aren't *Error
+// are converted, as an internal server error of the service if they aren't an encoded *Error,
+// or a timeout if the deadline was exceeded. Cancelled requests are returned as they are since
+// the caller has gone. The error isn't modified since errors are often shared.
+func Wrap(err error, service, endpoint, traceID string) error {
+	if err == nil || err == context.Canceled {
+		return err
+	}
+	verr := FromError(err)
+	if err == context.DeadlineExceeded {
+		verr = &Error{Id: service, Code: http.StatusRequestTimeout, Detail: err.Error(), Status: http.StatusText(http.StatusRequestTimeout)}
+	} else if verr.Code == 0 {
+		verr.Id = service
+		verr.Code = http.StatusInternalServerError
+		verr.Status = http.StatusText(http.StatusInternalServerError)
+	}
+
+	// the error was already added by the service, e.g. by more than one wrapper
+	if n := len(verr.Chain); n > 0 && verr.Chain[n-1].Service == service && verr.Chain[n-1].Endpoint == endpoint {
+		return err
+	}
+
+	e := *verr
+	e.Chain = make([]*Hop, len(verr.Chain), len(verr.Chain)+1)
+	copy(e.Chain, verr.Chain)
+	e.Chain = append(e.Chain, &Hop{
+		Service:  service,
+		Endpoint: endpoint,
+		Code:     verr.Code,
+		TraceId:  traceID,
+	})
+	return &e
+}
+
+// Chain returns the services the error was returned through, the first returned it. Nil is
+// returned if the error has no chain.
+func Chain(err error) []*Hop {
+	if err == nil {
+		return nil
+	}
+	return FromError(err).Chain
+}
+
+// Origin returns the service which returned the error, or nil if the error has no chain
+func Origin(err error) *Hop {
+	if chain := Chain(err); len(chain) > 0 {
+		return chain[0]
+	}
+	return nil
+}
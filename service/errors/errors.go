@@ -27,6 +27,8 @@ type Error struct {
 	Code   int32  `json:"code"`
 	Detail string `json:"detail"`
 	Status string `json:"status"`
+	// Chain of the services the error was returned through, the first returned it
+	Chain []*Hop `json:"chain,omitempty"`
 }
 
 func (e *Error) Error() string {
@@ -137,6 +139,16 @@ func Conflict(id, format string, a ...interface{}) error {
 	}
 }
 
+// TooManyRequests generates a 429 error.
+func TooManyRequests(id, format string, a ...interface{}) error {
+	return &Error{
+		Id:     id,
+		Code:   429,
+		Detail: fmt.Sprintf(format, a...),
+		Status: http.StatusText(429),
+	}
+}
+
 // InternalServerError generates a 500 error.
 func InternalServerError(id, format string, a ...interface{}) error {
 	return &Error{
@@ -14,6 +14,7 @@
 package errors
 
 import (
+	"context"
 	er "errors"
 	"net/http"
 	"testing"
@@ -88,3 +89,44 @@ func TestErrors(t *testing.T) {
 		}
 	}
 }
+
+func TestWrap(t *testing.T) {
+	err := InternalServerError("go.micro.store", "database unavailable")
+
+	// the chain starts at the service which returned the error
+	werr := Wrap(err, "store", "Store.Read", "abc")
+	werr = Wrap(werr, "store", "Store.Read", "abc")
+	werr = Wrap(werr, "users", "Users.Read", "abc")
+	if len(err.(*Error).Chain) != 0 {
+		t.Fatalf("Expected the error not to be modified, got chain %v", err.(*Error).Chain)
+	}
+
+	chain := Chain(Parse(werr.Error()))
+	if len(chain) != 2 {
+		t.Fatalf("Expected 2 hops got %d", len(chain))
+	}
+	if chain[1].Service != "users" || chain[1].Endpoint != "Users.Read" || chain[1].Code != 500 || chain[1].TraceId != "abc" {
+		t.Fatalf("Unexpected hop %v", chain[1])
+	}
+	if o := Origin(werr); o == nil || o.Service != "store" {
+		t.Fatalf("Expected the store to be the origin got %v", o)
+	}
+
+	// errors without a chain have no origin
+	if o := Origin(err); o != nil {
+		t.Fatalf("Expected no origin got %v", o)
+	}
+
+	// other errors are converted to internal server errors of the service
+	verr := FromError(Wrap(er.New("oops"), "store", "Store.Read", "abc"))
+	if verr.Code != 500 || verr.Id != "store" || verr.Detail != "oops" || len(verr.Chain) != 1 {
+		t.Fatalf("Expected the error to be converted got %v", verr)
+	}
+	verr = FromError(Wrap(context.DeadlineExceeded, "store", "Store.Read", "abc"))
+	if verr.Code != 408 || len(verr.Chain) != 1 {
+		t.Fatalf("Expected a timeout got %v", verr)
+	}
+	if Wrap(context.Canceled, "store", "Store.Read", "abc") != context.Canceled {
+		t.Fatal("Expected the error to be returned as it is")
+	}
+}
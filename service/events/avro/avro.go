@@ -0,0 +1,251 @@
+// Package avro encodes events using avro and a Confluent compatible schema registry so topics
+// can be shared with consumers in the Kafka ecosystem
+package avro
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+const (
+	// magicByte prefixes every message in the Confluent wire format, it is followed by
+	// the 4 byte schema id and then the avro binary encoded message
+	magicByte  = 0
+	headerSize = 5
+)
+
+var (
+	// ErrMissingRegistry is returned if no schema registry was provided
+	ErrMissingRegistry = errors.New("Missing schema registry")
+	// ErrIncompatibleSchema is returned on publish if the schema is not compatible with the
+	// latest version registered for the subject
+	ErrIncompatibleSchema = errors.New("Schema is incompatible with the latest registered version")
+	// ErrInvalidPayload is returned when decoding a payload which is not in the wire format
+	ErrInvalidPayload = errors.New("Payload is not avro encoded")
+)
+
+// NewStream wraps a stream so messages published to topics with a schema are avro encoded and
+// avro encoded events consumed are decoded to JSON, meaning Event.Unmarshal works as normal
+func NewStream(s events.Stream, opts ...Option) (events.Stream, error) {
+	options := Options{
+		Strategy: TopicNameStrategy,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.Registry == nil {
+		return nil, ErrMissingRegistry
+	}
+
+	return &stream{
+		Stream: s,
+		opts:   options,
+		codecs: make(map[int]*goavro.Codec),
+		topics: make(map[string]int),
+	}, nil
+}
+
+type stream struct {
+	events.Stream
+	opts Options
+
+	sync.RWMutex
+	// codecs by schema id
+	codecs map[int]*goavro.Codec
+	// schema ids by topic
+	topics map[string]int
+}
+
+func (s *stream) Publish(topic string, msg interface{}, opts ...events.PublishOption) error {
+	schema, ok := s.opts.Schemas[topic]
+	if !ok {
+		return s.Stream.Publish(topic, msg, opts...)
+	}
+
+	id, err := s.register(topic, schema)
+	if err != nil {
+		return err
+	}
+
+	codec, err := s.codec(id)
+	if err != nil {
+		return err
+	}
+
+	payload, err := Marshal(codec, id, msg)
+	if err != nil {
+		return err
+	}
+
+	return s.Stream.Publish(topic, payload, opts...)
+}
+
+func (s *stream) Consume(topic string, opts ...events.ConsumeOption) (<-chan events.Event, error) {
+	evChan, err := s.Stream.Consume(topic, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan events.Event)
+
+	go func() {
+		defer close(out)
+
+		for ev := range evChan {
+			// payloads which aren't in the wire format, e.g. JSON, are passed through as is
+			if isAvro(ev.Payload) {
+				payload, err := s.decode(ev.Payload)
+				if err != nil {
+					logger.Errorf("Error decoding avro event %v on topic %v: %v", ev.ID, topic, err)
+				} else {
+					ev.Payload = payload
+				}
+			}
+
+			out <- ev
+		}
+	}()
+
+	return out, nil
+}
+
+// register the schema for the topic, checking it's compatible first
+func (s *stream) register(topic, schema string) (int, error) {
+	s.RLock()
+	id, ok := s.topics[topic]
+	s.RUnlock()
+	if ok {
+		return id, nil
+	}
+
+	name, err := recordName(schema)
+	if err != nil {
+		return 0, err
+	}
+	subject := s.opts.Strategy(topic, name)
+
+	if !s.opts.SkipCompatibility {
+		ok, err := s.opts.Registry.Compatible(subject, schema)
+		if err != nil {
+			return 0, fmt.Errorf("Error checking schema compatibility for %v: %v", subject, err)
+		}
+		if !ok {
+			return 0, ErrIncompatibleSchema
+		}
+	}
+
+	id, err = s.opts.Registry.Register(subject, schema)
+	if err != nil {
+		return 0, fmt.Errorf("Error registering schema for %v: %v", subject, err)
+	}
+
+	s.Lock()
+	s.topics[topic] = id
+	s.Unlock()
+
+	return id, nil
+}
+
+// codec returns the codec for the schema id, looking the schema up in the registry
+func (s *stream) codec(id int) (*goavro.Codec, error) {
+	s.RLock()
+	codec, ok := s.codecs[id]
+	s.RUnlock()
+	if ok {
+		return codec, nil
+	}
+
+	schema, err := s.opts.Registry.Schema(id)
+	if err != nil {
+		return nil, err
+	}
+	codec, err = goavro.NewCodec(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Lock()
+	s.codecs[id] = codec
+	s.Unlock()
+
+	return codec, nil
+}
+
+func (s *stream) decode(payload []byte) ([]byte, error) {
+	codec, err := s.codec(SchemaID(payload))
+	if err != nil {
+		return nil, err
+	}
+	native, err := Unmarshal(codec, payload)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(native)
+}
+
+// Marshal encodes the message in the Confluent wire format. The message is converted to its JSON
+// representation before being encoded, so struct json tags should match the schema field names.
+func Marshal(codec *goavro.Codec, id int, msg interface{}) ([]byte, error) {
+	b, ok := msg.([]byte)
+	if !ok {
+		var err error
+		if b, err = json.Marshal(msg); err != nil {
+			return nil, events.ErrEncodingMessage
+		}
+	}
+
+	var native interface{}
+	if err := json.Unmarshal(b, &native); err != nil {
+		return nil, events.ErrEncodingMessage
+	}
+
+	buf := make([]byte, headerSize)
+	buf[0] = magicByte
+	binary.BigEndian.PutUint32(buf[1:], uint32(id))
+
+	return codec.BinaryFromNative(buf, native)
+}
+
+// Unmarshal decodes a payload in the Confluent wire format into its native go representation
+func Unmarshal(codec *goavro.Codec, payload []byte) (interface{}, error) {
+	if !isAvro(payload) {
+		return nil, ErrInvalidPayload
+	}
+	native, _, err := codec.NativeFromBinary(payload[headerSize:])
+	return native, err
+}
+
+// SchemaID returns the id of the schema used to encode the payload
+func SchemaID(payload []byte) int {
+	if len(payload) < headerSize {
+		return 0
+	}
+	return int(binary.BigEndian.Uint32(payload[1:headerSize]))
+}
+
+func isAvro(payload []byte) bool {
+	return len(payload) >= headerSize && payload[0] == magicByte
+}
+
+// recordName returns the fully qualified name of the schema, e.g. com.example.User
+func recordName(schema string) (string, error) {
+	var s struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	}
+	if err := json.Unmarshal([]byte(schema), &s); err != nil {
+		return "", fmt.Errorf("Invalid avro schema: %v", err)
+	}
+	if len(s.Namespace) == 0 || strings.Contains(s.Name, ".") {
+		return s.Name, nil
+	}
+	return s.Namespace + "." + s.Name, nil
+}
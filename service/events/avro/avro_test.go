@@ -0,0 +1,129 @@
+package avro
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/events/stream/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+const userSchema = `{
+	"type": "record",
+	"name": "User",
+	"namespace": "com.example",
+	"fields": [
+		{"name": "name", "type": "string"},
+		{"name": "age", "type": "int"}
+	]
+}`
+
+type user struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+// fakeRegistry implements the subset of the schema registry API used by the stream
+type fakeRegistry struct {
+	sync.Mutex
+	schemas    map[int]string
+	subjects   map[string]int
+	compatible bool
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	var req schemaRequest
+	json.NewDecoder(r.Body).Decode(&req)
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/compatibility/"):
+		json.NewEncoder(w).Encode(&compatibilityResponse{IsCompatible: f.compatible})
+	case strings.HasPrefix(r.URL.Path, "/subjects/"):
+		id := len(f.schemas) + 1
+		f.schemas[id] = req.Schema
+		f.subjects[strings.Split(r.URL.Path, "/")[2]] = id
+		json.NewEncoder(w).Encode(&schemaResponse{ID: id})
+	case strings.HasPrefix(r.URL.Path, "/schemas/ids/"):
+		json.NewEncoder(w).Encode(&schemaResponse{Schema: f.schemas[1]})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(&errorResponse{ErrorCode: 40401, Message: "Subject not found"})
+	}
+}
+
+func TestStream(t *testing.T) {
+	reg := &fakeRegistry{schemas: map[int]string{}, subjects: map[string]int{}, compatible: true}
+	srv := httptest.NewServer(reg)
+	defer srv.Close()
+
+	mem, err := memory.NewStream()
+	assert.Nil(t, err)
+
+	str, err := NewStream(mem,
+		WithRegistry(NewRegistry(srv.URL)),
+		WithStrategy(TopicRecordNameStrategy),
+		WithSchema("users", userSchema),
+	)
+	assert.Nil(t, err)
+
+	evChan, err := str.Consume("users")
+	assert.Nil(t, err)
+
+	// the raw events should be in the wire format
+	rawChan, err := mem.Consume("users")
+	assert.Nil(t, err)
+
+	assert.Nil(t, str.Publish("users", &user{Name: "John", Age: 30}))
+	assert.Equal(t, 1, reg.subjects["users-com.example.User"])
+
+	select {
+	case ev := <-rawChan:
+		assert.True(t, isAvro(ev.Payload))
+		assert.Equal(t, 1, SchemaID(ev.Payload))
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for raw event")
+	}
+
+	select {
+	case ev := <-evChan:
+		var u user
+		assert.Nil(t, ev.Unmarshal(&u))
+		assert.Equal(t, "John", u.Name)
+		assert.Equal(t, 30, u.Age)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for event")
+	}
+}
+
+func TestIncompatibleSchema(t *testing.T) {
+	reg := &fakeRegistry{schemas: map[int]string{}, subjects: map[string]int{}}
+	srv := httptest.NewServer(reg)
+	defer srv.Close()
+
+	mem, err := memory.NewStream()
+	assert.Nil(t, err)
+
+	str, err := NewStream(mem, WithRegistry(NewRegistry(srv.URL)), WithSchema("users", userSchema))
+	assert.Nil(t, err)
+
+	assert.Equal(t, ErrIncompatibleSchema, str.Publish("users", &user{Name: "John"}))
+	assert.Empty(t, reg.subjects)
+}
+
+func TestSubjectNameStrategies(t *testing.T) {
+	name, err := recordName(userSchema)
+	assert.Nil(t, err)
+	assert.Equal(t, "com.example.User", name)
+
+	assert.Equal(t, "users-value", TopicNameStrategy("users", name))
+	assert.Equal(t, "com.example.User", RecordNameStrategy("users", name))
+	assert.Equal(t, "users-com.example.User", TopicRecordNameStrategy("users", name))
+}
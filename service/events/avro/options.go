@@ -0,0 +1,68 @@
+package avro
+
+// SubjectNameStrategy determines the subject a schema is registered under
+type SubjectNameStrategy func(topic, record string) string
+
+// TopicNameStrategy registers schemas under <topic>-value. This is the Confluent default
+// and allows a single record type per topic.
+func TopicNameStrategy(topic, record string) string {
+	return topic + "-value"
+}
+
+// RecordNameStrategy registers schemas under the fully qualified record name, allowing
+// the same record to be used across topics.
+func RecordNameStrategy(topic, record string) string {
+	return record
+}
+
+// TopicRecordNameStrategy registers schemas under <topic>-<record>, allowing multiple
+// record types per topic.
+func TopicRecordNameStrategy(topic, record string) string {
+	return topic + "-" + record
+}
+
+// Options which are used to configure the avro stream
+type Options struct {
+	// Registry used to register and lookup schemas
+	Registry *Registry
+	// Strategy used to name subjects, defaults to TopicNameStrategy
+	Strategy SubjectNameStrategy
+	// Schemas by topic
+	Schemas map[string]string
+	// SkipCompatibility disables the compatibility check before a schema is registered
+	SkipCompatibility bool
+}
+
+// Option is a function which configures options
+type Option func(o *Options)
+
+// WithRegistry sets the schema registry to use
+func WithRegistry(r *Registry) Option {
+	return func(o *Options) {
+		o.Registry = r
+	}
+}
+
+// WithStrategy sets the subject name strategy
+func WithStrategy(s SubjectNameStrategy) Option {
+	return func(o *Options) {
+		o.Strategy = s
+	}
+}
+
+// WithSchema sets the avro schema used to encode messages published to the topic
+func WithSchema(topic, schema string) Option {
+	return func(o *Options) {
+		if o.Schemas == nil {
+			o.Schemas = make(map[string]string)
+		}
+		o.Schemas[topic] = schema
+	}
+}
+
+// SkipCompatibility disables the compatibility check on publish
+func SkipCompatibility(b bool) Option {
+	return func(o *Options) {
+		o.SkipCompatibility = b
+	}
+}
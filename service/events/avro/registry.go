@@ -0,0 +1,180 @@
+package avro
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// contentType is the content type expected by the Confluent schema registry API
+	contentType = "application/vnd.schemaregistry.v1+json"
+)
+
+// Registry is a client for a Confluent compatible schema registry
+type Registry struct {
+	// Address of the registry, e.g. http://localhost:8081
+	Address string
+	// Username and Password for basic auth, optional
+	Username string
+	Password string
+	// Client used to make the http requests
+	Client *http.Client
+
+	sync.RWMutex
+	// schemas by id
+	schemas map[int]string
+	// schema ids by subject and schema
+	ids map[string]int
+}
+
+// NewRegistry returns a schema registry client for the given address
+func NewRegistry(address string) *Registry {
+	return &Registry{
+		Address: strings.TrimSuffix(address, "/"),
+		Client:  &http.Client{Timeout: 10 * time.Second},
+		schemas: make(map[int]string),
+		ids:     make(map[string]int),
+	}
+}
+
+type schemaRequest struct {
+	Schema string `json:"schema"`
+}
+
+type schemaResponse struct {
+	ID     int    `json:"id"`
+	Schema string `json:"schema"`
+}
+
+type compatibilityResponse struct {
+	IsCompatible bool `json:"is_compatible"`
+}
+
+type errorResponse struct {
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+// Register the schema under the subject, returning the id the registry assigned to it. Registering
+// an identical schema twice returns the existing id.
+func (r *Registry) Register(subject, schema string) (int, error) {
+	key := subject + ":" + schema
+
+	r.RLock()
+	id, ok := r.ids[key]
+	r.RUnlock()
+	if ok {
+		return id, nil
+	}
+
+	var rsp schemaResponse
+	path := fmt.Sprintf("/subjects/%s/versions", url.PathEscape(subject))
+	if err := r.do("POST", path, &schemaRequest{Schema: schema}, &rsp); err != nil {
+		return 0, err
+	}
+
+	r.Lock()
+	r.ids[key] = rsp.ID
+	r.schemas[rsp.ID] = schema
+	r.Unlock()
+
+	return rsp.ID, nil
+}
+
+// Schema returns the schema with the given id
+func (r *Registry) Schema(id int) (string, error) {
+	r.RLock()
+	schema, ok := r.schemas[id]
+	r.RUnlock()
+	if ok {
+		return schema, nil
+	}
+
+	var rsp schemaResponse
+	if err := r.do("GET", fmt.Sprintf("/schemas/ids/%d", id), nil, &rsp); err != nil {
+		return "", err
+	}
+
+	r.Lock()
+	r.schemas[id] = rsp.Schema
+	r.Unlock()
+
+	return rsp.Schema, nil
+}
+
+// Compatible checks the schema against the latest version registered for the subject using the
+// compatibility level configured in the registry. A subject with no versions is always compatible.
+func (r *Registry) Compatible(subject, schema string) (bool, error) {
+	var rsp compatibilityResponse
+	path := fmt.Sprintf("/compatibility/subjects/%s/versions/latest", url.PathEscape(subject))
+	err := r.do("POST", path, &schemaRequest{Schema: schema}, &rsp)
+	if rerr, ok := err.(*RegistryError); ok && rerr.Status == http.StatusNotFound {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	return rsp.IsCompatible, nil
+}
+
+// RegistryError is returned when the registry responds with a non 2xx status code
+type RegistryError struct {
+	Status  int
+	Code    int
+	Message string
+}
+
+func (e *RegistryError) Error() string {
+	return fmt.Sprintf("schema registry error %d: %s", e.Code, e.Message)
+}
+
+func (r *Registry) do(method, path string, req, rsp interface{}) error {
+	var body *bytes.Buffer
+	if req != nil {
+		b, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewBuffer(b)
+	} else {
+		body = bytes.NewBuffer(nil)
+	}
+
+	hreq, err := http.NewRequest(method, r.Address+path, body)
+	if err != nil {
+		return err
+	}
+	hreq.Header.Set("Content-Type", contentType)
+	hreq.Header.Set("Accept", contentType)
+	if len(r.Username) > 0 {
+		hreq.SetBasicAuth(r.Username, r.Password)
+	}
+
+	hrsp, err := r.Client.Do(hreq)
+	if err != nil {
+		return err
+	}
+	defer hrsp.Body.Close()
+
+	b, err := ioutil.ReadAll(hrsp.Body)
+	if err != nil {
+		return err
+	}
+
+	if hrsp.StatusCode < 200 || hrsp.StatusCode > 299 {
+		var e errorResponse
+		json.Unmarshal(b, &e)
+		if len(e.Message) == 0 {
+			e.Message = string(b)
+		}
+		return &RegistryError{Status: hrsp.StatusCode, Code: e.ErrorCode, Message: e.Message}
+	}
+
+	return json.Unmarshal(b, rsp)
+}
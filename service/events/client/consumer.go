@@ -0,0 +1,176 @@
+package client
+
+import (
+	gocontext "context"
+	"sync"
+	"time"
+
+	pb "github.com/micro/micro/v3/proto/events"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/events/util"
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/metrics"
+	"github.com/micro/micro/v3/util/backoff"
+)
+
+var (
+	// MaxReconnectBackoff is the longest a consumer waits between attempts to reconnect to the
+	// events service
+	MaxReconnectBackoff = time.Second * 30
+)
+
+// consumer streams the events of a topic from the events service. If the stream breaks, e.g.
+// because the events service restarted, it reconnects with a backoff until the context of the
+// consumer is done. Consumers in a group resume from the last event acknowledged by the group,
+// which the backend keeps. Other consumers resume from the timestamp of the last event they
+// acknowledged, skipping the events of that second they've already acknowledged.
+type consumer struct {
+	topic   string
+	options events.ConsumeOptions
+	request *pb.ConsumeRequest
+	events  chan events.Event
+
+	// connect starts a stream, replaced in tests
+	connect func(ctx gocontext.Context, in *pb.ConsumeRequest, opts ...client.CallOption) (pb.Stream_ConsumeService, error)
+
+	sync.Mutex
+	stream pb.Stream_ConsumeService
+	// offset is the timestamp of the last event acknowledged
+	offset time.Time
+	// acked are the ids of the events acknowledged with the timestamp of the offset
+	acked map[string]bool
+}
+
+// run streams the events until the context of the consumer is done, closing the channel of
+// events once it is
+func (c *consumer) run() {
+	defer close(c.events)
+
+	for {
+		err := c.recv()
+		if err == nil {
+			return
+		}
+		log.Errorf("Error receiving from stream %s: %v", c.topic, err)
+		if !c.reconnect() {
+			return
+		}
+	}
+}
+
+// recv sends the events of the stream to the channel, returning an error if the stream breaks
+// or nil once the context of the consumer is done
+func (c *consumer) recv() error {
+	c.Lock()
+	stream := c.stream
+	c.Unlock()
+	defer stream.Close()
+
+	for {
+		ev, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		evt := util.DeserializeEvent(ev)
+		if c.seen(&evt) {
+			// redelivered since the stream resumed from the second of the offset
+			continue
+		}
+		if !c.options.AutoAck {
+			id, ts := evt.ID, evt.Timestamp
+			evt.SetNackFunc(func() error {
+				return stream.SendMsg(&pb.AckRequest{Id: id, Success: false})
+			})
+			evt.SetAckFunc(func() error {
+				if err := stream.SendMsg(&pb.AckRequest{Id: id, Success: true}); err != nil {
+					return err
+				}
+				c.ack(id, ts)
+				return nil
+			})
+		}
+
+		select {
+		case c.events <- evt:
+			if c.options.AutoAck {
+				c.ack(evt.ID, evt.Timestamp)
+			}
+		case <-c.options.Context.Done():
+			log.Info("Consuming stream context canceled")
+			return nil
+		}
+	}
+}
+
+// ack records the event was acknowledged so the stream resumes after it
+func (c *consumer) ack(id string, ts time.Time) {
+	c.Lock()
+	defer c.Unlock()
+
+	switch {
+	case ts.Unix() > c.offset.Unix():
+		c.offset = ts
+		c.acked = map[string]bool{id: true}
+	case ts.Unix() == c.offset.Unix():
+		c.acked[id] = true
+	}
+}
+
+// seen returns true if the event was acknowledged before the stream resumed
+func (c *consumer) seen(ev *events.Event) bool {
+	c.Lock()
+	defer c.Unlock()
+	return ev.Timestamp.Unix() == c.offset.Unix() && c.acked[ev.ID]
+}
+
+// reconnect to the events service with a backoff, returning false if the context of the
+// consumer is done first
+func (c *consumer) reconnect() bool {
+	start := time.Now()
+
+	for attempts := 1; ; attempts++ {
+		wait := backoff.Do(attempts)
+		if wait > MaxReconnectBackoff {
+			wait = MaxReconnectBackoff
+		}
+		select {
+		case <-c.options.Context.Done():
+			return false
+		case <-time.After(wait):
+		}
+
+		req := *c.request
+		c.Lock()
+		if len(req.Group) == 0 && !c.offset.IsZero() {
+			req.Offset = c.offset.Unix()
+		}
+		c.Unlock()
+
+		stream, err := c.connect(context.DefaultContext, &req, client.WithAuthToken())
+		if err != nil {
+			log.Debugf("Error reconnecting to stream %s, attempt %d: %v", c.topic, attempts, err)
+			recordReconnect(c.topic, "failure")
+			continue
+		}
+		recordReconnect(c.topic, "success")
+		if metrics.IsSet() {
+			metrics.Timing("events.consumer.downtime", time.Since(start), metrics.Tags{"topic": c.topic})
+		}
+		log.Infof("Reconnected to stream %s after %d attempts", c.topic, attempts)
+
+		c.Lock()
+		c.stream = stream
+		c.Unlock()
+		return true
+	}
+}
+
+func recordReconnect(topic, result string) {
+	if !metrics.IsSet() {
+		return
+	}
+	metrics.Count("events.consumer.reconnects", 1, metrics.Tags{"topic": topic, "result": result})
+}
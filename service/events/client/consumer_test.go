@@ -0,0 +1,111 @@
+package client
+
+import (
+	gocontext "context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/micro/micro/v3/proto/events"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/stretchr/testify/assert"
+)
+
+// testStream returns its events then fails, as if the events service restarted
+type testStream struct {
+	pb.Stream_ConsumeService
+	events []*pb.Event
+}
+
+func (t *testStream) Recv() (*pb.Event, error) {
+	if len(t.events) == 0 {
+		return nil, errors.New("connection reset")
+	}
+	ev := t.events[0]
+	t.events = t.events[1:]
+	return ev, nil
+}
+
+func (t *testStream) Close() error {
+	return nil
+}
+
+func TestConsumerReconnect(t *testing.T) {
+	first := &pb.Event{Id: "1", Topic: "foo", Timestamp: 100}
+	second := &pb.Event{Id: "2", Topic: "foo", Timestamp: 100}
+
+	var lock sync.Mutex
+	var requests []*pb.ConsumeRequest
+	streams := []*testStream{
+		// the stream resumes from the second of the first event so it's redelivered
+		{events: []*pb.Event{first, second}},
+	}
+	connect := func(ctx gocontext.Context, in *pb.ConsumeRequest, opts ...client.CallOption) (pb.Stream_ConsumeService, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		requests = append(requests, in)
+		if len(requests) == 1 {
+			return nil, errors.New("unavailable")
+		}
+		if len(streams) == 0 {
+			return nil, errors.New("unavailable")
+		}
+		s := streams[0]
+		streams = streams[1:]
+		return s, nil
+	}
+
+	ctx, cancel := gocontext.WithCancel(gocontext.Background())
+	defer cancel()
+
+	c := &consumer{
+		topic:   "foo",
+		options: events.ConsumeOptions{AutoAck: true, Context: ctx},
+		request: &pb.ConsumeRequest{Topic: "foo", AutoAck: true},
+		stream:  &testStream{events: []*pb.Event{first}},
+		events:  make(chan events.Event),
+		connect: connect,
+	}
+	go c.run()
+
+	var received []string
+	for ev := range c.events {
+		received = append(received, ev.ID)
+		if len(received) == 2 {
+			cancel()
+		}
+	}
+	assert.Equal(t, []string{"1", "2"}, received)
+
+	lock.Lock()
+	defer lock.Unlock()
+	// the first attempt to reconnect failed and was retried
+	assert.GreaterOrEqual(t, len(requests), 2)
+	assert.Equal(t, int64(100), requests[1].Offset)
+}
+
+func TestConsumerGroupOffset(t *testing.T) {
+	ctx, cancel := gocontext.WithCancel(gocontext.Background())
+	defer cancel()
+
+	var req *pb.ConsumeRequest
+	c := &consumer{
+		topic:   "foo",
+		options: events.ConsumeOptions{AutoAck: true, Context: ctx},
+		request: &pb.ConsumeRequest{Topic: "foo", Group: "bar", AutoAck: true},
+		events:  make(chan events.Event),
+		connect: func(ctx gocontext.Context, in *pb.ConsumeRequest, opts ...client.CallOption) (pb.Stream_ConsumeService, error) {
+			req = in
+			return &testStream{}, nil
+		},
+	}
+	c.ack("1", time.Unix(100, 0))
+
+	// the backend keeps the offset of groups
+	assert.True(t, c.reconnect())
+	assert.Equal(t, int64(0), req.Offset)
+	assert.True(t, c.seen(&events.Event{ID: "1", Timestamp: time.Unix(100, 0)}))
+	assert.False(t, c.seen(&events.Event{ID: "2", Timestamp: time.Unix(100, 0)}))
+}
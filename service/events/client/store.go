@@ -73,6 +73,14 @@ func (s *store) Write(ev *events.Event, opts ...events.WriteOption) error {
 	return err
 }
 
+func (s *store) Delete(topic, id string) error {
+	_, err := s.client().Delete(context.DefaultContext, &pb.DeleteRequest{
+		Topic: topic,
+		Id:    id,
+	}, client.WithAuthToken())
+	return err
+}
+
 // this is a tmp solution since the client isn't initialized when NewStream is called. There is a
 // fix in the works in another PR.
 func (s *store) client() pb.StoreService {
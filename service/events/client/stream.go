@@ -9,8 +9,6 @@ import (
 	"github.com/micro/micro/v3/service/client"
 	"github.com/micro/micro/v3/service/context"
 	"github.com/micro/micro/v3/service/events"
-	"github.com/micro/micro/v3/service/events/util"
-	log "github.com/micro/micro/v3/service/logger"
 )
 
 // NewStream returns an initialized stream service
@@ -81,41 +79,17 @@ func (s *stream) Consume(topic string, opts ...events.ConsumeOption) (<-chan eve
 		return nil, err
 	}
 
-	evChan := make(chan events.Event)
-
-	go func() {
-		for {
-
-			ev, err := stream.Recv()
-			if err != nil {
-				log.Errorf("Error receiving from stream %s", err)
-				close(evChan)
-				stream.Close()
-				return
-			}
-
-			evt := util.DeserializeEvent(ev)
-			if !options.AutoAck {
-				evt.SetNackFunc(func() error {
-					return stream.SendMsg(&pb.AckRequest{Id: evt.ID, Success: false})
-				})
-				evt.SetAckFunc(func() error {
-					return stream.SendMsg(&pb.AckRequest{Id: evt.ID, Success: true})
-				})
-			}
-
-			select {
-			case evChan <- evt:
-			case <-options.Context.Done():
-				log.Info("Consuming stream context canceled")
-				close(evChan)
-				stream.Close()
-				return
-			}
-		}
-	}()
+	c := &consumer{
+		topic:   topic,
+		options: options,
+		request: subReq,
+		stream:  stream,
+		events:  make(chan events.Event),
+		connect: s.client().Consume,
+	}
+	go c.run()
 
-	return evChan, nil
+	return c.events, nil
 }
 
 // this is a tmp solution since the client isn't initialized when NewStream is called. There is a
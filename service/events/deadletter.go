@@ -0,0 +1,111 @@
+package events
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/metrics"
+)
+
+const (
+	// DeadLetterSuffix is appended to a topic to name the stream its dead letters are moved to
+	DeadLetterSuffix = ".deadletter"
+
+	// DeadLetterTopicKey is the metadata key of the topic the event was published to
+	DeadLetterTopicKey = "Micro-Dead-Letter-Topic"
+	// DeadLetterEventKey is the metadata key of the id of the event
+	DeadLetterEventKey = "Micro-Dead-Letter-Event"
+	// DeadLetterGroupKey is the metadata key of the consumer group which failed to process the event
+	DeadLetterGroupKey = "Micro-Dead-Letter-Group"
+	// DeadLetterAttemptsKey is the metadata key of the number of times the event was delivered
+	DeadLetterAttemptsKey = "Micro-Dead-Letter-Attempts"
+	// DeadLetterReasonKey is the metadata key of the reason the event failed
+	DeadLetterReasonKey = "Micro-Dead-Letter-Reason"
+	// DeadLetterTimeKey is the metadata key of the time the event was dead lettered, in RFC 3339
+	DeadLetterTimeKey = "Micro-Dead-Letter-Time"
+)
+
+var (
+	// DeadLetterTTL is how long dead letters are kept in the events store to be inspected and replayed
+	DeadLetterTTL = time.Hour * 24 * 7
+)
+
+// DeadLetterTopic returns the topic the dead letters of a topic are moved to
+func DeadLetterTopic(topic string) string {
+	return topic + DeadLetterSuffix
+}
+
+// DeadLetter moves an event a consumer failed to process within the retry limit to the dead letter
+// stream of its topic, with the failure recorded in its metadata. The dead letter is written to the
+// DefaultStore too, if set, so it can be inspected and replayed after the stream moves on.
+func DeadLetter(s Stream, ev *Event, group string, attempts int, reason string) error {
+	md := make(map[string]string, len(ev.Metadata)+6)
+	for k, v := range ev.Metadata {
+		md[k] = v
+	}
+	now := time.Now()
+	md[DeadLetterTopicKey] = ev.Topic
+	md[DeadLetterEventKey] = ev.ID
+	md[DeadLetterGroupKey] = group
+	md[DeadLetterAttemptsKey] = strconv.Itoa(attempts)
+	md[DeadLetterReasonKey] = reason
+	md[DeadLetterTimeKey] = now.Format(time.RFC3339)
+
+	logger.Warnf("Moving event %v on topic %v to the dead letter stream after %d attempts: %v", ev.ID, ev.Topic, attempts, reason)
+	if metrics.IsSet() {
+		metrics.Count("events.dead_letters", 1, metrics.Tags{"topic": ev.Topic, "group": group})
+	}
+
+	topic := DeadLetterTopic(ev.Topic)
+	if err := s.Publish(topic, ev.Payload, WithMetadata(md), WithTimestamp(now)); err != nil {
+		return fmt.Errorf("error publishing event %v to %v: %w", ev.ID, topic, err)
+	}
+	if DefaultStore == nil {
+		return nil
+	}
+	dl := &Event{
+		ID:        ev.ID,
+		Topic:     topic,
+		Timestamp: now,
+		Metadata:  md,
+		Payload:   ev.Payload,
+	}
+	if err := DefaultStore.Write(dl, WithTTL(DeadLetterTTL)); err != nil {
+		return fmt.Errorf("error writing dead letter %v: %w", ev.ID, err)
+	}
+	return nil
+}
+
+// Replay publishes a dead letter to the topic it was originally published to, with its original
+// metadata, so it's processed again, then deletes it from the DefaultStore so it isn't replayed
+// twice. The event is delivered to every consumer group of the topic, not only the group which
+// failed to process it, so the handlers of the topic need to be idempotent.
+func Replay(s Stream, ev *Event) error {
+	topic, ok := ev.Metadata[DeadLetterTopicKey]
+	if !ok {
+		topic = strings.TrimSuffix(ev.Topic, DeadLetterSuffix)
+	}
+	if len(topic) == 0 || topic == ev.Topic {
+		return fmt.Errorf("event %v isn't a dead letter", ev.ID)
+	}
+
+	md := map[string]string{}
+	for k, v := range ev.Metadata {
+		if !strings.HasPrefix(k, "Micro-Dead-Letter-") {
+			md[k] = v
+		}
+	}
+	if err := s.Publish(topic, ev.Payload, WithMetadata(md)); err != nil {
+		return err
+	}
+	if DefaultStore == nil {
+		return nil
+	}
+	if err := DefaultStore.Delete(ev.Topic, ev.ID); err != nil {
+		return fmt.Errorf("error deleting dead letter %v: %w", ev.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package events_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/events/store"
+	"github.com/micro/micro/v3/service/events/stream/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadLetter(t *testing.T) {
+	s, err := memory.NewStream()
+	assert.NoError(t, err)
+	defer func(st events.Store) { events.DefaultStore = st }(events.DefaultStore)
+	events.DefaultStore = store.NewStore()
+
+	evs, err := s.Consume("payment.created", events.WithGroup("payments"),
+		events.WithAutoAck(false, time.Millisecond*50), events.WithRetryLimit(1))
+	assert.NoError(t, err)
+	dls, err := s.Consume(events.DeadLetterTopic("payment.created"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Publish("payment.created", map[string]int{"amount": 100}, events.WithMetadata(map[string]string{"user": "1"})))
+
+	// the event is dead lettered once it's been delivered more times than the retry limit
+	var id string
+	for i := 0; i < 2; i++ {
+		select {
+		case ev := <-evs:
+			id = ev.ID
+			assert.NoError(t, ev.Nack())
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for the event to be delivered")
+		}
+	}
+
+	var dl events.Event
+	select {
+	case dl = <-dls:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the dead letter")
+	}
+	assert.Equal(t, `{"amount":100}`, string(dl.Payload))
+	assert.Equal(t, "1", dl.Metadata["user"])
+	assert.Equal(t, "payment.created", dl.Metadata[events.DeadLetterTopicKey])
+	assert.Equal(t, id, dl.Metadata[events.DeadLetterEventKey])
+	assert.Equal(t, "payments", dl.Metadata[events.DeadLetterGroupKey])
+	assert.Equal(t, "2", dl.Metadata[events.DeadLetterAttemptsKey])
+	assert.Equal(t, "nacked", dl.Metadata[events.DeadLetterReasonKey])
+
+	// the dead letter is kept in the store to be inspected
+	recs, err := events.DefaultStore.Read(events.DeadLetterTopic("payment.created"))
+	assert.NoError(t, err)
+	if assert.Len(t, recs, 1) {
+		assert.Equal(t, id, recs[0].ID)
+	}
+
+	// replaying publishes the event to its topic without the failure
+	assert.NoError(t, events.Replay(s, recs[0]))
+	select {
+	case ev := <-evs:
+		assert.Equal(t, `{"amount":100}`, string(ev.Payload))
+		assert.Equal(t, map[string]string{"user": "1"}, ev.Metadata)
+		assert.NoError(t, ev.Ack())
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the replayed event")
+	}
+	recs, err = events.DefaultStore.Read(events.DeadLetterTopic("payment.created"))
+	assert.NoError(t, err)
+	assert.Empty(t, recs)
+	assert.Error(t, events.Replay(s, &events.Event{ID: "1", Topic: "payment.created"}))
+}
@@ -28,6 +28,10 @@ var (
 	DefaultStore Store
 )
 
+// PublisherNamespaceKey is the metadata key the events service sets to the namespace of the
+// account which published the event, so consumers can trust which namespace it came from
+const PublisherNamespaceKey = "Micro-Publisher-Namespace"
+
 var (
 	// ErrMissingTopic is returned if a blank topic was provided to publish
 	ErrMissingTopic = errors.New("Missing topic")
@@ -45,6 +49,7 @@ type Stream interface {
 type Store interface {
 	Read(topic string, opts ...ReadOption) ([]*Event, error)
 	Write(event *Event, opts ...WriteOption) error
+	Delete(topic, id string) error
 }
 
 type AckFunc func() error
@@ -7,19 +7,34 @@ import (
 
 	"github.com/google/uuid"
 	pb "github.com/micro/micro/v3/proto/events"
+	"github.com/micro/micro/v3/service/auth"
 	"github.com/micro/micro/v3/service/errors"
 	"github.com/micro/micro/v3/service/events"
 	"github.com/micro/micro/v3/service/events/util"
 	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/util/auth/audit"
 	"github.com/micro/micro/v3/util/auth/namespace"
+	"github.com/micro/micro/v3/util/residency"
 )
 
+// openTopics are the topics the services of every namespace can publish to, other topics can only
+// be published to by the services of the default namespace
+var openTopics = map[string]bool{
+	audit.Topic: true,
+}
+
 type Stream struct{}
 
 func (s *Stream) Publish(ctx context.Context, req *pb.PublishRequest, rsp *pb.PublishResponse) error {
 	// authorize the request
+	acc, ok := auth.AccountFromContext(ctx)
 	if err := namespace.AuthorizeAdmin(ctx, namespace.DefaultNamespace, "events.Stream.Publish"); err != nil {
-		return err
+		if !ok || !openTopics[req.Topic] {
+			return err
+		}
+		if err := namespace.AuthorizeAdmin(ctx, acc.Issuer, "events.Stream.Publish"); err != nil {
+			return err
+		}
 	}
 
 	// validate the request
@@ -27,14 +42,34 @@ func (s *Stream) Publish(ctx context.Context, req *pb.PublishRequest, rsp *pb.Pu
 		return errors.BadRequest("events.Stream.Publish", events.ErrMissingTopic.Error())
 	}
 
+	// ensure the event can be written to the region of the stream, events belong to the namespace
+	// of the publisher, or the namespace in their metadata if it's published by the platform
+	ns := req.Metadata["namespace"]
+	if ok && (len(ns) == 0 || acc.Issuer != namespace.DefaultNamespace) {
+		ns = acc.Issuer
+	}
+	if len(ns) == 0 {
+		ns = namespace.DefaultNamespace
+	}
+	if err := residency.Check(ns); err != nil {
+		return errors.Forbidden("events.Stream.Publish", err.Error())
+	}
+
+	// the namespace of the publisher is set so it can't be spoofed
+	md := make(map[string]string, len(req.Metadata)+1)
+	for k, v := range req.Metadata {
+		md[k] = v
+	}
+	delete(md, events.PublisherNamespaceKey)
+	if ok {
+		md[events.PublisherNamespaceKey] = acc.Issuer
+	}
+
 	// parse options
-	var opts []events.PublishOption
+	opts := []events.PublishOption{events.WithMetadata(md)}
 	if req.Timestamp > 0 {
 		opts = append(opts, events.WithTimestamp(time.Unix(req.Timestamp, 0)))
 	}
-	if req.Metadata != nil {
-		opts = append(opts, events.WithMetadata(req.Metadata))
-	}
 
 	// publish the event
 	if err := events.Publish(req.Topic, req.Payload, opts...); err != nil {
@@ -44,7 +79,7 @@ func (s *Stream) Publish(ctx context.Context, req *pb.PublishRequest, rsp *pb.Pu
 	// write the event to the store
 	event := events.Event{
 		ID:        uuid.New().String(),
-		Metadata:  req.Metadata,
+		Metadata:  md,
 		Payload:   req.Payload,
 		Topic:     req.Topic,
 		Timestamp: time.Unix(req.Timestamp, 0),
@@ -0,0 +1,109 @@
+package events
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/micro/micro/v3/service/logger"
+)
+
+// Schema describes the message published to a topic. Schemas are registered by the typed
+// publish and subscribe helpers protoc-gen-micro generates for messages with a topic option.
+type Schema struct {
+	// Topic the message is published to
+	Topic string
+	// Type is the fully qualified name of the message, e.g. users.UserCreated
+	Type string
+	// New returns an empty message to decode an event into
+	New func() interface{}
+}
+
+var (
+	schemaMtx sync.RWMutex
+	schemas   = map[string]*Schema{}
+)
+
+// RegisterSchema registers the schema of a topic. A topic can only have one schema, registering a
+// different type for a topic which already has one returns an error.
+func RegisterSchema(s *Schema) error {
+	if len(s.Topic) == 0 {
+		return ErrMissingTopic
+	}
+
+	schemaMtx.Lock()
+	defer schemaMtx.Unlock()
+
+	if existing, ok := schemas[s.Topic]; ok && existing.Type != s.Type {
+		return fmt.Errorf("topic %v already has the schema %v", s.Topic, existing.Type)
+	}
+	schemas[s.Topic] = s
+	return nil
+}
+
+// SchemaFor returns the schema registered for the topic
+func SchemaFor(topic string) (*Schema, bool) {
+	schemaMtx.RLock()
+	defer schemaMtx.RUnlock()
+	s, ok := schemas[topic]
+	return s, ok
+}
+
+// Schemas returns the registered schemas sorted by topic
+func Schemas() []*Schema {
+	schemaMtx.RLock()
+	defer schemaMtx.RUnlock()
+
+	res := make([]*Schema, 0, len(schemas))
+	for _, s := range schemas {
+		res = append(res, s)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Topic < res[j].Topic })
+	return res
+}
+
+// Subscribe consumes the events published to a topic, decoding each into the message of the
+// schema registered for the topic and calling the handler with it. When auto ack is disabled the
+// event is acked if the handler succeeds and nacked otherwise. Events which can't be decoded are
+// never passed to the handler.
+func Subscribe(topic string, fn func(ev *Event, msg interface{}) error, opts ...ConsumeOption) error {
+	schema, ok := SchemaFor(topic)
+	if !ok {
+		return fmt.Errorf("no schema registered for topic %v", topic)
+	}
+
+	// determine whether the events need to be acked
+	options := ConsumeOptions{AutoAck: true}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	evs, err := Consume(topic, opts...)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for ev := range evs {
+			ev := ev
+			msg := schema.New()
+			err := ev.Unmarshal(msg)
+			if err != nil {
+				logger.Errorf("Error decoding event %v on topic %v as %v: %v", ev.ID, topic, schema.Type, err)
+			} else {
+				err = fn(&ev, msg)
+			}
+
+			if options.AutoAck {
+				continue
+			}
+			if err != nil {
+				ev.Nack()
+			} else {
+				ev.Ack()
+			}
+		}
+	}()
+
+	return nil
+}
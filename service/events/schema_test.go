@@ -0,0 +1,46 @@
+package events_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/events/stream/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+type userCreated struct {
+	ID string `json:"id"`
+}
+
+func TestSubscribe(t *testing.T) {
+	s, err := memory.NewStream()
+	assert.NoError(t, err)
+	events.DefaultStream = s
+
+	// subscribing requires a schema
+	assert.Error(t, events.Subscribe("user.created", nil))
+
+	assert.NoError(t, events.RegisterSchema(&events.Schema{
+		Topic: "user.created",
+		Type:  "users.UserCreated",
+		New:   func() interface{} { return new(userCreated) },
+	}))
+	assert.Error(t, events.RegisterSchema(&events.Schema{Topic: "user.created", Type: "users.Other"}))
+	assert.Len(t, events.Schemas(), 1)
+
+	ids := make(chan string, 1)
+	err = events.Subscribe("user.created", func(ev *events.Event, msg interface{}) error {
+		ids <- msg.(*userCreated).ID
+		return nil
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, events.Publish("user.created", &userCreated{ID: "1"}))
+	select {
+	case id := <-ids:
+		assert.Equal(t, "1", id)
+	case <-time.After(time.Second):
+		t.Fatal("event not received")
+	}
+}
@@ -1,13 +1,42 @@
 package server
 
 import (
+	"context"
+
 	pb "github.com/micro/micro/v3/proto/events"
 	"github.com/micro/micro/v3/service"
 	"github.com/micro/micro/v3/service/events/handler"
 	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/util/ownership"
 	"github.com/urfave/cli/v2"
 )
 
+var (
+	// Flags specific to the events service
+	Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:    "alert_slack_token",
+			Usage:   "Set the token of the slack bot which posts alerts to the channels of the teams owning the services",
+			EnvVars: []string{"MICRO_EVENTS_ALERT_SLACK_TOKEN"},
+		},
+		&cli.StringFlag{
+			Name:    "alert_slack_channel",
+			Usage:   "Set the slack channel alerts are posted to when the team owning the service has no channel",
+			EnvVars: []string{"MICRO_EVENTS_ALERT_SLACK_CHANNEL"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "alert_route",
+			Usage:   "Route the alerts of a team to a webhook e.g. payments=https://example.com/hook, use * for the alerts of every other team",
+			EnvVars: []string{"MICRO_EVENTS_ALERT_ROUTE"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "alert_webhook_secret",
+			Usage:   "Set the secrets the deliveries to the alert webhooks are signed with",
+			EnvVars: []string{"MICRO_EVENTS_ALERT_WEBHOOK_SECRET"},
+		},
+	}
+)
+
 // Run the micro broker
 func Run(ctx *cli.Context) error {
 	// new service
@@ -19,6 +48,32 @@ func Run(ctx *cli.Context) error {
 	pb.RegisterStreamHandler(srv.Server(), new(handler.Stream))
 	pb.RegisterStoreHandler(srv.Server(), new(handler.Store))
 
+	// route the alerts to the teams which own the services
+	var opts []ownership.Option
+	if token := ctx.String("alert_slack_token"); len(token) > 0 {
+		opts = append(opts, ownership.WithNotifier(ownership.NewSlackNotifier(token, ctx.String("alert_slack_channel"))))
+	}
+	if rs := ctx.StringSlice("alert_route"); len(rs) > 0 {
+		routes := map[string]string{}
+		for _, r := range rs {
+			team, url, err := ownership.ParseRoute(r)
+			if err != nil {
+				logger.Fatal(err)
+			}
+			routes[team] = url
+		}
+		opts = append(opts, ownership.WithNotifier(ownership.NewWebhookNotifier(routes, ctx.StringSlice("alert_webhook_secret")...)))
+	}
+	if len(opts) > 0 {
+		runCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			if err := ownership.NewRouter(opts...).Run(runCtx); err != nil {
+				logger.Errorf("Error routing alerts: %v", err)
+			}
+		}()
+	}
+
 	// run the service
 	if err := srv.Run(); err != nil {
 		logger.Fatal(err)
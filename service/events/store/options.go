@@ -18,12 +18,15 @@ import (
 	"time"
 
 	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/util/sync"
 )
 
 type Options struct {
 	Store  store.Store
 	TTL    time.Duration
 	Backup Backup
+	// Sync elects the replica which takes the backups
+	Sync sync.Sync
 }
 
 type Option func(o *Options)
@@ -47,3 +50,11 @@ func WithBackup(back Backup) Option {
 		o.Backup = back
 	}
 }
+
+// WithSync sets the sync used to elect the replica which takes the backups, without it every
+// replica takes them
+func WithSync(s sync.Sync) Option {
+	return func(o *Options) {
+		o.Sync = s
+	}
+}
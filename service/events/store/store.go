@@ -15,6 +15,7 @@
 package store
 
 import (
+	"context"
 	"encoding/json"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/store"
 	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/micro/micro/v3/util/sync"
 	"github.com/pkg/errors"
 )
 
@@ -125,14 +127,47 @@ func (s *evStore) Write(event *events.Event, opts ...events.WriteOption) error {
 	return nil
 }
 
+// Delete an event from the store
+func (s *evStore) Delete(topic, id string) error {
+	if len(topic) == 0 {
+		return events.ErrMissingTopic
+	}
+
+	// the event is written with the hour as the suffix of the key
+	recs, err := s.opts.Store.Read(topic+joinKey+id+joinKey, store.ReadPrefix())
+	if err != nil {
+		return errors.Wrap(err, "Error reading from store")
+	}
+	for _, r := range recs {
+		if err := s.opts.Store.Delete(r.Key); err != nil && err != store.ErrNotFound {
+			return errors.Wrap(err, "Error deleting from the store")
+		}
+	}
+	return nil
+}
+
+// backupLoop snapshots the store every hour. When there are multiple replicas only the leader
+// takes the snapshots.
 func (s *evStore) backupLoop() {
+	if s.opts.Sync == nil {
+		s.backup(context.Background())
+		return
+	}
+	sync.Lead(context.Background(), s.opts.Sync, "events.backup", s.backup)
+}
+
+func (s *evStore) backup(ctx context.Context) {
 	for {
 		err := s.opts.Backup.Snapshot(s.opts.Store)
 		if err != nil {
 			logger.Errorf("Error running backup %s", err)
 		}
 
-		time.Sleep(1 * time.Hour)
+		select {
+		case <-time.After(time.Hour):
+		case <-ctx.Done():
+			return
+		}
 	}
 }
 
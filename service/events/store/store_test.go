@@ -59,4 +59,16 @@ func TestStore(t *testing.T) {
 		assert.Nilf(t, err, "No error should be returned")
 		assert.Len(t, evs, 1, "The result should include no more than the read limit")
 	})
+
+	// only the event deleted should be removed
+	t.Run("Delete", func(t *testing.T) {
+		err := store.Delete("foo", testData[0].ID)
+		assert.Nilf(t, err, "Deleting an event should not return an error")
+		evs, err := store.Read("foo")
+		assert.Nilf(t, err, "No error should be returned")
+		if assert.Len(t, evs, 1, "The event should be deleted") {
+			assert.Equal(t, testData[1].ID, evs[0].ID)
+		}
+		assert.Equal(t, events.ErrMissingTopic, store.Delete("", testData[1].ID))
+	})
 }
@@ -49,6 +49,7 @@ type subscriber struct {
 
 	sync.RWMutex
 	retryMap   map[string]int
+	nacked     map[string]bool
 	retryLimit int
 	autoAck    bool
 	ackWait    time.Duration
@@ -128,14 +129,13 @@ func (m *mem) Consume(topic string, opts ...events.ConsumeOption) (<-chan events
 	for _, o := range opts {
 		o(&options)
 	}
-	// TODO RetryLimit
-
 	// setup the subscriber
 	sub := &subscriber{
 		Channel:    make(chan events.Event),
 		Topic:      topic,
 		Group:      options.Group,
 		retryMap:   map[string]int{},
+		nacked:     map[string]bool{},
 		autoAck:    true,
 		retryLimit: options.GetRetryLimit(),
 	}
@@ -183,7 +183,7 @@ func (m *mem) lookupPreviousEvents(sub *subscriber, startTime time.Time) {
 		if ev.Timestamp.Unix() < startTime.Unix() {
 			continue
 		}
-		sendEvent(&ev, sub)
+		m.sendEvent(&ev, sub)
 	}
 }
 
@@ -206,11 +206,11 @@ func (m *mem) handleEvent(ev *events.Event) {
 
 	// send the message to each channel async (since one channel might be blocked)
 	for _, sub := range filteredSubs {
-		sendEvent(ev, sub)
+		m.sendEvent(ev, sub)
 	}
 }
 
-func sendEvent(ev *events.Event, sub *subscriber) {
+func (m *mem) sendEvent(ev *events.Event, sub *subscriber) {
 	go func(s *subscriber) {
 		evCopy := *ev
 		if s.autoAck {
@@ -232,12 +232,18 @@ func sendEvent(ev *events.Event, sub *subscriber) {
 			}
 
 			if s.retryLimit > -1 && count > s.retryLimit {
-				if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
-					logger.Errorf("Message retry limit reached, discarding: %v %d %d", evCopy.ID, count, s.retryLimit)
-				}
+				// move the event to the dead letter stream so it can be inspected and replayed
 				s.Lock()
+				reason := fmt.Sprintf("not acknowledged within %v", s.ackWait)
+				if s.nacked[evCopy.ID] {
+					reason = "nacked"
+				}
 				delete(s.retryMap, evCopy.ID)
+				delete(s.nacked, evCopy.ID)
 				s.Unlock()
+				if err := events.DeadLetter(m, &evCopy, s.Group, count, reason); err != nil {
+					logger.Errorf("Error dead lettering event %v: %v", evCopy.ID, err)
+				}
 				return
 			}
 			s.Channel <- evCopy
@@ -252,6 +258,7 @@ func ackFunc(s *subscriber, evCopy events.Event) func() error {
 	return func() error {
 		s.Lock()
 		delete(s.retryMap, evCopy.ID)
+		delete(s.nacked, evCopy.ID)
 		s.Unlock()
 		return nil
 	}
@@ -259,6 +266,12 @@ func ackFunc(s *subscriber, evCopy events.Event) func() error {
 
 func nackFunc(s *subscriber, evCopy events.Event) func() error {
 	return func() error {
+		// the event is redelivered after the ack wait, record it was nacked in case it's dead lettered
+		s.Lock()
+		if _, ok := s.retryMap[evCopy.ID]; ok {
+			s.nacked[evCopy.ID] = true
+		}
+		s.Unlock()
 		return nil
 	}
 }
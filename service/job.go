@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/micro/micro/v3/service/logger"
+)
+
+// JobFunc is a one-off task run by the service. The context is cancelled if the job is killed.
+type JobFunc func(ctx context.Context) error
+
+// runJob runs the job the service was started with and returns once it's finished. The server
+// isn't started so the job doesn't receive requests, an error exits the service with a non-zero
+// code which the runtime records as the job failing.
+func (s *Service) runJob() error {
+	fn, ok := s.opts.Jobs[s.opts.RunJob]
+	if !ok {
+		return fmt.Errorf("unknown job %v", s.opts.RunJob)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if s.opts.Signal {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT)
+		defer signal.Stop(ch)
+		go func() {
+			select {
+			case <-ch:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	logger.Infof("Running job %v of %v", s.opts.RunJob, s.Name())
+	start := time.Now()
+	if err := fn(ctx); err != nil {
+		return fmt.Errorf("job %v failed: %v", s.opts.RunJob, err)
+	}
+	logger.Infof("Job %v of %v finished in %v", s.opts.RunJob, s.Name(), time.Since(start))
+	return nil
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRunJob(t *testing.T) {
+	var ran bool
+	migrate := func(ctx context.Context) error {
+		ran = true
+		return nil
+	}
+	backfill := func(ctx context.Context) error {
+		return errors.New("backfill error")
+	}
+
+	s := &Service{opts: newOptions(Job("migrate", migrate), Job("backfill", backfill), RunJob("migrate"))}
+	s.opts.Name = "foo"
+	if err := s.Run(); err != nil {
+		t.Fatalf("expected the job to succeed, got %v", err)
+	}
+	if !ran {
+		t.Fatal("expected the job to run")
+	}
+
+	s.opts.RunJob = "backfill"
+	if err := s.Run(); err == nil || !strings.Contains(err.Error(), "backfill error") {
+		t.Fatalf("expected the job error, got %v", err)
+	}
+
+	s.opts.RunJob = "unknown"
+	if err := s.Run(); err == nil {
+		t.Fatal("expected an error running an unknown job")
+	}
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"fmt"
+
+	grpcClient "github.com/micro/micro/v3/service/client/grpc"
+	"github.com/micro/micro/v3/service/logger"
+	grpcServer "github.com/micro/micro/v3/service/server/grpc"
+	"github.com/micro/micro/v3/util/auth/cert"
+)
+
+// mtls issues the certificate of the service and authenticates the connections to and from
+// other services with it. Callers without a certificate can still connect without TLS and
+// authenticate with a token. Servers which haven't been issued a certificate are only dialed
+// without TLS if the service was started with MTLSPlaintext.
+func (s *Service) mtls() (*cert.Manager, error) {
+	m := cert.NewManager(cert.Plaintext(s.opts.MTLSPlaintext))
+	if err := m.Start(); err != nil {
+		return nil, fmt.Errorf("error issuing certificate: %v", err)
+	}
+	s.Server().Init(grpcServer.Credentials(m.ServerCredentials()))
+	s.Client().Init(grpcClient.Dialer(m.Dial))
+
+	if logger.V(logger.InfoLevel, logger.DefaultLogger) {
+		logger.Infof("Issued certificate for [service] %s expiring at %v", s.Name(), m.Expiry())
+	}
+	return m, nil
+}
@@ -235,3 +235,42 @@ func (n *Network) Services(ctx context.Context, req *pb.ServicesRequest, resp *p
 
 	return nil
 }
+
+// Links returns the tunnel links of the node and the traffic on them
+func (n *Network) Links(ctx context.Context, req *pb.LinksRequest, resp *pb.LinksResponse) error {
+	// authorize the request. only accounts issued by micro (root accounts) can access this endpoint
+	if err := authns.AuthorizeAdmin(ctx, authns.DefaultNamespace, "network.Network.Links"); err != nil {
+		return err
+	}
+
+	tun := n.Network.Options().Tunnel
+	if tun == nil {
+		return nil
+	}
+
+	for _, link := range tun.Links() {
+		m := link.Metrics()
+		l := &pb.Link{
+			Id:               link.Id(),
+			State:            link.State(),
+			Loopback:         link.Loopback(),
+			Length:           link.Length(),
+			Rate:             link.Rate(),
+			Delay:            link.Delay(),
+			BytesSent:        m.BytesSent,
+			BytesReceived:    m.BytesReceived,
+			MessagesSent:     m.MessagesSent,
+			MessagesReceived: m.MessagesReceived,
+			Errors:           m.Errors,
+			KeyRotations:     m.KeyRotations,
+			Relay:            link.Relay(),
+			Observed:         link.Observed(),
+		}
+		if !m.KeyRotated.IsZero() {
+			l.KeyRotated = m.KeyRotated.Unix()
+		}
+		resp.Links = append(resp.Links, l)
+	}
+
+	return nil
+}
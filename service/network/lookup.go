@@ -5,6 +5,7 @@ import (
 
 	"github.com/micro/micro/v3/service/client"
 	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/micro/micro/v3/service/router/failover"
 )
 
 // Lookup provides a lookup function that checks for namespace as the Micro-Namespace header
@@ -18,6 +19,6 @@ func Lookup(ctx context.Context, req client.Request, opts client.CallOptions) ([
 		}
 	}
 
-	// use the standard Lookup function
-	return client.LookupRoute(ctx, req, opts)
+	// use the failover Lookup function, which uses the local region unless the service fails over
+	return failover.Lookup(ctx, req, opts)
 }
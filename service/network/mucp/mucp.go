@@ -102,6 +102,10 @@ type mucpNetwork struct {
 	tunClient map[string]tunnel.Session
 	// peerLinks is a map of links for each peer
 	peerLinks map[string]tunnel.Link
+	// syncs is the state of the syncs sent to each peer
+	syncs map[string]*peerSync
+	// encodings are the links to nodes which accept compressed messages
+	encodings map[string]bool
 
 	sync.RWMutex
 	// connected marks the network as connected
@@ -201,6 +205,8 @@ func NewNetwork(opts ...network.Option) network.Network {
 		client:     client,
 		tunClient:  make(map[string]tunnel.Session),
 		peerLinks:  make(map[string]tunnel.Link),
+		syncs:      make(map[string]*peerSync),
+		encodings:  make(map[string]bool),
 		discovered: make(chan bool, 1),
 	}
 
@@ -491,6 +497,16 @@ func (n *mucpNetwork) handleNetConn(s tunnel.Session, msg chan *message) {
 			continue
 		}
 
+		if m.Header[acceptEncodingHeader] == gzipEncoding {
+			n.acceptsGzip(m.Header["Micro-Link"])
+		}
+		if err := decode(m); err != nil {
+			if logger.V(logger.DebugLevel, logger.DefaultLogger) {
+				logger.Debugf("Network tunnel [%s] decode error: %v", NetworkChannel, err)
+			}
+			continue
+		}
+
 		select {
 		case msg <- &message{
 			msg:     m,
@@ -791,27 +807,7 @@ func (n *mucpNetwork) processNetChan(listener tunnel.Listener) {
 				// The faster it gets the network config (routes and peer graph)
 				// the faster the network converges to a stable state
 
-				go func() {
-					// get node peer graph to send back to the connecting node
-					node := PeersToProto(n.node, MaxDepth)
-
-					msg := &pb.Sync{
-						Peer: node,
-					}
-
-					// get a list of the best routes for each service in our routing table
-					routes, err := n.getProtoRoutes()
-					if err != nil {
-						logger.Debugf("Network node %s failed listing routes: %v", n.id, err)
-					}
-					// attached the routes to the message
-					msg.Routes = routes
-
-					// send sync message to the newly connected peer
-					if err := n.sendTo("sync", NetworkChannel, peer, msg); err != nil {
-						logger.Debugf("Network failed to send sync message: %v", err)
-					}
-				}()
+				go n.sendSync(peer, true)
 			case "peer":
 				// mark the time the message has been received
 				now := time.Now()
@@ -848,27 +844,7 @@ func (n *mucpNetwork) processNetChan(listener tunnel.Listener) {
 
 				// if it's a new peer i.e. we do not have it in our graph, we request full sync
 				if err := n.node.AddPeer(peer); err == nil {
-					go func() {
-						// marshal node graph into protobuf
-						node := PeersToProto(n.node, MaxDepth)
-
-						msg := &pb.Sync{
-							Peer: node,
-						}
-
-						// get a list of the best routes for each service in our routing table
-						routes, err := n.getProtoRoutes()
-						if err != nil {
-							logger.Debugf("Network node %s failed listing routes: %v", n.id, err)
-						}
-						// attached the routes to the message
-						msg.Routes = routes
-
-						// send sync message to the newly connected peer
-						if err := n.sendTo("sync", NetworkChannel, peer, msg); err != nil {
-							logger.Debugf("Network failed to send sync message: %v", err)
-						}
-					}()
+					go n.sendSync(peer, true)
 
 					continue
 					// if we already have the peer in our graph, skip further steps
@@ -1310,46 +1286,14 @@ func (n *mucpNetwork) manage() {
 				}
 			}
 		case <-netsync.C:
-			// get a list of node peers
-			peers := n.Peers()
-
-			// skip when there are no peers
-			if len(peers) == 0 {
-				continue
-			}
-
-			// pick a random peer from the list of peers and request full sync
-			peer := n.node.GetPeerNode(peers[rnd.Intn(len(peers))].Id())
-			// skip if we can't find randomly selected peer
+			// pick a random peer which is due a sync, taking the quality of the links into account
+			peer := n.nextSyncPeer(rnd)
+			// skip when there are no peers to sync with
 			if peer == nil {
 				continue
 			}
 
-			go func() {
-				// get node peer graph to send back to the connecting node
-				node := PeersToProto(n.node, MaxDepth)
-
-				msg := &pb.Sync{
-					Peer: node,
-				}
-
-				// get a list of the best routes for each service in our routing table
-				routes, err := n.getProtoRoutes()
-				if err != nil {
-					if logger.V(logger.DebugLevel, logger.DefaultLogger) {
-						logger.Debugf("Network node %s failed listing routes: %v", n.id, err)
-					}
-				}
-				// attached the routes to the message
-				msg.Routes = routes
-
-				// send sync message to the newly connected peer
-				if err := n.sendTo("sync", NetworkChannel, peer, msg); err != nil {
-					if logger.V(logger.DebugLevel, logger.DefaultLogger) {
-						logger.Debugf("Network failed to send sync message: %v", err)
-					}
-				}
-			}()
+			go n.sendSync(peer, false)
 		}
 	}
 }
@@ -1446,6 +1390,9 @@ func (n *mucpNetwork) sendTo(method, channel string, peer *node, msg proto.Messa
 		tmsg.Header["Micro-Peer"] = peer.id
 	}
 
+	// compress the message if the link is constrained
+	n.encode(n.peerLink(peer), tmsg)
+
 	if err := c.Send(tmsg); err != nil {
 		// TODO: Lookup peer in our graph
 		if peerNode := n.GetPeerNode(peer.id); peerNode != nil {
@@ -1492,7 +1439,8 @@ func (n *mucpNetwork) sendMsg(method, channel string, msg proto.Message) error {
 
 	return client.Send(&transport.Message{
 		Header: map[string]string{
-			"Micro-Method": method,
+			"Micro-Method":       method,
+			acceptEncodingHeader: gzipEncoding,
 		},
 		Body: body,
 	})
@@ -0,0 +1,262 @@
+package mucp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"math/rand"
+	"time"
+
+	"github.com/micro/micro/v3/service/logger"
+	pb "github.com/micro/micro/v3/service/network/mucp/proto"
+	"github.com/micro/micro/v3/service/network/transport"
+	"github.com/micro/micro/v3/service/network/tunnel"
+)
+
+// Edge nodes are often connected over slow or lossy links such as a cellular backhaul, where
+// sending the full routing table every SyncTime uses much of the bandwidth. Syncs to a peer only
+// carry the routes which changed since the last sync to it, with a full sync every FullSyncTime in
+// case a delta was lost. Peers over constrained links are synced less often and their syncs are
+// compressed, and peers over links which are failing aren't synced until the link recovers.
+
+var (
+	// FullSyncTime is how often a peer is sent the full routing table rather than the changes
+	FullSyncTime = 10 * time.Minute
+	// ConstrainedSyncTime is the time between syncs to peers over constrained links
+	ConstrainedSyncTime = 5 * time.Minute
+	// ConstrainedLinkLength is the round trip time above which a link is constrained
+	ConstrainedLinkLength = 300 * time.Millisecond
+	// PoorLinkErrorRate is the ratio of failed sends above which a link is too unreliable to sync over
+	PoorLinkErrorRate = 0.2
+	// CompressThreshold is the size in bytes above which messages over constrained links are compressed
+	CompressThreshold = 1024
+)
+
+const (
+	// encodingHeader is the encoding of the message body
+	encodingHeader = "Micro-Network-Encoding"
+	// acceptEncodingHeader tells peers the encodings the node can decode
+	acceptEncodingHeader = "Micro-Network-Accept-Encoding"
+	// gzipEncoding is the only encoding supported
+	gzipEncoding = "gzip"
+)
+
+// linkQuality is how well a link is performing
+type linkQuality int
+
+const (
+	linkGood linkQuality = iota
+	// linkConstrained is a link with a long round trip time
+	linkConstrained
+	// linkPoor is a link which is failing to send messages
+	linkPoor
+)
+
+// qualityOf returns the quality of the link based on its round trip time and send errors
+func qualityOf(link tunnel.Link) linkQuality {
+	if link == nil || link.State() != "connected" {
+		return linkPoor
+	}
+	m := link.Metrics()
+	if m.MessagesSent > 0 && float64(m.Errors)/float64(m.MessagesSent) > PoorLinkErrorRate {
+		return linkPoor
+	}
+	if time.Duration(link.Length()) > ConstrainedLinkLength {
+		return linkConstrained
+	}
+	return linkGood
+}
+
+// peerSync is the state of the syncs sent to a peer
+type peerSync struct {
+	// the metrics of the routes sent to the peer, by route hash
+	routes map[uint64]int64
+	// when the peer was last sent a sync
+	last time.Time
+	// when the peer was last sent a full sync
+	full time.Time
+}
+
+// peerLink returns the link to the peer, which is the link the peer was last heard from or the
+// best link to its address
+func (n *mucpNetwork) peerLink(peer *node) tunnel.Link {
+	if len(peer.link) > 0 {
+		for _, link := range n.tunnel.Links() {
+			if link.Id() == peer.link {
+				return link
+			}
+		}
+	}
+	n.RLock()
+	defer n.RUnlock()
+	return n.peerLinks[peer.address]
+}
+
+// nextSyncPeer picks a random peer which is due a sync, skipping peers over poor links and peers
+// over constrained links which were synced within ConstrainedSyncTime
+func (n *mucpNetwork) nextSyncPeer(rnd *rand.Rand) *node {
+	now := time.Now()
+	peers := n.Peers()
+
+	var due []string
+	for _, p := range peers {
+		peer, ok := p.(*node)
+		if !ok {
+			continue
+		}
+		switch qualityOf(n.peerLink(peer)) {
+		case linkPoor:
+			continue
+		case linkConstrained:
+			n.RLock()
+			s, ok := n.syncs[peer.id]
+			n.RUnlock()
+			if ok && now.Sub(s.last) < ConstrainedSyncTime {
+				continue
+			}
+		}
+		due = append(due, peer.id)
+	}
+
+	// forget the peers and links which have gone
+	current := make(map[string]bool, len(peers))
+	for _, p := range peers {
+		current[p.Id()] = true
+	}
+	links := make(map[string]bool)
+	for _, link := range n.tunnel.Links() {
+		links[link.Id()] = true
+	}
+	n.Lock()
+	for id := range n.syncs {
+		if !current[id] {
+			delete(n.syncs, id)
+		}
+	}
+	for id := range n.encodings {
+		if !links[id] {
+			delete(n.encodings, id)
+		}
+	}
+	n.Unlock()
+
+	if len(due) == 0 {
+		return nil
+	}
+	return n.node.GetPeerNode(due[rnd.Intn(len(due))])
+}
+
+// sendSync sends the peer graph and routing table to the peer. Unless a full sync is requested or
+// the peer is due one, only the routes which changed since the last sync to the peer are sent.
+func (n *mucpNetwork) sendSync(peer *node, full bool) {
+	msg := &pb.Sync{
+		Peer: PeersToProto(n.node, MaxDepth),
+	}
+
+	// get a list of the best routes for each service in our routing table
+	routes, err := n.getProtoRoutes()
+	if err != nil {
+		if logger.V(logger.DebugLevel, logger.DefaultLogger) {
+			logger.Debugf("Network node %s failed listing routes: %v", n.id, err)
+		}
+	}
+
+	now := time.Now()
+	sent := make(map[uint64]int64, len(routes))
+	for _, r := range routes {
+		sent[routeHash(r)] = r.Metric
+	}
+
+	n.RLock()
+	prev, ok := n.syncs[peer.id]
+	n.RUnlock()
+	if !ok || now.Sub(prev.full) > FullSyncTime {
+		full = true
+	}
+
+	if full {
+		msg.Routes = routes
+	} else {
+		for _, r := range routes {
+			if metric, ok := prev.routes[routeHash(r)]; !ok || metric != r.Metric {
+				msg.Routes = append(msg.Routes, r)
+			}
+		}
+	}
+
+	if err := n.sendTo("sync", NetworkChannel, peer, msg); err != nil {
+		if logger.V(logger.DebugLevel, logger.DefaultLogger) {
+			logger.Debugf("Network failed to send sync message: %v", err)
+		}
+		return
+	}
+
+	state := &peerSync{routes: sent, last: now, full: now}
+	if !full {
+		state.full = prev.full
+	}
+	n.Lock()
+	n.syncs[peer.id] = state
+	n.Unlock()
+
+	if logger.V(logger.TraceLevel, logger.DefaultLogger) {
+		logger.Tracef("Network sent %d of %d routes to %s (full: %v)", len(msg.Routes), len(routes), peer.id, full)
+	}
+}
+
+// routeHash returns the hash identifying the route, which doesn't include its metric
+func routeHash(r *pb.Route) uint64 {
+	route := ProtoToRoute(r)
+	return route.Hash()
+}
+
+// acceptsGzip records that the node at the other end of the link can decode gzipped messages
+func (n *mucpNetwork) acceptsGzip(link string) {
+	n.Lock()
+	n.encodings[link] = true
+	n.Unlock()
+}
+
+// encode compresses large messages sent over constrained links to peers which can decode them
+func (n *mucpNetwork) encode(link tunnel.Link, msg *transport.Message) {
+	msg.Header[acceptEncodingHeader] = gzipEncoding
+	if link == nil || len(msg.Body) < CompressThreshold || qualityOf(link) != linkConstrained {
+		return
+	}
+	n.RLock()
+	ok := n.encodings[link.Id()]
+	n.RUnlock()
+	if !ok {
+		return
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(msg.Body); err != nil {
+		return
+	}
+	if err := w.Close(); err != nil {
+		return
+	}
+	msg.Body = buf.Bytes()
+	msg.Header[encodingHeader] = gzipEncoding
+}
+
+// decode decompresses the body of the message if it's encoded
+func decode(msg *transport.Message) error {
+	if msg.Header[encodingHeader] != gzipEncoding {
+		return nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(msg.Body))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	msg.Body = body
+	delete(msg.Header, encodingHeader)
+	return nil
+}
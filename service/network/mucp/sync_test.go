@@ -0,0 +1,79 @@
+package mucp
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/network/transport"
+	"github.com/micro/micro/v3/service/network/tunnel"
+)
+
+type testLink struct {
+	tunnel.Link
+	id      string
+	state   string
+	length  time.Duration
+	metrics tunnel.LinkMetrics
+}
+
+func (l *testLink) Id() string                  { return l.id }
+func (l *testLink) State() string               { return l.state }
+func (l *testLink) Length() int64               { return l.length.Nanoseconds() }
+func (l *testLink) Metrics() tunnel.LinkMetrics { return l.metrics }
+
+func TestLinkQuality(t *testing.T) {
+	testData := []struct {
+		link    *testLink
+		quality linkQuality
+	}{
+		{&testLink{state: "connected", length: time.Millisecond * 20}, linkGood},
+		{&testLink{state: "connected", length: time.Second}, linkConstrained},
+		{&testLink{state: "connected", metrics: tunnel.LinkMetrics{MessagesSent: 10, Errors: 5}}, linkPoor},
+		{&testLink{state: "closed"}, linkPoor},
+	}
+
+	for _, d := range testData {
+		if q := qualityOf(d.link); q != d.quality {
+			t.Errorf("Expected link quality %d, got %d", d.quality, q)
+		}
+	}
+	if q := qualityOf(nil); q != linkPoor {
+		t.Errorf("Expected a missing link to be poor, got %d", q)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	n := &mucpNetwork{encodings: make(map[string]bool)}
+	slow := &testLink{id: "slow", state: "connected", length: time.Second}
+	fast := &testLink{id: "fast", state: "connected", length: time.Millisecond}
+	body := bytes.Repeat([]byte("route"), CompressThreshold)
+
+	send := func(link tunnel.Link) *transport.Message {
+		msg := &transport.Message{Header: map[string]string{}, Body: body}
+		n.encode(link, msg)
+		return msg
+	}
+
+	// messages aren't compressed until the peer accepts compressed messages
+	if msg := send(slow); msg.Header[encodingHeader] != "" {
+		t.Fatalf("Expected message to not be compressed for a peer which doesn't accept it")
+	}
+
+	n.acceptsGzip("slow")
+	n.acceptsGzip("fast")
+	if msg := send(fast); msg.Header[encodingHeader] != "" {
+		t.Fatalf("Expected message over a fast link to not be compressed")
+	}
+
+	msg := send(slow)
+	if msg.Header[encodingHeader] != gzipEncoding || len(msg.Body) >= len(body) {
+		t.Fatalf("Expected message over a constrained link to be compressed")
+	}
+	if err := decode(msg); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(msg.Body, body) {
+		t.Fatalf("Expected decoded body to match the original")
+	}
+}
@@ -21,10 +21,12 @@ import (
 	grpcProxy "github.com/micro/micro/v3/service/proxy/grpc"
 	mucpProxy "github.com/micro/micro/v3/service/proxy/mucp"
 	"github.com/micro/micro/v3/service/router"
+	"github.com/micro/micro/v3/service/router/history"
 	"github.com/micro/micro/v3/service/server"
 	mucpServer "github.com/micro/micro/v3/service/server/mucp"
 	"github.com/micro/micro/v3/util/helper"
 	"github.com/micro/micro/v3/util/muxer"
+	"github.com/micro/micro/v3/util/namespace"
 	"github.com/urfave/cli/v2"
 )
 
@@ -74,6 +76,23 @@ var (
 			Usage:   "Set the micro network token for authentication",
 			EnvVars: []string{"MICRO_NETWORK_TOKEN"},
 		},
+		&cli.DurationFlag{
+			Name:    "key_rotation",
+			Usage:   "Set how often the session keys of the tunnel links are rotated",
+			EnvVars: []string{"MICRO_NETWORK_KEY_ROTATION"},
+			Value:   tunnel.DefaultKeyRotation,
+		},
+		&cli.StringFlag{
+			Name:    "relays",
+			Usage:   "Set the relays to connect through when the node can't accept connections, e.g. behind NAT. This can be a comma separated list.",
+			EnvVars: []string{"MICRO_NETWORK_RELAYS"},
+		},
+		&cli.IntFlag{
+			Name:    "max_relays",
+			Usage:   "Set the number of relays to stay connected to, the others are used as fallbacks",
+			EnvVars: []string{"MICRO_NETWORK_MAX_RELAYS"},
+			Value:   tunnel.DefaultMaxRelays,
+		},
 	}
 )
 
@@ -113,6 +132,11 @@ func Run(ctx *cli.Context) error {
 	tunOpts := []tunnel.Option{
 		tunnel.Address(peerAddress),
 		tunnel.Token(token),
+		tunnel.KeyRotation(ctx.Duration("key_rotation")),
+		tunnel.MaxRelays(ctx.Int("max_relays")),
+	}
+	if len(ctx.String("relays")) > 0 {
+		tunOpts = append(tunOpts, tunnel.Relays(strings.Split(ctx.String("relays"), ",")...))
 	}
 
 	if ctx.Bool("enable_tls") {
@@ -147,6 +171,11 @@ func Run(ctx *cli.Context) error {
 		router.Cache(),
 	)
 
+	// restore the routes created through the router which aren't learnt from the registry
+	if err := history.Restore(rtr.Table(), namespace.DefaultNamespace); err != nil {
+		log.Warnf("Error restoring routes: %v", err)
+	}
+
 	// create new network
 	netService := mucp.NewNetwork(
 		net.Id(id),
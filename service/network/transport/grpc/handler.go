@@ -21,6 +21,7 @@ import (
 	"github.com/micro/micro/v3/service/errors"
 	"github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/network/transport"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/peer"
 )
 
@@ -40,6 +41,9 @@ func (m *microTransport) Stream(ts pb.Transport_StreamServer) (err error) {
 	p, ok := peer.FromContext(ts.Context())
 	if ok {
 		sock.remote = p.Addr.String()
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			sock.tls = &info.State
+		}
 	}
 
 	defer func() {
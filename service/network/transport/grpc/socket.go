@@ -15,6 +15,8 @@
 package grpc
 
 import (
+	"crypto/tls"
+
 	pb "github.com/micro/micro/v3/proto/transport"
 	"github.com/micro/micro/v3/service/network/transport"
 	"google.golang.org/grpc"
@@ -32,6 +34,7 @@ type grpcTransportSocket struct {
 	stream pb.Transport_StreamServer
 	local  string
 	remote string
+	tls    *tls.ConnectionState
 }
 
 func (g *grpcTransportClient) Local() string {
@@ -80,6 +83,14 @@ func (g *grpcTransportSocket) Remote() string {
 	return g.remote
 }
 
+func (g *grpcTransportSocket) Protocol() string {
+	return "grpc"
+}
+
+func (g *grpcTransportSocket) TLS() *tls.ConnectionState {
+	return g.tls
+}
+
 func (g *grpcTransportSocket) Recv(m *transport.Message) error {
 	if m == nil {
 		return nil
@@ -203,6 +203,14 @@ func (h *httpTransportSocket) Remote() string {
 	return h.remote
 }
 
+func (h *httpTransportSocket) Protocol() string {
+	return h.r.Proto
+}
+
+func (h *httpTransportSocket) TLS() *tls.ConnectionState {
+	return h.r.TLS
+}
+
 func (h *httpTransportSocket) Recv(m *transport.Message) error {
 	if m == nil {
 		return errors.New("message passed in is nil")
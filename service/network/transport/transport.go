@@ -16,6 +16,7 @@
 package transport
 
 import (
+	"crypto/tls"
 	"time"
 )
 
@@ -62,3 +63,12 @@ type ListenOption func(*ListenOptions)
 var (
 	DefaultDialTimeout = time.Second * 5
 )
+
+// ConnState is implemented by sockets which know the state of their connection, so the
+// protocol and tls state of a peer can be exposed to handlers
+type ConnState interface {
+	// Protocol negotiated on the connection, e.g. HTTP/2.0
+	Protocol() string
+	// TLS state of the connection, nil if it isn't secured
+	TLS() *tls.ConnectionState
+}
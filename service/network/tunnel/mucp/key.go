@@ -0,0 +1,150 @@
+package mucp
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/micro/micro/v3/service/network/transport"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+// The session keys of links are established with an ephemeral x25519 key exchange which the
+// dialling side starts when the link is set up and repeats to rotate the key. The shared secret is
+// mixed with the tunnel token, so only nodes which know the token derive the key, and the private
+// keys are discarded once the key is derived so recorded traffic can't be decrypted later.
+
+const (
+	// keyMethod is the link method of the key exchange messages
+	keyMethod = "key"
+	// keyHeader is the public key of the sender of a key exchange message
+	keyHeader = "Micro-Tunnel-Key"
+	// keyIdHeader is the id of the session key being exchanged
+	keyIdHeader = "Micro-Tunnel-Key-Id"
+	// frameKeyHeader is the id of the session key a frame is encrypted with
+	frameKeyHeader = "Micro-Tunnel-Frame-Key"
+)
+
+var (
+	// errPlaintext is returned when a frame isn't encrypted but must be
+	errPlaintext = errors.New("plaintext frame")
+	// errUnknownKey is returned when a frame is encrypted with a key the link doesn't have
+	errUnknownKey = errors.New("frame encrypted with an unknown key")
+	// errInvalidFrame is returned when a decrypted frame can't be decoded
+	errInvalidFrame = errors.New("invalid frame")
+)
+
+// sessionKey is a key the frames sent over a link are encrypted with
+type sessionKey struct {
+	id  uint64
+	gcm cipher.AEAD
+}
+
+// keyExchange is an exchange started by the dialling side, waiting for the public key of the peer
+type keyExchange struct {
+	id   uint64
+	priv []byte
+	pub  []byte
+}
+
+// newKeyPair generates an ephemeral x25519 key pair
+func newKeyPair() ([]byte, []byte, error) {
+	priv := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(priv); err != nil {
+		return nil, nil, err
+	}
+	pub, err := curve25519.X25519(priv, curve25519.Basepoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	return priv, pub, nil
+}
+
+// deriveKey derives the session key from the private key of this side and the public key of the
+// peer. The public keys of the dialling and listening sides bind the key to the exchange.
+func deriveKey(token string, id uint64, priv, peer, dialer, listener []byte) (*sessionKey, error) {
+	secret, err := curve25519.X25519(priv, peer)
+	if err != nil {
+		return nil, err
+	}
+
+	info := make([]byte, 0, 32+len(dialer)+len(listener))
+	info = append(info, "micro tunnel link key"...)
+	info = append(info, dialer...)
+	info = append(info, listener...)
+
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, []byte(token), info), key); err != nil {
+		return nil, err
+	}
+	c, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(c)
+	if err != nil {
+		return nil, err
+	}
+	return &sessionKey{id: id, gcm: gcm}, nil
+}
+
+// encodeFrame encodes the headers and body of a message so they're encrypted together
+func encodeFrame(m *transport.Message) []byte {
+	size := binary.MaxVarintLen64 * (1 + 2*len(m.Header))
+	for k, v := range m.Header {
+		size += len(k) + len(v)
+	}
+	b := make([]byte, 0, size+len(m.Body))
+
+	var n [binary.MaxVarintLen64]byte
+	put := func(s []byte) {
+		b = append(b, n[:binary.PutUvarint(n[:], uint64(len(s)))]...)
+		b = append(b, s...)
+	}
+
+	b = append(b, n[:binary.PutUvarint(n[:], uint64(len(m.Header)))]...)
+	for k, v := range m.Header {
+		put([]byte(k))
+		put([]byte(v))
+	}
+	return append(b, m.Body...)
+}
+
+// decodeFrame decodes a frame encoded with encodeFrame into the message
+func decodeFrame(b []byte, m *transport.Message) error {
+	next := func() ([]byte, error) {
+		l, n := binary.Uvarint(b)
+		if n <= 0 || uint64(len(b)-n) < l {
+			return nil, errInvalidFrame
+		}
+		s := b[n : n+int(l)]
+		b = b[n+int(l):]
+		return s, nil
+	}
+
+	count, n := binary.Uvarint(b)
+	if n <= 0 || count > uint64(len(b)) {
+		return errInvalidFrame
+	}
+	b = b[n:]
+
+	m.Header = make(map[string]string, count)
+	for i := uint64(0); i < count; i++ {
+		k, err := next()
+		if err != nil {
+			return err
+		}
+		v, err := next()
+		if err != nil {
+			return err
+		}
+		m.Header[string(k)] = string(v)
+	}
+	m.Body = b
+	return nil
+}
@@ -0,0 +1,118 @@
+package mucp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/network/transport"
+	"github.com/micro/micro/v3/service/network/transport/memory"
+)
+
+// testLinks returns the dialling and listening sides of a link
+func testLinks(t *testing.T, dialToken, listenToken string) (*link, *link) {
+	tr := memory.NewTransport()
+	l, err := tr.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accepted := make(chan *link, 1)
+	go l.Accept(func(sock transport.Socket) {
+		lnk := newLink(sock, listenToken, 0)
+		accepted <- lnk
+		<-lnk.closed
+	})
+
+	c, err := tr.Dial(l.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dialer := newLink(c, dialToken, 0)
+	t.Cleanup(func() { dialer.Close() })
+	if err := dialer.handshake(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	listener := <-accepted
+	t.Cleanup(func() { listener.Close() })
+	// close the listener first, closing the sockets blocks while they're receiving
+	t.Cleanup(func() { l.Close() })
+	return dialer, listener
+}
+
+func testExchange(t *testing.T, from, to *link, body string) {
+	if err := from.Send(&transport.Message{Header: map[string]string{"Foo": "bar"}, Body: []byte(body)}); err != nil {
+		t.Fatal(err)
+	}
+	m := new(transport.Message)
+	if err := to.Recv(m); err != nil {
+		t.Fatal(err)
+	}
+	if string(m.Body) != body || m.Header["Foo"] != "bar" {
+		t.Fatalf("Expected %s with the header, got %s %v", body, m.Body, m.Header)
+	}
+}
+
+func TestLinkKeyExchange(t *testing.T) {
+	dialer, listener := testLinks(t, "token", "token")
+	testExchange(t, dialer, listener, "hello")
+	testExchange(t, listener, dialer, "world")
+
+	dialer.RLock()
+	first := dialer.key
+	dialer.RUnlock()
+	if first == nil || first.id != 1 {
+		t.Fatalf("Expected the first key to be established, got %v", first)
+	}
+
+	// rotate the key
+	if err := dialer.startExchange(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if dialer.Metrics().KeyRotations == 2 {
+			break
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	testExchange(t, listener, dialer, "rotated")
+	testExchange(t, dialer, listener, "rotated")
+
+	dialer.RLock()
+	key := dialer.key
+	dialer.RUnlock()
+	if key.id != 2 || key == first {
+		t.Fatalf("Expected the key to be rotated, got %v", key.id)
+	}
+	for _, l := range []*link{dialer, listener} {
+		if m := l.Metrics(); m.KeyRotations != 2 || m.MessagesSent == 0 || m.BytesReceived == 0 {
+			t.Fatalf("Unexpected metrics %+v", m)
+		}
+	}
+}
+
+func TestLinkTokenMismatch(t *testing.T) {
+	dialer, listener := testLinks(t, "token", "other")
+
+	// the listener can't decrypt the frames of the dialer
+	dialer.Send(&transport.Message{Body: []byte("hello")})
+	if err := listener.Recv(new(transport.Message)); err == nil {
+		t.Fatal("Expected an error decrypting the frame")
+	}
+}
+
+func TestFrame(t *testing.T) {
+	m := &transport.Message{
+		Header: map[string]string{"Micro-Tunnel": "session", "Empty": ""},
+		Body:   []byte("body"),
+	}
+	var got transport.Message
+	if err := decodeFrame(encodeFrame(m), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Header) != 2 || got.Header["Micro-Tunnel"] != "session" || string(got.Body) != "body" {
+		t.Fatalf("Unexpected frame %v", got)
+	}
+	if err := decodeFrame([]byte{5, 1}, &got); err != errInvalidFrame {
+		t.Fatalf("Expected invalid frame, got %v", err)
+	}
+}
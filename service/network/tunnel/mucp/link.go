@@ -16,17 +16,28 @@ package mucp
 
 import (
 	"bytes"
+	"encoding/base64"
 	"errors"
 	"io"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/network/transport"
+	"github.com/micro/micro/v3/service/network/tunnel"
 )
 
 type link struct {
+	// traffic counters, first so they're aligned for atomic access
+	bytesSent    uint64
+	bytesRecv    uint64
+	messagesSent uint64
+	messagesRecv uint64
+	errors       uint64
+
 	transport.Socket
 
 	// transport to use for connections
@@ -67,6 +78,27 @@ type link struct {
 	rate float64
 	// keep an error count on the link
 	errCount int
+	// whether the link is to a relay
+	relay bool
+	// the address the remote node sees the link connecting from
+	observed string
+	// the tunnel token the session keys are derived with
+	token string
+	// how often the dialling side rotates the session key
+	rotate time.Duration
+	// whether we dialled the link, the dialling side starts the key exchanges
+	outbound bool
+	// the key the frames are sent with
+	key *sessionKey
+	// the previous key, frames sent with it may still be in flight during a rotation
+	prevKey *sessionKey
+	// the key exchange started by the dialling side
+	exchange *keyExchange
+	// closed once the first session key is established
+	keyed chan bool
+	// the number of session keys established and when the current one was
+	rotations uint64
+	rotated   time.Time
 }
 
 // packet send over link
@@ -100,9 +132,12 @@ var (
 	ErrLinkConnectTimeout = errors.New("link connect timeout")
 )
 
-func newLink(s transport.Socket) *link {
+func newLink(s transport.Socket, token string, rotate time.Duration) *link {
 	l := &link{
 		Socket:        s,
+		token:         token,
+		rotate:        rotate,
+		keyed:         make(chan bool),
 		id:            uuid.New().String(),
 		lastKeepAlive: time.Now(),
 		closed:        make(chan bool),
@@ -189,6 +224,12 @@ func (l *link) process() {
 		for {
 			m := new(transport.Message)
 			err := l.recv(m)
+			if err == errPlaintext {
+				if logger.V(logger.DebugLevel, log) {
+					log.Debugf("Link %s dropped plaintext frame", l.Id())
+				}
+				continue
+			}
 			if err != nil {
 				// record the metric
 				select {
@@ -201,6 +242,15 @@ func (l *link) process() {
 
 			pk := &packet{message: m, err: err}
 
+			// the key exchange is handled before the next frame is read, which may be encrypted
+			// with the new key
+			if err == nil && m.Header["Micro-Method"] == keyMethod {
+				if err := l.exchangeKey(m); err != nil {
+					log.Errorf("Link %s key exchange failed: %v", l.Id(), err)
+				}
+				continue
+			}
+
 			// this is our link state packet
 			if m.Header["Micro-Method"] == "link" {
 				// process link state message
@@ -250,6 +300,14 @@ func (l *link) manage() {
 	t2 := time.NewTicker(time.Second * 5)
 	defer t2.Stop()
 
+	// used to rotate the session key
+	var rotate <-chan time.Time
+	if l.rotate > 0 {
+		t3 := time.NewTicker(l.rotate)
+		defer t3.Stop()
+		rotate = t3.C
+	}
+
 	// get link id
 	linkId := l.Id()
 
@@ -343,6 +401,18 @@ func (l *link) manage() {
 				l.record(metric)
 			}
 			l.Unlock()
+		case <-rotate:
+			l.RLock()
+			outbound := l.outbound
+			l.RUnlock()
+
+			// the dialling side rotates the key
+			if !outbound {
+				continue
+			}
+			if err := l.startExchange(); err != nil {
+				log.Errorf("Link %s failed to rotate key: %v", linkId, err)
+			}
 		}
 	}
 }
@@ -366,6 +436,7 @@ func (l *link) record(m *metric) {
 	// there's an error increment the counter and bail
 	if m.status != nil {
 		l.errCount++
+		atomic.AddUint64(&l.errors, 1)
 		return
 	}
 
@@ -386,8 +457,29 @@ func (l *link) send(m *transport.Message) error {
 	if m.Header == nil {
 		m.Header = make(map[string]string)
 	}
+
+	// encrypt the frame once the session key is established
+	l.RLock()
+	key := l.key
+	l.RUnlock()
+	if key != nil {
+		body, err := Encrypt(key.gcm, encodeFrame(m))
+		if err != nil {
+			return err
+		}
+		m = &transport.Message{
+			Header: map[string]string{frameKeyHeader: strconv.FormatUint(key.id, 10)},
+			Body:   body,
+		}
+	}
+
 	// send the message
-	return l.Socket.Send(m)
+	if err := l.Socket.Send(m); err != nil {
+		return err
+	}
+	atomic.AddUint64(&l.bytesSent, uint64(messageSize(m)))
+	atomic.AddUint64(&l.messagesSent, 1)
+	return nil
 }
 
 // recv a message on the link
@@ -396,7 +488,172 @@ func (l *link) recv(m *transport.Message) error {
 		m.Header = make(map[string]string)
 	}
 	// receive the transport message
-	return l.Socket.Recv(m)
+	if err := l.Socket.Recv(m); err != nil {
+		return err
+	}
+	atomic.AddUint64(&l.bytesRecv, uint64(messageSize(m)))
+	atomic.AddUint64(&l.messagesRecv, 1)
+
+	id, ok := m.Header[frameKeyHeader]
+	if !ok {
+		l.RLock()
+		keyed := l.key != nil
+		l.RUnlock()
+
+		// only the key exchange and link state probes are sent before the key is established
+		method := m.Header["Micro-Method"]
+		if keyed || (method != keyMethod && method != "link") {
+			return errPlaintext
+		}
+		return nil
+	}
+
+	l.RLock()
+	key := l.key
+	if key != nil && strconv.FormatUint(key.id, 10) != id {
+		key = l.prevKey
+	}
+	l.RUnlock()
+	if key == nil || strconv.FormatUint(key.id, 10) != id {
+		return errUnknownKey
+	}
+
+	b, err := Decrypt(key.gcm, m.Body)
+	if err != nil {
+		return tunnel.ErrDecryptingData
+	}
+	return decodeFrame(b, m)
+}
+
+// messageSize is the number of bytes of the headers and body of a message
+func messageSize(m *transport.Message) int {
+	size := len(m.Body)
+	for k, v := range m.Header {
+		size += len(k) + len(v)
+	}
+	return size
+}
+
+// handshake establishes the first session key of a link we dialled, waiting until the timeout
+func (l *link) handshake(timeout time.Duration) error {
+	l.Lock()
+	l.outbound = true
+	l.Unlock()
+
+	if err := l.startExchange(); err != nil {
+		return err
+	}
+
+	select {
+	case <-l.keyed:
+		return nil
+	case <-l.closed:
+		return io.EOF
+	case <-time.After(timeout):
+		return tunnel.ErrKeyExchange
+	}
+}
+
+// startExchange sends the public key of a new key pair to the peer, which replies with its own
+func (l *link) startExchange() error {
+	priv, pub, err := newKeyPair()
+	if err != nil {
+		return err
+	}
+
+	l.Lock()
+	var id uint64 = 1
+	if l.key != nil {
+		id = l.key.id + 1
+	}
+	l.exchange = &keyExchange{id: id, priv: priv, pub: pub}
+	l.Unlock()
+
+	return l.Send(keyMessage(id, pub))
+}
+
+// exchangeKey processes a key exchange message. A request from the dialling side is answered with
+// the public key of this side, a response completes the exchange we started.
+func (l *link) exchangeKey(m *transport.Message) error {
+	peer, err := base64.StdEncoding.DecodeString(m.Header[keyHeader])
+	if err != nil {
+		return err
+	}
+	id, err := strconv.ParseUint(m.Header[keyIdHeader], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	l.RLock()
+	outbound := l.outbound
+	ex := l.exchange
+	current := l.key
+	l.RUnlock()
+
+	// the response to the exchange we started
+	if outbound {
+		if ex == nil || ex.id != id {
+			return tunnel.ErrKeyExchange
+		}
+		key, err := deriveKey(l.token, id, ex.priv, peer, ex.pub, peer)
+		if err != nil {
+			return err
+		}
+		l.Lock()
+		if l.exchange == ex {
+			l.exchange = nil
+		}
+		l.setKey(key)
+		l.Unlock()
+		return nil
+	}
+
+	// keys can't be reused
+	if current != nil && id <= current.id {
+		return tunnel.ErrKeyExchange
+	}
+
+	priv, pub, err := newKeyPair()
+	if err != nil {
+		return err
+	}
+	key, err := deriveKey(l.token, id, priv, peer, peer, pub)
+	if err != nil {
+		return err
+	}
+
+	// the response is sent with the current key, the frames after it with the new key
+	if err := l.Send(keyMessage(id, pub)); err != nil {
+		return err
+	}
+	l.Lock()
+	l.setKey(key)
+	l.Unlock()
+	return nil
+}
+
+// setKey replaces the session key, the lock must be held
+func (l *link) setKey(key *sessionKey) {
+	l.prevKey = l.key
+	l.key = key
+	l.rotations++
+	l.rotated = time.Now()
+
+	select {
+	case <-l.keyed:
+	default:
+		close(l.keyed)
+	}
+}
+
+func keyMessage(id uint64, pub []byte) *transport.Message {
+	return &transport.Message{
+		Header: map[string]string{
+			"Micro-Method": keyMethod,
+			keyHeader:      base64.StdEncoding.EncodeToString(pub),
+			keyIdHeader:    strconv.FormatUint(id, 10),
+		},
+	}
 }
 
 // Delay is the current load on the link
@@ -419,6 +676,22 @@ func (l *link) Loopback() bool {
 	return lo
 }
 
+// Relay returns true if the link is to a relay
+func (l *link) Relay() bool {
+	l.RLock()
+	r := l.relay
+	l.RUnlock()
+	return r
+}
+
+// Observed returns the address the remote node sees the link connecting from
+func (l *link) Observed() string {
+	l.RLock()
+	o := l.observed
+	l.RUnlock()
+	return o
+}
+
 // Length returns the roundtrip time as nanoseconds (lower is better).
 // Returns 0 where no measurement has been taken.
 func (l *link) Length() int64 {
@@ -459,12 +732,7 @@ func (l *link) Send(m *transport.Message) error {
 	}
 
 	// calculate the data sent
-	dataSent := len(m.Body)
-
-	// set header length
-	for k, v := range m.Header {
-		dataSent += (len(k) + len(v))
-	}
+	dataSent := messageSize(m)
 
 	// get time now
 	now := time.Now()
@@ -530,6 +798,23 @@ func (l *link) Recv(m *transport.Message) error {
 	return nil
 }
 
+// Metrics of the traffic on the link
+func (l *link) Metrics() tunnel.LinkMetrics {
+	l.RLock()
+	rotations, rotated := l.rotations, l.rotated
+	l.RUnlock()
+
+	return tunnel.LinkMetrics{
+		BytesSent:        atomic.LoadUint64(&l.bytesSent),
+		BytesReceived:    atomic.LoadUint64(&l.bytesRecv),
+		MessagesSent:     atomic.LoadUint64(&l.messagesSent),
+		MessagesReceived: atomic.LoadUint64(&l.messagesRecv),
+		Errors:           atomic.LoadUint64(&l.errors),
+		KeyRotations:     rotations,
+		KeyRotated:       rotated,
+	}
+}
+
 // State can return connected, closed, error
 func (l *link) State() string {
 	select {
@@ -66,6 +66,9 @@ type tun struct {
 	// outbound links
 	links map[string]*link
 
+	// the state of the relays, keyed by address
+	relays map[string]*relay
+
 	// listener
 	listener transport.Listener
 }
@@ -86,6 +89,7 @@ func NewTunnel(opts ...tunnel.Option) *tun {
 		closed:   make(chan bool),
 		sessions: make(map[string]*session),
 		links:    make(map[string]*link),
+		relays:   make(map[string]*relay),
 	}
 }
 
@@ -239,6 +243,7 @@ func (t *tun) manage(reconnect time.Duration) {
 			return
 		case <-r.C:
 			t.manageLinks()
+			t.manageRelays()
 		}
 	}
 }
@@ -632,11 +637,9 @@ func (t *tun) listen(link *link) {
 			return
 		}
 
-		// TODO: figure out network authentication
-		// for now we use tunnel token to encrypt/decrypt
-		// session communication, but we will probably need
-		// some sort of network authentication (token) to avoid
-		// having rogue actors spamming the network
+		// the link only passes on frames encrypted with a session key
+		// derived using the tunnel token, so nodes which don't know
+		// the token can't send messages over the tunnel
 
 		// message type
 		mtype := msg.Header["Micro-Tunnel"]
@@ -686,6 +689,13 @@ func (t *tun) listen(link *link) {
 			t.links[link.Remote()] = link
 			t.Unlock()
 
+			// report the address the link was accepted from
+			link.RLock()
+			inbound := !link.outbound
+			link.RUnlock()
+			if inbound {
+				go t.sendObserved(link)
+			}
 			// send back an announcement of our channels discovery
 			go t.announce("", "", link)
 			// ask for the things on the other wise
@@ -806,6 +816,9 @@ func (t *tun) listen(link *link) {
 			// send back an announcement
 			go t.announce(channel, sessionId, link)
 			continue
+		case observeMethod:
+			t.setObserved(link, msg.Header[observedHeader])
+			continue
 		default:
 			// blackhole it
 			continue
@@ -932,13 +945,22 @@ func (t *tun) setupLink(node string) (*link, error) {
 		log.Debugf("Tunnel connected to %s", node)
 	}
 	// create a new link
-	link := newLink(c)
+	link := newLink(c, t.token, t.options.KeyRotation)
 
 	// set link id to remote side
 	link.Lock()
 	link.id = c.Remote()
 	link.Unlock()
 
+	// establish the session key before anything else is sent
+	if err := link.handshake(tunnel.DefaultDialTimeout); err != nil {
+		if logger.V(logger.DebugLevel, log) {
+			log.Debugf("Tunnel failed to exchange keys with %s: %v", node, err)
+		}
+		link.Close()
+		return nil, err
+	}
+
 	// send the first connect message
 	if err := t.sendMsg("connect", link); err != nil {
 		link.Close()
@@ -1007,7 +1029,7 @@ func (t *tun) connect() error {
 				log.Debugf("Tunnel accepted connection from %s", sock.Remote())
 			}
 			// create a new link
-			link := newLink(sock)
+			link := newLink(sock, t.token, t.options.KeyRotation)
 
 			// manage the link
 			go t.manageLink(link)
@@ -1060,6 +1082,9 @@ func (t *tun) Connect() error {
 	// call setup before managing them
 	t.setupLinks()
 
+	// connect to the relays
+	go t.manageRelays()
+
 	// manage the links
 	go t.manage(ReconnectTime)
 
@@ -0,0 +1,167 @@
+package mucp
+
+import (
+	"net"
+	"sort"
+	"time"
+
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/network/transport"
+	"github.com/micro/micro/v3/util/backoff"
+)
+
+// Nodes behind NAT or a firewall can't accept links, so they connect out to relays: publicly
+// reachable nodes which route the traffic of the network to them over the links they dialled. The
+// tunnel keeps links to the MaxRelays relays with the lowest round trip time and falls back to the
+// others when a relay can't be reached. Much like STUN, the node a link is accepted by reports the
+// address it sees the link coming from, so a node can tell it's behind NAT.
+
+const (
+	// observeMethod is the link method of the message reporting the observed address
+	observeMethod = "observe"
+	// observedHeader is the address the link was observed connecting from
+	observedHeader = "Micro-Tunnel-Observed"
+)
+
+// relay is the state of a relay the tunnel can connect through
+type relay struct {
+	// whether the tunnel has a link to the relay
+	connected bool
+	// the round trip time of the last link to the relay
+	length int64
+	// the number of consecutive failures to connect to the relay
+	failures int
+	// when the relay can be dialled again after a failure
+	retry time.Time
+}
+
+// manageRelays replaces the links to relays which have failed, dialling the best relays first
+func (t *tun) manageRelays() {
+	t.Lock()
+	max := t.options.MaxRelays
+	now := time.Now()
+
+	var connected int
+	var candidates []string
+	for _, addr := range t.options.Relays {
+		r, ok := t.relays[addr]
+		if !ok {
+			r = new(relay)
+			t.relays[addr] = r
+		}
+
+		if l, ok := t.links[addr]; ok && l.State() == "connected" {
+			if length := l.Length(); length > 0 {
+				r.length = length
+			}
+			r.connected = true
+			connected++
+			continue
+		}
+
+		// the link to the relay has failed since it was last checked
+		if r.connected {
+			r.connected = false
+			t.relayFailed(r, now)
+		}
+		if now.After(r.retry) {
+			candidates = append(candidates, addr)
+		}
+	}
+
+	// relays which haven't failed come first, then the fastest of the relays we've been connected
+	// to, then the relays we haven't been connected to yet in the order they were configured
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ri, rj := t.relays[candidates[i]], t.relays[candidates[j]]
+		if ri.failures != rj.failures {
+			return ri.failures < rj.failures
+		}
+		if (ri.length == 0) != (rj.length == 0) {
+			return ri.length > 0
+		}
+		return ri.length < rj.length
+	})
+	t.Unlock()
+
+	// fall back to the next relay until enough are connected
+	for _, addr := range candidates {
+		if connected >= max {
+			return
+		}
+
+		link, err := t.setupLink(addr)
+
+		t.Lock()
+		r := t.relays[addr]
+		if err != nil {
+			if logger.V(logger.DebugLevel, log) {
+				log.Debugf("Tunnel failed to connect to relay %s: %v", addr, err)
+			}
+			t.relayFailed(r, time.Now())
+			t.Unlock()
+			continue
+		}
+		if _, ok := t.links[addr]; ok {
+			link.Close()
+			t.Unlock()
+			continue
+		}
+
+		link.Lock()
+		link.relay = true
+		link.Unlock()
+		t.links[addr] = link
+		r.connected = true
+		r.failures = 0
+		connected++
+		t.Unlock()
+
+		log.Infof("Tunnel connected to relay %s", addr)
+	}
+
+	if connected < max && len(t.options.Relays) > 0 && logger.V(logger.DebugLevel, log) {
+		log.Debugf("Tunnel connected to %d of %d relays", connected, max)
+	}
+}
+
+// relayFailed backs off dialling a relay, the lock must be held
+func (t *tun) relayFailed(r *relay, now time.Time) {
+	r.failures++
+	r.retry = now.Add(backoff.Do(r.failures))
+}
+
+// sendObserved reports the address an accepted link was observed connecting from
+func (t *tun) sendObserved(link *link) error {
+	return link.Send(&transport.Message{
+		Header: map[string]string{
+			"Micro-Tunnel":    observeMethod,
+			"Micro-Tunnel-Id": t.id,
+			observedHeader:    link.Remote(),
+		},
+	})
+}
+
+// setObserved saves the address a link was observed connecting from
+func (t *tun) setObserved(link *link, addr string) {
+	link.Lock()
+	link.observed = addr
+	link.Unlock()
+
+	if behindNAT(link.Local(), addr) {
+		log.Infof("Tunnel link to %s observed from %s, the node appears to be behind NAT", link.Remote(), addr)
+	}
+}
+
+// behindNAT returns true if the address a link was observed connecting from isn't the local
+// address of the link
+func behindNAT(local, observed string) bool {
+	lhost, _, err := net.SplitHostPort(local)
+	if err != nil {
+		return false
+	}
+	ohost, _, err := net.SplitHostPort(observed)
+	if err != nil {
+		return false
+	}
+	return lhost != ohost
+}
@@ -0,0 +1,93 @@
+package mucp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/network/transport"
+	"github.com/micro/micro/v3/service/network/tunnel"
+)
+
+func TestRelay(t *testing.T) {
+	// the relay accepts the links of nodes which can't accept links themselves
+	relay := NewTunnel(
+		tunnel.Address("127.0.0.1:9098"),
+	)
+	if err := relay.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer relay.Close()
+
+	// the first relay is down so the node falls back to the second
+	node := NewTunnel(
+		tunnel.Address("127.0.0.1:9099"),
+		tunnel.Relays("127.0.0.1:9100", "127.0.0.1:9098"),
+		tunnel.MaxRelays(1),
+	)
+	if err := node.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer node.Close()
+
+	var link tunnel.Link
+	for i := 0; i < 100 && link == nil; i++ {
+		for _, l := range node.Links() {
+			if l.Relay() && len(l.Observed()) > 0 {
+				link = l
+			}
+		}
+		time.Sleep(time.Millisecond * 20)
+	}
+	if link == nil {
+		t.Fatal("Expected a link to the relay")
+	}
+	if link.Remote() != "127.0.0.1:9098" {
+		t.Fatalf("Expected the link to be to the second relay, got %v", link.Remote())
+	}
+
+	node.RLock()
+	failed := node.relays["127.0.0.1:9100"]
+	node.RUnlock()
+	if failed.failures != 1 || !failed.retry.After(time.Now().Add(-time.Second)) {
+		t.Fatalf("Expected the relay which is down to be backed off, got %+v", failed)
+	}
+
+	// the relay can reach the node over the link the node dialled
+	l, err := node.Listen("relay-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	recv := make(chan *transport.Message, 1)
+	go func() {
+		m := new(transport.Message)
+		if s, err := l.Accept(); err == nil {
+			s.Recv(m)
+		}
+		recv <- m
+	}()
+
+	c, err := relay.Dial("relay-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err := c.Send(&transport.Message{Header: map[string]string{"test": "relay"}}); err != nil {
+		t.Fatal(err)
+	}
+	if m := <-recv; m.Header["test"] != "relay" {
+		t.Fatalf("Expected the message sent by the relay, got %v", m.Header)
+	}
+}
+
+func TestBehindNAT(t *testing.T) {
+	if behindNAT("10.0.0.2:4000", "10.0.0.2:4000") {
+		t.Fatal("Expected the node not to be behind NAT")
+	}
+	if !behindNAT("10.0.0.2:4000", "203.0.113.7:61000") {
+		t.Fatal("Expected the node to be behind NAT")
+	}
+	if behindNAT("10.0.0.2:4000", "") {
+		t.Fatal("Expected the node not to be behind NAT without an observed address")
+	}
+}
@@ -27,6 +27,10 @@ var (
 	DefaultAddress = ":0"
 	// The shared default token
 	DefaultToken = "go.micro.tunnel"
+	// DefaultKeyRotation is how often the session keys of links are rotated
+	DefaultKeyRotation = time.Minute * 10
+	// DefaultMaxRelays is the number of relays a tunnel keeps links to
+	DefaultMaxRelays = 2
 )
 
 type Option func(*Options)
@@ -43,6 +47,13 @@ type Options struct {
 	Token string
 	// Transport listens to incoming connections
 	Transport transport.Transport
+	// KeyRotation is how often the session keys of the links dialled are rotated
+	KeyRotation time.Duration
+	// Relays are publicly reachable nodes which nodes behind NAT or a firewall connect out to,
+	// so they can join the network without accepting inbound connections
+	Relays []string
+	// MaxRelays is the number of relays links are kept to, the others are fallbacks
+	MaxRelays int
 }
 
 type DialOption func(*DialOptions)
@@ -102,6 +113,27 @@ func Transport(t transport.Transport) Option {
 	}
 }
 
+// KeyRotation sets how often the session keys of the links dialled are rotated
+func KeyRotation(d time.Duration) Option {
+	return func(o *Options) {
+		o.KeyRotation = d
+	}
+}
+
+// Relays sets the relay nodes to connect through
+func Relays(r ...string) Option {
+	return func(o *Options) {
+		o.Relays = r
+	}
+}
+
+// MaxRelays sets the number of relays links are kept to
+func MaxRelays(n int) Option {
+	return func(o *Options) {
+		o.MaxRelays = n
+	}
+}
+
 // Listen options
 func ListenMode(m Mode) ListenOption {
 	return func(o *ListenOptions) {
@@ -151,9 +183,11 @@ func DialWait(b bool) DialOption {
 // DefaultOptions returns router default options
 func DefaultOptions() Options {
 	return Options{
-		Id:        uuid.New().String(),
-		Address:   DefaultAddress,
-		Token:     DefaultToken,
-		Transport: grpc.NewTransport(),
+		Id:          uuid.New().String(),
+		Address:     DefaultAddress,
+		Token:       DefaultToken,
+		Transport:   grpc.NewTransport(),
+		KeyRotation: DefaultKeyRotation,
+		MaxRelays:   DefaultMaxRelays,
 	}
 }
@@ -50,6 +50,8 @@ var (
 	ErrReadTimeout = errors.New("read timeout")
 	// ErrDecryptingData is for when theres a nonce error
 	ErrDecryptingData = errors.New("error decrypting data")
+	// ErrKeyExchange is returned when the session key of a link couldn't be established
+	ErrKeyExchange = errors.New("link key exchange failed")
 )
 
 // Mode of the session
@@ -90,12 +92,37 @@ type Link interface {
 	Rate() float64
 	// Is this a loopback link
 	Loopback() bool
+	// Is this a link to a relay
+	Relay() bool
+	// Observed returns the address the remote node sees the link connecting from, e.g. the
+	// public address of a node behind NAT. It's blank until the remote node has reported it.
+	Observed() string
 	// State of the link: connected/closed/error
 	State() string
+	// Metrics of the traffic on the link
+	Metrics() LinkMetrics
 	// honours transport socket
 	transport.Socket
 }
 
+// LinkMetrics are the counters of the traffic on a link since it was established
+type LinkMetrics struct {
+	// BytesSent is the number of bytes sent, including the headers
+	BytesSent uint64
+	// BytesReceived is the number of bytes received, including the headers
+	BytesReceived uint64
+	// MessagesSent is the number of messages sent
+	MessagesSent uint64
+	// MessagesReceived is the number of messages received
+	MessagesReceived uint64
+	// Errors is the number of errors sending messages
+	Errors uint64
+	// KeyRotations is the number of session keys established
+	KeyRotations uint64
+	// KeyRotated is when the current session key was established
+	KeyRotated time.Time
+}
+
 // The listener provides similar constructs to the transport.Listener
 type Listener interface {
 	Accept() (Session, error)
@@ -6,7 +6,9 @@ import (
 	// TODO: replace with micro/v3/service/cli
 	"github.com/micro/micro/v3/cmd"
 	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/debug/health"
 	"github.com/micro/micro/v3/service/server"
+	"github.com/micro/micro/v3/util/ownership"
 )
 
 // Options for micro service
@@ -23,6 +25,36 @@ type Options struct {
 	AfterStop   []func() error
 
 	Signal bool
+
+	// Warmup is enabled if set, the dependencies are connected to before the service starts
+	Warmup []string
+	// WarmupTimeout is the longest the warmup can delay the start of the service by
+	WarmupTimeout time.Duration
+
+	// Prime funcs pre-populate the caches before the service starts
+	Prime []PrimeFunc
+	// PrimeTimeout is the longest priming can delay the start of the service by
+	PrimeTimeout time.Duration
+	// PrimeConcurrency is the number of prime funcs run at once
+	PrimeConcurrency int
+
+	// Readiness funcs must pass before the service is registered
+	Readiness []ReadinessFunc
+	// Dependencies is the criticality of the dependencies of the service, by name
+	Dependencies map[string]health.Criticality
+
+	// MTLS authenticates the connections to and from other services with a certificate
+	MTLS bool
+	// MTLSPlaintext dials the services which haven't been issued a certificate without TLS
+	MTLSPlaintext bool
+
+	// Owner of the service, registered with it so alerts are routed to the team
+	Owner *ownership.Owner
+
+	// Jobs are the one-off tasks the service can run, by command
+	Jobs map[string]JobFunc
+	// RunJob is the command of the job to run instead of serving requests
+	RunJob string
 }
 
 func newOptions(opts ...Option) Options {
@@ -139,6 +171,109 @@ func WrapSubscriber(w ...server.SubscriberWrapper) Option {
 	}
 }
 
+// Warmup prefetches the auth token and registry, and connects to the critical dependencies of
+// the service before it starts so the first requests it handles aren't slowed by the setup
+func Warmup(deps ...string) Option {
+	return func(o *Options) {
+		if o.Warmup == nil {
+			o.Warmup = []string{}
+		}
+		o.Warmup = append(o.Warmup, deps...)
+	}
+}
+
+// WarmupTimeout sets the longest the warmup can delay the start of the service by
+func WarmupTimeout(t time.Duration) Option {
+	return func(o *Options) {
+		o.WarmupTimeout = t
+	}
+}
+
+// Prime pre-populates the caches before the service starts, e.g. with PrimeKeys or PrimeCall, so
+// the first requests after a deploy don't all miss them at once
+func Prime(fns ...PrimeFunc) Option {
+	return func(o *Options) {
+		o.Prime = append(o.Prime, fns...)
+	}
+}
+
+// PrimeTimeout sets the longest priming can delay the start of the service by
+func PrimeTimeout(t time.Duration) Option {
+	return func(o *Options) {
+		o.PrimeTimeout = t
+	}
+}
+
+// PrimeConcurrency sets the number of prime funcs run at once
+func PrimeConcurrency(n int) Option {
+	return func(o *Options) {
+		o.PrimeConcurrency = n
+	}
+}
+
+// Readiness adds funcs which must pass before the service is registered. The service is started
+// and answers health checks while it initialises, but isn't discoverable until it's ready.
+func Readiness(fns ...ReadinessFunc) Option {
+	return func(o *Options) {
+		o.Readiness = append(o.Readiness, fns...)
+	}
+}
+
+// Dependency sets the criticality of a dependency of the service. The service isn't ready, so it's
+// deregistered, while its critical dependencies are unhealthy. The store is optional and the
+// services connected to by the warmup are critical unless set otherwise.
+func Dependency(name string, c health.Criticality) Option {
+	return func(o *Options) {
+		if o.Dependencies == nil {
+			o.Dependencies = map[string]health.Criticality{}
+		}
+		o.Dependencies[name] = c
+	}
+}
+
+// Owner sets the team which owns the service and how to reach them. The owner is registered in
+// the metadata of the service and the store of its namespace when it starts.
+func Owner(o ownership.Owner) Option {
+	return func(opts *Options) {
+		opts.Owner = &o
+	}
+}
+
+// MTLS authenticates the connections to and from other services with a certificate issued by the
+// auth service, which is rotated before it expires
+func MTLS(b bool) Option {
+	return func(o *Options) {
+		o.MTLS = b
+	}
+}
+
+// MTLSPlaintext dials the services which don't accept TLS connections, e.g. while certificates
+// are being rolled out, without TLS. Connections to them fail otherwise.
+func MTLSPlaintext(b bool) Option {
+	return func(o *Options) {
+		o.MTLSPlaintext = b
+	}
+}
+
+// Job registers a one-off task, such as a migration or a backfill, which is run with
+// `micro job run <service> --command <command>`
+func Job(command string, fn JobFunc) Option {
+	return func(o *Options) {
+		if o.Jobs == nil {
+			o.Jobs = make(map[string]JobFunc)
+		}
+		o.Jobs[command] = fn
+	}
+}
+
+// RunJob runs the job with the command instead of serving requests, the runtime sets it when the
+// service is started to run a job
+func RunJob(command string) Option {
+	return func(o *Options) {
+		o.RunJob = command
+	}
+}
+
 // Before and Afters
 
 // BeforeStart run funcs before service starts
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/server"
+	"github.com/micro/micro/v3/util/ownership"
+)
+
+// registerOwner sets the owner in the metadata the service is registered with and stores it in
+// the namespace with the account of the service, so it's still known while the service isn't
+// running. An invalid owner stops the service from running whereas an error storing it is
+// logged, the metadata is enough to route alerts while the service is running.
+func (s *Service) registerOwner() error {
+	o := *s.opts.Owner
+	o.Service = s.Name()
+	if err := o.Validate(); err != nil {
+		return fmt.Errorf("invalid owner: %v", err)
+	}
+
+	md := map[string]string{}
+	for k, v := range s.Server().Options().Metadata {
+		md[k] = v
+	}
+	for k, v := range o.Metadata() {
+		md[k] = v
+	}
+	s.Server().Init(server.Metadata(md))
+
+	var acc *auth.Account
+	if tok := auth.DefaultAuth.Options().Token; tok != nil {
+		acc, _ = auth.Inspect(tok.AccessToken)
+	}
+	if err := ownership.Register(s.Server().Options().Namespace, acc, &o); err != nil {
+		logger.Errorf("Error storing the owner of %v: %v", o.Service, err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/server"
+	"github.com/micro/micro/v3/util/policy"
+	"github.com/micro/micro/v3/util/wrapper"
+)
+
+var (
+	// policyAttempts is how many times the policy is loaded before the service stops
+	policyAttempts = 5
+	// policyRetryInterval is how long to wait before loading the policy again
+	policyRetryInterval = time.Second * 2
+
+	// policyExempt are the services the config service depends on, which run before the policy
+	// can be loaded
+	policyExempt = map[string]bool{
+		"registry": true,
+		"broker":   true,
+		"network":  true,
+		"runtime":  true,
+		"config":   true,
+		"store":    true,
+		"events":   true,
+		"auth":     true,
+	}
+)
+
+// applyPolicy applies the middleware policy of the namespace. The handler wrappers required by
+// the policy which the service disabled are applied after the wrappers applied at setup. The
+// policy can't be loaded by the services the config service depends on, so an error loading it
+// is logged by them. Other services stop rather than run without the wrappers it requires, as
+// they do if the policy is invalid.
+func (s *Service) applyPolicy() error {
+	p, err := s.loadPolicy()
+	if err != nil && policyExempt[s.Name()] {
+		logger.Errorf("Error loading the policy of namespace %v: %v", s.Server().Options().Namespace, err)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("error loading the policy: %v", err)
+	} else if p == nil {
+		return nil
+	}
+
+	if err := p.Defaults(); err != nil {
+		return fmt.Errorf("error applying the policy: %v", err)
+	}
+	missing, err := p.Missing(wrapper.AppliedHandlerWrappers)
+	if err != nil {
+		return fmt.Errorf("error applying the policy: %v", err)
+	}
+	hws, err := wrapper.HandlerWrappers(missing...)
+	if err != nil {
+		return fmt.Errorf("error applying the policy: %v", err)
+	}
+	for _, w := range hws {
+		s.Server().Init(server.WrapHandler(w))
+	}
+	wrapper.AppliedHandlerWrappers = append(wrapper.AppliedHandlerWrappers, missing...)
+
+	if len(missing) > 0 {
+		logger.Infof("Applied the handler wrappers %v required by the policy", missing)
+	}
+	return nil
+}
+
+// loadPolicy loads the policy, retrying if the config service is unavailable. The services the
+// config service depends on only try once so they don't delay it starting.
+func (s *Service) loadPolicy() (*policy.Policy, error) {
+	attempts := policyAttempts
+	if policyExempt[s.Name()] {
+		attempts = 1
+	}
+
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			time.Sleep(policyRetryInterval)
+		}
+		var p *policy.Policy
+		if p, err = policy.Load(); err == nil {
+			return p, nil
+		}
+		logger.Warnf("Error loading the policy of namespace %v, attempt %d of %d: %v", s.Server().Options().Namespace, i+1, attempts, err)
+	}
+	return nil, err
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/config"
+)
+
+type unavailableConfig struct {
+	config.Config
+	calls int
+}
+
+func (u *unavailableConfig) Get(path string, options ...config.Option) (config.Value, error) {
+	u.calls++
+	return nil, errors.New("config unavailable")
+}
+
+func TestApplyPolicyUnavailable(t *testing.T) {
+	defer func(c config.Config) { config.DefaultConfig = c }(config.DefaultConfig)
+	defer func(n int, d time.Duration) { policyAttempts, policyRetryInterval = n, d }(policyAttempts, policyRetryInterval)
+	policyAttempts = 2
+	policyRetryInterval = 0
+
+	// services stop rather than run without the wrappers the policy may require
+	c := &unavailableConfig{}
+	config.DefaultConfig = c
+	if err := (&Service{opts: Options{Name: "foo"}}).applyPolicy(); err == nil {
+		t.Fatal("Expected an error applying the policy")
+	}
+	if c.calls != 2 {
+		t.Fatalf("Expected the policy to be loaded twice, got %d", c.calls)
+	}
+
+	// the services the config service depends on run without it
+	c = &unavailableConfig{}
+	config.DefaultConfig = c
+	if err := (&Service{opts: Options{Name: "store"}}).applyPolicy(); err != nil {
+		t.Fatalf("Expected the store to run without the policy, got %v", err)
+	}
+	if c.calls != 1 {
+		t.Fatalf("Expected the policy to be loaded once, got %d", c.calls)
+	}
+}
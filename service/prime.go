@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
+)
+
+var (
+	// DefaultPrimeTimeout is the longest priming can delay the start of the service by
+	DefaultPrimeTimeout = time.Second * 10
+	// DefaultPrimeConcurrency is the number of prime funcs run at once
+	DefaultPrimeConcurrency = 8
+)
+
+// PrimeFunc pre-populates a cache, e.g. by reading the keys a service reads on every request. The
+// context is done when the prime timeout passes.
+type PrimeFunc func(ctx context.Context) error
+
+// PrimeKeys returns a prime func which reads the keys from the table of the default store in a
+// batch, populating the store's read-through cache
+func PrimeKeys(database, table string, keys ...string) PrimeFunc {
+	return func(ctx context.Context) error {
+		for i := 0; i < len(keys); i += store.MaxBatchSize {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			end := i + store.MaxBatchSize
+			if end > len(keys) {
+				end = len(keys)
+			}
+			_, err := store.Batch(store.DefaultStore).ReadMany(keys[i:end], store.ReadFrom(database, table))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// PrimeCall returns a prime func which calls the service, populating the caches of the service
+// called and leaving a connection to it in the client's pool. The response is discarded.
+func PrimeCall(service, endpoint string, req, rsp interface{}, opts ...client.CallOption) PrimeFunc {
+	return func(ctx context.Context) error {
+		r := client.DefaultClient.NewRequest(service, endpoint, req)
+		return client.DefaultClient.Call(ctx, r, rsp, opts...)
+	}
+}
+
+// prime runs the prime funcs before the service starts so the requests it handles after a deploy
+// don't all miss the caches at once. Priming is bounded by the prime timeout and best effort,
+// errors are logged but don't stop the service from starting.
+func (s *Service) prime() {
+	timeout := s.opts.PrimeTimeout
+	if timeout <= 0 {
+		timeout = DefaultPrimeTimeout
+	}
+	concurrency := s.opts.PrimeConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultPrimeConcurrency
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	var mtx sync.Mutex
+	var failed int
+
+	for _, fn := range s.opts.Prime {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(fn PrimeFunc) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := fn(ctx); err != nil {
+				logger.Warnf("Prime error: %v", err)
+				mtx.Lock()
+				failed++
+				mtx.Unlock()
+			}
+		}(fn)
+	}
+
+	// don't wait on the funcs which ignore the context past the timeout
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+
+	if err := ctx.Err(); err != nil {
+		logger.Warnf("Priming stopped after %v: %v", time.Since(start), err)
+		return
+	}
+	logger.Infof("Priming of %v caches completed in %v with %v errors", len(s.opts.Prime), time.Since(start), failed)
+}
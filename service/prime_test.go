@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/memory"
+)
+
+func TestPrime(t *testing.T) {
+	var running, max, calls int32
+	fn := func(ctx context.Context) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(time.Millisecond * 10)
+		return errors.New("prime error")
+	}
+
+	s := &Service{opts: newOptions(Prime(fn, fn, fn, fn, fn), PrimeConcurrency(2))}
+	s.prime()
+
+	if calls != 5 {
+		t.Fatalf("expected 5 prime funcs to run, got %v", calls)
+	}
+	if max > 2 {
+		t.Fatalf("expected at most 2 prime funcs to run at once, got %v", max)
+	}
+}
+
+func TestPrimeTimeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	fn := func(ctx context.Context) error {
+		<-block
+		return nil
+	}
+
+	s := &Service{opts: newOptions(Prime(fn, fn), PrimeTimeout(time.Millisecond*50))}
+
+	start := time.Now()
+	s.prime()
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("expected priming to stop at the timeout, took %v", d)
+	}
+}
+
+func TestPrimeKeys(t *testing.T) {
+	orig := store.DefaultStore
+	defer func() { store.DefaultStore = orig }()
+
+	var keys []string
+	store.DefaultStore = &readStore{Store: memory.NewStore(), keys: &keys}
+	store.DefaultStore.Write(store.NewRecord("foo", "bar"), store.WriteTo("micro", "prime"))
+
+	if err := PrimeKeys("micro", "prime", "foo", "baz")(context.TODO()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "foo" || keys[1] != "baz" {
+		t.Fatalf("expected the keys to be read, got %v", keys)
+	}
+}
+
+type readStore struct {
+	store.Store
+	keys *[]string
+}
+
+func (r *readStore) Read(key string, opts ...store.ReadOption) ([]*store.Record, error) {
+	*r.keys = append(*r.keys, key)
+	return r.Store.Read(key, opts...)
+}
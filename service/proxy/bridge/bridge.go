@@ -0,0 +1,466 @@
+// Package bridge lets a process written in another language serve and call micro services over a
+// local http api exposed by the sidecar, which handles the registry, auth, tracing and the broker
+// on its behalf. The process registers the endpoints it serves and the topics it subscribes to:
+//
+//	POST /register {"address": "http://localhost:9090", "endpoints": [{"name": "Users.Create"}], "subscriptions": [{"topic": "user-created", "path": "/events/user-created"}]}
+//
+// Requests to an endpoint are posted to the address of the process at the path of the endpoint,
+// e.g. /Users/Create, and messages to the path of the subscription. The id of the account which
+// made the request is set in the Micro-Account header and the trace context in the Traceparent
+// header. Errors are returned as a non 2xx status with a micro error as the body.
+//
+// The process calls other services and publishes messages through the bridge, propagating the
+// trace context by setting the Traceparent header of the request:
+//
+//	POST /call {"service": "users", "endpoint": "Users.Read", "request": {"id": "1"}}
+//	POST /publish {"topic": "user-created", "message": {"id": "1"}}
+//
+// Requests and messages are JSON, so callers must use the json content type, e.g. the api and the
+// cli do. The process is trusted with the account of the sidecar, so the bridge only listens on
+// loopback addresses, see ListenAddress.
+package bridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/broker"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/micro/micro/v3/service/debug/trace"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/server"
+)
+
+// Endpoint served by the process
+type Endpoint struct {
+	// Name of the endpoint, e.g. Users.Create
+	Name string `json:"name"`
+	// Metadata of the endpoint advertised in the registry
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Subscription of the process to a topic
+type Subscription struct {
+	Topic string `json:"topic"`
+	// Queue the subscription is in, messages are delivered to one subscriber of a queue. Defaults
+	// to the name of the service so each message is handled once by the service.
+	Queue string `json:"queue,omitempty"`
+	// Path the messages are posted to, defaults to /topic
+	Path string `json:"path,omitempty"`
+}
+
+// Registration of the handlers of the process
+type Registration struct {
+	// Address the process serves its handlers at, e.g. http://localhost:9090
+	Address       string          `json:"address"`
+	Endpoints     []*Endpoint     `json:"endpoints,omitempty"`
+	Subscriptions []*Subscription `json:"subscriptions,omitempty"`
+}
+
+// Validate the registration. The address must be a loopback address since the requests and events
+// forwarded to it carry the metadata and account of the callers.
+func (r *Registration) Validate() error {
+	u, err := url.Parse(r.Address)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return fmt.Errorf("invalid address %q, expected a url e.g. http://localhost:9090", r.Address)
+	}
+	if !isLoopback(u.Hostname()) {
+		return fmt.Errorf("invalid address %q, the handlers must be served at a loopback address", r.Address)
+	}
+	for _, e := range r.Endpoints {
+		if parts := strings.Split(e.Name, "."); len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			return fmt.Errorf("invalid endpoint %q, expected a name e.g. Users.Create", e.Name)
+		}
+	}
+	for _, s := range r.Subscriptions {
+		if len(s.Topic) == 0 {
+			return fmt.Errorf("missing topic")
+		}
+		if len(s.Path) > 0 && !strings.HasPrefix(s.Path, "/") {
+			return fmt.Errorf("invalid path %q of topic %v", s.Path, s.Topic)
+		}
+	}
+	return nil
+}
+
+// ListenAddress returns the address the bridge should listen on, e.g. localhost:9091. The host
+// defaults to 127.0.0.1 and an error is returned if it isn't a loopback address, since anyone who
+// can reach the bridge can call services with the account of the sidecar.
+func ListenAddress(addr string) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", fmt.Errorf("invalid bridge address %q, expected e.g. localhost:9091", addr)
+	}
+	if len(host) == 0 {
+		host = "127.0.0.1"
+	}
+	if !isLoopback(host) {
+		return "", fmt.Errorf("invalid bridge address %q, the bridge must listen on a loopback address", addr)
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// isLoopback returns true if the host is localhost or a loopback ip
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// CallRequest is a request by the process to call an endpoint
+type CallRequest struct {
+	Service  string          `json:"service"`
+	Endpoint string          `json:"endpoint"`
+	Request  json.RawMessage `json:"request,omitempty"`
+}
+
+// PublishRequest is a request by the process to publish a message
+type PublishRequest struct {
+	Topic   string          `json:"topic"`
+	Message json.RawMessage `json:"message"`
+}
+
+// Bridge serves the local http api the process registers its handlers with and routes the
+// requests and messages of the service to them. It's a proxy.Proxy.
+type Bridge struct {
+	options Options
+
+	sync.RWMutex
+	reg  *Registration
+	subs []broker.Subscriber
+}
+
+// New returns a bridge, the process registers its handlers with it before requests are routed
+func New(opts ...Option) *Bridge {
+	options := Options{
+		HTTPClient: &http.Client{Timeout: time.Minute},
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	return &Bridge{options: options}
+}
+
+func (b *Bridge) client() client.Client {
+	if b.options.Client != nil {
+		return b.options.Client
+	}
+	return client.DefaultClient
+}
+
+func (b *Bridge) broker() broker.Broker {
+	if b.options.Broker != nil {
+		return b.options.Broker
+	}
+	return broker.DefaultBroker
+}
+
+func (b *Bridge) server() server.Server {
+	if b.options.Server != nil {
+		return b.options.Server
+	}
+	return server.DefaultServer
+}
+
+// Register the handlers of the process, replacing those previously registered. The service is
+// registered again so the endpoints are advertised immediately.
+func (b *Bridge) Register(reg *Registration) error {
+	if err := reg.Validate(); err != nil {
+		return err
+	}
+	srv := b.server()
+
+	var subs []broker.Subscriber
+	for _, s := range reg.Subscriptions {
+		queue := s.Queue
+		if len(queue) == 0 && srv != nil {
+			queue = srv.Options().Name
+		}
+		s := s
+		sub, err := b.broker().Subscribe(s.Topic, func(m *broker.Message) error {
+			return b.deliver(reg.Address, s, m.Header, m.Body)
+		}, broker.Queue(queue))
+		if err != nil {
+			for _, sub := range subs {
+				sub.Unsubscribe()
+			}
+			return fmt.Errorf("error subscribing to %v: %v", s.Topic, err)
+		}
+		subs = append(subs, sub)
+	}
+
+	b.Lock()
+	old := b.subs
+	b.reg, b.subs = reg, subs
+	b.Unlock()
+
+	for _, sub := range old {
+		sub.Unsubscribe()
+	}
+
+	if r, ok := srv.(interface{ Register() error }); ok {
+		if err := r.Register(); err != nil {
+			logger.Warnf("Error registering the endpoints of the bridge: %v", err)
+		}
+	}
+	return nil
+}
+
+// Registration returns the handlers registered by the process, nil if it hasn't registered
+func (b *Bridge) Registration() *Registration {
+	b.RLock()
+	defer b.RUnlock()
+	return b.reg
+}
+
+// Endpoints returns the endpoints registered by the process, advertised by the server
+func (b *Bridge) Endpoints() []*registry.Endpoint {
+	reg := b.Registration()
+	if reg == nil {
+		return nil
+	}
+
+	var eps []*registry.Endpoint
+	for _, e := range reg.Endpoints {
+		md := map[string]string{}
+		for k, v := range e.Metadata {
+			md[k] = v
+		}
+		eps = append(eps, &registry.Endpoint{Name: e.Name, Metadata: md})
+	}
+	for _, s := range reg.Subscriptions {
+		eps = append(eps, &registry.Endpoint{
+			Name: subscriptionPath(s),
+			Metadata: map[string]string{
+				"topic":      s.Topic,
+				"subscriber": "true",
+			},
+		})
+	}
+	return eps
+}
+
+// ServeRequest routes the request to the endpoint registered by the process
+func (b *Bridge) ServeRequest(ctx context.Context, req server.Request, rsp server.Response) error {
+	reg := b.Registration()
+	if reg == nil || !registered(reg, req.Endpoint()) {
+		return errors.NotFound(req.Service(), "unknown endpoint %v", req.Endpoint())
+	}
+	if !strings.Contains(req.ContentType(), "json") {
+		return errors.BadRequest(req.Service(), "unsupported content type %v, requests must be json", req.ContentType())
+	}
+
+	body, err := req.Read()
+	if err != nil {
+		return err
+	}
+
+	// the header of the request is the metadata of the context, with the account verified by the
+	// server in place of its credentials
+	hdr := http.Header{}
+	if md, ok := metadata.FromContext(ctx); ok {
+		for k, v := range md {
+			hdr.Set(k, v)
+		}
+	}
+	hdr.Del("Authorization")
+	hdr.Del("Micro-Account")
+	if acc, ok := auth.AccountFromContext(ctx); ok {
+		hdr.Set("Micro-Account", acc.ID)
+	}
+	hdr.Set("Micro-Service", req.Service())
+	hdr.Set("Micro-Endpoint", req.Endpoint())
+	hdr.Set("Content-Type", "application/json")
+
+	path := "/" + strings.Replace(req.Endpoint(), ".", "/", 1)
+	out, rspHdr, err := b.post(ctx, req.Service(), reg.Address, path, hdr, body)
+	if err != nil {
+		return err
+	}
+
+	md := map[string]string{}
+	for k := range rspHdr {
+		md[k] = rspHdr.Get(k)
+	}
+	rsp.WriteHeader(md)
+	return rsp.Write(out)
+}
+
+// ProcessMessage delivers the message to the subscriptions of the process to its topic
+func (b *Bridge) ProcessMessage(ctx context.Context, msg server.Message) error {
+	reg := b.Registration()
+	if reg == nil {
+		return nil
+	}
+	for _, s := range reg.Subscriptions {
+		if s.Topic != msg.Topic() {
+			continue
+		}
+		if err := b.deliver(reg.Address, s, msg.Header(), msg.Body()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *Bridge) String() string {
+	return "bridge"
+}
+
+// deliver the message to the subscription
+func (b *Bridge) deliver(address string, s *Subscription, header map[string]string, body []byte) error {
+	hdr := http.Header{}
+	for k, v := range header {
+		hdr.Set(k, v)
+	}
+	hdr.Del("Authorization")
+	hdr.Set("Micro-Topic", s.Topic)
+	_, _, err := b.post(context.Background(), s.Topic, address, subscriptionPath(s), hdr, body)
+	return err
+}
+
+// post the body to the path of the process, returning the body and header of the response. Non
+// 2xx responses are returned as errors.
+func (b *Bridge) post(ctx context.Context, id, address, path string, hdr http.Header, body []byte) ([]byte, http.Header, error) {
+	req, err := http.NewRequest("POST", strings.TrimSuffix(address, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, errors.InternalServerError(id, err.Error())
+	}
+	req = req.WithContext(ctx)
+	req.Header = hdr
+
+	rsp, err := b.options.HTTPClient.Do(req)
+	if err != nil {
+		return nil, nil, errors.BadGateway(id, "error calling the process: %v", err)
+	}
+	defer rsp.Body.Close()
+
+	out, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, nil, errors.BadGateway(id, "error reading the response of the process: %v", err)
+	}
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		if perr := errors.Parse(string(out)); perr.Code > 0 {
+			return nil, nil, perr
+		}
+		return nil, nil, errors.New(id, string(out), int32(rsp.StatusCode))
+	}
+	return out, rsp.Header, nil
+}
+
+// ServeHTTP serves the local http api of the bridge
+func (b *Bridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		writeError(w, errors.MethodNotAllowed("bridge", "%v isn't allowed", r.Method))
+		return
+	}
+
+	switch r.URL.Path {
+	case "/register":
+		var reg *Registration
+		if err := json.NewDecoder(r.Body).Decode(&reg); err != nil || reg == nil {
+			writeError(w, errors.BadRequest("bridge", "invalid registration: %v", err))
+			return
+		}
+		if err := b.Register(reg); err != nil {
+			writeError(w, errors.BadRequest("bridge", err.Error()))
+			return
+		}
+		writeJSON(w, []byte(`{}`))
+	case "/call":
+		var req CallRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, errors.BadRequest("bridge", "invalid request: %v", err))
+			return
+		}
+		if len(req.Service) == 0 || len(req.Endpoint) == 0 {
+			writeError(w, errors.BadRequest("bridge", "missing service or endpoint"))
+			return
+		}
+		if len(req.Request) == 0 {
+			req.Request = json.RawMessage(`{}`)
+		}
+
+		var rsp json.RawMessage
+		creq := b.client().NewRequest(req.Service, req.Endpoint, &req.Request, client.WithContentType("application/json"))
+		if err := b.client().Call(outboundContext(r), creq, &rsp); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, rsp)
+	case "/publish":
+		var req PublishRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, errors.BadRequest("bridge", "invalid request: %v", err))
+			return
+		}
+		if len(req.Topic) == 0 {
+			writeError(w, errors.BadRequest("bridge", "missing topic"))
+			return
+		}
+
+		msg := b.client().NewMessage(req.Topic, &req.Message, client.WithMessageContentType("application/json"))
+		if err := b.client().Publish(outboundContext(r), msg); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, []byte(`{}`))
+	default:
+		writeError(w, errors.NotFound("bridge", "%v not found", r.URL.Path))
+	}
+}
+
+// outboundContext returns the context of a call made by the process, continuing its trace
+func outboundContext(r *http.Request) context.Context {
+	ctx := r.Context()
+	if tp := r.Header.Get(trace.TraceparentKey); len(tp) > 0 {
+		ctx = metadata.Set(ctx, trace.TraceparentKey, tp)
+	}
+	return ctx
+}
+
+func registered(reg *Registration, endpoint string) bool {
+	for _, e := range reg.Endpoints {
+		if e.Name == endpoint {
+			return true
+		}
+	}
+	return false
+}
+
+func subscriptionPath(s *Subscription) string {
+	if len(s.Path) > 0 {
+		return s.Path
+	}
+	return "/" + s.Topic
+}
+
+func writeJSON(w http.ResponseWriter, b []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	merr := errors.FromError(err)
+	if merr.Code == 0 {
+		merr.Code = http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(int(merr.Code))
+	w.Write([]byte(merr.Error()))
+}
@@ -0,0 +1,250 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/broker"
+	"github.com/micro/micro/v3/service/broker/memory"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/client/grpc"
+	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/micro/micro/v3/service/debug/trace"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/util/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+// testClient records the calls and messages of the process
+type testClient struct {
+	client.Client
+	calls    []client.Request
+	messages []client.Message
+	ctx      context.Context
+}
+
+func (t *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	t.calls = append(t.calls, req)
+	t.ctx = ctx
+	if req.Endpoint() == "Users.Delete" {
+		return errors.Forbidden("users", "not allowed")
+	}
+	*rsp.(*json.RawMessage) = json.RawMessage(`{"name":"john"}`)
+	return nil
+}
+
+func (t *testClient) Publish(ctx context.Context, msg client.Message, opts ...client.PublishOption) error {
+	t.messages = append(t.messages, msg)
+	return nil
+}
+
+type testRequest struct {
+	endpoint    string
+	contentType string
+	body        []byte
+}
+
+func (r *testRequest) Service() string           { return "users" }
+func (r *testRequest) Method() string            { return r.endpoint }
+func (r *testRequest) Endpoint() string          { return r.endpoint }
+func (r *testRequest) ContentType() string       { return r.contentType }
+func (r *testRequest) Header() map[string]string { return nil }
+func (r *testRequest) Body() interface{}         { return r.body }
+func (r *testRequest) Read() ([]byte, error)     { return r.body, nil }
+func (r *testRequest) Codec() codec.Reader       { return nil }
+func (r *testRequest) Stream() bool              { return false }
+
+type testResponse struct {
+	header map[string]string
+	body   []byte
+}
+
+func (r *testResponse) Codec() codec.Writer               { return nil }
+func (r *testResponse) WriteHeader(hdr map[string]string) { r.header = hdr }
+func (r *testResponse) Write(b []byte) error {
+	r.body = append(r.body, b...)
+	return nil
+}
+
+func post(t *testing.T, h http.Handler, path, body string, hdr ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", path, strings.NewReader(body))
+	for i := 0; i+1 < len(hdr); i += 2 {
+		req.Header.Set(hdr[i], hdr[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestListenAddress(t *testing.T) {
+	tt := map[string]string{
+		":9091":          "127.0.0.1:9091",
+		"localhost:9091": "localhost:9091",
+		"127.0.0.1:9091": "127.0.0.1:9091",
+		"[::1]:9091":     "[::1]:9091",
+	}
+	for addr, expect := range tt {
+		res, err := ListenAddress(addr)
+		assert.NoError(t, err, addr)
+		assert.Equal(t, expect, res)
+	}
+
+	for _, addr := range []string{"0.0.0.0:9091", "[::]:9091", "10.0.0.1:9091", "example.com:9091", "9091"} {
+		_, err := ListenAddress(addr)
+		assert.Error(t, err, addr)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	br := memory.NewBroker()
+	assert.NoError(t, br.Connect())
+	b := New(Broker(br))
+
+	assert.Error(t, b.Register(&Registration{Address: "localhost:9090"}))
+	assert.Error(t, b.Register(&Registration{Address: "http://10.0.0.1:9090"}))
+	assert.Error(t, b.Register(&Registration{Address: "https://example.com"}))
+	assert.Error(t, b.Register(&Registration{Address: "http://localhost:9090", Endpoints: []*Endpoint{{Name: "Create"}}}))
+	assert.Error(t, b.Register(&Registration{Address: "http://localhost:9090", Subscriptions: []*Subscription{{Topic: "events", Path: "events"}}}))
+	assert.Nil(t, b.Endpoints())
+
+	rec := post(t, b, "/register", `{"address": "http://localhost:9090", "endpoints": [{"name": "Users.Create", "metadata": {"method": "PUT"}}], "subscriptions": [{"topic": "user-created"}]}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	eps := b.Endpoints()
+	if assert.Len(t, eps, 2) {
+		assert.Equal(t, "Users.Create", eps[0].Name)
+		assert.Equal(t, "PUT", eps[0].Metadata["method"])
+		assert.Equal(t, "/user-created", eps[1].Name)
+		assert.Equal(t, map[string]string{"topic": "user-created", "subscriber": "true"}, eps[1].Metadata)
+	}
+
+	rec = post(t, b, "/register", `{"address": "localhost"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	// the last valid registration is kept
+	assert.Len(t, b.Endpoints(), 2)
+}
+
+func TestServeRequest(t *testing.T) {
+	app := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/Users/Create":
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.Equal(t, `{"name":"john"}`, string(body))
+			assert.Equal(t, "john", r.Header.Get("Micro-Account"))
+			assert.Empty(t, r.Header.Get("Authorization"))
+			assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", r.Header.Get(trace.TraceparentKey))
+			w.Header().Set("Users-Version", "1")
+			w.Write([]byte(`{"id":"1"}`))
+		case "/Users/Delete":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(errors.Forbidden("users", "not allowed").Error()))
+		case "/Users/Update":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("oops"))
+		}
+	}))
+	defer app.Close()
+
+	b := New(Broker(memory.NewBroker()))
+	assert.NoError(t, b.Register(&Registration{Address: app.URL, Endpoints: []*Endpoint{
+		{Name: "Users.Create"}, {Name: "Users.Delete"}, {Name: "Users.Update"},
+	}}))
+
+	ctx := auth.ContextWithAccount(context.Background(), &auth.Account{ID: "john"})
+	ctx = metadata.Set(ctx, "Authorization", "Bearer secret")
+	ctx = metadata.Set(ctx, trace.TraceparentKey, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	rsp := &testResponse{}
+	assert.NoError(t, b.ServeRequest(ctx, &testRequest{"Users.Create", "application/json", []byte(`{"name":"john"}`)}, rsp))
+	assert.Equal(t, `{"id":"1"}`, string(rsp.body))
+	assert.Equal(t, "1", rsp.header["Users-Version"])
+
+	// errors returned by the process are passed through
+	err := b.ServeRequest(ctx, &testRequest{"Users.Delete", "application/json", nil}, &testResponse{})
+	assert.Equal(t, int32(http.StatusForbidden), errors.FromError(err).Code)
+	assert.Equal(t, "not allowed", errors.FromError(err).Detail)
+	err = b.ServeRequest(ctx, &testRequest{"Users.Update", "application/json", nil}, &testResponse{})
+	assert.Equal(t, int32(http.StatusInternalServerError), errors.FromError(err).Code)
+	assert.Equal(t, "oops", errors.FromError(err).Detail)
+
+	// endpoints which weren't registered aren't routed
+	err = b.ServeRequest(ctx, &testRequest{"Users.Read", "application/json", nil}, &testResponse{})
+	assert.Equal(t, int32(http.StatusNotFound), errors.FromError(err).Code)
+	err = b.ServeRequest(ctx, &testRequest{"Users.Create", "application/grpc+proto", nil}, &testResponse{})
+	assert.Equal(t, int32(http.StatusBadRequest), errors.FromError(err).Code)
+}
+
+func TestSubscriptions(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	app := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r
+	}))
+	defer app.Close()
+
+	br := memory.NewBroker()
+	assert.NoError(t, br.Connect())
+	b := New(Broker(br))
+	assert.NoError(t, b.Register(&Registration{Address: app.URL, Subscriptions: []*Subscription{
+		{Topic: "user-created", Path: "/events/created"},
+	}}))
+
+	assert.NoError(t, br.Publish("user-created", &broker.Message{Header: map[string]string{"Content-Type": "application/json"}, Body: []byte(`{"id":"1"}`)}))
+	select {
+	case r := <-received:
+		assert.Equal(t, "/events/created", r.URL.Path)
+		assert.Equal(t, "user-created", r.Header.Get("Micro-Topic"))
+	case <-time.After(time.Second):
+		t.Fatal("message wasn't delivered")
+	}
+
+	// registering again replaces the subscriptions
+	assert.NoError(t, b.Register(&Registration{Address: app.URL}))
+	// the memory broker unsubscribes asynchronously
+	time.Sleep(time.Millisecond * 50)
+	assert.NoError(t, br.Publish("user-created", &broker.Message{Body: []byte(`{"id":"2"}`)}))
+	select {
+	case <-received:
+		t.Fatal("message was delivered after unsubscribing")
+	case <-time.After(time.Millisecond * 100):
+	}
+}
+
+func TestCallAndPublish(t *testing.T) {
+	c := &testClient{Client: grpc.NewClient()}
+	b := New(Client(c))
+
+	rec := post(t, b, "/call", `{"service": "users", "endpoint": "Users.Read", "request": {"id": "1"}}`, trace.TraceparentKey, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"name":"john"}`, rec.Body.String())
+	if assert.Len(t, c.calls, 1) {
+		assert.Equal(t, "users", c.calls[0].Service())
+		assert.Equal(t, "application/json", c.calls[0].ContentType())
+		assert.Equal(t, `{"id": "1"}`, string(*c.calls[0].Body().(*json.RawMessage)))
+		tp, _ := metadata.Get(c.ctx, trace.TraceparentKey)
+		assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", tp)
+	}
+
+	rec = post(t, b, "/call", `{"service": "users", "endpoint": "Users.Delete"}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, "not allowed", errors.Parse(rec.Body.String()).Detail)
+
+	rec = post(t, b, "/call", `{"service": "users"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = post(t, b, "/publish", `{"topic": "user-created", "message": {"id": "1"}}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	if assert.Len(t, c.messages, 1) {
+		assert.Equal(t, "user-created", c.messages[0].Topic())
+		assert.Equal(t, "application/json", c.messages[0].ContentType())
+	}
+
+	rec = post(t, b, "/unknown", `{}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,52 @@
+package bridge
+
+import (
+	"net/http"
+
+	"github.com/micro/micro/v3/service/broker"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/server"
+)
+
+// Options of the bridge
+type Options struct {
+	// Client used for the calls and messages of the process, defaults to client.DefaultClient
+	Client client.Client
+	// Broker the process subscribes to topics with, defaults to broker.DefaultBroker
+	Broker broker.Broker
+	// Server which advertises the endpoints of the process, defaults to server.DefaultServer
+	Server server.Server
+	// HTTPClient used to call the process
+	HTTPClient *http.Client
+}
+
+// Option sets an option of the bridge
+type Option func(o *Options)
+
+// Client sets the client used for the calls and messages of the process
+func Client(c client.Client) Option {
+	return func(o *Options) {
+		o.Client = c
+	}
+}
+
+// Broker sets the broker the process subscribes to topics with
+func Broker(b broker.Broker) Option {
+	return func(o *Options) {
+		o.Broker = b
+	}
+}
+
+// Server sets the server which advertises the endpoints of the process
+func Server(s server.Server) Option {
+	return func(o *Options) {
+		o.Server = s
+	}
+}
+
+// HTTPClient sets the client used to call the process
+func HTTPClient(c *http.Client) Option {
+	return func(o *Options) {
+		o.HTTPClient = c
+	}
+}
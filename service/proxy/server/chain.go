@@ -0,0 +1,25 @@
+package server
+
+import (
+	"context"
+
+	"github.com/micro/micro/v3/service/debug/trace"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/server"
+)
+
+// errorChainHandler adds the proxy to the chain of the errors returned by the requests it
+// proxies, with the service and endpoint called, so the caller can tell the error was returned
+// through the proxy, or by it if the service couldn't be reached
+func errorChainHandler() server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			err := h(ctx, req, rsp)
+			if err == nil {
+				return nil
+			}
+			traceID, _, _ := trace.FromContext(ctx)
+			return errors.Wrap(err, Name, req.Service()+"."+req.Endpoint(), traceID)
+		}
+	}
+}
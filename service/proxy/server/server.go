@@ -204,6 +204,9 @@ func Run(ctx *cli.Context) error {
 		p = grpc.NewProxy(popts...)
 	}
 
+	// add the proxy to the chain of the errors returned, including those of the authHandler
+	serverOpts = append(serverOpts, server.WrapHandler(errorChainHandler()))
+
 	// wrap the proxy using the proxy's authHandler
 	authOpt := server.WrapHandler(authHandler())
 	serverOpts = append(serverOpts, authOpt)
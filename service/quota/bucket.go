@@ -0,0 +1,44 @@
+package quota
+
+import (
+	"math"
+	"time"
+)
+
+// Bucket is the state of a token bucket. Implementations store the bucket and use Take to update
+// it, the tokens are refilled lazily when they're taken.
+type Bucket struct {
+	Tokens  float64   `json:"tokens"`
+	Updated time.Time `json:"updated"`
+}
+
+// Take tokens from the bucket at the time provided
+func (b *Bucket) Take(now time.Time, o AllowOptions) *Result {
+	// tokens added per nanosecond
+	rate := float64(o.Limit) / float64(o.Window)
+
+	if b.Updated.IsZero() {
+		b.Tokens = float64(o.Burst)
+	} else if now.After(b.Updated) {
+		b.Tokens += float64(now.Sub(b.Updated)) * rate
+	}
+	if b.Tokens > float64(o.Burst) {
+		b.Tokens = float64(o.Burst)
+	}
+	b.Updated = now
+
+	if n := float64(o.Tokens); b.Tokens >= n {
+		b.Tokens -= n
+		return &Result{Allowed: true, Remaining: int64(b.Tokens)}
+	}
+
+	return &Result{
+		Remaining:  int64(b.Tokens),
+		RetryAfter: time.Duration(math.Ceil((float64(o.Tokens) - b.Tokens) / rate)),
+	}
+}
+
+// FillTime is how long an empty bucket takes to fill, after which its state can be discarded
+func FillTime(o AllowOptions) time.Duration {
+	return time.Duration(float64(o.Window) * float64(o.Burst) / float64(o.Limit))
+}
@@ -0,0 +1,37 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucket(t *testing.T) {
+	opts := NewAllowOptions(Limit(2, time.Second), Burst(4))
+	now := time.Now()
+
+	// a new bucket starts full
+	var b Bucket
+	for i := 0; i < 4; i++ {
+		assert.True(t, b.Take(now, opts).Allowed)
+	}
+
+	res := b.Take(now, opts)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Millisecond*500, res.RetryAfter)
+
+	// tokens are added at the rate of the limit
+	now = now.Add(time.Second)
+	res = b.Take(now, opts)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, int64(1), res.Remaining)
+
+	// but the bucket never holds more than the burst
+	now = now.Add(time.Hour)
+	res = b.Take(now, NewAllowOptions(Limit(2, time.Second), Burst(4), Tokens(4)))
+	assert.True(t, res.Allowed)
+	assert.Equal(t, int64(0), res.Remaining)
+
+	assert.Equal(t, time.Second*2, FillTime(opts))
+}
@@ -0,0 +1,121 @@
+// Package client is a quota implementation which calls the quota service
+package client
+
+import (
+	"sync"
+	"time"
+
+	pb "github.com/micro/micro/v3/proto/quota"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+	"github.com/micro/micro/v3/service/quota"
+)
+
+const name = "quota"
+
+// sweepInterval is how often the buckets which have been refilled are removed from the cache
+var sweepInterval = time.Minute
+
+// NewQuota returns a quota which calls the quota service. Buckets which run out of tokens are
+// cached locally until they're refilled, so callers which are already being limited don't result
+// in a call to the service.
+func NewQuota() quota.Quota {
+	return &srv{denied: map[string]time.Time{}}
+}
+
+type srv struct {
+	client pb.QuotaService
+
+	sync.RWMutex
+	// denied is the time each empty bucket has enough tokens again
+	denied map[string]time.Time
+	// swept is when the refilled buckets were last removed from denied
+	swept time.Time
+}
+
+func (s *srv) Allow(key string, opts ...quota.AllowOption) (*quota.Result, error) {
+	options := quota.NewAllowOptions(opts...)
+	if len(key) == 0 {
+		return nil, quota.ErrMissingKey
+	}
+	cacheKey := options.Namespace + "/" + key
+
+	s.RLock()
+	until, ok := s.denied[cacheKey]
+	s.RUnlock()
+	if ok && time.Now().Before(until) {
+		return &quota.Result{RetryAfter: time.Until(until)}, nil
+	}
+
+	rsp, err := s.quotaService().Allow(context.DefaultContext, &pb.AllowRequest{
+		Key:       key,
+		Limit:     options.Limit,
+		Window:    options.Window.Milliseconds(),
+		Burst:     options.Burst,
+		Tokens:    options.Tokens,
+		Namespace: options.Namespace,
+	}, client.WithAuthToken())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &quota.Result{
+		Allowed:    rsp.Allowed,
+		Remaining:  rsp.Remaining,
+		RetryAfter: time.Duration(rsp.RetryAfter) * time.Millisecond,
+	}
+
+	s.Lock()
+	if res.Allowed {
+		delete(s.denied, cacheKey)
+	} else {
+		s.denied[cacheKey] = time.Now().Add(res.RetryAfter)
+		s.sweep()
+	}
+	s.Unlock()
+
+	return res, nil
+}
+
+// sweep removes the buckets which have been refilled, so keys which are never seen again once
+// they're denied don't stay in the cache. It's called with the lock held.
+func (s *srv) sweep() {
+	now := time.Now()
+	if now.Sub(s.swept) < sweepInterval {
+		return
+	}
+	s.swept = now
+	for k, until := range s.denied {
+		if now.After(until) {
+			delete(s.denied, k)
+		}
+	}
+}
+
+func (s *srv) Reset(key string, opts ...quota.ResetOption) error {
+	var options quota.ResetOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	s.Lock()
+	delete(s.denied, options.Namespace+"/"+key)
+	s.Unlock()
+
+	_, err := s.quotaService().Reset(context.DefaultContext, &pb.ResetRequest{
+		Key:       key,
+		Namespace: options.Namespace,
+	}, client.WithAuthToken())
+	return err
+}
+
+func (s *srv) String() string {
+	return "service"
+}
+
+func (s *srv) quotaService() pb.QuotaService {
+	if s.client == nil {
+		s.client = pb.NewQuotaService(name, client.DefaultClient)
+	}
+	return s.client
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	pb "github.com/micro/micro/v3/proto/quota"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/quota"
+	"github.com/stretchr/testify/assert"
+)
+
+// denyingService denies every request for a millisecond
+type denyingService struct {
+	calls int
+}
+
+func (d *denyingService) Allow(ctx context.Context, in *pb.AllowRequest, opts ...client.CallOption) (*pb.AllowResponse, error) {
+	d.calls++
+	return &pb.AllowResponse{RetryAfter: 1}, nil
+}
+
+func (d *denyingService) Reset(ctx context.Context, in *pb.ResetRequest, opts ...client.CallOption) (*pb.ResetResponse, error) {
+	return &pb.ResetResponse{}, nil
+}
+
+func TestDenied(t *testing.T) {
+	svc := &denyingService{}
+	q := &srv{client: svc, denied: map[string]time.Time{}}
+	opts := []quota.AllowOption{quota.Limit(1, time.Minute)}
+
+	// denied buckets are cached until they're refilled
+	res, err := q.Allow("user", opts...)
+	assert.NoError(t, err)
+	assert.False(t, res.Allowed)
+	res, err = q.Allow("user", opts...)
+	assert.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 1, svc.calls)
+
+	// refilled buckets are removed from the cache when others are denied
+	defer func(d time.Duration) { sweepInterval = d }(sweepInterval)
+	sweepInterval = 0
+	for i := 0; i < 10; i++ {
+		_, err := q.Allow(fmt.Sprintf("ip-%d", i), opts...)
+		assert.NoError(t, err)
+		time.Sleep(time.Millisecond * 2)
+	}
+	q.RLock()
+	defer q.RUnlock()
+	assert.Len(t, q.denied, 1)
+}
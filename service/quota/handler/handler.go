@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/micro/micro/v3/proto/quota"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/quota"
+	"github.com/micro/micro/v3/util/auth/namespace"
+)
+
+// Quota implements the quota service using the quota provided
+type Quota struct {
+	Quota quota.Quota
+}
+
+func (q *Quota) Allow(ctx context.Context, req *pb.AllowRequest, rsp *pb.AllowResponse) error {
+	if len(req.Namespace) == 0 {
+		req.Namespace = namespace.DefaultNamespace
+	}
+	if err := namespace.Authorize(ctx, req.Namespace, "quota.Quota.Allow"); err != nil {
+		return err
+	}
+
+	opts := []quota.AllowOption{
+		quota.Limit(req.Limit, time.Duration(req.Window)*time.Millisecond),
+		quota.AllowNamespace(req.Namespace),
+	}
+	if req.Burst > 0 {
+		opts = append(opts, quota.Burst(req.Burst))
+	}
+	if req.Tokens > 0 {
+		opts = append(opts, quota.Tokens(req.Tokens))
+	}
+
+	res, err := q.Quota.Allow(req.Key, opts...)
+	if err == quota.ErrMissingKey || err == quota.ErrInvalidLimit {
+		return errors.BadRequest("quota.Quota.Allow", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("quota.Quota.Allow", "Error taking tokens: %v", err)
+	}
+
+	rsp.Allowed = res.Allowed
+	rsp.Remaining = res.Remaining
+	rsp.RetryAfter = res.RetryAfter.Milliseconds()
+	return nil
+}
+
+func (q *Quota) Reset(ctx context.Context, req *pb.ResetRequest, rsp *pb.ResetResponse) error {
+	if len(req.Namespace) == 0 {
+		req.Namespace = namespace.DefaultNamespace
+	}
+	if err := namespace.AuthorizeAdmin(ctx, req.Namespace, "quota.Quota.Reset"); err != nil {
+		return err
+	}
+	if len(req.Key) == 0 {
+		return errors.BadRequest("quota.Quota.Reset", "Missing key")
+	}
+
+	if err := q.Quota.Reset(req.Key, quota.ResetNamespace(req.Namespace)); err != nil {
+		return errors.InternalServerError("quota.Quota.Reset", "Error resetting bucket: %v", err)
+	}
+	return nil
+}
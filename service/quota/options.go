@@ -0,0 +1,80 @@
+package quota
+
+import "time"
+
+// AllowOptions configures the bucket tokens are taken from
+type AllowOptions struct {
+	// Limit is the number of tokens added to the bucket per window
+	Limit int64
+	// Window the limit applies to
+	Window time.Duration
+	// Burst is the maximum number of tokens the bucket holds, defaults to the limit
+	Burst int64
+	// Tokens to take, defaults to one
+	Tokens int64
+	// Namespace of the bucket
+	Namespace string
+}
+
+// AllowOption sets an attribute on AllowOptions
+type AllowOption func(o *AllowOptions)
+
+// NewAllowOptions returns the options with the defaults set
+func NewAllowOptions(opts ...AllowOption) AllowOptions {
+	options := AllowOptions{
+		Limit:  1,
+		Window: time.Second,
+		Tokens: 1,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.Burst == 0 {
+		options.Burst = options.Limit
+	}
+	return options
+}
+
+// Limit sets the number of tokens added to the bucket per window, e.g. Limit(100, time.Minute)
+func Limit(limit int64, window time.Duration) AllowOption {
+	return func(o *AllowOptions) {
+		o.Limit = limit
+		o.Window = window
+	}
+}
+
+// Burst sets the maximum number of tokens the bucket holds
+func Burst(b int64) AllowOption {
+	return func(o *AllowOptions) {
+		o.Burst = b
+	}
+}
+
+// Tokens sets the number of tokens to take
+func Tokens(n int64) AllowOption {
+	return func(o *AllowOptions) {
+		o.Tokens = n
+	}
+}
+
+// AllowNamespace sets the namespace of the bucket
+func AllowNamespace(ns string) AllowOption {
+	return func(o *AllowOptions) {
+		o.Namespace = ns
+	}
+}
+
+// ResetOptions configures the bucket which is reset
+type ResetOptions struct {
+	Namespace string
+}
+
+// ResetOption sets an attribute on ResetOptions
+type ResetOption func(o *ResetOptions)
+
+// ResetNamespace sets the namespace of the bucket
+func ResetNamespace(ns string) ResetOption {
+	return func(o *ResetOptions) {
+		o.Namespace = ns
+	}
+}
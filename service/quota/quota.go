@@ -0,0 +1,48 @@
+// Package quota enforces limits shared by every replica of a service, e.g. a rate limit enforced
+// by the api gateway, using token buckets
+package quota
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// DefaultQuota implementation
+	DefaultQuota Quota
+	// ErrMissingKey is returned when a key isn't provided
+	ErrMissingKey = errors.New("missing key")
+	// ErrInvalidLimit is returned when the limit or window isn't positive
+	ErrInvalidLimit = errors.New("invalid limit")
+)
+
+// Quota takes tokens from buckets which are refilled at a constant rate. The buckets are shared so
+// a limit is enforced globally rather than by each replica.
+type Quota interface {
+	// Allow takes tokens from the bucket with the key, returning the result
+	Allow(key string, opts ...AllowOption) (*Result, error)
+	// Reset refills the bucket with the key
+	Reset(key string, opts ...ResetOption) error
+	// String returns the name of the implementation
+	String() string
+}
+
+// Result of taking tokens from a bucket
+type Result struct {
+	// Allowed is true if there were enough tokens in the bucket
+	Allowed bool
+	// Remaining is the number of tokens left in the bucket
+	Remaining int64
+	// RetryAfter is how long until the tokens requested are available if not allowed
+	RetryAfter time.Duration
+}
+
+// Allow takes tokens from the bucket with the key using the default quota
+func Allow(key string, opts ...AllowOption) (*Result, error) {
+	return DefaultQuota.Allow(key, opts...)
+}
+
+// Reset the bucket with the key using the default quota
+func Reset(key string, opts ...ResetOption) error {
+	return DefaultQuota.Reset(key, opts...)
+}
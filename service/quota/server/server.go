@@ -0,0 +1,40 @@
+package server
+
+import (
+	pb "github.com/micro/micro/v3/proto/quota"
+	"github.com/micro/micro/v3/service"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/quota"
+	"github.com/micro/micro/v3/service/quota/handler"
+	storeQuota "github.com/micro/micro/v3/service/quota/store"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	name    = "quota"
+	address = ":8005"
+)
+
+// Backend keeps the buckets, set it in a profile to use another backend e.g. redis. By default the
+// buckets are kept in the store so they outlive the service.
+var Backend quota.Quota
+
+// Run micro quota
+func Run(c *cli.Context) error {
+	srv := service.New(
+		service.Name(name),
+		service.Address(address),
+	)
+
+	q := Backend
+	if q == nil {
+		q = storeQuota.NewQuota(store.DefaultStore)
+	}
+	pb.RegisterQuotaHandler(srv.Server(), &handler.Quota{Quota: q})
+
+	if err := srv.Run(); err != nil {
+		logger.Fatal(err)
+	}
+	return nil
+}
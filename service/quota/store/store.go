@@ -0,0 +1,92 @@
+// Package store is a quota implementation which keeps the buckets in the store
+package store
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/micro/micro/v3/service/quota"
+	"github.com/micro/micro/v3/service/store"
+)
+
+const table = "quota"
+
+// allowRetries is the number of times taking tokens is retried when the bucket was changed by
+// another request since it was read
+var allowRetries = 10
+
+// NewQuota returns a quota which keeps the buckets in the store provided. Buckets are written
+// conditionally so requests taking tokens concurrently, from any replica of the quota service,
+// are all counted.
+func NewQuota(st store.Store) quota.Quota {
+	return &storeQuota{store: st}
+}
+
+type storeQuota struct {
+	store store.Store
+}
+
+func (s *storeQuota) Allow(key string, opts ...quota.AllowOption) (*quota.Result, error) {
+	options := quota.NewAllowOptions(opts...)
+	if len(key) == 0 {
+		return nil, quota.ErrMissingKey
+	}
+	if options.Limit <= 0 || options.Window <= 0 {
+		return nil, quota.ErrInvalidLimit
+	}
+
+	for i := 0; i < allowRetries; i++ {
+		res, err := s.take(key, options)
+		if err == store.ErrConflict {
+			continue
+		}
+		return res, err
+	}
+	return nil, store.ErrConflict
+}
+
+// take tokens from the bucket, returning ErrConflict if it was changed since it was read
+func (s *storeQuota) take(key string, options quota.AllowOptions) (*quota.Result, error) {
+	var b quota.Bucket
+	var etag string
+	recs, err := s.store.Read(key, store.ReadFrom(options.Namespace, table))
+	if err == nil {
+		if err := json.Unmarshal(recs[0].Value, &b); err != nil {
+			return nil, err
+		}
+		etag = recs[0].Etag
+	} else if err != store.ErrNotFound {
+		return nil, err
+	}
+
+	res := b.Take(time.Now(), options)
+
+	val, err := json.Marshal(&b)
+	if err != nil {
+		return nil, err
+	}
+	// the bucket is full again once it expires, so there's no need to keep it
+	rec := &store.Record{Key: key, Value: val, Expiry: quota.FillTime(options)}
+	if err := s.store.Write(rec, store.WriteTo(options.Namespace, table), store.IfMatch(etag)); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *storeQuota) Reset(key string, opts ...quota.ResetOption) error {
+	var options quota.ResetOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	err := s.store.Delete(key, store.DeleteFrom(options.Namespace, table))
+	if err == store.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+func (s *storeQuota) String() string {
+	return "store"
+}
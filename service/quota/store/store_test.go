@@ -0,0 +1,71 @@
+package store
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/quota"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuota(t *testing.T) {
+	q := NewQuota(memory.NewStore())
+
+	_, err := q.Allow("")
+	assert.Equal(t, quota.ErrMissingKey, err)
+	_, err = q.Allow("foo", quota.Limit(0, time.Second))
+	assert.Equal(t, quota.ErrInvalidLimit, err)
+
+	opts := []quota.AllowOption{quota.Limit(2, time.Minute), quota.AllowNamespace("foo")}
+	for i := 0; i < 2; i++ {
+		res, err := q.Allow("user", opts...)
+		assert.NoError(t, err)
+		assert.True(t, res.Allowed)
+	}
+
+	res, err := q.Allow("user", opts...)
+	assert.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.True(t, res.RetryAfter > 0)
+
+	// buckets in other namespaces are separate
+	res, err = q.Allow("user", quota.Limit(2, time.Minute), quota.AllowNamespace("bar"))
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	// resetting refills the bucket
+	assert.NoError(t, q.Reset("user", quota.ResetNamespace("foo")))
+	res, err = q.Allow("user", opts...)
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+}
+
+func TestQuotaConcurrent(t *testing.T) {
+	// replicas of the quota service share the store but not a lock
+	st := memory.NewStore()
+	replicas := []quota.Quota{NewQuota(st), NewQuota(st)}
+
+	var wg sync.WaitGroup
+	var mtx sync.Mutex
+	allowed := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(q quota.Quota) {
+			defer wg.Done()
+			res, err := q.Allow("user", quota.Limit(5, time.Minute))
+			if err != nil {
+				return
+			}
+			if res.Allowed {
+				mtx.Lock()
+				allowed++
+				mtx.Unlock()
+			}
+		}(replicas[i%2])
+	}
+	wg.Wait()
+
+	assert.True(t, allowed <= 5, "allowed %d requests with a limit of 5", allowed)
+}
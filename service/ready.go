@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/micro/micro/v3/service/debug/health"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/server"
+)
+
+var (
+	// DefaultReadinessInterval is how often the readiness gate is checked once the service has
+	// initialised, until it passes and the service is registered
+	DefaultReadinessInterval = time.Second
+
+	// errStarting is returned by the readiness gate until the service has initialised
+	errStarting = errors.New("service is starting")
+)
+
+// registerer is a server which can be registered on demand
+type registerer interface {
+	Register() error
+}
+
+// ReadinessFunc returns an error if the service isn't ready to handle requests, e.g. because a
+// connection it depends on hasn't been established
+type ReadinessFunc func(ctx context.Context) error
+
+// readiness is the gate a service passes before it's registered. The service is started before
+// it initialises, so the debug handler answers health checks, but it isn't discoverable until
+// the warmup and priming have completed and the readiness funcs pass.
+type readiness struct {
+	initialised int32
+	fns         []ReadinessFunc
+	// the register check of the server, which must pass too
+	check func(context.Context) error
+}
+
+func (r *readiness) Check(ctx context.Context) error {
+	if atomic.LoadInt32(&r.initialised) == 0 {
+		return errStarting
+	}
+	if r.check != nil {
+		if err := r.check(ctx); err != nil {
+			return err
+		}
+	}
+	for _, fn := range r.fns {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+	}
+	// the check is run on each register interval so the service is deregistered while its
+	// critical dependencies are unhealthy and registered again once they recover
+	return health.Ready(ctx)
+}
+
+// startup reports the service unhealthy until it has initialised
+func (r *readiness) startup() error {
+	if atomic.LoadInt32(&r.initialised) == 0 {
+		return errStarting
+	}
+	return nil
+}
+
+// gate the registration of the server on the readiness of the service
+func (s *Service) gate() *readiness {
+	r := &readiness{
+		fns:   s.opts.Readiness,
+		check: s.Server().Options().RegisterCheck,
+	}
+	s.Server().Init(server.RegisterCheck(r.Check))
+	health.Register("startup", r.startup)
+	return r
+}
+
+// register the service as soon as it's ready rather than waiting for the register interval of
+// the server, until the done channel is closed
+func (s *Service) register(r *readiness, done <-chan struct{}) {
+	atomic.StoreInt32(&r.initialised, 1)
+
+	for {
+		err := r.Check(context.Background())
+		if err == nil {
+			// servers which can't be registered on demand are registered on their interval
+			if srv, ok := s.Server().(registerer); ok {
+				if err := srv.Register(); err != nil {
+					logger.Errorf("Error registering %v: %v", s.Name(), err)
+				}
+			}
+			return
+		}
+		logger.Infof("Waiting for %v to be ready: %v", s.Name(), err)
+
+		select {
+		case <-done:
+			return
+		case <-time.After(DefaultReadinessInterval):
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/micro/micro/v3/service/debug/health"
+)
+
+func TestReadiness(t *testing.T) {
+	var ready bool
+	errCheck := errors.New("check failed")
+	r := &readiness{
+		fns: []ReadinessFunc{func(ctx context.Context) error {
+			if !ready {
+				return errCheck
+			}
+			return nil
+		}},
+	}
+
+	// the service isn't ready until it has initialised
+	ready = true
+	if err := r.Check(context.TODO()); err != errStarting {
+		t.Fatalf("Expected the service to be starting, got %v", err)
+	}
+	if err := r.startup(); err != errStarting {
+		t.Fatalf("Expected the startup check to fail, got %v", err)
+	}
+
+	atomic.StoreInt32(&r.initialised, 1)
+	if err := r.Check(context.TODO()); err != nil {
+		t.Fatalf("Expected the service to be ready, got %v", err)
+	}
+	if err := r.startup(); err != nil {
+		t.Fatalf("Expected the startup check to pass, got %v", err)
+	}
+
+	// the readiness funcs must pass
+	ready = false
+	if err := r.Check(context.TODO()); err != errCheck {
+		t.Fatalf("Expected the readiness func to fail, got %v", err)
+	}
+
+	// as must the register check of the server
+	ready = true
+	r.check = func(ctx context.Context) error { return errCheck }
+	if err := r.Check(context.TODO()); err != errCheck {
+		t.Fatalf("Expected the register check to fail, got %v", err)
+	}
+}
+
+func TestReadinessDependencies(t *testing.T) {
+	r := &readiness{initialised: 1}
+	errDown := errors.New("down")
+
+	health.RegisterDependency("foo", health.Critical, func() error { return errDown })
+	health.RegisterDependency("bar", health.Optional, func() error { return errDown })
+	defer health.DeregisterDependency("foo")
+	defer health.DeregisterDependency("bar")
+
+	// the service isn't ready while a critical dependency is unhealthy
+	if err := r.Check(context.TODO()); err == nil || !strings.Contains(err.Error(), "foo: down") {
+		t.Fatalf("Expected the critical dependency to fail, got %v", err)
+	}
+	if err := r.Check(context.TODO()); strings.Contains(err.Error(), "bar") {
+		t.Fatalf("Expected the optional dependency to be ignored, got %v", err)
+	}
+
+	// the criticality can be overridden
+	health.SetCriticality("foo", health.Optional)
+	defer health.SetCriticality("foo", health.Critical)
+	if err := r.Check(context.TODO()); err != nil {
+		t.Fatalf("Expected the service to be ready, got %v", err)
+	}
+}
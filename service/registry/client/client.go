@@ -85,7 +85,7 @@ func (s *srv) GetService(name string, opts ...registry.GetOption) ([]*registry.S
 	}
 
 	rsp, err := s.client.GetService(context.DefaultContext, &pb.GetRequest{
-		Service: name, Options: &pb.Options{Domain: options.Domain},
+		Service: name, Options: &pb.Options{Domain: options.Domain, Region: options.Region},
 	}, s.callOpts()...)
 
 	if verr := errors.FromError(err); verr != nil && verr.Code == 404 {
@@ -107,7 +107,7 @@ func (s *srv) ListServices(opts ...registry.ListOption) ([]*registry.Service, er
 		o(&options)
 	}
 
-	req := &pb.ListRequest{Options: &pb.Options{Domain: options.Domain}}
+	req := &pb.ListRequest{Options: &pb.Options{Domain: options.Domain, Region: options.Region}}
 	rsp, err := s.client.ListServices(context.DefaultContext, req, s.callOpts()...)
 	if err != nil {
 		return nil, err
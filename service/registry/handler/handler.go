@@ -18,6 +18,8 @@ type Registry struct {
 	ID string
 	// the event
 	Event *service.Event
+	// Region the registry is running in, set on the nodes registered without one
+	Region string
 }
 
 func ActionToEventType(action string) registry.EventType {
@@ -58,6 +60,9 @@ func (r *Registry) GetService(ctx context.Context, req *pb.GetRequest, rsp *pb.G
 	} else {
 		options.Domain = registry.DefaultDomain
 	}
+	if req.Options != nil {
+		options.Region = req.Options.Region
+	}
 
 	// authorize the request. Non admins can also do this
 	publicNS := namespace.Public(registry.DefaultDomain)
@@ -66,7 +71,8 @@ func (r *Registry) GetService(ctx context.Context, req *pb.GetRequest, rsp *pb.G
 	}
 
 	// get the services in the namespace
-	services, err := registry.DefaultRegistry.GetService(req.Service, registry.GetDomain(options.Domain))
+	services, err := registry.DefaultRegistry.GetService(req.Service,
+		registry.GetDomain(options.Domain), registry.GetRegion(options.Region))
 	if err == registry.ErrNotFound || len(services) == 0 {
 		return errors.NotFound("registry.Registry.GetService", registry.ErrNotFound.Error())
 	} else if err != nil {
@@ -104,6 +110,18 @@ func (r *Registry) Register(ctx context.Context, req *pb.Service, rsp *pb.EmptyR
 		return err
 	}
 
+	// set the region of the nodes
+	if len(r.Region) > 0 {
+		for _, n := range req.Nodes {
+			if n.Metadata == nil {
+				n.Metadata = make(map[string]string)
+			}
+			if len(n.Metadata[registry.RegionKey]) == 0 {
+				n.Metadata[registry.RegionKey] = r.Region
+			}
+		}
+	}
+
 	// register the service
 	if err := registry.DefaultRegistry.Register(util.ToService(req), opts...); err != nil {
 		return errors.InternalServerError("registry.Registry.Register", err.Error())
@@ -144,12 +162,15 @@ func (r *Registry) Deregister(ctx context.Context, req *pb.Service, rsp *pb.Empt
 // ListServices returns all the services
 func (r *Registry) ListServices(ctx context.Context, req *pb.ListRequest, rsp *pb.ListResponse) error {
 	// parse the options
-	var domain string
+	var domain, region string
 	if req.Options != nil && len(req.Options.Domain) > 0 {
 		domain = req.Options.Domain
 	} else {
 		domain = registry.DefaultDomain
 	}
+	if req.Options != nil {
+		region = req.Options.Region
+	}
 
 	// authorize the request. Non admins can also do this
 	publicNS := namespace.Public(registry.DefaultDomain)
@@ -158,7 +179,7 @@ func (r *Registry) ListServices(ctx context.Context, req *pb.ListRequest, rsp *p
 	}
 
 	// list the services from the registry
-	services, err := registry.DefaultRegistry.ListServices(registry.ListDomain(domain))
+	services, err := registry.DefaultRegistry.ListServices(registry.ListDomain(domain), registry.ListRegion(region))
 	if err != nil {
 		return errors.InternalServerError("registry.Registry.ListServices", err.Error())
 	}
@@ -411,6 +411,12 @@ func (m *Registry) GetService(name string, opts ...registry.GetOption) ([]*regis
 		i++
 	}
 
+	// scope the nodes to the region
+	result = registry.FilterRegion(result, options.Region)
+	if len(result) == 0 {
+		return nil, registry.ErrNotFound
+	}
+
 	return result, nil
 }
 
@@ -461,7 +467,7 @@ func (m *Registry) ListServices(opts ...registry.ListOption) ([]*registry.Servic
 		}
 	}
 
-	return result, nil
+	return registry.FilterRegion(result, options.Region), nil
 }
 
 func (m *Registry) Watch(opts ...registry.WatchOption) (registry.Watcher, error) {
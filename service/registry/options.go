@@ -72,12 +72,16 @@ type GetOptions struct {
 	Context context.Context
 	// Domain to scope the request to
 	Domain string
+	// Region to scope the nodes to
+	Region string
 }
 
 type ListOptions struct {
 	Context context.Context
 	// Domain to scope the request to
 	Domain string
+	// Region to scope the nodes to
+	Region string
 }
 
 // Addrs is the registry addresses to use
@@ -168,6 +172,13 @@ func GetDomain(d string) GetOption {
 	}
 }
 
+// GetRegion only returns the nodes in the region
+func GetRegion(r string) GetOption {
+	return func(o *GetOptions) {
+		o.Region = r
+	}
+}
+
 func ListContext(ctx context.Context) ListOption {
 	return func(o *ListOptions) {
 		o.Context = ctx
@@ -179,3 +190,10 @@ func ListDomain(d string) ListOption {
 		o.Domain = d
 	}
 }
+
+// ListRegion only returns the nodes in the region
+func ListRegion(r string) ListOption {
+	return func(o *ListOptions) {
+		o.Region = r
+	}
+}
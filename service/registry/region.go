@@ -0,0 +1,39 @@
+package registry
+
+// RegionKey is the node metadata key of the region the node is running in. The registry sets it
+// on nodes registered without one, so nodes replicated from other regions can be told apart.
+const RegionKey = "region"
+
+// NodeRegion returns the region of the node, or an empty string if it isn't known
+func NodeRegion(n *Node) string {
+	if n == nil || n.Metadata == nil {
+		return ""
+	}
+	return n.Metadata[RegionKey]
+}
+
+// FilterRegion returns the services with only the nodes in the region, services without any nodes
+// in the region are removed. An empty region returns the services unchanged.
+func FilterRegion(services []*Service, region string) []*Service {
+	if len(region) == 0 {
+		return services
+	}
+
+	var result []*Service
+	for _, s := range services {
+		var nodes []*Node
+		for _, n := range s.Nodes {
+			if NodeRegion(n) == region {
+				nodes = append(nodes, n)
+			}
+		}
+		if len(nodes) == 0 {
+			continue
+		}
+
+		srv := *s
+		srv.Nodes = nodes
+		result = append(result, &srv)
+	}
+	return result
+}
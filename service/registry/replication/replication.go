@@ -0,0 +1,209 @@
+// Package replication asynchronously replicates the services registered in other regions into the
+// local registry, so services resolve against the registry in their own region while still
+// knowing about the nodes in remote regions to fail over to.
+//
+// Each region is authoritative for the nodes registered in it. Only the nodes tagged with a
+// peer's region are copied from that peer, so nodes are never replicated back to the region they
+// came from or relayed between peers, and a replicated node never replaces a node with the same id
+// owned by another region. Replicated nodes are registered with a TTL of a few sync intervals so
+// they expire if the peer becomes unreachable, and nodes which disappear from the peer are
+// deregistered on the next sync.
+package replication
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry"
+)
+
+var (
+	// DefaultInterval between syncs with each peer
+	DefaultInterval = time.Second * 10
+	// ttlIntervals is the number of sync intervals replicated nodes live for without a sync
+	ttlIntervals = 3
+)
+
+// Peer is the registry of a remote region
+type Peer struct {
+	// Region of the peer
+	Region string
+	// Registry of the peer
+	Registry registry.Registry
+}
+
+// Replicator syncs the services of its peers into the local registry
+type Replicator struct {
+	region   string
+	local    registry.Registry
+	peers    []Peer
+	interval time.Duration
+
+	sync.Mutex
+	// the nodes replicated from each region on the last sync
+	replicated map[string]map[string]*registry.Service
+	exit       chan bool
+}
+
+// NewReplicator returns a replicator which copies the services of the peers into the local
+// registry, which is in the region provided
+func NewReplicator(local registry.Registry, region string, peers []Peer, interval time.Duration) *Replicator {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Replicator{
+		region:     region,
+		local:      local,
+		peers:      peers,
+		interval:   interval,
+		replicated: make(map[string]map[string]*registry.Service),
+	}
+}
+
+// Start syncing with the peers in the background
+func (r *Replicator) Start() {
+	r.Lock()
+	defer r.Unlock()
+	if r.exit != nil {
+		return
+	}
+	r.exit = make(chan bool)
+
+	for _, p := range r.peers {
+		go r.run(p, r.exit)
+	}
+}
+
+// Stop syncing with the peers
+func (r *Replicator) Stop() {
+	r.Lock()
+	defer r.Unlock()
+	if r.exit == nil {
+		return
+	}
+	close(r.exit)
+	r.exit = nil
+}
+
+func (r *Replicator) run(p Peer, exit chan bool) {
+	t := time.NewTicker(r.interval)
+	defer t.Stop()
+
+	for {
+		if err := r.Sync(p); err != nil {
+			log.Warnf("Error replicating the registry of region %v: %v", p.Region, err)
+		}
+
+		select {
+		case <-exit:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Sync the services of the peer into the local registry
+func (r *Replicator) Sync(p Peer) error {
+	services, err := p.Registry.ListServices(
+		registry.ListDomain(registry.WildcardDomain),
+		registry.ListRegion(p.Region),
+	)
+	if err != nil {
+		return err
+	}
+
+	ttl := r.interval * time.Duration(ttlIntervals)
+	synced := make(map[string]*registry.Service)
+
+	for _, srv := range services {
+		domain := srv.Metadata["domain"]
+		if len(domain) == 0 {
+			domain = registry.DefaultDomain
+		}
+
+		// the nodes owned by other regions, which replicated nodes mustn't replace
+		owned := make(map[string]bool)
+		if existing, err := r.local.GetService(srv.Name, registry.GetDomain(domain)); err == nil {
+			for _, s := range existing {
+				for _, n := range s.Nodes {
+					if rg := registry.NodeRegion(n); rg != p.Region {
+						owned[n.Id] = true
+					}
+				}
+			}
+		}
+
+		var nodes []*registry.Node
+		for _, n := range srv.Nodes {
+			// the local region is authoritative for its own nodes
+			if rg := registry.NodeRegion(n); rg != p.Region || rg == r.region || owned[n.Id] {
+				continue
+			}
+			nodes = append(nodes, n)
+		}
+		if len(nodes) == 0 {
+			continue
+		}
+
+		rep := copyService(srv, nodes)
+		if err := r.local.Register(rep, registry.RegisterDomain(domain), registry.RegisterTTL(ttl)); err != nil {
+			log.Warnf("Error registering %v replicated from region %v: %v", srv.Name, p.Region, err)
+			continue
+		}
+		synced[key(domain, rep)] = rep
+	}
+
+	r.Lock()
+	previous := r.replicated[p.Region]
+	r.replicated[p.Region] = synced
+	r.Unlock()
+
+	// deregister the nodes which are no longer registered in the peer
+	for k, srv := range previous {
+		var removed []*registry.Node
+		current := synced[k]
+		for _, n := range srv.Nodes {
+			if current == nil || !hasNode(current, n.Id) {
+				removed = append(removed, n)
+			}
+		}
+		if len(removed) == 0 {
+			continue
+		}
+
+		domain := srv.Metadata["domain"]
+		if err := r.local.Deregister(copyService(srv, removed), registry.DeregisterDomain(domain)); err != nil {
+			log.Warnf("Error deregistering %v replicated from region %v: %v", srv.Name, p.Region, err)
+		}
+	}
+
+	return nil
+}
+
+func key(domain string, s *registry.Service) string {
+	return domain + "/" + s.Name + "/" + s.Version
+}
+
+func hasNode(s *registry.Service, id string) bool {
+	for _, n := range s.Nodes {
+		if n.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+func copyService(s *registry.Service, nodes []*registry.Node) *registry.Service {
+	md := make(map[string]string, len(s.Metadata))
+	for k, v := range s.Metadata {
+		md[k] = v
+	}
+	return &registry.Service{
+		Name:      s.Name,
+		Version:   s.Version,
+		Metadata:  md,
+		Endpoints: s.Endpoints,
+		Nodes:     nodes,
+	}
+}
@@ -0,0 +1,82 @@
+package replication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/registry/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func node(id, region string) *registry.Node {
+	return &registry.Node{Id: id, Address: id + ":8080", Metadata: map[string]string{registry.RegionKey: region}}
+}
+
+func TestSync(t *testing.T) {
+	local := memory.NewRegistry()
+	remote := memory.NewRegistry()
+
+	// a node registered locally with the same id as a remote one
+	local.Register(&registry.Service{Name: "foo", Version: "latest", Nodes: []*registry.Node{node("foo-2", "eu-west")}})
+
+	remote.Register(&registry.Service{Name: "foo", Version: "latest", Nodes: []*registry.Node{
+		node("foo-1", "us-east"),
+		node("foo-2", "us-east"),
+		// replicated into the remote from the local region
+		node("foo-3", "eu-west"),
+		// replicated into the remote from another region
+		node("foo-4", "ap-south"),
+	}})
+	remote.Register(&registry.Service{Name: "bar", Version: "latest", Nodes: []*registry.Node{node("bar-1", "us-east")}},
+		registry.RegisterDomain("foo"))
+
+	r := NewReplicator(local, "eu-west", nil, time.Minute)
+	p := Peer{Region: "us-east", Registry: remote}
+	assert.NoError(t, r.Sync(p))
+
+	srvs, err := local.GetService("foo")
+	assert.NoError(t, err)
+	assert.Len(t, srvs, 1)
+	regions := map[string]string{}
+	for _, n := range srvs[0].Nodes {
+		regions[n.Id] = registry.NodeRegion(n)
+	}
+	assert.Equal(t, map[string]string{"foo-1": "us-east", "foo-2": "eu-west"}, regions)
+
+	// the domain is preserved
+	srvs, err = local.GetService("bar", registry.GetDomain("foo"))
+	assert.NoError(t, err)
+	assert.Len(t, srvs[0].Nodes, 1)
+
+	// region scoped queries
+	srvs, err = local.GetService("foo", registry.GetRegion("eu-west"))
+	assert.NoError(t, err)
+	assert.Len(t, srvs[0].Nodes, 1)
+	assert.Equal(t, "foo-2", srvs[0].Nodes[0].Id)
+	_, err = local.GetService("bar", registry.GetDomain("foo"), registry.GetRegion("eu-west"))
+	assert.Equal(t, registry.ErrNotFound, err)
+
+	// nodes which are deregistered from the peer are removed on the next sync
+	remote.Deregister(&registry.Service{Name: "foo", Version: "latest", Nodes: []*registry.Node{node("foo-1", "us-east")}})
+	assert.NoError(t, r.Sync(p))
+
+	srvs, err = local.GetService("foo")
+	assert.NoError(t, err)
+	assert.Len(t, srvs[0].Nodes, 1)
+	assert.Equal(t, "foo-2", srvs[0].Nodes[0].Id)
+}
+
+func TestFilterRegion(t *testing.T) {
+	srvs := []*registry.Service{
+		{Name: "foo", Nodes: []*registry.Node{node("foo-1", "us-east"), node("foo-2", "eu-west")}},
+		{Name: "bar", Nodes: []*registry.Node{node("bar-1", "us-east")}},
+	}
+	assert.Equal(t, srvs, registry.FilterRegion(srvs, ""))
+
+	res := registry.FilterRegion(srvs, "eu-west")
+	assert.Len(t, res, 1)
+	assert.Equal(t, "foo-2", res[0].Nodes[0].Id)
+	// the services passed aren't modified
+	assert.Len(t, srvs[0].Nodes, 2)
+}
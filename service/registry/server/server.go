@@ -2,13 +2,16 @@ package server
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	pb "github.com/micro/micro/v3/proto/registry"
 	"github.com/micro/micro/v3/service"
 	log "github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/registry/client"
 	"github.com/micro/micro/v3/service/registry/handler"
+	"github.com/micro/micro/v3/service/registry/replication"
 	"github.com/micro/micro/v3/service/registry/util"
 	"github.com/urfave/cli/v2"
 )
@@ -20,6 +23,21 @@ var (
 	address = ":8000"
 	// topic to publish registry events to
 	topic = "registry.events"
+
+	// Flags specific to the registry service
+	Flags = []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    "replicate",
+			Usage:   "Comma separated list of the registries in other regions to replicate services from, in the form region=address",
+			EnvVars: []string{"MICRO_REGISTRY_REPLICATE"},
+		},
+		&cli.DurationFlag{
+			Name:    "replicate_interval",
+			Usage:   "Set the interval between syncs with the registries of other regions",
+			EnvVars: []string{"MICRO_REGISTRY_REPLICATE_INTERVAL"},
+			Value:   replication.DefaultInterval,
+		},
+	}
 )
 
 // Sub processes registry events
@@ -102,12 +120,39 @@ func Run(ctx *cli.Context) error {
 	// get server id
 	id := srv.Server().Options().Id
 
+	// the region nodes registered with this registry are running in
+	region := ctx.String("region")
+
 	// register the handler
 	pb.RegisterRegistryHandler(srv.Server(), &handler.Registry{
-		ID:    id,
-		Event: service.NewEvent(topic),
+		ID:     id,
+		Event:  service.NewEvent(topic),
+		Region: region,
 	})
 
+	// replicate the services registered in other regions
+	if peers := ctx.StringSlice("replicate"); len(peers) > 0 {
+		if len(region) == 0 {
+			log.Fatal("The region is required to replicate the registry")
+		}
+
+		var rps []replication.Peer
+		for _, p := range peers {
+			parts := strings.SplitN(p, "=", 2)
+			if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+				log.Fatalf("Invalid registry to replicate %q, expected region=address", p)
+			}
+			rps = append(rps, replication.Peer{
+				Region:   parts[0],
+				Registry: client.NewRegistry(registry.Addrs(parts[1])),
+			})
+		}
+
+		r := replication.NewReplicator(registry.DefaultRegistry, region, rps, ctx.Duration("replicate_interval"))
+		r.Start()
+		defer r.Stop()
+	}
+
 	// run the service
 	if err := srv.Run(); err != nil {
 		log.Fatal(err)
@@ -0,0 +1,194 @@
+// Package failover routes requests to the instances of a service in another region when every
+// instance in the local region is unhealthy, so a regional dependency failure degrades latency
+// rather than causing an outage. Failover is configured per service and a region is only failed
+// over to if the latency it adds is within the budget of the service's policy.
+package failover
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/errors"
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/metrics"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/router"
+)
+
+var (
+	// Region the client is running in, routes are only partitioned by region if it's set. Routes
+	// without a region are considered local.
+	Region string
+
+	// DefaultPolicy is used for services without a policy, it doesn't fail over
+	DefaultPolicy = Policy{}
+
+	policies = map[string]Policy{}
+	mtx      sync.RWMutex
+)
+
+// Policy configures the failover of a service
+type Policy struct {
+	// Enabled fails over to other regions when every local instance is unhealthy
+	Enabled bool
+	// MaxLatency is the most latency failing over can add, regions which are slower than the
+	// local region by more than this aren't failed over to. Zero disables the check.
+	MaxLatency time.Duration
+	// Regions to fail over to in order of preference, by default the regions are ordered by
+	// their observed latency
+	Regions []string
+}
+
+// SetPolicy sets the policy of the service, * sets the policy of every service without one
+func SetPolicy(service string, p Policy) {
+	mtx.Lock()
+	defer mtx.Unlock()
+	policies[service] = p
+}
+
+// GetPolicy returns the policy of the service
+func GetPolicy(service string) Policy {
+	mtx.RLock()
+	defer mtx.RUnlock()
+	if p, ok := policies[service]; ok {
+		return p
+	}
+	if p, ok := policies["*"]; ok {
+		return p
+	}
+	return DefaultPolicy
+}
+
+// ParsePolicy parses a policy in the form service or service=max latency, e.g. users=150ms
+func ParsePolicy(s string) (string, Policy, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts[0]) == 0 {
+		return "", Policy{}, errors.BadRequest("failover.ParsePolicy", "missing service in %q", s)
+	}
+
+	p := Policy{Enabled: true}
+	if len(parts) == 2 {
+		d, err := time.ParseDuration(parts[1])
+		if err != nil {
+			return "", Policy{}, errors.BadRequest("failover.ParsePolicy", "invalid max latency in %q: %v", s, err)
+		}
+		p.MaxLatency = d
+	}
+	return parts[0], p, nil
+}
+
+// Lookup the addresses of the routes which can be used to execute the request, failing over to
+// another region if the policy of the service allows it
+func Lookup(ctx context.Context, req client.Request, opts client.CallOptions) ([]string, error) {
+	// check to see if an address was provided as a call option
+	if len(opts.Address) > 0 {
+		return opts.Address, nil
+	}
+
+	routes, err := client.LookupRoutes(ctx, req, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	routes = Select(req.Service(), routes)
+	if len(routes) == 0 {
+		return nil, errors.InternalServerError("go.micro.client", "service %s: %s", req.Service(), router.ErrRouteNotFound.Error())
+	}
+
+	addrs := make([]string, len(routes))
+	for i, r := range routes {
+		addrs[i] = r.Address
+	}
+	return addrs, nil
+}
+
+// Select the routes of the service to use. The healthy local routes are used if there are any,
+// otherwise the healthy routes of the first region within the latency budget are used if the
+// policy of the service allows failover. If there's nowhere to fail over to the local routes are
+// returned, even if they're unhealthy.
+func Select(service string, routes []router.Route) []router.Route {
+	if len(Region) == 0 {
+		return routes
+	}
+
+	var local, healthy []router.Route
+	remote := make(map[string][]router.Route)
+
+	for _, r := range routes {
+		region := r.Metadata[registry.RegionKey]
+		if len(region) == 0 || region == Region {
+			local = append(local, r)
+			if DefaultHealth.Healthy(r.Address) {
+				healthy = append(healthy, r)
+			}
+			continue
+		}
+		if DefaultHealth.Healthy(r.Address) {
+			remote[region] = append(remote[region], r)
+		}
+	}
+
+	if len(healthy) > 0 {
+		return healthy
+	}
+
+	p := GetPolicy(service)
+	if !p.Enabled || len(remote) == 0 {
+		return local
+	}
+
+	localLatency := DefaultHealth.RouteLatency(local)
+	for _, region := range regionOrder(p, remote) {
+		tags := metrics.Tags{"service": service, "region": region}
+
+		added := DefaultHealth.RouteLatency(remote[region]) - localLatency
+		if p.MaxLatency > 0 && added > p.MaxLatency {
+			log.Debugf("Not failing over %v to region %v, it adds %v of latency", service, region, added)
+			count("router.failover.rejected", tags)
+			continue
+		}
+
+		log.Debugf("Failing over %v to region %v, every instance in region %v is unhealthy", service, region, Region)
+		count("router.failover", tags)
+		return remote[region]
+	}
+
+	return local
+}
+
+// count a failover metric if a metrics reporter has been configured
+func count(id string, tags metrics.Tags) {
+	if metrics.IsSet() {
+		metrics.Count(id, 1, tags)
+	}
+}
+
+// regionOrder returns the regions to try failing over to
+func regionOrder(p Policy, remote map[string][]router.Route) []string {
+	var regions []string
+	if len(p.Regions) > 0 {
+		for _, r := range p.Regions {
+			if _, ok := remote[r]; ok {
+				regions = append(regions, r)
+			}
+		}
+		return regions
+	}
+
+	latency := make(map[string]time.Duration, len(remote))
+	for r, routes := range remote {
+		regions = append(regions, r)
+		latency[r] = DefaultHealth.RouteLatency(routes)
+	}
+	sort.Slice(regions, func(i, j int) bool {
+		if latency[regions[i]] == latency[regions[j]] {
+			return regions[i] < regions[j]
+		}
+		return latency[regions[i]] < latency[regions[j]]
+	})
+	return regions
+}
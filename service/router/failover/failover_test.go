@@ -0,0 +1,111 @@
+package failover
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/router"
+	"github.com/stretchr/testify/assert"
+)
+
+func route(addr, region string) router.Route {
+	return router.Route{Service: "foo", Address: addr, Metadata: map[string]string{registry.RegionKey: region}}
+}
+
+func fail(h *Health, addr string) {
+	for i := 0; i < Threshold; i++ {
+		h.Record(addr, 0, errors.InternalServerError("foo", "error"))
+	}
+}
+
+func addrs(routes []router.Route) []string {
+	var res []string
+	for _, r := range routes {
+		res = append(res, r.Address)
+	}
+	return res
+}
+
+func TestHealth(t *testing.T) {
+	h := NewHealth()
+	assert.True(t, h.Healthy("a"))
+
+	// client errors don't count as failures
+	for i := 0; i < Threshold; i++ {
+		h.Record("a", 0, errors.BadRequest("foo", "error"))
+	}
+	assert.True(t, h.Healthy("a"))
+
+	fail(h, "a")
+	assert.False(t, h.Healthy("a"))
+
+	// a success resets the failures
+	h.Record("a", time.Millisecond*10, nil)
+	assert.True(t, h.Healthy("a"))
+	h.Record("a", 0, fmt.Errorf("connection refused"))
+	assert.True(t, h.Healthy("a"))
+
+	h.Record("b", time.Millisecond*30, nil)
+	assert.Equal(t, time.Millisecond*20, h.RouteLatency([]router.Route{route("a", ""), route("b", ""), route("c", "")}))
+}
+
+func TestSelect(t *testing.T) {
+	defer func(r string, h *Health) { Region, DefaultHealth = r, h }(Region, DefaultHealth)
+	Region = "eu-west"
+	DefaultHealth = NewHealth()
+
+	routes := []router.Route{
+		route("local-1", "eu-west"),
+		route("local-2", ""),
+		route("us-1", "us-east"),
+		route("ap-1", "ap-south"),
+	}
+
+	// without a region every route is used
+	Region = ""
+	assert.Len(t, Select("foo", routes), 4)
+	Region = "eu-west"
+
+	// the local routes are used while they're healthy
+	assert.Equal(t, []string{"local-1", "local-2"}, addrs(Select("foo", routes)))
+	fail(DefaultHealth, "local-1")
+	assert.Equal(t, []string{"local-2"}, addrs(Select("foo", routes)))
+
+	// every local route is unhealthy but the service doesn't fail over
+	fail(DefaultHealth, "local-2")
+	assert.Equal(t, []string{"local-1", "local-2"}, addrs(Select("foo", routes)))
+
+	// fail over to the region with the lowest latency
+	SetPolicy("foo", Policy{Enabled: true, MaxLatency: time.Millisecond * 100})
+	DefaultHealth.Record("us-1", time.Millisecond*80, nil)
+	DefaultHealth.Record("ap-1", time.Millisecond*200, nil)
+	assert.Equal(t, []string{"us-1"}, addrs(Select("foo", routes)))
+
+	// the region adds too much latency
+	DefaultHealth.Record("us-1", time.Millisecond*580, nil)
+	assert.Equal(t, []string{"local-1", "local-2"}, addrs(Select("foo", routes)))
+
+	// the preferred regions are used in order
+	SetPolicy("foo", Policy{Enabled: true, Regions: []string{"ap-south", "us-east"}})
+	assert.Equal(t, []string{"ap-1"}, addrs(Select("foo", routes)))
+}
+
+func TestParsePolicy(t *testing.T) {
+	srv, p, err := ParsePolicy("users=150ms")
+	assert.NoError(t, err)
+	assert.Equal(t, "users", srv)
+	assert.Equal(t, Policy{Enabled: true, MaxLatency: time.Millisecond * 150}, p)
+
+	srv, p, err = ParsePolicy("*")
+	assert.NoError(t, err)
+	assert.Equal(t, "*", srv)
+	assert.Equal(t, Policy{Enabled: true}, p)
+
+	_, _, err = ParsePolicy("users=foo")
+	assert.Error(t, err)
+	_, _, err = ParsePolicy("=1s")
+	assert.Error(t, err)
+}
@@ -0,0 +1,119 @@
+package failover
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/router"
+)
+
+var (
+	// Threshold is the number of consecutive failures before an instance is unhealthy
+	Threshold = 3
+	// Cooldown is how long an unhealthy instance is avoided before it's tried again
+	Cooldown = time.Second * 30
+
+	// DefaultHealth tracks the instances called by the client
+	DefaultHealth = NewHealth()
+)
+
+// Health tracks the failures and latency of the instances called
+type Health struct {
+	sync.RWMutex
+	nodes map[string]*node
+}
+
+type node struct {
+	failures  int
+	unhealthy time.Time
+	latency   time.Duration
+}
+
+// NewHealth returns a tracker without any instances
+func NewHealth() *Health {
+	return &Health{nodes: make(map[string]*node)}
+}
+
+// Record the result of a call to the instance at the address
+func (h *Health) Record(addr string, d time.Duration, err error) {
+	h.Lock()
+	defer h.Unlock()
+
+	n, ok := h.nodes[addr]
+	if !ok {
+		n = new(node)
+		h.nodes[addr] = n
+	}
+
+	if failed(err) {
+		n.failures++
+		if n.failures >= Threshold {
+			n.unhealthy = time.Now().Add(Cooldown)
+		}
+		return
+	}
+
+	n.failures = 0
+	n.unhealthy = time.Time{}
+
+	// a moving average so one slow call doesn't skew the latency
+	if n.latency == 0 {
+		n.latency = d
+	} else {
+		n.latency = (n.latency*4 + d) / 5
+	}
+}
+
+// Healthy returns false if the instance at the address is unhealthy
+func (h *Health) Healthy(addr string) bool {
+	h.RLock()
+	defer h.RUnlock()
+	n, ok := h.nodes[addr]
+	return !ok || time.Now().After(n.unhealthy)
+}
+
+// RouteLatency returns the average latency of the routes which have been called
+func (h *Health) RouteLatency(routes []router.Route) time.Duration {
+	h.RLock()
+	defer h.RUnlock()
+
+	var total time.Duration
+	var count int64
+	for _, r := range routes {
+		if n, ok := h.nodes[r.Address]; ok && n.latency > 0 {
+			total += n.latency
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return total / time.Duration(count)
+}
+
+// CallWrapper records the result of each call in the default health tracker
+func CallWrapper(fn client.CallFunc) client.CallFunc {
+	return func(ctx context.Context, addr string, req client.Request, rsp interface{}, opts client.CallOptions) error {
+		start := time.Now()
+		err := fn(ctx, addr, req, rsp, opts)
+		DefaultHealth.Record(addr, time.Since(start), err)
+		return err
+	}
+}
+
+// failed returns true if the error indicates the instance is unhealthy, rather than the request
+// being invalid
+func failed(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch errors.FromError(err).Code {
+	case 0, 408, 500, 502, 503, 504:
+		return true
+	default:
+		return false
+	}
+}
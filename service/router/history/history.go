@@ -0,0 +1,199 @@
+// Package history persists the routing changes made to a namespace and keeps an audit of them.
+// Every change, to a route or a network policy, is given the next version of the namespace's
+// routing and recorded with the account which made it, so the routing of a namespace can be
+// explained after the fact.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/micro/micro/v3/service/store"
+)
+
+var (
+	table         = "routing"
+	versionKey    = "version"
+	changePrefix  = "change/"
+	maxRetries    = 10
+	versionDigits = 20
+)
+
+// Kinds of routing state changes are made to
+const (
+	KindRoute      = "route"
+	KindPolicy     = "policy"
+	KindPolicyMode = "policy-mode"
+)
+
+// Actions which change the routing
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Change to the routing of a namespace
+type Change struct {
+	// Version of the routing after the change
+	Version int64  `json:"version"`
+	Kind    string `json:"kind"`
+	Action  string `json:"action"`
+	// Key identifies what was changed, e.g. the service of a route or the rule of a policy
+	Key string `json:"key"`
+	// Before and After are the state before and after the change, if any
+	Before  json.RawMessage `json:"before,omitempty"`
+	After   json.RawMessage `json:"after,omitempty"`
+	Account string          `json:"account,omitempty"`
+	Time    time.Time       `json:"time"`
+}
+
+// Record a change to the routing of the namespace, the before and after states are encoded as
+// json. The change is given the next version of the namespace's routing.
+func Record(ns string, c *Change, before, after interface{}) error {
+	var err error
+	if before != nil {
+		if c.Before, err = json.Marshal(before); err != nil {
+			return err
+		}
+	}
+	if after != nil {
+		if c.After, err = json.Marshal(after); err != nil {
+			return err
+		}
+	}
+	if c.Time.IsZero() {
+		c.Time = time.Now()
+	}
+
+	if c.Version, err = next(ns); err != nil {
+		return fmt.Errorf("error versioning the routing of %v: %v", ns, err)
+	}
+	return store.DefaultStore.Write(store.NewRecord(changeKey(c.Version), c), store.WriteTo(ns, table))
+}
+
+// next increments the version of the namespace's routing, the version is written conditionally
+// so concurrent changes are given distinct versions
+func next(ns string) (int64, error) {
+	for i := 0; i < maxRetries; i++ {
+		var version int64
+		etag := ""
+		recs, err := store.Read(versionKey, store.ReadFrom(ns, table))
+		if err == nil && len(recs) > 0 {
+			if err := recs[0].Decode(&version); err != nil {
+				return 0, err
+			}
+			etag = recs[0].Etag
+		} else if err != nil && err != store.ErrNotFound {
+			return 0, err
+		}
+
+		version++
+		err = store.WriteIfMatch(store.NewRecord(versionKey, version), etag, store.WriteTo(ns, table))
+		if err == store.ErrConflict {
+			continue
+		} else if err != nil {
+			return 0, err
+		}
+		return version, nil
+	}
+	return 0, store.ErrConflict
+}
+
+// Version returns the current version of the namespace's routing
+func Version(ns string) (int64, error) {
+	recs, err := store.Read(versionKey, store.ReadFrom(ns, table))
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	var version int64
+	err = recs[0].Decode(&version)
+	return version, err
+}
+
+// ListOptions filter the changes listed
+type ListOptions struct {
+	Kind  string
+	Key   string
+	Limit uint
+}
+
+// ListOption sets a list option
+type ListOption func(o *ListOptions)
+
+// ListKind only lists changes of the kind
+func ListKind(kind string) ListOption {
+	return func(o *ListOptions) {
+		o.Kind = kind
+	}
+}
+
+// ListKey only lists changes to the key, or keys with the prefix if it ends with *
+func ListKey(key string) ListOption {
+	return func(o *ListOptions) {
+		o.Key = key
+	}
+}
+
+// ListLimit limits the number of changes listed
+func ListLimit(l uint) ListOption {
+	return func(o *ListOptions) {
+		o.Limit = l
+	}
+}
+
+// List the changes to the routing of the namespace, most recent first
+func List(ns string, opts ...ListOption) ([]*Change, error) {
+	var options ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	recs, err := store.Read(changePrefix, store.ReadPrefix(), store.ReadFrom(ns, table), store.ReadOrder(store.OrderDesc))
+	if err == store.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var changes []*Change
+	for _, rec := range recs {
+		var c *Change
+		if err := rec.Decode(&c); err != nil {
+			return nil, err
+		}
+		if len(options.Kind) > 0 && c.Kind != options.Kind {
+			continue
+		}
+		if len(options.Key) > 0 && !matchKey(options.Key, c.Key) {
+			continue
+		}
+		changes = append(changes, c)
+	}
+
+	// not every store orders the records read
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Version > changes[j].Version
+	})
+	if options.Limit > 0 && uint(len(changes)) > options.Limit {
+		changes = changes[:options.Limit]
+	}
+	return changes, nil
+}
+
+func matchKey(pattern, key string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(key, strings.TrimSuffix(pattern, "*"))
+	}
+	return pattern == key
+}
+
+// changeKey pads the version so the keys sort in the order of the versions
+func changeKey(version int64) string {
+	return fmt.Sprintf("%s%0*d", changePrefix, versionDigits, version)
+}
@@ -0,0 +1,139 @@
+package history
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/micro/micro/v3/service/router"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecord(t *testing.T) {
+	store.DefaultStore = memory.NewStore()
+	ns := "foo"
+
+	v, err := Version(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), v)
+
+	assert.NoError(t, Record(ns, &Change{Kind: KindPolicy, Action: ActionCreate, Key: "a -> b", Account: "alice"}, nil, map[string]string{"from": "a"}))
+	assert.NoError(t, Record(ns, &Change{Kind: KindPolicyMode, Action: ActionUpdate, Key: "enforce"}, nil, nil))
+	assert.NoError(t, Record(ns, &Change{Kind: KindPolicy, Action: ActionDelete, Key: "a -> b"}, map[string]string{"from": "a"}, nil))
+
+	v, err = Version(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), v)
+
+	changes, err := List(ns)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 3)
+	assert.Equal(t, int64(3), changes[0].Version)
+	assert.Equal(t, ActionDelete, changes[0].Action)
+	assert.JSONEq(t, `{"from":"a"}`, string(changes[0].Before))
+	assert.Empty(t, changes[0].After)
+	assert.Equal(t, "alice", changes[2].Account)
+
+	changes, err = List(ns, ListKind(KindPolicy))
+	assert.NoError(t, err)
+	assert.Len(t, changes, 2)
+
+	changes, err = List(ns, ListKey("a *"))
+	assert.NoError(t, err)
+	assert.Len(t, changes, 2)
+
+	changes, err = List(ns, ListLimit(1))
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, int64(3), changes[0].Version)
+
+	// namespaces are versioned separately
+	changes, err = List("bar")
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestRecordConcurrent(t *testing.T) {
+	store.DefaultStore = memory.NewStore()
+	ns := "foo"
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, Record(ns, &Change{Kind: KindRoute, Action: ActionCreate, Key: "foo"}, nil, nil))
+		}()
+	}
+	wg.Wait()
+
+	changes, err := List(ns)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 5)
+}
+
+type fakeTable struct {
+	routes map[uint64]router.Route
+}
+
+func (t *fakeTable) Create(r router.Route) error {
+	if _, ok := t.routes[r.Hash()]; ok {
+		return router.ErrDuplicateRoute
+	}
+	t.routes[r.Hash()] = r
+	return nil
+}
+
+func (t *fakeTable) Delete(r router.Route) error {
+	if _, ok := t.routes[r.Hash()]; !ok {
+		return router.ErrRouteNotFound
+	}
+	delete(t.routes, r.Hash())
+	return nil
+}
+
+func (t *fakeTable) Update(r router.Route) error {
+	t.routes[r.Hash()] = r
+	return nil
+}
+
+func (t *fakeTable) Read(...router.ReadOption) ([]router.Route, error) {
+	var routes []router.Route
+	for _, r := range t.routes {
+		routes = append(routes, r)
+	}
+	return routes, nil
+}
+
+func TestTable(t *testing.T) {
+	store.DefaultStore = memory.NewStore()
+	ns := "foo"
+
+	tbl := Table(&fakeTable{routes: map[uint64]router.Route{}}, ns, "alice")
+	r := router.Route{Service: "helloworld", Address: "10.0.0.1:8080", Network: "micro"}
+	assert.NoError(t, tbl.Create(r))
+	r.Metric = 10
+	assert.NoError(t, tbl.Update(r))
+	other := router.Route{Service: "orders", Address: "10.0.0.2:8080", Network: "micro"}
+	assert.NoError(t, tbl.Create(other))
+	assert.NoError(t, tbl.Delete(other))
+
+	routes, err := Routes(ns)
+	assert.NoError(t, err)
+	assert.Len(t, routes, 1)
+	assert.Equal(t, int64(10), routes[0].Metric)
+
+	changes, err := List(ns, ListKey("helloworld"))
+	assert.NoError(t, err)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, ActionUpdate, changes[0].Action)
+	assert.Equal(t, "alice", changes[0].Account)
+	assert.NotEmpty(t, changes[0].Before)
+
+	// a restarted router has the persisted routes restored
+	restored := &fakeTable{routes: map[uint64]router.Route{}}
+	assert.NoError(t, Restore(restored, ns))
+	assert.Len(t, restored.routes, 1)
+	assert.NoError(t, Restore(restored, ns))
+}
@@ -0,0 +1,114 @@
+package history
+
+import (
+	"fmt"
+
+	"github.com/micro/micro/v3/service/router"
+	"github.com/micro/micro/v3/service/store"
+)
+
+var routePrefix = "route/"
+
+func routeKey(r router.Route) string {
+	return fmt.Sprintf("%s%s/%d", routePrefix, r.Service, r.Hash())
+}
+
+// Table wraps a routing table so the routes created, updated and deleted through it are
+// persisted and recorded in the history of the namespace, by the account provided. Routes the
+// router learns from the registry aren't written through it so they aren't persisted.
+func Table(t router.Table, ns, account string) router.Table {
+	return &persistentTable{Table: t, ns: ns, account: account}
+}
+
+type persistentTable struct {
+	router.Table
+	ns      string
+	account string
+}
+
+func (t *persistentTable) Create(r router.Route) error {
+	if err := t.Table.Create(r); err != nil {
+		return err
+	}
+	if err := store.DefaultStore.Write(store.NewRecord(routeKey(r), r), store.WriteTo(t.ns, table)); err != nil {
+		return err
+	}
+	return Record(t.ns, &Change{Kind: KindRoute, Action: ActionCreate, Key: r.Service, Account: t.account}, nil, r)
+}
+
+func (t *persistentTable) Update(r router.Route) error {
+	before, err := readRoute(t.ns, routeKey(r))
+	if err != nil {
+		return err
+	}
+	if err := t.Table.Update(r); err != nil {
+		return err
+	}
+	if err := store.DefaultStore.Write(store.NewRecord(routeKey(r), r), store.WriteTo(t.ns, table)); err != nil {
+		return err
+	}
+	return Record(t.ns, &Change{Kind: KindRoute, Action: ActionUpdate, Key: r.Service, Account: t.account}, before, r)
+}
+
+func (t *persistentTable) Delete(r router.Route) error {
+	before, err := readRoute(t.ns, routeKey(r))
+	if err != nil {
+		return err
+	}
+	if err := t.Table.Delete(r); err != nil && err != router.ErrRouteNotFound {
+		return err
+	}
+	err = store.DefaultStore.Delete(routeKey(r), store.DeleteFrom(t.ns, table))
+	if err != nil && err != store.ErrNotFound {
+		return err
+	}
+	return Record(t.ns, &Change{Kind: KindRoute, Action: ActionDelete, Key: r.Service, Account: t.account}, before, nil)
+}
+
+// readRoute returns the persisted route, or nil if it wasn't persisted
+func readRoute(ns, key string) (*router.Route, error) {
+	recs, err := store.Read(key, store.ReadFrom(ns, table))
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var r *router.Route
+	err = recs[0].Decode(&r)
+	return r, err
+}
+
+// Routes returns the routes persisted for the namespace
+func Routes(ns string) ([]router.Route, error) {
+	recs, err := store.Read(routePrefix, store.ReadPrefix(), store.ReadFrom(ns, table))
+	if err == store.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	routes := make([]router.Route, 0, len(recs))
+	for _, rec := range recs {
+		var r router.Route
+		if err := rec.Decode(&r); err != nil {
+			return nil, err
+		}
+		routes = append(routes, r)
+	}
+	return routes, nil
+}
+
+// Restore creates the routes persisted for the namespace in the table, e.g. once a router has
+// restarted
+func Restore(t router.Table, ns string) error {
+	routes, err := Routes(ns)
+	if err != nil {
+		return err
+	}
+	for _, r := range routes {
+		if err := t.Create(r); err != nil && err != router.ErrDuplicateRoute {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,327 @@
+// Package artifact is a content addressed store of build outputs. Artifacts are keyed by the
+// sha256 of their content, so identical builds are only stored once, and are referenced by name
+// from each namespace which uses them. Removing a reference doesn't delete the artifact, GC
+// deletes the artifacts which are no longer referenced.
+//
+// Builds are attested with their SBOM and provenance, signed by the runtime, so the policy of a
+// namespace can require the artifacts it deploys to be signed.
+package artifact
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
+)
+
+var (
+	// ErrNotFound is returned when a reference doesn't exist
+	ErrNotFound = errors.New("artifact not found")
+
+	// DefaultGrace is how long an unreferenced artifact is kept before it's garbage collected,
+	// so an artifact isn't collected between being written and referenced
+	DefaultGrace = time.Hour
+
+	database = "micro"
+	table    = "artifacts"
+	// blobNamespace is the namespace artifacts are written to, they're shared by namespaces
+	blobNamespace = "micro"
+
+	artifactPrefix = "artifact:"
+	refPrefix      = "ref:"
+)
+
+// Artifact is a blob addressed by its digest
+type Artifact struct {
+	// Digest of the content, e.g. sha256:2c26b46b68ffc68f...
+	Digest  string    `json:"digest"`
+	Size    int64     `json:"size"`
+	Created time.Time `json:"created"`
+	// Used is when the artifact was last referenced
+	Used time.Time `json:"used"`
+	// Refs to the artifact in the form namespace/name, set by List and GC
+	Refs []string `json:"-"`
+}
+
+// ref is a named reference to an artifact from a namespace
+type ref struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Digest    string    `json:"digest"`
+	Updated   time.Time `json:"updated"`
+}
+
+func (r *ref) String() string {
+	return r.Namespace + "/" + r.Name
+}
+
+func refKey(namespace, name string) string {
+	return refPrefix + namespace + ":" + name
+}
+
+func blobKey(digest string) string {
+	return "artifact://" + digest
+}
+
+func opts() []store.ReadOption {
+	return []store.ReadOption{store.ReadFrom(database, table)}
+}
+
+// Digest returns the digest of the content
+func Digest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Put the content as an artifact and reference it by name from the namespace. The content is
+// only written if an identical artifact doesn't already exist.
+func Put(namespace, name string, content io.Reader) (*Artifact, error) {
+	b, err := ioutil.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := Digest(b)
+	a, err := read(digest)
+	if err == ErrNotFound {
+		if err := store.DefaultBlobStore.Write(blobKey(digest), bytes.NewReader(b), store.BlobNamespace(blobNamespace)); err != nil {
+			return nil, err
+		}
+		a = &Artifact{Digest: digest, Size: int64(len(b)), Created: time.Now()}
+	} else if err != nil {
+		return nil, err
+	} else {
+		logger.Debugf("Artifact %v already exists, referencing it as %v/%v", digest, namespace, name)
+	}
+
+	// the artifact is marked as used so it's not collected before the reference is written
+	a.Used = time.Now()
+	if err := write(artifactPrefix+digest, a); err != nil {
+		return nil, err
+	}
+
+	r := &ref{Namespace: namespace, Name: name, Digest: digest, Updated: time.Now()}
+	if err := write(refKey(namespace, name), r); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Get the content of the artifact referenced by name from the namespace
+func Get(namespace, name string) (io.Reader, error) {
+	r, err := readRef(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := store.DefaultBlobStore.Read(blobKey(r.Digest), store.BlobNamespace(blobNamespace))
+	if err == store.ErrNotFound {
+		return nil, ErrNotFound
+	}
+	return blob, err
+}
+
+// Stat returns the artifact referenced by name from the namespace
+func Stat(namespace, name string) (*Artifact, error) {
+	r, err := readRef(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return read(r.Digest)
+}
+
+// Tag references the artifact referenced by one name by another, e.g. to promote a build from
+// one namespace to another. The artifact isn't copied.
+func Tag(namespace, name, toNamespace, toName string) error {
+	r, err := readRef(namespace, name)
+	if err != nil {
+		return err
+	}
+	return write(refKey(toNamespace, toName), &ref{
+		Namespace: toNamespace,
+		Name:      toName,
+		Digest:    r.Digest,
+		Updated:   time.Now(),
+	})
+}
+
+// Untag removes the reference, the artifact is deleted by GC once nothing references it
+func Untag(namespace, name string) error {
+	err := store.DefaultStore.Delete(refKey(namespace, name), store.DeleteFrom(database, table))
+	if err == store.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+// List the artifacts with their references. If a namespace is provided only the artifacts it
+// references are returned, with only its references, otherwise every artifact including those
+// no longer referenced.
+func List(namespace string) ([]*Artifact, error) {
+	refs, err := readRefs(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	recs, err := store.Read(artifactPrefix, append(opts(), store.ReadPrefix())...)
+	if err != nil && err != store.ErrNotFound {
+		return nil, err
+	}
+
+	var artifacts []*Artifact
+	for _, rec := range recs {
+		var a *Artifact
+		if err := json.Unmarshal(rec.Value, &a); err != nil {
+			return nil, err
+		}
+		for _, r := range refs {
+			if r.Digest == a.Digest {
+				a.Refs = append(a.Refs, r.String())
+			}
+		}
+		if len(namespace) > 0 && len(a.Refs) == 0 {
+			continue
+		}
+		sort.Strings(a.Refs)
+		artifacts = append(artifacts, a)
+	}
+
+	sort.Slice(artifacts, func(i, j int) bool {
+		return artifacts[i].Created.After(artifacts[j].Created)
+	})
+	return artifacts, nil
+}
+
+// GCOptions are the options of a garbage collection
+type GCOptions struct {
+	// Grace is how long an unreferenced artifact is kept for
+	Grace time.Duration
+	// DryRun returns the artifacts which would be deleted without deleting them
+	DryRun bool
+}
+
+// GCOption sets an option of a garbage collection
+type GCOption func(o *GCOptions)
+
+// GCGrace sets how long an unreferenced artifact is kept for
+func GCGrace(d time.Duration) GCOption {
+	return func(o *GCOptions) {
+		o.Grace = d
+	}
+}
+
+// GCDryRun returns the artifacts which would be deleted without deleting them
+func GCDryRun() GCOption {
+	return func(o *GCOptions) {
+		o.DryRun = true
+	}
+}
+
+// GC deletes the artifacts which aren't referenced, returning those deleted
+func GC(opts ...GCOption) ([]*Artifact, error) {
+	options := GCOptions{Grace: DefaultGrace}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	artifacts, err := List("")
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []*Artifact
+	for _, a := range artifacts {
+		if len(a.Refs) > 0 || time.Since(a.Used) < options.Grace {
+			continue
+		}
+		deleted = append(deleted, a)
+		if options.DryRun {
+			continue
+		}
+
+		logger.Infof("Deleting unreferenced artifact %v", a.Digest)
+		err := store.DefaultBlobStore.Delete(blobKey(a.Digest), store.BlobNamespace(blobNamespace))
+		if err != nil && err != store.ErrNotFound {
+			return deleted, err
+		}
+		if err := store.DefaultStore.Delete(artifactPrefix+a.Digest, store.DeleteFrom(database, table)); err != nil {
+			return deleted, err
+		}
+		err = store.DefaultStore.Delete(attestationPrefix+a.Digest, store.DeleteFrom(database, table))
+		if err != nil && err != store.ErrNotFound {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+func read(digest string) (*Artifact, error) {
+	recs, err := store.Read(artifactPrefix+digest, opts()...)
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	var a *Artifact
+	if err := json.Unmarshal(recs[0].Value, &a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func readRef(namespace, name string) (*ref, error) {
+	recs, err := store.Read(refKey(namespace, name), opts()...)
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	var r *ref
+	if err := json.Unmarshal(recs[0].Value, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func readRefs(namespace string) ([]*ref, error) {
+	prefix := refPrefix
+	if len(namespace) > 0 {
+		prefix = refKey(namespace, "")
+	}
+	recs, err := store.Read(prefix, append(opts(), store.ReadPrefix())...)
+	if err == store.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	refs := make([]*ref, 0, len(recs))
+	for _, rec := range recs {
+		var r *ref
+		if err := json.Unmarshal(rec.Value, &r); err != nil {
+			return nil, err
+		}
+		refs = append(refs, r)
+	}
+	return refs, nil
+}
+
+func write(key string, v interface{}) error {
+	return store.DefaultStore.Write(store.NewRecord(key, v), store.WriteTo(database, table))
+}
+
+// Short formats the digest for display, e.g. sha256:2c26b46b68ff
+func Short(digest string) string {
+	if idx := strings.Index(digest, ":"); idx >= 0 && len(digest) > idx+13 {
+		return digest[:idx+13]
+	}
+	return digest
+}
@@ -0,0 +1,79 @@
+package artifact
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/file"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArtifacts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "artifacts")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store.DefaultStore = memory.NewStore()
+	store.DefaultBlobStore, err = file.NewBlobStore(file.WithDir(dir))
+	assert.NoError(t, err)
+
+	// identical builds are stored once
+	a, err := Put("foo", "build://users:v1", bytes.NewBufferString("binary"))
+	assert.NoError(t, err)
+	assert.Equal(t, Digest([]byte("binary")), a.Digest)
+	b, err := Put("bar", "build://users:latest", bytes.NewBufferString("binary"))
+	assert.NoError(t, err)
+	assert.Equal(t, a.Digest, b.Digest)
+	_, err = Put("foo", "build://users:v2", bytes.NewBufferString("binary v2"))
+	assert.NoError(t, err)
+
+	keys, err := store.DefaultBlobStore.List(store.BlobListNamespace(blobNamespace))
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	r, err := Get("bar", "build://users:latest")
+	assert.NoError(t, err)
+	content, _ := ioutil.ReadAll(r)
+	assert.Equal(t, "binary", string(content))
+
+	_, err = Get("bar", "build://users:v1")
+	assert.Equal(t, ErrNotFound, err)
+
+	// builds can be promoted without copying them
+	assert.NoError(t, Tag("foo", "build://users:v2", "prod", "build://users:v2"))
+	artifacts, err := List("prod")
+	assert.NoError(t, err)
+	if assert.Len(t, artifacts, 1) {
+		assert.Equal(t, []string{"prod/build://users:v2"}, artifacts[0].Refs)
+	}
+
+	// artifacts are only collected once they're unreferenced and the grace period has passed
+	assert.NoError(t, Untag("foo", "build://users:v1"))
+	assert.NoError(t, Untag("bar", "build://users:latest"))
+	deleted, err := GC()
+	assert.NoError(t, err)
+	assert.Empty(t, deleted)
+
+	deleted, err = GC(GCGrace(0), GCDryRun())
+	assert.NoError(t, err)
+	if assert.Len(t, deleted, 1) {
+		assert.Equal(t, a.Digest, deleted[0].Digest)
+	}
+	artifacts, err = List("")
+	assert.NoError(t, err)
+	assert.Len(t, artifacts, 2)
+
+	deleted, err = GC(GCGrace(0))
+	assert.NoError(t, err)
+	assert.Len(t, deleted, 1)
+	artifacts, err = List("")
+	assert.NoError(t, err)
+	assert.Len(t, artifacts, 1)
+	keys, err = store.DefaultBlobStore.List(store.BlobListNamespace(blobNamespace))
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+}
@@ -0,0 +1,362 @@
+package artifact
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/micro/micro/v3/service/store"
+)
+
+var (
+	// ErrNoAttestation is returned when an artifact doesn't have an attestation
+	ErrNoAttestation = errors.New("artifact has no attestation")
+	// ErrUnsigned is returned when an artifact is deployed unsigned to a namespace which requires
+	// signed artifacts
+	ErrUnsigned = errors.New("artifact isn't signed")
+	// ErrInvalidSignature is returned when the signature of an attestation doesn't match any of
+	// the trusted keys
+	ErrInvalidSignature = errors.New("invalid attestation signature")
+	// ErrDigestMismatch is returned when the artifact being deployed isn't the one attested
+	ErrDigestMismatch = errors.New("artifact doesn't match its attestation")
+
+	attestationPrefix = "attestation:"
+	policyPrefix      = "policy:"
+)
+
+// Provenance records how an artifact was built
+type Provenance struct {
+	// Source the artifact was built from, e.g. github.com/micro/services/helloworld
+	Source string `json:"source"`
+	// Ref of the source, e.g. a branch, tag or commit
+	Ref string `json:"ref"`
+	// Builder which built the artifact, e.g. the host of the runtime
+	Builder  string    `json:"builder"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+}
+
+// SBOM is a software bill of materials in the CycloneDX JSON format, listing the modules the
+// artifact was built from
+type SBOM struct {
+	BOMFormat   string       `json:"bomFormat"`
+	SpecVersion string       `json:"specVersion"`
+	Version     int          `json:"version"`
+	Metadata    SBOMMetadata `json:"metadata"`
+	Components  []*Component `json:"components"`
+}
+
+// SBOMMetadata describes the component the SBOM is for
+type SBOMMetadata struct {
+	Timestamp time.Time  `json:"timestamp"`
+	Component *Component `json:"component,omitempty"`
+}
+
+// Component of an SBOM, e.g. a go module
+type Component struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	// Purl is the package url of the component, e.g. pkg:golang/github.com/google/uuid@v1.1.2
+	Purl string `json:"purl,omitempty"`
+}
+
+// Attestation of an artifact, stored alongside it. The digest, provenance and the digest of the
+// SBOM are signed so the artifact can be verified before it's deployed.
+type Attestation struct {
+	Digest     string      `json:"digest"`
+	Provenance *Provenance `json:"provenance"`
+	SBOM       *SBOM       `json:"sbom,omitempty"`
+	// Signature is the base64 encoded ed25519 signature of the statement
+	Signature string `json:"signature,omitempty"`
+	// Key is the base64 encoded public key the attestation was signed with
+	Key string `json:"key,omitempty"`
+}
+
+// statement returns the bytes which are signed
+func (a *Attestation) statement() ([]byte, error) {
+	var sbom string
+	if a.SBOM != nil {
+		b, err := json.Marshal(a.SBOM)
+		if err != nil {
+			return nil, err
+		}
+		sbom = Digest(b)
+	}
+	return json.Marshal(struct {
+		Digest     string      `json:"digest"`
+		Provenance *Provenance `json:"provenance"`
+		SBOM       string      `json:"sbom"`
+	}{a.Digest, a.Provenance, sbom})
+}
+
+// Sign the attestation with the key
+func (a *Attestation) Sign(key ed25519.PrivateKey) error {
+	b, err := a.statement()
+	if err != nil {
+		return err
+	}
+	a.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, b))
+	a.Key = base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+	return nil
+}
+
+// Verify the attestation was signed by one of the base64 encoded public keys
+func (a *Attestation) Verify(keys ...string) error {
+	if len(a.Signature) == 0 {
+		return ErrUnsigned
+	}
+	sig, err := base64.StdEncoding.DecodeString(a.Signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	b, err := a.statement()
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		pub, err := base64.StdEncoding.DecodeString(k)
+		if err != nil || len(pub) != ed25519.PublicKeySize {
+			continue
+		}
+		if ed25519.Verify(ed25519.PublicKey(pub), b, sig) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// ParseSigningKey parses a base64 encoded ed25519 private key or seed
+func ParseSigningKey(s string) (ed25519.PrivateKey, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key: %v", err)
+	}
+	switch len(b) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(b), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(b), nil
+	}
+	return nil, errors.New("invalid signing key: must be an ed25519 private key or seed")
+}
+
+// Attest stores the attestation of an artifact, replacing any existing attestation
+func Attest(a *Attestation) error {
+	if len(a.Digest) == 0 {
+		return errors.New("missing digest")
+	}
+	return write(attestationPrefix+a.Digest, a)
+}
+
+// GetAttestation returns the attestation of the artifact with the digest
+func GetAttestation(digest string) (*Attestation, error) {
+	recs, err := store.Read(attestationPrefix+digest, opts()...)
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return nil, ErrNoAttestation
+	} else if err != nil {
+		return nil, err
+	}
+	var a *Attestation
+	if err := json.Unmarshal(recs[0].Value, &a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// NewSBOM returns the SBOM of a tar archive of go source, listing the modules required by the
+// go.mod closest to the entrypoint. Nil is returned if the source doesn't have a go.mod.
+func NewSBOM(src []byte, entrypoint string) (*SBOM, error) {
+	mods, err := readModFiles(src)
+	if err != nil {
+		return nil, err
+	}
+
+	// use the go.mod of the module the entrypoint is in
+	dir := path.Clean(entrypoint)
+	for {
+		if mod, ok := mods[path.Join(dir, "go.mod")]; ok {
+			return parseModFile(mod), nil
+		}
+		if dir == "." || dir == "/" || len(dir) == 0 {
+			return nil, nil
+		}
+		dir = path.Dir(dir)
+	}
+}
+
+// readModFiles returns the go.mod files in the archive, which may be gzipped, by their path
+func readModFiles(src []byte) (map[string][]byte, error) {
+	var r io.Reader = bytes.NewReader(src)
+	if gz, err := gzip.NewReader(bytes.NewReader(src)); err == nil {
+		r = gz
+	}
+
+	mods := map[string][]byte{}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return mods, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("error reading source: %v", err)
+		}
+		if path.Base(hdr.Name) != "go.mod" {
+			continue
+		}
+		b, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		mods[path.Clean(hdr.Name)] = b
+	}
+}
+
+// parseModFile returns the SBOM of the module and its requirements
+func parseModFile(b []byte) *SBOM {
+	sbom := &SBOM{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.4",
+		Version:     1,
+		Metadata:    SBOMMetadata{Timestamp: time.Now()},
+		Components:  []*Component{},
+	}
+
+	var block bool
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch {
+		case fields[0] == "module" && len(fields) > 1:
+			sbom.Metadata.Component = &Component{Type: "application", Name: strings.Trim(fields[1], `"`)}
+		case fields[0] == "require" && len(fields) > 1 && fields[1] == "(":
+			block = true
+		case fields[0] == "require" && len(fields) > 2:
+			sbom.Components = append(sbom.Components, module(fields[1], fields[2]))
+		case fields[0] == ")":
+			block = false
+		case block && len(fields) > 1:
+			sbom.Components = append(sbom.Components, module(fields[0], fields[1]))
+		}
+	}
+	return sbom
+}
+
+func module(name, version string) *Component {
+	name = strings.Trim(name, `"`)
+	return &Component{
+		Type:    "library",
+		Name:    name,
+		Version: version,
+		Purl:    fmt.Sprintf("pkg:golang/%s@%s", name, version),
+	}
+}
+
+// Policy of the artifacts a namespace can deploy
+type Policy struct {
+	Namespace string `json:"namespace"`
+	// RequireSigned rejects deploying artifacts without an attestation signed by a trusted key
+	RequireSigned bool `json:"require_signed"`
+	// Keys are the base64 encoded public keys trusted in addition to the key of the runtime
+	Keys    []string  `json:"keys,omitempty"`
+	Account string    `json:"account"`
+	Updated time.Time `json:"updated"`
+}
+
+// GetPolicy returns the policy of a namespace, nil is returned if it doesn't have one
+func GetPolicy(namespace string) (*Policy, error) {
+	recs, err := store.Read(policyPrefix+namespace, opts()...)
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var p *Policy
+	if err := json.Unmarshal(recs[0].Value, &p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// SetPolicy sets the policy of a namespace
+func SetPolicy(p *Policy) error {
+	if len(p.Namespace) == 0 {
+		return errors.New("missing namespace")
+	}
+	for _, k := range p.Keys {
+		if b, err := base64.StdEncoding.DecodeString(k); err != nil || len(b) != ed25519.PublicKeySize {
+			return fmt.Errorf("invalid key %q, must be a base64 encoded ed25519 public key", k)
+		}
+	}
+	if p.Updated.IsZero() {
+		p.Updated = time.Now()
+	}
+	return write(policyPrefix+p.Namespace, p)
+}
+
+// ListPolicies returns the policies of every namespace
+func ListPolicies() ([]*Policy, error) {
+	recs, err := store.Read(policyPrefix, append(opts(), store.ReadPrefix())...)
+	if err == store.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	policies := make([]*Policy, 0, len(recs))
+	for _, rec := range recs {
+		var p *Policy
+		if err := json.Unmarshal(rec.Value, &p); err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+// Check the artifact can be deployed to the namespace, verifying its attestation if the policy
+// of the namespace requires signed artifacts. The keys are trusted along with those of the policy.
+func Check(namespace, digest string, keys ...string) error {
+	p, err := GetPolicy(namespace)
+	if err != nil {
+		return fmt.Errorf("error reading the artifact policy of %v: %v", namespace, err)
+	}
+	if p == nil || !p.RequireSigned {
+		return nil
+	}
+	if len(digest) == 0 {
+		return fmt.Errorf("%w: %v requires signed artifacts, the service wasn't built by the runtime", ErrUnsigned, namespace)
+	}
+
+	a, err := GetAttestation(digest)
+	if err != nil {
+		return fmt.Errorf("error verifying %v: %w", Short(digest), err)
+	}
+	if a.Digest != digest {
+		return fmt.Errorf("error verifying %v: %w", Short(digest), ErrInvalidSignature)
+	}
+	if err := a.Verify(append(keys, p.Keys...)...); err != nil {
+		return fmt.Errorf("error verifying %v: %w", Short(digest), err)
+	}
+	return nil
+}
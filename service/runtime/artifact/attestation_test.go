@@ -0,0 +1,132 @@
+package artifact
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+const goMod = `module github.com/micro/services/users
+
+go 1.16
+
+require github.com/google/uuid v1.1.2 // indirect
+
+require (
+	github.com/micro/micro/v3 v3.2.1
+	google.golang.org/protobuf v1.25.0
+)
+`
+
+func archive(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}))
+		tw.Write([]byte(content))
+	}
+	assert.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func TestSBOM(t *testing.T) {
+	src := archive(t, map[string]string{
+		"users/go.mod":  goMod,
+		"users/main.go": "package main",
+		"go.mod":        "module github.com/micro/services\n",
+	})
+
+	// the go.mod of the module the entrypoint is in is used
+	sbom, err := NewSBOM(src, "users")
+	assert.NoError(t, err)
+	if assert.NotNil(t, sbom) {
+		assert.Equal(t, "CycloneDX", sbom.BOMFormat)
+		assert.Equal(t, "github.com/micro/services/users", sbom.Metadata.Component.Name)
+		if assert.Len(t, sbom.Components, 3) {
+			assert.Equal(t, "pkg:golang/github.com/google/uuid@v1.1.2", sbom.Components[0].Purl)
+			assert.Equal(t, "github.com/micro/micro/v3", sbom.Components[1].Name)
+			assert.Equal(t, "v1.25.0", sbom.Components[2].Version)
+		}
+	}
+
+	sbom, err = NewSBOM(src, "users/cmd/users")
+	assert.NoError(t, err)
+	assert.Equal(t, "github.com/micro/services/users", sbom.Metadata.Component.Name)
+
+	sbom, err = NewSBOM(src, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "github.com/micro/services", sbom.Metadata.Component.Name)
+	assert.Empty(t, sbom.Components)
+
+	sbom, err = NewSBOM(archive(t, map[string]string{"main.go": "package main"}), "")
+	assert.NoError(t, err)
+	assert.Nil(t, sbom)
+}
+
+func TestAttestation(t *testing.T) {
+	store.DefaultStore = memory.NewStore()
+
+	pub, key, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	trusted := base64.StdEncoding.EncodeToString(pub)
+
+	seed := base64.StdEncoding.EncodeToString(key.Seed())
+	parsed, err := ParseSigningKey(seed)
+	assert.NoError(t, err)
+	assert.Equal(t, key, parsed)
+	_, err = ParseSigningKey("foo")
+	assert.Error(t, err)
+
+	digest := Digest([]byte("binary"))
+	a := &Attestation{
+		Digest: digest,
+		Provenance: &Provenance{
+			Source:   "github.com/micro/services/users",
+			Ref:      "v1",
+			Builder:  "runtime@host",
+			Started:  time.Now(),
+			Finished: time.Now(),
+		},
+	}
+	assert.Equal(t, ErrUnsigned, a.Verify(trusted))
+	assert.NoError(t, a.Sign(key))
+	assert.NoError(t, a.Verify(trusted))
+	assert.NoError(t, Attest(a))
+
+	got, err := GetAttestation(digest)
+	assert.NoError(t, err)
+	assert.NoError(t, got.Verify(trusted))
+
+	// changes to the provenance invalidate the signature
+	got.Provenance.Ref = "v2"
+	assert.Equal(t, ErrInvalidSignature, got.Verify(trusted))
+
+	_, err = GetAttestation(Digest([]byte("other")))
+	assert.Equal(t, ErrNoAttestation, err)
+
+	// anything can be deployed without a policy
+	assert.NoError(t, Check("foo", ""))
+
+	assert.Error(t, SetPolicy(&Policy{Namespace: "foo", RequireSigned: true, Keys: []string{"bar"}}))
+	assert.NoError(t, SetPolicy(&Policy{Namespace: "foo", RequireSigned: true}))
+	assert.True(t, errors.Is(Check("foo", ""), ErrUnsigned))
+	assert.True(t, errors.Is(Check("foo", digest), ErrInvalidSignature))
+	assert.NoError(t, Check("foo", digest, trusted))
+	assert.True(t, errors.Is(Check("foo", Digest([]byte("other")), trusted), ErrNoAttestation))
+
+	// keys can be trusted by the policy
+	assert.NoError(t, SetPolicy(&Policy{Namespace: "foo", RequireSigned: true, Keys: []string{trusted}}))
+	assert.NoError(t, Check("foo", digest))
+
+	policies, err := ListPolicies()
+	assert.NoError(t, err)
+	assert.Len(t, policies, 1)
+}
@@ -452,9 +452,12 @@ func (s *svc) Update(resource runtime.Resource, opts ...runtime.UpdateOption) er
 				},
 			},
 			Options: &pb.UpdateOptions{
-				Namespace:  options.Namespace,
-				Entrypoint: options.Entrypoint,
-				Instances:  int64(options.Instances),
+				Namespace:      options.Namespace,
+				Entrypoint:     options.Entrypoint,
+				Instances:      int64(options.Instances),
+				Image:          options.Image,
+				Force:          options.Force,
+				MaxUnavailable: int64(options.MaxUnavailable),
 			},
 		}
 
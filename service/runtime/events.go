@@ -18,6 +18,13 @@ const (
 	EventResourceQuotaCreated = "resourcequota.created"
 	EventResourceQuotaUpdated = "resourcequota.updated"
 	EventResourceQuotaDeleted = "resourcequota.deleted"
+
+	// EventServiceUpdateBlocked is published when an update is blocked since the error budget of
+	// the service is exhausted
+	EventServiceUpdateBlocked = "service.update.blocked"
+	// EventServiceUpdateForced is published when a service is updated with --force even though
+	// its error budget is exhausted
+	EventServiceUpdateForced = "service.update.forced"
 )
 
 // EventPayload which is published with runtime events
@@ -25,6 +32,8 @@ type EventPayload struct {
 	Type      string
 	Service   *Service
 	Namespace string
+	// Reason explains why an update was blocked or forced
+	Reason string
 }
 
 // EventResourcePayload which is published with runtime resource events
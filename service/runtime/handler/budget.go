@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+
+	pb "github.com/micro/micro/v3/proto/runtime"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/events"
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/util/slo"
+)
+
+// checkBudget consults the error budget of the service before it's updated. If the budget is
+// exhausted the update is blocked unless it's forced, either way an event explaining why is
+// published. The objective is read from the running service unless the update sets it.
+func (r *Runtime) checkBudget(ctx context.Context, service *runtime.Service, opts *pb.UpdateOptions) error {
+	srv := &runtime.Service{Name: service.Name, Metadata: service.Metadata}
+	if _, ok := srv.Metadata[slo.MetadataKey]; !ok {
+		existing, err := r.Runtime.Read(
+			runtime.ReadService(service.Name),
+			runtime.ReadVersion(service.Version),
+			runtime.ReadNamespace(opts.Namespace),
+		)
+		if err != nil || len(existing) == 0 {
+			return nil
+		}
+		srv.Metadata = existing[0].Metadata
+	}
+
+	status, err := slo.Get(ctx, opts.Namespace, srv)
+	if err != nil {
+		// a budget which can't be determined shouldn't stop deploys
+		log.Warnf("Error getting the error budget of %v: %v", service.Name, err)
+		return nil
+	}
+	if status == nil || !status.Exhausted() {
+		return nil
+	}
+
+	ev := &runtime.EventPayload{
+		Service:   service,
+		Namespace: opts.Namespace,
+		Type:      runtime.EventServiceUpdateBlocked,
+		Reason:    "error budget exhausted: " + status.String(),
+	}
+	if opts.Force {
+		ev.Type = runtime.EventServiceUpdateForced
+		log.Warnf("Forcing update of %v, %v", service.Name, ev.Reason)
+	}
+
+	if err := events.Publish(runtime.EventTopic, ev, events.WithMetadata(map[string]string{
+		"type":      ev.Type,
+		"namespace": opts.Namespace,
+	})); err != nil {
+		log.Errorf("Error publishing %v event: %v", ev.Type, err)
+	}
+
+	if opts.Force {
+		return nil
+	}
+	return errors.New("runtime.Runtime.Update", "Update blocked, "+ev.Reason+". Use --force to update anyway", 412)
+}
@@ -8,6 +8,7 @@ import (
 	pb "github.com/micro/micro/v3/proto/runtime"
 	"github.com/micro/micro/v3/service/auth"
 	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/runtime/artifact"
 	"github.com/micro/micro/v3/service/store"
 )
 
@@ -33,9 +34,13 @@ func (b *Build) Read(ctx context.Context, req *pb.Service, stream pb.Build_ReadS
 		return errors.BadRequest("runtime.Build.Read", "Missing version")
 	}
 
-	// lookup the build from the blob store
+	// lookup the build from the artifact store, falling back to the blob store for builds
+	// uploaded before the artifact store was used
 	key := fmt.Sprintf("build://%v:%v", req.Name, req.Version)
-	build, err := store.DefaultBlobStore.Read(key, store.BlobNamespace(acc.Issuer))
+	build, err := artifact.Get(acc.Issuer, key)
+	if err == artifact.ErrNotFound {
+		build, err = store.DefaultBlobStore.Read(key, store.BlobNamespace(acc.Issuer))
+	}
 	if err == store.ErrNotFound {
 		return errors.NotFound("runtime.Build.Read", "Build not found")
 	} else if err != nil {
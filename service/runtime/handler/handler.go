@@ -472,6 +472,11 @@ func (r *Runtime) Update(ctx context.Context, req *pb.UpdateRequest, rsp *pb.Upd
 
 		options := toUpdateOptions(ctx, req.Options)
 
+		// services which have exhausted their error budget are only updated when forced
+		if err := r.checkBudget(ctx, service, req.Options); err != nil {
+			return err
+		}
+
 		log.Infof("Updating service %s version %s source %s", service.Name, service.Version, service.Source)
 
 		if err := r.Runtime.Update(service, options...); err != nil {
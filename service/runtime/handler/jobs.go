@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+
+	pb "github.com/micro/micro/v3/proto/runtime"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/util/auth/namespace"
+)
+
+// JobRunner runs one-off jobs with the build of a service and records their exit status
+type JobRunner interface {
+	RunJob(job *runtime.Job) error
+	ReadJobs(namespace, service, id string) ([]*runtime.Job, error)
+}
+
+// Jobs runs one-off administrative tasks, such as migrations, using the build of a service
+type Jobs struct {
+	Runner JobRunner
+}
+
+// Run a job, the job is returned once it's been started
+func (j *Jobs) Run(ctx context.Context, req *pb.RunJobRequest, rsp *pb.RunJobResponse) error {
+	if len(req.Namespace) == 0 {
+		req.Namespace = namespace.DefaultNamespace
+	}
+	if err := namespace.AuthorizeAdmin(ctx, req.Namespace, "runtime.Jobs.Run"); err != nil {
+		return err
+	}
+	if len(req.Service) == 0 {
+		return errors.BadRequest("runtime.Jobs.Run", "missing service")
+	}
+	if len(req.Command) == 0 {
+		return errors.BadRequest("runtime.Jobs.Run", "missing command")
+	}
+
+	job := &runtime.Job{
+		Service:   req.Service,
+		Version:   req.Version,
+		Command:   req.Command,
+		Env:       req.Env,
+		Namespace: req.Namespace,
+	}
+	if err := j.Runner.RunJob(job); err == runtime.ErrNotFound {
+		return errors.NotFound("runtime.Jobs.Run", "service %v not found", req.Service)
+	} else if err != nil {
+		return errors.InternalServerError("runtime.Jobs.Run", err.Error())
+	}
+
+	rsp.Job = toProtoJob(job)
+	return nil
+}
+
+// Read the jobs of the namespace, newest first
+func (j *Jobs) Read(ctx context.Context, req *pb.ReadJobsRequest, rsp *pb.ReadJobsResponse) error {
+	if len(req.Namespace) == 0 {
+		req.Namespace = namespace.DefaultNamespace
+	}
+	if err := namespace.Authorize(ctx, req.Namespace, "runtime.Jobs.Read"); err != nil {
+		return err
+	}
+
+	jobs, err := j.Runner.ReadJobs(req.Namespace, req.Service, req.Id)
+	if err != nil {
+		return errors.InternalServerError("runtime.Jobs.Read", err.Error())
+	}
+
+	rsp.Jobs = make([]*pb.Job, 0, len(jobs))
+	for _, job := range jobs {
+		rsp.Jobs = append(rsp.Jobs, toProtoJob(job))
+	}
+	return nil
+}
+
+func toProtoJob(job *runtime.Job) *pb.Job {
+	pj := &pb.Job{
+		Id:        job.ID,
+		Service:   job.Service,
+		Version:   job.Version,
+		Command:   job.Command,
+		Namespace: job.Namespace,
+		Runner:    job.Runner,
+		Status:    job.Status,
+		ExitCode:  int32(job.ExitCode),
+		Error:     job.Error,
+		Started:   job.Started.Unix(),
+	}
+	if !job.Finished.IsZero() {
+		pj.Finished = job.Finished.Unix()
+	}
+	return pj
+}
@@ -130,6 +130,9 @@ func toUpdateOptions(ctx context.Context, opts *pb.UpdateOptions) []runtime.Upda
 		runtime.UpdateNamespace(opts.Namespace),
 		runtime.UpdateEntrypoint(opts.Entrypoint),
 		runtime.UpdateInstances(int(opts.Instances)),
+		runtime.UpdateImage(opts.Image),
+		runtime.UpdateForce(opts.Force),
+		runtime.UpdateMaxUnavailable(int(opts.MaxUnavailable)),
 	}
 }
 
@@ -0,0 +1,48 @@
+package runtime
+
+import "time"
+
+// JobType is the type of the services jobs are run as. Runtimes run them once rather than
+// restarting them when they exit.
+const JobType = "job"
+
+// JobEnv is the env var the command of the job is passed to the service in
+const JobEnv = "MICRO_SERVICE_JOB"
+
+const (
+	// JobRunning is the status of a job which hasn't finished
+	JobRunning = "running"
+	// JobSucceeded is the status of a job which exited successfully
+	JobSucceeded = "succeeded"
+	// JobFailed is the status of a job which exited with an error
+	JobFailed = "failed"
+)
+
+// Job is a one-off task, such as a migration or a backfill, run with the build of a service so it
+// doesn't have to run inside the replicas serving requests
+type Job struct {
+	// ID of the job
+	ID string `json:"id"`
+	// Service whose build the job is run with
+	Service string `json:"service"`
+	// Version of the service
+	Version string `json:"version"`
+	// Command the service runs
+	Command string `json:"command"`
+	// Env to pass to the service
+	Env []string `json:"env,omitempty"`
+	// Namespace the job is run in
+	Namespace string `json:"namespace"`
+	// Runner is the name of the service the job is run as
+	Runner string `json:"runner"`
+	// Status of the job
+	Status string `json:"status"`
+	// ExitCode of the job once it's finished
+	ExitCode int `json:"exit_code"`
+	// Error the job failed with
+	Error string `json:"error,omitempty"`
+	// Started is when the job was started
+	Started time.Time `json:"started"`
+	// Finished is when the job finished
+	Finished time.Time `json:"finished,omitempty"`
+}
@@ -182,6 +182,9 @@ func (r *Request) request() (*http.Request, error) {
 	case "deployment":
 		// /apis/apps/v1/namespaces/{namespace}/deployments/{name}
 		url = fmt.Sprintf("%s/apis/apps/v1/namespaces/%s/%ss/", r.host, r.namespace, r.resource)
+	case "job":
+		// /apis/batch/v1/namespaces/{namespace}/jobs/{name}
+		url = fmt.Sprintf("%s/apis/batch/v1/namespaces/%s/jobs/", r.host, r.namespace)
 	case "networkpolicy", "networkpolicies":
 		// /apis/networking.k8s.io/v1/namespaces/{namespace}/networkpolicies
 		url = fmt.Sprintf("%s/apis/networking.k8s.io/v1/namespaces/%s/networkpolicies/", r.host, r.namespace)
@@ -28,6 +28,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/runtime"
@@ -45,6 +46,8 @@ var (
 	DefaultNamespace = "default"
 	// DefaultPort to expose on a service
 	DefaultPort = 8080
+	// JobTTL is how long finished jobs and their pods are kept for before kubernetes deletes them
+	JobTTL = time.Hour
 )
 
 // Client ...
@@ -189,13 +192,17 @@ func (c *client) Delete(r *Resource, opts ...DeleteOption) error {
 		o(&options)
 	}
 
-	return api.NewRequest(c.opts).
+	req := api.NewRequest(c.opts).
 		Delete().
 		Resource(r.Kind).
 		Name(r.Name).
-		Namespace(options.Namespace).
-		Do().
-		Error()
+		Namespace(options.Namespace)
+
+	if len(options.Propagation) > 0 {
+		req.Params(&api.Params{Additional: map[string]string{"propagationPolicy": options.Propagation}})
+	}
+
+	return req.Do().Error()
 }
 
 // List lists API objects and stores the result in r
@@ -399,6 +406,32 @@ func NewDeployment(s *runtime.Service, opts *runtime.CreateOptions) *Resource {
 	}
 }
 
+// NewJob returns the kubernetes job definition for running a service once. The pods are the same
+// as those of the deployment of the service, except they aren't restarted and don't serve.
+func NewJob(s *runtime.Service, opts *runtime.CreateOptions) *Resource {
+	dep := NewDeployment(s, opts).Value.(*Deployment)
+
+	pod := dep.Spec.Template.PodSpec
+	pod.RestartPolicy = "Never"
+	for i := range pod.Containers {
+		pod.Containers[i].Ports = nil
+		pod.Containers[i].ReadinessProbe = nil
+	}
+
+	return &Resource{
+		Kind: "job",
+		Name: dep.Metadata.Name,
+		Value: &Job{
+			Metadata: dep.Metadata,
+			Spec: &JobSpec{
+				BackoffLimit:            0,
+				TTLSecondsAfterFinished: int(JobTTL.Seconds()),
+				Template:                dep.Spec.Template,
+			},
+		},
+	}
+}
+
 // NewLocalClient returns a client that can be used with `kubectl proxy`
 func NewLocalClient(hosts ...string) *client {
 	if len(hosts) == 0 {
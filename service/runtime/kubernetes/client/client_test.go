@@ -116,6 +116,64 @@ spec:
       volumes:`,
 			expectedURL: `example.com/apis/apps/v1/namespaces/foo-bar-baz/deployments/`,
 		},
+		{
+			name:      "job",
+			namespace: "foo-bar-baz",
+			resource: NewJob(&runtime.Service{
+				Name:    "svc1",
+				Version: "latest",
+				Source:  "source",
+			}, &runtime.CreateOptions{
+				Env:       []string{"MICRO_SERVICE_JOB=migrate"},
+				Type:      "job",
+				Image:     "DefaultImage",
+				Namespace: DefaultNamespace,
+				Resources: &runtime.Resources{
+					Mem: 200,
+				},
+			},
+			),
+
+			expectedBody: `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: "svc1-latest"
+  namespace: "default"
+  labels:
+    micro: "job"
+    name: "svc1"
+    version: "latest"
+  annotations:
+    name: "svc1"
+    source: "source"
+    version: "latest"
+spec:
+  backoffLimit: 0
+  ttlSecondsAfterFinished: 3600
+  template:
+    metadata:
+      labels:
+        micro: "job"
+        name: "svc1"
+        version: "latest"
+    spec:
+      serviceAccountName: 
+      restartPolicy: Never
+      containers:
+        - name: svc1
+          env:
+          - name: "MICRO_SERVICE_JOB"
+            value: "migrate"
+          args:
+          command:
+          image: DefaultImage
+          imagePullPolicy: IfNotPresent
+          resources:
+            limits:
+              memory: 200Mi`,
+			expectedURL: `example.com/apis/batch/v1/namespaces/foo-bar-baz/jobs/`,
+		},
 		{
 			name:      "service",
 			namespace: "foo-bar-baz",
@@ -513,6 +571,15 @@ func TestDelete(t *testing.T) {
 			},
 			expectedURL: `example.com/apis/apps/v1/namespaces/foo-bar-baz/deployments/svc1-latest`,
 		},
+		{
+			name:      "job",
+			namespace: "foo-bar-baz",
+			resource: &Resource{
+				Name: "svc1-latest",
+				Kind: "job",
+			},
+			expectedURL: `example.com/apis/batch/v1/namespaces/foo-bar-baz/jobs/svc1-latest`,
+		},
 		{
 			name:      "service",
 			namespace: "foo-bar-baz",
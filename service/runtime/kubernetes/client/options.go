@@ -27,6 +27,8 @@ type UpdateOptions struct {
 }
 type DeleteOptions struct {
 	Namespace string
+	// Propagation is how the dependents of the resource are deleted, e.g. Background
+	Propagation string
 }
 type ListOptions struct {
 	Namespace string
@@ -99,6 +101,13 @@ func DeleteNamespace(ns string) DeleteOption {
 	}
 }
 
+// DeletePropagation sets how the dependents of the resource are deleted, e.g. the pods of a job
+func DeletePropagation(policy string) DeleteOption {
+	return func(o *DeleteOptions) {
+		o.Propagation = policy
+	}
+}
+
 // ListNamespace sets the namespace for listing resources
 func ListNamespace(ns string) ListOption {
 	return func(o *ListOptions) {
@@ -16,6 +16,7 @@ package client
 
 var templates = map[string]string{
 	"deployment":      deploymentTmpl,
+	"job":             jobTmpl,
 	"service":         serviceTmpl,
 	"namespace":       namespaceTmpl,
 	"secret":          secretTmpl,
@@ -176,6 +177,100 @@ spec:
       {{- end }}
 `
 
+var jobTmpl = `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: "{{ .Metadata.Name }}"
+  namespace: "{{ .Metadata.Namespace }}"
+  labels:
+    {{- with .Metadata.Labels }}
+    {{- range $key, $value := . }}
+    {{ $key }}: "{{ $value }}"
+    {{- end }}
+    {{- end }}
+  annotations:
+    {{- with .Metadata.Annotations }}
+    {{- range $key, $value := . }}
+    {{ $key }}: "{{ $value }}"
+    {{- end }}
+    {{- end }}
+spec:
+  backoffLimit: {{ .Spec.BackoffLimit }}
+  {{- if .Spec.TTLSecondsAfterFinished }}
+  ttlSecondsAfterFinished: {{ .Spec.TTLSecondsAfterFinished }}
+  {{- end }}
+  template:
+    metadata:
+      labels:
+        {{- with .Spec.Template.Metadata.Labels }}
+        {{- range $key, $value := . }}
+        {{ $key }}: "{{ $value }}"
+        {{- end }}
+        {{- end }}
+    spec:
+      {{- if .Spec.Template.PodSpec.RuntimeClassName }}
+      runtimeClassName: {{ .Spec.Template.PodSpec.RuntimeClassName }}
+      {{- end }}
+      serviceAccountName: {{ .Spec.Template.PodSpec.ServiceAccountName }}
+      restartPolicy: {{ .Spec.Template.PodSpec.RestartPolicy }}
+      containers:
+      {{- with .Spec.Template.PodSpec.Containers }}
+      {{- range . }}
+        - name: {{ .Name }}
+          env:
+          {{- with .Env }}
+          {{- range . }}
+          - name: "{{ .Name }}"
+            value: "{{ .Value }}"
+          {{- if .ValueFrom }}
+          {{- with .ValueFrom }}
+            valueFrom:
+              {{- if .SecretKeyRef }}
+              {{- with .SecretKeyRef }}
+              secretKeyRef:
+                key: {{ .Key }}
+                name: {{ .Name }}
+                optional: {{ .Optional }}
+              {{- end }}
+              {{- end }}
+          {{- end }}
+          {{- end }}
+          {{- end }}
+          {{- end }}
+          args:
+          {{- range .Args }}
+          - {{.}}
+          {{- end }}
+          command:
+          {{- range .Command }}
+          - {{.}}
+          {{- end }}
+          image: {{ .Image }}
+          imagePullPolicy: IfNotPresent
+          {{- if .Resources }}
+          {{- with .Resources }}
+          resources:
+            {{- if .Limits }}
+            {{- with .Limits }}
+            limits:
+              {{- if .Memory }}
+              memory: {{ .Memory }}
+              {{- end }}
+              {{- if .CPU }}
+              cpu: {{ .CPU }}
+              {{- end }}
+              {{- if .EphemeralStorage }}
+              ephemeral-storage: {{ .EphemeralStorage }}
+              {{- end }}
+            {{- end }}
+            {{- end }}
+          {{- end }}
+          {{- end }}
+      {{- end }}
+      {{- end }}
+`
+
 var serviceTmpl = `
 apiVersion: v1
 kind: Service
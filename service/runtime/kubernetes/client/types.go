@@ -42,9 +42,10 @@ type SecretKeySelector struct {
 }
 
 type Condition struct {
-	Started string `json:"startedAt,omitempty"`
-	Reason  string `json:"reason,omitempty"`
-	Message string `json:"message,omitempty"`
+	Started  string `json:"startedAt,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	Message  string `json:"message,omitempty"`
+	ExitCode int    `json:"exitCode,omitempty"`
 }
 
 // Container defined container runtime values
@@ -62,9 +63,23 @@ type Container struct {
 
 // DeploymentSpec defines micro deployment spec
 type DeploymentSpec struct {
-	Replicas int            `json:"replicas,omitempty"`
-	Selector *LabelSelector `json:"selector"`
-	Template *Template      `json:"template,omitempty"`
+	Replicas int                 `json:"replicas,omitempty"`
+	Selector *LabelSelector      `json:"selector"`
+	Template *Template           `json:"template,omitempty"`
+	Strategy *DeploymentStrategy `json:"strategy,omitempty"`
+}
+
+// DeploymentStrategy is how the pods of a deployment are replaced when it's updated
+type DeploymentStrategy struct {
+	Type          string                   `json:"type,omitempty"`
+	RollingUpdate *RollingUpdateDeployment `json:"rollingUpdate,omitempty"`
+}
+
+// RollingUpdateDeployment is the number of pods which can be unavailable or surge above the
+// replicas of the deployment while they're replaced
+type RollingUpdateDeployment struct {
+	MaxUnavailable int `json:"maxUnavailable"`
+	MaxSurge       int `json:"maxSurge"`
 }
 
 // DeploymentCondition describes the state of deployment
@@ -97,6 +112,32 @@ type DeploymentList struct {
 	Items []Deployment `json:"items"`
 }
 
+// JobSpec defines the spec of a job
+type JobSpec struct {
+	BackoffLimit            int       `json:"backoffLimit"`
+	TTLSecondsAfterFinished int       `json:"ttlSecondsAfterFinished,omitempty"`
+	Template                *Template `json:"template,omitempty"`
+}
+
+// JobStatus is the number of pods of the job in each state
+type JobStatus struct {
+	Active    int `json:"active,omitempty"`
+	Succeeded int `json:"succeeded,omitempty"`
+	Failed    int `json:"failed,omitempty"`
+}
+
+// Job is a Kubernetes job
+type Job struct {
+	Metadata *Metadata  `json:"metadata"`
+	Spec     *JobSpec   `json:"spec,omitempty"`
+	Status   *JobStatus `json:"status,omitempty"`
+}
+
+// JobList
+type JobList struct {
+	Items []Job `json:"items"`
+}
+
 // LabelSelector is a label query over a set of resources
 // NOTE: we do not support MatchExpressions at the moment
 type LabelSelector struct {
@@ -126,6 +167,7 @@ type PodSpec struct {
 	Containers         []Container `json:"containers,omitempty"`
 	RuntimeClassName   string      `json:"runtimeClassName,omitempty"`
 	ServiceAccountName string      `json:"serviceAccountName,omitempty"`
+	RestartPolicy      string      `json:"restartPolicy,omitempty"`
 	Volumes            []Volume    `json:"volumes,omitempty"`
 }
 
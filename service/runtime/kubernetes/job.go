@@ -0,0 +1,106 @@
+package kubernetes
+
+import (
+	"strconv"
+
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/kubernetes/api"
+	"github.com/micro/micro/v3/service/runtime/kubernetes/client"
+)
+
+// getJobs queries kubernetes for the services run as jobs. The status is taken from the job and the
+// exit code from the container of its pod once it has terminated.
+func (k *kubernetes) getJobs(opts ...client.GetOption) ([]*runtime.Service, error) {
+	jobList := new(client.JobList)
+	if err := k.client.Get(&client.Resource{Kind: "job", Value: jobList}, opts...); err != nil {
+		return nil, err
+	}
+
+	srvMap := make(map[string]*runtime.Service, len(jobList.Items))
+	for _, job := range jobList.Items {
+		srv := &runtime.Service{
+			Name:     job.Metadata.Labels["name"],
+			Version:  job.Metadata.Labels["version"],
+			Source:   job.Metadata.Annotations["source"],
+			Metadata: job.Metadata.Annotations,
+			Status:   runtime.Pending,
+		}
+		if srv.Metadata == nil {
+			srv.Metadata = make(map[string]string)
+		}
+		delete(srv.Metadata, "name")
+		delete(srv.Metadata, "version")
+		delete(srv.Metadata, "source")
+
+		if s := job.Status; s != nil {
+			switch {
+			case s.Succeeded > 0:
+				srv.Status = runtime.Stopped
+			case s.Failed > 0:
+				srv.Status = runtime.Error
+			case s.Active > 0:
+				srv.Status = runtime.Running
+			}
+		}
+
+		srvMap[resourceName(srv)] = srv
+	}
+
+	podList := new(client.PodList)
+	if err := k.client.Get(&client.Resource{Kind: "pod", Value: podList}, opts...); err != nil {
+		logger.Errorf("Error fetching pods: %v", err)
+		return nil, nil
+	}
+	for _, item := range podList.Items {
+		if item.Status == nil || len(item.Status.Containers) == 0 {
+			continue
+		}
+		srv, ok := srvMap[resourceName(&runtime.Service{
+			Name:    item.Metadata.Labels["name"],
+			Version: item.Metadata.Labels["version"],
+		})]
+		if !ok {
+			continue
+		}
+
+		state := item.Status.Containers[0].State
+		if state.Running != nil {
+			srv.Metadata["started"] = state.Running.Started
+		}
+		if t := state.Terminated; t != nil {
+			srv.Metadata["exit_code"] = strconv.Itoa(t.ExitCode)
+			if t.ExitCode != 0 && len(t.Reason) > 0 {
+				srv.Metadata["error"] = t.Reason
+			}
+		}
+	}
+
+	services := make([]*runtime.Service, 0, len(srvMap))
+	for _, srv := range srvMap {
+		services = append(services, srv)
+	}
+	return services, nil
+}
+
+// deleteJob deletes the job the service was run as along with its pods and credentials
+func (k *kubernetes) deleteJob(s *runtime.Service, namespace string) error {
+	job := client.NewJob(s, &runtime.CreateOptions{
+		Type:      runtime.JobType,
+		Namespace: namespace,
+	})
+	err := k.client.Delete(job,
+		client.DeleteNamespace(namespace),
+		client.DeletePropagation("Background"),
+	)
+	if err == api.ErrNotFound {
+		return runtime.ErrNotFound
+	} else if err != nil {
+		if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+			logger.Errorf("Runtime failed to delete job: %v", err)
+		}
+		return err
+	}
+
+	return k.deleteCredentials(s, &runtime.CreateOptions{Namespace: namespace})
+}
@@ -222,6 +222,25 @@ func (k *kubernetes) create(resource runtime.Resource, opts ...runtime.CreateOpt
 			options.Image = DefaultImage
 		}
 
+		// jobs are run once by a kubernetes job rather than a deployment, they aren't called so
+		// don't need a kubernetes service
+		if options.Type == runtime.JobType {
+			job := client.NewJob(s, options)
+			if rcn := getRuntimeClassName(k.options.Context); len(rcn) > 0 {
+				job.Value.(*client.Job).Spec.Template.PodSpec.RuntimeClassName = rcn
+			}
+			if err := k.client.Create(job, client.CreateNamespace(options.Namespace)); err != nil {
+				if parseError(err).Reason == "AlreadyExists" {
+					return runtime.ErrAlreadyExists
+				}
+				if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+					logger.Errorf("Runtime failed to create job: %v", err)
+				}
+				return err
+			}
+			return nil
+		}
+
 		// create the deployment and set the runtime class name if provided
 		dep := client.NewDeployment(s, options)
 		if rcn := getRuntimeClassName(k.options.Context); len(rcn) > 0 {
@@ -282,6 +301,10 @@ func (k *kubernetes) Read(opts ...runtime.ReadOption) ([]*runtime.Service, error
 		labels["micro"] = client.Format(options.Type)
 	}
 
+	if options.Type == runtime.JobType {
+		return k.getJobs(client.GetNamespace(options.Namespace), client.GetLabels(labels))
+	}
+
 	// lookup all the serivces which match this query, if one service has two different versions,
 	// they'll be returned as two seperate resullts
 	return k.getServices(client.GetNamespace(options.Namespace), client.GetLabels(labels))
@@ -377,6 +400,23 @@ func (k *kubernetes) Update(resource runtime.Resource, opts ...runtime.UpdateOpt
 				dep.Spec.Replicas = int(options.Instances)
 			}
 
+			// replace the pods a few at a time without surging above the number of replicas
+			if options.MaxUnavailable > 0 {
+				dep.Spec.Strategy = &client.DeploymentStrategy{
+					Type: "RollingUpdate",
+					RollingUpdate: &client.RollingUpdateDeployment{
+						MaxUnavailable: options.MaxUnavailable,
+					},
+				}
+			}
+
+			// change the image, this triggers a rolling update of the pods
+			if len(options.Image) > 0 {
+				for i := range dep.Spec.Template.PodSpec.Containers {
+					dep.Spec.Template.PodSpec.Containers[i].Image = options.Image
+				}
+			}
+
 			// update the deployment
 			res := &client.Resource{
 				Kind:  "deployment",
@@ -445,10 +485,10 @@ func (k *kubernetes) Delete(resource runtime.Resource, opts ...runtime.DeleteOpt
 			Type:      k.options.Type,
 			Namespace: options.Namespace,
 		})
-		if err := k.client.Delete(dep, client.DeleteNamespace(options.Namespace)); err != nil {
-			if err == api.ErrNotFound {
-				return runtime.ErrNotFound
-			}
+		if err := k.client.Delete(dep, client.DeleteNamespace(options.Namespace)); err == api.ErrNotFound {
+			// the service could have been run as a job
+			return k.deleteJob(s, options.Namespace)
+		} else if err != nil {
 			if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
 				logger.Errorf("Runtime failed to delete deployment: %v", err)
 			}
@@ -18,7 +18,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/micro/micro/v3/service/runtime"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -45,3 +47,33 @@ func TestEntrypoint(t *testing.T) {
 	assert.Nil(t, err, "Didn't expecte entrypoint to return an error")
 	assert.Equal(t, "cmd/test/main.go", result, "Expected entrypoint to return cmd/test/main.go")
 }
+
+func TestJob(t *testing.T) {
+	run := func(script string) *service {
+		s := newService(&runtime.Service{Name: "job"}, runtime.CreateOptions{
+			Type:    runtime.JobType,
+			Command: []string{"sh"},
+			Args:    []string{"-c", script},
+		})
+		assert.NoError(t, s.Start())
+		for i := 0; i < 100 && s.Running(); i++ {
+			time.Sleep(time.Millisecond * 20)
+		}
+		assert.False(t, s.Running(), "Expected the job to exit")
+		return s
+	}
+
+	s := run("exit 0")
+	s.RLock()
+	assert.Equal(t, runtime.Stopped, s.Service.Status)
+	assert.Equal(t, "0", s.Metadata["exit_code"])
+	s.RUnlock()
+	assert.False(t, s.ShouldStart(), "Expected the job not to be restarted")
+
+	s = run("exit 3")
+	s.RLock()
+	assert.Equal(t, runtime.Error, s.Service.Status)
+	assert.Equal(t, "3", s.Metadata["exit_code"])
+	s.RUnlock()
+	assert.False(t, s.ShouldStart(), "Expected the job not to be restarted")
+}
@@ -39,6 +39,9 @@ type service struct {
 	retries    int
 	maxRetries int
 
+	// jobs are run once, they aren't restarted when they exit
+	job bool
+
 	// output for logs
 	output io.Writer
 
@@ -78,6 +81,7 @@ func newService(s *runtime.Service, c runtime.CreateOptions) *service {
 		output:     c.Output,
 		updated:    time.Now(),
 		maxRetries: c.Retries,
+		job:        c.Type == runtime.JobType,
 	}
 }
 
@@ -90,6 +94,9 @@ func (s *service) shouldStart() bool {
 	if s.running {
 		return false
 	}
+	if s.job && s.PID != nil {
+		return false
+	}
 	return s.retries <= s.maxRetries
 }
 
@@ -246,6 +253,11 @@ func (s *service) Wait() {
 		return
 	}
 
+	// record how the job exited
+	if s.job {
+		s.Metadata["exit_code"] = strconv.Itoa(exitCode(err))
+	}
+
 	// save the error
 	if err != nil {
 		if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
@@ -257,8 +269,10 @@ func (s *service) Wait() {
 
 		s.err = err
 	} else {
-		// check if it was stopped
-		if s.Service.Status != runtime.Stopped {
+		// check if it was stopped, jobs are expected to exit
+		if s.job {
+			s.Status(runtime.Stopped, nil)
+		} else if s.Service.Status != runtime.Stopped {
 			s.Status(runtime.Error, fmt.Errorf("Service %s terminated", s.Name))
 		}
 	}
@@ -266,3 +280,15 @@ func (s *service) Wait() {
 	// no longer running
 	s.running = false
 }
+
+// exitCode returns the exit code of the process from the error it exited with
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var code int
+	if _, serr := fmt.Sscanf(err.Error(), "exit status %d", &code); serr != nil {
+		return 1
+	}
+	return code
+}
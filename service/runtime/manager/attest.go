@@ -0,0 +1,101 @@
+package manager
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/artifact"
+	"github.com/micro/micro/v3/service/runtime/vuln"
+)
+
+// attest records the sbom and provenance of the build of the service, signed with the signing
+// key of the manager if it has one
+func (m *manager) attest(srv *service, src []byte, started time.Time) error {
+	sbom, err := artifact.NewSBOM(src, srv.Options.Entrypoint)
+	if err != nil {
+		logger.Warnf("Error generating the sbom of %v:%v: %v", srv.Service.Name, srv.Service.Version, err)
+	}
+	if sbom != nil {
+		sbom.Metadata.Timestamp = started
+	}
+
+	a := &artifact.Attestation{
+		Digest: srv.Digest,
+		Provenance: &artifact.Provenance{
+			Source:   srv.Service.Source,
+			Ref:      srv.Service.Version,
+			Builder:  m.options.Builder,
+			Started:  started,
+			Finished: time.Now(),
+		},
+		SBOM: sbom,
+	}
+	if m.options.SigningKey != nil {
+		if err := a.Sign(m.options.SigningKey); err != nil {
+			return err
+		}
+	}
+	return artifact.Attest(a)
+}
+
+// verify the build of the service can be deployed to its namespace, checking its signature and
+// vulnerabilities against the policies of the namespace. The error status is set on the service
+// if it can't be deployed.
+func (m *manager) verify(srv *service) error {
+	if err := m.verifyBuild(srv); err != nil {
+		return m.reject(srv, "Error verifying build", err)
+	}
+	if err := artifact.Check(srv.Options.Namespace, srv.Digest, m.trustedKeys()...); err != nil {
+		return m.reject(srv, "Error verifying build", err)
+	}
+	if _, err := vuln.Check(srv.Options.Namespace, srv.Service.Name, srv.Digest); err != nil {
+		return m.reject(srv, "Error scanning build", err)
+	}
+	return nil
+}
+
+// verifyBuild checks the build which will be deployed is the one which was attested. The
+// kubernetes runtime pulls the build from the artifact store, so it's hashed and compared with
+// the digest of the service. The local runtime doesn't deploy from the artifact store.
+func (m *manager) verifyBuild(srv *service) error {
+	if len(srv.Digest) == 0 {
+		return nil
+	}
+	blob, err := artifact.Get(srv.Options.Namespace, buildKey(srv))
+	if err == artifact.ErrNotFound && m.Runtime.String() == "local" {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadAll(blob)
+	if err != nil {
+		return err
+	}
+	if digest := artifact.Digest(b); digest != srv.Digest {
+		return fmt.Errorf("%w: deploying %v, attested %v", artifact.ErrDigestMismatch, artifact.Short(digest), artifact.Short(srv.Digest))
+	}
+	return nil
+}
+
+// reject the deploy of the service, setting the error status on it
+func (m *manager) reject(srv *service, msg string, err error) error {
+	logger.Warnf("Rejected deploy of %v:%v: %v", srv.Service.Name, srv.Service.Version, err)
+	srv.Status = runtime.Error
+	srv.Error = fmt.Sprintf("%v: %v", msg, err)
+	m.writeService(srv)
+	return err
+}
+
+// trustedKeys returns the public key of the signing key of the manager
+func (m *manager) trustedKeys() []string {
+	if m.options.SigningKey == nil {
+		return nil
+	}
+	pub := m.options.SigningKey.Public().(ed25519.PublicKey)
+	return []string{base64.StdEncoding.EncodeToString(pub)}
+}
@@ -0,0 +1,39 @@
+package manager
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/artifact"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/file"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyBuild(t *testing.T) {
+	store.DefaultStore = memory.NewStore()
+	bs, err := file.NewBlobStore(file.WithDir(t.TempDir()))
+	assert.NoError(t, err)
+	store.DefaultBlobStore = bs
+	m := &manager{}
+
+	srv := &service{
+		Service: &runtime.Service{Name: "users", Version: "latest"},
+		Options: &runtime.CreateOptions{Namespace: "micro"},
+		Digest:  artifact.Digest([]byte("binary")),
+	}
+	_, err = artifact.Put("micro", buildKey(srv), bytes.NewBufferString("binary"))
+	assert.NoError(t, err)
+	assert.NoError(t, m.verify(srv))
+
+	// the build was replaced after it was attested
+	_, err = artifact.Put("micro", buildKey(srv), bytes.NewBufferString("tampered"))
+	assert.NoError(t, err)
+	err = m.verify(srv)
+	assert.True(t, errors.Is(err, artifact.ErrDigestMismatch))
+	assert.Equal(t, runtime.Error, srv.Status)
+	assert.Contains(t, srv.Error, "Error verifying build")
+}
@@ -0,0 +1,190 @@
+package manager
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/micro/micro/v3/service/config"
+	configCli "github.com/micro/micro/v3/service/config/client"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/runtime"
+)
+
+// The env vars of a service can reference config values of its namespace, which are resolved when
+// the service is started, e.g. DATABASE_URL={{ secret "db/url" }} or LOG_LEVEL={{ config "log.level" }}.
+// Vars which reference secrets are passed to the runtime as secrets rather than env vars so their
+// values aren't stored in the service definition. The leader of the runtime periodically resolves
+// the references again and restarts services whose values changed, since the env of a running
+// process can't be changed.
+
+var (
+	// EnvCheckInterval is how often the values referenced by the env of services are checked for changes
+	EnvCheckInterval = time.Second * 30
+
+	// newConfig returns the config of the namespace the service is running in
+	newConfig = func(ns string) config.Config {
+		return configCli.NewConfig(ns)
+	}
+)
+
+// envTemplated returns true if the env var value references config
+func envTemplated(v string) bool {
+	return strings.Contains(v, "{{")
+}
+
+// resolvedEnv is the env of a service once the config references have been resolved
+type resolvedEnv struct {
+	// Env vars which didn't reference secrets
+	Env []string
+	// Secrets are the vars which referenced secrets
+	Secrets map[string]string
+	// Hash of the resolved values, it's blank if no values were referenced
+	Hash string
+}
+
+// resolveEnv resolves the config references of the env vars. An error is returned if a referenced
+// value doesn't exist so a service isn't started with a blank value.
+func resolveEnv(conf config.Config, env []string) (*resolvedEnv, error) {
+	res := &resolvedEnv{Secrets: map[string]string{}}
+	hash := sha256.New()
+	var templated bool
+
+	for _, kv := range env {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || !envTemplated(parts[1]) {
+			res.Env = append(res.Env, kv)
+			continue
+		}
+		templated = true
+
+		var secret bool
+		get := func(path string, opts ...config.Option) (string, error) {
+			// paths can be separated by slashes as well as dots
+			path = strings.Replace(path, "/", ".", -1)
+			v, err := conf.Get(path, opts...)
+			if err != nil {
+				return "", fmt.Errorf("error reading %v: %v", path, err)
+			}
+			if !v.Exists() {
+				return "", fmt.Errorf("%v not found", path)
+			}
+			if s := v.String(""); len(s) > 0 {
+				return s, nil
+			}
+			return string(v.Bytes()), nil
+		}
+		funcs := template.FuncMap{
+			"config": func(path string) (string, error) {
+				return get(path)
+			},
+			"secret": func(path string) (string, error) {
+				secret = true
+				return get(path, config.Secret(true))
+			},
+		}
+
+		tmpl, err := template.New(parts[0]).Funcs(funcs).Parse(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid env var %v: %v", parts[0], err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, nil); err != nil {
+			return nil, fmt.Errorf("error resolving env var %v: %v", parts[0], err)
+		}
+
+		if secret {
+			res.Secrets[parts[0]] = buf.String()
+		} else {
+			res.Env = append(res.Env, parts[0]+"="+buf.String())
+		}
+		fmt.Fprintf(hash, "%s=%s\n", parts[0], buf.String())
+	}
+
+	if templated {
+		res.Hash = fmt.Sprintf("%x", hash.Sum(nil))
+	}
+	return res, nil
+}
+
+// checkEnv restarts the running services whose env references values which have changed
+func (m *manager) checkEnv() {
+	nss, err := m.listNamespaces()
+	if err != nil {
+		logger.Warnf("Error listing namespaces: %v", err)
+		return
+	}
+
+	for _, ns := range nss {
+		srvs, err := m.readServices(ns, &runtime.Service{})
+		if err != nil {
+			logger.Warnf("Error reading services from the %v namespace: %v", ns, err)
+			return
+		}
+
+		running := map[string]bool{}
+		curr, _ := runtime.Read(runtime.ReadNamespace(ns))
+		for _, v := range curr {
+			running[v.Name+":"+v.Version] = true
+		}
+
+		for _, srv := range srvs {
+			if !hasTemplatedEnv(srv.Options.Env) {
+				continue
+			}
+			// services which aren't running are resolved when they're next started
+			if !running[srv.Service.Name+":"+srv.Service.Version] {
+				continue
+			}
+			if srv.Status == runtime.Error || srv.Status == runtime.Building || srv.Status == runtime.Stopped {
+				continue
+			}
+
+			env, err := resolveEnv(newConfig(ns), srv.Options.Env)
+			if err != nil {
+				logger.Warnf("Error resolving the env of %v:%v: %v", srv.Service.Name, srv.Service.Version, err)
+				continue
+			}
+			if env.Hash == srv.EnvHash {
+				continue
+			}
+
+			// services started before the hash was recorded
+			if len(srv.EnvHash) == 0 {
+				srv.EnvHash = env.Hash
+				m.writeService(srv)
+				continue
+			}
+
+			logger.Infof("Env of %v:%v changed, restarting", srv.Service.Name, srv.Service.Version)
+			if err := m.Runtime.Delete(srv.Service, runtime.DeleteNamespace(ns)); err != nil && err != runtime.ErrNotFound {
+				logger.Errorf("Error stopping %v:%v: %v", srv.Service.Name, srv.Service.Version, err)
+				continue
+			}
+			source := srv.Service.Source
+			srv.Service.Source = filepath.Dir(source)
+			err = m.createServiceInRuntime(srv)
+			srv.Service.Source = source
+			if err != nil {
+				logger.Errorf("Error restarting %v:%v: %v", srv.Service.Name, srv.Service.Version, err)
+				srv.Status = runtime.Error
+				srv.Error = fmt.Sprintf("Error restarting service: %v", err)
+			}
+			srv.UpdatedAt = time.Now()
+			m.writeService(srv)
+		}
+	}
+}
+
+func hasTemplatedEnv(env []string) bool {
+	for _, kv := range env {
+		if envTemplated(kv) {
+			return true
+		}
+	}
+	return false
+}
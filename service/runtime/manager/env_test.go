@@ -0,0 +1,49 @@
+package manager
+
+import (
+	"testing"
+
+	storeConfig "github.com/micro/micro/v3/service/config/store"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveEnv(t *testing.T) {
+	conf, _ := storeConfig.NewConfig(memory.NewStore(), "")
+	conf.Set("db.url", "postgres://db:5432")
+	conf.Set("log.level", "debug")
+	conf.Set("workers", 4)
+
+	env := []string{
+		"FOO=bar",
+		`DATABASE_URL={{ secret "db/url" }}`,
+		`LOG_LEVEL={{ config "log.level" }}`,
+		`WORKERS={{ config "workers" }}`,
+	}
+	res, err := resolveEnv(conf, env)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"FOO=bar", "LOG_LEVEL=debug", "WORKERS=4"}, res.Env)
+	assert.Equal(t, map[string]string{"DATABASE_URL": "postgres://db:5432"}, res.Secrets)
+	assert.NotEmpty(t, res.Hash)
+
+	// the hash only changes when a referenced value does
+	again, err := resolveEnv(conf, env)
+	assert.NoError(t, err)
+	assert.Equal(t, res.Hash, again.Hash)
+
+	conf.Set("db.url", "postgres://replica:5432")
+	changed, err := resolveEnv(conf, env)
+	assert.NoError(t, err)
+	assert.Equal(t, "postgres://replica:5432", changed.Secrets["DATABASE_URL"])
+	assert.NotEqual(t, res.Hash, changed.Hash)
+
+	// env without references isn't hashed
+	plain, err := resolveEnv(conf, []string{"FOO=bar"})
+	assert.NoError(t, err)
+	assert.Empty(t, plain.Hash)
+
+	_, err = resolveEnv(conf, []string{`MISSING={{ config "missing" }}`})
+	assert.Error(t, err)
+	_, err = resolveEnv(conf, []string{`INVALID={{ config "db.url" `})
+	assert.Error(t, err)
+}
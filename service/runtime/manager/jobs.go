@@ -0,0 +1,212 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/util/namespace"
+)
+
+const (
+	// jobPrefix is prefixed to the key for job records
+	jobPrefix = "job:"
+	// jobServiceKey is the metadata key of the runner for the service the job is run for
+	jobServiceKey = "job_service"
+)
+
+// JobRetention is how long the records of finished jobs are kept for
+var JobRetention = time.Hour * 24 * 7
+
+// key to write the job to the store under, e.g. "job:micro:foo:1a2b3c4d"
+func jobKey(job *runtime.Job) string {
+	return jobPrefix + job.Namespace + ":" + job.Service + ":" + job.ID
+}
+
+// RunJob runs a one-off task with the build of a service. The service is started as a runner with
+// the command of the job set in its env, it runs the job rather than serving requests and isn't
+// restarted once it exits. The leader of the runtime records the exit status of the job.
+func (m *manager) RunJob(job *runtime.Job) error {
+	if len(job.Namespace) == 0 {
+		job.Namespace = namespace.DefaultNamespace
+	}
+	if len(job.Version) == 0 {
+		job.Version = "latest"
+	}
+
+	srvs, err := m.readServices(job.Namespace, &runtime.Service{Name: job.Service, Version: job.Version})
+	if err != nil {
+		return err
+	}
+	if len(srvs) == 0 {
+		return runtime.ErrNotFound
+	}
+	srv := srvs[0]
+	if srv.Status == runtime.Pending || srv.Status == runtime.Building {
+		return fmt.Errorf("%v:%v hasn't been built yet", job.Service, job.Version)
+	}
+
+	job.ID = strings.Split(uuid.New().String(), "-")[0]
+	job.Runner = job.Service + "-job-" + job.ID
+	job.Status = runtime.JobRunning
+	job.Started = time.Now()
+
+	// the runner uses the build of the service, the env of the job overrides the service's
+	options := *srv.Options
+	options.Type = runtime.JobType
+	options.Instances = 1
+	options.Force = false
+	options.Env = append(append([]string{}, srv.Options.Env...), job.Env...)
+	options.Env = append(options.Env,
+		"MICRO_SERVICE_NAME="+job.Service,
+		runtime.JobEnv+"="+job.Command,
+	)
+
+	// the local runtime appends the entrypoint to the source of the service
+	source := srv.Service.Source
+	if len(options.Entrypoint) > 0 {
+		source = strings.TrimSuffix(source, string(filepath.Separator)+filepath.Clean(options.Entrypoint))
+	}
+
+	runner := &service{
+		Service: &runtime.Service{
+			Name:    job.Runner,
+			Version: job.Version,
+			Source:  source,
+			Metadata: map[string]string{
+				"job":         job.ID,
+				jobServiceKey: job.Service,
+			},
+		},
+		Options: &options,
+	}
+
+	if err := m.createServiceInRuntime(runner); err != nil {
+		return err
+	}
+	logger.Infof("Running job %v of %v:%v as %v", job.Command, job.Service, job.Version, job.Runner)
+	return m.writeJob(job)
+}
+
+// ReadJobs returns the jobs in the namespace, newest first. If a service is provided only its jobs
+// are returned, and if an id is provided only that job is.
+func (m *manager) ReadJobs(ns, srv, id string) ([]*runtime.Job, error) {
+	if len(ns) == 0 {
+		ns = namespace.DefaultNamespace
+	}
+	prefix := jobPrefix + ns + ":"
+	if len(srv) > 0 {
+		prefix += srv + ":"
+	}
+
+	recs, err := store.Read(prefix, store.ReadPrefix())
+	if err != nil && err != store.ErrNotFound {
+		return nil, err
+	}
+
+	jobs := make([]*runtime.Job, 0, len(recs))
+	for _, r := range recs {
+		var job *runtime.Job
+		if err := json.Unmarshal(r.Value, &job); err != nil {
+			return nil, err
+		}
+		if len(id) > 0 && job.ID != id {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].Started.After(jobs[j].Started)
+	})
+	return jobs, nil
+}
+
+// checkJobs records the exit status of the jobs which have finished and removes their runners
+func (m *manager) checkJobs() {
+	recs, err := store.Read(jobPrefix, store.ReadPrefix())
+	if err != nil {
+		logger.Warnf("Error reading jobs: %v", err)
+		return
+	}
+
+	for _, r := range recs {
+		var job *runtime.Job
+		if err := json.Unmarshal(r.Value, &job); err != nil {
+			logger.Warnf("Error unmarshaling job %v: %v", r.Key, err)
+			continue
+		}
+		if job.Status != runtime.JobRunning {
+			continue
+		}
+
+		srvs, err := m.Runtime.Read(
+			runtime.ReadService(job.Runner),
+			runtime.ReadType(runtime.JobType),
+			runtime.ReadNamespace(job.Namespace),
+		)
+		if err != nil {
+			logger.Warnf("Error reading job %v: %v", job.Runner, err)
+			continue
+		}
+
+		if len(srvs) == 0 {
+			// the runner was lost, e.g. the runtime was restarted
+			job.Status = runtime.JobFailed
+			job.Error = "job runner not found"
+		} else {
+			finishJob(job, srvs[0])
+		}
+		if job.Status == runtime.JobRunning {
+			continue
+		}
+
+		job.Finished = time.Now()
+		logger.Infof("Job %v of %v %v", job.Command, job.Service, job.Status)
+		if err := m.writeJob(job); err != nil {
+			logger.Errorf("Error writing job %v: %v", job.Runner, err)
+			continue
+		}
+
+		runner := &runtime.Service{Name: job.Runner, Version: job.Version}
+		if err := m.Runtime.Delete(runner, runtime.DeleteNamespace(job.Namespace)); err != nil && err != runtime.ErrNotFound {
+			logger.Warnf("Error deleting job runner %v: %v", job.Runner, err)
+		}
+	}
+}
+
+// finishJob sets the status of the job from its runner once the runner has exited
+func finishJob(job *runtime.Job, runner *runtime.Service) {
+	switch runner.Status {
+	case runtime.Stopped:
+		job.Status = runtime.JobSucceeded
+	case runtime.Error:
+		job.Status = runtime.JobFailed
+		job.Error = runner.Metadata["error"]
+	default:
+		return
+	}
+	if code, err := strconv.Atoi(runner.Metadata["exit_code"]); err == nil {
+		job.ExitCode = code
+	}
+}
+
+// writeJob to the store, finished jobs expire after the retention period
+func (m *manager) writeJob(job *runtime.Job) error {
+	bytes, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	rec := &store.Record{Key: jobKey(job), Value: bytes}
+	if job.Status != runtime.JobRunning {
+		rec.Expiry = JobRetention
+	}
+	return store.Write(rec)
+}
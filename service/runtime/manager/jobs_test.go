@@ -0,0 +1,96 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadJobs(t *testing.T) {
+	store.DefaultStore = memory.NewStore()
+	m := &manager{}
+
+	now := time.Now()
+	jobs := []*runtime.Job{
+		{ID: "a", Service: "foo", Namespace: "micro", Status: runtime.JobSucceeded, Started: now.Add(-time.Hour)},
+		{ID: "b", Service: "foo", Namespace: "micro", Status: runtime.JobRunning, Started: now},
+		{ID: "c", Service: "bar", Namespace: "micro", Status: runtime.JobFailed, Started: now.Add(-time.Minute)},
+		{ID: "d", Service: "foo", Namespace: "other", Status: runtime.JobRunning, Started: now},
+	}
+	for _, j := range jobs {
+		assert.NoError(t, m.writeJob(j))
+	}
+
+	res, err := m.ReadJobs("micro", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "c", "a"}, jobIDs(res))
+
+	res, err = m.ReadJobs("micro", "foo", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, jobIDs(res))
+
+	res, err = m.ReadJobs("micro", "foo", "a")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, jobIDs(res))
+
+	// finished jobs expire after the retention period
+	recs, err := store.Read(jobKey(jobs[0]))
+	assert.NoError(t, err)
+	assert.NotZero(t, recs[0].Expiry)
+	recs, err = store.Read(jobKey(jobs[1]))
+	assert.NoError(t, err)
+	assert.Zero(t, recs[0].Expiry)
+}
+
+func TestFinishJob(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Runner   *runtime.Service
+		Status   string
+		ExitCode int
+		Error    string
+	}{
+		{
+			Name:   "Running",
+			Runner: &runtime.Service{Status: runtime.Running, Metadata: map[string]string{}},
+			Status: runtime.JobRunning,
+		},
+		{
+			Name:   "Succeeded",
+			Runner: &runtime.Service{Status: runtime.Stopped, Metadata: map[string]string{"exit_code": "0"}},
+			Status: runtime.JobSucceeded,
+		},
+		{
+			Name: "Failed",
+			Runner: &runtime.Service{Status: runtime.Error, Metadata: map[string]string{
+				"exit_code": "3",
+				"error":     "exit status 3",
+			}},
+			Status:   runtime.JobFailed,
+			ExitCode: 3,
+			Error:    "exit status 3",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			job := &runtime.Job{Status: runtime.JobRunning}
+			finishJob(job, tc.Runner)
+			assert.Equal(t, tc.Status, job.Status)
+			assert.Equal(t, tc.ExitCode, job.ExitCode)
+			assert.Equal(t, tc.Error, job.Error)
+		})
+	}
+}
+
+func jobIDs(jobs []*runtime.Job) []string {
+	ids := make([]string, 0, len(jobs))
+	for _, j := range jobs {
+		ids = append(ids, j.ID)
+	}
+	return ids
+}
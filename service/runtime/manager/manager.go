@@ -1,6 +1,9 @@
 package manager
 
 import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -16,10 +19,15 @@ import (
 	"github.com/micro/micro/v3/service/client"
 	"github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/artifact"
 	kclient "github.com/micro/micro/v3/service/runtime/kubernetes/client"
 	"github.com/micro/micro/v3/service/runtime/source/git"
+	"github.com/micro/micro/v3/service/runtime/vuln"
 	"github.com/micro/micro/v3/service/store"
+	inauth "github.com/micro/micro/v3/util/auth"
 	"github.com/micro/micro/v3/util/namespace"
+	"github.com/micro/micro/v3/util/sync"
+	"github.com/micro/micro/v3/util/sync/memory"
 )
 
 const (
@@ -34,6 +42,12 @@ type service struct {
 	Status    runtime.ServiceStatus  `json:"status"`
 	UpdatedAt time.Time              `json:"last_updated"`
 	Error     string                 `json:"error"`
+	// EnvHash is the hash of the config values referenced by the env the service was started with
+	EnvHash string `json:"env_hash,omitempty"`
+	// MaxUnavailable is the number of instances replaced at a time when the service is updated
+	MaxUnavailable int `json:"max_unavailable,omitempty"`
+	// Digest of the build the service is running, set once it's built by the runtime
+	Digest string `json:"digest,omitempty"`
 }
 
 // key to write the service to the store under, e.g:
@@ -42,6 +56,11 @@ func (s *service) Key() string {
 	return servicePrefix + s.Options.Namespace + ":" + s.Service.Name + ":" + s.Service.Version
 }
 
+// buildKey is the name the build of the service is referenced by in the artifact store
+func buildKey(srv *service) string {
+	return fmt.Sprintf("build://%v:%v", srv.Service.Name, srv.Service.Version)
+}
+
 // unique is a helper method to filter a slice of strings
 // down to unique entries
 func unique(stringSlice []string) []string {
@@ -179,6 +198,9 @@ func (m *manager) buildAndRun(srv *service) {
 	if err := m.build(srv); err != nil {
 		return
 	}
+	if err := m.verify(srv); err != nil {
+		return
+	}
 
 	srv.Status = runtime.Starting
 	m.writeService(srv)
@@ -194,6 +216,9 @@ func (m *manager) buildAndUpdate(srv *service) {
 	if err := m.build(srv); err != nil {
 		return
 	}
+	if err := m.verify(srv); err != nil {
+		return
+	}
 
 	srv.Status = runtime.Starting
 	m.writeService(srv)
@@ -254,29 +279,49 @@ func (m *manager) build(srv *service) error {
 		return err
 	}
 
+	// the source is kept to generate the sbom of the build
+	src, err := ioutil.ReadAll(source)
+	if err != nil {
+		handleError(err, "Error loading source")
+		return err
+	}
+
 	// build the source
 	logger.Infof("Build starting %v:%v", srv.Service.Name, srv.Service.Version)
-	build, err := build.DefaultBuilder.Build(source,
+	started := time.Now()
+	var out []byte
+	build, err := build.DefaultBuilder.Build(bytes.NewReader(src),
 		build.Archive("tar"),
 		build.Entrypoint(srv.Options.Entrypoint),
 	)
+	if err == nil {
+		out, err = ioutil.ReadAll(build)
+	}
 	logger.Infof("Build finished %v:%v %v", srv.Service.Name, srv.Service.Version, err)
 	if err != nil {
 		handleError(err, "Error building service")
 		return err
 	}
+	srv.Digest = artifact.Digest(out)
 
 	// for the kubernetes runtime, the container needs to pull the source (it's not got access to the
-	// local filesystem like the local runtime does). hence we'll upload the source to the blob store
-	// which the cell (container) can then pull via the Runtime.Build.Read RPC.
+	// local filesystem like the local runtime does). hence we'll upload the build to the artifact
+	// store which the cell (container) can then pull via the Runtime.Build.Read RPC. identical
+	// builds are only stored once.
 	if m.Runtime.String() != "local" {
 		logger.Infof("Uploading build %v:%v", srv.Service.Name, srv.Service.Version)
-		nsOpt := store.BlobNamespace(srv.Options.Namespace)
-		key := fmt.Sprintf("build://%v:%v", srv.Service.Name, srv.Service.Version)
-		if err := store.DefaultBlobStore.Write(key, build, nsOpt); err != nil {
+		a, err := artifact.Put(srv.Options.Namespace, buildKey(srv), bytes.NewReader(out))
+		if err != nil {
 			handleError(err, "Error uploading build")
 			return err
 		}
+		logger.Infof("Uploaded build %v:%v as %v", srv.Service.Name, srv.Service.Version, artifact.Short(a.Digest))
+	}
+
+	// record the sbom and provenance of the build so it can be verified before it's deployed
+	if err := m.attest(srv, src, started); err != nil {
+		handleError(err, "Error attesting build")
+		return err
 	}
 
 	return nil
@@ -287,6 +332,8 @@ func (m *manager) updateServiceInRuntime(srv *service) error {
 	options := []runtime.UpdateOption{
 		runtime.UpdateEntrypoint(srv.Options.Entrypoint),
 		runtime.UpdateNamespace(srv.Options.Namespace),
+		runtime.UpdateImage(srv.Options.Image),
+		runtime.UpdateMaxUnavailable(srv.MaxUnavailable),
 	}
 
 	// add the secrets
@@ -306,6 +353,15 @@ func (m *manager) createServiceInRuntime(srv *service) error {
 		return err
 	}
 
+	// resolve the config referenced by the env
+	env, err := resolveEnv(newConfig(srv.Options.Namespace), srv.Options.Env)
+	if err != nil {
+		return err
+	}
+	srv.EnvHash = env.Hash
+	envOpts := *srv.Options
+	envOpts.Env = env.Env
+
 	// construct the options
 	options := []runtime.CreateOption{
 		runtime.CreateEntrypoint(srv.Options.Entrypoint),
@@ -314,7 +370,7 @@ func (m *manager) createServiceInRuntime(srv *service) error {
 		runtime.CreateNamespace(srv.Options.Namespace),
 		runtime.WithArgs(srv.Options.Args...),
 		runtime.WithCommand(srv.Options.Command...),
-		runtime.WithEnv(m.runtimeEnv(srv.Service, srv.Options)),
+		runtime.WithEnv(m.runtimeEnv(srv.Service, &envOpts)),
 		runtime.CreateInstances(srv.Options.Instances),
 		runtime.WithForce(srv.Options.Force),
 	}
@@ -323,6 +379,9 @@ func (m *manager) createServiceInRuntime(srv *service) error {
 	for key, value := range srv.Options.Secrets {
 		options = append(options, runtime.WithSecret(key, value))
 	}
+	for key, value := range env.Secrets {
+		options = append(options, runtime.WithSecret(key, value))
+	}
 
 	// inject the credentials into the service if present
 	if len(acc.ID) > 0 && len(acc.Secret) > 0 {
@@ -441,10 +500,18 @@ func (m *manager) runtimeEnv(srv *runtime.Service, options *runtime.CreateOption
 func (m *manager) generateAccount(srv *service) (*auth.Account, error) {
 	accName := srv.Service.Name + "-" + srv.Service.Version
 
+	// job runners act as the service the job is run for
+	name := srv.Service.Name
+	if v := srv.Service.Metadata[jobServiceKey]; len(v) > 0 {
+		name = v
+	}
+
 	opts := []auth.GenerateOption{
 		auth.WithIssuer(srv.Options.Namespace),
 		auth.WithScopes("service"),
 		auth.WithType("service"),
+		// bind the name of the service to its account so it can't claim to be another service
+		auth.WithMetadata(map[string]string{inauth.ServiceMetadataKey: name}),
 	}
 
 	acc, err := auth.Generate(accName, opts...)
@@ -476,10 +543,14 @@ func (m *manager) cleanupBlobStore(srv *service) {
 		return
 	}
 
-	// delete the binary
-	buildKey := fmt.Sprintf("build://%v:%v", srv.Service.Name, srv.Service.Version)
-	if err := store.DefaultBlobStore.Delete(buildKey, opt); err != nil && err != store.ErrNotFound {
-		logger.Warnf("Error deleting build %v: %v", srcKey, err)
+	// remove the reference to the build, the artifact is deleted once it's no longer referenced
+	key := buildKey(srv)
+	if err := artifact.Untag(srv.Options.Namespace, key); err != nil {
+		logger.Warnf("Error deleting build %v: %v", key, err)
+	}
+	// builds uploaded before the artifact store was used
+	if err := store.DefaultBlobStore.Delete(key, opt); err != nil && err != store.ErrNotFound {
+		logger.Warnf("Error deleting build %v: %v", key, err)
 	}
 }
 
@@ -575,6 +646,14 @@ func (m *manager) Create(resource runtime.Resource, opts ...runtime.CreateOption
 
 		// if there is not a build configured, start the service and then write it to the store
 		if build.DefaultBuilder == nil {
+			// services which aren't built by the runtime can't be verified or scanned
+			if err := artifact.Check(options.Namespace, "", m.trustedKeys()...); err != nil {
+				return err
+			}
+			if _, err := vuln.Check(options.Namespace, srv.Name, ""); err != nil {
+				return err
+			}
+
 			// the source could be a git remote or a reference to the blob store, parse it before we run
 			// the service
 			var err error
@@ -657,6 +736,11 @@ func (m *manager) Read(opts ...runtime.ReadOption) ([]*runtime.Service, error) {
 			result[i].Metadata["started"] = s.UpdatedAt.Format(time.RFC3339)
 		}
 
+		// the image is restored if a rolling restart onto a new image is rolled back
+		if len(s.Options.Image) > 0 {
+			result[i].Metadata["image"] = s.Options.Image
+		}
+
 		// the service might still be building and not have been created in the underlying runtime yet
 		rs, ok := rSrvMap[kclient.Format(s.Service.Name)+":"+kclient.Format(s.Service.Version)]
 		if !ok {
@@ -756,6 +840,16 @@ func (m *manager) Update(resource runtime.Resource, opts ...runtime.UpdateOption
 		if err != nil {
 			return err
 		}
+
+		// the core services are created directly in the runtime by micro server and aren't tracked
+		// by the manager, these can only have their image changed, e.g. by micro admin upgrade
+		if len(srvs) == 0 && len(options.Image) > 0 {
+			return m.Runtime.Update(srv,
+				runtime.UpdateNamespace(options.Namespace),
+				runtime.UpdateImage(options.Image),
+				runtime.UpdateMaxUnavailable(options.MaxUnavailable),
+			)
+		}
 		if len(srvs) == 0 {
 			return runtime.ErrNotFound
 		}
@@ -764,6 +858,7 @@ func (m *manager) Update(resource runtime.Resource, opts ...runtime.UpdateOption
 		service := srvs[0]
 		service.Service.Source = srv.Source
 		service.UpdatedAt = time.Now()
+		service.MaxUnavailable = options.MaxUnavailable
 		if options.Instances > 0 {
 			service.Options.Instances = options.Instances
 		}
@@ -773,9 +868,20 @@ func (m *manager) Update(resource runtime.Resource, opts ...runtime.UpdateOption
 		if len(options.Secrets) > 0 {
 			service.Options.Secrets = options.Secrets
 		}
+		if len(options.Image) > 0 {
+			service.Options.Image = options.Image
+		}
 
 		// if there is not a build configured, update the service and then write it to the store
 		if build.DefaultBuilder == nil {
+			// services which aren't built by the runtime can't be verified or scanned
+			if err := artifact.Check(options.Namespace, "", m.trustedKeys()...); err != nil {
+				return err
+			}
+			if _, err := vuln.Check(options.Namespace, srv.Name, ""); err != nil {
+				return err
+			}
+
 			// the source could be a git remote or a reference to the blob store, parse it before we run
 			// the service
 			var err error
@@ -930,7 +1036,9 @@ func (m *manager) Start() error {
 	}
 
 	// Watch services that were running previously. TODO: rename and run periodically
-	go m.watchServices()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	go m.watchServices(ctx)
 
 	return nil
 }
@@ -953,16 +1061,38 @@ func (m *manager) Logs(resource runtime.Resource, opts ...runtime.LogsOption) (r
 	}
 }
 
-// watchServices periodically checks services and whether they need to be recreated
-func (m *manager) watchServices() {
+// watchServices periodically checks services and whether they need to be recreated. When there
+// are multiple replicas of the runtime only the leader checks the services, if it dies another
+// replica is elected once its leadership expires.
+func (m *manager) watchServices(ctx context.Context) {
+	sync.Lead(ctx, m.options.Sync, "runtime.manager", m.lead)
+}
+
+// lead checks the services and jobs, restarts services whose env changed and collects
+// unreferenced builds until leadership is lost or the manager is stopped
+func (m *manager) lead(ctx context.Context) {
+	logger.Info("Elected leader of the runtime, checking services")
+
 	t := time.NewTicker(time.Second * 10)
 	defer t.Stop()
+	gc := time.NewTicker(artifact.DefaultGrace)
+	defer gc.Stop()
+	env := time.NewTicker(EnvCheckInterval)
+	defer env.Stop()
 
 	for {
 		select {
 		case <-t.C:
 			m.checkServices()
-		case <-m.exit:
+			m.checkJobs()
+		case <-env.C:
+			m.checkEnv()
+		case <-gc.C:
+			if _, err := artifact.GC(); err != nil {
+				logger.Errorf("Error collecting unreferenced artifacts: %v", err)
+			}
+		case <-ctx.Done():
+			logger.Info("No longer the leader of the runtime, stopped checking services")
 			return
 		}
 	}
@@ -975,11 +1105,8 @@ func (m *manager) Stop() error {
 	}
 	m.running = false
 
-	// ping to exit
-	select {
-	case m.exit <- true:
-	default:
-	}
+	// stop checking the services and resign leadership, or stop waiting for it
+	m.cancel()
 
 	return runtime.DefaultRuntime.Stop()
 }
@@ -992,15 +1119,57 @@ func (m *manager) String() string {
 type manager struct {
 	// running is true after Start is called
 	running bool
-	exit    chan bool
+	// cancel stops watching the services
+	cancel  context.CancelFunc
+	options Options
 
 	runtime.Runtime
 }
 
+// Options for the manager
+type Options struct {
+	// Sync is used to elect the manager which checks the services when there are multiple
+	// replicas of the runtime
+	Sync sync.Sync
+	// SigningKey the attestations of builds are signed with
+	SigningKey ed25519.PrivateKey
+	// Builder identifies the manager in the provenance of builds
+	Builder string
+}
+
+// Option sets an option
+type Option func(o *Options)
+
+// Sync sets the sync used for leader election
+func Sync(s sync.Sync) Option {
+	return func(o *Options) {
+		o.Sync = s
+	}
+}
+
+// SigningKey sets the key the attestations of builds are signed with
+func SigningKey(k ed25519.PrivateKey) Option {
+	return func(o *Options) {
+		o.SigningKey = k
+	}
+}
+
+// Builder sets the identity of the manager in the provenance of builds
+func Builder(b string) Option {
+	return func(o *Options) {
+		o.Builder = b
+	}
+}
+
 // New returns a manager for the runtime
-func New() runtime.Runtime {
+func New(opts ...Option) runtime.Runtime {
+	options := Options{Sync: memory.NewSync(), Builder: "micro-runtime"}
+	for _, o := range opts {
+		o(&options)
+	}
+
 	return &manager{
-		exit:    make(chan bool, 1),
+		options: options,
 		Runtime: NewCache(runtime.DefaultRuntime),
 	}
 }
@@ -250,6 +250,13 @@ func WithForce(f bool) CreateOption {
 	}
 }
 
+// UpdateMaxUnavailable sets the number of instances replaced at a time, e.g. by a rolling restart
+func UpdateMaxUnavailable(n int) UpdateOption {
+	return func(o *UpdateOptions) {
+		o.MaxUnavailable = n
+	}
+}
+
 // ReadService returns services with the given name
 func ReadService(service string) ReadOption {
 	return func(o *ReadOptions) {
@@ -298,6 +305,13 @@ type UpdateOptions struct {
 	Secrets map[string]string
 	// Number of instances
 	Instances int
+	// Image to run the service with
+	Image string
+	// Force the update even though the error budget of the service is exhausted
+	Force bool
+	// MaxUnavailable is the number of instances replaced at a time, all of them are replaced at
+	// once if zero
+	MaxUnavailable int
 }
 
 // WithSecret sets a secret to provide the service with
@@ -339,6 +353,20 @@ func UpdateInstances(v int) UpdateOption {
 	}
 }
 
+// UpdateImage sets the image to run the service with
+func UpdateImage(img string) UpdateOption {
+	return func(o *UpdateOptions) {
+		o.Image = img
+	}
+}
+
+// UpdateForce updates the service even though its error budget is exhausted
+func UpdateForce(f bool) UpdateOption {
+	return func(o *UpdateOptions) {
+		o.Force = f
+	}
+}
+
 type DeleteOption func(o *DeleteOptions)
 
 type DeleteOptions struct {
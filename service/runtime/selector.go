@@ -0,0 +1,165 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LabelPrefix is prefixed to the keys of the labels of a service in its metadata, e.g. the label
+// team=payments is set as label.team=payments
+const LabelPrefix = "label."
+
+var (
+	// labelKey is the format of the key of a label
+	labelKey = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9._-]*[a-zA-Z0-9])?$`)
+
+	// ErrInvalidSelector is returned when a selector can't be parsed
+	ErrInvalidSelector = errors.New("invalid selector")
+)
+
+// Labels returns the labels of the service, which are set in its metadata
+func (s *Service) Labels() map[string]string {
+	labels := map[string]string{}
+	for k, v := range s.Metadata {
+		if strings.HasPrefix(k, LabelPrefix) {
+			labels[strings.TrimPrefix(k, LabelPrefix)] = v
+		}
+	}
+	return labels
+}
+
+// ParseLabel parses a label in the format key=value, returning the key and value
+func ParseLabel(s string) (string, string, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || !labelKey.MatchString(parts[0]) {
+		return "", "", fmt.Errorf("invalid label %q, must be of form team=payments", s)
+	}
+	return parts[0], parts[1], nil
+}
+
+// Selector matches services by their labels. Each of its requirements must be met.
+type Selector []Requirement
+
+// Operator of a requirement
+type Operator string
+
+const (
+	// Equals requires the label to be set to the value
+	Equals Operator = "="
+	// NotEquals requires the label to not be set to the value, or not set at all
+	NotEquals Operator = "!="
+	// Exists requires the label to be set
+	Exists Operator = "exists"
+	// NotExists requires the label to not be set
+	NotExists Operator = "!"
+)
+
+// Requirement of a selector on a label
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Value    string
+}
+
+// ParseSelector parses a comma separated list of requirements, e.g. team=payments,tier!=frontend.
+// A key on its own requires the label to be set and a key prefixed with ! requires it not to be.
+func ParseSelector(s string) (Selector, error) {
+	var sel Selector
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if len(part) == 0 {
+			continue
+		}
+
+		var req Requirement
+		switch {
+		case strings.Contains(part, "!="):
+			kv := strings.SplitN(part, "!=", 2)
+			req = Requirement{Key: kv[0], Operator: NotEquals, Value: kv[1]}
+		case strings.Contains(part, "="):
+			kv := strings.SplitN(strings.Replace(part, "==", "=", 1), "=", 2)
+			req = Requirement{Key: kv[0], Operator: Equals, Value: kv[1]}
+		case strings.HasPrefix(part, "!"):
+			req = Requirement{Key: part[1:], Operator: NotExists}
+		default:
+			req = Requirement{Key: part, Operator: Exists}
+		}
+
+		req.Key = strings.TrimSpace(req.Key)
+		req.Value = strings.TrimSpace(req.Value)
+		if !labelKey.MatchString(req.Key) {
+			return nil, fmt.Errorf("%w %q, the key %q isn't a valid label", ErrInvalidSelector, s, req.Key)
+		}
+		sel = append(sel, req)
+	}
+
+	if len(sel) == 0 {
+		return nil, fmt.Errorf("%w %q, it has no requirements", ErrInvalidSelector, s)
+	}
+	return sel, nil
+}
+
+// Matches returns true if the labels meet every requirement of the selector
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, req := range s {
+		v, ok := labels[req.Key]
+		switch req.Operator {
+		case Equals:
+			if !ok || v != req.Value {
+				return false
+			}
+		case NotEquals:
+			if ok && v == req.Value {
+				return false
+			}
+		case Exists:
+			if !ok {
+				return false
+			}
+		case NotExists:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Select returns the services whose labels match the selector
+func (s Selector) Select(srvs []*Service) []*Service {
+	var selected []*Service
+	for _, srv := range srvs {
+		if s.Matches(srv.Labels()) {
+			selected = append(selected, srv)
+		}
+	}
+	return selected
+}
+
+func (s Selector) String() string {
+	parts := make([]string, len(s))
+	for i, req := range s {
+		switch req.Operator {
+		case Exists:
+			parts[i] = req.Key
+		case NotExists:
+			parts[i] = "!" + req.Key
+		default:
+			parts[i] = req.Key + string(req.Operator) + req.Value
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// FormatLabels returns the labels in the format team=payments,tier=frontend, sorted by key
+func FormatLabels(labels map[string]string) string {
+	parts := make([]string, 0, len(labels))
+	for k, v := range labels {
+		parts = append(parts, k+"="+v)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,68 @@
+package runtime
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLabel(t *testing.T) {
+	k, v, err := ParseLabel("team=payments")
+	assert.NoError(t, err)
+	assert.Equal(t, "team", k)
+	assert.Equal(t, "payments", v)
+
+	for _, l := range []string{"team", "=payments", "-team=payments", "te am=payments"} {
+		_, _, err := ParseLabel(l)
+		assert.Error(t, err, l)
+	}
+}
+
+func TestSelector(t *testing.T) {
+	srvs := []*Service{
+		{Name: "checkout", Metadata: map[string]string{"label.team": "payments", "label.tier": "backend"}},
+		{Name: "web", Metadata: map[string]string{"label.team": "payments", "label.tier": "frontend"}},
+		{Name: "search", Metadata: map[string]string{"label.team": "discovery", "owner": "bob"}},
+		{Name: "helloworld"},
+	}
+
+	tt := []struct {
+		Selector string
+		Selected []string
+	}{
+		{Selector: "team=payments", Selected: []string{"checkout", "web"}},
+		{Selector: "team==payments", Selected: []string{"checkout", "web"}},
+		{Selector: "team=payments,tier!=frontend", Selected: []string{"checkout"}},
+		{Selector: "tier!=frontend", Selected: []string{"checkout", "search", "helloworld"}},
+		{Selector: "tier", Selected: []string{"checkout", "web"}},
+		{Selector: "!tier", Selected: []string{"search", "helloworld"}},
+		{Selector: " team = discovery ", Selected: []string{"search"}},
+		{Selector: "owner=bob", Selected: nil},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Selector, func(t *testing.T) {
+			sel, err := ParseSelector(tc.Selector)
+			assert.NoError(t, err)
+
+			var names []string
+			for _, srv := range sel.Select(srvs) {
+				names = append(names, srv.Name)
+			}
+			assert.Equal(t, tc.Selected, names)
+		})
+	}
+
+	for _, s := range []string{"", ",", "=payments", "team=payments,!"} {
+		_, err := ParseSelector(s)
+		assert.True(t, errors.Is(err, ErrInvalidSelector), s)
+	}
+}
+
+func TestSelectorString(t *testing.T) {
+	sel, err := ParseSelector("team==payments, tier!=frontend,canary,!legacy")
+	assert.NoError(t, err)
+	assert.Equal(t, "team=payments,tier!=frontend,canary,!legacy", sel.String())
+	assert.Equal(t, "team=payments,tier=backend", FormatLabels(map[string]string{"tier": "backend", "team": "payments"}))
+}
@@ -7,8 +7,13 @@ import (
 	"github.com/micro/micro/v3/service"
 	log "github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/artifact"
 	"github.com/micro/micro/v3/service/runtime/handler"
 	"github.com/micro/micro/v3/service/runtime/manager"
+	"github.com/micro/micro/v3/service/runtime/vuln"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/util/sync"
+	storesync "github.com/micro/micro/v3/util/sync/store"
 	"github.com/urfave/cli/v2"
 )
 
@@ -30,6 +35,17 @@ var (
 			Usage:   "Set the max retries per service",
 			EnvVars: []string{"MICRO_RUNTIME_RETRIES"},
 		},
+		&cli.StringFlag{
+			Name:    "build_signing_key",
+			Usage:   "Base64 encoded ed25519 key the provenance attestations of builds are signed with",
+			EnvVars: []string{"MICRO_RUNTIME_BUILD_SIGNING_KEY"},
+		},
+		&cli.StringFlag{
+			Name:    "vuln_scanner_url",
+			Usage:   "Url of the OSV api the builds deployed to namespaces with a vulnerability policy are scanned with",
+			EnvVars: []string{"MICRO_RUNTIME_VULN_SCANNER_URL"},
+			Value:   vuln.DefaultOSVURL,
+		},
 	}
 )
 
@@ -53,14 +69,30 @@ func Run(ctx *cli.Context) error {
 		runtime.DefaultRuntime.Init(runtime.WithSource(ctx.String("source")))
 	}
 
+	// the scanner of the vulnerability gate
+	vuln.DefaultScanner = vuln.NewOSVScanner(ctx.String("vuln_scanner_url"))
+
 	// append name
 	srvOpts = append(srvOpts, service.Name(name))
 
 	// new service
 	srv := service.New(srvOpts...)
 
-	// create a new runtime manager
-	manager := manager.New()
+	// create a new runtime manager, the replicas of the runtime elect a leader using the store
+	mopts := []manager.Option{
+		manager.Sync(storesync.NewSync(store.DefaultStore, sync.Prefix("runtime/"))),
+	}
+	if host, err := os.Hostname(); err == nil {
+		mopts = append(mopts, manager.Builder(name+"@"+host))
+	}
+	if k := ctx.String("build_signing_key"); len(k) > 0 {
+		key, err := artifact.ParseSigningKey(k)
+		if err != nil {
+			return err
+		}
+		mopts = append(mopts, manager.SigningKey(key))
+	}
+	manager := manager.New(mopts...)
 
 	// start the manager
 	if err := manager.Start(); err != nil {
@@ -72,6 +104,9 @@ func Run(ctx *cli.Context) error {
 	pb.RegisterRuntimeHandler(srv.Server(), &handler.Runtime{Runtime: manager})
 	pb.RegisterBuildHandler(srv.Server(), new(handler.Build))
 	pb.RegisterSourceHandler(srv.Server(), new(handler.Source))
+	if runner, ok := manager.(handler.JobRunner); ok {
+		pb.RegisterJobsHandler(srv.Server(), &handler.Jobs{Runner: runner})
+	}
 
 	// start runtime service
 	if err := srv.Run(); err != nil {
@@ -0,0 +1,87 @@
+package vuln
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// cvss3 weights of the base metrics, see https://www.first.org/cvss/v3.1/specification-document
+var cvss3 = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"PR": {"N": 0.85, "L": 0.62, "H": 0.27},
+	"UI": {"N": 0.85, "R": 0.62},
+	"S":  {"U": 0, "C": 0},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// ParseCVSS returns the severity of a CVSS v3 vector, e.g.
+// CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H, rated by its base score. Scores of none are
+// rated low.
+func ParseCVSS(vector string) (Severity, error) {
+	parts := strings.Split(vector, "/")
+	if !strings.HasPrefix(parts[0], "CVSS:3.") {
+		return Unknown, fmt.Errorf("unsupported cvss vector %q", vector)
+	}
+
+	m := map[string]string{}
+	for _, p := range parts[1:] {
+		kv := strings.SplitN(p, ":", 2)
+		if len(kv) != 2 {
+			return Unknown, fmt.Errorf("invalid cvss vector %q", vector)
+		}
+		m[kv[0]] = kv[1]
+	}
+	w := map[string]float64{}
+	for metric, values := range cvss3 {
+		v, ok := values[m[metric]]
+		if !ok {
+			return Unknown, fmt.Errorf("invalid cvss vector %q, missing %v", vector, metric)
+		}
+		w[metric] = v
+	}
+
+	// privileges required weigh more when the scope changes
+	changed := m["S"] == "C"
+	if changed && m["PR"] == "L" {
+		w["PR"] = 0.68
+	} else if changed && m["PR"] == "H" {
+		w["PR"] = 0.5
+	}
+
+	iss := 1 - (1-w["C"])*(1-w["I"])*(1-w["A"])
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	exploitability := 8.22 * w["AV"] * w["AC"] * w["PR"] * w["UI"]
+
+	var score float64
+	if impact > 0 && changed {
+		score = roundUp(math.Min(1.08*(impact+exploitability), 10))
+	} else if impact > 0 {
+		score = roundUp(math.Min(impact+exploitability, 10))
+	}
+
+	switch {
+	case score >= 9:
+		return Critical, nil
+	case score >= 7:
+		return High, nil
+	case score >= 4:
+		return Medium, nil
+	}
+	return Low, nil
+}
+
+// roundUp to one decimal place as defined by the specification
+func roundUp(f float64) float64 {
+	i := int(math.Round(f * 100000))
+	if i%10000 == 0 {
+		return float64(i) / 100000
+	}
+	return (math.Floor(float64(i)/10000) + 1) / 10
+}
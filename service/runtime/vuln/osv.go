@@ -0,0 +1,207 @@
+package vuln
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/micro/micro/v3/service/runtime/artifact"
+)
+
+// DefaultOSVURL is the url of the OSV api, see https://osv.dev
+const DefaultOSVURL = "https://api.osv.dev"
+
+type osvScanner struct {
+	url    string
+	client *http.Client
+}
+
+// NewOSVScanner returns a scanner which queries the OSV api at the url for the advisories of the
+// components. Advisories are rated by the severity of their database, e.g. GitHub advisories,
+// falling back to their CVSS vector, and advisories which are aliases of one another are
+// reported once.
+func NewOSVScanner(url string) Scanner {
+	return &osvScanner{
+		url:    strings.TrimSuffix(url, "/"),
+		client: &http.Client{Timeout: time.Second * 30},
+	}
+}
+
+type osvQuery struct {
+	Package struct {
+		Purl string `json:"purl"`
+	} `json:"package"`
+}
+
+type osvVuln struct {
+	ID       string   `json:"id"`
+	Summary  string   `json:"summary"`
+	Aliases  []string `json:"aliases"`
+	Severity []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+	Affected []struct {
+		Package struct {
+			Name string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Events []struct {
+				Fixed string `json:"fixed"`
+			} `json:"events"`
+		} `json:"ranges"`
+	} `json:"affected"`
+}
+
+func (o *osvScanner) Scan(sbom *artifact.SBOM) ([]*Vulnerability, error) {
+	var comps []*artifact.Component
+	var queries []*osvQuery
+	for _, c := range sbom.Components {
+		if len(c.Purl) == 0 {
+			continue
+		}
+		q := &osvQuery{}
+		q.Package.Purl = c.Purl
+		comps = append(comps, c)
+		queries = append(queries, q)
+	}
+	if len(queries) == 0 {
+		return nil, nil
+	}
+
+	// the batch api only returns the ids of the advisories
+	var batch struct {
+		Results []struct {
+			Vulns []struct {
+				ID string `json:"id"`
+			} `json:"vulns"`
+		} `json:"results"`
+	}
+	if err := o.do("POST", "/v1/querybatch", map[string]interface{}{"queries": queries}, &batch); err != nil {
+		return nil, err
+	}
+	if len(batch.Results) != len(queries) {
+		return nil, fmt.Errorf("unexpected response from osv, %d results for %d queries", len(batch.Results), len(queries))
+	}
+
+	advisories := map[string]*osvVuln{}
+	var res []*Vulnerability
+	for i, r := range batch.Results {
+		var found []*Vulnerability
+		for _, ref := range r.Vulns {
+			adv, ok := advisories[ref.ID]
+			if !ok {
+				adv = &osvVuln{}
+				if err := o.do("GET", "/v1/vulns/"+ref.ID, nil, adv); err != nil {
+					return nil, err
+				}
+				advisories[ref.ID] = adv
+			}
+			found = merge(found, vulnerability(comps[i], adv))
+		}
+		res = append(res, found...)
+	}
+	return res, nil
+}
+
+func (o *osvScanner) String() string {
+	return "osv"
+}
+
+func (o *osvScanner) do(method, path string, body, rsp interface{}) error {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, o.url+path, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	r, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+	if r.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response %v from osv", r.Status)
+	}
+	return json.NewDecoder(r.Body).Decode(rsp)
+}
+
+func vulnerability(c *artifact.Component, adv *osvVuln) *Vulnerability {
+	v := &Vulnerability{
+		ID:        adv.ID,
+		Aliases:   append([]string(nil), adv.Aliases...),
+		Component: c.Name,
+		Version:   c.Version,
+		Summary:   adv.Summary,
+	}
+	v.Severity, _ = ParseSeverity(adv.DatabaseSpecific.Severity)
+	for _, s := range adv.Severity {
+		if v.Severity != Unknown {
+			break
+		}
+		if s.Type == "CVSS_V3" {
+			v.Severity, _ = ParseCVSS(s.Score)
+		}
+	}
+	for _, a := range adv.Affected {
+		if a.Package.Name != c.Name {
+			continue
+		}
+		for _, r := range a.Ranges {
+			for _, e := range r.Events {
+				if len(e.Fixed) > 0 {
+					v.Fixed = e.Fixed
+				}
+			}
+		}
+	}
+	return v
+}
+
+// merge the vulnerability into those found, if it's an alias of one found the one with the
+// highest severity is kept
+func merge(found []*Vulnerability, v *Vulnerability) []*Vulnerability {
+	for i, f := range found {
+		if !related(f, v) {
+			continue
+		}
+		keep, drop := f, v
+		if v.Severity > f.Severity {
+			keep, drop = v, f
+		}
+		if len(keep.Fixed) == 0 {
+			keep.Fixed = drop.Fixed
+		}
+		// the ids of both can still be exempted
+		for _, id := range append([]string{drop.ID}, drop.Aliases...) {
+			if !keep.Matches(id) {
+				keep.Aliases = append(keep.Aliases, id)
+			}
+		}
+		found[i] = keep
+		return found
+	}
+	return append(found, v)
+}
+
+// related returns true if the vulnerabilities are the same advisory in different databases
+func related(a, b *Vulnerability) bool {
+	for _, id := range append([]string{b.ID}, b.Aliases...) {
+		if a.Matches(id) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,425 @@
+// Package vuln scans the dependencies of builds for known vulnerabilities before they're deployed.
+// The policy of a namespace sets the severity at which deploys are blocked, or only warned about,
+// and vulnerabilities can be exempted from the policy for a time, e.g. while a fix is rolled out.
+package vuln
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/runtime/artifact"
+	"github.com/micro/micro/v3/service/store"
+)
+
+var (
+	// ErrBlocked is returned when a build has vulnerabilities above the threshold of the policy
+	ErrBlocked = errors.New("deploy blocked by vulnerabilities")
+	// ErrNotFound is returned when a report or exemption doesn't exist
+	ErrNotFound = errors.New("not found")
+
+	// DefaultScanner scans the builds
+	DefaultScanner Scanner = NewOSVScanner(DefaultOSVURL)
+	// DefaultExemptionTTL is how long an exemption lasts if no expiry is set
+	DefaultExemptionTTL = time.Hour * 24 * 30
+	// Topic alerts about vulnerable deploys are published to
+	Topic = "alerts"
+
+	database = "micro"
+	table    = "vulnerabilities"
+
+	reportPrefix    = "report:"
+	policyPrefix    = "policy:"
+	exemptionPrefix = "exemption:"
+)
+
+// Severity of a vulnerability
+type Severity int
+
+const (
+	// Unknown is the severity of vulnerabilities which weren't rated
+	Unknown Severity = iota
+	Low
+	Medium
+	High
+	Critical
+)
+
+var severities = []string{"unknown", "low", "medium", "high", "critical"}
+
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severities) {
+		return severities[0]
+	}
+	return severities[s]
+}
+
+// MarshalJSON encodes the severity as its name
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON decodes the severity from its name
+func (s *Severity) UnmarshalJSON(b []byte) error {
+	var v string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	sev, err := ParseSeverity(v)
+	if err != nil {
+		return err
+	}
+	*s = sev
+	return nil
+}
+
+// ParseSeverity parses the name of a severity, e.g. high
+func ParseSeverity(s string) (Severity, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "moderate" {
+		return Medium, nil
+	}
+	for i, v := range severities {
+		if v == s {
+			return Severity(i), nil
+		}
+	}
+	return Unknown, fmt.Errorf("invalid severity %q, must be one of %v", s, strings.Join(severities[1:], ", "))
+}
+
+// Vulnerability of a component of a build
+type Vulnerability struct {
+	// ID of the advisory, e.g. GHSA-xxxx-xxxx-xxxx
+	ID string `json:"id"`
+	// Aliases of the advisory in other databases, e.g. CVE-2021-1234
+	Aliases   []string `json:"aliases,omitempty"`
+	Component string   `json:"component"`
+	Version   string   `json:"version"`
+	Severity  Severity `json:"severity"`
+	Summary   string   `json:"summary,omitempty"`
+	// Fixed is the version the vulnerability is fixed in, if there is one
+	Fixed string `json:"fixed,omitempty"`
+}
+
+// Matches returns true if the id is the id of the vulnerability or one of its aliases
+func (v *Vulnerability) Matches(id string) bool {
+	if strings.EqualFold(v.ID, id) {
+		return true
+	}
+	for _, a := range v.Aliases {
+		if strings.EqualFold(a, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Scanner finds the known vulnerabilities of the components of an SBOM
+type Scanner interface {
+	Scan(sbom *artifact.SBOM) ([]*Vulnerability, error)
+	String() string
+}
+
+// Report of a scan of a build
+type Report struct {
+	Digest          string           `json:"digest"`
+	Scanner         string           `json:"scanner"`
+	Scanned         time.Time        `json:"scanned"`
+	Vulnerabilities []*Vulnerability `json:"vulnerabilities"`
+}
+
+// Scan the build with the digest and store the report, the vulnerabilities are sorted by
+// severity, highest first
+func Scan(digest string, sbom *artifact.SBOM) (*Report, error) {
+	vulns, err := DefaultScanner.Scan(sbom)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(vulns, func(i, j int) bool { return vulns[i].Severity > vulns[j].Severity })
+
+	r := &Report{
+		Digest:          digest,
+		Scanner:         DefaultScanner.String(),
+		Scanned:         time.Now(),
+		Vulnerabilities: vulns,
+	}
+	if err := write(reportPrefix+digest, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetReport returns the last report of the build with the digest
+func GetReport(digest string) (*Report, error) {
+	var r *Report
+	if err := read(reportPrefix+digest, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Policy of the vulnerabilities the builds deployed to a namespace can have
+type Policy struct {
+	Namespace string `json:"namespace"`
+	// Threshold is the lowest severity which violates the policy
+	Threshold Severity `json:"threshold"`
+	// Warn only warns about deploys which violate the policy rather than blocking them
+	Warn    bool      `json:"warn"`
+	Account string    `json:"account"`
+	Updated time.Time `json:"updated"`
+}
+
+// GetPolicy returns the policy of a namespace, nil is returned if it doesn't have one
+func GetPolicy(ns string) (*Policy, error) {
+	var p *Policy
+	if err := read(policyPrefix+ns, &p); err == ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// SetPolicy sets the policy of a namespace
+func SetPolicy(p *Policy) error {
+	if len(p.Namespace) == 0 {
+		return errors.New("missing namespace")
+	}
+	if p.Threshold == Unknown {
+		return errors.New("missing threshold")
+	}
+	if p.Updated.IsZero() {
+		p.Updated = time.Now()
+	}
+	return write(policyPrefix+p.Namespace, p)
+}
+
+// DeletePolicy deletes the policy of a namespace, builds are no longer scanned before they're
+// deployed to it
+func DeletePolicy(ns string) error {
+	return remove(policyPrefix + ns)
+}
+
+// ListPolicies returns the policies of every namespace
+func ListPolicies() ([]*Policy, error) {
+	var policies []*Policy
+	err := list(policyPrefix, func(b []byte) error {
+		var p *Policy
+		if err := json.Unmarshal(b, &p); err != nil {
+			return err
+		}
+		policies = append(policies, p)
+		return nil
+	})
+	return policies, err
+}
+
+// Exemption of a vulnerability from the policy of a namespace
+type Exemption struct {
+	Namespace string `json:"namespace"`
+	// ID of the vulnerability or one of its aliases
+	ID      string    `json:"id"`
+	Reason  string    `json:"reason"`
+	Account string    `json:"account"`
+	Created time.Time `json:"created"`
+	Expires time.Time `json:"expires"`
+}
+
+// Expired returns true if the exemption no longer applies
+func (e *Exemption) Expired() bool {
+	return time.Now().After(e.Expires)
+}
+
+// Exempt the vulnerability from the policy of the namespace until the exemption expires. A reason
+// is required so it can be reviewed.
+func Exempt(e *Exemption) error {
+	if len(e.Namespace) == 0 {
+		return errors.New("missing namespace")
+	}
+	if len(e.ID) == 0 {
+		return errors.New("missing id")
+	}
+	if len(e.Reason) == 0 {
+		return errors.New("missing reason")
+	}
+	if e.Created.IsZero() {
+		e.Created = time.Now()
+	}
+	if e.Expires.IsZero() {
+		e.Expires = e.Created.Add(DefaultExemptionTTL)
+	}
+	return write(exemptionKey(e.Namespace, e.ID), e)
+}
+
+// Unexempt removes the exemption of the vulnerability from the policy of the namespace
+func Unexempt(ns, id string) error {
+	return remove(exemptionKey(ns, id))
+}
+
+// ListExemptions returns the exemptions of the namespace, including those which have expired
+func ListExemptions(ns string) ([]*Exemption, error) {
+	var exemptions []*Exemption
+	err := list(exemptionKey(ns, ""), func(b []byte) error {
+		var e *Exemption
+		if err := json.Unmarshal(b, &e); err != nil {
+			return err
+		}
+		exemptions = append(exemptions, e)
+		return nil
+	})
+	sort.Slice(exemptions, func(i, j int) bool { return exemptions[i].ID < exemptions[j].ID })
+	return exemptions, err
+}
+
+func exemptionKey(ns, id string) string {
+	return exemptionPrefix + ns + ":" + strings.ToUpper(id)
+}
+
+// Violations returns the vulnerabilities of the report which violate the policy, excluding those
+// exempted. Vulnerabilities which weren't rated violate any threshold.
+func Violations(p *Policy, r *Report, exemptions []*Exemption) []*Vulnerability {
+	var res []*Vulnerability
+	for _, v := range r.Vulnerabilities {
+		if v.Severity != Unknown && v.Severity < p.Threshold {
+			continue
+		}
+		var exempt bool
+		for _, e := range exemptions {
+			if !e.Expired() && v.Matches(e.ID) {
+				exempt = true
+				break
+			}
+		}
+		if !exempt {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// Check the build of the service with the digest can be deployed to the namespace, scanning the
+// sbom of its attestation if the namespace has a policy. If the policy blocks the deploy an error
+// wrapping ErrBlocked is returned, when it only warns the violations are returned and an alert is
+// published. Services without a digest weren't built by the runtime so can't be scanned.
+func Check(ns, service, digest string) ([]*Vulnerability, error) {
+	p, err := GetPolicy(ns)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the vulnerability policy of %v: %v", ns, err)
+	}
+	if p == nil {
+		return nil, nil
+	}
+
+	// fail closed when the policy blocks deploys
+	fail := func(err error) ([]*Vulnerability, error) {
+		if p.Warn {
+			logger.Warnf("Error scanning %v in %v: %v", service, ns, err)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: %v", ErrBlocked, err)
+	}
+	if len(digest) == 0 {
+		return fail(errors.New("the service wasn't built by the runtime so can't be scanned"))
+	}
+	a, err := artifact.GetAttestation(digest)
+	if err != nil {
+		return fail(fmt.Errorf("error reading the attestation of %v: %v", artifact.Short(digest), err))
+	}
+	if a.SBOM == nil {
+		return fail(fmt.Errorf("%v has no sbom", artifact.Short(a.Digest)))
+	}
+
+	r, err := Scan(a.Digest, a.SBOM)
+	if err != nil {
+		return fail(fmt.Errorf("error scanning %v: %v", artifact.Short(a.Digest), err))
+	}
+	exemptions, err := ListExemptions(ns)
+	if err != nil {
+		return nil, err
+	}
+
+	vulns := Violations(p, r, exemptions)
+	if len(vulns) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(vulns))
+	for i, v := range vulns {
+		ids[i] = v.ID
+	}
+	if !p.Warn {
+		return vulns, fmt.Errorf("%w: %v has %d vulnerabilities of %v severity or above: %v",
+			ErrBlocked, artifact.Short(a.Digest), len(vulns), p.Threshold, strings.Join(ids, ", "))
+	}
+
+	publish(&Alert{
+		Type:            "vulnerability",
+		Service:         service,
+		Namespace:       ns,
+		Digest:          a.Digest,
+		Severity:        vulns[0].Severity,
+		Vulnerabilities: ids,
+		Time:            time.Now(),
+	})
+	return vulns, nil
+}
+
+// Alert published when a build which violates the policy of its namespace is deployed
+type Alert struct {
+	Type            string    `json:"type"`
+	Service         string    `json:"service"`
+	Namespace       string    `json:"namespace"`
+	Digest          string    `json:"digest"`
+	Severity        Severity  `json:"severity"`
+	Vulnerabilities []string  `json:"vulnerabilities"`
+	Time            time.Time `json:"time"`
+}
+
+func publish(a *Alert) {
+	logger.Warnf("Deployed %v to %v with %d vulnerabilities: %v", a.Service, a.Namespace, len(a.Vulnerabilities), strings.Join(a.Vulnerabilities, ", "))
+	if err := events.Publish(Topic, a); err != nil {
+		logger.Errorf("Error publishing alert: %v", err)
+	}
+}
+
+func read(key string, v interface{}) error {
+	recs, err := store.Read(key, store.ReadFrom(database, table))
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	return json.Unmarshal(recs[0].Value, v)
+}
+
+func list(prefix string, fn func(b []byte) error) error {
+	recs, err := store.Read(prefix, store.ReadFrom(database, table), store.ReadPrefix())
+	if err == store.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, rec := range recs {
+		if err := fn(rec.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func write(key string, v interface{}) error {
+	return store.DefaultStore.Write(store.NewRecord(key, v), store.WriteTo(database, table))
+}
+
+func remove(key string) error {
+	err := store.DefaultStore.Delete(key, store.DeleteFrom(database, table))
+	if err == store.ErrNotFound {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,214 @@
+package vuln
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/events"
+	memstream "github.com/micro/micro/v3/service/events/stream/memory"
+	"github.com/micro/micro/v3/service/runtime/artifact"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// testOSV serves advisories for github.com/example/vulnerable
+func testOSV(t *testing.T) *httptest.Server {
+	advisories := map[string]string{
+		"GO-2021-0001":        `{"id":"GO-2021-0001","aliases":["CVE-2021-1234"],"affected":[{"package":{"name":"github.com/example/vulnerable"},"ranges":[{"events":[{"introduced":"0"},{"fixed":"v1.2.0"}]}]}]}`,
+		"GHSA-aaaa-bbbb-cccc": `{"id":"GHSA-aaaa-bbbb-cccc","summary":"Remote code execution","aliases":["CVE-2021-1234"],"database_specific":{"severity":"CRITICAL"}}`,
+		"GHSA-dddd-eeee-ffff": `{"id":"GHSA-dddd-eeee-ffff","summary":"Denial of service","severity":[{"type":"CVSS_V3","score":"CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:U/C:N/I:N/A:H"}]}`,
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/querybatch" {
+			var req struct {
+				Queries []osvQuery `json:"queries"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+			var results []string
+			for _, q := range req.Queries {
+				if strings.HasPrefix(q.Package.Purl, "pkg:golang/github.com/example/vulnerable@") {
+					results = append(results, `{"vulns":[{"id":"GO-2021-0001"},{"id":"GHSA-aaaa-bbbb-cccc"},{"id":"GHSA-dddd-eeee-ffff"}]}`)
+				} else {
+					results = append(results, `{}`)
+				}
+			}
+			w.Write([]byte(`{"results":[` + strings.Join(results, ",") + `]}`))
+			return
+		}
+
+		adv, ok := advisories[strings.TrimPrefix(r.URL.Path, "/v1/vulns/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(adv))
+	}))
+}
+
+func testSBOM() *artifact.SBOM {
+	return &artifact.SBOM{Components: []*artifact.Component{
+		{Name: "github.com/example/safe", Version: "v1.0.0", Purl: "pkg:golang/github.com/example/safe@v1.0.0"},
+		{Name: "github.com/example/vulnerable", Version: "v1.1.0", Purl: "pkg:golang/github.com/example/vulnerable@v1.1.0"},
+	}}
+}
+
+func TestSeverity(t *testing.T) {
+	s, err := ParseSeverity("MODERATE")
+	assert.NoError(t, err)
+	assert.Equal(t, Medium, s)
+	_, err = ParseSeverity("severe")
+	assert.Error(t, err)
+
+	b, err := json.Marshal(High)
+	assert.NoError(t, err)
+	assert.Equal(t, `"high"`, string(b))
+	assert.NoError(t, json.Unmarshal([]byte(`"critical"`), &s))
+	assert.Equal(t, Critical, s)
+}
+
+func TestParseCVSS(t *testing.T) {
+	tt := map[string]Severity{
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H": Critical, // 9.8
+		"CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H": Critical, // 9.9
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H": High,     // 7.5
+		"CVSS:3.0/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N": Medium,   // 6.1
+		"CVSS:3.1/AV:L/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N": Low,      // 1.8
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N": Low,      // 0.0
+	}
+	for vector, sev := range tt {
+		s, err := ParseCVSS(vector)
+		assert.NoError(t, err, vector)
+		assert.Equal(t, sev, s, vector)
+	}
+
+	_, err := ParseCVSS("CVSS:2.0/AV:N/AC:L/Au:N/C:P/I:P/A:P")
+	assert.Error(t, err)
+	_, err = ParseCVSS("CVSS:3.1/AV:N/AC:L")
+	assert.Error(t, err)
+}
+
+func TestViolations(t *testing.T) {
+	r := &Report{Vulnerabilities: []*Vulnerability{
+		{ID: "GHSA-aaaa-bbbb-cccc", Severity: Low},
+		{ID: "GO-2021-0001", Severity: Unknown},
+		{ID: "GHSA-dddd-eeee-ffff", Severity: High},
+	}}
+
+	// unrated vulnerabilities violate any threshold
+	vulns := Violations(&Policy{Threshold: Critical}, r, nil)
+	if assert.Len(t, vulns, 1) {
+		assert.Equal(t, "GO-2021-0001", vulns[0].ID)
+	}
+	vulns = Violations(&Policy{Threshold: Medium}, r, nil)
+	assert.Len(t, vulns, 2)
+}
+
+func TestScan(t *testing.T) {
+	srv := testOSV(t)
+	defer srv.Close()
+
+	vulns, err := NewOSVScanner(srv.URL).Scan(testSBOM())
+	assert.NoError(t, err)
+	if assert.Len(t, vulns, 2) {
+		// aliases of one another are reported once with the highest severity
+		assert.Equal(t, "GHSA-aaaa-bbbb-cccc", vulns[0].ID)
+		assert.Equal(t, Critical, vulns[0].Severity)
+		assert.Equal(t, "v1.2.0", vulns[0].Fixed)
+		assert.True(t, vulns[0].Matches("GO-2021-0001"))
+		assert.True(t, vulns[0].Matches("cve-2021-1234"))
+
+		assert.Equal(t, "GHSA-dddd-eeee-ffff", vulns[1].ID)
+		assert.Equal(t, Medium, vulns[1].Severity)
+		assert.Equal(t, "github.com/example/vulnerable", vulns[1].Component)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	srv := testOSV(t)
+	defer srv.Close()
+	DefaultScanner = NewOSVScanner(srv.URL)
+	store.DefaultStore = memory.NewStore()
+	stream, err := memstream.NewStream()
+	assert.NoError(t, err)
+	events.DefaultStream = stream
+	alerts, err := events.Consume(Topic)
+	assert.NoError(t, err)
+
+	digest := artifact.Digest([]byte("binary"))
+	assert.NoError(t, artifact.Attest(&artifact.Attestation{Digest: digest, SBOM: testSBOM()}))
+
+	// builds aren't scanned without a policy
+	vulns, err := Check("foo", "users", digest)
+	assert.NoError(t, err)
+	assert.Empty(t, vulns)
+	_, err = GetReport(digest)
+	assert.Equal(t, ErrNotFound, err)
+
+	assert.Error(t, SetPolicy(&Policy{Namespace: "foo"}))
+	assert.NoError(t, SetPolicy(&Policy{Namespace: "foo", Threshold: High}))
+
+	vulns, err = Check("foo", "users", digest)
+	assert.True(t, errors.Is(err, ErrBlocked))
+	if assert.Len(t, vulns, 1) {
+		assert.Equal(t, "GHSA-aaaa-bbbb-cccc", vulns[0].ID)
+	}
+	r, err := GetReport(digest)
+	assert.NoError(t, err)
+	assert.Len(t, r.Vulnerabilities, 2)
+
+	// services which weren't built by the runtime can't be scanned
+	_, err = Check("foo", "users", "")
+	assert.True(t, errors.Is(err, ErrBlocked))
+
+	// exemptions require a reason and can be made by any alias
+	assert.Error(t, Exempt(&Exemption{Namespace: "foo", ID: "CVE-2021-1234"}))
+	assert.NoError(t, Exempt(&Exemption{Namespace: "foo", ID: "cve-2021-1234", Reason: "not reachable"}))
+	vulns, err = Check("foo", "users", digest)
+	assert.NoError(t, err)
+	assert.Empty(t, vulns)
+
+	exemptions, err := ListExemptions("foo")
+	assert.NoError(t, err)
+	if assert.Len(t, exemptions, 1) {
+		assert.Equal(t, DefaultExemptionTTL, exemptions[0].Expires.Sub(exemptions[0].Created))
+	}
+
+	// expired exemptions no longer apply
+	assert.NoError(t, Exempt(&Exemption{Namespace: "foo", ID: "CVE-2021-1234", Reason: "not reachable", Expires: time.Now().Add(-time.Minute)}))
+	_, err = Check("foo", "users", digest)
+	assert.True(t, errors.Is(err, ErrBlocked))
+	assert.NoError(t, Unexempt("foo", "CVE-2021-1234"))
+	exemptions, err = ListExemptions("foo")
+	assert.NoError(t, err)
+	assert.Empty(t, exemptions)
+
+	// policies which only warn return the violations without blocking the deploy, alerting on them
+	assert.NoError(t, SetPolicy(&Policy{Namespace: "foo", Threshold: Medium, Warn: true}))
+	vulns, err = Check("foo", "users", digest)
+	assert.NoError(t, err)
+	assert.Len(t, vulns, 2)
+
+	select {
+	case ev := <-alerts:
+		var a Alert
+		assert.NoError(t, ev.Unmarshal(&a))
+		assert.Equal(t, "users", a.Service)
+		assert.Equal(t, Critical, a.Severity)
+		assert.Len(t, a.Vulnerabilities, 2)
+	case <-time.After(time.Second):
+		t.Fatal("alert wasn't published")
+	}
+
+	assert.NoError(t, DeletePolicy("foo"))
+	policies, err := ListPolicies()
+	assert.NoError(t, err)
+	assert.Empty(t, policies)
+}
@@ -35,6 +35,13 @@ var errMapping = map[int32]codes.Code{
 	http.StatusNotImplemented:      codes.Unimplemented,
 	http.StatusInternalServerError: codes.Internal,
 	http.StatusServiceUnavailable:  codes.Unavailable,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	http.StatusGatewayTimeout:      codes.DeadlineExceeded,
+}
+
+// Code returns the grpc status code of the error
+func Code(err *errors.Error) codes.Code {
+	return microError(err)
 }
 
 func microError(err *errors.Error) codes.Code {
@@ -22,8 +22,34 @@ import (
 	"strings"
 
 	"github.com/micro/micro/v3/service/registry"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
+// wellKnownType returns the full name of a well known protobuf type, e.g. google.protobuf.Timestamp.
+// These have a special JSON representation so their fields aren't described.
+func wellKnownType(v reflect.Type) (string, bool) {
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", false
+	}
+	m, ok := reflect.New(v).Interface().(protoreflect.ProtoMessage)
+	if !ok {
+		return "", false
+	}
+	name := string(m.ProtoReflect().Descriptor().FullName())
+	return name, strings.HasPrefix(name, "google.protobuf.")
+}
+
+// typeName returns the name of the type, using the full name for well known types
+func typeName(v reflect.Type) string {
+	if name, ok := wellKnownType(v); ok {
+		return name
+	}
+	return v.Name()
+}
+
 func extractValue(v reflect.Type, d int) *registry.Value {
 	if d == 6 {
 		return nil
@@ -46,10 +72,22 @@ func extractValue(v reflect.Type, d int) *registry.Value {
 		Type: v.Name(),
 	}
 
+	if name, ok := wellKnownType(v); ok {
+		arg.Type = name
+		return arg
+	}
+
 	switch v.Kind() {
 	case reflect.Struct:
 		for i := 0; i < v.NumField(); i++ {
 			f := v.Field(i)
+
+			// the fields of a oneof are set on the message as if they were fields of it
+			if name := f.Tag.Get("protobuf_oneof"); len(name) > 0 {
+				arg.Values = append(arg.Values, oneofValues(v, name)...)
+				continue
+			}
+
 			val := extractValue(f.Type, d+1)
 			if val == nil {
 				continue
@@ -78,7 +116,7 @@ func extractValue(v reflect.Type, d int) *registry.Value {
 		if p.Kind() == reflect.Ptr {
 			p = p.Elem()
 		}
-		arg.Type = "[]" + p.Name()
+		arg.Type = "[]" + typeName(p)
 	case reflect.Map:
 		p := v.Elem()
 		if p.Kind() == reflect.Ptr {
@@ -89,13 +127,65 @@ func extractValue(v reflect.Type, d int) *registry.Value {
 			key = key.Elem()
 		}
 
-		arg.Type = fmt.Sprintf("map[%s]%s", key.Name(), p.Name())
+		arg.Type = fmt.Sprintf("map[%s]%s", key.Name(), typeName(p))
 
 	}
 
 	return arg
 }
 
+// oneofValues returns the values of the fields of the oneof of the message. The fields of messages
+// in the oneof aren't described.
+func oneofValues(v reflect.Type, name string) []*registry.Value {
+	m, ok := reflect.New(v).Interface().(protoreflect.ProtoMessage)
+	if !ok {
+		return nil
+	}
+	od := m.ProtoReflect().Descriptor().Oneofs().ByName(protoreflect.Name(name))
+	if od == nil {
+		return nil
+	}
+
+	var vals []*registry.Value
+	for i := 0; i < od.Fields().Len(); i++ {
+		fd := od.Fields().Get(i)
+		vals = append(vals, &registry.Value{Name: string(fd.Name()), Type: kindType(fd)})
+	}
+	return vals
+}
+
+// kindType returns the name of the go type of the field, as extractValue names it
+func kindType(fd protoreflect.FieldDescriptor) string {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return "bool"
+	case protoreflect.StringKind:
+		return "string"
+	case protoreflect.BytesKind:
+		return "[]uint8"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return "int32"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return "int64"
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return "uint32"
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "uint64"
+	case protoreflect.FloatKind:
+		return "float32"
+	case protoreflect.DoubleKind:
+		return "float64"
+	case protoreflect.EnumKind:
+		return string(fd.Enum().Name())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if name := string(fd.Message().FullName()); strings.HasPrefix(name, "google.protobuf.") {
+			return name
+		}
+		return string(fd.Message().Name())
+	}
+	return ""
+}
+
 func extractEndpoint(method reflect.Method) *registry.Endpoint {
 	if method.PkgPath != "" {
 		return nil
@@ -22,6 +22,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type TestHandler struct{}
@@ -55,6 +58,21 @@ func (t *TestHandler) Test2(ctx context.Context, req *TestRequest2, rsp *TestRes
 	return nil
 }
 
+type TestRequest3 struct {
+	CreatedAt  *timestamppb.Timestamp            `json:"created_at,omitempty"`
+	Timeout    *durationpb.Duration              `json:"timeout,omitempty"`
+	Times      []*timestamppb.Timestamp          `json:"times,omitempty"`
+	Timestamps map[string]*timestamppb.Timestamp `json:"timestamps,omitempty"`
+}
+
+func (t *TestHandler) Test3(ctx context.Context, req *TestRequest3, rsp *TestResponse) error {
+	return nil
+}
+
+func (t *TestHandler) Test4(ctx context.Context, req *rpb.ServerReflectionRequest, rsp *TestResponse) error {
+	return nil
+}
+
 func TestExtractEndpoint(t *testing.T) {
 	handler := &TestHandler{}
 	typ := reflect.TypeOf(handler)
@@ -138,6 +156,64 @@ func TestExtractEndpoint(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "Test3",
+			reqName: "TestRequest3",
+			reqType: "TestRequest3",
+			rspName: "TestResponse",
+			rspType: "TestResponse",
+			reqArgs: []param{
+				{
+					name:  "created_at",
+					value: "google.protobuf.Timestamp",
+				},
+				{
+					name:  "timeout",
+					value: "google.protobuf.Duration",
+				},
+				{
+					name:  "times",
+					value: "[]google.protobuf.Timestamp",
+				},
+				{
+					name:  "timestamps",
+					value: "map[string]google.protobuf.Timestamp",
+				},
+			},
+		},
+		{
+			name:    "Test4",
+			reqName: "ServerReflectionRequest",
+			reqType: "ServerReflectionRequest",
+			rspName: "TestResponse",
+			rspType: "TestResponse",
+			reqArgs: []param{
+				{
+					name:  "host",
+					value: "string",
+				},
+				{
+					name:  "file_by_filename",
+					value: "string",
+				},
+				{
+					name:  "file_containing_symbol",
+					value: "string",
+				},
+				{
+					name:  "file_containing_extension",
+					value: "ExtensionRequest",
+				},
+				{
+					name:  "all_extension_numbers_of_type",
+					value: "string",
+				},
+				{
+					name:  "list_services",
+					value: "string",
+				},
+			},
+		},
 	}
 
 	for _, tc := range tcs {
@@ -151,6 +227,7 @@ func TestExtractEndpoint(t *testing.T) {
 		assert.Equal(t, tc.reqType, e.Request.Type)
 		assert.Equal(t, tc.rspName, e.Response.Name)
 		assert.Equal(t, tc.rspType, e.Response.Type)
+		assert.Len(t, e.Request.Values, len(tc.reqArgs))
 		for i, v := range tc.reqArgs {
 			assert.Equal(t, v.name, e.Request.Values[i].Name)
 			assert.Equal(t, v.value, e.Request.Values[i].Type)
@@ -53,8 +53,11 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 )
 
@@ -90,6 +93,8 @@ type grpcServer struct {
 
 	// registry service instance
 	rsvc *registry.Service
+	// health service served to plain grpc clients
+	health *health.Server
 }
 
 func init() {
@@ -164,8 +169,41 @@ func (g *grpcServer) configure(opts ...server.Option) {
 		gopts = append(gopts, opts...)
 	}
 
+	// calls to the reflection service are run through the handler wrappers
+	if g.reflection() {
+		gopts = append(gopts, grpc.StreamInterceptor(g.reflectionInterceptor))
+	}
+
 	g.rsvc = nil
 	g.srv = grpc.NewServer(gopts...)
+
+	// serve the standard health service to clients which aren't micro
+	g.health = nil
+	if g.wireCompatible() {
+		g.health = health.NewServer()
+		healthpb.RegisterHealthServer(g.srv, g.health)
+	}
+
+	// serve the standard reflection service so tools can discover the endpoints of the handlers
+	if g.reflection() {
+		reflection.Register(&reflectionRegistrar{Server: g.srv, g: g})
+	}
+}
+
+func (g *grpcServer) wireCompatible() bool {
+	if g.opts.Context == nil {
+		return false
+	}
+	v, _ := g.opts.Context.Value(wireCompatibleKey{}).(bool)
+	return v
+}
+
+func (g *grpcServer) reflection() bool {
+	if g.opts.Context == nil {
+		return false
+	}
+	v, _ := g.opts.Context.Value(reflectionKey{}).(bool)
+	return v
 }
 
 func (g *grpcServer) maxRecvMsgSizeValue() int {
@@ -192,6 +230,9 @@ func (g *grpcServer) maxSendMsgSizeValue() int {
 
 func (g *grpcServer) getCredentials() credentials.TransportCredentials {
 	if g.opts.Context != nil {
+		if v, ok := g.opts.Context.Value(credentialsKey{}).(credentials.TransportCredentials); ok && v != nil {
+			return v
+		}
 		if v, ok := g.opts.Context.Value(tlsAuth{}).(*tls.Config); ok && v != nil {
 			return credentials.NewTLS(v)
 		}
@@ -254,42 +295,7 @@ func (g *grpcServer) handler(srv interface{}, stream grpc.ServerStream) (err err
 		return status.New(codes.InvalidArgument, err.Error()).Err()
 	}
 
-	// get grpc metadata
-	gmd, ok := metadata.FromIncomingContext(stream.Context())
-	if !ok {
-		gmd = metadata.MD{}
-	}
-
-	// copy the metadata to go-micro.metadata
-	md := meta.Metadata{}
-	for k, v := range gmd {
-		md[k] = strings.Join(v, ", ")
-	}
-
-	// timeout for server deadline
-	to := md["timeout"]
-
-	// get content type
-	ct := defaultContentType
-
-	if ctype, ok := md["x-content-type"]; ok {
-		ct = ctype
-	}
-	if ctype, ok := md["content-type"]; ok {
-		ct = ctype
-	}
-
-	delete(md, "x-content-type")
-	delete(md, "timeout")
-
-	// create new context
-	ctx := meta.NewContext(stream.Context(), md)
-
-	// get peer from context
-	if p, ok := peer.FromContext(stream.Context()); ok {
-		md["Remote"] = p.Addr.String()
-		ctx = peer.NewContext(ctx, p)
-	}
+	ctx, ct, to := requestContext(stream)
 
 	// set the timeout if we have it
 	if len(to) > 0 {
@@ -345,6 +351,9 @@ func (g *grpcServer) handler(srv interface{}, stream grpc.ServerStream) (err err
 			if _, ok := status.FromError(err); ok {
 				return err
 			}
+			if verr, ok := err.(*errors.Error); ok && g.wireCompatible() {
+				return status.New(microError(verr), verr.Detail).Err()
+			}
 			return status.Errorf(codes.Internal, err.Error())
 		}
 
@@ -374,6 +383,57 @@ func (g *grpcServer) handler(srv interface{}, stream grpc.ServerStream) (err err
 	return g.processStream(stream, service, mtype, ct, ctx)
 }
 
+// requestContext returns the context of a request with the metadata sent by the caller and its
+// peer, handlers can set the metadata of the response in it. The content type and timeout of the
+// request are returned rather than set in the metadata.
+func requestContext(stream grpc.ServerStream) (context.Context, string, string) {
+	// get grpc metadata
+	gmd, ok := metadata.FromIncomingContext(stream.Context())
+	if !ok {
+		gmd = metadata.MD{}
+	}
+
+	// copy the metadata to go-micro.metadata
+	md := meta.Metadata{}
+	for k, v := range gmd {
+		md[k] = strings.Join(v, ", ")
+	}
+
+	// timeout for server deadline
+	to := md["timeout"]
+
+	// get content type
+	ct := defaultContentType
+
+	if ctype, ok := md["x-content-type"]; ok {
+		ct = ctype
+	}
+	if ctype, ok := md["content-type"]; ok {
+		ct = ctype
+	}
+
+	delete(md, "x-content-type")
+	delete(md, "timeout")
+
+	// create new context, handlers can set the metadata of the response in it
+	ctx := meta.NewContext(stream.Context(), md)
+	ctx = server.NewResponseContext(ctx)
+
+	// get peer from context
+	if p, ok := peer.FromContext(stream.Context()); ok {
+		md["Remote"] = p.Addr.String()
+		ctx = peer.NewContext(ctx, p)
+
+		sp := &server.Peer{Remote: p.Addr.String(), Protocol: "grpc"}
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			sp.TLS = &info.State
+		}
+		ctx = server.NewPeerContext(ctx, sp)
+	}
+
+	return ctx, ct, to
+}
+
 func (g *grpcServer) processRequest(stream grpc.ServerStream, service *service, mtype *methodType, ct string, ctx context.Context) error {
 	for {
 		var argv, replyv reflect.Value
@@ -499,7 +559,16 @@ func (g *grpcServer) processRequest(stream grpc.ServerStream, service *service,
 		statusDesc := ""
 
 		// execute the handler
-		if appErr := fn(ctx, r, replyv.Interface()); appErr != nil {
+		appErr := fn(ctx, r, replyv.Interface())
+
+		// the response metadata is sent as headers, they're sent with the status if the handler errored
+		if md := responseMetadata(ctx); md != nil {
+			if err := stream.SetHeader(md); err != nil {
+				return err
+			}
+		}
+
+		if appErr != nil {
 			var errStatus *status.Status
 			switch verr := appErr.(type) {
 			case *errors.Error:
@@ -513,6 +582,10 @@ func (g *grpcServer) processRequest(stream grpc.ServerStream, service *service,
 				// micro.Error now proto based and we can attach it to grpc status
 				statusCode = microError(verr)
 				statusDesc = verr.Error()
+				// plain grpc clients expect the message to be human readable
+				if g.wireCompatible() {
+					statusDesc = verr.Detail
+				}
 
 				errStatus, err = status.New(statusCode, statusDesc).WithDetails(perr)
 				if err != nil {
@@ -579,7 +652,14 @@ func (g *grpcServer) processStream(stream grpc.ServerStream, service *service, m
 	statusCode := codes.OK
 	statusDesc := ""
 
-	if appErr := fn(ctx, r, ss); appErr != nil {
+	appErr := fn(ctx, r, ss)
+
+	// the headers have been sent by the time a stream ends so the response metadata is sent as trailers
+	if md := responseMetadata(ctx); md != nil {
+		stream.SetTrailer(md)
+	}
+
+	if appErr != nil {
 		var err error
 		var errStatus *status.Status
 		switch verr := appErr.(type) {
@@ -593,6 +673,10 @@ func (g *grpcServer) processStream(stream grpc.ServerStream, service *service, m
 			// micro.Error now proto based and we can attach it to grpc status
 			statusCode = microError(verr)
 			statusDesc = verr.Error()
+			// plain grpc clients expect the message to be human readable
+			if g.wireCompatible() {
+				statusDesc = verr.Detail
+			}
 			errStatus, err = status.New(statusCode, statusDesc).WithDetails(perr)
 			if err != nil {
 				return err
@@ -812,6 +896,12 @@ func (g *grpcServer) Register() error {
 	}
 	g.RUnlock()
 
+	// the endpoints of the router can change so the service isn't cached
+	if r, ok := config.Router.(server.EndpointRouter); ok {
+		endpoints = append(endpoints, r.Endpoints()...)
+		cacheService = false
+	}
+
 	service := &registry.Service{
 		Name:      config.Name,
 		Version:   config.Version,
@@ -1016,8 +1106,13 @@ func (g *grpcServer) Start() error {
 		}
 	}
 
-	// announce self to the world
-	if err := g.Register(); err != nil {
+	// use RegisterCheck func before register
+	if err := config.RegisterCheck(config.Context); err != nil {
+		if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+			logger.Errorf("Server %s-%s register check error: %s", config.Name, config.Id, err)
+		}
+	} else if err := g.Register(); err != nil {
+		// announce self to the world
 		if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
 			logger.Errorf("Server register error: %v", err)
 		}
@@ -1071,6 +1166,24 @@ func (g *grpcServer) Start() error {
 			select {
 			// register self on interval
 			case <-t.C:
+				g.RLock()
+				registered := g.registered
+				check := g.opts.RegisterCheck
+				g.RUnlock()
+				if rerr := check(config.Context); rerr != nil {
+					if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+						logger.Errorf("Server %s-%s register check error: %s", config.Name, config.Id, rerr)
+					}
+					// deregister self in case of error
+					if registered {
+						if err := g.Deregister(); err != nil {
+							if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+								logger.Error("Server deregister error: ", err)
+							}
+						}
+					}
+					continue
+				}
 				if err := g.Register(); err != nil {
 					if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
 						logger.Error("Server register error: ", err)
@@ -1159,6 +1272,11 @@ func (g *grpcServer) Stop() error {
 	}
 	g.RUnlock()
 
+	// let plain grpc clients know the server is going away
+	if g.health != nil {
+		g.health.Shutdown()
+	}
+
 	ch := make(chan error)
 	g.exit <- ch
 
@@ -19,14 +19,18 @@ package grpc_test
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	pberr "github.com/micro/micro/v3/proto/errors"
 	bmemory "github.com/micro/micro/v3/service/broker/memory"
 	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context/metadata"
 	gcli "github.com/micro/micro/v3/service/client/grpc"
 	"github.com/micro/micro/v3/service/errors"
 	tgrpc "github.com/micro/micro/v3/service/network/transport/grpc"
+	"github.com/micro/micro/v3/service/registry"
 	rmemory "github.com/micro/micro/v3/service/registry/memory"
 	"github.com/micro/micro/v3/service/router"
 	rtreg "github.com/micro/micro/v3/service/router/registry"
@@ -34,6 +38,8 @@ import (
 	gsrv "github.com/micro/micro/v3/service/server/grpc"
 	pb "github.com/micro/micro/v3/service/server/grpc/proto"
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
 	"google.golang.org/grpc/status"
 )
 
@@ -317,3 +323,282 @@ func TestGRPCServerWithPanicHandler(t *testing.T) {
 		t.Fatal("this must return error, as handler should be panic")
 	}
 }
+
+func TestGRPCServerWireCompatible(t *testing.T) {
+	r := rmemory.NewRegistry()
+	b := bmemory.NewBroker()
+	tr := tgrpc.NewTransport()
+	s := gsrv.NewServer(
+		server.Broker(b),
+		server.Name("foo"),
+		server.Registry(r),
+		server.Transport(tr),
+		gsrv.WireCompatible(),
+	)
+
+	h := &testServer{}
+	pb.RegisterTestHandler(s, h)
+
+	if err := s.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+
+	defer func() {
+		if err := s.Stop(); err != nil {
+			t.Fatalf("failed to stop: %v", err)
+		}
+	}()
+
+	cc, err := grpc.Dial(s.Options().Address, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("failed to dial server: %v", err)
+	}
+
+	// plain clients get the detail of micro errors as the message
+	rsp := pb.Response{}
+	err = cc.Invoke(context.Background(), "/test.Test/Call", &pb.Request{Name: "Error"}, &rsp)
+	st, ok := status.FromError(err)
+	if !ok || st.Message() != "detail" {
+		t.Fatalf("invalid error received %#+v\n", err)
+	}
+
+	// the standard health service is served
+	hrsp, err := healthpb.NewHealthClient(cc).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("error checking health: %v", err)
+	}
+	if hrsp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("expected the server to be serving, got %v", hrsp.Status)
+	}
+}
+
+// TestGRPCServerReflection tests the handlers are listed by the reflection service and calls to it
+// are run through the handler wrappers
+func TestGRPCServerReflection(t *testing.T) {
+	r := rmemory.NewRegistry()
+	b := bmemory.NewBroker()
+	tr := tgrpc.NewTransport()
+
+	var endpoint atomic.Value
+	wrapper := func(fn server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			endpoint.Store(req.Endpoint())
+			return fn(ctx, req, rsp)
+		}
+	}
+
+	s := gsrv.NewServer(
+		server.Broker(b),
+		server.Name("foo"),
+		server.Registry(r),
+		server.Transport(tr),
+		server.WrapHandler(wrapper),
+		gsrv.Reflection(),
+	)
+
+	h := &testServer{}
+	pb.RegisterTestHandler(s, h)
+
+	if err := s.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+
+	defer func() {
+		if err := s.Stop(); err != nil {
+			t.Fatalf("failed to stop: %v", err)
+		}
+	}()
+
+	cc, err := grpc.Dial(s.Options().Address, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("failed to dial server: %v", err)
+	}
+
+	stream, err := rpb.NewServerReflectionClient(cc).ServerReflectionInfo(context.Background())
+	if err != nil {
+		t.Fatalf("error calling the reflection service: %v", err)
+	}
+	req := &rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_ListServices{},
+	}
+	if err := stream.Send(req); err != nil {
+		t.Fatalf("error sending the request: %v", err)
+	}
+	rsp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("error receiving the response: %v", err)
+	}
+
+	var found bool
+	for _, svc := range rsp.GetListServicesResponse().GetService() {
+		if svc.Name == "Test" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected the Test handler to be listed, got %v", rsp.GetListServicesResponse())
+	}
+
+	// the descriptor of the handler can be resolved
+	req = &rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: "Test"},
+	}
+	if err := stream.Send(req); err != nil {
+		t.Fatalf("error sending the request: %v", err)
+	}
+	rsp, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("error receiving the response: %v", err)
+	}
+	if len(rsp.GetFileDescriptorResponse().GetFileDescriptorProto()) == 0 {
+		t.Fatalf("expected the file descriptor of the handler, got %v", rsp)
+	}
+
+	if v, _ := endpoint.Load().(string); v != "ServerReflection.ServerReflectionInfo" {
+		t.Fatalf("expected the call to be wrapped, got endpoint %q", v)
+	}
+}
+
+// TestGRPCServerPeer test the peer of a request is available to wrappers
+func TestGRPCServerPeer(t *testing.T) {
+	r := rmemory.NewRegistry()
+	b := bmemory.NewBroker()
+	tr := tgrpc.NewTransport()
+
+	peers := make(chan *server.Peer, 1)
+	s := gsrv.NewServer(
+		server.Broker(b),
+		server.Name("foo"),
+		server.Registry(r),
+		server.Transport(tr),
+		server.WrapHandler(func(hf server.HandlerFunc) server.HandlerFunc {
+			return func(ctx context.Context, req server.Request, rsp interface{}) error {
+				p, _ := server.PeerFromContext(ctx)
+				peers <- p
+				return hf(ctx, req, rsp)
+			}
+		}),
+	)
+
+	h := &testServer{}
+	pb.RegisterTestHandler(s, h)
+
+	if err := s.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	defer s.Stop()
+
+	cc, err := grpc.Dial(s.Options().Address, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("failed to dial server: %v", err)
+	}
+	defer cc.Close()
+
+	rsp := pb.Response{}
+	if err := cc.Invoke(context.Background(), "/test.Test/Call", &pb.Request{Name: "John"}, &rsp); err != nil {
+		t.Fatalf("error calling server: %v", err)
+	}
+
+	p := <-peers
+	if p == nil {
+		t.Fatal("expected the peer of the request")
+	}
+	if len(p.Remote) == 0 || p.Protocol != "grpc" {
+		t.Fatalf("unexpected peer %+v", p)
+	}
+	if p.Secure() {
+		t.Fatal("expected an insecure connection")
+	}
+}
+
+// TestGRPCServerResponseMetadata test the metadata set by handlers is returned to clients
+func TestGRPCServerResponseMetadata(t *testing.T) {
+	r := rmemory.NewRegistry()
+	b := bmemory.NewBroker()
+	tr := tgrpc.NewTransport()
+	rtr := rtreg.NewRouter(router.Registry(r))
+
+	s := gsrv.NewServer(
+		server.Broker(b),
+		server.Name("foo"),
+		server.Registry(r),
+		server.Transport(tr),
+		server.WrapHandler(func(hf server.HandlerFunc) server.HandlerFunc {
+			return func(ctx context.Context, req server.Request, rsp interface{}) error {
+				server.SetResponseMetadata(ctx, "cursor", "abc")
+				return hf(ctx, req, rsp)
+			}
+		}),
+	)
+
+	c := gcli.NewClient(
+		client.Router(rtr),
+		client.Broker(b),
+		client.Transport(tr),
+	)
+
+	h := &testServer{}
+	pb.RegisterTestHandler(s, h)
+
+	if err := s.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	defer s.Stop()
+
+	for _, name := range []string{"John", "Error"} {
+		var md metadata.Metadata
+		req := c.NewRequest("foo", "Test.Call", &pb.Request{Name: name})
+		err := c.Call(context.TODO(), req, &pb.Response{}, client.ResponseMetadata(&md))
+		if name == "Error" && err == nil {
+			t.Fatal("expected an error")
+		} else if name != "Error" && err != nil {
+			t.Fatalf("error calling server: %v", err)
+		}
+
+		if v, _ := md.Get("Cursor"); v != "abc" {
+			t.Fatalf("expected the response metadata to be returned, got %v", md)
+		}
+	}
+}
+
+func TestGRPCServerRegisterCheck(t *testing.T) {
+	r := rmemory.NewRegistry()
+
+	var ready int32
+	s := gsrv.NewServer(
+		server.Broker(bmemory.NewBroker()),
+		server.Name("foo"),
+		server.Registry(r),
+		server.RegisterInterval(time.Millisecond*10),
+		server.RegisterCheck(func(ctx context.Context) error {
+			if atomic.LoadInt32(&ready) == 0 {
+				return fmt.Errorf("not ready")
+			}
+			return nil
+		}),
+	)
+	pb.RegisterTestHandler(s, &testServer{})
+
+	if err := s.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	defer s.Stop()
+
+	// the server isn't registered until the check passes
+	if _, err := r.GetService("foo"); err != registry.ErrNotFound {
+		t.Fatalf("expected the server not to be registered, got %v", err)
+	}
+
+	atomic.StoreInt32(&ready, 1)
+	time.Sleep(time.Millisecond * 50)
+	if _, err := r.GetService("foo"); err != nil {
+		t.Fatalf("expected the server to be registered, got %v", err)
+	}
+
+	// and it's deregistered when the check fails
+	atomic.StoreInt32(&ready, 0)
+	time.Sleep(time.Millisecond * 50)
+	if _, err := r.GetService("foo"); err != registry.ErrNotFound {
+		t.Fatalf("expected the server to be deregistered, got %v", err)
+	}
+}
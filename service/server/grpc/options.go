@@ -27,6 +27,7 @@ import (
 	"github.com/micro/micro/v3/service/server"
 	"github.com/micro/micro/v3/util/codec"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/encoding"
 )
 
@@ -38,8 +39,11 @@ type maxRecvMsgSizeKey struct{}
 type maxSendMsgSizeKey struct{}
 type maxConnKey struct{}
 type tlsAuth struct{}
+type credentialsKey struct{}
 type grpcWebOptions struct{}
 type grpcWebPort struct{}
+type wireCompatibleKey struct{}
+type reflectionKey struct{}
 
 // gRPC Codec to be used to encode/decode requests for a given content type
 func Codec(contentType string, c encoding.Codec) server.Option {
@@ -62,6 +66,12 @@ func AuthTLS(t *tls.Config) server.Option {
 }
 
 // MaxConn specifies maximum number of max simultaneous connections to server
+// Credentials sets the transport credentials of the server, e.g. to authenticate the callers
+// with mTLS, taking precedence over AuthTLS
+func Credentials(c credentials.TransportCredentials) server.Option {
+	return setServerOption(credentialsKey{}, c)
+}
+
 func MaxConn(n int) server.Option {
 	return setServerOption(maxConnKey{}, n)
 }
@@ -86,6 +96,22 @@ func GRPCWebPort(addr string) server.Option {
 	return setServerOption(grpcWebPort{}, addr)
 }
 
+// WireCompatible exposes the handlers as standard gRPC services so plain gRPC clients in other
+// languages can call them without the gateway. Errors are returned with the status code matching
+// the error and its detail as the message, rather than the encoded micro error, and the standard
+// grpc.health.v1.Health service is served. Requests use the standard /package.Service/Method
+// path and deadlines set by the client using grpc-timeout apply to the handler context.
+func WireCompatible() server.Option {
+	return setServerOption(wireCompatibleKey{}, true)
+}
+
+// Reflection serves the standard grpc.reflection.v1alpha.ServerReflection service so tools such
+// as grpcurl and Postman can discover the endpoints of the handlers without their proto files.
+// Calls to the reflection service are run through the handler wrappers, e.g. to authorize them.
+func Reflection() server.Option {
+	return setServerOption(reflectionKey{}, true)
+}
+
 //
 // Deprecated: use MaxRecvMsgSize or MaxSendMsgSize instead
 // MaxMsgSize set the maximum message in bytes the server can receive and
@@ -130,6 +156,7 @@ func newOptions(opt ...server.Option) server.Options {
 		Version:          server.DefaultVersion,
 		RegisterInterval: server.DefaultRegisterInterval,
 		RegisterTTL:      server.DefaultRegisterTTL,
+		RegisterCheck:    server.DefaultRegisterCheck,
 	}
 
 	for _, o := range opt {
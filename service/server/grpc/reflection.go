@@ -0,0 +1,120 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	meta "github.com/micro/micro/v3/service/context/metadata"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// reflectionPrefix is the prefix of the methods of the reflection service
+const reflectionPrefix = "/grpc.reflection."
+
+// reflectionRegistrar registers the reflection service with the grpc server. The handlers of the
+// micro server are served by the unknown service handler so the grpc server doesn't know about
+// them, they're added to the services it lists using the proto files compiled into the binary.
+type reflectionRegistrar struct {
+	*grpc.Server
+
+	g *grpcServer
+}
+
+// GetServiceInfo returns the services registered with the grpc server and the handlers which have
+// a proto service matching their name and methods. The proto file is set as the metadata of the
+// handlers so the reflection service can return their descriptors.
+func (r *reflectionRegistrar) GetServiceInfo() map[string]grpc.ServiceInfo {
+	info := r.Server.GetServiceInfo()
+
+	r.g.rpc.mu.Lock()
+	handlers := make(map[string]*service, len(r.g.rpc.serviceMap))
+	for name, h := range r.g.rpc.serviceMap {
+		handlers[name] = h
+	}
+	r.g.rpc.mu.Unlock()
+
+	if len(handlers) == 0 {
+		return info
+	}
+
+	protoregistry.GlobalFiles.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		for i := 0; i < fd.Services().Len(); i++ {
+			sd := fd.Services().Get(i)
+			h, ok := handlers[string(sd.Name())]
+			if !ok {
+				continue
+			}
+
+			var methods []grpc.MethodInfo
+			for j := 0; j < sd.Methods().Len(); j++ {
+				md := sd.Methods().Get(j)
+				if _, ok := h.method[string(md.Name())]; !ok {
+					break
+				}
+				methods = append(methods, grpc.MethodInfo{
+					Name:           string(md.Name()),
+					IsClientStream: md.IsStreamingClient(),
+					IsServerStream: md.IsStreamingServer(),
+				})
+			}
+			// the handler doesn't implement this service, e.g. another package uses the same name
+			if len(methods) != sd.Methods().Len() {
+				continue
+			}
+
+			info[string(sd.FullName())] = grpc.ServiceInfo{Methods: methods, Metadata: fd.Path()}
+		}
+		return true
+	})
+
+	return info
+}
+
+// reflectionInterceptor runs the calls to the reflection service through the handler wrappers so
+// they're authorized in the same way as the calls to the handlers. Other calls are passed through.
+func (g *grpcServer) reflectionInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !strings.HasPrefix(info.FullMethod, reflectionPrefix) {
+		return handler(srv, ss)
+	}
+
+	ctx, ct, _ := requestContext(ss)
+	md, _ := meta.FromContext(ctx)
+
+	fn := func(ctx context.Context, req server.Request, rsp interface{}) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+	for i := len(g.opts.HdlrWrappers); i > 0; i-- {
+		fn = g.opts.HdlrWrappers[i-1](fn)
+	}
+
+	serviceName, methodName, _ := ServiceMethod(info.FullMethod)
+	req := &rpcRequest{
+		service:     g.opts.Name,
+		method:      serviceName + "." + methodName,
+		contentType: ct,
+		header:      md,
+		stream:      true,
+	}
+
+	err := fn(ctx, req, nil)
+	if verr, ok := err.(*errors.Error); ok {
+		return status.New(microError(verr), verr.Detail).Err()
+	}
+	return err
+}
+
+// contextStream is a grpc stream with the context of the micro request
+type contextStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (c *contextStream) Context() context.Context {
+	return c.ctx
+}
@@ -24,7 +24,10 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/micro/micro/v3/service/server"
 	"google.golang.org/grpc/codes"
+	gmetadata "google.golang.org/grpc/metadata"
 )
 
 // convertCode converts a standard Go error into its canonical code. Note that
@@ -116,3 +119,17 @@ func ServiceFromMethod(m string) string {
 	parts = strings.Split(parts[1], ".")
 	return strings.Join(parts[:len(parts)-1], ".")
 }
+
+// responseMetadata returns the metadata the handler set for the response as grpc metadata, nil
+// if none was set
+func responseMetadata(ctx context.Context) gmetadata.MD {
+	md := server.ResponseMetadata(ctx)
+	if len(md) == 0 {
+		return nil
+	}
+	gmd := gmetadata.MD{}
+	for k, v := range metadata.ResponseHeader(md) {
+		gmd.Set(k, v)
+	}
+	return gmd
+}
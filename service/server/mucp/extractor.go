@@ -22,8 +22,34 @@ import (
 	"strings"
 
 	"github.com/micro/micro/v3/service/registry"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
+// wellKnownType returns the full name of a well known protobuf type, e.g. google.protobuf.Timestamp.
+// These have a special JSON representation so their fields aren't described.
+func wellKnownType(v reflect.Type) (string, bool) {
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", false
+	}
+	m, ok := reflect.New(v).Interface().(protoreflect.ProtoMessage)
+	if !ok {
+		return "", false
+	}
+	name := string(m.ProtoReflect().Descriptor().FullName())
+	return name, strings.HasPrefix(name, "google.protobuf.")
+}
+
+// typeName returns the name of the type, using the full name for well known types
+func typeName(v reflect.Type) string {
+	if name, ok := wellKnownType(v); ok {
+		return name
+	}
+	return v.Name()
+}
+
 func extractValue(v reflect.Type, d int) *registry.Value {
 	if d == 3 {
 		return nil
@@ -41,6 +67,11 @@ func extractValue(v reflect.Type, d int) *registry.Value {
 		Type: v.Name(),
 	}
 
+	if name, ok := wellKnownType(v); ok {
+		arg.Type = name
+		return arg
+	}
+
 	switch v.Kind() {
 	case reflect.Struct:
 		for i := 0; i < v.NumField(); i++ {
@@ -76,7 +107,7 @@ func extractValue(v reflect.Type, d int) *registry.Value {
 		if p.Kind() == reflect.Ptr {
 			p = p.Elem()
 		}
-		arg.Type = "[]" + p.Name()
+		arg.Type = "[]" + typeName(p)
 	}
 
 	return arg
@@ -34,6 +34,7 @@ import (
 	"unicode"
 	"unicode/utf8"
 
+	"github.com/micro/micro/v3/service/context/metadata"
 	merrors "github.com/micro/micro/v3/service/errors"
 	"github.com/micro/micro/v3/service/server"
 	"github.com/micro/micro/v3/util/codec"
@@ -202,9 +203,10 @@ func prepareMethod(method reflect.Method) *methodType {
 	return &methodType{method: method, ArgType: argType, ReplyType: replyType, ContextType: contextType, stream: stream}
 }
 
-func (router *router) sendResponse(sending sync.Locker, req *request, reply interface{}, cc codec.Writer, last bool) error {
+func (router *router) sendResponse(sending sync.Locker, req *request, reply interface{}, hdr map[string]string, cc codec.Writer, last bool) error {
 	msg := new(codec.Message)
 	msg.Type = codec.Response
+	msg.Header = hdr
 	resp := router.getResponse()
 	resp.msg = msg
 
@@ -259,7 +261,8 @@ func (s *service) call(ctx context.Context, router *router, sending *sync.Mutex,
 		}
 
 		// send response
-		return router.sendResponse(sending, req, replyv.Interface(), cc, true)
+		hdr := metadata.ResponseHeader(server.ResponseMetadata(ctx))
+		return router.sendResponse(sending, req, replyv.Interface(), hdr, cc, true)
 	}
 
 	// declare a local error to see if we errored out already
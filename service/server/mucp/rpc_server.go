@@ -293,8 +293,10 @@ func (s *rpcServer) ServeConn(sock transport.Socket) {
 		hdr["Local"] = sock.Local()
 		hdr["Remote"] = sock.Remote()
 
-		// create new context with the metadata
+		// create new context with the metadata and peer, handlers can set the metadata of the response in it
 		ctx := metadata.NewContext(context.Background(), hdr)
+		ctx = server.NewPeerContext(ctx, newPeer(sock))
+		ctx = server.NewResponseContext(ctx)
 
 		// set the timeout from the header if we have it
 		if len(to) > 0 {
@@ -436,9 +438,16 @@ func (s *rpcServer) ServeConn(sock transport.Socket) {
 
 			// serve the actual request using the request router
 			if serveRequestError := r.ServeRequest(ctx, request, response); serveRequestError != nil {
-				// write an error response
+				// write an error response, it ends streams so it has the response metadata
+				errHeader := make(map[string]string, len(msg.Header))
+				for k, v := range msg.Header {
+					errHeader[k] = v
+				}
+				for k, v := range metadata.ResponseHeader(server.ResponseMetadata(ctx)) {
+					errHeader[k] = v
+				}
 				writeError := rcodec.Write(&codec.Message{
-					Header: msg.Header,
+					Header: errHeader,
 					Error:  serveRequestError.Error(),
 					Type:   codec.Error,
 				}, nil)
@@ -660,6 +669,12 @@ func (s *rpcServer) Register() error {
 		endpoints = append(endpoints, e.Endpoints()...)
 	}
 
+	// the endpoints of the router can change so the service isn't cached
+	if r, ok := config.Router.(server.EndpointRouter); ok {
+		endpoints = append(endpoints, r.Endpoints()...)
+		cacheService = false
+	}
+
 	service := &registry.Service{
 		Name:      config.Name,
 		Version:   config.Version,
@@ -18,6 +18,9 @@ package mucp
 
 import (
 	"sync"
+
+	"github.com/micro/micro/v3/service/network/transport"
+	"github.com/micro/micro/v3/service/server"
 )
 
 // waitgroup for global management of connections
@@ -46,3 +49,13 @@ func (w *waitGroup) Wait() {
 	// only wait on local group
 	w.lg.Wait()
 }
+
+// newPeer returns the peer of the socket, with the protocol and tls state if the transport knows them
+func newPeer(sock transport.Socket) *server.Peer {
+	p := &server.Peer{Remote: sock.Remote(), Local: sock.Local(), Protocol: "mucp"}
+	if cs, ok := sock.(transport.ConnState); ok {
+		p.Protocol = cs.Protocol()
+		p.TLS = cs.TLS()
+	}
+	return p
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+
+	"github.com/micro/micro/v3/service/context/metadata"
+)
+
+// Peer is the transport level information of the caller of a request, so handlers and wrappers
+// can make decisions on it, e.g. to audit or firewall calls, regardless of the transport
+type Peer struct {
+	// Remote address of the caller
+	Remote string
+	// Local address the request was received on
+	Local string
+	// Protocol negotiated with the caller, e.g. grpc or HTTP/2.0
+	Protocol string
+	// TLS state of the connection, nil if it isn't secured
+	TLS *tls.ConnectionState
+}
+
+type peerKey struct{}
+
+// NewPeerContext returns a context with the peer, set by the servers for each request
+func NewPeerContext(ctx context.Context, p *Peer) context.Context {
+	return context.WithValue(ctx, peerKey{}, p)
+}
+
+// PeerFromContext returns the peer of the request. If the server didn't set the peer it's read
+// from the Remote and Local metadata of the request.
+func PeerFromContext(ctx context.Context) (*Peer, bool) {
+	if p, ok := ctx.Value(peerKey{}).(*Peer); ok {
+		return p, true
+	}
+	remote, ok := metadata.Get(ctx, "Remote")
+	if !ok {
+		return nil, false
+	}
+	local, _ := metadata.Get(ctx, "Local")
+	return &Peer{Remote: remote, Local: local}, true
+}
+
+// Secure returns true if the connection of the peer is secured with tls
+func (p *Peer) Secure() bool {
+	return p.TLS != nil && p.TLS.HandshakeComplete
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/micro/micro/v3/service/context/metadata"
+)
+
+type responseKey struct{}
+
+type responseMetadata struct {
+	sync.Mutex
+	md metadata.Metadata
+}
+
+// NewResponseContext returns a context handlers can set the metadata of the response in, set
+// by the servers for each request
+func NewResponseContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, responseKey{}, &responseMetadata{md: metadata.Metadata{}})
+}
+
+// SetResponseMetadata sets metadata returned to the caller with the response, e.g. a pagination
+// cursor or a deprecation notice. Clients read it with the client.ResponseMetadata call option
+// and the api gateway returns it as response headers. It returns false if the server handling
+// the request doesn't support response metadata. Metadata set by streams is returned once the
+// stream ends.
+func SetResponseMetadata(ctx context.Context, key, val string) bool {
+	r, ok := ctx.Value(responseKey{}).(*responseMetadata)
+	if !ok {
+		return false
+	}
+	r.Lock()
+	r.md[strings.Title(key)] = val
+	r.Unlock()
+	return true
+}
+
+// ResponseMetadata returns the metadata set for the response, used by the servers to write it
+func ResponseMetadata(ctx context.Context) metadata.Metadata {
+	r, ok := ctx.Value(responseKey{}).(*responseMetadata)
+	if !ok {
+		return nil
+	}
+	r.Lock()
+	defer r.Unlock()
+	return metadata.Copy(r.md)
+}
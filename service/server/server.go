@@ -58,6 +58,14 @@ type Router interface {
 	ServeRequest(context.Context, Request, Response) error
 }
 
+// EndpointRouter is a router which advertises the endpoints it serves, e.g. the handlers a process
+// in another language registered with the sidecar bridge
+type EndpointRouter interface {
+	Router
+	// Endpoints served by the router
+	Endpoints() []*registry.Endpoint
+}
+
 // Message is an async message interface
 type Message interface {
 	// Topic of the message
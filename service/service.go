@@ -15,9 +15,11 @@ import (
 	"github.com/micro/micro/v3/service/client"
 	mudebug "github.com/micro/micro/v3/service/debug"
 	debug "github.com/micro/micro/v3/service/debug/handler"
+	"github.com/micro/micro/v3/service/debug/health"
 	"github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/model"
 	"github.com/micro/micro/v3/service/server"
+	"github.com/micro/micro/v3/util/ownership"
 )
 
 var (
@@ -63,6 +65,33 @@ func New(opts ...Option) *Service {
 		if a := ctx.String("service_address"); len(a) > 0 {
 			opts = append(opts, Address(a))
 		}
+		if ctx.IsSet("service_warmup") {
+			opts = append(opts, Warmup(ctx.StringSlice("service_warmup")...))
+		}
+		for _, d := range ctx.StringSlice("service_dependency") {
+			name, c, err := health.ParseCriticality(d)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, Dependency(name, c))
+		}
+		if t := ctx.String("service_team"); len(t) > 0 {
+			opts = append(opts, Owner(ownership.Owner{
+				Team:    t,
+				Oncall:  ctx.String("service_oncall"),
+				Slack:   ctx.String("service_slack_channel"),
+				Runbook: ctx.String("service_runbook"),
+			}))
+		}
+		if ctx.Bool("service_mtls") {
+			opts = append(opts, MTLS(true))
+		}
+		if ctx.Bool("service_mtls_plaintext") {
+			opts = append(opts, MTLSPlaintext(true))
+		}
+		if j := ctx.String("service_job"); len(j) > 0 {
+			opts = append(opts, RunJob(j))
+		}
 		return nil
 	}
 
@@ -169,6 +198,16 @@ func (s *Service) Run() error {
 		return errMissingName
 	}
 
+	// the service was started to run a job rather than serve requests
+	if len(s.opts.RunJob) > 0 {
+		return s.runJob()
+	}
+
+	// apply the middleware policy of the namespace before any requests are handled
+	if err := s.applyPolicy(); err != nil {
+		return err
+	}
+
 	// register the debug handler
 	s.Server().Handle(
 		s.Server().NewHandler(
@@ -191,14 +230,50 @@ func (s *Service) Run() error {
 		defer mudebug.DefaultProfiler.Stop()
 	}
 
+	// start serving the debug handler before the service initialises, so its health can be
+	// checked, but don't register the service until it's ready to handle requests
+	gate := s.gate()
+
+	// the service isn't ready while its critical dependencies are unhealthy
+	s.dependencies()
+
+	// register the owner in the metadata before the service is registered
+	if s.opts.Owner != nil {
+		if err := s.registerOwner(); err != nil {
+			return err
+		}
+	}
+
 	if logger.V(logger.InfoLevel, logger.DefaultLogger) {
 		logger.Infof("Starting [service] %s", s.Name())
 	}
 
+	// the certificate is issued before starting as the server can't serve TLS without it
+	if s.opts.MTLS {
+		m, err := s.mtls()
+		if err != nil {
+			return err
+		}
+		defer m.Stop()
+	}
+
 	if err := s.Start(); err != nil {
 		return err
 	}
 
+	// warmup before registering so the first requests routed to the service are fast
+	if s.opts.Warmup != nil {
+		s.warmup()
+	}
+
+	// prime the caches after the warmup as priming calls the dependencies
+	if len(s.opts.Prime) > 0 {
+		s.prime()
+	}
+
+	done := make(chan struct{})
+	go s.register(gate, done)
+
 	ch := make(chan os.Signal, 1)
 	if s.opts.Signal {
 		signal.Notify(ch, syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGKILL)
@@ -206,6 +281,7 @@ func (s *Service) Run() error {
 
 	// wait on kill signal
 	<-ch
+	close(done)
 	return s.Stop()
 }
 
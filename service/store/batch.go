@@ -0,0 +1,125 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// MaxBatchSize is the most records which can be written, or keys read, in a batch
+	MaxBatchSize = 1000
+	// ErrBatchTooLarge is returned when a batch exceeds MaxBatchSize
+	ErrBatchTooLarge = errors.New("batch too large")
+)
+
+// Batcher is implemented by stores which natively batch writes and reads of many keys. Batches
+// are performed a record at a time with stores which don't implement it.
+type Batcher interface {
+	// BatchWrite writes the records. If only some of the records are written a *BatchError is
+	// returned with the reason each of the others failed, any other error means none were.
+	BatchWrite(recs []*Record, opts ...WriteOption) error
+	// ReadMany reads the records with the keys, keys which don't exist are omitted from the
+	// result. If some of the keys fail to be read the records which were read are returned
+	// with a *BatchError.
+	ReadMany(keys []string, opts ...ReadOption) ([]*Record, error)
+}
+
+// BatchError reports the records of a batch which failed
+type BatchError struct {
+	// Errors keyed by the key of the record which failed
+	Errors map[string]error
+}
+
+func (b *BatchError) Error() string {
+	keys := make([]string, 0, len(b.Errors))
+	for k := range b.Errors {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	msgs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", k, b.Errors[k]))
+	}
+	return fmt.Sprintf("%d records in the batch failed: %s", len(keys), strings.Join(msgs, ", "))
+}
+
+// Batch returns a batcher for the store which enforces MaxBatchSize, using the store's native
+// batching if it has any
+func Batch(s Store) Batcher {
+	return &batcher{s}
+}
+
+type batcher struct {
+	s Store
+}
+
+func (b *batcher) BatchWrite(recs []*Record, opts ...WriteOption) error {
+	if len(recs) > MaxBatchSize {
+		return ErrBatchTooLarge
+	}
+	if len(recs) == 0 {
+		return nil
+	}
+	if n, ok := b.s.(Batcher); ok {
+		return n.BatchWrite(recs, opts...)
+	}
+
+	errs := map[string]error{}
+	for _, r := range recs {
+		if err := b.s.Write(r, opts...); err != nil {
+			errs[r.Key] = err
+		}
+	}
+	if len(errs) > 0 {
+		return &BatchError{Errors: errs}
+	}
+	return nil
+}
+
+func (b *batcher) ReadMany(keys []string, opts ...ReadOption) ([]*Record, error) {
+	if len(keys) > MaxBatchSize {
+		return nil, ErrBatchTooLarge
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	if n, ok := b.s.(Batcher); ok {
+		return n.ReadMany(keys, opts...)
+	}
+
+	// only single keys are read, prefix and suffix reads aren't supported
+	opts = append(opts, func(o *ReadOptions) {
+		o.Prefix = false
+		o.Suffix = false
+	})
+
+	var recs []*Record
+	errs := map[string]error{}
+	for _, k := range keys {
+		r, err := b.s.Read(k, opts...)
+		if err == ErrNotFound {
+			continue
+		} else if err != nil {
+			errs[k] = err
+			continue
+		}
+		recs = append(recs, r...)
+	}
+	if len(errs) > 0 {
+		return recs, &BatchError{Errors: errs}
+	}
+	return recs, nil
+}
+
+// BatchWrite writes the records to the default store in a batch
+func BatchWrite(recs []*Record, opts ...WriteOption) error {
+	return Batch(DefaultStore).BatchWrite(recs, opts...)
+}
+
+// ReadMany reads the records with the keys from the default store in a batch
+func ReadMany(keys []string, opts ...ReadOption) ([]*Record, error) {
+	return Batch(DefaultStore).ReadMany(keys, opts...)
+}
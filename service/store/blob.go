@@ -17,11 +17,14 @@ package store
 import (
 	"errors"
 	"io"
+	"time"
 )
 
 var (
 	// ErrMissingKey is returned when no key is passed to blob store Read / Write
 	ErrMissingKey = errors.New("missing key")
+	// ErrSigningNotSupported is returned by SignedURL when the blob store can't sign urls
+	ErrSigningNotSupported = errors.New("signed urls not supported")
 )
 
 // BlobStore is an interface for reading / writing blobs
@@ -33,6 +36,22 @@ type BlobStore interface {
 	List(opts ...BlobListOption) ([]string, error)
 }
 
+// BlobSigner is implemented by blob stores which can sign urls to blobs
+type BlobSigner interface {
+	// SignedURL returns a url the blob can be downloaded (GET) or uploaded (PUT) with until the
+	// ttl has passed, without any other credentials
+	SignedURL(key string, ttl time.Duration, method string, opts ...BlobOption) (string, error)
+}
+
+// SignedURL returns a url to a blob in the default blob store, served by the api gateway
+func SignedURL(key string, ttl time.Duration, method string, opts ...BlobOption) (string, error) {
+	s, ok := DefaultBlobStore.(BlobSigner)
+	if !ok {
+		return "", ErrSigningNotSupported
+	}
+	return s.SignedURL(key, ttl, method, opts...)
+}
+
 // BlobOptions contains options to use when interacting with the store
 type BlobOptions struct {
 	// Namespace to  from
@@ -15,6 +15,8 @@
 package cache
 
 import (
+	"sync"
+
 	"github.com/micro/micro/v3/service/store"
 	"github.com/micro/micro/v3/service/store/memory"
 )
@@ -26,13 +28,19 @@ type cache struct {
 	m       store.Store // the memory store
 	b       store.Store // the backing store, could be file, cockroach etc
 	options store.Options
+
+	// etags of the cached records in the backing store, which the memory store doesn't know.
+	// Records without one are read from the backing store.
+	sync.RWMutex
+	etags map[string]string
 }
 
 // NewStore returns a new cache store
 func NewStore(store store.Store, opts ...store.Option) store.Store {
 	cf := &cache{
-		m: memory.NewStore(opts...),
-		b: store,
+		m:     memory.NewStore(opts...),
+		b:     store,
+		etags: map[string]string{},
 	}
 	return cf
 
@@ -61,21 +69,73 @@ func (c *cache) Options() store.Options {
 	return c.options
 }
 
+func (c *cache) etagKey(database, table, key string) string {
+	if len(database) == 0 {
+		database = c.m.Options().Database
+	}
+	if len(table) == 0 {
+		table = c.m.Options().Table
+	}
+	return database + "/" + table + "/" + key
+}
+
+// cached sets the backing store etags of the records read from memory, returning false if any
+// of them aren't known
+func (c *cache) cached(database, table string, recs []*store.Record) bool {
+	c.RLock()
+	defer c.RUnlock()
+	for _, r := range recs {
+		etag, ok := c.etags[c.etagKey(database, table, r.Key)]
+		if !ok {
+			return false
+		}
+		r.Etag = etag
+	}
+	return true
+}
+
+// fill the cache with the records read from the backing store
+func (c *cache) fill(database, table string, recs []*store.Record) error {
+	for _, r := range recs {
+		if err := c.m.Write(r, store.WriteTo(database, table)); err != nil {
+			return err
+		}
+		c.Lock()
+		c.etags[c.etagKey(database, table, r.Key)] = r.Etag
+		c.Unlock()
+	}
+	return nil
+}
+
+// forget the etag of a record which was written, so it's read from the backing store again
+func (c *cache) forget(database, table, key string) {
+	c.Lock()
+	delete(c.etags, c.etagKey(database, table, key))
+	c.Unlock()
+}
+
 // Read takes a single key name and optional ReadOptions. It returns matching []*Record or an error.
 func (c *cache) Read(key string, opts ...store.ReadOption) ([]*store.Record, error) {
+	var options store.ReadOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	// reads from the primary bypass the cache
+	if options.Primary {
+		return c.b.Read(key, opts...)
+	}
+
 	recs, err := c.m.Read(key, opts...)
 	if err != nil && err != store.ErrNotFound {
 		return nil, err
 	}
-	if len(recs) > 0 {
+	if len(recs) > 0 && c.cached(options.Database, options.Table, recs) {
 		return recs, nil
 	}
 	recs, err = c.b.Read(key, opts...)
 	if err == nil {
-		for _, rec := range recs {
-			if err := c.m.Write(rec); err != nil {
-				return nil, err
-			}
+		if err := c.fill(options.Database, options.Table, recs); err != nil {
+			return nil, err
 		}
 	}
 	return recs, err
@@ -85,16 +145,94 @@ func (c *cache) Read(key string, opts ...store.ReadOption) ([]*store.Record, err
 // If the write succeeds in writing to memory but fails to write through to file, you'll receive an error
 // but the value may still reside in memory so appropriate action should be taken.
 func (c *cache) Write(r *store.Record, opts ...store.WriteOption) error {
+	var options store.WriteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	defer c.forget(options.Database, options.Table, r.Key)
+
+	// conditional writes are checked against the backing store since the cache may be stale
+	if options.IfMatch {
+		if err := c.b.Write(r, opts...); err != nil {
+			return err
+		}
+		return c.m.Write(r, store.WriteTo(options.Database, options.Table))
+	}
+
 	if err := c.m.Write(r, opts...); err != nil {
 		return err
 	}
 	return c.b.Write(r, opts...)
 }
 
+// BatchWrite writes the records to memory and then writes them through to the backing store in
+// a batch
+func (c *cache) BatchWrite(recs []*store.Record, opts ...store.WriteOption) error {
+	var options store.WriteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	for _, r := range recs {
+		defer c.forget(options.Database, options.Table, r.Key)
+	}
+	if err := store.Batch(c.m).BatchWrite(recs, opts...); err != nil {
+		return err
+	}
+	return store.Batch(c.b).BatchWrite(recs, opts...)
+}
+
+// ReadMany reads the keys from memory, reading the keys which aren't cached from the backing
+// store in a batch
+func (c *cache) ReadMany(keys []string, opts ...store.ReadOption) ([]*store.Record, error) {
+	var options store.ReadOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.Primary {
+		return store.Batch(c.b).ReadMany(keys, opts...)
+	}
+
+	recs, err := store.Batch(c.m).ReadMany(keys, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	cached := make(map[string]bool, len(recs))
+	var hits []*store.Record
+	for _, r := range recs {
+		if c.cached(options.Database, options.Table, []*store.Record{r}) {
+			cached[r.Key] = true
+			hits = append(hits, r)
+		}
+	}
+	recs = hits
+	var missing []string
+	for _, k := range keys {
+		if !cached[k] {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) == 0 {
+		return recs, nil
+	}
+
+	read, err := store.Batch(c.b).ReadMany(missing, opts...)
+	if err := c.fill(options.Database, options.Table, read); err != nil {
+		return nil, err
+	}
+	return append(recs, read...), err
+}
+
 // Delete removes the record with the corresponding key from the store.
 // If the delete succeeds in writing to memory but fails to write through to file, you'll receive an error
 // but the value may still reside in memory so appropriate action should be taken.
 func (c *cache) Delete(key string, opts ...store.DeleteOption) error {
+	var options store.DeleteOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	defer c.forget(options.Database, options.Table, key)
+
 	if err := c.m.Delete(key, opts...); err != nil {
 		return err
 	}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"time"
 
 	pb "github.com/micro/micro/v3/proto/store"
 	"github.com/micro/micro/v3/service/client"
@@ -189,3 +190,37 @@ func (b *blob) List(opts ...store.BlobListOption) ([]string, error) {
 
 	return rsp.Keys, nil
 }
+
+func (b *blob) SignedURL(key string, ttl time.Duration, method string, opts ...store.BlobOption) (string, error) {
+	// validate the key
+	if len(key) == 0 {
+		return "", store.ErrMissingKey
+	}
+
+	// parse the options
+	var options store.BlobOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	// execute the rpc
+	rsp, err := b.cli().Sign(context.TODO(), &pb.BlobSignRequest{
+		Key: key,
+		Options: &pb.BlobOptions{
+			Namespace: options.Namespace,
+		},
+		Method: method,
+		Ttl:    int64(ttl.Seconds()),
+	}, client.WithAuthToken())
+
+	// handle the error
+	if verr := errors.FromError(err); verr != nil && verr.Code == http.StatusNotImplemented {
+		return "", store.ErrSigningNotSupported
+	} else if verr != nil {
+		return "", verr
+	} else if err != nil {
+		return "", err
+	}
+
+	return rsp.Url, nil
+}
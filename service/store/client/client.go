@@ -2,6 +2,7 @@ package client
 
 import (
 	goctx "context"
+	goerrors "errors"
 	"fmt"
 	"io"
 	"reflect"
@@ -13,6 +14,9 @@ import (
 	"github.com/micro/micro/v3/service/context/metadata"
 	"github.com/micro/micro/v3/service/errors"
 	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/util/auth/namespace"
+	"github.com/micro/micro/v3/util/cost"
+	"github.com/micro/micro/v3/util/storelimit"
 )
 
 type srv struct {
@@ -70,12 +74,23 @@ func (s *srv) List(opts ...store.ListOption) ([]string, error) {
 		o(&options)
 	}
 
+	// attribute the operation to the request it's performed for
+	defer cost.Record(options.Context, cost.Store, time.Now())
+
+	// cap the keys listed to the limit of the table
+	db, table := limitTable(options.Database, options.Table)
+	limits := storelimit.DefaultGuard.Limits(db, table)
+	limit, err := limits.Bound(options.Limit)
+	if err != nil {
+		return nil, storelimit.Violation(db, table, "list", err)
+	}
+
 	listOpts := &pb.ListOptions{
 		Database: options.Database,
 		Table:    options.Table,
 		Prefix:   options.Prefix,
 		Suffix:   options.Suffix,
-		Limit:    uint64(options.Limit),
+		Limit:    uint64(limit),
 		Offset:   uint64(options.Offset),
 		Order:    string(options.Order),
 	}
@@ -89,6 +104,7 @@ func (s *srv) List(opts ...store.ListOption) ([]string, error) {
 	defer stream.Close()
 
 	var keys []string
+	var size int
 
 	for {
 		rsp, err := stream.Recv()
@@ -101,9 +117,13 @@ func (s *srv) List(opts ...store.ListOption) ([]string, error) {
 
 		for _, key := range rsp.Keys {
 			keys = append(keys, key)
+			size += len(key)
 		}
 	}
 
+	if err := limits.Check(len(keys), size); err != nil {
+		return nil, storelimit.Violation(db, table, "list", err)
+	}
 	return keys, nil
 }
 
@@ -118,14 +138,31 @@ func (s *srv) Read(key string, opts ...store.ReadOption) ([]*store.Record, error
 		o(&options)
 	}
 
+	// attribute the operation to the request it's performed for
+	defer cost.Record(options.Context, cost.Store, time.Now())
+
+	// cap the records read by prefix or suffix to the limit of the table, reads of a key return
+	// a single record
+	scan := options.Prefix || options.Suffix
+	db, table := limitTable(options.Database, options.Table)
+	limits := storelimit.DefaultGuard.Limits(db, table)
+	limit := options.Limit
+	if scan {
+		var err error
+		if limit, err = limits.Bound(options.Limit); err != nil {
+			return nil, storelimit.Violation(db, table, "read", err)
+		}
+	}
+
 	readOpts := &pb.ReadOptions{
 		Database: options.Database,
 		Table:    options.Table,
 		Prefix:   options.Prefix,
 		Suffix:   options.Suffix,
-		Limit:    uint64(options.Limit),
+		Limit:    uint64(limit),
 		Offset:   uint64(options.Offset),
 		Order:    string(options.Order),
+		Primary:  options.Primary,
 	}
 
 	rsp, err := s.Client.Read(s.Context(), &pb.ReadRequest{
@@ -138,6 +175,16 @@ func (s *srv) Read(key string, opts ...store.ReadOption) ([]*store.Record, error
 		return nil, err
 	}
 
+	if scan {
+		var size int
+		for _, val := range rsp.Records {
+			size += len(val.Key) + len(val.Value)
+		}
+		if err := limits.Check(len(rsp.Records), size); err != nil {
+			return nil, storelimit.Violation(db, table, "read", err)
+		}
+	}
+
 	records := make([]*store.Record, 0, len(rsp.Records))
 
 	for _, val := range rsp.Records {
@@ -156,6 +203,7 @@ func (s *srv) Read(key string, opts ...store.ReadOption) ([]*store.Record, error
 			Value:    val.Value,
 			Expiry:   time.Duration(val.Expiry) * time.Second,
 			Metadata: metadata,
+			Etag:     val.Etag,
 		})
 	}
 
@@ -173,9 +221,14 @@ func (s *srv) Write(record *store.Record, opts ...store.WriteOption) error {
 		o(&options)
 	}
 
+	// attribute the operation to the request it's performed for
+	defer cost.Record(options.Context, cost.Store, time.Now())
+
 	writeOpts := &pb.WriteOptions{
 		Database: options.Database,
 		Table:    options.Table,
+		IfMatch:  options.IfMatch,
+		Etag:     options.Etag,
 	}
 
 	metadata := make(map[string]*pb.Field)
@@ -197,11 +250,125 @@ func (s *srv) Write(record *store.Record, opts ...store.WriteOption) error {
 		Options: writeOpts}, client.WithAddress(s.Nodes...), client.WithAuthToken())
 	if err != nil && errors.Equal(err, errors.NotFound("", "")) {
 		return store.ErrNotFound
+	} else if err != nil && errors.Equal(err, errors.Conflict("", "")) {
+		return store.ErrConflict
 	}
 
 	return err
 }
 
+// BatchWrite writes the records in a single request
+func (s *srv) BatchWrite(records []*store.Record, opts ...store.WriteOption) error {
+	options := store.WriteOptions{
+		Database: s.Database,
+		Table:    s.Table,
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	// attribute the operation to the request it's performed for
+	defer cost.Record(options.Context, cost.Store, time.Now())
+
+	recs := make([]*pb.Record, 0, len(records))
+	for _, record := range records {
+		metadata := make(map[string]*pb.Field)
+		for k, v := range record.Metadata {
+			metadata[k] = &pb.Field{
+				Type:  reflect.TypeOf(v).String(),
+				Value: fmt.Sprintf("%v", v),
+			}
+		}
+		recs = append(recs, &pb.Record{
+			Key:      record.Key,
+			Value:    record.Value,
+			Expiry:   int64(record.Expiry.Seconds()),
+			Metadata: metadata,
+		})
+	}
+
+	rsp, err := s.Client.BatchWrite(s.Context(), &pb.BatchWriteRequest{
+		Records: recs,
+		Options: &pb.WriteOptions{
+			Database: options.Database,
+			Table:    options.Table,
+		},
+	}, client.WithAddress(s.Nodes...), client.WithAuthToken())
+	if err != nil {
+		return err
+	}
+	return batchError(rsp.Errors)
+}
+
+// ReadMany reads the keys in a single request
+func (s *srv) ReadMany(keys []string, opts ...store.ReadOption) ([]*store.Record, error) {
+	options := store.ReadOptions{
+		Database: s.Database,
+		Table:    s.Table,
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	// attribute the operation to the request it's performed for
+	defer cost.Record(options.Context, cost.Store, time.Now())
+
+	rsp, err := s.Client.ReadMany(s.Context(), &pb.ReadManyRequest{
+		Keys: keys,
+		Options: &pb.ReadOptions{
+			Database: options.Database,
+			Table:    options.Table,
+			Primary:  options.Primary,
+		},
+	}, client.WithAddress(s.Nodes...), client.WithAuthToken())
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*store.Record, 0, len(rsp.Records))
+	for _, val := range rsp.Records {
+		metadata := make(map[string]interface{})
+		for k, v := range val.Metadata {
+			metadata[k] = v
+		}
+		records = append(records, &store.Record{
+			Key:      val.Key,
+			Value:    val.Value,
+			Expiry:   time.Duration(val.Expiry) * time.Second,
+			Metadata: metadata,
+			Etag:     val.Etag,
+		})
+	}
+
+	return records, batchError(rsp.Errors)
+}
+
+// limitTable returns the database and table the limits of a query are looked up by, the store
+// service defaults them to the default namespace
+func limitTable(database, table string) (string, string) {
+	if len(database) == 0 {
+		database = namespace.DefaultNamespace
+	}
+	if len(table) == 0 {
+		table = namespace.DefaultNamespace
+	}
+	return database, table
+}
+
+// batchError returns the errors reported by the store service as a *store.BatchError
+func batchError(errs map[string]string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	berr := &store.BatchError{Errors: make(map[string]error, len(errs))}
+	for k, e := range errs {
+		berr.Errors[k] = goerrors.New(e)
+	}
+	return berr
+}
+
 // Delete a record with key
 func (s *srv) Delete(key string, opts ...store.DeleteOption) error {
 	options := store.DeleteOptions{
@@ -213,6 +380,9 @@ func (s *srv) Delete(key string, opts ...store.DeleteOption) error {
 		o(&options)
 	}
 
+	// attribute the operation to the request it's performed for
+	defer cost.Record(options.Context, cost.Store, time.Now())
+
 	deleteOpts := &pb.DeleteOptions{
 		Database: options.Database,
 		Table:    options.Table,
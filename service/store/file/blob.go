@@ -148,7 +148,17 @@ func (b *blobStore) Write(key string, blob io.Reader, opts ...store.BlobOption)
 			return err
 		}
 
-		return bucket.Put([]byte(key), value)
+		if err := bucket.Put([]byte(key), value); err != nil {
+			return err
+		}
+
+		// record when the blob was modified for the lifecycle rules
+		meta, err := tx.CreateBucketIfNotExists(metaBucket(options.Namespace))
+		if err != nil {
+			return err
+		}
+		modified, _ := time.Now().MarshalBinary()
+		return meta.Put([]byte(key), modified)
 	})
 }
 
@@ -182,10 +192,69 @@ func (b *blobStore) Delete(key string, opts ...store.BlobOption) error {
 			return nil
 		}
 
+		if meta := tx.Bucket(metaBucket(options.Namespace)); meta != nil {
+			if err := meta.Delete([]byte(key)); err != nil {
+				return err
+			}
+		}
 		return bucket.Delete([]byte(key))
 	})
 }
 
+// Stat describes a blob. Blobs written before modified times were recorded have a zero
+// modified time.
+func (b *blobStore) Stat(key string, opts ...store.BlobOption) (*store.BlobInfo, error) {
+	// validate the key
+	if len(key) == 0 {
+		return nil, store.ErrMissingKey
+	}
+
+	// parse the options
+	var options store.BlobOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	if len(options.Namespace) == 0 {
+		options.Namespace = "micro"
+	}
+
+	// open a connection to the database
+	db, err := b.db()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	info := &store.BlobInfo{Key: key}
+	err = db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(options.Namespace))
+		if bucket == nil {
+			return store.ErrNotFound
+		}
+		res := bucket.Get([]byte(key))
+		if res == nil {
+			return store.ErrNotFound
+		}
+		info.Size = int64(len(res))
+
+		if meta := tx.Bucket(metaBucket(options.Namespace)); meta != nil {
+			if v := meta.Get([]byte(key)); v != nil {
+				return info.Modified.UnmarshalBinary(v)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// metaBucket is the bucket the metadata of the blobs in a namespace is kept in
+func metaBucket(namespace string) []byte {
+	return []byte("__meta__/" + namespace)
+}
+
 func (b *blobStore) List(opts ...store.BlobListOption) ([]string, error) {
 	var options store.BlobListOptions
 	for _, o := range opts {
@@ -210,11 +279,7 @@ func (b *blobStore) List(opts ...store.BlobListOption) ([]string, error) {
 			return store.ErrNotFound
 		}
 		c := bucket.Cursor()
-		for {
-			k, _ := c.Next()
-			if k == nil {
-				break
-			}
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
 			kcopy := make([]byte, len(k))
 			copy(kcopy, k)
 			kstring := string(kcopy)
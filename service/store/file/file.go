@@ -18,6 +18,7 @@ package file
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"time"
@@ -62,6 +63,8 @@ type record struct {
 	Value     []byte
 	Metadata  map[string]interface{}
 	ExpiresAt time.Time
+	// Revision is taken from the bucket sequence on every write and used as the etag
+	Revision uint64
 }
 
 func key(database, table string) string {
@@ -234,6 +237,11 @@ func (m *fileStore) get(db *bolt.DB, k string) (*store.Record, error) {
 		return nil
 	})
 
+	return m.decode(value)
+}
+
+// decode a stored record, returning store.ErrNotFound if it doesn't exist or has expired
+func (m *fileStore) decode(value []byte) (*store.Record, error) {
 	if value == nil {
 		return nil, store.ErrNotFound
 	}
@@ -260,10 +268,19 @@ func (m *fileStore) get(db *bolt.DB, k string) (*store.Record, error) {
 		newRecord.Expiry = time.Until(storedRecord.ExpiresAt)
 	}
 
+	newRecord.Etag = store.Etag(storedRecord.Revision)
+
 	return newRecord, nil
 }
 
 func (m *fileStore) set(db *bolt.DB, r *store.Record) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		return m.put(tx, r)
+	})
+}
+
+// put the record in the transaction
+func (m *fileStore) put(tx *bolt.Tx, r *store.Record) error {
 	// copy the incoming record and then
 	// convert the expiry in to a hard timestamp
 	item := &record{}
@@ -279,20 +296,25 @@ func (m *fileStore) set(db *bolt.DB, r *store.Record) error {
 		item.Metadata[k] = v
 	}
 
+	b := tx.Bucket([]byte(dataBucket))
+	if b == nil {
+		var err error
+		b, err = tx.CreateBucketIfNotExists([]byte(dataBucket))
+		if err != nil {
+			return err
+		}
+	}
+
+	rev, err := b.NextSequence()
+	if err != nil {
+		return err
+	}
+	item.Revision = rev
+
 	// marshal the data
 	data, _ := json.Marshal(item)
 
-	return db.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket([]byte(dataBucket))
-		if b == nil {
-			var err error
-			b, err = tx.CreateBucketIfNotExists([]byte(dataBucket))
-			if err != nil {
-				return err
-			}
-		}
-		return b.Put([]byte(r.Key), data)
-	})
+	return b.Put([]byte(r.Key), data)
 }
 
 func (f *fileStore) Close() error {
@@ -373,6 +395,24 @@ func (m *fileStore) Write(r *store.Record, opts ...store.WriteOption) error {
 	}
 	defer db.Close()
 
+	// check the etag and write the record in the same transaction
+	if writeOpts.IfMatch {
+		return db.Update(func(tx *bolt.Tx) error {
+			var current *store.Record
+			if b := tx.Bucket([]byte(dataBucket)); b != nil {
+				rec, err := m.decode(b.Get([]byte(r.Key)))
+				if err != nil && err != store.ErrNotFound {
+					return err
+				}
+				current = rec
+			}
+			if err := store.CheckEtag(current, writeOpts.Etag); err != nil {
+				return err
+			}
+			return m.put(tx, r)
+		})
+	}
+
 	if len(opts) > 0 {
 		// Copy the record before applying options, or the incoming record will be mutated
 		newRecord := store.Record{}
@@ -391,6 +431,70 @@ func (m *fileStore) Write(r *store.Record, opts ...store.WriteOption) error {
 	return m.set(db, r)
 }
 
+// BatchWrite writes the records in a single transaction, so either all of the records are
+// written or none are
+func (m *fileStore) BatchWrite(recs []*store.Record, opts ...store.WriteOption) error {
+	var writeOpts store.WriteOptions
+	for _, o := range opts {
+		o(&writeOpts)
+	}
+
+	db, err := m.getDB(writeOpts.Database, writeOpts.Table)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.Update(func(tx *bolt.Tx) error {
+		for _, r := range recs {
+			if err := m.put(tx, r); err != nil {
+				return fmt.Errorf("error writing %s: %v", r.Key, err)
+			}
+		}
+		return nil
+	})
+}
+
+// ReadMany reads the records in a single transaction
+func (m *fileStore) ReadMany(keys []string, opts ...store.ReadOption) ([]*store.Record, error) {
+	var readOpts store.ReadOptions
+	for _, o := range opts {
+		o(&readOpts)
+	}
+
+	db, err := m.getDB(readOpts.Database, readOpts.Table)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	var results []*store.Record
+	errs := map[string]error{}
+
+	db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(dataBucket))
+		if b == nil {
+			return nil
+		}
+		for _, k := range keys {
+			r, err := m.decode(b.Get([]byte(k)))
+			if err == store.ErrNotFound {
+				continue
+			} else if err != nil {
+				errs[k] = err
+				continue
+			}
+			results = append(results, r)
+		}
+		return nil
+	})
+
+	if len(errs) > 0 {
+		return results, &store.BatchError{Errors: errs}
+	}
+	return results, nil
+}
+
 func (m *fileStore) Options() store.Options {
 	return m.options
 }
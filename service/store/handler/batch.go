@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	pb "github.com/micro/micro/v3/proto/store"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/util/auth/namespace"
+	"github.com/micro/micro/v3/util/residency"
+)
+
+// BatchWrite writes many records to the store at once
+func (h *Store) BatchWrite(ctx context.Context, req *pb.BatchWriteRequest, rsp *pb.BatchWriteResponse) error {
+	// validate the request
+	if len(req.Records) > store.MaxBatchSize {
+		return errors.BadRequest("store.Store.BatchWrite", "batch of %d records exceeds the limit of %d", len(req.Records), store.MaxBatchSize)
+	}
+
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.WriteOptions{}
+	}
+	if len(req.Options.Database) == 0 {
+		req.Options.Database = defaultDatabase
+	}
+	if len(req.Options.Table) == 0 {
+		req.Options.Table = defaultTable
+	}
+
+	// authorize the request
+	if err := namespace.AuthorizeAdmin(ctx, req.Options.Database, "store.Store.BatchWrite"); err != nil {
+		return err
+	}
+
+	// ensure the records can be written to the region of the store
+	if err := residency.Check(req.Options.Database); err != nil {
+		return errors.Forbidden("store.Store.BatchWrite", err.Error())
+	}
+
+	// setup the store
+	if err := h.setupTable(req.Options.Database, req.Options.Table); err != nil {
+		return errors.InternalServerError("store.Store.BatchWrite", err.Error())
+	}
+
+	records := make([]*store.Record, 0, len(req.Records))
+	for _, r := range req.Records {
+		if r == nil {
+			return errors.BadRequest("store.Store.BatchWrite", "no record specified")
+		}
+		metadata := make(map[string]interface{})
+		for k, v := range r.Metadata {
+			metadata[k] = v.Value
+		}
+		records = append(records, &store.Record{
+			Key:      r.Key,
+			Value:    r.Value,
+			Expiry:   time.Duration(r.Expiry) * time.Second,
+			Metadata: metadata,
+		})
+	}
+
+	// write to the store, reporting the records which failed
+	err := store.Batch(store.DefaultStore).BatchWrite(records, store.WriteTo(req.Options.Database, req.Options.Table))
+	if berr, ok := err.(*store.BatchError); ok {
+		rsp.Errors = make(map[string]string, len(berr.Errors))
+		for k, e := range berr.Errors {
+			rsp.Errors[k] = e.Error()
+		}
+		return nil
+	} else if err != nil {
+		return errors.InternalServerError("store.Store.BatchWrite", err.Error())
+	}
+
+	return nil
+}
+
+// ReadMany reads many keys from the store at once
+func (h *Store) ReadMany(ctx context.Context, req *pb.ReadManyRequest, rsp *pb.ReadManyResponse) error {
+	// validate the request
+	if len(req.Keys) > store.MaxBatchSize {
+		return errors.BadRequest("store.Store.ReadMany", "batch of %d keys exceeds the limit of %d", len(req.Keys), store.MaxBatchSize)
+	}
+
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.ReadOptions{}
+	}
+	if len(req.Options.Database) == 0 {
+		req.Options.Database = defaultDatabase
+	}
+	if len(req.Options.Table) == 0 {
+		req.Options.Table = defaultTable
+	}
+
+	// authorize the request
+	if err := namespace.AuthorizeAdmin(ctx, req.Options.Database, "store.Store.ReadMany"); err != nil {
+		return err
+	}
+
+	// setup the store
+	if err := h.setupTable(req.Options.Database, req.Options.Table); err != nil {
+		return errors.InternalServerError("store.Store.ReadMany", err.Error())
+	}
+
+	// setup the options
+	opts := []store.ReadOption{
+		store.ReadFrom(req.Options.Database, req.Options.Table),
+	}
+	if req.Options.Primary {
+		opts = append(opts, store.ReadPrimary())
+	}
+
+	// read from the store, reporting the keys which failed
+	vals, err := store.Batch(store.DefaultStore).ReadMany(req.Keys, opts...)
+	if berr, ok := err.(*store.BatchError); ok {
+		rsp.Errors = make(map[string]string, len(berr.Errors))
+		for k, e := range berr.Errors {
+			rsp.Errors[k] = e.Error()
+		}
+	} else if err != nil {
+		return errors.InternalServerError("store.Store.ReadMany", err.Error())
+	}
+
+	// serialize the result
+	for _, val := range vals {
+		metadata := make(map[string]*pb.Field)
+		for k, v := range val.Metadata {
+			metadata[k] = &pb.Field{
+				Type:  reflect.TypeOf(v).String(),
+				Value: fmt.Sprintf("%v", v),
+			}
+		}
+		rsp.Records = append(rsp.Records, &pb.Record{
+			Key:      val.Key,
+			Value:    val.Value,
+			Expiry:   int64(val.Expiry.Seconds()),
+			Metadata: metadata,
+			Etag:     val.Etag,
+		})
+	}
+	return nil
+}
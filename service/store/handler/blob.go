@@ -4,17 +4,31 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"net/http"
+	"time"
 
 	pb "github.com/micro/micro/v3/proto/store"
 	"github.com/micro/micro/v3/service/errors"
 	"github.com/micro/micro/v3/service/store"
 	authns "github.com/micro/micro/v3/util/auth/namespace"
 	"github.com/micro/micro/v3/util/namespace"
+	"github.com/micro/micro/v3/util/residency"
+	"github.com/micro/micro/v3/util/signedurl"
 )
 
-const bufferSize = 1024
+const (
+	bufferSize = 1024
+	// maxSignedTTL is the longest a signed url can be valid for
+	maxSignedTTL = time.Hour * 24 * 7
+)
 
-type BlobStore struct{}
+type BlobStore struct {
+	// SigningKey signs urls to blobs, they're verified by the api gateway with the same key.
+	// Signed urls aren't supported without it.
+	SigningKey []byte
+	// URL of the api gateway the signed urls are served by
+	URL string
+}
 
 func (b *BlobStore) Read(ctx context.Context, req *pb.BlobReadRequest, stream pb.BlobStore_ReadStream) error {
 	// parse the options
@@ -99,6 +113,11 @@ func (b *BlobStore) Write(ctx context.Context, stream pb.BlobStore_WriteStream)
 		return errors.BadRequest("store.Blob.Write", "No blob was sent")
 	}
 
+	// ensure the blob can be written to the region of the store
+	if err := residency.Check(options.Namespace); err != nil {
+		return errors.Forbidden("store.Blob.Write", err.Error())
+	}
+
 	// execute the request
 	err := store.DefaultBlobStore.Write(key, buf, store.BlobNamespace(options.Namespace), store.BlobPublic(options.Public), store.BlobContentType(options.ContentType))
 	if err == store.ErrMissingKey {
@@ -163,3 +182,41 @@ func (b *BlobStore) List(ctx context.Context, req *pb.BlobListRequest, rsp *pb.B
 	return nil
 
 }
+
+func (b *BlobStore) Sign(ctx context.Context, req *pb.BlobSignRequest, rsp *pb.BlobSignResponse) error {
+	if len(b.SigningKey) == 0 {
+		return errors.NotImplemented("store.Blob.Sign", "Signed urls aren't enabled")
+	}
+
+	// parse the options
+	if ns := req.GetOptions().GetNamespace(); len(ns) == 0 {
+		req.Options = &pb.BlobOptions{
+			Namespace: namespace.FromContext(ctx),
+		}
+	}
+
+	// authorize the request, the url can be used to write the blob
+	if err := authns.AuthorizeAdmin(ctx, req.Options.Namespace, "store.Blob.Sign"); err != nil {
+		return err
+	}
+
+	// validate the request
+	if len(req.Key) == 0 {
+		return errors.BadRequest("store.Blob.Sign", "Missing key")
+	}
+	if req.Method != http.MethodGet && req.Method != http.MethodPut {
+		return errors.BadRequest("store.Blob.Sign", "Invalid method %q, must be GET or PUT", req.Method)
+	}
+	ttl := time.Duration(req.Ttl) * time.Second
+	if ttl <= 0 || ttl > maxSignedTTL {
+		return errors.BadRequest("store.Blob.Sign", "Invalid ttl, must be between 1 second and %v", maxSignedTTL)
+	}
+
+	rsp.Url = signedurl.Sign(b.SigningKey, b.URL, &signedurl.URL{
+		Namespace: req.Options.Namespace,
+		Key:       req.Key,
+		Method:    req.Method,
+		Expires:   time.Now().Add(ttl),
+	})
+	return nil
+}
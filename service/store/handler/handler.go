@@ -13,6 +13,7 @@ import (
 	"github.com/micro/micro/v3/service/errors"
 	"github.com/micro/micro/v3/service/store"
 	"github.com/micro/micro/v3/util/auth/namespace"
+	"github.com/micro/micro/v3/util/residency"
 )
 
 const (
@@ -145,6 +146,9 @@ func (h *Store) Read(ctx context.Context, req *pb.ReadRequest, rsp *pb.ReadRespo
 		}
 		opts = append(opts, store.ReadOrder(order))
 	}
+	if req.Options.Primary {
+		opts = append(opts, store.ReadPrimary())
+	}
 
 	// read from the database
 	vals, err := store.DefaultStore.Read(req.Key, opts...)
@@ -168,6 +172,7 @@ func (h *Store) Read(ctx context.Context, req *pb.ReadRequest, rsp *pb.ReadRespo
 			Value:    val.Value,
 			Expiry:   int64(val.Expiry.Seconds()),
 			Metadata: metadata,
+			Etag:     val.Etag,
 		})
 	}
 	return nil
@@ -196,6 +201,11 @@ func (h *Store) Write(ctx context.Context, req *pb.WriteRequest, rsp *pb.WriteRe
 		return err
 	}
 
+	// ensure the record can be written to the region of the store
+	if err := residency.Check(req.Options.Database); err != nil {
+		return errors.Forbidden("store.Store.Write", err.Error())
+	}
+
 	// setup the store
 	if err := h.setupTable(req.Options.Database, req.Options.Table); err != nil {
 		return errors.InternalServerError("store.Store.Write", err.Error())
@@ -205,6 +215,9 @@ func (h *Store) Write(ctx context.Context, req *pb.WriteRequest, rsp *pb.WriteRe
 	opts := []store.WriteOption{
 		store.WriteTo(req.Options.Database, req.Options.Table),
 	}
+	if req.Options.IfMatch {
+		opts = append(opts, store.IfMatch(req.Options.Etag))
+	}
 
 	// construct the record
 	metadata := make(map[string]interface{})
@@ -222,6 +235,8 @@ func (h *Store) Write(ctx context.Context, req *pb.WriteRequest, rsp *pb.WriteRe
 	err := store.DefaultStore.Write(record, opts...)
 	if err != nil && err == store.ErrNotFound {
 		return errors.NotFound("store.Store.Write", err.Error())
+	} else if err == store.ErrConflict {
+		return errors.Conflict("store.Store.Write", "%s has been modified", req.Record.Key)
 	} else if err != nil {
 		return errors.InternalServerError("store.Store.Write", err.Error())
 	}
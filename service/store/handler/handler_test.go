@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/micro/micro/v3/proto/store"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/micro/micro/v3/util/residency"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteResidency(t *testing.T) {
+	defer func(s store.Store) { store.DefaultStore = s }(store.DefaultStore)
+	store.DefaultStore = memory.NewStore()
+	defer func(r string) { residency.Region = r }(residency.Region)
+	residency.Region = "eu-west-1"
+
+	h := &Store{Stores: map[string]bool{}}
+	tenant := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "1", Type: "user", Issuer: "foo", Scopes: []string{"admin"}})
+	write := func(key string) error {
+		return h.Write(tenant, &pb.WriteRequest{
+			Record:  &pb.Record{Key: key, Value: []byte("bar")},
+			Options: &pb.WriteOptions{Database: "foo"},
+		}, &pb.WriteResponse{})
+	}
+
+	// tenants can write their data without access to the policies in the micro namespace
+	assert.NoError(t, write("a"))
+	assert.NoError(t, residency.Set(&residency.Policy{Namespace: "foo", Regions: []string{"eu-west-1"}}))
+	assert.NoError(t, write("b"))
+
+	// writes to a region the policy doesn't permit are rejected
+	assert.NoError(t, residency.Set(&residency.Policy{Namespace: "foo", Regions: []string{"eu-central-1"}}))
+	assert.Equal(t, int32(403), errors.FromError(write("c")).Code)
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"time"
+)
+
+// LifecycleRule manages the blobs of a namespace whose keys start with the prefix. Blobs are
+// deleted once they're older than ExpireAfter, moved to the StorageClass once they're older
+// than TransitionAfter and only the MaxVersions most recent versions of each are retained.
+// Zero values disable each part of the rule.
+type LifecycleRule struct {
+	Namespace       string
+	Prefix          string
+	ExpireAfter     time.Duration
+	TransitionAfter time.Duration
+	StorageClass    string
+	MaxVersions     int
+}
+
+// BlobInfo describes a blob
+type BlobInfo struct {
+	Key          string
+	Size         int64
+	Modified     time.Time
+	StorageClass string
+}
+
+// BlobLifecycler is implemented by blob stores which expire and transition blobs natively.
+// SetLifecycle replaces any rules previously set.
+type BlobLifecycler interface {
+	SetLifecycle(rules []*LifecycleRule) error
+}
+
+// BlobStater is implemented by blob stores which can describe a blob, which is needed to
+// enforce lifecycle rules on stores without native support
+type BlobStater interface {
+	Stat(key string, opts ...BlobOption) (*BlobInfo, error)
+}
+
+// BlobTransitioner is implemented by blob stores with storage classes
+type BlobTransitioner interface {
+	Transition(key, storageClass string, opts ...BlobOption) error
+}
+
+// BlobVersioner is implemented by blob stores which retain the previous versions of blobs
+type BlobVersioner interface {
+	// Versions returns the ids of the previous versions of the blob, newest first
+	Versions(key string, opts ...BlobOption) ([]string, error)
+	// DeleteVersion deletes a previous version of the blob
+	DeleteVersion(key, version string, opts ...BlobOption) error
+}
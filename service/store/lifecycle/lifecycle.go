@@ -0,0 +1,333 @@
+// Package lifecycle enforces the lifecycle rules of a blob store. Rules are set natively on
+// stores which support them, e.g. s3, otherwise a worker periodically lists the blobs each rule
+// applies to, expiring, transitioning and pruning the versions of them.
+package lifecycle
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
+)
+
+// Options of a worker
+type Options struct {
+	// Interval between enforcing the rules
+	Interval time.Duration
+}
+
+// Option sets an option of a worker
+type Option func(o *Options)
+
+// Interval sets the time between enforcing the rules
+func Interval(d time.Duration) Option {
+	return func(o *Options) {
+		o.Interval = d
+	}
+}
+
+// Worker enforces lifecycle rules on a blob store
+type Worker struct {
+	opts  Options
+	store store.BlobStore
+	rules []*store.LifecycleRule
+
+	// native is set once the rules have been set on the store
+	native bool
+	// warned records the unsupported parts of rules which have been logged
+	warned map[string]bool
+
+	sync.Mutex
+	exit chan bool
+}
+
+// New returns a worker which enforces the rules on the blob store
+func New(bs store.BlobStore, rules []*store.LifecycleRule, opts ...Option) *Worker {
+	options := Options{Interval: time.Hour}
+	for _, o := range opts {
+		o(&options)
+	}
+	return &Worker{
+		opts:   options,
+		store:  bs,
+		rules:  rules,
+		warned: map[string]bool{},
+	}
+}
+
+// Start the worker. The rules are set on stores with native support and enforced in the
+// background for the rest.
+func (w *Worker) Start() error {
+	w.Lock()
+	defer w.Unlock()
+
+	if w.exit != nil {
+		return nil
+	}
+
+	if l, ok := w.store.(store.BlobLifecycler); ok {
+		if err := l.SetLifecycle(w.rules); err != nil {
+			return err
+		}
+		w.native = true
+	}
+
+	// versions are always pruned by the worker
+	if w.native && !w.pruneVersions() {
+		return nil
+	}
+
+	w.exit = make(chan bool)
+	go w.run(w.exit)
+	return nil
+}
+
+// Stop the worker
+func (w *Worker) Stop() {
+	w.Lock()
+	defer w.Unlock()
+	if w.exit != nil {
+		close(w.exit)
+		w.exit = nil
+	}
+}
+
+func (w *Worker) pruneVersions() bool {
+	for _, r := range w.rules {
+		if r.MaxVersions > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Worker) run(exit chan bool) {
+	t := time.NewTicker(w.opts.Interval)
+	defer t.Stop()
+
+	for {
+		if err := w.Run(time.Now()); err != nil {
+			logger.Errorf("Error enforcing blob lifecycle rules: %v", err)
+		}
+
+		select {
+		case <-exit:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Run enforces the rules once, as of the time given. Errors with individual blobs are logged
+// and the first is returned once every rule has been enforced.
+func (w *Worker) Run(now time.Time) error {
+	var first error
+	fail := func(err error) {
+		logger.Warnf("Error enforcing blob lifecycle rules: %v", err)
+		if first == nil {
+			first = err
+		}
+	}
+
+	stater, _ := w.store.(store.BlobStater)
+	transitioner, _ := w.store.(store.BlobTransitioner)
+	versioner, _ := w.store.(store.BlobVersioner)
+
+	for _, r := range w.rules {
+		expire, transition := r.ExpireAfter > 0 && !w.native, r.TransitionAfter > 0 && !w.native
+		if (expire || transition) && stater == nil {
+			w.warn(r, "expiry", "the blob store can't describe blobs")
+			expire, transition = false, false
+		}
+		if transition && transitioner == nil {
+			w.warn(r, "transitions", "the blob store doesn't have storage classes")
+			transition = false
+		}
+		prune := r.MaxVersions > 0
+		if prune && versioner == nil {
+			w.warn(r, "versions", "the blob store doesn't retain versions")
+			prune = false
+		}
+		if !expire && !transition && !prune {
+			continue
+		}
+
+		keys, err := w.store.List(store.BlobListNamespace(r.Namespace), store.BlobListPrefix(r.Prefix))
+		if err == store.ErrNotFound {
+			continue
+		} else if err != nil {
+			fail(fmt.Errorf("error listing %s: %v", Format(r), err))
+			continue
+		}
+
+		ns := store.BlobNamespace(r.Namespace)
+		for _, key := range keys {
+			if prune {
+				if err := w.prune(versioner, r, key); err != nil {
+					fail(err)
+				}
+			}
+			if !expire && !transition {
+				continue
+			}
+
+			info, err := stater.Stat(key, ns)
+			if err == store.ErrNotFound {
+				continue
+			} else if err != nil {
+				fail(fmt.Errorf("error describing %s/%s: %v", r.Namespace, key, err))
+				continue
+			}
+			// blobs written before their modified time was recorded are left alone
+			if info.Modified.IsZero() {
+				continue
+			}
+			age := now.Sub(info.Modified)
+
+			if expire && age >= r.ExpireAfter {
+				logger.Debugf("Expiring blob %s/%s", r.Namespace, key)
+				if err := w.store.Delete(key, ns); err != nil {
+					fail(fmt.Errorf("error expiring %s/%s: %v", r.Namespace, key, err))
+				}
+				continue
+			}
+			if transition && age >= r.TransitionAfter && info.StorageClass != r.StorageClass {
+				logger.Debugf("Transitioning blob %s/%s to %s", r.Namespace, key, r.StorageClass)
+				if err := transitioner.Transition(key, r.StorageClass, ns); err != nil {
+					fail(fmt.Errorf("error transitioning %s/%s: %v", r.Namespace, key, err))
+				}
+			}
+		}
+	}
+
+	return first
+}
+
+// prune deletes the versions of the blob beyond those retained, the current version counts
+// towards the maximum
+func (w *Worker) prune(v store.BlobVersioner, r *store.LifecycleRule, key string) error {
+	ns := store.BlobNamespace(r.Namespace)
+	versions, err := v.Versions(key, ns)
+	if err != nil {
+		return fmt.Errorf("error listing versions of %s/%s: %v", r.Namespace, key, err)
+	}
+	if len(versions) < r.MaxVersions {
+		return nil
+	}
+	for _, id := range versions[r.MaxVersions-1:] {
+		if err := v.DeleteVersion(key, id, ns); err != nil {
+			return fmt.Errorf("error deleting version %s of %s/%s: %v", id, r.Namespace, key, err)
+		}
+	}
+	return nil
+}
+
+// warn logs an unsupported part of a rule, once
+func (w *Worker) warn(r *store.LifecycleRule, part, reason string) {
+	w.Lock()
+	defer w.Unlock()
+
+	key := fmt.Sprintf("%s/%s:%s", r.Namespace, r.Prefix, part)
+	if w.warned[key] {
+		return
+	}
+	w.warned[key] = true
+	logger.Warnf("Ignoring the %s of lifecycle rule %s, %s", part, Format(r), reason)
+}
+
+// Parse a rule, e.g. "micro/logs/,expire=30d,transition=7d,class=GLACIER,versions=3". The rule
+// starts with the namespace and the prefix of the keys it applies to, followed by any of:
+//
+//	expire=<age>      delete blobs older than the age
+//	transition=<age>  move blobs older than the age to the storage class
+//	class=<class>     storage class blobs are transitioned to
+//	versions=<n>      number of versions of each blob retained
+//
+// Ages are a number of days, e.g. 30d, or a duration, e.g. 12h.
+func Parse(s string) (*store.LifecycleRule, error) {
+	parts := strings.Split(s, ",")
+	if len(parts[0]) == 0 {
+		return nil, fmt.Errorf("invalid lifecycle rule %q, missing namespace", s)
+	}
+
+	r := &store.LifecycleRule{Namespace: parts[0]}
+	if idx := strings.Index(parts[0], "/"); idx >= 0 {
+		r.Namespace, r.Prefix = parts[0][:idx], parts[0][idx+1:]
+	}
+
+	for _, p := range parts[1:] {
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid lifecycle rule %q, expected key=value but got %q", s, p)
+		}
+
+		var err error
+		switch kv[0] {
+		case "expire":
+			r.ExpireAfter, err = parseAge(kv[1])
+		case "transition":
+			r.TransitionAfter, err = parseAge(kv[1])
+		case "class":
+			r.StorageClass = kv[1]
+		case "versions":
+			r.MaxVersions, err = strconv.Atoi(kv[1])
+			if err == nil && r.MaxVersions < 1 {
+				err = fmt.Errorf("must be at least 1")
+			}
+		default:
+			err = fmt.Errorf("unknown key")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid lifecycle rule %q, %s: %v", s, kv[0], err)
+		}
+	}
+
+	if r.TransitionAfter > 0 && len(r.StorageClass) == 0 {
+		return nil, fmt.Errorf("invalid lifecycle rule %q, transition requires a class", s)
+	}
+	if r.ExpireAfter == 0 && r.TransitionAfter == 0 && r.MaxVersions == 0 {
+		return nil, fmt.Errorf("invalid lifecycle rule %q, no expire, transition or versions", s)
+	}
+	return r, nil
+}
+
+func parseAge(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil || days < 1 {
+			return 0, fmt.Errorf("invalid number of days %q", s)
+		}
+		return time.Duration(days) * time.Hour * 24, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err == nil && d <= 0 {
+		err = fmt.Errorf("must be positive")
+	}
+	return d, err
+}
+
+// Format a rule in the syntax accepted by Parse
+func Format(r *store.LifecycleRule) string {
+	parts := []string{r.Namespace + "/" + r.Prefix}
+	if r.ExpireAfter > 0 {
+		parts = append(parts, "expire="+formatAge(r.ExpireAfter))
+	}
+	if r.TransitionAfter > 0 {
+		parts = append(parts, "transition="+formatAge(r.TransitionAfter), "class="+r.StorageClass)
+	}
+	if r.MaxVersions > 0 {
+		parts = append(parts, "versions="+strconv.Itoa(r.MaxVersions))
+	}
+	return strings.Join(parts, ",")
+}
+
+func formatAge(d time.Duration) string {
+	if day := time.Hour * 24; d%day == 0 {
+		return fmt.Sprintf("%dd", d/day)
+	}
+	return d.String()
+}
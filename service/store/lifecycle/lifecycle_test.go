@@ -0,0 +1,117 @@
+package lifecycle
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	r, err := Parse("micro/logs/,expire=30d,transition=12h,class=GLACIER,versions=3")
+	assert.NoError(t, err)
+	assert.Equal(t, &store.LifecycleRule{
+		Namespace:       "micro",
+		Prefix:          "logs/",
+		ExpireAfter:     time.Hour * 24 * 30,
+		TransitionAfter: time.Hour * 12,
+		StorageClass:    "GLACIER",
+		MaxVersions:     3,
+	}, r)
+	assert.Equal(t, "micro/logs/,expire=30d,transition=12h0m0s,class=GLACIER,versions=3", Format(r))
+
+	r, err = Parse("foo,expire=1d")
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", r.Namespace)
+	assert.Equal(t, "", r.Prefix)
+
+	for _, s := range []string{
+		"",
+		"micro",
+		"micro/logs,expire",
+		"micro/logs,expire=0d",
+		"micro/logs,expire=-1h",
+		"micro/logs,transition=1d",
+		"micro/logs,versions=0",
+		"micro/logs,size=1",
+	} {
+		_, err := Parse(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestExpire(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lifecycle")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	bs, err := file.NewBlobStore(file.WithDir(dir))
+	assert.NoError(t, err)
+
+	for _, key := range []string{"logs/a", "logs/b", "images/a"} {
+		assert.NoError(t, bs.Write(key, bytes.NewBufferString("hello"), store.BlobNamespace("micro")))
+	}
+
+	r, _ := Parse("micro/logs/,expire=1d,versions=2")
+	w := New(bs, []*store.LifecycleRule{r})
+
+	// nothing has expired yet, the versions aren't retained by the file store so are ignored
+	assert.NoError(t, w.Run(time.Now()))
+	keys, _ := bs.List(store.BlobListNamespace("micro"))
+	assert.Len(t, keys, 3)
+
+	// only the blobs with the prefix expire
+	assert.NoError(t, w.Run(time.Now().Add(time.Hour*25)))
+	keys, _ = bs.List(store.BlobListNamespace("micro"))
+	assert.Equal(t, []string{"images/a"}, keys)
+}
+
+// versionedStore keeps every version of the blobs written to it
+type versionedStore struct {
+	store.BlobStore
+	sync.Mutex
+	versions map[string][]string
+	deleted  []string
+}
+
+func (v *versionedStore) List(opts ...store.BlobListOption) ([]string, error) {
+	return []string{"a"}, nil
+}
+
+func (v *versionedStore) Versions(key string, opts ...store.BlobOption) ([]string, error) {
+	return v.versions[key], nil
+}
+
+func (v *versionedStore) DeleteVersion(key, version string, opts ...store.BlobOption) error {
+	v.Lock()
+	defer v.Unlock()
+	v.deleted = append(v.deleted, version)
+	return nil
+}
+
+func (v *versionedStore) SetLifecycle(rules []*store.LifecycleRule) error {
+	return nil
+}
+
+func TestVersions(t *testing.T) {
+	vs := &versionedStore{versions: map[string][]string{"a": {"v4", "v3", "v2", "v1"}}}
+
+	// the current version counts towards those retained
+	r, _ := Parse("micro,expire=1d,versions=2")
+	w := New(vs, []*store.LifecycleRule{r})
+	assert.NoError(t, w.Start())
+	defer w.Stop()
+
+	// expiry is left to the store, which supports it natively, but the worker prunes versions
+	assert.Eventually(t, func() bool {
+		vs.Lock()
+		defer vs.Unlock()
+		return assert.ObjectsAreEqual([]string{"v3", "v2", "v1"}, vs.deleted)
+	}, time.Second, time.Millisecond*10)
+}
@@ -46,6 +46,11 @@ type memoryStore struct {
 	sync.RWMutex
 	options store.Options
 
+	// writes are serialised so conditional writes can't interleave
+	writeMtx sync.Mutex
+	// revision is bumped on every write, under writeMtx, and used as the etag of records
+	revision uint64
+
 	stores map[string]*cache.Cache
 }
 
@@ -54,6 +59,7 @@ type storeRecord struct {
 	value     []byte
 	metadata  map[string]interface{}
 	expiresAt time.Time
+	revision  uint64
 }
 
 func (m *memoryStore) prefix(database, table string) string {
@@ -111,6 +117,7 @@ func (m *memoryStore) get(prefix, key string) (*store.Record, error) {
 	for k, v := range storedRecord.metadata {
 		newRecord.Metadata[k] = v
 	}
+	newRecord.Etag = store.Etag(storedRecord.revision)
 
 	return newRecord, nil
 }
@@ -122,6 +129,8 @@ func (m *memoryStore) set(prefix string, r *store.Record) {
 	i.key = r.Key
 	i.value = make([]byte, len(r.Value))
 	i.metadata = make(map[string]interface{})
+	m.revision++
+	i.revision = m.revision
 
 	// copy the the value
 	copy(i.value, r.Value)
@@ -266,6 +275,19 @@ func (m *memoryStore) Write(r *store.Record, opts ...store.WriteOption) error {
 
 	prefix := m.prefix(writeOpts.Database, writeOpts.Table)
 
+	m.writeMtx.Lock()
+	defer m.writeMtx.Unlock()
+
+	if writeOpts.IfMatch {
+		current, err := m.get(prefix, r.Key)
+		if err != nil && err != store.ErrNotFound {
+			return err
+		}
+		if err := store.CheckEtag(current, writeOpts.Etag); err != nil {
+			return err
+		}
+	}
+
 	if len(opts) > 0 {
 		// Copy the record before applying options, or the incoming record will be mutated
 		newRecord := store.Record{}
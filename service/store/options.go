@@ -79,6 +79,10 @@ type ReadOptions struct {
 	Offset uint
 	// Order of the data returned e.g asc or desc
 	Order Order
+	// Primary reads from the primary rather than a replica
+	Primary bool
+	// Context of the request the read is performed for
+	Context context.Context
 }
 
 // ReadOption sets values in ReadOptions
@@ -127,10 +131,32 @@ func ReadOffset(o uint) ReadOption {
 	}
 }
 
+// ReadPrimary reads from the primary even when the store reads from replicas, for reads which
+// must see the writes which preceded them
+func ReadPrimary() ReadOption {
+	return func(r *ReadOptions) {
+		r.Primary = true
+	}
+}
+
+// ReadContext sets the context of the request the read is performed for, so the read is
+// attributed to it
+func ReadContext(ctx context.Context) ReadOption {
+	return func(r *ReadOptions) {
+		r.Context = ctx
+	}
+}
+
 // WriteOptions configures an individual Write operation
 // If Expiry and TTL are set TTL takes precedence
 type WriteOptions struct {
 	Database, Table string
+	// IfMatch makes the write conditional on the stored record having the Etag, an empty
+	// Etag requires the record not to exist
+	IfMatch bool
+	Etag    string
+	// Context of the request the write is performed for
+	Context context.Context
 }
 
 // WriteOption sets values in WriteOptions
@@ -144,9 +170,27 @@ func WriteTo(database, table string) WriteOption {
 	}
 }
 
+// IfMatch only writes the record if the stored record has the etag, otherwise ErrConflict is
+// returned. An empty etag only writes the record if it doesn't exist.
+func IfMatch(etag string) WriteOption {
+	return func(w *WriteOptions) {
+		w.IfMatch = true
+		w.Etag = etag
+	}
+}
+
+// WriteContext sets the context of the request the write is performed for
+func WriteContext(ctx context.Context) WriteOption {
+	return func(w *WriteOptions) {
+		w.Context = ctx
+	}
+}
+
 // DeleteOptions configures an individual Delete operation
 type DeleteOptions struct {
 	Database, Table string
+	// Context of the request the delete is performed for
+	Context context.Context
 }
 
 // DeleteOption sets values in DeleteOptions
@@ -160,6 +204,13 @@ func DeleteFrom(database, table string) DeleteOption {
 	}
 }
 
+// DeleteContext sets the context of the request the delete is performed for
+func DeleteContext(ctx context.Context) DeleteOption {
+	return func(d *DeleteOptions) {
+		d.Context = ctx
+	}
+}
+
 // ListOptions configures an individual List operation
 type ListOptions struct {
 	// List from the following
@@ -174,6 +225,8 @@ type ListOptions struct {
 	Offset uint
 	// Order to list the data set
 	Order Order
+	// Context of the request the list is performed for
+	Context context.Context
 }
 
 // ListOption sets values in ListOptions
@@ -187,6 +240,13 @@ func ListFrom(database, table string) ListOption {
 	}
 }
 
+// ListContext sets the context of the request the list is performed for
+func ListContext(ctx context.Context) ListOption {
+	return func(l *ListOptions) {
+		l.Context = ctx
+	}
+}
+
 // ListOrder specifies the order to return the data
 func ListOrder(o Order) ListOption {
 	return func(l *ListOptions) {
@@ -4,7 +4,9 @@ import (
 	pb "github.com/micro/micro/v3/proto/store"
 	"github.com/micro/micro/v3/service"
 	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
 	"github.com/micro/micro/v3/service/store/handler"
+	"github.com/micro/micro/v3/service/store/lifecycle"
 	"github.com/urfave/cli/v2"
 )
 
@@ -13,6 +15,26 @@ var (
 	name = "store"
 	// address is the store address
 	address = ":8002"
+
+	// Flags specific to the store service
+	Flags = []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    "blob_lifecycle",
+			EnvVars: []string{"MICRO_BLOB_LIFECYCLE"},
+			Usage:   "Lifecycle rule of the blob store, e.g. micro/logs/,expire=30d,transition=7d,class=GLACIER,versions=3",
+		},
+		&cli.StringFlag{
+			Name:    "blob_signing_key",
+			EnvVars: []string{"MICRO_BLOB_SIGNING_KEY"},
+			Usage:   "Key signed urls to blobs are signed with, the api must be started with the same key",
+		},
+		&cli.StringFlag{
+			Name:    "blob_url",
+			EnvVars: []string{"MICRO_BLOB_URL"},
+			Usage:   "Public url of the api gateway which serves signed urls to blobs",
+			Value:   "http://localhost:8080",
+		},
+	}
 )
 
 // Run micro store
@@ -36,7 +58,28 @@ func Run(ctx *cli.Context) error {
 	})
 
 	// the blob store handler
-	pb.RegisterBlobStoreHandler(service.Server(), new(handler.BlobStore))
+	pb.RegisterBlobStoreHandler(service.Server(), &handler.BlobStore{
+		SigningKey: []byte(ctx.String("blob_signing_key")),
+		URL:        ctx.String("blob_url"),
+	})
+
+	// enforce the lifecycle rules of the blob store
+	if rules := ctx.StringSlice("blob_lifecycle"); len(rules) > 0 {
+		var parsed []*store.LifecycleRule
+		for _, r := range rules {
+			rule, err := lifecycle.Parse(r)
+			if err != nil {
+				log.Fatal(err)
+			}
+			parsed = append(parsed, rule)
+		}
+
+		w := lifecycle.New(store.DefaultBlobStore, parsed)
+		if err := w.Start(); err != nil {
+			log.Fatalf("Error starting the blob lifecycle worker: %v", err)
+		}
+		defer w.Stop()
+	}
 
 	// start the service
 	if err := service.Run(); err != nil {
@@ -19,6 +19,7 @@ package store
 import (
 	"encoding/json"
 	"errors"
+	"strconv"
 	"time"
 )
 
@@ -37,6 +38,8 @@ var (
 	DefaultBlobStore BlobStore
 	// ErrNotFound is returned when a key doesn't exist
 	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned by conditional writes when the record doesn't match the etag
+	ErrConflict = errors.New("conflict")
 )
 
 // Store is a data storage interface
@@ -69,6 +72,8 @@ type Record struct {
 	Metadata map[string]interface{} `json:"metadata"`
 	// Time to expire a record: TODO: change to timestamp
 	Expiry time.Duration `json:"expiry,omitempty"`
+	// Etag identifies the revision of the record, it's set by the store when the record is read
+	Etag string `json:"etag,omitempty"`
 }
 
 // NewRecord returns a record from key, val
@@ -80,6 +85,27 @@ func NewRecord(key string, val interface{}) *Record {
 	}
 }
 
+// Etag returns the etag of a record revision. Stores bump the revision of a record every
+// time it's written, so a record written back to an earlier value doesn't get its old etag.
+func Etag(revision uint64) string {
+	return strconv.FormatUint(revision, 10)
+}
+
+// CheckEtag returns ErrConflict if the stored record, or nil if there isn't one, doesn't have
+// the etag. It's used by stores to implement conditional writes.
+func CheckEtag(current *Record, etag string) error {
+	if current == nil {
+		if len(etag) > 0 {
+			return ErrConflict
+		}
+		return nil
+	}
+	if current.Etag != etag {
+		return ErrConflict
+	}
+	return nil
+}
+
 // Encode will marshal any type into the byte Value field
 func (r *Record) Encode(v interface{}) error {
 	b, err := json.Marshal(v)
@@ -106,6 +132,12 @@ func Write(r *Record) error {
 	return DefaultStore.Write(r)
 }
 
+// WriteIfMatch writes the record only if the stored record has the etag, returning ErrConflict
+// if it's been changed since it was read. An empty etag requires the record not to exist.
+func WriteIfMatch(r *Record, etag string, opts ...WriteOption) error {
+	return DefaultStore.Write(r, append(opts, IfMatch(etag))...)
+}
+
 // Delete removes the record with the corresponding key from the store.
 func Delete(key string) error {
 	return DefaultStore.Delete(key)
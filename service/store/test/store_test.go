@@ -446,3 +446,161 @@ func suffixPrefixExpiryTests(s store.Store, t *testing.T) {
 		}
 	}
 }
+
+// failingStore fails to write the key
+type failingStore struct {
+	store.Store
+	key string
+}
+
+func (f *failingStore) Write(r *store.Record, opts ...store.WriteOption) error {
+	if r.Key == f.key {
+		return fmt.Errorf("write failed")
+	}
+	return f.Store.Write(r, opts...)
+}
+
+func TestStoreBatch(t *testing.T) {
+	tcs := []struct {
+		name    string
+		s       store.Store
+		cleanup func(db string, s store.Store)
+	}{
+		{name: "file", s: file.NewStore(), cleanup: fileStoreCleanup},
+		{name: "memory", s: memory.NewStore(), cleanup: memoryCleanup},
+		{name: "cache", s: cache.NewStore(file.NewStore()), cleanup: fileStoreCleanup},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			defer tc.cleanup("batch", tc.s)
+
+			b := store.Batch(tc.s)
+			recs := []*store.Record{
+				{Key: "foo", Value: []byte("1")},
+				{Key: "bar", Value: []byte("2")},
+				{Key: "baz", Value: []byte("3"), Expiry: time.Hour},
+			}
+			if err := b.BatchWrite(recs, store.WriteTo("batch", "batch")); err != nil {
+				t.Fatalf("Error writing batch: %v", err)
+			}
+
+			res, err := b.ReadMany([]string{"foo", "baz", "missing"}, store.ReadFrom("batch", "batch"))
+			if err != nil {
+				t.Fatalf("Error reading batch: %v", err)
+			}
+			if len(res) != 2 {
+				t.Fatalf("Expected 2 records, got %d", len(res))
+			}
+			vals := map[string]string{}
+			for _, r := range res {
+				vals[r.Key] = string(r.Value)
+			}
+			if vals["foo"] != "1" || vals["baz"] != "3" {
+				t.Errorf("Unexpected records %v", vals)
+			}
+
+			// the records are written to the table
+			if _, err := tc.s.Read("bar", store.ReadFrom("batch", "batch")); err != nil {
+				t.Errorf("Error reading record written in batch: %v", err)
+			}
+		})
+	}
+
+	t.Run("limit", func(t *testing.T) {
+		keys := make([]string, store.MaxBatchSize+1)
+		if _, err := store.Batch(memory.NewStore()).ReadMany(keys); err != store.ErrBatchTooLarge {
+			t.Errorf("Expected ErrBatchTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		s := &failingStore{Store: memory.NewStore(), key: "bar"}
+		err := store.Batch(s).BatchWrite([]*store.Record{{Key: "foo"}, {Key: "bar"}})
+		berr, ok := err.(*store.BatchError)
+		if !ok {
+			t.Fatalf("Expected a batch error, got %v", err)
+		}
+		if len(berr.Errors) != 1 || berr.Errors["bar"] == nil {
+			t.Errorf("Expected bar to fail, got %v", berr)
+		}
+		if _, err := s.Read("foo"); err != nil {
+			t.Errorf("Expected foo to be written: %v", err)
+		}
+	})
+}
+
+func TestStoreIfMatch(t *testing.T) {
+	tcs := []struct {
+		name    string
+		s       store.Store
+		cleanup func(db string, s store.Store)
+	}{
+		{name: "file", s: file.NewStore(), cleanup: fileStoreCleanup},
+		{name: "memory", s: memory.NewStore(), cleanup: memoryCleanup},
+		{name: "cache", s: cache.NewStore(file.NewStore()), cleanup: fileStoreCleanup},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			defer tc.cleanup("etag", tc.s)
+			from, to := store.ReadFrom("etag", "etag"), store.WriteTo("etag", "etag")
+
+			// an empty etag only creates the record
+			rec := &store.Record{Key: "foo", Value: []byte("1"), Metadata: map[string]interface{}{"a": "b"}}
+			if err := tc.s.Write(rec, to, store.IfMatch("")); err != nil {
+				t.Fatalf("Error creating record: %v", err)
+			}
+			if err := tc.s.Write(rec, to, store.IfMatch("")); err != store.ErrConflict {
+				t.Errorf("Expected a conflict creating an existing record, got %v", err)
+			}
+
+			recs, err := tc.s.Read("foo", from)
+			if err != nil {
+				t.Fatalf("Error reading record: %v", err)
+			}
+			etag := recs[0].Etag
+			if len(etag) == 0 {
+				t.Fatalf("Expected the record to have an etag")
+			}
+
+			// the first of two writes with the etag wins
+			if err := tc.s.Write(&store.Record{Key: "foo", Value: []byte("2")}, to, store.IfMatch(etag)); err != nil {
+				t.Fatalf("Error writing record: %v", err)
+			}
+			if err := tc.s.Write(&store.Record{Key: "foo", Value: []byte("3")}, to, store.IfMatch(etag)); err != store.ErrConflict {
+				t.Errorf("Expected a conflict, got %v", err)
+			}
+
+			recs, err = tc.s.Read("foo", from)
+			if err != nil {
+				t.Fatalf("Error reading record: %v", err)
+			}
+			if string(recs[0].Value) != "2" || recs[0].Etag == etag {
+				t.Errorf("Expected the record to be updated once, got %s %s", recs[0].Value, recs[0].Etag)
+			}
+
+			// writing the record back to an earlier value doesn't give it the earlier etag
+			if err := tc.s.Write(&store.Record{Key: "foo", Value: []byte("1"), Metadata: map[string]interface{}{"a": "b"}}, to); err != nil {
+				t.Fatalf("Error writing record: %v", err)
+			}
+			if err := tc.s.Write(&store.Record{Key: "foo", Value: []byte("4")}, to, store.IfMatch(etag)); err != store.ErrConflict {
+				t.Errorf("Expected a conflict writing with the etag of an earlier revision, got %v", err)
+			}
+
+			// nor does deleting and recreating it
+			recs, err = tc.s.Read("foo", from)
+			if err != nil {
+				t.Fatalf("Error reading record: %v", err)
+			}
+			etag = recs[0].Etag
+			if err := tc.s.Delete("foo", store.DeleteFrom("etag", "etag")); err != nil {
+				t.Fatalf("Error deleting record: %v", err)
+			}
+			if err := tc.s.Write(&store.Record{Key: "foo", Value: []byte("1"), Metadata: map[string]interface{}{"a": "b"}}, to, store.IfMatch("")); err != nil {
+				t.Fatalf("Error recreating record: %v", err)
+			}
+			if err := tc.s.Write(&store.Record{Key: "foo", Value: []byte("4")}, to, store.IfMatch(etag)); err != store.ErrConflict {
+				t.Errorf("Expected a conflict writing with the etag of a deleted record, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/micro/micro/v3/service"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/server"
+	"github.com/micro/micro/v3/service/stub"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// Flags specific to the stub service
+	Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:    "stub",
+			Usage:   "Set the service to stub and the file declaring the stub, e.g. payments=./stubs/payments.yaml",
+			EnvVars: []string{"MICRO_STUB"},
+		},
+	}
+)
+
+// Parse a stub flag in the form name=path, the name defaults to the name of the file
+func Parse(flag string) (*stub.Stub, error) {
+	name, path := "", flag
+	if parts := strings.SplitN(flag, "=", 2); len(parts) == 2 {
+		name, path = parts[0], parts[1]
+	}
+	s, err := stub.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(name) > 0 {
+		s.Name = name
+	}
+	return s, nil
+}
+
+// Run a stub of a service
+func Run(c *cli.Context) error {
+	if len(c.String("stub")) == 0 {
+		logger.Fatal("Missing stub, e.g. --stub=payments=./stubs/payments.yaml")
+	}
+	s, err := Parse(c.String("stub"))
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	srv := service.New(
+		service.Name(s.Name),
+	)
+
+	// every request is served by the stub rather than by handlers
+	srv.Server().Init(
+		server.WithRouter(s.Router()),
+	)
+
+	logger.Infof("Stubbing %v with %d endpoints", s.Name, len(s.Endpoints))
+
+	if err := srv.Run(); err != nil {
+		logger.Fatal(err)
+	}
+	return nil
+}
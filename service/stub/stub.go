@@ -0,0 +1,247 @@
+// Package stub serves canned responses in place of a service which isn't running, so a service
+// can be developed locally without running everything it depends on. Stubs are declared in yaml
+// or json files:
+//
+//	endpoints:
+//	  Payments.Charge:
+//	  - match:
+//	      currency: xyz
+//	    error:
+//	      code: 400
+//	      detail: unsupported currency
+//	  - delay: 100ms
+//	    response:
+//	      id: "ch_{{ uuid }}"
+//	      amount: "{{ .amount }}"
+//	      status: succeeded
+//	  "*":
+//	    - response: {}
+//
+// The first rule of an endpoint whose match fields equal those of the request is used, "*"
+// matches any endpoint without rules. String values in responses are templates executed with the
+// fields of the request.
+package stub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/server"
+)
+
+// Any is the endpoint whose rules are used for endpoints without any
+const Any = "*"
+
+// Stub of a service
+type Stub struct {
+	// Name of the service
+	Name string `json:"name"`
+	// Endpoints and the rules used to respond to them
+	Endpoints map[string][]*Rule `json:"endpoints"`
+}
+
+// Rule to respond to a request with
+type Rule struct {
+	// Match are the fields the request must have for the rule to be used, e.g. id: "1"
+	Match map[string]string `json:"match,omitempty"`
+	// Response returned, string values are templates executed with the request fields
+	Response interface{} `json:"response,omitempty"`
+	// Error returned instead of the response
+	Error *Error `json:"error,omitempty"`
+	// Delay before responding, e.g. 100ms
+	Delay string `json:"delay,omitempty"`
+
+	delay time.Duration
+}
+
+// Error returned by a rule
+type Error struct {
+	Code   int32  `json:"code"`
+	Detail string `json:"detail"`
+}
+
+// Load the stub from a yaml or json file, the name defaults to the name of the file
+func Load(path string) (*Stub, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s, err := Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing stub %v: %v", path, err)
+	}
+	if len(s.Name) == 0 {
+		s.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return s, nil
+}
+
+// Parse a yaml or json stub
+func Parse(b []byte) (*Stub, error) {
+	var s Stub
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+	for ep, rules := range s.Endpoints {
+		for _, r := range rules {
+			if r == nil {
+				return nil, fmt.Errorf("%v has an empty rule", ep)
+			}
+			if len(r.Delay) > 0 {
+				d, err := time.ParseDuration(r.Delay)
+				if err != nil {
+					return nil, fmt.Errorf("%v has an invalid delay: %v", ep, err)
+				}
+				r.delay = d
+			}
+		}
+	}
+	return &s, nil
+}
+
+// Respond to a request for the endpoint, the request and response are json
+func (s *Stub) Respond(ctx context.Context, endpoint string, req []byte) ([]byte, error) {
+	fields := map[string]interface{}{}
+	if len(bytes.TrimSpace(req)) > 0 {
+		if err := json.Unmarshal(req, &fields); err != nil {
+			return nil, errors.BadRequest(s.Name, "stub requests must be json objects: %v", err)
+		}
+	}
+
+	rule := s.match(endpoint, fields)
+	if rule == nil {
+		return nil, errors.NotImplemented(s.Name, "the stub of %v has no response for %v", s.Name, endpoint)
+	}
+
+	if rule.delay > 0 {
+		select {
+		case <-time.After(rule.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if rule.Error != nil {
+		return nil, errors.New(s.Name, rule.Error.Detail, rule.Error.Code)
+	}
+
+	rsp, err := render(rule.Response, fields)
+	if err != nil {
+		return nil, errors.InternalServerError(s.Name, "error rendering the stub response: %v", err)
+	}
+	if rsp == nil {
+		rsp = map[string]interface{}{}
+	}
+	return json.Marshal(rsp)
+}
+
+// match returns the first rule of the endpoint which matches the request
+func (s *Stub) match(endpoint string, fields map[string]interface{}) *Rule {
+	rules, ok := s.Endpoints[endpoint]
+	if !ok {
+		rules = s.Endpoints[Any]
+	}
+
+	for _, r := range rules {
+		matched := true
+		for k, v := range r.Match {
+			if f, ok := fields[k]; !ok || fmt.Sprint(f) != v {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return r
+		}
+	}
+	return nil
+}
+
+var funcs = template.FuncMap{
+	"uuid": func() string { return uuid.New().String() },
+	"now":  func() string { return time.Now().Format(time.RFC3339) },
+}
+
+// render executes the string values of the response as templates
+func render(v interface{}, fields map[string]interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case string:
+		if !strings.Contains(t, "{{") {
+			return t, nil
+		}
+		tmpl, err := template.New("").Funcs(funcs).Option("missingkey=zero").Parse(t)
+		if err != nil {
+			return nil, err
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := tmpl.Execute(buf, fields); err != nil {
+			return nil, err
+		}
+		return strings.Replace(buf.String(), "<no value>", "", -1), nil
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			r, err := render(val, fields)
+			if err != nil {
+				return nil, err
+			}
+			res[k] = r
+		}
+		return res, nil
+	case []interface{}:
+		res := make([]interface{}, len(t))
+		for i, val := range t {
+			r, err := render(val, fields)
+			if err != nil {
+				return nil, err
+			}
+			res[i] = r
+		}
+		return res, nil
+	default:
+		return v, nil
+	}
+}
+
+// Router returns a server router which serves every request using the stub
+func (s *Stub) Router() server.Router {
+	return &router{s}
+}
+
+type router struct {
+	stub *Stub
+}
+
+func (r *router) ProcessMessage(ctx context.Context, msg server.Message) error {
+	// events published by the stubbed service are dropped
+	return nil
+}
+
+func (r *router) ServeRequest(ctx context.Context, req server.Request, rsp server.Response) error {
+	if req.Stream() {
+		return errors.NotImplemented(r.stub.Name, "stubs don't support streams")
+	}
+	if ct := req.ContentType(); !strings.Contains(ct, "json") {
+		return errors.BadRequest(r.stub.Name, "stubs only support json requests, not %v", ct)
+	}
+
+	body, err := req.Read()
+	if err != nil {
+		return err
+	}
+	b, err := r.stub.Respond(ctx, req.Endpoint(), body)
+	if err != nil {
+		return err
+	}
+	return rsp.Write(b)
+}
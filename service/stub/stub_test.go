@@ -0,0 +1,55 @@
+package stub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var payments = `
+name: payments
+endpoints:
+  Payments.Charge:
+  - match:
+      currency: xyz
+    error:
+      code: 400
+      detail: unsupported currency
+  - delay: 1ms
+    response:
+      id: "ch_{{ .amount }}"
+      currency: "{{ .currency }}"
+      captured: true
+      lines:
+      - "{{ .missing }}"
+  "*":
+  - response: {}
+`
+
+func TestStub(t *testing.T) {
+	s, err := Parse([]byte(payments))
+	assert.NoError(t, err)
+	assert.Equal(t, "payments", s.Name)
+
+	ctx := context.TODO()
+	rsp, err := s.Respond(ctx, "Payments.Charge", []byte(`{"amount":100,"currency":"gbp"}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":"ch_100","currency":"gbp","captured":true,"lines":[""]}`, string(rsp))
+
+	_, err = s.Respond(ctx, "Payments.Charge", []byte(`{"amount":100,"currency":"xyz"}`))
+	assert.Equal(t, int32(400), errors.FromError(err).Code)
+	assert.Equal(t, "unsupported currency", errors.FromError(err).Detail)
+
+	// endpoints without rules use the rules of any endpoint
+	rsp, err = s.Respond(ctx, "Payments.Refund", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", string(rsp))
+
+	_, err = s.Respond(ctx, "Payments.Charge", []byte(`not json`))
+	assert.Equal(t, int32(400), errors.FromError(err).Code)
+
+	_, err = Parse([]byte("endpoints:\n  Foo.Bar:\n  - delay: soon\n"))
+	assert.Error(t, err)
+}
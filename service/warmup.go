@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	pb "github.com/micro/micro/v3/proto/debug"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/registry"
+)
+
+var (
+	// DefaultWarmupTimeout is the longest the warmup can delay the start of the service by
+	DefaultWarmupTimeout = time.Second * 10
+	// maxWarmupNodes is the number of nodes of each dependency connected to during the warmup
+	maxWarmupNodes = 5
+)
+
+// warmup prefetches the auth token and a snapshot of the registry, then resolves and connects to
+// the nodes of the critical dependencies so the first requests the service handles don't pay for
+// the lookups and connection setup. Warmup is best effort, errors are logged but don't stop the
+// service from starting.
+func (s *Service) warmup() {
+	timeout := s.opts.WarmupTimeout
+	if timeout <= 0 {
+		timeout = DefaultWarmupTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	// prefetch the token if it's missing or about to expire
+	if opts := auth.DefaultAuth.Options(); len(opts.ID) > 0 && len(opts.Secret) > 0 {
+		if opts.Token == nil || opts.Token.Expiry.Before(time.Now().Add(time.Minute)) {
+			tok, err := auth.Token(auth.WithCredentials(opts.ID, opts.Secret), auth.WithExpiry(time.Minute*10))
+			if err != nil {
+				logger.Warnf("Warmup error fetching auth token: %v", err)
+			} else {
+				auth.DefaultAuth.Init(auth.ClientToken(tok))
+			}
+		}
+	}
+
+	// prefetch a snapshot of the registry, which also connects to the registry
+	if _, err := registry.DefaultRegistry.ListServices(); err != nil {
+		logger.Warnf("Warmup error listing services: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, dep := range s.opts.Warmup {
+		wg.Add(1)
+		go func(dep string) {
+			defer wg.Done()
+			warmupDependency(ctx, s.Client(), dep)
+		}(dep)
+	}
+	wg.Wait()
+
+	logger.Infof("Warmup of %v dependencies completed in %v", len(s.opts.Warmup), time.Since(start))
+}
+
+// warmupDependency resolves the nodes of the dependency and connects to them by checking their
+// health, which leaves a connection to each in the client's pool
+func warmupDependency(ctx context.Context, c client.Client, dep string) {
+	req := c.NewRequest(dep, "Debug.Health", &pb.HealthRequest{})
+	addrs, err := lookup(ctx, c, req)
+	if err != nil {
+		logger.Warnf("Warmup error looking up %v: %v", dep, err)
+		return
+	}
+	if len(addrs) > maxWarmupNodes {
+		addrs = addrs[:maxWarmupNodes]
+	}
+
+	var wg sync.WaitGroup
+	for _, addr := range addrs {
+		wg.Add(1)
+		go func(addr string) {
+			defer wg.Done()
+
+			// resolve the host, which primes the dns cache of the resolver
+			if host, _, err := net.SplitHostPort(addr); err == nil && net.ParseIP(host) == nil {
+				if _, err := net.DefaultResolver.LookupHost(ctx, host); err != nil {
+					logger.Warnf("Warmup error resolving %v: %v", host, err)
+					return
+				}
+			}
+
+			if err := c.Call(ctx, req, &pb.HealthResponse{}, client.WithAddress(addr), client.WithRetries(0)); err != nil {
+				logger.Warnf("Warmup error connecting to %v at %v: %v", dep, addr, err)
+			}
+		}(addr)
+	}
+	wg.Wait()
+}
+
+// lookup the nodes of the service the request is for the same way the client does
+func lookup(ctx context.Context, c client.Client, req client.Request) ([]string, error) {
+	copts := c.Options().CallOptions
+	if copts.Router == nil {
+		copts.Router = c.Options().Router
+	}
+	if len(c.Options().Proxy) > 0 {
+		copts.Address = []string{c.Options().Proxy}
+	}
+	return c.Options().Lookup(ctx, req, copts)
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/client/grpc"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/registry/memory"
+	"github.com/micro/micro/v3/service/router"
+	regRouter "github.com/micro/micro/v3/service/router/registry"
+)
+
+// warmupClient records the addresses called rather than calling them, blocking until the context
+// is done if block is set
+type warmupClient struct {
+	client.Client
+	block bool
+
+	sync.Mutex
+	endpoints []string
+	addrs     []string
+}
+
+func (w *warmupClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	var options client.CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	w.Lock()
+	w.endpoints = append(w.endpoints, req.Endpoint())
+	w.addrs = append(w.addrs, options.Address...)
+	w.Unlock()
+
+	if w.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func newWarmupClient(nodes int, opts ...client.Option) *warmupClient {
+	reg := memory.NewRegistry()
+	svc := &registry.Service{Name: "foo", Version: "latest"}
+	for i := 0; i < nodes; i++ {
+		svc.Nodes = append(svc.Nodes, &registry.Node{Id: fmt.Sprintf("foo-%d", i), Address: fmt.Sprintf("10.0.0.%d:8080", i)})
+	}
+	reg.Register(svc)
+
+	opts = append([]client.Option{client.Router(regRouter.NewRouter(router.Registry(reg)))}, opts...)
+	return &warmupClient{Client: grpc.NewClient(opts...)}
+}
+
+func TestWarmupDependency(t *testing.T) {
+	c := newWarmupClient(maxWarmupNodes + 2)
+	warmupDependency(context.Background(), c, "foo")
+
+	// a connection is made to each node, up to the max
+	if len(c.addrs) != maxWarmupNodes {
+		t.Fatalf("expected %v nodes to be called, got %v", maxWarmupNodes, c.addrs)
+	}
+	sort.Strings(c.addrs)
+	for i := 1; i < len(c.addrs); i++ {
+		if c.addrs[i] == c.addrs[i-1] {
+			t.Fatalf("expected each node to be called once, got %v", c.addrs)
+		}
+	}
+	for _, e := range c.endpoints {
+		if e != "Debug.Health" {
+			t.Fatalf("expected the health endpoint to be called, got %v", e)
+		}
+	}
+}
+
+func TestWarmupDependencyMissing(t *testing.T) {
+	c := newWarmupClient(1)
+	warmupDependency(context.Background(), c, "bar")
+
+	if len(c.addrs) > 0 {
+		t.Fatalf("expected no nodes to be called for a missing service, got %v", c.addrs)
+	}
+}
+
+func TestWarmupDependencyTimeout(t *testing.T) {
+	c := newWarmupClient(2)
+	c.block = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	start := time.Now()
+	warmupDependency(ctx, c, "foo")
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("expected the warmup to stop at the timeout, took %v", d)
+	}
+	if len(c.addrs) != 2 {
+		t.Fatalf("expected 2 nodes to be called, got %v", c.addrs)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	c := newWarmupClient(2)
+	addrs, err := lookup(context.Background(), c, c.NewRequest("foo", "Debug.Health", nil))
+	if err != nil {
+		t.Fatalf("unexpected error looking up service: %v", err)
+	}
+	sort.Strings(addrs)
+	if fmt.Sprint(addrs) != "[10.0.0.0:8080 10.0.0.1:8080]" {
+		t.Fatalf("expected the addresses of the nodes, got %v", addrs)
+	}
+
+	if _, err := lookup(context.Background(), c, c.NewRequest("bar", "Debug.Health", nil)); err == nil {
+		t.Fatalf("expected an error looking up a missing service")
+	}
+
+	// requests through a proxy are sent to the proxy
+	c = newWarmupClient(2, client.Proxy("10.1.0.1:8080"))
+	addrs, err = lookup(context.Background(), c, c.NewRequest("bar", "Debug.Health", nil))
+	if err != nil {
+		t.Fatalf("unexpected error looking up service: %v", err)
+	}
+	if fmt.Sprint(addrs) != "[10.1.0.1:8080]" {
+		t.Fatalf("expected the proxy address, got %v", addrs)
+	}
+}
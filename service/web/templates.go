@@ -430,6 +430,11 @@ jQuery(function($, undefined) {
 			req.open("POST", "/rpc", true);
 			req.setRequestHeader("Content-type","application/json");
 
+			var csrf = document.cookie.match(/(?:^|; )micro-csrf=([^;]*)/);
+			if (csrf) {
+				req.setRequestHeader("X-CSRF-Token", decodeURIComponent(csrf[1]));
+			}
+
 			if (headers != undefined) {
 				for (let [key, value] of Object.entries(headers)) {
 					req.setRequestHeader(key, value);
@@ -504,6 +509,19 @@ pre {padding: 20px;}
 {{end}}
 {{define "content"}}
 	<hr>
+	<h4 class="bold">Owner</h4>
+	{{with .Owner}}
+	<table class="table">
+		<tbody>
+			<tr><th class="col-sm-2" scope="row">Team</th><td>{{.Team}}</td></tr>
+			{{if .Oncall}}<tr><th class="col-sm-2" scope="row">Oncall</th><td>{{.Oncall}}</td></tr>{{end}}
+			{{if .Slack}}<tr><th class="col-sm-2" scope="row">Slack</th><td>{{.Slack}}</td></tr>{{end}}
+			{{if .Runbook}}<tr><th class="col-sm-2" scope="row">Runbook</th><td><a href="{{.Runbook}}">{{.Runbook}}</a></td></tr>{{end}}
+		</tbody>
+	</table>
+	{{else}}
+	<p>No owner registered</p>
+	{{end}}
 	<h4 class="bold">Nodes</h4>
 	{{range .Results}}
 	<h5>Version: {{.Version}}</h5>
@@ -726,6 +744,11 @@ pre {padding: 20px;}
 			req.open("POST", "/rpc", true);
 			req.setRequestHeader("Content-type","application/json");
 
+			var csrf = document.cookie.match(/(?:^|; )micro-csrf=([^;]*)/);
+			if (csrf) {
+				req.setRequestHeader("X-CSRF-Token", decodeURIComponent(csrf[1]));
+			}
+
 			if (headers != undefined) {
 				for (let [key, value] of Object.entries(headers)) {
 					req.setRequestHeader(key, value);
@@ -16,6 +16,7 @@ import (
 	"github.com/go-acme/lego/v3/providers/dns/cloudflare"
 	"github.com/gorilla/mux"
 	"github.com/micro/micro/v3/cmd"
+	pb "github.com/micro/micro/v3/proto/auth"
 	"github.com/micro/micro/v3/service"
 	server "github.com/micro/micro/v3/service/api"
 	apiAuth "github.com/micro/micro/v3/service/api/auth"
@@ -23,6 +24,8 @@ import (
 	"github.com/micro/micro/v3/service/api/resolver/subdomain"
 	httpapi "github.com/micro/micro/v3/service/api/server/http"
 	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/errors"
 	log "github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/registry"
 	muregistry "github.com/micro/micro/v3/service/registry"
@@ -32,7 +35,10 @@ import (
 	"github.com/micro/micro/v3/util/acme"
 	"github.com/micro/micro/v3/util/acme/autocert"
 	"github.com/micro/micro/v3/util/acme/certmagic"
+	inauth "github.com/micro/micro/v3/util/auth"
+	"github.com/micro/micro/v3/util/clientip"
 	"github.com/micro/micro/v3/util/helper"
+	"github.com/micro/micro/v3/util/ownership"
 	"github.com/micro/micro/v3/util/sync/memory"
 	"github.com/serenize/snaker"
 	"github.com/urfave/cli/v2"
@@ -61,6 +67,8 @@ type srv struct {
 	registry registry.Registry
 	// the resolver
 	resolver res.Resolver
+	// sessions of the users logged into the dashboard
+	sessions pb.SessionsService
 }
 
 type reg struct {
@@ -75,7 +83,10 @@ type reg struct {
 func (s *srv) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// check if authenticated
 	if r.URL.Path != LoginURL {
-		c, err := r.Cookie(TokenCookieName)
+		c, err := r.Cookie(inauth.SessionCookieName)
+		if err != nil || c == nil {
+			c, err = r.Cookie(TokenCookieName)
+		}
 		if err != nil || c == nil {
 			http.Redirect(w, r, LoginURL, 302)
 			return
@@ -233,6 +244,17 @@ func (s *srv) loginHandler(w http.ResponseWriter, req *http.Request) {
 }
 
 func (s *srv) logoutHandler(w http.ResponseWriter, req *http.Request) {
+	// revoke the session so the key can't be used again
+	if c, err := req.Cookie(inauth.SessionCookieName); err == nil && len(c.Value) > 0 {
+		_, err := s.sessions.Revoke(req.Context(), &pb.RevokeSessionRequest{
+			Key: c.Value, Options: &pb.Options{Namespace: Namespace},
+		}, client.WithAuthToken())
+		if err != nil {
+			log.Errorf("Error revoking session: %v", err)
+		}
+	}
+	inauth.ClearSessionCookies(w, req.Host)
+
 	var domain string
 	if arr := strings.Split(req.Host, ":"); len(arr) > 0 {
 		domain = arr[0]
@@ -276,29 +298,20 @@ func (s *srv) generateTokenHandler(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	acc, err := auth.Token(
-		auth.WithCredentials(user, pass),
-		auth.WithTokenIssuer(Namespace),
-		auth.WithExpiry(time.Hour*24*7),
-	)
+	rsp, err := s.sessions.Create(req.Context(), &pb.CreateSessionRequest{
+		Id:        user,
+		Secret:    pass,
+		UserAgent: req.UserAgent(),
+		Ip:        clientip.FromRequest(req, clientip.DefaultTrusted),
+		Options:   &pb.Options{Namespace: Namespace},
+	}, client.WithAuthToken())
 	if err != nil {
-		renderError("Authentication failed: " + err.Error())
+		renderError("Authentication failed: " + errors.FromError(err).Detail)
 		return
 	}
 
-	var domain string
-	if arr := strings.Split(req.Host, ":"); len(arr) > 0 {
-		domain = arr[0]
-	}
-
-	http.SetCookie(w, &http.Cookie{
-		Name:    TokenCookieName,
-		Value:   acc.AccessToken,
-		Expires: acc.Expiry,
-		Domain:  domain,
-		Secure:  true,
-	})
-
+	expiry := time.Unix(rsp.Session.Expiry, 0)
+	inauth.SetSessionCookies(w, req.Host, rsp.Key, rsp.CsrfToken, expiry)
 	http.Redirect(w, req, "/", http.StatusFound)
 }
 
@@ -337,7 +350,20 @@ func (s *srv) registryHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		s.render(w, r, serviceTemplate, sv)
+		// the owner of the service is shown so users know who to contact about it
+		owner := ownership.FromService(sv)
+		if owner == nil {
+			if o, err := ownership.Get(domain, svc); err == nil {
+				owner = o
+			} else if err != ownership.ErrNotFound {
+				log.Errorf("Error getting the owner of %v: %v", svc, err)
+			}
+		}
+
+		s.render(w, r, serviceTemplate, sv, templateValue{
+			Key:   "Owner",
+			Value: owner,
+		})
 		return
 	}
 
@@ -539,6 +565,20 @@ func Run(ctx *cli.Context) error {
 		Namespace = ctx.String("namespace")
 	}
 
+	if proxies := ctx.StringSlice("trusted_proxies"); len(proxies) > 0 {
+		trusted, err := clientip.ParseTrusted(proxies)
+		if err != nil {
+			log.Fatalf("Invalid trusted proxies: %v", err)
+		}
+		clientip.DefaultTrusted = trusted
+	}
+	if h := ctx.String("forwarded_header"); len(h) > 0 {
+		if !strings.EqualFold(h, "X-Forwarded-For") && !strings.EqualFold(h, "Forwarded") {
+			log.Fatalf("Invalid forwarded header %v, expected X-Forwarded-For or Forwarded", h)
+		}
+		clientip.DefaultHeader = h
+	}
+
 	// Initialize Server
 	s := service.New(service.Name(Name))
 
@@ -566,6 +606,7 @@ func Run(ctx *cli.Context) error {
 			Registry: muregistry.DefaultRegistry,
 		},
 		resolver: resolver,
+		sessions: pb.NewSessionsService("auth", client.DefaultClient),
 	}
 
 	var h http.Handler
@@ -700,6 +741,17 @@ func init() {
 				EnvVars: []string{"MICRO_WEB_LOGIN_URL"},
 				Usage:   "The relative URL where a user can login",
 			},
+			&cli.StringSliceFlag{
+				Name:    "trusted_proxies",
+				Usage:   "Comma separated list of CIDRs of the proxies trusted to set the forwarded header, e.g. the load balancer",
+				EnvVars: []string{"MICRO_WEB_TRUSTED_PROXIES"},
+			},
+			&cli.StringFlag{
+				Name:    "forwarded_header",
+				Usage:   "Set the header the trusted proxies forward the client ip in, X-Forwarded-For or Forwarded",
+				EnvVars: []string{"MICRO_WEB_FORWARDED_HEADER"},
+				Value:   "X-Forwarded-For",
+			},
 		},
 	})
 }
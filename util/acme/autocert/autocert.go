@@ -15,10 +15,11 @@
 // Original source: github.com/micro/go-micro/v3/api/server/acme/autocert/autocert.go
 
 // Package autocert is the ACME provider from golang.org/x/crypto/acme/autocert
-// This provider does not take any config.
+// The only option this provider uses is the host policy.
 package autocert
 
 import (
+	"context"
 	"crypto/tls"
 	"net"
 	"os"
@@ -29,21 +30,31 @@ import (
 )
 
 // autoCertACME is the ACME provider from golang.org/x/crypto/acme/autocert
-type autocertProvider struct{}
+type autocertProvider struct {
+	opts acme.Options
+}
 
 // Listen implements acme.Provider
 func (a *autocertProvider) Listen(hosts ...string) (net.Listener, error) {
-	return autocert.NewListener(hosts...), nil
+	if a.opts.HostPolicy == nil || len(hosts) == 0 {
+		return autocert.NewListener(hosts...), nil
+	}
+	return a.manager(hosts...).Listener(), nil
 }
 
 // TLSConfig returns a new tls config
 func (a *autocertProvider) TLSConfig(hosts ...string) (*tls.Config, error) {
+	return a.manager(hosts...).TLSConfig(), nil
+}
+
+func (a *autocertProvider) manager(hosts ...string) *autocert.Manager {
 	// create a new manager
 	m := &autocert.Manager{
 		Prompt: autocert.AcceptTOS,
 	}
+	// without any hosts every host is allowed so the policy isn't needed
 	if len(hosts) > 0 {
-		m.HostPolicy = autocert.HostWhitelist(hosts...)
+		m.HostPolicy = hostPolicy(hosts, a.opts.HostPolicy)
 	}
 	dir := cacheDir()
 	if err := os.MkdirAll(dir, 0700); err != nil {
@@ -53,10 +64,27 @@ func (a *autocertProvider) TLSConfig(hosts ...string) (*tls.Config, error) {
 	} else {
 		m.Cache = autocert.DirCache(dir)
 	}
-	return m.TLSConfig(), nil
+	return m
+}
+
+// hostPolicy allows the hosts, falling back to the policy for any others
+func hostPolicy(hosts []string, policy func(ctx context.Context, host string) error) autocert.HostPolicy {
+	whitelist := autocert.HostWhitelist(hosts...)
+	return func(ctx context.Context, host string) error {
+		err := whitelist(ctx, host)
+		if err == nil || policy == nil {
+			return err
+		}
+		return policy(ctx, host)
+	}
 }
 
 // New returns an autocert acme.Provider
-func NewProvider() acme.Provider {
-	return &autocertProvider{}
+func NewProvider(options ...acme.Option) acme.Provider {
+	// autocert doesn't use the other options
+	var opts acme.Options
+	for _, o := range options {
+		o(&opts)
+	}
+	return &autocertProvider{opts: opts}
 }
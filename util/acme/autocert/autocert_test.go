@@ -17,6 +17,8 @@
 package autocert
 
 import (
+	"context"
+	"errors"
 	"testing"
 )
 
@@ -30,3 +32,29 @@ func TestAutocert(t *testing.T) {
 	// 	t.Error(err.Error())
 	// }
 }
+
+func TestHostPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	// without a policy only the hosts are allowed
+	p := hostPolicy([]string{"example.com"}, nil)
+	if err := p(ctx, "example.com"); err != nil {
+		t.Errorf("Expected example.com to be allowed, got %v", err)
+	}
+	if err := p(ctx, "custom.com"); err == nil {
+		t.Error("Expected custom.com to be rejected")
+	}
+
+	p = hostPolicy([]string{"example.com"}, func(ctx context.Context, host string) error {
+		if host == "custom.com" {
+			return nil
+		}
+		return errors.New("unknown host")
+	})
+	if err := p(ctx, "custom.com"); err != nil {
+		t.Errorf("Expected custom.com to be allowed by the policy, got %v", err)
+	}
+	if err := p(ctx, "other.com"); err == nil {
+		t.Error("Expected other.com to be rejected")
+	}
+}
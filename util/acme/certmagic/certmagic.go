@@ -18,6 +18,7 @@
 package certmagic
 
 import (
+	"context"
 	"crypto/tls"
 	"math/rand"
 	"net"
@@ -42,6 +43,14 @@ func (c *certmagicProvider) setup() {
 	if c.opts.OnDemand {
 		certmagic.Default.OnDemand = new(certmagic.OnDemandConfig)
 	}
+	if policy := c.opts.HostPolicy; policy != nil {
+		// hosts allowed by the policy aren't known up front so they're issued on demand
+		certmagic.Default.OnDemand = &certmagic.OnDemandConfig{
+			DecisionFunc: func(name string) error {
+				return policy(context.Background(), name)
+			},
+		}
+	}
 	if c.opts.Cache != nil {
 		// already validated by new()
 		certmagic.Default.Storage = c.opts.Cache.(certmagic.Storage)
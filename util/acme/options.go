@@ -16,7 +16,11 @@
 
 package acme
 
-import "github.com/go-acme/lego/v3/challenge"
+import (
+	"context"
+
+	"github.com/go-acme/lego/v3/challenge"
+)
 
 // Option (or Options) are passed to New() to configure providers
 type Option func(o *Options)
@@ -38,6 +42,10 @@ type Options struct {
 	// there's no defined interface, so if you consume this option
 	// sanity check it before using.
 	Cache interface{}
+	// HostPolicy decides if certificates can be issued for hosts which weren't passed to
+	// Listen or TLSConfig, e.g. custom domains added while running. If nil only those hosts
+	// are allowed.
+	HostPolicy func(ctx context.Context, host string) error
 }
 
 // AcceptToS indicates whether you accept your CA's terms of service
@@ -79,6 +87,13 @@ func Cache(c interface{}) Option {
 	}
 }
 
+// HostPolicy sets the policy used to decide if certificates can be issued for other hosts
+func HostPolicy(fn func(ctx context.Context, host string) error) Option {
+	return func(o *Options) {
+		o.HostPolicy = fn
+	}
+}
+
 // DefaultOptions uses the Let's Encrypt Production CA, with DNS Challenge disabled.
 func DefaultOptions() Options {
 	return Options{
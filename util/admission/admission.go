@@ -0,0 +1,231 @@
+// Package admission bounds the requests a service handles at once. Requests beyond the concurrency
+// limit wait in a bounded queue with a lane for each priority, the highest priority lane is served
+// first, and requests are rejected once the queue is full so overload is returned to the callers
+// rather than accumulating in the service until it runs out of memory. A request of a higher
+// priority arriving at a full queue takes the place of the newest request of a lower priority.
+package admission
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PriorityHeader is the header of the priority of a request, e.g. Micro-Priority: low
+const PriorityHeader = "Micro-Priority"
+
+// Priority of a request, the lane it waits in
+type Priority int
+
+const (
+	// Low priority requests, e.g. batch jobs, are handled once the other lanes are empty
+	Low Priority = iota
+	// Normal is the priority of requests which don't set one
+	Normal
+	// High priority requests, e.g. health checks, are handled before the other lanes
+	High
+)
+
+var (
+	// ErrQueueFull is returned when a request is rejected because the queue is full
+	ErrQueueFull = errors.New("admission queue full")
+
+	// DefaultController is used by the admission handler wrapper
+	DefaultController = New()
+)
+
+// ParsePriority parses the priority header, an unknown priority is normal
+func ParsePriority(v string) Priority {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "low":
+		return Low
+	case "high":
+		return High
+	default:
+		return Normal
+	}
+}
+
+func (p Priority) String() string {
+	switch p {
+	case Low:
+		return "low"
+	case High:
+		return "high"
+	default:
+		return "normal"
+	}
+}
+
+// Stats of the controller
+type Stats struct {
+	// InFlight is the number of requests being handled
+	InFlight int
+	// Queued is the number of requests waiting to be handled
+	Queued int
+	// Rejected is the number of requests rejected since the controller was created
+	Rejected uint64
+}
+
+// Controller admits requests up to the concurrency limit, queueing the rest
+type Controller struct {
+	sync.Mutex
+	opts     Options
+	inflight int
+	queued   int
+	rejected uint64
+	// the waiting requests of each priority, oldest first
+	lanes [High + 1]*list.List
+}
+
+type waiter struct {
+	// admit receives nil once a slot is handed to the request, or the error it's rejected with
+	admit chan error
+	elem  *list.Element
+}
+
+// New returns a controller
+func New(opts ...Option) *Controller {
+	c := &Controller{opts: newOptions(opts...)}
+	for i := range c.lanes {
+		c.lanes[i] = list.New()
+	}
+	return c
+}
+
+// Init sets the options of the controller. Requests waiting are admitted if the limit is raised.
+func (c *Controller) Init(opts ...Option) {
+	c.Lock()
+	defer c.Unlock()
+	for _, o := range opts {
+		o(&c.opts)
+	}
+	// the limit may have been raised
+	c.admit()
+}
+
+// Options of the controller
+func (c *Controller) Options() Options {
+	c.Lock()
+	defer c.Unlock()
+	return c.opts
+}
+
+// Stats returns the current state of the controller
+func (c *Controller) Stats() Stats {
+	c.Lock()
+	defer c.Unlock()
+	return Stats{InFlight: c.inflight, Queued: c.queued, Rejected: c.rejected}
+}
+
+// Acquire a slot for a request, waiting in the lane of its priority until one is free. It returns
+// ErrQueueFull if the queue is full, or the error of the context if it's done before a slot is
+// free. The release func must be called once the request has been handled.
+func (c *Controller) Acquire(ctx context.Context, p Priority) (func(), error) {
+	if p < Low || p > High {
+		p = Normal
+	}
+
+	c.Lock()
+	if c.opts.Concurrency <= 0 {
+		c.inflight++
+		c.Unlock()
+		return c.release, nil
+	}
+	// requests don't jump the queue
+	if c.inflight < c.opts.Concurrency && c.queued == 0 {
+		c.inflight++
+		c.Unlock()
+		return c.release, nil
+	}
+	if c.queued >= c.opts.QueueSize && !c.evict(p) {
+		c.rejected++
+		c.Unlock()
+		return nil, ErrQueueFull
+	}
+	w := &waiter{admit: make(chan error, 1)}
+	w.elem = c.lanes[p].PushBack(w)
+	c.queued++
+	c.Unlock()
+
+	select {
+	case err := <-w.admit:
+		if err != nil {
+			return nil, err
+		}
+		return c.release, nil
+	case <-ctx.Done():
+	}
+
+	c.Lock()
+	select {
+	case err := <-w.admit:
+		// admitted or evicted as the context was done
+		c.Unlock()
+		if err == nil {
+			c.release()
+		}
+	default:
+		c.lanes[p].Remove(w.elem)
+		c.queued--
+		c.Unlock()
+	}
+	return nil, ctx.Err()
+}
+
+// evict the newest request of a priority lower than the one provided, making room in the queue
+func (c *Controller) evict(p Priority) bool {
+	for i := Low; i < p; i++ {
+		e := c.lanes[i].Back()
+		if e == nil {
+			continue
+		}
+		c.lanes[i].Remove(e)
+		c.queued--
+		c.rejected++
+		e.Value.(*waiter).admit <- ErrQueueFull
+		return true
+	}
+	return false
+}
+
+// release the slot of a request, handing it to the requests waiting
+func (c *Controller) release() {
+	c.Lock()
+	defer c.Unlock()
+	c.inflight--
+	c.admit()
+}
+
+// admit the oldest requests of the highest priority waiting while there are free slots
+func (c *Controller) admit() {
+	for c.queued > 0 && (c.opts.Concurrency <= 0 || c.inflight < c.opts.Concurrency) {
+		for i := High; i >= Low; i-- {
+			e := c.lanes[i].Front()
+			if e == nil {
+				continue
+			}
+			c.lanes[i].Remove(e)
+			c.queued--
+			c.inflight++
+			e.Value.(*waiter).admit <- nil
+			break
+		}
+	}
+}
+
+type waitKey struct{}
+
+// NewContext returns a context recording how long the request waited in the queue
+func NewContext(ctx context.Context, wait time.Duration) context.Context {
+	return context.WithValue(ctx, waitKey{}, wait)
+}
+
+// WaitFromContext returns how long the request waited in the queue
+func WaitFromContext(ctx context.Context) (time.Duration, bool) {
+	d, ok := ctx.Value(waitKey{}).(time.Duration)
+	return d, ok
+}
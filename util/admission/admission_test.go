@@ -0,0 +1,109 @@
+package admission
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// queue a request in the background, returning the result once it's admitted or rejected
+func queue(c *Controller, ctx context.Context, p Priority) chan error {
+	res := make(chan error, 1)
+	go func() {
+		release, err := c.Acquire(ctx, p)
+		if err == nil {
+			release()
+		}
+		res <- err
+	}()
+	return res
+}
+
+func waitQueued(t *testing.T, c *Controller, n int) {
+	assert.Eventually(t, func() bool {
+		return c.Stats().Queued == n
+	}, time.Second, time.Millisecond)
+}
+
+func TestAcquire(t *testing.T) {
+	c := New(Concurrency(1), QueueSize(1))
+
+	release, err := c.Acquire(context.TODO(), Normal)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, c.Stats().InFlight)
+
+	res := queue(c, context.TODO(), Normal)
+	waitQueued(t, c, 1)
+
+	// the queue is full
+	_, err = c.Acquire(context.TODO(), Normal)
+	assert.Equal(t, ErrQueueFull, err)
+	assert.Equal(t, uint64(1), c.Stats().Rejected)
+
+	release()
+	assert.NoError(t, <-res)
+	assert.Equal(t, Stats{Rejected: 1}, c.Stats())
+}
+
+func TestPriority(t *testing.T) {
+	c := New(Concurrency(1), QueueSize(2))
+	release, err := c.Acquire(context.TODO(), Normal)
+	assert.NoError(t, err)
+
+	low := queue(c, context.TODO(), Low)
+	waitQueued(t, c, 1)
+	normal := queue(c, context.TODO(), Normal)
+	waitQueued(t, c, 2)
+
+	// the high priority request takes the place of the low priority one
+	high := make(chan func(), 1)
+	go func() {
+		r, err := c.Acquire(context.TODO(), High)
+		assert.NoError(t, err)
+		high <- r
+	}()
+	assert.Equal(t, ErrQueueFull, <-low)
+	waitQueued(t, c, 2)
+
+	// and is admitted first
+	release()
+	(<-high)()
+	assert.NoError(t, <-normal)
+	assert.Equal(t, 0, c.Stats().InFlight)
+}
+
+func TestCancel(t *testing.T) {
+	c := New(Concurrency(1), QueueSize(1))
+	release, err := c.Acquire(context.TODO(), Normal)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Millisecond*10)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, <-queue(c, ctx, Normal))
+	assert.Equal(t, 0, c.Stats().Queued)
+
+	release()
+	assert.Equal(t, 0, c.Stats().InFlight)
+}
+
+func TestInit(t *testing.T) {
+	c := New(Concurrency(1), QueueSize(1))
+	release, err := c.Acquire(context.TODO(), Normal)
+	assert.NoError(t, err)
+	defer release()
+
+	res := queue(c, context.TODO(), Normal)
+	waitQueued(t, c, 1)
+
+	// raising the limit admits the requests waiting
+	c.Init(Concurrency(2))
+	assert.NoError(t, <-res)
+}
+
+func TestParsePriority(t *testing.T) {
+	assert.Equal(t, Low, ParsePriority("low"))
+	assert.Equal(t, High, ParsePriority(" High"))
+	assert.Equal(t, Normal, ParsePriority("urgent"))
+}
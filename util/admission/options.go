@@ -0,0 +1,49 @@
+package admission
+
+import "time"
+
+// Options of the controller
+type Options struct {
+	// Concurrency is the number of requests handled at once, zero is unlimited
+	Concurrency int
+	// QueueSize is the number of requests which can wait for a slot across every lane
+	QueueSize int
+	// RetryAfter is returned to the callers of rejected requests
+	RetryAfter time.Duration
+}
+
+// Option sets an option of the controller
+type Option func(o *Options)
+
+func newOptions(opts ...Option) Options {
+	options := Options{
+		Concurrency: 1000,
+		QueueSize:   1000,
+		RetryAfter:  time.Second,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	return options
+}
+
+// Concurrency sets the number of requests handled at once, zero is unlimited
+func Concurrency(n int) Option {
+	return func(o *Options) {
+		o.Concurrency = n
+	}
+}
+
+// QueueSize sets the number of requests which can wait for a slot
+func QueueSize(n int) Option {
+	return func(o *Options) {
+		o.QueueSize = n
+	}
+}
+
+// RetryAfter sets the delay returned to the callers of rejected requests
+func RetryAfter(d time.Duration) Option {
+	return func(o *Options) {
+		o.RetryAfter = d
+	}
+}
@@ -0,0 +1,271 @@
+// Package analytics taps the requests served by the api and services, publishing a sanitized
+// summary of each to a topic for product analytics. Requests are sampled per route and personal
+// data is scrubbed from the summaries before they're published.
+package analytics
+
+import (
+	"crypto/hmac"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/util/scrub"
+)
+
+var (
+	// Topic the request events are published to
+	Topic = "analytics.requests"
+	// DefaultTap is used by the api and handler wrappers
+	DefaultTap = New()
+
+	// bufferSize is the number of events queued to be published, events are dropped once it's
+	// full so the tap never slows down requests
+	bufferSize = 1024
+)
+
+// DefaultScrubRules replace the personal data commonly found in routes and user agents. Numeric
+// segments of routes are also replaced with {id}.
+var DefaultScrubRules = []*ScrubRule{
+	{Pattern: regexp.MustCompile(`[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`), Replace: "{email}"},
+	{Pattern: regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`), Replace: "{uuid}"},
+}
+
+// Event summarises a request
+type Event struct {
+	// Source of the event, e.g. api or the name of the service
+	Source string `json:"source"`
+	// Route requested, e.g. /users/list or users/Users.List
+	Route string `json:"route"`
+	// Method of http requests
+	Method string `json:"method,omitempty"`
+	// Status code of the response
+	Status int `json:"status"`
+	// Latency in milliseconds
+	Latency float64 `json:"latency"`
+	// Account which made the request, hashed unless raw accounts are enabled
+	Account   string `json:"account,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	// Sample rate the event was recorded at, each event represents 1/rate requests
+	Sample    float64   `json:"sample"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ScrubRule replaces the matches of the pattern in the route and user agent
+type ScrubRule struct {
+	Pattern *regexp.Regexp
+	Replace string
+}
+
+// Options of a tap
+type Options struct {
+	// Sample is the rate requests are recorded at, from 0 to 1
+	Sample float64
+	// Routes are the sample rates of routes, keyed by the route prefix. The longest matching
+	// prefix is used.
+	Routes map[string]float64
+	// Scrub rules are applied to the route and user agent
+	Scrub []*ScrubRule
+	// RawAccounts publishes account ids as is rather than hashed
+	RawAccounts bool
+	// Salt of the account hashes, random unless set so hashes can't be reversed by hashing
+	// known account ids. Set it to group accounts across instances and restarts.
+	Salt []byte
+}
+
+// Option sets an option of a tap
+type Option func(o *Options)
+
+// Sample sets the default sample rate
+func Sample(rate float64) Option {
+	return func(o *Options) {
+		o.Sample = rate
+	}
+}
+
+// RouteSample sets the sample rate of routes starting with the prefix
+func RouteSample(prefix string, rate float64) Option {
+	return func(o *Options) {
+		o.Routes[prefix] = rate
+	}
+}
+
+// Scrub adds a rule, the default rules are always applied
+func Scrub(r *ScrubRule) Option {
+	return func(o *Options) {
+		o.Scrub = append(o.Scrub, r)
+	}
+}
+
+// RawAccounts publishes account ids without hashing them
+func RawAccounts(b bool) Option {
+	return func(o *Options) {
+		o.RawAccounts = b
+	}
+}
+
+// Salt sets the salt of the account hashes
+func Salt(b []byte) Option {
+	return func(o *Options) {
+		o.Salt = b
+	}
+}
+
+// ParseSample parses a sample rate flag, either a rate e.g. 0.1 or a route and rate e.g.
+// /users=0.5
+func ParseSample(s string) (Option, error) {
+	prefix, rate := "", s
+	if idx := strings.LastIndex(s, "="); idx >= 0 {
+		prefix, rate = s[:idx], s[idx+1:]
+	}
+	r, err := strconv.ParseFloat(rate, 64)
+	if err != nil || r < 0 || r > 1 {
+		return nil, fmt.Errorf("invalid sample rate %q, must be between 0 and 1", rate)
+	}
+	if len(prefix) == 0 {
+		return Sample(r), nil
+	}
+	return RouteSample(prefix, r), nil
+}
+
+// Tap samples, scrubs and publishes events
+type Tap struct {
+	sync.RWMutex
+	opts Options
+
+	once   sync.Once
+	events chan *Event
+}
+
+// New returns a tap which records every request
+func New(opts ...Option) *Tap {
+	salt := make([]byte, 32)
+	if _, err := crand.Read(salt); err != nil {
+		logger.Errorf("Error generating analytics salt: %v", err)
+	}
+	t := &Tap{opts: Options{Sample: 1, Routes: map[string]float64{}, Salt: salt}}
+	for _, o := range opts {
+		o(&t.opts)
+	}
+	return t
+}
+
+// Init applies options to the tap
+func (t *Tap) Init(opts ...Option) {
+	t.Lock()
+	defer t.Unlock()
+	for _, o := range opts {
+		o(&t.opts)
+	}
+}
+
+// rate returns the sample rate of the route
+func (t *Tap) rate(route string) float64 {
+	rate := t.opts.Sample
+	longest := -1
+	for p, r := range t.opts.Routes {
+		if strings.HasPrefix(route, p) && len(p) > longest {
+			rate, longest = r, len(p)
+		}
+	}
+	return rate
+}
+
+// scrub applies the scrub rules of the source and the rules of the tap to the value
+func (t *Tap) scrub(source, v string) string {
+	v = scrub.String(source, v)
+	for _, r := range DefaultScrubRules {
+		v = r.Pattern.ReplaceAllString(v, r.Replace)
+	}
+	for _, r := range t.opts.Scrub {
+		v = r.Pattern.ReplaceAllString(v, r.Replace)
+	}
+	return v
+}
+
+// scrubIDs replaces the numeric segments of a route, e.g. /users/123 becomes /users/{id}
+func scrubIDs(route string) string {
+	parts := strings.Split(route, "/")
+	for i, p := range parts {
+		if _, err := strconv.ParseUint(p, 10, 64); err == nil {
+			parts[i] = "{id}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// Sanitize returns the event with the scrub rules applied and the account hashed. It returns
+// nil if the event isn't sampled.
+func (t *Tap) Sanitize(ev *Event) *Event {
+	t.RLock()
+	defer t.RUnlock()
+
+	rate := t.rate(ev.Route)
+	if rate <= 0 || (rate < 1 && rand.Float64() >= rate) {
+		return nil
+	}
+
+	e := *ev
+	e.Sample = rate
+	e.Route = t.scrub(e.Source, scrubIDs(e.Route))
+	e.UserAgent = t.scrub(e.Source, e.UserAgent)
+	if len(e.Account) > 0 && !t.opts.RawAccounts {
+		mac := hmac.New(sha256.New, t.opts.Salt)
+		mac.Write([]byte(e.Namespace + "/" + e.Account))
+		e.Account = hex.EncodeToString(mac.Sum(nil)[:8])
+	}
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	return &e
+}
+
+// Record an event. Events are published in the background and dropped if the tap falls behind.
+func (t *Tap) Record(ev *Event) {
+	e := t.Sanitize(ev)
+	if e == nil {
+		return
+	}
+
+	t.once.Do(func() {
+		t.events = make(chan *Event, bufferSize)
+		go t.publish()
+	})
+
+	select {
+	case t.events <- e:
+	default:
+		logger.Debugf("Dropping analytics event for %v, the buffer is full", e.Route)
+	}
+}
+
+func (t *Tap) publish() {
+	for e := range t.events {
+		if err := events.Publish(Topic, e); err != nil {
+			logger.Debugf("Error publishing analytics event: %v", err)
+		}
+	}
+}
+
+// String describes the sampling of the tap
+func (t *Tap) String() string {
+	t.RLock()
+	defer t.RUnlock()
+
+	parts := []string{strconv.FormatFloat(t.opts.Sample, 'f', -1, 64)}
+	for p, r := range t.opts.Routes {
+		parts = append(parts, p+"="+strconv.FormatFloat(r, 'f', -1, 64))
+	}
+	sort.Strings(parts[1:])
+	return strings.Join(parts, ",")
+}
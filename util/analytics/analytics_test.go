@@ -0,0 +1,89 @@
+package analytics
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/events/stream/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitize(t *testing.T) {
+	tap := New(
+		RouteSample("/health", 0),
+		Scrub(&ScrubRule{Pattern: regexp.MustCompile(`token=[a-z0-9]+`), Replace: "token={redacted}"}),
+	)
+
+	e := tap.Sanitize(&Event{
+		Route:     "/users/123/emails/bob@example.com/f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		Account:   "bob",
+		Namespace: "foo",
+		UserAgent: "curl/8.0 token=abc123",
+	})
+	if assert.NotNil(t, e) {
+		assert.Equal(t, "/users/{id}/emails/{email}/{uuid}", e.Route)
+		assert.Equal(t, "curl/8.0 token={redacted}", e.UserAgent)
+		assert.Len(t, e.Account, 16)
+		assert.NotEqual(t, "bob", e.Account)
+		assert.Equal(t, 1.0, e.Sample)
+		assert.False(t, e.Timestamp.IsZero())
+	}
+
+	// the hash is stable so requests by the same account can be grouped
+	e2 := tap.Sanitize(&Event{Route: "/users", Account: "bob", Namespace: "foo"})
+	assert.Equal(t, e.Account, e2.Account)
+
+	// but salted, so the hash differs between taps unless they share the salt
+	assert.NotEqual(t, e.Account, New().Sanitize(&Event{Route: "/users", Account: "bob", Namespace: "foo"}).Account)
+	salted := func() string {
+		return New(Salt([]byte("secret"))).Sanitize(&Event{Route: "/users", Account: "bob", Namespace: "foo"}).Account
+	}
+	assert.Equal(t, salted(), salted())
+
+	// routes with a zero rate are never recorded
+	assert.Nil(t, tap.Sanitize(&Event{Route: "/health/live"}))
+
+	tap.Init(RawAccounts(true))
+	assert.Equal(t, "bob", tap.Sanitize(&Event{Route: "/users", Account: "bob"}).Account)
+}
+
+func TestParseSample(t *testing.T) {
+	tap := New()
+	for _, s := range []string{"0.1", "/users=0.5", "/users/list=1"} {
+		opt, err := ParseSample(s)
+		assert.Nil(t, err)
+		tap.Init(opt)
+	}
+	assert.Equal(t, 0.1, tap.rate("/orders"))
+	assert.Equal(t, 0.5, tap.rate("/users/create"))
+	assert.Equal(t, 1.0, tap.rate("/users/list"))
+	assert.Equal(t, "0.1,/users/list=1,/users=0.5", tap.String())
+
+	for _, s := range []string{"", "2", "/users=abc", "/users=-1"} {
+		_, err := ParseSample(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	stream, err := memory.NewStream()
+	assert.Nil(t, err)
+	events.DefaultStream = stream
+
+	evs, err := events.Consume(Topic)
+	assert.Nil(t, err)
+
+	New().Record(&Event{Source: "api", Route: "/users/1", Status: 200})
+
+	select {
+	case ev := <-evs:
+		var e Event
+		assert.Nil(t, ev.Unmarshal(&e))
+		assert.Equal(t, "/users/{id}", e.Route)
+		assert.Equal(t, 200, e.Status)
+	case <-time.After(time.Second):
+		t.Fatal("Expected the event to be published")
+	}
+}
@@ -0,0 +1,335 @@
+// Package anomaly detects anomalies in the request rate, error rate and latency of the endpoints
+// of a service. Rather than using hand tuned thresholds each metric is compared to a baseline
+// learnt for the hour of the week, so the daily and weekly patterns of traffic aren't treated as
+// anomalies. An alert is published when a metric deviates from its baseline by more than the
+// sensitivity, measured in standard deviations.
+//
+// Baselines are kept in memory by each instance of a service. Until the baseline for an hour
+// has enough observations the baseline across every hour is used instead. When a sync is set the
+// instances of a service elect a leader and only it publishes alerts, the others keep learning
+// their baselines so a new leader alerts straight away.
+package anomaly
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/logger"
+	msync "github.com/micro/micro/v3/util/sync"
+)
+
+var (
+	// Topic alerts are published to
+	Topic = "alerts"
+	// DefaultDetector is used by the anomaly handler wrapper
+	DefaultDetector = New()
+)
+
+const (
+	// MetricRate is the number of requests in a window
+	MetricRate = "rate"
+	// MetricErrors is the fraction of requests in a window which failed
+	MetricErrors = "errors"
+	// MetricLatency is the 95th percentile latency in milliseconds
+	MetricLatency = "latency"
+
+	// maxSamples caps the latencies kept per window to calculate the percentile from
+	maxSamples = 1000
+	// seasons is the number of hours in a week
+	seasons = 7 * 24
+)
+
+// Alert is raised when a metric deviates from its baseline
+type Alert struct {
+	Type     string    `json:"type"`
+	Service  string    `json:"service"`
+	Endpoint string    `json:"endpoint"`
+	Metric   string    `json:"metric"`
+	Value    float64   `json:"value"`
+	Expected float64   `json:"expected"`
+	Score    float64   `json:"score"`
+	Time     time.Time `json:"time"`
+}
+
+// stat is an exponentially weighted mean and variance. Until there are enough observations
+// for the weight to apply each is weighted equally.
+type stat struct {
+	N    int
+	Mean float64
+	Var  float64
+}
+
+func (s *stat) add(v, alpha float64) {
+	s.N++
+	a := 1 / float64(s.N)
+	if a < alpha {
+		a = alpha
+	}
+	d := v - s.Mean
+	s.Mean += a * d
+	s.Var = (1 - a) * (s.Var + a*d*d)
+}
+
+// score returns the number of standard deviations the value is from the mean. The deviation
+// has a floor so metrics which barely vary don't alert on tiny changes.
+func (s *stat) score(v, floor float64) float64 {
+	dev := math.Sqrt(s.Var)
+	if min := math.Max(floor, math.Abs(s.Mean)*0.1); dev < min {
+		dev = min
+	}
+	return (v - s.Mean) / dev
+}
+
+// baseline of a metric of an endpoint
+type baseline struct {
+	overall stat
+	season  [seasons]stat
+	alerted time.Time
+}
+
+// window of requests to an endpoint
+type window struct {
+	count     int
+	errors    int
+	latencies []float64
+}
+
+// Detector observes the requests to the endpoints of a service
+type Detector struct {
+	sync.Mutex
+	opts    Options
+	service string
+
+	windows   map[string]*window
+	baselines map[string]map[string]*baseline
+	// leader is true while the detector is elected to publish the alerts of the service
+	leader bool
+
+	once sync.Once
+	exit chan bool
+}
+
+// New returns a detector, it starts evaluating the windows when the first request is observed
+func New(opts ...Option) *Detector {
+	d := &Detector{
+		opts:      newOptions(opts...),
+		windows:   map[string]*window{},
+		baselines: map[string]map[string]*baseline{},
+		exit:      make(chan bool),
+	}
+	return d
+}
+
+// Init applies options to the detector
+func (d *Detector) Init(opts ...Option) {
+	d.Lock()
+	defer d.Unlock()
+	for _, o := range opts {
+		o(&d.opts)
+	}
+}
+
+// Observe a request to an endpoint of the service
+func (d *Detector) Observe(service, endpoint string, latency time.Duration, failed bool) {
+	d.once.Do(func() { go d.run(service) })
+
+	d.Lock()
+	defer d.Unlock()
+
+	d.service = service
+	w, ok := d.windows[endpoint]
+	if !ok {
+		w = &window{}
+		d.windows[endpoint] = w
+	}
+	w.count++
+	if failed {
+		w.errors++
+	}
+	if len(w.latencies) < maxSamples {
+		w.latencies = append(w.latencies, float64(latency.Microseconds())/1000)
+	}
+}
+
+// Stop evaluating the windows
+func (d *Detector) Stop() {
+	select {
+	case <-d.exit:
+	default:
+		close(d.exit)
+	}
+}
+
+func (d *Detector) run(service string) {
+	d.Lock()
+	interval := d.opts.Window
+	s := d.opts.Sync
+	d.leader = s == nil
+	d.Unlock()
+
+	if s != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go msync.Lead(ctx, s, service, d.lead)
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-d.exit:
+			return
+		case now := <-t.C:
+			alerts := d.Evaluate(now)
+			d.Lock()
+			fn := d.opts.Alert
+			leader := d.leader
+			d.Unlock()
+			if !leader {
+				continue
+			}
+			for _, a := range alerts {
+				fn(a)
+			}
+		}
+	}
+}
+
+// lead publishes the alerts of the service until leadership is lost
+func (d *Detector) lead(ctx context.Context) {
+	d.Lock()
+	d.leader = true
+	d.Unlock()
+
+	<-ctx.Done()
+
+	d.Lock()
+	d.leader = false
+	d.Unlock()
+}
+
+// Evaluate the window which has just closed against the baselines, returning the alerts
+// raised. The baselines are then updated with the window.
+func (d *Detector) Evaluate(now time.Time) []*Alert {
+	d.Lock()
+	defer d.Unlock()
+
+	windows := d.windows
+	d.windows = map[string]*window{}
+
+	// endpoints without any requests are evaluated too, a drop in traffic is an anomaly
+	for ep := range d.baselines {
+		if _, ok := windows[ep]; !ok {
+			windows[ep] = &window{}
+		}
+	}
+
+	season := int(now.UTC().Weekday())*24 + now.UTC().Hour()
+
+	var alerts []*Alert
+	for ep, w := range windows {
+		metrics := map[string]float64{MetricRate: float64(w.count)}
+		// the error rate and latency of a handful of requests are too noisy to be useful
+		if w.count >= d.opts.MinRequests {
+			metrics[MetricErrors] = float64(w.errors) / float64(w.count)
+			metrics[MetricLatency] = percentile(w.latencies, 0.95)
+		}
+
+		bs, ok := d.baselines[ep]
+		if !ok {
+			bs = map[string]*baseline{}
+			d.baselines[ep] = bs
+		}
+
+		for metric, v := range metrics {
+			b, ok := bs[metric]
+			if !ok {
+				b = &baseline{}
+				bs[metric] = b
+			}
+
+			if a := d.check(b, season, metric, v, now); a != nil {
+				a.Service = d.service
+				a.Endpoint = ep
+				alerts = append(alerts, a)
+			}
+
+			b.overall.add(v, d.opts.Alpha)
+			b.season[season].add(v, d.opts.Alpha)
+		}
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Endpoint == alerts[j].Endpoint {
+			return alerts[i].Metric < alerts[j].Metric
+		}
+		return alerts[i].Endpoint < alerts[j].Endpoint
+	})
+	return alerts
+}
+
+// check the value against the baseline, returning an alert if it's anomalous
+func (d *Detector) check(b *baseline, season int, metric string, v float64, now time.Time) *Alert {
+	ref := &b.season[season]
+	if ref.N < d.opts.Warmup {
+		ref = &b.overall
+	}
+	if ref.N < d.opts.Warmup {
+		return nil
+	}
+
+	score := ref.score(v, floors[metric])
+	// only increases in errors and latency are anomalies, but traffic can drop or spike
+	if metric != MetricRate && score < 0 {
+		return nil
+	}
+	if math.Abs(score) < d.opts.Sensitivity {
+		return nil
+	}
+	if now.Sub(b.alerted) < d.opts.Cooldown {
+		return nil
+	}
+	b.alerted = now
+
+	return &Alert{
+		Type:     "anomaly",
+		Metric:   metric,
+		Value:    v,
+		Expected: ref.Mean,
+		Score:    score,
+		Time:     now,
+	}
+}
+
+// floors are the minimum deviation of each metric, e.g. an endpoint which never errors
+// shouldn't alert on a single failure
+var floors = map[string]float64{
+	MetricRate:    1,
+	MetricErrors:  0.02,
+	MetricLatency: 5,
+}
+
+func percentile(v []float64, p float64) float64 {
+	if len(v) == 0 {
+		return 0
+	}
+	sort.Float64s(v)
+	idx := int(math.Ceil(p*float64(len(v)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return v[idx]
+}
+
+// Publish an alert to the alerts topic
+func Publish(a *Alert) {
+	logger.Warnf("Anomaly in the %v of %v %v: %.2f, expected %.2f", a.Metric, a.Service, a.Endpoint, a.Value, a.Expected)
+	if err := events.Publish(Topic, a); err != nil {
+		logger.Errorf("Error publishing alert: %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package anomaly
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/store/memory"
+	storesync "github.com/micro/micro/v3/util/sync/store"
+	"github.com/stretchr/testify/assert"
+)
+
+// observe a window of requests to the endpoint and evaluate it
+func observe(d *Detector, now time.Time, count, errors int, latency time.Duration) []*Alert {
+	for i := 0; i < count; i++ {
+		d.Observe("users", "Users.Read", latency, i < errors)
+	}
+	return d.Evaluate(now)
+}
+
+func TestDetector(t *testing.T) {
+	d := New(Warmup(10))
+	r := rand.New(rand.NewSource(1))
+	now := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	// learn the baseline from normal traffic, nothing is raised while it warms up
+	for i := 0; i < 50; i++ {
+		now = now.Add(time.Minute)
+		alerts := observe(d, now, 100+r.Intn(10), r.Intn(2), time.Millisecond*time.Duration(20+r.Intn(5)))
+		assert.Empty(t, alerts)
+	}
+
+	// a spike in errors is raised
+	now = now.Add(time.Minute)
+	alerts := observe(d, now, 100, 30, time.Millisecond*20)
+	if assert.Len(t, alerts, 1) {
+		assert.Equal(t, "users", alerts[0].Service)
+		assert.Equal(t, "Users.Read", alerts[0].Endpoint)
+		assert.Equal(t, MetricErrors, alerts[0].Metric)
+		assert.Equal(t, 0.3, alerts[0].Value)
+		assert.True(t, alerts[0].Score >= 4)
+	}
+
+	// but not again until the cooldown has passed
+	now = now.Add(time.Minute)
+	assert.Empty(t, observe(d, now, 100, 30, time.Millisecond*20))
+
+	// slow requests are raised
+	now = now.Add(time.Minute)
+	alerts = observe(d, now, 100, 0, time.Millisecond*200)
+	if assert.Len(t, alerts, 1) {
+		assert.Equal(t, MetricLatency, alerts[0].Metric)
+	}
+
+	// as is an endpoint which stops receiving requests
+	now = now.Add(time.Minute)
+	alerts = d.Evaluate(now)
+	if assert.Len(t, alerts, 1) {
+		assert.Equal(t, MetricRate, alerts[0].Metric)
+		assert.True(t, alerts[0].Score < 0)
+	}
+}
+
+func TestSensitivity(t *testing.T) {
+	// a small increase in latency is only raised by a sensitive detector
+	for _, s := range []struct {
+		sensitivity float64
+		alerts      int
+	}{{10, 0}, {2, 1}} {
+		d := New(Warmup(10), Sensitivity(s.sensitivity))
+		now := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+		for i := 0; i < 20; i++ {
+			now = now.Add(time.Minute)
+			observe(d, now, 100, 0, time.Millisecond*time.Duration(50+i%2*5))
+		}
+		now = now.Add(time.Minute)
+		assert.Len(t, observe(d, now, 100, 0, time.Millisecond*75), s.alerts)
+	}
+}
+
+func TestSeasonal(t *testing.T) {
+	d := New(Warmup(2))
+	now := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	// quiet at night and busy during the day, for a couple of weeks
+	for i := 0; i < 14*24; i++ {
+		now = now.Add(time.Hour)
+		count := 10
+		if h := now.Hour(); h >= 9 && h < 17 {
+			count = 100
+		}
+		alerts := observe(d, now, count, 0, time.Millisecond*20)
+		if i >= 7*24 {
+			assert.Empty(t, alerts, "unexpected alert at %v", now)
+		}
+	}
+
+	// daytime traffic in the middle of the night is raised
+	now = now.Add(time.Hour * time.Duration(24-now.Hour()+2))
+	alerts := observe(d, now, 100, 0, time.Millisecond*20)
+	if assert.Len(t, alerts, 1) {
+		assert.Equal(t, MetricRate, alerts[0].Metric)
+		assert.Equal(t, 10.0, alerts[0].Expected)
+	}
+}
+
+func TestLeader(t *testing.T) {
+	st := memory.NewStore()
+	a := New(Window(time.Millisecond*10), Sync(storesync.NewSync(st)))
+	b := New(Window(time.Millisecond*10), Sync(storesync.NewSync(st)))
+
+	leader := func(d *Detector) bool {
+		d.Lock()
+		defer d.Unlock()
+		return d.leader
+	}
+	waitLeader := func(d *Detector) {
+		for i := 0; i < 300 && !leader(d); i++ {
+			time.Sleep(time.Millisecond * 10)
+		}
+	}
+
+	// only one instance of the service is elected to publish its alerts
+	a.Observe("users", "Users.Read", time.Millisecond, false)
+	waitLeader(a)
+	b.Observe("users", "Users.Read", time.Millisecond, false)
+	time.Sleep(time.Millisecond * 50)
+	assert.True(t, leader(a))
+	assert.False(t, leader(b))
+
+	// when the leader stops another instance takes over
+	a.Stop()
+	waitLeader(b)
+	assert.True(t, leader(b))
+	b.Stop()
+}
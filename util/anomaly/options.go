@@ -0,0 +1,98 @@
+package anomaly
+
+import (
+	"time"
+
+	"github.com/micro/micro/v3/util/sync"
+)
+
+// Options of a detector
+type Options struct {
+	// Window requests are aggregated over before being evaluated
+	Window time.Duration
+	// Sensitivity is the number of standard deviations from the baseline a metric must be to
+	// raise an alert, lower values raise more alerts
+	Sensitivity float64
+	// Warmup is the number of windows a baseline needs before it's used
+	Warmup int
+	// MinRequests is the number of requests a window needs for its error rate and latency to
+	// be evaluated
+	MinRequests int
+	// Cooldown between alerts for the same metric of an endpoint
+	Cooldown time.Duration
+	// Alpha is the weight of each window in the baselines
+	Alpha float64
+	// Alert is called with each alert raised, defaults to publishing it
+	Alert func(*Alert)
+	// Sync elects the instance of the service which publishes the alerts, without it every
+	// instance publishes them
+	Sync sync.Sync
+}
+
+// Option sets an option of a detector
+type Option func(o *Options)
+
+func newOptions(opts ...Option) Options {
+	o := Options{
+		Window:      time.Minute,
+		Sensitivity: 4,
+		Warmup:      30,
+		MinRequests: 10,
+		Cooldown:    time.Minute * 15,
+		Alpha:       0.05,
+		Alert:       Publish,
+	}
+	for _, fn := range opts {
+		fn(&o)
+	}
+	return o
+}
+
+// Window sets the duration requests are aggregated over
+func Window(d time.Duration) Option {
+	return func(o *Options) {
+		o.Window = d
+	}
+}
+
+// Sensitivity sets the number of standard deviations which raises an alert
+func Sensitivity(s float64) Option {
+	return func(o *Options) {
+		o.Sensitivity = s
+	}
+}
+
+// Warmup sets the number of windows a baseline needs before it's used
+func Warmup(n int) Option {
+	return func(o *Options) {
+		o.Warmup = n
+	}
+}
+
+// MinRequests sets the number of requests needed to evaluate the error rate and latency
+func MinRequests(n int) Option {
+	return func(o *Options) {
+		o.MinRequests = n
+	}
+}
+
+// Cooldown sets the time between alerts for the same metric of an endpoint
+func Cooldown(d time.Duration) Option {
+	return func(o *Options) {
+		o.Cooldown = d
+	}
+}
+
+// WithAlert sets the function called with each alert
+func WithAlert(fn func(*Alert)) Option {
+	return func(o *Options) {
+		o.Alert = fn
+	}
+}
+
+// Sync sets the sync used to elect the instance of the service which publishes the alerts
+func Sync(s sync.Sync) Option {
+	return func(o *Options) {
+		o.Sync = s
+	}
+}
@@ -0,0 +1,134 @@
+// Package audit records the authorization decisions made for the calls services receive. Records
+// are published to a topic in the background and stored by the auth service, which serves them
+// from the Audit endpoint and `micro auth audit`.
+package audit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// Topic the audit records are published to
+	Topic = "audit"
+	// DefaultRecorder is used by the auth handler wrapper
+	DefaultRecorder = New()
+
+	// bufferSize is the number of records queued to be published, records are dropped once it's
+	// full so auditing never slows down calls
+	bufferSize = 1024
+)
+
+const (
+	// Granted is the decision recorded for calls which were allowed
+	Granted = "granted"
+	// Denied is the decision recorded for calls which were rejected
+	Denied = "denied"
+)
+
+// Record of an authorization decision
+type Record struct {
+	ID string `json:"id"`
+	// Account which made the call, blank for unauthenticated calls
+	Account string `json:"account,omitempty"`
+	// Namespace of the service called
+	Namespace string `json:"namespace"`
+	Service   string `json:"service"`
+	Endpoint  string `json:"endpoint"`
+	// Decision is either granted or denied
+	Decision string `json:"decision"`
+	// Reason the call was denied
+	Reason    string    `json:"reason,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Options of a recorder
+type Options struct {
+	// Enabled turns on recording, it's off by default since every call is recorded
+	Enabled bool
+}
+
+// Option sets an option of a recorder
+type Option func(o *Options)
+
+// Enabled turns recording on or off
+func Enabled(b bool) Option {
+	return func(o *Options) {
+		o.Enabled = b
+	}
+}
+
+// Recorder publishes audit records
+type Recorder struct {
+	sync.RWMutex
+	opts Options
+
+	once    sync.Once
+	records chan *Record
+}
+
+// New returns a recorder
+func New(opts ...Option) *Recorder {
+	r := &Recorder{}
+	for _, o := range opts {
+		o(&r.opts)
+	}
+	return r
+}
+
+// Init applies options to the recorder
+func (r *Recorder) Init(opts ...Option) {
+	r.Lock()
+	defer r.Unlock()
+	for _, o := range opts {
+		o(&r.opts)
+	}
+}
+
+// Enabled returns true if the recorder is recording
+func (r *Recorder) Enabled() bool {
+	r.RLock()
+	defer r.RUnlock()
+	return r.opts.Enabled
+}
+
+// Record a decision. Records are published in the background and dropped if the recorder falls
+// behind.
+func (r *Recorder) Record(rec *Record) {
+	if !r.Enabled() {
+		return
+	}
+	if len(rec.ID) == 0 {
+		rec.ID = uuid.New().String()
+	}
+	if rec.Timestamp.IsZero() {
+		rec.Timestamp = time.Now()
+	}
+
+	r.once.Do(func() {
+		r.records = make(chan *Record, bufferSize)
+		go r.publish()
+	})
+
+	select {
+	case r.records <- rec:
+	default:
+		logger.Warnf("Dropping audit record of %v:%v, the buffer is full", rec.Service, rec.Endpoint)
+	}
+}
+
+func (r *Recorder) publish() {
+	for rec := range r.records {
+		err := events.Publish(Topic, rec, events.WithMetadata(map[string]string{
+			"namespace": rec.Namespace,
+			"decision":  rec.Decision,
+		}))
+		if err != nil {
+			logger.Debugf("Error publishing audit record: %v", err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/events/stream/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecord(t *testing.T) {
+	stream, err := memory.NewStream()
+	assert.Nil(t, err)
+	events.DefaultStream = stream
+
+	evs, err := events.Consume(Topic)
+	assert.Nil(t, err)
+
+	// nothing is recorded until the recorder is enabled
+	r := New()
+	r.Record(&Record{Service: "users", Endpoint: "Users.Read", Decision: Granted})
+	r.Init(Enabled(true))
+	r.Record(&Record{Account: "john", Namespace: "micro", Service: "users", Endpoint: "Users.Delete", Decision: Denied})
+
+	select {
+	case ev := <-evs:
+		var rec Record
+		assert.Nil(t, ev.Unmarshal(&rec))
+		assert.Equal(t, "Users.Delete", rec.Endpoint)
+		assert.Equal(t, Denied, rec.Decision)
+		assert.NotEmpty(t, rec.ID)
+		assert.False(t, rec.Timestamp.IsZero())
+	case <-time.After(time.Second):
+		t.Fatal("Expected the record to be published")
+	}
+}
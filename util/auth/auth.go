@@ -1,6 +1,9 @@
 package auth
 
 import (
+	"context"
+	"strings"
+
 	"github.com/micro/micro/v3/service/auth"
 )
 
@@ -9,6 +12,10 @@ const (
 	BearerScheme = "Bearer "
 	// TokenCookieName is the name of the cookie which stores the auth token
 	TokenCookieName = "micro-token"
+	// APIKeyHeader is the header machine clients can pass an api key in
+	APIKeyHeader = "X-Micro-ApiKey"
+	// APIKeyPrefix is the prefix of api keys, which tells them apart from tokens
+	APIKeyPrefix = "mk_"
 )
 
 // SystemRules are the default rules which are applied to the runtime services
@@ -20,3 +27,27 @@ var SystemRules = []*auth.Rule{
 		Resource: &auth.Resource{Type: "*", Name: "*", Endpoint: "*"},
 	},
 }
+
+// ServiceMetadataKey is the account metadata key the name of the service the account belongs to
+// is set in. It's part of the signed token so a service can't claim to be another.
+const ServiceMetadataKey = "service"
+
+type serviceKey struct{}
+
+// ContextWithService sets the verified name of the service making a call in the context
+func ContextWithService(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, serviceKey{}, name)
+}
+
+// FromService returns the name of the service which made the call. It's only set if the name was
+// verified using the account of the service, unlike the Micro-From-Service header which can be
+// set by any caller.
+func FromService(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(serviceKey{}).(string)
+	return name, ok && len(name) > 0
+}
+
+// IsAPIKey returns true if the credential is an api key rather than a token
+func IsAPIKey(s string) bool {
+	return strings.HasPrefix(s, APIKeyPrefix)
+}
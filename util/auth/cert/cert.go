@@ -0,0 +1,187 @@
+// Package cert is the certificate identity of services. Services obtain short lived certificates
+// from the auth service which identify their account, authenticate the connections between them
+// with mTLS, and rotate the certificates before they expire. The account of a caller presenting a
+// certificate is read from the URI of the certificate, e.g.
+//
+//	micro://micro/helloworld-d7f8?scope=service&service=helloworld
+package cert
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/micro/micro/v3/service/auth"
+	inauth "github.com/micro/micro/v3/util/auth"
+)
+
+// URIScheme is the scheme of the URI identifying the account of a certificate
+const URIScheme = "micro"
+
+var (
+	// ErrInvalidRequest is returned when a certificate signing request can't be decoded or its
+	// signature doesn't match its key
+	ErrInvalidRequest = errors.New("invalid certificate signing request")
+	// ErrInvalidAuthority is returned when the certificate or key of the authority can't be decoded
+	ErrInvalidAuthority = errors.New("invalid certificate authority")
+)
+
+// Identity returns the URI identifying the account
+func Identity(acc *auth.Account) *url.URL {
+	q := url.Values{}
+	for _, s := range acc.Scopes {
+		q.Add("scope", s)
+	}
+	if name := acc.Metadata[inauth.ServiceMetadataKey]; len(name) > 0 {
+		q.Set("service", name)
+	}
+	return &url.URL{
+		Scheme:   URIScheme,
+		Host:     acc.Issuer,
+		Path:     "/" + acc.ID,
+		RawQuery: q.Encode(),
+	}
+}
+
+// AccountFromCertificate returns the account identified by the certificate. The certificate must
+// have been verified against the authority, the account isn't checked with the auth service.
+func AccountFromCertificate(c *x509.Certificate) (*auth.Account, bool) {
+	for _, u := range c.URIs {
+		if u.Scheme != URIScheme {
+			continue
+		}
+		id := strings.TrimPrefix(u.Path, "/")
+		if len(u.Host) == 0 || len(id) == 0 {
+			continue
+		}
+
+		q := u.Query()
+		acc := &auth.Account{
+			ID:       id,
+			Issuer:   u.Host,
+			Type:     "service",
+			Scopes:   q["scope"],
+			Metadata: map[string]string{},
+		}
+		if name := q.Get("service"); len(name) > 0 {
+			acc.Metadata[inauth.ServiceMetadataKey] = name
+		}
+		return acc, true
+	}
+	return nil, false
+}
+
+// NewAuthority generates the certificate and private key of an authority, PEM encoded
+func NewAuthority(name string, expiry time.Duration) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             now.Add(-time.Minute),
+		NotAfter:              now.Add(expiry),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
+
+// ParseAuthority decodes the PEM encoded certificate and private key of an authority
+func ParseAuthority(certPEM, keyPEM []byte) (*x509.Certificate, crypto.Signer, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	keyBlock, _ := pem.Decode(keyPEM)
+	if certBlock == nil || keyBlock == nil {
+		return nil, nil, ErrInvalidAuthority
+	}
+	c, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, ErrInvalidAuthority
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, ErrInvalidAuthority
+	}
+	return c, key, nil
+}
+
+// NewRequest returns a PEM encoded certificate signing request for the key
+func NewRequest(key crypto.Signer) ([]byte, error) {
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}), nil
+}
+
+// Sign the certificate signing request, issuing a PEM encoded certificate identifying the account
+// which is valid for both servers and clients. The subject of the request is ignored.
+func Sign(ca *x509.Certificate, caKey crypto.Signer, csrPEM []byte, acc *auth.Account, expiry time.Duration) ([]byte, error) {
+	block, _ := pem.Decode(csrPEM)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, ErrInvalidRequest
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, ErrInvalidRequest
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, ErrInvalidRequest
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	notAfter := now.Add(expiry)
+	if notAfter.After(ca.NotAfter) {
+		notAfter = ca.NotAfter
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: acc.ID, Organization: []string{acc.Issuer}},
+		URIs:         []*url.URL{Identity(acc)},
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, csr.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
+
+func serialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
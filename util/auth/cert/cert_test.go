@@ -0,0 +1,178 @@
+package cert
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/credentials"
+)
+
+func testIssuer(t *testing.T, acc *auth.Account, expiry time.Duration) IssueFunc {
+	caPEM, keyPEM, err := NewAuthority("test", time.Hour)
+	assert.NoError(t, err)
+	ca, key, err := ParseAuthority(caPEM, keyPEM)
+	assert.NoError(t, err)
+
+	return func(ctx context.Context, csr []byte) ([]byte, []byte, error) {
+		c, err := Sign(ca, key, csr, acc, expiry)
+		return c, caPEM, err
+	}
+}
+
+func TestSign(t *testing.T) {
+	acc := &auth.Account{
+		ID:       "helloworld-123",
+		Issuer:   "micro",
+		Scopes:   []string{"service"},
+		Metadata: map[string]string{"service": "helloworld"},
+	}
+	m := NewManager(Issue(testIssuer(t, acc, time.Hour)))
+	assert.NoError(t, m.Rotate(context.TODO()))
+	assert.WithinDuration(t, time.Now().Add(time.Hour), m.Expiry(), time.Minute)
+
+	got, ok := AccountFromCertificate(m.cert.Leaf)
+	assert.True(t, ok)
+	assert.Equal(t, acc.ID, got.ID)
+	assert.Equal(t, acc.Issuer, got.Issuer)
+	assert.Equal(t, "service", got.Type)
+	assert.Equal(t, acc.Scopes, got.Scopes)
+	assert.Equal(t, "helloworld", got.Metadata["service"])
+
+	// requests must be signed by their key
+	caPEM, keyPEM, _ := NewAuthority("test", time.Hour)
+	ca, key, _ := ParseAuthority(caPEM, keyPEM)
+	_, err := Sign(ca, key, []byte("invalid"), acc, time.Hour)
+	assert.Equal(t, ErrInvalidRequest, err)
+}
+
+func TestRotate(t *testing.T) {
+	acc := &auth.Account{ID: "foo", Issuer: "micro"}
+	m := NewManager(Issue(testIssuer(t, acc, time.Second*3)), RotateAt(0.5))
+	assert.NoError(t, m.Start())
+	defer m.Stop()
+
+	first := m.Expiry()
+	assert.Eventually(t, func() bool {
+		return m.Expiry().After(first)
+	}, time.Second*5, time.Millisecond*100)
+}
+
+func TestCredentials(t *testing.T) {
+	issue := testIssuer(t, &auth.Account{
+		ID:       "foo",
+		Issuer:   "micro",
+		Type:     "service",
+		Metadata: map[string]string{"service": "foo"},
+	}, time.Hour)
+	server := NewManager(Issue(issue))
+	client := NewManager(Issue(issue))
+	assert.NoError(t, server.Rotate(context.TODO()))
+	assert.NoError(t, client.Rotate(context.TODO()))
+
+	// accept the connections with the credentials of the server, returning the auth info
+	serve := func(creds credentials.TransportCredentials) (string, chan credentials.AuthInfo) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		infos := make(chan credentials.AuthInfo, 1)
+		go func() {
+			defer l.Close()
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			_, info, err := creds.ServerHandshake(conn)
+			if err != nil {
+				infos <- nil
+				return
+			}
+			infos <- info
+		}()
+		return l.Addr().String(), infos
+	}
+
+	t.Run("MTLS", func(t *testing.T) {
+		addr, infos := serve(server.ServerCredentials())
+		conn, err := client.Dial(context.TODO(), "foo", addr)
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		info, ok := (<-infos).(credentials.TLSInfo)
+		assert.True(t, ok)
+		assert.Len(t, info.State.VerifiedChains, 1)
+		acc, ok := AccountFromCertificate(info.State.PeerCertificates[0])
+		assert.True(t, ok)
+		assert.Equal(t, "foo", acc.ID)
+	})
+
+	t.Run("PlaintextClient", func(t *testing.T) {
+		addr, infos := serve(server.ServerCredentials())
+		conn, err := net.Dial("tcp", addr)
+		assert.NoError(t, err)
+		defer conn.Close()
+		conn.Write([]byte("PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"))
+
+		info := <-infos
+		assert.NotNil(t, info)
+		assert.NotEqual(t, "tls", info.AuthType())
+	})
+
+	t.Run("PlaintextServer", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		defer l.Close()
+		go func() {
+			for {
+				conn, err := l.Accept()
+				if err != nil {
+					return
+				}
+				// a plaintext server closes connections which don't start with its preface
+				conn.Read(make([]byte, 24))
+				conn.Close()
+			}
+		}()
+
+		// servers are only dialed without TLS if plaintext is enabled
+		_, err = client.Dial(context.TODO(), "foo", l.Addr().String())
+		assert.Error(t, err)
+		assert.False(t, client.plaintext(l.Addr().String()))
+
+		plain := NewManager(Issue(issue), Plaintext(true))
+		assert.NoError(t, plain.Rotate(context.TODO()))
+		conn, err := plain.Dial(context.TODO(), "foo", l.Addr().String())
+		assert.NoError(t, err)
+		conn.Close()
+		assert.True(t, plain.plaintext(l.Addr().String()))
+	})
+
+	t.Run("OtherService", func(t *testing.T) {
+		addr, _ := serve(server.ServerCredentials())
+		_, err := client.Dial(context.TODO(), "bar", addr)
+		assert.Error(t, err)
+	})
+
+	t.Run("UnknownAuthority", func(t *testing.T) {
+		other := NewManager(Issue(testIssuer(t, &auth.Account{ID: "bar", Issuer: "micro"}, time.Hour)))
+		assert.NoError(t, other.Rotate(context.TODO()))
+
+		addr, _ := serve(other.ServerCredentials())
+		_, err := client.Dial(context.TODO(), "bar", addr)
+		assert.Error(t, err)
+		assert.False(t, client.plaintext(addr))
+	})
+}
+
+func TestNewRequest(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	csr, err := NewRequest(key)
+	assert.NoError(t, err)
+	assert.Contains(t, string(csr), "CERTIFICATE REQUEST")
+}
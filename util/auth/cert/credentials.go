@@ -0,0 +1,211 @@
+package cert
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	inauth "github.com/micro/micro/v3/util/auth"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var (
+	// HandshakeTimeout is how long a connection has to start the handshake
+	HandshakeTimeout = time.Second * 10
+	// PlaintextTTL is how long a server which doesn't accept TLS connections is dialed without TLS
+	// before trying again, e.g. once it has been issued a certificate. Servers are only dialed
+	// without TLS if the manager was started with the Plaintext option.
+	PlaintextTTL = time.Minute * 5
+)
+
+// the type of the first record of a TLS handshake
+const handshakeRecord = 0x16
+
+// verifyError is returned when the certificate of the peer can't be verified
+type verifyError struct {
+	err error
+}
+
+func (v *verifyError) Error() string {
+	return "error verifying certificate: " + v.err.Error()
+}
+
+// serverConfig returns the TLS config of the servers, which verify the certificates of clients
+// presenting one against the authority. Clients without a certificate authenticate with a token.
+func (m *Manager) serverConfig() *tls.Config {
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			m.RLock()
+			defer m.RUnlock()
+			if m.cert == nil {
+				return nil, ErrNotIssued
+			}
+			return &tls.Config{
+				Certificates: []tls.Certificate{*m.cert},
+				ClientAuth:   tls.VerifyClientCertIfGiven,
+				ClientCAs:    m.pool,
+				NextProtos:   []string{"h2"},
+			}, nil
+		},
+	}
+}
+
+// clientConfig returns the TLS config of the clients, which present the certificate and verify
+// the certificate of the server against the authority and the identity of the service dialed
+func (m *Manager) clientConfig(service string) *tls.Config {
+	return &tls.Config{
+		// servers are dialed by address rather than the name in their certificate, so the chain
+		// and the service in the identity are verified instead of the hostname
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(raw [][]byte, _ [][]*x509.Certificate) error {
+			return m.verifyServer(service, raw)
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			m.RLock()
+			defer m.RUnlock()
+			if m.cert == nil {
+				return &tls.Certificate{}, nil
+			}
+			return m.cert, nil
+		},
+		NextProtos: []string{"h2"},
+	}
+}
+
+func (m *Manager) verifyServer(service string, raw [][]byte) error {
+	if len(raw) == 0 {
+		return &verifyError{errors.New("missing certificate")}
+	}
+	certs := make([]*x509.Certificate, len(raw))
+	for i, b := range raw {
+		c, err := x509.ParseCertificate(b)
+		if err != nil {
+			return &verifyError{err}
+		}
+		certs[i] = c
+	}
+
+	m.RLock()
+	opts := x509.VerifyOptions{
+		Roots:         m.pool,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	m.RUnlock()
+	for _, c := range certs[1:] {
+		opts.Intermediates.AddCert(c)
+	}
+	if _, err := certs[0].Verify(opts); err != nil {
+		return &verifyError{err}
+	}
+
+	acc, ok := AccountFromCertificate(certs[0])
+	if !ok {
+		return &verifyError{errors.New("missing identity")}
+	}
+	if name := acc.Metadata[inauth.ServiceMetadataKey]; name != service {
+		return &verifyError{fmt.Errorf("certificate of service %q rather than %q", name, service)}
+	}
+	return nil
+}
+
+// ServerCredentials returns the transport credentials of a grpc server. Connections which start
+// with a TLS handshake are secured with the certificate, others are accepted without TLS so
+// callers which haven't been issued a certificate can still authenticate with a token.
+func (m *Manager) ServerCredentials() credentials.TransportCredentials {
+	return &serverCredentials{
+		TransportCredentials: credentials.NewTLS(m.serverConfig()),
+	}
+}
+
+type serverCredentials struct {
+	credentials.TransportCredentials
+}
+
+func (s *serverCredentials) ServerHandshake(raw net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	r := bufio.NewReader(raw)
+	raw.SetReadDeadline(time.Now().Add(HandshakeTimeout))
+	b, err := r.Peek(1)
+	raw.SetReadDeadline(time.Time{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conn := &bufferedConn{Conn: raw, r: r}
+	if b[0] != handshakeRecord {
+		return insecure.NewCredentials().ServerHandshake(conn)
+	}
+	return s.TransportCredentials.ServerHandshake(conn)
+}
+
+func (s *serverCredentials) Clone() credentials.TransportCredentials {
+	return &serverCredentials{TransportCredentials: s.TransportCredentials.Clone()}
+}
+
+// bufferedConn reads the bytes peeked from the connection before the rest
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (b *bufferedConn) Read(p []byte) (int, error) {
+	return b.r.Read(p)
+}
+
+// Dial a server of the service, presenting the certificate. The certificate of the server must
+// identify the service. Servers which don't accept TLS connections, e.g. services which haven't
+// been issued a certificate, are only dialed without TLS with the Plaintext option.
+func (m *Manager) Dial(ctx context.Context, service, addr string) (net.Conn, error) {
+	var d net.Dialer
+	if m.opts.Plaintext && m.plaintext(addr) {
+		return d.DialContext(ctx, "tcp", addr)
+	}
+
+	raw, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	conn := tls.Client(raw, m.clientConfig(service))
+
+	deadline := time.Now().Add(HandshakeTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+	err = conn.Handshake()
+	conn.SetDeadline(time.Time{})
+	if err == nil {
+		return conn, nil
+	}
+	raw.Close()
+
+	// the server has a certificate which couldn't be verified, or rejected ours
+	var verr *verifyError
+	if errors.As(err, &verr) || strings.Contains(err.Error(), "remote error") || !m.opts.Plaintext {
+		return nil, err
+	}
+
+	// the server doesn't accept TLS connections
+	m.Lock()
+	m.plain[addr] = time.Now().Add(PlaintextTTL)
+	m.Unlock()
+	return d.DialContext(ctx, "tcp", addr)
+}
+
+func (m *Manager) plaintext(addr string) bool {
+	m.Lock()
+	defer m.Unlock()
+	exp, ok := m.plain[addr]
+	if ok && time.Now().After(exp) {
+		delete(m.plain, addr)
+		return false
+	}
+	return ok
+}
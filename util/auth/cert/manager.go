@@ -0,0 +1,191 @@
+package cert
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"sync"
+	"time"
+
+	pb "github.com/micro/micro/v3/proto/auth"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// ErrNotIssued is returned when a certificate hasn't been issued yet
+	ErrNotIssued = errors.New("certificate not issued")
+
+	// DefaultIssue is the func certificates are issued with, the auth service replaces it with one
+	// which issues the certificates itself
+	DefaultIssue IssueFunc = IssueFromService
+)
+
+// IssueFunc issues a certificate for the PEM encoded certificate signing request, returning the
+// PEM encoded certificate and the certificate of the authority which issued it
+type IssueFunc func(ctx context.Context, csr []byte) (cert []byte, authority []byte, err error)
+
+// IssueFromService issues the certificate for the account of the service with the auth service
+func IssueFromService(ctx context.Context, csr []byte) ([]byte, []byte, error) {
+	opts := auth.DefaultAuth.Options()
+	req := &pb.IssueCertificateRequest{
+		Csr:     string(csr),
+		Options: &pb.Options{Namespace: opts.Issuer},
+	}
+	rsp, err := pb.NewCertificatesService("auth", client.DefaultClient).Issue(ctx, req,
+		client.WithAddress(opts.Addrs...),
+		client.WithAuthToken(),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []byte(rsp.Certificate), []byte(rsp.Authority), nil
+}
+
+// Manager holds the certificate of the service, issuing a new one before it expires
+type Manager struct {
+	opts Options
+
+	sync.RWMutex
+	cert   *tls.Certificate
+	pool   *x509.CertPool
+	issued time.Time
+	expiry time.Time
+	// the addresses of the servers which don't accept TLS connections
+	plain map[string]time.Time
+	exit  chan struct{}
+}
+
+// NewManager returns a manager of the certificate of the service
+func NewManager(opts ...Option) *Manager {
+	return &Manager{
+		opts:  newOptions(opts...),
+		plain: map[string]time.Time{},
+	}
+}
+
+// Start issues the certificate, returning an error if it couldn't be issued before the timeout,
+// then rotates it before it expires until the manager is stopped
+func (m *Manager) Start() error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.opts.Timeout)
+	defer cancel()
+
+	for {
+		err := m.Rotate(ctx)
+		if err == nil {
+			break
+		}
+		logger.Warnf("Error issuing certificate: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(m.opts.RetryInterval):
+		}
+	}
+
+	m.Lock()
+	m.exit = make(chan struct{})
+	exit := m.exit
+	m.Unlock()
+	go m.run(exit)
+	return nil
+}
+
+// Stop rotating the certificate
+func (m *Manager) Stop() {
+	m.Lock()
+	defer m.Unlock()
+	if m.exit != nil {
+		close(m.exit)
+		m.exit = nil
+	}
+}
+
+// Expiry of the current certificate
+func (m *Manager) Expiry() time.Time {
+	m.RLock()
+	defer m.RUnlock()
+	return m.expiry
+}
+
+// Rotate generates a new key and issues a certificate for it, replacing the current certificate
+// once it has been issued
+func (m *Manager) Rotate(ctx context.Context) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	csr, err := NewRequest(key)
+	if err != nil {
+		return err
+	}
+	certPEM, authorityPEM, err := m.opts.Issue(ctx, csr)
+	if err != nil {
+		return err
+	}
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return errors.New("invalid certificate issued")
+	}
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(authorityPEM) {
+		return ErrInvalidAuthority
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	m.cert = &tls.Certificate{
+		Certificate: [][]byte{block.Bytes},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}
+	m.pool = pool
+	m.issued = time.Now()
+	m.expiry = leaf.NotAfter
+	return nil
+}
+
+// run rotates the certificate once the rotation point of its lifetime has passed, retrying until
+// a new one is issued
+func (m *Manager) run(exit chan struct{}) {
+	for {
+		m.RLock()
+		issued, expiry := m.issued, m.expiry
+		m.RUnlock()
+
+		wait := time.Until(issued.Add(time.Duration(float64(expiry.Sub(issued)) * m.opts.RotateAt)))
+		select {
+		case <-exit:
+			return
+		case <-time.After(wait):
+		}
+
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), m.opts.Timeout)
+			err := m.Rotate(ctx)
+			cancel()
+			if err == nil {
+				break
+			}
+			logger.Errorf("Error rotating certificate expiring at %v: %v", expiry, err)
+
+			select {
+			case <-exit:
+				return
+			case <-time.After(m.opts.RetryInterval):
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package cert
+
+import "time"
+
+// Options of the manager
+type Options struct {
+	// Issue the certificates
+	Issue IssueFunc
+	// Timeout of issuing a certificate
+	Timeout time.Duration
+	// RetryInterval is how long to wait before trying to issue a certificate again
+	RetryInterval time.Duration
+	// RotateAt is the fraction of the lifetime of a certificate after which it's rotated
+	RotateAt float64
+	// Plaintext dials servers which don't accept TLS connections without TLS
+	Plaintext bool
+}
+
+// Option sets an option of the manager
+type Option func(o *Options)
+
+func newOptions(opts ...Option) Options {
+	options := Options{
+		Issue:         DefaultIssue,
+		Timeout:       time.Second * 30,
+		RetryInterval: time.Second * 5,
+		RotateAt:      2.0 / 3.0,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	return options
+}
+
+// Issue sets the func the certificates are issued with
+func Issue(fn IssueFunc) Option {
+	return func(o *Options) {
+		o.Issue = fn
+	}
+}
+
+// Timeout sets the timeout of issuing a certificate
+func Timeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.Timeout = d
+	}
+}
+
+// RetryInterval sets how long to wait before trying to issue a certificate again
+func RetryInterval(d time.Duration) Option {
+	return func(o *Options) {
+		o.RetryInterval = d
+	}
+}
+
+// RotateAt sets the fraction of the lifetime of a certificate after which it's rotated
+func RotateAt(f float64) Option {
+	return func(o *Options) {
+		o.RotateAt = f
+	}
+}
+
+// Plaintext sets whether servers which don't accept TLS connections, e.g. services which haven't
+// been issued a certificate yet, are dialed without TLS. Connections fail otherwise.
+func Plaintext(b bool) Option {
+	return func(o *Options) {
+		o.Plaintext = b
+	}
+}
@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// RefreshBefore is how long before the token expires it's refreshed in the background
+	RefreshBefore = time.Minute
+	// RefreshInterval is how often the expiry of the token is checked in the background
+	RefreshInterval = time.Second * 15
+	// RefreshWait is how long calls wait for an expired token to be refreshed
+	RefreshWait = time.Second * 5
+	// MinRefreshInterval is how often a token which hasn't expired can be refreshed, e.g. because
+	// it was rejected by the services called
+	MinRefreshInterval = time.Second * 10
+	// TokenExpiry is the expiry of the tokens issued by the refresher
+	TokenExpiry = time.Minute * 10
+
+	// DefaultRefresher refreshes the token of auth.DefaultAuth
+	DefaultRefresher = NewRefresher()
+)
+
+// Refresher refreshes the token the client authenticates with, using the refresh token or the
+// account credentials if it has expired too. Refreshes requested at the same time are merged so
+// the auth service is only called once.
+type Refresher struct {
+	sync.Mutex
+	// the refresh in progress
+	pending *refresh
+	// when the token was last refreshed
+	refreshed time.Time
+}
+
+type refresh struct {
+	done chan struct{}
+	tok  *auth.AccountToken
+	err  error
+}
+
+// NewRefresher returns a refresher of the token of auth.DefaultAuth
+func NewRefresher() *Refresher {
+	return &Refresher{}
+}
+
+// Token returns the token, waiting for it to be refreshed if it has expired until the context is
+// done. A nil token is returned if the client doesn't have one.
+func (r *Refresher) Token(ctx context.Context) (*auth.AccountToken, error) {
+	tok := auth.DefaultAuth.Options().Token
+	if tok == nil || !tok.Expired() {
+		return tok, nil
+	}
+	return r.Refresh(ctx, tok)
+}
+
+// Refresh the token if it's still the token provided, e.g. one rejected by a service, and return
+// the current token. Tokens which haven't expired are refreshed at most once per
+// MinRefreshInterval.
+func (r *Refresher) Refresh(ctx context.Context, stale *auth.AccountToken) (*auth.AccountToken, error) {
+	r.Lock()
+	tok := auth.DefaultAuth.Options().Token
+	if tok == nil {
+		r.Unlock()
+		return nil, nil
+	}
+	// the token has been refreshed since
+	if stale != nil && tok.AccessToken != stale.AccessToken {
+		r.Unlock()
+		return tok, nil
+	}
+	if r.pending == nil {
+		if !tok.Expired() && time.Since(r.refreshed) < MinRefreshInterval {
+			r.Unlock()
+			return tok, nil
+		}
+		r.pending = &refresh{done: make(chan struct{})}
+		go r.refresh(r.pending, tok)
+	}
+	p := r.pending
+	r.Unlock()
+
+	select {
+	case <-p.done:
+		return p.tok, p.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// refresh the token, the callers waiting may give up before it finishes so it isn't cancelled
+// with their contexts
+func (r *Refresher) refresh(p *refresh, tok *auth.AccountToken) {
+	p.tok, p.err = newToken(tok)
+	if p.err == nil {
+		logger.Debugf("Auth token refreshed, expires at %v", p.tok.Expiry.Format(time.UnixDate))
+		auth.DefaultAuth.Init(auth.ClientToken(p.tok))
+	}
+
+	r.Lock()
+	r.pending = nil
+	if p.err == nil {
+		r.refreshed = time.Now()
+	}
+	r.Unlock()
+	close(p.done)
+}
+
+func newToken(tok *auth.AccountToken) (*auth.AccountToken, error) {
+	if len(tok.RefreshToken) > 0 {
+		t, err := auth.Token(
+			auth.WithToken(tok.RefreshToken),
+			auth.WithExpiry(TokenExpiry),
+		)
+		if err != auth.ErrInvalidToken {
+			return t, err
+		}
+		logger.Warnf("[Auth] Refresh token expired, regenerating using account credentials")
+	}
+
+	opts := auth.DefaultAuth.Options()
+	if len(opts.ID) == 0 || len(opts.Secret) == 0 {
+		return nil, auth.ErrInvalidToken
+	}
+	return auth.Token(
+		auth.WithCredentials(opts.ID, opts.Secret),
+		auth.WithExpiry(TokenExpiry),
+	)
+}
+
+// Run refreshes the token in the background before it expires. It returns immediately if the
+// client doesn't have a token.
+func (r *Refresher) Run() {
+	// can't refresh a token we don't have
+	if auth.DefaultAuth.Options().Token == nil {
+		return
+	}
+
+	t := time.NewTicker(RefreshInterval)
+	defer t.Stop()
+
+	for range t.C {
+		// don't refresh the token if it's not close to expiring
+		tok := auth.DefaultAuth.Options().Token
+		if tok == nil || time.Until(tok.Expiry) > RefreshBefore {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), RefreshInterval)
+		if _, err := r.Refresh(ctx, tok); err != nil {
+			logger.Warnf("[Auth] Error refreshing token: %v", err)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/auth/noop"
+	"github.com/stretchr/testify/assert"
+)
+
+// testAuth issues a new token each time one is requested
+type testAuth struct {
+	auth.Auth
+
+	sync.Mutex
+	issued int
+	// requests made with a refresh token
+	refreshed int
+}
+
+func (t *testAuth) Init(opts ...auth.Option) {
+	t.Lock()
+	defer t.Unlock()
+	t.Auth.Init(opts...)
+}
+
+func (t *testAuth) Options() auth.Options {
+	t.Lock()
+	defer t.Unlock()
+	return t.Auth.Options()
+}
+
+func (t *testAuth) Token(opts ...auth.TokenOption) (*auth.AccountToken, error) {
+	var options auth.TokenOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	// slow enough for the refreshes to overlap
+	time.Sleep(time.Millisecond * 20)
+
+	t.Lock()
+	defer t.Unlock()
+	if len(options.RefreshToken) > 0 {
+		t.refreshed++
+		if options.RefreshToken == "expired" {
+			return nil, auth.ErrInvalidToken
+		}
+	}
+	t.issued++
+	n := strconv.Itoa(t.issued)
+	return &auth.AccountToken{
+		AccessToken:  "access-" + n,
+		RefreshToken: "refresh-" + n,
+		Expiry:       time.Now().Add(options.Expiry),
+	}, nil
+}
+
+func setupAuth(t *testing.T, tok *auth.AccountToken) *testAuth {
+	prev := auth.DefaultAuth
+	t.Cleanup(func() { auth.DefaultAuth = prev })
+
+	a := &testAuth{Auth: noop.NewAuth(auth.ClientToken(tok), auth.Credentials("foo", "bar"))}
+	auth.DefaultAuth = a
+	return a
+}
+
+func TestRefresherToken(t *testing.T) {
+	expired := &auth.AccountToken{AccessToken: "old", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Second)}
+	a := setupAuth(t, expired)
+	r := NewRefresher()
+
+	// concurrent callers share the refresh
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tok, err := r.Token(context.TODO())
+			assert.NoError(t, err)
+			assert.Equal(t, "access-1", tok.AccessToken)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, a.refreshed)
+	assert.Equal(t, "access-1", auth.DefaultAuth.Options().Token.AccessToken)
+
+	// a valid token is returned as is
+	tok, err := r.Token(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, "access-1", tok.AccessToken)
+	assert.Equal(t, 1, a.issued)
+}
+
+func TestRefresherCredentials(t *testing.T) {
+	setupAuth(t, &auth.AccountToken{AccessToken: "old", RefreshToken: "expired", Expiry: time.Now().Add(-time.Second)})
+
+	// the account credentials are used when the refresh token has expired too
+	tok, err := NewRefresher().Token(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, "access-1", tok.AccessToken)
+}
+
+func TestRefresherRefresh(t *testing.T) {
+	a := setupAuth(t, &auth.AccountToken{AccessToken: "old", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)})
+	r := NewRefresher()
+	rejected := auth.DefaultAuth.Options().Token
+
+	tok, err := r.Refresh(context.TODO(), rejected)
+	assert.NoError(t, err)
+	assert.Equal(t, "access-1", tok.AccessToken)
+
+	// the token has been refreshed since it was rejected
+	tok, err = r.Refresh(context.TODO(), rejected)
+	assert.NoError(t, err)
+	assert.Equal(t, "access-1", tok.AccessToken)
+
+	// valid tokens aren't refreshed again straight away
+	tok, err = r.Refresh(context.TODO(), tok)
+	assert.NoError(t, err)
+	assert.Equal(t, "access-1", tok.AccessToken)
+	assert.Equal(t, 1, a.issued)
+}
+
+func TestRefresherWait(t *testing.T) {
+	setupAuth(t, &auth.AccountToken{AccessToken: "old", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Second)})
+
+	// callers stop waiting once their context is done
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Millisecond)
+	defer cancel()
+	r := NewRefresher()
+	_, err := r.Token(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// the refresh continues
+	tok, err := r.Token(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, "access-1", tok.AccessToken)
+}
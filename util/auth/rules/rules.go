@@ -47,6 +47,11 @@ func VerifyAccess(rules []*auth.Rule, acc *auth.Account, res *auth.Resource, opt
 			wildcard := fmt.Sprintf("%v/*", strings.Join(comps[0:i], "/"))
 			validEndpoints = append(validEndpoints, wildcard)
 		}
+	} else if comps := strings.Split(res.Endpoint, "."); len(comps) > 1 {
+		// rpc endpoints can be matched by the handler, e.g. Users.* includes Users.Delete
+		for i := 1; i < len(comps); i++ {
+			validEndpoints = append(validEndpoints, strings.Join(comps[0:i], ".")+".*")
+		}
 	}
 
 	// filter the rules to the ones which match the criteria above
@@ -295,6 +295,60 @@ func TestVerify(t *testing.T) {
 			},
 			Error: auth.ErrForbidden,
 		},
+		{
+			Name:     "RPCWildcardEndpointValid",
+			Resource: srvResource,
+			Account:  &auth.Account{Scopes: []string{"admin"}},
+			Rules: []*auth.Rule{
+				&auth.Rule{
+					Scope: "admin",
+					Resource: &auth.Resource{
+						Type:     srvResource.Type,
+						Name:     srvResource.Name,
+						Endpoint: "Foo.*",
+					},
+				},
+			},
+		},
+		{
+			Name:     "RPCWildcardEndpointInvalid",
+			Resource: srvResource,
+			Account:  &auth.Account{Scopes: []string{"admin"}},
+			Rules: []*auth.Rule{
+				&auth.Rule{
+					Scope: "admin",
+					Resource: &auth.Resource{
+						Type:     srvResource.Type,
+						Name:     srvResource.Name,
+						Endpoint: "Bar.*",
+					},
+				},
+			},
+			Error: auth.ErrForbidden,
+		},
+		{
+			Name:     "RPCEndpointDeniedForScope",
+			Resource: srvResource,
+			Account:  &auth.Account{Scopes: []string{"developer"}},
+			Rules: []*auth.Rule{
+				&auth.Rule{
+					Scope:    "*",
+					Resource: catchallResource,
+					Access:   auth.AccessGranted,
+				},
+				&auth.Rule{
+					Scope: "developer",
+					Resource: &auth.Resource{
+						Type:     srvResource.Type,
+						Name:     srvResource.Name,
+						Endpoint: srvResource.Endpoint,
+					},
+					Access:   auth.AccessDenied,
+					Priority: 1,
+				},
+			},
+			Error: auth.ErrForbidden,
+		},
 		{
 			Name:     "CrossNamespaceForbidden",
 			Resource: srvResource,
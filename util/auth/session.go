@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// SessionCookieName is the name of the cookie which stores the session key. The cookie isn't
+	// readable by scripts, the key is exchanged for an access token by the api and web services.
+	SessionCookieName = "micro-session"
+	// CSRFCookieName is the name of the cookie which stores the csrf token of the session. Scripts
+	// must read it and send it in the CSRFHeader of requests which aren't safe, e.g. POST.
+	CSRFCookieName = "micro-csrf"
+	// CSRFHeader is the header the csrf token is sent in
+	CSRFHeader = "X-CSRF-Token"
+)
+
+// SetSessionCookies sets the session and csrf cookies for the host
+func SetSessionCookies(w http.ResponseWriter, host, key, csrf string, expiry time.Time) {
+	domain := cookieDomain(host)
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    key,
+		Path:     "/",
+		Expires:  expiry,
+		Domain:   domain,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     CSRFCookieName,
+		Value:    csrf,
+		Path:     "/",
+		Expires:  expiry,
+		Domain:   domain,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// ClearSessionCookies expires the session and csrf cookies for the host
+func ClearSessionCookies(w http.ResponseWriter, host string) {
+	for _, name := range []string{SessionCookieName, CSRFCookieName} {
+		http.SetCookie(w, &http.Cookie{
+			Name:    name,
+			Value:   "",
+			Path:    "/",
+			Expires: time.Unix(0, 0),
+			Domain:  cookieDomain(host),
+			Secure:  true,
+		})
+	}
+}
+
+func cookieDomain(host string) string {
+	if arr := strings.Split(host, ":"); len(arr) > 0 {
+		return arr[0]
+	}
+	return ""
+}
@@ -120,3 +120,55 @@ func TestInspect(t *testing.T) {
 	})
 
 }
+
+// testKeys is a key source whose namespaces sign with the default key, some of which have keys of
+// their own
+type testKeys struct {
+	hasKeys map[string]bool
+}
+
+func (k *testKeys) SigningKey(ns string) (*token.Key, error) {
+	return nil, nil
+}
+
+func (k *testKeys) VerificationKey(ns, id string) (*token.Key, error) {
+	return nil, token.ErrNotFound
+}
+
+func (k *testKeys) HasKeys(ns string) (bool, error) {
+	return k.hasKeys[ns], nil
+}
+
+func TestInspectDefaultKey(t *testing.T) {
+	pubKey, err := ioutil.ReadFile("test/sample_key.pub")
+	if err != nil {
+		t.Fatalf("Unable to read public key: %v", err)
+	}
+	privKey, err := ioutil.ReadFile("test/sample_key")
+	if err != nil {
+		t.Fatalf("Unable to read private key: %v", err)
+	}
+
+	j := NewTokenProvider(
+		token.WithPublicKey(string(pubKey)),
+		token.WithPrivateKey(string(privKey)),
+		token.WithKeys(&testKeys{hasKeys: map[string]bool{"bar": true}}),
+	)
+
+	tok, err := j.Generate(&auth.Account{ID: "test", Issuer: "foo"})
+	if err != nil {
+		t.Fatalf("Generate returned %v error, expected nil", err)
+	}
+	if _, err := j.Inspect(tok.Token); err != nil {
+		t.Fatalf("Inspect returned %v error, expected nil", err)
+	}
+
+	// tokens signed with the default key aren't valid once the namespace has keys of its own
+	tok, err = j.Generate(&auth.Account{ID: "test", Issuer: "bar"})
+	if err != nil {
+		t.Fatalf("Generate returned %v error, expected nil", err)
+	}
+	if _, err := j.Inspect(tok.Token); err != token.ErrInvalidToken {
+		t.Fatalf("Inspect returned %v error, expected %v", err, token.ErrInvalidToken)
+	}
+}
@@ -0,0 +1,27 @@
+package token
+
+// Key is a key pair which signs the tokens issued by a namespace, so the key of one namespace can be
+// rotated without invalidating the tokens of the others
+type Key struct {
+	// ID of the key, set as the kid header of the tokens it signs
+	ID string
+	// Namespace the key signs tokens for
+	Namespace string
+	// PublicKey PEM encoded
+	PublicKey string
+	// PrivateKey PEM encoded, only the auth service has the private keys
+	PrivateKey string
+}
+
+// KeySource provides the signing keys of namespaces. Namespaces without a key use the default key.
+type KeySource interface {
+	// SigningKey returns the key new tokens issued by the namespace are signed with, or nil if the
+	// namespace uses the default key
+	SigningKey(ns string) (*Key, error)
+	// VerificationKey returns the key of the namespace with the id, ErrNotFound is returned if the key
+	// doesn't exist, e.g. because it was revoked
+	VerificationKey(ns, id string) (*Key, error)
+	// HasKeys returns true if the namespace has keys of its own, in which case the tokens it issues
+	// aren't verified with the default key
+	HasKeys(ns string) (bool, error)
+}
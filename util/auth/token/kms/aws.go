@@ -0,0 +1,180 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// aws signs using an AWS KMS asymmetric key. Requests are made to the KMS API directly, signed
+// using signature version 4.
+type aws struct {
+	keyID    string
+	region   string
+	endpoint string
+
+	accessKey    string
+	secretKey    string
+	sessionToken string
+}
+
+// newAWS parses a uri of the form awskms:///<key id, arn or alias>?region=<region>. The region
+// defaults to the one in the key arn or AWS_REGION, the credentials are read from
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN.
+func newAWS(u *url.URL) (*aws, error) {
+	a := &aws{
+		keyID:        strings.Trim(u.Host+u.Path, "/"),
+		region:       u.Query().Get("region"),
+		endpoint:     u.Query().Get("endpoint"),
+		accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if len(a.region) == 0 {
+		if comps := strings.Split(a.keyID, ":"); len(comps) > 3 && comps[0] == "arn" {
+			a.region = comps[3]
+		} else if r := os.Getenv("AWS_REGION"); len(r) > 0 {
+			a.region = r
+		} else {
+			a.region = os.Getenv("AWS_DEFAULT_REGION")
+		}
+	}
+	if len(a.endpoint) == 0 {
+		a.endpoint = fmt.Sprintf("https://kms.%v.amazonaws.com", a.region)
+	}
+
+	switch {
+	case len(a.keyID) == 0:
+		return nil, errors.New("missing aws key id")
+	case len(a.region) == 0:
+		return nil, errors.New("missing aws region, set AWS_REGION")
+	case len(a.accessKey) == 0 || len(a.secretKey) == 0:
+		return nil, errors.New("missing aws credentials, set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	return a, nil
+}
+
+func (a *aws) sign(ctx context.Context, digest []byte) ([]byte, error) {
+	in := map[string]interface{}{
+		"KeyId":            a.keyID,
+		"Message":          digest,
+		"MessageType":      "DIGEST",
+		"SigningAlgorithm": "RSASSA_PKCS1_V1_5_SHA_256",
+	}
+	var out struct {
+		Signature []byte
+	}
+	if err := a.call(ctx, "Sign", in, &out); err != nil {
+		return nil, err
+	}
+	return out.Signature, nil
+}
+
+func (a *aws) publicKey(ctx context.Context) ([]byte, error) {
+	var out struct {
+		PublicKey         []byte
+		SigningAlgorithms []string
+	}
+	if err := a.call(ctx, "GetPublicKey", map[string]string{"KeyId": a.keyID}, &out); err != nil {
+		return nil, err
+	}
+	for _, alg := range out.SigningAlgorithms {
+		if alg == "RSASSA_PKCS1_V1_5_SHA_256" {
+			return out.PublicKey, nil
+		}
+	}
+	return nil, errors.New("aws key must support RSASSA_PKCS1_V1_5_SHA_256")
+}
+
+func (a *aws) call(ctx context.Context, action string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", a.endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+	a.signRequest(req, body, time.Now())
+	return do(req, out)
+}
+
+// signRequest adds the signature version 4 authorization header to the request
+func (a *aws) signRequest(req *http.Request, body []byte, now time.Time) {
+	t := now.UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if len(a.sessionToken) > 0 {
+		req.Header.Set("X-Amz-Security-Token", a.sessionToken)
+	}
+
+	// the headers are signed in lexical order
+	headers := map[string]string{"host": req.URL.Host}
+	for k := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(req.Header.Get(k))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		"/",
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		hexSHA256(body),
+	}, "\n")
+
+	scope := strings.Join([]string{date, a.region, "kms", "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hexSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+a.secretKey), date)
+	key = hmacSHA256(key, a.region)
+	key = hmacSHA256(key, "kms")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%v/%v, SignedHeaders=%v, Signature=%v",
+		a.accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,125 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	gcpEndpoint = "https://cloudkms.googleapis.com"
+	// gcpMetadataToken is used to get an access token for the service account of the instance
+	gcpMetadataToken = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// gcp signs using a Google Cloud KMS asymmetric signing key version
+type gcp struct {
+	name     string
+	endpoint string
+
+	sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// newGCP parses a uri of the form gcpkms://projects/<project>/locations/<location>/keyRings/<ring>/
+// cryptoKeys/<key>/cryptoKeyVersions/<version>. The access token is read from
+// GOOGLE_OAUTH_ACCESS_TOKEN or requested from the metadata server.
+func newGCP(u *url.URL) (*gcp, error) {
+	name := strings.Trim(u.Host+u.Path, "/")
+	if !strings.HasPrefix(name, "projects/") || !strings.Contains(name, "/cryptoKeyVersions/") {
+		return nil, errors.New("gcp key uri must be the full name of a key version, e.g. gcpkms://projects/<project>/.../cryptoKeyVersions/1")
+	}
+
+	g := &gcp{name: name, endpoint: gcpEndpoint}
+	if ep := u.Query().Get("endpoint"); len(ep) > 0 {
+		g.endpoint = strings.TrimSuffix(ep, "/")
+	}
+	return g, nil
+}
+
+func (g *gcp) sign(ctx context.Context, digest []byte) ([]byte, error) {
+	in := map[string]interface{}{
+		"digest": map[string][]byte{"sha256": digest},
+	}
+	var out struct {
+		Signature []byte `json:"signature"`
+	}
+	if err := g.call(ctx, "POST", g.name+":asymmetricSign", in, &out); err != nil {
+		return nil, err
+	}
+	return out.Signature, nil
+}
+
+func (g *gcp) publicKey(ctx context.Context) ([]byte, error) {
+	var out struct {
+		Pem       string `json:"pem"`
+		Algorithm string `json:"algorithm"`
+	}
+	if err := g.call(ctx, "GET", g.name+"/publicKey", nil, &out); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(out.Algorithm, "RSA_SIGN_PKCS1_") || !strings.HasSuffix(out.Algorithm, "_SHA256") {
+		return nil, errors.New("gcp key must use an RSA_SIGN_PKCS1_*_SHA256 algorithm")
+	}
+	return pemToDER(out.Pem)
+}
+
+func (g *gcp) call(ctx context.Context, method, path string, in, out interface{}) error {
+	tok, err := g.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+	if in != nil {
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, g.endpoint+"/v1/"+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+tok)
+	req.Header.Set("Content-Type", "application/json")
+	return do(req, out)
+}
+
+// accessToken returns a token for the service account, refreshing it before it expires
+func (g *gcp) accessToken(ctx context.Context) (string, error) {
+	if tok := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); len(tok) > 0 {
+		return tok, nil
+	}
+
+	g.Lock()
+	defer g.Unlock()
+	if len(g.token) > 0 && time.Until(g.expiry) > time.Minute {
+		return g.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", gcpMetadataToken, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	var out struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := do(req, &out); err != nil {
+		return "", err
+	}
+	g.token = out.AccessToken
+	g.expiry = time.Now().Add(time.Duration(out.ExpiresIn) * time.Second)
+	return g.token, nil
+}
@@ -0,0 +1,173 @@
+// Package kms signs tokens using keys held by a key management service, so the private key is never
+// loaded into the process or written to disk
+package kms
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// DefaultCacheTTL is how long the public key fetched from the key management service is cached
+	DefaultCacheTTL = time.Hour
+	// DefaultTimeout is the timeout for requests to the key management service
+	DefaultTimeout = time.Second * 10
+
+	// ErrUnsupportedHash is returned when asked to sign a digest which isn't SHA-256
+	ErrUnsupportedHash = errors.New("only SHA-256 digests can be signed")
+)
+
+// client signs using a key management service
+type client interface {
+	// sign the SHA-256 digest, returning a PKCS #1 v1.5 signature
+	sign(ctx context.Context, digest []byte) ([]byte, error)
+	// publicKey returns the DER encoded PKIX public key
+	publicKey(ctx context.Context) ([]byte, error)
+}
+
+// NewSigner returns a signer for the key identified by the uri. The scheme selects the key
+// management service:
+//
+//	awskms:///arn:aws:kms:eu-west-1:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab
+//	gcpkms://projects/p/locations/global/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1
+//	vault://transit/jwt?version=2
+//
+// Credentials are loaded from the environment as they are by each service's own tooling, e.g.
+// AWS_ACCESS_KEY_ID, VAULT_TOKEN. The public key is fetched when the signer is created so
+// misconfiguration is detected at startup.
+func NewSigner(uri string) (crypto.Signer, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key uri: %v", err)
+	}
+
+	var c client
+	switch u.Scheme {
+	case "awskms":
+		c, err = newAWS(u)
+	case "gcpkms":
+		c, err = newGCP(u)
+	case "vault":
+		c, err = newVault(u)
+	default:
+		return nil, fmt.Errorf("unsupported key management service %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := &signer{client: c}
+	if _, err := s.fetchPublicKey(); err != nil {
+		return nil, fmt.Errorf("error fetching public key: %v", err)
+	}
+	return s, nil
+}
+
+// PublicKeyPEM returns the public key of the signer as a base64 encoded PEM, the format used to
+// configure the public key for JWT auth
+func PublicKeyPEM(s crypto.Signer) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(s.Public())
+	if err != nil {
+		return "", err
+	}
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	return base64.StdEncoding.EncodeToString(pemBytes), nil
+}
+
+type signer struct {
+	client client
+
+	sync.RWMutex
+	pub     crypto.PublicKey
+	fetched time.Time
+}
+
+// Public returns the public key, which is cached for DefaultCacheTTL. If the key can't be refreshed
+// the cached key continues to be used.
+func (s *signer) Public() crypto.PublicKey {
+	s.RLock()
+	pub, fetched := s.pub, s.fetched
+	s.RUnlock()
+
+	if time.Since(fetched) < DefaultCacheTTL {
+		return pub
+	}
+	if key, err := s.fetchPublicKey(); err == nil {
+		return key
+	} else {
+		logger.Errorf("Error refreshing public key: %v", err)
+	}
+	return pub
+}
+
+// Sign the SHA-256 digest using the key management service
+func (s *signer) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts.HashFunc() != crypto.SHA256 {
+		return nil, ErrUnsupportedHash
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+	return s.client.sign(ctx, digest)
+}
+
+func (s *signer) fetchPublicKey() (crypto.PublicKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+
+	der, err := s.client.publicKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Lock()
+	s.pub = key
+	s.fetched = time.Now()
+	s.Unlock()
+	return key, nil
+}
+
+// pemToDER decodes a PEM encoded public key
+func pemToDER(p string) ([]byte, error) {
+	block, _ := pem.Decode([]byte(p))
+	if block == nil {
+		return nil, errors.New("invalid PEM encoded public key")
+	}
+	return block.Bytes, nil
+}
+
+// do sends the request, decoding the JSON response into out
+func do(req *http.Request, out interface{}) error {
+	rsp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	body, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return err
+	}
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return fmt.Errorf("%v %v returned %v: %s", req.Method, req.URL.Path, rsp.Status, bytes.TrimSpace(body))
+	}
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,97 @@
+package kms
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/util/auth/token"
+	"github.com/micro/micro/v3/util/auth/token/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+// testVault implements the transit endpoints used by the signer
+func testVault(t *testing.T, key *rsa.PrivateKey) *httptest.Server {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.Nil(t, err)
+	pub := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("X-Vault-Token"))
+
+		switch r.URL.Path {
+		case "/v1/transit/keys/jwt":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"latest_version": 1,
+					"keys":           map[string]interface{}{"1": map[string]string{"public_key": pub}},
+				},
+			})
+		case "/v1/transit/sign/jwt/sha2-256":
+			var in struct {
+				Input     string `json:"input"`
+				Prehashed bool   `json:"prehashed"`
+			}
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(&in))
+			assert.True(t, in.Prehashed)
+
+			digest, _ := base64.StdEncoding.DecodeString(in.Input)
+			sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest)
+			assert.Nil(t, err)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]string{"signature": "vault:v1:" + base64.StdEncoding.EncodeToString(sig)},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestVaultSigner(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	srv := testVault(t, key)
+	defer srv.Close()
+
+	os.Setenv("VAULT_TOKEN", "secret")
+	defer os.Unsetenv("VAULT_TOKEN")
+
+	s, err := NewSigner("vault://transit/jwt?address=" + srv.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, &key.PublicKey, s.Public())
+
+	// tokens signed by the kms can be verified using the signer or the public key
+	tok, err := jwt.NewTokenProvider(token.WithSigner(s)).Generate(&auth.Account{ID: "test", Issuer: "micro"})
+	assert.Nil(t, err)
+
+	acc, err := jwt.NewTokenProvider(token.WithSigner(s)).Inspect(tok.Token)
+	assert.Nil(t, err)
+	assert.Equal(t, "test", acc.ID)
+
+	pub, err := PublicKeyPEM(s)
+	assert.Nil(t, err)
+	acc, err = jwt.NewTokenProvider(token.WithPublicKey(pub)).Inspect(tok.Token)
+	assert.Nil(t, err)
+	assert.Equal(t, "micro", acc.Issuer)
+}
+
+func TestNewSigner(t *testing.T) {
+	_, err := NewSigner("foo://bar")
+	assert.NotNil(t, err)
+
+	_, err = NewSigner("gcpkms://projects/p/locations/global/keyRings/r/cryptoKeys/k")
+	assert.NotNil(t, err)
+
+	os.Unsetenv("VAULT_TOKEN")
+	_, err = NewSigner("vault://transit/jwt?address=http://localhost:8200")
+	assert.NotNil(t, err)
+}
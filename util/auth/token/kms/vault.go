@@ -0,0 +1,120 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// vault signs using a HashiCorp Vault transit key
+type vault struct {
+	address string
+	token   string
+	mount   string
+	key     string
+	version int
+}
+
+// newVault parses a uri of the form vault://<mount>/<key>?version=<version>. The address and token
+// are read from VAULT_ADDR and VAULT_TOKEN, the address can be overridden using the address param.
+func newVault(u *url.URL) (*vault, error) {
+	v := &vault{
+		address: strings.TrimSuffix(os.Getenv("VAULT_ADDR"), "/"),
+		token:   os.Getenv("VAULT_TOKEN"),
+		mount:   u.Host,
+		key:     strings.Trim(u.Path, "/"),
+	}
+	if addr := u.Query().Get("address"); len(addr) > 0 {
+		v.address = strings.TrimSuffix(addr, "/")
+	}
+	if ver := u.Query().Get("version"); len(ver) > 0 {
+		n, err := strconv.Atoi(ver)
+		if err != nil {
+			return nil, errors.New("invalid vault key version")
+		}
+		v.version = n
+	}
+
+	switch {
+	case len(v.address) == 0:
+		return nil, errors.New("missing vault address, set VAULT_ADDR")
+	case len(v.token) == 0:
+		return nil, errors.New("missing vault token, set VAULT_TOKEN")
+	case len(v.mount) == 0 || len(v.key) == 0:
+		return nil, errors.New("vault key uri must be of the form vault://<mount>/<key>")
+	}
+	return v, nil
+}
+
+func (v *vault) sign(ctx context.Context, digest []byte) ([]byte, error) {
+	in := map[string]interface{}{
+		"input":               base64.StdEncoding.EncodeToString(digest),
+		"prehashed":           true,
+		"signature_algorithm": "pkcs1v15",
+	}
+	if v.version > 0 {
+		in["key_version"] = v.version
+	}
+
+	var out struct {
+		Data struct {
+			Signature string `json:"signature"`
+		} `json:"data"`
+	}
+	if err := v.call(ctx, "POST", "sign/"+v.key+"/sha2-256", in, &out); err != nil {
+		return nil, err
+	}
+
+	// signatures are of the form vault:v1:<base64 signature>
+	comps := strings.Split(out.Data.Signature, ":")
+	return base64.StdEncoding.DecodeString(comps[len(comps)-1])
+}
+
+func (v *vault) publicKey(ctx context.Context) ([]byte, error) {
+	var out struct {
+		Data struct {
+			LatestVersion int `json:"latest_version"`
+			Keys          map[string]struct {
+				PublicKey string `json:"public_key"`
+			} `json:"keys"`
+		} `json:"data"`
+	}
+	if err := v.call(ctx, "GET", "keys/"+v.key, nil, &out); err != nil {
+		return nil, err
+	}
+
+	version := v.version
+	if version == 0 {
+		version = out.Data.LatestVersion
+	}
+	key, ok := out.Data.Keys[strconv.Itoa(version)]
+	if !ok || len(key.PublicKey) == 0 {
+		return nil, errors.New("vault key has no public key, it must be an rsa key")
+	}
+	return pemToDER(key.PublicKey)
+}
+
+func (v *vault) call(ctx context.Context, method, path string, in, out interface{}) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, v.address+"/v1/"+v.mount+"/"+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	req.Header.Set("Content-Type", "application/json")
+	return do(req, out)
+}
@@ -17,6 +17,7 @@
 package token
 
 import (
+	"crypto"
 	"time"
 
 	"github.com/micro/micro/v3/service/store"
@@ -29,6 +30,10 @@ type Options struct {
 	PublicKey string
 	// PrivateKey base64 encoded, used by JWT
 	PrivateKey string
+	// Signer signs JWTs in place of the private key, e.g. using a key held in a KMS
+	Signer crypto.Signer
+	// Keys are the signing keys of namespaces, used by JWT in place of the default key
+	Keys KeySource
 }
 
 type Option func(o *Options)
@@ -54,6 +59,20 @@ func WithPrivateKey(key string) Option {
 	}
 }
 
+// WithSigner sets the signer used to sign JWTs rather than the private key
+func WithSigner(s crypto.Signer) Option {
+	return func(o *Options) {
+		o.Signer = s
+	}
+}
+
+// WithKeys sets the source of the signing keys of namespaces
+func WithKeys(k KeySource) Option {
+	return func(o *Options) {
+		o.Keys = k
+	}
+}
+
 func NewOptions(opts ...Option) Options {
 	var options Options
 	for _, o := range opts {
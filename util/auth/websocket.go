@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+)
+
+// TokenProtocolPrefix is prefixed to a token passed as a websocket subprotocol, e.g.
+// "micro-token.eyJhbGciOi...". Browsers can't set the Authorization header of a websocket so
+// scripts pass their token in the Sec-WebSocket-Protocol header instead.
+const TokenProtocolPrefix = "micro-token."
+
+// Subprotocols returns the websocket subprotocols requested by the client
+func Subprotocols(r *http.Request) []string {
+	var protocols []string
+	for _, v := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, p := range strings.Split(v, ",") {
+			if p = strings.TrimSpace(p); len(p) > 0 {
+				protocols = append(protocols, p)
+			}
+		}
+	}
+	return protocols
+}
+
+// WebSocketToken returns the token passed as a subprotocol of a websocket upgrade request
+func WebSocketToken(r *http.Request) string {
+	for _, p := range Subprotocols(r) {
+		if strings.HasPrefix(p, TokenProtocolPrefix) {
+			return strings.TrimPrefix(p, TokenProtocolPrefix)
+		}
+	}
+	return ""
+}
@@ -2,26 +2,48 @@ package cache
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
-	"fmt"
-	"hash/fnv"
+	"errors"
+	"reflect"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/auth"
 	"github.com/micro/micro/v3/service/client"
 	"github.com/micro/micro/v3/service/context/metadata"
-	cache "github.com/patrickmn/go-cache"
 )
 
+var (
+	// DefaultBackend is the backend of caches which don't set one. Set it to a shared backend,
+	// e.g. redis, to share the cached responses between the replicas of a service.
+	DefaultBackend Backend = NewMemoryBackend()
+
+	// ErrNotFound is returned by a backend when the key isn't cached
+	ErrNotFound = errors.New("not found")
+)
+
+// Backend stores the encoded responses by key
+type Backend interface {
+	// Get the value of the key, returning ErrNotFound if it isn't cached
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set the value of the key until the expiry
+	Set(ctx context.Context, key string, val []byte, expiry time.Duration) error
+	// Delete the key, it's not an error if the key isn't cached
+	Delete(ctx context.Context, key string) error
+	String() string
+}
+
 // New returns an initialised cache.
 func New() *Cache {
-	return &Cache{
-		cache: cache.New(cache.NoExpiration, 30*time.Second),
-	}
+	return &Cache{}
 }
 
 // Cache for responses
 type Cache struct {
-	cache *cache.Cache
+	// Backend the responses are stored in, DefaultBackend is used if nil
+	Backend Backend
 }
 
 type Options struct {
@@ -32,46 +54,141 @@ type Options struct {
 // used to store the options in context
 type optionsKey struct{}
 
-// Get a response from the cache
-func (c *Cache) Get(ctx context.Context, req client.Request) (interface{}, bool) {
-	return c.cache.Get(key(ctx, req))
+// used to store the cache key of a request in context
+type cacheKey struct{}
+
+func (c *Cache) backend() Backend {
+	if c.Backend != nil {
+		return c.Backend
+	}
+	return DefaultBackend
+}
+
+// Get a response from the cache, decoding it into rsp. Returns false if there's no response cached.
+func (c *Cache) Get(ctx context.Context, req client.Request, rsp interface{}) (bool, error) {
+	version, err := c.version(ctx, false)
+	if err == ErrNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	b, err := c.backend().Get(ctx, key(ctx, req, version))
+	if err == ErrNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(b, rsp); err != nil {
+		// don't leave a partially decoded response
+		v := reflect.ValueOf(rsp).Elem()
+		v.Set(reflect.Zero(v.Type()))
+		return false, err
+	}
+	return true, nil
 }
 
 // Set a response in the cache
-func (c *Cache) Set(ctx context.Context, req client.Request, rsp interface{}, expiry time.Duration) {
-	c.cache.Set(key(ctx, req), rsp, expiry)
+func (c *Cache) Set(ctx context.Context, req client.Request, rsp interface{}, expiry time.Duration) error {
+	b, err := json.Marshal(rsp)
+	if err != nil {
+		return err
+	}
+	version, err := c.version(ctx, true)
+	if err != nil {
+		return err
+	}
+	if len(version) > 0 {
+		// keep the version as long as the response, once it expires the responses cached under
+		// it can't be read, so it's safe for the version to expire first
+		if err := c.backend().Set(ctx, versionKey(ctx, KeyFromContext(ctx)), []byte(version), expiry); err != nil {
+			return err
+		}
+	}
+	return c.backend().Set(ctx, key(ctx, req, version), b, expiry)
 }
 
-// List the key value pairs in the cache
-func (c *Cache) List() map[string]string {
-	items := c.cache.Items()
+// Delete the responses cached under the key, set with client.WithCacheKey, in the namespace of the
+// context. Only the backend of the cache is purged, use the package level Delete to purge the
+// responses cached by the other services too.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	// the responses are cached under the version of the key, once it's deleted the responses
+	// can't be read and are removed by the backend when they expire
+	return c.backend().Delete(ctx, versionKey(ctx, key))
+}
 
-	rsp := make(map[string]string, len(items))
-	for k, v := range items {
-		bytes, _ := json.Marshal(v.Object)
-		rsp[k] = string(bytes)
+// version returns the version of the cache key of the context, creating one if it doesn't exist
+// and create is true. The version is blank if the request has no cache key.
+func (c *Cache) version(ctx context.Context, create bool) (string, error) {
+	name := KeyFromContext(ctx)
+	if len(name) == 0 {
+		return "", nil
 	}
 
-	return rsp
+	b, err := c.backend().Get(ctx, versionKey(ctx, name))
+	if err == nil {
+		return string(b), nil
+	} else if err != ErrNotFound || !create {
+		return "", err
+	}
+	return uuid.New().String(), nil
 }
 
-// key returns a hash for the context and request
-func key(ctx context.Context, req client.Request) string {
+// versionKey returns the key the version of a cache key is stored under in the backend
+func versionKey(ctx context.Context, name string) string {
 	ns, _ := metadata.Get(ctx, "Micro-Namespace")
-
 	bytes, _ := json.Marshal(map[string]interface{}{
 		"namespace": ns,
+		"key":       name,
+	})
+	h := sha256.Sum256(bytes)
+	return "version/" + hex.EncodeToString(h[:])
+}
+
+// key returns a hash for the context and request. The namespace and account of the caller are
+// included so a response is never returned to another tenant or account.
+func key(ctx context.Context, req client.Request, version string) string {
+	ns, _ := metadata.Get(ctx, "Micro-Namespace")
+
+	// the account making the request, or the token of a caller outside a service
+	var account string
+	if acc, ok := auth.AccountFromContext(ctx); ok {
+		account = acc.Issuer + "/" + acc.ID
+	} else if tok, ok := metadata.Get(ctx, "Authorization"); ok {
+		account = tok
+	}
+
+	k := map[string]interface{}{
+		"namespace": ns,
+		"account":   account,
+		"version":   version,
 		"request": map[string]interface{}{
 			"service":  req.Service(),
 			"endpoint": req.Endpoint(),
 			"method":   req.Method(),
 			"body":     req.Body(),
 		},
-	})
+	}
+	// the metadata the handler said the response varies by
+	if vary := varyValues(ctx); len(vary) > 0 {
+		k["vary"] = vary
+	}
+
+	bytes, _ := json.Marshal(k)
+
+	h := sha256.Sum256(bytes)
+	return hex.EncodeToString(h[:])
+}
+
+// ContextWithKey returns a context with the cache key of a request, set with client.WithCacheKey
+func ContextWithKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, cacheKey{}, key)
+}
 
-	h := fnv.New64()
-	h.Write(bytes)
-	return fmt.Sprintf("%x", h.Sum(nil))
+// KeyFromContext returns the cache key of a request, or a blank string if it doesn't have one
+func KeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(cacheKey{}).(string)
+	return key
 }
 
 func SetOptions(ctx context.Context, opts *Options) context.Context {
@@ -5,7 +5,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/micro/micro/v3/service/auth"
 	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/events/stream/memory"
 )
 
 func TestCache(t *testing.T) {
@@ -13,23 +16,37 @@ func TestCache(t *testing.T) {
 	req := &testRequest{service: "go.micro.service.foo", method: "Foo.Bar"}
 
 	t.Run("CacheMiss", func(t *testing.T) {
-		if _, ok := New().Get(ctx, req); ok {
+		var rsp string
+		if ok, err := (&Cache{Backend: NewMemoryBackend()}).Get(ctx, req, &rsp); ok || err != nil {
 			t.Errorf("Expected to get no result from Get")
 		}
 	})
 
 	t.Run("CacheHit", func(t *testing.T) {
-		c := New()
+		c := &Cache{Backend: NewMemoryBackend()}
 
 		rsp := "theresponse"
-		c.Set(ctx, req, rsp, time.Minute)
+		if err := c.Set(ctx, req, rsp, time.Minute); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
 
-		if res, ok := c.Get(ctx, req); !ok {
+		var res string
+		if ok, err := c.Get(ctx, req, &res); !ok || err != nil {
 			t.Errorf("Expected a result, got nothing")
 		} else if res != rsp {
 			t.Errorf("Expected '%v' result, got '%v'", rsp, res)
 		}
 	})
+
+	t.Run("SharedBackend", func(t *testing.T) {
+		b := NewMemoryBackend()
+		(&Cache{Backend: b}).Set(ctx, req, "theresponse", time.Minute)
+
+		var res string
+		if ok, _ := (&Cache{Backend: b}).Get(ctx, req, &res); !ok || res != "theresponse" {
+			t.Errorf("Expected the response cached by another cache with the backend")
+		}
+	})
 }
 
 func TestCacheKey(t *testing.T) {
@@ -40,16 +57,16 @@ func TestCacheKey(t *testing.T) {
 	req3 := &testRequest{service: "go.micro.service.foo", method: "Foo.Bar", body: "customquery"}
 
 	t.Run("IdenticalRequests", func(t *testing.T) {
-		key1 := key(ctx, req1)
-		key2 := key(ctx, req1)
+		key1 := key(ctx, req1, "")
+		key2 := key(ctx, req1, "")
 		if key1 != key2 {
 			t.Errorf("Expected the keys to match for identical requests and context")
 		}
 	})
 
 	t.Run("DifferentRequestEndpoints", func(t *testing.T) {
-		key1 := key(ctx, req1)
-		key2 := key(ctx, req2)
+		key1 := key(ctx, req1, "")
+		key2 := key(ctx, req2, "")
 
 		if key1 == key2 {
 			t.Errorf("Expected the keys to differ for different request endpoints")
@@ -57,21 +74,106 @@ func TestCacheKey(t *testing.T) {
 	})
 
 	t.Run("DifferentRequestBody", func(t *testing.T) {
-		key1 := key(ctx, req2)
-		key2 := key(ctx, req3)
+		key1 := key(ctx, req2, "")
+		key2 := key(ctx, req3, "")
 
 		if key1 == key2 {
 			t.Errorf("Expected the keys to differ for different request bodies")
 		}
 	})
 
+	t.Run("DifferentAccount", func(t *testing.T) {
+		key1 := key(auth.ContextWithAccount(ctx, &auth.Account{ID: "foo", Issuer: "micro"}), req1, "")
+		key2 := key(auth.ContextWithAccount(ctx, &auth.Account{ID: "bar", Issuer: "micro"}), req1, "")
+		key3 := key(auth.ContextWithAccount(ctx, &auth.Account{ID: "foo", Issuer: "other"}), req1, "")
+		key4 := key(metadata.Set(ctx, "Authorization", "Bearer foo"), req1, "")
+
+		if key1 == key2 || key1 == key3 || key1 == key4 || key4 == key(ctx, req1, "") {
+			t.Errorf("Expected the keys to differ for different accounts")
+		}
+	})
+
 	t.Run("DifferentMetadata", func(t *testing.T) {
 		mdCtx := metadata.Set(context.TODO(), "Micro-Namespace", "bar")
-		key1 := key(mdCtx, req1)
-		key2 := key(ctx, req1)
+		key1 := key(mdCtx, req1, "")
+		key2 := key(ctx, req1, "")
 
 		if key1 == key2 {
 			t.Errorf("Expected the keys to differ for different metadata")
 		}
 	})
 }
+
+func TestCacheDelete(t *testing.T) {
+	ctx := ContextWithKey(context.TODO(), "user-1")
+	req := &testRequest{service: "go.micro.service.foo", method: "Foo.Bar"}
+
+	t.Run("Delete", func(t *testing.T) {
+		c := &Cache{Backend: NewMemoryBackend()}
+		c.Set(ctx, req, "theresponse", time.Minute)
+		c.Set(context.TODO(), req, "untagged", time.Minute)
+
+		var res string
+		if ok, _ := c.Get(ctx, req, &res); !ok || res != "theresponse" {
+			t.Fatalf("Expected the response cached under the key")
+		}
+		if err := c.Delete(context.TODO(), "user-1"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if ok, _ := c.Get(ctx, req, &res); ok {
+			t.Errorf("Expected the response to be deleted")
+		}
+		if ok, _ := c.Get(context.TODO(), req, &res); !ok || res != "untagged" {
+			t.Errorf("Expected the response without the key to remain cached")
+		}
+
+		// the key is cached again once deleted
+		c.Set(ctx, req, "updated", time.Minute)
+		if ok, _ := c.Get(ctx, req, &res); !ok || res != "updated" {
+			t.Errorf("Expected the updated response, got %v", res)
+		}
+	})
+
+	t.Run("DifferentNamespace", func(t *testing.T) {
+		c := &Cache{Backend: NewMemoryBackend()}
+		c.Set(ctx, req, "theresponse", time.Minute)
+
+		c.Delete(metadata.Set(context.TODO(), "Micro-Namespace", "bar"), "user-1")
+
+		var res string
+		if ok, _ := c.Get(ctx, req, &res); !ok {
+			t.Errorf("Expected the key of another namespace not to be deleted")
+		}
+	})
+
+	t.Run("Subscribe", func(t *testing.T) {
+		stream, err := memory.NewStream()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		events.DefaultStream = stream
+		defer func() { events.DefaultStream = nil }()
+
+		// another replica of the service, caching the responses in process
+		replica := &Cache{Backend: NewMemoryBackend()}
+		sctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+		if err := replica.Subscribe(sctx); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		replica.Set(ctx, req, "theresponse", time.Minute)
+
+		if err := Delete(context.TODO(), "user-1"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var res string
+		for i := 0; i < 50; i++ {
+			if ok, _ := replica.Get(ctx, req, &res); !ok {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Errorf("Expected the replica to delete the invalidated key")
+	})
+}
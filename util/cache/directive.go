@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/micro/micro/v3/service/server"
+)
+
+const (
+	// ControlKey is the response metadata key the directive is set under, the api gateway returns
+	// it as the Cache-Control header
+	ControlKey = "Cache-Control"
+	// VaryKey is the response metadata key the vary keys are set under
+	VaryKey = "Vary"
+)
+
+// Directive is set by handlers to control how their responses are cached, since they know how
+// volatile the data is better than the callers do
+type Directive struct {
+	// MaxAge is how long the response can be cached for
+	MaxAge time.Duration
+	// NoStore prevents the response from being cached
+	NoStore bool
+	// Vary are the keys of the request metadata the response depends on, e.g. Accept-Language.
+	// The namespace and account of the caller are always part of the cache key.
+	Vary []string
+}
+
+// String returns the directive in the format of the Cache-Control header
+func (d *Directive) String() string {
+	if d.NoStore {
+		return "no-store"
+	}
+	return fmt.Sprintf("max-age=%d", int64(d.MaxAge.Seconds()))
+}
+
+// SetDirective sets the directive of the response being returned by a handler. The client cache
+// and the api gateway honor it. It returns false if the server doesn't support response metadata.
+func SetDirective(ctx context.Context, d Directive) bool {
+	if !server.SetResponseMetadata(ctx, ControlKey, d.String()) {
+		return false
+	}
+	if len(d.Vary) > 0 {
+		server.SetResponseMetadata(ctx, VaryKey, strings.Join(d.Vary, ", "))
+	}
+	return true
+}
+
+// ParseDirective returns the directive set in the response metadata, false is returned if the
+// handler didn't set one
+func ParseDirective(md metadata.Metadata) (*Directive, bool) {
+	ctrl, ok := md.Get(ControlKey)
+	if !ok {
+		return nil, false
+	}
+
+	d := &Directive{}
+	for _, part := range strings.Split(ctrl, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		switch {
+		case part == "no-store" || part == "no-cache":
+			d.NoStore = true
+		case strings.HasPrefix(part, "max-age="):
+			secs, err := strconv.ParseInt(strings.TrimPrefix(part, "max-age="), 10, 64)
+			if err != nil || secs < 0 {
+				d.NoStore = true
+				continue
+			}
+			d.MaxAge = time.Duration(secs) * time.Second
+		}
+	}
+	if d.MaxAge == 0 {
+		d.NoStore = true
+	}
+
+	if vary, ok := md.Get(VaryKey); ok {
+		for _, k := range strings.Split(vary, ",") {
+			if k = strings.TrimSpace(k); len(k) > 0 {
+				d.Vary = append(d.Vary, k)
+			}
+		}
+	}
+	return d, true
+}
+
+// used to store the vary keys of a request in context
+type varyKey struct{}
+
+// ContextWithVary returns a context with the keys of the request metadata the response varies by,
+// their values are included in the cache key
+func ContextWithVary(ctx context.Context, keys []string) context.Context {
+	return context.WithValue(ctx, varyKey{}, keys)
+}
+
+// varyValues returns the values of the vary keys of the context
+func varyValues(ctx context.Context) map[string]string {
+	keys, _ := ctx.Value(varyKey{}).([]string)
+	if len(keys) == 0 {
+		return nil
+	}
+	vals := make(map[string]string, len(keys))
+	for _, k := range keys {
+		vals[strings.ToLower(k)], _ = metadata.Get(ctx, k)
+	}
+	return vals
+}
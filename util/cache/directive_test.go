@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/micro/micro/v3/service/server"
+)
+
+func TestDirective(t *testing.T) {
+	ctx := server.NewResponseContext(context.TODO())
+	if !SetDirective(ctx, Directive{MaxAge: time.Minute, Vary: []string{"Accept-Language"}}) {
+		t.Fatalf("Expected the directive to be set")
+	}
+
+	d, ok := ParseDirective(server.ResponseMetadata(ctx))
+	if !ok {
+		t.Fatalf("Expected a directive")
+	}
+	if d.NoStore || d.MaxAge != time.Minute {
+		t.Errorf("Expected a max age of 1m, got %v", d)
+	}
+	if !reflect.DeepEqual(d.Vary, []string{"Accept-Language"}) {
+		t.Errorf("Expected to vary by Accept-Language, got %v", d.Vary)
+	}
+
+	for _, ctrl := range []string{"no-store", "max-age=0", "max-age=abc", "private"} {
+		d, _ := ParseDirective(metadata.Metadata{"Cache-Control": ctrl})
+		if !d.NoStore {
+			t.Errorf("Expected %v not to be stored", ctrl)
+		}
+	}
+
+	if _, ok := ParseDirective(metadata.Metadata{}); ok {
+		t.Errorf("Expected no directive")
+	}
+}
+
+func TestVaryKey(t *testing.T) {
+	req := &testRequest{service: "go.micro.service.foo", method: "Foo.Bar"}
+	en := ContextWithVary(metadata.Set(context.TODO(), "Accept-Language", "en"), []string{"Accept-Language"})
+	fr := ContextWithVary(metadata.Set(context.TODO(), "Accept-Language", "fr"), []string{"Accept-Language"})
+
+	if key(en, req, "") == key(fr, req, "") {
+		t.Errorf("Expected the keys to differ by the vary metadata")
+	}
+	if key(context.TODO(), req, "") != key(ContextWithVary(context.TODO(), nil), req, "") {
+		t.Errorf("Expected requests without vary keys to have the same key")
+	}
+}
@@ -0,0 +1,246 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// MaxHTTPExpiry is the longest an HTTP response is cached for, whatever its max age
+	MaxHTTPExpiry = time.Hour * 24
+	// MaxHTTPBodySize is the size of the largest HTTP response body cached
+	MaxHTTPBodySize = 1024 * 1024
+
+	// cacheableStatus are the statuses of the responses which can be cached
+	cacheableStatus = map[int]bool{
+		http.StatusOK:                   true,
+		http.StatusNonAuthoritativeInfo: true,
+		http.StatusNoContent:            true,
+		http.StatusMultipleChoices:      true,
+		http.StatusMovedPermanently:     true,
+		http.StatusNotFound:             true,
+		http.StatusGone:                 true,
+	}
+)
+
+// HTTPEntry is an HTTP response cached by the api gateway
+type HTTPEntry struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+	// Vary are the values of the request headers the response varies by, keyed by header
+	Vary map[string]string `json:"vary,omitempty"`
+	// Stored is when the response was cached, the Age header is derived from it
+	Stored time.Time `json:"stored"`
+}
+
+// Matches returns true if the request has the values of the headers the response varies by
+func (e *HTTPEntry) Matches(r *http.Request) bool {
+	for k, v := range e.Vary {
+		if r.Header.Get(k) != v {
+			return false
+		}
+	}
+	return true
+}
+
+// HTTPCache caches the responses of the api gateway. Responses are cached by namespace, host
+// and url. They can be purged by path, or for the whole namespace, by versioning the keys.
+type HTTPCache struct {
+	// Backend the responses are stored in, DefaultBackend is used if nil. Use a shared backend,
+	// e.g. redis, so the replicas of the api share the responses cached.
+	Backend Backend
+}
+
+// NewHTTP returns a cache of HTTP responses
+func NewHTTP() *HTTPCache {
+	return &HTTPCache{}
+}
+
+func (c *HTTPCache) backend() Backend {
+	if c.Backend != nil {
+		return c.Backend
+	}
+	return DefaultBackend
+}
+
+// Get the response cached for the request in the namespace, returning ErrNotFound if there
+// isn't one or the request doesn't match the headers it varies by
+func (c *HTTPCache) Get(ctx context.Context, ns string, r *http.Request) (*HTTPEntry, error) {
+	key, err := c.key(ctx, ns, r, false)
+	if err != nil {
+		return nil, err
+	}
+	b, err := c.backend().Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	var e *HTTPEntry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, err
+	}
+	if !e.Matches(r) {
+		return nil, ErrNotFound
+	}
+	return e, nil
+}
+
+// Set the response of the request in the namespace, it's cached until the expiry or
+// MaxHTTPExpiry, whichever is sooner
+func (c *HTTPCache) Set(ctx context.Context, ns string, r *http.Request, e *HTTPEntry, expiry time.Duration) error {
+	if expiry > MaxHTTPExpiry {
+		expiry = MaxHTTPExpiry
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	key, err := c.key(ctx, ns, r, true)
+	if err != nil {
+		return err
+	}
+	return c.backend().Set(ctx, key, b, expiry)
+}
+
+// Purge the responses cached for the path in the namespace, every response cached for the
+// namespace is purged if the path is blank. Responses to every host are purged.
+func (c *HTTPCache) Purge(ctx context.Context, ns, path string) error {
+	// the responses are cached under the versions, once they're deleted the responses can't be
+	// read and are removed by the backend when they expire
+	return c.backend().Delete(ctx, httpVersionKey(ns, path))
+}
+
+// key returns the key of the response to the request, it includes the versions of the namespace
+// and path so they can be purged. The versions are created if they don't exist and create is
+// true, otherwise ErrNotFound is returned.
+func (c *HTTPCache) key(ctx context.Context, ns string, r *http.Request, create bool) (string, error) {
+	nsVersion, err := c.version(ctx, httpVersionKey(ns, ""), create)
+	if err != nil {
+		return "", err
+	}
+	pathVersion, err := c.version(ctx, httpVersionKey(ns, r.URL.Path), create)
+	if err != nil {
+		return "", err
+	}
+
+	bytes, _ := json.Marshal(map[string]interface{}{
+		"namespace": ns,
+		"host":      strings.ToLower(r.Host),
+		"path":      r.URL.Path,
+		"query":     r.URL.Query().Encode(),
+		"versions":  []string{nsVersion, pathVersion},
+	})
+	h := sha256.Sum256(bytes)
+	return "http/" + hex.EncodeToString(h[:]), nil
+}
+
+// version returns the version stored under the key, creating one if it doesn't exist and create
+// is true
+func (c *HTTPCache) version(ctx context.Context, key string, create bool) (string, error) {
+	b, err := c.backend().Get(ctx, key)
+	if err == nil {
+		return string(b), nil
+	} else if err != ErrNotFound || !create {
+		return "", err
+	}
+
+	// the version outlives the responses cached under it when it's created, once it expires the
+	// responses can't be read so it's safe for it to expire first
+	version := uuid.New().String()
+	if err := c.backend().Set(ctx, key, []byte(version), MaxHTTPExpiry); err != nil {
+		return "", err
+	}
+	return version, nil
+}
+
+// httpVersionKey returns the key the version of the responses of the path in the namespace is
+// stored under, or the version of the namespace if the path is blank
+func httpVersionKey(ns, path string) string {
+	bytes, _ := json.Marshal(map[string]interface{}{
+		"namespace": ns,
+		"path":      path,
+	})
+	h := sha256.Sum256(bytes)
+	return "http-version/" + hex.EncodeToString(h[:])
+}
+
+// HTTPExpiry returns how long a response with the status and headers can be cached by a shared
+// cache, false is returned if it can't be. Responses must set a max age, either s-maxage or
+// max-age, and mustn't be private, set cookies or vary by every header.
+func HTTPExpiry(status int, h http.Header) (time.Duration, bool) {
+	if !cacheableStatus[status] || len(h.Values("Set-Cookie")) > 0 {
+		return 0, false
+	}
+	for _, v := range h.Values("Vary") {
+		if strings.TrimSpace(v) == "*" {
+			return 0, false
+		}
+	}
+
+	var maxAge, sMaxAge int64 = -1, -1
+	for _, part := range strings.Split(strings.Join(h.Values(ControlKey), ","), ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		switch {
+		case part == "private" || part == "no-store" || part == "no-cache":
+			return 0, false
+		case strings.HasPrefix(part, "max-age="):
+			maxAge, _ = strconv.ParseInt(strings.TrimPrefix(part, "max-age="), 10, 64)
+		case strings.HasPrefix(part, "s-maxage="):
+			sMaxAge, _ = strconv.ParseInt(strings.TrimPrefix(part, "s-maxage="), 10, 64)
+		}
+	}
+	if sMaxAge >= 0 {
+		maxAge = sMaxAge
+	}
+	if maxAge <= 0 {
+		return 0, false
+	}
+	return time.Duration(maxAge) * time.Second, true
+}
+
+// VaryHeaders returns the values of the request headers the response varies by
+func VaryHeaders(r *http.Request, h http.Header) map[string]string {
+	vary := map[string]string{}
+	for _, v := range h.Values("Vary") {
+		for _, k := range strings.Split(v, ",") {
+			if k = http.CanonicalHeaderKey(strings.TrimSpace(k)); len(k) > 0 {
+				vary[k] = r.Header.Get(k)
+			}
+		}
+	}
+	return vary
+}
+
+// ETag returns a strong entity tag of the body
+func ETag(body []byte) string {
+	h := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(h[:16]) + `"`
+}
+
+// MatchETag returns true if the If-None-Match header of the request matches the entity tag, so
+// the client's copy of the response is still valid
+func MatchETag(r *http.Request, etag string) bool {
+	inm := r.Header.Get("If-None-Match")
+	if len(inm) == 0 || len(etag) == 0 {
+		return false
+	}
+	if strings.TrimSpace(inm) == "*" {
+		return true
+	}
+	// the weak comparison is used, as it is for If-None-Match
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, t := range strings.Split(inm, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(t), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
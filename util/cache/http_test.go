@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPCache(t *testing.T) {
+	ctx := context.TODO()
+	c := &HTTPCache{Backend: NewMemoryBackend()}
+	req := httptest.NewRequest("GET", "http://api.example.com/users/list?b=2&a=1", nil)
+
+	if _, err := c.Get(ctx, "foo", req); err != ErrNotFound {
+		t.Fatalf("Expected a cache miss, got %v", err)
+	}
+
+	e := &HTTPEntry{Status: 200, Body: []byte(`{"users":[]}`), Stored: time.Now()}
+	if err := c.Set(ctx, "foo", req, e, time.Minute); err != nil {
+		t.Fatalf("Error caching the response: %v", err)
+	}
+	got, err := c.Get(ctx, "foo", req)
+	if err != nil {
+		t.Fatalf("Expected a cache hit, got %v", err)
+	}
+	if string(got.Body) != `{"users":[]}` {
+		t.Errorf("Expected the body cached, got %v", string(got.Body))
+	}
+
+	// the order of the query doesn't matter
+	if _, err := c.Get(ctx, "foo", httptest.NewRequest("GET", "http://api.example.com/users/list?a=1&b=2", nil)); err != nil {
+		t.Errorf("Expected a cache hit, got %v", err)
+	}
+	// responses aren't shared between namespaces or hosts
+	if _, err := c.Get(ctx, "bar", req); err != ErrNotFound {
+		t.Errorf("Expected a cache miss for another namespace, got %v", err)
+	}
+	if _, err := c.Get(ctx, "foo", httptest.NewRequest("GET", "http://other.example.com/users/list?b=2&a=1", nil)); err != ErrNotFound {
+		t.Errorf("Expected a cache miss for another host, got %v", err)
+	}
+
+	// purging another path doesn't purge the response
+	if err := c.Purge(ctx, "foo", "/users/read"); err != nil {
+		t.Fatalf("Error purging the cache: %v", err)
+	}
+	if _, err := c.Get(ctx, "foo", req); err != nil {
+		t.Errorf("Expected a cache hit, got %v", err)
+	}
+	if err := c.Purge(ctx, "foo", "/users/list"); err != nil {
+		t.Fatalf("Error purging the cache: %v", err)
+	}
+	if _, err := c.Get(ctx, "foo", req); err != ErrNotFound {
+		t.Errorf("Expected the path to be purged, got %v", err)
+	}
+
+	// purging the namespace purges every path
+	if err := c.Set(ctx, "foo", req, e, time.Minute); err != nil {
+		t.Fatalf("Error caching the response: %v", err)
+	}
+	if err := c.Purge(ctx, "foo", ""); err != nil {
+		t.Fatalf("Error purging the cache: %v", err)
+	}
+	if _, err := c.Get(ctx, "foo", req); err != ErrNotFound {
+		t.Errorf("Expected the namespace to be purged, got %v", err)
+	}
+
+	// responses are only returned to requests with the headers they vary by
+	e.Vary = map[string]string{"Accept-Language": "en"}
+	if err := c.Set(ctx, "foo", req, e, time.Minute); err != nil {
+		t.Fatalf("Error caching the response: %v", err)
+	}
+	if _, err := c.Get(ctx, "foo", req); err != ErrNotFound {
+		t.Errorf("Expected a cache miss for another language, got %v", err)
+	}
+	req.Header.Set("Accept-Language", "en")
+	if _, err := c.Get(ctx, "foo", req); err != nil {
+		t.Errorf("Expected a cache hit, got %v", err)
+	}
+}
+
+func TestHTTPExpiry(t *testing.T) {
+	tt := []struct {
+		Name   string
+		Status int
+		Header http.Header
+		Expiry time.Duration
+	}{
+		{"MaxAge", 200, http.Header{"Cache-Control": {"max-age=60"}}, time.Minute},
+		{"SharedMaxAge", 200, http.Header{"Cache-Control": {"public, max-age=60, s-maxage=120"}}, time.Minute * 2},
+		{"NotFound", 404, http.Header{"Cache-Control": {"max-age=60"}}, time.Minute},
+		{"NoDirective", 200, http.Header{}, 0},
+		{"Private", 200, http.Header{"Cache-Control": {"private, max-age=60"}}, 0},
+		{"NoStore", 200, http.Header{"Cache-Control": {"no-store"}}, 0},
+		{"ZeroMaxAge", 200, http.Header{"Cache-Control": {"max-age=0"}}, 0},
+		{"Error", 500, http.Header{"Cache-Control": {"max-age=60"}}, 0},
+		{"SetCookie", 200, http.Header{"Cache-Control": {"max-age=60"}, "Set-Cookie": {"a=b"}}, 0},
+		{"VaryAll", 200, http.Header{"Cache-Control": {"max-age=60"}, "Vary": {"*"}}, 0},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			expiry, ok := HTTPExpiry(tc.Status, tc.Header)
+			if ok != (tc.Expiry > 0) || expiry != tc.Expiry {
+				t.Errorf("Expected an expiry of %v, got %v (%v)", tc.Expiry, expiry, ok)
+			}
+		})
+	}
+}
+
+func TestMatchETag(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	if MatchETag(req, `"abc"`) {
+		t.Errorf("Expected no match without If-None-Match")
+	}
+
+	req.Header.Set("If-None-Match", `"xyz", W/"abc"`)
+	if !MatchETag(req, `"abc"`) {
+		t.Errorf("Expected the weak tag to match")
+	}
+	if MatchETag(req, `"def"`) {
+		t.Errorf("Expected another tag not to match")
+	}
+}
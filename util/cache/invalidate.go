@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+// InvalidateTopic is the topic the cache keys deleted with Delete are published to
+var InvalidateTopic = "micro.cache.invalidate"
+
+// Invalidation of the responses cached under a key
+type Invalidation struct {
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+}
+
+// Delete the responses cached under the key in the namespace of the context, e.g. when the data
+// they were built from changes. The key is deleted from the default backend and published to the
+// InvalidateTopic, so the services caching the responses in process delete it too.
+func Delete(ctx context.Context, key string) error {
+	if err := New().Delete(ctx, key); err != nil {
+		return err
+	}
+	if events.DefaultStream == nil {
+		return nil
+	}
+	ns, _ := metadata.Get(ctx, "Micro-Namespace")
+	return events.Publish(InvalidateTopic, &Invalidation{Namespace: ns, Key: key})
+}
+
+// Subscribe to the invalidations published by Delete, deleting the keys from the backend of the
+// cache until the context is done
+func (c *Cache) Subscribe(ctx context.Context) error {
+	// without a group every subscriber receives the invalidations
+	evs, err := events.Consume(InvalidateTopic, events.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-evs:
+				if !ok {
+					return
+				}
+				var inv Invalidation
+				if err := ev.Unmarshal(&inv); err != nil {
+					logger.Warnf("Error decoding cache invalidation %v: %v", ev.ID, err)
+					continue
+				}
+				if err := c.Delete(metadata.Set(ctx, "Micro-Namespace", inv.Namespace), inv.Key); err != nil {
+					logger.Warnf("Error invalidating cache key %v: %v", inv.Key, err)
+				}
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	cache "github.com/patrickmn/go-cache"
+)
+
+// NewMemoryBackend returns a backend which caches the responses in process
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		cache: cache.New(cache.NoExpiration, 30*time.Second),
+	}
+}
+
+// MemoryBackend caches responses in process, so each replica of a service has its own cache
+type MemoryBackend struct {
+	cache *cache.Cache
+}
+
+func (m *MemoryBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	v, ok := m.cache.Get(key)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return v.([]byte), nil
+}
+
+func (m *MemoryBackend) Set(ctx context.Context, key string, val []byte, expiry time.Duration) error {
+	m.cache.Set(key, val, expiry)
+	return nil
+}
+
+func (m *MemoryBackend) Delete(ctx context.Context, key string) error {
+	m.cache.Delete(key)
+	return nil
+}
+
+// List the key value pairs in the cache
+func (m *MemoryBackend) List() map[string]string {
+	items := m.cache.Items()
+
+	rsp := make(map[string]string, len(items))
+	for k, v := range items {
+		rsp[k] = string(v.Object.([]byte))
+	}
+
+	return rsp
+}
+
+func (m *MemoryBackend) String() string {
+	return "memory"
+}
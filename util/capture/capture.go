@@ -0,0 +1,256 @@
+// Package capture records the http exchanges made with the api for a route to HAR files in the
+// blob store, to debug issues with third party clients. Captures are started on demand and end
+// after a bounded duration. Credentials are removed from the headers, query strings and JSON bodies
+// recorded.
+//
+// Captures apply to every namespace served by the api so they're managed by admins of the micro
+// namespace. Each replica of the api writes the exchanges it handled to its own HAR file, which
+// are merged when the capture is read.
+package capture
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/util/auth/namespace"
+)
+
+var (
+	// DefaultDuration of a capture
+	DefaultDuration = time.Minute * 5
+	// MaxDuration of a capture, to stop a forgotten capture recording indefinitely
+	MaxDuration = time.Hour
+	// DefaultLimit is the number of exchanges each replica of the api records for a capture
+	DefaultLimit = 1000
+	// RefreshInterval is how often the api reloads the captures
+	RefreshInterval = time.Second * 10
+
+	// ErrNotFound is returned when a capture doesn't exist
+	ErrNotFound = errors.New("capture not found")
+
+	table  = "capture"
+	prefix = "capture/"
+)
+
+// Capture records the exchanges for a route until it expires
+type Capture struct {
+	ID string `json:"id"`
+	// Host to capture, all hosts are captured if blank
+	Host string `json:"host"`
+	// Route is the path prefix to capture
+	Route string `json:"route"`
+	// Limit is the number of exchanges each replica of the api records
+	Limit   int       `json:"limit"`
+	Account string    `json:"account"`
+	Created time.Time `json:"created"`
+	Expiry  time.Time `json:"expiry"`
+}
+
+// Expired returns true if the capture is no longer recording
+func (c *Capture) Expired() bool {
+	return time.Now().After(c.Expiry)
+}
+
+// Matches returns true if the request to the host and path should be captured
+func (c *Capture) Matches(host, path string) bool {
+	if len(c.Host) > 0 && !strings.EqualFold(c.Host, host) {
+		return false
+	}
+	return strings.HasPrefix(path, c.Route) && !c.Expired()
+}
+
+// Start a capture, the id, limit and expiry are set if blank
+func Start(c *Capture) error {
+	if len(c.Route) == 0 || !strings.HasPrefix(c.Route, "/") {
+		return errors.New("the route must be a path prefix, e.g. /users")
+	}
+	if len(c.ID) == 0 {
+		c.ID = strings.Split(uuid.New().String(), "-")[0]
+	}
+	if c.Limit <= 0 {
+		c.Limit = DefaultLimit
+	}
+	if c.Created.IsZero() {
+		c.Created = time.Now()
+	}
+	if c.Expiry.IsZero() {
+		c.Expiry = c.Created.Add(DefaultDuration)
+	}
+	if c.Expiry.Sub(c.Created) > MaxDuration {
+		return errors.New("captures can't last longer than " + MaxDuration.String())
+	}
+
+	return store.DefaultStore.Write(store.NewRecord(c.ID, c), store.WriteTo(namespace.DefaultNamespace, table))
+}
+
+// Stop a capture, the exchanges recorded can still be read
+func Stop(id string) error {
+	c, err := Get(id)
+	if err != nil {
+		return err
+	}
+	if c.Expired() {
+		return nil
+	}
+	c.Expiry = time.Now()
+	return store.DefaultStore.Write(store.NewRecord(c.ID, c), store.WriteTo(namespace.DefaultNamespace, table))
+}
+
+// Get a capture
+func Get(id string) (*Capture, error) {
+	recs, err := store.Read(id, store.ReadFrom(namespace.DefaultNamespace, table))
+	if err == store.ErrNotFound || len(recs) == 0 {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	var c Capture
+	if err := recs[0].Decode(&c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// List the captures, including those which have expired
+func List() ([]*Capture, error) {
+	recs, err := store.Read("", store.ReadPrefix(), store.ReadFrom(namespace.DefaultNamespace, table))
+	if err != nil && err != store.ErrNotFound {
+		return nil, err
+	}
+
+	res := make([]*Capture, 0, len(recs))
+	for _, r := range recs {
+		var c Capture
+		if err := r.Decode(&c); err != nil {
+			return nil, err
+		}
+		res = append(res, &c)
+	}
+	return res, nil
+}
+
+// Delete a capture and the exchanges recorded
+func Delete(id string) error {
+	keys, err := store.DefaultBlobStore.List(store.BlobListNamespace(namespace.DefaultNamespace), store.BlobListPrefix(prefix+id+"/"))
+	if err != nil && err != store.ErrNotFound {
+		return err
+	}
+	for _, k := range keys {
+		if err := store.DefaultBlobStore.Delete(k, store.BlobNamespace(namespace.DefaultNamespace)); err != nil && err != store.ErrNotFound {
+			return err
+		}
+	}
+
+	err = store.DefaultStore.Delete(id, store.DeleteFrom(namespace.DefaultNamespace, table))
+	if err != nil && err != store.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+// Read the exchanges recorded by every replica of the api for a capture
+func Read(id string) (*HAR, error) {
+	keys, err := store.DefaultBlobStore.List(store.BlobListNamespace(namespace.DefaultNamespace), store.BlobListPrefix(prefix+id+"/"))
+	if err != nil && err != store.ErrNotFound {
+		return nil, err
+	}
+
+	har := NewHAR()
+	for _, k := range keys {
+		r, err := store.DefaultBlobStore.Read(k, store.BlobNamespace(namespace.DefaultNamespace))
+		if err != nil {
+			return nil, err
+		}
+		var h HAR
+		if err := json.NewDecoder(r).Decode(&h); err != nil {
+			return nil, err
+		}
+		har.Log.Entries = append(har.Log.Entries, h.Log.Entries...)
+	}
+	har.Sort()
+	return har, nil
+}
+
+var (
+	mtx      sync.RWMutex
+	captures []*Capture
+	watching bool
+)
+
+// Match returns the capture the request to the host and path should be recorded by, if any. The
+// captures are loaded in the background the first time it's called, it never blocks on the store
+// since it's called for every request.
+func Match(host, path string) *Capture {
+	mtx.RLock()
+	cs, ok := captures, watching
+	mtx.RUnlock()
+
+	if !ok {
+		mtx.Lock()
+		if !watching {
+			watching = true
+			go watch()
+		}
+		mtx.Unlock()
+		return nil
+	}
+
+	for _, c := range cs {
+		if c.Matches(host, path) {
+			return c
+		}
+	}
+	return nil
+}
+
+// watch reloads the captures periodically
+func watch() {
+	t := time.NewTicker(RefreshInterval)
+	defer t.Stop()
+
+	for {
+		load()
+		<-t.C
+	}
+}
+
+func load() {
+	if store.DefaultStore == nil {
+		return
+	}
+	cs, err := List()
+	if err != nil {
+		logger.Debugf("Error loading captures: %v", err)
+		return
+	}
+
+	var active []*Capture
+	for _, c := range cs {
+		if !c.Expired() {
+			active = append(active, c)
+		}
+	}
+
+	mtx.Lock()
+	captures = active
+	mtx.Unlock()
+}
+
+// write the HAR to the blob store
+func write(key string, h *HAR) error {
+	b, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	return store.DefaultBlobStore.Write(key, bytes.NewReader(b),
+		store.BlobNamespace(namespace.DefaultNamespace),
+		store.BlobContentType("application/json"),
+	)
+}
@@ -0,0 +1,91 @@
+package capture
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/file"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEntry(t *testing.T) {
+	body := []byte(`{"email":"john@example.com","password":"hunter2"}`)
+	req := httptest.NewRequest("POST", "/users/create?token=abc&page=1", strings.NewReader(string(body)))
+	req.Header.Set("Authorization", "Bearer abc")
+	req.Header.Set("Content-Type", "application/json")
+
+	rsp := http.Header{}
+	rsp.Set("Content-Type", "application/json")
+	e := NewEntry(req, body, len(body), 200, rsp, []byte(`{"id":"1"}`), 10, time.Now(), time.Millisecond*5)
+
+	assert.Equal(t, "POST", e.Request.Method)
+	assert.Equal(t, float64(5), e.Time)
+	assert.NotContains(t, e.Request.URL, "abc")
+	assert.Equal(t, []*NameValue{{Name: "page", Value: "1"}, {Name: "token", Value: "[REDACTED]"}}, e.Request.QueryString)
+
+	for _, h := range e.Request.Headers {
+		if h.Name == "Authorization" {
+			assert.Equal(t, "[REDACTED]", h.Value)
+		}
+	}
+
+	assert.NotContains(t, e.Request.PostData.Text, "john@example.com")
+	assert.Contains(t, e.Request.PostData.Text, "{email}")
+	assert.NotContains(t, e.Request.PostData.Text, "hunter2")
+	assert.Equal(t, `{"id":"1"}`, e.Response.Content.Text)
+	assert.Empty(t, e.Response.Content.Comment)
+
+	// the fields of form bodies are scrubbed too
+	form := []byte(`username=john&password=hunter2`)
+	req = httptest.NewRequest("POST", "/login", strings.NewReader(string(form)))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	e = NewEntry(req, form, len(form), 200, rsp, nil, 0, time.Now(), time.Millisecond)
+	assert.NotContains(t, e.Request.PostData.Text, "hunter2")
+	assert.Contains(t, e.Request.PostData.Text, "username=john")
+}
+
+func TestCapture(t *testing.T) {
+	store.DefaultStore = memory.NewStore()
+	bs, err := file.NewBlobStore(file.WithDir(t.TempDir()))
+	assert.NoError(t, err)
+	store.DefaultBlobStore = bs
+
+	// captures must be for a path prefix
+	assert.Error(t, Start(&Capture{Route: "users"}))
+	assert.Error(t, Start(&Capture{Route: "/users", Expiry: time.Now().Add(MaxDuration * 2)}))
+
+	c := &Capture{Route: "/users"}
+	assert.NoError(t, Start(c))
+	assert.NotEmpty(t, c.ID)
+	assert.Equal(t, DefaultLimit, c.Limit)
+	assert.True(t, c.Matches("api.example.com", "/users/create"))
+	assert.False(t, c.Matches("api.example.com", "/posts"))
+
+	cs, err := List()
+	assert.NoError(t, err)
+	assert.Len(t, cs, 1)
+
+	c.Limit = 1
+	req := httptest.NewRequest("GET", "/users/read", nil)
+	Record(c, NewEntry(req, nil, 0, 200, http.Header{}, nil, 0, time.Now(), time.Millisecond))
+	Record(c, NewEntry(req, nil, 0, 200, http.Header{}, nil, 0, time.Now(), time.Millisecond))
+	flush()
+
+	har, err := Read(c.ID)
+	assert.NoError(t, err)
+	assert.Len(t, har.Log.Entries, 1)
+
+	assert.NoError(t, Stop(c.ID))
+	got, err := Get(c.ID)
+	assert.NoError(t, err)
+	assert.True(t, got.Expired())
+
+	assert.NoError(t, Delete(c.ID))
+	_, err = Get(c.ID)
+	assert.Equal(t, ErrNotFound, err)
+}
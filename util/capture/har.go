@@ -0,0 +1,240 @@
+package capture
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/micro/micro/v3/util/scrub"
+)
+
+var (
+	// MaxBodySize is the number of bytes of each request and response body recorded
+	MaxBodySize = 64 * 1024
+
+	// redacted replaces the values of credentials
+	redacted = "[REDACTED]"
+	// omitted replaces JSON bodies which couldn't be sanitized
+	omitted = "[OMITTED]"
+
+	// sensitiveHeaders are removed from the headers recorded
+	sensitiveHeaders = map[string]bool{
+		"authorization":       true,
+		"cookie":              true,
+		"proxy-authorization": true,
+		"set-cookie":          true,
+		"x-api-key":           true,
+		"micro-signature":     true,
+	}
+)
+
+// HAR is an HTTP archive, see http://www.softwareishard.com/blog/har-12-spec
+type HAR struct {
+	Log *Log `json:"log"`
+}
+
+// Log of the exchanges in the archive
+type Log struct {
+	Version string   `json:"version"`
+	Creator *Creator `json:"creator"`
+	Entries []*Entry `json:"entries"`
+}
+
+// Creator of the archive
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Entry is an exchange with the api
+type Entry struct {
+	StartedDateTime time.Time `json:"startedDateTime"`
+	Time            float64   `json:"time"`
+	Request         *Request  `json:"request"`
+	Response        *Response `json:"response"`
+	Cache           struct{}  `json:"cache"`
+	Timings         *Timings  `json:"timings"`
+	ServerIPAddress string    `json:"serverIPAddress,omitempty"`
+}
+
+// Request of an exchange
+type Request struct {
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	HTTPVersion string       `json:"httpVersion"`
+	Cookies     []*NameValue `json:"cookies"`
+	Headers     []*NameValue `json:"headers"`
+	QueryString []*NameValue `json:"queryString"`
+	PostData    *PostData    `json:"postData,omitempty"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+// Response of an exchange
+type Response struct {
+	Status      int          `json:"status"`
+	StatusText  string       `json:"statusText"`
+	HTTPVersion string       `json:"httpVersion"`
+	Cookies     []*NameValue `json:"cookies"`
+	Headers     []*NameValue `json:"headers"`
+	Content     *Content     `json:"content"`
+	RedirectURL string       `json:"redirectURL"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+// NameValue is a header, cookie or query string parameter
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// PostData is the body of a request
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// Content is the body of a response
+type Content struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// Timings of an exchange, only the time waiting for the response is known to the api
+type Timings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// NewHAR returns an archive without any entries
+func NewHAR() *HAR {
+	return &HAR{Log: &Log{
+		Version: "1.2",
+		Creator: &Creator{Name: "micro", Version: "v3"},
+		Entries: []*Entry{},
+	}}
+}
+
+// Sort the entries by the time they started
+func (h *HAR) Sort() {
+	sort.SliceStable(h.Log.Entries, func(i, j int) bool {
+		return h.Log.Entries[i].StartedDateTime.Before(h.Log.Entries[j].StartedDateTime)
+	})
+}
+
+// NewEntry returns the sanitized entry for an exchange. The bodies should be truncated to the
+// max body size, the sizes are the number of bytes actually sent.
+func NewEntry(r *http.Request, reqBody []byte, reqSize int, status int, header http.Header, rspBody []byte, rspSize int, start time.Time, d time.Duration) *Entry {
+	ms := float64(d) / float64(time.Millisecond)
+
+	u := *r.URL
+	u.Host = r.Host
+	u.Scheme = "http"
+	if r.TLS != nil {
+		u.Scheme = "https"
+	}
+	query := sanitizeQuery(u.Query())
+	u.RawQuery = query.Encode()
+
+	e := &Entry{
+		StartedDateTime: start,
+		Time:            ms,
+		Request: &Request{
+			Method:      r.Method,
+			URL:         u.String(),
+			HTTPVersion: r.Proto,
+			Cookies:     []*NameValue{},
+			Headers:     headers(r.Header),
+			QueryString: []*NameValue{},
+			HeadersSize: -1,
+			BodySize:    reqSize,
+		},
+		Response: &Response{
+			Status:      status,
+			StatusText:  http.StatusText(status),
+			HTTPVersion: r.Proto,
+			Cookies:     []*NameValue{},
+			Headers:     headers(header),
+			Content: &Content{
+				Size:     rspSize,
+				MimeType: header.Get("Content-Type"),
+				Text:     sanitizeBody(header.Get("Content-Type"), rspBody),
+			},
+			RedirectURL: header.Get("Location"),
+			HeadersSize: -1,
+			BodySize:    rspSize,
+		},
+		Timings: &Timings{Send: 0, Wait: ms, Receive: 0},
+	}
+
+	for k, vs := range query {
+		for _, v := range vs {
+			e.Request.QueryString = append(e.Request.QueryString, &NameValue{Name: k, Value: v})
+		}
+	}
+	sort.Slice(e.Request.QueryString, func(i, j int) bool {
+		return e.Request.QueryString[i].Name < e.Request.QueryString[j].Name
+	})
+
+	if reqSize > 0 {
+		e.Request.PostData = &PostData{
+			MimeType: r.Header.Get("Content-Type"),
+			Text:     sanitizeBody(r.Header.Get("Content-Type"), reqBody),
+		}
+	}
+	if rspSize > len(rspBody) {
+		e.Response.Content.Comment = "truncated"
+	}
+
+	return e
+}
+
+func headers(h http.Header) []*NameValue {
+	res := []*NameValue{}
+	for k, vs := range h {
+		for _, v := range vs {
+			if sensitiveHeaders[strings.ToLower(k)] {
+				v = redacted
+			}
+			res = append(res, &NameValue{Name: k, Value: v})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+func sanitizeQuery(q url.Values) url.Values {
+	for k, vs := range q {
+		if scrub.Field("", k) {
+			q[k] = []string{redacted}
+			continue
+		}
+		for i, v := range vs {
+			vs[i] = scrub.String("", v)
+		}
+	}
+	return q
+}
+
+// sanitizeBody scrubs the personal data and credentials of bodies. JSON and form bodies which
+// can't be parsed, e.g. because they were truncated, are omitted since their data can't be
+// removed.
+func sanitizeBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if !strings.Contains(contentType, "json") && !strings.Contains(contentType, "x-www-form-urlencoded") {
+		contentType = "text/plain"
+	}
+	b, err := scrub.Body("", contentType, body)
+	if err != nil {
+		return omitted
+	}
+	return b
+}
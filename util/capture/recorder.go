@@ -0,0 +1,93 @@
+package capture
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// FlushInterval is how often the exchanges recorded are written to the blob store
+	FlushInterval = time.Second * 10
+
+	// replica identifies the HAR file written by this replica of the api
+	replica = uuid.New().String()
+
+	recMtx     sync.Mutex
+	recordings = map[string]*recording{}
+	flushing   bool
+)
+
+// recording is the exchanges this replica recorded for a capture
+type recording struct {
+	capture *Capture
+	har     *HAR
+	dirty   bool
+}
+
+// Record an exchange for the capture. Exchanges beyond the limit of the capture are dropped. The
+// exchanges are written to the blob store in the background.
+func Record(c *Capture, e *Entry) {
+	recMtx.Lock()
+	defer recMtx.Unlock()
+
+	r, ok := recordings[c.ID]
+	if !ok {
+		r = &recording{capture: c, har: NewHAR()}
+		recordings[c.ID] = r
+	}
+	if len(r.har.Log.Entries) >= c.Limit {
+		return
+	}
+	r.har.Log.Entries = append(r.har.Log.Entries, e)
+	r.dirty = true
+
+	if !flushing {
+		flushing = true
+		go flushLoop()
+	}
+}
+
+func flushLoop() {
+	t := time.NewTicker(FlushInterval)
+	defer t.Stop()
+
+	for range t.C {
+		flush()
+	}
+}
+
+// flush writes the recordings with new exchanges to the blob store, and forgets the recordings of
+// captures which have expired once they've been written
+func flush() {
+	recMtx.Lock()
+	var pending []*recording
+	for id, r := range recordings {
+		if r.dirty {
+			// copy the entries so recording can continue while writing
+			har := NewHAR()
+			har.Log.Entries = append(har.Log.Entries, r.har.Log.Entries...)
+			pending = append(pending, &recording{capture: r.capture, har: har})
+			r.dirty = false
+		}
+		if r.capture.Expired() {
+			delete(recordings, id)
+		}
+	}
+	recMtx.Unlock()
+
+	for _, r := range pending {
+		if err := write(prefix+r.capture.ID+"/"+replica+".har", r.har); err != nil {
+			logger.Errorf("Error writing capture %v: %v", r.capture.ID, err)
+
+			// retry on the next flush
+			recMtx.Lock()
+			if rec, ok := recordings[r.capture.ID]; ok {
+				rec.dirty = true
+			}
+			recMtx.Unlock()
+		}
+	}
+}
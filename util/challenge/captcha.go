@@ -0,0 +1,87 @@
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// TurnstileURL is the siteverify endpoint of Cloudflare Turnstile
+	TurnstileURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+	// HCaptchaURL is the siteverify endpoint of hCaptcha
+	HCaptchaURL = "https://api.hcaptcha.com/siteverify"
+)
+
+// NewTurnstile returns a verifier which checks responses with Cloudflare Turnstile
+func NewTurnstile(siteKey, secret string) Verifier {
+	return &captcha{name: "turnstile", url: TurnstileURL, siteKey: siteKey, secret: secret}
+}
+
+// NewHCaptcha returns a verifier which checks responses with hCaptcha
+func NewHCaptcha(siteKey, secret string) Verifier {
+	return &captcha{name: "hcaptcha", url: HCaptchaURL, siteKey: siteKey, secret: secret}
+}
+
+// captcha verifies responses using the siteverify api shared by turnstile and hcaptcha
+type captcha struct {
+	name    string
+	url     string
+	siteKey string
+	secret  string
+}
+
+type siteVerifyResponse struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+var httpClient = &http.Client{Timeout: time.Second * 10}
+
+func (c *captcha) Issue(ip string) (*Challenge, error) {
+	return &Challenge{Type: c.name, SiteKey: c.siteKey}, nil
+}
+
+func (c *captcha) Verify(ctx context.Context, response, ip string) error {
+	if len(response) == 0 {
+		return ErrFailed
+	}
+
+	form := url.Values{"secret": {c.secret}, "response": {response}}
+	if len(ip) > 0 {
+		form.Set("remoteip", ip)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	rsp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error verifying %v response: %v", c.name, err)
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error verifying %v response: %v", c.name, rsp.Status)
+	}
+
+	var res siteVerifyResponse
+	if err := json.NewDecoder(rsp.Body).Decode(&res); err != nil {
+		return fmt.Errorf("error decoding %v response: %v", c.name, err)
+	}
+	if !res.Success {
+		return ErrFailed
+	}
+	return nil
+}
+
+func (c *captcha) String() string {
+	return c.name
+}
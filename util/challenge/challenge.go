@@ -0,0 +1,131 @@
+// Package challenge verifies clients are human, or at least willing to spend some cpu, before
+// they're allowed to continue making requests. Challenges are only issued to clients which have
+// been flagged, e.g. by the api when they overflow the rate limit, and a client which passes a
+// challenge is given a signed pass so it isn't challenged again until the pass expires.
+package challenge
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrFailed is returned when the response to a challenge is invalid
+	ErrFailed = errors.New("challenge failed")
+	// ErrInvalidPass is returned when a pass is malformed, expired or wasn't issued to the client
+	ErrInvalidPass = errors.New("invalid challenge pass")
+
+	// DefaultTracker tracks the clients flagged by the api
+	DefaultTracker = NewTracker()
+)
+
+// Challenge is issued to flagged clients which must respond to it before continuing
+type Challenge struct {
+	// Type of the challenge, e.g. turnstile
+	Type string `json:"type"`
+	// SiteKey used by the client to render a captcha widget
+	SiteKey string `json:"site_key,omitempty"`
+	// Nonce which must be solved for proof of work challenges
+	Nonce string `json:"nonce,omitempty"`
+	// Difficulty is the number of leading zero bits required in proof of work solutions
+	Difficulty int `json:"difficulty,omitempty"`
+}
+
+// Verifier issues challenges and verifies the responses to them
+type Verifier interface {
+	// Issue a challenge to the client with the ip provided
+	Issue(ip string) (*Challenge, error)
+	// Verify the response of the client with the ip provided
+	Verify(ctx context.Context, response, ip string) error
+	// String returns the type of challenge
+	String() string
+}
+
+// Tracker records the clients which should be challenged
+type Tracker struct {
+	sync.RWMutex
+	flagged map[string]time.Time
+}
+
+// NewTracker returns an empty tracker
+func NewTracker() *Tracker {
+	return &Tracker{flagged: make(map[string]time.Time)}
+}
+
+// Flag the client so it's challenged for the duration provided
+func (t *Tracker) Flag(key string, d time.Duration) {
+	t.Lock()
+	defer t.Unlock()
+
+	now := time.Now()
+	for k, exp := range t.flagged {
+		if now.After(exp) {
+			delete(t.flagged, k)
+		}
+	}
+
+	if exp := now.Add(d); exp.After(t.flagged[key]) {
+		t.flagged[key] = exp
+	}
+}
+
+// Flagged returns true if the client should be challenged
+func (t *Tracker) Flagged(key string) bool {
+	t.RLock()
+	defer t.RUnlock()
+	exp, ok := t.flagged[key]
+	return ok && time.Now().Before(exp)
+}
+
+// Clear the flag of a client, e.g. once it has passed a challenge
+func (t *Tracker) Clear(key string) {
+	t.Lock()
+	defer t.Unlock()
+	delete(t.flagged, key)
+}
+
+// Flag the client using the default tracker
+func Flag(key string, d time.Duration) {
+	DefaultTracker.Flag(key, d)
+}
+
+// Flagged checks the client using the default tracker
+func Flagged(key string) bool {
+	return DefaultTracker.Flagged(key)
+}
+
+// Pass returns a pass for the client with the ip provided, signed with the secret. The pass is
+// in the form <unix expiry>.<signature>.
+func Pass(secret, ip string, expiry time.Time) string {
+	exp := strconv.FormatInt(expiry.Unix(), 10)
+	return exp + "." + sign(secret, exp+"."+ip)
+}
+
+// VerifyPass checks the pass was signed with the secret for the client and hasn't expired
+func VerifyPass(secret, pass, ip string) error {
+	parts := strings.SplitN(pass, ".", 2)
+	if len(parts) != 2 {
+		return ErrInvalidPass
+	}
+	exp, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || time.Now().After(time.Unix(exp, 0)) {
+		return ErrInvalidPass
+	}
+	if !hmac.Equal([]byte(parts[1]), []byte(sign(secret, parts[0]+"."+ip))) {
+		return ErrInvalidPass
+	}
+	return nil
+}
+
+func sign(secret, value string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
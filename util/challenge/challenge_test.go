@@ -0,0 +1,78 @@
+package challenge
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker(t *testing.T) {
+	tr := NewTracker()
+	assert.False(t, tr.Flagged("10.0.0.1"))
+
+	tr.Flag("10.0.0.1", time.Minute)
+	assert.True(t, tr.Flagged("10.0.0.1"))
+	assert.False(t, tr.Flagged("10.0.0.2"))
+
+	// a shorter flag doesn't reduce the existing one
+	tr.Flag("10.0.0.1", time.Nanosecond)
+	assert.True(t, tr.Flagged("10.0.0.1"))
+
+	tr.Clear("10.0.0.1")
+	assert.False(t, tr.Flagged("10.0.0.1"))
+}
+
+func TestPass(t *testing.T) {
+	pass := Pass("secret", "10.0.0.1", time.Now().Add(time.Minute))
+	assert.NoError(t, VerifyPass("secret", pass, "10.0.0.1"))
+	assert.Equal(t, ErrInvalidPass, VerifyPass("secret", pass, "10.0.0.2"))
+	assert.Equal(t, ErrInvalidPass, VerifyPass("other", pass, "10.0.0.1"))
+	assert.Equal(t, ErrInvalidPass, VerifyPass("secret", "foo", "10.0.0.1"))
+
+	expired := Pass("secret", "10.0.0.1", time.Now().Add(-time.Minute))
+	assert.Equal(t, ErrInvalidPass, VerifyPass("secret", expired, "10.0.0.1"))
+}
+
+func TestProofOfWork(t *testing.T) {
+	v := NewProofOfWork("secret", 8)
+	c, err := v.Issue("10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "pow", c.Type)
+	assert.Equal(t, 8, c.Difficulty)
+
+	rsp := Solve(c)
+	assert.NoError(t, v.Verify(context.TODO(), rsp, "10.0.0.1"))
+
+	// the nonce was issued to another client
+	assert.Equal(t, ErrFailed, v.Verify(context.TODO(), rsp, "10.0.0.2"))
+	// the nonce wasn't signed with the secret
+	assert.Equal(t, ErrFailed, NewProofOfWork("other", 8).Verify(context.TODO(), rsp, "10.0.0.1"))
+	// the solution doesn't meet the difficulty
+	assert.Equal(t, ErrFailed, NewProofOfWork("secret", 64).Verify(context.TODO(), rsp, "10.0.0.1"))
+	assert.Equal(t, ErrFailed, v.Verify(context.TODO(), "foo", "10.0.0.1"))
+}
+
+func TestCaptcha(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("secret") == "secret" && r.Form.Get("response") == "valid" && r.Form.Get("remoteip") == "10.0.0.1" {
+			w.Write([]byte(`{"success":true}`))
+			return
+		}
+		w.Write([]byte(`{"success":false,"error-codes":["invalid-input-response"]}`))
+	}))
+	defer srv.Close()
+
+	v := &captcha{name: "turnstile", url: srv.URL, siteKey: "site", secret: "secret"}
+	c, err := v.Issue("10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, &Challenge{Type: "turnstile", SiteKey: "site"}, c)
+
+	assert.NoError(t, v.Verify(context.TODO(), "valid", "10.0.0.1"))
+	assert.Equal(t, ErrFailed, v.Verify(context.TODO(), "invalid", "10.0.0.1"))
+	assert.Equal(t, ErrFailed, v.Verify(context.TODO(), "", "10.0.0.1"))
+}
@@ -0,0 +1,100 @@
+package challenge
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// DefaultDifficulty of proof of work challenges, a client needs around 2^20 hashes to solve one
+	DefaultDifficulty = 20
+	// DefaultNonceExpiry is how long a client has to solve a proof of work challenge
+	DefaultNonceExpiry = time.Minute * 5
+)
+
+// NewProofOfWork returns a verifier which requires clients to find a solution where the sha256
+// of "<nonce>:<solution>" has the number of leading zero bits set by the difficulty. It doesn't
+// depend on a third party so can be used as a fallback when no captcha provider is configured.
+// Nonces are signed with the secret so no state is shared between replicas of the api.
+func NewProofOfWork(secret string, difficulty int) Verifier {
+	if difficulty <= 0 {
+		difficulty = DefaultDifficulty
+	}
+	return &proofOfWork{secret: secret, difficulty: difficulty}
+}
+
+type proofOfWork struct {
+	secret     string
+	difficulty int
+}
+
+func (p *proofOfWork) Issue(ip string) (*Challenge, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	// the nonce is in the form <random>.<unix expiry>.<signature>
+	nonce := hex.EncodeToString(b) + "." + strconv.FormatInt(time.Now().Add(DefaultNonceExpiry).Unix(), 10)
+	nonce += "." + sign(p.secret, nonce+"."+ip)
+
+	return &Challenge{Type: p.String(), Nonce: nonce, Difficulty: p.difficulty}, nil
+}
+
+// Verify the response, which is in the form <nonce>:<solution>
+func (p *proofOfWork) Verify(ctx context.Context, response, ip string) error {
+	idx := strings.LastIndex(response, ":")
+	if idx < 0 {
+		return ErrFailed
+	}
+	nonce := response[:idx]
+
+	parts := strings.Split(nonce, ".")
+	if len(parts) != 3 {
+		return ErrFailed
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(sign(p.secret, parts[0]+"."+parts[1]+"."+ip))) {
+		return ErrFailed
+	}
+	exp, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().After(time.Unix(exp, 0)) {
+		return ErrFailed
+	}
+
+	if LeadingZeros(sha256.Sum256([]byte(response))) < p.difficulty {
+		return ErrFailed
+	}
+	return nil
+}
+
+func (p *proofOfWork) String() string {
+	return "pow"
+}
+
+// Solve a proof of work challenge, returning the response to send to the api
+func Solve(c *Challenge) string {
+	for i := 0; ; i++ {
+		rsp := c.Nonce + ":" + strconv.Itoa(i)
+		if LeadingZeros(sha256.Sum256([]byte(rsp))) >= c.Difficulty {
+			return rsp
+		}
+	}
+}
+
+// LeadingZeros returns the number of leading zero bits in the hash
+func LeadingZeros(hash [sha256.Size]byte) int {
+	var n int
+	for _, b := range hash {
+		n += bits.LeadingZeros8(b)
+		if b != 0 {
+			break
+		}
+	}
+	return n
+}
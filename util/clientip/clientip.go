@@ -0,0 +1,151 @@
+// Package clientip determines the ip a request was made from when it passes through proxies, e.g.
+// a load balancer in front of the api
+package clientip
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/micro/micro/v3/service/context/metadata"
+)
+
+const (
+	// MetadataKey is the metadata key the client ip is set in by the api and web, it's always
+	// overwritten so it can't be set by the client
+	MetadataKey = "Micro-Client-Ip"
+)
+
+var (
+	// DefaultTrusted are the proxies trusted to set the forwarding headers. By default no proxies
+	// are trusted so the client ip is the address the request was received from.
+	DefaultTrusted []*net.IPNet
+	// DefaultHeader is the forwarding header the trusted proxies set, either X-Forwarded-For or
+	// Forwarded. Only this header is read, a proxy which appends to one header passes the other
+	// through from the client unchanged.
+	DefaultHeader = "X-Forwarded-For"
+)
+
+// ParseTrusted parses a list of CIDRs or ips, e.g. 10.0.0.0/8 or 10.1.2.3
+func ParseTrusted(list []string) ([]*net.IPNet, error) {
+	var res []*net.IPNet
+	for _, s := range list {
+		s = strings.TrimSpace(s)
+		if len(s) == 0 {
+			continue
+		}
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, &net.ParseError{Type: "IP address", Text: s}
+			}
+			bits := 32
+			if ip.To4() == nil {
+				bits = 128
+			}
+			res = append(res, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, n)
+	}
+	return res, nil
+}
+
+// FromRequest returns the ip of the client which made the request. The forwarding header is only
+// used if the request was received from a trusted proxy, and is read from right to left
+// skipping the trusted proxies since the addresses to the left can be set by the client.
+func FromRequest(r *http.Request, trusted []*net.IPNet) string {
+	ip := host(r.RemoteAddr)
+	if !isTrusted(ip, trusted) {
+		return ip
+	}
+
+	chain := forwarded(r.Header)
+	for i := len(chain) - 1; i >= 0; i-- {
+		if !isTrusted(chain[i], trusted) {
+			return chain[i]
+		}
+	}
+	// every address is a trusted proxy so the leftmost is the closest we have to the client
+	if len(chain) > 0 {
+		return chain[0]
+	}
+	return ip
+}
+
+// FromContext returns the client ip set by the api or web, falling back to the address the
+// request was received from if the request didn't come through either
+func FromContext(ctx context.Context) (string, bool) {
+	if ip, ok := metadata.Get(ctx, MetadataKey); ok && len(ip) > 0 {
+		return ip, true
+	}
+	return RemoteFromContext(ctx)
+}
+
+// RemoteFromContext returns the address the request was received from, ignoring the client ip set
+// by the api or web, e.g. because the caller isn't trusted to set it
+func RemoteFromContext(ctx context.Context) (string, bool) {
+	if addr, ok := metadata.Get(ctx, "Remote"); ok && len(addr) > 0 {
+		return host(addr), true
+	}
+	return "", false
+}
+
+// forwarded returns the addresses the request was forwarded for in the default header, in the
+// order the proxies added them
+func forwarded(h http.Header) []string {
+	var chain []string
+	if strings.EqualFold(DefaultHeader, "Forwarded") {
+		for _, v := range h.Values("Forwarded") {
+			for _, elem := range strings.Split(v, ",") {
+				for _, pair := range strings.Split(elem, ";") {
+					kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+					if len(kv) != 2 || !strings.EqualFold(kv[0], "for") {
+						continue
+					}
+					// e.g. for="[2001:db8::1]:4711" or for=192.0.2.60
+					addr := strings.Trim(kv[1], `"`)
+					if ip := host(addr); net.ParseIP(ip) != nil {
+						chain = append(chain, ip)
+					}
+				}
+			}
+		}
+		return chain
+	}
+
+	for _, v := range h.Values(DefaultHeader) {
+		for _, addr := range strings.Split(v, ",") {
+			if ip := host(strings.TrimSpace(addr)); net.ParseIP(ip) != nil {
+				chain = append(chain, ip)
+			}
+		}
+	}
+	return chain
+}
+
+// host strips the port and brackets from an address
+func host(addr string) string {
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		return h
+	}
+	return strings.Trim(addr, "[]")
+}
+
+func isTrusted(ip string, trusted []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, n := range trusted {
+		if n.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package clientip
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromRequest(t *testing.T) {
+	trusted, err := ParseTrusted([]string{"10.0.0.0/8", "192.168.1.1", "fd00::/8"})
+	assert.NoError(t, err)
+
+	_, err = ParseTrusted([]string{"foo"})
+	assert.Error(t, err)
+
+	tt := []struct {
+		Name            string
+		Remote          string
+		Header          http.Header
+		ForwardedHeader string
+		ClientIP        string
+	}{
+		{
+			Name:     "NoProxy",
+			Remote:   "1.2.3.4:5678",
+			ClientIP: "1.2.3.4",
+		},
+		{
+			Name:     "UntrustedProxy",
+			Remote:   "1.2.3.4:5678",
+			Header:   http.Header{"X-Forwarded-For": {"5.6.7.8"}},
+			ClientIP: "1.2.3.4",
+		},
+		{
+			Name:     "TrustedProxy",
+			Remote:   "10.0.0.1:5678",
+			Header:   http.Header{"X-Forwarded-For": {"5.6.7.8"}},
+			ClientIP: "5.6.7.8",
+		},
+		{
+			Name:     "SpoofedHeader",
+			Remote:   "10.0.0.1:5678",
+			Header:   http.Header{"X-Forwarded-For": {"9.9.9.9, 5.6.7.8, 192.168.1.1"}},
+			ClientIP: "5.6.7.8",
+		},
+		{
+			Name:     "MultipleHeaders",
+			Remote:   "10.0.0.1:5678",
+			Header:   http.Header{"X-Forwarded-For": {"9.9.9.9", "5.6.7.8"}},
+			ClientIP: "5.6.7.8",
+		},
+		{
+			Name:     "AllTrusted",
+			Remote:   "10.0.0.1:5678",
+			Header:   http.Header{"X-Forwarded-For": {"10.0.0.3, 10.0.0.2"}},
+			ClientIP: "10.0.0.3",
+		},
+		{
+			// the proxy appends to X-Forwarded-For and passes the client's Forwarded through
+			Name:   "SpoofedForwarded",
+			Remote: "10.0.0.1:5678",
+			Header: http.Header{
+				"Forwarded":       {"for=6.6.6.6"},
+				"X-Forwarded-For": {"5.6.7.8"},
+			},
+			ClientIP: "5.6.7.8",
+		},
+		{
+			Name:   "Forwarded",
+			Remote: "10.0.0.1:5678",
+			Header: http.Header{
+				"Forwarded":       {`for="[2001:db8::1]:4711";proto=https, for=10.0.0.2`},
+				"X-Forwarded-For": {"6.6.6.6"},
+			},
+			ForwardedHeader: "Forwarded",
+			ClientIP:        "2001:db8::1",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			if len(tc.ForwardedHeader) > 0 {
+				DefaultHeader = tc.ForwardedHeader
+				defer func() { DefaultHeader = "X-Forwarded-For" }()
+			}
+
+			r := &http.Request{RemoteAddr: tc.Remote, Header: tc.Header}
+			if r.Header == nil {
+				r.Header = http.Header{}
+			}
+			assert.Equal(t, tc.ClientIP, FromRequest(r, trusted))
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	_, ok := FromContext(context.TODO())
+	assert.False(t, ok)
+
+	ctx := metadata.NewContext(context.TODO(), metadata.Metadata{"Remote": "1.2.3.4:5678"})
+	ip, ok := FromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "1.2.3.4", ip)
+
+	ctx = metadata.NewContext(context.TODO(), metadata.Metadata{"micro-client-ip": "5.6.7.8", "Remote": "1.2.3.4:5678"})
+	ip, ok = FromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "5.6.7.8", ip)
+}
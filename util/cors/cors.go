@@ -0,0 +1,303 @@
+// Package cors sets the CORS headers of the responses of the api gateway by the policy of the
+// namespace and route requested. The policies are loaded from the config service at runtime so
+// they can be changed without redeploying, e.g.
+//
+//	micro config set cors '{"default": {"allowed_origins": ["https://*.example.com"], "allow_credentials": true}, "namespaces": {"foo": {"allowed_origins": ["https://foo.com"], "max_age": 600}}, "routes": [{"route": "/public/", "policy": {"allowed_origins": ["*"]}}]}'
+//
+// The policy of the first route matching the request is used, then the policy of its namespace,
+// then the default policy. Until a policy is set any origin is allowed, as it was before policies
+// could be set.
+package cors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/api/handler/grpcweb"
+	"github.com/micro/micro/v3/service/config"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// ConfigPath is the path of the policies in the config service
+	ConfigPath = "cors"
+	// RefreshInterval is how often the policies are reloaded from the config service
+	RefreshInterval = time.Minute
+
+	// DefaultMethods are allowed if a policy doesn't set the methods
+	DefaultMethods = []string{"POST", "PATCH", "GET", "OPTIONS", "PUT", "DELETE"}
+	// DefaultHeaders are allowed if a policy doesn't set the headers
+	DefaultHeaders = append([]string{"Accept", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "Micro-Namespace"}, grpcweb.AllowedHeaders...)
+	// DefaultExposedHeaders are exposed if a policy doesn't set the exposed headers, gRPC-Web
+	// clients read the status of the call from the response headers
+	DefaultExposedHeaders = grpcweb.ExposedHeaders
+
+	// DefaultPolicy is used until a policy is set, it allows any origin
+	DefaultPolicy = &Policy{
+		AllowedOrigins:   []string{"*"},
+		AllowCredentials: true,
+	}
+)
+
+// Policy of the requests allowed from other origins
+type Policy struct {
+	// AllowedOrigins e.g. https://example.com, a "*" matches any origin or part of one, e.g.
+	// https://*.example.com. No origins are allowed if empty.
+	AllowedOrigins []string `json:"allowed_origins,omitempty"`
+	// AllowedMethods defaults to DefaultMethods, a "*" allows any method
+	AllowedMethods []string `json:"allowed_methods,omitempty"`
+	// AllowedHeaders defaults to DefaultHeaders, a "*" allows any header
+	AllowedHeaders []string `json:"allowed_headers,omitempty"`
+	// ExposedHeaders defaults to DefaultExposedHeaders
+	ExposedHeaders []string `json:"exposed_headers,omitempty"`
+	// AllowCredentials allows cookies and the authorization header to be sent
+	AllowCredentials bool `json:"allow_credentials,omitempty"`
+	// MaxAge is the number of seconds a preflight request can be cached for
+	MaxAge int `json:"max_age,omitempty"`
+}
+
+// Route sets the policy of the requests to a route
+type Route struct {
+	// Namespace to match, all namespaces match if blank
+	Namespace string `json:"namespace,omitempty"`
+	// Host to match, all hosts match if blank
+	Host string `json:"host,omitempty"`
+	// Route is the path prefix to match
+	Route  string  `json:"route"`
+	Policy *Policy `json:"policy"`
+}
+
+// Config of the policies
+type Config struct {
+	// Default policy of every namespace
+	Default *Policy `json:"default,omitempty"`
+	// Namespaces policies keyed by namespace
+	Namespaces map[string]*Policy `json:"namespaces,omitempty"`
+	// Routes policies, the first matching a request is used
+	Routes []*Route `json:"routes,omitempty"`
+}
+
+// Validate the config
+func (c *Config) Validate() error {
+	for i, r := range c.Routes {
+		if len(r.Route) == 0 || !strings.HasPrefix(r.Route, "/") {
+			return fmt.Errorf("invalid route %v, the route must be a path prefix, e.g. /users", i)
+		}
+		if r.Policy == nil {
+			return fmt.Errorf("missing policy of route %v", r.Route)
+		}
+	}
+	policies := []*Policy{c.Default}
+	for _, p := range c.Namespaces {
+		policies = append(policies, p)
+	}
+	for _, r := range c.Routes {
+		policies = append(policies, r.Policy)
+	}
+	for _, p := range policies {
+		if p != nil && p.MaxAge < 0 {
+			return errors.New("the max age can't be negative")
+		}
+	}
+	return nil
+}
+
+// Policy returns the policy of the request to the namespace
+func (c *Config) Policy(ns, host, path string) *Policy {
+	for _, r := range c.Routes {
+		if len(r.Namespace) > 0 && r.Namespace != ns {
+			continue
+		}
+		if len(r.Host) > 0 && !strings.EqualFold(r.Host, host) {
+			continue
+		}
+		if strings.HasPrefix(path, r.Route) {
+			return r.Policy
+		}
+	}
+	if p, ok := c.Namespaces[ns]; ok && p != nil {
+		return p
+	}
+	if c.Default != nil {
+		return c.Default
+	}
+	return DefaultPolicy
+}
+
+// AllowOrigin returns true if requests from the origin are allowed
+func (p *Policy) AllowOrigin(origin string) bool {
+	for _, o := range p.AllowedOrigins {
+		if match(o, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetHeaders sets the CORS headers of the response to the request, returning false if the origin
+// of the request isn't allowed
+func (p *Policy) SetHeaders(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	h := w.Header()
+	h.Add("Vary", "Origin")
+	if len(origin) == 0 || !p.AllowOrigin(origin) {
+		return false
+	}
+
+	// the origin must be returned rather than a wildcard if credentials are allowed
+	if !p.AllowCredentials && contains(p.AllowedOrigins, "*") {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+	}
+	if p.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	exposed := p.ExposedHeaders
+	if exposed == nil {
+		exposed = DefaultExposedHeaders
+	}
+	if len(exposed) > 0 {
+		h.Set("Access-Control-Expose-Headers", strings.Join(exposed, ", "))
+	}
+
+	if !IsPreflight(r) {
+		return true
+	}
+
+	methods := p.AllowedMethods
+	if len(methods) == 0 {
+		methods = DefaultMethods
+	}
+	if contains(methods, "*") {
+		h.Set("Access-Control-Allow-Methods", r.Header.Get("Access-Control-Request-Method"))
+	} else {
+		h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+	}
+
+	headers := p.AllowedHeaders
+	if len(headers) == 0 {
+		headers = DefaultHeaders
+	}
+	if contains(headers, "*") {
+		if req := r.Header.Get("Access-Control-Request-Headers"); len(req) > 0 {
+			h.Set("Access-Control-Allow-Headers", req)
+		}
+	} else {
+		h.Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+	}
+
+	if p.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(p.MaxAge))
+	}
+	return true
+}
+
+// IsPreflight returns true if the request is a CORS preflight request
+func IsPreflight(r *http.Request) bool {
+	return r.Method == "OPTIONS" && len(r.Header.Get("Access-Control-Request-Method")) > 0
+}
+
+// match returns true if the origin matches the pattern, a "*" in the pattern matches any part of
+// the origin
+func match(pattern, origin string) bool {
+	pattern, origin = strings.ToLower(pattern), strings.ToLower(origin)
+	i := strings.Index(pattern, "*")
+	if i < 0 {
+		return pattern == origin
+	}
+	prefix, suffix := pattern[:i], pattern[i+1:]
+	return len(origin) >= len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Policies of the requests to the api, loaded from the config service
+type Policies struct {
+	sync.Mutex
+	config  *Config
+	loaded  time.Time
+	loading bool
+
+	// load the config, replaced in tests
+	load func() (*Config, error)
+}
+
+// New returns the policies loaded from the config service
+func New() *Policies {
+	return &Policies{load: loadConfig}
+}
+
+// Policy returns the policy of the request to the namespace. Until the policies are first loaded
+// the default policy is used.
+func (p *Policies) Policy(ns, host, path string) *Policy {
+	c := p.refresh()
+	if c == nil {
+		return DefaultPolicy
+	}
+	return c.Policy(ns, host, path)
+}
+
+// refresh returns the config, reloading it in the background if it's stale
+func (p *Policies) refresh() *Config {
+	p.Lock()
+	defer p.Unlock()
+
+	if time.Since(p.loaded) < RefreshInterval || p.loading {
+		return p.config
+	}
+	p.loading = true
+
+	go func() {
+		c, err := p.load()
+
+		p.Lock()
+		defer p.Unlock()
+		p.loading = false
+		p.loaded = time.Now()
+		if err != nil {
+			// keep the last policies loaded
+			logger.Warnf("Error loading cors policies: %v", err)
+			return
+		}
+		p.config = c
+	}()
+
+	return p.config
+}
+
+func loadConfig() (*Config, error) {
+	if config.DefaultConfig == nil {
+		return nil, nil
+	}
+	val, err := config.Get(ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	if !val.Exists() {
+		return nil, nil
+	}
+	var c *Config
+	if err := val.Scan(&c); err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, nil
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,114 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func request(method, origin string) *http.Request {
+	r := httptest.NewRequest(method, "/users/read", nil)
+	if len(origin) > 0 {
+		r.Header.Set("Origin", origin)
+	}
+	return r
+}
+
+func TestMatch(t *testing.T) {
+	assert.True(t, match("*", "https://example.com"))
+	assert.True(t, match("https://*.example.com", "https://api.EXAMPLE.com"))
+	assert.False(t, match("https://*.example.com", "https://example.com"))
+	assert.False(t, match("https://*.example.com", "https://evilexample.com"))
+	assert.True(t, match("https://example.com", "https://example.com"))
+	assert.False(t, match("https://example.com", "https://example.com.evil.com"))
+}
+
+func TestSetHeaders(t *testing.T) {
+	p := &Policy{
+		AllowedOrigins: []string{"https://*.example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		MaxAge:         600,
+	}
+
+	// requests from origins which aren't allowed don't get the headers
+	w := httptest.NewRecorder()
+	assert.False(t, p.SetHeaders(w, request("GET", "https://evil.com")))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "Origin", w.Header().Get("Vary"))
+
+	w = httptest.NewRecorder()
+	assert.True(t, p.SetHeaders(w, request("GET", "https://app.example.com")))
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Contains(t, w.Header().Get("Access-Control-Expose-Headers"), "Grpc-Status")
+
+	// preflight requests get the allowed methods and headers
+	r := request("OPTIONS", "https://app.example.com")
+	r.Header.Set("Access-Control-Request-Method", "POST")
+	w = httptest.NewRecorder()
+	assert.True(t, p.SetHeaders(w, r))
+	assert.Equal(t, "GET, POST", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+
+	// wildcards allow anything requested
+	any := &Policy{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"*"}, AllowedHeaders: []string{"*"}, ExposedHeaders: []string{}}
+	r = request("OPTIONS", "https://other.com")
+	r.Header.Set("Access-Control-Request-Method", "PURGE")
+	r.Header.Set("Access-Control-Request-Headers", "X-Custom")
+	w = httptest.NewRecorder()
+	assert.True(t, any.SetHeaders(w, r))
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "PURGE", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "X-Custom", w.Header().Get("Access-Control-Allow-Headers"))
+	assert.Empty(t, w.Header().Get("Access-Control-Expose-Headers"))
+
+	// the origin is returned rather than a wildcard if credentials are allowed
+	w = httptest.NewRecorder()
+	assert.True(t, DefaultPolicy.SetHeaders(w, request("GET", "https://other.com")))
+	assert.Equal(t, "https://other.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestPolicy(t *testing.T) {
+	public := &Policy{AllowedOrigins: []string{"*"}}
+	foo := &Policy{AllowedOrigins: []string{"https://foo.com"}}
+	def := &Policy{AllowedOrigins: []string{"https://example.com"}}
+	c := &Config{
+		Default:    def,
+		Namespaces: map[string]*Policy{"foo": foo},
+		Routes: []*Route{
+			{Namespace: "bar", Route: "/public/", Policy: public},
+			{Host: "api.example.com", Route: "/", Policy: public},
+		},
+	}
+	assert.NoError(t, c.Validate())
+
+	assert.Equal(t, public, c.Policy("bar", "localhost", "/public/read"))
+	assert.Equal(t, def, c.Policy("bar", "localhost", "/users/read"))
+	assert.Equal(t, foo, c.Policy("foo", "localhost", "/public/read"))
+	assert.Equal(t, public, c.Policy("foo", "API.example.com", "/users/read"))
+	assert.Equal(t, DefaultPolicy, (&Config{}).Policy("foo", "localhost", "/"))
+
+	assert.Error(t, (&Config{Routes: []*Route{{Route: "public", Policy: public}}}).Validate())
+	assert.Error(t, (&Config{Routes: []*Route{{Route: "/public"}}}).Validate())
+	assert.Error(t, (&Config{Default: &Policy{MaxAge: -1}}).Validate())
+}
+
+func TestRefresh(t *testing.T) {
+	p := New()
+	def := &Policy{AllowedOrigins: []string{"https://example.com"}}
+	p.load = func() (*Config, error) {
+		return &Config{Default: def}, nil
+	}
+
+	// the default policy is used until the policies are first loaded
+	assert.Equal(t, DefaultPolicy, p.Policy("micro", "", "/"))
+	assert.Eventually(t, func() bool {
+		return p.Policy("micro", "", "/") == def
+	}, time.Second, time.Millisecond*10)
+}
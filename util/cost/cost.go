@@ -0,0 +1,151 @@
+// Package cost attributes the work done while serving a request, e.g. store reads and calls to
+// other services, to the request so expensive callers can be found
+package cost
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Header is the header the cost of a request is returned in when debugging
+	Header = "Micro-Cost"
+
+	// the kinds of operation recorded
+	Client = "client"
+	Store  = "store"
+	Broker = "broker"
+)
+
+type costKey struct{}
+
+// Operation is the number and total duration of an operation
+type Operation struct {
+	Count    int
+	Duration time.Duration
+}
+
+// Cost of a request, safe for concurrent use since handlers may do work in goroutines
+type Cost struct {
+	sync.Mutex
+	ops map[string]*Operation
+}
+
+// NewContext returns a context which the cost of the operations performed with it is recorded in
+func NewContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, costKey{}, &Cost{ops: map[string]*Operation{}})
+}
+
+// FromContext returns the cost recorded in the context
+func FromContext(ctx context.Context) (*Cost, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	c, ok := ctx.Value(costKey{}).(*Cost)
+	return c, ok
+}
+
+// Record an operation which started at the time provided against the request the context belongs
+// to. It's a noop if the context isn't recording the cost. It's intended to be deferred, e.g.
+// defer cost.Record(ctx, cost.Store, time.Now()).
+func Record(ctx context.Context, op string, start time.Time) {
+	c, ok := FromContext(ctx)
+	if !ok {
+		return
+	}
+	c.add(op, 1, time.Since(start))
+}
+
+// Merge the cost returned by another service in its header, the work it did was triggered by this
+// request so it's attributed to it
+func Merge(ctx context.Context, header string) {
+	c, ok := FromContext(ctx)
+	if !ok || len(header) == 0 {
+		return
+	}
+	for op, o := range Parse(header) {
+		c.add(op, o.Count, o.Duration)
+	}
+}
+
+func (c *Cost) add(op string, count int, d time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+
+	o, ok := c.ops[op]
+	if !ok {
+		o = &Operation{}
+		c.ops[op] = o
+	}
+	o.Count += count
+	o.Duration += d
+}
+
+// Operations returns a copy of the operations recorded
+func (c *Cost) Operations() map[string]Operation {
+	c.Lock()
+	defer c.Unlock()
+
+	ops := make(map[string]Operation, len(c.ops))
+	for k, v := range c.ops {
+		ops[k] = *v
+	}
+	return ops
+}
+
+// Metadata returns the cost as span metadata, e.g. cost.store.count=2 and cost.store.duration=3ms
+func (c *Cost) Metadata() map[string]string {
+	md := map[string]string{}
+	for op, o := range c.Operations() {
+		md["cost."+op+".count"] = strconv.Itoa(o.Count)
+		md["cost."+op+".duration"] = o.Duration.String()
+	}
+	return md
+}
+
+// String returns the cost in the format of the header, based on Server-Timing, e.g.
+// "client;count=1;dur=12.5, store;count=2;dur=3.1" where the duration is in milliseconds
+func (c *Cost) String() string {
+	ops := c.Operations()
+	names := make([]string, 0, len(ops))
+	for op := range ops {
+		names = append(names, op)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, op := range names {
+		ms := float64(ops[op].Duration) / float64(time.Millisecond)
+		parts = append(parts, fmt.Sprintf("%v;count=%d;dur=%.1f", op, ops[op].Count, ms))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Parse a header written using String. Malformed operations are ignored.
+func Parse(header string) map[string]Operation {
+	ops := map[string]Operation{}
+	for _, part := range strings.Split(header, ",") {
+		comps := strings.Split(strings.TrimSpace(part), ";")
+		if len(comps) != 3 || len(comps[0]) == 0 {
+			continue
+		}
+
+		var o Operation
+		for _, kv := range comps[1:] {
+			switch {
+			case strings.HasPrefix(kv, "count="):
+				o.Count, _ = strconv.Atoi(strings.TrimPrefix(kv, "count="))
+			case strings.HasPrefix(kv, "dur="):
+				ms, _ := strconv.ParseFloat(strings.TrimPrefix(kv, "dur="), 64)
+				o.Duration = time.Duration(ms * float64(time.Millisecond))
+			}
+		}
+		ops[comps[0]] = o
+	}
+	return ops
+}
@@ -0,0 +1,59 @@
+package cost
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecord(t *testing.T) {
+	// recording without a cost in the context is a noop
+	Record(context.TODO(), Store, time.Now())
+
+	ctx := NewContext(context.TODO())
+	Record(ctx, Store, time.Now().Add(-time.Millisecond*2))
+	Record(ctx, Store, time.Now().Add(-time.Millisecond*3))
+	Record(ctx, Client, time.Now().Add(-time.Millisecond*10))
+
+	c, ok := FromContext(ctx)
+	assert.True(t, ok)
+
+	ops := c.Operations()
+	assert.Equal(t, 2, ops[Store].Count)
+	assert.True(t, ops[Store].Duration >= time.Millisecond*5)
+	assert.Equal(t, 1, ops[Client].Count)
+
+	md := c.Metadata()
+	assert.Equal(t, "2", md["cost.store.count"])
+	assert.Equal(t, "1", md["cost.client.count"])
+}
+
+func TestParse(t *testing.T) {
+	ctx := NewContext(context.TODO())
+	c, _ := FromContext(ctx)
+	c.add(Store, 2, time.Millisecond*3)
+	c.add(Client, 1, time.Microsecond*12500)
+
+	str := c.String()
+	assert.Equal(t, "client;count=1;dur=12.5, store;count=2;dur=3.0", str)
+
+	ops := Parse(str)
+	assert.Equal(t, Operation{Count: 1, Duration: time.Microsecond * 12500}, ops[Client])
+	assert.Equal(t, Operation{Count: 2, Duration: time.Millisecond * 3}, ops[Store])
+
+	// malformed operations are ignored
+	assert.Len(t, Parse("store;count=1, ;count=1;dur=1, broker;count=1;dur=2"), 1)
+}
+
+func TestMerge(t *testing.T) {
+	ctx := NewContext(context.TODO())
+	Merge(ctx, "store;count=2;dur=3.0, broker;count=1;dur=1.0")
+	Merge(ctx, "store;count=1;dur=1.0")
+
+	c, _ := FromContext(ctx)
+	ops := c.Operations()
+	assert.Equal(t, Operation{Count: 3, Duration: time.Millisecond * 4}, ops[Store])
+	assert.Equal(t, Operation{Count: 1, Duration: time.Millisecond}, ops[Broker])
+}
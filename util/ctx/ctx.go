@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/micro/micro/v3/util/clientip"
 )
 
 func FromRequest(r *http.Request) context.Context {
@@ -36,6 +37,8 @@ func FromRequest(r *http.Request) context.Context {
 	md["Host"] = r.Host
 	// pass http method
 	md["Method"] = r.Method
+	// pass the client ip, overwriting any value set by the client
+	md[clientip.MetadataKey] = clientip.FromRequest(r, clientip.DefaultTrusted)
 	if r.URL != nil {
 		md["URL"] = r.URL.String()
 	}
@@ -0,0 +1,278 @@
+// Package export streams large result sets to http clients as CSV or newline delimited JSON.
+// Rows are read a page at a time and each page is written and flushed before the next is read,
+// so a slow client applies backpressure to the reads and the result set is never held in memory.
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
+)
+
+// Format of an export
+type Format string
+
+const (
+	// CSV writes a header row followed by a row per record, nested values are encoded as JSON
+	CSV Format = "csv"
+	// NDJSON writes each record as a JSON object on its own line
+	NDJSON Format = "ndjson"
+)
+
+var (
+	// DefaultPageSize is the number of rows read at a time
+	DefaultPageSize uint = 500
+
+	// ErrUnknownFormat is returned when the format requested isn't supported
+	ErrUnknownFormat = errors.New("unknown export format")
+
+	// bom is written at the start of CSV exports which should be opened in Excel, otherwise it
+	// assumes the file isn't UTF-8
+	bom = []byte{0xEF, 0xBB, 0xBF}
+)
+
+// Row of an export
+type Row map[string]interface{}
+
+// Source returns the rows at the offset, up to the limit. Fewer rows than the limit indicates
+// there are no more rows.
+type Source func(ctx context.Context, offset, limit uint) ([]Row, error)
+
+// StoreSource returns a source which reads the records with the prefix from the store. Records
+// which are JSON objects are exported as their fields, otherwise as their key and value.
+func StoreSource(s store.Store, prefix string, opts ...store.ReadOption) Source {
+	return func(ctx context.Context, offset, limit uint) ([]Row, error) {
+		ropts := append([]store.ReadOption{
+			store.ReadPrefix(),
+			store.ReadOffset(offset),
+			store.ReadLimit(limit),
+			store.ReadContext(ctx),
+		}, opts...)
+
+		recs, err := s.Read(prefix, ropts...)
+		if err == store.ErrNotFound {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		rows := make([]Row, 0, len(recs))
+		for _, r := range recs {
+			var row Row
+			if err := json.Unmarshal(r.Value, &row); err != nil || row == nil {
+				row = Row{"key": r.Key, "value": string(r.Value)}
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
+	}
+}
+
+// ParseFormat returns the format with the name, csv or ndjson
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "csv":
+		return CSV, nil
+	case "ndjson", "jsonl":
+		return NDJSON, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// FormatFor returns the format requested using the format query parameter or the accept header,
+// defaulting to NDJSON
+func FormatFor(r *http.Request) (Format, error) {
+	if f := r.URL.Query().Get("format"); len(f) > 0 {
+		return ParseFormat(f)
+	}
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, "text/csv"):
+		return CSV, nil
+	case strings.Contains(accept, "application/x-ndjson"):
+		return NDJSON, nil
+	}
+	return NDJSON, nil
+}
+
+// Write the rows from the source to the response in the format requested. Once the first page
+// has been written the status can't be changed, so errors after that point end the response early
+// and are returned for logging.
+func Write(w http.ResponseWriter, r *http.Request, src Source, opts ...Option) error {
+	options := newOptions(opts...)
+
+	format := options.Format
+	if len(format) == 0 {
+		f, err := FormatFor(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return err
+		}
+		format = f
+	}
+
+	ctx := r.Context()
+
+	// read the first page before writing the headers so a failure can still be reported
+	rows, err := src(ctx, 0, options.PageSize)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return err
+	}
+
+	switch format {
+	case CSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	default:
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	if len(options.Filename) > 0 {
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, options.Filename, format))
+	}
+	// the length isn't known so the response is chunked
+	w.Header().Del("Content-Length")
+	w.WriteHeader(http.StatusOK)
+
+	bw := bufio.NewWriter(w)
+	enc := newEncoder(format, bw, options)
+
+	for offset := uint(0); ; {
+		for _, row := range rows {
+			if err := enc.Encode(row); err != nil {
+				return err
+			}
+		}
+		if err := enc.Flush(); err != nil {
+			return err
+		}
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+
+		if uint(len(rows)) < options.PageSize {
+			return nil
+		}
+		// stop reading if the client has gone away
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		offset += uint(len(rows))
+		rows, err = src(ctx, offset, options.PageSize)
+		if err != nil {
+			logger.Errorf("Error reading page at offset %d of export: %v", offset, err)
+			return err
+		}
+	}
+}
+
+// Handler returns a http handler which writes the rows from the source
+func Handler(src Source, opts ...Option) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := Write(w, r, src, opts...); err != nil {
+			logger.Debugf("Error exporting %v: %v", r.URL.Path, err)
+		}
+	})
+}
+
+type encoder interface {
+	Encode(Row) error
+	Flush() error
+}
+
+func newEncoder(f Format, w *bufio.Writer, opts Options) encoder {
+	if f == CSV {
+		return &csvEncoder{w: w, cw: csv.NewWriter(w), columns: opts.Columns, excel: opts.Excel}
+	}
+	return &jsonEncoder{enc: json.NewEncoder(w)}
+}
+
+type jsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (j *jsonEncoder) Encode(r Row) error {
+	return j.enc.Encode(r)
+}
+
+func (j *jsonEncoder) Flush() error {
+	return nil
+}
+
+type csvEncoder struct {
+	w       *bufio.Writer
+	cw      *csv.Writer
+	columns []string
+	excel   bool
+	started bool
+}
+
+func (c *csvEncoder) Encode(r Row) error {
+	if !c.started {
+		// without columns the header is taken from the first row
+		if len(c.columns) == 0 {
+			for k := range r {
+				c.columns = append(c.columns, k)
+			}
+			sort.Strings(c.columns)
+		}
+		if err := c.header(); err != nil {
+			return err
+		}
+	}
+
+	record := make([]string, len(c.columns))
+	for i, col := range c.columns {
+		record[i] = cell(r[col])
+	}
+	return c.cw.Write(record)
+}
+
+func (c *csvEncoder) Flush() error {
+	// an empty export still has a header if the columns are known
+	if !c.started && len(c.columns) > 0 {
+		if err := c.header(); err != nil {
+			return err
+		}
+	}
+	c.cw.Flush()
+	return c.cw.Error()
+}
+
+func (c *csvEncoder) header() error {
+	c.started = true
+	if c.excel {
+		c.w.Write(bom)
+	}
+	return c.cw.Write(c.columns)
+}
+
+// cell returns the CSV value of a field
+func cell(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case bool, float64, int, int64, uint, uint64:
+		return fmt.Sprint(t)
+	default:
+		b, err := json.Marshal(t)
+		if err != nil {
+			return fmt.Sprint(t)
+		}
+		return string(b)
+	}
+}
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	s := memory.NewStore()
+	for i := 0; i < 7; i++ {
+		s.Write(store.NewRecord(fmt.Sprintf("user/%d", i), map[string]interface{}{
+			"id":   i,
+			"name": fmt.Sprintf("user %d", i),
+			"tags": []string{"a", "b"},
+		}))
+	}
+	s.Write(&store.Record{Key: "other/1", Value: []byte("{}")})
+
+	var pages int
+	src := StoreSource(s, "user/")
+	counted := func(ctx context.Context, offset, limit uint) ([]Row, error) {
+		pages++
+		return src(ctx, offset, limit)
+	}
+
+	// ndjson
+	req := httptest.NewRequest("GET", "/export", nil)
+	rsp := httptest.NewRecorder()
+	assert.NoError(t, Write(rsp, req, counted, PageSize(3)))
+	assert.Equal(t, "application/x-ndjson", rsp.Header().Get("Content-Type"))
+	assert.Equal(t, 3, pages)
+
+	var lines int
+	sc := bufio.NewScanner(rsp.Body)
+	for sc.Scan() {
+		var row Row
+		assert.NoError(t, json.Unmarshal(sc.Bytes(), &row))
+		assert.Contains(t, row["name"], "user")
+		lines++
+	}
+	assert.Equal(t, 7, lines)
+
+	// csv
+	req = httptest.NewRequest("GET", "/export?format=csv", nil)
+	rsp = httptest.NewRecorder()
+	assert.NoError(t, Write(rsp, req, src, Columns("id", "name", "tags"), Filename("users")))
+	assert.Equal(t, `attachment; filename="users.csv"`, rsp.Header().Get("Content-Disposition"))
+
+	rows := strings.Split(strings.TrimSpace(rsp.Body.String()), "\n")
+	assert.Len(t, rows, 8)
+	assert.Equal(t, "id,name,tags", rows[0])
+	assert.Equal(t, `0,user 0,"[""a"",""b""]"`, rows[1])
+
+	// an empty csv export still has a header
+	req = httptest.NewRequest("GET", "/export", nil)
+	rsp = httptest.NewRecorder()
+	assert.NoError(t, Write(rsp, req, StoreSource(s, "missing/"), WithFormat(CSV), Columns("id"), Excel()))
+	assert.Equal(t, string(bom)+"id\n", rsp.Body.String())
+
+	// unknown formats are rejected
+	req = httptest.NewRequest("GET", "/export?format=xml", nil)
+	rsp = httptest.NewRecorder()
+	assert.Equal(t, ErrUnknownFormat, Write(rsp, req, src))
+	assert.Equal(t, 400, rsp.Code)
+}
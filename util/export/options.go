@@ -0,0 +1,66 @@
+package export
+
+// Options of an export
+type Options struct {
+	// Format to write, the format requested is used if blank
+	Format Format
+	// Columns of a CSV export, the fields of the first row are used if blank
+	Columns []string
+	// Filename the client should save the export as, without an extension
+	Filename string
+	// PageSize is the number of rows read at a time
+	PageSize uint
+	// Excel prefixes CSV exports with a byte order mark so Excel reads them as UTF-8
+	Excel bool
+}
+
+// Option sets an export option
+type Option func(o *Options)
+
+func newOptions(opts ...Option) Options {
+	options := Options{
+		PageSize: DefaultPageSize,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.PageSize == 0 {
+		options.PageSize = DefaultPageSize
+	}
+	return options
+}
+
+// WithFormat writes the export in the format regardless of the format requested
+func WithFormat(f Format) Option {
+	return func(o *Options) {
+		o.Format = f
+	}
+}
+
+// Columns sets the columns, and their order, of a CSV export
+func Columns(cols ...string) Option {
+	return func(o *Options) {
+		o.Columns = cols
+	}
+}
+
+// Filename sets the name the client should save the export as
+func Filename(name string) Option {
+	return func(o *Options) {
+		o.Filename = name
+	}
+}
+
+// PageSize sets the number of rows read at a time
+func PageSize(n uint) Option {
+	return func(o *Options) {
+		o.PageSize = n
+	}
+}
+
+// Excel makes CSV exports readable by Excel
+func Excel() Option {
+	return func(o *Options) {
+		o.Excel = true
+	}
+}
@@ -0,0 +1,321 @@
+// Package idempotency records the responses of requests made with an idempotency key, so retries
+// of a request, e.g. after a network error, replay its response rather than repeating it. This
+// makes endpoints which aren't naturally idempotent, e.g. taking a payment, safe to retry.
+//
+// Clients set the Idempotency-Key header, or call services with client.WithIdempotencyKey. The
+// first request with a key claims it, concurrent retries are rejected with ErrInProgress until
+// its response is recorded, and the key can't be reused for a different request.
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
+)
+
+var (
+	// Header is the HTTP header, and metadata key, the idempotency key is set in
+	Header = "Idempotency-Key"
+	// DefaultTTL is how long the responses are recorded for
+	DefaultTTL = time.Hour * 24
+	// LockTTL is how long a key is claimed by a request in progress without the claim being
+	// renewed, so the request can be retried if the process handling it fails before its
+	// response is recorded
+	LockTTL = time.Minute
+	// MaxKeyLength is the length of the longest key allowed
+	MaxKeyLength = 255
+	// MaxBodySize is the size of the largest request and response bodies of HTTP requests made
+	// with a key, larger responses aren't recorded
+	MaxBodySize = 1024 * 1024
+
+	// ErrInProgress is returned when a request with the key is in progress
+	ErrInProgress = errors.New("a request with the idempotency key is in progress")
+	// ErrMismatch is returned when the key was used for a different request
+	ErrMismatch = errors.New("the idempotency key was used for a different request")
+	// ErrInvalidKey is returned when the key is blank or too long
+	ErrInvalidKey = errors.New("invalid idempotency key")
+	// ErrFinished is returned when completing a claim which was already completed or released
+	ErrFinished = errors.New("the idempotency key was already completed or released")
+)
+
+// table the responses are recorded in
+const table = "idempotency"
+
+// Response recorded for an idempotency key
+type Response struct {
+	// Fingerprint of the request the key was used for
+	Fingerprint string `json:"fingerprint"`
+	// Complete is false while the request is in progress
+	Complete bool `json:"complete,omitempty"`
+	// Status of an HTTP response
+	Status int `json:"status,omitempty"`
+	// Header of an HTTP response
+	Header http.Header `json:"header,omitempty"`
+	// Body of the response
+	Body []byte `json:"body,omitempty"`
+	// Error returned by an RPC, the body is blank if it's set
+	Error string `json:"error,omitempty"`
+	// Created is when the key was first used
+	Created time.Time `json:"created"`
+}
+
+// Options of the records of a key
+type Options struct {
+	// Namespace the response is recorded in
+	Namespace string
+	// TTL of the response recorded
+	TTL time.Duration
+	// Store the responses are recorded in, defaults to store.DefaultStore
+	Store store.Store
+}
+
+// Option sets an option
+type Option func(o *Options)
+
+// Namespace the response is recorded in, it's part of the key so keys are never shared between
+// namespaces
+func Namespace(ns string) Option {
+	return func(o *Options) {
+		o.Namespace = ns
+	}
+}
+
+// TTL of the response recorded, defaults to DefaultTTL
+func TTL(d time.Duration) Option {
+	return func(o *Options) {
+		o.TTL = d
+	}
+}
+
+// Store the responses are recorded in
+func Store(s store.Store) Option {
+	return func(o *Options) {
+		o.Store = s
+	}
+}
+
+func newOptions(opts ...Option) Options {
+	options := Options{Namespace: "micro", TTL: DefaultTTL, Store: store.DefaultStore}
+	for _, o := range opts {
+		o(&options)
+	}
+	return options
+}
+
+// Fingerprint returns a hash of the parts of a request, e.g. its method, path and body
+func Fingerprint(parts ...[]byte) string {
+	h := sha256.New()
+	for _, p := range parts {
+		// the length is written so the boundaries of the parts are part of the hash
+		b, _ := json.Marshal(len(p))
+		h.Write(b)
+		h.Write(p)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// Validate the key
+func Validate(key string) error {
+	if len(key) == 0 || len(key) > MaxKeyLength {
+		return ErrInvalidKey
+	}
+	return nil
+}
+
+// Claim of a key by the request in progress. The claim is renewed until the response is recorded
+// with Complete, or the key is released with Release, so requests which take longer than LockTTL
+// keep the key. If the process handling the request fails the claim expires after LockTTL.
+type Claim struct {
+	key         string
+	fingerprint string
+	value       []byte
+	options     Options
+
+	// mtx guards the etag, which changes every time the claim is renewed
+	mtx  sync.Mutex
+	etag string
+
+	once sync.Once
+	exit chan bool
+}
+
+// Begin claims the key for the request with the fingerprint. The key is scoped, e.g. by the
+// account making the request, so callers can't replay each other's responses. If the key was
+// already used the recorded response is returned, which should be replayed. Otherwise the claim
+// is returned and the request should be made, then recorded with Complete or released with
+// Release if it can be retried.
+func Begin(scope, key, fingerprint string, opts ...Option) (*Response, *Claim, error) {
+	if err := Validate(key); err != nil {
+		return nil, nil, err
+	}
+	options := newOptions(opts...)
+	k := scope + "/" + key
+
+	b, err := json.Marshal(&Response{Fingerprint: fingerprint, Created: time.Now()})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the claim may expire between it being written and read, in which case it's claimed again
+	for i := 0; i < 2; i++ {
+		rec := &store.Record{Key: k, Value: b, Expiry: LockTTL}
+		err := options.Store.Write(rec, store.WriteTo(options.Namespace, table), store.IfMatch(""))
+		if err == nil {
+			etag, err := readEtag(options, k)
+			if err != nil {
+				return nil, nil, err
+			}
+			c := &Claim{
+				key:         k,
+				fingerprint: fingerprint,
+				value:       b,
+				etag:        etag,
+				options:     options,
+				exit:        make(chan bool),
+			}
+			go c.renew()
+			return nil, c, nil
+		} else if err != store.ErrConflict {
+			return nil, nil, err
+		}
+
+		recs, err := options.Store.Read(k, store.ReadFrom(options.Namespace, table))
+		if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+			continue
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		var rsp *Response
+		if err := json.Unmarshal(recs[0].Value, &rsp); err != nil {
+			return nil, nil, err
+		}
+		if rsp.Fingerprint != fingerprint {
+			return nil, nil, ErrMismatch
+		}
+		if !rsp.Complete {
+			return nil, nil, ErrInProgress
+		}
+		return rsp, nil, nil
+	}
+	return nil, nil, ErrInProgress
+}
+
+// renew the claim before it expires until it's completed or released. The claim is written
+// conditionally so a claim which expired and was taken by another request isn't overwritten.
+func (c *Claim) renew() {
+	t := time.NewTicker(LockTTL / 3)
+	defer t.Stop()
+	for {
+		select {
+		case <-c.exit:
+			return
+		case <-t.C:
+		}
+		if err := c.renewOnce(); err == store.ErrConflict {
+			logger.Warnf("Idempotency key %v was claimed by another request", c.key)
+			return
+		} else if err != nil {
+			logger.Errorf("Error renewing claim of idempotency key %v: %v", c.key, err)
+		}
+	}
+}
+
+// renewOnce writes the claim and reads back its new etag, unless it was finished meanwhile
+func (c *Claim) renewOnce() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	select {
+	case <-c.exit:
+		return nil
+	default:
+	}
+	err := c.options.Store.Write(&store.Record{Key: c.key, Value: c.value, Expiry: LockTTL},
+		store.WriteTo(c.options.Namespace, table),
+		store.IfMatch(c.etag),
+	)
+	if err != nil {
+		return err
+	}
+	etag, err := readEtag(c.options, c.key)
+	if err != nil {
+		return err
+	}
+	c.etag = etag
+	return nil
+}
+
+// readEtag returns the etag of the key, which is set by the store when the record is written
+func readEtag(options Options, key string) (string, error) {
+	recs, err := options.Store.Read(key, store.ReadFrom(options.Namespace, table))
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return "", store.ErrConflict
+	} else if err != nil {
+		return "", err
+	}
+	return recs[0].Etag, nil
+}
+
+// finish stops renewing the claim, returning false if it was already completed or released
+func (c *Claim) finish() bool {
+	first := false
+	c.once.Do(func() {
+		first = true
+		close(c.exit)
+	})
+	return first
+}
+
+// Complete records the response of the request, so it's replayed to the retries of the request.
+// ErrConflict is returned if the claim expired and the key was claimed by another request.
+func (c *Claim) Complete(rsp *Response) error {
+	if !c.finish() {
+		return ErrFinished
+	}
+
+	r := *rsp
+	r.Fingerprint = c.fingerprint
+	r.Complete = true
+	if r.Created.IsZero() {
+		r.Created = time.Now()
+	}
+	b, err := json.Marshal(&r)
+	if err != nil {
+		return err
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.options.Store.Write(&store.Record{Key: c.key, Value: b, Expiry: c.options.TTL},
+		store.WriteTo(c.options.Namespace, table),
+		store.IfMatch(c.etag),
+	)
+}
+
+// Release the key without recording a response, e.g. because the request failed and can be
+// retried. The key isn't released if it was claimed by another request, and releasing a claim
+// which was completed does nothing, so it can be deferred in case the request panics.
+func (c *Claim) Release() error {
+	if !c.finish() {
+		return nil
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	recs, err := c.options.Store.Read(c.key, store.ReadFrom(c.options.Namespace, table))
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if recs[0].Etag != c.etag {
+		return nil
+	}
+	return c.options.Store.Delete(c.key, store.DeleteFrom(c.options.Namespace, table))
+}
@@ -0,0 +1,109 @@
+package idempotency
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotency(t *testing.T) {
+	store.DefaultStore = memory.NewStore()
+	fp := Fingerprint([]byte("POST"), []byte("/payments/create"), []byte(`{"amount":100}`))
+
+	// the first request claims the key
+	rsp, claim, err := Begin("account/1", "abc", fp, Namespace("foo"))
+	assert.NoError(t, err)
+	assert.Nil(t, rsp)
+	assert.NotNil(t, claim)
+
+	// retries are rejected until the response is recorded
+	_, _, err = Begin("account/1", "abc", fp, Namespace("foo"))
+	assert.Equal(t, ErrInProgress, err)
+
+	// the key can't be used for another request
+	other := Fingerprint([]byte("POST"), []byte("/payments/create"), []byte(`{"amount":200}`))
+	_, _, err = Begin("account/1", "abc", other, Namespace("foo"))
+	assert.Equal(t, ErrMismatch, err)
+
+	// keys aren't shared between scopes or namespaces
+	rsp, released, err := Begin("account/2", "abc", fp, Namespace("foo"))
+	assert.NoError(t, err)
+	assert.Nil(t, rsp)
+	rsp, _, err = Begin("account/1", "abc", fp, Namespace("bar"))
+	assert.NoError(t, err)
+	assert.Nil(t, rsp)
+
+	// once it's recorded the response is replayed
+	assert.NoError(t, claim.Complete(&Response{Status: 201, Body: []byte(`{"id":"1"}`)}))
+	rsp, _, err = Begin("account/1", "abc", fp, Namespace("foo"))
+	assert.NoError(t, err)
+	if assert.NotNil(t, rsp) {
+		assert.True(t, rsp.Complete)
+		assert.Equal(t, 201, rsp.Status)
+		assert.Equal(t, `{"id":"1"}`, string(rsp.Body))
+	}
+
+	// releasing a completed claim does nothing
+	assert.NoError(t, claim.Release())
+	rsp, _, err = Begin("account/1", "abc", fp, Namespace("foo"))
+	assert.NoError(t, err)
+	assert.NotNil(t, rsp)
+
+	// released keys can be claimed again
+	assert.NoError(t, released.Release())
+	rsp, _, err = Begin("account/2", "abc", fp, Namespace("foo"))
+	assert.NoError(t, err)
+	assert.Nil(t, rsp)
+}
+
+func TestClaimRenewed(t *testing.T) {
+	store.DefaultStore = memory.NewStore()
+	defer func(d time.Duration) { LockTTL = d }(LockTTL)
+	LockTTL = time.Millisecond * 60
+
+	// the claim is renewed while the request is in progress
+	_, claim, err := Begin("account/1", "abc", "fp")
+	assert.NoError(t, err)
+	time.Sleep(LockTTL * 2)
+	_, _, err = Begin("account/1", "abc", "fp")
+	assert.Equal(t, ErrInProgress, err)
+	assert.NoError(t, claim.Complete(&Response{Status: 200}))
+}
+
+func TestClaimExpired(t *testing.T) {
+	store.DefaultStore = memory.NewStore()
+
+	// a claim which expired and was taken by another request can't be completed or released
+	_, claim, err := Begin("account/1", "abc", "fp")
+	assert.NoError(t, err)
+	assert.NoError(t, store.DefaultStore.Delete("account/1/abc", store.DeleteFrom("micro", table)))
+	_, other, err := Begin("account/1", "abc", "fp")
+	assert.NoError(t, err)
+
+	assert.Equal(t, store.ErrConflict, claim.Complete(&Response{Status: 200}))
+	assert.NoError(t, other.Complete(&Response{Status: 201}))
+	rsp, _, err := Begin("account/1", "abc", "fp")
+	assert.NoError(t, err)
+	if assert.NotNil(t, rsp) {
+		assert.Equal(t, 201, rsp.Status)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate("abc"))
+	assert.Equal(t, ErrInvalidKey, Validate(""))
+	assert.Equal(t, ErrInvalidKey, Validate(strings.Repeat("a", MaxKeyLength+1)))
+
+	_, _, err := Begin("account/1", "", "fp")
+	assert.Equal(t, ErrInvalidKey, err)
+}
+
+func TestFingerprint(t *testing.T) {
+	assert.Equal(t, Fingerprint([]byte("a"), []byte("b")), Fingerprint([]byte("a"), []byte("b")))
+	// the boundaries of the parts are part of the fingerprint
+	assert.NotEqual(t, Fingerprint([]byte("ab"), []byte("c")), Fingerprint([]byte("a"), []byte("bc")))
+}
@@ -0,0 +1,275 @@
+// Package killswitch disables endpoints across every instance of a service, e.g. to turn off a
+// misbehaving endpoint during an incident without redeploying the service
+package killswitch
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
+)
+
+var (
+	// Topic the changes to switches are published to. The events double as the audit log of who
+	// disabled and enabled endpoints.
+	Topic = "killswitch"
+	// RefreshInterval is how often services reload the switches from the store in case an event
+	// was missed
+	RefreshInterval = time.Minute
+
+	table = "killswitch"
+)
+
+const (
+	// EventDisabled is published when an endpoint is disabled
+	EventDisabled = "endpoint.disabled"
+	// EventEnabled is published when an endpoint is enabled
+	EventEnabled = "endpoint.enabled"
+)
+
+// Switch disables an endpoint of a service until it expires
+type Switch struct {
+	Service  string    `json:"service"`
+	Endpoint string    `json:"endpoint"`
+	Reason   string    `json:"reason"`
+	Account  string    `json:"account"`
+	Created  time.Time `json:"created"`
+	Expiry   time.Time `json:"expiry"`
+}
+
+// Expired returns true if the switch no longer applies
+func (s *Switch) Expired() bool {
+	return !s.Expiry.IsZero() && time.Now().After(s.Expiry)
+}
+
+func (s *Switch) key() string {
+	return s.Service + "/" + s.Endpoint
+}
+
+// Event is published when a switch changes
+type Event struct {
+	Type   string  `json:"type"`
+	Switch *Switch `json:"switch"`
+}
+
+// Disable an endpoint of a service in the namespace
+func Disable(ns string, s *Switch) error {
+	if len(s.Service) == 0 || len(s.Endpoint) == 0 {
+		return fmt.Errorf("missing service or endpoint")
+	}
+	if s.Created.IsZero() {
+		s.Created = time.Now()
+	}
+
+	rec := store.NewRecord(s.key(), s)
+	if !s.Expiry.IsZero() {
+		rec.Expiry = time.Until(s.Expiry)
+	}
+	if err := store.DefaultStore.Write(rec, store.WriteTo(ns, table)); err != nil {
+		return err
+	}
+
+	return publish(ns, EventDisabled, s)
+}
+
+// Enable an endpoint of a service in the namespace which was disabled
+func Enable(ns string, s *Switch) error {
+	err := store.DefaultStore.Delete(s.key(), store.DeleteFrom(ns, table))
+	if err != nil && err != store.ErrNotFound {
+		return err
+	}
+
+	return publish(ns, EventEnabled, s)
+}
+
+// List the endpoints disabled in the namespace
+func List(ns string) ([]*Switch, error) {
+	recs, err := store.Read("", store.ReadPrefix(), store.ReadFrom(ns, table))
+	if err != nil && err != store.ErrNotFound {
+		return nil, err
+	}
+
+	res := make([]*Switch, 0, len(recs))
+	for _, r := range recs {
+		var s Switch
+		if err := r.Decode(&s); err != nil {
+			return nil, err
+		}
+		if !s.Expired() {
+			res = append(res, &s)
+		}
+	}
+	return res, nil
+}
+
+// History returns the changes made to the switches in the namespace
+func History(ns string, limit uint) ([]*Event, error) {
+	evs, err := events.Read(Topic, events.ReadLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*Event, 0, len(evs))
+	for _, ev := range evs {
+		if ev.Metadata["namespace"] != ns {
+			continue
+		}
+		var e Event
+		if err := ev.Unmarshal(&e); err != nil {
+			return nil, err
+		}
+		res = append(res, &e)
+	}
+	return res, nil
+}
+
+func publish(ns, typ string, s *Switch) error {
+	return events.Publish(Topic, &Event{Type: typ, Switch: s}, events.WithMetadata(map[string]string{
+		"type":      typ,
+		"namespace": ns,
+		"service":   s.Service,
+	}))
+}
+
+var (
+	mtx      sync.RWMutex
+	watchers = map[string]*watcher{}
+)
+
+// Disabled returns the switch if the endpoint of a service is disabled. The first call for a
+// service starts watching its switches in the background, it never blocks on the store since
+// it's called for every request, including those made to the store.
+func Disabled(ns, service, endpoint string) *Switch {
+	mtx.RLock()
+	w, ok := watchers[ns+"/"+service]
+	mtx.RUnlock()
+
+	if !ok {
+		mtx.Lock()
+		if w, ok = watchers[ns+"/"+service]; !ok {
+			w = &watcher{namespace: ns, service: service, switches: map[string]*Switch{}}
+			watchers[ns+"/"+service] = w
+			go w.run()
+		}
+		mtx.Unlock()
+	}
+
+	return w.get(endpoint)
+}
+
+// watcher keeps the switches of a service up to date
+type watcher struct {
+	namespace string
+	service   string
+
+	sync.RWMutex
+	switches map[string]*Switch
+}
+
+func (w *watcher) get(endpoint string) *Switch {
+	w.RLock()
+	s, ok := w.switches[endpoint]
+	w.RUnlock()
+	if !ok || s.Expired() {
+		return nil
+	}
+	return s
+}
+
+func (w *watcher) run() {
+	// consume the events first so changes made while loading aren't missed
+	evChan := w.consume()
+	w.load()
+
+	t := time.NewTicker(RefreshInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			if evChan == nil {
+				evChan = w.consume()
+			}
+			w.load()
+		case ev, ok := <-evChan:
+			if !ok {
+				evChan = nil
+				continue
+			}
+			w.apply(ev)
+		}
+	}
+}
+
+// consume the events, if the stream isn't available the switches are only loaded from the store
+// until it is
+func (w *watcher) consume() <-chan events.Event {
+	if events.DefaultStream == nil {
+		return nil
+	}
+	evChan, err := events.Consume(Topic)
+	if err != nil {
+		logger.Debugf("Error consuming %v events: %v", Topic, err)
+		return nil
+	}
+	return evChan
+}
+
+// load replaces the switches with those in the store
+func (w *watcher) load() {
+	if store.DefaultStore == nil {
+		return
+	}
+	recs, err := store.Read(w.service+"/", store.ReadPrefix(), store.ReadFrom(w.namespace, table))
+	if err != nil && err != store.ErrNotFound {
+		logger.Debugf("Error loading the disabled endpoints of %v: %v", w.service, err)
+		return
+	}
+
+	switches := make(map[string]*Switch, len(recs))
+	for _, r := range recs {
+		var s Switch
+		if err := r.Decode(&s); err != nil {
+			continue
+		}
+		switches[s.Endpoint] = &s
+	}
+
+	w.Lock()
+	w.switches = switches
+	w.Unlock()
+}
+
+func (w *watcher) apply(ev events.Event) {
+	if ev.Metadata["service"] != w.service || ev.Metadata["namespace"] != w.namespace {
+		return
+	}
+	var e Event
+	if err := ev.Unmarshal(&e); err != nil || e.Switch == nil {
+		return
+	}
+
+	w.Lock()
+	defer w.Unlock()
+
+	switch e.Type {
+	case EventDisabled:
+		w.switches[e.Switch.Endpoint] = e.Switch
+		logger.Warnf("Endpoint %v of %v disabled by %v: %v", e.Switch.Endpoint, w.service, e.Switch.Account, e.Switch.Reason)
+	case EventEnabled:
+		delete(w.switches, e.Switch.Endpoint)
+		logger.Infof("Endpoint %v of %v enabled by %v", e.Switch.Endpoint, w.service, e.Switch.Account)
+	}
+}
+
+// ParseEndpoint validates an endpoint is of the form Handler.Method
+func ParseEndpoint(endpoint string) error {
+	if comps := strings.Split(endpoint, "."); len(comps) != 2 || len(comps[0]) == 0 || len(comps[1]) == 0 {
+		return fmt.Errorf("invalid endpoint %v, expected the form Handler.Method, e.g. Users.Export", endpoint)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package killswitch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/events/stream/memory"
+	"github.com/micro/micro/v3/service/store"
+	memstore "github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKillSwitch(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	stream, err := memory.NewStream()
+	assert.Nil(t, err)
+	events.DefaultStream = stream
+
+	// the first check starts watching the switches of the service
+	assert.Nil(t, Disabled("foo", "users", "Users.Export"))
+	time.Sleep(time.Millisecond * 50)
+
+	s := &Switch{Service: "users", Endpoint: "Users.Export", Reason: "incident-123"}
+	assert.Nil(t, Disable("foo", s))
+	assert.Eventually(t, func() bool {
+		return Disabled("foo", "users", "Users.Export") != nil
+	}, time.Second, time.Millisecond*10)
+
+	// switches only apply to the namespace and endpoint they were set for
+	assert.Nil(t, Disabled("bar", "users", "Users.Export"))
+	assert.Nil(t, Disabled("foo", "users", "Users.Read"))
+
+	switches, err := List("foo")
+	assert.Nil(t, err)
+	assert.Len(t, switches, 1)
+
+	assert.Nil(t, Enable("foo", s))
+	assert.Eventually(t, func() bool {
+		return Disabled("foo", "users", "Users.Export") == nil
+	}, time.Second, time.Millisecond*10)
+}
+
+func TestExpired(t *testing.T) {
+	assert.False(t, (&Switch{}).Expired())
+	assert.False(t, (&Switch{Expiry: time.Now().Add(time.Minute)}).Expired())
+	assert.True(t, (&Switch{Expiry: time.Now().Add(-time.Minute)}).Expired())
+}
+
+func TestParseEndpoint(t *testing.T) {
+	assert.Nil(t, ParseEndpoint("Users.Export"))
+	assert.NotNil(t, ParseEndpoint("Users"))
+	assert.NotNil(t, ParseEndpoint("Users."))
+	assert.NotNil(t, ParseEndpoint("users.Users.Export"))
+}
@@ -0,0 +1,331 @@
+// Package netpolicy restricts which services can call each other. Namespaces opt in to enforcing
+// the policies, after which calls between services are denied unless a rule allows them. In dry
+// run mode calls which would be denied are logged but allowed, so the rules can be checked before
+// they're enforced.
+package netpolicy
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/router/history"
+	"github.com/micro/micro/v3/service/store"
+)
+
+var (
+	// RefreshInterval is how often services reload the policies of their namespace
+	RefreshInterval = time.Second * 30
+	// LoadTimeout is how long the first check of a namespace waits for its policies to load
+	LoadTimeout = time.Second * 5
+	// DefaultPolicies enforces the policies in the auth wrapper, it's set up once the store is
+	DefaultPolicies *Policies
+
+	// ErrDenied is returned when a call isn't allowed by the policies
+	ErrDenied = errors.New("call denied by network policy")
+	// ErrUnavailable is returned when the policies of the namespace have never been loaded
+	ErrUnavailable = errors.New("network policy unavailable")
+
+	table       = "netpolicy"
+	rulePrefix  = "rule/"
+	settingsKey = "settings"
+)
+
+// Mode the policies of a namespace are in
+type Mode string
+
+const (
+	// ModeDisabled allows every call, it's the default
+	ModeDisabled Mode = "disabled"
+	// ModeDryRun logs the calls which would be denied
+	ModeDryRun Mode = "dry-run"
+	// ModeEnforce denies calls which aren't allowed
+	ModeEnforce Mode = "enforce"
+)
+
+// Rule allows a service to call another
+type Rule struct {
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+	Account string    `json:"account"`
+	Created time.Time `json:"created"`
+}
+
+func (r *Rule) key() string {
+	return rulePrefix + r.From + "/" + r.To
+}
+
+func (r *Rule) String() string {
+	return r.From + " -> " + r.To
+}
+
+// Settings of the policies of a namespace
+type Settings struct {
+	Mode    Mode      `json:"mode"`
+	Account string    `json:"account"`
+	Updated time.Time `json:"updated"`
+}
+
+// Allow a service to call another in the namespace
+func Allow(ns string, r *Rule) error {
+	if len(r.From) == 0 || len(r.To) == 0 {
+		return errors.New("missing from or to service")
+	}
+	if r.Created.IsZero() {
+		r.Created = time.Now()
+	}
+	if err := store.DefaultStore.Write(store.NewRecord(r.key(), r), store.WriteTo(ns, table)); err != nil {
+		return err
+	}
+	invalidate(ns)
+	return history.Record(ns, &history.Change{
+		Kind: history.KindPolicy, Action: history.ActionCreate, Key: r.String(), Account: r.Account,
+	}, nil, r)
+}
+
+// Revoke a rule which allowed a service to call another, the account of the rule is recorded as
+// the account which revoked it
+func Revoke(ns string, r *Rule) error {
+	err := store.DefaultStore.Delete(r.key(), store.DeleteFrom(ns, table))
+	if err == store.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	invalidate(ns)
+	return history.Record(ns, &history.Change{
+		Kind: history.KindPolicy, Action: history.ActionDelete, Key: r.String(), Account: r.Account,
+	}, &Rule{From: r.From, To: r.To}, nil)
+}
+
+// Rules returns the rules of the namespace
+func Rules(ns string) ([]*Rule, error) {
+	return readRules(store.DefaultStore, ns)
+}
+
+func readRules(st store.Store, ns string) ([]*Rule, error) {
+	recs, err := st.Read(rulePrefix, store.ReadPrefix(), store.ReadFrom(ns, table))
+	if err != nil && err != store.ErrNotFound {
+		return nil, err
+	}
+
+	res := make([]*Rule, 0, len(recs))
+	for _, rec := range recs {
+		var r Rule
+		if err := rec.Decode(&r); err != nil {
+			return nil, err
+		}
+		res = append(res, &r)
+	}
+	return res, nil
+}
+
+// SetMode sets the mode of the policies of the namespace
+func SetMode(ns string, s *Settings) error {
+	switch s.Mode {
+	case ModeDisabled, ModeDryRun, ModeEnforce:
+	default:
+		return errors.New("invalid mode " + string(s.Mode))
+	}
+	if s.Updated.IsZero() {
+		s.Updated = time.Now()
+	}
+	before, err := GetMode(ns)
+	if err != nil {
+		return err
+	}
+	if err := store.DefaultStore.Write(store.NewRecord(settingsKey, s), store.WriteTo(ns, table)); err != nil {
+		return err
+	}
+	invalidate(ns)
+	return history.Record(ns, &history.Change{
+		Kind: history.KindPolicyMode, Action: history.ActionUpdate, Key: string(s.Mode), Account: s.Account,
+	}, before, s)
+}
+
+// GetMode returns the settings of the policies of the namespace
+func GetMode(ns string) (*Settings, error) {
+	return readMode(store.DefaultStore, ns)
+}
+
+func readMode(st store.Store, ns string) (*Settings, error) {
+	recs, err := st.Read(settingsKey, store.ReadFrom(ns, table))
+	if err == store.ErrNotFound {
+		return &Settings{Mode: ModeDisabled}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var s Settings
+	if err := recs[0].Decode(&s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// policy is the loaded policies of a namespace
+type policy struct {
+	mode  Mode
+	rules map[string]bool
+}
+
+// cached policy of a namespace
+type cached struct {
+	// policy is nil until it's first loaded
+	policy *policy
+	loaded time.Time
+	// loading is closed once the load in progress completes, it's nil if the policy isn't loading
+	loading chan struct{}
+}
+
+// Policies checks calls against the policies kept in a store. The policies of each namespace are
+// cached and reloaded in the background once they're stale.
+type Policies struct {
+	store store.Store
+
+	mtx        sync.Mutex
+	namespaces map[string]*cached
+}
+
+// New returns policies which are loaded from the store provided
+func New(st store.Store) *Policies {
+	return &Policies{store: st, namespaces: map[string]*cached{}}
+}
+
+// Check returns ErrDenied if the from service isn't allowed to call the to service. The first
+// check of a namespace waits for its policies to load, returning ErrUnavailable if they can't be,
+// after which stale policies are reloaded in the background so checks don't block on the store.
+// The last policies loaded are kept if reloading them fails.
+func (p *Policies) Check(ns, from, to string) error {
+	if from == to {
+		return nil
+	}
+	pol, err := p.get(ns)
+	if err != nil {
+		return err
+	}
+
+	if pol.mode == ModeDisabled || pol.rules[from+"/"+to] {
+		return nil
+	}
+
+	if pol.mode == ModeDryRun {
+		logger.Warnf("Network policy would deny %v calling %v in %v", from, to, ns)
+		return nil
+	}
+	logger.Debugf("Network policy denied %v calling %v in %v", from, to, ns)
+	return ErrDenied
+}
+
+// get the policy of the namespace, loading it if it's stale
+func (p *Policies) get(ns string) (*policy, error) {
+	p.mtx.Lock()
+	c, ok := p.namespaces[ns]
+	if !ok {
+		c = &cached{}
+		p.namespaces[ns] = c
+	}
+	if c.policy != nil && time.Since(c.loaded) < RefreshInterval {
+		pol := c.policy
+		p.mtx.Unlock()
+		return pol, nil
+	}
+	done := c.loading
+	if done == nil {
+		done = make(chan struct{})
+		c.loading = done
+		go p.load(ns, c, done)
+	}
+	pol := c.policy
+	p.mtx.Unlock()
+
+	// a stale policy is used while it's reloaded
+	if pol != nil {
+		return pol, nil
+	}
+
+	select {
+	case <-done:
+	case <-time.After(LoadTimeout):
+		return nil, ErrUnavailable
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if c.policy == nil {
+		return nil, ErrUnavailable
+	}
+	return c.policy, nil
+}
+
+// load the policy of the namespace into the cache, keeping the last policy loaded on error
+func (p *Policies) load(ns string, c *cached, done chan struct{}) {
+	defer close(done)
+
+	pol, err := p.read(ns)
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if c.loading == done {
+		c.loading = nil
+	}
+	if err != nil {
+		logger.Warnf("Error loading the network policies of %v: %v", ns, err)
+		if c.policy != nil {
+			// retry once the policy is stale again rather than on every check
+			c.loaded = time.Now()
+		}
+		return
+	}
+	c.policy = pol
+	c.loaded = time.Now()
+}
+
+func (p *Policies) read(ns string) (*policy, error) {
+	s, err := readMode(p.store, ns)
+	if err != nil {
+		return nil, err
+	}
+	pol := &policy{mode: s.Mode, rules: map[string]bool{}}
+	if s.Mode == ModeDisabled {
+		return pol, nil
+	}
+
+	rules, err := readRules(p.store, ns)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rules {
+		pol.rules[r.From+"/"+r.To] = true
+	}
+	return pol, nil
+}
+
+// invalidate reloads the policy of the namespace if it's been loaded, so changes made by this
+// process apply to its next check
+func (p *Policies) invalidate(ns string) {
+	p.mtx.Lock()
+	c, ok := p.namespaces[ns]
+	p.mtx.Unlock()
+	if ok {
+		p.load(ns, c, make(chan struct{}))
+	}
+}
+
+// invalidate the policy of the namespace cached by the default policies
+func invalidate(ns string) {
+	if DefaultPolicies != nil {
+		DefaultPolicies.invalidate(ns)
+	}
+}
+
+// ParseRule parses a rule of the form "orders -> payments" or the arguments "orders" "payments"
+func ParseRule(args []string) (*Rule, error) {
+	comps := strings.Fields(strings.Replace(strings.Join(args, " "), "->", " ", 1))
+	if len(comps) != 2 {
+		return nil, errors.New("invalid rule, expected the form <from> -> <to>, e.g. orders -> payments")
+	}
+	return &Rule{From: comps[0], To: comps[1]}, nil
+}
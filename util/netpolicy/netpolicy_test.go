@@ -0,0 +1,91 @@
+package netpolicy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	store.DefaultStore = memory.NewStore()
+	DefaultPolicies = New(store.DefaultStore)
+	defer func() { DefaultPolicies = nil }()
+	ns := "foo"
+
+	assert.NoError(t, Allow(ns, &Rule{From: "orders", To: "payments"}))
+
+	// calls are allowed until the namespace opts in
+	assert.NoError(t, DefaultPolicies.Check(ns, "users", "payments"))
+
+	assert.NoError(t, SetMode(ns, &Settings{Mode: ModeDryRun}))
+	assert.NoError(t, DefaultPolicies.Check(ns, "users", "payments"))
+
+	assert.NoError(t, SetMode(ns, &Settings{Mode: ModeEnforce}))
+	assert.NoError(t, DefaultPolicies.Check(ns, "orders", "payments"))
+	assert.NoError(t, DefaultPolicies.Check(ns, "orders", "orders"))
+	assert.Equal(t, ErrDenied, DefaultPolicies.Check(ns, "users", "payments"))
+	assert.Equal(t, ErrDenied, DefaultPolicies.Check(ns, "payments", "orders"))
+
+	assert.NoError(t, Revoke(ns, &Rule{From: "orders", To: "payments"}))
+	assert.Equal(t, ErrDenied, DefaultPolicies.Check(ns, "orders", "payments"))
+
+	assert.Error(t, SetMode(ns, &Settings{Mode: "foo"}))
+}
+
+// failingStore returns an error from reads once failing is set
+type failingStore struct {
+	store.Store
+	failing bool
+}
+
+func (f *failingStore) Read(key string, opts ...store.ReadOption) ([]*store.Record, error) {
+	if f.failing {
+		return nil, errors.New("store unavailable")
+	}
+	return f.Store.Read(key, opts...)
+}
+
+func TestCheckLoadError(t *testing.T) {
+	st := &failingStore{Store: memory.NewStore()}
+	ns := "foo"
+	assert.NoError(t, st.Write(store.NewRecord(settingsKey, &Settings{Mode: ModeEnforce}), store.WriteTo(ns, table)))
+
+	// the first check waits for the policies to load
+	p := New(st)
+	assert.Equal(t, ErrDenied, p.Check(ns, "users", "payments"))
+
+	// the last policies loaded are kept if they can't be reloaded
+	st.failing = true
+	defer func(d time.Duration) { RefreshInterval = d }(RefreshInterval)
+	RefreshInterval = 0
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, ErrDenied, p.Check(ns, "users", "payments"))
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	// calls aren't allowed if the policies have never been loaded
+	p = New(st)
+	assert.Equal(t, ErrUnavailable, p.Check(ns, "users", "payments"))
+}
+
+func TestParseRule(t *testing.T) {
+	r, err := ParseRule([]string{"orders -> payments"})
+	assert.NoError(t, err)
+	assert.Equal(t, "orders", r.From)
+	assert.Equal(t, "payments", r.To)
+
+	r, err = ParseRule([]string{"orders", "->", "payments"})
+	assert.NoError(t, err)
+	assert.Equal(t, "payments", r.To)
+
+	r, err = ParseRule([]string{"orders", "payments"})
+	assert.NoError(t, err)
+	assert.Equal(t, "orders", r.From)
+
+	_, err = ParseRule([]string{"orders"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,54 @@
+package ownership
+
+import (
+	"github.com/micro/micro/v3/util/auth/namespace"
+)
+
+// Options of a router
+type Options struct {
+	// Namespace the owners are looked up in when the namespace of an alert isn't known
+	Namespace string
+	// Topic the alerts are consumed from
+	Topic string
+	// Group the router consumes the alerts as, so each alert is routed by a single instance
+	Group string
+	// Notifiers the alerts are sent with
+	Notifiers []Notifier
+}
+
+// Option sets an option of a router
+type Option func(o *Options)
+
+func newOptions(opts ...Option) Options {
+	o := Options{
+		Namespace: namespace.DefaultNamespace,
+		Topic:     "alerts",
+		Group:     "ownership",
+	}
+	for _, fn := range opts {
+		fn(&o)
+	}
+	return o
+}
+
+// Namespace sets the namespace the owners are looked up in when the namespace of an alert isn't
+// known
+func Namespace(ns string) Option {
+	return func(o *Options) {
+		o.Namespace = ns
+	}
+}
+
+// Topic sets the topic the alerts are consumed from
+func Topic(t string) Option {
+	return func(o *Options) {
+		o.Topic = t
+	}
+}
+
+// WithNotifier adds a notifier the alerts are sent with
+func WithNotifier(n Notifier) Option {
+	return func(o *Options) {
+		o.Notifiers = append(o.Notifiers, n)
+	}
+}
@@ -0,0 +1,212 @@
+// Package ownership records the team which owns each service, along with how to reach them, so
+// operators know who to contact about a service and alerts are routed to the right team.
+//
+// Services register their owner in the metadata of their nodes and in the store of their
+// namespace, so the owner of a service is still known while none of its nodes are running. The
+// stored owner is keyed by the account which registered it, and an account bound to a service
+// can only register the owner of that service.
+package ownership
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/store"
+	inauth "github.com/micro/micro/v3/util/auth"
+)
+
+const (
+	// MetadataTeam is the metadata key of the team which owns the service
+	MetadataTeam = "owner.team"
+	// MetadataOncall is the metadata key of the oncall rotation of the team
+	MetadataOncall = "owner.oncall"
+	// MetadataSlack is the metadata key of the slack channel of the team
+	MetadataSlack = "owner.slack"
+	// MetadataRunbook is the metadata key of the url of the runbook of the service
+	MetadataRunbook = "owner.runbook"
+)
+
+var (
+	// ErrNotFound is returned when the owner of a service isn't known
+	ErrNotFound = errors.New("owner not found")
+
+	table = "ownership"
+)
+
+// Owner of a service
+type Owner struct {
+	Service string `json:"service"`
+	Team    string `json:"team"`
+	// Oncall is the rotation or person paged for the service, e.g. payments-primary
+	Oncall string `json:"oncall,omitempty"`
+	// Slack is the channel of the team, e.g. #payments
+	Slack string `json:"slack,omitempty"`
+	// Runbook is the url of the runbook of the service
+	Runbook string `json:"runbook,omitempty"`
+	// Account which registered the owner
+	Account string    `json:"account,omitempty"`
+	Updated time.Time `json:"updated"`
+}
+
+// Validate the owner, the team is required
+func (o *Owner) Validate() error {
+	if len(o.Team) == 0 {
+		return errors.New("missing team")
+	}
+	if len(o.Runbook) > 0 {
+		u, err := url.Parse(o.Runbook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+			return fmt.Errorf("invalid runbook url %q", o.Runbook)
+		}
+	}
+	return nil
+}
+
+// Metadata returns the owner as the metadata of a node
+func (o *Owner) Metadata() map[string]string {
+	md := map[string]string{MetadataTeam: o.Team}
+	if len(o.Oncall) > 0 {
+		md[MetadataOncall] = o.Oncall
+	}
+	if len(o.Slack) > 0 {
+		md[MetadataSlack] = o.Slack
+	}
+	if len(o.Runbook) > 0 {
+		md[MetadataRunbook] = o.Runbook
+	}
+	return md
+}
+
+// FromMetadata returns the owner in the metadata of a node, or nil if it has no owner
+func FromMetadata(service string, md map[string]string) *Owner {
+	if len(md[MetadataTeam]) == 0 {
+		return nil
+	}
+	return &Owner{
+		Service: service,
+		Team:    md[MetadataTeam],
+		Oncall:  md[MetadataOncall],
+		Slack:   md[MetadataSlack],
+		Runbook: md[MetadataRunbook],
+	}
+}
+
+func ownerKey(service, account string) string {
+	return service + "/" + account
+}
+
+// Register the owner of a service in the namespace with the account of the service. Accounts
+// bound to a service can't register the owner of another service.
+func Register(ns string, acc *auth.Account, o *Owner) error {
+	if len(o.Service) == 0 {
+		return errors.New("missing service")
+	}
+	if acc == nil || len(acc.ID) == 0 {
+		return errors.New("missing account")
+	}
+	if name := acc.Metadata[inauth.ServiceMetadataKey]; len(name) > 0 && name != o.Service {
+		return fmt.Errorf("account %v belongs to %v, not %v", acc.ID, name, o.Service)
+	}
+	if err := o.Validate(); err != nil {
+		return err
+	}
+	o.Account = acc.ID
+	if o.Updated.IsZero() {
+		o.Updated = time.Now()
+	}
+	return store.DefaultStore.Write(store.NewRecord(ownerKey(o.Service, acc.ID), o), store.WriteTo(ns, table))
+}
+
+// Deregister the owners of a service in the namespace registered by any account
+func Deregister(ns, service string) error {
+	recs, err := store.Read(ownerKey(service, ""), store.ReadPrefix(), store.ReadFrom(ns, table))
+	if err != nil && err != store.ErrNotFound {
+		return err
+	}
+	for _, r := range recs {
+		if err := store.DefaultStore.Delete(r.Key, store.DeleteFrom(ns, table)); err != nil && err != store.ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get the owner of a service in the namespace. The owner registered most recently in the store
+// is returned, falling back to the metadata of the nodes of the service which are running.
+func Get(ns, service string) (*Owner, error) {
+	owners, err := read(ns, ownerKey(service, ""))
+	if err != nil {
+		return nil, err
+	}
+	if len(owners) > 0 {
+		return owners[0], nil
+	}
+
+	srvs, err := registry.DefaultRegistry.GetService(service, registry.GetDomain(ns))
+	if err == registry.ErrNotFound {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if o := FromService(srvs); o != nil {
+		return o, nil
+	}
+	return nil, ErrNotFound
+}
+
+// FromService returns the owner in the metadata of the nodes of the service, or nil if none of
+// them have an owner
+func FromService(srvs []*registry.Service) *Owner {
+	for _, srv := range srvs {
+		for _, n := range srv.Nodes {
+			if o := FromMetadata(srv.Name, n.Metadata); o != nil {
+				return o
+			}
+		}
+	}
+	return nil
+}
+
+// List the owners of the services in the namespace, sorted by service. The owner registered
+// most recently is listed for each service.
+func List(ns string) ([]*Owner, error) {
+	owners, err := read(ns, "")
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	res := make([]*Owner, 0, len(owners))
+	for _, o := range owners {
+		if !seen[o.Service] {
+			seen[o.Service] = true
+			res = append(res, o)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Service < res[j].Service })
+	return res, nil
+}
+
+// read the owners stored with the key prefix, most recently registered first
+func read(ns, prefix string) ([]*Owner, error) {
+	recs, err := store.Read(prefix, store.ReadPrefix(), store.ReadFrom(ns, table))
+	if err != nil && err != store.ErrNotFound {
+		return nil, err
+	}
+
+	res := make([]*Owner, 0, len(recs))
+	for _, r := range recs {
+		var o Owner
+		if err := r.Decode(&o); err != nil {
+			return nil, err
+		}
+		res = append(res, &o)
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Updated.After(res[j].Updated) })
+	return res, nil
+}
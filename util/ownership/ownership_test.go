@@ -0,0 +1,130 @@
+package ownership
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/registry"
+	memregistry "github.com/micro/micro/v3/service/registry/memory"
+	"github.com/micro/micro/v3/service/store"
+	memstore "github.com/micro/micro/v3/service/store/memory"
+	"github.com/micro/micro/v3/util/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwnership(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	registry.DefaultRegistry = memregistry.NewRegistry()
+
+	acc := &auth.Account{ID: "payments-latest", Metadata: map[string]string{"service": "payments"}}
+	assert.Error(t, Register("foo", acc, &Owner{Service: "payments"}))
+	assert.Error(t, Register("foo", acc, &Owner{Service: "payments", Team: "payments", Runbook: "wiki/payments"}))
+	assert.Error(t, Register("foo", nil, &Owner{Service: "payments", Team: "payments"}))
+
+	o := &Owner{Service: "payments", Team: "payments", Oncall: "payments-primary", Slack: "#payments", Runbook: "https://wiki/payments"}
+	assert.NoError(t, Register("foo", acc, o))
+
+	// the account of another service can't register the owner
+	other := &auth.Account{ID: "search-latest", Metadata: map[string]string{"service": "search"}}
+	assert.Error(t, Register("foo", other, &Owner{Service: "payments", Team: "search"}))
+
+	got, err := Get("foo", "payments")
+	assert.NoError(t, err)
+	assert.Equal(t, "payments-primary", got.Oncall)
+	assert.Equal(t, "#payments", got.Slack)
+	assert.Equal(t, "payments-latest", got.Account)
+
+	// the owner registered most recently is returned
+	assert.NoError(t, Register("foo", &auth.Account{ID: "payments-v2"}, &Owner{Service: "payments", Team: "billing", Updated: time.Now().Add(time.Minute)}))
+	got, err = Get("foo", "payments")
+	assert.NoError(t, err)
+	assert.Equal(t, "billing", got.Team)
+
+	// owners are kept per namespace
+	_, err = Get("bar", "payments")
+	assert.Equal(t, ErrNotFound, err)
+
+	// the owner in the metadata of running services is used when it isn't stored
+	assert.NoError(t, registry.DefaultRegistry.Register(&registry.Service{
+		Name:  "search",
+		Nodes: []*registry.Node{{Id: "search-1", Address: "localhost:9090", Metadata: (&Owner{Team: "discovery"}).Metadata()}},
+	}, registry.RegisterDomain("foo")))
+	got, err = Get("foo", "search")
+	assert.NoError(t, err)
+	assert.Equal(t, "discovery", got.Team)
+
+	owners, err := List("foo")
+	assert.NoError(t, err)
+	assert.Len(t, owners, 1)
+
+	assert.NoError(t, Deregister("foo", "payments"))
+	_, err = Get("foo", "payments")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestRouter(t *testing.T) {
+	store.DefaultStore = memstore.NewStore()
+	registry.DefaultRegistry = memregistry.NewRegistry()
+	assert.NoError(t, Register("foo", &auth.Account{ID: "payments"}, &Owner{Service: "payments", Team: "payments", Slack: "#payments"}))
+
+	var delivered []*Notification
+	var teams []string
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var n Notification
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&n))
+		delivered = append(delivered, &n)
+		teams = append(teams, r.URL.Path)
+		assert.NotEmpty(t, r.Header.Get(webhook.Header))
+	}))
+	defer hook.Close()
+
+	var posted []map[string]string
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		var msg map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		posted = append(posted, msg)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer slack.Close()
+	SlackURL = slack.URL
+
+	r := NewRouter(
+		WithNotifier(NewSlackNotifier("token", "#alerts")),
+		WithNotifier(NewWebhookNotifier(map[string]string{"payments": hook.URL + "/payments", "*": hook.URL + "/default"}, "secret")),
+	)
+
+	// alerts are routed to the team which owns the service
+	n, err := r.Route("foo", map[string]interface{}{"type": "anomaly", "service": "payments", "metric": "errors"})
+	assert.NoError(t, err)
+	assert.Equal(t, "payments", n.Team())
+	assert.Equal(t, "#payments", posted[0]["channel"])
+	assert.Contains(t, posted[0]["text"], "[anomaly] alert for payments: metric=errors")
+	assert.Equal(t, "/payments", teams[0])
+	assert.Equal(t, "errors", delivered[0].Alert["metric"])
+
+	// alerts about services without an owner go to the defaults
+	n, err = r.Route("foo", map[string]interface{}{"type": "skew", "service": "search"})
+	assert.NoError(t, err)
+	assert.Nil(t, n.Owner)
+	assert.Equal(t, "#alerts", posted[1]["channel"])
+	assert.Equal(t, "/default", teams[1])
+}
+
+func TestAlertNamespace(t *testing.T) {
+	alert := map[string]interface{}{"service": "payments", "namespace": "bar"}
+
+	// alerts published by a namespace are about its services
+	ev := events.Event{Metadata: map[string]string{events.PublisherNamespaceKey: "foo"}}
+	assert.Equal(t, "foo", alertNamespace(ev, alert))
+
+	// the platform sets the namespace the alert is about
+	ev = events.Event{Metadata: map[string]string{events.PublisherNamespaceKey: "micro"}}
+	assert.Equal(t, "bar", alertNamespace(ev, alert))
+	assert.Empty(t, alertNamespace(ev, map[string]interface{}{"service": "payments"}))
+}
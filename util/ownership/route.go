@@ -0,0 +1,267 @@
+package ownership
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/util/auth/namespace"
+	"github.com/micro/micro/v3/util/webhook"
+)
+
+// Notification of an alert sent to the team which owns the service
+type Notification struct {
+	// Type of the alert, e.g. anomaly
+	Type    string `json:"type"`
+	Service string `json:"service"`
+	// Owner of the service, nil if it isn't known
+	Owner *Owner `json:"owner,omitempty"`
+	// Alert as it was published
+	Alert map[string]interface{} `json:"alert"`
+}
+
+// Team returns the team which owns the service, or an empty string if it isn't known
+func (n *Notification) Team() string {
+	if n.Owner == nil {
+		return ""
+	}
+	return n.Owner.Team
+}
+
+// Text summarises the notification in a single line
+func (n *Notification) Text() string {
+	keys := make([]string, 0, len(n.Alert))
+	for k := range n.Alert {
+		if k != "type" && k != "service" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	fields := make([]string, len(keys))
+	for i, k := range keys {
+		fields[i] = fmt.Sprintf("%v=%v", k, n.Alert[k])
+	}
+
+	text := fmt.Sprintf("[%v] alert for %v: %v", n.Type, n.Service, strings.Join(fields, " "))
+	if n.Owner == nil {
+		return text + " (no owner registered)"
+	}
+	if len(n.Owner.Oncall) > 0 {
+		text += fmt.Sprintf(" oncall: %v", n.Owner.Oncall)
+	}
+	if len(n.Owner.Runbook) > 0 {
+		text += fmt.Sprintf(" runbook: %v", n.Owner.Runbook)
+	}
+	return text
+}
+
+// Notifier sends notifications to the teams
+type Notifier interface {
+	Notify(n *Notification) error
+	String() string
+}
+
+// Router routes the alerts published by the services to the teams which own them
+type Router struct {
+	opts Options
+}
+
+// NewRouter returns a router of alerts
+func NewRouter(opts ...Option) *Router {
+	return &Router{opts: newOptions(opts...)}
+}
+
+// Route an alert to the team which owns the service it's about, looking the owner up in the
+// namespace of the alert. Alerts about services without an owner are still sent, so the
+// notifiers can fall back to a default destination.
+func (r *Router) Route(ns string, alert map[string]interface{}) (*Notification, error) {
+	n := &Notification{Alert: alert}
+	n.Type, _ = alert["type"].(string)
+	n.Service, _ = alert["service"].(string)
+	if len(ns) == 0 {
+		ns = r.opts.Namespace
+	}
+
+	if len(n.Service) > 0 {
+		o, err := Get(ns, n.Service)
+		if err != nil && err != ErrNotFound {
+			logger.Errorf("Error looking up the owner of %v: %v", n.Service, err)
+		}
+		n.Owner = o
+	}
+
+	var errs []string
+	for _, nt := range r.opts.Notifiers {
+		if err := nt.Notify(n); err != nil {
+			errs = append(errs, fmt.Sprintf("%v: %v", nt, err))
+		}
+	}
+	if len(errs) > 0 {
+		return n, fmt.Errorf("error sending notification: %v", strings.Join(errs, ", "))
+	}
+	return n, nil
+}
+
+// Run routes the alerts until the context is cancelled
+func (r *Router) Run(ctx context.Context) error {
+	evs, err := events.Consume(r.opts.Topic, events.WithGroup(r.opts.Group))
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-evs:
+			if !ok {
+				return fmt.Errorf("alerts stream closed")
+			}
+
+			var alert map[string]interface{}
+			if err := ev.Unmarshal(&alert); err != nil {
+				logger.Errorf("Error decoding alert %v: %v", ev.ID, err)
+				continue
+			}
+			if _, err := r.Route(alertNamespace(ev, alert), alert); err != nil {
+				logger.Errorf("Error routing alert %v: %v", ev.ID, err)
+			}
+		}
+	}
+}
+
+// alertNamespace returns the namespace the alert is about. Alerts published by a namespace are
+// about its services, the platform can publish alerts about any namespace in their namespace
+// field, e.g. vulnerability alerts.
+func alertNamespace(ev events.Event, alert map[string]interface{}) string {
+	if ns := ev.Metadata[events.PublisherNamespaceKey]; len(ns) > 0 && ns != namespace.DefaultNamespace {
+		return ns
+	}
+	ns, _ := alert["namespace"].(string)
+	return ns
+}
+
+// httpClient is used by the notifiers
+var httpClient = &http.Client{Timeout: time.Second * 10}
+
+// SlackURL is the endpoint of the slack api messages are posted to
+var SlackURL = "https://slack.com/api/chat.postMessage"
+
+type slackNotifier struct {
+	token   string
+	channel string
+}
+
+// NewSlackNotifier returns a notifier which posts to the slack channel of the team with the bot
+// token. Notifications for services without a channel are posted to the default channel, or
+// dropped if there isn't one.
+func NewSlackNotifier(token, defaultChannel string) Notifier {
+	return &slackNotifier{token: token, channel: defaultChannel}
+}
+
+func (s *slackNotifier) Notify(n *Notification) error {
+	channel := s.channel
+	if n.Owner != nil && len(n.Owner.Slack) > 0 {
+		channel = n.Owner.Slack
+	}
+	if len(channel) == 0 {
+		return nil
+	}
+
+	b, _ := json.Marshal(map[string]string{"channel": channel, "text": n.Text()})
+	req, err := http.NewRequest("POST", SlackURL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+s.token)
+
+	rsp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	var res struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(rsp.Body).Decode(&res); err != nil {
+		return fmt.Errorf("unexpected response %v from slack", rsp.Status)
+	}
+	if !res.OK {
+		return fmt.Errorf("error posting to %v: %v", channel, res.Error)
+	}
+	return nil
+}
+
+func (s *slackNotifier) String() string {
+	return "slack"
+}
+
+type webhookNotifier struct {
+	routes  map[string]string
+	secrets []string
+}
+
+// NewWebhookNotifier returns a notifier which posts the notifications to the webhook of the team
+// which owns the service, e.g. the integration of their paging provider. The routes map teams to
+// their webhook, a route for the team "*" receives the notifications of every other team and
+// services without an owner. Deliveries are signed with the secrets, see the webhook package.
+func NewWebhookNotifier(routes map[string]string, secrets ...string) Notifier {
+	return &webhookNotifier{routes: routes, secrets: secrets}
+}
+
+func (w *webhookNotifier) Notify(n *Notification) error {
+	url, ok := w.routes[n.Team()]
+	if !ok {
+		url = w.routes["*"]
+	}
+	if len(url) == 0 {
+		return nil
+	}
+
+	b, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(w.secrets) > 0 {
+		req.Header.Set(webhook.Header, webhook.Sign(b, time.Now(), w.secrets...))
+	}
+
+	rsp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response %v from the webhook of %v", rsp.Status, n.Team())
+	}
+	return nil
+}
+
+func (w *webhookNotifier) String() string {
+	return "webhook"
+}
+
+// ParseRoute parses the route of a team to its webhook in the format team=url
+func ParseRoute(s string) (string, string, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", "", fmt.Errorf("invalid route %q, must be in the format team=url", s)
+	}
+	return parts[0], parts[1], nil
+}
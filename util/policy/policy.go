@@ -0,0 +1,92 @@
+// Package policy is the middleware policy of a namespace, which the services of the namespace
+// apply when they're run. The policy is loaded from the config of the namespace, so the platform
+// decides which built in handler wrappers are mandatory and their defaults rather than the code
+// or flags of each service, e.g.
+//
+//	micro config set policy '{"handlers": ["auth", "ratelimit"], "timeout": "30s", "ratelimit": {"account": {"limit": 100, "window": "1m"}}}'
+package policy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/micro/micro/v3/service/config"
+	"github.com/micro/micro/v3/util/ratelimit"
+	"github.com/micro/micro/v3/util/wrapper"
+)
+
+// ConfigPath is the path of the policy in the config of the namespace
+var ConfigPath = "policy"
+
+// Policy of the built in handler wrappers of the services in a namespace
+type Policy struct {
+	// Handlers are the handler wrappers every service applies, even if the service disabled them
+	Handlers []string `json:"handlers,omitempty"`
+	// Timeout of the requests handled which don't set one, unless the service sets a default
+	Timeout string `json:"timeout,omitempty"`
+	// RateLimit are the limits of the ratelimit wrapper when they're not set in the config
+	RateLimit *ratelimit.Limits `json:"ratelimit,omitempty"`
+}
+
+// Load the policy from the config, nil is returned if the namespace doesn't have a policy
+func Load() (*Policy, error) {
+	if config.DefaultConfig == nil {
+		return nil, nil
+	}
+	val, err := config.Get(ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	if !val.Exists() {
+		return nil, nil
+	}
+	var p *Policy
+	if err := val.Scan(&p); err != nil {
+		return nil, fmt.Errorf("invalid policy: %v", err)
+	}
+	return p, nil
+}
+
+// Missing returns the handler wrappers required by the policy which aren't in the applied list.
+// An error is returned if the policy requires a wrapper which doesn't exist, so a service isn't
+// run without a wrapper the platform requires.
+func (p *Policy) Missing(applied []string) ([]string, error) {
+	var missing []string
+	for _, n := range p.Handlers {
+		if contains(applied, n) || contains(missing, n) {
+			continue
+		}
+		if _, err := wrapper.HandlerWrappers(n); err != nil {
+			return nil, err
+		}
+		missing = append(missing, n)
+	}
+	return missing, nil
+}
+
+// Defaults sets the default parameters of the wrappers. Parameters set by the service, e.g. the
+// timeout set with --handler_timeout, take precedence.
+func (p *Policy) Defaults() error {
+	if len(p.Timeout) > 0 {
+		d, err := time.ParseDuration(p.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout %q: %v", p.Timeout, err)
+		}
+		if wrapper.DefaultTimeout == 0 {
+			wrapper.DefaultTimeout = d
+		}
+	}
+	if p.RateLimit != nil {
+		ratelimit.DefaultLimits = p.RateLimit
+	}
+	return nil
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
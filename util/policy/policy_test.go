@@ -0,0 +1,71 @@
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/config"
+	storeConf "github.com/micro/micro/v3/service/config/store"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/micro/micro/v3/util/ratelimit"
+	"github.com/micro/micro/v3/util/wrapper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	conf, err := storeConf.NewConfig(memory.NewStore(), "foo")
+	assert.NoError(t, err)
+	config.DefaultConfig = conf
+	defer func() { config.DefaultConfig = nil }()
+
+	p, err := Load()
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+
+	assert.NoError(t, config.Set(ConfigPath, map[string]interface{}{
+		"handlers":  []string{"auth", "ratelimit"},
+		"timeout":   "30s",
+		"ratelimit": map[string]interface{}{"account": map[string]interface{}{"limit": 100, "window": "1m"}},
+	}))
+	p, err = Load()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"auth", "ratelimit"}, p.Handlers)
+	assert.Equal(t, "30s", p.Timeout)
+	assert.Equal(t, int64(100), p.RateLimit.Account.Limit)
+}
+
+func TestMissing(t *testing.T) {
+	p := &Policy{Handlers: []string{"auth", "ratelimit", "auth"}}
+
+	missing, err := p.Missing([]string{"trace", "auth"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ratelimit"}, missing)
+
+	missing, err = p.Missing(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"auth", "ratelimit"}, missing)
+
+	// a wrapper which doesn't exist can't be applied
+	p.Handlers = append(p.Handlers, "unknown")
+	_, err = p.Missing(nil)
+	assert.Error(t, err)
+}
+
+func TestDefaults(t *testing.T) {
+	defer func() {
+		wrapper.DefaultTimeout = 0
+		ratelimit.DefaultLimits = nil
+	}()
+
+	limits := &ratelimit.Limits{Account: &ratelimit.Limit{Limit: 10, Window: "1m"}}
+	assert.NoError(t, (&Policy{Timeout: "30s", RateLimit: limits}).Defaults())
+	assert.Equal(t, 30*time.Second, wrapper.DefaultTimeout)
+	assert.Equal(t, limits, ratelimit.DefaultLimits)
+
+	// the timeout set by the service takes precedence
+	wrapper.DefaultTimeout = time.Second
+	assert.NoError(t, (&Policy{Timeout: "30s"}).Defaults())
+	assert.Equal(t, time.Second, wrapper.DefaultTimeout)
+
+	assert.Error(t, (&Policy{Timeout: "soon"}).Defaults())
+}
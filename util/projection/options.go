@@ -0,0 +1,87 @@
+package projection
+
+import (
+	"context"
+	"time"
+
+	"github.com/micro/micro/v3/util/namespace"
+)
+
+// ResetFunc clears the read model before the events are replayed
+type ResetFunc func(ctx context.Context) error
+
+// Options of a projection
+type Options struct {
+	// Namespace the offsets of the projection are stored in
+	Namespace string
+	// Topics the events are consumed from
+	Topics []string
+	// Reset the read model before a replay
+	Reset ResetFunc
+	// DedupeWindow is how long the ids of the events handled are kept so events delivered again
+	// aren't handled twice
+	DedupeWindow time.Duration
+	// AckWait is how long the stream waits for an event to be handled before delivering it again
+	AckWait time.Duration
+	// MaxFailures is the number of consecutive events of a topic which can fail to be handled
+	// before the projection is reported unhealthy
+	MaxFailures int
+}
+
+// Option sets an option of a projection
+type Option func(o *Options)
+
+func newOptions(opts ...Option) Options {
+	options := Options{
+		Namespace:    namespace.DefaultNamespace,
+		DedupeWindow: 24 * time.Hour,
+		AckWait:      30 * time.Second,
+		MaxFailures:  5,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	return options
+}
+
+// Namespace sets the namespace the offsets are stored in
+func Namespace(ns string) Option {
+	return func(o *Options) {
+		o.Namespace = ns
+	}
+}
+
+// Topics sets the topics the events are consumed from
+func Topics(topics ...string) Option {
+	return func(o *Options) {
+		o.Topics = append(o.Topics, topics...)
+	}
+}
+
+// Reset sets the func which clears the read model before a replay
+func Reset(fn ResetFunc) Option {
+	return func(o *Options) {
+		o.Reset = fn
+	}
+}
+
+// DedupeWindow sets how long the ids of the events handled are kept
+func DedupeWindow(d time.Duration) Option {
+	return func(o *Options) {
+		o.DedupeWindow = d
+	}
+}
+
+// AckWait sets how long the stream waits for an event to be handled before delivering it again
+func AckWait(d time.Duration) Option {
+	return func(o *Options) {
+		o.AckWait = d
+	}
+}
+
+// MaxFailures sets the number of consecutive failures before the projection is unhealthy
+func MaxFailures(n int) Option {
+	return func(o *Options) {
+		o.MaxFailures = n
+	}
+}
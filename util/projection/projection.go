@@ -0,0 +1,262 @@
+// Package projection runs projection services, which build a read model from the events of
+// source topics. The handler of a projection is called once for each event, the projection takes
+// care of consuming the topics from where it left off, skipping events delivered more than once,
+// replaying the topics to rebuild the read model, and reporting its health.
+package projection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/debug/health"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
+)
+
+var (
+	// ErrMissingTopics is returned when starting a projection without topics
+	ErrMissingTopics = errors.New("missing topics")
+	// ErrRunning is returned when starting a projection which is already running
+	ErrRunning = errors.New("projection is already running")
+
+	table = "projections"
+	// the offset of a topic which hasn't been consumed, the start of the stream
+	start = time.Unix(1, 0)
+)
+
+// Handler applies an event to the read model
+type Handler func(ctx context.Context, ev *events.Event) error
+
+// Status of a topic consumed by the projection
+type Status struct {
+	Topic string `json:"topic"`
+	// Offset is the timestamp of the latest event handled
+	Offset time.Time `json:"offset"`
+	// Handled is the number of events handled since the projection started
+	Handled int64 `json:"handled"`
+	// Skipped is the number of events delivered again which were skipped
+	Skipped int64 `json:"skipped"`
+	// Failures is the number of consecutive events which failed to be handled
+	Failures int `json:"failures"`
+	// Error of the last event which failed to be handled
+	Error string `json:"error,omitempty"`
+}
+
+// Projection consumes events from the topics and applies them to the read model with the handler
+type Projection struct {
+	name    string
+	handler Handler
+	opts    Options
+
+	sync.Mutex
+	status map[string]*Status
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns a projection with the name, which identifies its offsets so it must be unique in
+// the namespace
+func New(name string, h Handler, opts ...Option) *Projection {
+	return &Projection{
+		name:    name,
+		handler: h,
+		opts:    newOptions(opts...),
+		status:  map[string]*Status{},
+	}
+}
+
+// Start consuming the topics from the offsets stored, or the start of the stream if the topics
+// haven't been consumed by the projection before
+func (p *Projection) Start() error {
+	if len(p.opts.Topics) == 0 {
+		return ErrMissingTopics
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	if p.cancel != nil {
+		return ErrRunning
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	for _, topic := range p.opts.Topics {
+		offset, err := p.readOffset(topic)
+		if err != nil {
+			cancel()
+			return err
+		}
+		evs, err := events.Consume(topic,
+			events.WithGroup(p.opts.Namespace+"."+p.name),
+			events.WithOffset(offset),
+			events.WithAutoAck(false, p.opts.AckWait),
+			events.WithContext(ctx),
+		)
+		if err != nil {
+			cancel()
+			return fmt.Errorf("error consuming %v: %v", topic, err)
+		}
+
+		p.status[topic] = &Status{Topic: topic, Offset: offset}
+		p.wg.Add(1)
+		go p.consume(ctx, topic, evs)
+	}
+	p.cancel = cancel
+
+	health.Register("projection "+p.name, p.check)
+	return nil
+}
+
+// Stop consuming the topics, waiting for the events being handled
+func (p *Projection) Stop() error {
+	p.Lock()
+	cancel := p.cancel
+	p.cancel = nil
+	p.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	p.wg.Wait()
+	health.Deregister("projection " + p.name)
+	return nil
+}
+
+// Replay the topics from the start of the stream. The projection is stopped, the read model is
+// reset and the offsets and ids of the events handled are cleared before starting again.
+func (p *Projection) Replay(ctx context.Context) error {
+	if err := p.Stop(); err != nil {
+		return err
+	}
+	if p.opts.Reset != nil {
+		if err := p.opts.Reset(ctx); err != nil {
+			return fmt.Errorf("error resetting the read model: %v", err)
+		}
+	}
+
+	keys, err := store.List(store.ListPrefix(p.name+"/"), store.ListFrom(p.opts.Namespace, table))
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := store.DefaultStore.Delete(k, store.DeleteFrom(p.opts.Namespace, table)); err != nil {
+			return err
+		}
+	}
+	return p.Start()
+}
+
+// Status of the topics consumed
+func (p *Projection) Status() []*Status {
+	p.Lock()
+	defer p.Unlock()
+
+	status := make([]*Status, 0, len(p.opts.Topics))
+	for _, t := range p.opts.Topics {
+		if s, ok := p.status[t]; ok {
+			cp := *s
+			status = append(status, &cp)
+		}
+	}
+	return status
+}
+
+// check reports the projection unhealthy if a topic has failed too many events in a row
+func (p *Projection) check() error {
+	for _, s := range p.Status() {
+		if p.opts.MaxFailures > 0 && s.Failures >= p.opts.MaxFailures {
+			return fmt.Errorf("%d events of %v failed, last error: %v", s.Failures, s.Topic, s.Error)
+		}
+	}
+	return nil
+}
+
+func (p *Projection) consume(ctx context.Context, topic string, evs <-chan events.Event) {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-evs:
+			if !ok {
+				return
+			}
+			p.handle(ctx, topic, &ev)
+		}
+	}
+}
+
+func (p *Projection) handle(ctx context.Context, topic string, ev *events.Event) {
+	dedupeKey := p.name + "/handled/" + topic + "/" + ev.ID
+
+	// skip events delivered again, e.g. after a restart from the offset
+	if recs, err := store.Read(dedupeKey, store.ReadFrom(p.opts.Namespace, table)); err == nil && len(recs) > 0 {
+		p.update(topic, func(s *Status) { s.Skipped++ })
+		ev.Ack()
+		return
+	}
+
+	if err := p.handler(ctx, ev); err != nil {
+		logger.Errorf("Error handling event %v of %v in projection %v: %v", ev.ID, topic, p.name, err)
+		p.update(topic, func(s *Status) {
+			s.Failures++
+			s.Error = err.Error()
+		})
+		ev.Nack()
+		return
+	}
+
+	rec := store.NewRecord(dedupeKey, ev.Timestamp)
+	rec.Expiry = p.opts.DedupeWindow
+	if err := store.DefaultStore.Write(rec, store.WriteTo(p.opts.Namespace, table)); err != nil {
+		logger.Warnf("Error recording event %v of %v in projection %v: %v", ev.ID, topic, p.name, err)
+	}
+
+	var advanced bool
+	p.update(topic, func(s *Status) {
+		s.Handled++
+		s.Failures = 0
+		s.Error = ""
+		if ev.Timestamp.After(s.Offset) {
+			s.Offset = ev.Timestamp
+			advanced = true
+		}
+	})
+	if advanced {
+		if err := p.writeOffset(topic, ev.Timestamp); err != nil {
+			logger.Warnf("Error writing offset of %v in projection %v: %v", topic, p.name, err)
+		}
+	}
+	ev.Ack()
+}
+
+func (p *Projection) update(topic string, fn func(s *Status)) {
+	p.Lock()
+	defer p.Unlock()
+	if s, ok := p.status[topic]; ok {
+		fn(s)
+	}
+}
+
+func (p *Projection) readOffset(topic string) (time.Time, error) {
+	recs, err := store.Read(p.name+"/offset/"+topic, store.ReadFrom(p.opts.Namespace, table))
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		return start, nil
+	} else if err != nil {
+		return time.Time{}, fmt.Errorf("error reading the offset of %v: %v", topic, err)
+	}
+	var offset time.Time
+	if err := recs[0].Decode(&offset); err != nil {
+		return time.Time{}, err
+	}
+	return offset, nil
+}
+
+func (p *Projection) writeOffset(topic string, offset time.Time) error {
+	rec := store.NewRecord(p.name+"/offset/"+topic, offset)
+	return store.DefaultStore.Write(rec, store.WriteTo(p.opts.Namespace, table))
+}
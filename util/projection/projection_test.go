@@ -0,0 +1,161 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/debug/health"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// stream delivers the events published to it, replaying them from the offset of a consumer
+type stream struct {
+	sync.Mutex
+	evs  []events.Event
+	subs map[string][]chan events.Event
+}
+
+func (s *stream) Publish(topic string, msg interface{}, opts ...events.PublishOption) error {
+	ev := msg.(events.Event)
+	s.Lock()
+	defer s.Unlock()
+	s.evs = append(s.evs, ev)
+	for _, c := range s.subs[topic] {
+		c <- ack(ev)
+	}
+	return nil
+}
+
+func (s *stream) Consume(topic string, opts ...events.ConsumeOption) (<-chan events.Event, error) {
+	var options events.ConsumeOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	c := make(chan events.Event, 100)
+	s.Lock()
+	defer s.Unlock()
+	for _, ev := range s.evs {
+		if ev.Topic == topic && !ev.Timestamp.Before(options.Offset) {
+			c <- ack(ev)
+		}
+	}
+	s.subs[topic] = append(s.subs[topic], c)
+	return c, nil
+}
+
+func ack(ev events.Event) events.Event {
+	ev.SetAckFunc(func() error { return nil })
+	ev.SetNackFunc(func() error { return nil })
+	return ev
+}
+
+func setup(t *testing.T) *stream {
+	store.DefaultStore = memory.NewStore()
+	s := &stream{subs: map[string][]chan events.Event{}}
+	events.DefaultStream = s
+	return s
+}
+
+func publish(s *stream, topic, id string, ts time.Time) {
+	s.Publish(topic, events.Event{ID: id, Topic: topic, Timestamp: ts})
+}
+
+// readModel counts the events handled
+type readModel struct {
+	sync.Mutex
+	counts map[string]int
+	fail   bool
+}
+
+func (r *readModel) handle(ctx context.Context, ev *events.Event) error {
+	r.Lock()
+	defer r.Unlock()
+	if r.fail {
+		return errors.New("boom")
+	}
+	r.counts[ev.ID]++
+	return nil
+}
+
+func (r *readModel) reset(ctx context.Context) error {
+	r.Lock()
+	defer r.Unlock()
+	r.counts = map[string]int{}
+	return nil
+}
+
+func (r *readModel) total() int {
+	r.Lock()
+	defer r.Unlock()
+	var n int
+	for _, c := range r.counts {
+		n += c
+	}
+	return n
+}
+
+func TestProjection(t *testing.T) {
+	s := setup(t)
+	now := time.Now().Truncate(time.Second)
+	for i := 0; i < 3; i++ {
+		publish(s, "orders", fmt.Sprintf("order-%d", i), now.Add(time.Duration(i)*time.Second))
+	}
+
+	rm := &readModel{counts: map[string]int{}}
+	p := New("orders-view", rm.handle, Topics("orders"), Reset(rm.reset))
+	assert.Equal(t, ErrMissingTopics, New("foo", rm.handle).Start())
+
+	// the events published before the projection started are handled
+	assert.NoError(t, p.Start())
+	assert.Equal(t, ErrRunning, p.Start())
+	assert.Eventually(t, func() bool { return rm.total() == 3 }, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool { return p.Status()[0].Offset.Equal(now.Add(2 * time.Second)) }, time.Second, 10*time.Millisecond)
+
+	// events delivered again are skipped
+	publish(s, "orders", "order-1", now.Add(time.Second))
+	assert.Eventually(t, func() bool { return p.Status()[0].Skipped == 1 }, time.Second, 10*time.Millisecond)
+	assert.NoError(t, p.Stop())
+
+	// restarting resumes from the offset, the last event is delivered again but not handled
+	assert.NoError(t, p.Start())
+	assert.Eventually(t, func() bool { return p.Status()[0].Skipped == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 3, rm.total())
+	assert.Equal(t, int64(0), p.Status()[0].Handled)
+
+	// replaying resets the read model and handles every event again
+	assert.NoError(t, p.Replay(context.TODO()))
+	assert.Eventually(t, func() bool { return p.Status()[0].Handled == 3 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 3, rm.total())
+	assert.NoError(t, p.Stop())
+}
+
+func TestProjectionHealth(t *testing.T) {
+	s := setup(t)
+
+	rm := &readModel{counts: map[string]int{}, fail: true}
+	p := New("payments-view", rm.handle, Topics("payments"), MaxFailures(2))
+	assert.NoError(t, p.Start())
+	defer p.Stop()
+
+	publish(s, "payments", "payment-1", time.Now())
+	assert.Eventually(t, func() bool { return p.Status()[0].Failures == 1 }, time.Second, 10*time.Millisecond)
+	assert.Empty(t, health.Run())
+
+	publish(s, "payments", "payment-2", time.Now())
+	assert.Eventually(t, func() bool { return len(health.Run()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "projection payments-view", health.Run()[0].Name)
+
+	// handling an event clears the failures
+	rm.Lock()
+	rm.fail = false
+	rm.Unlock()
+	publish(s, "payments", "payment-3", time.Now())
+	assert.Eventually(t, func() bool { return len(health.Run()) == 0 }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,173 @@
+// Package ratelimit limits the requests a service handles for each account and each namespace
+// using token buckets. The limits are loaded from the config service at runtime so they can be
+// changed without redeploying, e.g.
+//
+//	micro config set ratelimit '{"account": {"limit": 100, "window": "1m"}, "namespaces": {"foo": {"limit": 1000, "window": "1m", "burst": 2000}}}'
+//
+// The buckets are kept by the quota service, so the limits apply across every instance of a
+// service rather than to each one.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/config"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/quota"
+)
+
+var (
+	// ConfigPath is the path of the limits in the config service
+	ConfigPath = "ratelimit"
+	// RefreshInterval is how often the limits are reloaded from the config service
+	RefreshInterval = time.Minute
+	// DefaultLimiter is used by the rate limit handler wrapper
+	DefaultLimiter = New()
+	// DefaultLimits are used when the limits aren't set in the config service, e.g. the limits of
+	// the middleware policy of the namespace
+	DefaultLimits *Limits
+)
+
+// Limit of the requests in a window
+type Limit struct {
+	// Limit is the number of requests allowed per window
+	Limit int64 `json:"limit"`
+	// Window the limit applies to, e.g. 1m
+	Window string `json:"window"`
+	// Burst is the number of requests which can be made at once, defaults to the limit
+	Burst int64 `json:"burst,omitempty"`
+}
+
+// Limits loaded from the config service. The limits of a specific account or namespace override
+// the defaults, a missing limit is unlimited.
+type Limits struct {
+	// Account is the default limit of each account
+	Account *Limit `json:"account,omitempty"`
+	// Namespace is the default limit of each namespace
+	Namespace *Limit `json:"namespace,omitempty"`
+	// Accounts limits keyed by account id
+	Accounts map[string]*Limit `json:"accounts,omitempty"`
+	// Namespaces limits keyed by namespace
+	Namespaces map[string]*Limit `json:"namespaces,omitempty"`
+}
+
+// Limiter enforces the limits
+type Limiter struct {
+	sync.Mutex
+	limits  *Limits
+	loaded  time.Time
+	loading bool
+
+	// load the limits, replaced in tests
+	load func() (*Limits, error)
+}
+
+// New returns a limiter which loads the limits from the config service
+func New() *Limiter {
+	return &Limiter{load: loadConfig}
+}
+
+// Allow a request by the account in the namespace. The account is blank for requests which
+// aren't authenticated, which are only limited by the namespace. The buckets are kept in the
+// namespace by the quota service.
+func (l *Limiter) Allow(ns, account string) (*quota.Result, error) {
+	res := &quota.Result{Allowed: true, Remaining: -1}
+	limits := l.refresh()
+	if limits == nil {
+		return res, nil
+	}
+
+	if len(account) > 0 {
+		lim := limits.Account
+		if a, ok := limits.Accounts[account]; ok {
+			lim = a
+		}
+		r, err := take("ratelimit/account/"+account, ns, lim)
+		if err != nil {
+			return nil, err
+		}
+		if r != nil {
+			if !r.Allowed {
+				return r, nil
+			}
+			res = r
+		}
+	}
+
+	lim := limits.Namespace
+	if n, ok := limits.Namespaces[ns]; ok {
+		lim = n
+	}
+	r, err := take("ratelimit/namespace", ns, lim)
+	if err != nil {
+		return nil, err
+	}
+	if r != nil && (!r.Allowed || res.Remaining < 0 || r.Remaining < res.Remaining) {
+		res = r
+	}
+	return res, nil
+}
+
+// take a token from the bucket with the key, returning nil if the limit is unlimited
+func take(key, ns string, lim *Limit) (*quota.Result, error) {
+	if lim == nil || lim.Limit <= 0 {
+		return nil, nil
+	}
+	window, err := time.ParseDuration(lim.Window)
+	if err != nil || window <= 0 {
+		return nil, nil
+	}
+	return quota.Allow(key,
+		quota.Limit(lim.Limit, window),
+		quota.Burst(lim.Burst),
+		quota.AllowNamespace(ns),
+	)
+}
+
+// refresh returns the limits, reloading them in the background if they're stale. Until the
+// limits are first loaded requests aren't limited.
+func (l *Limiter) refresh() *Limits {
+	l.Lock()
+	defer l.Unlock()
+
+	if time.Since(l.loaded) < RefreshInterval || l.loading {
+		return l.limits
+	}
+	l.loading = true
+
+	go func() {
+		limits, err := l.load()
+
+		l.Lock()
+		defer l.Unlock()
+		l.loading = false
+		l.loaded = time.Now()
+		if err != nil {
+			// keep the last limits loaded
+			logger.Warnf("Error loading rate limits: %v", err)
+			return
+		}
+		l.limits = limits
+	}()
+
+	return l.limits
+}
+
+func loadConfig() (*Limits, error) {
+	if config.DefaultConfig == nil {
+		return DefaultLimits, nil
+	}
+	val, err := config.Get(ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	if !val.Exists() {
+		return DefaultLimits, nil
+	}
+	var limits *Limits
+	if err := val.Scan(&limits); err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
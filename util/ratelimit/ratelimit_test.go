@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/quota"
+	qstore "github.com/micro/micro/v3/service/quota/store"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func testLimiter(limits *Limits) *Limiter {
+	quota.DefaultQuota = qstore.NewQuota(memory.NewStore())
+	l := New()
+	l.limits = limits
+	l.loaded = time.Now()
+	return l
+}
+
+func allowed(t *testing.T, l *Limiter, ns, account string) bool {
+	res, err := l.Allow(ns, account)
+	assert.NoError(t, err)
+	return res.Allowed
+}
+
+func TestAccountLimit(t *testing.T) {
+	l := testLimiter(&Limits{
+		Account:  &Limit{Limit: 2, Window: "1m"},
+		Accounts: map[string]*Limit{"vip": {Limit: 5, Window: "1m"}},
+	})
+
+	for i := 0; i < 2; i++ {
+		assert.True(t, allowed(t, l, "micro", "john"))
+	}
+	res, err := l.Allow("micro", "john")
+	assert.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.True(t, res.RetryAfter > 0)
+
+	// accounts have their own buckets and overrides
+	assert.True(t, allowed(t, l, "micro", "jane"))
+	for i := 0; i < 5; i++ {
+		assert.True(t, allowed(t, l, "micro", "vip"))
+	}
+	assert.False(t, allowed(t, l, "micro", "vip"))
+
+	// requests which aren't authenticated aren't limited by the account limit
+	for i := 0; i < 10; i++ {
+		assert.True(t, allowed(t, l, "micro", ""))
+	}
+}
+
+func TestNamespaceLimit(t *testing.T) {
+	l := testLimiter(&Limits{
+		Namespace:  &Limit{Limit: 3, Window: "1m"},
+		Namespaces: map[string]*Limit{"foo": {Limit: 1, Window: "1m"}},
+	})
+
+	assert.True(t, allowed(t, l, "micro", "john"))
+	assert.True(t, allowed(t, l, "micro", "jane"))
+	assert.True(t, allowed(t, l, "micro", ""))
+	assert.False(t, allowed(t, l, "micro", "john"))
+
+	assert.True(t, allowed(t, l, "foo", "john"))
+	assert.False(t, allowed(t, l, "foo", "jane"))
+}
+
+func TestUnlimited(t *testing.T) {
+	l := New()
+	l.load = func() (*Limits, error) { return nil, nil }
+
+	for i := 0; i < 100; i++ {
+		assert.True(t, allowed(t, l, "micro", "john"))
+	}
+}
+
+func TestSharedLimit(t *testing.T) {
+	limits := &Limits{Account: &Limit{Limit: 2, Window: "1m"}}
+	a := testLimiter(limits)
+
+	// the buckets are shared by every instance using the quota service
+	b := New()
+	b.limits = limits
+	b.loaded = time.Now()
+
+	assert.True(t, allowed(t, a, "micro", "john"))
+	assert.True(t, allowed(t, b, "micro", "john"))
+	assert.False(t, allowed(t, a, "micro", "john"))
+	assert.False(t, allowed(t, b, "micro", "john"))
+}
@@ -0,0 +1,82 @@
+package realtime
+
+import (
+	"context"
+	"time"
+
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/util/namespace"
+)
+
+// Actions an account is authorized for
+const (
+	ActionJoin    = "join"
+	ActionPublish = "publish"
+)
+
+// AuthorizeFunc returns an error if the account can't perform the action on the channel
+type AuthorizeFunc func(ctx context.Context, acc *auth.Account, channel, action string) error
+
+// Options of a realtime
+type Options struct {
+	// Namespace the channels belong to
+	Namespace string
+	// Authorize accounts joining and publishing to channels, any account can if not set
+	Authorize AuthorizeFunc
+	// HistorySize is the number of recent messages returned on join
+	HistorySize int
+	// PresenceTTL is how long a member is present for unless they join again
+	PresenceTTL time.Duration
+	// NotifyPresence pushes a message to the members of a channel when an account joins or leaves
+	NotifyPresence bool
+}
+
+// Option sets an option of a realtime
+type Option func(o *Options)
+
+func newOptions(opts ...Option) Options {
+	options := Options{
+		Namespace:   namespace.DefaultNamespace,
+		HistorySize: 50,
+		PresenceTTL: 5 * time.Minute,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	return options
+}
+
+// Namespace sets the namespace the channels belong to
+func Namespace(ns string) Option {
+	return func(o *Options) {
+		o.Namespace = ns
+	}
+}
+
+// Authorize sets the func which authorizes accounts joining and publishing to channels
+func Authorize(fn AuthorizeFunc) Option {
+	return func(o *Options) {
+		o.Authorize = fn
+	}
+}
+
+// HistorySize sets the number of recent messages returned on join, none are if zero
+func HistorySize(n int) Option {
+	return func(o *Options) {
+		o.HistorySize = n
+	}
+}
+
+// PresenceTTL sets how long a member is present for unless they join again
+func PresenceTTL(d time.Duration) Option {
+	return func(o *Options) {
+		o.PresenceTTL = d
+	}
+}
+
+// NotifyPresence pushes a message to the members of a channel when an account joins or leaves
+func NotifyPresence() Option {
+	return func(o *Options) {
+		o.NotifyPresence = true
+	}
+}
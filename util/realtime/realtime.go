@@ -0,0 +1,279 @@
+// Package realtime implements channels for realtime features, e.g. chat or notifications, on top
+// of the connections service and the events stream. Accounts join a channel, messages published
+// to it are pushed to the websocket and server sent event clients of its members, and recent
+// messages are returned on join.
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/connections"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
+)
+
+var (
+	// ErrMissingChannel is returned when a channel isn't provided
+	ErrMissingChannel = errors.New("missing channel")
+	// ErrInvalidChannel is returned when a channel contains a slash
+	ErrInvalidChannel = errors.New("invalid channel")
+	// ErrUnauthorized is returned when there's no account in the context
+	ErrUnauthorized = errors.New("unauthorized")
+
+	table          = "realtime"
+	presencePrefix = "presence/"
+	topicPrefix    = "realtime."
+	// maxHistory is the number of events read to find the most recent messages of a channel
+	maxHistory uint = 1000
+
+	// push delivers a message to the clients of an account, replaced in tests
+	push = connections.Push
+)
+
+// Message types
+const (
+	TypeMessage = "message"
+	TypeJoin    = "join"
+	TypeLeave   = "leave"
+)
+
+// Message pushed to the members of a channel
+type Message struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Channel string `json:"channel"`
+	// Sender is the account which published the message, joined or left
+	Sender string          `json:"sender,omitempty"`
+	Data   json.RawMessage `json:"data,omitempty"`
+	Time   time.Time       `json:"time"`
+}
+
+// Member of a channel
+type Member struct {
+	Account string    `json:"account"`
+	Session string    `json:"session,omitempty"`
+	Joined  time.Time `json:"joined"`
+}
+
+// Realtime manages the channels of a namespace
+type Realtime struct {
+	opts Options
+}
+
+// New returns a realtime with the options
+func New(opts ...Option) *Realtime {
+	return &Realtime{opts: newOptions(opts...)}
+}
+
+// Join the channel as the account in the context, returning the recent messages published to it.
+// The session limits the messages pushed to a session of the account, e.g. a browser tab, every
+// session of the account receives them if blank. Members are present until they leave or the
+// presence ttl passes without them joining again.
+func (r *Realtime) Join(ctx context.Context, channel, session string) ([]*Message, error) {
+	acc, err := r.authorize(ctx, channel, ActionJoin)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Member{Account: acc.ID, Session: session, Joined: time.Now()}
+	rec := store.NewRecord(presenceKey(channel, acc.ID, session), m)
+	rec.Expiry = r.opts.PresenceTTL
+	if err := store.DefaultStore.Write(rec, store.WriteTo(r.opts.Namespace, table)); err != nil {
+		return nil, err
+	}
+
+	if r.opts.NotifyPresence {
+		r.fanOut(&Message{ID: uuid.New().String(), Type: TypeJoin, Channel: channel, Sender: acc.ID, Time: m.Joined})
+	}
+	return r.History(channel, r.opts.HistorySize)
+}
+
+// Leave the channel as the account in the context
+func (r *Realtime) Leave(ctx context.Context, channel, session string) error {
+	acc, ok := auth.AccountFromContext(ctx)
+	if !ok {
+		return ErrUnauthorized
+	}
+	if err := validate(channel); err != nil {
+		return err
+	}
+
+	err := store.DefaultStore.Delete(presenceKey(channel, acc.ID, session), store.DeleteFrom(r.opts.Namespace, table))
+	if err == store.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if r.opts.NotifyPresence {
+		r.fanOut(&Message{ID: uuid.New().String(), Type: TypeLeave, Channel: channel, Sender: acc.ID, Time: time.Now()})
+	}
+	return nil
+}
+
+// Publish the data, encoded as json, to the channel. The message is published to the events
+// stream so it's kept as the history of the channel and can be consumed by other services, and
+// pushed to the members of the channel.
+func (r *Realtime) Publish(ctx context.Context, channel string, data interface{}) (*Message, error) {
+	acc, err := r.authorize(ctx, channel, ActionPublish)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	msg := &Message{
+		ID:      uuid.New().String(),
+		Type:    TypeMessage,
+		Channel: channel,
+		Sender:  acc.ID,
+		Data:    b,
+		Time:    time.Now(),
+	}
+	if err := events.Publish(topicPrefix+channel, msg, events.WithTimestamp(msg.Time)); err != nil {
+		return nil, err
+	}
+
+	r.fanOut(msg)
+	return msg, nil
+}
+
+// Presence returns the members of the channel
+func (r *Realtime) Presence(channel string) ([]*Member, error) {
+	if err := validate(channel); err != nil {
+		return nil, err
+	}
+
+	recs, err := store.Read(presencePrefix+channel+"/", store.ReadPrefix(), store.ReadFrom(r.opts.Namespace, table))
+	if err == store.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	members := make([]*Member, 0, len(recs))
+	for _, rec := range recs {
+		var m *Member
+		if err := rec.Decode(&m); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Joined.Before(members[j].Joined)
+	})
+	return members, nil
+}
+
+// History returns up to the limit of the most recent messages published to the channel, oldest first
+func (r *Realtime) History(channel string, limit int) ([]*Message, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	evs, err := events.Read(topicPrefix+channel, events.ReadLimit(maxHistory))
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]*Message, 0, len(evs))
+	for _, ev := range evs {
+		var m *Message
+		if err := ev.Unmarshal(&m); err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, m)
+	}
+
+	// the events aren't read in order
+	sort.Slice(msgs, func(i, j int) bool {
+		return msgs[i].Time.Before(msgs[j].Time)
+	})
+	if len(msgs) > limit {
+		msgs = msgs[len(msgs)-limit:]
+	}
+	return msgs, nil
+}
+
+func (r *Realtime) authorize(ctx context.Context, channel, action string) (*auth.Account, error) {
+	if err := validate(channel); err != nil {
+		return nil, err
+	}
+	acc, ok := auth.AccountFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+	if r.opts.Authorize != nil {
+		if err := r.opts.Authorize(ctx, acc, channel, action); err != nil {
+			return nil, err
+		}
+	}
+	return acc, nil
+}
+
+// fanOut pushes the message to the members of its channel. Members which aren't connected miss
+// the message, they can read it from the history when they join again.
+func (r *Realtime) fanOut(msg *Message) {
+	members, err := r.Presence(msg.Channel)
+	if err != nil {
+		logger.Errorf("Error reading the members of %v: %v", msg.Channel, err)
+		return
+	}
+	b, err := json.Marshal(msg)
+	if err != nil {
+		logger.Errorf("Error encoding message: %v", err)
+		return
+	}
+
+	// members which joined without a session receive the message on every session so their
+	// other sessions aren't pushed to again
+	sessions := map[string][]string{}
+	for _, m := range members {
+		if s, ok := sessions[m.Account]; ok && len(s) == 0 {
+			continue
+		} else if len(m.Session) == 0 {
+			sessions[m.Account] = []string{}
+			continue
+		}
+		sessions[m.Account] = append(sessions[m.Account], m.Session)
+	}
+
+	for account, ss := range sessions {
+		opts := []connections.PushOption{connections.PushNamespace(r.opts.Namespace)}
+		if len(ss) == 0 {
+			if _, err := push(account, b, opts...); err != nil {
+				logger.Warnf("Error pushing message to %v: %v", account, err)
+			}
+			continue
+		}
+		for _, s := range ss {
+			if _, err := push(account, b, append(opts, connections.PushSession(s))...); err != nil {
+				logger.Warnf("Error pushing message to %v: %v", account, err)
+			}
+		}
+	}
+}
+
+func validate(channel string) error {
+	if len(channel) == 0 {
+		return ErrMissingChannel
+	}
+	if strings.Contains(channel, "/") {
+		return ErrInvalidChannel
+	}
+	return nil
+}
+
+func presenceKey(channel, account, session string) string {
+	return presencePrefix + strings.Join([]string{channel, account, session}, "/")
+}
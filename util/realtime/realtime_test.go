@@ -0,0 +1,148 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/connections"
+	"github.com/micro/micro/v3/service/events"
+	evstore "github.com/micro/micro/v3/service/events/store"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// stream writes the events published to the events store, as the events service does
+type stream struct{}
+
+func (s *stream) Publish(topic string, msg interface{}, opts ...events.PublishOption) error {
+	var options events.PublishOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return events.DefaultStore.Write(&events.Event{ID: uuid.New().String(), Topic: topic, Timestamp: options.Timestamp, Payload: b})
+}
+
+func (s *stream) Consume(topic string, opts ...events.ConsumeOption) (<-chan events.Event, error) {
+	return nil, errors.New("not implemented")
+}
+
+type pushed struct {
+	account string
+	session string
+	msg     *Message
+}
+
+func setup(t *testing.T) *[]pushed {
+	store.DefaultStore = memory.NewStore()
+	events.DefaultStore = evstore.NewStore(evstore.WithStore(memory.NewStore()))
+	events.DefaultStream = &stream{}
+
+	var mtx sync.Mutex
+	var msgs []pushed
+	push = func(account string, payload []byte, opts ...connections.PushOption) (int64, error) {
+		var options connections.PushOptions
+		for _, o := range opts {
+			o(&options)
+		}
+		var m *Message
+		assert.NoError(t, json.Unmarshal(payload, &m))
+
+		mtx.Lock()
+		defer mtx.Unlock()
+		msgs = append(msgs, pushed{account: account, session: options.Session, msg: m})
+		return 1, nil
+	}
+	return &msgs
+}
+
+func accountContext(id string) context.Context {
+	return auth.ContextWithAccount(context.Background(), &auth.Account{ID: id, Issuer: "micro"})
+}
+
+func TestPublish(t *testing.T) {
+	msgs := setup(t)
+	r := New(HistorySize(2))
+	alice, bob := accountContext("alice"), accountContext("bob")
+
+	history, err := r.Join(alice, "general", "")
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+	_, err = r.Join(bob, "general", "tab1")
+	assert.NoError(t, err)
+	_, err = r.Join(bob, "general", "tab2")
+	assert.NoError(t, err)
+
+	members, err := r.Presence("general")
+	assert.NoError(t, err)
+	assert.Len(t, members, 3)
+
+	msg, err := r.Publish(alice, "general", map[string]string{"text": "hello"})
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", msg.Sender)
+	assert.JSONEq(t, `{"text":"hello"}`, string(msg.Data))
+
+	// bob joined with two sessions so the message is pushed to each of them
+	assert.Len(t, *msgs, 3)
+	sessions := map[string]bool{}
+	for _, p := range *msgs {
+		assert.Equal(t, msg.ID, p.msg.ID)
+		sessions[p.account+"/"+p.session] = true
+	}
+	assert.Equal(t, map[string]bool{"alice/": true, "bob/tab1": true, "bob/tab2": true}, sessions)
+
+	// the most recent messages are returned on join
+	for _, text := range []string{"one", "two"} {
+		time.Sleep(time.Millisecond)
+		_, err = r.Publish(bob, "general", text)
+		assert.NoError(t, err)
+	}
+	history, err = r.Join(accountContext("carol"), "general", "")
+	assert.NoError(t, err)
+	if assert.Len(t, history, 2) {
+		assert.JSONEq(t, `"one"`, string(history[0].Data))
+		assert.JSONEq(t, `"two"`, string(history[1].Data))
+	}
+
+	assert.NoError(t, r.Leave(bob, "general", "tab1"))
+	members, err = r.Presence("general")
+	assert.NoError(t, err)
+	assert.Len(t, members, 3)
+}
+
+func TestAuthorize(t *testing.T) {
+	msgs := setup(t)
+	r := New(NotifyPresence(), Authorize(func(ctx context.Context, acc *auth.Account, channel, action string) error {
+		if action == ActionPublish && acc.ID != "admin" {
+			return errors.New("forbidden")
+		}
+		return nil
+	}))
+
+	_, err := r.Join(context.Background(), "general", "")
+	assert.Equal(t, ErrUnauthorized, err)
+	_, err = r.Join(accountContext("alice"), "a/b", "")
+	assert.Equal(t, ErrInvalidChannel, err)
+
+	_, err = r.Join(accountContext("alice"), "general", "")
+	assert.NoError(t, err)
+	if assert.Len(t, *msgs, 1) {
+		assert.Equal(t, TypeJoin, (*msgs)[0].msg.Type)
+	}
+
+	_, err = r.Publish(accountContext("alice"), "general", "hi")
+	assert.Error(t, err)
+	_, err = r.Publish(accountContext("admin"), "general", "hi")
+	assert.NoError(t, err)
+	assert.Len(t, *msgs, 2)
+}
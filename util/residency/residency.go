@@ -0,0 +1,175 @@
+// Package residency restricts the regions the data of a namespace can be written to. The store
+// and events services check the policy of a namespace before writing its data and reject the
+// write if the backends they use aren't in a permitted region. The policies are kept in the micro
+// namespace, which only the services can read, so they're checked server side rather than by
+// the clients of tenants.
+package residency
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/store"
+)
+
+var (
+	// Region of the backends written to, set using the --region flag. If it's not set writes to
+	// namespaces with a policy are rejected since the region can't be verified.
+	Region string
+
+	// ErrViolation is returned when data is written to a region the policy doesn't permit
+	ErrViolation = errors.New("data residency violation")
+
+	// cacheTTL is how long the policy of a namespace is cached for since it's checked on every
+	// write
+	cacheTTL = time.Minute
+	// errorTTL is how long a policy which couldn't be read is retried after
+	errorTTL = time.Second * 10
+
+	database = "micro"
+	table    = "residency"
+)
+
+// Policy restricts the data of a namespace to the regions listed
+type Policy struct {
+	Namespace string    `json:"namespace"`
+	Regions   []string  `json:"regions"`
+	Account   string    `json:"account"`
+	Updated   time.Time `json:"updated"`
+}
+
+// Permits returns true if data can be written to the region
+func (p *Policy) Permits(region string) bool {
+	for _, r := range p.Regions {
+		if r == region {
+			return true
+		}
+	}
+	return false
+}
+
+type cachedPolicy struct {
+	policy *Policy
+	expiry time.Time
+}
+
+var (
+	mtx   sync.RWMutex
+	cache = map[string]*cachedPolicy{}
+)
+
+// Check returns an error if the data of the namespace can't be written to the region of the
+// backends. Violations are logged so they can be alerted on.
+func Check(ns string) error {
+	p := cached(ns)
+	if p == nil || p.Permits(Region) {
+		return nil
+	}
+
+	logger.Errorf("Rejected write of %v data to region %q, the policy permits %v", ns, Region, p.Regions)
+	return fmt.Errorf("%w: the data of %v can't be written to region %q", ErrViolation, ns, Region)
+}
+
+// cached returns the policy of the namespace, reading it again once it expires. If the policy
+// can't be read the last one read is used, or none if it was never read, so the write path
+// doesn't fail while the store is unavailable. Failures are cached for errorTTL so the store
+// isn't read on every write until it recovers.
+func cached(ns string) *Policy {
+	mtx.RLock()
+	c, ok := cache[ns]
+	mtx.RUnlock()
+	if ok && time.Now().Before(c.expiry) {
+		return c.policy
+	}
+
+	ttl := cacheTTL
+	p, err := Get(ns)
+	if err != nil {
+		logger.Warnf("Error reading the residency policy of %v: %v", ns, err)
+		ttl = errorTTL
+		p = nil
+		if ok {
+			p = c.policy
+		}
+	}
+
+	mtx.Lock()
+	cache[ns] = &cachedPolicy{policy: p, expiry: time.Now().Add(ttl)}
+	mtx.Unlock()
+	return p
+}
+
+// Get the policy of a namespace, nil is returned if it doesn't have one
+func Get(ns string) (*Policy, error) {
+	if store.DefaultStore == nil {
+		return nil, nil
+	}
+	recs, err := store.Read(ns, store.ReadFrom(database, table))
+	if err == store.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var p Policy
+	if err := recs[0].Decode(&p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Set the policy of a namespace
+func Set(p *Policy) error {
+	if len(p.Namespace) == 0 {
+		return errors.New("missing namespace")
+	}
+	if len(p.Regions) == 0 {
+		return errors.New("missing regions")
+	}
+	if p.Updated.IsZero() {
+		p.Updated = time.Now()
+	}
+
+	if err := store.DefaultStore.Write(store.NewRecord(p.Namespace, p), store.WriteTo(database, table)); err != nil {
+		return err
+	}
+	invalidate(p.Namespace)
+	return nil
+}
+
+// Delete the policy of a namespace, allowing its data to be written to any region
+func Delete(ns string) error {
+	err := store.DefaultStore.Delete(ns, store.DeleteFrom(database, table))
+	if err != nil && err != store.ErrNotFound {
+		return err
+	}
+	invalidate(ns)
+	return nil
+}
+
+// List the policies of every namespace
+func List() ([]*Policy, error) {
+	recs, err := store.Read("", store.ReadPrefix(), store.ReadFrom(database, table))
+	if err != nil && err != store.ErrNotFound {
+		return nil, err
+	}
+
+	res := make([]*Policy, 0, len(recs))
+	for _, r := range recs {
+		var p Policy
+		if err := r.Decode(&p); err != nil {
+			return nil, err
+		}
+		res = append(res, &p)
+	}
+	return res, nil
+}
+
+func invalidate(ns string) {
+	mtx.Lock()
+	delete(cache, ns)
+	mtx.Unlock()
+}
@@ -0,0 +1,68 @@
+package residency
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	store.DefaultStore = memory.NewStore()
+	Region = "us-east-1"
+
+	// namespaces without a policy can be written anywhere
+	assert.NoError(t, Check("foo"))
+
+	assert.NoError(t, Set(&Policy{Namespace: "foo", Regions: []string{"eu-west-1", "eu-central-1"}}))
+	assert.True(t, errors.Is(Check("foo"), ErrViolation))
+	assert.NoError(t, Check("bar"))
+
+	Region = "eu-central-1"
+	assert.NoError(t, Check("foo"))
+
+	// the region must be known to write to a namespace with a policy
+	Region = ""
+	assert.True(t, errors.Is(Check("foo"), ErrViolation))
+
+	policies, err := List()
+	assert.NoError(t, err)
+	assert.Len(t, policies, 1)
+
+	assert.NoError(t, Delete("foo"))
+	assert.NoError(t, Check("foo"))
+
+	assert.Error(t, Set(&Policy{Namespace: "foo"}))
+}
+
+type failingStore struct {
+	store.Store
+}
+
+func (f *failingStore) Read(key string, opts ...store.ReadOption) ([]*store.Record, error) {
+	return nil, errors.New("store unavailable")
+}
+
+func TestCheckUnavailable(t *testing.T) {
+	mem := memory.NewStore()
+	store.DefaultStore = mem
+	Region = "us-east-1"
+	invalidate("foo")
+
+	// writes aren't blocked if the policy can't be read
+	store.DefaultStore = &failingStore{mem}
+	assert.NoError(t, Check("foo"))
+
+	// the last policy read is used until it can be read again
+	store.DefaultStore = mem
+	assert.NoError(t, Set(&Policy{Namespace: "foo", Regions: []string{"eu-west-1"}}))
+	assert.True(t, errors.Is(Check("foo"), ErrViolation))
+	mtx.Lock()
+	cache["foo"].expiry = time.Now()
+	mtx.Unlock()
+	store.DefaultStore = &failingStore{mem}
+	assert.True(t, errors.Is(Check("foo"), ErrViolation))
+}
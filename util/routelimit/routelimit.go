@@ -0,0 +1,229 @@
+// Package routelimit limits the requests made to the routes of the api gateway by each api key,
+// account or ip. The limits are loaded from the config service at runtime so they can be changed
+// without redeploying, e.g.
+//
+//	micro config set routelimits '[{"route": "/v1/search", "key": "api_key", "rate": 10, "burst": 20, "daily": 10000}, {"route": "/", "key": "ip", "rate": 100}]'
+//
+// The buckets are kept by the quota service so the limits apply across every replica of the api.
+// The daily quota is a bucket which holds the quota and is refilled continuously over the day.
+package routelimit
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/config"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/quota"
+)
+
+var (
+	// ConfigPath is the path of the limits in the config service
+	ConfigPath = "routelimits"
+	// RefreshInterval is how often the limits are reloaded from the config service
+	RefreshInterval = time.Minute
+)
+
+const (
+	// KeyAPIKey limits each api key, requests made without one are limited by account
+	KeyAPIKey = "api_key"
+	// KeyAccount limits each account, requests which aren't authenticated are limited by ip
+	KeyAccount = "account"
+	// KeyIP limits each ip
+	KeyIP = "ip"
+)
+
+// Limit of the requests to a route
+type Limit struct {
+	// Name of the limit, the buckets are keyed by it so it should be changed if the route is.
+	// Defaults to the host and route.
+	Name string `json:"name,omitempty"`
+	// Host to match, all hosts match if blank
+	Host string `json:"host,omitempty"`
+	// Route is the path prefix to match
+	Route string `json:"route"`
+	// Methods to match, all methods match if empty
+	Methods []string `json:"methods,omitempty"`
+	// Key the requests are limited by; api_key, account or ip. Defaults to account.
+	Key string `json:"key,omitempty"`
+	// Rate is the number of requests per second, unlimited if zero
+	Rate int64 `json:"rate,omitempty"`
+	// Burst is the number of requests which can be made at once, defaults to the rate
+	Burst int64 `json:"burst,omitempty"`
+	// Daily is the number of requests per day, unlimited if zero
+	Daily int64 `json:"daily,omitempty"`
+}
+
+// Matches returns true if the request is limited by the limit
+func (l *Limit) Matches(host, method, path string) bool {
+	if len(l.Host) > 0 && !strings.EqualFold(l.Host, host) {
+		return false
+	}
+	if len(l.Methods) > 0 {
+		var found bool
+		for _, m := range l.Methods {
+			if strings.EqualFold(m, method) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return strings.HasPrefix(path, l.Route)
+}
+
+// Validate the limit
+func (l *Limit) Validate() error {
+	if len(l.Route) == 0 || !strings.HasPrefix(l.Route, "/") {
+		return errors.New("the route must be a path prefix, e.g. /users")
+	}
+	switch l.Key {
+	case "", KeyAPIKey, KeyAccount, KeyIP:
+	default:
+		return fmt.Errorf("invalid key %q, expected one of api_key, account or ip", l.Key)
+	}
+	if l.Rate < 0 || l.Burst < 0 || l.Daily < 0 {
+		return errors.New("the rate, burst and daily quota can't be negative")
+	}
+	if l.Rate == 0 && l.Daily == 0 {
+		return errors.New("either the rate or daily quota is required")
+	}
+	return nil
+}
+
+// Client returns the key of the bucket of the client making the request, the key of the limit
+// falls back to the account then the ip if the request doesn't have one
+func (l *Limit) Client(apiKey, account, ip string) string {
+	switch {
+	case l.Key == KeyAPIKey && len(apiKey) > 0:
+		return "key/" + apiKey
+	case l.Key != KeyIP && len(account) > 0:
+		return "account/" + account
+	default:
+		return "ip/" + ip
+	}
+}
+
+// Allow a request by the client in the namespace, taking a token from the rate and daily buckets.
+// The result has the lowest number of requests remaining.
+func (l *Limit) Allow(client, ns string) (*quota.Result, error) {
+	name := l.Name
+	if len(name) == 0 {
+		name = l.Host + l.Route
+	}
+	prefix := "route/" + name + "/" + client
+
+	res := &quota.Result{Allowed: true, Remaining: -1}
+	if l.Rate > 0 {
+		r, err := quota.Allow(prefix+"/rate",
+			quota.Limit(l.Rate, time.Second),
+			quota.Burst(l.Burst),
+			quota.AllowNamespace(ns),
+		)
+		if err != nil || !r.Allowed {
+			return r, err
+		}
+		res = r
+	}
+	if l.Daily > 0 {
+		r, err := quota.Allow(prefix+"/daily",
+			quota.Limit(l.Daily, time.Hour*24),
+			quota.AllowNamespace(ns),
+		)
+		if err != nil || !r.Allowed {
+			return r, err
+		}
+		if res.Remaining < 0 || r.Remaining < res.Remaining {
+			res = r
+		}
+	}
+	return res, nil
+}
+
+// Limiter matches requests to the limits loaded from the config service
+type Limiter struct {
+	sync.Mutex
+	limits  []*Limit
+	loaded  time.Time
+	loading bool
+
+	// load the limits, replaced in tests
+	load func() ([]*Limit, error)
+}
+
+// New returns a limiter which loads the limits from the config service
+func New() *Limiter {
+	return &Limiter{load: loadConfig}
+}
+
+// Match returns the first limit which matches the request, or nil if none do. Until the limits
+// are first loaded requests aren't limited.
+func (l *Limiter) Match(host, method, path string) *Limit {
+	for _, lim := range l.refresh() {
+		if lim.Matches(host, method, path) {
+			return lim
+		}
+	}
+	return nil
+}
+
+// refresh returns the limits, reloading them in the background if they're stale
+func (l *Limiter) refresh() []*Limit {
+	l.Lock()
+	defer l.Unlock()
+
+	if time.Since(l.loaded) < RefreshInterval || l.loading {
+		return l.limits
+	}
+	l.loading = true
+
+	go func() {
+		limits, err := l.load()
+
+		l.Lock()
+		defer l.Unlock()
+		l.loading = false
+		l.loaded = time.Now()
+		if err != nil {
+			// keep the last limits loaded
+			logger.Warnf("Error loading route limits: %v", err)
+			return
+		}
+		l.limits = limits
+	}()
+
+	return l.limits
+}
+
+func loadConfig() ([]*Limit, error) {
+	if config.DefaultConfig == nil {
+		return nil, nil
+	}
+	val, err := config.Get(ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	if !val.Exists() {
+		return nil, nil
+	}
+	var limits []*Limit
+	if err := val.Scan(&limits); err != nil {
+		return nil, err
+	}
+	for i, lim := range limits {
+		if err := lim.Validate(); err != nil {
+			name := lim.Name
+			if len(name) == 0 {
+				name = strconv.Itoa(i)
+			}
+			return nil, fmt.Errorf("invalid limit %v: %v", name, err)
+		}
+	}
+	return limits, nil
+}
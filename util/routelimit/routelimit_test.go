@@ -0,0 +1,101 @@
+package routelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/quota"
+	qstore "github.com/micro/micro/v3/service/quota/store"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func testLimiter(limits ...*Limit) *Limiter {
+	quota.DefaultQuota = qstore.NewQuota(memory.NewStore())
+	l := New()
+	l.limits = limits
+	l.loaded = time.Now()
+	return l
+}
+
+func TestMatch(t *testing.T) {
+	l := testLimiter(
+		&Limit{Route: "/v1/search", Methods: []string{"get"}, Rate: 1},
+		&Limit{Route: "/", Host: "api.example.com", Rate: 1},
+	)
+
+	assert.Equal(t, "/v1/search", l.Match("api.example.com", "GET", "/v1/search/query").Route)
+	assert.Equal(t, "/", l.Match("API.example.com", "POST", "/v1/search/query").Route)
+	assert.Nil(t, l.Match("other.example.com", "POST", "/v1/search/query"))
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, (&Limit{Route: "/", Key: KeyAPIKey, Daily: 100}).Validate())
+	assert.Error(t, (&Limit{Route: "users", Rate: 1}).Validate())
+	assert.Error(t, (&Limit{Route: "/", Key: "session", Rate: 1}).Validate())
+	assert.Error(t, (&Limit{Route: "/", Rate: -1}).Validate())
+	assert.Error(t, (&Limit{Route: "/"}).Validate())
+}
+
+func TestClient(t *testing.T) {
+	keyed := &Limit{Key: KeyAPIKey}
+	assert.Equal(t, "key/k1", keyed.Client("k1", "john", "10.0.0.1"))
+	assert.Equal(t, "account/john", keyed.Client("", "john", "10.0.0.1"))
+	assert.Equal(t, "ip/10.0.0.1", keyed.Client("", "", "10.0.0.1"))
+
+	// the account is the default key
+	assert.Equal(t, "account/john", (&Limit{}).Client("k1", "john", "10.0.0.1"))
+	assert.Equal(t, "ip/10.0.0.1", (&Limit{Key: KeyIP}).Client("k1", "john", "10.0.0.1"))
+}
+
+func TestAllow(t *testing.T) {
+	testLimiter()
+
+	rate := &Limit{Route: "/v1/search", Rate: 2, Burst: 3}
+	for i := 0; i < 3; i++ {
+		res, err := rate.Allow("key/k1", "micro")
+		assert.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, int64(2-i), res.Remaining)
+	}
+	res, err := rate.Allow("key/k1", "micro")
+	assert.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.True(t, res.RetryAfter > 0)
+
+	// clients and routes have their own buckets
+	res, err = rate.Allow("key/k2", "micro")
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+	res, err = (&Limit{Route: "/v1/users", Rate: 2}).Allow("key/k1", "micro")
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	// the daily quota is enforced alongside the rate, the lowest remaining is returned
+	daily := &Limit{Route: "/v1/export", Rate: 10, Daily: 2}
+	res, err = daily.Allow("account/john", "micro")
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, int64(1), res.Remaining)
+	res, err = daily.Allow("account/john", "micro")
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+	res, err = daily.Allow("account/john", "micro")
+	assert.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.True(t, res.RetryAfter > time.Hour)
+}
+
+func TestRefresh(t *testing.T) {
+	l := testLimiter()
+	l.loaded = time.Time{}
+	l.load = func() ([]*Limit, error) {
+		return []*Limit{{Route: "/", Rate: 1}}, nil
+	}
+
+	// requests aren't limited until the limits are first loaded
+	assert.Nil(t, l.Match("", "GET", "/users"))
+	assert.Eventually(t, func() bool {
+		return l.Match("", "GET", "/users") != nil
+	}, time.Second, time.Millisecond*10)
+}
@@ -0,0 +1,42 @@
+package scrub
+
+import (
+	"fmt"
+
+	"github.com/micro/micro/v3/service/logger"
+)
+
+// NewLogger returns a logger which scrubs the messages logged by the rules of the service set
+// in the "service" field of the logger, before they're written by the logger wrapped
+func NewLogger(l logger.Logger) logger.Logger {
+	// skip the frame of the scrubbing logger so the caller is still reported
+	l.Init(logger.WithCallerSkipCount(l.Options().CallerSkipCount + 1))
+	return &scrubLogger{Logger: l}
+}
+
+type scrubLogger struct {
+	logger.Logger
+}
+
+func (l *scrubLogger) service() string {
+	s, _ := l.Logger.Options().Fields["service"].(string)
+	return s
+}
+
+func (l *scrubLogger) Fields(fields map[string]interface{}) logger.Logger {
+	return &scrubLogger{Logger: l.Logger.Fields(fields)}
+}
+
+func (l *scrubLogger) Log(level logger.Level, v ...interface{}) {
+	if !l.Logger.Options().Level.Enabled(level) {
+		return
+	}
+	l.Logger.Log(level, String(l.service(), fmt.Sprint(v...)))
+}
+
+func (l *scrubLogger) Logf(level logger.Level, format string, v ...interface{}) {
+	if !l.Logger.Options().Level.Enabled(level) {
+		return
+	}
+	l.Logger.Log(level, String(l.service(), fmt.Sprintf(format, v...)))
+}
@@ -0,0 +1,443 @@
+// Package scrub removes personal data and credentials from payloads before they're recorded by
+// traces, logs, captures or analytics. Emails, card numbers, tokens and the values of fields
+// named like credentials are detected by default. Custom rules, which can be limited to
+// services, are loaded from the config service at runtime so they can be changed without
+// redeploying, e.g.
+//
+//	micro config set scrub '[{"name": "phone", "pattern": "\\+?[0-9]{10,14}", "replace": "{phone}", "services": ["users"]}, {"name": "address", "fields": ["address", "postcode"]}]'
+//
+// Rules can be tested against sample payloads with `micro scrub test`.
+package scrub
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/config"
+	"github.com/micro/micro/v3/service/logger"
+	inauth "github.com/micro/micro/v3/util/auth"
+)
+
+var (
+	// ConfigPath is the path of the custom rules in the config service
+	ConfigPath = "scrub"
+	// RefreshInterval is how often the custom rules are reloaded from the config service
+	RefreshInterval = time.Minute
+	// DefaultReplace replaces the values matched by custom rules which don't set a replacement
+	DefaultReplace = "{redacted}"
+
+	// Detectors are the built in rules applied to every payload
+	Detectors = []*Rule{
+		{
+			Name:    "credentials",
+			Fields:  []string{"password", "secret", "token", "api_key", "apikey", "authorization"},
+			Replace: "[REDACTED]",
+		},
+		{
+			Name:    "bearer",
+			Pattern: `(?i)\b(bearer)\s+[A-Za-z0-9\-._~+/]+=*`,
+			Replace: "$1 {token}",
+		},
+		{
+			Name:    "jwt",
+			Pattern: `\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`,
+			Replace: "{token}",
+		},
+		{
+			Name:    "api_key",
+			Pattern: `\b` + regexp.QuoteMeta(inauth.APIKeyPrefix) + `[A-Za-z0-9_-]+`,
+			Replace: "{token}",
+		},
+		{
+			Name:    "email",
+			Pattern: `[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`,
+			Replace: "{email}",
+		},
+		{
+			Name:    "card",
+			Pattern: `\b(?:\d[ -]?){12,18}\d\b`,
+			Replace: "{card}",
+			check:   luhn,
+		},
+	}
+
+	// DefaultScrubber applies the detectors and the custom rules loaded from the config service
+	DefaultScrubber = New()
+)
+
+func init() {
+	if err := validate(Detectors); err != nil {
+		panic(err)
+	}
+}
+
+// Rule replaces the data matching a pattern, or the values of fields, in payloads
+type Rule struct {
+	// Name of the rule, reported by `micro scrub test`
+	Name string `json:"name"`
+	// Pattern is the regular expression of the data replaced in strings, the replacement can
+	// reference the groups of the expression, e.g. $1
+	Pattern string `json:"pattern,omitempty"`
+	// Replace is the replacement of the data matched, defaults to DefaultReplace
+	Replace string `json:"replace,omitempty"`
+	// Fields are the names of the JSON fields and query parameters whose values are replaced, a
+	// field matches if its name contains one, ignoring case
+	Fields []string `json:"fields,omitempty"`
+	// Services the rule applies to, all services if empty
+	Services []string `json:"services,omitempty"`
+
+	re *regexp.Regexp
+	// check the data matched, it's only replaced if true
+	check func(string) bool
+}
+
+// Validate the rule, compiling its pattern
+func (r *Rule) Validate() error {
+	if len(r.Name) == 0 {
+		return errors.New("missing name")
+	}
+	if len(r.Pattern) == 0 && len(r.Fields) == 0 {
+		return errors.New("either a pattern or fields are required")
+	}
+	if len(r.Pattern) > 0 {
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %v", r.Pattern, err)
+		}
+		r.re = re
+	}
+	for _, f := range r.Fields {
+		if len(f) == 0 {
+			return errors.New("fields can't be blank")
+		}
+	}
+	return nil
+}
+
+// Applies returns true if the rule applies to the payloads of the service
+func (r *Rule) Applies(service string) bool {
+	if len(r.Services) == 0 {
+		return true
+	}
+	for _, s := range r.Services {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
+
+// Field returns true if the values of the field are replaced by the rule
+func (r *Rule) Field(name string) bool {
+	name = strings.ToLower(name)
+	for _, f := range r.Fields {
+		if strings.Contains(name, strings.ToLower(f)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) replacement() string {
+	if len(r.Replace) == 0 {
+		return DefaultReplace
+	}
+	return r.Replace
+}
+
+// Result of scrubbing a payload
+type Result struct {
+	// Payload scrubbed
+	Payload string
+	// Matched are the names of the rules which replaced data, sorted
+	Matched []string
+}
+
+// Apply the rules to the payload. JSON payloads, either by their content type or because the
+// content type is blank and they're an object or array, and form encoded payloads have the
+// values of their fields scrubbed. An error is returned if a JSON or form payload can't be
+// parsed, e.g. because it was truncated, since its data can't be reliably removed.
+func Apply(rules []*Rule, contentType string, b []byte) (*Result, error) {
+	p := &pass{rules: rules, matched: map[string]bool{}}
+
+	var out string
+	if isJSON(contentType, b) {
+		dec := json.NewDecoder(bytes.NewReader(b))
+		// numbers are kept as they are rather than being converted to floats
+		dec.UseNumber()
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		j, err := json.Marshal(p.value(v))
+		if err != nil {
+			return nil, err
+		}
+		out = string(j)
+	} else if strings.Contains(contentType, "x-www-form-urlencoded") {
+		form, err := url.ParseQuery(string(b))
+		if err != nil {
+			return nil, err
+		}
+		out = p.form(form).Encode()
+	} else {
+		out = p.string(string(b))
+	}
+
+	res := &Result{Payload: out, Matched: []string{}}
+	for name := range p.matched {
+		res.Matched = append(res.Matched, name)
+	}
+	sort.Strings(res.Matched)
+	return res, nil
+}
+
+func isJSON(contentType string, b []byte) bool {
+	if len(contentType) > 0 {
+		return strings.Contains(contentType, "json")
+	}
+	trimmed := bytes.TrimSpace(b)
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+// pass applies the rules to a payload, recording the rules which matched
+type pass struct {
+	rules   []*Rule
+	matched map[string]bool
+}
+
+// field returns the rule which replaces the values of the field, or nil if none do
+func (p *pass) field(name string) *Rule {
+	for _, r := range p.rules {
+		if r.Field(name) {
+			return r
+		}
+	}
+	return nil
+}
+
+func (p *pass) string(v string) string {
+	for _, r := range p.rules {
+		if r.re == nil {
+			continue
+		}
+		v = r.re.ReplaceAllStringFunc(v, func(m string) string {
+			if r.check != nil && !r.check(m) {
+				return m
+			}
+			p.matched[r.Name] = true
+			return string(r.re.ExpandString(nil, r.replacement(), m, r.re.FindStringSubmatchIndex(m)))
+		})
+	}
+	return v
+}
+
+func (p *pass) value(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if r := p.field(k); r != nil {
+				p.matched[r.Name] = true
+				t[k] = r.replacement()
+				continue
+			}
+			t[k] = p.value(val)
+		}
+	case []interface{}:
+		for i, val := range t {
+			t[i] = p.value(val)
+		}
+	case string:
+		return p.string(t)
+	case json.Number:
+		if s := p.string(t.String()); s != t.String() {
+			return s
+		}
+	}
+	return v
+}
+
+func (p *pass) form(f url.Values) url.Values {
+	for k, vs := range f {
+		if r := p.field(k); r != nil {
+			p.matched[r.Name] = true
+			f[k] = []string{r.replacement()}
+			continue
+		}
+		for i, v := range vs {
+			vs[i] = p.string(v)
+		}
+	}
+	return f
+}
+
+// luhn returns true if the digits of the number pass the Luhn checksum of card numbers
+func luhn(number string) bool {
+	var sum, n int
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n > 0 && sum%10 == 0
+}
+
+// Scrubber applies the detectors and the custom rules loaded from the config service
+type Scrubber struct {
+	sync.Mutex
+	rules   []*Rule
+	loaded  time.Time
+	loading bool
+
+	// load the rules, replaced in tests
+	load func() ([]*Rule, error)
+}
+
+// New returns a scrubber which loads the custom rules from the config service
+func New() *Scrubber {
+	return &Scrubber{load: Load}
+}
+
+// Rules returns the detectors and custom rules which apply to the service. Until the custom
+// rules are first loaded only the detectors apply.
+func (s *Scrubber) Rules(service string) []*Rule {
+	rules := append([]*Rule{}, Detectors...)
+	for _, r := range s.refresh() {
+		if r.Applies(service) {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// String returns the string scrubbed by the rules of the service
+func (s *Scrubber) String(service, v string) string {
+	p := &pass{rules: s.Rules(service), matched: map[string]bool{}}
+	return p.string(v)
+}
+
+// Field returns true if the values of the field are replaced by the rules of the service
+func (s *Scrubber) Field(service, name string) bool {
+	p := &pass{rules: s.Rules(service)}
+	return p.field(name) != nil
+}
+
+// Body returns the body scrubbed by the rules of the service, see Apply
+func (s *Scrubber) Body(service, contentType string, b []byte) (string, error) {
+	res, err := Apply(s.Rules(service), contentType, b)
+	if err != nil {
+		return "", err
+	}
+	return res.Payload, nil
+}
+
+// refresh returns the custom rules, reloading them in the background if they're stale
+func (s *Scrubber) refresh() []*Rule {
+	s.Lock()
+	defer s.Unlock()
+
+	if time.Since(s.loaded) < RefreshInterval || s.loading {
+		return s.rules
+	}
+	s.loading = true
+
+	go func() {
+		rules, err := s.load()
+
+		s.Lock()
+		s.loading = false
+		s.loaded = time.Now()
+		if err == nil {
+			s.rules = rules
+		}
+		s.Unlock()
+
+		// logged once unlocked since the logs are scrubbed too, the last rules loaded are kept
+		if err != nil {
+			logger.Warnf("Error loading scrub rules: %v", err)
+		}
+	}()
+
+	return s.rules
+}
+
+// Load the custom rules from the config service, validating them
+func Load() ([]*Rule, error) {
+	if config.DefaultConfig == nil {
+		return nil, nil
+	}
+	val, err := config.Get(ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	if !val.Exists() {
+		return nil, nil
+	}
+	var rules []*Rule
+	if err := val.Scan(&rules); err != nil {
+		return nil, err
+	}
+	if err := validate(rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Parse the custom rules from JSON, validating them
+func Parse(b []byte) ([]*Rule, error) {
+	var rules []*Rule
+	if err := json.Unmarshal(b, &rules); err != nil {
+		return nil, err
+	}
+	if err := validate(rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func validate(rules []*Rule) error {
+	for i, r := range rules {
+		if err := r.Validate(); err != nil {
+			name := r.Name
+			if len(name) == 0 {
+				name = strconv.Itoa(i)
+			}
+			return fmt.Errorf("invalid rule %v: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// String returns the string scrubbed by the default scrubber
+func String(service, v string) string {
+	return DefaultScrubber.String(service, v)
+}
+
+// Field returns true if the values of the field are replaced by the default scrubber
+func Field(service, name string) bool {
+	return DefaultScrubber.Field(service, name)
+}
+
+// Body returns the body scrubbed by the default scrubber
+func Body(service, contentType string, b []byte) (string, error) {
+	return DefaultScrubber.Body(service, contentType, b)
+}
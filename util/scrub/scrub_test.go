@@ -0,0 +1,147 @@
+package scrub
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectors(t *testing.T) {
+	tt := []struct {
+		Name    string
+		Input   string
+		Output  string
+		Matched []string
+	}{
+		{"Email", "contact john.doe@example.com now", "contact {email} now", []string{"email"}},
+		{"Card", "paid with 4111 1111 1111 1111", "paid with {card}", []string{"card"}},
+		{"CardFailingLuhn", "order 4111111111111112", "order 4111111111111112", []string{}},
+		{"Bearer", "Authorization: Bearer abc.def-123", "Authorization: Bearer {token}", []string{"bearer"}},
+		{"JWT", "token eyJhbGciOi.eyJzdWIiOiIx.sig_nature", "token {token}", []string{"jwt"}},
+		{"APIKey", "key mk_1a2b3c4d", "key {token}", []string{"api_key"}},
+		{"None", "nothing to see here", "nothing to see here", []string{}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			res, err := Apply(Detectors, "text/plain", []byte(tc.Input))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Output, res.Payload)
+			assert.Equal(t, tc.Matched, res.Matched)
+		})
+	}
+}
+
+func TestApplyJSON(t *testing.T) {
+	body := []byte(`{"user": {"email": "jane@example.com", "password": "hunter2", "age": 30}, "cards": [4111111111111111], "notes": ["call jane@example.com"]}`)
+
+	res, err := Apply(Detectors, "application/json", body)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"user": {"email": "{email}", "password": "[REDACTED]", "age": 30}, "cards": ["{card}"], "notes": ["call {email}"]}`, res.Payload)
+	assert.Equal(t, []string{"card", "credentials", "email"}, res.Matched)
+
+	// the content type is optional
+	res, err = Apply(Detectors, "", body)
+	assert.NoError(t, err)
+	assert.NotContains(t, res.Payload, "hunter2")
+
+	// truncated bodies can't be scrubbed
+	_, err = Apply(Detectors, "application/json", body[:20])
+	assert.Error(t, err)
+}
+
+func TestApplyForm(t *testing.T) {
+	body := []byte(`username=jane&password=hunter2&email=jane%40example.com`)
+
+	res, err := Apply(Detectors, "application/x-www-form-urlencoded", body)
+	assert.NoError(t, err)
+	form, err := url.ParseQuery(res.Payload)
+	assert.NoError(t, err)
+	assert.Equal(t, "jane", form.Get("username"))
+	assert.Equal(t, "[REDACTED]", form.Get("password"))
+	assert.Equal(t, "{email}", form.Get("email"))
+	assert.Equal(t, []string{"credentials", "email"}, res.Matched)
+
+	_, err = Apply(Detectors, "application/x-www-form-urlencoded", []byte(`password=%zz`))
+	assert.Error(t, err)
+}
+
+func TestRules(t *testing.T) {
+	assert.Error(t, (&Rule{Pattern: "x"}).Validate())
+	assert.Error(t, (&Rule{Name: "empty"}).Validate())
+	assert.Error(t, (&Rule{Name: "invalid", Pattern: "("}).Validate())
+
+	rules, err := Parse([]byte(`[{"name": "phone", "pattern": "\\+44[0-9]{10}", "services": ["users"]}, {"name": "address", "fields": ["address"], "replace": "{address}"}]`))
+	assert.NoError(t, err)
+
+	s := &Scrubber{
+		rules:  rules,
+		loaded: time.Now(),
+		load:   func() ([]*Rule, error) { return rules, nil },
+	}
+
+	// rules only apply to their services
+	assert.Equal(t, "call {redacted}", s.String("users", "call +447700900123"))
+	assert.Equal(t, "call +447700900123", s.String("posts", "call +447700900123"))
+
+	b, err := s.Body("posts", "application/json", []byte(`{"home_address": "1 Main St", "name": "Jane"}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"home_address": "{address}", "name": "Jane"}`, b)
+
+	assert.True(t, s.Field("posts", "Password"))
+	assert.True(t, s.Field("posts", "address"))
+	assert.False(t, s.Field("posts", "name"))
+
+	_, err = Parse([]byte(`[{"name": "invalid", "pattern": "("}]`))
+	assert.Error(t, err)
+}
+
+func TestRefresh(t *testing.T) {
+	interval := RefreshInterval
+	RefreshInterval = 0
+	defer func() { RefreshInterval = interval }()
+
+	rules, err := Parse([]byte(`[{"name": "secret", "pattern": "s3cr3t"}]`))
+	assert.NoError(t, err)
+
+	var fail bool
+	s := &Scrubber{load: func() ([]*Rule, error) {
+		if fail {
+			return nil, errors.New("unavailable")
+		}
+		return rules, nil
+	}}
+
+	assert.Eventually(t, func() bool {
+		return s.String("", "s3cr3t") == "{redacted}"
+	}, time.Second, time.Millisecond*10)
+
+	// the last rules loaded are kept if loading fails
+	s.Lock()
+	fail = true
+	s.Unlock()
+	time.Sleep(time.Millisecond * 50)
+	assert.Equal(t, "{redacted}", s.String("", "s3cr3t"))
+}
+
+func TestLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := NewLogger(logger.NewLogger(logger.WithOutput(buf), logger.WithLevel(logger.InfoLevel)))
+
+	l.Logf(logger.InfoLevel, "created user %s", "jane@example.com")
+	l.Fields(map[string]interface{}{"service": "users"}).Log(logger.InfoLevel, "card 4111111111111111")
+	l.Log(logger.DebugLevel, "jane@example.com")
+
+	out := buf.String()
+	assert.NotContains(t, out, "jane@example.com")
+	assert.NotContains(t, out, "4111111111111111")
+	assert.Contains(t, out, "created user {email}")
+	assert.Contains(t, out, "card {card}")
+	assert.Equal(t, 2, strings.Count(out, "\n"))
+}
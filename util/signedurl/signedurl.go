@@ -0,0 +1,92 @@
+// Package signedurl signs and verifies time limited urls to blobs, served by the api gateway.
+// The url authorizes a single method on a single blob until it expires, so services can hand
+// out downloads and uploads without the bytes passing through them.
+package signedurl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Path the gateway serves signed urls at, it can't clash with a service name
+const Path = "/_blob/"
+
+var (
+	// ErrInvalid is returned when the signature of a url doesn't match
+	ErrInvalid = errors.New("invalid signature")
+	// ErrExpired is returned when a url has expired
+	ErrExpired = errors.New("url expired")
+)
+
+// URL to a blob
+type URL struct {
+	Namespace string
+	Key       string
+	Method    string
+	Expires   time.Time
+}
+
+// Sign the url with the key, returning it relative to the base url of the gateway
+func Sign(key []byte, base string, u *URL) string {
+	parts := strings.Split(u.Key, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+
+	q := url.Values{}
+	q.Set("method", u.Method)
+	q.Set("expires", strconv.FormatInt(u.Expires.Unix(), 10))
+	q.Set("signature", signature(key, u))
+
+	return fmt.Sprintf("%s%s%s/%s?%s", strings.TrimSuffix(base, "/"), Path,
+		url.PathEscape(u.Namespace), strings.Join(parts, "/"), q.Encode())
+}
+
+// Verify the signature of a request to a signed url, returning the url
+func Verify(key []byte, r *http.Request) (*URL, error) {
+	path := strings.TrimPrefix(r.URL.Path, Path)
+	idx := strings.Index(path, "/")
+	if idx <= 0 || idx == len(path)-1 {
+		return nil, ErrInvalid
+	}
+
+	q := r.URL.Query()
+	exp, err := strconv.ParseInt(q.Get("expires"), 10, 64)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	u := &URL{
+		Namespace: path[:idx],
+		Key:       path[idx+1:],
+		Method:    q.Get("method"),
+		Expires:   time.Unix(exp, 0),
+	}
+	if !hmac.Equal([]byte(signature(key, u)), []byte(q.Get("signature"))) {
+		return nil, ErrInvalid
+	}
+	if time.Now().After(u.Expires) {
+		return nil, ErrExpired
+	}
+	return u, nil
+}
+
+// Allows returns true if the url can be used with the method, urls to download a blob can
+// also be used for HEAD requests
+func (u *URL) Allows(method string) bool {
+	return method == u.Method || (method == http.MethodHead && u.Method == http.MethodGet)
+}
+
+func signature(key []byte, u *URL) string {
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%d", u.Method, u.Namespace, u.Key, u.Expires.Unix())
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,51 @@
+package signedurl
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignedURL(t *testing.T) {
+	key := []byte("secret")
+	u := &URL{
+		Namespace: "micro",
+		Key:       "images/cat picture.png",
+		Method:    "GET",
+		Expires:   time.Now().Add(time.Minute),
+	}
+
+	signed := Sign(key, "https://api.example.com/", u)
+	assert.True(t, strings.HasPrefix(signed, "https://api.example.com/_blob/micro/images/cat%20picture.png?"))
+
+	// the url verifies
+	v, err := Verify(key, httptest.NewRequest("GET", signed, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, u.Key, v.Key)
+	assert.Equal(t, u.Namespace, v.Namespace)
+	assert.True(t, v.Allows("GET"))
+	assert.True(t, v.Allows("HEAD"))
+	assert.False(t, v.Allows("PUT"))
+
+	// but not with another key
+	_, err = Verify([]byte("other"), httptest.NewRequest("GET", signed, nil))
+	assert.Equal(t, ErrInvalid, err)
+
+	// or once it's been changed
+	for _, s := range []string{
+		strings.Replace(signed, "images", "videos", 1),
+		strings.Replace(signed, "method=GET", "method=PUT", 1),
+		strings.Replace(signed, "/micro/", "/other/", 1),
+	} {
+		_, err = Verify(key, httptest.NewRequest("GET", s, nil))
+		assert.Equal(t, ErrInvalid, err, s)
+	}
+
+	// or once it's expired
+	u.Expires = time.Now().Add(-time.Second)
+	_, err = Verify(key, httptest.NewRequest("GET", Sign(key, "", u), nil))
+	assert.Equal(t, ErrExpired, err)
+}
@@ -0,0 +1,68 @@
+package skew
+
+import "time"
+
+// Options of a detector
+type Options struct {
+	// Threshold is the offset beyond which a clock is skewed
+	Threshold time.Duration
+	// MaxRTT is the longest round trip used to estimate an offset
+	MaxRTT time.Duration
+	// MinSamples is the number of calls observed before an alert is raised for a service
+	MinSamples int
+	// Cooldown between alerts for the same service
+	Cooldown time.Duration
+	// Expiry is how long a service is kept after it was last observed
+	Expiry time.Duration
+	// Alpha is the weight of each sample in the offset
+	Alpha float64
+	// Alert is called with each alert raised, defaults to publishing it
+	Alert func(*Alert)
+}
+
+// Option sets an option of a detector
+type Option func(o *Options)
+
+func newOptions(opts ...Option) Options {
+	o := Options{
+		Threshold:  time.Second,
+		MaxRTT:     time.Second,
+		MinSamples: 5,
+		Cooldown:   time.Minute * 15,
+		Expiry:     time.Minute * 10,
+		Alpha:      0.2,
+		Alert:      Publish,
+	}
+	for _, fn := range opts {
+		fn(&o)
+	}
+	return o
+}
+
+// Threshold sets the offset beyond which a clock is skewed
+func Threshold(d time.Duration) Option {
+	return func(o *Options) {
+		o.Threshold = d
+	}
+}
+
+// MinSamples sets the number of calls observed before an alert is raised
+func MinSamples(n int) Option {
+	return func(o *Options) {
+		o.MinSamples = n
+	}
+}
+
+// Cooldown sets the time between alerts for the same service
+func Cooldown(d time.Duration) Option {
+	return func(o *Options) {
+		o.Cooldown = d
+	}
+}
+
+// WithAlert sets the function called with each alert
+func WithAlert(fn func(*Alert)) Option {
+	return func(o *Options) {
+		o.Alert = fn
+	}
+}
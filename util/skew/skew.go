@@ -0,0 +1,188 @@
+// Package skew detects clock skew between services. Callers set the time a request is sent in its
+// metadata, the handler sets the time it was received in the response metadata, and the caller
+// estimates the offset of the remote clock from the midpoint of the round trip. Token expiry and
+// cache ttls misbehave when the clock of a node drifts, so an alert is published when the offset
+// of a service exceeds the threshold.
+package skew
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// TimestampKey is the metadata key the time a request was sent, or received, is set in
+	TimestampKey = "Micro-Timestamp"
+	// Topic alerts are published to, the same topic as the anomaly detector
+	Topic = "alerts"
+	// DefaultDetector is used by the skew wrappers
+	DefaultDetector = New()
+)
+
+// Alert is raised when the clock of a service is skewed
+type Alert struct {
+	Type    string `json:"type"`
+	Service string `json:"service"`
+	// Offset of the clock of the service relative to the clock which detected it, in milliseconds
+	Offset float64   `json:"offset"`
+	Time   time.Time `json:"time"`
+}
+
+// Peer is the estimated clock offset of a service
+type Peer struct {
+	Service string
+	// Offset of the clock of the service relative to the local clock, positive if it's ahead
+	Offset time.Duration
+	// RTT is the round trip time of the most recent sample, the offset is accurate to half of it
+	RTT     time.Duration
+	Samples int
+	Updated time.Time
+}
+
+type peer struct {
+	Peer
+	alerted time.Time
+}
+
+// Detector keeps the offsets of the services called
+type Detector struct {
+	sync.Mutex
+	opts  Options
+	peers map[string]*peer
+}
+
+// New returns a detector
+func New(opts ...Option) *Detector {
+	return &Detector{
+		opts:  newOptions(opts...),
+		peers: map[string]*peer{},
+	}
+}
+
+// Init the detector with the options
+func (d *Detector) Init(opts ...Option) {
+	d.Lock()
+	defer d.Unlock()
+	for _, o := range opts {
+		o(&d.opts)
+	}
+}
+
+// Observe a call to the service which was sent at the start, received by the service at the
+// remote time and replied to by the end
+func (d *Detector) Observe(service string, start, remote, end time.Time) {
+	rtt := end.Sub(start)
+	if rtt < 0 || rtt > d.opts.MaxRTT {
+		// the offset of slow calls is too inaccurate to be useful
+		return
+	}
+	offset := remote.Sub(start.Add(rtt / 2))
+
+	d.Lock()
+	p, ok := d.peers[service]
+	if !ok {
+		p = &peer{Peer: Peer{Service: service, Offset: offset}}
+		d.peers[service] = p
+	}
+	p.Offset = time.Duration(d.opts.Alpha*float64(offset) + (1-d.opts.Alpha)*float64(p.Offset))
+	p.RTT = rtt
+	p.Samples++
+	p.Updated = end
+
+	var alert *Alert
+	if d.skewed(&p.Peer) && end.Sub(p.alerted) >= d.opts.Cooldown {
+		p.alerted = end
+		alert = &Alert{
+			Type:    "clock_skew",
+			Service: service,
+			Offset:  float64(p.Offset.Microseconds()) / 1000,
+			Time:    end,
+		}
+	}
+	fn := d.opts.Alert
+	d.Unlock()
+
+	if alert != nil {
+		fn(alert)
+	}
+}
+
+// skewed returns true if the offset of the peer exceeds the threshold by more than its accuracy
+func (d *Detector) skewed(p *Peer) bool {
+	return p.Samples >= d.opts.MinSamples && abs(p.Offset)-p.RTT/2 > d.opts.Threshold
+}
+
+// Peers returns the offsets of the services observed recently, sorted by service
+func (d *Detector) Peers() []*Peer {
+	d.Lock()
+	defer d.Unlock()
+
+	now := time.Now()
+	peers := make([]*Peer, 0, len(d.peers))
+	for name, p := range d.peers {
+		if now.Sub(p.Updated) > d.opts.Expiry {
+			delete(d.peers, name)
+			continue
+		}
+		cp := p.Peer
+		peers = append(peers, &cp)
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].Service < peers[j].Service
+	})
+	return peers
+}
+
+// Max returns the largest offset observed recently, by magnitude
+func (d *Detector) Max() time.Duration {
+	var max time.Duration
+	for _, p := range d.Peers() {
+		if abs(p.Offset) > abs(max) {
+			max = p.Offset
+		}
+	}
+	return max
+}
+
+// Stamp sets the current time in the metadata of the context
+func Stamp(ctx context.Context) context.Context {
+	return metadata.Set(ctx, TimestampKey, Format(time.Now()))
+}
+
+// Format the time as it's set in metadata, in unix nanoseconds
+func Format(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// Parse the time from the metadata, returning false if it isn't set or is invalid
+func Parse(md metadata.Metadata) (time.Time, bool) {
+	v, ok := md.Get(TimestampKey)
+	if !ok {
+		return time.Time{}, false
+	}
+	ns, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || ns <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(0, ns), true
+}
+
+// Publish an alert to the alerts topic
+func Publish(a *Alert) {
+	logger.Warnf("Clock of %v is skewed by %.2fms", a.Service, a.Offset)
+	if err := events.Publish(Topic, a); err != nil {
+		logger.Errorf("Error publishing alert: %v", err)
+	}
+}
+
+func abs(d time.Duration) time.Duration {
+	return time.Duration(math.Abs(float64(d)))
+}
@@ -0,0 +1,57 @@
+package skew
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetector(t *testing.T) {
+	var alerts []*Alert
+	d := New(WithAlert(func(a *Alert) { alerts = append(alerts, a) }))
+	now := time.Now()
+
+	// a service within the threshold isn't alerted on
+	for i := 0; i < 10; i++ {
+		start := now.Add(time.Duration(i) * time.Second)
+		d.Observe("users", start, start.Add(time.Millisecond*60), start.Add(time.Millisecond*20))
+	}
+	assert.Empty(t, alerts)
+
+	// a service which is ahead is alerted on once it has enough samples, and once per cooldown
+	for i := 0; i < 10; i++ {
+		start := now.Add(time.Duration(i) * time.Second)
+		d.Observe("orders", start, start.Add(time.Second*3), start.Add(time.Millisecond*20))
+	}
+	if assert.Len(t, alerts, 1) {
+		assert.Equal(t, "orders", alerts[0].Service)
+		assert.Equal(t, "clock_skew", alerts[0].Type)
+		assert.InDelta(t, 2990, alerts[0].Offset, 1)
+	}
+
+	// slow calls aren't observed
+	d.Observe("slow", now, now.Add(time.Minute), now.Add(time.Second*2))
+
+	peers := d.Peers()
+	if assert.Len(t, peers, 2) {
+		assert.Equal(t, "orders", peers[0].Service)
+		assert.Equal(t, 10, peers[0].Samples)
+		assert.Equal(t, "users", peers[1].Service)
+		assert.InDelta(t, float64(time.Millisecond*50), float64(peers[1].Offset), float64(time.Millisecond))
+	}
+	assert.InDelta(t, float64(time.Millisecond*2990), float64(d.Max()), float64(time.Millisecond))
+}
+
+func TestParse(t *testing.T) {
+	now := time.Now()
+	tm, ok := Parse(metadata.Metadata{TimestampKey: Format(now)})
+	assert.True(t, ok)
+	assert.True(t, tm.Equal(now.Round(0)))
+
+	_, ok = Parse(metadata.Metadata{TimestampKey: "yesterday"})
+	assert.False(t, ok)
+	_, ok = Parse(nil)
+	assert.False(t, ok)
+}
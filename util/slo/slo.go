@@ -0,0 +1,113 @@
+// Package slo calculates the error budgets of services from their service level objectives. The
+// objective of a service is the percentage of requests it should serve successfully and is set in
+// its metadata, for example:
+//   micro run --metadata slo=99.9 github.com/micro/services/helloworld
+package slo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	pb "github.com/micro/micro/v3/proto/debug"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/runtime"
+)
+
+// MetadataKey is the key of the objective in the service metadata
+const MetadataKey = "slo"
+
+// DefaultSource is used to get the requests served by a service
+var DefaultSource Source = DebugStats
+
+// Source returns the number of requests served by a service and how many of them failed
+type Source func(ctx context.Context, namespace, service string) (requests, errors uint64, err error)
+
+// Status of the error budget of a service
+type Status struct {
+	Service string
+	// Objective is the percentage of requests which should succeed
+	Objective float64
+	Requests  uint64
+	Errors    uint64
+}
+
+// Remaining returns the fraction of the error budget remaining, it's negative once the budget
+// has been overspent
+func (s *Status) Remaining() float64 {
+	if s.Errors == 0 {
+		return 1
+	}
+	allowed := float64(s.Requests) * (100 - s.Objective) / 100
+	if allowed <= 0 {
+		return -1
+	}
+	return 1 - float64(s.Errors)/allowed
+}
+
+// Exhausted returns true if the service has used its error budget
+func (s *Status) Exhausted() bool {
+	return s.Remaining() <= 0
+}
+
+func (s *Status) String() string {
+	var failed float64
+	if s.Requests > 0 {
+		failed = float64(s.Errors) / float64(s.Requests) * 100
+	}
+	return fmt.Sprintf("%d of %d requests to %s failed (%.2f%%), its objective of %v%% allows %.2f%%",
+		s.Errors, s.Requests, s.Service, failed, s.Objective, 100-s.Objective)
+}
+
+// Objective returns the objective set in the metadata of a service, or false if it has none
+func Objective(md map[string]string) (float64, bool, error) {
+	v, ok := md[MetadataKey]
+	if !ok || len(v) == 0 {
+		return 0, false, nil
+	}
+	o, err := strconv.ParseFloat(v, 64)
+	if err != nil || o <= 0 || o > 100 {
+		return 0, false, fmt.Errorf("invalid objective %q, must be a percentage e.g. 99.9", v)
+	}
+	return o, true, nil
+}
+
+// Get the status of the error budget of a service, returning nil if the service has no objective
+func Get(ctx context.Context, namespace string, srv *runtime.Service) (*Status, error) {
+	obj, ok, err := Objective(srv.Metadata)
+	if err != nil || !ok {
+		return nil, err
+	}
+	reqs, errs, err := DefaultSource(ctx, namespace, srv.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &Status{Service: srv.Name, Objective: obj, Requests: reqs, Errors: errs}, nil
+}
+
+// DebugStats sums the stats of each instance of the service. The stats are counted from when
+// each instance started, so the budget is reset as instances are restarted.
+func DebugStats(ctx context.Context, namespace, service string) (uint64, uint64, error) {
+	srvs, err := registry.DefaultRegistry.GetService(service, registry.GetDomain(namespace))
+	if err == registry.ErrNotFound {
+		return 0, 0, nil
+	} else if err != nil {
+		return 0, 0, err
+	}
+
+	var reqs, errs uint64
+	req := client.NewRequest(service, "Debug.Stats", &pb.StatsRequest{})
+	for _, srv := range srvs {
+		for _, node := range srv.Nodes {
+			rsp := &pb.StatsResponse{}
+			if err := client.DefaultClient.Call(ctx, req, rsp, client.WithAddress(node.Address)); err != nil {
+				// instances which don't respond can't be counted
+				continue
+			}
+			reqs += rsp.Requests
+			errs += rsp.Errors
+		}
+	}
+	return reqs, errs, nil
+}
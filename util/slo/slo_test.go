@@ -0,0 +1,62 @@
+package slo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatus(t *testing.T) {
+	s := &Status{Objective: 99, Requests: 1000, Errors: 5}
+	assert.InDelta(t, 0.5, s.Remaining(), 0.0001)
+	assert.False(t, s.Exhausted())
+
+	s.Errors = 10
+	assert.True(t, s.Exhausted())
+
+	s.Errors = 20
+	assert.InDelta(t, -1, s.Remaining(), 0.0001)
+
+	// a service which hasn't served any requests has its whole budget
+	assert.False(t, (&Status{Objective: 100}).Exhausted())
+	// and an objective of 100% has no budget for errors
+	assert.True(t, (&Status{Objective: 100, Requests: 10, Errors: 1}).Exhausted())
+}
+
+func TestObjective(t *testing.T) {
+	o, ok, err := Objective(map[string]string{"slo": "99.9"})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 99.9, o)
+
+	_, ok, err = Objective(map[string]string{"foo": "bar"})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	for _, v := range []string{"abc", "0", "101"} {
+		_, _, err = Objective(map[string]string{"slo": v})
+		assert.Error(t, err, v)
+	}
+}
+
+func TestGet(t *testing.T) {
+	DefaultSource = func(ctx context.Context, ns, srv string) (uint64, uint64, error) {
+		assert.Equal(t, "foo", ns)
+		return 200, 3, nil
+	}
+	defer func() { DefaultSource = DebugStats }()
+
+	s, err := Get(context.TODO(), "foo", &runtime.Service{Name: "helloworld", Metadata: map[string]string{"slo": "99"}})
+	assert.NoError(t, err)
+	if assert.NotNil(t, s) {
+		assert.True(t, s.Exhausted())
+		assert.Equal(t, "3 of 200 requests to helloworld failed (1.50%), its objective of 99% allows 1.00%", s.String())
+	}
+
+	// services without an objective aren't budgeted
+	s, err = Get(context.TODO(), "foo", &runtime.Service{Name: "helloworld"})
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+}
@@ -0,0 +1,217 @@
+// Package storelimit guards the store against expensive queries, such as a list of a whole table
+// by a batch job. Reads of many records and lists of keys are capped to the most records a table
+// allows, so the database never scans more than the limit, and those which exceed it or the size
+// limit are rejected. Tables can require every such query to set a limit, so they're paginated.
+// Tables are unlimited unless limits are set for them or a default is, since the services read
+// whole tables, e.g. the accounts of a namespace.
+// The limits are loaded from the config service at runtime so they can be changed without
+// redeploying, e.g.
+//
+//	micro config set storelimits '{"default": {"max_records": 10000}, "tables": {"micro/events": {"max_records": 1000, "require_limit": true}}}'
+//
+// Tables are keyed by database and table, either of which can be *. Violations are logged and
+// counted by the store.query.violations metric.
+package storelimit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/config"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/metrics"
+)
+
+var (
+	// ConfigPath is the path of the limits in the config service
+	ConfigPath = "storelimits"
+	// RefreshInterval is how often the limits are reloaded from the config service
+	RefreshInterval = time.Minute
+	// DefaultLimits apply to the tables which don't have limits set in the config service, they're
+	// unlimited unless a default is set there
+	DefaultLimits = Limits{}
+	// DefaultGuard is the guard used by the store client
+	DefaultGuard = New()
+
+	// ErrUnbounded is returned when a query of a table which requires a limit doesn't set one
+	ErrUnbounded = errors.New("query without a limit")
+	// ErrTooManyRecords is returned when a query returns more records than the table allows
+	ErrTooManyRecords = errors.New("query exceeds the records limit")
+	// ErrResponseTooLarge is returned when the response to a query is larger than the table allows
+	ErrResponseTooLarge = errors.New("query exceeds the response size limit")
+)
+
+// Limits of the queries of a table
+type Limits struct {
+	// MaxRecords is the most records a query can read, or keys it can list, unlimited if zero
+	MaxRecords uint `json:"max_records,omitempty"`
+	// MaxResponseSize is the size in bytes of the largest response to a query, unlimited if zero
+	MaxResponseSize int `json:"max_response_size,omitempty"`
+	// RequireLimit rejects queries which don't set a limit, so they're paginated
+	RequireLimit bool `json:"require_limit,omitempty"`
+}
+
+// Bound returns the limit to query the store with for a query with the limit, zero if it's
+// unbounded. Queries are made with one more than the most records allowed, so queries which
+// exceed it can be detected without reading the rest of the table.
+func (l *Limits) Bound(limit uint) (uint, error) {
+	if limit == 0 && l.RequireLimit {
+		return 0, ErrUnbounded
+	}
+	if l.MaxRecords > 0 && (limit == 0 || limit > l.MaxRecords) {
+		return l.MaxRecords + 1, nil
+	}
+	return limit, nil
+}
+
+// Check the number and total size of the records returned by a query
+func (l *Limits) Check(records, size int) error {
+	if l.MaxRecords > 0 && records > int(l.MaxRecords) {
+		return fmt.Errorf("%w of %d, paginate with a limit and offset", ErrTooManyRecords, l.MaxRecords)
+	}
+	if l.MaxResponseSize > 0 && size > l.MaxResponseSize {
+		return fmt.Errorf("%w of %d bytes, paginate with a limit and offset", ErrResponseTooLarge, l.MaxResponseSize)
+	}
+	return nil
+}
+
+// Policy is the limits of the tables loaded from the config service
+type Policy struct {
+	// Default limits of the tables which don't have limits set, DefaultLimits if nil
+	Default *Limits `json:"default,omitempty"`
+	// Tables are the limits of the tables, keyed by database/table. Either can be *.
+	Tables map[string]*Limits `json:"tables,omitempty"`
+}
+
+// Validate the policy
+func (p *Policy) Validate() error {
+	for key, l := range p.Tables {
+		if parts := strings.Split(key, "/"); len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			return fmt.Errorf("invalid table %q, expected database/table", key)
+		}
+		if l == nil {
+			return fmt.Errorf("table %v has no limits", key)
+		}
+		if l.MaxResponseSize < 0 {
+			return fmt.Errorf("table %v has a negative response size limit", key)
+		}
+	}
+	if p.Default != nil && p.Default.MaxResponseSize < 0 {
+		return errors.New("the default response size limit can't be negative")
+	}
+	return nil
+}
+
+// For returns the limits of the table, the most specific match is used
+func (p *Policy) For(database, table string) Limits {
+	for _, key := range []string{database + "/" + table, "*/" + table, database + "/*"} {
+		if l, ok := p.Tables[key]; ok {
+			return *l
+		}
+	}
+	if p.Default != nil {
+		return *p.Default
+	}
+	return DefaultLimits
+}
+
+// Guard returns the limits of the tables loaded from the config service
+type Guard struct {
+	sync.Mutex
+	policy  *Policy
+	loaded  time.Time
+	loading bool
+
+	// load the policy, replaced in tests
+	load func() (*Policy, error)
+}
+
+// New returns a guard which loads the limits from the config service
+func New() *Guard {
+	return &Guard{load: loadConfig}
+}
+
+// Limits returns the limits of the table. Until the limits are first loaded, or if none are set,
+// DefaultLimits are returned.
+func (g *Guard) Limits(database, table string) Limits {
+	if p := g.refresh(); p != nil {
+		return p.For(database, table)
+	}
+	return DefaultLimits
+}
+
+// refresh returns the policy, reloading it in the background if it's stale
+func (g *Guard) refresh() *Policy {
+	g.Lock()
+	defer g.Unlock()
+
+	if time.Since(g.loaded) < RefreshInterval || g.loading {
+		return g.policy
+	}
+	g.loading = true
+
+	go func() {
+		policy, err := g.load()
+
+		g.Lock()
+		defer g.Unlock()
+		g.loading = false
+		g.loaded = time.Now()
+		if err != nil {
+			// keep the last limits loaded
+			logger.Warnf("Error loading store limits: %v", err)
+			return
+		}
+		g.policy = policy
+	}()
+
+	return g.policy
+}
+
+// Violation logs and counts the query of the table which exceeded its limits, returning the error
+// with the table the query was of
+func Violation(database, table, operation string, err error) error {
+	logger.Warnf("Store %v of %v/%v rejected: %v", operation, database, table, err)
+
+	if metrics.IsSet() {
+		limit := "records"
+		switch {
+		case errors.Is(err, ErrUnbounded):
+			limit = "unbounded"
+		case errors.Is(err, ErrResponseTooLarge):
+			limit = "size"
+		}
+		metrics.Count("store.query.violations", 1, metrics.Tags{
+			"database":  database,
+			"table":     table,
+			"operation": operation,
+			"limit":     limit,
+		})
+	}
+
+	return fmt.Errorf("%v of %v/%v: %w", operation, database, table, err)
+}
+
+func loadConfig() (*Policy, error) {
+	if config.DefaultConfig == nil {
+		return nil, nil
+	}
+	val, err := config.Get(ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	if !val.Exists() {
+		return nil, nil
+	}
+	var p *Policy
+	if err := val.Scan(&p); err != nil {
+		return nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,103 @@
+package storelimit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBound(t *testing.T) {
+	l := &Limits{MaxRecords: 100}
+
+	// unbounded queries and those over the limit are capped, one over so they can be detected
+	limit, err := l.Bound(0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(101), limit)
+	limit, err = l.Bound(500)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(101), limit)
+	limit, err = l.Bound(10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(10), limit)
+
+	// tables can require a limit
+	l.RequireLimit = true
+	_, err = l.Bound(0)
+	assert.Equal(t, ErrUnbounded, err)
+
+	// tables without a records limit aren't capped
+	limit, err = (&Limits{}).Bound(0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), limit)
+}
+
+func TestCheck(t *testing.T) {
+	l := &Limits{MaxRecords: 100, MaxResponseSize: 1024}
+	assert.NoError(t, l.Check(100, 1024))
+	assert.True(t, errors.Is(l.Check(101, 10), ErrTooManyRecords))
+	assert.True(t, errors.Is(l.Check(10, 1025), ErrResponseTooLarge))
+	assert.NoError(t, (&Limits{}).Check(1000000, 1<<30))
+}
+
+func TestPolicy(t *testing.T) {
+	p := &Policy{
+		Default: &Limits{MaxRecords: 500},
+		Tables: map[string]*Limits{
+			"micro/events": {MaxRecords: 10, RequireLimit: true},
+			"*/users":      {MaxRecords: 20},
+			"foo/*":        {MaxRecords: 30},
+		},
+	}
+	assert.NoError(t, p.Validate())
+
+	assert.Equal(t, Limits{MaxRecords: 10, RequireLimit: true}, p.For("micro", "events"))
+	assert.Equal(t, Limits{MaxRecords: 20}, p.For("foo", "users"))
+	assert.Equal(t, Limits{MaxRecords: 30}, p.For("foo", "events"))
+	assert.Equal(t, Limits{MaxRecords: 500}, p.For("bar", "events"))
+	assert.Equal(t, DefaultLimits, (&Policy{}).For("bar", "events"))
+
+	// tables are unlimited by default, so the services can read whole tables
+	l := (&Policy{}).For("micro", "auth")
+	bound, err := l.Bound(0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), bound)
+	assert.NoError(t, l.Check(100000, 1<<30))
+
+	assert.Error(t, (&Policy{Tables: map[string]*Limits{"events": {MaxRecords: 10}}}).Validate())
+	assert.Error(t, (&Policy{Tables: map[string]*Limits{"micro/events": {MaxResponseSize: -1}}}).Validate())
+}
+
+func TestRefresh(t *testing.T) {
+	g := New()
+	calls := make(chan struct{}, 2)
+	g.load = func() (*Policy, error) {
+		calls <- struct{}{}
+		return &Policy{Default: &Limits{MaxRecords: 5}}, nil
+	}
+
+	// the default limits are used until the policy is loaded
+	assert.Equal(t, DefaultLimits, g.Limits("micro", "events"))
+	<-calls
+	assert.Eventually(t, func() bool {
+		return g.Limits("micro", "events").MaxRecords == 5
+	}, time.Second, time.Millisecond*10)
+
+	// the last policy is kept if it can't be loaded
+	g.Lock()
+	g.loaded = time.Time{}
+	g.load = func() (*Policy, error) {
+		calls <- struct{}{}
+		return nil, errors.New("config unavailable")
+	}
+	g.Unlock()
+	g.Limits("micro", "events")
+	<-calls
+	assert.Eventually(t, func() bool {
+		g.Lock()
+		defer g.Unlock()
+		return !g.loading && !g.loaded.IsZero()
+	}, time.Second, time.Millisecond*10)
+	assert.Equal(t, uint(5), g.Limits("micro", "events").MaxRecords)
+}
@@ -0,0 +1,51 @@
+package sync
+
+import (
+	"context"
+	"time"
+
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// RetryInterval is how long Lead waits before standing for election again after an error
+	RetryInterval = time.Second * 10
+)
+
+// Lead runs fn each time the node is elected leader of id, until the context is done. The context
+// passed to fn is cancelled when leadership is lost or the context is done, fn must then return
+// so another node can take over. Leadership is resigned once fn returns.
+func Lead(ctx context.Context, s Sync, id string, fn func(ctx context.Context), opts ...LeaderOption) {
+	opts = append(opts, LeaderContext(ctx))
+
+	for {
+		leader, err := s.Leader(id, opts...)
+		if ctx.Err() != nil {
+			if err == nil {
+				leader.Resign()
+			}
+			return
+		} else if err != nil {
+			logger.Errorf("Error electing leader of %v: %v", id, err)
+			select {
+			case <-time.After(RetryInterval):
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		lctx, cancel := context.WithCancel(ctx)
+		go func() {
+			select {
+			case <-leader.Status():
+				cancel()
+			case <-lctx.Done():
+			}
+		}()
+
+		fn(lctx)
+		cancel()
+		leader.Resign()
+	}
+}
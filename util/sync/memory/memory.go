@@ -16,6 +16,7 @@
 package memory
 
 import (
+	"context"
 	gosync "sync"
 	"time"
 
@@ -58,9 +59,21 @@ func (m *memorySync) Leader(id string, opts ...sync.LeaderOption) (sync.Leader,
 		o(&options)
 	}
 
-	// acquire a lock for the id
-	if err := m.Lock(id); err != nil {
-		return nil, err
+	ctx := options.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// acquire a lock for the id, checking the context while waiting for it
+	for {
+		err := m.Lock(id, sync.LockWait(time.Second))
+		if err == nil {
+			break
+		} else if err != sync.ErrLockTimeout {
+			return nil, err
+		} else if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 	}
 
 	// return the leader
@@ -15,6 +15,7 @@
 package sync
 
 import (
+	"context"
 	"time"
 )
 
@@ -45,3 +46,19 @@ func LockWait(t time.Duration) LockOption {
 		o.Wait = t
 	}
 }
+
+// LeaderTTL sets how long leadership lasts without being renewed, a shorter ttl means a faster
+// failover when the leader dies
+func LeaderTTL(t time.Duration) LeaderOption {
+	return func(o *LeaderOptions) {
+		o.TTL = t
+	}
+}
+
+// LeaderContext sets a context which stops waiting to be elected once it's done, the election
+// then returns the error of the context
+func LeaderContext(ctx context.Context) LeaderOption {
+	return func(o *LeaderOptions) {
+		o.Context = ctx
+	}
+}
@@ -0,0 +1,256 @@
+// Package store provides a sync implementation backed by the store so locks and leadership can
+// be shared by the replicas of a service
+package store
+
+import (
+	"context"
+	"encoding/json"
+	gosync "sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/util/sync"
+)
+
+var (
+	// DefaultTTL is the ttl of locks which don't set one and of leadership
+	DefaultTTL = time.Second * 15
+	// pollInterval is how often a lock held by another node is checked
+	pollInterval = time.Second
+)
+
+type storeSync struct {
+	options sync.Options
+	store   store.Store
+	// id of this node, used as the owner of the leases it holds
+	node string
+}
+
+// lease is the record written for a lock
+type lease struct {
+	Node   string    `json:"node"`
+	Expiry time.Time `json:"expiry"`
+}
+
+type storeLeader struct {
+	// mtx prevents the lease being renewed while it's resigned
+	mtx    gosync.Mutex
+	once   gosync.Once
+	exit   chan bool
+	status chan bool
+	resign func() error
+}
+
+func (l *storeLeader) Resign() error {
+	var err error
+	l.once.Do(func() {
+		l.mtx.Lock()
+		defer l.mtx.Unlock()
+		close(l.exit)
+		err = l.resign()
+	})
+	return err
+}
+
+func (l *storeLeader) Status() chan bool {
+	return l.status
+}
+
+func (s *storeSync) Init(opts ...sync.Option) error {
+	for _, o := range opts {
+		o(&s.options)
+	}
+	return nil
+}
+
+func (s *storeSync) Options() sync.Options {
+	return s.options
+}
+
+// Leader blocks until the node is elected leader or the context of the options is done. The lease
+// is renewed in the background until leadership is resigned, if a renewal fails false is sent on
+// the status channel and the node must stop acting as the leader.
+func (s *storeSync) Leader(id string, opts ...sync.LeaderOption) (sync.Leader, error) {
+	var options sync.LeaderOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	ttl := options.TTL
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+	ctx := options.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := s.lock(ctx, id, ttl, time.Time{}); err != nil {
+		return nil, err
+	}
+
+	l := &storeLeader{
+		exit:   make(chan bool),
+		status: make(chan bool, 1),
+		resign: func() error { return s.Unlock(id) },
+	}
+
+	go func() {
+		// renew well before the lease expires so a slow store doesn't cost us leadership
+		t := time.NewTicker(ttl / 3)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-l.exit:
+				return
+			case <-t.C:
+			}
+
+			l.mtx.Lock()
+			select {
+			case <-l.exit:
+				l.mtx.Unlock()
+				return
+			default:
+			}
+			ok, err := s.renew(id, ttl)
+			l.mtx.Unlock()
+
+			if err != nil || !ok {
+				l.status <- false
+				return
+			}
+		}
+	}()
+
+	return l, nil
+}
+
+// Lock acquires the lock, waiting for it to be released or expire if it's held by another node.
+// If the wait option is not set it waits indefinitely.
+func (s *storeSync) Lock(id string, opts ...sync.LockOption) error {
+	var options sync.LockOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.TTL == 0 {
+		options.TTL = DefaultTTL
+	}
+
+	var deadline time.Time
+	if options.Wait > 0 {
+		deadline = time.Now().Add(options.Wait)
+	}
+	return s.lock(context.Background(), id, options.TTL, deadline)
+}
+
+// lock polls until the lock is acquired, the deadline passes or the context is done
+func (s *storeSync) lock(ctx context.Context, id string, ttl time.Duration, deadline time.Time) error {
+	for {
+		ok, err := s.acquire(id, ttl)
+		if err != nil {
+			return err
+		} else if ok {
+			return nil
+		}
+
+		if !deadline.IsZero() && time.Now().Add(pollInterval).After(deadline) {
+			return sync.ErrLockTimeout
+		}
+		select {
+		case <-time.After(pollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Unlock releases the lock if it's held by this node
+func (s *storeSync) Unlock(id string) error {
+	l, _, err := s.read(id)
+	if err != nil || l == nil || l.Node != s.node {
+		return err
+	}
+	return s.store.Delete(s.key(id))
+}
+
+func (s *storeSync) String() string {
+	return "store"
+}
+
+// acquire writes a lease for the lock if it's not held by another node. The lease is written only
+// if it's unchanged since it was read, so when several nodes acquire the lock at once only one
+// of them holds it.
+func (s *storeSync) acquire(id string, ttl time.Duration) (bool, error) {
+	l, etag, err := s.read(id)
+	if err != nil {
+		return false, err
+	}
+	if l != nil && l.Node != s.node && time.Now().Before(l.Expiry) {
+		return false, nil
+	}
+	return s.write(id, ttl, etag)
+}
+
+// renew extends the lease, returning false if the lock is no longer held by this node
+func (s *storeSync) renew(id string, ttl time.Duration) (bool, error) {
+	l, etag, err := s.read(id)
+	if err != nil {
+		return false, err
+	}
+	if l == nil || l.Node != s.node {
+		return false, nil
+	}
+	return s.write(id, ttl, etag)
+}
+
+// read the lease of the lock and the etag of its record, which is empty if there's no lease
+func (s *storeSync) read(id string) (*lease, string, error) {
+	recs, err := s.store.Read(s.key(id))
+	if err == store.ErrNotFound {
+		return nil, "", nil
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	var l lease
+	if err := json.Unmarshal(recs[0].Value, &l); err != nil {
+		return nil, "", err
+	}
+	return &l, recs[0].Etag, nil
+}
+
+// write a lease held by this node if the record still has the etag, returning false if another
+// node changed the lease since it was read
+func (s *storeSync) write(id string, ttl time.Duration, etag string) (bool, error) {
+	b, err := json.Marshal(&lease{Node: s.node, Expiry: time.Now().Add(ttl)})
+	if err != nil {
+		return false, err
+	}
+	// the record expires a little after the lease so an abandoned lock is eventually cleaned up
+	rec := &store.Record{Key: s.key(id), Value: b, Expiry: ttl * 2}
+	err = s.store.Write(rec, store.IfMatch(etag))
+	if err == store.ErrConflict {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *storeSync) key(id string) string {
+	return "sync/" + s.options.Prefix + id
+}
+
+// NewSync returns a sync which stores its locks in the store provided
+func NewSync(st store.Store, opts ...sync.Option) sync.Sync {
+	var options sync.Options
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return &storeSync{
+		options: options,
+		store:   st,
+		node:    uuid.New().String(),
+	}
+}
@@ -0,0 +1,185 @@
+package store
+
+import (
+	"context"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/micro/micro/v3/util/sync"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	pollInterval = time.Millisecond * 10
+}
+
+func TestLock(t *testing.T) {
+	st := memory.NewStore()
+	a, b := NewSync(st), NewSync(st)
+
+	assert.Nil(t, a.Lock("foo"))
+	assert.Equal(t, sync.ErrLockTimeout, b.Lock("foo", sync.LockWait(time.Millisecond*50)))
+
+	// unlocking a lock held by another node does nothing
+	assert.Nil(t, b.Unlock("foo"))
+	assert.Equal(t, sync.ErrLockTimeout, b.Lock("foo", sync.LockWait(time.Millisecond*50)))
+
+	assert.Nil(t, a.Unlock("foo"))
+	assert.Nil(t, b.Lock("foo", sync.LockWait(time.Millisecond*50)))
+
+	// an expired lock can be acquired by another node
+	assert.Nil(t, b.Unlock("foo"))
+	assert.Nil(t, a.Lock("foo", sync.LockTTL(time.Millisecond*20)))
+	assert.Nil(t, b.Lock("foo", sync.LockWait(time.Millisecond*200)))
+}
+
+func TestLeader(t *testing.T) {
+	st := memory.NewStore()
+	a, b := NewSync(st), NewSync(st)
+
+	la, err := a.Leader("foo", sync.LeaderTTL(time.Millisecond*60))
+	assert.Nil(t, err)
+
+	elected := make(chan sync.Leader)
+	go func() {
+		lb, err := b.Leader("foo", sync.LeaderTTL(time.Millisecond*60))
+		assert.Nil(t, err)
+		elected <- lb
+	}()
+
+	// the leader renews its lease so the other node isn't elected
+	select {
+	case <-elected:
+		t.Fatal("Expected the leadership to be renewed")
+	case <-la.Status():
+		t.Fatal("Expected the leadership to be renewed")
+	case <-time.After(time.Millisecond * 200):
+	}
+
+	// once the leader resigns the other node is elected
+	assert.Nil(t, la.Resign())
+	select {
+	case lb := <-elected:
+		assert.Nil(t, lb.Resign())
+	case <-time.After(time.Second):
+		t.Fatal("Expected a new leader to be elected")
+	}
+}
+
+func TestLockConcurrent(t *testing.T) {
+	st := memory.NewStore()
+
+	// nodes racing for the lock at once are not all given it
+	var wg gosync.WaitGroup
+	var mtx gosync.Mutex
+	held := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if NewSync(st).Lock("foo", sync.LockWait(time.Millisecond*50)) == nil {
+				mtx.Lock()
+				held++
+				mtx.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, held)
+}
+
+func TestLeaderLost(t *testing.T) {
+	st := memory.NewStore()
+	a := NewSync(st).(*storeSync)
+
+	la, err := a.Leader("foo", sync.LeaderTTL(time.Millisecond*60))
+	assert.Nil(t, err)
+
+	// another node overwriting the lease takes the leadership
+	b := NewSync(st).(*storeSync)
+	_, etag, err := b.read("foo")
+	assert.Nil(t, err)
+	ok, err := b.write("foo", time.Minute, etag)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	select {
+	case status := <-la.Status():
+		assert.False(t, status)
+	case <-time.After(time.Second):
+		t.Fatal("Expected the leadership to be lost")
+	}
+}
+
+func TestLeaderContext(t *testing.T) {
+	st := memory.NewStore()
+	a, b := NewSync(st), NewSync(st)
+
+	la, err := a.Leader("foo")
+	assert.Nil(t, err)
+	defer la.Resign()
+
+	// waiting to be elected stops once the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	start := time.Now()
+	_, err = b.Leader("foo", sync.LeaderContext(ctx))
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestLead(t *testing.T) {
+	st := memory.NewStore()
+	a := NewSync(st).(*storeSync)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	elected := make(chan context.Context, 1)
+	done := make(chan bool)
+	go func() {
+		sync.Lead(ctx, a, "foo", func(ctx context.Context) {
+			elected <- ctx
+			<-ctx.Done()
+		}, sync.LeaderTTL(time.Millisecond*60))
+		close(done)
+	}()
+
+	var lctx context.Context
+	select {
+	case lctx = <-elected:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the node to be elected")
+	}
+
+	// losing the leadership cancels the context of the leader
+	b := NewSync(st).(*storeSync)
+	_, etag, err := b.read("foo")
+	assert.Nil(t, err)
+	ok, err := b.write("foo", time.Millisecond*100, etag)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	select {
+	case <-lctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the leadership to be lost")
+	}
+
+	// the node is elected again once the other lease expires
+	select {
+	case <-elected:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the node to be elected again")
+	}
+
+	// cancelling the context stops leading and resigns
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected leading to stop")
+	}
+	l, _, err := a.read("foo")
+	assert.Nil(t, err)
+	assert.Nil(t, l)
+}
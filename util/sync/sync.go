@@ -16,6 +16,7 @@
 package sync
 
 import (
+	"context"
 	"errors"
 	"time"
 )
@@ -55,7 +56,11 @@ type Options struct {
 
 type Option func(o *Options)
 
-type LeaderOptions struct{}
+type LeaderOptions struct {
+	TTL time.Duration
+	// Context stops waiting to be elected once it's done
+	Context context.Context
+}
 
 type LeaderOption func(o *LeaderOptions)
 
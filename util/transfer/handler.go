@@ -0,0 +1,154 @@
+package transfer
+
+import (
+	"context"
+	"io"
+	"os"
+
+	pb "github.com/micro/micro/v3/proto/transfer"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+// Storage of the blobs a service transfers
+type Storage interface {
+	// Size returns the number of bytes stored of the blob
+	Size(name string) (int64, error)
+	// Reader returns a reader of the blob starting at the offset
+	Reader(name string, offset int64) (io.ReadCloser, error)
+	// Writer returns a writer which writes to the blob from the offset, discarding anything
+	// stored after it
+	Writer(name string, offset int64) (io.WriteCloser, error)
+}
+
+// NewHandler returns a handler which transfers the blobs in the storage
+func NewHandler(s Storage, opts ...Option) pb.TransferHandler {
+	return &handler{storage: s, options: newOptions(opts...)}
+}
+
+type handler struct {
+	storage Storage
+	options Options
+}
+
+func (h *handler) Download(ctx context.Context, req *pb.DownloadRequest, stream pb.Transfer_DownloadStream) error {
+	if len(req.Name) == 0 {
+		return errors.BadRequest("transfer.Download", "missing name")
+	}
+	size, err := h.storage.Size(req.Name)
+	if os.IsNotExist(err) {
+		return errors.NotFound("transfer.Download", "%v not found", req.Name)
+	} else if err != nil {
+		return errors.InternalServerError("transfer.Download", "error reading %v: %v", req.Name, err)
+	}
+	if req.Offset < 0 || req.Offset > size {
+		return errors.BadRequest("transfer.Download", "offset %d is outside the blob", req.Offset)
+	}
+
+	r, err := h.storage.Reader(req.Name, req.Offset)
+	if err != nil {
+		return errors.InternalServerError("transfer.Download", "error reading %v: %v", req.Name, err)
+	}
+	defer r.Close()
+
+	offset := req.Offset
+	buf := make([]byte, h.options.ChunkSize)
+	for offset < size {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			chunk := &pb.Chunk{
+				Name:     req.Name,
+				Offset:   offset,
+				Data:     buf[:n],
+				Checksum: Checksum(buf[:n]),
+				Size:     size,
+			}
+			// the send blocks while the client is behind, so the blob is read at the rate
+			// the client receives it
+			if err := stream.Send(chunk); err != nil {
+				return err
+			}
+			offset += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		} else if err != nil {
+			return errors.InternalServerError("transfer.Download", "error reading %v: %v", req.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (h *handler) Upload(ctx context.Context, stream pb.Transfer_UploadStream) error {
+	var w io.WriteCloser
+	var name string
+	var offset int64
+
+	defer func() {
+		if w != nil {
+			w.Close()
+		}
+	}()
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		// the writer is opened at the offset of the first chunk to resume the upload
+		if w == nil {
+			if len(chunk.Name) == 0 {
+				return errors.BadRequest("transfer.Upload", "missing name")
+			}
+			size, err := h.storage.Size(chunk.Name)
+			if err != nil && !os.IsNotExist(err) {
+				return errors.InternalServerError("transfer.Upload", "error reading %v: %v", chunk.Name, err)
+			}
+			if chunk.Offset < 0 || chunk.Offset > size {
+				return errors.BadRequest("transfer.Upload", "offset %d is beyond the %d bytes received", chunk.Offset, size)
+			}
+			name, offset = chunk.Name, chunk.Offset
+			if w, err = h.storage.Writer(name, offset); err != nil {
+				return errors.InternalServerError("transfer.Upload", "error writing %v: %v", name, err)
+			}
+		}
+
+		if chunk.Name != name || chunk.Offset != offset {
+			return errors.BadRequest("transfer.Upload", ErrOffset.Error())
+		}
+		if Checksum(chunk.Data) != chunk.Checksum {
+			return errors.BadRequest("transfer.Upload", ErrChecksum.Error())
+		}
+		if _, err := w.Write(chunk.Data); err != nil {
+			return errors.InternalServerError("transfer.Upload", "error writing %v: %v", name, err)
+		}
+		offset += int64(len(chunk.Data))
+	}
+
+	if w == nil {
+		return errors.BadRequest("transfer.Upload", "no chunks received")
+	}
+	if err := w.Close(); err != nil {
+		return errors.InternalServerError("transfer.Upload", "error writing %v: %v", name, err)
+	}
+	w = nil
+
+	return stream.SendAndClose(&pb.UploadResponse{Size: offset})
+}
+
+func (h *handler) Stat(ctx context.Context, req *pb.StatRequest, rsp *pb.StatResponse) error {
+	if len(req.Name) == 0 {
+		return errors.BadRequest("transfer.Stat", "missing name")
+	}
+	size, err := h.storage.Size(req.Name)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.InternalServerError("transfer.Stat", "error reading %v: %v", req.Name, err)
+	}
+	rsp.Size = size
+	return nil
+}
@@ -0,0 +1,69 @@
+package transfer
+
+import "github.com/micro/micro/v3/service/client"
+
+// Options of a transfer
+type Options struct {
+	// ChunkSize is the number of bytes sent in each chunk of an upload
+	ChunkSize int
+	// Offset to start a download from
+	Offset int64
+	// Resume an upload from the number of bytes the service already has
+	Resume bool
+	// Progress is called after each chunk with the bytes transferred and the size of the blob,
+	// the size is -1 if it's not known
+	Progress func(done, size int64)
+	// CallOptions passed to the client
+	CallOptions []client.CallOption
+}
+
+// Option sets a transfer option
+type Option func(o *Options)
+
+func newOptions(opts ...Option) Options {
+	options := Options{
+		ChunkSize: DefaultChunkSize,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.ChunkSize <= 0 {
+		options.ChunkSize = DefaultChunkSize
+	}
+	return options
+}
+
+// ChunkSize sets the number of bytes sent in each chunk of an upload
+func ChunkSize(n int) Option {
+	return func(o *Options) {
+		o.ChunkSize = n
+	}
+}
+
+// Offset starts a download from the offset, e.g. the offset returned by an interrupted download
+func Offset(n int64) Option {
+	return func(o *Options) {
+		o.Offset = n
+	}
+}
+
+// Resume an upload from the number of bytes the service already has
+func Resume() Option {
+	return func(o *Options) {
+		o.Resume = true
+	}
+}
+
+// Progress sets the func called after each chunk is transferred
+func Progress(fn func(done, size int64)) Option {
+	return func(o *Options) {
+		o.Progress = fn
+	}
+}
+
+// WithCallOptions sets the options passed to the client
+func WithCallOptions(opts ...client.CallOption) Option {
+	return func(o *Options) {
+		o.CallOptions = append(o.CallOptions, opts...)
+	}
+}
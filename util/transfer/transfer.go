@@ -0,0 +1,182 @@
+// Package transfer streams large blobs between services in chunks over micro streams, so they
+// don't have to be routed through a shared disk or the blob store. Each chunk carries its offset
+// and a checksum of its data, so corruption is detected and an interrupted transfer can be
+// resumed from the last chunk received rather than starting over.
+//
+// Services serve blobs by registering the handler:
+//
+//	pb.RegisterTransferHandler(srv.Server(), transfer.NewHandler(transfer.Dir("/data")))
+//
+// and other services download or upload them using a client:
+//
+//	c := transfer.NewClient("files")
+//	n, err := c.Download(ctx, "backup.tar", f, transfer.Progress(fn))
+package transfer
+
+import (
+	"context"
+	"errors"
+	"hash/crc32"
+	"io"
+
+	pb "github.com/micro/micro/v3/proto/transfer"
+	"github.com/micro/micro/v3/service/client"
+)
+
+var (
+	// DefaultChunkSize is the number of bytes sent in each chunk
+	DefaultChunkSize = 64 * 1024
+
+	// ErrChecksum is returned when the data of a chunk doesn't match its checksum
+	ErrChecksum = errors.New("chunk checksum mismatch")
+	// ErrOffset is returned when a chunk doesn't follow on from the previous one
+	ErrOffset = errors.New("chunk offset mismatch")
+	// ErrIncomplete is returned when the stream ends before the whole blob was transferred
+	ErrIncomplete = errors.New("transfer incomplete")
+
+	table = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// Checksum of the data in a chunk
+func Checksum(data []byte) uint32 {
+	return crc32.Checksum(data, table)
+}
+
+// Client transfers blobs to and from a service
+type Client struct {
+	client pb.TransferService
+}
+
+// NewClient returns a client for the service
+func NewClient(service string) *Client {
+	return &Client{client: pb.NewTransferService(service, client.DefaultClient)}
+}
+
+// Stat returns the number of bytes the service has of the blob
+func (c *Client) Stat(ctx context.Context, name string) (int64, error) {
+	rsp, err := c.client.Stat(ctx, &pb.StatRequest{Name: name})
+	if err != nil {
+		return 0, err
+	}
+	return rsp.Size, nil
+}
+
+// Download the blob to the writer, starting from the offset option. The offset reached is
+// returned, even on error, so the download can be resumed by passing it as the offset.
+func (c *Client) Download(ctx context.Context, name string, w io.Writer, opts ...Option) (int64, error) {
+	options := newOptions(opts...)
+	offset := options.Offset
+
+	stream, err := c.client.Download(ctx, &pb.DownloadRequest{Name: name, Offset: offset}, options.CallOptions...)
+	if err != nil {
+		return offset, err
+	}
+	defer stream.Close()
+
+	size := int64(-1)
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return offset, err
+		}
+
+		if chunk.Size > 0 {
+			size = chunk.Size
+		}
+		if chunk.Offset != offset {
+			return offset, ErrOffset
+		}
+		if Checksum(chunk.Data) != chunk.Checksum {
+			return offset, ErrChecksum
+		}
+		if _, err := w.Write(chunk.Data); err != nil {
+			return offset, err
+		}
+
+		offset += int64(len(chunk.Data))
+		if options.Progress != nil {
+			options.Progress(offset, size)
+		}
+	}
+
+	if size >= 0 && offset != size {
+		return offset, ErrIncomplete
+	}
+	return offset, nil
+}
+
+// Upload the blob from the reader. If the resume option is set the upload continues from the
+// number of bytes the service already has, otherwise it starts from the beginning. The number of
+// bytes the service has once the upload completes is returned.
+func (c *Client) Upload(ctx context.Context, name string, r io.ReadSeeker, opts ...Option) (int64, error) {
+	options := newOptions(opts...)
+
+	// the size lets the service know when it has the whole blob
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+
+	var offset int64
+	if options.Resume {
+		offset, err = c.Stat(ctx, name)
+		if err != nil {
+			return 0, err
+		}
+		if offset > size {
+			offset = 0
+		}
+	}
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	stream, err := c.client.Upload(ctx, options.CallOptions...)
+	if err != nil {
+		return offset, err
+	}
+
+	buf := make([]byte, options.ChunkSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if err == io.EOF {
+			break
+		} else if err != nil && err != io.ErrUnexpectedEOF {
+			return offset, err
+		}
+
+		chunk := &pb.Chunk{
+			Name:     name,
+			Offset:   offset,
+			Data:     buf[:n],
+			Checksum: Checksum(buf[:n]),
+			Size:     size,
+		}
+		if err := stream.Send(chunk); err != nil {
+			return offset, err
+		}
+
+		offset += int64(n)
+		if options.Progress != nil {
+			options.Progress(offset, size)
+		}
+	}
+
+	// an empty blob is still created
+	if size == 0 {
+		if err := stream.Send(&pb.Chunk{Name: name, Checksum: Checksum(nil)}); err != nil {
+			return 0, err
+		}
+	}
+
+	rsp, err := stream.CloseAndRecv()
+	if err != nil {
+		return offset, err
+	}
+	if rsp.Size != size {
+		return rsp.Size, ErrIncomplete
+	}
+	return rsp.Size, nil
+}
@@ -0,0 +1,163 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/micro/micro/v3/proto/transfer"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/stretchr/testify/assert"
+)
+
+// local calls the handler directly in place of a service
+type local struct {
+	h pb.TransferHandler
+}
+
+func (l *local) Stat(ctx context.Context, in *pb.StatRequest, opts ...client.CallOption) (*pb.StatResponse, error) {
+	rsp := new(pb.StatResponse)
+	return rsp, l.h.Stat(ctx, in, rsp)
+}
+
+func (l *local) Download(ctx context.Context, in *pb.DownloadRequest, opts ...client.CallOption) (pb.Transfer_DownloadService, error) {
+	s := newStream(ctx)
+	go func() {
+		s.err = l.h.Download(ctx, in, s)
+		close(s.chunks)
+	}()
+	return s, nil
+}
+
+func (l *local) Upload(ctx context.Context, opts ...client.CallOption) (pb.Transfer_UploadService, error) {
+	s := newStream(ctx)
+	go func() {
+		s.err = l.h.Upload(ctx, s)
+		close(s.done)
+	}()
+	return s, nil
+}
+
+type stream struct {
+	ctx    context.Context
+	chunks chan *pb.Chunk
+	done   chan bool
+	rsp    *pb.UploadResponse
+	err    error
+}
+
+func newStream(ctx context.Context) *stream {
+	return &stream{ctx: ctx, chunks: make(chan *pb.Chunk), done: make(chan bool)}
+}
+
+func (s *stream) Context() context.Context    { return s.ctx }
+func (s *stream) SendMsg(m interface{}) error { return s.Send(m.(*pb.Chunk)) }
+func (s *stream) RecvMsg(m interface{}) error { return nil }
+func (s *stream) Close() error                { return nil }
+func (s *stream) SendAndClose(rsp *pb.UploadResponse) error {
+	s.rsp = rsp
+	return nil
+}
+
+func (s *stream) Send(c *pb.Chunk) error {
+	// the data is reused by the sender once sent
+	c.Data = append([]byte(nil), c.Data...)
+	s.chunks <- c
+	return nil
+}
+
+func (s *stream) Recv() (*pb.Chunk, error) {
+	c, ok := <-s.chunks
+	if !ok {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	return c, nil
+}
+
+func (s *stream) CloseAndRecv() (*pb.UploadResponse, error) {
+	close(s.chunks)
+	<-s.done
+	return s.rsp, s.err
+}
+
+func TestTransfer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "transfer")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c := &Client{client: &local{NewHandler(Dir(dir), ChunkSize(10))}}
+	ctx := context.TODO()
+	blob := bytes.Repeat([]byte("0123456789abcdef"), 10)
+
+	// upload in chunks, reporting the progress
+	var progress []int64
+	n, err := c.Upload(ctx, "a/blob", bytes.NewReader(blob), ChunkSize(64), Progress(func(done, size int64) {
+		assert.Equal(t, int64(len(blob)), size)
+		progress = append(progress, done)
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(blob)), n)
+	assert.Equal(t, []int64{64, 128, 160}, progress)
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "a/blob"))
+	assert.NoError(t, err)
+	assert.Equal(t, blob, b)
+
+	// resume an upload which was interrupted
+	assert.NoError(t, os.Truncate(filepath.Join(dir, "a/blob"), 100))
+	size, err := c.Stat(ctx, "a/blob")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), size)
+
+	progress = nil
+	n, err = c.Upload(ctx, "a/blob", bytes.NewReader(blob), Resume(), Progress(func(done, size int64) {
+		progress = append(progress, done)
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(blob)), n)
+	assert.Equal(t, []int64{160}, progress)
+
+	// download, then resume from an offset
+	buf := bytes.NewBuffer(nil)
+	n, err = c.Download(ctx, "a/blob", buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(blob)), n)
+	assert.Equal(t, blob, buf.Bytes())
+
+	buf.Reset()
+	n, err = c.Download(ctx, "a/blob", buf, Offset(150))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(blob)), n)
+	assert.Equal(t, blob[150:], buf.Bytes())
+
+	// names outside the directory are rejected
+	_, err = c.Upload(ctx, "../escape", bytes.NewReader(blob))
+	assert.Error(t, err)
+	_, err = c.Download(ctx, "missing", buf)
+	assert.Error(t, err)
+}
+
+func TestChecksum(t *testing.T) {
+	dir, err := ioutil.TempDir("", "transfer")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s := newStream(context.TODO())
+	go func() {
+		s.err = NewHandler(Dir(dir)).Upload(context.TODO(), s)
+		close(s.done)
+	}()
+
+	s.Send(&pb.Chunk{Name: "blob", Data: []byte("hello"), Checksum: Checksum([]byte("hello"))})
+	s.Send(&pb.Chunk{Name: "blob", Offset: 5, Data: []byte("world"), Checksum: Checksum([]byte("hello"))})
+	_, err = s.CloseAndRecv()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), ErrChecksum.Error())
+}
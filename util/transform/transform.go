@@ -0,0 +1,386 @@
+// Package transform rewrites the requests and responses of routes in the api gateway, so the
+// shape of an external api can be adapted without changing the services behind it. Rules set and
+// remove headers, rewrite paths and rename or remove the fields of JSON bodies. The rules are
+// loaded from the config service at runtime so they can be changed without redeploying, e.g.
+//
+//	micro config set transforms '[{"route": "/v1/users", "request": {"rewrite": {"from": "^/v1/users/(.*)$", "to": "/users/$1"}, "rename": {"userName": "name"}}, "response": {"set_headers": {"Api-Version": "1"}, "rename": {"name": "userName", "addresses.*.zip": "addresses.*.postcode"}}}]'
+//
+// Fields are referenced by dot separated paths, a "*" matches each element of an array or each
+// value of an object.
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/micro/v3/service/config"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// ConfigPath is the path of the rules in the config service
+	ConfigPath = "transforms"
+	// RefreshInterval is how often the rules are reloaded from the config service
+	RefreshInterval = time.Minute
+	// MaxBodySize is the size of the largest body transformed, larger bodies are passed through
+	MaxBodySize = 10 * 1024 * 1024
+
+	// ErrNotJSON is returned when a body which isn't a JSON object or array is transformed
+	ErrNotJSON = errors.New("body isn't a JSON object or array")
+)
+
+// Rule transforms the requests to a route and their responses
+type Rule struct {
+	// Name of the rule, used in logs
+	Name string `json:"name,omitempty"`
+	// Host to match, all hosts match if blank
+	Host string `json:"host,omitempty"`
+	// Route is the path prefix to match
+	Route string `json:"route"`
+	// Methods to match, all methods match if empty
+	Methods []string `json:"methods,omitempty"`
+	// Request is the transform applied to the request before it's routed
+	Request *Transform `json:"request,omitempty"`
+	// Response is the transform applied to the response
+	Response *Transform `json:"response,omitempty"`
+}
+
+// Transform of a request or response
+type Transform struct {
+	// SetHeaders are set, replacing any existing values
+	SetHeaders map[string]string `json:"set_headers,omitempty"`
+	// RemoveHeaders are removed before the headers are set
+	RemoveHeaders []string `json:"remove_headers,omitempty"`
+	// Rewrite the path of the request, ignored for responses
+	Rewrite *Rewrite `json:"rewrite,omitempty"`
+	// Rename the fields of the JSON body, keyed by the path of the field to its new path
+	Rename map[string]string `json:"rename,omitempty"`
+	// Remove the fields of the JSON body
+	Remove []string `json:"remove,omitempty"`
+
+	rewrite *regexp.Regexp
+}
+
+// Rewrite replaces the path matching the regular expression, the replacement can reference the
+// groups of the expression, e.g. $1
+type Rewrite struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Matches returns true if the request should be transformed by the rule
+func (r *Rule) Matches(host, method, path string) bool {
+	if len(r.Host) > 0 && !strings.EqualFold(r.Host, host) {
+		return false
+	}
+	if len(r.Methods) > 0 {
+		var found bool
+		for _, m := range r.Methods {
+			if strings.EqualFold(m, method) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return strings.HasPrefix(path, r.Route)
+}
+
+// Validate the rule, compiling its rewrite
+func (r *Rule) Validate() error {
+	if len(r.Route) == 0 || !strings.HasPrefix(r.Route, "/") {
+		return errors.New("the route must be a path prefix, e.g. /users")
+	}
+	for _, t := range []*Transform{r.Request, r.Response} {
+		if t == nil {
+			continue
+		}
+		if err := t.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *Transform) validate() error {
+	if t.Rewrite != nil {
+		re, err := regexp.Compile(t.Rewrite.From)
+		if err != nil {
+			return fmt.Errorf("invalid rewrite %q: %v", t.Rewrite.From, err)
+		}
+		t.rewrite = re
+	}
+	for from, to := range t.Rename {
+		prefix, fromRest, toRest := splitRename(from, to)
+		if len(fromRest) == 0 || len(toRest) == 0 {
+			return fmt.Errorf("invalid rename of %q to %q", from, to)
+		}
+		for _, s := range append(fromRest, toRest...) {
+			if s == "*" || len(s) == 0 {
+				return fmt.Errorf("invalid rename of %q to %q, wildcards must be in the same position of both paths", from, to)
+			}
+		}
+		for _, s := range prefix {
+			if len(s) == 0 {
+				return fmt.Errorf("invalid rename of %q to %q", from, to)
+			}
+		}
+	}
+	for _, p := range t.Remove {
+		for _, s := range strings.Split(p, ".") {
+			if len(s) == 0 {
+				return fmt.Errorf("invalid path %q", p)
+			}
+		}
+	}
+	return nil
+}
+
+// Headers sets and removes the headers
+func (t *Transform) Headers(h http.Header) {
+	for _, k := range t.RemoveHeaders {
+		h.Del(k)
+	}
+	for k, v := range t.SetHeaders {
+		h.Set(k, v)
+	}
+}
+
+// Path returns the path rewritten
+func (t *Transform) Path(path string) string {
+	if t.rewrite == nil {
+		return path
+	}
+	return t.rewrite.ReplaceAllString(path, t.Rewrite.To)
+}
+
+// HasBody returns true if the transform changes the body
+func (t *Transform) HasBody() bool {
+	return len(t.Rename) > 0 || len(t.Remove) > 0
+}
+
+// Body returns the JSON body with the fields renamed and removed. Renames are applied before the
+// fields are removed.
+func (t *Transform) Body(b []byte) ([]byte, error) {
+	if !t.HasBody() {
+		return b, nil
+	}
+	trimmed := bytes.TrimSpace(b)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return nil, ErrNotJSON
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	// numbers are kept as they are rather than being converted to floats
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	for from, to := range t.Rename {
+		rename(v, from, to)
+	}
+	for _, p := range t.Remove {
+		path := strings.Split(p, ".")
+		walk(v, path[:len(path)-1], func(parent interface{}) {
+			if obj, ok := parent.(map[string]interface{}); ok {
+				delete(obj, path[len(path)-1])
+			}
+		})
+	}
+	return json.Marshal(v)
+}
+
+// splitRename splits the paths of a rename into their common prefix and the remainder of each
+func splitRename(from, to string) (prefix, fromRest, toRest []string) {
+	f, t := strings.Split(from, "."), strings.Split(to, ".")
+	i := 0
+	for i < len(f)-1 && i < len(t)-1 && f[i] == t[i] {
+		i++
+	}
+	return f[:i], f[i:], t[i:]
+}
+
+// rename the field at the path from to the path to, the wildcards of the paths are the common
+// prefix so the field is moved within each element matched
+func rename(v interface{}, from, to string) {
+	prefix, fromRest, toRest := splitRename(from, to)
+	walk(v, prefix, func(parent interface{}) {
+		obj, ok := parent.(map[string]interface{})
+		if !ok {
+			return
+		}
+		val, ok := take(obj, fromRest)
+		if !ok {
+			return
+		}
+		put(obj, toRest, val)
+	})
+}
+
+// walk calls the function with each value at the path
+func walk(v interface{}, path []string, fn func(interface{})) {
+	if len(path) == 0 {
+		fn(v)
+		return
+	}
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if path[0] == "*" {
+			for _, c := range t {
+				walk(c, path[1:], fn)
+			}
+		} else if c, ok := t[path[0]]; ok {
+			walk(c, path[1:], fn)
+		}
+	case []interface{}:
+		if path[0] == "*" {
+			for _, c := range t {
+				walk(c, path[1:], fn)
+			}
+		} else if i, err := strconv.Atoi(path[0]); err == nil && i >= 0 && i < len(t) {
+			walk(t[i], path[1:], fn)
+		}
+	}
+}
+
+// take removes the value at the path of the object, returning false if it doesn't exist
+func take(obj map[string]interface{}, path []string) (interface{}, bool) {
+	for _, k := range path[:len(path)-1] {
+		c, ok := obj[k].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		obj = c
+	}
+	k := path[len(path)-1]
+	val, ok := obj[k]
+	if ok {
+		delete(obj, k)
+	}
+	return val, ok
+}
+
+// put the value at the path of the object, creating the objects it's nested in
+func put(obj map[string]interface{}, path []string, val interface{}) {
+	for _, k := range path[:len(path)-1] {
+		c, ok := obj[k].(map[string]interface{})
+		if !ok {
+			c = map[string]interface{}{}
+			obj[k] = c
+		}
+		obj = c
+	}
+	obj[path[len(path)-1]] = val
+}
+
+// Transformer matches requests to the rules loaded from the config service
+type Transformer struct {
+	sync.Mutex
+	rules   []*Rule
+	loaded  time.Time
+	loading bool
+
+	// load the rules, replaced in tests
+	load func() ([]*Rule, error)
+}
+
+// New returns a transformer which loads the rules from the config service
+func New() *Transformer {
+	return &Transformer{load: loadConfig}
+}
+
+// NewStatic returns a transformer with fixed rules, which aren't loaded from the config service
+func NewStatic(rules ...*Rule) (*Transformer, error) {
+	if err := validate(rules); err != nil {
+		return nil, err
+	}
+	return &Transformer{
+		rules: rules,
+		load:  func() ([]*Rule, error) { return rules, nil },
+	}, nil
+}
+
+// Match returns the first rule which matches the request, or nil if none do. Until the rules are
+// first loaded no requests are matched.
+func (t *Transformer) Match(host, method, path string) *Rule {
+	for _, r := range t.refresh() {
+		if r.Matches(host, method, path) {
+			return r
+		}
+	}
+	return nil
+}
+
+// refresh returns the rules, reloading them in the background if they're stale
+func (t *Transformer) refresh() []*Rule {
+	t.Lock()
+	defer t.Unlock()
+
+	if time.Since(t.loaded) < RefreshInterval || t.loading {
+		return t.rules
+	}
+	t.loading = true
+
+	go func() {
+		rules, err := t.load()
+
+		t.Lock()
+		defer t.Unlock()
+		t.loading = false
+		t.loaded = time.Now()
+		if err != nil {
+			// keep the last rules loaded
+			logger.Warnf("Error loading transforms: %v", err)
+			return
+		}
+		t.rules = rules
+	}()
+
+	return t.rules
+}
+
+func loadConfig() ([]*Rule, error) {
+	if config.DefaultConfig == nil {
+		return nil, nil
+	}
+	val, err := config.Get(ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	if !val.Exists() {
+		return nil, nil
+	}
+	var rules []*Rule
+	if err := val.Scan(&rules); err != nil {
+		return nil, err
+	}
+	if err := validate(rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func validate(rules []*Rule) error {
+	for i, r := range rules {
+		if err := r.Validate(); err != nil {
+			name := r.Name
+			if len(name) == 0 {
+				name = strconv.Itoa(i)
+			}
+			return fmt.Errorf("invalid rule %v: %v", name, err)
+		}
+	}
+	return nil
+}
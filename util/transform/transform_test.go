@@ -0,0 +1,124 @@
+package transform
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	tr, err := NewStatic(
+		&Rule{Route: "/v1/users", Host: "api.example.com", Methods: []string{"post"}},
+		&Rule{Route: "/v1/"},
+	)
+	assert.NoError(t, err)
+	tr.loaded = time.Now()
+
+	assert.Equal(t, "/v1/users", tr.Match("API.example.com", "POST", "/v1/users/create").Route)
+	assert.Equal(t, "/v1/", tr.Match("api.example.com", "GET", "/v1/users/read").Route)
+	assert.Equal(t, "/v1/", tr.Match("other.example.com", "POST", "/v1/users/create").Route)
+	assert.Nil(t, tr.Match("api.example.com", "POST", "/users/create"))
+}
+
+func TestValidate(t *testing.T) {
+	_, err := NewStatic(&Rule{Route: "users"})
+	assert.Error(t, err)
+	_, err = NewStatic(&Rule{Route: "/users", Request: &Transform{Rewrite: &Rewrite{From: "("}}})
+	assert.Error(t, err)
+	// wildcards of renames must be in the same position of both paths
+	_, err = NewStatic(&Rule{Route: "/users", Response: &Transform{Rename: map[string]string{"items.*.zip": "zips.*"}}})
+	assert.Error(t, err)
+	_, err = NewStatic(&Rule{Route: "/users", Response: &Transform{Remove: []string{"user..password"}}})
+	assert.Error(t, err)
+}
+
+func TestHeadersAndPath(t *testing.T) {
+	tr := &Transform{
+		SetHeaders:    map[string]string{"Api-Version": "2"},
+		RemoveHeaders: []string{"X-Internal", "Api-Version"},
+		Rewrite:       &Rewrite{From: "^/v1/users/(.*)$", To: "/users/$1"},
+	}
+	assert.NoError(t, tr.validate())
+
+	h := http.Header{"X-Internal": {"true"}, "Api-Version": {"1"}}
+	tr.Headers(h)
+	assert.Equal(t, http.Header{"Api-Version": {"2"}}, h)
+
+	assert.Equal(t, "/users/read", tr.Path("/v1/users/read"))
+	assert.Equal(t, "/accounts/read", tr.Path("/accounts/read"))
+}
+
+func TestBody(t *testing.T) {
+	tr := &Transform{
+		Rename: map[string]string{
+			"userName":          "name",
+			"profile.bio":       "about.bio",
+			"addresses.*.zip":   "addresses.*.postcode",
+			"addresses.*.lines": "addresses.*.street.lines",
+		},
+		Remove: []string{"password", "addresses.*.internal"},
+	}
+	assert.NoError(t, tr.validate())
+
+	out, err := tr.Body([]byte(`{
+		"userName": "john",
+		"password": "secret",
+		"id": 12345678901234567890,
+		"profile": {"bio": "hello"},
+		"addresses": [{"zip": "N1", "lines": ["1 Street"], "internal": true}, {"city": "London"}]
+	}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": "john",
+		"id": 12345678901234567890,
+		"profile": {},
+		"about": {"bio": "hello"},
+		"addresses": [{"postcode": "N1", "street": {"lines": ["1 Street"]}}, {"city": "London"}]
+	}`, string(out))
+
+	// wildcards match the elements of top level arrays
+	arr := &Transform{Rename: map[string]string{"*.userName": "*.name"}}
+	assert.NoError(t, arr.validate())
+	out, err = arr.Body([]byte(`[{"userName": "john"}, {"userName": "jane"}]`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"name": "john"}, {"name": "jane"}]`, string(out))
+
+	_, err = tr.Body([]byte(`"hello"`))
+	assert.Equal(t, ErrNotJSON, err)
+	_, err = tr.Body([]byte(`{"userName":`))
+	assert.Error(t, err)
+}
+
+func TestRefresh(t *testing.T) {
+	tr := New()
+	tr.load = func() ([]*Rule, error) {
+		return []*Rule{{Route: "/users"}}, nil
+	}
+
+	// requests aren't transformed until the rules are first loaded
+	assert.Nil(t, tr.Match("", "GET", "/users"))
+	assert.Eventually(t, func() bool {
+		return tr.Match("", "GET", "/users") != nil
+	}, time.Second, time.Millisecond*10)
+
+	// the last rules loaded are kept if the rules can't be reloaded
+	failed := make(chan bool)
+	tr.Lock()
+	tr.loaded = time.Time{}
+	tr.load = func() ([]*Rule, error) {
+		close(failed)
+		return nil, errors.New("config unavailable")
+	}
+	tr.Unlock()
+	assert.NotNil(t, tr.Match("", "GET", "/users"))
+	<-failed
+	assert.Eventually(t, func() bool {
+		tr.Lock()
+		defer tr.Unlock()
+		return !tr.loading
+	}, time.Second, time.Millisecond*10)
+	assert.NotNil(t, tr.Match("", "GET", "/users"))
+}
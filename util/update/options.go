@@ -0,0 +1,68 @@
+package update
+
+import "net/http"
+
+// Options for fetching and applying releases
+type Options struct {
+	// URL the release manifests are served at
+	URL string
+	// Channel to fetch the latest release of, e.g. stable or beta
+	Channel string
+	// Version to fetch, overriding the channel
+	Version string
+	// PublicKey binaries are verified with, base64 encoded
+	PublicKey string
+	// Client used to fetch releases
+	Client *http.Client
+}
+
+// Option sets an option
+type Option func(o *Options)
+
+func newOptions(opts ...Option) Options {
+	o := Options{
+		URL:       DefaultURL,
+		Channel:   DefaultChannel,
+		PublicKey: PublicKey,
+		Client:    http.DefaultClient,
+	}
+	for _, fn := range opts {
+		fn(&o)
+	}
+	return o
+}
+
+// URL sets the url the release manifests are served at
+func URL(u string) Option {
+	return func(o *Options) {
+		o.URL = u
+	}
+}
+
+// Channel sets the channel to fetch the latest release of
+func Channel(c string) Option {
+	return func(o *Options) {
+		o.Channel = c
+	}
+}
+
+// Version pins the version to fetch
+func Version(v string) Option {
+	return func(o *Options) {
+		o.Version = v
+	}
+}
+
+// WithPublicKey sets the key binaries are verified with
+func WithPublicKey(k string) Option {
+	return func(o *Options) {
+		o.PublicKey = k
+	}
+}
+
+// WithClient sets the http client used to fetch releases
+func WithClient(c *http.Client) Option {
+	return func(o *Options) {
+		o.Client = c
+	}
+}
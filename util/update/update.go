@@ -0,0 +1,241 @@
+// Package update fetches releases of the micro binary and replaces the running binary with them.
+// Releases are described by a manifest served for each channel, e.g. <url>/stable.json, and for
+// each version, e.g. <url>/versions/v3.6.0.json. The manifest lists the binary of each platform
+// with its sha256 checksum and an ed25519 signature of <version>|<platform>|<sha256>, which is
+// verified against the release key built into the binary before it's swapped in. Signing the
+// version and platform stops a signed binary being served as another release or platform.
+package update
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	ver "github.com/hashicorp/go-version"
+)
+
+var (
+	// PublicKey is the base64 encoded ed25519 key releases are signed with, populated by ldflags
+	PublicKey string
+	// DefaultURL is where the release manifests are served
+	DefaultURL = "https://micro.mu/releases"
+	// DefaultChannel is the channel updated from
+	DefaultChannel = "stable"
+
+	// ErrNoPublicKey is returned when the binary was built without a release key
+	ErrNoPublicKey = errors.New("binary built without a release key, updates can't be verified")
+	// ErrInvalidSignature is returned when the signature of a binary doesn't match the release key
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrChecksumMismatch is returned when the binary downloaded doesn't match the manifest
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	// ErrDowngrade is returned when a release is older than the running binary
+	ErrDowngrade = errors.New("release is older than the running binary")
+)
+
+// Release of the micro binary
+type Release struct {
+	Version string `json:"version"`
+	Channel string `json:"channel,omitempty"`
+	// Binaries keyed by platform in the form <os>-<arch>, e.g. linux-amd64
+	Binaries map[string]*Binary `json:"binaries"`
+}
+
+// Binary of a release for a platform
+type Binary struct {
+	URL string `json:"url"`
+	// SHA256 is the hex encoded checksum of the binary
+	SHA256 string `json:"sha256"`
+	// Signature is the base64 encoded ed25519 signature of the message returned by Message
+	Signature string `json:"signature"`
+
+	// version and platform of the release the binary was listed under, set by Release.Binary
+	version  string
+	platform string
+}
+
+// Message returns the message signed for the binary of a release
+func Message(version, platform, sha256 string) []byte {
+	return []byte(version + "|" + platform + "|" + strings.ToLower(sha256))
+}
+
+// CheckVersion returns ErrDowngrade if the release is older than the current version. Current
+// versions which can't be parsed, e.g. those of development builds, aren't checked.
+func CheckVersion(current, release string) error {
+	cv, err := ver.NewVersion(current)
+	if err != nil {
+		return nil
+	}
+	rv, err := ver.NewVersion(release)
+	if err != nil {
+		return fmt.Errorf("invalid release version %v: %v", release, err)
+	}
+	if rv.LessThan(cv) {
+		return ErrDowngrade
+	}
+	return nil
+}
+
+// Platform returns the platform of the running binary
+func Platform() string {
+	return runtime.GOOS + "-" + runtime.GOARCH
+}
+
+// Fetch the manifest of the release. The pinned version is fetched if one is set, otherwise the
+// latest release of the channel.
+func Fetch(ctx context.Context, opts ...Option) (*Release, error) {
+	options := newOptions(opts...)
+
+	url := strings.TrimSuffix(options.URL, "/") + "/" + options.Channel + ".json"
+	if len(options.Version) > 0 {
+		url = strings.TrimSuffix(options.URL, "/") + "/versions/" + options.Version + ".json"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := options.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching %v: %v", url, rsp.Status)
+	}
+
+	var rel *Release
+	if err := json.NewDecoder(rsp.Body).Decode(&rel); err != nil {
+		return nil, fmt.Errorf("error decoding release: %v", err)
+	}
+	if len(rel.Version) == 0 {
+		return nil, errors.New("release is missing a version")
+	}
+	return rel, nil
+}
+
+// Binary returns the binary of the release for the platform
+func (r *Release) Binary(platform string) (*Binary, error) {
+	b, ok := r.Binaries[platform]
+	if !ok {
+		return nil, fmt.Errorf("release %v has no binary for %v", r.Version, platform)
+	}
+	b.version = r.Version
+	b.platform = platform
+	return b, nil
+}
+
+// Verify the version, platform and checksum were signed with the key
+func (b *Binary) Verify(key string) error {
+	if len(key) == 0 {
+		return ErrNoPublicKey
+	}
+	if len(b.version) == 0 || len(b.platform) == 0 {
+		return errors.New("binary isn't from a release, use Release.Binary")
+	}
+	pub, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return errors.New("invalid release key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(b.Signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	sum, err := hex.DecodeString(b.SHA256)
+	if err != nil || len(sum) != sha256.Size {
+		return ErrChecksumMismatch
+	}
+	if !ed25519.Verify(ed25519.PublicKey(pub), Message(b.version, b.platform, hex.EncodeToString(sum)), sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Apply downloads the binary, verifies it and atomically replaces the executable with it. The
+// binary is downloaded next to the executable so it can be renamed over it, a failed update
+// leaves the executable untouched.
+func Apply(ctx context.Context, b *Binary, exe string, opts ...Option) error {
+	options := newOptions(opts...)
+
+	// verify the signature before downloading anything
+	if err := b.Verify(options.PublicKey); err != nil {
+		return err
+	}
+
+	exe, err := filepath.EvalSymlinks(exe)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(exe)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(exe), "."+filepath.Base(exe)+".new-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := download(ctx, options.Client, b, tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode()); err != nil {
+		return err
+	}
+
+	// windows doesn't allow renaming over a running executable, but does allow moving it aside
+	if runtime.GOOS == "windows" {
+		old := exe + ".old"
+		os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp.Name(), exe); err != nil {
+			os.Rename(old, exe)
+			return err
+		}
+		return nil
+	}
+	return os.Rename(tmp.Name(), exe)
+}
+
+// download the binary to the file, checking it matches the checksum
+func download(ctx context.Context, c *http.Client, b *Binary, f *os.File) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.URL, nil)
+	if err != nil {
+		return err
+	}
+	rsp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error downloading %v: %v", b.URL, rsp.Status)
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), rsp.Body); err != nil {
+		return err
+	}
+	if hex.EncodeToString(h.Sum(nil)) != strings.ToLower(b.SHA256) {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package update
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testServer(t *testing.T, bin []byte, sig []byte) *httptest.Server {
+	sum := sha256.Sum256(bin)
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	mux.HandleFunc("/beta.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&Release{
+			Version: "v3.6.0-beta",
+			Binaries: map[string]*Binary{
+				Platform(): {
+					URL:       srv.URL + "/micro",
+					SHA256:    hex.EncodeToString(sum[:]),
+					Signature: base64.StdEncoding.EncodeToString(sig),
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/micro", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bin)
+	})
+	return srv
+}
+
+func TestUpdate(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	key := base64.StdEncoding.EncodeToString(pub)
+
+	bin := []byte("new binary")
+	sum := sha256.Sum256(bin)
+	srv := testServer(t, bin, ed25519.Sign(priv, Message("v3.6.0-beta", Platform(), hex.EncodeToString(sum[:]))))
+	defer srv.Close()
+
+	exe := filepath.Join(t.TempDir(), "micro")
+	assert.NoError(t, ioutil.WriteFile(exe, []byte("old binary"), 0755))
+
+	// the pinned version isn't served
+	_, err = Fetch(context.TODO(), URL(srv.URL), Version("v3.5.0"))
+	assert.Error(t, err)
+
+	rel, err := Fetch(context.TODO(), URL(srv.URL), Channel("beta"))
+	assert.NoError(t, err)
+	assert.Equal(t, "v3.6.0-beta", rel.Version)
+	b, err := rel.Binary(Platform())
+	assert.NoError(t, err)
+
+	// a binary built without a key, or with another key, can't be updated
+	assert.Equal(t, ErrNoPublicKey, Apply(context.TODO(), b, exe, WithPublicKey("")))
+	other, _, _ := ed25519.GenerateKey(rand.Reader)
+	assert.Equal(t, ErrInvalidSignature, Apply(context.TODO(), b, exe, WithPublicKey(base64.StdEncoding.EncodeToString(other))))
+	contents, _ := ioutil.ReadFile(exe)
+	assert.Equal(t, "old binary", string(contents))
+
+	assert.NoError(t, Apply(context.TODO(), b, exe, WithPublicKey(key)))
+	contents, _ = ioutil.ReadFile(exe)
+	assert.Equal(t, "new binary", string(contents))
+
+	// the temporary file isn't left behind
+	files, _ := ioutil.ReadDir(filepath.Dir(exe))
+	assert.Len(t, files, 1)
+}
+
+func TestChecksumMismatch(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	// the signature is of a different binary than the one served
+	sum := sha256.Sum256([]byte("signed binary"))
+	srv := testServer(t, []byte("tampered binary"), ed25519.Sign(priv, Message("v3.6.0-beta", Platform(), hex.EncodeToString(sum[:]))))
+	defer srv.Close()
+
+	rel, err := Fetch(context.TODO(), URL(srv.URL), Channel("beta"))
+	assert.NoError(t, err)
+	b, _ := rel.Binary(Platform())
+	b.SHA256 = hex.EncodeToString(sum[:])
+
+	exe := filepath.Join(t.TempDir(), "micro")
+	assert.NoError(t, ioutil.WriteFile(exe, []byte("old binary"), 0755))
+	assert.Equal(t, ErrChecksumMismatch, Apply(context.TODO(), b, exe, WithPublicKey(base64.StdEncoding.EncodeToString(pub))))
+
+	contents, _ := ioutil.ReadFile(exe)
+	assert.Equal(t, "old binary", string(contents))
+}
+
+func TestSignedRelease(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	key := base64.StdEncoding.EncodeToString(pub)
+
+	// the binary was signed for another version, e.g. an older vulnerable release
+	bin := []byte("new binary")
+	sum := sha256.Sum256(bin)
+	srv := testServer(t, bin, ed25519.Sign(priv, Message("v3.5.0", Platform(), hex.EncodeToString(sum[:]))))
+	defer srv.Close()
+
+	rel, err := Fetch(context.TODO(), URL(srv.URL), Channel("beta"))
+	assert.NoError(t, err)
+	b, err := rel.Binary(Platform())
+	assert.NoError(t, err)
+	assert.Equal(t, ErrInvalidSignature, b.Verify(key))
+
+	// a binary which wasn't looked up from a release can't be verified
+	assert.Error(t, (&Binary{SHA256: b.SHA256, Signature: b.Signature}).Verify(key))
+}
+
+func TestCheckVersion(t *testing.T) {
+	assert.NoError(t, CheckVersion("v3.5.0", "v3.6.0"))
+	assert.NoError(t, CheckVersion("v3.6.0", "v3.6.0"))
+	assert.NoError(t, CheckVersion("latest", "v3.5.0"))
+	assert.Equal(t, ErrDowngrade, CheckVersion("v3.6.0", "v3.5.0"))
+	assert.Equal(t, ErrDowngrade, CheckVersion("v3.6.0", "v3.6.0-beta"))
+	assert.Error(t, CheckVersion("v3.6.0", "unknown"))
+}
@@ -50,6 +50,22 @@ func GetConfigSecretKey() (string, error) {
 	return string(dat), nil
 }
 
+// GetCacheKey returns the local key or generates and returns it for encrypting the
+// CLI's cache of tokens and registry snapshots.
+func GetCacheKey() (string, error) {
+	key := filepath.Join(Dir, "cache_key")
+	if !fileExists(key) {
+		if err := setupConfigSecretKey(key); err != nil {
+			return "", err
+		}
+	}
+	dat, err := ioutil.ReadFile(key)
+	if err != nil {
+		return "", err
+	}
+	return string(dat), nil
+}
+
 func setupConfigSecretKey(path string) error {
 	logger.Debugf("Setting up config key to %v", path)
 	bytes := make([]byte, 32) //generate a random 32 byte key for AES-256
@@ -0,0 +1,97 @@
+// Package webhook signs the payloads of webhook deliveries and verifies them. Receivers can use
+// Verify to check a delivery was sent by micro and isn't being replayed.
+//
+// The signature is sent in the Micro-Signature header in the form t=<unix time>,v1=<signature>
+// where the signature is the hex encoded HMAC-SHA256 of "<unix time>.<payload>". When the secret
+// is being rotated the payload is signed with both the old and new secrets, each included as a v1
+// value, so receivers can switch to the new secret at any point during the rotation.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Header the signature is sent in
+	Header = "Micro-Signature"
+	// scheme of the signatures
+	scheme = "v1"
+)
+
+var (
+	// DefaultTolerance is how old a delivery can be before it's rejected as a replay
+	DefaultTolerance = time.Minute * 5
+
+	// ErrInvalidHeader is returned when the signature header can't be parsed
+	ErrInvalidHeader = errors.New("invalid signature header")
+	// ErrNoSignature is returned when none of the signatures match the secrets
+	ErrNoSignature = errors.New("no valid signature")
+	// ErrExpired is returned when the timestamp is outside the tolerance
+	ErrExpired = errors.New("signature timestamp outside the tolerance")
+)
+
+// Sign the payload at the time provided with each of the secrets, returning the value of the
+// signature header
+func Sign(payload []byte, t time.Time, secrets ...string) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	parts := []string{"t=" + ts}
+	for _, s := range secrets {
+		parts = append(parts, scheme+"="+signature(payload, ts, s))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Verify the signature header of a payload was created with one of the secrets within the
+// tolerance, a tolerance of zero uses the default
+func Verify(header string, payload []byte, tolerance time.Duration, secrets ...string) error {
+	if tolerance == 0 {
+		tolerance = DefaultTolerance
+	}
+
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			return ErrInvalidHeader
+		}
+		switch kv[0] {
+		case "t":
+			ts = kv[1]
+		case scheme:
+			sigs = append(sigs, kv[1])
+		}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrInvalidHeader
+	}
+	if age := time.Since(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrExpired
+	}
+
+	for _, s := range secrets {
+		expected := signature(payload, ts, s)
+		for _, sig := range sigs {
+			if hmac.Equal([]byte(sig), []byte(expected)) {
+				return nil
+			}
+		}
+	}
+	return ErrNoSignature
+}
+
+func signature(payload []byte, ts, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%s.", ts)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
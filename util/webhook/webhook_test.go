@@ -0,0 +1,30 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignVerify(t *testing.T) {
+	payload := []byte(`{"id":"1"}`)
+
+	header := Sign(payload, time.Now(), "secret")
+	assert.NoError(t, Verify(header, payload, 0, "secret"))
+	assert.Equal(t, ErrNoSignature, Verify(header, payload, 0, "other"))
+	assert.Equal(t, ErrNoSignature, Verify(header, []byte(`{"id":"2"}`), 0, "secret"))
+
+	// during a rotation either secret is valid
+	header = Sign(payload, time.Now(), "old", "new")
+	assert.NoError(t, Verify(header, payload, 0, "old"))
+	assert.NoError(t, Verify(header, payload, 0, "new"))
+
+	// old deliveries are rejected
+	header = Sign(payload, time.Now().Add(-time.Hour), "secret")
+	assert.Equal(t, ErrExpired, Verify(header, payload, 0, "secret"))
+	assert.NoError(t, Verify(header, payload, time.Hour*2, "secret"))
+
+	assert.Equal(t, ErrInvalidHeader, Verify("foo", payload, 0, "secret"))
+	assert.Equal(t, ErrInvalidHeader, Verify("v1=abc", payload, 0, "secret"))
+}
@@ -0,0 +1,80 @@
+package wrapper
+
+import (
+	"fmt"
+
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/server"
+)
+
+var (
+	// DefaultClientWrappers are the built in client wrappers applied at setup, in the order calls
+	// pass through them. Set it before the service is created to reorder or disable them.
+	DefaultClientWrappers = []string{"from_service", "opentrace", "log", "trace", "auth", "skew"}
+	// DefaultHandlerWrappers are the built in handler wrappers applied at setup, in the order
+	// requests pass through them. Set it before the service is created to reorder or disable them.
+	DefaultHandlerWrappers = []string{"error_chain", "timeout", "skew", "auth", "killswitch", "admission", "idempotency", "trace", "stats", "log", "metrics", "opentrace"}
+	// AppliedHandlerWrappers are the built in handler wrappers applied at setup
+	AppliedHandlerWrappers []string
+
+	clientWrappers = map[string]client.Wrapper{
+		"auth":         AuthClient,
+		"cache":        CacheClient,
+		"from_service": FromService,
+		"log":          LogClient,
+		"opentrace":    OpentraceClient,
+		"skew":         SkewClient,
+		"trace":        TraceCall,
+	}
+
+	handlerWrappers = map[string]func() server.HandlerWrapper{
+		"admission":   AdmissionHandler,
+		"analytics":   AnalyticsHandler,
+		"anomaly":     AnomalyHandler,
+		"auth":        AuthHandler,
+		"error_chain": ErrorChainHandler,
+		"idempotency": IdempotencyHandler,
+		"killswitch":  KillSwitchHandler,
+		"log":         LogHandler,
+		"metrics":     MetricsHandler,
+		"opentrace":   OpenTraceHandler,
+		"ratelimit":   RateLimitHandler,
+		"skew":        SkewHandler,
+		"stats":       HandlerStats,
+		"timeout":     TimeoutHandler,
+		"trace":       TraceHandler,
+	}
+)
+
+// WrapClient wraps the client with the built in wrappers named, in the order calls pass through
+// them, so the first is the outermost
+func WrapClient(c client.Client, names ...string) (client.Client, error) {
+	wrappers := make([]client.Wrapper, len(names))
+	for i, n := range names {
+		w, ok := clientWrappers[n]
+		if !ok {
+			return nil, fmt.Errorf("unknown client wrapper %q", n)
+		}
+		wrappers[i] = w
+	}
+
+	// apply in reverse so the first is executed first
+	for i := len(wrappers); i > 0; i-- {
+		c = wrappers[i-1](c)
+	}
+	return c, nil
+}
+
+// HandlerWrappers returns the built in handler wrappers named, in the order requests pass through
+// them, so the first is the outermost
+func HandlerWrappers(names ...string) ([]server.HandlerWrapper, error) {
+	wrappers := make([]server.HandlerWrapper, len(names))
+	for i, n := range names {
+		fn, ok := handlerWrappers[n]
+		if !ok {
+			return nil, fmt.Errorf("unknown handler wrapper %q", n)
+		}
+		wrappers[i] = fn()
+	}
+	return wrappers, nil
+}
@@ -3,8 +3,11 @@ package wrapper
 import (
 	"context"
 	"encoding/base64"
-	"reflect"
+	"encoding/json"
+	rdebug "runtime/debug"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/micro/micro/v3/service/auth"
@@ -13,11 +16,25 @@ import (
 	"github.com/micro/micro/v3/service/debug"
 	"github.com/micro/micro/v3/service/debug/trace"
 	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/events"
 	"github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/metrics"
 	"github.com/micro/micro/v3/service/server"
+	"github.com/micro/micro/v3/util/admission"
+	"github.com/micro/micro/v3/util/analytics"
+	"github.com/micro/micro/v3/util/anomaly"
 	inauth "github.com/micro/micro/v3/util/auth"
+	"github.com/micro/micro/v3/util/auth/audit"
+	"github.com/micro/micro/v3/util/auth/cert"
+	"github.com/micro/micro/v3/util/auth/namespace"
 	"github.com/micro/micro/v3/util/cache"
+	"github.com/micro/micro/v3/util/cost"
+	"github.com/micro/micro/v3/util/idempotency"
+	"github.com/micro/micro/v3/util/killswitch"
+	"github.com/micro/micro/v3/util/netpolicy"
+	"github.com/micro/micro/v3/util/ratelimit"
+	"github.com/micro/micro/v3/util/scrub"
+	"github.com/micro/micro/v3/util/skew"
 )
 
 type authWrapper struct {
@@ -25,16 +42,31 @@ type authWrapper struct {
 }
 
 func (a *authWrapper) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
-	ctx = a.wrapContext(ctx, opts...)
+	ctx, tok := a.wrapContext(ctx, opts...)
+	err := a.Client.Call(ctx, req, rsp, opts...)
+	if err == nil || tok == nil || errors.FromError(err).Code != 401 {
+		return err
+	}
+
+	// the token may have been revoked or be seen as expired by the service called, so the call is
+	// retried once with a fresh token
+	rctx, cancel := context.WithTimeout(ctx, inauth.RefreshWait)
+	fresh, rerr := inauth.DefaultRefresher.Refresh(rctx, tok)
+	cancel()
+	if rerr != nil || fresh == nil || fresh.AccessToken == tok.AccessToken {
+		return err
+	}
+	ctx = metadata.Set(ctx, "Authorization", inauth.BearerScheme+fresh.AccessToken)
 	return a.Client.Call(ctx, req, rsp, opts...)
 }
 
 func (a *authWrapper) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
-	ctx = a.wrapContext(ctx, opts...)
+	ctx, _ = a.wrapContext(ctx, opts...)
 	return a.Client.Stream(ctx, req, opts...)
 }
 
-func (a *authWrapper) wrapContext(ctx context.Context, opts ...client.CallOption) context.Context {
+// wrapContext sets the auth headers of a call, returning the token of the client if it was set
+func (a *authWrapper) wrapContext(ctx context.Context, opts ...client.CallOption) (context.Context, *auth.AccountToken) {
 	// parse the options
 	var options client.CallOptions
 	for _, o := range opts {
@@ -49,17 +81,30 @@ func (a *authWrapper) wrapContext(ctx context.Context, opts ...client.CallOption
 
 	// We dont't override the header unless the AuthToken option has been specified
 	if !options.AuthToken {
-		return ctx
+		return ctx, nil
+	}
+
+	// wait briefly for an expired token to be refreshed rather than failing authorization
+	// downstream
+	tok := authOpts.Token
+	if tok != nil && tok.Expired() {
+		rctx, cancel := context.WithTimeout(ctx, inauth.RefreshWait)
+		t, err := inauth.DefaultRefresher.Token(rctx)
+		cancel()
+		if err != nil {
+			logger.Warnf("[Auth] Error refreshing expired token: %v", err)
+		}
+		tok = t
 	}
 
 	// check to see if we have a valid access token
-	if authOpts.Token != nil && !authOpts.Token.Expired() {
-		ctx = metadata.Set(ctx, "Authorization", inauth.BearerScheme+authOpts.Token.AccessToken)
-		return ctx
+	if tok != nil && !tok.Expired() {
+		ctx = metadata.Set(ctx, "Authorization", inauth.BearerScheme+tok.AccessToken)
+		return ctx, tok
 	}
 
 	// call without an auth token
-	return ctx
+	return ctx, nil
 }
 
 // AuthClient wraps requests with the auth header
@@ -67,6 +112,60 @@ func AuthClient(c client.Client) client.Client {
 	return &authWrapper{c}
 }
 
+// FromServiceHeader is the metadata key the name of the calling service is set in
+const FromServiceHeader = "Micro-From-Service"
+
+// TimeoutHeader is the metadata key callers set the timeout of a request in, as a duration such
+// as 5s or a number of milliseconds
+const TimeoutHeader = "Micro-Timeout"
+
+// DefaultTimeout of requests which don't set the TimeoutHeader, zero disables the timeout
+var DefaultTimeout time.Duration
+
+type fromServiceWrapper struct {
+	client.Client
+}
+
+func (f *fromServiceWrapper) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	ctx = f.wrapContext(ctx)
+	return f.Client.Call(ctx, req, rsp, opts...)
+}
+
+func (f *fromServiceWrapper) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	ctx = f.wrapContext(ctx)
+	return f.Client.Stream(ctx, req, opts...)
+}
+
+func (f *fromServiceWrapper) Publish(ctx context.Context, p client.Message, opts ...client.PublishOption) error {
+	ctx = f.wrapContext(ctx)
+	return f.Client.Publish(ctx, p, opts...)
+}
+
+// wrapContext sets the name of this service, overwriting the name of the service which called it
+func (f *fromServiceWrapper) wrapContext(ctx context.Context) context.Context {
+	return metadata.Set(ctx, FromServiceHeader, server.DefaultServer.Options().Name)
+}
+
+// FromService wraps requests with the name of the service making them
+func FromService(c client.Client) client.Client {
+	return &fromServiceWrapper{c}
+}
+
+// apiKeyHeader returns the api key in the metadata. The case of the header depends on the transport
+// it was sent over so it's matched case insensitively.
+func apiKeyHeader(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	for k, v := range md {
+		if strings.EqualFold(k, inauth.APIKeyHeader) && len(v) > 0 {
+			return v, true
+		}
+	}
+	return "", false
+}
+
 // AuthHandler wraps a server handler to perform auth
 func AuthHandler() server.HandlerWrapper {
 	return func(h server.HandlerFunc) server.HandlerFunc {
@@ -96,11 +195,28 @@ func AuthHandler() server.HandlerWrapper {
 				}
 			}
 
+			// Machine clients can pass an api key instead of a token. Keys passed as the password of
+			// the basic scheme are inspected the same way.
+			if key, ok := apiKeyHeader(ctx); ok && len(token) == 0 {
+				token = key
+			}
+
 			// Determine the namespace
 			ns := auth.DefaultAuth.Options().Issuer
 
+			// Tokens signed with the key of a namespace are verified with the keys of the namespace
+			// the call was made to
+			callNs, _ := metadata.Get(ctx, "Micro-Namespace")
+			if len(callNs) == 0 {
+				callNs = ns
+			}
+
+			// Callers without a token are identified by the certificate they connected with, if any
 			var acc *auth.Account
-			if a, err := auth.Inspect(token); err == nil {
+			if a, ok := certAccount(ctx); ok && len(token) == 0 {
+				ctx = auth.ContextWithAccount(ctx, a)
+				acc = a
+			} else if a, err := auth.InspectNamespace(token, callNs); err == nil {
 				ctx = auth.ContextWithAccount(ctx, a)
 				acc = a
 			}
@@ -112,17 +228,411 @@ func AuthHandler() server.HandlerWrapper {
 				Endpoint: req.Endpoint(),
 			}
 
+			// record the decision made for the call
+			record := func(decision, reason string) {
+				if !audit.DefaultRecorder.Enabled() || skipAudit(req, acc) {
+					return
+				}
+				rec := &audit.Record{
+					Namespace: ns,
+					Service:   req.Service(),
+					Endpoint:  req.Endpoint(),
+					Decision:  decision,
+					Reason:    reason,
+				}
+				if acc != nil {
+					rec.Account = acc.ID
+				}
+				audit.DefaultRecorder.Record(rec)
+			}
+
 			// Verify the caller has access to the resource.
 			err := auth.Verify(acc, res, auth.VerifyNamespace(ns))
 			if err == auth.ErrForbidden && acc != nil {
+				record(audit.Denied, "forbidden")
 				return errors.Forbidden(req.Service(), "Forbidden call made to %v:%v by %v", req.Service(), req.Endpoint(), acc.ID)
 			} else if err == auth.ErrForbidden {
+				record(audit.Denied, "unauthorized")
 				return errors.Unauthorized(req.Service(), "Unauthorized call made to %v:%v", req.Service(), req.Endpoint())
 			} else if err != nil {
 				return errors.InternalServerError(req.Service(), "Error authorizing request: %v", err)
 			}
 
+			// Determine the service making the call. The name is bound to the account of the service
+			// when it's generated, the header is only checked against it since any caller can set it.
+			if acc != nil && acc.Type == "service" {
+				name := acc.Metadata[inauth.ServiceMetadataKey]
+				if from, _ := metadata.Get(ctx, FromServiceHeader); len(from) > 0 && len(name) > 0 && from != name {
+					record(audit.Denied, "service impersonation")
+					return errors.Forbidden(req.Service(), "Call made to %v:%v by %v claiming to be %v", req.Service(), req.Endpoint(), name, from)
+				}
+				if len(name) > 0 {
+					ctx = inauth.ContextWithService(ctx, name)
+				}
+
+				// Calls between services must be allowed by the network policies of the namespace
+				if pol := netpolicy.DefaultPolicies; pol != nil {
+					if err := pol.Check(ns, name, req.Service()); err == netpolicy.ErrDenied {
+						record(audit.Denied, "network policy")
+						return errors.Forbidden(req.Service(), "Call made to %v:%v by %v denied by network policy", req.Service(), req.Endpoint(), acc.ID)
+					} else if err != nil {
+						return errors.InternalServerError(req.Service(), "Error checking network policy: %v", err)
+					}
+				}
+			}
+
 			// The user is authorised, allow the call
+			record(audit.Granted, "")
+			return h(ctx, req, rsp)
+		}
+	}
+}
+
+// skipAudit returns true for the calls services make to publish events, which include the audit
+// records themselves
+func skipAudit(req server.Request, acc *auth.Account) bool {
+	return req.Service() == "events" && req.Endpoint() == "Stream.Publish" && acc != nil && acc.Type == "service"
+}
+
+// certAccount returns the account of the certificate the caller connected with, if it was verified
+func certAccount(ctx context.Context) (*auth.Account, bool) {
+	p, ok := server.PeerFromContext(ctx)
+	if !ok || p.TLS == nil || len(p.TLS.VerifiedChains) == 0 || len(p.TLS.PeerCertificates) == 0 {
+		return nil, false
+	}
+	return cert.AccountFromCertificate(p.TLS.PeerCertificates[0])
+}
+
+// KillSwitchHandler rejects calls to endpoints which have been disabled
+func KillSwitchHandler() server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			ns := auth.DefaultAuth.Options().Issuer
+			if s := killswitch.Disabled(ns, req.Service(), req.Endpoint()); s != nil {
+				return errors.ServiceUnavailable(req.Service(), "%v has been disabled: %v", req.Endpoint(), s.Reason)
+			}
+			return h(ctx, req, rsp)
+		}
+	}
+}
+
+// TimeoutHandler enforces a deadline on each request, taken from the TimeoutHeader of the request
+// or DefaultTimeout if it isn't set. The header can be set by callers outside the platform, so it
+// can only shorten DefaultTimeout and values which aren't positive are ignored. The context of the
+// handler is cancelled at the deadline and the caller is returned a timeout error rather than
+// waiting on a slow handler.
+func TimeoutHandler() server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			timeout := DefaultTimeout
+			if v, ok := metadata.Get(ctx, TimeoutHeader); ok {
+				if d, err := parseTimeout(v); err == nil && d > 0 && (DefaultTimeout <= 0 || d < DefaultTimeout) {
+					timeout = d
+				}
+			}
+			if timeout <= 0 {
+				return h(ctx, req, rsp)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			errCh := make(chan error, 1)
+			go func() {
+				// the handler runs outside the goroutine of the server, which can't recover its panics
+				defer func() {
+					if r := recover(); r != nil {
+						if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
+							logger.Error("panic recovered: ", r)
+							logger.Error(string(rdebug.Stack()))
+						}
+						errCh <- errors.InternalServerError(req.Service(), "panic recovered: %v", r)
+					}
+				}()
+				errCh <- h(ctx, req, rsp)
+			}()
+
+			select {
+			case err := <-errCh:
+				return err
+			case <-ctx.Done():
+				// the deadline of the caller may be sooner than the timeout
+				if ctx.Err() == context.Canceled {
+					return errors.Timeout(req.Service(), "%v cancelled", req.Endpoint())
+				}
+				return errors.Timeout(req.Service(), "%v timed out after %v", req.Endpoint(), timeout)
+			}
+		}
+	}
+}
+
+// parseTimeout parses a duration, e.g. 5s, or a number of milliseconds
+func parseTimeout(v string) (time.Duration, error) {
+	if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+	return time.ParseDuration(v)
+}
+
+// RateLimitHandler limits the requests handled for each account and each namespace with the
+// limits loaded from the config service. It runs after the auth wrapper so the account is known.
+// The buckets are kept by the quota service so the limits apply across every instance, requests
+// to the quota service itself aren't limited as checking them would call it again.
+func RateLimitHandler() server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			if req.Service() == "quota" {
+				return h(ctx, req, rsp)
+			}
+
+			ns := auth.DefaultAuth.Options().Issuer
+			var account string
+			if acc, ok := auth.AccountFromContext(ctx); ok {
+				ns = acc.Issuer
+				account = acc.ID
+			}
+
+			res, err := ratelimit.DefaultLimiter.Allow(ns, account)
+			if err != nil {
+				// don't take the service down with the quota service
+				logger.Errorf("Error checking rate limit of %v in %v: %v", account, ns, err)
+				return h(ctx, req, rsp)
+			}
+			if !res.Allowed {
+				retry := int(res.RetryAfter.Seconds()) + 1
+				server.SetResponseMetadata(ctx, "Retry-After", strconv.Itoa(retry))
+				return errors.TooManyRequests(req.Service(), "rate limit exceeded, retry after %vs", retry)
+			}
+			return h(ctx, req, rsp)
+		}
+	}
+}
+
+// AdmissionHandler bounds the requests handled at once with the admission controller. Requests
+// wait for a slot in the lane of the priority they set in the PriorityHeader, debug requests in the
+// highest so the service can still be checked while overloaded, and are rejected with a
+// Retry-After header once the queue is full. The time waited is recorded in the context. Any
+// caller can set the header, so it's only honoured for services and admins, it runs after the
+// auth wrapper so the account is known.
+func AdmissionHandler() server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			p := admission.Normal
+			if strings.HasPrefix(req.Endpoint(), "Debug.") {
+				p = admission.High
+			} else if v, ok := metadata.Get(ctx, admission.PriorityHeader); ok && canPrioritise(ctx) {
+				p = admission.ParsePriority(v)
+			}
+
+			start := time.Now()
+			release, err := admission.DefaultController.Acquire(ctx, p)
+			if err == admission.ErrQueueFull {
+				retry := int(admission.DefaultController.Options().RetryAfter.Seconds())
+				if retry < 1 {
+					retry = 1
+				}
+				server.SetResponseMetadata(ctx, "Retry-After", strconv.Itoa(retry))
+				return errors.TooManyRequests(req.Service(), "server overloaded, retry after %vs", retry)
+			} else if err == context.DeadlineExceeded {
+				return errors.Timeout(req.Service(), "request timed out waiting to be handled")
+			} else if err != nil {
+				return err
+			}
+			defer release()
+
+			return h(admission.NewContext(ctx, time.Since(start)), req, rsp)
+		}
+	}
+}
+
+// IdempotencyHandler records the responses to requests made with an idempotency key, set with
+// client.WithIdempotencyKey, so retries of the request return the response rather than being
+// handled again. Keys are scoped by endpoint and account, and server errors aren't recorded so
+// the request can be retried. If the store can't be reached the request is handled as usual.
+func IdempotencyHandler() server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			key, ok := metadata.Get(ctx, idempotency.Header)
+			if !ok || len(key) == 0 || req.Stream() {
+				return h(ctx, req, rsp)
+			}
+			if err := idempotency.Validate(key); err != nil {
+				return errors.BadRequest(req.Service(), "%v", err)
+			}
+
+			ns := auth.DefaultAuth.Options().Issuer
+			scope := req.Service() + "/" + req.Endpoint() + "/"
+			if acc, ok := auth.AccountFromContext(ctx); ok {
+				ns = acc.Issuer
+				scope += acc.ID
+			}
+			body, err := json.Marshal(req.Body())
+			if err != nil {
+				return h(ctx, req, rsp)
+			}
+			fingerprint := idempotency.Fingerprint([]byte(req.Endpoint()), body)
+			opts := []idempotency.Option{idempotency.Namespace(ns)}
+
+			rec, claim, err := idempotency.Begin(scope, key, fingerprint, opts...)
+			switch err {
+			case nil:
+			case idempotency.ErrInProgress, idempotency.ErrMismatch:
+				return errors.Conflict(req.Service(), "%v", err)
+			default:
+				logger.Errorf("Error claiming idempotency key of %v: %v", req.Endpoint(), err)
+				return h(ctx, req, rsp)
+			}
+			if rec != nil {
+				if len(rec.Error) > 0 {
+					return errors.Parse(rec.Error)
+				}
+				return json.Unmarshal(rec.Body, rsp)
+			}
+
+			// server errors, and responses which can't be recorded, release the key so the request
+			// can be retried, as do panics
+			defer claim.Release()
+			herr := h(ctx, req, rsp)
+			rec = &idempotency.Response{}
+			retry := false
+			if herr != nil {
+				rec.Error = herr.Error()
+				code := errors.FromError(herr).Code
+				retry = code == 0 || code >= 500
+			} else if rec.Body, err = json.Marshal(rsp); err != nil {
+				retry = true
+			}
+			if retry {
+				if err := claim.Release(); err != nil {
+					logger.Errorf("Error releasing idempotency key of %v: %v", req.Endpoint(), err)
+				}
+				return herr
+			}
+			if err := claim.Complete(rec); err != nil {
+				logger.Errorf("Error recording response of %v: %v", req.Endpoint(), err)
+			}
+			return herr
+		}
+	}
+}
+
+// canPrioritise returns true if the caller can set the priority of its requests, i.e. it's a
+// service or an admin of its namespace
+func canPrioritise(ctx context.Context) bool {
+	acc, ok := auth.AccountFromContext(ctx)
+	if !ok {
+		return false
+	}
+	return namespace.AuthorizeAdmin(ctx, acc.Issuer, "") == nil
+}
+
+// ErrorChainHandler adds the service to the chain of the errors it returns, so the caller can
+// tell which service an error came from. It's the first handler wrapper so the errors returned by
+// the other wrappers are included.
+func ErrorChainHandler() server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			err := h(ctx, req, rsp)
+			if err == nil {
+				return nil
+			}
+			traceID, _, _ := trace.FromContext(ctx)
+			return errors.Wrap(err, req.Service(), req.Endpoint(), traceID)
+		}
+	}
+}
+
+// AnalyticsHandler records a summary of each request with the analytics tap
+func AnalyticsHandler() server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			start := time.Now()
+			err := h(ctx, req, rsp)
+
+			ev := &analytics.Event{
+				Source:  req.Service(),
+				Route:   req.Service() + "/" + req.Endpoint(),
+				Status:  200,
+				Latency: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				ev.Status = 500
+				if code := errors.FromError(err).Code; code > 0 {
+					ev.Status = int(code)
+				}
+			}
+			if acc, ok := auth.AccountFromContext(ctx); ok {
+				ev.Account = acc.ID
+				ev.Namespace = acc.Issuer
+			}
+			if md, ok := metadata.FromContext(ctx); ok {
+				ev.UserAgent, _ = md.Get("User-Agent")
+			}
+			analytics.DefaultTap.Record(ev)
+			return err
+		}
+	}
+}
+
+// AnomalyHandler observes each request with the anomaly detector. Only server errors count
+// towards the error rate, errors caused by the caller aren't anomalies of the service.
+func AnomalyHandler() server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			start := time.Now()
+			err := h(ctx, req, rsp)
+
+			failed := false
+			if err != nil {
+				code := errors.FromError(err).Code
+				failed = code == 0 || code >= 500
+			}
+			anomaly.DefaultDetector.Observe(req.Service(), req.Endpoint(), time.Since(start), failed)
+			return err
+		}
+	}
+}
+
+type skewWrapper struct {
+	client.Client
+}
+
+// Call sets the time the call is sent in its metadata and observes the offset of the clock of the
+// service from the time it received the call, which it sets in the response metadata
+func (s *skewWrapper) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	var options client.CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	md := options.ResponseMetadata
+	if md == nil {
+		md = new(metadata.Metadata)
+		opts = append(opts, client.ResponseMetadata(md))
+	}
+
+	start := time.Now()
+	err := s.Client.Call(skew.Stamp(ctx), req, rsp, opts...)
+	end := time.Now()
+
+	if remote, ok := skew.Parse(*md); ok {
+		skew.DefaultDetector.Observe(req.Service(), start, remote, end)
+	}
+	return err
+}
+
+// SkewClient detects clock skew between this service and the services it calls
+func SkewClient(c client.Client) client.Client {
+	return &skewWrapper{c}
+}
+
+// SkewHandler sets the time a request was received in the response metadata, if the caller set
+// the time it was sent, so the caller can detect clock skew
+func SkewHandler() server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			if _, ok := metadata.Get(ctx, skew.TimestampKey); ok {
+				server.SetResponseMetadata(ctx, skew.TimestampKey, skew.Format(time.Now()))
+			}
 			return h(ctx, req, rsp)
 		}
 	}
@@ -183,7 +693,7 @@ func (c *traceWrapper) Call(ctx context.Context, req client.Request, rsp interfa
 	s.Type = trace.SpanTypeRequestOutbound
 	err := c.Client.Call(newCtx, req, rsp, opts...)
 	if err != nil {
-		s.Metadata["error"] = err.Error()
+		s.Metadata["error"] = scrub.String(req.Service(), err.Error())
 	}
 
 	// finish the trace
@@ -213,10 +723,28 @@ func TraceHandler() server.HandlerWrapper {
 			// get the span
 			newCtx, s := debug.DefaultTracer.Start(ctx, req.Service()+"."+req.Endpoint())
 			s.Type = trace.SpanTypeRequestInbound
+			if wait, ok := admission.WaitFromContext(ctx); ok {
+				s.Metadata["queue_time"] = wait.String()
+			}
+
+			// when debugging record the cost of the work done by the handler
+			if logger.V(logger.DebugLevel, logger.DefaultLogger) {
+				newCtx = cost.NewContext(newCtx)
+			}
 
 			err := h(newCtx, req, rsp)
 			if err != nil {
-				s.Metadata["error"] = err.Error()
+				s.Metadata["error"] = scrub.String(req.Service(), err.Error())
+			}
+
+			if c, ok := cost.FromContext(newCtx); ok {
+				for k, v := range c.Metadata() {
+					s.Metadata[k] = v
+				}
+				// return the cost to the caller so it's attributed to its request too
+				if str := c.String(); len(str) > 0 {
+					server.SetResponseMetadata(ctx, cost.Header, str)
+				}
 			}
 
 			// finish
@@ -230,10 +758,17 @@ func TraceHandler() server.HandlerWrapper {
 type cacheWrapper struct {
 	Cache *cache.Cache
 	client.Client
+
+	subscribe sync.Once
+
+	// directives are the last cache directives returned by endpoints, keyed by service and endpoint
+	directives sync.Map
 }
 
-// Call executes the request. If the CacheExpiry option was set, the response will be cached using
-// a hash of the metadata and request as the key.
+// Call executes the request. The response is cached if the CacheExpiry option was set or the
+// handler returned a cache directive allowing it, using a hash of the metadata and request as the
+// key. The directive of the handler takes precedence, it can shorten the expiry or prevent the
+// response from being cached.
 func (c *cacheWrapper) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
 	// parse the options
 	var options client.CallOptions
@@ -242,25 +777,58 @@ func (c *cacheWrapper) Call(ctx context.Context, req client.Request, rsp interfa
 	}
 
 	// if the client doesn't have a cacbe setup don't continue
-	if c.Cache == nil {
+	if c.Cache == nil || rsp == nil {
 		return c.Client.Call(ctx, req, rsp, opts...)
 	}
 
-	cacheOpts, ok := cache.GetOptions(options.Context)
-	if !ok {
-		return c.Client.Call(ctx, req, rsp, opts...)
+	// the expiry requested by the caller and the directive last returned by the endpoint
+	var expiry time.Duration
+	if cacheOpts, ok := cache.GetOptions(options.Context); ok {
+		expiry = cacheOpts.Expiry
+	}
+	endpoint := req.Service() + "/" + req.Endpoint()
+	var directive *cache.Directive
+	if v, ok := c.directives.Load(endpoint); ok {
+		directive = v.(*cache.Directive)
 	}
 
-	// if the cache expiry is not set, execute the call without the cache
-	if cacheOpts.Expiry == 0 || rsp == nil {
-		return c.Client.Call(ctx, req, rsp, opts...)
+	cacheCtx := func() context.Context {
+		cctx := ctx
+		if len(options.CacheKey) > 0 {
+			cctx = cache.ContextWithKey(cctx, options.CacheKey)
+		}
+		if directive != nil && len(directive.Vary) > 0 {
+			cctx = cache.ContextWithVary(cctx, directive.Vary)
+		}
+		return cctx
 	}
 
-	// check to see if there is a response cached, if there is assign it
-	if r, ok := c.Cache.Get(ctx, req); ok {
-		val := reflect.ValueOf(rsp).Elem()
-		val.Set(reflect.ValueOf(r).Elem())
-		return nil
+	if cacheTTL(expiry, directive) > 0 {
+		// purge the responses invalidated by other services, the stream isn't setup when the client
+		// is wrapped so subscribe on the first call cached
+		c.subscribe.Do(func() {
+			if events.DefaultStream == nil {
+				return
+			}
+			if err := c.Cache.Subscribe(context.Background()); err != nil {
+				logger.Warnf("Error subscribing to cache invalidations: %v", err)
+			}
+		})
+
+		// check to see if there is a response cached, if there is it's decoded into the response.
+		// The call is made if the cache can't be read, e.g. a shared backend is unavailable.
+		if ok, err := c.Cache.Get(cacheCtx(), req, rsp); err != nil {
+			logger.Debugf("Error reading %v:%v from the cache: %v", req.Service(), req.Endpoint(), err)
+		} else if ok {
+			return nil
+		}
+	}
+
+	// the directive is returned in the response metadata
+	md := options.ResponseMetadata
+	if md == nil {
+		md = new(metadata.Metadata)
+		opts = append(opts, client.ResponseMetadata(md))
 	}
 
 	// don't cache the result if there was an error
@@ -268,11 +836,42 @@ func (c *cacheWrapper) Call(ctx context.Context, req client.Request, rsp interfa
 		return err
 	}
 
+	// remember the directive so the next call to the endpoint is looked up in the cache
+	directive = nil
+	if d, ok := cache.ParseDirective(*md); ok {
+		directive = d
+		c.directives.Store(endpoint, d)
+	} else {
+		c.directives.Delete(endpoint)
+	}
+
+	ttl := cacheTTL(expiry, directive)
+	if ttl == 0 {
+		return nil
+	}
+
 	// set the result in the cache
-	c.Cache.Set(ctx, req, rsp, cacheOpts.Expiry)
+	if err := c.Cache.Set(cacheCtx(), req, rsp, ttl); err != nil {
+		logger.Debugf("Error caching %v:%v: %v", req.Service(), req.Endpoint(), err)
+	}
 	return nil
 }
 
+// cacheTTL returns how long a response can be cached for, the directive of the handler can shorten
+// the expiry requested by the caller or prevent the response from being cached
+func cacheTTL(expiry time.Duration, d *cache.Directive) time.Duration {
+	switch {
+	case d == nil:
+		return expiry
+	case d.NoStore:
+		return 0
+	case expiry == 0 || d.MaxAge < expiry:
+		return d.MaxAge
+	default:
+		return expiry
+	}
+}
+
 // CacheClient wraps requests with the cache wrapper
 func CacheClient(c client.Client) client.Client {
 	return &cacheWrapper{
@@ -2,13 +2,26 @@ package wrapper
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/client"
 	"github.com/micro/micro/v3/service/context/metadata"
+	"github.com/micro/micro/v3/service/debug/trace"
 	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/server"
+	"github.com/micro/micro/v3/service/store"
+	"github.com/micro/micro/v3/service/store/memory"
+	"github.com/micro/micro/v3/util/admission"
+	inauth "github.com/micro/micro/v3/util/auth"
+	"github.com/micro/micro/v3/util/cache"
 	"github.com/micro/micro/v3/util/codec"
+	"github.com/micro/micro/v3/util/cost"
+	"github.com/micro/micro/v3/util/idempotency"
 
 	. "github.com/onsi/gomega"
 )
@@ -113,6 +126,7 @@ func TestAuthWrapper(t *testing.T) {
 	tcs := []struct {
 		name    string
 		authHdr string
+		apiKey  string
 		err     error
 		tok     string
 	}{
@@ -133,6 +147,17 @@ func TestAuthWrapper(t *testing.T) {
 			authHdr: "Basic Zm9vYmFyCg==",
 			err:     errors.Unauthorized("dummy", "invalid authorization header. Incorrect format"),
 		},
+		{
+			name:   "API key",
+			apiKey: "mk_1_abc",
+			tok:    "mk_1_abc",
+		},
+		{
+			name:    "API key and bearer auth",
+			authHdr: "Bearer 123355",
+			apiKey:  "mk_1_abc",
+			tok:     "123355",
+		},
 		{
 			name:    "Unknown auth",
 			authHdr: "Foobar 11111",
@@ -156,6 +181,7 @@ func TestAuthWrapper(t *testing.T) {
 			}
 			ctx := context.Background()
 			ctx = metadata.Set(ctx, "Authorization", tc.authHdr)
+			ctx = metadata.Set(ctx, "x-micro-apikey", tc.apiKey)
 			err := w(dummy)(ctx, &dummyReq{}, nil)
 
 			if tc.err == nil {
@@ -169,3 +195,414 @@ func TestAuthWrapper(t *testing.T) {
 		})
 	}
 }
+
+// serviceAuth returns service accounts bound to the service named by the token
+type serviceAuth struct {
+	dummyAuth
+}
+
+func (s serviceAuth) Inspect(token string) (*auth.Account, error) {
+	return &auth.Account{ID: token, Type: "service", Metadata: map[string]string{inauth.ServiceMetadataKey: token}}, nil
+}
+
+func TestFromService(t *testing.T) {
+	g := NewWithT(t)
+	auth.DefaultAuth = serviceAuth{}
+
+	var from string
+	h := AuthHandler()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		from, _ = inauth.FromService(ctx)
+		return nil
+	})
+
+	// the name of the service is taken from its account
+	ctx := metadata.Set(context.Background(), "Authorization", inauth.BearerScheme+"orders")
+	g.Expect(h(ctx, &dummyReq{}, nil)).To(BeNil())
+	g.Expect(from).To(Equal("orders"))
+
+	ctx = metadata.Set(ctx, FromServiceHeader, "orders")
+	g.Expect(h(ctx, &dummyReq{}, nil)).To(BeNil())
+	g.Expect(from).To(Equal("orders"))
+
+	// a service can't claim to be another
+	from = ""
+	ctx = metadata.Set(ctx, FromServiceHeader, "payments")
+	err := h(ctx, &dummyReq{}, nil)
+	g.Expect(errors.FromError(err).Code).To(Equal(int32(403)))
+	g.Expect(from).To(BeEmpty())
+}
+
+func TestWrapClient(t *testing.T) {
+	g := NewWithT(t)
+
+	// the first wrapper is the outermost
+	c, err := WrapClient(nil, "log", "trace", "auth")
+	g.Expect(err).To(BeNil())
+	lw, ok := c.(*logWrapper)
+	g.Expect(ok).To(BeTrue())
+	tw, ok := lw.Client.(*traceWrapper)
+	g.Expect(ok).To(BeTrue())
+	_, ok = tw.Client.(*authWrapper)
+	g.Expect(ok).To(BeTrue())
+
+	_, err = WrapClient(nil, "auth", "foo")
+	g.Expect(err).NotTo(BeNil())
+
+	hws, err := HandlerWrappers(DefaultHandlerWrappers...)
+	g.Expect(err).To(BeNil())
+	g.Expect(hws).To(HaveLen(len(DefaultHandlerWrappers)))
+
+	_, err = HandlerWrappers("foo")
+	g.Expect(err).NotTo(BeNil())
+}
+
+func TestTimeoutHandler(t *testing.T) {
+	g := NewWithT(t)
+
+	h := TimeoutHandler()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(200 * time.Millisecond):
+			return nil
+		}
+	})
+
+	// no timeout by default
+	defer func(d time.Duration) { DefaultTimeout = d }(DefaultTimeout)
+	DefaultTimeout = 0
+	g.Expect(h(context.Background(), &dummyReq{}, nil)).To(BeNil())
+
+	// the timeout of the request
+	start := time.Now()
+	err := h(metadata.Set(context.Background(), TimeoutHeader, "10ms"), &dummyReq{}, nil)
+	g.Expect(errors.FromError(err).Code).To(Equal(int32(408)))
+	g.Expect(time.Since(start)).To(BeNumerically("<", 200*time.Millisecond))
+
+	err = h(metadata.Set(context.Background(), TimeoutHeader, "10"), &dummyReq{}, nil)
+	g.Expect(errors.FromError(err).Code).To(Equal(int32(408)))
+
+	// the default is used when the header isn't set
+	DefaultTimeout = 10 * time.Millisecond
+	err = h(context.Background(), &dummyReq{}, nil)
+	g.Expect(errors.FromError(err).Code).To(Equal(int32(408)))
+
+	// the header can't disable or extend the default
+	for _, v := range []string{"0", "-1", "1h"} {
+		err = h(metadata.Set(context.Background(), TimeoutHeader, v), &dummyReq{}, nil)
+		g.Expect(errors.FromError(err).Code).To(Equal(int32(408)))
+	}
+
+	// handlers which finish in time return their result
+	DefaultTimeout = time.Minute
+	fast := TimeoutHandler()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		return errors.BadRequest("dummy", "bad")
+	})
+	g.Expect(errors.FromError(fast(context.Background(), &dummyReq{}, nil)).Code).To(Equal(int32(400)))
+
+	// panics are recovered rather than crashing the service
+	panics := TimeoutHandler()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		panic("oops")
+	})
+	g.Expect(errors.FromError(panics(context.Background(), &dummyReq{}, nil)).Code).To(Equal(int32(500)))
+}
+
+func TestAdmissionHandler(t *testing.T) {
+	g := NewWithT(t)
+
+	defer func(c *admission.Controller) { admission.DefaultController = c }(admission.DefaultController)
+	admission.DefaultController = admission.New(admission.Concurrency(1), admission.QueueSize(0))
+
+	block := make(chan struct{})
+	h := AdmissionHandler()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		_, ok := admission.WaitFromContext(ctx)
+		g.Expect(ok).To(BeTrue())
+		<-block
+		return nil
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- h(context.Background(), &dummyReq{}, nil) }()
+	g.Eventually(func() int { return admission.DefaultController.Stats().InFlight }).Should(Equal(1))
+
+	// the server is overloaded
+	err := h(context.Background(), &dummyReq{}, nil)
+	g.Expect(errors.FromError(err).Code).To(Equal(int32(429)))
+
+	close(block)
+	g.Expect(<-done).To(BeNil())
+	g.Expect(h(context.Background(), &dummyReq{}, nil)).To(BeNil())
+}
+
+func TestCanPrioritise(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(canPrioritise(context.Background())).To(BeFalse())
+
+	user := &auth.Account{ID: "john", Type: "user", Issuer: "foo"}
+	g.Expect(canPrioritise(auth.ContextWithAccount(context.Background(), user))).To(BeFalse())
+
+	admin := &auth.Account{ID: "jane", Type: "user", Issuer: "foo", Scopes: []string{"admin"}}
+	g.Expect(canPrioritise(auth.ContextWithAccount(context.Background(), admin))).To(BeTrue())
+
+	svc := &auth.Account{ID: "orders", Type: "service", Issuer: "foo", Scopes: []string{"service"}}
+	g.Expect(canPrioritise(auth.ContextWithAccount(context.Background(), svc))).To(BeTrue())
+}
+
+// bodyReq is a unary request with a body
+type bodyReq struct {
+	dummyReq
+	body interface{}
+}
+
+func (b bodyReq) Body() interface{} {
+	return b.body
+}
+
+func (b bodyReq) Stream() bool {
+	return false
+}
+
+type idRsp struct {
+	ID string `json:"id"`
+}
+
+func TestIdempotencyHandler(t *testing.T) {
+	g := NewWithT(t)
+
+	defer func(a auth.Auth) { auth.DefaultAuth = a }(auth.DefaultAuth)
+	auth.DefaultAuth = &dummyAuth{opts: auth.Options{Issuer: "micro"}}
+	defer func(s store.Store) { store.DefaultStore = s }(store.DefaultStore)
+	store.DefaultStore = memory.NewStore()
+
+	var calls int
+	h := IdempotencyHandler()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		calls++
+		if req.Body().(map[string]int)["amount"] > 100 {
+			return errors.BadRequest("dummy", "amount too large")
+		}
+		rsp.(*idRsp).ID = fmt.Sprintf("%d", calls)
+		return nil
+	})
+	ctx := metadata.Set(context.Background(), idempotency.Header, "abc")
+
+	// retries return the response recorded
+	rsp := &idRsp{}
+	g.Expect(h(ctx, &bodyReq{body: map[string]int{"amount": 100}}, rsp)).To(BeNil())
+	g.Expect(rsp.ID).To(Equal("1"))
+	rsp = &idRsp{}
+	g.Expect(h(ctx, &bodyReq{body: map[string]int{"amount": 100}}, rsp)).To(BeNil())
+	g.Expect(rsp.ID).To(Equal("1"))
+	g.Expect(calls).To(Equal(1))
+
+	// the key can't be used for another request
+	err := h(ctx, &bodyReq{body: map[string]int{"amount": 50}}, &idRsp{})
+	g.Expect(errors.FromError(err).Code).To(Equal(int32(409)))
+
+	// client errors are replayed
+	ctx = metadata.Set(context.Background(), idempotency.Header, "def")
+	err = h(ctx, &bodyReq{body: map[string]int{"amount": 200}}, &idRsp{})
+	g.Expect(errors.FromError(err).Code).To(Equal(int32(400)))
+	err = h(ctx, &bodyReq{body: map[string]int{"amount": 200}}, &idRsp{})
+	g.Expect(errors.FromError(err).Code).To(Equal(int32(400)))
+	g.Expect(calls).To(Equal(2))
+
+	// requests without a key are handled as usual
+	g.Expect(h(context.Background(), &bodyReq{body: map[string]int{"amount": 100}}, &idRsp{})).To(BeNil())
+	g.Expect(calls).To(Equal(3))
+}
+
+func TestTraceHandlerCost(t *testing.T) {
+	g := NewWithT(t)
+
+	h := TraceHandler()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		cost.Record(ctx, cost.Store, time.Now())
+		return nil
+	})
+
+	// the cost isn't recorded unless debugging
+	defer func(l logger.Logger) { logger.DefaultLogger = l }(logger.DefaultLogger)
+	logger.DefaultLogger = logger.NewLogger(logger.WithLevel(logger.InfoLevel))
+	ctx := server.NewResponseContext(context.Background())
+	g.Expect(h(ctx, &dummyReq{}, nil)).To(BeNil())
+	g.Expect(server.ResponseMetadata(ctx)).ToNot(HaveKey(cost.Header))
+
+	// when debugging it's returned with the response metadata
+	logger.DefaultLogger = logger.NewLogger(logger.WithLevel(logger.DebugLevel))
+	ctx = server.NewResponseContext(context.Background())
+	g.Expect(h(ctx, &dummyReq{}, nil)).To(BeNil())
+	g.Expect(server.ResponseMetadata(ctx)).To(HaveKey(cost.Header))
+}
+
+func TestErrorChainHandler(t *testing.T) {
+	g := NewWithT(t)
+
+	var herr error
+	h := ErrorChainHandler()(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		return herr
+	})
+	ctx := trace.ToContext(context.Background(), "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7")
+
+	// errors returned by a service called are added to
+	herr = &errors.Error{Id: "store", Code: 500, Chain: []*errors.Hop{{Service: "store", Endpoint: "Store.Read", Code: 500}}}
+	err := h(ctx, &dummyReq{}, nil)
+	chain := errors.Chain(err)
+	g.Expect(chain).To(HaveLen(2))
+	g.Expect(*chain[1]).To(Equal(errors.Hop{Service: "dummy", Endpoint: "dummy", Code: 500, TraceId: "4bf92f3577b34da6a3ce929d0e0e4736"}))
+	g.Expect(errors.Origin(err).Service).To(Equal("store"))
+	g.Expect(herr.(*errors.Error).Chain).To(HaveLen(1))
+
+	// other errors are converted so the service is added
+	herr = fmt.Errorf("oops")
+	err = h(ctx, &dummyReq{}, nil)
+	g.Expect(errors.FromError(err).Code).To(Equal(int32(500)))
+	g.Expect(errors.FromError(err).Detail).To(Equal("oops"))
+	g.Expect(errors.Origin(err).Service).To(Equal("dummy"))
+	herr = nil
+	g.Expect(h(ctx, &dummyReq{}, nil)).To(BeNil())
+}
+
+// tokenAuth issues a new token each time one is requested
+type tokenAuth struct {
+	dummyAuth
+	sync.Mutex
+	issued int
+}
+
+func (a *tokenAuth) Init(opts ...auth.Option) {
+	a.Lock()
+	defer a.Unlock()
+	for _, o := range opts {
+		o(&a.opts)
+	}
+}
+
+func (a *tokenAuth) Options() auth.Options {
+	a.Lock()
+	defer a.Unlock()
+	return a.opts
+}
+
+func (a *tokenAuth) Token(opts ...auth.TokenOption) (*auth.AccountToken, error) {
+	a.Lock()
+	defer a.Unlock()
+	a.issued++
+	return &auth.AccountToken{AccessToken: fmt.Sprintf("new-%d", a.issued), Expiry: time.Now().Add(time.Hour)}, nil
+}
+
+type callClient struct {
+	client.Client
+	call func(ctx context.Context) error
+}
+
+func (c *callClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	return c.call(ctx)
+}
+
+func TestAuthClientRefresh(t *testing.T) {
+	g := NewWithT(t)
+
+	defer func(a auth.Auth) { auth.DefaultAuth = a }(auth.DefaultAuth)
+	defer func(r *inauth.Refresher) { inauth.DefaultRefresher = r }(inauth.DefaultRefresher)
+	inauth.DefaultRefresher = inauth.NewRefresher()
+
+	// the services called only accept the newest token
+	var tokens []string
+	c := AuthClient(&callClient{call: func(ctx context.Context) error {
+		tok, _ := metadata.Get(ctx, "Authorization")
+		tokens = append(tokens, tok)
+		if tok != inauth.BearerScheme+"new-1" {
+			return errors.Unauthorized("dummy", "invalid token")
+		}
+		return nil
+	}})
+
+	// an expired token is refreshed before the call
+	a := &tokenAuth{}
+	a.opts.Token = &auth.AccountToken{AccessToken: "old", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Minute)}
+	auth.DefaultAuth = a
+	g.Expect(c.Call(context.Background(), nil, nil, client.WithAuthToken())).To(BeNil())
+	g.Expect(tokens).To(Equal([]string{inauth.BearerScheme + "new-1"}))
+
+	// the call is retried once with a fresh token when the token is rejected
+	tokens = nil
+	a = &tokenAuth{}
+	a.opts.Token = &auth.AccountToken{AccessToken: "revoked", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)}
+	auth.DefaultAuth = a
+	inauth.DefaultRefresher = inauth.NewRefresher()
+	g.Expect(c.Call(context.Background(), nil, nil, client.WithAuthToken())).To(BeNil())
+	g.Expect(tokens).To(Equal([]string{inauth.BearerScheme + "revoked", inauth.BearerScheme + "new-1"}))
+}
+
+type cacheRequest struct {
+	client.Request
+	endpoint string
+}
+
+func (r *cacheRequest) Service() string     { return "foo" }
+func (r *cacheRequest) Endpoint() string    { return r.endpoint }
+func (r *cacheRequest) Method() string      { return r.endpoint }
+func (r *cacheRequest) Body() interface{}   { return nil }
+func (r *cacheRequest) ContentType() string { return "application/json" }
+
+// directiveClient returns the number of calls made as the response, with the directive set
+type directiveClient struct {
+	client.Client
+	directive string
+	calls     int
+}
+
+func (c *directiveClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	var options client.CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	c.calls++
+	*rsp.(*int) = c.calls
+	if options.ResponseMetadata != nil && len(c.directive) > 0 {
+		*options.ResponseMetadata = metadata.Metadata{"Cache-Control": c.directive}
+	}
+	return nil
+}
+
+func TestCacheDirectives(t *testing.T) {
+	g := NewWithT(t)
+
+	call := func(c client.Client, endpoint string, opts ...client.CallOption) int {
+		var rsp int
+		g.Expect(c.Call(context.Background(), &cacheRequest{endpoint: endpoint}, &rsp, opts...)).To(BeNil())
+		return rsp
+	}
+
+	// responses are cached once the handler allows it
+	dc := &directiveClient{directive: "max-age=60"}
+	c := CacheClient(dc)
+	c.(*cacheWrapper).Cache.Backend = cache.NewMemoryBackend()
+	g.Expect(call(c, "Foo.Cached")).To(Equal(1))
+	g.Expect(call(c, "Foo.Cached")).To(Equal(1))
+
+	// the handler can prevent responses being cached even if the caller asks for it
+	dc = &directiveClient{directive: "no-store"}
+	c = CacheClient(dc)
+	c.(*cacheWrapper).Cache.Backend = cache.NewMemoryBackend()
+	g.Expect(call(c, "Foo.Volatile", cache.CallExpiry(time.Minute))).To(Equal(1))
+	g.Expect(call(c, "Foo.Volatile", cache.CallExpiry(time.Minute))).To(Equal(2))
+
+	// responses without a directive are only cached if the caller asks for it
+	dc = &directiveClient{}
+	c = CacheClient(dc)
+	c.(*cacheWrapper).Cache.Backend = cache.NewMemoryBackend()
+	g.Expect(call(c, "Foo.Plain")).To(Equal(1))
+	g.Expect(call(c, "Foo.Plain")).To(Equal(2))
+	g.Expect(call(c, "Foo.Plain", cache.CallExpiry(time.Minute))).To(Equal(3))
+	g.Expect(call(c, "Foo.Plain", cache.CallExpiry(time.Minute))).To(Equal(3))
+}
+
+func TestCacheTTL(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(cacheTTL(time.Minute, nil)).To(Equal(time.Minute))
+	g.Expect(cacheTTL(time.Minute, &cache.Directive{NoStore: true})).To(BeZero())
+	g.Expect(cacheTTL(time.Minute, &cache.Directive{MaxAge: time.Second})).To(Equal(time.Second))
+	g.Expect(cacheTTL(time.Second, &cache.Directive{MaxAge: time.Minute})).To(Equal(time.Second))
+	g.Expect(cacheTTL(0, &cache.Directive{MaxAge: time.Minute})).To(Equal(time.Minute))
+}